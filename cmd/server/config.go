@@ -0,0 +1,45 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/dictybase/dcr-mcp/pkg/reload"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+)
+
+// runConfigCommand runs "config validate FILE", which checks a
+// configuration file without starting the server, and returns the exit
+// code. Problems are printed as FILE:LINE: path: message, one per line.
+func runConfigCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 2 || args[0] != "validate" {
+		fmt.Fprintln(stderr, "usage: dcr-mcp-server config validate FILE")
+		return 2
+	}
+	path := args[1]
+	tools, err := describedTools()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	cfg, err := reload.LoadConfig(path, reload.Schema{Tools: registry.Names(), Parameters: inputSchemas(tools)})
+	var validationErr *reload.ValidationError
+	if errors.As(err, &validationErr) {
+		for _, problem := range validationErr.Problems {
+			fmt.Fprintf(stderr, "%s:%d: %s\n", path, problem.Line, problem)
+		}
+		return 1
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	toolTimeouts := 0
+	if cfg.Timeouts != nil {
+		toolTimeouts = len(cfg.Timeouts.Tools)
+	}
+	fmt.Fprintf(stdout, "%s is valid: %d prompts, %d tool timeouts, %d rate limits, %d tools with defaults\n",
+		path, len(cfg.Prompts), toolTimeouts, len(cfg.RateLimits), len(cfg.ToolDefaults))
+	return 0
+}
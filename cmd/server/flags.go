@@ -0,0 +1,458 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"net/url"
+	"os"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/concurrency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/profile"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/telemetry"
+	"github.com/dictybase/dcr-mcp/pkg/timeout"
+)
+
+// serverOptions holds the command-line configuration of the server.
+type serverOptions struct {
+	selection        toolSelection
+	httpAddr         string
+	webhookConfig    string
+	nats             natsOptions
+	artifacts        artifactOptions
+	logConfig        logging.Config
+	timeouts         timeout.Config
+	rateLimits       map[string]ratelimit.Limit
+	retry            retry.Policy
+	concurrency      concurrency.Config
+	progressInterval time.Duration
+	uploads          uploadOptions
+	documentsDir     string
+	idempotencyTTL   time.Duration
+	coverageRun      bool
+	signingKeys      string
+	glossary         string
+	literature       literatureOptions
+	telemetry        telemetryOptions
+	snapshots        snapshotOptions
+	configPath       string
+	profile          string
+	showVersion      bool
+}
+
+// uploadOptions limits the chunked uploads kept by the server.
+type uploadOptions struct {
+	maxBytes int
+	ttl      time.Duration
+}
+
+// literatureOptions makes literature-fetch replay or record the responses
+// of its providers.
+type literatureOptions struct {
+	replay string
+	record string
+}
+
+// telemetryOptions configures the opt-in anonymous usage reports.
+type telemetryOptions struct {
+	endpoint string
+	interval time.Duration
+}
+
+// snapshotOptions configures the session snapshots used to reproduce
+// problems in a development environment.
+type snapshotOptions struct {
+	dir     string
+	restore string
+	token   string
+}
+
+// artifactOptions selects and configures the artifact store backend.
+type artifactOptions struct {
+	backend string
+	dir     string
+	s3      artifact.S3Config
+	drive   artifact.DriveConfig
+}
+
+// natsOptions holds the configuration of the optional NATS job queue.
+type natsOptions struct {
+	url           string
+	subject       string
+	queue         string
+	resultSubject string
+}
+
+// parseFlags parses the command-line arguments into serverOptions.
+func parseFlags(args []string) (serverOptions, error) {
+	flagSet := flag.NewFlagSet("dcr-mcp-server", flag.ContinueOnError)
+	enableTools := flagSet.String(
+		"enable-tools",
+		"",
+		"comma-separated list of tools to enable (default: all tools)",
+	)
+	disableTools := flagSet.String(
+		"disable-tools",
+		"",
+		"comma-separated list of tools to disable",
+	)
+	httpAddr := flagSet.String(
+		"http-addr",
+		"",
+		"address for the optional HTTP+JSON gateway, e.g. :8080 (disabled when empty)",
+	)
+	webhookConfig := flagSet.String(
+		"webhook-config",
+		"",
+		"path to a JSON file mapping webhook events to tool runs (requires --http-addr)",
+	)
+	natsURL := flagSet.String(
+		"nats-url",
+		"",
+		"NATS server URL for accepting tool-run requests (disabled when empty)",
+	)
+	natsSubject := flagSet.String(
+		"nats-subject",
+		"dcr.tools.run",
+		"NATS subject on which tool-run requests are received",
+	)
+	natsQueue := flagSet.String(
+		"nats-queue",
+		"dcr-mcp",
+		"NATS queue group shared by server instances",
+	)
+	natsResultSubject := flagSet.String(
+		"nats-result-subject",
+		"dcr.tools.results",
+		"NATS subject on which tool results are published",
+	)
+	artifactStore := flagSet.String(
+		"artifact-store",
+		"local",
+		"where generated artifacts are written: local, s3 or gdrive",
+	)
+	artifactDir := flagSet.String(
+		"artifact-dir",
+		"",
+		"directory for relative artifact names with the local store (default: working directory)",
+	)
+	s3Endpoint := flagSet.String("s3-endpoint", "", "S3/MinIO endpoint, e.g. minio.example.org:9000")
+	s3Bucket := flagSet.String("s3-bucket", "", "S3/MinIO bucket for artifacts")
+	s3Prefix := flagSet.String("s3-prefix", "", "key prefix for artifacts in the bucket")
+	s3Region := flagSet.String("s3-region", "", "S3 region")
+	s3UseSSL := flagSet.Bool("s3-use-ssl", true, "use TLS when talking to the S3 endpoint")
+	s3URLExpiry := flagSet.Duration("s3-url-expiry", 24*time.Hour, "lifetime of presigned download URLs")
+	driveCredentials := flagSet.String(
+		"gdrive-credentials",
+		os.Getenv("GOOGLE_APPLICATION_CREDENTIALS"),
+		"path to a Google service account key (default: $GOOGLE_APPLICATION_CREDENTIALS)",
+	)
+	driveFolder := flagSet.String("gdrive-folder", "", "Google Drive folder ID for artifacts")
+	driveShareAnyone := flagSet.Bool(
+		"gdrive-share-anyone",
+		false,
+		"make uploaded artifacts readable by anyone with the link",
+	)
+	logFormat := flagSet.String(
+		"log-format",
+		logging.FormatText,
+		"log output format: text or json",
+	)
+	logLevel := flagSet.String(
+		"log-level",
+		"info",
+		"default log level: debug, info, warn or error",
+	)
+	toolLogLevels := flagSet.String(
+		"tool-log-levels",
+		"",
+		"comma-separated per-tool log levels, e.g. git-summary=debug,literature-fetch=warn",
+	)
+	toolTimeout := flagSet.Duration(
+		"tool-timeout",
+		2*time.Minute,
+		"default deadline for a tool call (0 disables the deadline)",
+	)
+	toolTimeouts := flagSet.String(
+		"tool-timeouts",
+		"",
+		"comma-separated per-tool deadlines, e.g. git-summary=10m,literature-fetch=30s",
+	)
+	rateLimits := flagSet.String(
+		"rate-limits",
+		"",
+		"comma-separated outbound request limits per provider or host in requests/second[/burst], e.g. pubmed=10,europepmc=5/10",
+	)
+	retryAttempts := flagSet.Int(
+		"retry-attempts",
+		retry.DefaultPolicy.MaxAttempts,
+		"calls made to PubMed and Europe PMC when they rate limit or fail with a server error, "+
+			"including the first (1 disables retries)",
+	)
+	retryDelay := flagSet.Duration(
+		"retry-delay",
+		retry.DefaultPolicy.InitialDelay,
+		"wait before the first retry, doubled for every further retry",
+	)
+	retryMaxDelay := flagSet.Duration(
+		"retry-max-delay",
+		retry.DefaultPolicy.MaxDelay,
+		"longest wait between retries, unless the provider's Retry-After asks for longer",
+	)
+	maxHeavyTools := flagSet.Int(
+		"max-heavy-tools",
+		2,
+		"number of heavy tool calls (git clones, PDF renders) run at once; further calls queue (0 disables the limit)",
+	)
+	heavyTools := flagSet.String(
+		"heavy-tools",
+		strings.Join(concurrency.DefaultHeavyTools, ","),
+		"comma-separated tools subject to --max-heavy-tools",
+	)
+	progressInterval := flagSet.Duration(
+		"progress-interval",
+		progress.DefaultInterval,
+		"minimum time between streamed progress notifications, such as partial summaries, of a call",
+	)
+	uploadMaxBytes := flagSet.Int(
+		"upload-max-bytes",
+		50<<20,
+		"largest input accepted through the upload tool, in bytes",
+	)
+	uploadTTL := flagSet.Duration(
+		"upload-ttl",
+		time.Hour,
+		"how long an upload is kept after its last chunk",
+	)
+	documentsDir := flagSet.String(
+		"documents-dir",
+		"",
+		"directory the documents drafted with the document tool and their revisions are kept in, so they survive "+
+			"restarts (in memory when empty)",
+	)
+	idempotencyTTL := flagSet.Duration(
+		"idempotency-ttl",
+		24*time.Hour,
+		"how long the result of a call with an idempotency_key is returned for repeats of the call",
+	)
+	coverageRun := flagSet.Bool(
+		"coverage-run",
+		false,
+		"let coverage-report run go test in clones of requested repositories, which executes their code",
+	)
+	signingKeys := flagSet.String(
+		"signing-keys",
+		"",
+		"file of armored GPG public keys and SSH public keys that git-summary verifies commit signatures against",
+	)
+	glossary := flagSet.String(
+		"glossary",
+		"",
+		"JSON file of terms and the pages markdown and markdown_to_pdf link them to when asked, "+
+			"a page being a URL or a dictyBase gene ID",
+	)
+	literatureReplay := flagSet.String(
+		"literature-replay",
+		os.Getenv("DCR_MCP_LITERATURE_REPLAY"),
+		"cassette directory, or \"bundled\" for the shipped fixtures, whose recorded responses literature-fetch "+
+			"serves instead of reaching the network (default: $DCR_MCP_LITERATURE_REPLAY)",
+	)
+	literatureRecord := flagSet.String(
+		"literature-record",
+		os.Getenv("DCR_MCP_LITERATURE_RECORD"),
+		"cassette directory literature-fetch records provider responses to, for --literature-replay "+
+			"(default: $DCR_MCP_LITERATURE_RECORD)",
+	)
+	telemetryEndpoint := flagSet.String(
+		"telemetry-endpoint",
+		os.Getenv("DCR_MCP_TELEMETRY_ENDPOINT"),
+		"URL anonymous tool usage counts and error types are posted to, to help the maintainers prioritize "+
+			"(disabled when empty, default: $DCR_MCP_TELEMETRY_ENDPOINT)",
+	)
+	telemetryInterval := flagSet.Duration(
+		"telemetry-interval",
+		telemetry.DefaultInterval,
+		"time between telemetry reports",
+	)
+	snapshotDir := flagSet.String(
+		"snapshot-dir",
+		"",
+		"directory session snapshots are written to when the server stops and on POST /admin/snapshot "+
+			"(disabled when empty; snapshots hold uploaded data and call arguments)",
+	)
+	snapshotToken := flagSet.String(
+		"snapshot-token",
+		os.Getenv("DCR_MCP_SNAPSHOT_TOKEN"),
+		"bearer token POST /admin/snapshot requires; the endpoint is not served without one",
+	)
+	restoreSnapshot := flagSet.String(
+		"restore-snapshot",
+		"",
+		"session snapshot whose uploads, keyed results and resources are loaded at startup, for reproducing problems",
+	)
+	configPath := flagSet.String(
+		"config",
+		"",
+		"JSON file of prompt templates, timeouts and rate limits, reloaded when it changes",
+	)
+	profileName := flagSet.String(
+		"profile",
+		os.Getenv("DCR_MCP_PROFILE"),
+		"deployment preset for logging, artifact directory, rate limits and tools: "+
+			strings.Join(profile.Names(), ", ")+" (default: $DCR_MCP_PROFILE); explicit flags take precedence",
+	)
+	showVersion := flagSet.Bool("version", false, "print the version, commit and build date and exit")
+	if err := flagSet.Parse(args); err != nil {
+		return serverOptions{}, err
+	}
+	if *showVersion {
+		return serverOptions{showVersion: true}, nil
+	}
+	if *profileName != "" {
+		preset, err := profile.Lookup(*profileName)
+		if err != nil {
+			return serverOptions{}, fmt.Errorf("--profile: %w", err)
+		}
+		if err := preset.Apply(flagSet); err != nil {
+			return serverOptions{}, fmt.Errorf("--profile: %w", err)
+		}
+		*profileName = preset.Name
+	}
+
+	if !slices.Contains([]string{"local", "s3", "gdrive"}, *artifactStore) {
+		return serverOptions{}, fmt.Errorf("--artifact-store: unsupported backend %q", *artifactStore)
+	}
+	if *literatureReplay != "" && *literatureRecord != "" {
+		return serverOptions{}, errors.New("--literature-replay and --literature-record cannot be combined")
+	}
+	if *telemetryEndpoint != "" {
+		if endpoint, err := url.Parse(*telemetryEndpoint); err != nil || !slices.Contains([]string{"http", "https"}, endpoint.Scheme) {
+			return serverOptions{}, fmt.Errorf("--telemetry-endpoint: %q is not an http or https URL", *telemetryEndpoint)
+		}
+		if *telemetryInterval <= 0 {
+			return serverOptions{}, errors.New("--telemetry-interval must be positive")
+		}
+	}
+	if *webhookConfig != "" && *httpAddr == "" {
+		return serverOptions{}, errors.New("--webhook-config requires --http-addr")
+	}
+	selection, err := parseToolSelection(*enableTools, *disableTools)
+	if err != nil {
+		return serverOptions{}, fmt.Errorf("invalid tool selection: %w", err)
+	}
+	level, err := logging.ParseLevel(*logLevel)
+	if err != nil {
+		return serverOptions{}, fmt.Errorf("--log-level: %w", err)
+	}
+	toolLevels, err := logging.ParseToolLevels(*toolLogLevels)
+	if err != nil {
+		return serverOptions{}, fmt.Errorf("--tool-log-levels: %w", err)
+	}
+	if *toolTimeout < 0 {
+		return serverOptions{}, errors.New("--tool-timeout must not be negative")
+	}
+	perToolTimeouts, err := timeout.ParseToolTimeouts(*toolTimeouts)
+	if err != nil {
+		return serverOptions{}, fmt.Errorf("--tool-timeouts: %w", err)
+	}
+	if *maxHeavyTools < 0 {
+		return serverOptions{}, errors.New("--max-heavy-tools must not be negative")
+	}
+	if *uploadMaxBytes <= 0 || *uploadTTL <= 0 {
+		return serverOptions{}, errors.New("--upload-max-bytes and --upload-ttl must be positive")
+	}
+	if *idempotencyTTL <= 0 {
+		return serverOptions{}, errors.New("--idempotency-ttl must be positive")
+	}
+	limits, err := ratelimit.ParseLimits(*rateLimits)
+	if err != nil {
+		return serverOptions{}, fmt.Errorf("--rate-limits: %w", err)
+	}
+	retryPolicy := retry.Policy{
+		MaxAttempts:  *retryAttempts,
+		InitialDelay: *retryDelay,
+		MaxDelay:     *retryMaxDelay,
+	}
+	if err := retryPolicy.Validate(); err != nil {
+		return serverOptions{}, fmt.Errorf("--retry-attempts, --retry-delay, --retry-max-delay: %w", err)
+	}
+
+	return serverOptions{
+		selection:     selection,
+		httpAddr:      *httpAddr,
+		webhookConfig: *webhookConfig,
+		nats: natsOptions{
+			url:           *natsURL,
+			subject:       *natsSubject,
+			queue:         *natsQueue,
+			resultSubject: *natsResultSubject,
+		},
+		artifacts: artifactOptions{
+			backend: *artifactStore,
+			dir:     *artifactDir,
+			s3: artifact.S3Config{
+				Endpoint:        *s3Endpoint,
+				AccessKeyID:     os.Getenv("AWS_ACCESS_KEY_ID"),
+				SecretAccessKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+				Bucket:          *s3Bucket,
+				Prefix:          *s3Prefix,
+				Region:          *s3Region,
+				UseSSL:          *s3UseSSL,
+				URLExpiry:       *s3URLExpiry,
+			},
+			drive: artifact.DriveConfig{
+				CredentialsFile: *driveCredentials,
+				FolderID:        *driveFolder,
+				ShareAnyone:     *driveShareAnyone,
+			},
+		},
+		logConfig: logging.Config{
+			Format:     *logFormat,
+			Level:      level,
+			ToolLevels: toolLevels,
+		},
+		timeouts: timeout.Config{
+			Default:      *toolTimeout,
+			ToolTimeouts: perToolTimeouts,
+		},
+		rateLimits: limits,
+		retry:      retryPolicy,
+		concurrency: concurrency.Config{
+			MaxConcurrent: *maxHeavyTools,
+			Tools:         concurrency.ParseTools(*heavyTools),
+		},
+		progressInterval: *progressInterval,
+		uploads: uploadOptions{
+			maxBytes: *uploadMaxBytes,
+			ttl:      *uploadTTL,
+		},
+		documentsDir:   *documentsDir,
+		idempotencyTTL: *idempotencyTTL,
+		coverageRun:    *coverageRun,
+		signingKeys:    *signingKeys,
+		glossary:       *glossary,
+		literature: literatureOptions{
+			replay: *literatureReplay,
+			record: *literatureRecord,
+		},
+		telemetry: telemetryOptions{
+			endpoint: *telemetryEndpoint,
+			interval: *telemetryInterval,
+		},
+		snapshots: snapshotOptions{
+			dir:     *snapshotDir,
+			restore: *restoreSnapshot,
+			token:   *snapshotToken,
+		},
+		configPath: *configPath,
+		profile:    *profileName,
+	}, nil
+}
@@ -1,102 +1,442 @@
 package main
 
 import (
+	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net/http"
 	"os"
+	"time"
 
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/buildinfo"
+	"github.com/dictybase/dcr-mcp/pkg/concurrency"
+	"github.com/dictybase/dcr-mcp/pkg/document"
+	"github.com/dictybase/dcr-mcp/pkg/gateway"
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/natsqueue"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
 	"github.com/dictybase/dcr-mcp/pkg/prompts"
-	"github.com/dictybase/dcr-mcp/pkg/tools/gitsummary"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/reload"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/snapshot"
+	"github.com/dictybase/dcr-mcp/pkg/status"
+	"github.com/dictybase/dcr-mcp/pkg/telemetry"
+	"github.com/dictybase/dcr-mcp/pkg/timeout"
+	"github.com/dictybase/dcr-mcp/pkg/tooldefaults"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
 	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
-	"github.com/dictybase/dcr-mcp/pkg/tools/markdowntool"
-	"github.com/dictybase/dcr-mcp/pkg/tools/pdftool"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/toolschema"
+	"github.com/dictybase/dcr-mcp/pkg/toolversion"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/webhook"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
 	"github.com/mark3labs/mcp-go/server"
+	"github.com/nats-io/nats.go"
 )
 
 func main() {
+	if len(os.Args) > 1 && os.Args[1] == "config" {
+		os.Exit(runConfigCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	if len(os.Args) > 1 && os.Args[1] == "schema" {
+		os.Exit(runSchemaCommand(os.Args[2:], os.Stdout, os.Stderr))
+	}
+	opts, err := parseFlags(os.Args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		os.Exit(0)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "%v\n", err)
+		os.Exit(2)
+	}
+	if opts.showVersion {
+		fmt.Println(buildinfo.Get())
+		os.Exit(0)
+	}
+	loggers, err := logging.NewFactory(opts.logConfig)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid logging configuration: %v\n", err)
+		os.Exit(2)
+	}
+	if err := run(opts, loggers); err != nil {
+		loggers.Logger().Error("server error", "error", err)
+		os.Exit(1)
+	}
+}
+
+// run registers the tools and prompts and serves MCP over stdio, together
+// with any optional transports requested on the command line.
+func run(opts serverOptions, loggers *logging.Factory) error {
+	logger := loggers.Logger()
+	if opts.profile != "" {
+		logger.Info("using profile", "profile", opts.profile)
+	}
+	if err := ratelimit.Configure(opts.rateLimits); err != nil {
+		return fmt.Errorf("error configuring rate limits: %w", err)
+	}
+	if err := retry.Configure(opts.retry); err != nil {
+		return fmt.Errorf("error configuring retries: %w", err)
+	}
 	mcpServer := createMCPServer()
+	// The gateway doubles as the in-process tool catalog used by the
+	// HTTP, webhook and NATS integrations.
+	toolGateway := gateway.NewGateway(
+		gateway.WithLogger(logger.With("component", "gateway")),
+		gateway.WithVersion(buildinfo.Get().Version),
+	)
+	timeouts := opts.timeouts
+	timeouts.Logger = logger.With("component", "timeout")
+	if opts.configPath != "" {
+		// Deadlines from the configuration file replace these on reload.
+		timeouts.Settings = timeout.NewSettings(opts.timeouts)
+	}
+	limits := opts.concurrency
+	limits.Logger = logger.With("component", "concurrency")
+	keyed := idempotency.NewStore(
+		idempotency.WithTTL(opts.idempotencyTTL),
+		idempotency.WithLogger(logger.With("component", "idempotency")),
+	)
+	defaults := tooldefaults.NewStore(
+		tooldefaults.WithLogger(logger.With("component", "tooldefaults")),
+	)
+	catalog := resources.NewCatalog(
+		mcpServer,
+		resources.WithLogger(logger.With("component", "resources")),
+	)
+	uploads := upload.NewStore(
+		upload.WithMaxSize(opts.uploads.maxBytes),
+		upload.WithTTL(opts.uploads.ttl),
+		upload.WithLogger(logger.With("component", "upload")),
+	)
+	documents := document.NewStore(
+		document.WithDir(opts.documentsDir),
+		document.WithLogger(logger.With("component", "document")),
+	)
+	if err := documents.Load(); err != nil {
+		return err
+	}
+	snapshots := snapshot.NewManager(
+		snapshot.WithUploads(uploads),
+		snapshot.WithResults(keyed),
+		snapshot.WithResources(catalog),
+		snapshot.WithDir(opts.snapshots.dir),
+		snapshot.WithToken(opts.snapshots.token),
+		snapshot.WithVersion(buildinfo.Get().Version),
+		snapshot.WithLogger(logger.With("component", "snapshot")),
+	)
+	if opts.snapshots.restore != "" {
+		restored, err := snapshot.Load(opts.snapshots.restore)
+		if err != nil {
+			return err
+		}
+		snapshots.Restore(restored)
+	}
+	usage, stopTelemetry := startTelemetry(opts.telemetry, logger)
+	defer stopTelemetry()
+	// The middlewares run outermost first: metrics, usage, toolerror,
+	// toolversion, snapshots, keyed, defaults, timeout, progress and
+	// concurrency. Metrics and usage telemetry wrap the rest so failed and
+	// aborted calls count as errors, and toolerror turns any error a handler
+	// still returns into an error result. Toolversion renames deprecated
+	// parameters before anything else reads them and adds its warnings to
+	// the result. Snapshots record running calls with the arguments the
+	// client sent; keyed then answers repeated calls before defaults are
+	// added, so changing a default does not invalidate a key, and before
+	// the deadline starts. Progress reports and the concurrency limit run
+	// inside the deadline, so time spent queued counts towards it.
+	registrars := middlewareRegistrar{
+		next: multiRegistrar{mcpServer, toolGateway},
+		middlewares: []server.ToolHandlerMiddleware{
+			metrics.ToolMiddleware,
+			usage,
+			toolerror.Middleware,
+			toolversion.Middleware(logger.With("component", "toolversion")),
+			snapshots.Middleware(),
+			keyed.Middleware(),
+			defaults.Middleware(),
+			timeout.Middleware(timeouts),
+			progress.Middleware(opts.progressInterval),
+			concurrency.Middleware(limits),
+		},
+	}
 
-	registerTools(mcpServer)
-	registerPrompts(mcpServer)
+	store, err := newArtifactStore(opts.artifacts)
+	if err != nil {
+		return err
+	}
+	monitor := status.NewMonitor(
+		status.WithVersion(buildinfo.Get().Version),
+		status.WithLimits(status.Limits{
+			DefaultTimeout: opts.timeouts.Default,
+			ToolTimeouts:   opts.timeouts.ToolTimeouts,
+			MaxHeavyTools:  opts.concurrency.MaxConcurrent,
+			HeavyTools:     opts.concurrency.Tools,
+			RateLimits:     opts.rateLimits,
+			UploadMaxBytes: opts.uploads.maxBytes,
+			UploadTTL:      opts.uploads.ttl,
+		}),
+		status.WithLogger(logger.With("component", "status")),
+	)
+	shared := registry.Dependencies{
+		Store:     store,
+		Resources: catalog,
+		Uploads:   uploads,
+		Documents: documents,
+		Status:    monitor,
+		RunTests:  opts.coverageRun,
+		Tools:     toolGateway,
+		Literature: registry.LiteratureCassette{
+			Replay: opts.literature.replay,
+			Record: opts.literature.record,
+		},
+	}
+	if opts.literature.replay != "" {
+		logger.Info("replaying literature responses", "cassette", opts.literature.replay)
+	}
+	if opts.literature.record != "" {
+		logger.Info("recording literature responses", "cassette", opts.literature.record)
+	}
+	citations, err := literaturetool.NewCitationResolver(logger.With("component", "citations"), shared.Literature)
+	if err != nil {
+		return err
+	}
+	shared.Citations = citations
+	if opts.signingKeys != "" {
+		keys, err := worksummary.LoadTrustedKeys(opts.signingKeys)
+		if err != nil {
+			return err
+		}
+		logger.Info("loaded commit signing keys", "keys", keys.Len())
+		shared.SigningKeys = keys
+	}
+	if opts.glossary != "" {
+		glossary, err := markdown.LoadGlossary(opts.glossary)
+		if err != nil {
+			return err
+		}
+		logger.Info("loaded glossary", "terms", len(glossary))
+		shared.Glossary = glossary
+	}
+	registered, err := registerTools(registrars, opts.selection, loggers, shared)
+	if err != nil {
+		return fmt.Errorf("failed to register tools: %w", err)
+	}
+	names := make([]string, 0, len(registered))
+	for _, tool := range registered {
+		names = append(names, tool.GetName())
+	}
+	monitor.SetTools(names)
+	if err := registerPrompts(mcpServer, loggers); err != nil {
+		return fmt.Errorf("failed to register prompts: %w", err)
+	}
+	if opts.configPath != "" {
+		stop, err := startReloader(opts, mcpServer, timeouts.Settings, defaults, registered, logger)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
 
-	if err := server.ServeStdio(mcpServer); err != nil {
-		fmt.Fprintf(os.Stderr, "server error %v", err)
+	if opts.httpAddr != "" {
+		if opts.webhookConfig != "" {
+			if err := mountWebhooks(toolGateway, opts.webhookConfig, logger); err != nil {
+				return fmt.Errorf("failed to configure webhooks: %w", err)
+			}
+		}
+		toolGateway.Handle("GET /metrics", metrics.Handler())
+		toolGateway.Handle("GET /schema.json", toolschema.Handler(buildSchema(registered), logger))
+		if opts.snapshots.dir != "" && opts.snapshots.token != "" {
+			toolGateway.Handle("POST /admin/snapshot", snapshots.Handler())
+		}
+		go serveGateway(opts.httpAddr, toolGateway, logger)
 	}
+	if opts.nats.url != "" {
+		stop, err := startNATS(opts.nats, toolGateway, logger)
+		if err != nil {
+			return err
+		}
+		defer stop()
+	}
+
+	err = server.ServeStdio(mcpServer)
+	if opts.snapshots.dir != "" {
+		if _, saveErr := snapshots.Save(); saveErr != nil {
+			logger.Error("error saving session snapshot", "error", saveErr)
+		}
+	}
+	return err
 }
 
-// createMCPServer initializes the MCP server with capabilities.
+// createMCPServer initializes the MCP server with capabilities. The
+// version announced to clients carries the commit and build date.
 func createMCPServer() *server.MCPServer {
-	return server.NewMCPServer("DCR-MCP Server", "1.0.0",
+	return server.NewMCPServer("DCR-MCP Server", buildinfo.Get().String(),
 		server.WithToolCapabilities(true),
 		server.WithPromptCapabilities(true),
+		server.WithResourceCapabilities(false, true),
 		server.WithLogging(),
 	)
 }
 
-// registerTools creates and registers all tools with the MCP server.
-func registerTools(mcpServer *server.MCPServer) {
-	registerGitSummaryTool(mcpServer)
-	registerMarkdownTool(mcpServer)
-	registerPdfTool(mcpServer)
-	registerLiteratureTool(mcpServer)
+// serveGateway runs the HTTP+JSON gateway until it fails.
+func serveGateway(addr string, handler http.Handler, logger *slog.Logger) {
+	httpServer := &http.Server{
+		Addr:              addr,
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	logger.Info("HTTP gateway listening", "addr", addr)
+	err := httpServer.ListenAndServe()
+	if err != nil && !errors.Is(err, http.ErrServerClosed) {
+		logger.Error("gateway error", "error", err)
+	}
 }
 
-// registerGitSummaryTool creates and registers the git summary tool.
-func registerGitSummaryTool(mcpServer *server.MCPServer) {
-	gitSummaryTool, err := gitsummary.NewGitSummaryTool(
-		log.New(os.Stderr, "[git-summary] ", log.LstdFlags),
+// startTelemetry starts sending anonymous usage reports when an endpoint is
+// configured, and returns the middleware that counts the calls together
+// with the function that sends the last report and stops. Without an
+// endpoint the middleware passes calls through untouched.
+func startTelemetry(opts telemetryOptions, logger *slog.Logger) (server.ToolHandlerMiddleware, func()) {
+	if opts.endpoint == "" {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc { return next }, func() {}
+	}
+	reporter := telemetry.NewReporter(
+		opts.endpoint,
+		telemetry.WithInterval(opts.interval),
+		telemetry.WithLogger(logger.With("component", "telemetry")),
 	)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create git-summary tool: %v", err)
-		os.Exit(1)
+	logger.Info("sending anonymous usage telemetry", "endpoint", opts.endpoint, "interval", opts.interval)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reporter.Run(ctx)
+	}()
+	return reporter.Middleware, func() {
+		cancel()
+		<-done
 	}
-	mcpServer.AddTool(gitSummaryTool.GetTool(), gitSummaryTool.Handler)
 }
 
-// registerMarkdownTool creates and registers the markdown tool.
-func registerMarkdownTool(mcpServer *server.MCPServer) {
-	markdownTool, err := markdowntool.NewMarkdownTool(
-		log.New(os.Stderr, "[markdown] ", log.LstdFlags),
-	)
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create markdown tool: %v", err)
-		os.Exit(1)
+// newArtifactStore creates the configured artifact store backend.
+func newArtifactStore(opts artifactOptions) (artifact.Store, error) {
+	switch opts.backend {
+	case "s3":
+		store, err := artifact.NewS3Store(opts.s3)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create S3 artifact store: %w", err)
+		}
+		return store, nil
+	case "gdrive":
+		store, err := artifact.NewDriveStore(opts.drive)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Google Drive artifact store: %w", err)
+		}
+		return store, nil
+	default:
+		return artifact.NewLocalStore(opts.dir), nil
 	}
-	mcpServer.AddTool(markdownTool.GetTool(), markdownTool.Handler)
 }
 
-// registerPdfTool creates and registers the PDF tool.
-func registerPdfTool(mcpServer *server.MCPServer) {
-	pdfTool, err := pdftool.NewPdfTool(
-		log.New(os.Stderr, "[pdf-tool] ", log.LstdFlags),
-	)
+// startNATS connects to NATS and starts consuming tool-run requests. The
+// returned function drains the subscription and closes the connection.
+func startNATS(
+	opts natsOptions,
+	invoker natsqueue.ToolInvoker,
+	logger *slog.Logger,
+) (func(), error) {
+	conn, err := nats.Connect(opts.url, nats.Name("dcr-mcp"))
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create pdf tool: %v", err)
-		os.Exit(1)
+		return nil, fmt.Errorf("failed to connect to NATS at %s: %w", opts.url, err)
 	}
-	mcpServer.AddTool(pdfTool.GetTool(), pdfTool.Handler)
+	subscriber := natsqueue.NewSubscriber(
+		conn,
+		invoker,
+		natsqueue.WithSubject(opts.subject),
+		natsqueue.WithQueue(opts.queue),
+		natsqueue.WithResultSubject(opts.resultSubject),
+		natsqueue.WithLogger(logger.With("component", "nats")),
+	)
+	if err := subscriber.Start(); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return func() {
+		if err := subscriber.Stop(); err != nil {
+			logger.Error("failed to stop NATS subscriber", "error", err)
+		}
+		conn.Close()
+	}, nil
 }
 
-// registerLiteratureTool creates and registers the literature tool.
-func registerLiteratureTool(mcpServer *server.MCPServer) {
-	literatureTool, err := literaturetool.NewLiteratureTool(
-		log.New(os.Stderr, "[literature] ", log.LstdFlags),
+// startReloader applies the configuration file and keeps watching it for
+// changes. The returned function stops the watcher.
+func startReloader(
+	opts serverOptions,
+	mcpServer *server.MCPServer,
+	settings *timeout.Settings,
+	defaults *tooldefaults.Store,
+	tools []registry.Tool,
+	logger *slog.Logger,
+) (func(), error) {
+	reloader := reload.New(
+		opts.configPath,
+		mcpServer,
+		reload.WithTimeouts(settings, opts.timeouts),
+		reload.WithRateLimits(opts.rateLimits),
+		reload.WithToolDefaults(defaults),
+		reload.WithToolNames(registry.Names()),
+		reload.WithToolSchemas(inputSchemas(tools)),
+		reload.WithLogger(logger.With("component", "reload")),
 	)
+	if err := reloader.Load(); err != nil {
+		return nil, fmt.Errorf("failed to load configuration: %w", err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() {
+		if err := reloader.Watch(ctx); err != nil {
+			logger.Error("configuration reloading stopped", "error", err)
+		}
+	}()
+	return cancel, nil
+}
+
+// mountWebhooks loads the webhook configuration and serves inbound GitHub
+// events on the gateway.
+func mountWebhooks(
+	toolGateway *gateway.Gateway,
+	configPath string,
+	logger *slog.Logger,
+) error {
+	webhookConfig, err := webhook.LoadConfig(configPath)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create literature tool: %v", err)
-		os.Exit(1)
+		return err
 	}
-	mcpServer.AddTool(literatureTool.GetTool(), literatureTool.Handler)
+	toolGateway.Handle("POST /webhooks/github", webhook.NewHandler(
+		webhookConfig,
+		toolGateway,
+		webhook.WithLogger(logger.With("component", "webhook")),
+	))
+	return nil
 }
 
 // registerPrompts creates and registers all prompts with the MCP server.
-func registerPrompts(mcpServer *server.MCPServer) {
+func registerPrompts(mcpServer *server.MCPServer, loggers *logging.Factory) error {
 	emailPrompt, err := prompts.NewEmailPrompt(
-		log.New(os.Stderr, "[email-prompt] ", log.LstdFlags),
+		loggers.ToolLogger("draft_casual_email"),
 	)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "failed to create email prompt: %v", err)
-		os.Exit(1)
+		return fmt.Errorf("failed to create email prompt: %w", err)
 	}
 	mcpServer.AddPrompt(emailPrompt.GetPrompt(), emailPrompt.Handler)
+	return nil
 }
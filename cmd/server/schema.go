@@ -0,0 +1,92 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/buildinfo"
+	"github.com/dictybase/dcr-mcp/pkg/document"
+	"github.com/dictybase/dcr-mcp/pkg/status"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/toolschema"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// runSchemaCommand runs "schema", which prints the input schema,
+// annotations and examples of every registered tool as one JSON document,
+// and returns the exit code. Examples that do not match their tool's input
+// schema are printed instead and fail the command.
+func runSchemaCommand(args []string, stdout, stderr io.Writer) int {
+	if len(args) != 0 {
+		fmt.Fprintln(stderr, "usage: dcr-mcp-server schema")
+		return 2
+	}
+	tools, err := describedTools()
+	if err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	doc := buildSchema(tools)
+	failed := false
+	for _, tool := range doc.Tools {
+		if err := toolschema.CheckExamples(tool); err != nil {
+			fmt.Fprintln(stderr, err)
+			failed = true
+		}
+	}
+	if failed {
+		return 1
+	}
+	encoder := json.NewEncoder(stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		fmt.Fprintln(stderr, err)
+		return 1
+	}
+	return 0
+}
+
+// buildSchema describes tools under the server's name and version.
+func buildSchema(tools []registry.Tool) toolschema.Document {
+	return toolschema.Build(toolschema.Params{
+		Title:   "DCR-MCP Server",
+		Version: buildinfo.Get().Version,
+		Tools:   tools,
+	})
+}
+
+// describedTools creates every registered tool to read its definition.
+// The tools are only described, never run, so they get throwaway
+// dependencies.
+func describedTools() ([]registry.Tool, error) {
+	deps := registry.Dependencies{
+		Logger:    slog.New(slog.NewTextHandler(io.Discard, nil)),
+		Store:     artifact.NewLocalStore(os.TempDir()),
+		Uploads:   upload.NewStore(),
+		Documents: document.NewStore(),
+		Status:    status.NewMonitor(),
+	}
+	tools := make([]registry.Tool, 0, len(registry.Names()))
+	for _, name := range registry.Names() {
+		tool, err := registry.New(name, deps)
+		if err != nil {
+			return nil, err
+		}
+		tools = append(tools, tool)
+	}
+	return tools, nil
+}
+
+// inputSchemas returns the input schemas of tools keyed by tool name.
+func inputSchemas(tools []registry.Tool) map[string]mcp.ToolInputSchema {
+	schemas := make(map[string]mcp.ToolInputSchema, len(tools))
+	for _, tool := range tools {
+		schemas[tool.GetName()] = tool.GetTool().InputSchema
+	}
+	return schemas
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+
+	// Tool packages register themselves with the registry on import.
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/batchtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/calendartool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/citationtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/coveragetool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/dependencytool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/digesttool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/documenttool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/genelittool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/gitauthors"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/gitbranches"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/gitsummary"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/granttool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/htmlaudit"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/imagetool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/infotool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/journaltool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/k8stool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/licensetool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/markdowntool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/nltool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/onboardingtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/orcidtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/orgsummary"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/pdftool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/publishtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/repostats"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/searchtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/sitetool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/statustool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/todotool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/uploadtool"
+	_ "github.com/dictybase/dcr-mcp/pkg/tools/zoterotool"
+)
+
+// toolRegistrar is implemented by anything tools can be registered with,
+// such as the MCP server and the HTTP gateway.
+type toolRegistrar interface {
+	AddTool(tool mcp.Tool, handler server.ToolHandlerFunc)
+}
+
+// multiRegistrar registers every tool with all of its registrars.
+type multiRegistrar []toolRegistrar
+
+// AddTool implements toolRegistrar.
+func (mr multiRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	for _, registrar := range mr {
+		registrar.AddTool(tool, handler)
+	}
+}
+
+// middlewareRegistrar wraps every tool handler with middlewares before
+// passing it on, so all transports share the same instrumentation.
+type middlewareRegistrar struct {
+	next        toolRegistrar
+	middlewares []server.ToolHandlerMiddleware
+}
+
+// AddTool implements toolRegistrar. Middlewares are applied in order, the
+// first one being the outermost.
+func (mr middlewareRegistrar) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	for i := len(mr.middlewares) - 1; i >= 0; i-- {
+		handler = mr.middlewares[i](handler)
+	}
+	mr.next.AddTool(tool, handler)
+}
+
+// toolSelection holds the tool names requested on the command line.
+type toolSelection struct {
+	enabled  map[string]bool
+	disabled map[string]bool
+}
+
+// isEnabled reports whether the named tool should be registered.
+func (ts toolSelection) isEnabled(name string) bool {
+	if ts.disabled[name] {
+		return false
+	}
+	if len(ts.enabled) == 0 {
+		return true
+	}
+	return ts.enabled[name]
+}
+
+// parseToolSelection builds a toolSelection from comma-separated tool lists,
+// rejecting names that do not match any registered tool.
+func parseToolSelection(enable, disable string) (toolSelection, error) {
+	enabled, err := parseToolList(enable)
+	if err != nil {
+		return toolSelection{}, fmt.Errorf("--enable-tools: %w", err)
+	}
+	disabled, err := parseToolList(disable)
+	if err != nil {
+		return toolSelection{}, fmt.Errorf("--disable-tools: %w", err)
+	}
+	return toolSelection{enabled: enabled, disabled: disabled}, nil
+}
+
+// parseToolList splits a comma-separated list of tool names into a set.
+func parseToolList(list string) (map[string]bool, error) {
+	names := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		if !registry.IsRegistered(name) {
+			return nil, fmt.Errorf("unknown tool %q", name)
+		}
+		names[name] = true
+	}
+	return names, nil
+}
+
+// registerTools creates and registers the selected tools, logs the tools
+// that were skipped and returns the registered ones. Every tool receives
+// the shared dependencies together with its own logger.
+func registerTools(
+	registrar toolRegistrar,
+	selection toolSelection,
+	loggers *logging.Factory,
+	shared registry.Dependencies,
+) ([]registry.Tool, error) {
+	var registered []registry.Tool
+	var skipped []string
+	for _, name := range registry.Names() {
+		if !selection.isEnabled(name) {
+			skipped = append(skipped, name)
+			continue
+		}
+		deps := shared
+		deps.Logger = loggers.ToolLogger(name)
+		tool, err := registry.New(name, deps)
+		if err != nil {
+			return nil, err
+		}
+		registrar.AddTool(tool.GetTool(), tool.Handler)
+		registered = append(registered, tool)
+	}
+	if len(skipped) > 0 {
+		loggers.Logger().Info("skipped tools", "tools", skipped)
+	}
+	return registered, nil
+}
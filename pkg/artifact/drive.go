@@ -0,0 +1,205 @@
+package artifact
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"path"
+	"strings"
+	"time"
+)
+
+const defaultDriveEndpoint = "https://www.googleapis.com"
+
+// DriveConfig holds the settings for uploading artifacts to Google Drive.
+type DriveConfig struct {
+	// CredentialsFile is the path to a service account key in JSON format.
+	CredentialsFile string `validate:"required"`
+	// FolderID is the Drive folder artifacts are uploaded to. The folder
+	// must be shared with the service account.
+	FolderID string `validate:"required"`
+	// ShareAnyone makes uploaded files readable by anyone with the link.
+	// Otherwise the link only works for users with access to the folder.
+	ShareAnyone bool
+	// Endpoint overrides the Drive API base URL.
+	Endpoint string
+}
+
+// DriveStore uploads artifacts to a Google Drive folder using a service
+// account and returns their share links, which is how lab documents are
+// distributed.
+type DriveStore struct {
+	tokens      *serviceAccountTokenSource
+	httpClient  *http.Client
+	endpoint    string
+	folderID    string
+	shareAnyone bool
+}
+
+// driveFile is the subset of the Drive file resource returned on upload.
+type driveFile struct {
+	ID          string `json:"id"`
+	WebViewLink string `json:"webViewLink"`
+}
+
+// NewDriveStore creates a DriveStore from the given configuration.
+func NewDriveStore(cfg DriveConfig) (*DriveStore, error) {
+	if err := validate.Struct(cfg); err != nil {
+		return nil, fmt.Errorf("invalid Google Drive configuration: %w", err)
+	}
+	httpClient := &http.Client{Timeout: 2 * time.Minute}
+	tokens, err := newServiceAccountTokenSource(cfg.CredentialsFile, httpClient)
+	if err != nil {
+		return nil, err
+	}
+	endpoint := cfg.Endpoint
+	if endpoint == "" {
+		endpoint = defaultDriveEndpoint
+	}
+	return &DriveStore{
+		tokens:      tokens,
+		httpClient:  httpClient,
+		endpoint:    strings.TrimSuffix(endpoint, "/"),
+		folderID:    cfg.FolderID,
+		shareAnyone: cfg.ShareAnyone,
+	}, nil
+}
+
+// Put implements Store.
+func (ds *DriveStore) Put(ctx context.Context, params PutParams) (*Artifact, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, fmt.Errorf("invalid artifact parameters: %w", err)
+	}
+	token, err := ds.tokens.Token(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to authenticate with Google Drive: %w", err)
+	}
+	name := path.Base(params.Name)
+	file, err := ds.upload(ctx, token, name, params)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s to Google Drive: %w", name, err)
+	}
+	if ds.shareAnyone {
+		if err := ds.shareWithAnyone(ctx, token, file.ID); err != nil {
+			return nil, fmt.Errorf("failed to share %s: %w", name, err)
+		}
+	}
+
+	return &Artifact{
+		Name:     params.Name,
+		Location: fmt.Sprintf("gdrive://%s/%s", ds.folderID, file.ID),
+		URL:      file.WebViewLink,
+	}, nil
+}
+
+// upload creates the file in the configured folder with a multipart upload.
+func (ds *DriveStore) upload(
+	ctx context.Context,
+	token, name string,
+	params PutParams,
+) (*driveFile, error) {
+	metadata, err := json.Marshal(map[string]any{
+		"name":    name,
+		"parents": []string{ds.folderID},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error encoding file metadata: %w", err)
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	metadataPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {"application/json; charset=UTF-8"},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating metadata part: %w", err)
+	}
+	if _, err := metadataPart.Write(metadata); err != nil {
+		return nil, fmt.Errorf("error writing metadata part: %w", err)
+	}
+	mediaPart, err := writer.CreatePart(textproto.MIMEHeader{
+		"Content-Type": {params.ContentType},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error creating media part: %w", err)
+	}
+	if _, err := io.Copy(mediaPart, params.Body); err != nil {
+		return nil, fmt.Errorf("error writing media part: %w", err)
+	}
+	if err := writer.Close(); err != nil {
+		return nil, fmt.Errorf("error finishing multipart body: %w", err)
+	}
+
+	uploadURL := ds.endpoint + "/upload/drive/v3/files" +
+		"?uploadType=multipart&supportsAllDrives=true&fields=id,webViewLink"
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, uploadURL, &body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating upload request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "multipart/related; boundary="+writer.Boundary())
+
+	var file driveFile
+	if err := ds.do(req, &file); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
+
+// shareWithAnyone grants read access to anyone holding the file link.
+func (ds *DriveStore) shareWithAnyone(ctx context.Context, token, fileID string) error {
+	permission, err := json.Marshal(map[string]string{
+		"role": "reader",
+		"type": "anyone",
+	})
+	if err != nil {
+		return fmt.Errorf("error encoding permission: %w", err)
+	}
+	permissionURL := fmt.Sprintf(
+		"%s/drive/v3/files/%s/permissions?supportsAllDrives=true",
+		ds.endpoint,
+		fileID,
+	)
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		permissionURL,
+		bytes.NewReader(permission),
+	)
+	if err != nil {
+		return fmt.Errorf("error creating permission request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	return ds.do(req, nil)
+}
+
+// do sends a Drive API request and decodes the JSON response into out,
+// unless out is nil.
+func (ds *DriveStore) do(req *http.Request, out any) error {
+	resp, err := ds.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Google Drive: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"google Drive returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding Google Drive response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,180 @@
+package artifact
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	driveScope      = "https://www.googleapis.com/auth/drive.file"
+	defaultTokenURI = "https://oauth2.googleapis.com/token"
+	jwtGrantType    = "urn:ietf:params:oauth:grant-type:jwt-bearer"
+)
+
+// serviceAccountKey holds the fields of a Google service account key file
+// needed to request access tokens.
+type serviceAccountKey struct {
+	ClientEmail  string `json:"client_email"  validate:"required"`
+	PrivateKey   string `json:"private_key"   validate:"required"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// serviceAccountTokenSource exchanges signed JWT assertions for OAuth2
+// access tokens and caches them until shortly before they expire.
+type serviceAccountTokenSource struct {
+	key        serviceAccountKey
+	signer     *rsa.PrivateKey
+	httpClient *http.Client
+
+	mu      sync.Mutex
+	token   string
+	expires time.Time
+}
+
+// newServiceAccountTokenSource reads a service account key file.
+func newServiceAccountTokenSource(
+	credentialsFile string,
+	httpClient *http.Client,
+) (*serviceAccountTokenSource, error) {
+	data, err := os.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, fmt.Errorf("error reading credentials file %s: %w", credentialsFile, err)
+	}
+	var key serviceAccountKey
+	if err := json.Unmarshal(data, &key); err != nil {
+		return nil, fmt.Errorf("error parsing credentials file %s: %w", credentialsFile, err)
+	}
+	if err := validate.Struct(key); err != nil {
+		return nil, fmt.Errorf("invalid service account key: %w", err)
+	}
+	if key.TokenURI == "" {
+		key.TokenURI = defaultTokenURI
+	}
+	signer, err := parsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return &serviceAccountTokenSource{
+		key:        key,
+		signer:     signer,
+		httpClient: httpClient,
+	}, nil
+}
+
+// parsePrivateKey decodes the PEM encoded RSA key of a service account.
+func parsePrivateKey(pemKey string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(pemKey))
+	if block == nil {
+		return nil, errors.New("service account private key is not PEM encoded")
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		rsaKey, pkcs1Err := x509.ParsePKCS1PrivateKey(block.Bytes)
+		if pkcs1Err != nil {
+			return nil, fmt.Errorf("error parsing service account private key: %w", err)
+		}
+		return rsaKey, nil
+	}
+	rsaKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("service account private key is not an RSA key")
+	}
+	return rsaKey, nil
+}
+
+// Token returns a valid access token, requesting a new one when needed.
+func (ts *serviceAccountTokenSource) Token(ctx context.Context) (string, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if ts.token != "" && time.Now().Before(ts.expires) {
+		return ts.token, nil
+	}
+
+	assertion, err := ts.assertion(time.Now())
+	if err != nil {
+		return "", err
+	}
+	form := url.Values{
+		"grant_type": {jwtGrantType},
+		"assertion":  {assertion},
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		ts.key.TokenURI,
+		strings.NewReader(form.Encode()),
+	)
+	if err != nil {
+		return "", fmt.Errorf("error creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	resp, err := ts.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error requesting access token: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("token endpoint returned status %d", resp.StatusCode)
+	}
+
+	var payload struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&payload); err != nil {
+		return "", fmt.Errorf("error decoding token response: %w", err)
+	}
+	if payload.AccessToken == "" {
+		return "", errors.New("token endpoint returned an empty access token")
+	}
+	ts.token = payload.AccessToken
+	// Refresh a minute early so in-flight uploads do not race the expiry.
+	ts.expires = time.Now().Add(time.Duration(payload.ExpiresIn)*time.Second - time.Minute)
+	return ts.token, nil
+}
+
+// assertion builds the RS256 signed JWT used in the token request.
+func (ts *serviceAccountTokenSource) assertion(now time.Time) (string, error) {
+	header, err := json.Marshal(map[string]string{
+		"alg": "RS256",
+		"typ": "JWT",
+		"kid": ts.key.PrivateKeyID,
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding JWT header: %w", err)
+	}
+	claims, err := json.Marshal(map[string]any{
+		"iss":   ts.key.ClientEmail,
+		"scope": driveScope,
+		"aud":   ts.key.TokenURI,
+		"iat":   now.Unix(),
+		"exp":   now.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("error encoding JWT claims: %w", err)
+	}
+	unsigned := base64.RawURLEncoding.EncodeToString(header) + "." +
+		base64.RawURLEncoding.EncodeToString(claims)
+	digest := sha256.Sum256([]byte(unsigned))
+	signature, err := rsa.SignPKCS1v15(rand.Reader, ts.signer, crypto.SHA256, digest[:])
+	if err != nil {
+		return "", fmt.Errorf("error signing JWT: %w", err)
+	}
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(signature), nil
+}
@@ -0,0 +1,143 @@
+package artifact
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeServiceAccountKey writes a throwaway service account key whose token
+// endpoint points at tokenURI.
+func writeServiceAccountKey(t *testing.T, tokenURI string) string {
+	t.Helper()
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	der, err := x509.MarshalPKCS8PrivateKey(rsaKey)
+	require.NoError(t, err)
+	data, err := json.Marshal(map[string]string{
+		"client_email": "dcr@example.iam.gserviceaccount.com",
+		"private_key":  string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})),
+		"token_uri":    tokenURI,
+	})
+	require.NoError(t, err)
+	path := filepath.Join(t.TempDir(), "credentials.json")
+	require.NoError(t, os.WriteFile(path, data, 0o600))
+	return path
+}
+
+func TestDriveStore_Put(t *testing.T) {
+	t.Parallel()
+	var tokenRequests int
+	var shared bool
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, r *http.Request) {
+		tokenRequests++
+		assert.NoError(t, r.ParseForm())
+		assert.Equal(t, jwtGrantType, r.PostForm.Get("grant_type"))
+		assert.Len(t, strings.Split(r.PostForm.Get("assertion"), "."), 3)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, `{"access_token":"secret","expires_in":3600}`)
+	})
+	mux.HandleFunc("POST /upload/drive/v3/files", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		_, mediaParams, err := mime.ParseMediaType(r.Header.Get("Content-Type"))
+		assert.NoError(t, err)
+		reader := multipart.NewReader(r.Body, mediaParams["boundary"])
+		metadataPart, err := reader.NextPart()
+		assert.NoError(t, err)
+		var metadata struct {
+			Name    string   `json:"name"`
+			Parents []string `json:"parents"`
+		}
+		assert.NoError(t, json.NewDecoder(metadataPart).Decode(&metadata))
+		assert.Equal(t, "summary.pdf", metadata.Name)
+		assert.Equal(t, []string{"folder-1"}, metadata.Parents)
+		mediaPart, err := reader.NextPart()
+		assert.NoError(t, err)
+		content, _ := io.ReadAll(mediaPart)
+		assert.Equal(t, "%PDF-test", string(content))
+		_, _ = io.WriteString(w, `{"id":"file-1","webViewLink":"https://drive.example/file-1"}`)
+	})
+	mux.HandleFunc("POST /drive/v3/files/file-1/permissions", func(w http.ResponseWriter, _ *http.Request) {
+		shared = true
+		_, _ = io.WriteString(w, `{"id":"anyoneWithLink"}`)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := NewDriveStore(DriveConfig{
+		CredentialsFile: writeServiceAccountKey(t, server.URL+"/token"),
+		FolderID:        "folder-1",
+		ShareAnyone:     true,
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+
+	for range 2 {
+		stored, err := store.Put(context.Background(), PutParams{
+			Name:        "reports/summary.pdf",
+			ContentType: "application/pdf",
+			Body:        strings.NewReader("%PDF-test"),
+			Size:        -1,
+		})
+		require.NoError(t, err)
+		assert.Equal(t, "gdrive://folder-1/file-1", stored.Location)
+		assert.Equal(t, "https://drive.example/file-1", stored.URL)
+	}
+	assert.True(t, shared)
+	assert.Equal(t, 1, tokenRequests, "access token should be cached")
+}
+
+func TestDriveStore_PutError(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /token", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"access_token":"secret","expires_in":3600}`)
+	})
+	mux.HandleFunc("POST /upload/drive/v3/files", func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, `{"error":"folder not found"}`, http.StatusNotFound)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	store, err := NewDriveStore(DriveConfig{
+		CredentialsFile: writeServiceAccountKey(t, server.URL+"/token"),
+		FolderID:        "missing",
+		Endpoint:        server.URL,
+	})
+	require.NoError(t, err)
+
+	_, err = store.Put(context.Background(), PutParams{
+		Name:        "summary.pdf",
+		ContentType: "application/pdf",
+		Body:        strings.NewReader("data"),
+	})
+	require.ErrorContains(t, err, "status 404")
+}
+
+func TestNewDriveStore_Validation(t *testing.T) {
+	t.Parallel()
+	_, err := NewDriveStore(DriveConfig{FolderID: "folder-1"})
+	require.Error(t, err)
+
+	_, err = NewDriveStore(DriveConfig{
+		CredentialsFile: filepath.Join(t.TempDir(), "missing.json"),
+		FolderID:        "folder-1",
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,45 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// LocalStore writes artifacts to the local filesystem.
+type LocalStore struct {
+	root string
+}
+
+// NewLocalStore creates a LocalStore that resolves relative artifact names
+// against root. An empty root resolves them against the working directory.
+func NewLocalStore(root string) *LocalStore {
+	return &LocalStore{root: root}
+}
+
+// Put implements Store.
+func (ls *LocalStore) Put(_ context.Context, params PutParams) (*Artifact, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, fmt.Errorf("invalid artifact parameters: %w", err)
+	}
+	path := params.Name
+	if !filepath.IsAbs(path) && ls.root != "" {
+		path = filepath.Join(ls.root, path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("error creating directory for %s: %w", path, err)
+	}
+
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("error creating file %s: %w", path, err)
+	}
+	defer file.Close()
+	if _, err := io.Copy(file, params.Body); err != nil {
+		return nil, fmt.Errorf("error writing file %s: %w", path, err)
+	}
+
+	return &Artifact{Name: params.Name, Location: path}, nil
+}
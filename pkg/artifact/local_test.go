@@ -0,0 +1,59 @@
+package artifact
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalStore_Put(t *testing.T) {
+	t.Parallel()
+	root := t.TempDir()
+	store := NewLocalStore(root)
+
+	stored, err := store.Put(context.Background(), PutParams{
+		Name:        "reports/summary.pdf",
+		ContentType: "application/pdf",
+		Body:        strings.NewReader("%PDF-test"),
+		Size:        -1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "reports/summary.pdf", stored.Name)
+	assert.Equal(t, filepath.Join(root, "reports", "summary.pdf"), stored.Location)
+	assert.Empty(t, stored.URL)
+
+	data, err := os.ReadFile(stored.Location)
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-test", string(data))
+}
+
+func TestLocalStore_PutAbsolutePath(t *testing.T) {
+	t.Parallel()
+	target := filepath.Join(t.TempDir(), "absolute.pdf")
+	store := NewLocalStore("/should/not/be/used")
+
+	stored, err := store.Put(context.Background(), PutParams{
+		Name:        target,
+		ContentType: "application/pdf",
+		Body:        strings.NewReader("data"),
+		Size:        4,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, target, stored.Location)
+}
+
+func TestLocalStore_PutValidation(t *testing.T) {
+	t.Parallel()
+	store := NewLocalStore(t.TempDir())
+
+	_, err := store.Put(context.Background(), PutParams{
+		ContentType: "application/pdf",
+		Body:        strings.NewReader("data"),
+	})
+	require.Error(t, err)
+}
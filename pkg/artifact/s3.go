@@ -0,0 +1,89 @@
+package artifact
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+)
+
+// S3Config holds the connection settings for an S3-compatible store.
+type S3Config struct {
+	Endpoint        string `validate:"required"`
+	AccessKeyID     string `validate:"required"`
+	SecretAccessKey string `validate:"required"`
+	Bucket          string `validate:"required"`
+	// Prefix is prepended to every object key.
+	Prefix string
+	Region string
+	UseSSL bool
+	// URLExpiry is the lifetime of presigned download URLs.
+	URLExpiry time.Duration
+}
+
+// S3Store writes artifacts to an S3 or MinIO bucket and returns presigned
+// download URLs, so artifacts survive container restarts.
+type S3Store struct {
+	client    *minio.Client
+	bucket    string
+	prefix    string
+	urlExpiry time.Duration
+}
+
+// NewS3Store creates an S3Store from the given configuration.
+func NewS3Store(cfg S3Config) (*S3Store, error) {
+	if err := validate.Struct(cfg); err != nil {
+		return nil, fmt.Errorf("invalid S3 configuration: %w", err)
+	}
+	client, err := minio.New(cfg.Endpoint, &minio.Options{
+		Creds:  credentials.NewStaticV4(cfg.AccessKeyID, cfg.SecretAccessKey, ""),
+		Secure: cfg.UseSSL,
+		Region: cfg.Region,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+	urlExpiry := cfg.URLExpiry
+	if urlExpiry <= 0 {
+		urlExpiry = 24 * time.Hour
+	}
+	return &S3Store{
+		client:    client,
+		bucket:    cfg.Bucket,
+		prefix:    cfg.Prefix,
+		urlExpiry: urlExpiry,
+	}, nil
+}
+
+// Put implements Store.
+func (ss *S3Store) Put(ctx context.Context, params PutParams) (*Artifact, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, fmt.Errorf("invalid artifact parameters: %w", err)
+	}
+	key := path.Join(ss.prefix, path.Base(params.Name))
+	_, err := ss.client.PutObject(
+		ctx,
+		ss.bucket,
+		key,
+		params.Body,
+		params.Size,
+		minio.PutObjectOptions{ContentType: params.ContentType},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to upload %s to bucket %s: %w", key, ss.bucket, err)
+	}
+
+	presigned, err := ss.client.PresignedGetObject(ctx, ss.bucket, key, ss.urlExpiry, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to presign URL for %s: %w", key, err)
+	}
+
+	return &Artifact{
+		Name:     params.Name,
+		Location: fmt.Sprintf("s3://%s/%s", ss.bucket, key),
+		URL:      presigned.String(),
+	}, nil
+}
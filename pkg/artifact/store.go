@@ -0,0 +1,36 @@
+package artifact
+
+import (
+	"context"
+	"io"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Artifact describes a file written to a Store.
+type Artifact struct {
+	// Name is the name the artifact was stored under.
+	Name string `json:"name"`
+	// Location identifies where the artifact lives, e.g. a file path or an
+	// s3:// URI.
+	Location string `json:"location"`
+	// URL is a download link for the artifact, if the store provides one.
+	URL string `json:"url,omitempty"`
+}
+
+// PutParams holds the parameters for storing an artifact.
+type PutParams struct {
+	Name        string    `validate:"required"`
+	ContentType string    `validate:"required"`
+	Body        io.Reader `validate:"required"`
+	// Size is the length of Body in bytes, or -1 when unknown.
+	Size int64
+}
+
+// Store persists artifacts generated by tools, such as PDFs and exports.
+type Store interface {
+	Put(ctx context.Context, params PutParams) (*Artifact, error)
+}
@@ -0,0 +1,108 @@
+// Package buildinfo holds the version, commit and build date of the
+// binary. Release builds set them through the linker:
+//
+//	go build -ldflags "-X github.com/dictybase/dcr-mcp/pkg/buildinfo.Version=v1.2.0 \
+//	  -X github.com/dictybase/dcr-mcp/pkg/buildinfo.Commit=$(git rev-parse HEAD) \
+//	  -X github.com/dictybase/dcr-mcp/pkg/buildinfo.Date=$(date -u +%Y-%m-%dT%H:%M:%SZ)"
+//
+// Values left unset are filled in from the module and VCS information the
+// Go toolchain embeds, so go install and plain go build still report them.
+package buildinfo
+
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"strings"
+)
+
+// Set through -ldflags "-X ...".
+var (
+	Version = ""
+	Commit  = ""
+	Date    = ""
+)
+
+// devVersion is reported when no version is known.
+const devVersion = "dev"
+
+// Info describes the build of the running binary.
+type Info struct {
+	Version   string `json:"version"`
+	Commit    string `json:"commit,omitempty"`
+	Date      string `json:"date,omitempty"`
+	Modified  bool   `json:"modified,omitempty"`
+	GoVersion string `json:"go_version"`
+	Platform  string `json:"platform"`
+}
+
+// Get returns the build information of the running binary.
+func Get() Info {
+	info := Info{
+		Version:   Version,
+		Commit:    Commit,
+		Date:      Date,
+		GoVersion: runtime.Version(),
+		Platform:  runtime.GOOS + "/" + runtime.GOARCH,
+	}
+	if embedded, ok := debug.ReadBuildInfo(); ok {
+		fillFromBuildInfo(&info, embedded)
+	}
+	if info.Version == "" {
+		info.Version = devVersion
+	}
+	return info
+}
+
+// fillFromBuildInfo fills the fields not set by the linker from the
+// information embedded by the Go toolchain. The VCS details are only used
+// when no commit was linked, and the date is then the commit time.
+func fillFromBuildInfo(info *Info, embedded *debug.BuildInfo) {
+	if info.Version == "" && embedded.Main.Version != "(devel)" {
+		info.Version = embedded.Main.Version
+	}
+	linked := info.Commit != ""
+	for _, setting := range embedded.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			if !linked {
+				info.Commit = setting.Value
+			}
+		case "vcs.time":
+			if !linked && info.Date == "" {
+				info.Date = setting.Value
+			}
+		case "vcs.modified":
+			if !linked {
+				info.Modified = setting.Value == "true"
+			}
+		}
+	}
+}
+
+// ShortCommit returns the first twelve characters of the commit.
+func (i Info) ShortCommit() string {
+	const length = 12
+	if len(i.Commit) > length {
+		return i.Commit[:length]
+	}
+	return i.Commit
+}
+
+// String formats the information on one line, e.g.
+// "v1.2.0 (commit 3f2a9c1d0b7e, built 2025-07-01T10:00:00Z, go1.24.4 linux/amd64)".
+func (i Info) String() string {
+	details := make([]string, 0, 3)
+	if i.Commit != "" {
+		commit := "commit " + i.ShortCommit()
+		if i.Modified {
+			commit += "-dirty"
+		}
+		details = append(details, commit)
+	}
+	if i.Date != "" {
+		details = append(details, "built "+i.Date)
+	}
+	details = append(details, i.GoVersion+" "+i.Platform)
+	return fmt.Sprintf("%s (%s)", i.Version, strings.Join(details, ", "))
+}
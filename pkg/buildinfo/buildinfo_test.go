@@ -0,0 +1,65 @@
+package buildinfo
+
+import (
+	"runtime/debug"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFillFromBuildInfo(t *testing.T) {
+	t.Parallel()
+	embedded := &debug.BuildInfo{
+		Main: debug.Module{Version: "v1.2.0"},
+		Settings: []debug.BuildSetting{
+			{Key: "vcs.revision", Value: "3f2a9c1d0b7e4a5b6c7d8e9f"},
+			{Key: "vcs.time", Value: "2025-07-01T10:00:00Z"},
+			{Key: "vcs.modified", Value: "true"},
+		},
+	}
+
+	info := Info{}
+	fillFromBuildInfo(&info, embedded)
+	assert.Equal(t, "v1.2.0", info.Version)
+	assert.Equal(t, "3f2a9c1d0b7e4a5b6c7d8e9f", info.Commit)
+	assert.Equal(t, "2025-07-01T10:00:00Z", info.Date)
+	assert.True(t, info.Modified)
+
+	linked := Info{Version: "v2.0.0", Commit: "abc123", Date: "2025-08-01"}
+	fillFromBuildInfo(&linked, embedded)
+	assert.Equal(t, Info{Version: "v2.0.0", Commit: "abc123", Date: "2025-08-01"}, linked)
+
+	devel := Info{}
+	fillFromBuildInfo(&devel, &debug.BuildInfo{Main: debug.Module{Version: "(devel)"}})
+	assert.Empty(t, devel.Version)
+}
+
+func TestInfo_String(t *testing.T) {
+	t.Parallel()
+	info := Info{
+		Version:   "v1.2.0",
+		Commit:    "3f2a9c1d0b7e4a5b6c7d8e9f",
+		Date:      "2025-07-01T10:00:00Z",
+		Modified:  true,
+		GoVersion: "go1.24.4",
+		Platform:  "linux/amd64",
+	}
+	assert.Equal(
+		t,
+		"v1.2.0 (commit 3f2a9c1d0b7e-dirty, built 2025-07-01T10:00:00Z, go1.24.4 linux/amd64)",
+		info.String(),
+	)
+	assert.Equal(
+		t,
+		"dev (go1.24.4 linux/amd64)",
+		Info{Version: "dev", GoVersion: "go1.24.4", Platform: "linux/amd64"}.String(),
+	)
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	info := Get()
+	assert.NotEmpty(t, info.Version)
+	assert.NotEmpty(t, info.GoVersion)
+	assert.NotEmpty(t, info.Platform)
+}
@@ -0,0 +1,103 @@
+package concurrency
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultHeavyTools are the tools that clone repositories or render PDFs.
+var DefaultHeavyTools = []string{
+	"git-summary",
+	"git-authors",
+	"git-calendar",
+	"org-summary",
+	"onboarding-brief",
+	"repo-stats",
+	"todo-scan",
+	"coverage-report",
+	"dependency-digest",
+	"license-scan",
+	"k8s-manifest-summary",
+	"dictybase-digest",
+	"markdown_to_pdf",
+	"publish",
+}
+
+// Config holds the limit enforced by the middleware.
+type Config struct {
+	// MaxConcurrent is the number of heavy tool calls that may run at
+	// once. Zero or less disables the limit.
+	MaxConcurrent int
+	// Tools names the heavy tools sharing the limit.
+	Tools  []string
+	Logger *slog.Logger
+}
+
+// Middleware returns a tool handler middleware that runs at most
+// cfg.MaxConcurrent calls of the heavy tools at a time. Queued calls report
+// their position through the progress reporter of their context, and give
+// up their place when their context is done.
+func Middleware(cfg Config) server.ToolHandlerMiddleware {
+	if cfg.MaxConcurrent <= 0 || len(cfg.Tools) == 0 {
+		return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+			return next
+		}
+	}
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	heavy := make(map[string]bool, len(cfg.Tools))
+	for _, name := range cfg.Tools {
+		heavy[name] = true
+	}
+	semaphore := NewSemaphore(cfg.MaxConcurrent)
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(
+			ctx context.Context,
+			request mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			name := request.Params.Name
+			if !heavy[name] {
+				return next(ctx, request)
+			}
+			reporter := progress.FromContext(ctx)
+			initial := 0
+			err := semaphore.Acquire(ctx, func(position int) {
+				logger.Info("tool call queued", "tool", name, "position", position)
+				if initial == 0 {
+					initial = position
+				}
+				// Queue progress stays below 1 so the tool's own progress,
+				// counted from 1, follows on.
+				reporter.Report(
+					float64(initial-position)/float64(initial),
+					0,
+					fmt.Sprintf("waiting for a free slot, position %d in queue", position),
+				)
+			})
+			if err != nil {
+				return nil, fmt.Errorf("%s call abandoned while queued: %w", name, err)
+			}
+			defer semaphore.Release()
+			return next(ctx, request)
+		}
+	}
+}
+
+// ParseTools parses a comma-separated list of tool names.
+func ParseTools(spec string) []string {
+	var tools []string
+	for _, name := range strings.Split(spec, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			tools = append(tools, name)
+		}
+	}
+	return tools
+}
@@ -0,0 +1,112 @@
+package concurrency
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func discardLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func TestMiddleware_LimitsHeavyTools(t *testing.T) {
+	t.Parallel()
+	var running, peak atomic.Int32
+	release := make(chan struct{})
+	handler := Middleware(Config{
+		MaxConcurrent: 2,
+		Tools:         []string{"git-summary"},
+		Logger:        discardLogger(),
+	})(
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if request.Params.Name != "git-summary" {
+				return mcp.NewToolResultText("done"), nil
+			}
+			current := running.Add(1)
+			defer running.Add(-1)
+			for {
+				old := peak.Load()
+				if current <= old || peak.CompareAndSwap(old, current) {
+					break
+				}
+			}
+			<-release
+			return mcp.NewToolResultText("done"), nil
+		},
+	)
+
+	call := func(name string) <-chan error {
+		done := make(chan error, 1)
+		go func() {
+			request := mcp.CallToolRequest{}
+			request.Params.Name = name
+			_, err := handler(context.Background(), request)
+			done <- err
+		}()
+		return done
+	}
+	heavyCalls := []<-chan error{call("git-summary"), call("git-summary"), call("git-summary")}
+	require.Eventually(t, func() bool { return running.Load() == 2 }, time.Second, time.Millisecond)
+
+	require.NoError(t, <-call("zotero"), "other tools are not queued")
+	close(release)
+	for _, done := range heavyCalls {
+		require.NoError(t, <-done)
+	}
+	assert.Equal(t, int32(2), peak.Load())
+}
+
+func TestMiddleware_QueuedCallCancelled(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	handler := Middleware(Config{
+		MaxConcurrent: 1,
+		Tools:         []string{"publish"},
+		Logger:        discardLogger(),
+	})(
+		func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			<-release
+			return mcp.NewToolResultText("done"), nil
+		},
+	)
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "publish"
+	go func() {
+		_, _ = handler(context.Background(), request)
+	}()
+	defer close(release)
+	time.Sleep(10 * time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := handler(ctx, request)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestMiddleware_Disabled(t *testing.T) {
+	t.Parallel()
+	called := false
+	handler := Middleware(Config{Tools: DefaultHeavyTools})(
+		func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			called = true
+			return mcp.NewToolResultText("done"), nil
+		},
+	)
+	_, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, called)
+}
+
+func TestParseTools(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"git-summary", "publish"}, ParseTools(" git-summary,, publish "))
+	assert.Empty(t, ParseTools(""))
+}
@@ -0,0 +1,120 @@
+// Package concurrency limits how many heavy tool calls, such as git clones
+// and PDF renders, run at once and queues the rest in arrival order.
+package concurrency
+
+import (
+	"context"
+	"slices"
+	"sync"
+)
+
+// Semaphore admits a fixed number of holders and queues further callers
+// first in, first out.
+type Semaphore struct {
+	mu       sync.Mutex
+	capacity int
+	running  int
+	waiters  []*waiter
+}
+
+// waiter is a queued Acquire call.
+type waiter struct {
+	// ready is closed when the waiter is granted a slot.
+	ready   chan struct{}
+	granted bool
+	// moved is signalled when the waiter's queue position changes.
+	moved chan struct{}
+}
+
+// NewSemaphore creates a semaphore with the given number of slots.
+func NewSemaphore(capacity int) *Semaphore {
+	return &Semaphore{capacity: max(capacity, 1)}
+}
+
+// Acquire takes a slot, waiting in line while all slots are in use.
+// onQueued, when not nil, is called with the caller's 1-based queue
+// position when it starts waiting and whenever the position improves. It
+// returns ctx.Err() if ctx is done before a slot frees up.
+func (s *Semaphore) Acquire(ctx context.Context, onQueued func(position int)) error {
+	s.mu.Lock()
+	if s.running < s.capacity && len(s.waiters) == 0 {
+		s.running++
+		s.mu.Unlock()
+		return nil
+	}
+	queued := &waiter{ready: make(chan struct{}), moved: make(chan struct{}, 1)}
+	s.waiters = append(s.waiters, queued)
+	position := len(s.waiters)
+	s.mu.Unlock()
+
+	for {
+		if onQueued != nil {
+			onQueued(position)
+		}
+		select {
+		case <-queued.ready:
+			return nil
+		case <-queued.moved:
+			s.mu.Lock()
+			position = slices.Index(s.waiters, queued) + 1
+			s.mu.Unlock()
+			if position == 0 {
+				// Granted while the move was signalled.
+				<-queued.ready
+				return nil
+			}
+		case <-ctx.Done():
+			s.abandon(queued)
+			return ctx.Err()
+		}
+	}
+}
+
+// Release returns a slot, handing it to the longest waiting caller.
+func (s *Semaphore) Release() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.waiters) == 0 {
+		s.running--
+		return
+	}
+	next := s.waiters[0]
+	s.waiters = s.waiters[1:]
+	next.granted = true
+	close(next.ready)
+	s.signalMoved()
+}
+
+// Queued returns the number of callers waiting for a slot.
+func (s *Semaphore) Queued() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.waiters)
+}
+
+// abandon removes a waiter whose context is done. A slot granted in the
+// meantime is passed on.
+func (s *Semaphore) abandon(queued *waiter) {
+	s.mu.Lock()
+	if queued.granted {
+		s.mu.Unlock()
+		s.Release()
+		return
+	}
+	defer s.mu.Unlock()
+	if index := slices.Index(s.waiters, queued); index >= 0 {
+		s.waiters = slices.Delete(s.waiters, index, index+1)
+		s.signalMoved()
+	}
+}
+
+// signalMoved tells every waiter that its position may have changed. The
+// caller must hold s.mu.
+func (s *Semaphore) signalMoved() {
+	for _, queued := range s.waiters {
+		select {
+		case queued.moved <- struct{}{}:
+		default:
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package concurrency
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// positionLog records the queue positions reported to a waiter.
+type positionLog struct {
+	mu        sync.Mutex
+	positions []int
+}
+
+func (l *positionLog) record(position int) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.positions = append(l.positions, position)
+}
+
+func (l *positionLog) values() []int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return append([]int(nil), l.positions...)
+}
+
+func TestSemaphore_FIFO(t *testing.T) {
+	t.Parallel()
+	semaphore := NewSemaphore(1)
+	require.NoError(t, semaphore.Acquire(context.Background(), nil))
+
+	order := make(chan int, 2)
+	done := []chan struct{}{make(chan struct{}), make(chan struct{})}
+	logs := []*positionLog{{}, {}}
+	for i := range 2 {
+		go func() {
+			assert.NoError(t, semaphore.Acquire(context.Background(), logs[i].record))
+			order <- i
+			<-done[i]
+			semaphore.Release()
+		}()
+		require.Eventually(t, func() bool { return semaphore.Queued() == i+1 },
+			time.Second, time.Millisecond)
+	}
+
+	semaphore.Release()
+	assert.Equal(t, 0, <-order)
+	assert.Equal(t, []int{1}, logs[0].values())
+	require.Eventually(t, func() bool { return len(logs[1].values()) == 2 },
+		time.Second, time.Millisecond)
+	assert.Equal(t, []int{2, 1}, logs[1].values(), "the second waiter moves up")
+
+	close(done[0])
+	assert.Equal(t, 1, <-order)
+	close(done[1])
+}
+
+func TestSemaphore_AbandonedWait(t *testing.T) {
+	t.Parallel()
+	semaphore := NewSemaphore(1)
+	require.NoError(t, semaphore.Acquire(context.Background(), nil))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err := semaphore.Acquire(ctx, nil)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Zero(t, semaphore.Queued())
+
+	semaphore.Release()
+	require.NoError(t, semaphore.Acquire(context.Background(), nil),
+		"the slot is free once its holder releases it")
+}
@@ -0,0 +1,240 @@
+package document
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+// defaultMaxComments is how many comments a document may have.
+const defaultMaxComments = 500
+
+// Errors returned for comments; wrapped errors carry the document name.
+var (
+	ErrCommentNotFound  = errors.New("comment not found")
+	ErrAnchorNotFound   = errors.New("comment anchor not found in the revision")
+	ErrTooManyComments  = errors.New("document has too many comments")
+	ErrConflictingQuote = errors.New("a comment is anchored to a quote or a line, not both")
+)
+
+// Comment is a remark on a revision of a document, anchored to a quoted
+// passage, to a line, or to the whole revision when it has neither.
+type Comment struct {
+	// ID counts the comments of the document from 1.
+	ID       int    `json:"id"`
+	Revision int    `json:"revision"`
+	Author   string `json:"author"`
+	Text     string `json:"text"`
+	// Quote is the passage commented on, as it appears in the revision.
+	Quote string `json:"quote,omitempty"`
+	// Line is the 1-based line commented on, or 0.
+	Line      int       `json:"line,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Resolved  bool      `json:"resolved,omitempty"`
+}
+
+// CommentParams holds a new comment on a document.
+type CommentParams struct {
+	Name string `validate:"required"`
+	// Revision is the revision commented on, the latest when 0.
+	Revision int    `validate:"gte=0"`
+	Author   string `validate:"required,max=100"`
+	Text     string `validate:"required,max=10000"`
+	Quote    string
+	Line     int `validate:"gte=0"`
+}
+
+// WithMaxComments sets how many comments a document may have.
+func WithMaxComments(count int) Option {
+	return func(c *Config) {
+		c.maxComments = count
+	}
+}
+
+// AddComment attaches a comment to a revision of a document. A quote must
+// appear in the revision and a line must exist in it.
+func (s *Store) AddComment(params CommentParams) (Comment, error) {
+	if err := validate.Struct(params); err != nil {
+		return Comment{}, fmt.Errorf("invalid comment parameters: %w", err)
+	}
+	if params.Quote != "" && params.Line > 0 {
+		return Comment{}, ErrConflictingQuote
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, err := s.lookup(params.Name)
+	if err != nil {
+		return Comment{}, err
+	}
+	revision, err := document.Revision(params.Revision)
+	if err != nil {
+		return Comment{}, err
+	}
+	if params.Quote != "" && !strings.Contains(revision.Content, params.Quote) {
+		return Comment{}, fmt.Errorf("%w: %q is not in %s revision %d",
+			ErrAnchorNotFound, params.Quote, document.Name, revision.Number)
+	}
+	if lineCount := len(lines(revision.Content)); params.Line > lineCount {
+		return Comment{}, fmt.Errorf("%w: %s revision %d has %d lines",
+			ErrAnchorNotFound, document.Name, revision.Number, lineCount)
+	}
+	if len(document.Comments) >= s.config.maxComments {
+		return Comment{}, fmt.Errorf("%w: %s has %d", ErrTooManyComments, document.Name, len(document.Comments))
+	}
+	comment := Comment{
+		ID:        len(document.Comments) + 1,
+		Revision:  revision.Number,
+		Author:    strings.TrimSpace(params.Author),
+		Text:      strings.TrimSpace(params.Text),
+		Quote:     params.Quote,
+		Line:      params.Line,
+		CreatedAt: s.config.now(),
+	}
+	updated := clone(document)
+	updated.Comments = append(updated.Comments, comment)
+	if err := s.save(&updated); err != nil {
+		return Comment{}, err
+	}
+	*document = updated
+	s.config.logger.Debug("comment added", "document", params.Name, "comment", comment.ID)
+	return comment, nil
+}
+
+// ResolveComment marks a comment of a document as resolved, which leaves
+// it out of annotated exports.
+func (s *Store) ResolveComment(name string, id int) (Comment, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, err := s.lookup(name)
+	if err != nil {
+		return Comment{}, err
+	}
+	if id < 1 || id > len(document.Comments) {
+		return Comment{}, fmt.Errorf("%w: %s has no comment %d", ErrCommentNotFound, name, id)
+	}
+	updated := clone(document)
+	updated.Comments[id-1].Resolved = true
+	if err := s.save(&updated); err != nil {
+		return Comment{}, err
+	}
+	*document = updated
+	return updated.Comments[id-1], nil
+}
+
+// OpenComments returns the comments of the document not yet resolved.
+func (d Document) OpenComments() []Comment {
+	var open []Comment
+	for _, comment := range d.Comments {
+		if !comment.Resolved {
+			open = append(open, comment)
+		}
+	}
+	return open
+}
+
+// Annotate returns the content of revision with comments as footnotes. A
+// quoted comment is referenced right after the first occurrence of its
+// quote and a line comment at the end of its line; line comments on other
+// revisions, comments whose quote is no longer in the revision and
+// comments on the whole document are referenced under a closing
+// "Comments" paragraph.
+func Annotate(revision Revision, comments []Comment) string {
+	text := lines(revision.Content)
+	refs := make([][]int, len(text))
+	var unplaced []int
+	type insertion struct{ line, offset, id int }
+	var quoted []insertion
+	for _, comment := range comments {
+		switch {
+		case comment.Quote != "":
+			start := strings.Index(revision.Content, comment.Quote)
+			if start < 0 {
+				unplaced = append(unplaced, comment.ID)
+				continue
+			}
+			line, offset := position(text, start+len(comment.Quote))
+			quoted = append(quoted, insertion{line: line, offset: offset, id: comment.ID})
+		case comment.Line > 0 && comment.Revision == revision.Number && comment.Line <= len(text):
+			refs[comment.Line-1] = append(refs[comment.Line-1], comment.ID)
+		default:
+			unplaced = append(unplaced, comment.ID)
+		}
+	}
+	// Quotes are referenced from the end of each line, so earlier offsets
+	// stay valid.
+	slices.SortStableFunc(quoted, func(a, b insertion) int {
+		return cmp.Or(a.line-b.line, b.offset-a.offset, b.id-a.id)
+	})
+	for _, at := range quoted {
+		line := text[at.line]
+		text[at.line] = line[:at.offset] + footnoteRef(at.id) + line[at.offset:]
+	}
+
+	var builder strings.Builder
+	for i, line := range text {
+		body, newline := strings.CutSuffix(line, "\n")
+		builder.WriteString(body)
+		for _, id := range refs[i] {
+			builder.WriteString(footnoteRef(id))
+		}
+		if newline || i < len(text)-1 {
+			builder.WriteString("\n")
+		}
+	}
+	if len(comments) == 0 {
+		return builder.String()
+	}
+	if !strings.HasSuffix(builder.String(), "\n") {
+		builder.WriteString("\n")
+	}
+	if len(unplaced) > 0 {
+		builder.WriteString("\nComments")
+		for _, id := range unplaced {
+			builder.WriteString(footnoteRef(id))
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("\n")
+	for _, comment := range comments {
+		fmt.Fprintf(&builder, "[^comment-%d]: %s\n", comment.ID, footnoteText(comment, slices.Contains(unplaced, comment.ID)))
+	}
+	return builder.String()
+}
+
+// position returns the line of text holding the byte offset of the
+// joined text, and the offset within that line; an offset at the end of
+// a line is placed before its line break.
+func position(text []string, offset int) (int, int) {
+	for i, line := range text {
+		if offset < len(line) || i == len(text)-1 {
+			return i, min(offset, len(strings.TrimSuffix(line, "\n")))
+		}
+		if offset == len(line) && strings.HasSuffix(line, "\n") {
+			return i, len(line) - 1
+		}
+		offset -= len(line)
+	}
+	return 0, 0
+}
+
+// footnoteRef is the reference to the footnote of comment id.
+func footnoteRef(id int) string {
+	return fmt.Sprintf("[^comment-%d]", id)
+}
+
+// footnoteText renders a comment as a one-line footnote, naming its anchor
+// when the comment could not be placed next to it.
+func footnoteText(comment Comment, unplaced bool) string {
+	text := fmt.Sprintf("**%s** (%s): %s",
+		comment.Author, comment.CreatedAt.UTC().Format(time.DateOnly), strings.Join(strings.Fields(comment.Text), " "))
+	switch {
+	case unplaced && comment.Quote != "":
+		text += fmt.Sprintf(" (on %q in revision %d)", strings.Join(strings.Fields(comment.Quote), " "), comment.Revision)
+	case unplaced && comment.Line > 0:
+		text += fmt.Sprintf(" (on line %d of revision %d)", comment.Line, comment.Revision)
+	}
+	return text
+}
@@ -0,0 +1,90 @@
+package document
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_Comments(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	store := newTestStore(WithDir(filepath.Join(dir, "documents")), WithMaxComments(3))
+	require.NoError(t, store.Load())
+	_, err := store.Create(CreateParams{Name: "notes", Content: "# Notes\n\nStrains: 10\n"})
+	require.NoError(t, err)
+
+	comment, err := store.AddComment(CommentParams{Name: "notes", Author: " Ada ", Text: "Recount.", Quote: "10"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, comment.ID)
+	assert.Equal(t, 1, comment.Revision, "comments default to the latest revision")
+	assert.Equal(t, "Ada", comment.Author)
+
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Ada", Text: "x", Quote: "12"})
+	require.ErrorIs(t, err, ErrAnchorNotFound)
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Ada", Text: "x", Line: 4})
+	require.ErrorIs(t, err, ErrAnchorNotFound)
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Ada", Text: "x", Quote: "10", Line: 3})
+	require.ErrorIs(t, err, ErrConflictingQuote)
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Ada", Text: "x", Revision: 2})
+	require.ErrorIs(t, err, ErrNoRevision)
+	_, err = store.AddComment(CommentParams{Name: "missing", Author: "Ada", Text: "x"})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.AddComment(CommentParams{Name: "notes", Text: "x"})
+	require.Error(t, err, "comments need an author")
+
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Grace", Text: "Add a summary.", Line: 1})
+	require.NoError(t, err)
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Grace", Text: "Looks good."})
+	require.NoError(t, err)
+	_, err = store.AddComment(CommentParams{Name: "notes", Author: "Grace", Text: "One more."})
+	require.ErrorIs(t, err, ErrTooManyComments)
+
+	resolved, err := store.ResolveComment("notes", 2)
+	require.NoError(t, err)
+	assert.True(t, resolved.Resolved)
+	_, err = store.ResolveComment("notes", 4)
+	require.ErrorIs(t, err, ErrCommentNotFound)
+
+	found, err := store.Get("notes")
+	require.NoError(t, err)
+	assert.Len(t, found.OpenComments(), 2)
+	assert.Equal(t, 2, found.Info().OpenComments)
+
+	restarted := newTestStore(WithDir(filepath.Join(dir, "documents")))
+	require.NoError(t, restarted.Load())
+	loaded, err := restarted.Get("notes")
+	require.NoError(t, err)
+	assert.Equal(t, found.Comments, loaded.Comments)
+}
+
+func TestAnnotate(t *testing.T) {
+	t.Parallel()
+	revision := Revision{Number: 2, Content: "# Notes\n\nStrains: 10, plasmids: 4\nDone"}
+	comments := []Comment{
+		{ID: 1, Revision: 2, Author: "Ada", Text: "Recount.", Quote: "10"},
+		{ID: 2, Revision: 2, Author: "Grace", Text: "Which\nplasmids?", Quote: "plasmids: 4"},
+		{ID: 3, Revision: 2, Author: "Ada", Text: "Add a title.", Line: 1},
+		{ID: 4, Revision: 1, Author: "Ada", Text: "Too short.", Line: 3},
+		{ID: 5, Revision: 1, Author: "Grace", Text: "Typo.", Quote: "Stains"},
+		{ID: 6, Revision: 2, Author: "Grace", Text: "Looks good.", Quote: "Done"},
+	}
+	for i := range comments {
+		comments[i].CreatedAt = time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	}
+	assert.Equal(t,
+		"# Notes[^comment-3]\n\nStrains: 10[^comment-1], plasmids: 4[^comment-2]\nDone[^comment-6]\n"+
+			"\nComments[^comment-4][^comment-5]\n\n"+
+			"[^comment-1]: **Ada** (2025-06-02): Recount.\n"+
+			"[^comment-2]: **Grace** (2025-06-02): Which plasmids?\n"+
+			"[^comment-3]: **Ada** (2025-06-02): Add a title.\n"+
+			"[^comment-4]: **Ada** (2025-06-02): Too short. (on line 3 of revision 1)\n"+
+			"[^comment-5]: **Grace** (2025-06-02): Typo. (on \"Stains\" in revision 1)\n"+
+			"[^comment-6]: **Grace** (2025-06-02): Looks good.\n",
+		Annotate(revision, comments),
+	)
+	assert.Equal(t, "# Notes\n", Annotate(Revision{Content: "# Notes\n"}, nil))
+}
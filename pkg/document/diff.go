@@ -0,0 +1,76 @@
+package document
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+)
+
+// diffContext is the number of unchanged lines shown around each change.
+const diffContext = 3
+
+// Diff compares two revisions of a document line by line.
+type Diff struct {
+	Name    string `json:"name"`
+	From    int    `json:"from"`
+	To      int    `json:"to"`
+	Added   int    `json:"added"`
+	Removed int    `json:"removed"`
+	// Unified is the change as a unified diff, empty when the revisions
+	// are the same.
+	Unified string `json:"unified"`
+}
+
+// Compare diffs the revisions numbered from and to of document; a to of 0
+// is the latest revision, and a from of 0 the one before to.
+func Compare(document Document, from, to int) (Diff, error) {
+	newer, err := document.Revision(to)
+	if err != nil {
+		return Diff{}, err
+	}
+	if from == 0 {
+		from = max(newer.Number-1, 1)
+	}
+	older, err := document.Revision(from)
+	if err != nil {
+		return Diff{}, err
+	}
+	a, b := lines(older.Content), lines(newer.Content)
+	diff := Diff{Name: document.Name, From: older.Number, To: newer.Number}
+	for _, op := range difflib.NewMatcher(a, b).GetOpCodes() {
+		switch op.Tag {
+		case 'r':
+			diff.Removed += op.I2 - op.I1
+			diff.Added += op.J2 - op.J1
+		case 'd':
+			diff.Removed += op.I2 - op.I1
+		case 'i':
+			diff.Added += op.J2 - op.J1
+		}
+	}
+	diff.Unified, err = difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        a,
+		B:        b,
+		FromFile: fmt.Sprintf("%s@%d", document.Name, older.Number),
+		ToFile:   fmt.Sprintf("%s@%d", document.Name, newer.Number),
+		Context:  diffContext,
+	})
+	if err != nil {
+		return Diff{}, fmt.Errorf("error diffing %s: %w", document.Name, err)
+	}
+	return diff, nil
+}
+
+// lines splits text into lines ending in line breaks. Unlike
+// difflib.SplitLines, it adds no empty line after a final line break; a
+// last line without one is given one, so diffs stay line-oriented.
+func lines(text string) []string {
+	split := strings.SplitAfter(text, "\n")
+	if last := len(split) - 1; split[last] == "" {
+		split = split[:last]
+	} else {
+		split[last] += "\n"
+	}
+	return split
+}
@@ -0,0 +1,415 @@
+// Package document keeps named markdown documents and their revisions, so
+// a draft can be revised over several tool calls, its revisions compared
+// and the final version exported. Documents live in memory, and in a
+// directory too when one is configured, so they survive restarts.
+package document
+
+import (
+	"cmp"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+const (
+	defaultMaxSize      = 5 << 20
+	defaultMaxRevisions = 200
+	maxNameLength       = 64
+)
+
+// Errors returned by the store; wrapped errors carry the document name.
+var (
+	ErrNotFound      = errors.New("document not found")
+	ErrExists        = errors.New("document already exists")
+	ErrInvalidName   = errors.New("invalid document name")
+	ErrNoRevision    = errors.New("revision not found")
+	ErrUnchanged     = errors.New("revision is unchanged from the latest")
+	ErrTooLarge      = errors.New("revision exceeds the size limit")
+	ErrTooManyDrafts = errors.New("document has too many revisions")
+)
+
+// namePattern matches document names, which are also their file names.
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9-]*$`)
+
+// Revision is one version of a document.
+type Revision struct {
+	// Number counts the revisions of the document from 1.
+	Number    int       `json:"number"`
+	Note      string    `json:"note,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	Size      int       `json:"size"`
+	SHA256    string    `json:"sha256"`
+	// Content is the markdown of the revision; it is left out of histories.
+	Content string `json:"content,omitempty"`
+}
+
+// Document is a named document and its revisions, oldest first.
+type Document struct {
+	Name      string    `json:"name"`
+	Title     string    `json:"title,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// Final is the number of the revision last exported as final, or 0.
+	Final     int        `json:"final,omitempty"`
+	Revisions []Revision `json:"revisions"`
+	// Comments are the comments on the revisions, oldest first.
+	Comments []Comment `json:"comments,omitempty"`
+}
+
+// Info summarizes a document without its revisions.
+type Info struct {
+	Name      string    `json:"name"`
+	Title     string    `json:"title,omitempty"`
+	CreatedAt time.Time `json:"created_at"`
+	// UpdatedAt is the time of the latest revision, or of the creation.
+	UpdatedAt time.Time `json:"updated_at"`
+	Revisions int       `json:"revisions"`
+	Final     int       `json:"final,omitempty"`
+	// OpenComments is the number of comments not yet resolved.
+	OpenComments int `json:"open_comments,omitempty"`
+}
+
+// CreateParams holds the parameters for creating a document.
+type CreateParams struct {
+	Name  string `validate:"required"`
+	Title string
+	// Content is the first revision, if any.
+	Content string
+	Note    string
+}
+
+// ReviseParams holds a new revision of a document.
+type ReviseParams struct {
+	Name    string `validate:"required"`
+	Content string `validate:"required"`
+	Note    string
+}
+
+// Info returns the summary of the document.
+func (d Document) Info() Info {
+	info := Info{
+		Name:         d.Name,
+		Title:        d.Title,
+		CreatedAt:    d.CreatedAt,
+		UpdatedAt:    d.CreatedAt,
+		Revisions:    len(d.Revisions),
+		Final:        d.Final,
+		OpenComments: len(d.OpenComments()),
+	}
+	if len(d.Revisions) > 0 {
+		info.UpdatedAt = d.Revisions[len(d.Revisions)-1].CreatedAt
+	}
+	return info
+}
+
+// History returns the revisions of the document without their content.
+func (d Document) History() []Revision {
+	history := make([]Revision, 0, len(d.Revisions))
+	for _, revision := range d.Revisions {
+		revision.Content = ""
+		history = append(history, revision)
+	}
+	return history
+}
+
+// Revision returns the revision numbered number, or the latest for 0.
+func (d Document) Revision(number int) (Revision, error) {
+	if number == 0 {
+		number = len(d.Revisions)
+	}
+	if number < 1 || number > len(d.Revisions) {
+		return Revision{}, fmt.Errorf("%w: %s has no revision %d", ErrNoRevision, d.Name, number)
+	}
+	return d.Revisions[number-1], nil
+}
+
+// Store keeps documents in memory, and in a directory when configured.
+type Store struct {
+	mu        sync.Mutex
+	documents map[string]*Document
+	config    *Config
+}
+
+// Option represents a configuration option for Store.
+type Option func(*Config)
+
+// Config holds the configuration for the store.
+type Config struct {
+	dir          string
+	maxSize      int
+	maxRevisions int
+	maxComments  int
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+// WithDir keeps each document in a JSON file in dir, so documents survive
+// restarts; see Load.
+func WithDir(dir string) Option {
+	return func(c *Config) {
+		c.dir = dir
+	}
+}
+
+// WithMaxSize sets the largest revision in bytes.
+func WithMaxSize(size int) Option {
+	return func(c *Config) {
+		c.maxSize = size
+	}
+}
+
+// WithMaxRevisions sets how many revisions a document may have.
+func WithMaxRevisions(count int) Option {
+	return func(c *Config) {
+		c.maxRevisions = count
+	}
+}
+
+// WithLogger sets the logger for the store.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewStore creates an empty document store.
+func NewStore(opts ...Option) *Store {
+	cfg := &Config{
+		maxSize:      defaultMaxSize,
+		maxRevisions: defaultMaxRevisions,
+		maxComments:  defaultMaxComments,
+		now:          time.Now,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Store{documents: make(map[string]*Document), config: cfg}
+}
+
+// Load reads the documents kept in the store's directory, creating the
+// directory when it does not exist. It does nothing without a directory.
+func (s *Store) Load() error {
+	if s.config.dir == "" {
+		return nil
+	}
+	if err := os.MkdirAll(s.config.dir, 0o750); err != nil {
+		return fmt.Errorf("error creating document directory: %w", err)
+	}
+	paths, err := filepath.Glob(filepath.Join(s.config.dir, "*.json"))
+	if err != nil {
+		return fmt.Errorf("error listing documents: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("error reading document %s: %w", path, err)
+		}
+		var document Document
+		if err := json.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("error decoding document %s: %w", path, err)
+		}
+		if err := checkName(document.Name); err != nil {
+			return fmt.Errorf("error loading document %s: %w", path, err)
+		}
+		s.documents[document.Name] = &document
+	}
+	s.config.logger.Info("loaded documents", "dir", s.config.dir, "documents", len(paths))
+	return nil
+}
+
+// Create adds a document, with a first revision when params has content.
+func (s *Store) Create(params CreateParams) (Document, error) {
+	if err := validate.Struct(params); err != nil {
+		return Document{}, fmt.Errorf("invalid document parameters: %w", err)
+	}
+	if err := checkName(params.Name); err != nil {
+		return Document{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.documents[params.Name]; ok {
+		return Document{}, fmt.Errorf("%w: %s", ErrExists, params.Name)
+	}
+	document := &Document{Name: params.Name, Title: params.Title, CreatedAt: s.config.now()}
+	if params.Content != "" {
+		if _, err := s.addRevision(document, params.Content, params.Note); err != nil {
+			return Document{}, err
+		}
+	}
+	if err := s.save(document); err != nil {
+		return Document{}, err
+	}
+	s.documents[params.Name] = document
+	s.config.logger.Debug("document created", "document", params.Name)
+	return clone(document), nil
+}
+
+// Revise appends a revision to a document.
+func (s *Store) Revise(params ReviseParams) (Revision, error) {
+	if err := validate.Struct(params); err != nil {
+		return Revision{}, fmt.Errorf("invalid revision parameters: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, err := s.lookup(params.Name)
+	if err != nil {
+		return Revision{}, err
+	}
+	updated := clone(document)
+	revision, err := s.addRevision(&updated, params.Content, params.Note)
+	if err != nil {
+		return Revision{}, err
+	}
+	if err := s.save(&updated); err != nil {
+		return Revision{}, err
+	}
+	*document = updated
+	s.config.logger.Debug("document revised", "document", params.Name, "revision", revision.Number)
+	return revision, nil
+}
+
+// Get returns a document with its revisions.
+func (s *Store) Get(name string) (Document, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, err := s.lookup(name)
+	if err != nil {
+		return Document{}, err
+	}
+	return clone(document), nil
+}
+
+// MarkFinal records the revision numbered number, or the latest for 0, as
+// the final version of a document and returns it.
+func (s *Store) MarkFinal(name string, number int) (Revision, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	document, err := s.lookup(name)
+	if err != nil {
+		return Revision{}, err
+	}
+	revision, err := document.Revision(number)
+	if err != nil {
+		return Revision{}, err
+	}
+	updated := clone(document)
+	updated.Final = revision.Number
+	if err := s.save(&updated); err != nil {
+		return Revision{}, err
+	}
+	*document = updated
+	return revision, nil
+}
+
+// List returns the documents ordered by name.
+func (s *Store) List() []Info {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	infos := make([]Info, 0, len(s.documents))
+	for _, document := range s.documents {
+		infos = append(infos, document.Info())
+	}
+	slices.SortFunc(infos, func(a, b Info) int {
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return infos
+}
+
+// lookup returns the named document. Callers must hold the lock.
+func (s *Store) lookup(name string) (*Document, error) {
+	document, ok := s.documents[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNotFound, name)
+	}
+	return document, nil
+}
+
+// addRevision appends content to document as its next revision.
+func (s *Store) addRevision(document *Document, content, note string) (Revision, error) {
+	if len(content) > s.config.maxSize {
+		return Revision{}, fmt.Errorf("%w: %d bytes, limit %d", ErrTooLarge, len(content), s.config.maxSize)
+	}
+	if len(document.Revisions) >= s.config.maxRevisions {
+		return Revision{}, fmt.Errorf("%w: %s has %d", ErrTooManyDrafts, document.Name, len(document.Revisions))
+	}
+	sum := sha256.Sum256([]byte(content))
+	revision := Revision{
+		Number:    len(document.Revisions) + 1,
+		Note:      strings.TrimSpace(note),
+		CreatedAt: s.config.now(),
+		Size:      len(content),
+		SHA256:    hex.EncodeToString(sum[:]),
+		Content:   content,
+	}
+	if latest, err := document.Revision(0); err == nil && latest.SHA256 == revision.SHA256 {
+		return Revision{}, fmt.Errorf("%w: %s revision %d", ErrUnchanged, document.Name, latest.Number)
+	}
+	document.Revisions = append(document.Revisions, revision)
+	return revision, nil
+}
+
+// save writes document to the store's directory, replacing its previous
+// file at once so a crash never leaves half a document.
+func (s *Store) save(document *Document) error {
+	if s.config.dir == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(document, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding document %s: %w", document.Name, err)
+	}
+	file, err := os.CreateTemp(s.config.dir, "."+document.Name+"-*")
+	if err != nil {
+		return fmt.Errorf("error saving document %s: %w", document.Name, err)
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(data); err != nil {
+		_ = file.Close()
+		return fmt.Errorf("error saving document %s: %w", document.Name, err)
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("error saving document %s: %w", document.Name, err)
+	}
+	if err := os.Rename(file.Name(), filepath.Join(s.config.dir, document.Name+".json")); err != nil {
+		return fmt.Errorf("error saving document %s: %w", document.Name, err)
+	}
+	return nil
+}
+
+// checkName reports whether name can name a document: lowercase letters,
+// digits and hyphens, starting with a letter or digit.
+func checkName(name string) error {
+	if len(name) > maxNameLength || !namePattern.MatchString(name) {
+		return fmt.Errorf(
+			"%w: %q must be at most %d lowercase letters, digits and hyphens",
+			ErrInvalidName, name, maxNameLength,
+		)
+	}
+	return nil
+}
+
+// clone copies document, so callers cannot change the stored revisions
+// and comments.
+func clone(document *Document) Document {
+	copied := *document
+	copied.Revisions = slices.Clone(document.Revisions)
+	copied.Comments = slices.Clone(document.Comments)
+	return copied
+}
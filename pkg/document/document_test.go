@@ -0,0 +1,158 @@
+package document
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestStore creates a store with a fixed clock and a silent logger.
+func newTestStore(opts ...Option) *Store {
+	now := time.Date(2025, time.June, 2, 9, 0, 0, 0, time.UTC)
+	opts = append([]Option{
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		func(c *Config) {
+			c.now = func() time.Time {
+				now = now.Add(time.Minute)
+				return now
+			}
+		},
+	}, opts...)
+	return NewStore(opts...)
+}
+
+func TestStore_Revisions(t *testing.T) {
+	t.Parallel()
+	store := newTestStore()
+	created, err := store.Create(CreateParams{
+		Name: "r01-progress", Title: "R01 Progress", Content: "# Progress\n", Note: "First draft",
+	})
+	require.NoError(t, err)
+	assert.Len(t, created.Revisions, 1)
+
+	revision, err := store.Revise(ReviseParams{Name: "r01-progress", Content: "# Progress\n\nMore strains.\n"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, revision.Number)
+	assert.Equal(t, len("# Progress\n\nMore strains.\n"), revision.Size)
+
+	_, err = store.Revise(ReviseParams{Name: "r01-progress", Content: "# Progress\n\nMore strains.\n"})
+	require.ErrorIs(t, err, ErrUnchanged)
+	_, err = store.Revise(ReviseParams{Name: "missing", Content: "text"})
+	require.ErrorIs(t, err, ErrNotFound)
+	_, err = store.Create(CreateParams{Name: "r01-progress"})
+	require.ErrorIs(t, err, ErrExists)
+	_, err = store.Create(CreateParams{Name: "../etc"})
+	require.ErrorIs(t, err, ErrInvalidName)
+
+	final, err := store.MarkFinal("r01-progress", 1)
+	require.NoError(t, err)
+	assert.Equal(t, "# Progress\n", final.Content)
+	_, err = store.MarkFinal("r01-progress", 3)
+	require.ErrorIs(t, err, ErrNoRevision)
+
+	found, err := store.Get("r01-progress")
+	require.NoError(t, err)
+	assert.Equal(t, 1, found.Final)
+	history := found.History()
+	assert.Equal(t, "First draft", history[0].Note)
+	assert.Empty(t, history[1].Content, "histories leave out the content")
+	assert.NotEmpty(t, found.Revisions[1].Content, "History does not change the document")
+
+	infos := store.List()
+	require.Len(t, infos, 1)
+	assert.Equal(t, 2, infos[0].Revisions)
+	assert.Equal(t, found.Revisions[1].CreatedAt, infos[0].UpdatedAt)
+}
+
+func TestStore_Limits(t *testing.T) {
+	t.Parallel()
+	store := newTestStore(WithMaxSize(8), WithMaxRevisions(2))
+	_, err := store.Create(CreateParams{Name: "notes", Content: "too large\n"})
+	require.ErrorIs(t, err, ErrTooLarge)
+	_, err = store.Get("notes")
+	require.ErrorIs(t, err, ErrNotFound, "a failed create keeps no document")
+
+	_, err = store.Create(CreateParams{Name: "notes", Content: "one\n"})
+	require.NoError(t, err)
+	_, err = store.Revise(ReviseParams{Name: "notes", Content: "two\n"})
+	require.NoError(t, err)
+	_, err = store.Revise(ReviseParams{Name: "notes", Content: "three\n"})
+	require.ErrorIs(t, err, ErrTooManyDrafts)
+}
+
+func TestStore_Load(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "documents")
+	store := newTestStore(WithDir(dir))
+	require.NoError(t, store.Load())
+	_, err := store.Create(CreateParams{Name: "notes", Title: "Notes", Content: "one\n"})
+	require.NoError(t, err)
+	_, err = store.Revise(ReviseParams{Name: "notes", Content: "two\n", Note: "Second"})
+	require.NoError(t, err)
+	_, err = store.MarkFinal("notes", 2)
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	require.Len(t, entries, 1, "no temporary files are left behind")
+	assert.Equal(t, "notes.json", entries[0].Name())
+
+	restarted := newTestStore(WithDir(dir))
+	require.NoError(t, restarted.Load())
+	original, err := store.Get("notes")
+	require.NoError(t, err)
+	loaded, err := restarted.Get("notes")
+	require.NoError(t, err)
+	assert.Equal(t, original, loaded)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "broken.json"), []byte("{"), 0o600))
+	assert.Error(t, newTestStore(WithDir(dir)).Load())
+}
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	store := newTestStore()
+	_, err := store.Create(CreateParams{Name: "notes", Content: "# Notes\n\nStrains: 10\nPlasmids: 4\n"})
+	require.NoError(t, err)
+	_, err = store.Revise(ReviseParams{Name: "notes", Content: "# Notes\n\nStrains: 12\nPlasmids: 4\nAntibodies: 2\n"})
+	require.NoError(t, err)
+	_, err = store.Revise(ReviseParams{Name: "notes", Content: "# Notes\n"})
+	require.NoError(t, err)
+	found, err := store.Get("notes")
+	require.NoError(t, err)
+
+	diff, err := Compare(found, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, Diff{
+		Name:    "notes",
+		From:    1,
+		To:      2,
+		Added:   2,
+		Removed: 1,
+		Unified: "--- notes@1\n+++ notes@2\n@@ -1,4 +1,5 @@\n # Notes\n \n-Strains: 10\n+Strains: 12\n" +
+			" Plasmids: 4\n+Antibodies: 2\n",
+	}, diff)
+
+	diff, err = Compare(found, 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, diff.From, "from defaults to the revision before to")
+	assert.Equal(t, 3, diff.To, "to defaults to the latest")
+	assert.Equal(t, 4, diff.Removed)
+
+	diff, err = Compare(found, 2, 2)
+	require.NoError(t, err)
+	assert.Empty(t, diff.Unified)
+	diff, err = Compare(Document{Name: "notes", Revisions: []Revision{
+		{Number: 1, Content: "Strains: 10"}, {Number: 2, Content: "Strains: 12"},
+	}}, 1, 2)
+	require.NoError(t, err)
+	assert.Equal(t, "--- notes@1\n+++ notes@2\n@@ -1 +1 @@\n-Strains: 10\n+Strains: 12\n", diff.Unified)
+	_, err = Compare(found, 1, 4)
+	require.ErrorIs(t, err, ErrNoRevision)
+}
@@ -0,0 +1,397 @@
+// Package docx converts markdown to Word documents in the Office Open XML
+// (.docx) format, using only the standard library to write the package.
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// ContentType is the MIME type of .docx files.
+const ContentType = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+
+// Metadata holds the document properties shown by word processors.
+type Metadata struct {
+	Title       string
+	Authors     []string
+	Description string
+	Keywords    []string
+	Created     time.Time
+}
+
+// ConvertParams holds the parameters for converting markdown to .docx.
+type ConvertParams struct {
+	Source   []byte `validate:"required"`
+	Metadata Metadata
+}
+
+// Convert renders the markdown source as a .docx document written to w.
+// Front matter is not interpreted; strip it before converting.
+func Convert(w io.Writer, params ConvertParams) error {
+	if err := validate.Struct(params); err != nil {
+		return fmt.Errorf("invalid docx parameters: %w", err)
+	}
+	markdown := goldmark.New(goldmark.WithExtensions(extension.GFM))
+	root := markdown.Parser().Parse(text.NewReader(params.Source))
+
+	doc := &document{source: params.Source}
+	doc.block(root, blockStyle{})
+
+	archive := zip.NewWriter(w)
+	parts := []struct {
+		name    string
+		content string
+	}{
+		{"[Content_Types].xml", contentTypesXML},
+		{"_rels/.rels", packageRelsXML},
+		{"docProps/core.xml", coreXML(params.Metadata)},
+		{"word/styles.xml", stylesXML},
+		{"word/_rels/document.xml.rels", doc.relsXML()},
+		{"word/document.xml", doc.documentXML()},
+	}
+	for _, part := range parts {
+		writer, err := archive.Create(part.name)
+		if err != nil {
+			return fmt.Errorf("error adding %s: %w", part.name, err)
+		}
+		if _, err := io.WriteString(writer, part.content); err != nil {
+			return fmt.Errorf("error writing %s: %w", part.name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return fmt.Errorf("error finishing docx archive: %w", err)
+	}
+	return nil
+}
+
+// blockStyle is the paragraph formatting inherited by nested blocks.
+type blockStyle struct {
+	style  string
+	indent int
+	// prefix is written before the first paragraph, e.g. a list bullet.
+	prefix string
+}
+
+// runStyle is the character formatting of a run.
+type runStyle struct {
+	bold, italic, strike bool
+	// charStyle names a character style such as CodeChar or Hyperlink.
+	charStyle string
+}
+
+// runEnd closes a text run.
+const runEnd = "</w:t></w:r>"
+
+// document accumulates the body and hyperlink relationships.
+type document struct {
+	source []byte
+	body   bytes.Buffer
+	links  []string
+	// lastRunEnd and lastRunStyle describe the run that ends the body, so
+	// adjacent text with the same formatting shares one run.
+	lastRunEnd   int
+	lastRunStyle runStyle
+}
+
+// block renders a block node and its children.
+func (d *document) block(node ast.Node, style blockStyle) {
+	switch node := node.(type) {
+	case *ast.Heading:
+		d.paragraph(node, blockStyle{style: fmt.Sprintf("Heading%d", node.Level)})
+	case *ast.Paragraph, *ast.TextBlock:
+		d.paragraph(node, style)
+	case *ast.List:
+		d.list(node, style)
+	case *ast.Blockquote:
+		quoted := style
+		quoted.style = "Quote"
+		d.children(node, quoted)
+	case *ast.FencedCodeBlock, *ast.CodeBlock:
+		d.code(node, style)
+	case *ast.ThematicBreak:
+		d.body.WriteString(
+			`<w:p><w:pPr><w:pBdr><w:bottom w:val="single" w:sz="6" w:space="1" w:color="auto"/></w:pBdr></w:pPr></w:p>`,
+		)
+	case *ast.HTMLBlock:
+		// Raw HTML has no Word equivalent.
+	case *east.Table:
+		d.table(node)
+	default:
+		d.children(node, style)
+	}
+}
+
+// children renders the children of a block; only the first inherits the
+// prefix.
+func (d *document) children(node ast.Node, style blockStyle) {
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		d.block(child, style)
+		style.prefix = ""
+	}
+}
+
+// list renders list items as indented paragraphs with bullets or numbers.
+func (d *document) list(list *ast.List, style blockStyle) {
+	number := list.Start
+	for item := list.FirstChild(); item != nil; item = item.NextSibling() {
+		itemStyle := blockStyle{style: style.style, indent: style.indent + 1, prefix: "• "}
+		if list.IsOrdered() {
+			itemStyle.prefix = fmt.Sprintf("%d. ", number)
+			number++
+		}
+		d.children(item, itemStyle)
+	}
+}
+
+// paragraph renders a block with inline children as one paragraph.
+func (d *document) paragraph(node ast.Node, style blockStyle) {
+	d.openParagraph(style)
+	if style.prefix != "" {
+		d.run(style.prefix, runStyle{})
+	}
+	d.inlines(node, runStyle{})
+	d.body.WriteString("</w:p>")
+}
+
+// openParagraph starts a paragraph with its properties.
+func (d *document) openParagraph(style blockStyle) {
+	d.body.WriteString("<w:p>")
+	if style.style == "" && style.indent == 0 {
+		return
+	}
+	d.body.WriteString("<w:pPr>")
+	if style.style != "" {
+		fmt.Fprintf(&d.body, `<w:pStyle w:val="%s"/>`, style.style)
+	}
+	if style.indent > 0 {
+		fmt.Fprintf(&d.body, `<w:ind w:left="%d" w:hanging="360"/>`, style.indent*720)
+	}
+	d.body.WriteString("</w:pPr>")
+}
+
+// code renders each line of a code block as a Code paragraph.
+func (d *document) code(node ast.Node, style blockStyle) {
+	lines := node.Lines()
+	for i := range lines.Len() {
+		segment := lines.At(i)
+		line := strings.TrimRight(string(segment.Value(d.source)), "\r\n")
+		d.openParagraph(blockStyle{style: "Code", indent: style.indent})
+		d.run(line, runStyle{})
+		d.body.WriteString("</w:p>")
+	}
+}
+
+// table renders a GFM table; header cells are bold.
+func (d *document) table(table *east.Table) {
+	columns := max(len(table.Alignments), 1)
+	d.body.WriteString(
+		`<w:tbl><w:tblPr><w:tblStyle w:val="TableGrid"/><w:tblW w:w="0" w:type="auto"/></w:tblPr><w:tblGrid>`,
+	)
+	for range columns {
+		fmt.Fprintf(&d.body, `<w:gridCol w:w="%d"/>`, 9360/columns)
+	}
+	d.body.WriteString("</w:tblGrid>")
+	for row := table.FirstChild(); row != nil; row = row.NextSibling() {
+		_, header := row.(*east.TableHeader)
+		d.body.WriteString("<w:tr>")
+		for cell := row.FirstChild(); cell != nil; cell = cell.NextSibling() {
+			d.body.WriteString("<w:tc><w:p>")
+			d.inlines(cell, runStyle{bold: header})
+			d.body.WriteString("</w:p></w:tc>")
+		}
+		d.body.WriteString("</w:tr>")
+	}
+	// Word expects a paragraph between a table and what follows it.
+	d.body.WriteString("</w:tbl><w:p/>")
+}
+
+// inlines renders the inline children of node.
+func (d *document) inlines(node ast.Node, style runStyle) {
+	for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+		d.inline(child, style)
+	}
+}
+
+// inline renders a single inline node.
+func (d *document) inline(node ast.Node, style runStyle) {
+	switch node := node.(type) {
+	case *ast.Text:
+		d.run(string(node.Value(d.source)), style)
+		switch {
+		case node.HardLineBreak():
+			d.body.WriteString("<w:r><w:br/></w:r>")
+		case node.SoftLineBreak():
+			d.run(" ", style)
+		}
+	case *ast.String:
+		d.run(string(node.Value), style)
+	case *ast.Emphasis:
+		emphasized := style
+		if node.Level >= 2 {
+			emphasized.bold = true
+		} else {
+			emphasized.italic = true
+		}
+		d.inlines(node, emphasized)
+	case *ast.CodeSpan:
+		code := style
+		code.charStyle = "CodeChar"
+		var content strings.Builder
+		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+			if segment, ok := child.(*ast.Text); ok {
+				content.Write(segment.Value(d.source))
+			}
+		}
+		d.run(content.String(), code)
+	case *ast.Link:
+		d.hyperlink(string(node.Destination), func(linked runStyle) {
+			d.inlines(node, linked)
+		}, style)
+	case *ast.AutoLink:
+		label := string(node.Label(d.source))
+		d.hyperlink(string(node.URL(d.source)), func(linked runStyle) {
+			d.run(label, linked)
+		}, style)
+	case *ast.Image:
+		var alt strings.Builder
+		for child := node.FirstChild(); child != nil; child = child.NextSibling() {
+			if segment, ok := child.(*ast.Text); ok {
+				alt.Write(segment.Value(d.source))
+			}
+		}
+		d.run(fmt.Sprintf("[image: %s]", alt.String()), style)
+	case *east.Strikethrough:
+		struck := style
+		struck.strike = true
+		d.inlines(node, struck)
+	case *east.TaskCheckBox:
+		box := "☐ "
+		if node.IsChecked {
+			box = "☑ "
+		}
+		d.run(box, style)
+	case *ast.RawHTML:
+		// Raw HTML has no Word equivalent.
+	default:
+		d.inlines(node, style)
+	}
+}
+
+// hyperlink wraps the runs written by content in a link to target.
+func (d *document) hyperlink(target string, content func(runStyle), style runStyle) {
+	d.links = append(d.links, target)
+	// rId1 is the styles part.
+	fmt.Fprintf(&d.body, `<w:hyperlink r:id="rId%d">`, len(d.links)+1)
+	style.charStyle = "Hyperlink"
+	content(style)
+	d.body.WriteString("</w:hyperlink>")
+}
+
+// run writes a run of text with the given formatting.
+func (d *document) run(value string, style runStyle) {
+	if value == "" {
+		return
+	}
+	if d.body.Len() > 0 && d.body.Len() == d.lastRunEnd && style == d.lastRunStyle {
+		d.body.Truncate(d.body.Len() - len(runEnd))
+		d.body.WriteString(escape(value))
+		d.body.WriteString(runEnd)
+		d.lastRunEnd = d.body.Len()
+		return
+	}
+	d.body.WriteString("<w:r>")
+	if style != (runStyle{}) {
+		d.body.WriteString("<w:rPr>")
+		if style.charStyle != "" {
+			fmt.Fprintf(&d.body, `<w:rStyle w:val="%s"/>`, style.charStyle)
+		}
+		if style.bold {
+			d.body.WriteString("<w:b/>")
+		}
+		if style.italic {
+			d.body.WriteString("<w:i/>")
+		}
+		if style.strike {
+			d.body.WriteString("<w:strike/>")
+		}
+		d.body.WriteString("</w:rPr>")
+	}
+	d.body.WriteString(`<w:t xml:space="preserve">`)
+	d.body.WriteString(escape(value))
+	d.body.WriteString(runEnd)
+	d.lastRunEnd = d.body.Len()
+	d.lastRunStyle = style
+}
+
+// documentXML returns the main document part.
+func (d *document) documentXML() string {
+	return xml.Header + `<w:document xmlns:w="http://schemas.openxmlformats.org/wordprocessingml/2006/main" ` +
+		`xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships"><w:body>` +
+		d.body.String() +
+		`<w:sectPr><w:pgSz w:w="12240" w:h="15840"/>` +
+		`<w:pgMar w:top="1440" w:right="1440" w:bottom="1440" w:left="1440" w:header="720" w:footer="720" w:gutter="0"/>` +
+		`</w:sectPr></w:body></w:document>`
+}
+
+// relsXML returns the relationships of the main document part.
+func (d *document) relsXML() string {
+	var rels strings.Builder
+	rels.WriteString(xml.Header)
+	rels.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	rels.WriteString(
+		`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" ` +
+			`Target="styles.xml"/>`,
+	)
+	for i, link := range d.links {
+		fmt.Fprintf(
+			&rels,
+			`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" `+
+				`Target="%s" TargetMode="External"/>`,
+			i+2,
+			escape(link),
+		)
+	}
+	rels.WriteString("</Relationships>")
+	return rels.String()
+}
+
+// coreXML returns the core properties part holding the metadata.
+func coreXML(metadata Metadata) string {
+	created := metadata.Created
+	if created.IsZero() {
+		created = time.Now()
+	}
+	return xml.Header +
+		`<cp:coreProperties xmlns:cp="http://schemas.openxmlformats.org/package/2006/metadata/core-properties" ` +
+		`xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:dcterms="http://purl.org/dc/terms/" ` +
+		`xmlns:xsi="http://www.w3.org/2001/XMLSchema-instance">` +
+		"<dc:title>" + escape(metadata.Title) + "</dc:title>" +
+		"<dc:creator>" + escape(strings.Join(metadata.Authors, "; ")) + "</dc:creator>" +
+		"<dc:description>" + escape(metadata.Description) + "</dc:description>" +
+		"<cp:keywords>" + escape(strings.Join(metadata.Keywords, ", ")) + "</cp:keywords>" +
+		`<dcterms:created xsi:type="dcterms:W3CDTF">` + created.UTC().Format(time.RFC3339) + "</dcterms:created>" +
+		"</cp:coreProperties>"
+}
+
+// escape escapes text for use in XML content and attributes.
+func escape(value string) string {
+	var escaped strings.Builder
+	// Writing to a strings.Builder cannot fail.
+	_ = xml.EscapeText(&escaped, []byte(value))
+	return escaped.String()
+}
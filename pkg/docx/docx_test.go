@@ -0,0 +1,121 @@
+package docx
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleMarkdown = `# Chemotaxis report
+
+Cells move toward **cAMP** with *speed* and ` + "`acaA`" + ` & friends.
+See [dictyBase](https://dictybase.org) or <https://www.ebi.ac.uk>.
+
+- first
+- second
+  1. nested
+
+> quoted
+
+` + "```go\nfmt.Println(\"hi\")\n```" + `
+
+| Gene | Role |
+|------|------|
+| carA | receptor |
+
+---
+`
+
+// readParts converts the markdown and returns the package parts by name.
+func readParts(t *testing.T, params ConvertParams) map[string]string {
+	t.Helper()
+	var buf bytes.Buffer
+	require.NoError(t, Convert(&buf, params))
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	require.NoError(t, err)
+	parts := make(map[string]string)
+	for _, file := range archive.File {
+		reader, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(reader)
+		require.NoError(t, err)
+		require.NoError(t, reader.Close())
+		parts[file.Name] = string(content)
+	}
+	return parts
+}
+
+// requireWellFormed fails unless content is well-formed XML.
+func requireWellFormed(t *testing.T, name, content string) {
+	t.Helper()
+	decoder := xml.NewDecoder(bytes.NewReader([]byte(content)))
+	for {
+		_, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return
+		}
+		require.NoError(t, err, name)
+	}
+}
+
+func TestConvert(t *testing.T) {
+	t.Parallel()
+	parts := readParts(t, ConvertParams{
+		Source: []byte(sampleMarkdown),
+		Metadata: Metadata{
+			Title:   "Chemotaxis & motility",
+			Authors: []string{"Jane Doe", "Richard Roe"},
+			Created: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		},
+	})
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"docProps/core.xml",
+		"word/styles.xml",
+		"word/_rels/document.xml.rels",
+		"word/document.xml",
+	} {
+		require.Contains(t, parts, name)
+		requireWellFormed(t, name, parts[name])
+	}
+
+	document := parts["word/document.xml"]
+	assert.Contains(t, document, `<w:pStyle w:val="Heading1"/></w:pPr><w:r><w:t xml:space="preserve">Chemotaxis report</w:t>`)
+	assert.Contains(t, document, `<w:rPr><w:b/></w:rPr><w:t xml:space="preserve">cAMP</w:t>`)
+	assert.Contains(t, document, `<w:rPr><w:i/></w:rPr><w:t xml:space="preserve">speed</w:t>`)
+	assert.Contains(t, document, `<w:rStyle w:val="CodeChar"/></w:rPr><w:t xml:space="preserve">acaA</w:t>`)
+	assert.Contains(t, document, "<w:t xml:space=\"preserve\"> &amp; friends. See </w:t>")
+	assert.Contains(t, document, `<w:hyperlink r:id="rId2">`)
+	assert.Contains(t, document, `<w:hyperlink r:id="rId3">`)
+	assert.Contains(t, document, `<w:t xml:space="preserve">• first</w:t>`)
+	assert.Contains(t, document, `<w:ind w:left="1440" w:hanging="360"/></w:pPr><w:r><w:t xml:space="preserve">1. nested</w:t>`)
+	assert.Contains(t, document, `<w:pStyle w:val="Quote"/>`)
+	assert.Contains(t, document, `<w:pStyle w:val="Code"/></w:pPr><w:r><w:t xml:space="preserve">fmt.Println(&#34;hi&#34;)</w:t>`)
+	assert.Contains(t, document, `<w:tbl>`)
+	assert.Contains(t, document, `<w:b/></w:rPr><w:t xml:space="preserve">Gene</w:t>`)
+	assert.Contains(t, document, `<w:pBdr>`)
+
+	rels := parts["word/_rels/document.xml.rels"]
+	assert.Contains(t, rels,
+		`Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="https://dictybase.org"`)
+	assert.Contains(t, rels, `Target="https://www.ebi.ac.uk"`)
+
+	core := parts["docProps/core.xml"]
+	assert.Contains(t, core, "<dc:title>Chemotaxis &amp; motility</dc:title>")
+	assert.Contains(t, core, "<dc:creator>Jane Doe; Richard Roe</dc:creator>")
+	assert.Contains(t, core, "2024-01-02T03:04:05Z")
+}
+
+func TestConvert_EmptySource(t *testing.T) {
+	t.Parallel()
+	err := Convert(io.Discard, ConvertParams{})
+	require.Error(t, err)
+}
@@ -0,0 +1,226 @@
+// Package filecheck validates file inputs before tools act on them. It
+// sniffs the content's magic bytes, compares them with the declared media
+// type and enforces per-tool allow lists and size limits, reporting
+// unexpected content as classified tool errors.
+package filecheck
+
+import (
+	"bytes"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+)
+
+// Media types recognised by Sniff or accepted by the policies.
+const (
+	TypePDF      = "application/pdf"
+	TypePNG      = "image/png"
+	TypeJPEG     = "image/jpeg"
+	TypeGIF      = "image/gif"
+	TypeWebP     = "image/webp"
+	TypeZIP      = "application/zip"
+	TypeDOCX     = "application/vnd.openxmlformats-officedocument.wordprocessingml.document"
+	TypeXLSX     = "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	TypeGzip     = "application/gzip"
+	TypeMarkdown = "text/markdown"
+	TypeCSV      = "text/csv"
+	TypeTSV      = "text/tab-separated-values"
+	TypePlain    = "text/plain"
+	TypeJSON     = "application/json"
+	TypeUnknown  = "application/octet-stream"
+)
+
+// Error codes of the tool errors returned by Policy.Check.
+const (
+	CodeTooLarge    = "FILE_TOO_LARGE"
+	CodeUnsupported = "UNSUPPORTED_CONTENT_TYPE"
+	CodeMismatch    = "CONTENT_TYPE_MISMATCH"
+	CodeEmpty       = "EMPTY_FILE"
+)
+
+// signature is the magic number identifying a binary format.
+type signature struct {
+	offset    int
+	magic     []byte
+	mediaType string
+}
+
+// signatures are checked in order; the first match wins.
+var signatures = []signature{
+	{magic: []byte("%PDF-"), mediaType: TypePDF},
+	{magic: []byte("\x89PNG\r\n\x1a\n"), mediaType: TypePNG},
+	{magic: []byte("\xff\xd8\xff"), mediaType: TypeJPEG},
+	{magic: []byte("GIF87a"), mediaType: TypeGIF},
+	{magic: []byte("GIF89a"), mediaType: TypeGIF},
+	{offset: 8, magic: []byte("WEBP"), mediaType: TypeWebP},
+	{magic: []byte("PK\x03\x04"), mediaType: TypeZIP},
+	{magic: []byte("\x1f\x8b"), mediaType: TypeGzip},
+}
+
+// zipContainers are media types stored as ZIP archives.
+var zipContainers = []string{TypeZIP, TypeDOCX, TypeXLSX}
+
+// Policy is the set of inputs a tool accepts.
+type Policy struct {
+	// Allowed lists the accepted media types; "image/*" accepts any image.
+	Allowed []string
+	// MaxSize is the largest accepted input in bytes; zero means no limit.
+	MaxSize int
+}
+
+// Policies for the kinds of file input the tools take.
+var (
+	// Text accepts documents converted by the markdown tools.
+	Text = Policy{Allowed: []string{TypeMarkdown, TypePlain}}
+	// PDF accepts PDF documents.
+	PDF = Policy{Allowed: []string{TypePDF}}
+	// Images accepts the raster formats browsers display.
+	Images = Policy{Allowed: []string{TypePNG, TypeJPEG, TypeGIF, TypeWebP}}
+	// Data accepts tabular and structured data, optionally gzipped.
+	Data = Policy{Allowed: []string{TypeCSV, TypeTSV, TypeJSON, TypeXLSX, TypeGzip}}
+)
+
+// Combine returns a policy accepting everything the given policies accept,
+// limited to maxSize bytes.
+func Combine(maxSize int, policies ...Policy) Policy {
+	combined := Policy{MaxSize: maxSize}
+	for _, policy := range policies {
+		for _, mediaType := range policy.Allowed {
+			if !slices.Contains(combined.Allowed, mediaType) {
+				combined.Allowed = append(combined.Allowed, mediaType)
+			}
+		}
+	}
+	return combined
+}
+
+// Sniff detects the media type of data from its magic bytes. Text without
+// a recognisable signature is reported as text/plain, anything else as
+// application/octet-stream.
+func Sniff(data []byte) string {
+	for _, sig := range signatures {
+		if len(data) >= sig.offset+len(sig.magic) &&
+			bytes.Equal(data[sig.offset:sig.offset+len(sig.magic)], sig.magic) {
+			return sig.mediaType
+		}
+	}
+	if isText(data) {
+		return TypePlain
+	}
+	return TypeUnknown
+}
+
+// Allows reports whether the policy accepts the media type, ignoring any
+// parameters such as charset. It returns a classified error if not.
+func (p Policy) Allows(mediaType string) error {
+	normalized := Normalize(mediaType)
+	for _, allowed := range p.Allowed {
+		if normalized == allowed ||
+			(strings.HasSuffix(allowed, "/*") &&
+				strings.HasPrefix(normalized, strings.TrimSuffix(allowed, "*"))) {
+			return nil
+		}
+	}
+	return &toolerror.Error{
+		Type:    toolerror.TypeInvalidInput,
+		Code:    CodeUnsupported,
+		Message: "unsupported content type " + strconv.Quote(normalized),
+		Details: map[string]string{"allowed": strings.Join(p.Allowed, ", ")},
+	}
+}
+
+// Check validates data against the policy and returns its media type. An
+// empty declared type is replaced by the sniffed one; otherwise the content
+// must match the declaration, with any text accepted for textual types and
+// any ZIP archive for ZIP-based formats.
+func (p Policy) Check(data []byte, declared string) (string, error) {
+	if err := p.checkSize(len(data)); err != nil {
+		return "", err
+	}
+	if len(data) == 0 {
+		return "", toolerror.New(toolerror.TypeInvalidInput, CodeEmpty, "file is empty")
+	}
+	detected := Sniff(data)
+	mediaType := Normalize(declared)
+	if mediaType == "" {
+		mediaType = detected
+	}
+	if !matches(mediaType, detected) {
+		return "", &toolerror.Error{
+			Type: toolerror.TypeInvalidInput,
+			Code: CodeMismatch,
+			Message: "content does not match its declared type: declared " +
+				strconv.Quote(mediaType) + " but found " + strconv.Quote(detected),
+			Details: map[string]string{"declared": mediaType, "detected": detected},
+		}
+	}
+	if err := p.Allows(mediaType); err != nil {
+		return "", err
+	}
+	return mediaType, nil
+}
+
+// checkSize returns a classified error when size exceeds the limit.
+func (p Policy) checkSize(size int) error {
+	if p.MaxSize <= 0 || size <= p.MaxSize {
+		return nil
+	}
+	return &toolerror.Error{
+		Type:    toolerror.TypeInvalidInput,
+		Code:    CodeTooLarge,
+		Message: "file exceeds the size limit of " + strconv.Itoa(p.MaxSize) + " bytes",
+		Details: map[string]string{
+			"size":     strconv.Itoa(size),
+			"max_size": strconv.Itoa(p.MaxSize),
+		},
+	}
+}
+
+// Normalize lowercases a media type and strips its parameters.
+func Normalize(mediaType string) string {
+	if parsed, _, err := mime.ParseMediaType(mediaType); err == nil {
+		return parsed
+	}
+	return strings.ToLower(strings.TrimSpace(mediaType))
+}
+
+// IsTextual reports whether a media type holds text.
+func IsTextual(mediaType string) bool {
+	normalized := Normalize(mediaType)
+	return strings.HasPrefix(normalized, "text/") ||
+		normalized == TypeJSON ||
+		strings.HasSuffix(normalized, "+json") ||
+		strings.HasSuffix(normalized, "+xml")
+}
+
+// matches reports whether content sniffed as detected may carry the
+// declared media type.
+func matches(declared, detected string) bool {
+	switch {
+	case declared == detected:
+		return true
+	case detected == TypePlain:
+		return IsTextual(declared)
+	case detected == TypeZIP:
+		return slices.Contains(zipContainers, declared)
+	}
+	return false
+}
+
+// isText reports whether data is UTF-8 text without control characters
+// other than whitespace.
+func isText(data []byte) bool {
+	if !utf8.Valid(data) {
+		return false
+	}
+	for _, b := range data {
+		if b < 0x20 && b != '\n' && b != '\r' && b != '\t' && b != '\f' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,81 @@
+package filecheck
+
+import (
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSniff(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		data     []byte
+		expected string
+	}{
+		"pdf":      {data: []byte("%PDF-1.7\n%\xe2\xe3"), expected: TypePDF},
+		"png":      {data: []byte("\x89PNG\r\n\x1a\n\x00\x00"), expected: TypePNG},
+		"jpeg":     {data: []byte("\xff\xd8\xff\xe0\x00\x10JFIF"), expected: TypeJPEG},
+		"gif":      {data: []byte("GIF89a\x01\x00"), expected: TypeGIF},
+		"webp":     {data: []byte("RIFF\x24\x00\x00\x00WEBPVP8 "), expected: TypeWebP},
+		"zip":      {data: []byte("PK\x03\x04\x14\x00"), expected: TypeZIP},
+		"gzip":     {data: []byte("\x1f\x8b\x08\x00"), expected: TypeGzip},
+		"markdown": {data: []byte("# Title\n\n- item\n"), expected: TypePlain},
+		"binary":   {data: []byte{0x00, 0x01, 0x02}, expected: TypeUnknown},
+		"latin1":   {data: []byte("caf\xe9"), expected: TypeUnknown},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tc.expected, Sniff(tc.data))
+		})
+	}
+}
+
+func TestPolicy_Check(t *testing.T) {
+	t.Parallel()
+	policy := Combine(64, Text, PDF, Images)
+	tests := map[string]struct {
+		data     []byte
+		declared string
+		expected string
+		code     string
+	}{
+		"declared markdown": {
+			data: []byte("# Title"), declared: "text/markdown; charset=utf-8", expected: TypeMarkdown,
+		},
+		"sniffed pdf":     {data: []byte("%PDF-1.4"), expected: TypePDF},
+		"wildcard image":  {data: []byte("GIF87a"), declared: "IMAGE/GIF", expected: TypeGIF},
+		"pdf as markdown": {data: []byte("%PDF-1.4"), declared: TypeMarkdown, code: CodeMismatch},
+		"text as pdf":     {data: []byte("hello"), declared: TypePDF, code: CodeMismatch},
+		"unsupported":     {data: []byte("a,b\n1,2\n"), declared: TypeCSV, code: CodeUnsupported},
+		"unknown binary":  {data: []byte{0x00, 0x01}, code: CodeUnsupported},
+		"too large":       {data: make([]byte, 65), declared: TypePlain, code: CodeTooLarge},
+		"empty":           {data: nil, declared: TypePlain, code: CodeEmpty},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			mediaType, err := policy.Check(tc.data, tc.declared)
+			if tc.code == "" {
+				require.NoError(t, err)
+				assert.Equal(t, tc.expected, mediaType)
+				return
+			}
+			var toolErr *toolerror.Error
+			require.ErrorAs(t, err, &toolErr)
+			assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+			assert.Equal(t, tc.code, toolErr.Code)
+		})
+	}
+}
+
+func TestPolicy_CheckZIPContainer(t *testing.T) {
+	t.Parallel()
+	mediaType, err := Data.Check([]byte("PK\x03\x04rest"), TypeXLSX)
+	require.NoError(t, err)
+	assert.Equal(t, TypeXLSX, mediaType)
+	_, err = Data.Check([]byte("PK\x03\x04rest"), TypeCSV)
+	require.Error(t, err)
+}
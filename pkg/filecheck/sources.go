@@ -0,0 +1,157 @@
+package filecheck
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+)
+
+// extensionTypes maps file extensions the mime package may not know about
+// on minimal container images.
+var extensionTypes = map[string]string{
+	".md":       TypeMarkdown,
+	".markdown": TypeMarkdown,
+	".txt":      TypePlain,
+	".csv":      TypeCSV,
+	".tsv":      TypeTSV,
+	".json":     TypeJSON,
+	".pdf":      TypePDF,
+	".png":      TypePNG,
+	".jpg":      TypeJPEG,
+	".jpeg":     TypeJPEG,
+	".gif":      TypeGIF,
+	".webp":     TypeWebP,
+	".docx":     TypeDOCX,
+	".xlsx":     TypeXLSX,
+	".gz":       TypeGzip,
+}
+
+// TypeByExtension returns the media type of a file name, or an empty
+// string when the extension is unknown.
+func TypeByExtension(name string) string {
+	ext := strings.ToLower(path.Ext(name))
+	if mediaType, ok := extensionTypes[ext]; ok {
+		return mediaType
+	}
+	return Normalize(mime.TypeByExtension(ext))
+}
+
+// DecodeBase64 decodes a base64 file argument and checks it against the
+// policy. It returns the data and its media type.
+func (p Policy) DecodeBase64(encoded, declared string) ([]byte, string, error) {
+	encoded = strings.TrimSpace(encoded)
+	// Reject oversized input before allocating the decoded buffer; padding
+	// makes DecodedLen overestimate by up to two bytes.
+	if err := p.checkSize(base64.StdEncoding.DecodedLen(len(encoded)) - 2); err != nil {
+		return nil, "", err
+	}
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, "", toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_ENCODING",
+			err,
+			"file is not valid base64",
+		)
+	}
+	return p.checked(data, declared)
+}
+
+// ReadFile reads a local file and checks it against the policy. Without a
+// declared type the file's extension is used.
+func (p Policy) ReadFile(name, declared string) ([]byte, string, error) {
+	info, err := os.Stat(name)
+	switch {
+	case errors.Is(err, fs.ErrNotExist):
+		return nil, "", toolerror.Wrap(toolerror.TypeNotFound, "FILE_NOT_FOUND", err, "cannot read file")
+	case err != nil:
+		return nil, "", toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_FILE", err, "cannot read file")
+	case info.IsDir():
+		return nil, "", toolerror.New(toolerror.TypeInvalidInput, "INVALID_FILE", name+" is a directory")
+	}
+	if err := p.checkSize(int(info.Size())); err != nil {
+		return nil, "", err
+	}
+	data, err := os.ReadFile(filepath.Clean(name))
+	if err != nil {
+		return nil, "", toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_FILE", err, "cannot read file")
+	}
+	if declared == "" {
+		declared = TypeByExtension(name)
+	}
+	return p.checked(data, declared)
+}
+
+// Fetch downloads a file and checks it against the policy. Without a
+// declared type the URL's extension is used, then the response's
+// Content-Type. The body is read up to one byte past the size limit.
+func (p Policy) Fetch(
+	ctx context.Context,
+	client *http.Client,
+	rawURL, declared string,
+) ([]byte, string, error) {
+	parsed, err := url.Parse(rawURL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return nil, "", toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_URL",
+			"file URL must be an http or https URL",
+		)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, parsed.String(), nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating file request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, "", toolerror.Wrap(toolerror.TypeNetworkError, "FETCH_FAILED", err, "cannot fetch file")
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		errType := toolerror.TypeAPIError
+		if resp.StatusCode == http.StatusNotFound {
+			errType = toolerror.TypeNotFound
+		}
+		return nil, "", toolerror.New(
+			errType,
+			"FETCH_FAILED",
+			fmt.Sprintf("cannot fetch file: server returned status %d", resp.StatusCode),
+		)
+	}
+	body := io.Reader(resp.Body)
+	if p.MaxSize > 0 {
+		body = io.LimitReader(resp.Body, int64(p.MaxSize)+1)
+	}
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return nil, "", toolerror.Wrap(toolerror.TypeNetworkError, "FETCH_FAILED", err, "cannot fetch file")
+	}
+	if declared == "" {
+		declared = TypeByExtension(parsed.Path)
+	}
+	if declared == "" {
+		declared = resp.Header.Get("Content-Type")
+	}
+	return p.checked(data, declared)
+}
+
+// checked runs Check and returns the data alongside its media type.
+func (p Policy) checked(data []byte, declared string) ([]byte, string, error) {
+	mediaType, err := p.Check(data, declared)
+	if err != nil {
+		return nil, "", err
+	}
+	return data, mediaType, nil
+}
@@ -0,0 +1,86 @@
+package filecheck
+
+import (
+	"context"
+	"encoding/base64"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func requireCode(t *testing.T, err error, code string) {
+	t.Helper()
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, code, toolErr.Code)
+}
+
+func TestPolicy_DecodeBase64(t *testing.T) {
+	t.Parallel()
+	policy := Policy{Allowed: []string{TypePNG}, MaxSize: 16}
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00")
+	data, mediaType, err := policy.DecodeBase64(base64.StdEncoding.EncodeToString(png), "")
+	require.NoError(t, err)
+	assert.Equal(t, png, data)
+	assert.Equal(t, TypePNG, mediaType)
+
+	_, _, err = policy.DecodeBase64("not base64!", "")
+	requireCode(t, err, "INVALID_ENCODING")
+	_, _, err = policy.DecodeBase64(base64.StdEncoding.EncodeToString(make([]byte, 32)), "")
+	requireCode(t, err, CodeTooLarge)
+}
+
+func TestPolicy_ReadFile(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	csvPath := filepath.Join(dir, "genes.csv")
+	require.NoError(t, os.WriteFile(csvPath, []byte("gene,go\nacaA,GO:0004016\n"), 0o600))
+	fakePath := filepath.Join(dir, "scan.pdf")
+	require.NoError(t, os.WriteFile(fakePath, []byte("plain text"), 0o600))
+
+	_, mediaType, err := Data.ReadFile(csvPath, "")
+	require.NoError(t, err)
+	assert.Equal(t, TypeCSV, mediaType, "the extension declares the type")
+
+	_, _, err = PDF.ReadFile(fakePath, "")
+	requireCode(t, err, CodeMismatch)
+	_, _, err = PDF.ReadFile(filepath.Join(dir, "missing.pdf"), "")
+	requireCode(t, err, "FILE_NOT_FOUND")
+	_, _, err = Policy{Allowed: Data.Allowed, MaxSize: 4}.ReadFile(csvPath, "")
+	requireCode(t, err, CodeTooLarge)
+}
+
+func TestPolicy_Fetch(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/report":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("%PDF-1.7"))
+		case "/disguised":
+			w.Header().Set("Content-Type", "application/pdf")
+			_, _ = w.Write([]byte("<html></html>"))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	data, mediaType, err := PDF.Fetch(context.Background(), server.Client(), server.URL+"/report", "")
+	require.NoError(t, err)
+	assert.Equal(t, "%PDF-1.7", string(data))
+	assert.Equal(t, TypePDF, mediaType)
+
+	_, _, err = PDF.Fetch(context.Background(), server.Client(), server.URL+"/disguised", "")
+	requireCode(t, err, CodeMismatch)
+	_, _, err = PDF.Fetch(context.Background(), server.Client(), server.URL+"/missing", "")
+	requireCode(t, err, "FETCH_FAILED")
+	_, _, err = PDF.Fetch(context.Background(), server.Client(), "file:///etc/passwd", "")
+	requireCode(t, err, "INVALID_URL")
+}
@@ -0,0 +1,218 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// maxRequestBodySize limits the size of a tool invocation request body.
+const maxRequestBodySize = 10 << 20
+
+// Gateway exposes registered MCP tools as HTTP+JSON endpoints so that
+// services without an MCP client can invoke them.
+//
+// The following routes are served:
+//   - GET  /tools          lists the registered tools
+//   - POST /tools/{name}   invokes a tool with a JSON object of arguments
+//   - GET  /openapi.json   returns an OpenAPI document built from tool schemas
+type Gateway struct {
+	mu     sync.RWMutex
+	tools  map[string]server.ServerTool
+	mux    *http.ServeMux
+	config *Config
+}
+
+// Option represents a configuration option for Gateway.
+type Option func(*Config)
+
+// Config holds the configuration for the gateway.
+type Config struct {
+	title   string
+	version string
+	logger  *slog.Logger
+}
+
+// WithTitle sets the API title reported in the OpenAPI document.
+func WithTitle(title string) Option {
+	return func(c *Config) {
+		c.title = title
+	}
+}
+
+// WithVersion sets the API version reported in the OpenAPI document.
+func WithVersion(version string) Option {
+	return func(c *Config) {
+		c.version = version
+	}
+}
+
+// WithLogger sets the logger for the gateway.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// ErrUnknownTool is returned when invoking a tool that is not registered.
+var ErrUnknownTool = errors.New("unknown tool")
+
+// errorResponse is the JSON body returned for failed requests.
+type errorResponse struct {
+	Error string `json:"error"`
+}
+
+// NewGateway creates a new Gateway with the provided options.
+func NewGateway(opts ...Option) *Gateway {
+	cfg := &Config{
+		title:   "DCR-MCP Gateway",
+		version: "1.0.0",
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	gw := &Gateway{
+		tools:  make(map[string]server.ServerTool),
+		mux:    http.NewServeMux(),
+		config: cfg,
+	}
+	gw.mux.HandleFunc("GET /tools", gw.handleListTools)
+	gw.mux.HandleFunc("POST /tools/{name}", gw.handleCallTool)
+	gw.mux.HandleFunc("GET /openapi.json", gw.handleOpenAPI)
+
+	return gw
+}
+
+// AddTool registers a tool and its handler with the gateway. The signature
+// mirrors server.MCPServer.AddTool so both can be fed by the same code.
+func (gw *Gateway) AddTool(tool mcp.Tool, handler server.ToolHandlerFunc) {
+	gw.mu.Lock()
+	defer gw.mu.Unlock()
+	gw.tools[tool.Name] = server.ServerTool{Tool: tool, Handler: handler}
+}
+
+// Handle registers an additional HTTP handler on the gateway, allowing other
+// HTTP-only features such as webhooks to share the gateway listener.
+func (gw *Gateway) Handle(pattern string, handler http.Handler) {
+	gw.mux.Handle(pattern, handler)
+}
+
+// CallTool invokes the named tool with the given arguments.
+func (gw *Gateway) CallTool(
+	ctx context.Context,
+	name string,
+	args map[string]any,
+) (*mcp.CallToolResult, error) {
+	registered, ok := gw.lookupTool(name)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+	request := mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      name,
+			Arguments: args,
+		},
+	}
+	return registered.Handler(ctx, request)
+}
+
+// ServeHTTP implements http.Handler.
+func (gw *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	gw.mux.ServeHTTP(w, r)
+}
+
+// Tools returns the registered tools sorted by name.
+func (gw *Gateway) Tools() []mcp.Tool {
+	gw.mu.RLock()
+	defer gw.mu.RUnlock()
+	tools := make([]mcp.Tool, 0, len(gw.tools))
+	for _, registered := range gw.tools {
+		tools = append(tools, registered.Tool)
+	}
+	slices.SortFunc(tools, func(a, b mcp.Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return tools
+}
+
+// lookupTool returns the registered tool with the given name.
+func (gw *Gateway) lookupTool(name string) (server.ServerTool, bool) {
+	gw.mu.RLock()
+	defer gw.mu.RUnlock()
+	registered, ok := gw.tools[name]
+	return registered, ok
+}
+
+func (gw *Gateway) handleListTools(w http.ResponseWriter, _ *http.Request) {
+	gw.writeJSON(w, http.StatusOK, map[string]any{"tools": gw.Tools()})
+}
+
+func (gw *Gateway) handleCallTool(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("name")
+	if _, ok := gw.lookupTool(name); !ok {
+		gw.writeError(w, http.StatusNotFound, fmt.Errorf("%w: %s", ErrUnknownTool, name))
+		return
+	}
+
+	args, err := decodeArguments(http.MaxBytesReader(w, r.Body, maxRequestBodySize))
+	if err != nil {
+		gw.writeError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	result, err := gw.CallTool(r.Context(), name, args)
+	if err != nil {
+		gw.config.logger.Error("tool invocation failed", "tool", name, "error", err)
+		gw.writeError(w, http.StatusInternalServerError, err)
+		return
+	}
+	gw.writeJSON(w, http.StatusOK, result)
+}
+
+func (gw *Gateway) handleOpenAPI(w http.ResponseWriter, _ *http.Request) {
+	gw.writeJSON(w, http.StatusOK, BuildOpenAPI(OpenAPIParams{
+		Title:   gw.config.title,
+		Version: gw.config.version,
+		Tools:   gw.Tools(),
+	}))
+}
+
+// decodeArguments reads the tool arguments from a JSON request body. An empty
+// body is treated as an empty argument object.
+func decodeArguments(body io.Reader) (map[string]any, error) {
+	args := make(map[string]any)
+	err := json.NewDecoder(body).Decode(&args)
+	if errors.Is(err, io.EOF) {
+		return args, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON request body: %w", err)
+	}
+	return args, nil
+}
+
+// writeJSON writes value as a JSON response with the given status code.
+func (gw *Gateway) writeJSON(w http.ResponseWriter, status int, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		gw.config.logger.Error("failed to encode response", "error", err)
+	}
+}
+
+// writeError writes err as a JSON error response with the given status code.
+func (gw *Gateway) writeError(w http.ResponseWriter, status int, err error) {
+	gw.writeJSON(w, status, errorResponse{Error: err.Error()})
+}
@@ -0,0 +1,129 @@
+package gateway
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestGateway() *Gateway {
+	gw := NewGateway(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	gw.AddTool(
+		mcp.NewTool(
+			"echo",
+			mcp.WithDescription("Echoes the message argument"),
+			mcp.WithString("message", mcp.Required()),
+		),
+		func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			message, ok := request.GetArguments()["message"].(string)
+			if !ok {
+				return nil, errors.New("missing required parameter: message")
+			}
+			return mcp.NewToolResultText(message), nil
+		},
+	)
+	return gw
+}
+
+func TestGateway_CallTool(t *testing.T) {
+	t.Parallel()
+	gw := newTestGateway()
+
+	req := httptest.NewRequest(
+		http.MethodPost,
+		"/tools/echo",
+		strings.NewReader(`{"message":"hello"}`),
+	)
+	rec := httptest.NewRecorder()
+	gw.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var body struct {
+		Content []struct {
+			Type string `json:"type"`
+			Text string `json:"text"`
+		} `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Content, 1)
+	assert.Equal(t, "text", body.Content[0].Type)
+	assert.Equal(t, "hello", body.Content[0].Text)
+}
+
+func TestGateway_CallToolErrors(t *testing.T) {
+	t.Parallel()
+	gw := newTestGateway()
+
+	tests := []struct {
+		name       string
+		path       string
+		body       string
+		wantStatus int
+	}{
+		{
+			name:       "unknown tool",
+			path:       "/tools/missing",
+			body:       `{}`,
+			wantStatus: http.StatusNotFound,
+		},
+		{
+			name:       "malformed body",
+			path:       "/tools/echo",
+			body:       `{"message":`,
+			wantStatus: http.StatusBadRequest,
+		},
+		{
+			name:       "handler error",
+			path:       "/tools/echo",
+			body:       ``,
+			wantStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			req := httptest.NewRequest(
+				http.MethodPost,
+				testCase.path,
+				strings.NewReader(testCase.body),
+			)
+			rec := httptest.NewRecorder()
+			gw.ServeHTTP(rec, req)
+
+			assert.Equal(t, testCase.wantStatus, rec.Code)
+			var body errorResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+			assert.NotEmpty(t, body.Error)
+		})
+	}
+}
+
+func TestGateway_OpenAPI(t *testing.T) {
+	t.Parallel()
+	gw := newTestGateway()
+
+	req := httptest.NewRequest(http.MethodGet, "/openapi.json", nil)
+	rec := httptest.NewRecorder()
+	gw.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	var doc map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &doc))
+	assert.Equal(t, "3.0.3", doc["openapi"])
+
+	paths, ok := doc["paths"].(map[string]any)
+	require.True(t, ok, "paths should be an object")
+	assert.Contains(t, paths, "/tools")
+	assert.Contains(t, paths, "/tools/echo")
+}
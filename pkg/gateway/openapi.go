@@ -0,0 +1,125 @@
+package gateway
+
+import (
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// OpenAPIParams holds the inputs for building an OpenAPI document.
+type OpenAPIParams struct {
+	Title   string
+	Version string
+	Tools   []mcp.Tool
+}
+
+// BuildOpenAPI generates an OpenAPI 3 document describing the gateway routes.
+// Each tool gets a POST /tools/{name} operation whose request body schema is
+// the tool's input schema.
+func BuildOpenAPI(params OpenAPIParams) map[string]any {
+	paths := map[string]any{
+		"/tools": map[string]any{
+			"get": map[string]any{
+				"operationId": "listTools",
+				"summary":     "List the available tools",
+				"responses": map[string]any{
+					"200": jsonResponse("The registered tools", map[string]any{
+						"type": "object",
+					}),
+				},
+			},
+		},
+	}
+	for _, tool := range params.Tools {
+		paths["/tools/"+tool.Name] = map[string]any{
+			"post": toolOperation(tool),
+		}
+	}
+
+	return map[string]any{
+		"openapi": "3.0.3",
+		"info": map[string]any{
+			"title":   params.Title,
+			"version": params.Version,
+		},
+		"paths": paths,
+		"components": map[string]any{
+			"schemas": map[string]any{
+				"CallToolResult": callToolResultSchema(),
+				"Error":          errorSchema(),
+			},
+		},
+	}
+}
+
+// toolOperation describes the invocation endpoint of a single tool.
+func toolOperation(tool mcp.Tool) map[string]any {
+	var inputSchema any = tool.InputSchema
+	if tool.RawInputSchema != nil {
+		inputSchema = tool.RawInputSchema
+	}
+	return map[string]any{
+		"operationId": tool.Name,
+		"summary":     tool.Description,
+		"requestBody": map[string]any{
+			"required": true,
+			"content": map[string]any{
+				"application/json": map[string]any{
+					"schema": inputSchema,
+				},
+			},
+		},
+		"responses": map[string]any{
+			"200": jsonResponse("Tool result", schemaRef("CallToolResult")),
+			"400": jsonResponse("Malformed request body", schemaRef("Error")),
+			"404": jsonResponse("Unknown tool", schemaRef("Error")),
+			"500": jsonResponse("Tool execution failed", schemaRef("Error")),
+		},
+	}
+}
+
+// jsonResponse builds an OpenAPI response object with a JSON body.
+func jsonResponse(description string, schema map[string]any) map[string]any {
+	return map[string]any{
+		"description": description,
+		"content": map[string]any{
+			"application/json": map[string]any{
+				"schema": schema,
+			},
+		},
+	}
+}
+
+// schemaRef returns a reference to a named component schema.
+func schemaRef(name string) map[string]any {
+	return map[string]any{"$ref": "#/components/schemas/" + name}
+}
+
+// callToolResultSchema describes the JSON encoding of mcp.CallToolResult.
+func callToolResultSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"content": map[string]any{
+				"type": "array",
+				"items": map[string]any{
+					"type": "object",
+					"properties": map[string]any{
+						"type": map[string]any{"type": "string"},
+						"text": map[string]any{"type": "string"},
+					},
+				},
+			},
+			"isError": map[string]any{"type": "boolean"},
+		},
+	}
+}
+
+// errorSchema describes the JSON body of an error response.
+func errorSchema() map[string]any {
+	return map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"error": map[string]any{"type": "string"},
+		},
+		"required": []string{"error"},
+	}
+}
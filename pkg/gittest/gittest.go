@@ -0,0 +1,131 @@
+// Package gittest creates git repositories for the tests of the tools that
+// read them.
+package gittest
+
+import (
+	"cmp"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/require"
+)
+
+// Author is the author and committer of the commits that name none.
+var Author = object.Signature{
+	Name:  "Jane Doe",
+	Email: "jane@example.org",
+	When:  time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC),
+}
+
+// Commit describes a commit made by NewRepo or Repo.Commit.
+type Commit struct {
+	// Files are written before the commit, keyed by their slash-separated
+	// paths. Without files, file.txt is rewritten so the commit is not
+	// empty.
+	Files map[string]string
+	// Message defaults to "change".
+	Message string
+	// Author is the author and committer. Its name and email default to
+	// those of Author, and its time to Author's.
+	Author object.Signature
+	// Tag names a lightweight tag created on the commit.
+	Tag string
+	// Branch is the branch the commit is made on, created at the current
+	// commit when it does not exist. Commits without one stay on the
+	// current branch.
+	Branch string
+}
+
+// Repo is a repository created in a temporary directory.
+type Repo struct {
+	// Dir is the directory of the repository.
+	Dir string
+	// Repository is the repository itself.
+	Repository *git.Repository
+	// Commits are the hashes of the commits made so far, in order.
+	Commits []plumbing.Hash
+}
+
+// NewRepo creates a repository in a temporary directory with the commits,
+// made in order.
+func NewRepo(t testing.TB, commits ...Commit) *Repo {
+	t.Helper()
+	dir := t.TempDir()
+	repository, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	repo := &Repo{Dir: dir, Repository: repository}
+	for _, commit := range commits {
+		repo.Commit(t, commit)
+	}
+	return repo
+}
+
+// InitRepo creates a repository in a temporary directory with one commit
+// by Author holding files, keyed by their slash-separated paths, and
+// returns the directory.
+func InitRepo(t testing.TB, files map[string]string) string {
+	t.Helper()
+	return NewRepo(t, Commit{Files: files, Message: "initial commit"}).Dir
+}
+
+// Commit makes commit in the repository and returns its hash.
+func (r *Repo) Commit(t testing.TB, commit Commit) plumbing.Hash {
+	t.Helper()
+	worktree, err := r.Repository.Worktree()
+	require.NoError(t, err)
+	if commit.Branch != "" {
+		r.checkout(t, worktree, commit.Branch)
+	}
+	signature := commit.Author
+	if signature.Name == "" {
+		signature.Name, signature.Email = Author.Name, Author.Email
+	}
+	if signature.When.IsZero() {
+		signature.When = Author.When
+	}
+	files := commit.Files
+	if len(files) == 0 {
+		files = map[string]string{"file.txt": fmt.Sprintf("commit %d at %s", len(r.Commits), signature.When)}
+	}
+	for name, content := range files {
+		path := filepath.Join(r.Dir, filepath.FromSlash(name))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+		_, err := worktree.Add(name)
+		require.NoError(t, err)
+	}
+	hash, err := worktree.Commit(
+		cmp.Or(commit.Message, "change"),
+		&git.CommitOptions{Author: &signature, Committer: &signature},
+	)
+	require.NoError(t, err)
+	if commit.Tag != "" {
+		_, err = r.Repository.CreateTag(commit.Tag, hash, nil)
+		require.NoError(t, err)
+	}
+	r.Commits = append(r.Commits, hash)
+	return hash
+}
+
+// Checkout switches the worktree to branch.
+func (r *Repo) Checkout(t testing.TB, branch string) {
+	t.Helper()
+	worktree, err := r.Repository.Worktree()
+	require.NoError(t, err)
+	r.checkout(t, worktree, branch)
+}
+
+// checkout switches worktree to branch, creating it at the current commit
+// when it does not exist.
+func (r *Repo) checkout(t testing.TB, worktree *git.Worktree, branch string) {
+	t.Helper()
+	name := plumbing.NewBranchReferenceName(branch)
+	_, err := r.Repository.Reference(name, false)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{Branch: name, Create: err != nil}))
+}
@@ -0,0 +1,61 @@
+package gittest
+
+import (
+	"testing"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInitRepo(t *testing.T) {
+	t.Parallel()
+	dir := InitRepo(t, map[string]string{"README.md": "# App\n", "cmd/app/main.go": "package main\n"})
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	assert.Equal(t, "initial commit", commit.Message)
+	assert.Equal(t, Author.Name, commit.Author.Name)
+	assert.True(t, Author.When.Equal(commit.Author.When))
+	file, err := commit.File("cmd/app/main.go")
+	require.NoError(t, err)
+	content, err := file.Contents()
+	require.NoError(t, err)
+	assert.Equal(t, "package main\n", content)
+}
+
+func TestNewRepo(t *testing.T) {
+	t.Parallel()
+	later := Author.When.AddDate(0, 0, 1)
+	repo := NewRepo(t,
+		Commit{Files: map[string]string{"go.mod": "module app\n"}, Tag: "v1.0.0"},
+		Commit{Message: "fix: paging", Author: object.Signature{Name: "Joe", Email: "joe@example.org", When: later}},
+		Commit{Branch: "develop"},
+	)
+	require.Len(t, repo.Commits, 3)
+
+	tag, err := repo.Repository.Tag("v1.0.0")
+	require.NoError(t, err)
+	assert.Equal(t, repo.Commits[0], tag.Hash())
+	second, err := repo.Repository.CommitObject(repo.Commits[1])
+	require.NoError(t, err)
+	assert.Equal(t, "fix: paging", second.Message)
+	assert.Equal(t, "Joe", second.Author.Name)
+	assert.True(t, later.Equal(second.Author.When))
+	third, err := repo.Repository.CommitObject(repo.Commits[2])
+	require.NoError(t, err)
+	assert.Equal(t, "change", third.Message)
+	assert.Equal(t, Author.Name, third.Author.Name)
+
+	head, err := repo.Repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "develop", head.Name().Short())
+	repo.Checkout(t, "master")
+	head, err = repo.Repository.Head()
+	require.NoError(t, err)
+	assert.Equal(t, repo.Commits[1], head.Hash())
+}
@@ -0,0 +1,337 @@
+// Package idempotency makes retried tool calls safe. Tools that write
+// files or call paid LLM APIs accept an idempotency_key argument, declared
+// with the WithKey option; Middleware keeps the result of the first
+// successful call with a key and returns it for every repeat, so a client
+// retrying after a lost response does not redo the work or the spend.
+package idempotency
+
+import (
+	"cmp"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// KeyArgument is the tool argument holding the idempotency key.
+const KeyArgument = "idempotency_key"
+
+// ReplayKey is the _meta field set on results returned from the store.
+const ReplayKey = "dictybase.org/idempotent-replay"
+
+const (
+	defaultTTL        = 24 * time.Hour
+	defaultMaxEntries = 1000
+)
+
+var (
+	toolsMu sync.RWMutex
+	tools   = make(map[string]bool)
+)
+
+// WithKey declares that a tool accepts an idempotency key.
+func WithKey() mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		mcp.WithString(
+			KeyArgument,
+			mcp.Description(
+				"Optional client-chosen key, up to 200 characters; repeating a call with the same key and "+
+					"arguments returns the first result instead of running the tool again",
+			),
+		)(tool)
+		toolsMu.Lock()
+		defer toolsMu.Unlock()
+		tools[tool.Name] = true
+	}
+}
+
+// Accepts tells whether the named tool declared WithKey.
+func Accepts(name string) bool {
+	toolsMu.RLock()
+	defer toolsMu.RUnlock()
+	return tools[name]
+}
+
+// entry is the result of a keyed call, or a call still running.
+type entry struct {
+	// fingerprint is the hash of the call's other arguments.
+	fingerprint string
+	result      *mcp.CallToolResult
+	// done is closed when the call finishes.
+	done      chan struct{}
+	expiresAt time.Time
+}
+
+// Config holds the configuration of a Store.
+type Config struct {
+	ttl        time.Duration
+	maxEntries int
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// Option configures a Store.
+type Option func(*Config)
+
+// WithTTL sets how long a result is kept after its call.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.ttl = ttl
+	}
+}
+
+// WithMaxEntries sets the number of results kept; the oldest is dropped to
+// make room for a new one.
+func WithMaxEntries(maxEntries int) Option {
+	return func(c *Config) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// WithLogger sets the logger for the store.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// Store keeps the results of keyed calls in memory.
+type Store struct {
+	mu      sync.Mutex
+	entries map[string]*entry
+	config  *Config
+}
+
+// NewStore creates an empty store.
+func NewStore(opts ...Option) *Store {
+	cfg := &Config{
+		ttl:        defaultTTL,
+		maxEntries: defaultMaxEntries,
+		now:        time.Now,
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Store{entries: make(map[string]*entry), config: cfg}
+}
+
+// Middleware returns a middleware that runs a keyed call once and replays
+// its result to repeats with the same key. A repeat arriving while the
+// first call runs waits for it. Failed calls are not kept, so they can be
+// retried with the same key; reusing a key with other arguments is an
+// invalid input error.
+func (s *Store) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			if !Accepts(request.Params.Name) {
+				return next(ctx, request)
+			}
+			key := request.GetString(KeyArgument, "")
+			if key == "" {
+				return next(ctx, request)
+			}
+			if err := validate.Var(key, "max=200,printascii"); err != nil {
+				return nil, toolerror.Wrap(
+					toolerror.TypeInvalidInput, "INVALID_IDEMPOTENCY_KEY", err, "invalid "+KeyArgument,
+				)
+			}
+			arguments := maps.Clone(request.GetArguments())
+			delete(arguments, KeyArgument)
+			fingerprint, err := fingerprintOf(arguments)
+			if err != nil {
+				return nil, err
+			}
+			request.Params.Arguments = arguments
+			return s.call(ctx, request.Params.Name, key, fingerprint, func() (*mcp.CallToolResult, error) {
+				return next(ctx, request)
+			})
+		}
+	}
+}
+
+// call returns the kept result of the tool's call with key, waits for the
+// running one, or runs run and keeps its result.
+func (s *Store) call(
+	ctx context.Context,
+	tool, key, fingerprint string,
+	run func() (*mcp.CallToolResult, error),
+) (*mcp.CallToolResult, error) {
+	id := tool + "\x00" + key
+	for {
+		s.mu.Lock()
+		s.expire()
+		existing, found := s.entries[id]
+		if !found {
+			break
+		}
+		s.mu.Unlock()
+		if existing.fingerprint != fingerprint {
+			return nil, toolerror.New(
+				toolerror.TypeInvalidInput,
+				"IDEMPOTENCY_KEY_REUSED",
+				KeyArgument+" was already used with different arguments",
+			)
+		}
+		select {
+		case <-existing.done:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		if existing.result != nil {
+			s.config.logger.Debug("replaying result", "tool", tool, "idempotency_key", key)
+			replayed := clone(existing.result)
+			replayed.Meta.AdditionalFields[ReplayKey] = true
+			return replayed, nil
+		}
+		// The first call failed and was dropped; look again, since
+		// another repeat may already be running it.
+	}
+	running := &entry{fingerprint: fingerprint, done: make(chan struct{})}
+	s.makeRoom()
+	s.entries[id] = running
+	s.mu.Unlock()
+
+	result, err := run()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if err != nil || result == nil || result.IsError {
+		delete(s.entries, id)
+	} else {
+		// Later middlewares may change the returned result, so a copy is
+		// kept.
+		running.result = clone(result)
+		running.expiresAt = s.config.now().Add(s.config.ttl)
+	}
+	close(running.done)
+	return result, err
+}
+
+// expire drops the results past their TTL. s.mu must be held.
+func (s *Store) expire() {
+	now := s.config.now()
+	for id, kept := range s.entries {
+		if kept.result != nil && now.After(kept.expiresAt) {
+			delete(s.entries, id)
+		}
+	}
+}
+
+// makeRoom drops the oldest result when the store is full. s.mu must be
+// held.
+func (s *Store) makeRoom() {
+	if len(s.entries) < s.config.maxEntries {
+		return
+	}
+	oldest := ""
+	for id, kept := range s.entries {
+		if kept.result == nil {
+			continue
+		}
+		if oldest == "" || kept.expiresAt.Before(s.entries[oldest].expiresAt) {
+			oldest = id
+		}
+	}
+	if oldest != "" {
+		delete(s.entries, oldest)
+	}
+}
+
+// fingerprintOf hashes the arguments of a call. Map keys are encoded in
+// sorted order, so equal arguments give equal fingerprints.
+func fingerprintOf(arguments map[string]any) (string, error) {
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return "", fmt.Errorf("error encoding arguments: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// clone returns a copy of result that can be changed without changing
+// result.
+func clone(result *mcp.CallToolResult) *mcp.CallToolResult {
+	copied := *result
+	copied.Content = slices.Clone(result.Content)
+	copied.Meta = &mcp.Meta{AdditionalFields: make(map[string]any)}
+	if result.Meta != nil {
+		maps.Copy(copied.Meta.AdditionalFields, result.Meta.AdditionalFields)
+	}
+	return &copied
+}
+
+// Record is the kept result of a keyed call, as kept in a session
+// snapshot.
+type Record struct {
+	Tool        string              `json:"tool"`
+	Key         string              `json:"key"`
+	Fingerprint string              `json:"fingerprint"`
+	Result      *mcp.CallToolResult `json:"result"`
+	ExpiresAt   time.Time           `json:"expires_at"`
+}
+
+// Export returns the kept results, ordered by tool and key. Calls still
+// running have no result yet and are left out.
+func (s *Store) Export() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	records := make([]Record, 0, len(s.entries))
+	for id, kept := range s.entries {
+		if kept.result == nil {
+			continue
+		}
+		tool, key, _ := strings.Cut(id, "\x00")
+		records = append(records, Record{
+			Tool:        tool,
+			Key:         key,
+			Fingerprint: kept.fingerprint,
+			Result:      clone(kept.result),
+			ExpiresAt:   kept.expiresAt,
+		})
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return cmp.Or(strings.Compare(a.Tool, b.Tool), strings.Compare(a.Key, b.Key))
+	})
+	return records
+}
+
+// Import adds results exported from another store, so repeating a keyed
+// call of the snapshot replays its result. Their expiry restarts, and
+// results of calls running in this store are left alone.
+func (s *Store) Import(records []Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	expiresAt := s.config.now().Add(s.config.ttl)
+	for _, record := range records {
+		id := record.Tool + "\x00" + record.Key
+		if existing, found := s.entries[id]; (found && existing.result == nil) || record.Result == nil {
+			continue
+		}
+		done := make(chan struct{})
+		close(done)
+		s.makeRoom()
+		s.entries[id] = &entry{
+			fingerprint: record.Fingerprint,
+			result:      clone(record.Result),
+			done:        done,
+			expiresAt:   expiresAt,
+		}
+	}
+	s.config.logger.Debug("results imported", "results", len(records))
+}
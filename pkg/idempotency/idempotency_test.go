@@ -0,0 +1,182 @@
+package idempotency
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingHandler answers with the number of calls it has run, so replays
+// are told apart from new runs.
+type countingHandler struct {
+	calls atomic.Int32
+	// fail makes the next call return an error result.
+	fail atomic.Bool
+	// release, when set, holds every call until it is closed.
+	release chan struct{}
+}
+
+func (h *countingHandler) handle(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	count := h.calls.Add(1)
+	if h.release != nil {
+		<-h.release
+	}
+	if _, ok := request.GetArguments()[KeyArgument]; ok {
+		return nil, errors.New("the key reached the handler")
+	}
+	if h.fail.CompareAndSwap(true, false) {
+		return mcp.NewToolResultError("failed"), nil
+	}
+	return mcp.NewToolResultText(string(rune('0' + count))), nil
+}
+
+// newTestStore declares a keyed tool and returns a store around handler.
+func newTestStore(name string, handler *countingHandler, opts ...Option) func(map[string]any) (*mcp.CallToolResult, error) {
+	mcp.NewTool(name, mcp.WithString("id"), WithKey())
+	opts = append(opts, WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	wrapped := NewStore(opts...).Middleware()(handler.handle)
+	return func(arguments map[string]any) (*mcp.CallToolResult, error) {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = name
+		request.Params.Arguments = arguments
+		return wrapped(context.Background(), request)
+	}
+}
+
+// text returns the text of a result.
+func text(t *testing.T, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.NotEmpty(t, result.Content)
+	content, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	return content.Text
+}
+
+func TestWithKey(t *testing.T) {
+	t.Parallel()
+	tool := mcp.NewTool("idempotency-schema", WithKey())
+	assert.Contains(t, tool.InputSchema.Properties, KeyArgument)
+	assert.True(t, Accepts("idempotency-schema"))
+	assert.False(t, Accepts("idempotency-unknown"))
+}
+
+func TestMiddleware_ReplaysResult(t *testing.T) {
+	t.Parallel()
+	handler := &countingHandler{}
+	call := newTestStore("idempotency-replay", handler)
+
+	first, err := call(map[string]any{"id": "a", KeyArgument: "k1"})
+	require.NoError(t, err)
+	again, err := call(map[string]any{"id": "a", KeyArgument: "k1"})
+	require.NoError(t, err)
+	assert.Equal(t, "1", text(t, first))
+	assert.Equal(t, "1", text(t, again), "the repeat returns the first result")
+	assert.Equal(t, true, again.Meta.AdditionalFields[ReplayKey])
+	assert.Nil(t, first.Meta, "the first result is not marked")
+
+	other, err := call(map[string]any{"id": "a", KeyArgument: "k2"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", text(t, other), "another key runs the tool")
+	unkeyed, err := call(map[string]any{"id": "a"})
+	require.NoError(t, err)
+	assert.Equal(t, "3", text(t, unkeyed), "calls without a key always run")
+}
+
+func TestMiddleware_RejectsReusedKey(t *testing.T) {
+	t.Parallel()
+	call := newTestStore("idempotency-reuse", &countingHandler{})
+	_, err := call(map[string]any{"id": "a", KeyArgument: "k"})
+	require.NoError(t, err)
+	_, err = call(map[string]any{"id": "b", KeyArgument: "k"})
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, "IDEMPOTENCY_KEY_REUSED", toolErr.Code)
+}
+
+func TestMiddleware_RetriesFailures(t *testing.T) {
+	t.Parallel()
+	handler := &countingHandler{}
+	handler.fail.Store(true)
+	call := newTestStore("idempotency-failure", handler)
+
+	failed, err := call(map[string]any{KeyArgument: "k"})
+	require.NoError(t, err)
+	assert.True(t, failed.IsError)
+	retried, err := call(map[string]any{KeyArgument: "k"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", text(t, retried), "failed calls are not kept")
+}
+
+func TestMiddleware_WaitsForRunningCall(t *testing.T) {
+	t.Parallel()
+	handler := &countingHandler{release: make(chan struct{})}
+	call := newTestStore("idempotency-concurrent", handler)
+
+	var wg sync.WaitGroup
+	results := make([]string, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			result, err := call(map[string]any{KeyArgument: "k"})
+			assert.NoError(t, err)
+			results[i] = text(t, result)
+		}()
+	}
+	time.Sleep(20 * time.Millisecond)
+	close(handler.release)
+	wg.Wait()
+	assert.Equal(t, []string{"1", "1", "1"}, results)
+	assert.Equal(t, int32(1), handler.calls.Load())
+}
+
+func TestMiddleware_Expires(t *testing.T) {
+	t.Parallel()
+	handler := &countingHandler{}
+	now := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	call := newTestStore("idempotency-expiry", handler, WithTTL(time.Hour), func(c *Config) {
+		c.now = func() time.Time { return now }
+	})
+	_, err := call(map[string]any{KeyArgument: "k"})
+	require.NoError(t, err)
+	now = now.Add(2 * time.Hour)
+	result, err := call(map[string]any{KeyArgument: "k"})
+	require.NoError(t, err)
+	assert.Equal(t, "2", text(t, result))
+}
+
+func TestStore_ExportImport(t *testing.T) {
+	t.Parallel()
+	mcp.NewTool("idempotency-export", mcp.WithString("id"), WithKey())
+	logger := WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	handler := &countingHandler{}
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "idempotency-export"
+	request.Params.Arguments = map[string]any{"id": "a", KeyArgument: "k1"}
+
+	source := NewStore(logger)
+	_, err := source.Middleware()(handler.handle)(context.Background(), request)
+	require.NoError(t, err)
+	records := source.Export()
+	require.Len(t, records, 1)
+	assert.Equal(t, "idempotency-export", records[0].Tool)
+	assert.Equal(t, "k1", records[0].Key)
+
+	restored := NewStore(logger)
+	restored.Import(records)
+	replayed, err := restored.Middleware()(handler.handle)(context.Background(), request)
+	require.NoError(t, err)
+	assert.Equal(t, "1", text(t, replayed))
+	assert.Equal(t, true, replayed.Meta.AdditionalFields[ReplayKey])
+	assert.Equal(t, int32(1), handler.calls.Load(), "the restored result is replayed")
+}
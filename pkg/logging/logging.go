@@ -0,0 +1,124 @@
+package logging
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+)
+
+const (
+	// FormatText renders log records as key=value pairs.
+	FormatText = "text"
+	// FormatJSON renders log records as JSON objects.
+	FormatJSON = "json"
+)
+
+// Config describes how loggers are constructed.
+type Config struct {
+	// Format is either FormatText or FormatJSON.
+	Format string
+	// Level is the default minimum level for all loggers.
+	Level slog.Level
+	// ToolLevels overrides Level for individual tools, keyed by tool name.
+	ToolLevels map[string]slog.Level
+	// Writer receives the log output, defaults to os.Stderr.
+	Writer io.Writer
+}
+
+// Factory creates the server-wide logger and per-tool child loggers.
+type Factory struct {
+	config Config
+}
+
+// NewFactory creates a new logger factory from the given configuration.
+func NewFactory(cfg Config) (*Factory, error) {
+	switch cfg.Format {
+	case "":
+		cfg.Format = FormatText
+	case FormatText, FormatJSON:
+	default:
+		return nil, fmt.Errorf("unsupported log format: %s", cfg.Format)
+	}
+	if cfg.Writer == nil {
+		cfg.Writer = os.Stderr
+	}
+	return &Factory{config: cfg}, nil
+}
+
+// Logger returns a logger using the default level.
+func (f *Factory) Logger() *slog.Logger {
+	return slog.New(f.handler(f.config.Level))
+}
+
+// ToolLogger returns a child logger for the named tool. The logger carries a
+// "tool" attribute and honours any per-tool level override.
+func (f *Factory) ToolLogger(name string) *slog.Logger {
+	level := f.config.Level
+	if toolLevel, ok := f.config.ToolLevels[name]; ok {
+		level = toolLevel
+	}
+	return slog.New(f.handler(level)).With("tool", name)
+}
+
+func (f *Factory) handler(level slog.Level) slog.Handler {
+	opts := &slog.HandlerOptions{Level: level}
+	if f.config.Format == FormatJSON {
+		return slog.NewJSONHandler(f.config.Writer, opts)
+	}
+	return slog.NewTextHandler(f.config.Writer, opts)
+}
+
+// ParseLevel converts a level name such as "debug" or "warn" into a slog.Level.
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return level, fmt.Errorf("invalid log level %q: %w", name, err)
+	}
+	return level, nil
+}
+
+// ParseToolLevels parses a comma-separated list of tool=level pairs, for
+// example "git-summary=debug,literature-fetch=warn".
+func ParseToolLevels(spec string) (map[string]slog.Level, error) {
+	levels := make(map[string]slog.Level)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, levelName, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid tool log level %q, expected tool=level", pair)
+		}
+		level, err := ParseLevel(levelName)
+		if err != nil {
+			return nil, err
+		}
+		levels[strings.TrimSpace(name)] = level
+	}
+	return levels, nil
+}
+
+// WithRequestID returns a child logger annotated with a freshly generated
+// request id, so all records emitted while serving one call can be correlated.
+func WithRequestID(logger *slog.Logger) *slog.Logger {
+	return logger.With("request_id", NewRequestID())
+}
+
+// NewRequestID generates a random 16 character hexadecimal request id.
+func NewRequestID() string {
+	buf := make([]byte, 8)
+	if _, err := rand.Read(buf); err != nil {
+		return "unknown"
+	}
+	return hex.EncodeToString(buf)
+}
+
+// Discard returns a logger that drops all records.
+func Discard() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
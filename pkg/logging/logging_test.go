@@ -0,0 +1,56 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseToolLevels(t *testing.T) {
+	t.Parallel()
+
+	levels, err := ParseToolLevels("git-summary=debug, literature-fetch=WARN")
+	require.NoError(t, err)
+	assert.Equal(t, slog.LevelDebug, levels["git-summary"])
+	assert.Equal(t, slog.LevelWarn, levels["literature-fetch"])
+
+	_, err = ParseToolLevels("git-summary")
+	require.Error(t, err)
+
+	_, err = ParseToolLevels("git-summary=loud")
+	require.Error(t, err)
+}
+
+func TestNewFactory_InvalidFormat(t *testing.T) {
+	t.Parallel()
+
+	_, err := NewFactory(Config{Format: "xml"})
+	require.Error(t, err)
+}
+
+func TestFactory_ToolLogger(t *testing.T) {
+	t.Parallel()
+
+	var buf bytes.Buffer
+	factory, err := NewFactory(Config{
+		Format:     FormatJSON,
+		Level:      slog.LevelInfo,
+		ToolLevels: map[string]slog.Level{"quiet": slog.LevelError},
+		Writer:     &buf,
+	})
+	require.NoError(t, err)
+
+	factory.ToolLogger("quiet").Info("dropped")
+	assert.Empty(t, buf.String(), "per-tool level should suppress info records")
+
+	WithRequestID(factory.ToolLogger("markdown")).Info("kept")
+	var record map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &record))
+	assert.Equal(t, "kept", record["msg"])
+	assert.Equal(t, "markdown", record["tool"])
+	assert.Len(t, record["request_id"], 16)
+}
@@ -0,0 +1,123 @@
+package markdown
+
+import (
+	"bytes"
+	"strings"
+	"unicode"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// maxSlugLength bounds the length of chapter slugs, which end up in file
+// names.
+const maxSlugLength = 40
+
+// Chapter is the part of a markdown document from one heading to the next
+// heading of the same or a higher level.
+type Chapter struct {
+	// Title is the text of the heading, as written in the source.
+	Title string
+	// Content is the markdown of the chapter, starting with its heading.
+	Content string
+}
+
+// Slug returns a lowercase, hyphenated form of the title for file names,
+// such as data-sources for "Data Sources (2024)".
+func (c Chapter) Slug() string {
+	var slug strings.Builder
+	hyphen := false
+	for _, r := range strings.ToLower(c.Title) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if hyphen && slug.Len() > 0 {
+				slug.WriteByte('-')
+			}
+			hyphen = false
+			slug.WriteRune(r)
+		default:
+			hyphen = true
+		}
+		if slug.Len() >= maxSlugLength {
+			break
+		}
+	}
+	if slug.Len() == 0 {
+		return "chapter"
+	}
+	return slug.String()
+}
+
+// headingStart is a heading of a document and the offset of the line it
+// starts on.
+type headingStart struct {
+	level  int
+	title  string
+	offset int
+}
+
+// ChapterLevel returns the highest heading level, 1 for #, that occurs at
+// least twice in source and so splits it into chapters, or 0 when no
+// level does.
+func ChapterLevel(source []byte) int {
+	counts := make(map[int]int)
+	for _, heading := range headings(source) {
+		counts[heading.level]++
+	}
+	for level := 1; level <= 6; level++ {
+		if counts[level] >= 2 {
+			return level
+		}
+	}
+	return 0
+}
+
+// SplitChapters splits source into chapters at its headings of level.
+// Text before the first of those headings, such as the document title and
+// an introduction, is kept with the first chapter, and headings within code
+// blocks are not split at. A document without such headings is one
+// chapter.
+func SplitChapters(source []byte, level int) []Chapter {
+	var starts []headingStart
+	for _, heading := range headings(source) {
+		if heading.level == level {
+			starts = append(starts, heading)
+		}
+	}
+	if len(starts) == 0 {
+		return []Chapter{{Content: string(source)}}
+	}
+	chapters := make([]Chapter, 0, len(starts))
+	for i, start := range starts {
+		from, to := start.offset, len(source)
+		if i == 0 {
+			from = 0
+		}
+		if i+1 < len(starts) {
+			to = starts[i+1].offset
+		}
+		chapters = append(chapters, Chapter{Title: start.title, Content: string(source[from:to])})
+	}
+	return chapters
+}
+
+// headings returns the headings of source in document order.
+func headings(source []byte) []headingStart {
+	document := goldmark.DefaultParser().Parse(text.NewReader(source))
+	var found []headingStart
+	for child := document.FirstChild(); child != nil; child = child.NextSibling() {
+		heading, ok := child.(*ast.Heading)
+		if !ok || heading.Lines().Len() == 0 {
+			continue
+		}
+		offset := heading.Lines().At(0).Start
+		offset = bytes.LastIndexByte(source[:offset], '\n') + 1
+		found = append(found, headingStart{
+			level:  heading.Level,
+			title:  strings.TrimSpace(string(heading.Lines().Value(source))),
+			offset: offset,
+		})
+	}
+	return found
+}
@@ -0,0 +1,39 @@
+package markdown
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const report = "# dictyBase Summary\n\nThe year in review.\n\n" +
+	"## Data Sources (2024)\n\nStock center.\n\n```\n## not a heading\n```\n\n" +
+	"### Details\n\nMore.\n\n" +
+	"Annotations\n-----------\n\nGO terms.\n"
+
+func TestChapterLevel(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, 2, ChapterLevel([]byte(report)), "a single title does not split")
+	assert.Equal(t, 1, ChapterLevel([]byte("# One\n\n# Two\n")))
+	assert.Equal(t, 0, ChapterLevel([]byte("# Only\n\nText.\n")))
+}
+
+func TestSplitChapters(t *testing.T) {
+	t.Parallel()
+	chapters := SplitChapters([]byte(report), 2)
+	require.Len(t, chapters, 2)
+	assert.Equal(t, "Data Sources (2024)", chapters[0].Title)
+	assert.Equal(t, "data-sources-2024", chapters[0].Slug())
+	assert.Equal(t,
+		"# dictyBase Summary\n\nThe year in review.\n\n"+
+			"## Data Sources (2024)\n\nStock center.\n\n```\n## not a heading\n```\n\n### Details\n\nMore.\n\n",
+		chapters[0].Content, "the preamble and subsections stay in the chapter")
+	assert.Equal(t, "Annotations", chapters[1].Title)
+	assert.Equal(t, "Annotations\n-----------\n\nGO terms.\n", chapters[1].Content)
+
+	single := SplitChapters([]byte("Just text.\n"), 1)
+	require.Len(t, single, 1)
+	assert.Equal(t, "chapter", single[0].Slug())
+	assert.Equal(t, "Just text.\n", single[0].Content)
+}
@@ -0,0 +1,197 @@
+package markdown
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+// Identifier types of citations.
+const (
+	CitationPMID = "pmid"
+	CitationDOI  = "doi"
+)
+
+// ReferencesHeading is the heading of the reference list appended to a
+// document, which in-text citations link to by its id, references.
+const ReferencesHeading = "## References"
+
+// citationPattern matches a group of citations, such as [@pmid:23172289]
+// or [@pmid:23172289; @doi:10.1093/nar/gks1064].
+var citationPattern = regexp.MustCompile(
+	`\[\s*(@(?i:pmid|doi):[^\s;\]]+(?:\s*;\s*@(?i:pmid|doi):[^\s;\]]+)*)\s*\]`,
+)
+
+// citationIDPatterns validate the identifiers of citations by type.
+var citationIDPatterns = map[string]*regexp.Regexp{
+	CitationPMID: regexp.MustCompile(`^\d+$`),
+	CitationDOI:  regexp.MustCompile(`^10\.\d{4,9}/\S+$`),
+}
+
+// Citation is a publication cited in markdown as [@pmid:23172289] or
+// [@doi:10.1093/nar/gks1064].
+type Citation struct {
+	// Type is CitationPMID or CitationDOI.
+	Type string `json:"type"`
+	ID   string `json:"id"`
+}
+
+// key identifies the cited publication, ignoring the case of DOIs.
+func (c Citation) key() string {
+	return c.Type + ":" + strings.ToLower(c.ID)
+}
+
+// CitationResolver returns the entry of the reference list for a cited
+// publication, as a line of markdown.
+type CitationResolver func(ctx context.Context, citation Citation) (string, error)
+
+// Reference is an entry of the reference list of a document.
+type Reference struct {
+	Number   int      `json:"number"`
+	Citation Citation `json:"citation"`
+	Text     string   `json:"text"`
+	// Unresolved tells the publication could not be looked up, and Text
+	// only names its identifier.
+	Unresolved bool `json:"unresolved,omitempty"`
+}
+
+// ExpandCitations replaces the citations of source with numbered in-text
+// citations, [1] or [1, 3] for a group, linking to a reference list that
+// is appended to the document under ReferencesHeading. Publications are
+// numbered in the order they are first cited and looked up once each with
+// resolve. A publication resolve fails on is listed by its identifier and
+// marked Unresolved, unless ctx is done, which is an error. Citations in
+// code are left alone, and a document without citations is returned as is.
+func ExpandCitations(ctx context.Context, source string, resolve CitationResolver) (string, []Reference, error) {
+	groups := citationGroups(source)
+	if len(groups) == 0 {
+		return source, nil, nil
+	}
+	var references []Reference
+	numbers := make(map[string]int)
+	var expanded strings.Builder
+	last := 0
+	for _, group := range groups {
+		cited := make([]string, 0, len(group.citations))
+		for _, citation := range group.citations {
+			number, ok := numbers[citation.key()]
+			if !ok {
+				number = len(references) + 1
+				numbers[citation.key()] = number
+				references = append(references, Reference{Number: number, Citation: citation})
+			}
+			cited = append(cited, fmt.Sprintf("[%d](#references)", number))
+		}
+		expanded.WriteString(source[last:group.start])
+		expanded.WriteString("[" + strings.Join(cited, ", ") + "]")
+		last = group.end
+	}
+	expanded.WriteString(source[last:])
+
+	for i := range references {
+		reference := &references[i]
+		text, err := resolve(ctx, reference.Citation)
+		if err != nil {
+			if ctx.Err() != nil {
+				return "", nil, fmt.Errorf("failed to resolve citation %s: %w", reference.Citation.key(), err)
+			}
+			text, reference.Unresolved = unresolvedText(reference.Citation), true
+		}
+		reference.Text = strings.Join(strings.Fields(text), " ")
+	}
+
+	var result strings.Builder
+	result.WriteString(strings.TrimRight(expanded.String(), "\n") + "\n\n" + ReferencesHeading + "\n\n")
+	for _, reference := range references {
+		fmt.Fprintf(&result, "%d. %s\n", reference.Number, reference.Text)
+	}
+	return result.String(), references, nil
+}
+
+// unresolvedText is the reference list entry of a publication that could
+// not be looked up.
+func unresolvedText(citation Citation) string {
+	if citation.Type == CitationPMID {
+		return fmt.Sprintf("PMID [%s](https://pubmed.ncbi.nlm.nih.gov/%s/) (not found)", citation.ID, citation.ID)
+	}
+	return fmt.Sprintf("[doi:%s](https://doi.org/%s) (not found)", citation.ID, citation.ID)
+}
+
+// citationGroup is a bracketed group of citations and its offsets in the
+// source.
+type citationGroup struct {
+	start, end int
+	citations  []Citation
+}
+
+// citationGroups returns the groups of citations of source outside code,
+// skipping groups with an identifier that is not a PMID or a DOI, and the
+// text of links such as [@pmid:1](https://example.org).
+func citationGroups(source string) []citationGroup {
+	code := codeRanges([]byte(source))
+	var groups []citationGroup
+	for _, match := range citationPattern.FindAllStringSubmatchIndex(source, -1) {
+		start, end := match[0], match[1]
+		if inRanges(code, start) || (end < len(source) && (source[end] == '(' || source[end] == '[')) {
+			continue
+		}
+		group := citationGroup{start: start, end: end}
+		for _, item := range strings.Split(source[match[2]:match[3]], ";") {
+			kind, id, _ := strings.Cut(strings.TrimPrefix(strings.TrimSpace(item), "@"), ":")
+			kind = strings.ToLower(kind)
+			if !citationIDPatterns[kind].MatchString(id) {
+				group.citations = nil
+				break
+			}
+			group.citations = append(group.citations, Citation{Type: kind, ID: id})
+		}
+		if len(group.citations) > 0 {
+			groups = append(groups, group)
+		}
+	}
+	return groups
+}
+
+// codeRanges returns the offsets of the code blocks, code spans and HTML
+// blocks of source.
+func codeRanges(source []byte) [][2]int {
+	document := goldmark.DefaultParser().Parse(text.NewReader(source))
+	var ranges [][2]int
+	_ = ast.Walk(document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch n.Kind() {
+		case ast.KindCodeBlock, ast.KindFencedCodeBlock, ast.KindHTMLBlock:
+			lines := n.Lines()
+			for i := range lines.Len() {
+				ranges = append(ranges, [2]int{lines.At(i).Start, lines.At(i).Stop})
+			}
+			return ast.WalkSkipChildren, nil
+		case ast.KindCodeSpan:
+			for child := n.FirstChild(); child != nil; child = child.NextSibling() {
+				if segment, ok := child.(*ast.Text); ok {
+					ranges = append(ranges, [2]int{segment.Segment.Start, segment.Segment.Stop})
+				}
+			}
+			return ast.WalkSkipChildren, nil
+		}
+		return ast.WalkContinue, nil
+	})
+	return ranges
+}
+
+// inRanges reports whether offset lies within one of ranges.
+func inRanges(ranges [][2]int, offset int) bool {
+	for _, r := range ranges {
+		if offset >= r[0] && offset < r[1] {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,53 @@
+package markdown
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpandCitations(t *testing.T) {
+	t.Parallel()
+	source := "dictyBase [@pmid:23172289] hosts the genome [@DOI:10.1093/nar/gks1064; @pmid:1].\n\n" +
+		"Again [@doi:10.1093/NAR/GKS1064], not `[@pmid:2]` nor [@pmid:3](https://example.org) nor [@pmid:abc].\n\n" +
+		"```\n[@pmid:4]\n```\n"
+	var resolved []Citation
+	resolve := func(_ context.Context, citation Citation) (string, error) {
+		resolved = append(resolved, citation)
+		if citation.ID == "1" {
+			return "", errors.New("not found")
+		}
+		return "Fey P, et al. dictyBase 2013.\n*Nucleic Acids Res.* 2013.", nil
+	}
+
+	expanded, references, err := ExpandCitations(context.Background(), source, resolve)
+	require.NoError(t, err)
+	assert.Equal(t,
+		"dictyBase [[1](#references)] hosts the genome [[2](#references), [3](#references)].\n\n"+
+			"Again [[2](#references)], not `[@pmid:2]` nor [@pmid:3](https://example.org) nor [@pmid:abc].\n\n"+
+			"```\n[@pmid:4]\n```\n\n"+
+			"## References\n\n"+
+			"1. Fey P, et al. dictyBase 2013. *Nucleic Acids Res.* 2013.\n"+
+			"2. Fey P, et al. dictyBase 2013. *Nucleic Acids Res.* 2013.\n"+
+			"3. PMID [1](https://pubmed.ncbi.nlm.nih.gov/1/) (not found)\n",
+		expanded)
+	assert.Len(t, resolved, 3, "each publication is looked up once")
+	require.Len(t, references, 3)
+	assert.Equal(t, Citation{Type: CitationDOI, ID: "10.1093/nar/gks1064"}, references[1].Citation)
+	assert.True(t, references[2].Unresolved)
+
+	plain, references, err := ExpandCitations(context.Background(), "No citations.\n", resolve)
+	require.NoError(t, err)
+	assert.Equal(t, "No citations.\n", plain)
+	assert.Empty(t, references)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, _, err = ExpandCitations(ctx, source, func(ctx context.Context, _ Citation) (string, error) {
+		return "", ctx.Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+}
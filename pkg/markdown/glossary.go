@@ -0,0 +1,176 @@
+package markdown
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/yuin/goldmark"
+	meta "github.com/yuin/goldmark-meta"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/text"
+)
+
+// GenePageURL is the address of a dictyBase gene page, followed by the
+// gene ID.
+const GenePageURL = "https://dictybase.org/gene/"
+
+// geneIDPattern matches dictyBase gene IDs, such as DDB_G0283275.
+var geneIDPattern = regexp.MustCompile(`^DDB_G\d{7}$`)
+
+// termParser parses documents the way NewParser does, to tell the text of
+// a document from its code, links and headings.
+var termParser = goldmark.New(goldmark.WithExtensions(
+	extension.GFM,
+	extension.Footnote,
+	extension.DefinitionList,
+	meta.Meta,
+)).Parser()
+
+// Glossary maps terms, such as gene names, to the pages they link to. A
+// page may be given as a dictyBase gene ID, for the page of the gene.
+type Glossary map[string]string
+
+// LoadGlossary reads a glossary from a JSON object of terms and pages.
+func LoadGlossary(path string) (Glossary, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading glossary: %w", err)
+	}
+	var glossary Glossary
+	if err := json.Unmarshal(data, &glossary); err != nil {
+		return nil, fmt.Errorf("error parsing glossary %s: %w", path, err)
+	}
+	return glossary, nil
+}
+
+// ParseGlossary reads a glossary from comma-separated term=page pairs, such
+// as "carA=DDB_G0273397,cAMP=https://en.wikipedia.org/wiki/CAMP".
+func ParseGlossary(pairs string) (Glossary, error) {
+	glossary := make(Glossary)
+	for _, pair := range strings.Split(pairs, ",") {
+		if strings.TrimSpace(pair) == "" {
+			continue
+		}
+		term, page, found := strings.Cut(pair, "=")
+		term, page = strings.TrimSpace(term), strings.TrimSpace(page)
+		if !found || term == "" || page == "" {
+			return nil, fmt.Errorf("glossary entry %q is not term=page", strings.TrimSpace(pair))
+		}
+		glossary[term] = page
+	}
+	return glossary, nil
+}
+
+// Merge returns the terms of g and of other, the pages of other taking
+// precedence.
+func (g Glossary) Merge(other Glossary) Glossary {
+	merged := make(Glossary, len(g)+len(other))
+	for term, page := range g {
+		merged[term] = page
+	}
+	for term, page := range other {
+		merged[term] = page
+	}
+	return merged
+}
+
+// page returns the address a term links to.
+func (g Glossary) page(term string) string {
+	page := g[term]
+	if page == "" && geneIDPattern.MatchString(term) {
+		page = term
+	}
+	if geneIDPattern.MatchString(page) {
+		return GenePageURL + page
+	}
+	return page
+}
+
+// LinkTerms links the dictyBase gene IDs of source, such as DDB_G0283275,
+// to their gene pages, and the terms of glossary to their pages. Terms are
+// matched as whole words, case sensitively and longest first; those in
+// code, headings, links and HTML blocks are left alone, as are terms
+// without a page.
+func LinkTerms(source string, glossary Glossary) string {
+	terms := make([]string, 0, len(glossary)+1)
+	for term := range glossary {
+		if term != "" && glossary.page(term) != "" {
+			terms = append(terms, regexp.QuoteMeta(term))
+		}
+	}
+	slices.SortFunc(terms, func(a, b string) int {
+		if len(a) != len(b) {
+			return len(b) - len(a)
+		}
+		return strings.Compare(a, b)
+	})
+	terms = append(terms, `DDB_G\d{7}`)
+	pattern := regexp.MustCompile(strings.Join(terms, "|"))
+
+	var linked strings.Builder
+	last := 0
+	for _, span := range textSpans([]byte(source)) {
+		for _, match := range pattern.FindAllStringIndex(source[span[0]:span[1]], -1) {
+			start, end := span[0]+match[0], span[0]+match[1]
+			if !wordBoundary(source, start, end) {
+				continue
+			}
+			term := source[start:end]
+			linked.WriteString(source[last:start])
+			fmt.Fprintf(&linked, "[%s](%s)", term, glossary.page(term))
+			last = end
+		}
+	}
+	linked.WriteString(source[last:])
+	return linked.String()
+}
+
+// wordBoundary reports whether source[start:end] is a whole word, with no
+// letter, digit or underscore right before or after it.
+func wordBoundary(source string, start, end int) bool {
+	isWord := func(r rune) bool {
+		return r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r)
+	}
+	if before, _ := utf8.DecodeLastRuneInString(source[:start]); start > 0 && isWord(before) {
+		return false
+	}
+	if after, _ := utf8.DecodeRuneInString(source[end:]); end < len(source) && isWord(after) {
+		return false
+	}
+	return true
+}
+
+// textSpans returns the offsets of the plain text of source, joining text
+// split by markup that did not take effect, such as the underscore of
+// DDB_G0283275. Text within code, headings, links, images and HTML is
+// left out.
+func textSpans(source []byte) [][2]int {
+	document := termParser.Parse(text.NewReader(source))
+	var spans [][2]int
+	_ = ast.Walk(document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Heading, *ast.CodeBlock, *ast.FencedCodeBlock, *ast.HTMLBlock,
+			*ast.CodeSpan, *ast.Link, *ast.Image, *ast.AutoLink, *ast.RawHTML:
+			return ast.WalkSkipChildren, nil
+		case *ast.Text:
+			segment := node.Segment
+			if last := len(spans) - 1; last >= 0 && spans[last][1] == segment.Start {
+				spans[last][1] = segment.Stop
+			} else {
+				spans = append(spans, [2]int{segment.Start, segment.Stop})
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	return spans
+}
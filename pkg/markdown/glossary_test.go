@@ -0,0 +1,59 @@
+package markdown
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLinkTerms(t *testing.T) {
+	t.Parallel()
+	source := "---\ntitle: carA DDB_G0273397\n---\n" +
+		"# carA\n\n" +
+		"The carA gene (DDB_G0273397) and *carA-1* bind cAMP, unlike xcarA or DDB_G02733971.\n\n" +
+		"See [carA](https://example.org), `carA`, <https://example.org/carA> and <b>carA</b>.\n\n" +
+		"```\ncarA DDB_G0273397\n```\n"
+	glossary := Glossary{"carA": "DDB_G0273397", "carA-1": "https://example.org/carA-1", "cAMP": ""}
+
+	assert.Equal(t,
+		"---\ntitle: carA DDB_G0273397\n---\n"+
+			"# carA\n\n"+
+			"The [carA](https://dictybase.org/gene/DDB_G0273397) gene "+
+			"([DDB_G0273397](https://dictybase.org/gene/DDB_G0273397)) and "+
+			"*[carA-1](https://example.org/carA-1)* bind cAMP, unlike xcarA or DDB_G02733971.\n\n"+
+			"See [carA](https://example.org), `carA`, <https://example.org/carA> and "+
+			"<b>[carA](https://dictybase.org/gene/DDB_G0273397)</b>.\n\n"+
+			"```\ncarA DDB_G0273397\n```\n",
+		LinkTerms(source, glossary))
+}
+
+func TestParseGlossary(t *testing.T) {
+	t.Parallel()
+	glossary, err := ParseGlossary(" carA = DDB_G0273397, ,cAMP=https://example.org/cAMP")
+	require.NoError(t, err)
+	assert.Equal(t, Glossary{"carA": "DDB_G0273397", "cAMP": "https://example.org/cAMP"}, glossary)
+
+	_, err = ParseGlossary("carA")
+	assert.Error(t, err)
+
+	merged := Glossary{"carA": "https://example.org", "acaA": "DDB_G0281545"}.Merge(glossary)
+	assert.Equal(t, Glossary{
+		"carA": "DDB_G0273397", "acaA": "DDB_G0281545", "cAMP": "https://example.org/cAMP",
+	}, merged)
+}
+
+func TestLoadGlossary(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "glossary.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"carA": "DDB_G0273397"}`), 0o600))
+	glossary, err := LoadGlossary(path)
+	require.NoError(t, err)
+	assert.Equal(t, Glossary{"carA": "DDB_G0273397"}, glossary)
+
+	require.NoError(t, os.WriteFile(path, []byte(`["carA"]`), 0o600))
+	_, err = LoadGlossary(path)
+	assert.Error(t, err)
+}
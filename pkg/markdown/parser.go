@@ -14,6 +14,8 @@ import (
 )
 
 // Parser is a Markdown parser with GFM, syntax highlighting, typographer extensions and XHTML rendering.
+// Footnotes, definition lists and heading ids, generated or set as in `## Methods {#methods}`, support
+// the cross-references of scholarly documents, with the anchors the PDF renderer uses too.
 type Parser struct {
 	converter goldmark.Markdown
 	context   parser.Context
@@ -37,6 +39,8 @@ func WithXHTML() ParserOption {
 			goldmark.WithExtensions(
 				extension.GFM,
 				extension.Typographer,
+				extension.Footnote,
+				extension.DefinitionList,
 				highlighting.NewHighlighting(
 					highlighting.WithStyle("github"),
 				),
@@ -45,6 +49,7 @@ func WithXHTML() ParserOption {
 			),
 			goldmark.WithParserOptions(
 				parser.WithAutoHeadingID(),
+				parser.WithHeadingAttribute(),
 			),
 			goldmark.WithRendererOptions(
 				html_renderer.WithHardWraps(),
@@ -62,6 +67,8 @@ func WithUnsafeHTML() ParserOption {
 			goldmark.WithExtensions(
 				extension.GFM,
 				extension.Typographer,
+				extension.Footnote,
+				extension.DefinitionList,
 				highlighting.NewHighlighting(
 					highlighting.WithStyle("github"),
 				),
@@ -70,6 +77,7 @@ func WithUnsafeHTML() ParserOption {
 			),
 			goldmark.WithParserOptions(
 				parser.WithAutoHeadingID(),
+				parser.WithHeadingAttribute(),
 			),
 			goldmark.WithRendererOptions(
 				html_renderer.WithHardWraps(),
@@ -87,6 +95,8 @@ func NewParser(opts ...ParserOption) *Parser {
 			goldmark.WithExtensions(
 				extension.GFM,
 				extension.Typographer,
+				extension.Footnote,
+				extension.DefinitionList,
 				highlighting.NewHighlighting(
 					highlighting.WithStyle("paraiso-light"),
 				),
@@ -95,6 +105,7 @@ func NewParser(opts ...ParserOption) *Parser {
 			),
 			goldmark.WithParserOptions(
 				parser.WithAutoHeadingID(),
+				parser.WithHeadingAttribute(),
 			),
 			goldmark.WithRendererOptions(
 				html_renderer.WithHardWraps(),
@@ -34,6 +34,25 @@ func getParserTestCases() []parserTestCase {
 			want:     "<table>",
 			options:  nil,
 		},
+		{
+			name:     "footnotes",
+			markdown: "Claim[^1]\n\n[^1]: [abc1234](https://github.com/o/r/commit/abc1234)",
+			want:     "<div class=\"footnotes\" role=\"doc-endnotes\">",
+			options:  nil,
+		},
+		{
+			name:     "definition lists",
+			markdown: "Axenic\n: Grown without other organisms.",
+			want:     "<dl>\n<dt>Axenic</dt>\n<dd>Grown without other organisms.</dd>",
+			options:  nil,
+		},
+		{
+			name:     "heading anchors",
+			markdown: "## Methods {#methods}\n\nSee [Methods](#methods) and [Results](#results).\n\n## Results",
+			want: "<h2 id=\"methods\">Methods</h2>\n<p>See <a href=\"#methods\">Methods</a> and " +
+				"<a href=\"#results\">Results</a>.</p>\n<h2 id=\"results\">",
+			options: nil,
+		},
 		{
 			name:     "emoji",
 			markdown: ":smile:",
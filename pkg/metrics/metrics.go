@@ -0,0 +1,108 @@
+package metrics
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const (
+	// StatusSuccess labels a call that completed without error.
+	StatusSuccess = "success"
+	// StatusError labels a call that returned an error.
+	StatusError = "error"
+)
+
+// Outbound service labels used with ObserveOutbound.
+const (
+	ServiceOpenAI          = "openai"
+	ServiceEuropePMC       = "europepmc"
+	ServicePubMed          = "pubmed"
+	ServiceGitClone        = "git_clone"
+	ServiceZotero          = "zotero"
+	ServiceORCID           = "orcid"
+	ServiceGeneOntology    = "geneontology"
+	ServiceGitHub          = "github"
+	ServiceOSV             = "osv"
+	ServiceDepsDev         = "depsdev"
+	ServiceCrossref        = "crossref"
+	ServiceRegistry        = "registry"
+	ServiceBioRxiv         = "biorxiv"
+	ServiceArXiv           = "arxiv"
+	ServiceOpenAlex        = "openalex"
+	ServiceSemanticScholar = "semanticscholar"
+	ServiceDictyBase       = "dictybase"
+	ServiceMeSH            = "mesh"
+)
+
+var (
+	toolCalls = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Namespace: "dcr_mcp",
+			Name:      "tool_calls_total",
+			Help:      "Number of tool invocations by tool and status.",
+		},
+		[]string{"tool", "status"},
+	)
+	toolDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "dcr_mcp",
+			Name:      "tool_call_duration_seconds",
+			Help:      "Duration of tool invocations by tool.",
+			Buckets:   prometheus.ExponentialBuckets(0.01, 4, 8),
+		},
+		[]string{"tool"},
+	)
+	outboundDuration = promauto.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Namespace: "dcr_mcp",
+			Name:      "outbound_request_duration_seconds",
+			Help:      "Latency of outbound API calls by service and status.",
+			Buckets:   prometheus.ExponentialBuckets(0.05, 3, 8),
+		},
+		[]string{"service", "status"},
+	)
+)
+
+// ToolMiddleware records call counts, error rates and durations for every
+// tool invocation that passes through it.
+func ToolMiddleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		start := time.Now()
+		result, err := next(ctx, request)
+		status := StatusSuccess
+		if err != nil || (result != nil && result.IsError) {
+			status = StatusError
+		}
+		toolCalls.WithLabelValues(request.Params.Name, status).Inc()
+		toolDuration.WithLabelValues(request.Params.Name).
+			Observe(time.Since(start).Seconds())
+		return result, err
+	}
+}
+
+// ObserveOutbound records the latency of an outbound call to service that
+// started at start and finished with err.
+func ObserveOutbound(service string, start time.Time, err error) {
+	status := StatusSuccess
+	if err != nil {
+		status = StatusError
+	}
+	outboundDuration.WithLabelValues(service, status).
+		Observe(time.Since(start).Seconds())
+}
+
+// Handler returns the HTTP handler that exposes metrics in the Prometheus
+// text format.
+func Handler() http.Handler {
+	return promhttp.Handler()
+}
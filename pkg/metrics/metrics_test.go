@@ -0,0 +1,57 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestToolMiddleware(t *testing.T) {
+	t.Parallel()
+
+	handler := ToolMiddleware(func(
+		_ context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error) {
+		if request.GetArguments()["fail"] == true {
+			return nil, errors.New("boom")
+		}
+		return mcp.NewToolResultText("ok"), nil
+	})
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "metrics-test-tool"
+	_, err := handler(context.Background(), request)
+	require.NoError(t, err)
+
+	request.Params.Arguments = map[string]any{"fail": true}
+	_, err = handler(context.Background(), request)
+	require.Error(t, err)
+
+	assert.InDelta(t, 1, testutil.ToFloat64(
+		toolCalls.WithLabelValues("metrics-test-tool", StatusSuccess),
+	), 0)
+	assert.InDelta(t, 1, testutil.ToFloat64(
+		toolCalls.WithLabelValues("metrics-test-tool", StatusError),
+	), 0)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+
+	ObserveOutbound(ServiceOpenAI, time.Now(), nil)
+
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "dcr_mcp_outbound_request_duration_seconds")
+}
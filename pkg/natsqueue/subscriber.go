@@ -0,0 +1,211 @@
+package natsqueue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nats-io/nats.go"
+)
+
+// drainPollInterval is how often Stop checks whether the subscription
+// has drained.
+const drainPollInterval = 10 * time.Millisecond
+
+// Initialize validator.
+var validate = validator.New()
+
+// ToolInvoker runs a registered tool by name.
+type ToolInvoker interface {
+	CallTool(
+		ctx context.Context,
+		name string,
+		args map[string]any,
+	) (*mcp.CallToolResult, error)
+}
+
+// JobRequest is the message payload that asks for a tool run.
+type JobRequest struct {
+	ID        string         `json:"id"`
+	Tool      string         `json:"tool"      validate:"required"`
+	Arguments map[string]any `json:"arguments"`
+}
+
+// JobResult is the message payload published once a tool run finishes.
+type JobResult struct {
+	ID     string              `json:"id,omitempty"`
+	Tool   string              `json:"tool,omitempty"`
+	Result *mcp.CallToolResult `json:"result,omitempty"`
+	Error  string              `json:"error,omitempty"`
+}
+
+// Subscriber consumes tool-run requests from a NATS subject and publishes
+// the results. Requests sent with a reply subject receive the result as a
+// reply; all results are also published to the result subject.
+type Subscriber struct {
+	conn          *nats.Conn
+	invoker       ToolInvoker
+	subject       string
+	queue         string
+	resultSubject string
+	timeout       time.Duration
+	logger        *slog.Logger
+	subscription  *nats.Subscription
+	wg            sync.WaitGroup
+}
+
+// Option defines a functional option for configuring Subscriber.
+type Option func(*Subscriber)
+
+// WithSubject sets the subject on which tool-run requests are received.
+func WithSubject(subject string) Option {
+	return func(s *Subscriber) {
+		s.subject = subject
+	}
+}
+
+// WithQueue sets the queue group so that multiple server instances share
+// the request load.
+func WithQueue(queue string) Option {
+	return func(s *Subscriber) {
+		s.queue = queue
+	}
+}
+
+// WithResultSubject sets the subject on which results are published. An
+// empty subject disables publishing of results other than replies.
+func WithResultSubject(subject string) Option {
+	return func(s *Subscriber) {
+		s.resultSubject = subject
+	}
+}
+
+// WithJobTimeout limits how long a single tool run may take.
+func WithJobTimeout(timeout time.Duration) Option {
+	return func(s *Subscriber) {
+		s.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the subscriber.
+func WithLogger(logger *slog.Logger) Option {
+	return func(s *Subscriber) {
+		s.logger = logger
+	}
+}
+
+// NewSubscriber creates a new Subscriber on an established NATS connection.
+func NewSubscriber(
+	conn *nats.Conn,
+	invoker ToolInvoker,
+	opts ...Option,
+) *Subscriber {
+	subscriber := &Subscriber{
+		conn:          conn,
+		invoker:       invoker,
+		subject:       "dcr.tools.run",
+		queue:         "dcr-mcp",
+		resultSubject: "dcr.tools.results",
+		timeout:       10 * time.Minute,
+		logger:        slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(subscriber)
+	}
+	return subscriber
+}
+
+// Start subscribes to the request subject.
+func (s *Subscriber) Start() error {
+	subscription, err := s.conn.QueueSubscribe(s.subject, s.queue, s.handleMessage)
+	if err != nil {
+		return fmt.Errorf("failed to subscribe to %s: %w", s.subject, err)
+	}
+	s.subscription = subscription
+	s.logger.Info(
+		"listening for tool-run requests",
+		"subject", s.subject,
+		"queue", s.queue,
+	)
+	return nil
+}
+
+// Stop drains the subscription and waits for in-flight jobs to finish.
+// Drain hands the pending messages over in the background and closes the
+// subscription after the last one, so Stop waits for that before waiting
+// for the jobs.
+func (s *Subscriber) Stop() error {
+	if s.subscription != nil {
+		if err := s.subscription.Drain(); err != nil {
+			return fmt.Errorf("failed to drain subscription: %w", err)
+		}
+		for s.subscription.IsValid() {
+			time.Sleep(drainPollInterval)
+		}
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// handleMessage runs each job in its own goroutine so that long-running
+// tools do not block delivery of further requests.
+func (s *Subscriber) handleMessage(msg *nats.Msg) {
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		ctx, cancel := context.WithTimeout(context.Background(), s.timeout)
+		defer cancel()
+		s.publish(msg, s.process(ctx, msg.Data))
+	}()
+}
+
+// process decodes a request, runs the tool and builds the result.
+func (s *Subscriber) process(ctx context.Context, data []byte) JobResult {
+	var request JobRequest
+	if err := json.Unmarshal(data, &request); err != nil {
+		return JobResult{Error: fmt.Sprintf("invalid job request: %v", err)}
+	}
+	result := JobResult{ID: request.ID, Tool: request.Tool}
+	if err := validate.Struct(request); err != nil {
+		result.Error = fmt.Sprintf("validation error: %v", err)
+		return result
+	}
+
+	toolResult, err := s.invoker.CallTool(ctx, request.Tool, request.Arguments)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.Result = toolResult
+	return result
+}
+
+// publish sends the result as a reply and to the result subject.
+func (s *Subscriber) publish(msg *nats.Msg, result JobResult) {
+	payload, err := json.Marshal(result)
+	if err != nil {
+		s.logger.Error("failed to encode job result", "error", err)
+		return
+	}
+	if msg.Reply != "" {
+		if err := msg.Respond(payload); err != nil {
+			s.logger.Error("failed to reply with job result", "error", err)
+		}
+	}
+	if s.resultSubject != "" {
+		if err := s.conn.Publish(s.resultSubject, payload); err != nil {
+			s.logger.Error("failed to publish job result", "error", err)
+		}
+	}
+	s.logger.Info(
+		"finished tool-run request",
+		"job_id", result.ID,
+		"tool", result.Tool,
+		"error", result.Error,
+	)
+}
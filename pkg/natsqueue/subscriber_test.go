@@ -0,0 +1,228 @@
+package natsqueue
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/nats-io/nats.go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type stubInvoker struct{}
+
+func (stubInvoker) CallTool(
+	_ context.Context,
+	name string,
+	args map[string]any,
+) (*mcp.CallToolResult, error) {
+	if name != "markdown" {
+		return nil, errors.New("unknown tool: " + name)
+	}
+	content, _ := args["content"].(string)
+	return mcp.NewToolResultText("<p>" + content + "</p>"), nil
+}
+
+func TestSubscriber_Process(t *testing.T) {
+	t.Parallel()
+	subscriber := NewSubscriber(
+		nil,
+		stubInvoker{},
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+
+	tests := []struct {
+		name      string
+		data      string
+		wantID    string
+		wantError string
+		wantText  string
+	}{
+		{
+			name:     "successful run",
+			data:     `{"id": "job-1", "tool": "markdown", "arguments": {"content": "hi"}}`,
+			wantID:   "job-1",
+			wantText: "<p>hi</p>",
+		},
+		{
+			name:      "malformed payload",
+			data:      `{"tool":`,
+			wantError: "invalid job request",
+		},
+		{
+			name:      "missing tool",
+			data:      `{"id": "job-2"}`,
+			wantID:    "job-2",
+			wantError: "validation error",
+		},
+		{
+			name:      "tool failure",
+			data:      `{"id": "job-3", "tool": "nope"}`,
+			wantID:    "job-3",
+			wantError: "unknown tool",
+		},
+	}
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := subscriber.process(context.Background(), []byte(testCase.data))
+			assert.Equal(t, testCase.wantID, result.ID)
+			if testCase.wantError != "" {
+				assert.Contains(t, result.Error, testCase.wantError)
+				assert.Nil(t, result.Result)
+				return
+			}
+			require.Empty(t, result.Error)
+			require.NotNil(t, result.Result)
+			text, ok := mcp.AsTextContent(result.Result.Content[0])
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantText, text.Text)
+		})
+	}
+}
+
+// slowInvoker takes a while for every call and counts the calls that
+// finished.
+type slowInvoker struct {
+	finished atomic.Int32
+}
+
+func (si *slowInvoker) CallTool(
+	_ context.Context,
+	name string,
+	_ map[string]any,
+) (*mcp.CallToolResult, error) {
+	time.Sleep(20 * time.Millisecond)
+	si.finished.Add(1)
+	return mcp.NewToolResultText("ran " + name), nil
+}
+
+// fakeServer speaks enough of the NATS protocol for one client: it answers
+// pings, remembers the subscription and records the subjects published to.
+type fakeServer struct {
+	listener  net.Listener
+	mu        sync.Mutex
+	conn      net.Conn
+	sid       string
+	published []string
+	ready     chan struct{}
+}
+
+// newFakeServer starts a fakeServer on a loopback port.
+func newFakeServer(t *testing.T) *fakeServer {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	server := &fakeServer{listener: listener, ready: make(chan struct{})}
+	t.Cleanup(func() { listener.Close() })
+	go server.serve()
+	return server
+}
+
+// URL returns the address clients connect to.
+func (f *fakeServer) URL() string {
+	return "nats://" + f.listener.Addr().String()
+}
+
+// serve handles the protocol lines of the first client.
+func (f *fakeServer) serve() {
+	conn, err := f.listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	f.mu.Lock()
+	f.conn = conn
+	f.mu.Unlock()
+	f.write(`INFO {"server_id":"fake","version":"2.10.0","proto":1,"max_payload":1048576}` + "\r\n")
+	reader := bufio.NewReader(conn)
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+		switch strings.ToUpper(fields[0]) {
+		case "PING":
+			f.write("PONG\r\n")
+		case "SUB":
+			f.mu.Lock()
+			f.sid = fields[len(fields)-1]
+			f.mu.Unlock()
+			close(f.ready)
+		case "PUB":
+			size, _ := strconv.Atoi(fields[len(fields)-1])
+			if _, err := io.CopyN(io.Discard, reader, int64(size)+2); err != nil {
+				return
+			}
+			f.mu.Lock()
+			f.published = append(f.published, fields[1])
+			f.mu.Unlock()
+		}
+	}
+}
+
+// write sends a protocol line to the client.
+func (f *fakeServer) write(line string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, _ = io.WriteString(f.conn, line)
+}
+
+// send delivers a message to the subscription.
+func (f *fakeServer) send(subject, payload string) {
+	<-f.ready
+	f.mu.Lock()
+	sid := f.sid
+	f.mu.Unlock()
+	f.write(fmt.Sprintf("MSG %s %s %d\r\n%s\r\n", subject, sid, len(payload), payload))
+}
+
+// Published returns the subjects published to so far.
+func (f *fakeServer) Published() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.published...)
+}
+
+func TestSubscriber_StopWaitsForJobs(t *testing.T) {
+	t.Parallel()
+	server := newFakeServer(t)
+	conn, err := nats.Connect(server.URL())
+	require.NoError(t, err)
+	defer conn.Close()
+	invoker := &slowInvoker{}
+	subscriber := NewSubscriber(
+		conn,
+		invoker,
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	require.NoError(t, subscriber.Start())
+
+	const jobs = 5
+	for i := range jobs {
+		server.send("dcr.tools.run", fmt.Sprintf(`{"id": "job-%d", "tool": "markdown"}`, i))
+	}
+	require.NoError(t, subscriber.Stop())
+	assert.Equal(t, int32(jobs), invoker.finished.Load(), "Stop returns after every delivered job finished")
+
+	require.NoError(t, conn.Flush())
+	assert.Equal(t, slices.Repeat([]string{"dcr.tools.results"}, jobs), server.Published())
+}
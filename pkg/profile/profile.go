@@ -0,0 +1,99 @@
+// Package profile holds named deployment presets. A profile is a set of
+// command-line flag values for logging, the artifact directory, rate limits
+// and tool selection, so development, staging and production deployments
+// differ by one --profile flag instead of a drifting list of flags.
+package profile
+
+import (
+	"flag"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// Profile names.
+const (
+	Dev     = "dev"
+	Staging = "staging"
+	Prod    = "prod"
+)
+
+// Profile is a named set of flag values.
+type Profile struct {
+	Name        string
+	Description string
+	// Flags maps flag names to the values the profile gives them.
+	Flags map[string]string
+}
+
+// Presets are the built-in profiles. Development and staging share API
+// keys with production, so they run below its rate limits and leave the
+// lab's Zotero library alone.
+var Presets = map[string]Profile{
+	Dev: {
+		Name:        Dev,
+		Description: "debug logging, artifacts in the temporary directory, low rate limits, no zotero or publish",
+		Flags: map[string]string{
+			"log-level":     "debug",
+			"log-format":    "text",
+			"artifact-dir":  filepath.Join(os.TempDir(), "dcr-mcp", Dev),
+			"rate-limits":   "pubmed=1,europepmc=2,crossref=1,openai=1,github=1/5",
+			"disable-tools": "zotero,publish",
+		},
+	},
+	Staging: {
+		Name:        Staging,
+		Description: "JSON logging at info, artifacts in /var/cache/dcr-mcp/staging, reduced rate limits, no zotero",
+		Flags: map[string]string{
+			"log-level":     "info",
+			"log-format":    "json",
+			"artifact-dir":  "/var/cache/dcr-mcp/staging",
+			"rate-limits":   "pubmed=2,europepmc=5,crossref=2,openai=2,github=2/5",
+			"disable-tools": "zotero",
+		},
+	},
+	Prod: {
+		Name:        Prod,
+		Description: "JSON logging at warn, artifacts in /var/cache/dcr-mcp/prod, default rate limits, all tools",
+		Flags: map[string]string{
+			"log-level":    "warn",
+			"log-format":   "json",
+			"artifact-dir": "/var/cache/dcr-mcp/prod",
+		},
+	},
+}
+
+// Names returns the names of the built-in profiles in sorted order.
+func Names() []string {
+	return slices.Sorted(maps.Keys(Presets))
+}
+
+// Lookup returns the named profile.
+func Lookup(name string) (Profile, error) {
+	found, ok := Presets[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q, expected one of %s", name, strings.Join(Names(), ", "))
+	}
+	return found, nil
+}
+
+// Apply sets the profile's values on flagSet, which must already be
+// parsed. Flags given on the command line keep their values.
+func (p Profile) Apply(flagSet *flag.FlagSet) error {
+	explicit := make(map[string]bool)
+	flagSet.Visit(func(set *flag.Flag) {
+		explicit[set.Name] = true
+	})
+	for _, name := range slices.Sorted(maps.Keys(p.Flags)) {
+		if explicit[name] {
+			continue
+		}
+		if err := flagSet.Set(name, p.Flags[name]); err != nil {
+			return fmt.Errorf("profile %s: invalid value for --%s: %w", p.Name, name, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package profile
+
+import (
+	"flag"
+	"io"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newFlagSet returns a flag set with the flags the presets set.
+func newFlagSet() *flag.FlagSet {
+	flagSet := flag.NewFlagSet("test", flag.ContinueOnError)
+	flagSet.SetOutput(io.Discard)
+	flagSet.String("log-level", "info", "")
+	flagSet.String("log-format", "text", "")
+	flagSet.String("artifact-dir", "", "")
+	flagSet.String("rate-limits", "", "")
+	flagSet.String("disable-tools", "", "")
+	return flagSet
+}
+
+func TestLookup(t *testing.T) {
+	t.Parallel()
+	found, err := Lookup(" Staging ")
+	require.NoError(t, err)
+	assert.Equal(t, Staging, found.Name)
+
+	_, err = Lookup("qa")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "dev, prod, staging")
+}
+
+func TestProfile_Apply(t *testing.T) {
+	t.Parallel()
+	flagSet := newFlagSet()
+	require.NoError(t, flagSet.Parse([]string{"--log-level=warn", "--disable-tools="}))
+	found, err := Lookup(Dev)
+	require.NoError(t, err)
+	require.NoError(t, found.Apply(flagSet))
+
+	assert.Equal(t, "warn", flagSet.Lookup("log-level").Value.String(), "explicit flags win")
+	assert.Empty(t, flagSet.Lookup("disable-tools").Value.String(), "explicit empty flags win")
+	assert.Equal(t, "text", flagSet.Lookup("log-format").Value.String())
+	assert.Equal(t, found.Flags["rate-limits"], flagSet.Lookup("rate-limits").Value.String())
+}
+
+func TestProfile_ApplyUnknownFlag(t *testing.T) {
+	t.Parallel()
+	flagSet := newFlagSet()
+	require.NoError(t, flagSet.Parse(nil))
+	err := Profile{Name: "broken", Flags: map[string]string{"cache-dir": "/tmp"}}.Apply(flagSet)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "--cache-dir")
+}
+
+func TestPresets_RateLimits(t *testing.T) {
+	t.Parallel()
+	for _, name := range Names() {
+		spec, ok := Presets[name].Flags["rate-limits"]
+		if !ok {
+			continue
+		}
+		_, err := ratelimit.ParseLimits(spec)
+		assert.NoError(t, err, name)
+	}
+}
@@ -0,0 +1,177 @@
+// Package progress sends MCP progress notifications for long-running tool
+// calls, so clients see what a call is doing instead of waiting silently.
+package progress
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultInterval is the minimum time between throttled notifications.
+const DefaultInterval = 500 * time.Millisecond
+
+// sender delivers a notification to the client of the call.
+type sender func(ctx context.Context, params map[string]any)
+
+// Reporter sends notifications/progress for one tool call. It does nothing
+// when the client sent no progress token or the call did not arrive over an
+// MCP session, e.g. through the HTTP gateway. A nil Reporter is valid and
+// reports nothing.
+type Reporter struct {
+	state *state
+	// sub is set on reporters created by Sub, which map their progress
+	// onto the range starting at base and span wide.
+	sub        bool
+	base, span float64
+}
+
+// state is shared by a reporter and its sub-reporters.
+type state struct {
+	mu       sync.Mutex
+	ctx      context.Context
+	token    mcp.ProgressToken
+	send     sender
+	interval time.Duration
+	sent     bool
+	last     float64
+	total    float64
+	lastSent time.Time
+}
+
+// NewReporter creates a reporter for the request.
+func NewReporter(ctx context.Context, request mcp.CallToolRequest) *Reporter {
+	callState := &state{ctx: ctx, interval: DefaultInterval}
+	if request.Params.Meta != nil {
+		callState.token = request.Params.Meta.ProgressToken
+	}
+	if mcpServer := server.ServerFromContext(ctx); mcpServer != nil {
+		callState.send = func(ctx context.Context, params map[string]any) {
+			// Delivery is best effort; the call proceeds either way.
+			_ = mcpServer.SendNotificationToClient(ctx, "notifications/progress", params)
+		}
+	}
+	return &Reporter{state: callState}
+}
+
+// NewFuncReporter creates a reporter that passes the notification
+// parameters to fn instead of an MCP client, e.g. to relay progress over
+// another transport.
+func NewFuncReporter(fn func(params map[string]any)) *Reporter {
+	return &Reporter{state: &state{
+		ctx:      context.Background(),
+		token:    "progress",
+		interval: DefaultInterval,
+		send: func(_ context.Context, params map[string]any) {
+			fn(params)
+		},
+	}}
+}
+
+// Sub returns a reporter for one stage of the call, covering the range
+// from start to end of r's progress. Code running the stage reports its
+// own progress and total, which the sub-reporter scales into that range.
+// On a sub-reporter, start and end are fractions of its range.
+func (r *Reporter) Sub(start, end float64) *Reporter {
+	if r == nil {
+		return nil
+	}
+	if !r.sub {
+		return &Reporter{state: r.state, sub: true, base: start, span: end - start}
+	}
+	return &Reporter{
+		state: r.state,
+		sub:   true,
+		base:  r.base + start*r.span,
+		span:  (end - start) * r.span,
+	}
+}
+
+// Report sends a notification. A total of zero means the total is unknown.
+// The protocol requires progress to increase with every notification, so
+// values not above the last one sent are dropped.
+func (r *Reporter) Report(progress, total float64, message string) {
+	r.report(progress, total, message, false)
+}
+
+// Throttled is like Report but also drops the notification when the last
+// one went out less than the reporter's interval ago. Use it for frequent
+// updates such as streamed output.
+func (r *Reporter) Throttled(progress, total float64, message string) {
+	r.report(progress, total, message, true)
+}
+
+// report sends a notification unless it is out of order or throttled.
+func (r *Reporter) report(progress, total float64, message string, throttle bool) {
+	if r == nil || r.state.token == nil || r.state.send == nil {
+		return
+	}
+	if r.sub {
+		if total <= 0 {
+			return
+		}
+		progress = r.base + min(max(progress/total, 0), 1)*r.span
+		total = 0
+	}
+	callState := r.state
+	callState.mu.Lock()
+	now := time.Now()
+	if (callState.sent && progress <= callState.last) ||
+		(throttle && now.Sub(callState.lastSent) < callState.interval) {
+		callState.mu.Unlock()
+		return
+	}
+	if total > 0 {
+		callState.total = total
+	}
+	total = callState.total
+	callState.sent, callState.last, callState.lastSent = true, progress, now
+	callState.mu.Unlock()
+
+	params := map[string]any{"progressToken": callState.token, "progress": progress}
+	if total > 0 {
+		params["total"] = total
+	}
+	if message != "" {
+		params["message"] = message
+	}
+	callState.send(callState.ctx, params)
+}
+
+// contextKey is the context key of the call's reporter.
+type contextKey struct{}
+
+// NewContext returns a context carrying the reporter.
+func NewContext(ctx context.Context, reporter *Reporter) context.Context {
+	return context.WithValue(ctx, contextKey{}, reporter)
+}
+
+// FromContext returns the reporter of the tool call, or nil when there is
+// none; the nil reporter discards notifications.
+func FromContext(ctx context.Context) *Reporter {
+	reporter, _ := ctx.Value(contextKey{}).(*Reporter)
+	return reporter
+}
+
+// Middleware gives every tool call a reporter, available to the handler
+// and the code it calls through FromContext. Throttled notifications of a
+// call are sent at most once per interval; zero or less means
+// DefaultInterval.
+func Middleware(interval time.Duration) server.ToolHandlerMiddleware {
+	if interval <= 0 {
+		interval = DefaultInterval
+	}
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(
+			ctx context.Context,
+			request mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			reporter := NewReporter(ctx, request)
+			reporter.state.interval = interval
+			return next(NewContext(ctx, reporter), request)
+		}
+	}
+}
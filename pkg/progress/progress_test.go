@@ -0,0 +1,106 @@
+package progress
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingReporter returns a reporter that records the notifications it
+// sends.
+func recordingReporter(token mcp.ProgressToken) (*Reporter, *[]map[string]any) {
+	var sent []map[string]any
+	reporter := &Reporter{state: &state{
+		ctx:      context.Background(),
+		token:    token,
+		interval: time.Hour,
+		send: func(_ context.Context, params map[string]any) {
+			sent = append(sent, params)
+		},
+	}}
+	return reporter, &sent
+}
+
+func TestReporter_Report(t *testing.T) {
+	t.Parallel()
+	reporter, sent := recordingReporter("token-1")
+	reporter.Report(0, 3, "cloning")
+	reporter.Report(1, 3, "listing commits")
+	reporter.Report(1, 3, "repeated")
+	reporter.Report(0.5, 0, "backwards")
+	reporter.Report(2, 0, "")
+
+	require.Len(t, *sent, 3)
+	assert.Equal(t, float64(3), (*sent)[2]["total"], "the last total is kept")
+	assert.Equal(t, map[string]any{
+		"progressToken": "token-1",
+		"progress":      float64(0),
+		"total":         float64(3),
+		"message":       "cloning",
+	}, (*sent)[0])
+	assert.Equal(t, "listing commits", (*sent)[1]["message"])
+}
+
+func TestReporter_Sub(t *testing.T) {
+	t.Parallel()
+	reporter, sent := recordingReporter("token-1")
+	reporter.Report(1, 5, "cloning")
+	clone := reporter.Sub(1, 3)
+	clone.Report(50, 100, "receiving objects")
+	clone.Report(10, 0, "unknown total is dropped")
+	deltas := clone.Sub(0.5, 1)
+	deltas.Report(1, 2, "resolving deltas")
+	reporter.Report(3, 5, "listing commits")
+
+	require.Len(t, *sent, 4)
+	assert.Equal(t, float64(2), (*sent)[1]["progress"])
+	assert.Equal(t, float64(5), (*sent)[1]["total"])
+	assert.Equal(t, float64(2.5), (*sent)[2]["progress"])
+	assert.Equal(t, float64(3), (*sent)[3]["progress"])
+
+	var nilReporter *Reporter
+	assert.Nil(t, nilReporter.Sub(0, 1))
+}
+
+func TestReporter_Throttled(t *testing.T) {
+	t.Parallel()
+	reporter, sent := recordingReporter(7)
+	reporter.Throttled(1, 0, "first")
+	reporter.Throttled(2, 0, "too soon")
+	reporter.Report(3, 0, "unthrottled")
+	assert.Len(t, *sent, 2)
+}
+
+func TestReporter_Disabled(t *testing.T) {
+	t.Parallel()
+	reporter, sent := recordingReporter(nil)
+	reporter.Report(1, 1, "no token")
+	assert.Empty(t, *sent)
+
+	var nilReporter *Reporter
+	assert.NotPanics(t, func() { nilReporter.Report(1, 1, "nil") })
+
+	reporter = NewReporter(context.Background(), mcp.CallToolRequest{})
+	assert.NotPanics(t, func() { reporter.Report(1, 1, "no session") })
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	var reporter *Reporter
+	handler := Middleware(time.Second)(func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		reporter = FromContext(ctx)
+		return mcp.NewToolResultText("done"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Meta = &mcp.Meta{ProgressToken: "abc"}
+	_, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.NotNil(t, reporter)
+	assert.Equal(t, mcp.ProgressToken("abc"), reporter.state.token)
+	assert.Equal(t, time.Second, reporter.state.interval)
+	assert.Nil(t, FromContext(context.Background()))
+}
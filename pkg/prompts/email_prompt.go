@@ -3,7 +3,7 @@ package prompts
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -13,11 +13,11 @@ type EmailPrompt struct {
 	Name        string
 	Description string
 	Prompt      mcp.Prompt
-	Logger      *log.Logger
+	Logger      *slog.Logger
 }
 
 // NewEmailPrompt creates a new EmailPrompt instance.
-func NewEmailPrompt(logger *log.Logger) (*EmailPrompt, error) {
+func NewEmailPrompt(logger *slog.Logger) (*EmailPrompt, error) {
 	// Define the dynamic email prompt template
 	prompt := mcp.NewPrompt(
 		"draft_casual_email", // Unique name for the prompt
@@ -0,0 +1,121 @@
+package prompts
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"text/template"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// TemplateArgument describes an argument of a template prompt.
+type TemplateArgument struct {
+	Name        string `json:"name"        validate:"required"`
+	Description string `json:"description"`
+	Required    bool   `json:"required"`
+}
+
+// TemplateSpec defines a prompt whose text is a Go text/template. The
+// template is executed with the prompt arguments, e.g. {{.from}}.
+type TemplateSpec struct {
+	Name        string             `json:"name"        validate:"required"`
+	Description string             `json:"description"`
+	Arguments   []TemplateArgument `json:"arguments"   validate:"dive"`
+	// Role is the role of the generated message, defaults to assistant.
+	Role     string `json:"role"     validate:"omitempty,oneof=user assistant"`
+	Template string `json:"template" validate:"required"`
+}
+
+// TemplatePrompt is a prompt defined in configuration rather than code.
+type TemplatePrompt struct {
+	Name        string
+	Description string
+	Prompt      mcp.Prompt
+	Logger      *slog.Logger
+	spec        TemplateSpec
+	template    *template.Template
+}
+
+// NewTemplatePrompt creates a prompt from spec. The template is parsed up
+// front so syntax errors surface when the configuration is loaded.
+func NewTemplatePrompt(spec TemplateSpec, logger *slog.Logger) (*TemplatePrompt, error) {
+	if err := validate.Struct(spec); err != nil {
+		return nil, fmt.Errorf("invalid prompt %s: %w", spec.Name, err)
+	}
+	parsed, err := template.New(spec.Name).Option("missingkey=zero").Parse(spec.Template)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template of prompt %s: %w", spec.Name, err)
+	}
+	opts := []mcp.PromptOption{mcp.WithPromptDescription(spec.Description)}
+	for _, argument := range spec.Arguments {
+		argumentOpts := []mcp.ArgumentOption{mcp.ArgumentDescription(argument.Description)}
+		if argument.Required {
+			argumentOpts = append(argumentOpts, mcp.RequiredArgument())
+		}
+		opts = append(opts, mcp.WithArgument(argument.Name, argumentOpts...))
+	}
+	if spec.Role == "" {
+		spec.Role = string(mcp.RoleAssistant)
+	}
+	return &TemplatePrompt{
+		Name:        spec.Name,
+		Description: spec.Description,
+		Prompt:      mcp.NewPrompt(spec.Name, opts...),
+		Logger:      logger,
+		spec:        spec,
+		template:    parsed,
+	}, nil
+}
+
+// GetName returns the name of the prompt.
+func (tp *TemplatePrompt) GetName() string {
+	return tp.Name
+}
+
+// GetDescription returns the description of the prompt.
+func (tp *TemplatePrompt) GetDescription() string {
+	return tp.Description
+}
+
+// GetPrompt returns the MCP Prompt definition.
+func (tp *TemplatePrompt) GetPrompt() mcp.Prompt {
+	return tp.Prompt
+}
+
+// Handler renders the template with the request arguments. Declared
+// arguments that were not given render as empty strings.
+func (tp *TemplatePrompt) Handler(
+	_ context.Context,
+	request mcp.GetPromptRequest,
+) (*mcp.GetPromptResult, error) {
+	data := maps.Clone(request.Params.Arguments)
+	if data == nil {
+		data = make(map[string]string, len(tp.spec.Arguments))
+	}
+	for _, argument := range tp.spec.Arguments {
+		if _, ok := data[argument.Name]; ok {
+			continue
+		}
+		if argument.Required {
+			return nil, fmt.Errorf("required argument '%s' is missing", argument.Name)
+		}
+		data[argument.Name] = ""
+	}
+	var content strings.Builder
+	if err := tp.template.Execute(&content, data); err != nil {
+		return nil, fmt.Errorf("failed to render prompt %s: %w", tp.Name, err)
+	}
+	return mcp.NewGetPromptResult(
+		tp.Description,
+		[]mcp.PromptMessage{
+			mcp.NewPromptMessage(mcp.Role(tp.spec.Role), mcp.NewTextContent(content.String())),
+		},
+	), nil
+}
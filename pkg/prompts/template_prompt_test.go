@@ -0,0 +1,45 @@
+package prompts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTemplatePrompt(t *testing.T) {
+	t.Parallel()
+	prompt, err := NewTemplatePrompt(TemplateSpec{
+		Name:        "review",
+		Description: "Asks for a review",
+		Arguments: []TemplateArgument{
+			{Name: "paper", Required: true},
+			{Name: "focus"},
+		},
+		Role:     "user",
+		Template: "Review {{.paper}}{{if .focus}}, focusing on {{.focus}}{{end}}.",
+	}, logging.Discard())
+	require.NoError(t, err)
+	require.Len(t, prompt.GetPrompt().Arguments, 2)
+	assert.True(t, prompt.GetPrompt().Arguments[0].Required)
+
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = map[string]string{"paper": "PMID:123"}
+	result, err := prompt.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, result.Messages, 1)
+	assert.Equal(t, mcp.RoleUser, result.Messages[0].Role)
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	require.True(t, ok)
+	assert.Equal(t, "Review PMID:123.", text.Text)
+
+	request.Params.Arguments = map[string]string{}
+	_, err = prompt.Handler(context.Background(), request)
+	require.Error(t, err, "required arguments must be given")
+
+	_, err = NewTemplatePrompt(TemplateSpec{Name: "bad", Template: "{{if}}"}, logging.Discard())
+	require.Error(t, err)
+}
@@ -0,0 +1,109 @@
+// Package provenance records where a tool result came from, so downstream
+// users can attribute and reproduce it. The record travels in the result's
+// _meta field and does not change the content the model reads.
+package provenance
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// MetaKey is the _meta field holding the provenance record.
+const MetaKey = "dictybase.org/provenance"
+
+// Cache outcomes.
+const (
+	// CacheHit means the result was served from a cache.
+	CacheHit = "hit"
+	// CacheMiss means the result was fetched from its providers.
+	CacheMiss = "miss"
+)
+
+// Provenance describes the sources of a tool result.
+type Provenance struct {
+	// Providers are the upstream services the result was built from, named
+	// like the outbound request metrics, e.g. pubmed or openai.
+	Providers []string `json:"providers"`
+	// FetchedAt is when the upstream data was retrieved.
+	FetchedAt time.Time `json:"fetchedAt"`
+	// Cache is CacheHit or CacheMiss.
+	Cache string `json:"cache"`
+	// Model is the language model that generated the result, if any.
+	Model string `json:"model,omitempty"`
+}
+
+// Option configures a Provenance.
+type Option func(*Provenance)
+
+// WithModel records the language model that generated the result.
+func WithModel(model string) Option {
+	return func(p *Provenance) {
+		p.Model = model
+	}
+}
+
+// WithCacheHit marks the result as served from a cache.
+func WithCacheHit() Option {
+	return func(p *Provenance) {
+		p.Cache = CacheHit
+	}
+}
+
+// WithFetchedAt overrides the retrieval time, which defaults to now.
+func WithFetchedAt(fetchedAt time.Time) Option {
+	return func(p *Provenance) {
+		p.FetchedAt = fetchedAt.UTC()
+	}
+}
+
+// New creates a provenance record for data fetched now from providers.
+func New(providers []string, opts ...Option) Provenance {
+	record := Provenance{
+		Providers: providers,
+		FetchedAt: time.Now().UTC(),
+		Cache:     CacheMiss,
+	}
+	for _, opt := range opts {
+		opt(&record)
+	}
+	return record
+}
+
+// Attach stores the record in the result's _meta field and returns the
+// result.
+func Attach(result *mcp.CallToolResult, record Provenance) *mcp.CallToolResult {
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[MetaKey] = record
+	return result
+}
+
+// FromResult returns the provenance record of a result, whether it was
+// attached in process or decoded from JSON.
+func FromResult(result *mcp.CallToolResult) (Provenance, bool) {
+	if result == nil || result.Meta == nil {
+		return Provenance{}, false
+	}
+	value, ok := result.Meta.AdditionalFields[MetaKey]
+	if !ok {
+		return Provenance{}, false
+	}
+	if record, ok := value.(Provenance); ok {
+		return record, true
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return Provenance{}, false
+	}
+	var record Provenance
+	if err := json.Unmarshal(data, &record); err != nil {
+		return Provenance{}, false
+	}
+	return record, true
+}
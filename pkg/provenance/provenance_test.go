@@ -0,0 +1,61 @@
+package provenance
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNew(t *testing.T) {
+	t.Parallel()
+	record := New([]string{"pubmed"})
+	assert.Equal(t, []string{"pubmed"}, record.Providers)
+	assert.Equal(t, CacheMiss, record.Cache)
+	assert.Empty(t, record.Model)
+	assert.WithinDuration(t, time.Now(), record.FetchedAt, time.Minute)
+
+	fetchedAt := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	record = New(
+		[]string{"openai"},
+		WithModel("gpt-4o"),
+		WithCacheHit(),
+		WithFetchedAt(fetchedAt),
+	)
+	assert.Equal(t, "gpt-4o", record.Model)
+	assert.Equal(t, CacheHit, record.Cache)
+	assert.Equal(t, fetchedAt, record.FetchedAt)
+}
+
+func TestAttach_RoundTrip(t *testing.T) {
+	t.Parallel()
+	record := New(
+		[]string{"europepmc"},
+		WithFetchedAt(time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)),
+	)
+	result := Attach(mcp.NewToolResultText("article"), record)
+
+	attached, ok := FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, record, attached)
+
+	data, err := json.Marshal(result)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"_meta":{"dictybase.org/provenance":`)
+	var decoded mcp.CallToolResult
+	require.NoError(t, json.Unmarshal(data, &decoded))
+	roundTripped, ok := FromResult(&decoded)
+	require.True(t, ok)
+	assert.Equal(t, record, roundTripped)
+}
+
+func TestFromResult_Missing(t *testing.T) {
+	t.Parallel()
+	_, ok := FromResult(mcp.NewToolResultText("no provenance"))
+	assert.False(t, ok)
+	_, ok = FromResult(nil)
+	assert.False(t, ok)
+}
@@ -0,0 +1,327 @@
+// Package ratelimit throttles outbound API calls with a token bucket per
+// host, so tools stay within the request caps of NCBI, Europe PMC and other
+// providers, and counts the requests sent to each provider to show they
+// did.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Provider names accepted in place of host names. They match the service
+// labels of the outbound request metrics.
+const (
+	ProviderPubMed          = "pubmed"
+	ProviderEuropePMC       = "europepmc"
+	ProviderOpenAI          = "openai"
+	ProviderORCID           = "orcid"
+	ProviderZotero          = "zotero"
+	ProviderGitHub          = "github"
+	ProviderOSV             = "osv"
+	ProviderDepsDev         = "depsdev"
+	ProviderCrossref        = "crossref"
+	ProviderDockerHub       = "dockerhub"
+	ProviderBioRxiv         = "biorxiv"
+	ProviderArXiv           = "arxiv"
+	ProviderOpenAlex        = "openalex"
+	ProviderSemanticScholar = "semanticscholar"
+	ProviderDictyBase       = "dictybase"
+)
+
+// providerHosts maps provider names to the hosts they are served from.
+var providerHosts = map[string][]string{
+	ProviderPubMed:          {"eutils.ncbi.nlm.nih.gov"},
+	ProviderEuropePMC:       {"www.ebi.ac.uk"},
+	ProviderOpenAI:          {"openrouter.ai", "api.openai.com"},
+	ProviderORCID:           {"pub.orcid.org"},
+	ProviderZotero:          {"api.zotero.org"},
+	ProviderGitHub:          {"api.github.com"},
+	ProviderOSV:             {"api.osv.dev"},
+	ProviderDepsDev:         {"api.deps.dev"},
+	ProviderCrossref:        {"api.crossref.org"},
+	ProviderDockerHub:       {"registry-1.docker.io", "auth.docker.io"},
+	ProviderBioRxiv:         {"api.biorxiv.org"},
+	ProviderArXiv:           {"export.arxiv.org"},
+	ProviderOpenAlex:        {"api.openalex.org"},
+	ProviderSemanticScholar: {"api.semanticscholar.org"},
+	ProviderDictyBase:       {"graphql.dictybase.org"},
+}
+
+// Limit is the sustained request rate and burst size allowed for a host.
+type Limit struct {
+	// Rate is the number of requests per second.
+	Rate float64 `validate:"gt=0"`
+	// Burst is the number of requests allowed back to back.
+	Burst int `validate:"gte=1"`
+}
+
+// String renders the limit in the rate/burst form accepted by ParseLimits.
+func (l Limit) String() string {
+	return strconv.FormatFloat(l.Rate, 'f', -1, 64) + "/" + strconv.Itoa(l.Burst)
+}
+
+// DefaultLimits follow the published caps of each provider: NCBI allows
+// three requests per second without an API key, arXiv one every three
+// seconds and Semantic Scholar one per second with an API key.
+var DefaultLimits = map[string]Limit{
+	ProviderPubMed:          {Rate: 3, Burst: 3},
+	ProviderEuropePMC:       {Rate: 10, Burst: 10},
+	ProviderOpenAI:          {Rate: 5, Burst: 5},
+	ProviderORCID:           {Rate: 20, Burst: 20},
+	ProviderZotero:          {Rate: 5, Burst: 5},
+	ProviderGitHub:          {Rate: 5, Burst: 10},
+	ProviderOSV:             {Rate: 10, Burst: 10},
+	ProviderDepsDev:         {Rate: 20, Burst: 20},
+	ProviderCrossref:        {Rate: 5, Burst: 5},
+	ProviderDockerHub:       {Rate: 5, Burst: 5},
+	ProviderBioRxiv:         {Rate: 5, Burst: 5},
+	ProviderArXiv:           {Rate: 1.0 / 3, Burst: 1},
+	ProviderOpenAlex:        {Rate: 10, Burst: 10},
+	ProviderSemanticScholar: {Rate: 1, Burst: 1},
+	ProviderDictyBase:       {Rate: 5, Burst: 5},
+}
+
+// Registry holds the token buckets of all limited hosts. Hosts without a
+// limit are not throttled.
+type Registry struct {
+	mu      sync.Mutex
+	limits  map[string]Limit
+	buckets map[string]*bucket
+	now     func() time.Time
+	usage   *Usage
+}
+
+// bucket is a token bucket refilled continuously at the limit's rate.
+type bucket struct {
+	limit  Limit
+	tokens float64
+	last   time.Time
+}
+
+// NewRegistry creates a registry from limits keyed by provider name or
+// host name. The requests it lets through to the hosts of providers are
+// counted for UsageReport.
+func NewRegistry(limits map[string]Limit) (*Registry, error) {
+	registry := &Registry{
+		limits:  make(map[string]Limit),
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+		usage:   sharedUsage,
+	}
+	for name, limit := range limits {
+		if err := validate.Struct(limit); err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", name, err)
+		}
+		for _, host := range hostsOf(name) {
+			registry.limits[host] = limit
+		}
+	}
+	return registry, nil
+}
+
+// Wait blocks until a request to host is allowed or ctx is done. host may
+// also be a provider name, which waits on the provider's first host.
+func (r *Registry) Wait(ctx context.Context, host string) error {
+	host = hostsOf(host)[0]
+	delay, ok := r.reserve(host)
+	if !ok || delay == 0 {
+		r.record(host)
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		r.record(host)
+		return nil
+	case <-ctx.Done():
+		r.release(host)
+		return ctx.Err()
+	}
+}
+
+// record counts a request let through to host if it serves a provider.
+func (r *Registry) record(host string) {
+	if provider, ok := providerOf(host); ok && r.usage != nil {
+		r.usage.Record(provider)
+	}
+}
+
+// Limit returns the limit of a provider or host.
+func (r *Registry) Limit(name string) (Limit, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit, ok := r.limits[hostsOf(name)[0]]
+	return limit, ok
+}
+
+// reserve takes a token for host and returns how long to wait before using
+// it. It reports false when host is not limited.
+func (r *Registry) reserve(host string) (time.Duration, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	limit, ok := r.limits[host]
+	if !ok {
+		return 0, false
+	}
+	now := r.now()
+	hostBucket, ok := r.buckets[host]
+	if !ok {
+		hostBucket = &bucket{limit: limit, tokens: float64(limit.Burst), last: now}
+		r.buckets[host] = hostBucket
+	}
+	elapsed := now.Sub(hostBucket.last).Seconds()
+	hostBucket.tokens = min(float64(limit.Burst), hostBucket.tokens+elapsed*limit.Rate)
+	hostBucket.last = now
+	hostBucket.tokens--
+	if hostBucket.tokens >= 0 {
+		return 0, true
+	}
+	return time.Duration(-hostBucket.tokens / limit.Rate * float64(time.Second)), true
+}
+
+// release returns a token taken by an abandoned wait.
+func (r *Registry) release(host string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if hostBucket, ok := r.buckets[host]; ok {
+		hostBucket.tokens = min(float64(hostBucket.limit.Burst), hostBucket.tokens+1)
+	}
+}
+
+// shared is the process-wide registry used by Wait and Transport.
+var shared atomic.Pointer[Registry]
+
+//nolint:gochecknoinits // the shared registry starts with the default limits
+func init() {
+	registry, err := NewRegistry(DefaultLimits)
+	if err != nil {
+		panic(err)
+	}
+	shared.Store(registry)
+}
+
+// Configure replaces the limits of the shared registry.
+func Configure(limits map[string]Limit) error {
+	registry, err := NewRegistry(limits)
+	if err != nil {
+		return err
+	}
+	shared.Store(registry)
+	return nil
+}
+
+// Wait blocks until the shared registry allows a request to host.
+func Wait(ctx context.Context, host string) error {
+	return shared.Load().Wait(ctx, host)
+}
+
+// Transport is an http.RoundTripper that waits for the request's host to
+// be allowed before sending it.
+type Transport struct {
+	// Base sends the requests, http.DefaultTransport when nil.
+	Base http.RoundTripper
+	// Registry holds the limits, the shared registry when nil.
+	Registry *Registry
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	registry := t.Registry
+	if registry == nil {
+		registry = shared.Load()
+	}
+	if err := registry.Wait(req.Context(), req.URL.Hostname()); err != nil {
+		return nil, fmt.Errorf("rate limit wait for %s: %w", req.URL.Hostname(), err)
+	}
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// NewHTTPClient returns an HTTP client throttled by the shared registry.
+func NewHTTPClient(timeout time.Duration) *http.Client {
+	return &http.Client{Timeout: timeout, Transport: &Transport{}}
+}
+
+// ParseLimits parses a comma-separated list of name=rate[/burst] pairs,
+// for example "pubmed=10,www.example.org=2/5", and merges them over
+// DefaultLimits. The burst defaults to the rate rounded up.
+func ParseLimits(spec string) (map[string]Limit, error) {
+	limits := make(map[string]Limit, len(DefaultLimits))
+	for name, limit := range DefaultLimits {
+		limits[name] = limit
+	}
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		name = strings.ToLower(strings.TrimSpace(name))
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid rate limit %q, expected name=rate[/burst]", pair)
+		}
+		rateValue, burstValue, hasBurst := strings.Cut(strings.TrimSpace(value), "/")
+		rate, err := strconv.ParseFloat(rateValue, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid rate for %s: %w", name, err)
+		}
+		limit := Limit{Rate: rate, Burst: int(rate + 0.999)}
+		if hasBurst {
+			if limit.Burst, err = strconv.Atoi(burstValue); err != nil {
+				return nil, fmt.Errorf("invalid burst for %s: %w", name, err)
+			}
+		}
+		if err := validate.Struct(limit); err != nil {
+			return nil, fmt.Errorf("invalid rate limit for %s: %w", name, err)
+		}
+		limits[name] = limit
+	}
+	return limits, nil
+}
+
+// Providers returns the provider names accepted in place of host names,
+// in sorted order.
+func Providers() []string {
+	return slices.Sorted(maps.Keys(providerHosts))
+}
+
+// hostsOf resolves a provider name to its hosts; other names are hosts.
+func hostsOf(name string) []string {
+	if hosts, ok := providerHosts[strings.ToLower(name)]; ok {
+		return hosts
+	}
+	return []string{normalizeHost(name)}
+}
+
+// providerOf returns the provider a host serves.
+func providerOf(host string) (string, bool) {
+	for provider, hosts := range providerHosts {
+		if slices.Contains(hosts, host) {
+			return provider, true
+		}
+	}
+	return "", false
+}
+
+// normalizeHost lowercases a host name.
+func normalizeHost(host string) string {
+	return strings.ToLower(strings.TrimSpace(host))
+}
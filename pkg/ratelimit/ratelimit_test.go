@@ -0,0 +1,94 @@
+package ratelimit
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry_Reserve(t *testing.T) {
+	t.Parallel()
+	registry, err := NewRegistry(map[string]Limit{
+		ProviderPubMed: {Rate: 2, Burst: 2},
+	})
+	require.NoError(t, err)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	registry.now = func() time.Time { return now }
+
+	for range 2 {
+		delay, limited := registry.reserve("eutils.ncbi.nlm.nih.gov")
+		assert.True(t, limited)
+		assert.Zero(t, delay, "burst requests are not delayed")
+	}
+	delay, _ := registry.reserve("eutils.ncbi.nlm.nih.gov")
+	assert.Equal(t, 500*time.Millisecond, delay)
+
+	now = now.Add(time.Second)
+	delay, _ = registry.reserve("eutils.ncbi.nlm.nih.gov")
+	assert.Zero(t, delay, "tokens refill over time")
+
+	_, limited := registry.reserve("example.org")
+	assert.False(t, limited, "hosts without a limit are not throttled")
+}
+
+func TestRegistry_WaitHonorsContext(t *testing.T) {
+	t.Parallel()
+	registry, err := NewRegistry(map[string]Limit{"slow.example.org": {Rate: 0.01, Burst: 1}})
+	require.NoError(t, err)
+	require.NoError(t, registry.Wait(context.Background(), "slow.example.org"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = registry.Wait(ctx, "SLOW.example.org")
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestTransport(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(server.Close)
+	serverURL, err := url.Parse(server.URL)
+	require.NoError(t, err)
+	registry, err := NewRegistry(map[string]Limit{serverURL.Hostname(): {Rate: 20, Burst: 1}})
+	require.NoError(t, err)
+	client := &http.Client{Transport: &Transport{Registry: registry}}
+
+	start := time.Now()
+	for range 3 {
+		resp, err := client.Get(server.URL)
+		require.NoError(t, err)
+		require.NoError(t, resp.Body.Close())
+	}
+	assert.GreaterOrEqual(t, time.Since(start), 90*time.Millisecond)
+}
+
+func TestParseLimits(t *testing.T) {
+	t.Parallel()
+	limits, err := ParseLimits("pubmed=10, www.example.org=0.5/2")
+	require.NoError(t, err)
+	assert.Equal(t, Limit{Rate: 10, Burst: 10}, limits[ProviderPubMed])
+	assert.Equal(t, Limit{Rate: 0.5, Burst: 2}, limits["www.example.org"])
+	assert.Equal(t, DefaultLimits[ProviderEuropePMC], limits[ProviderEuropePMC])
+
+	for _, spec := range []string{"pubmed", "pubmed=fast", "pubmed=0", "pubmed=1/0", "=1"} {
+		_, err := ParseLimits(spec)
+		assert.Error(t, err, spec)
+	}
+}
+
+func TestProviders(t *testing.T) {
+	t.Parallel()
+	providers := Providers()
+	assert.Contains(t, providers, ProviderPubMed)
+	assert.True(t, slices.IsSorted(providers))
+	assert.Len(t, providers, len(DefaultLimits))
+}
@@ -0,0 +1,221 @@
+package ratelimit
+
+import (
+	"maps"
+	"math"
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// usageDays is the number of UTC days the daily volumes are kept for.
+	usageDays = 7
+	// minutesPerDay is the size of the ring counting the requests of the
+	// last 24 hours by minute.
+	minutesPerDay = 24 * 60
+	// secondsPerMinute is the size of the ring counting the requests of the
+	// last minute by second.
+	secondsPerMinute = 60
+)
+
+// Policy is the request cap a provider publishes for its users.
+type Policy struct {
+	// Rate is the number of requests per second allowed.
+	Rate float64
+	// Source names the policy the cap is taken from.
+	Source string
+}
+
+// Policies are the published caps the compliance report checks the
+// achieved request rates against. Providers without one are checked
+// against their configured limit.
+var Policies = map[string]Policy{
+	ProviderPubMed: {
+		Rate:   3,
+		Source: "NCBI E-utilities usage guidelines: at most 3 requests per second without an API key",
+	},
+	ProviderArXiv: {
+		Rate:   1.0 / 3,
+		Source: "arXiv API terms of use: at most one request every three seconds",
+	},
+}
+
+// DayVolume is the number of requests sent to a provider on a UTC day.
+type DayVolume struct {
+	Date     string `json:"date"`
+	Requests int    `json:"requests"`
+}
+
+// ProviderUsage reports the requests sent to a provider since the server
+// started and whether they kept to its published or configured cap.
+type ProviderUsage struct {
+	Provider string `json:"provider"`
+	// Limit is the configured limit in the rate[/burst] form of
+	// --rate-limits, if any.
+	Limit string `json:"limit,omitempty"`
+	// PolicyRate and Policy are the published cap, if the provider has
+	// one.
+	PolicyRate float64 `json:"policy_rate,omitempty"`
+	Policy     string  `json:"policy,omitempty"`
+	// Requests counts every request since the server started.
+	Requests int64 `json:"requests"`
+	// PeakPerSecond is the most requests sent within one second, at
+	// PeakAt.
+	PeakPerSecond int        `json:"peak_per_second"`
+	PeakAt        *time.Time `json:"peak_at,omitempty"`
+	// LastMinuteRate is the mean number of requests per second over the
+	// last minute.
+	LastMinuteRate float64 `json:"last_minute_rate"`
+	// Last24Hours counts the requests of the last 24 hours.
+	Last24Hours int `json:"last_24_hours"`
+	// Daily holds the requests of the last seven UTC days with any,
+	// oldest first.
+	Daily []DayVolume `json:"daily,omitempty"`
+	// Compliant reports whether the peak second stayed within the
+	// published cap, or else within the configured rate and burst. It is
+	// left out for providers with neither.
+	Compliant *bool `json:"compliant,omitempty"`
+}
+
+// slot counts the requests of one second or minute, identified by its
+// number since the Unix epoch.
+type slot struct {
+	period int64
+	count  int
+}
+
+// providerUsage holds the request counts of one provider.
+type providerUsage struct {
+	total   int64
+	peak    int
+	peakAt  time.Time
+	seconds [secondsPerMinute]slot
+	minutes [minutesPerDay]slot
+	days    []DayVolume
+}
+
+// Usage counts the outbound requests sent to each provider, so operators
+// can show that the server keeps to the usage policies of NCBI, Europe PMC
+// and the other providers. Counts are kept in memory and start over when
+// the server restarts.
+type Usage struct {
+	mu        sync.Mutex
+	providers map[string]*providerUsage
+	now       func() time.Time
+}
+
+// NewUsage creates an empty usage counter.
+func NewUsage() *Usage {
+	return &Usage{providers: make(map[string]*providerUsage), now: time.Now}
+}
+
+// Record counts a request sent to a provider now.
+func (u *Usage) Record(provider string) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := u.now()
+	usage, ok := u.providers[provider]
+	if !ok {
+		usage = &providerUsage{}
+		u.providers[provider] = usage
+	}
+	usage.total++
+	second := now.Unix()
+	if count := increment(usage.seconds[:], second); count > usage.peak {
+		usage.peak = count
+		usage.peakAt = time.Unix(second, 0).UTC()
+	}
+	increment(usage.minutes[:], second/secondsPerMinute)
+	date := now.UTC().Format(time.DateOnly)
+	if len(usage.days) == 0 || usage.days[len(usage.days)-1].Date != date {
+		usage.days = append(usage.days, DayVolume{Date: date})
+		usage.days = usage.days[max(0, len(usage.days)-usageDays):]
+	}
+	usage.days[len(usage.days)-1].Requests++
+}
+
+// increment counts a request in the slot of period within ring, starting
+// the slot over when it last counted an earlier period, and returns the
+// count of period.
+func increment(ring []slot, period int64) int {
+	current := &ring[period%int64(len(ring))]
+	if current.period != period {
+		*current = slot{period: period}
+	}
+	current.count++
+	return current.count
+}
+
+// sinceCount sums the counts of the slots of ring from period from on.
+func sinceCount(ring []slot, from int64) int {
+	total := 0
+	for _, counted := range ring {
+		if counted.period >= from {
+			total += counted.count
+		}
+	}
+	return total
+}
+
+// Report returns the usage of every provider that was sent requests or
+// publishes a cap, sorted by provider, checked against the limits of
+// registry.
+func (u *Usage) Report(registry *Registry) []ProviderUsage {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	now := u.now()
+	names := slices.Collect(maps.Keys(u.providers))
+	for name := range Policies {
+		if !slices.Contains(names, name) {
+			names = append(names, name)
+		}
+	}
+	slices.Sort(names)
+	report := make([]ProviderUsage, 0, len(names))
+	for _, name := range names {
+		row := ProviderUsage{Provider: name}
+		if usage, ok := u.providers[name]; ok {
+			second := now.Unix()
+			row.Requests = usage.total
+			row.PeakPerSecond = usage.peak
+			peakAt := usage.peakAt
+			row.PeakAt = &peakAt
+			row.LastMinuteRate = float64(sinceCount(usage.seconds[:], second-secondsPerMinute+1)) / secondsPerMinute
+			row.Last24Hours = sinceCount(usage.minutes[:], second/secondsPerMinute-minutesPerDay+1)
+			row.Daily = slices.Clone(usage.days)
+		}
+		limit, limited := registry.Limit(name)
+		if limited {
+			row.Limit = limit.String()
+		}
+		var allowed float64
+		switch policy, ok := Policies[name]; {
+		case ok:
+			row.PolicyRate = policy.Rate
+			row.Policy = policy.Source
+			// A second holds at least one request, however low the cap.
+			allowed = math.Max(1, math.Ceil(policy.Rate))
+		case limited:
+			// A full bucket lets its burst through on top of the rate.
+			allowed = math.Ceil(limit.Rate) + float64(limit.Burst)
+		default:
+			report = append(report, row)
+			continue
+		}
+		compliant := float64(row.PeakPerSecond) <= allowed
+		row.Compliant = &compliant
+		report = append(report, row)
+	}
+	return report
+}
+
+// sharedUsage counts the requests of the shared registry and of every
+// registry created with NewRegistry.
+var sharedUsage = NewUsage()
+
+// UsageReport returns the usage of every provider, checked against the
+// limits of the shared registry.
+func UsageReport() []ProviderUsage {
+	return sharedUsage.Report(shared.Load())
+}
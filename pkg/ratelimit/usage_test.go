@@ -0,0 +1,114 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUsage_Report(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 23, 59, 0, 0, time.UTC)
+	usage := NewUsage()
+	usage.now = func() time.Time { return now }
+	registry, err := NewRegistry(map[string]Limit{
+		ProviderPubMed:    {Rate: 3, Burst: 1},
+		ProviderEuropePMC: {Rate: 10, Burst: 10},
+	})
+	require.NoError(t, err)
+
+	for range 3 {
+		usage.Record(ProviderPubMed)
+	}
+	now = now.Add(2 * time.Minute)
+	for range 2 {
+		usage.Record(ProviderPubMed)
+	}
+	for range 21 {
+		usage.Record(ProviderEuropePMC)
+	}
+	usage.Record("zotero")
+
+	report := usage.Report(registry)
+	require.Len(t, report, 4, "providers with traffic or a published cap")
+	assert.Equal(t, "arxiv", report[0].Provider)
+	assert.Zero(t, report[0].Requests)
+	require.NotNil(t, report[0].Compliant)
+	assert.True(t, *report[0].Compliant)
+
+	europePMC := report[1]
+	assert.Equal(t, "europepmc", europePMC.Provider)
+	assert.Equal(t, "10/10", europePMC.Limit)
+	assert.Zero(t, europePMC.PolicyRate)
+	assert.Equal(t, 21, europePMC.PeakPerSecond)
+	require.NotNil(t, europePMC.Compliant)
+	assert.False(t, *europePMC.Compliant, "more than the rate and burst in one second")
+
+	pubmed := report[2]
+	assert.Equal(t, "pubmed", pubmed.Provider)
+	assert.Equal(t, "3/1", pubmed.Limit)
+	assert.InDelta(t, 3, pubmed.PolicyRate, 0)
+	assert.Contains(t, pubmed.Policy, "NCBI")
+	assert.Equal(t, int64(5), pubmed.Requests)
+	assert.Equal(t, 3, pubmed.PeakPerSecond)
+	assert.Equal(t, time.Date(2024, 1, 1, 23, 59, 0, 0, time.UTC), *pubmed.PeakAt)
+	assert.InDelta(t, 2.0/60, pubmed.LastMinuteRate, 1e-9)
+	assert.Equal(t, 5, pubmed.Last24Hours)
+	assert.Equal(t, []DayVolume{{Date: "2024-01-01", Requests: 3}, {Date: "2024-01-02", Requests: 2}}, pubmed.Daily)
+	require.NotNil(t, pubmed.Compliant)
+	assert.True(t, *pubmed.Compliant)
+
+	zotero := report[3]
+	assert.Equal(t, "zotero", zotero.Provider)
+	assert.Empty(t, zotero.Limit)
+	assert.Nil(t, zotero.Compliant, "no cap to check against")
+
+	now = now.Add(25 * time.Hour)
+	usage.Record(ProviderPubMed)
+	pubmed = usage.Report(registry)[2]
+	assert.Equal(t, 1, pubmed.Last24Hours, "older requests leave the last 24 hours")
+	assert.Equal(t, int64(6), pubmed.Requests)
+	assert.Len(t, pubmed.Daily, 3)
+}
+
+func TestUsage_KeepsSevenDays(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	usage := NewUsage()
+	usage.now = func() time.Time { return now }
+	for range 10 {
+		usage.Record(ProviderPubMed)
+		now = now.AddDate(0, 0, 1)
+	}
+	registry, err := NewRegistry(nil)
+	require.NoError(t, err)
+	daily := usage.Report(registry)[1].Daily
+	require.Len(t, daily, usageDays)
+	assert.Equal(t, "2024-01-04", daily[0].Date)
+	assert.Equal(t, "2024-01-10", daily[6].Date)
+}
+
+func TestRegistry_RecordsUsage(t *testing.T) {
+	t.Parallel()
+	registry, err := NewRegistry(map[string]Limit{ProviderCrossref: {Rate: 100, Burst: 10}})
+	require.NoError(t, err)
+	registry.usage = NewUsage()
+
+	require.NoError(t, registry.Wait(context.Background(), "api.crossref.org"))
+	require.NoError(t, registry.Wait(context.Background(), ProviderOpenAlex))
+	require.NoError(t, registry.Wait(context.Background(), "example.org"))
+
+	var providers []string
+	for _, row := range registry.usage.Report(registry) {
+		if row.Requests > 0 {
+			providers = append(providers, row.Provider)
+		}
+	}
+	assert.Equal(t, []string{"crossref", "openalex"}, providers, "hosts of unknown providers are not counted")
+	limit, ok := registry.Limit("api.crossref.org")
+	require.True(t, ok)
+	assert.Equal(t, "100/10", limit.String())
+}
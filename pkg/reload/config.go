@@ -0,0 +1,147 @@
+package reload
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/prompts"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/timeout"
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Config is the content of the reloadable configuration file.
+type Config struct {
+	// Prompts are added to the built-in prompts.
+	Prompts []PromptConfig `json:"prompts" validate:"dive"`
+	// Timeouts override --tool-timeout and --tool-timeouts.
+	Timeouts *TimeoutConfig `json:"timeouts"`
+	// RateLimits override --rate-limits, keyed by provider or host name.
+	RateLimits map[string]ratelimit.Limit `json:"rate_limits" validate:"dive"`
+	// ToolDefaults set the optional arguments calls leave out, keyed by
+	// tool and then parameter name.
+	ToolDefaults map[string]map[string]any `json:"tool_defaults"`
+}
+
+// PromptConfig defines a template prompt. The template is given inline or
+// read from TemplateFile, which is resolved against the configuration
+// file's directory and watched as well.
+type PromptConfig struct {
+	prompts.TemplateSpec
+	TemplateFile string `json:"template_file"`
+}
+
+// TimeoutConfig holds tool deadlines as Go durations such as "90s".
+type TimeoutConfig struct {
+	Default string            `json:"default"`
+	Tools   map[string]string `json:"tools"`
+}
+
+// LoadConfig reads and validates a configuration file, inlining the
+// templates of prompts defined in separate files. A file that does not
+// match the schema fails with a *ValidationError listing every problem.
+func LoadConfig(path string, schema Schema) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config %s: %w", path, err)
+	}
+	if problems := Validate(data, path, schema); len(problems) > 0 {
+		return nil, &ValidationError{File: path, Problems: problems}
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse config %s: %w", path, err)
+	}
+	for i, prompt := range cfg.Prompts {
+		if prompt.TemplateFile == "" {
+			continue
+		}
+		template, err := os.ReadFile(cfg.templatePath(path, i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template of prompt %s: %w", prompt.Name, err)
+		}
+		cfg.Prompts[i].Template = string(template)
+	}
+	if err := validate.Struct(cfg); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// templatePath resolves the template file of the i-th prompt.
+func (c *Config) templatePath(configPath string, i int) string {
+	name := c.Prompts[i].TemplateFile
+	if filepath.IsAbs(name) {
+		return name
+	}
+	return filepath.Join(filepath.Dir(configPath), name)
+}
+
+// files returns the configuration file and the template files it uses.
+func (c *Config) files(configPath string) []string {
+	files := []string{configPath}
+	for i, prompt := range c.Prompts {
+		if prompt.TemplateFile != "" {
+			files = append(files, c.templatePath(configPath, i))
+		}
+	}
+	return files
+}
+
+// timeouts overlays the configured deadlines on base.
+func (c *Config) timeouts(base timeout.Config) (timeout.Config, error) {
+	merged := timeout.Config{Default: base.Default, ToolTimeouts: maps.Clone(base.ToolTimeouts)}
+	if c.Timeouts == nil {
+		return merged, nil
+	}
+	if merged.ToolTimeouts == nil {
+		merged.ToolTimeouts = make(map[string]time.Duration)
+	}
+	if c.Timeouts.Default != "" {
+		parsed, err := parseTimeout(c.Timeouts.Default)
+		if err != nil {
+			return timeout.Config{}, fmt.Errorf("invalid default timeout: %w", err)
+		}
+		merged.Default = parsed
+	}
+	for name, value := range c.Timeouts.Tools {
+		parsed, err := parseTimeout(value)
+		if err != nil {
+			return timeout.Config{}, fmt.Errorf("invalid timeout for %s: %w", name, err)
+		}
+		merged.ToolTimeouts[name] = parsed
+	}
+	return merged, nil
+}
+
+// rateLimits overlays the configured rate limits on base.
+func (c *Config) rateLimits(base map[string]ratelimit.Limit) map[string]ratelimit.Limit {
+	merged := maps.Clone(base)
+	if merged == nil {
+		merged = make(map[string]ratelimit.Limit, len(c.RateLimits))
+	}
+	for name, limit := range c.RateLimits {
+		merged[strings.ToLower(name)] = limit
+	}
+	return merged
+}
+
+// parseTimeout parses a non-negative duration.
+func parseTimeout(value string) (time.Duration, error) {
+	duration, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, err
+	}
+	if duration < 0 {
+		return 0, fmt.Errorf("%s is negative", value)
+	}
+	return duration, nil
+}
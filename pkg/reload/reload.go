@@ -0,0 +1,267 @@
+// Package reload applies a configuration file of prompt templates and tool
+// settings to the running server and re-applies it whenever the file or
+// one of its templates changes, so prompts and limits can be tuned without
+// a restart.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/prompts"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/timeout"
+	"github.com/dictybase/dcr-mcp/pkg/tooldefaults"
+	"github.com/fsnotify/fsnotify"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+const defaultDebounce = 200 * time.Millisecond
+
+// PromptServer is the part of the MCP server prompts are registered with.
+// Both methods notify clients that the prompt list changed.
+type PromptServer interface {
+	AddPrompts(prompts ...server.ServerPrompt)
+	DeletePrompts(names ...string)
+}
+
+// Reloader keeps the server in line with the configuration file.
+type Reloader struct {
+	path   string
+	target PromptServer
+	config *Options
+	mu     sync.Mutex
+	// prompts are the names of the prompts registered from the file.
+	prompts []string
+	// files are the configuration file and its templates.
+	files []string
+}
+
+// Option represents a configuration option for Reloader.
+type Option func(*Options)
+
+// Options holds the configuration for the reloader.
+type Options struct {
+	settings   *timeout.Settings
+	timeouts   timeout.Config
+	rateLimits map[string]ratelimit.Limit
+	defaults   *tooldefaults.Store
+	schema     Schema
+	debounce   time.Duration
+	logger     *slog.Logger
+}
+
+// WithTimeouts applies the configured deadlines to settings, on top of the
+// base deadlines given on the command line.
+func WithTimeouts(settings *timeout.Settings, base timeout.Config) Option {
+	return func(o *Options) {
+		o.settings = settings
+		o.timeouts = base
+	}
+}
+
+// WithRateLimits sets the rate limits the configured ones are merged over,
+// defaults to ratelimit.DefaultLimits.
+func WithRateLimits(base map[string]ratelimit.Limit) Option {
+	return func(o *Options) {
+		o.rateLimits = base
+	}
+}
+
+// WithToolNames sets the tools the file may set deadlines for; deadlines
+// of other tools are rejected. Any name is accepted by default.
+func WithToolNames(names []string) Option {
+	return func(o *Options) {
+		o.schema.Tools = names
+	}
+}
+
+// WithToolDefaults applies the configured argument defaults to store.
+func WithToolDefaults(store *tooldefaults.Store) Option {
+	return func(o *Options) {
+		o.defaults = store
+	}
+}
+
+// WithToolSchemas sets the input schemas argument defaults are checked
+// against, keyed by tool name. Defaults of other tools are not checked.
+func WithToolSchemas(schemas map[string]mcp.ToolInputSchema) Option {
+	return func(o *Options) {
+		o.schema.Parameters = schemas
+	}
+}
+
+// WithDebounce sets how long to wait for a burst of file events to settle
+// before reloading.
+func WithDebounce(debounce time.Duration) Option {
+	return func(o *Options) {
+		o.debounce = debounce
+	}
+}
+
+// WithLogger sets the logger for the reloader.
+func WithLogger(logger *slog.Logger) Option {
+	return func(o *Options) {
+		o.logger = logger
+	}
+}
+
+// New creates a reloader for the configuration file at path.
+func New(path string, target PromptServer, opts ...Option) *Reloader {
+	cfg := &Options{
+		rateLimits: ratelimit.DefaultLimits,
+		debounce:   defaultDebounce,
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Reloader{path: filepath.Clean(path), target: target, config: cfg}
+}
+
+// Load reads the configuration file and applies it. Nothing is applied
+// when the file is invalid.
+func (r *Reloader) Load() error {
+	cfg, err := LoadConfig(r.path, r.config.schema)
+	if err != nil {
+		return err
+	}
+	entries := make([]server.ServerPrompt, 0, len(cfg.Prompts))
+	for _, promptConfig := range cfg.Prompts {
+		prompt, err := prompts.NewTemplatePrompt(
+			promptConfig.TemplateSpec,
+			r.config.logger.With("prompt", promptConfig.Name),
+		)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, server.ServerPrompt{
+			Prompt:  prompt.GetPrompt(),
+			Handler: prompt.Handler,
+		})
+	}
+	deadlines, err := cfg.timeouts(r.config.timeouts)
+	if err != nil {
+		return err
+	}
+	if err := ratelimit.Configure(cfg.rateLimits(r.config.rateLimits)); err != nil {
+		return err
+	}
+	if r.config.settings != nil {
+		r.config.settings.Store(deadlines)
+	}
+	if r.config.defaults != nil {
+		r.config.defaults.Store(cfg.ToolDefaults)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	names := make([]string, 0, len(entries))
+	for _, entry := range entries {
+		names = append(names, entry.Prompt.Name)
+	}
+	var removed []string
+	for _, name := range r.prompts {
+		if !slices.Contains(names, name) {
+			removed = append(removed, name)
+		}
+	}
+	if len(removed) > 0 {
+		r.target.DeletePrompts(removed...)
+	}
+	if len(entries) > 0 {
+		r.target.AddPrompts(entries...)
+	}
+	r.prompts = names
+	r.files = cfg.files(r.path)
+	r.config.logger.Info("configuration loaded", "path", r.path, "prompts", names)
+	return nil
+}
+
+// Watch reloads the configuration whenever the file or one of its
+// templates is written, created or renamed, until ctx is done. Editors
+// often replace files, so the containing directories are watched. A
+// failed reload is logged and the previous configuration stays in effect.
+func (r *Reloader) Watch(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+	watched := make(map[string]bool)
+	r.watchDirs(watcher, watched)
+
+	debounce := time.NewTimer(r.config.debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if r.isWatchedFile(event.Name) {
+				debounce.Reset(r.config.debounce)
+			}
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			r.config.logger.Error("file watcher error", "error", err)
+		case <-debounce.C:
+			if err := r.Load(); err != nil {
+				r.config.logger.Error(
+					"failed to reload configuration, keeping the previous one",
+					"path", r.path,
+					"error", err,
+				)
+				continue
+			}
+			r.watchDirs(watcher, watched)
+		}
+	}
+}
+
+// watchDirs adds the directories of newly referenced files to the watcher.
+func (r *Reloader) watchDirs(watcher *fsnotify.Watcher, watched map[string]bool) {
+	for _, dir := range r.dirs() {
+		if watched[dir] {
+			continue
+		}
+		if err := watcher.Add(dir); err != nil {
+			r.config.logger.Error("failed to watch directory", "dir", dir, "error", err)
+			continue
+		}
+		watched[dir] = true
+	}
+}
+
+// dirs returns the directories holding the watched files.
+func (r *Reloader) dirs() []string {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	dirs := []string{filepath.Dir(r.path)}
+	for _, file := range r.files {
+		if dir := filepath.Dir(file); !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+	return dirs
+}
+
+// isWatchedFile reports whether name is the configuration file or one of
+// its templates.
+func (r *Reloader) isWatchedFile(name string) bool {
+	name = filepath.Clean(name)
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return name == r.path || slices.Contains(r.files, name)
+}
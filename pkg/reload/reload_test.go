@@ -0,0 +1,163 @@
+package reload
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/timeout"
+	"github.com/dictybase/dcr-mcp/pkg/tooldefaults"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer records the registered prompts.
+type fakeServer struct {
+	mu      sync.Mutex
+	prompts map[string]server.ServerPrompt
+	changes int
+}
+
+func newFakeServer() *fakeServer {
+	return &fakeServer{prompts: make(map[string]server.ServerPrompt)}
+}
+
+func (f *fakeServer) AddPrompts(prompts ...server.ServerPrompt) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, prompt := range prompts {
+		f.prompts[prompt.Prompt.Name] = prompt
+	}
+	f.changes++
+}
+
+func (f *fakeServer) DeletePrompts(names ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for _, name := range names {
+		delete(f.prompts, name)
+	}
+	f.changes++
+}
+
+func (f *fakeServer) names() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	names := make([]string, 0, len(f.prompts))
+	for name := range f.prompts {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func (f *fakeServer) render(t *testing.T, name string, args map[string]string) string {
+	t.Helper()
+	f.mu.Lock()
+	prompt, ok := f.prompts[name]
+	f.mu.Unlock()
+	require.True(t, ok, "prompt %s is registered", name)
+	request := mcp.GetPromptRequest{}
+	request.Params.Arguments = args
+	result, err := prompt.Handler(context.Background(), request)
+	require.NoError(t, err)
+	text, ok := result.Messages[0].Content.(mcp.TextContent)
+	require.True(t, ok)
+	return text.Text
+}
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+}
+
+const standupConfig = `{
+  "prompts": [
+    {
+      "name": "standup",
+      "description": "Drafts a standup update",
+      "arguments": [{"name": "project", "required": true}],
+      "template_file": "standup.tmpl"
+    },
+    {"name": "thanks", "template": "Thank {{.to}} warmly."}
+  ],
+  "timeouts": {"default": "90s", "tools": {"git-summary": "15m"}},
+  "rate_limits": {"pubmed": {"rate": 10, "burst": 10}},
+  "tool_defaults": {"literature-fetch": {"provider": "europepmc"}}
+}`
+
+func TestReloader_Load(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	writeFile(t, configPath, standupConfig)
+	writeFile(t, filepath.Join(dir, "standup.tmpl"), "Summarise yesterday's work on {{.project}}.")
+	target := newFakeServer()
+	settings := timeout.NewSettings(timeout.Config{Default: time.Minute})
+	defaults := tooldefaults.NewStore()
+	reloader := New(
+		configPath,
+		target,
+		WithTimeouts(settings, timeout.Config{
+			Default:      time.Minute,
+			ToolTimeouts: map[string]time.Duration{"literature-fetch": 30 * time.Second},
+		}),
+		WithToolDefaults(defaults),
+		WithLogger(logging.Discard()),
+	)
+
+	require.NoError(t, reloader.Load())
+	assert.Equal(t, []string{"standup", "thanks"}, target.names())
+	assert.Equal(t, "Summarise yesterday's work on dictyBase.",
+		target.render(t, "standup", map[string]string{"project": "dictyBase"}))
+	deadlines := settings.Load()
+	assert.Equal(t, 90*time.Second, deadlines.Default)
+	assert.Equal(t, map[string]time.Duration{
+		"literature-fetch": 30 * time.Second,
+		"git-summary":      15 * time.Minute,
+	}, deadlines.ToolTimeouts)
+	assert.Equal(t, map[string]any{"provider": "europepmc"}, defaults.For("literature-fetch"))
+
+	writeFile(t, configPath, `{"prompts": [{"name": "thanks", "template": "Thanks, {{.to}}!"}]}`)
+	require.NoError(t, reloader.Load())
+	assert.Equal(t, []string{"thanks"}, target.names(), "removed prompts are deleted")
+	assert.Equal(t, "Thanks, Jane!", target.render(t, "thanks", map[string]string{"to": "Jane"}))
+	assert.Equal(t, time.Minute, settings.Load().Default, "removed settings revert to the flags")
+	assert.Empty(t, defaults.For("literature-fetch"), "removed defaults no longer apply")
+
+	writeFile(t, configPath, `{"prompts": [{"name": "broken", "template": "{{.to"}]}`)
+	require.Error(t, reloader.Load())
+	assert.Equal(t, []string{"thanks"}, target.names(), "an invalid file changes nothing")
+}
+
+func TestReloader_Watch(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	templatePath := filepath.Join(dir, "standup.tmpl")
+	writeFile(t, configPath, standupConfig)
+	writeFile(t, templatePath, "Version one for {{.project}}.")
+	target := newFakeServer()
+	reloader := New(configPath, target, WithDebounce(10*time.Millisecond), WithLogger(logging.Discard()))
+	require.NoError(t, reloader.Load())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- reloader.Watch(ctx)
+	}()
+	// Give the watcher time to subscribe before changing the template.
+	time.Sleep(50 * time.Millisecond)
+	writeFile(t, templatePath, "Version two for {{.project}}.")
+	assert.Eventually(t, func() bool {
+		return target.render(t, "standup", map[string]string{"project": "x"}) == "Version two for x."
+	}, 2*time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
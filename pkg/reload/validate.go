@@ -0,0 +1,616 @@
+package reload
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// errTrailingData is returned for a document with more than one value.
+var errTrailingData = errors.New("unexpected data after the top-level value")
+
+// identifierRegex matches keys that can be written as .key in a path.
+var identifierRegex = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_-]*$`)
+
+// Schema holds the names a configuration file may refer to besides its own
+// fields.
+type Schema struct {
+	// Tools are the tools deadlines and argument defaults may be set for;
+	// any name is accepted when empty.
+	Tools []string
+	// Parameters are the input schemas of the tools, keyed by name, which
+	// argument defaults must match. Defaults of tools without a schema are
+	// not checked.
+	Parameters map[string]mcp.ToolInputSchema
+}
+
+// Problem is an error at one place of a configuration file.
+type Problem struct {
+	// Path locates the value, e.g. prompts[1].arguments[0].name; it is
+	// empty for syntax errors.
+	Path string
+	// Line is the line of the value in the file, starting at 1.
+	Line    int
+	Message string
+	// Suggestion is the valid name closest to a misspelled one.
+	Suggestion string
+}
+
+// String renders the problem as "prompts[0].role: message", without its
+// line.
+func (p Problem) String() string {
+	var text strings.Builder
+	if p.Path != "" {
+		text.WriteString(p.Path + ": ")
+	}
+	text.WriteString(p.Message)
+	if p.Suggestion != "" {
+		fmt.Fprintf(&text, " (did you mean %q?)", p.Suggestion)
+	}
+	return text.String()
+}
+
+// ValidationError lists every problem found in a configuration file.
+type ValidationError struct {
+	File     string
+	Problems []Problem
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	problems := make([]string, len(e.Problems))
+	for i, problem := range e.Problems {
+		problems[i] = fmt.Sprintf("line %d: %s", problem.Line, problem)
+	}
+	return fmt.Sprintf("invalid config %s: %s", e.File, strings.Join(problems, "; "))
+}
+
+// Validate checks a configuration document against the schema and returns
+// the problems in document order. Template files are resolved against the
+// directory of configPath and checked as well.
+func Validate(data []byte, configPath string, schema Schema) []Problem {
+	checker := &checker{data: data, configPath: configPath, schema: schema}
+	root, err := parseDocument(data)
+	if err != nil {
+		// Errors without an offset, such as a truncated file, are at the end.
+		offset := int64(len(data))
+		var syntaxErr *json.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			offset = syntaxErr.Offset
+		}
+		checker.add("", offset, "invalid JSON: "+err.Error())
+		return checker.problems
+	}
+	checker.root(root)
+	return checker.problems
+}
+
+// nodeKind is the JSON type of a node.
+type nodeKind string
+
+const (
+	kindObject  nodeKind = "an object"
+	kindArray   nodeKind = "an array"
+	kindString  nodeKind = "a string"
+	kindNumber  nodeKind = "a number"
+	kindBoolean nodeKind = "a boolean"
+	kindNull    nodeKind = "null"
+)
+
+// node is a parsed JSON value that remembers where it ends in the file.
+// Object fields keep their order and duplicates, which encoding/json
+// silently drops.
+type node struct {
+	kind   nodeKind
+	value  any
+	fields []field
+	items  []*node
+	offset int64
+}
+
+// field is a key of an object with its value.
+type field struct {
+	key    string
+	offset int64
+	value  *node
+}
+
+// lookup returns the value of a key, matched case-insensitively the way
+// encoding/json matches struct fields.
+func (n *node) lookup(key string) *node {
+	for _, field := range n.fields {
+		if strings.EqualFold(field.key, key) {
+			return field.value
+		}
+	}
+	return nil
+}
+
+// parseDocument parses data into a node tree.
+func parseDocument(data []byte) (*node, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	root, err := parseValue(decoder)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := decoder.Token(); !errors.Is(err, io.EOF) {
+		if err == nil {
+			err = errTrailingData
+		}
+		return nil, err
+	}
+	return root, nil
+}
+
+// parseValue reads the next value from decoder.
+func parseValue(decoder *json.Decoder) (*node, error) {
+	token, err := decoder.Token()
+	if err != nil {
+		return nil, err
+	}
+	parsed := &node{value: token, offset: decoder.InputOffset()}
+	switch token := token.(type) {
+	case json.Delim:
+		if token == '{' {
+			parsed.kind = kindObject
+			for decoder.More() {
+				key, err := decoder.Token()
+				if err != nil {
+					return nil, err
+				}
+				keyOffset := decoder.InputOffset()
+				value, err := parseValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				parsed.fields = append(parsed.fields, field{key: key.(string), offset: keyOffset, value: value})
+			}
+		} else {
+			parsed.kind = kindArray
+			for decoder.More() {
+				item, err := parseValue(decoder)
+				if err != nil {
+					return nil, err
+				}
+				parsed.items = append(parsed.items, item)
+			}
+		}
+		// The closing delimiter.
+		if _, err := decoder.Token(); err != nil {
+			return nil, err
+		}
+	case string:
+		parsed.kind = kindString
+	case json.Number:
+		parsed.kind = kindNumber
+	case bool:
+		parsed.kind = kindBoolean
+	default:
+		parsed.kind = kindNull
+	}
+	return parsed, nil
+}
+
+// checker collects the problems of a configuration document.
+type checker struct {
+	data       []byte
+	configPath string
+	schema     Schema
+	problems   []Problem
+}
+
+// add records a problem at offset.
+func (c *checker) add(path string, offset int64, message string) {
+	c.suggest(path, offset, message, "")
+}
+
+// suggest records a problem at offset with a suggested replacement.
+func (c *checker) suggest(path string, offset int64, message, suggestion string) {
+	c.problems = append(c.problems, Problem{
+		Path:       path,
+		Line:       c.line(offset),
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+// line converts a byte offset to a line number.
+func (c *checker) line(offset int64) int {
+	offset = min(max(offset, 0), int64(len(c.data)))
+	return bytes.Count(c.data[:offset], []byte("\n")) + 1
+}
+
+// expect records a problem unless value is of the wanted kind.
+func (c *checker) expect(path string, value *node, kind nodeKind) bool {
+	if value.kind == kind {
+		return true
+	}
+	c.add(path, value.offset, fmt.Sprintf("must be %s, not %s", kind, value.kind))
+	return false
+}
+
+// fields checks that an object only has known, unrepeated keys; any key is
+// known when known is empty. Keys are compared case-insensitively, like
+// encoding/json does.
+func (c *checker) fields(path string, object *node, known ...string) {
+	seen := make(map[string]int64, len(object.fields))
+	for _, field := range object.fields {
+		fieldPath := joinKey(path, field.key)
+		if first, found := seen[strings.ToLower(field.key)]; found {
+			c.add(fieldPath, field.offset, fmt.Sprintf("duplicate field, first set on line %d", c.line(first)))
+			continue
+		}
+		seen[strings.ToLower(field.key)] = field.offset
+		if len(known) > 0 &&
+			!slices.ContainsFunc(known, func(name string) bool { return strings.EqualFold(name, field.key) }) {
+			c.suggest(fieldPath, field.offset, "unknown field", closest(field.key, known))
+		}
+	}
+}
+
+// root checks the top-level object.
+func (c *checker) root(root *node) {
+	if !c.expect("", root, kindObject) {
+		return
+	}
+	c.fields("", root, "prompts", "timeouts", "rate_limits", "tool_defaults")
+	if prompts := root.lookup("prompts"); prompts != nil && c.expect("prompts", prompts, kindArray) {
+		c.prompts(prompts)
+	}
+	if timeouts := root.lookup("timeouts"); timeouts != nil && c.expect("timeouts", timeouts, kindObject) {
+		c.timeouts(timeouts)
+	}
+	if limits := root.lookup("rate_limits"); limits != nil && c.expect("rate_limits", limits, kindObject) {
+		c.rateLimits(limits)
+	}
+	if defaults := root.lookup("tool_defaults"); defaults != nil && c.expect("tool_defaults", defaults, kindObject) {
+		c.toolDefaults(defaults)
+	}
+}
+
+// prompts checks the prompt definitions, whose names must be unique.
+func (c *checker) prompts(prompts *node) {
+	names := make(map[string]int)
+	for i, prompt := range prompts.items {
+		path := fmt.Sprintf("prompts[%d]", i)
+		if !c.expect(path, prompt, kindObject) {
+			continue
+		}
+		c.fields(path, prompt, "name", "description", "arguments", "role", "template", "template_file")
+		name, ok := c.requiredString(path, prompt, "name")
+		if ok {
+			if first, found := names[name]; found {
+				c.add(path+".name", prompt.lookup("name").offset,
+					fmt.Sprintf("duplicate prompt name %q, first defined at prompts[%d]", name, first))
+			} else {
+				names[name] = i
+			}
+		}
+		c.optionalString(path, prompt, "description")
+		if role, ok := c.optionalString(path, prompt, "role"); ok {
+			c.enum(path+".role", prompt.lookup("role"), role, "user", "assistant")
+		}
+		if arguments := prompt.lookup("arguments"); arguments != nil && c.expect(path+".arguments", arguments, kindArray) {
+			c.arguments(path+".arguments", arguments)
+		}
+		c.template(path, prompt, name)
+	}
+}
+
+// arguments checks the arguments of a prompt, whose names must be unique.
+func (c *checker) arguments(path string, arguments *node) {
+	names := make(map[string]int)
+	for i, argument := range arguments.items {
+		argumentPath := fmt.Sprintf("%s[%d]", path, i)
+		if !c.expect(argumentPath, argument, kindObject) {
+			continue
+		}
+		c.fields(argumentPath, argument, "name", "description", "required")
+		if name, ok := c.requiredString(argumentPath, argument, "name"); ok {
+			if first, found := names[name]; found {
+				c.add(argumentPath+".name", argument.lookup("name").offset,
+					fmt.Sprintf("duplicate argument name %q, first defined at %s[%d]", name, path, first))
+			} else {
+				names[name] = i
+			}
+		}
+		c.optionalString(argumentPath, argument, "description")
+		if required := argument.lookup("required"); required != nil {
+			c.expect(argumentPath+".required", required, kindBoolean)
+		}
+	}
+}
+
+// template checks that a prompt has either an inline template or a
+// template file, and that the template parses.
+func (c *checker) template(path string, prompt *node, name string) {
+	inline, hasInline := c.optionalString(path, prompt, "template")
+	file, hasFile := c.optionalString(path, prompt, "template_file")
+	switch {
+	case hasInline && hasFile:
+		c.add(path+".template_file", prompt.lookup("template_file").offset,
+			"set only one of template and template_file")
+	case hasInline:
+		if inline == "" {
+			c.add(path+".template", prompt.lookup("template").offset, "must not be empty")
+			return
+		}
+		c.parseTemplate(path+".template", prompt.lookup("template"), name, inline)
+	case hasFile:
+		if c.configPath == "" {
+			return
+		}
+		if !filepath.IsAbs(file) {
+			file = filepath.Join(filepath.Dir(c.configPath), file)
+		}
+		content, err := os.ReadFile(file)
+		if err != nil {
+			c.add(path+".template_file", prompt.lookup("template_file").offset,
+				fmt.Sprintf("cannot read template: %v", err))
+			return
+		}
+		c.parseTemplate(path+".template_file", prompt.lookup("template_file"), name, string(content))
+	case prompt.lookup("template") == nil && prompt.lookup("template_file") == nil:
+		c.add(path, prompt.offset, "missing template or template_file")
+	}
+}
+
+// parseTemplate records a problem when text is not a valid template.
+func (c *checker) parseTemplate(path string, value *node, name, text string) {
+	if _, err := template.New(name).Option("missingkey=zero").Parse(text); err != nil {
+		c.add(path, value.offset, fmt.Sprintf("invalid template: %v", err))
+	}
+}
+
+// timeouts checks the default and per-tool deadlines.
+func (c *checker) timeouts(timeouts *node) {
+	c.fields("timeouts", timeouts, "default", "tools")
+	if value := timeouts.lookup("default"); value != nil {
+		c.duration("timeouts.default", value)
+	}
+	tools := timeouts.lookup("tools")
+	if tools == nil || !c.expect("timeouts.tools", tools, kindObject) {
+		return
+	}
+	seen := make(map[string]int64)
+	for _, field := range tools.fields {
+		path := joinKey("timeouts.tools", field.key)
+		if first, found := seen[field.key]; found {
+			c.add(path, field.offset, fmt.Sprintf("duplicate tool, first set on line %d", c.line(first)))
+			continue
+		}
+		seen[field.key] = field.offset
+		if len(c.schema.Tools) > 0 && !slices.Contains(c.schema.Tools, field.key) {
+			c.suggest(path, field.offset, "unknown tool", closest(field.key, c.schema.Tools))
+		}
+		c.duration(path, field.value)
+	}
+}
+
+// toolDefaults checks the argument defaults, keyed by tool and parameter
+// name, against the input schemas of the tools. Required parameters and
+// idempotency keys have no defaults, since every call sets its own.
+func (c *checker) toolDefaults(defaults *node) {
+	seen := make(map[string]int64)
+	for _, tool := range defaults.fields {
+		path := joinKey("tool_defaults", tool.key)
+		if first, found := seen[tool.key]; found {
+			c.add(path, tool.offset, fmt.Sprintf("duplicate tool, first set on line %d", c.line(first)))
+			continue
+		}
+		seen[tool.key] = tool.offset
+		if len(c.schema.Tools) > 0 && !slices.Contains(c.schema.Tools, tool.key) {
+			c.suggest(path, tool.offset, "unknown tool", closest(tool.key, c.schema.Tools))
+		}
+		if !c.expect(path, tool.value, kindObject) {
+			continue
+		}
+		schema, ok := c.schema.Parameters[tool.key]
+		if !ok {
+			c.fields(path, tool.value)
+			continue
+		}
+		// Deprecated names are renamed before defaults are applied.
+		var names []string
+		for _, name := range slices.Sorted(maps.Keys(schema.Properties)) {
+			if property, ok := schema.Properties[name].(map[string]any); !ok || property["deprecated"] != true {
+				names = append(names, name)
+			}
+		}
+		if len(names) == 0 {
+			if len(tool.value.fields) > 0 {
+				c.add(path, tool.offset, "the tool has no parameters")
+			}
+			continue
+		}
+		c.fields(path, tool.value, names...)
+		for _, parameter := range tool.value.fields {
+			property, ok := schema.Properties[parameter.key].(map[string]any)
+			if !ok || !slices.Contains(names, parameter.key) {
+				continue
+			}
+			parameterPath := joinKey(path, parameter.key)
+			if slices.Contains(schema.Required, parameter.key) || parameter.key == idempotency.KeyArgument {
+				c.add(parameterPath, parameter.offset, "set by each call, cannot have a default")
+				continue
+			}
+			c.parameter(parameterPath, parameter.value, property)
+		}
+	}
+}
+
+// parameter checks a default against the JSON schema of its parameter:
+// its type and, for strings, its allowed values.
+func (c *checker) parameter(path string, value *node, property map[string]any) {
+	kinds := map[string]nodeKind{
+		"string":  kindString,
+		"number":  kindNumber,
+		"integer": kindNumber,
+		"boolean": kindBoolean,
+		"array":   kindArray,
+		"object":  kindObject,
+	}
+	typeName, _ := property["type"].(string)
+	kind, ok := kinds[typeName]
+	if !ok || !c.expect(path, value, kind) {
+		return
+	}
+	if typeName == "integer" {
+		if _, err := strconv.Atoi(string(value.value.(json.Number))); err != nil {
+			c.add(path, value.offset, "must be a whole number")
+		}
+	}
+	allowed, ok := property["enum"].([]string)
+	if ok && kind == kindString {
+		c.enum(path, value, value.value.(string), allowed...)
+	}
+}
+
+// duration checks a non-negative Go duration such as "90s".
+func (c *checker) duration(path string, value *node) {
+	if !c.expect(path, value, kindString) {
+		return
+	}
+	if _, err := parseTimeout(value.value.(string)); err != nil {
+		c.add(path, value.offset, fmt.Sprintf("invalid duration: %v", err))
+	}
+}
+
+// rateLimits checks the rate limits, keyed by provider or host name.
+// Names are lowercased when applied, so they must be unique regardless
+// of case.
+func (c *checker) rateLimits(limits *node) {
+	providers := ratelimit.Providers()
+	seen := make(map[string]int64)
+	for _, field := range limits.fields {
+		path := joinKey("rate_limits", field.key)
+		name := strings.ToLower(field.key)
+		if first, found := seen[name]; found {
+			c.add(path, field.offset, fmt.Sprintf("duplicate provider or host, first set on line %d", c.line(first)))
+			continue
+		}
+		seen[name] = field.offset
+		// Host names have a dot; anything else has to be a provider.
+		if !strings.Contains(name, ".") && !slices.Contains(providers, name) {
+			c.suggest(path, field.offset, "unknown provider, use a provider or host name", closest(name, providers))
+		}
+		if !c.expect(path, field.value, kindObject) {
+			continue
+		}
+		c.fields(path, field.value, "rate", "burst")
+		if rate, ok := c.number(path, field.value, "rate"); ok && rate <= 0 {
+			c.add(path+".rate", field.value.lookup("rate").offset, "must be greater than 0")
+		}
+		if burst, ok := c.number(path, field.value, "burst"); ok && (burst < 1 || burst != float64(int(burst))) {
+			c.add(path+".burst", field.value.lookup("burst").offset, "must be a whole number of at least 1")
+		}
+	}
+}
+
+// number returns a required numeric field of an object.
+func (c *checker) number(path string, object *node, key string) (float64, bool) {
+	value := object.lookup(key)
+	if value == nil {
+		c.add(path, object.offset, "missing "+key)
+		return 0, false
+	}
+	if !c.expect(joinKey(path, key), value, kindNumber) {
+		return 0, false
+	}
+	number, err := strconv.ParseFloat(string(value.value.(json.Number)), 64)
+	return number, err == nil
+}
+
+// requiredString returns a required, non-empty string field of an object.
+func (c *checker) requiredString(path string, object *node, key string) (string, bool) {
+	value := object.lookup(key)
+	if value == nil {
+		c.add(path, object.offset, "missing "+key)
+		return "", false
+	}
+	text, ok := c.optionalString(path, object, key)
+	if ok && text == "" {
+		c.add(joinKey(path, key), value.offset, "must not be empty")
+		return "", false
+	}
+	return text, ok
+}
+
+// optionalString returns a string field of an object and whether it is
+// set to a string.
+func (c *checker) optionalString(path string, object *node, key string) (string, bool) {
+	value := object.lookup(key)
+	if value == nil || !c.expect(joinKey(path, key), value, kindString) {
+		return "", false
+	}
+	return value.value.(string), true
+}
+
+// enum records a problem unless text is one of allowed.
+func (c *checker) enum(path string, value *node, text string, allowed ...string) {
+	if !slices.Contains(allowed, text) {
+		c.suggest(path, value.offset, "must be one of "+strings.Join(allowed, ", "), closest(text, allowed))
+	}
+}
+
+// joinKey appends an object key to a path, quoting keys that are not
+// plain identifiers.
+func joinKey(path, key string) string {
+	if !identifierRegex.MatchString(key) {
+		return path + "[" + strconv.Quote(key) + "]"
+	}
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// closest returns the candidate most similar to name, or "" when none is
+// close enough to be a likely typo.
+func closest(name string, candidates []string) string {
+	best, bestDistance := "", max(2, len(name)/3)+1
+	for _, candidate := range candidates {
+		distance := editDistance(strings.ToLower(name), strings.ToLower(candidate))
+		if distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings.
+func editDistance(a, b string) int {
+	source, target := []rune(a), []rune(b)
+	previous := make([]int, len(target)+1)
+	current := make([]int, len(target)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+	for i := range source {
+		current[0] = i + 1
+		for j := range target {
+			cost := 1
+			if source[i] == target[j] {
+				cost = 0
+			}
+			current[j+1] = min(previous[j+1]+1, current[j]+1, previous[j]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(target)]
+}
@@ -0,0 +1,154 @@
+package reload
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testSchema = Schema{Tools: []string{"git-summary", "literature-fetch"}}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	problems := Validate([]byte(`{
+  "prompts": [
+    {"name": "standup", "role": "assitant", "template": "Hi {{.to}}"},
+    {"name": "standup", "template": "{{.to", "arguments": [{"name": "to"}, {"name": "to", "required": "yes"}]},
+    {"description": "no name"},
+    {"name": "both", "template": "x", "template_file": "x.tmpl"}
+  ],
+  "timeouts": {
+    "default": "soon",
+    "tools": {"git-sumary": "15m", "literature-fetch": "30s", "literature-fetch": "-1s"}
+  },
+  "rate_limits": {
+    "pubmd": {"rate": 0, "burst": 1.5},
+    "pubmed": {"rate": 5, "burst": 5},
+    "PubMed": {"rate": 5, "burst": 5},
+    "www.example.org": {"rate": "fast"}
+  },
+  "rate_limit": {}
+}`), "", testSchema)
+
+	rendered := make([]string, len(problems))
+	for i, problem := range problems {
+		rendered[i] = problem.String()
+	}
+	assert.Equal(t, []string{
+		`rate_limit: unknown field (did you mean "rate_limits"?)`,
+		`prompts[0].role: must be one of user, assistant (did you mean "assistant"?)`,
+		`prompts[1].name: duplicate prompt name "standup", first defined at prompts[0]`,
+		`prompts[1].arguments[1].name: duplicate argument name "to", first defined at prompts[1].arguments[0]`,
+		`prompts[1].arguments[1].required: must be a boolean, not a string`,
+		`prompts[1].template: invalid template: template: standup:1: unclosed action`,
+		`prompts[2]: missing name`,
+		`prompts[2]: missing template or template_file`,
+		`prompts[3].template_file: set only one of template and template_file`,
+		`timeouts.default: invalid duration: time: invalid duration "soon"`,
+		`timeouts.tools.git-sumary: unknown tool (did you mean "git-summary"?)`,
+		`timeouts.tools.literature-fetch: duplicate tool, first set on line 10`,
+		`rate_limits.pubmd: unknown provider, use a provider or host name (did you mean "pubmed"?)`,
+		`rate_limits.pubmd.rate: must be greater than 0`,
+		`rate_limits.pubmd.burst: must be a whole number of at least 1`,
+		`rate_limits.PubMed: duplicate provider or host, first set on line 14`,
+		`rate_limits["www.example.org"].rate: must be a number, not a string`,
+		`rate_limits["www.example.org"]: missing burst`,
+	}, rendered)
+	assert.Equal(t, 18, problems[0].Line)
+	assert.Equal(t, 3, problems[1].Line)
+	assert.Equal(t, 15, problems[15].Line)
+}
+
+func TestValidate_ToolDefaults(t *testing.T) {
+	t.Parallel()
+	pdf := mcp.NewTool(
+		"markdown_to_pdf",
+		mcp.WithString("content", mcp.Required()),
+		mcp.WithString("page_size", mcp.Enum("A4", "Letter")),
+		mcp.WithNumber("margin"),
+		mcp.WithBoolean("toc"),
+	)
+	pdf.InputSchema.Properties["size"] = map[string]any{"type": "string", "deprecated": true}
+	schema := Schema{
+		Tools: []string{"markdown_to_pdf", "git-summary", "server-info"},
+		Parameters: map[string]mcp.ToolInputSchema{
+			"markdown_to_pdf": pdf.InputSchema,
+			"server-info":     mcp.NewTool("server-info").InputSchema,
+		},
+	}
+	problems := Validate([]byte(`{
+  "tool_defaults": {
+    "markdown_to_pdf": {"page_size": "A5", "margin": "2cm", "toc": true, "content": "x", "pagesize": "A4", "size": "A4"},
+    "git-summary": {"model": "gpt-4o"},
+    "git-sumary": {},
+    "server-info": {"verbose": true}
+  }
+}`), "", schema)
+	rendered := make([]string, len(problems))
+	for i, problem := range problems {
+		rendered[i] = problem.String()
+	}
+	assert.Equal(t, []string{
+		`tool_defaults.markdown_to_pdf.pagesize: unknown field (did you mean "page_size"?)`,
+		`tool_defaults.markdown_to_pdf.size: unknown field`,
+		`tool_defaults.markdown_to_pdf.page_size: must be one of A4, Letter (did you mean "A4"?)`,
+		`tool_defaults.markdown_to_pdf.margin: must be a number, not a string`,
+		`tool_defaults.markdown_to_pdf.content: set by each call, cannot have a default`,
+		`tool_defaults.git-sumary: unknown tool (did you mean "git-summary"?)`,
+		`tool_defaults.server-info: the tool has no parameters`,
+	}, rendered)
+}
+
+func TestValidate_Syntax(t *testing.T) {
+	t.Parallel()
+	problems := Validate([]byte("{\n  \"prompts\": [\n    {\"name\": \"x\",}\n  ]\n}"), "", Schema{})
+	require.Len(t, problems, 1)
+	assert.Equal(t, 3, problems[0].Line)
+	assert.Contains(t, problems[0].Message, "invalid JSON: invalid character")
+
+	problems = Validate([]byte(`{} {}`), "", Schema{})
+	require.Len(t, problems, 1)
+	assert.Equal(t, "invalid JSON: unexpected data after the top-level value", problems[0].Message)
+
+	problems = Validate([]byte(`[]`), "", Schema{})
+	require.Len(t, problems, 1)
+	assert.Equal(t, "must be an object, not an array", problems[0].Message)
+}
+
+func TestValidate_AcceptsAnyToolWithoutSchema(t *testing.T) {
+	t.Parallel()
+	assert.Empty(t, Validate([]byte(`{"timeouts": {"tools": {"custom-tool": "1m"}}}`), "", Schema{}))
+}
+
+func TestLoadConfig_ValidationError(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, "config.json")
+	writeFile(t, configPath, `{
+  "prompts": [{"name": "standup", "template_file": "missing.tmpl"}],
+  "timeouts": {"tools": {"git-summary": "15m"}}
+}`)
+
+	_, err := LoadConfig(configPath, testSchema)
+	var validationErr *ValidationError
+	require.True(t, errors.As(err, &validationErr))
+	require.Len(t, validationErr.Problems, 1)
+	assert.Equal(t, "prompts[0].template_file", validationErr.Problems[0].Path)
+	assert.Contains(t, err.Error(), "invalid config "+configPath+": line 2: prompts[0].template_file: cannot read template")
+
+	writeFile(t, filepath.Join(dir, "missing.tmpl"), "Yesterday on {{.project}}")
+	cfg, err := LoadConfig(configPath, testSchema)
+	require.NoError(t, err)
+	assert.Equal(t, "Yesterday on {{.project}}", cfg.Prompts[0].Template)
+}
+
+func TestClosest(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "git-summary", closest("Git-Sumary", []string{"git-summary", "repo-stats"}))
+	assert.Empty(t, closest("coverage", []string{"git-summary", "repo-stats"}))
+	assert.Equal(t, 3, editDistance("kitten", "sitting"))
+}
@@ -0,0 +1,271 @@
+// Package resources publishes artifacts generated by tools, such as PDFs,
+// rendered HTML and git summaries, as MCP resources that clients can list
+// and read.
+package resources
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Resource kinds, used as the host part of resource URIs.
+const (
+	KindPDF        = "pdf"
+	KindHTML       = "html"
+	KindGitSummary = "git-summary"
+	KindDigest     = "digest"
+	KindExport     = "export"
+	KindCalendar   = "calendar"
+)
+
+// Scheme is the URI scheme of published resources.
+const Scheme = "dcr"
+
+// defaultMaxEntries bounds how many resources are kept in memory.
+const defaultMaxEntries = 100
+
+// resourceRegistrar is implemented by anything resources can be published
+// to, such as the MCP server.
+type resourceRegistrar interface {
+	AddResource(resource mcp.Resource, handler server.ResourceHandlerFunc)
+	RemoveResource(uri string)
+}
+
+// PublishParams holds the parameters for publishing a resource.
+type PublishParams struct {
+	Kind        string `validate:"required,oneof=pdf html git-summary digest export calendar"`
+	Name        string `validate:"required"`
+	MIMEType    string `validate:"required"`
+	Description string
+	Data        []byte `validate:"required"`
+}
+
+// entry is a published resource together with its content.
+type entry struct {
+	resource mcp.Resource
+	data     []byte
+}
+
+// Catalog keeps the content of published resources in memory and serves
+// them through the registrar. When the catalog is full the oldest resource
+// is removed.
+type Catalog struct {
+	mu        sync.Mutex
+	registrar resourceRegistrar
+	entries   map[string]entry
+	order     []string
+	config    *Config
+}
+
+// Option represents a configuration option for Catalog.
+type Option func(*Config)
+
+// Config holds the configuration for the catalog.
+type Config struct {
+	maxEntries int
+	logger     *slog.Logger
+}
+
+// WithMaxEntries sets how many resources are kept before the oldest ones
+// are evicted.
+func WithMaxEntries(maxEntries int) Option {
+	return func(c *Config) {
+		c.maxEntries = maxEntries
+	}
+}
+
+// WithLogger sets the logger for the catalog.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewCatalog creates a Catalog publishing to the given registrar.
+func NewCatalog(registrar resourceRegistrar, opts ...Option) *Catalog {
+	cfg := &Config{
+		maxEntries: defaultMaxEntries,
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Catalog{
+		registrar: registrar,
+		entries:   make(map[string]entry),
+		config:    cfg,
+	}
+}
+
+// URI returns the resource URI for a kind and name, e.g. dcr://pdf/report.pdf.
+func URI(kind, name string) string {
+	return fmt.Sprintf("%s://%s/%s", Scheme, kind, url.PathEscape(name))
+}
+
+// ContentName derives a stable resource name from data, so publishing the
+// same content twice replaces the earlier resource.
+func ContentName(prefix, extension string, data []byte) string {
+	digest := sha256.Sum256(data)
+	return fmt.Sprintf("%s-%s%s", prefix, hex.EncodeToString(digest[:6]), extension)
+}
+
+// Publish stores the content and registers it as a resource. Publishing to
+// an existing URI replaces its content.
+func (c *Catalog) Publish(params PublishParams) (mcp.Resource, error) {
+	if err := validate.Struct(params); err != nil {
+		return mcp.Resource{}, fmt.Errorf("invalid resource parameters: %w", err)
+	}
+	uri := URI(params.Kind, params.Name)
+	resource := mcp.NewResource(
+		uri,
+		params.Name,
+		mcp.WithResourceDescription(params.Description),
+		mcp.WithMIMEType(params.MIMEType),
+	)
+
+	c.mu.Lock()
+	if _, exists := c.entries[uri]; !exists {
+		c.order = append(c.order, uri)
+	}
+	c.entries[uri] = entry{resource: resource, data: params.Data}
+	evicted := c.evict()
+	c.mu.Unlock()
+
+	for _, evictedURI := range evicted {
+		c.registrar.RemoveResource(evictedURI)
+	}
+	c.registrar.AddResource(resource, c.read)
+	c.config.logger.Debug("published resource", "uri", uri, "evicted", len(evicted))
+	return resource, nil
+}
+
+// evict removes the oldest entries while there are more than the catalog
+// keeps and returns their URIs. The caller holds c.mu.
+func (c *Catalog) evict() []string {
+	var evicted []string
+	for len(c.order) > c.config.maxEntries {
+		evicted = append(evicted, c.order[0])
+		delete(c.entries, c.order[0])
+		c.order = c.order[1:]
+	}
+	return evicted
+}
+
+// Link returns a tool result content block pointing at a published resource.
+func Link(resource mcp.Resource) mcp.ResourceLink {
+	return mcp.NewResourceLink(
+		resource.URI,
+		resource.Name,
+		resource.Description,
+		resource.MIMEType,
+	)
+}
+
+// read serves the content of a published resource.
+func (c *Catalog) read(
+	_ context.Context,
+	request mcp.ReadResourceRequest,
+) ([]mcp.ResourceContents, error) {
+	c.mu.Lock()
+	published, ok := c.entries[request.Params.URI]
+	c.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("resource %s not found", request.Params.URI)
+	}
+	if isText(published.resource.MIMEType) {
+		return []mcp.ResourceContents{mcp.TextResourceContents{
+			URI:      published.resource.URI,
+			MIMEType: published.resource.MIMEType,
+			Text:     string(published.data),
+		}}, nil
+	}
+	return []mcp.ResourceContents{mcp.BlobResourceContents{
+		URI:      published.resource.URI,
+		MIMEType: published.resource.MIMEType,
+		Blob:     base64.StdEncoding.EncodeToString(published.data),
+	}}, nil
+}
+
+// isText reports whether content of the MIME type can be served as text.
+func isText(mimeType string) bool {
+	return strings.HasPrefix(mimeType, "text/") ||
+		strings.HasPrefix(mimeType, "application/json")
+}
+
+// Record is a published resource together with its content, as kept in a
+// session snapshot.
+type Record struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MIMEType    string `json:"mime_type"`
+	Data        []byte `json:"data"`
+}
+
+// Export returns the published resources, oldest first.
+func (c *Catalog) Export() []Record {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	records := make([]Record, 0, len(c.order))
+	for _, uri := range c.order {
+		published := c.entries[uri]
+		records = append(records, Record{
+			URI:         uri,
+			Name:        published.resource.Name,
+			Description: published.resource.Description,
+			MIMEType:    published.resource.MIMEType,
+			Data:        published.data,
+		})
+	}
+	return records
+}
+
+// Import publishes resources exported from another catalog under their
+// original URIs, so links in replayed results resolve. Like Publish, it
+// evicts the oldest resources beyond the catalog's limit, which may be
+// imported ones.
+func (c *Catalog) Import(records []Record) {
+	c.mu.Lock()
+	for _, record := range records {
+		resource := mcp.NewResource(
+			record.URI,
+			record.Name,
+			mcp.WithResourceDescription(record.Description),
+			mcp.WithMIMEType(record.MIMEType),
+		)
+		if _, exists := c.entries[record.URI]; !exists {
+			c.order = append(c.order, record.URI)
+		}
+		c.entries[record.URI] = entry{resource: resource, data: record.Data}
+	}
+	evicted := c.evict()
+	imported := make([]mcp.Resource, 0, len(records))
+	for _, record := range records {
+		if published, ok := c.entries[record.URI]; ok {
+			imported = append(imported, published.resource)
+		}
+	}
+	c.mu.Unlock()
+
+	for _, evictedURI := range evicted {
+		c.registrar.RemoveResource(evictedURI)
+	}
+	for _, resource := range imported {
+		c.registrar.AddResource(resource, c.read)
+	}
+	c.config.logger.Debug("imported resources", "resources", len(imported), "evicted", len(evicted))
+}
@@ -0,0 +1,132 @@
+package resources
+
+import (
+	"context"
+	"encoding/base64"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeRegistrar struct {
+	handlers map[string]server.ResourceHandlerFunc
+	removed  []string
+}
+
+func (fr *fakeRegistrar) AddResource(resource mcp.Resource, handler server.ResourceHandlerFunc) {
+	fr.handlers[resource.URI] = handler
+}
+
+func (fr *fakeRegistrar) RemoveResource(uri string) {
+	delete(fr.handlers, uri)
+	fr.removed = append(fr.removed, uri)
+}
+
+func readResource(
+	t *testing.T,
+	registrar *fakeRegistrar,
+	uri string,
+) mcp.ResourceContents {
+	t.Helper()
+	handler, ok := registrar.handlers[uri]
+	require.True(t, ok, "resource %s is not registered", uri)
+	request := mcp.ReadResourceRequest{}
+	request.Params.URI = uri
+	contents, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	require.Len(t, contents, 1)
+	return contents[0]
+}
+
+func TestCatalog_Publish(t *testing.T) {
+	t.Parallel()
+	registrar := &fakeRegistrar{handlers: make(map[string]server.ResourceHandlerFunc)}
+	catalog := NewCatalog(registrar)
+
+	pdfResource, err := catalog.Publish(PublishParams{
+		Kind:     KindPDF,
+		Name:     "my report.pdf",
+		MIMEType: "application/pdf",
+		Data:     []byte("%PDF-test"),
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "dcr://pdf/my%20report.pdf", pdfResource.URI)
+	blob, ok := readResource(t, registrar, pdfResource.URI).(mcp.BlobResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, base64.StdEncoding.EncodeToString([]byte("%PDF-test")), blob.Blob)
+
+	htmlResource, err := catalog.Publish(PublishParams{
+		Kind:     KindHTML,
+		Name:     "page.html",
+		MIMEType: "text/html",
+		Data:     []byte("<h1>Hi</h1>"),
+	})
+	require.NoError(t, err)
+	text, ok := readResource(t, registrar, htmlResource.URI).(mcp.TextResourceContents)
+	require.True(t, ok)
+	assert.Equal(t, "<h1>Hi</h1>", text.Text)
+
+	link := Link(htmlResource)
+	assert.Equal(t, htmlResource.URI, link.URI)
+	assert.Equal(t, "text/html", link.MIMEType)
+}
+
+func TestCatalog_Eviction(t *testing.T) {
+	t.Parallel()
+	registrar := &fakeRegistrar{handlers: make(map[string]server.ResourceHandlerFunc)}
+	catalog := NewCatalog(registrar, WithMaxEntries(2))
+
+	for _, name := range []string{"a.md", "b.md", "a.md", "c.md"} {
+		_, err := catalog.Publish(PublishParams{
+			Kind:     KindGitSummary,
+			Name:     name,
+			MIMEType: "text/markdown",
+			Data:     []byte(name),
+		})
+		require.NoError(t, err)
+	}
+	assert.Equal(t, []string{URI(KindGitSummary, "a.md")}, registrar.removed)
+	assert.Len(t, registrar.handlers, 2)
+}
+
+func TestCatalog_ImportEviction(t *testing.T) {
+	t.Parallel()
+	registrar := &fakeRegistrar{handlers: make(map[string]server.ResourceHandlerFunc)}
+	catalog := NewCatalog(registrar, WithMaxEntries(2))
+	_, err := catalog.Publish(PublishParams{Kind: KindGitSummary, Name: "a.md", MIMEType: "text/markdown", Data: []byte("a")})
+	require.NoError(t, err)
+
+	records := make([]Record, 0, 3)
+	for _, name := range []string{"x.md", "y.md", "z.md"} {
+		records = append(records, Record{URI: URI(KindGitSummary, name), Name: name, MIMEType: "text/markdown"})
+	}
+	catalog.Import(records)
+	assert.Equal(t, []string{URI(KindGitSummary, "a.md"), URI(KindGitSummary, "x.md")}, registrar.removed)
+	assert.Len(t, registrar.handlers, 2)
+	assert.Len(t, catalog.Export(), 2)
+}
+
+func TestCatalog_PublishValidation(t *testing.T) {
+	t.Parallel()
+	registrar := &fakeRegistrar{handlers: make(map[string]server.ResourceHandlerFunc)}
+	catalog := NewCatalog(registrar)
+
+	_, err := catalog.Publish(PublishParams{
+		Kind:     "video",
+		Name:     "clip.mp4",
+		MIMEType: "video/mp4",
+		Data:     []byte("data"),
+	})
+	require.Error(t, err)
+}
+
+func TestContentName(t *testing.T) {
+	t.Parallel()
+	first := ContentName("document", ".html", []byte("same"))
+	assert.Equal(t, first, ContentName("document", ".html", []byte("same")))
+	assert.NotEqual(t, first, ContentName("document", ".html", []byte("other")))
+	assert.Regexp(t, `^document-[0-9a-f]{12}\.html$`, first)
+}
@@ -0,0 +1,247 @@
+// Package retry repeats outbound API calls that fail with transient
+// errors, such as rate limiting or server errors, backing off
+// exponentially between attempts and honoring the Retry-After time a
+// provider asks for.
+package retry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Policy sets how often and how patiently a call is retried.
+type Policy struct {
+	// MaxAttempts is the number of calls made, including the first; 1
+	// disables retries.
+	MaxAttempts int `validate:"gte=1"`
+	// InitialDelay is the wait before the first retry, doubled for every
+	// further retry.
+	InitialDelay time.Duration `validate:"gt=0"`
+	// MaxDelay caps the doubled wait. A longer Retry-After is still
+	// honored.
+	MaxDelay time.Duration `validate:"gtefield=InitialDelay"`
+}
+
+// DefaultPolicy retries twice, after about half a second and a second.
+var DefaultPolicy = Policy{
+	MaxAttempts:  3,
+	InitialDelay: 500 * time.Millisecond,
+	MaxDelay:     10 * time.Second,
+}
+
+// Validate checks that the policy makes at least one attempt and waits a
+// positive time between them.
+func (p Policy) Validate() error {
+	if err := validate.Struct(p); err != nil {
+		return fmt.Errorf("invalid retry policy: %w", err)
+	}
+	return nil
+}
+
+// StatusError is an HTTP response worth classifying as transient or not.
+type StatusError struct {
+	StatusCode int
+	// RetryAfter is the wait the response asked for, zero when it did not.
+	RetryAfter time.Duration
+}
+
+// Error implements the error interface.
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// CheckResponse returns a StatusError for responses that rate limit the
+// caller or report a server error, and nil for any other response.
+func CheckResponse(resp *http.Response) error {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode < http.StatusInternalServerError {
+		return nil
+	}
+	return &StatusError{
+		StatusCode: resp.StatusCode,
+		RetryAfter: ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+	}
+}
+
+// ParseRetryAfter reads a Retry-After header, given in seconds or as an
+// HTTP date. It returns zero for a missing, malformed or past value.
+func ParseRetryAfter(value string, now time.Time) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(date.Sub(now), 0)
+	}
+	return 0
+}
+
+var (
+	// transientStatus finds the status codes of transient failures in the
+	// messages of clients that do not expose the response.
+	transientStatus = regexp.MustCompile(`\b(429|500|502|503|504)\b`)
+	// retryAfterText finds a Retry-After time given in seconds in such
+	// messages.
+	retryAfterText = regexp.MustCompile(`(?i)retry[- ]after:?\s*(\d+)`)
+)
+
+// transientMessages are parts of the messages of transient failures.
+var transientMessages = []string{
+	"too many requests",
+	"service unavailable",
+	"bad gateway",
+	"gateway timeout",
+	"internal server error",
+	"connection reset",
+	"connection refused",
+	"client.timeout exceeded",
+	"unexpected eof",
+}
+
+// Transient reports whether err is worth retrying, together with the wait
+// the provider asked for. Context errors are never transient: the caller
+// gave up.
+func Transient(err error) (time.Duration, bool) {
+	if err == nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return 0, false
+	}
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		transient := statusErr.StatusCode == http.StatusTooManyRequests ||
+			statusErr.StatusCode >= http.StatusInternalServerError
+		return statusErr.RetryAfter, transient
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return 0, true
+	}
+	message := strings.ToLower(err.Error())
+	var retryAfter time.Duration
+	if match := retryAfterText.FindStringSubmatch(message); match != nil {
+		seconds, _ := strconv.Atoi(match[1])
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	if transientStatus.MatchString(message) {
+		return retryAfter, true
+	}
+	for _, part := range transientMessages {
+		if strings.Contains(message, part) {
+			return retryAfter, true
+		}
+	}
+	return 0, false
+}
+
+// Error reports a call that still failed with a transient error when the
+// policy gave up on it.
+type Error struct {
+	// Attempts is the number of calls made.
+	Attempts int
+	// RetryAfter is the wait the last response asked for, if any.
+	RetryAfter time.Duration
+	// Err is the last failure.
+	Err error
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.Attempts, e.Err)
+}
+
+// Unwrap returns the last failure.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Do calls call until it succeeds, fails with an error that is not
+// transient, or the attempts run out, logging each retry. Errors that are
+// not transient are returned as they are; transient ones that remain are
+// returned as an *Error. It stops early when ctx is done or its deadline
+// would pass before the next attempt.
+func (p Policy) Do(ctx context.Context, logger *slog.Logger, call func() error) error {
+	for attempt := 1; ; attempt++ {
+		err := call()
+		retryAfter, transient := Transient(err)
+		if !transient {
+			return err
+		}
+		failure := &Error{Attempts: attempt, RetryAfter: retryAfter, Err: err}
+		if attempt >= p.MaxAttempts {
+			return failure
+		}
+		delay := p.delay(attempt, retryAfter)
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return failure
+		}
+		logger.Warn("retrying after transient error", "attempt", attempt, "delay", delay, "error", err)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return failure
+		}
+	}
+}
+
+// delay returns the wait after the given failed attempt: the exponential
+// backoff with jitter, so concurrent callers do not retry in step, or the
+// Retry-After time when the provider asked for longer.
+func (p Policy) delay(attempt int, retryAfter time.Duration) time.Duration {
+	backoff := p.InitialDelay
+	for range attempt - 1 {
+		if backoff >= p.MaxDelay {
+			break
+		}
+		backoff *= 2
+	}
+	backoff = min(backoff, p.MaxDelay)
+	//nolint:gosec // jitter does not need a secure random source
+	jittered := backoff/2 + rand.N(backoff/2+1)
+	return max(jittered, retryAfter)
+}
+
+// shared is the process-wide policy used by Do.
+var shared atomic.Pointer[Policy]
+
+//nolint:gochecknoinits // the shared policy starts as the default policy
+func init() {
+	policy := DefaultPolicy
+	shared.Store(&policy)
+}
+
+// Configure replaces the shared policy.
+func Configure(policy Policy) error {
+	if err := policy.Validate(); err != nil {
+		return err
+	}
+	shared.Store(&policy)
+	return nil
+}
+
+// Current returns the shared policy.
+func Current() Policy {
+	return *shared.Load()
+}
+
+// Do retries call under the shared policy.
+func Do(ctx context.Context, logger *slog.Logger, call func() error) error {
+	return shared.Load().Do(ctx, logger, call)
+}
@@ -0,0 +1,127 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// quickPolicy retries without noticeable waits.
+var quickPolicy = Policy{MaxAttempts: 3, InitialDelay: time.Millisecond, MaxDelay: 2 * time.Millisecond}
+
+// discard is a logger that drops the retry warnings.
+var discard = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+func TestPolicy_DoRetriesTransientErrors(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	err := quickPolicy.Do(context.Background(), discard, func() error {
+		calls++
+		if calls < 3 {
+			return &StatusError{StatusCode: http.StatusServiceUnavailable}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPolicy_DoGivesUp(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	err := quickPolicy.Do(context.Background(), discard, func() error {
+		calls++
+		return errors.New("429 Too Many Requests")
+	})
+	var retryErr *Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, 3, retryErr.Attempts)
+	assert.Equal(t, 3, calls)
+
+	calls = 0
+	notFound := errors.New("article not found")
+	err = quickPolicy.Do(context.Background(), discard, func() error {
+		calls++
+		return notFound
+	})
+	require.ErrorIs(t, err, notFound)
+	assert.NotErrorAs(t, err, &retryErr, "permanent errors are returned as they are")
+	assert.Equal(t, 1, calls)
+}
+
+func TestPolicy_DoStopsBeforeDeadline(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	calls := 0
+	err := quickPolicy.Do(ctx, discard, func() error {
+		calls++
+		return &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: time.Minute}
+	})
+	var retryErr *Error
+	require.ErrorAs(t, err, &retryErr)
+	assert.Equal(t, time.Minute, retryErr.RetryAfter)
+	assert.Equal(t, 1, calls, "a Retry-After past the deadline is not waited for")
+}
+
+func TestPolicy_Delay(t *testing.T) {
+	t.Parallel()
+	policy := Policy{MaxAttempts: 5, InitialDelay: time.Second, MaxDelay: 4 * time.Second}
+	for attempt, backoff := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 4 * time.Second} {
+		delay := policy.delay(attempt, 0)
+		assert.GreaterOrEqual(t, delay, backoff/2, "attempt %d", attempt)
+		assert.LessOrEqual(t, delay, backoff, "attempt %d", attempt)
+	}
+	assert.Equal(t, 30*time.Second, policy.delay(1, 30*time.Second), "a longer Retry-After is honored")
+}
+
+func TestTransient(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		err        error
+		retryAfter time.Duration
+		transient  bool
+	}{
+		{
+			err:        &StatusError{StatusCode: http.StatusTooManyRequests, RetryAfter: 2 * time.Second},
+			retryAfter: 2 * time.Second,
+			transient:  true,
+		},
+		{err: &StatusError{StatusCode: http.StatusBadGateway}, transient: true},
+		{err: &StatusError{StatusCode: http.StatusBadRequest}},
+		{err: errors.New("efetch: unexpected status 503 Service Unavailable"), transient: true},
+		{err: errors.New("rate limited, retry after 5 seconds: status 429"), retryAfter: 5 * time.Second, transient: true},
+		{err: errors.New("article not found for PMID 25029504")},
+		{err: context.DeadlineExceeded},
+		{err: nil},
+	}
+	for _, tt := range tests {
+		retryAfter, transient := Transient(tt.err)
+		assert.Equal(t, tt.transient, transient, "%v", tt.err)
+		assert.Equal(t, tt.retryAfter, retryAfter, "%v", tt.err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2025, 6, 1, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 7*time.Second, ParseRetryAfter("7", now))
+	assert.Equal(t, 90*time.Second, ParseRetryAfter("Sun, 01 Jun 2025 12:01:30 GMT", now))
+	assert.Zero(t, ParseRetryAfter("Sun, 01 Jun 2025 11:00:00 GMT", now), "past dates do not wait")
+	assert.Zero(t, ParseRetryAfter("soon", now))
+	assert.Zero(t, ParseRetryAfter("", now))
+}
+
+func TestPolicy_Validate(t *testing.T) {
+	t.Parallel()
+	require.NoError(t, DefaultPolicy.Validate())
+	require.Error(t, Policy{MaxAttempts: 0, InitialDelay: time.Second, MaxDelay: time.Second}.Validate())
+	require.Error(t, Policy{MaxAttempts: 3, InitialDelay: time.Second, MaxDelay: time.Millisecond}.Validate())
+}
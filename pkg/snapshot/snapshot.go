@@ -0,0 +1,320 @@
+// Package snapshot dumps the state of a session, its upload handles, the
+// kept results of keyed calls, published resources and the calls still
+// running, to a file, and restores it in another server. Restoring a
+// snapshot taken when a user hit a problem with a composite workflow lets
+// a developer repeat the workflow's later calls against the same state.
+//
+// Snapshots hold uploaded data and call arguments as sent by the client,
+// so they are meant for development environments and must be handled like
+// the user data they contain.
+package snapshot
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// FormatVersion is the version of the snapshot file format; Load rejects
+// files of other versions.
+const FormatVersion = 1
+
+// ErrUnsupportedVersion is returned by Load for snapshots written in
+// another format version.
+var ErrUnsupportedVersion = errors.New("unsupported snapshot version")
+
+// Snapshot is the state of a session at one point in time.
+type Snapshot struct {
+	Version       int       `json:"version"`
+	ServerVersion string    `json:"server_version,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+	// Uploads are the upload handles, with their data.
+	Uploads []upload.Record `json:"uploads"`
+	// Results are the last results of keyed calls, which a repeat of the
+	// call replays.
+	Results []idempotency.Record `json:"results"`
+	// Resources are the published artifacts results link to.
+	Resources []resources.Record `json:"resources"`
+	// Pending are the calls that were running.
+	Pending []Call `json:"pending"`
+}
+
+// Call is a tool call that was running when a snapshot was taken.
+type Call struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"`
+	StartedAt time.Time      `json:"started_at"`
+}
+
+// Manager takes snapshots of the session state it is given and restores
+// them.
+type Manager struct {
+	mu      sync.Mutex
+	pending map[uint64]Call
+	nextID  atomic.Uint64
+	// saves numbers the snapshot files, which keeps their names unique.
+	saves  atomic.Uint64
+	config *Config
+}
+
+// Option configures a Manager.
+type Option func(*Config)
+
+// Config holds the configuration of a Manager.
+type Config struct {
+	uploads   *upload.Store
+	results   *idempotency.Store
+	resources *resources.Catalog
+	dir       string
+	token     string
+	version   string
+	now       func() time.Time
+	logger    *slog.Logger
+}
+
+// WithUploads includes the uploads of store in snapshots.
+func WithUploads(store *upload.Store) Option {
+	return func(c *Config) {
+		c.uploads = store
+	}
+}
+
+// WithResults includes the kept results of store in snapshots.
+func WithResults(store *idempotency.Store) Option {
+	return func(c *Config) {
+		c.results = store
+	}
+}
+
+// WithResources includes the resources published in catalog in snapshots.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(c *Config) {
+		c.resources = catalog
+	}
+}
+
+// WithDir sets the directory Save writes snapshots to.
+func WithDir(dir string) Option {
+	return func(c *Config) {
+		c.dir = dir
+	}
+}
+
+// WithToken sets the bearer token the admin handler requires. Without a
+// token the handler refuses every request.
+func WithToken(token string) Option {
+	return func(c *Config) {
+		c.token = token
+	}
+}
+
+// WithVersion sets the server version recorded in snapshots.
+func WithVersion(version string) Option {
+	return func(c *Config) {
+		c.version = version
+	}
+}
+
+// WithLogger sets the logger for the manager.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewManager creates a Manager for the state given with the options.
+func NewManager(opts ...Option) *Manager {
+	cfg := &Config{
+		dir:    ".",
+		now:    time.Now,
+		logger: slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Manager{pending: make(map[uint64]Call), config: cfg}
+}
+
+// Middleware returns a middleware that tracks running calls, so snapshots
+// list them as pending.
+func (m *Manager) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			id := m.nextID.Add(1)
+			m.mu.Lock()
+			m.pending[id] = Call{
+				Tool:      request.Params.Name,
+				Arguments: maps.Clone(request.GetArguments()),
+				StartedAt: m.config.now(),
+			}
+			m.mu.Unlock()
+			defer func() {
+				m.mu.Lock()
+				delete(m.pending, id)
+				m.mu.Unlock()
+			}()
+			return next(ctx, request)
+		}
+	}
+}
+
+// Take returns a snapshot of the current state.
+func (m *Manager) Take() Snapshot {
+	snapshot := Snapshot{
+		Version:       FormatVersion,
+		ServerVersion: m.config.version,
+		CreatedAt:     m.config.now().UTC(),
+	}
+	if m.config.uploads != nil {
+		snapshot.Uploads = m.config.uploads.Export()
+	}
+	if m.config.results != nil {
+		snapshot.Results = m.config.results.Export()
+	}
+	if m.config.resources != nil {
+		snapshot.Resources = m.config.resources.Export()
+	}
+	m.mu.Lock()
+	snapshot.Pending = slices.SortedFunc(maps.Values(m.pending), func(a, b Call) int {
+		return a.StartedAt.Compare(b.StartedAt)
+	})
+	m.mu.Unlock()
+	return snapshot
+}
+
+// Save takes a snapshot and writes it to a new file in the snapshot
+// directory, returning the file's path.
+func (m *Manager) Save() (string, error) {
+	snapshot := m.Take()
+	if err := os.MkdirAll(m.config.dir, 0o700); err != nil {
+		return "", fmt.Errorf("error creating snapshot directory: %w", err)
+	}
+	name := "snapshot-" + snapshot.CreatedAt.Format("20060102T150405.000Z") +
+		"-" + strconv.FormatUint(m.saves.Add(1), 10) + ".json"
+	path := filepath.Join(m.config.dir, name)
+	if err := Write(path, snapshot); err != nil {
+		return "", err
+	}
+	m.config.logger.Info(
+		"saved session snapshot",
+		"path", path,
+		"uploads", len(snapshot.Uploads),
+		"results", len(snapshot.Results),
+		"resources", len(snapshot.Resources),
+		"pending", len(snapshot.Pending),
+	)
+	return path, nil
+}
+
+// Restore loads the state of snapshot into the stores of the manager.
+// Pending calls cannot be resumed; they are logged with their arguments,
+// so they can be repeated by hand.
+func (m *Manager) Restore(snapshot Snapshot) {
+	if m.config.uploads != nil {
+		m.config.uploads.Import(snapshot.Uploads)
+	}
+	if m.config.results != nil {
+		m.config.results.Import(snapshot.Results)
+	}
+	if m.config.resources != nil {
+		m.config.resources.Import(snapshot.Resources)
+	}
+	for _, call := range snapshot.Pending {
+		m.config.logger.Warn(
+			"pending call of snapshot not resumed",
+			"tool", call.Tool,
+			"arguments", call.Arguments,
+			"started_at", call.StartedAt,
+		)
+	}
+	m.config.logger.Info(
+		"restored session snapshot",
+		"created_at", snapshot.CreatedAt,
+		"server_version", snapshot.ServerVersion,
+		"uploads", len(snapshot.Uploads),
+		"results", len(snapshot.Results),
+		"resources", len(snapshot.Resources),
+	)
+}
+
+// Write writes snapshot to path, readable by the owner only.
+func Write(path string, snapshot Snapshot) error {
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("error encoding snapshot: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o600); err != nil {
+		return fmt.Errorf("error writing snapshot: %w", err)
+	}
+	return nil
+}
+
+// Load reads a snapshot written by Write.
+func Load(path string) (Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Snapshot{}, fmt.Errorf("error reading snapshot: %w", err)
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return Snapshot{}, fmt.Errorf("error decoding snapshot %s: %w", path, err)
+	}
+	if snapshot.Version != FormatVersion {
+		return Snapshot{}, fmt.Errorf("snapshot %s: %w %d", path, ErrUnsupportedVersion, snapshot.Version)
+	}
+	return snapshot, nil
+}
+
+// Saved describes a snapshot written by the admin handler.
+type Saved struct {
+	Path string `json:"path"`
+}
+
+// Handler returns the admin handler that saves a snapshot on every
+// request bearing the manager's token and answers with the path of its
+// file.
+func (m *Manager) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !m.authorized(r) {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		path, err := m.Save()
+		if err != nil {
+			m.config.logger.Error("error saving snapshot", "error", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(Saved{Path: path}); err != nil {
+			m.config.logger.Error("error writing snapshot response", "error", err)
+		}
+	})
+}
+
+// authorized reports whether r bears the manager's token.
+func (m *Manager) authorized(r *http.Request) bool {
+	token, found := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return found && m.config.token != "" &&
+		subtle.ConstantTimeCompare([]byte(token), []byte(m.config.token)) == 1
+}
@@ -0,0 +1,157 @@
+package snapshot
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// session is the state of a test session.
+type session struct {
+	uploads   *upload.Store
+	catalog   *resources.Catalog
+	manager   *Manager
+	directory string
+}
+
+// newSession creates empty stores and a manager snapshotting them to a
+// temporary directory.
+func newSession(t *testing.T) session {
+	t.Helper()
+	s := session{
+		uploads:   upload.NewStore(),
+		catalog:   resources.NewCatalog(server.NewMCPServer("test", "1.0.0")),
+		directory: t.TempDir(),
+	}
+	s.manager = NewManager(
+		WithUploads(s.uploads),
+		WithResults(idempotency.NewStore()),
+		WithResources(s.catalog),
+		WithDir(s.directory),
+		WithVersion("v1.2.3"),
+		WithToken("s3cret"),
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+	)
+	return s
+}
+
+func TestManager_SaveAndRestore(t *testing.T) {
+	t.Parallel()
+	original := newSession(t)
+	info, err := original.uploads.Begin(upload.BeginParams{Name: "notes.md", ContentType: "text/markdown"})
+	require.NoError(t, err)
+	_, err = original.uploads.Append(upload.AppendParams{ID: info.ID, Data: []byte("# Notes\n")})
+	require.NoError(t, err)
+	_, err = original.uploads.Complete(info.ID, "")
+	require.NoError(t, err)
+	published, err := original.catalog.Publish(resources.PublishParams{
+		Kind:     resources.KindPDF,
+		Name:     "report.pdf",
+		MIMEType: "application/pdf",
+		Data:     []byte("%PDF-test"),
+	})
+	require.NoError(t, err)
+
+	path, err := original.manager.Save()
+	require.NoError(t, err)
+	assert.Equal(t, original.directory, filepath.Dir(path))
+	stat, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), stat.Mode().Perm())
+
+	snapshot, err := Load(path)
+	require.NoError(t, err)
+	assert.Equal(t, "v1.2.3", snapshot.ServerVersion)
+	restored := newSession(t)
+	restored.manager.Restore(snapshot)
+
+	text, err := restored.uploads.Text(info.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\n", text, "the upload keeps its handle")
+	records := restored.catalog.Export()
+	require.Len(t, records, 1)
+	assert.Equal(t, published.URI, records[0].URI)
+	assert.Equal(t, []byte("%PDF-test"), records[0].Data)
+}
+
+func TestManager_Pending(t *testing.T) {
+	t.Parallel()
+	manager := newSession(t).manager
+	started := make(chan struct{})
+	release := make(chan struct{})
+	handler := manager.Middleware()(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		close(started)
+		<-release
+		return mcp.NewToolResultText("done"), nil
+	})
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "git-summary"
+	request.Params.Arguments = map[string]any{"repo": "dictybase/dcr-mcp"}
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_, _ = handler(context.Background(), request)
+	}()
+
+	<-started
+	pending := manager.Take().Pending
+	require.Len(t, pending, 1)
+	assert.Equal(t, "git-summary", pending[0].Tool)
+	assert.Equal(t, map[string]any{"repo": "dictybase/dcr-mcp"}, pending[0].Arguments)
+	close(release)
+	<-done
+	assert.Empty(t, manager.Take().Pending, "finished calls are no longer pending")
+}
+
+func TestManager_Handler(t *testing.T) {
+	t.Parallel()
+	session := newSession(t)
+	for _, authorization := range []string{"", "Bearer wrong", "s3cret"} {
+		request := httptest.NewRequest(http.MethodPost, "/admin/snapshot", nil)
+		request.Header.Set("Authorization", authorization)
+		recorder := httptest.NewRecorder()
+		session.manager.Handler().ServeHTTP(recorder, request)
+		assert.Equal(t, http.StatusUnauthorized, recorder.Code, authorization)
+	}
+	entries, err := os.ReadDir(session.directory)
+	require.NoError(t, err)
+	assert.Empty(t, entries, "unauthorized requests save nothing")
+
+	request := httptest.NewRequest(http.MethodPost, "/admin/snapshot", nil)
+	request.Header.Set("Authorization", "Bearer s3cret")
+	recorder := httptest.NewRecorder()
+	session.manager.Handler().ServeHTTP(recorder, request)
+	require.Equal(t, http.StatusOK, recorder.Code)
+	var saved Saved
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &saved))
+	_, err = Load(saved.Path)
+	require.NoError(t, err)
+}
+
+func TestLoad_Errors(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "snapshot.json")
+	require.NoError(t, Write(path, Snapshot{Version: FormatVersion + 1}))
+	_, err := Load(path)
+	require.ErrorIs(t, err, ErrUnsupportedVersion)
+
+	require.NoError(t, os.WriteFile(path, []byte("{"), 0o600))
+	_, err = Load(path)
+	require.Error(t, err)
+	_, err = Load(filepath.Join(t.TempDir(), "missing.json"))
+	require.Error(t, err)
+}
@@ -0,0 +1,294 @@
+// Package status collects the self-diagnostics reported by the
+// server-status tool: uptime, registered tools, configured limits, whether
+// the upstream providers can be reached and the provider compliance report.
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+// Overall health reported by Report.Status.
+const (
+	StatusOK       = "ok"
+	StatusDegraded = "degraded"
+)
+
+// ProviderOpenRouter names the LLM gateway used by git-summary.
+const ProviderOpenRouter = "openrouter"
+
+const defaultProbeTimeout = 5 * time.Second
+
+// Probe is an upstream endpoint checked for reachability.
+type Probe struct {
+	Name string
+	URL  string
+}
+
+// DefaultProbes check the literature providers and the LLM gateway with
+// cheap, unauthenticated requests.
+var DefaultProbes = []Probe{
+	{
+		Name: metrics.ServiceEuropePMC,
+		URL:  "https://www.ebi.ac.uk/europepmc/webservices/rest/search?query=dictyostelium&format=json&pageSize=1",
+	},
+	{
+		Name: metrics.ServicePubMed,
+		URL:  "https://eutils.ncbi.nlm.nih.gov/entrez/eutils/einfo.fcgi?retmode=json",
+	},
+	{
+		Name: ProviderOpenRouter,
+		URL:  "https://openrouter.ai/api/v1/models",
+	},
+}
+
+// Limits are the operational limits the server was started with.
+type Limits struct {
+	DefaultTimeout time.Duration
+	ToolTimeouts   map[string]time.Duration
+	MaxHeavyTools  int
+	HeavyTools     []string
+	RateLimits     map[string]ratelimit.Limit
+	UploadMaxBytes int
+	UploadTTL      time.Duration
+}
+
+// MarshalJSON renders durations as Go duration strings and rate limits in
+// the rate[/burst] form accepted by --rate-limits.
+func (l Limits) MarshalJSON() ([]byte, error) {
+	toolTimeouts := make(map[string]string, len(l.ToolTimeouts))
+	for name, toolTimeout := range l.ToolTimeouts {
+		toolTimeouts[name] = toolTimeout.String()
+	}
+	rateLimits := make(map[string]string, len(l.RateLimits))
+	for name, limit := range l.RateLimits {
+		rateLimits[name] = limit.String()
+	}
+	return json.Marshal(struct {
+		DefaultTimeout string            `json:"default_timeout"`
+		ToolTimeouts   map[string]string `json:"tool_timeouts,omitempty"`
+		MaxHeavyTools  int               `json:"max_heavy_tools"`
+		HeavyTools     []string          `json:"heavy_tools,omitempty"`
+		RateLimits     map[string]string `json:"rate_limits,omitempty"`
+		UploadMaxBytes int               `json:"upload_max_bytes"`
+		UploadTTL      string            `json:"upload_ttl"`
+	}{
+		DefaultTimeout: l.DefaultTimeout.String(),
+		ToolTimeouts:   toolTimeouts,
+		MaxHeavyTools:  l.MaxHeavyTools,
+		HeavyTools:     l.HeavyTools,
+		RateLimits:     rateLimits,
+		UploadMaxBytes: l.UploadMaxBytes,
+		UploadTTL:      l.UploadTTL.String(),
+	})
+}
+
+// ProviderStatus is the outcome of one probe. A provider counts as
+// reachable when it answers with anything but a server error, so an
+// authentication failure still shows the network path works.
+type ProviderStatus struct {
+	Name       string `json:"name"`
+	URL        string `json:"url"`
+	Reachable  bool   `json:"reachable"`
+	StatusCode int    `json:"status_code,omitempty"`
+	Latency    string `json:"latency,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// Report is a snapshot of the server's health.
+type Report struct {
+	// Status is degraded when a probed provider is unreachable.
+	Status    string           `json:"status"`
+	Version   string           `json:"version,omitempty"`
+	StartedAt time.Time        `json:"started_at"`
+	Uptime    string           `json:"uptime"`
+	Tools     []string         `json:"tools"`
+	Providers []ProviderStatus `json:"providers,omitempty"`
+	Limits    Limits           `json:"limits"`
+	// Compliance reports the requests sent to each provider, when asked
+	// for.
+	Compliance []ratelimit.ProviderUsage `json:"compliance,omitempty"`
+}
+
+// Monitor assembles status reports for the running server.
+type Monitor struct {
+	config *Config
+	mu     sync.RWMutex
+	tools  []string
+}
+
+// Option represents a configuration option for Monitor.
+type Option func(*Config)
+
+// Config holds the configuration for the monitor.
+type Config struct {
+	version      string
+	limits       Limits
+	probes       []Probe
+	probeTimeout time.Duration
+	httpClient   *http.Client
+	usage        func() []ratelimit.ProviderUsage
+	started      time.Time
+	now          func() time.Time
+	logger       *slog.Logger
+}
+
+// WithVersion sets the server version shown in reports.
+func WithVersion(version string) Option {
+	return func(c *Config) {
+		c.version = version
+	}
+}
+
+// WithLimits sets the limits shown in reports.
+func WithLimits(limits Limits) Option {
+	return func(c *Config) {
+		c.limits = limits
+	}
+}
+
+// WithProbes replaces the default provider probes.
+func WithProbes(probes []Probe) Option {
+	return func(c *Config) {
+		c.probes = probes
+	}
+}
+
+// WithProbeTimeout sets how long each probe may take.
+func WithProbeTimeout(probeTimeout time.Duration) Option {
+	return func(c *Config) {
+		c.probeTimeout = probeTimeout
+	}
+}
+
+// WithHTTPClient sets the client used for probes.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.httpClient = client
+	}
+}
+
+// WithUsage replaces the source of the compliance report, which is
+// otherwise the usage of the shared rate limit registry.
+func WithUsage(usage func() []ratelimit.ProviderUsage) Option {
+	return func(c *Config) {
+		c.usage = usage
+	}
+}
+
+// WithLogger sets the logger for the monitor.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewMonitor creates a monitor; uptime is counted from its creation.
+func NewMonitor(opts ...Option) *Monitor {
+	cfg := &Config{
+		probes:       DefaultProbes,
+		probeTimeout: defaultProbeTimeout,
+		usage:        ratelimit.UsageReport,
+		now:          time.Now,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	if cfg.httpClient == nil {
+		cfg.httpClient = ratelimit.NewHTTPClient(cfg.probeTimeout)
+	}
+	cfg.started = cfg.now()
+	return &Monitor{config: cfg}
+}
+
+// SetTools records the names of the tools the server registered.
+func (m *Monitor) SetTools(names []string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.tools = slices.Sorted(slices.Values(names))
+}
+
+// Report returns the current status. Providers are only probed when
+// checkProviders is set, as probing takes a network round trip each.
+func (m *Monitor) Report(ctx context.Context, checkProviders bool) Report {
+	m.mu.RLock()
+	tools := slices.Clone(m.tools)
+	m.mu.RUnlock()
+	report := Report{
+		Status:    StatusOK,
+		Version:   m.config.version,
+		StartedAt: m.config.started.UTC(),
+		Uptime:    m.config.now().Sub(m.config.started).Round(time.Second).String(),
+		Tools:     tools,
+		Limits:    m.config.limits,
+	}
+	if !checkProviders {
+		return report
+	}
+	report.Providers = m.probeAll(ctx)
+	for _, provider := range report.Providers {
+		if !provider.Reachable {
+			report.Status = StatusDegraded
+		}
+	}
+	return report
+}
+
+// Compliance reports the requests the server sent to each provider since
+// it started and whether their peak rate kept to the provider's published
+// or configured cap.
+func (m *Monitor) Compliance() []ratelimit.ProviderUsage {
+	return m.config.usage()
+}
+
+// probeAll runs the probes concurrently, keeping their configured order.
+func (m *Monitor) probeAll(ctx context.Context) []ProviderStatus {
+	statuses := make([]ProviderStatus, len(m.config.probes))
+	var wg sync.WaitGroup
+	for i, probe := range m.config.probes {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			statuses[i] = m.probe(ctx, probe)
+		}()
+	}
+	wg.Wait()
+	return statuses
+}
+
+// probe checks a single provider.
+func (m *Monitor) probe(ctx context.Context, probe Probe) ProviderStatus {
+	providerStatus := ProviderStatus{Name: probe.Name, URL: probe.URL}
+	ctx, cancel := context.WithTimeout(ctx, m.config.probeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, probe.URL, nil)
+	if err != nil {
+		providerStatus.Error = fmt.Sprintf("error creating request: %v", err)
+		return providerStatus
+	}
+	start := m.config.now()
+	resp, err := m.config.httpClient.Do(req)
+	if err != nil {
+		providerStatus.Error = err.Error()
+		m.config.logger.Warn("provider unreachable", "provider", probe.Name, "error", err)
+		return providerStatus
+	}
+	defer resp.Body.Close()
+	providerStatus.Latency = m.config.now().Sub(start).Round(time.Millisecond).String()
+	providerStatus.StatusCode = resp.StatusCode
+	providerStatus.Reachable = resp.StatusCode < http.StatusInternalServerError
+	if !providerStatus.Reachable {
+		providerStatus.Error = "server error " + resp.Status
+	}
+	return providerStatus
+}
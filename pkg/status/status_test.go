@@ -0,0 +1,91 @@
+package status
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitor_Report(t *testing.T) {
+	t.Parallel()
+	healthy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer healthy.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer failing.Close()
+
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	monitor := NewMonitor(
+		WithVersion("1.0.0"),
+		WithProbes([]Probe{
+			{Name: "healthy", URL: healthy.URL},
+			{Name: "failing", URL: failing.URL},
+		}),
+		WithHTTPClient(healthy.Client()),
+		func(c *Config) {
+			c.now = func() time.Time { return now }
+		},
+	)
+	monitor.SetTools([]string{"markdown", "git-summary"})
+	now = now.Add(90 * time.Minute)
+
+	report := monitor.Report(context.Background(), false)
+	assert.Equal(t, StatusOK, report.Status)
+	assert.Equal(t, "1h30m0s", report.Uptime)
+	assert.Equal(t, []string{"git-summary", "markdown"}, report.Tools)
+	assert.Empty(t, report.Providers, "providers are only probed on request")
+
+	report = monitor.Report(context.Background(), true)
+	assert.Equal(t, StatusDegraded, report.Status)
+	require.Len(t, report.Providers, 2)
+	assert.True(t, report.Providers[0].Reachable, "an auth failure still reaches the provider")
+	assert.Equal(t, http.StatusUnauthorized, report.Providers[0].StatusCode)
+	assert.False(t, report.Providers[1].Reachable)
+	assert.Contains(t, report.Providers[1].Error, "502")
+}
+
+func TestMonitor_UnreachableProvider(t *testing.T) {
+	t.Parallel()
+	closed := httptest.NewServer(http.NotFoundHandler())
+	closed.Close()
+	monitor := NewMonitor(WithProbes([]Probe{{Name: "closed", URL: closed.URL}}))
+
+	report := monitor.Report(context.Background(), true)
+	assert.Equal(t, StatusDegraded, report.Status)
+	require.Len(t, report.Providers, 1)
+	assert.False(t, report.Providers[0].Reachable)
+	assert.NotEmpty(t, report.Providers[0].Error)
+}
+
+func TestLimits_MarshalJSON(t *testing.T) {
+	t.Parallel()
+	encoded, err := json.Marshal(Limits{
+		DefaultTimeout: 2 * time.Minute,
+		ToolTimeouts:   map[string]time.Duration{"git-summary": 10 * time.Minute},
+		MaxHeavyTools:  2,
+		HeavyTools:     []string{"git-summary"},
+		RateLimits:     map[string]ratelimit.Limit{"pubmed": {Rate: 3, Burst: 3}},
+		UploadMaxBytes: 1024,
+		UploadTTL:      time.Hour,
+	})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{
+		"default_timeout": "2m0s",
+		"tool_timeouts": {"git-summary": "10m0s"},
+		"max_heavy_tools": 2,
+		"heavy_tools": ["git-summary"],
+		"rate_limits": {"pubmed": "3/3"},
+		"upload_max_bytes": 1024,
+		"upload_ttl": "1h0m0s"
+	}`, string(encoded))
+}
@@ -0,0 +1,262 @@
+// Package telemetry reports anonymous tool usage to the maintainers, so
+// they can tell which tools are used and which fail most. It is off unless
+// an endpoint is configured. A report holds the number of calls and
+// failures of each tool, the failures by error type, the server version and
+// platform, and a random ID drawn when the server starts; it never holds
+// arguments, results, error messages, file paths or host names.
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/buildinfo"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// DefaultInterval is the time between reports.
+const DefaultInterval = 24 * time.Hour
+
+// unclassified is the error type of failed calls whose result carries no
+// toolerror.Error.
+const unclassified = "unclassified"
+
+// ToolUsage counts the calls of a tool within a report's period.
+type ToolUsage struct {
+	Tool   string `json:"tool"`
+	Calls  int    `json:"calls"`
+	Errors int    `json:"errors"`
+	// ErrorTypes counts the failed calls by toolerror.Type.
+	ErrorTypes map[string]int `json:"error_types,omitempty"`
+}
+
+// Report is the body posted to the telemetry endpoint.
+type Report struct {
+	// InstanceID is drawn at random when the server starts, so reports of
+	// one run can be told apart without identifying the installation.
+	InstanceID  string      `json:"instance_id"`
+	Version     string      `json:"version"`
+	Platform    string      `json:"platform"`
+	PeriodStart time.Time   `json:"period_start"`
+	PeriodEnd   time.Time   `json:"period_end"`
+	Tools       []ToolUsage `json:"tools"`
+}
+
+// Config holds the configuration of a Reporter.
+type Config struct {
+	interval time.Duration
+	client   *http.Client
+	now      func() time.Time
+	logger   *slog.Logger
+}
+
+// Option configures a Reporter.
+type Option func(*Config)
+
+// WithInterval sets the time between reports.
+func WithInterval(interval time.Duration) Option {
+	return func(c *Config) {
+		c.interval = interval
+	}
+}
+
+// WithHTTPClient sets the client reports are posted with.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.client = client
+	}
+}
+
+// WithLogger sets the logger for the reporter.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// Reporter counts tool calls and posts them to an endpoint.
+type Reporter struct {
+	endpoint   string
+	instanceID string
+	config     Config
+	mu         sync.Mutex
+	since      time.Time
+	tools      map[string]*ToolUsage
+}
+
+// NewReporter creates a Reporter that posts to endpoint.
+func NewReporter(endpoint string, opts ...Option) *Reporter {
+	config := Config{
+		interval: DefaultInterval,
+		client:   &http.Client{Timeout: 30 * time.Second},
+		now:      time.Now,
+		logger:   slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	return &Reporter{
+		endpoint:   endpoint,
+		instanceID: newInstanceID(),
+		config:     config,
+		since:      config.now(),
+		tools:      make(map[string]*ToolUsage),
+	}
+}
+
+// newInstanceID returns a random 128-bit ID in hex.
+func newInstanceID() string {
+	id := make([]byte, 16)
+	_, _ = rand.Read(id)
+	return hex.EncodeToString(id)
+}
+
+// Middleware counts every tool call that passes through it, and the type
+// of every failure.
+func (r *Reporter) Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		errType := ""
+		switch {
+		case err != nil:
+			errType = string(toolerror.Classify(err).Type)
+		case result != nil && result.IsError:
+			errType = unclassified
+			if toolErr, ok := result.StructuredContent.(*toolerror.Error); ok {
+				errType = string(toolErr.Type)
+			}
+		}
+		r.record(request.Params.Name, errType)
+		return result, err
+	}
+}
+
+// record counts a call of tool, failed with errType unless it is empty.
+func (r *Reporter) record(tool, errType string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage, ok := r.tools[tool]
+	if !ok {
+		usage = &ToolUsage{Tool: tool}
+		r.tools[tool] = usage
+	}
+	usage.Calls++
+	if errType == "" {
+		return
+	}
+	usage.Errors++
+	if usage.ErrorTypes == nil {
+		usage.ErrorTypes = make(map[string]int)
+	}
+	usage.ErrorTypes[errType]++
+}
+
+// snapshot returns the report of the calls counted so far.
+func (r *Reporter) snapshot() Report {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := buildinfo.Get()
+	report := Report{
+		InstanceID:  r.instanceID,
+		Version:     info.Version,
+		Platform:    info.Platform,
+		PeriodStart: r.since.UTC(),
+		PeriodEnd:   r.config.now().UTC(),
+		Tools:       make([]ToolUsage, 0, len(r.tools)),
+	}
+	for _, name := range slices.Sorted(maps.Keys(r.tools)) {
+		usage := *r.tools[name]
+		usage.ErrorTypes = maps.Clone(usage.ErrorTypes)
+		report.Tools = append(report.Tools, usage)
+	}
+	return report
+}
+
+// subtract removes the calls of a sent report from the counts, keeping the
+// calls made while it was sent for the next report.
+func (r *Reporter) subtract(report Report) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sent := range report.Tools {
+		usage := r.tools[sent.Tool]
+		usage.Calls -= sent.Calls
+		usage.Errors -= sent.Errors
+		for errType, count := range sent.ErrorTypes {
+			usage.ErrorTypes[errType] -= count
+			if usage.ErrorTypes[errType] == 0 {
+				delete(usage.ErrorTypes, errType)
+			}
+		}
+		if usage.Calls == 0 {
+			delete(r.tools, sent.Tool)
+		}
+	}
+	r.since = report.PeriodEnd
+}
+
+// Send posts the calls counted since the last report that was sent. Nothing
+// is posted when no tool was called. Calls of a report that fails to send
+// are kept for the next one.
+func (r *Reporter) Send(ctx context.Context) error {
+	report := r.snapshot()
+	if len(report.Tools) == 0 {
+		return nil
+	}
+	body, err := json.Marshal(report)
+	if err != nil {
+		return fmt.Errorf("failed to encode telemetry report: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telemetry request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := r.config.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send telemetry report to %s: %w", r.endpoint, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf("telemetry report to %s failed with status %d", r.endpoint, resp.StatusCode)
+	}
+	r.subtract(report)
+	return nil
+}
+
+// Run sends a report every interval until ctx is done, then sends the
+// calls counted since the last one. Failures are logged and never stop
+// the server.
+func (r *Reporter) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.config.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			r.send(ctx)
+		case <-ctx.Done():
+			final, cancel := context.WithTimeout(context.WithoutCancel(ctx), 5*time.Second)
+			r.send(final)
+			cancel()
+			return
+		}
+	}
+}
+
+// send sends a report and logs a failure.
+func (r *Reporter) send(ctx context.Context) {
+	if err := r.Send(ctx); err != nil {
+		r.config.logger.Warn("failed to send telemetry report", "error", err)
+	}
+}
@@ -0,0 +1,121 @@
+package telemetry
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// call runs handler for tool through the reporter's middleware.
+func call(t *testing.T, reporter *Reporter, tool string, handler func() (*mcp.CallToolResult, error)) {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+	request.Params.Arguments = map[string]any{"path": "/home/someone/secret.docx"}
+	_, _ = reporter.Middleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return handler()
+	})(context.Background(), request)
+}
+
+func TestReporter_Send(t *testing.T) {
+	t.Parallel()
+	var bodies []string
+	status := http.StatusInternalServerError
+	endpoint := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bodies = append(bodies, readAll(t, r))
+		w.WriteHeader(status)
+	}))
+	t.Cleanup(endpoint.Close)
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	reporter := NewReporter(endpoint.URL)
+	reporter.config.now = func() time.Time { return now }
+	reporter.since = now
+
+	require.NoError(t, reporter.Send(context.Background()), "nothing to report")
+	assert.Empty(t, bodies)
+
+	success := func() (*mcp.CallToolResult, error) { return mcp.NewToolResultText("done"), nil }
+	call(t, reporter, "pdf-generator", success)
+	call(t, reporter, "pdf-generator", func() (*mcp.CallToolResult, error) {
+		return toolerror.Result(toolerror.New(toolerror.TypeInvalidInput, "INVALID_INPUT", "bad")), nil
+	})
+	call(t, reporter, "git-summary", func() (*mcp.CallToolResult, error) {
+		return nil, context.DeadlineExceeded
+	})
+	call(t, reporter, "git-summary", func() (*mcp.CallToolResult, error) {
+		return mcp.NewToolResultError("failed"), nil
+	})
+	now = now.Add(time.Hour)
+
+	require.Error(t, reporter.Send(context.Background()))
+	call(t, reporter, "git-summary", success)
+	status = http.StatusNoContent
+	require.NoError(t, reporter.Send(context.Background()))
+	require.Len(t, bodies, 2)
+	assert.NotContains(t, bodies[1], "secret", "arguments are never reported")
+
+	var report Report
+	require.NoError(t, json.Unmarshal([]byte(bodies[1]), &report))
+	assert.Len(t, report.InstanceID, 32)
+	assert.NotEmpty(t, report.Version)
+	assert.Equal(t, time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), report.PeriodStart)
+	assert.Equal(t, time.Date(2024, 1, 1, 1, 0, 0, 0, time.UTC), report.PeriodEnd)
+	assert.Equal(t, []ToolUsage{
+		{
+			Tool: "git-summary", Calls: 3, Errors: 2,
+			ErrorTypes: map[string]int{"timeout": 1, unclassified: 1},
+		},
+		{
+			Tool: "pdf-generator", Calls: 2, Errors: 1,
+			ErrorTypes: map[string]int{"invalid_input": 1},
+		},
+	}, report.Tools, "counts of a failed report are kept for the next")
+
+	require.NoError(t, reporter.Send(context.Background()))
+	assert.Len(t, bodies, 2, "sent counts are not reported again")
+}
+
+func TestReporter_RunSendsOnStop(t *testing.T) {
+	t.Parallel()
+	reports := make(chan Report, 1)
+	endpoint := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var report Report
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&report))
+		reports <- report
+	}))
+	t.Cleanup(endpoint.Close)
+	reporter := NewReporter(endpoint.URL, WithInterval(time.Hour))
+	call(t, reporter, "literature-search", func() (*mcp.CallToolResult, error) {
+		return nil, errors.New("boom")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		reporter.Run(ctx)
+		close(done)
+	}()
+	cancel()
+	<-done
+	report := <-reports
+	require.Len(t, report.Tools, 1)
+	assert.Equal(t, map[string]int{"internal": 1}, report.Tools[0].ErrorTypes)
+}
+
+// readAll returns the body of r.
+func readAll(t *testing.T, r *http.Request) string {
+	t.Helper()
+	body, err := io.ReadAll(r.Body)
+	assert.NoError(t, err)
+	return string(body)
+}
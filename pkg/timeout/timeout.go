@@ -0,0 +1,160 @@
+// Package timeout enforces per-tool deadlines on tool handlers.
+package timeout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Config holds the deadlines enforced by the middleware.
+type Config struct {
+	// Default applies to tools without an override. Zero disables the
+	// deadline, although cancellation is still honored.
+	Default time.Duration
+	// ToolTimeouts overrides Default for individual tools, keyed by name.
+	ToolTimeouts map[string]time.Duration
+	// Settings, when set, replaces Default and ToolTimeouts with deadlines
+	// that can be changed while the server runs.
+	Settings *Settings
+	Logger   *slog.Logger
+}
+
+// timeoutFor returns the deadline configured for the named tool.
+func (cfg Config) timeoutFor(name string) time.Duration {
+	if cfg.Settings != nil {
+		return cfg.Settings.Load().timeoutFor(name)
+	}
+	if toolTimeout, ok := cfg.ToolTimeouts[name]; ok {
+		return toolTimeout
+	}
+	return cfg.Default
+}
+
+// Settings holds deadlines shared with running middlewares, so they can be
+// replaced when the configuration is reloaded.
+type Settings struct {
+	current atomic.Pointer[Config]
+}
+
+// NewSettings creates settings holding the deadlines of cfg.
+func NewSettings(cfg Config) *Settings {
+	settings := &Settings{}
+	settings.Store(cfg)
+	return settings
+}
+
+// Load returns the current deadlines.
+func (s *Settings) Load() Config {
+	return *s.current.Load()
+}
+
+// Store replaces the deadlines. Calls already running keep their deadline.
+func (s *Settings) Store(cfg Config) {
+	s.current.Store(&Config{Default: cfg.Default, ToolTimeouts: cfg.ToolTimeouts})
+}
+
+// Middleware returns a tool handler middleware that cancels the handler's
+// context once the tool's deadline passes and returns as soon as the context
+// is done, even if the handler ignores it. Timeouts and cancellations are
+// reported as error results rather than left hanging.
+func Middleware(cfg Config) server.ToolHandlerMiddleware {
+	logger := cfg.Logger
+	if logger == nil {
+		logger = slog.Default()
+	}
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(
+			ctx context.Context,
+			request mcp.CallToolRequest,
+		) (*mcp.CallToolResult, error) {
+			name := request.Params.Name
+			toolTimeout := cfg.timeoutFor(name)
+			var cancel context.CancelFunc
+			if toolTimeout > 0 {
+				ctx, cancel = context.WithTimeout(ctx, toolTimeout)
+			} else {
+				ctx, cancel = context.WithCancel(ctx)
+			}
+			defer cancel()
+
+			type outcome struct {
+				result *mcp.CallToolResult
+				err    error
+			}
+			// Buffered so an abandoned handler can still finish and exit.
+			done := make(chan outcome, 1)
+			go func() {
+				result, err := next(ctx, request)
+				done <- outcome{result: result, err: err}
+			}()
+
+			select {
+			case finished := <-done:
+				return finished.result, finished.err
+			case <-ctx.Done():
+				toolErr := abortError(ctx.Err(), name, toolTimeout)
+				logger.Warn(
+					"tool call aborted",
+					"tool", name,
+					"reason", toolErr.Type,
+					"timeout", toolTimeout,
+				)
+				return toolerror.Result(toolErr), nil
+			}
+		}
+	}
+}
+
+// abortError describes a call aborted because its context ended with err.
+func abortError(err error, name string, toolTimeout time.Duration) *toolerror.Error {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return &toolerror.Error{
+			Type:    toolerror.TypeTimeout,
+			Code:    "TOOL_TIMEOUT",
+			Message: fmt.Sprintf("tool %s did not finish within %s", name, toolTimeout),
+			Details: map[string]string{"tool": name, "timeout": toolTimeout.String()},
+			Err:     err,
+		}
+	}
+	return &toolerror.Error{
+		Type:    toolerror.TypeCancelled,
+		Code:    "TOOL_CANCELLED",
+		Message: fmt.Sprintf("tool %s was cancelled", name),
+		Details: map[string]string{"tool": name},
+		Err:     err,
+	}
+}
+
+// ParseToolTimeouts parses a comma-separated list of tool=duration pairs,
+// for example "git-summary=10m,literature-fetch=30s".
+func ParseToolTimeouts(spec string) (map[string]time.Duration, error) {
+	timeouts := make(map[string]time.Duration)
+	for _, pair := range strings.Split(spec, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		name, value, found := strings.Cut(pair, "=")
+		if !found || strings.TrimSpace(name) == "" {
+			return nil, fmt.Errorf("invalid tool timeout %q, expected tool=duration", pair)
+		}
+		duration, err := time.ParseDuration(strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout for %s: %w", name, err)
+		}
+		if duration < 0 {
+			return nil, fmt.Errorf("timeout for %s must not be negative", name)
+		}
+		timeouts[strings.TrimSpace(name)] = duration
+	}
+	return timeouts, nil
+}
@@ -0,0 +1,101 @@
+package timeout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(name string) mcp.CallToolRequest {
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	return request
+}
+
+// stuckHandler ignores its context and never returns on its own.
+func stuckHandler(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	select {}
+}
+
+func TestMiddleware_Timeout(t *testing.T) {
+	t.Parallel()
+	handler := Middleware(Config{
+		Default:      time.Hour,
+		ToolTimeouts: map[string]time.Duration{"slow": 20 * time.Millisecond},
+	})(stuckHandler)
+
+	result, err := handler(context.Background(), callTool("slow"))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeTimeout, toolErr.Type)
+	assert.Equal(t, "slow", toolErr.Details["tool"])
+	assert.Equal(t, "20ms", toolErr.Details["timeout"])
+}
+
+func TestMiddleware_Cancelled(t *testing.T) {
+	t.Parallel()
+	handler := Middleware(Config{})(stuckHandler)
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	result, err := handler(ctx, callTool("stuck"))
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeCancelled, toolErr.Type)
+}
+
+func TestMiddleware_PassesThrough(t *testing.T) {
+	t.Parallel()
+	handler := Middleware(Config{Default: time.Second})(
+		func(ctx context.Context, _ mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			_, hasDeadline := ctx.Deadline()
+			assert.True(t, hasDeadline)
+			return mcp.NewToolResultText("ok"), nil
+		},
+	)
+
+	result, err := handler(context.Background(), callTool("fast"))
+	require.NoError(t, err)
+	assert.False(t, result.IsError)
+}
+
+func TestMiddleware_Settings(t *testing.T) {
+	t.Parallel()
+	settings := NewSettings(Config{Default: time.Hour})
+	handler := Middleware(Config{Default: time.Hour, Settings: settings})(stuckHandler)
+	settings.Store(Config{
+		Default:      time.Hour,
+		ToolTimeouts: map[string]time.Duration{"slow": 20 * time.Millisecond},
+	})
+
+	result, err := handler(context.Background(), callTool("slow"))
+	require.NoError(t, err)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeTimeout, toolErr.Type)
+	assert.Equal(t, "20ms", toolErr.Details["timeout"], "stored deadlines apply to new calls")
+}
+
+func TestParseToolTimeouts(t *testing.T) {
+	t.Parallel()
+	timeouts, err := ParseToolTimeouts("git-summary=10m, literature-fetch=30s,")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]time.Duration{
+		"git-summary":      10 * time.Minute,
+		"literature-fetch": 30 * time.Second,
+	}, timeouts)
+
+	for _, spec := range []string{"git-summary", "=1s", "markdown=soon", "markdown=-1s"} {
+		_, err := ParseToolTimeouts(spec)
+		assert.Error(t, err, spec)
+	}
+}
@@ -0,0 +1,107 @@
+// Package tooldefaults fills in the optional tool arguments a call leaves
+// out with defaults from the configuration file, such as the page size of
+// markdown_to_pdf or the provider of literature-fetch, so deployments can
+// tune tools without clients repeating the same arguments.
+package tooldefaults
+
+import (
+	"context"
+	"log/slog"
+	"maps"
+	"slices"
+	"sync/atomic"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Config holds the configuration of a Store.
+type Config struct {
+	logger *slog.Logger
+}
+
+// Option configures a Store.
+type Option func(*Config)
+
+// WithLogger sets the logger for the store.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// Store holds the argument defaults of every tool, keyed by tool and then
+// parameter name. They can be replaced while the server runs.
+type Store struct {
+	current atomic.Pointer[map[string]map[string]any]
+	config  *Config
+}
+
+// NewStore creates a store without defaults.
+func NewStore(opts ...Option) *Store {
+	cfg := &Config{logger: slog.Default()}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	store := &Store{config: cfg}
+	store.Store(nil)
+	return store
+}
+
+// Store replaces the defaults. Calls already running keep their arguments.
+func (s *Store) Store(defaults map[string]map[string]any) {
+	cloned := make(map[string]map[string]any, len(defaults))
+	for tool, arguments := range defaults {
+		cloned[tool] = maps.Clone(arguments)
+	}
+	s.current.Store(&cloned)
+}
+
+// For returns the defaults of the named tool.
+func (s *Store) For(tool string) map[string]any {
+	return (*s.current.Load())[tool]
+}
+
+// Middleware returns a middleware that adds the defaults of the called
+// tool to its arguments. Arguments set by the call win over the defaults;
+// an argument sent as null counts as left out.
+func (s *Store) Middleware() server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			defaults := s.For(request.Params.Name)
+			if len(defaults) == 0 {
+				return next(ctx, request)
+			}
+			arguments := request.GetArguments()
+			if arguments == nil && request.Params.Arguments != nil {
+				// Arguments that are not an object are left to the tool to reject.
+				return next(ctx, request)
+			}
+			merged, applied := Merge(arguments, defaults)
+			if len(applied) > 0 {
+				s.config.logger.Debug("applied argument defaults", "tool", request.Params.Name, "parameters", applied)
+				request.Params.Arguments = merged
+			}
+			return next(ctx, request)
+		}
+	}
+}
+
+// Merge returns a copy of arguments with defaults added for the parameters
+// they leave out or set to null, and the sorted names of those parameters.
+func Merge(arguments, defaults map[string]any) (map[string]any, []string) {
+	merged := maps.Clone(arguments)
+	if merged == nil {
+		merged = make(map[string]any, len(defaults))
+	}
+	var applied []string
+	for name, value := range defaults {
+		if current, set := merged[name]; set && current != nil {
+			continue
+		}
+		merged[name] = value
+		applied = append(applied, name)
+	}
+	slices.Sort(applied)
+	return merged, applied
+}
@@ -0,0 +1,75 @@
+package tooldefaults
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// echoArguments returns the arguments it was called with as its result.
+func echoArguments(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultStructured(request.Params.Arguments, "arguments"), nil
+}
+
+// call runs the middleware of store for tool with arguments and returns
+// the arguments the handler saw.
+func call(t *testing.T, store *Store, tool string, arguments any) any {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = tool
+	request.Params.Arguments = arguments
+	result, err := store.Middleware()(echoArguments)(context.Background(), request)
+	require.NoError(t, err)
+	return result.StructuredContent
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	store := NewStore(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	store.Store(map[string]map[string]any{
+		"markdown_to_pdf":  {"page_size": "A4", "margin": "2cm"},
+		"literature-fetch": {"provider": "europepmc"},
+	})
+
+	assert.Equal(t,
+		map[string]any{"content": "# Hi", "page_size": "Letter", "margin": "2cm"},
+		call(t, store, "markdown_to_pdf", map[string]any{"content": "# Hi", "page_size": "Letter"}),
+		"explicit arguments win over defaults",
+	)
+	assert.Equal(t,
+		map[string]any{"id": "1", "id_type": "pmid", "provider": "europepmc"},
+		call(t, store, "literature-fetch", map[string]any{"id": "1", "id_type": "pmid", "provider": nil}),
+		"null arguments take the default",
+	)
+	assert.Equal(t,
+		map[string]any{"provider": "europepmc"},
+		call(t, store, "literature-fetch", nil),
+	)
+	assert.Equal(t,
+		map[string]any{"repo_url": "x"},
+		call(t, store, "git-summary", map[string]any{"repo_url": "x"}),
+		"tools without defaults are left alone",
+	)
+	assert.Equal(t, "not an object", call(t, store, "literature-fetch", "not an object"))
+
+	store.Store(nil)
+	assert.Equal(t,
+		map[string]any{"content": "# Hi"},
+		call(t, store, "markdown_to_pdf", map[string]any{"content": "# Hi"}),
+		"replaced defaults apply to later calls",
+	)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	arguments := map[string]any{"a": 1}
+	merged, applied := Merge(arguments, map[string]any{"c": 3, "a": 2, "b": 2})
+	assert.Equal(t, map[string]any{"a": 1, "b": 2, "c": 3}, merged)
+	assert.Equal(t, []string{"b", "c"}, applied)
+	assert.Equal(t, map[string]any{"a": 1}, arguments, "the arguments are not modified")
+}
@@ -0,0 +1,82 @@
+package toolerror
+
+import (
+	"regexp"
+	"slices"
+)
+
+// Hint is remediation advice for the failures matching a signature. A
+// failure matches when its code or type is listed, or its message matches
+// the pattern.
+type Hint struct {
+	// Name identifies the signature.
+	Name  string
+	Codes []string
+	Types []Type
+	// Pattern is matched against the message, which carries the wording of
+	// the downstream service for failures no tool classified.
+	Pattern *regexp.Regexp
+	// Text tells the user what to do about the failure.
+	Text string
+}
+
+// matches reports whether err has the signature of h.
+func (h Hint) matches(err *Error) bool {
+	return slices.Contains(h.Codes, err.Code) ||
+		slices.Contains(h.Types, err.Type) ||
+		(h.Pattern != nil && h.Pattern.MatchString(err.Message))
+}
+
+// Hints is the catalog of common failures, tried in order. Signatures of
+// specific causes come before the types they share with other causes, so
+// a rate limited provider gets the rate limit hint rather than the one for
+// unreachable services.
+var Hints = []Hint{
+	{
+		Name:    "ref_not_found",
+		Codes:   []string{"REF_NOT_FOUND"},
+		Pattern: regexp.MustCompile(`(?i)couldn't find remote ref|reference not found`),
+		Text: "Check the spelling of the branch or tag; git-branches lists the branches of the repository, " +
+			"and leaving the branch out uses the default branch.",
+	},
+	{
+		Name:    "rate_limited",
+		Pattern: regexp.MustCompile(`(?i)\b429\b|too many requests|rate limit`),
+		Text: "The provider is rate limiting this server. Wait a minute before retrying, and if it keeps " +
+			"happening lower the provider's limit with --rate-limits, e.g. pubmed=3/1 for NCBI.",
+	},
+	{
+		Name:  "missing_api_key",
+		Codes: []string{"MISSING_OPENAI_API_KEY", "MISSING_API_KEY"},
+		Text: "Set the environment variable named in the message where the server runs, e.g. " +
+			"OPENAI_API_KEY, and restart the server.",
+	},
+	{
+		Name:    "invalid_api_key",
+		Pattern: regexp.MustCompile(`(?i)status code: 401|\b401 unauthorized|incorrect api key|invalid api key|invalid_api_key`),
+		Text: "The service rejected the server's credentials. Check that the API key or token, such as " +
+			"OPENAI_API_KEY, GITHUB_TOKEN or ZOTERO_API_KEY, is current and allowed to access the resource.",
+	},
+	{
+		Name:  "deadline",
+		Types: []Type{TypeTimeout},
+		Text: "The call ran past its deadline. Narrow the request, e.g. a shorter date range or fewer " +
+			"results, or raise the deadline with --tool-timeout or --tool-timeouts, e.g. git-summary=10m.",
+	},
+	{
+		Name:  "unreachable",
+		Types: []Type{TypeNetworkError},
+		Text:  "The service could not be reached. Check the server's network access and proxy settings, then retry.",
+	},
+}
+
+// HintFor returns the text of the first hint in Hints matching err, or ""
+// when none does.
+func HintFor(err *Error) string {
+	for _, hint := range Hints {
+		if hint.matches(err) {
+			return hint.Text
+		}
+	}
+	return ""
+}
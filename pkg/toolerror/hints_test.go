@@ -0,0 +1,103 @@
+package toolerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// hintNamed returns the text of the hint with a name.
+func hintNamed(t *testing.T, name string) string {
+	t.Helper()
+	for _, hint := range Hints {
+		if hint.Name == name {
+			return hint.Text
+		}
+	}
+	require.Failf(t, "unknown hint", "%s", name)
+	return ""
+}
+
+func TestHintFor(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		err  error
+		hint string
+	}{
+		{
+			name: "missing branch",
+			err:  fmt.Errorf("error cloning repository: %w", errors.New(`couldn't find remote ref "refs/heads/mian"`)),
+			hint: "ref_not_found",
+		},
+		{
+			name: "unresolved ref",
+			err:  New(TypeNotFound, "REF_NOT_FOUND", `failed to resolve "v9" on the cloned branch`),
+			hint: "ref_not_found",
+		},
+		{
+			name: "ncbi 429",
+			err: New(TypeNetworkError, "PUBMED_UNAVAILABLE",
+				"PubMed unavailable: failed after 3 attempts: unexpected status 429 Too Many Requests"),
+			hint: "rate_limited",
+		},
+		{
+			name: "missing_api_key",
+			err:  New(TypeConfiguration, "MISSING_OPENAI_API_KEY", "OPENAI_API_KEY is not set on the server"),
+			hint: "missing_api_key",
+		},
+		{
+			name: "invalid_api_key",
+			err: fmt.Errorf("error generating summary: %w", errors.New(
+				"error, status code: 401, status: 401 Unauthorized, message: Incorrect API key provided")),
+			hint: "invalid_api_key",
+		},
+		{
+			name: "deadline",
+			err:  fmt.Errorf("request aborted: %w", context.DeadlineExceeded),
+			hint: "deadline",
+		},
+		{
+			name: "unreachable",
+			err:  New(TypeNetworkError, "HOST_UNREACHABLE", "no route to host"),
+			hint: "unreachable",
+		},
+		{
+			name: "no hint",
+			err:  InvalidInput(errors.New("missing title")),
+		},
+		{
+			name: "cancelled",
+			err:  context.Canceled,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			want := ""
+			if testCase.hint != "" {
+				want = hintNamed(t, testCase.hint)
+			}
+			assert.Equal(t, want, HintFor(Classify(testCase.err)))
+		})
+	}
+}
+
+func TestResult_Hint(t *testing.T) {
+	t.Parallel()
+	cause := New(TypeConfiguration, "MISSING_OPENAI_API_KEY", "OPENAI_API_KEY is not set on the server")
+	result := Result(cause)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	hint := hintNamed(t, "missing_api_key")
+	assert.Equal(t, "OPENAI_API_KEY is not set on the server\n\nHint: "+hint, text.Text)
+	toolErr, ok := result.StructuredContent.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, hint, toolErr.Hint)
+	assert.Empty(t, cause.Hint, "the original error is not modified")
+}
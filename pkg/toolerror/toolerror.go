@@ -0,0 +1,152 @@
+// Package toolerror reports tool failures as MCP error results that carry a
+// machine-readable type and code next to the human-readable message, and a
+// remediation hint for common failures.
+package toolerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// Type classifies a tool failure.
+type Type string
+
+const (
+	// TypeInvalidInput means the arguments were missing or malformed.
+	TypeInvalidInput Type = "invalid_input"
+	// TypeNotFound means the requested record does not exist.
+	TypeNotFound Type = "not_found"
+	// TypeConfiguration means the server lacks required configuration,
+	// such as an API key.
+	TypeConfiguration Type = "configuration"
+	// TypeAPIError means a downstream service rejected or failed the call.
+	TypeAPIError Type = "api_error"
+	// TypeNetworkError means a downstream service could not be reached.
+	TypeNetworkError Type = "network_error"
+	// TypeTimeout means the call did not finish within its deadline.
+	TypeTimeout Type = "timeout"
+	// TypeCancelled means the client cancelled the call.
+	TypeCancelled Type = "cancelled"
+	// TypeInternal covers failures inside the server itself.
+	TypeInternal Type = "internal"
+)
+
+// Error is a classified tool failure.
+type Error struct {
+	Type    Type   `json:"type"`
+	Message string `json:"message"`
+	Code    string `json:"code,omitempty"`
+	// Details holds extra context, e.g. the deadline of a timed out call.
+	Details map[string]string `json:"details,omitempty"`
+	// Hint tells the user how to fix a common failure; see Hints.
+	Hint string `json:"hint,omitempty"`
+	// Err is the underlying cause, kept for errors.Is and errors.As.
+	Err error `json:"-"`
+}
+
+// Error implements the error interface.
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Unwrap returns the underlying cause.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// New creates an Error with the given type, code and message.
+func New(errType Type, code, message string) *Error {
+	return &Error{Type: errType, Code: code, Message: message}
+}
+
+// Wrap classifies err, prefixing its message with prefix.
+func Wrap(errType Type, code string, err error, prefix string) *Error {
+	return &Error{
+		Type:    errType,
+		Code:    code,
+		Message: fmt.Sprintf("%s: %v", prefix, err),
+		Err:     err,
+	}
+}
+
+// InvalidInput reports an argument validation failure.
+func InvalidInput(err error) *Error {
+	return Wrap(TypeInvalidInput, "INVALID_INPUT", err, "validation error")
+}
+
+// Upstream reports a failed call to a downstream service such as Zotero or
+// ORCID. Deadlines and cancellations keep their own types, as do errors
+// the service client already classified.
+func Upstream(service string, err error, prefix string) *Error {
+	var toolErr *Error
+	switch {
+	case errors.As(err, &toolErr):
+		classified := *Wrap(toolErr.Type, toolErr.Code, err, prefix)
+		classified.Details = toolErr.Details
+		return &classified
+	case errors.Is(err, context.DeadlineExceeded):
+		return Wrap(TypeTimeout, "DEADLINE_EXCEEDED", err, prefix)
+	case errors.Is(err, context.Canceled):
+		return Wrap(TypeCancelled, "CANCELLED", err, prefix)
+	default:
+		return Wrap(TypeAPIError, strings.ToUpper(service)+"_API_ERROR", err, prefix)
+	}
+}
+
+// Classify returns the Error in err's chain. Context errors become timeout
+// and cancellation errors; anything else is an internal error.
+func Classify(err error) *Error {
+	var toolErr *Error
+	if errors.As(err, &toolErr) {
+		if toolErr.Message != err.Error() {
+			// Keep the context added by wrapping the classified error.
+			classified := *toolErr
+			classified.Message = err.Error()
+			return &classified
+		}
+		return toolErr
+	}
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return &Error{Type: TypeTimeout, Code: "DEADLINE_EXCEEDED", Message: err.Error(), Err: err}
+	case errors.Is(err, context.Canceled):
+		return &Error{Type: TypeCancelled, Code: "CANCELLED", Message: err.Error(), Err: err}
+	default:
+		return &Error{Type: TypeInternal, Code: "INTERNAL", Message: err.Error(), Err: err}
+	}
+}
+
+// Result converts err into an MCP error result. The text content holds the
+// message, followed by the hint of a known failure, and the structured
+// content holds the classified Error.
+func Result(err error) *mcp.CallToolResult {
+	toolErr := Classify(err)
+	text := toolErr.Message
+	if hint := HintFor(toolErr); hint != "" {
+		withHint := *toolErr
+		withHint.Hint = hint
+		toolErr = &withHint
+		text += "\n\nHint: " + hint
+	}
+	result := mcp.NewToolResultError(text)
+	result.StructuredContent = toolErr
+	return result
+}
+
+// Middleware converts errors returned by tool handlers into error results,
+// so failures reach the client as readable tool output rather than as
+// protocol errors.
+func Middleware(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+	return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		result, err := next(ctx, request)
+		if err != nil {
+			return Result(err), nil
+		}
+		return result, nil
+	}
+}
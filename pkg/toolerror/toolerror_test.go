@@ -0,0 +1,115 @@
+package toolerror
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+	notFound := New(TypeNotFound, "DOI_NOT_FOUND", "no article found")
+	tests := []struct {
+		name        string
+		err         error
+		wantType    Type
+		wantMessage string
+	}{
+		{
+			name:        "classified",
+			err:         notFound,
+			wantType:    TypeNotFound,
+			wantMessage: "no article found",
+		},
+		{
+			name:        "wrapped classified",
+			err:         fmt.Errorf("failed to fetch: %w", notFound),
+			wantType:    TypeNotFound,
+			wantMessage: "failed to fetch: no article found",
+		},
+		{
+			name:        "deadline",
+			err:         fmt.Errorf("request aborted: %w", context.DeadlineExceeded),
+			wantType:    TypeTimeout,
+			wantMessage: "request aborted: context deadline exceeded",
+		},
+		{
+			name:        "cancelled",
+			err:         context.Canceled,
+			wantType:    TypeCancelled,
+			wantMessage: "context canceled",
+		},
+		{
+			name:        "plain",
+			err:         errors.New("boom"),
+			wantType:    TypeInternal,
+			wantMessage: "boom",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			classified := Classify(testCase.err)
+			assert.Equal(t, testCase.wantType, classified.Type)
+			assert.Equal(t, testCase.wantMessage, classified.Message)
+		})
+	}
+	assert.Equal(t, "no article found", notFound.Message, "the original error is not modified")
+}
+
+func TestResult(t *testing.T) {
+	t.Parallel()
+	cause := errors.New("missing title")
+	result := Result(InvalidInput(cause))
+	assert.True(t, result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Equal(t, "validation error: missing title", text.Text)
+	toolErr, ok := result.StructuredContent.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, TypeInvalidInput, toolErr.Type)
+	assert.Equal(t, "INVALID_INPUT", toolErr.Code)
+	assert.ErrorIs(t, toolErr, cause)
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	handler := Middleware(func(context.Context, mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		return nil, New(TypeConfiguration, "MISSING_API_KEY", "OPENAI_API_KEY is not set")
+	})
+	result, err := handler(context.Background(), mcp.CallToolRequest{})
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*Error)
+	require.True(t, ok)
+	assert.Equal(t, TypeConfiguration, toolErr.Type)
+	assert.Equal(t, "MISSING_API_KEY", toolErr.Code)
+}
+
+func TestUpstream(t *testing.T) {
+	t.Parallel()
+	apiErr := Upstream("zotero", errors.New("status 403"), "failed to export references")
+	assert.Equal(t, TypeAPIError, apiErr.Type)
+	assert.Equal(t, "ZOTERO_API_ERROR", apiErr.Code)
+	assert.Equal(t, "failed to export references: status 403", apiErr.Message)
+
+	timeoutErr := Upstream("orcid", fmt.Errorf("get: %w", context.DeadlineExceeded), "failed")
+	assert.Equal(t, TypeTimeout, timeoutErr.Type)
+
+	classified := &Error{
+		Type:    TypeNetworkError,
+		Code:    "HOST_UNREACHABLE",
+		Message: "no route",
+		Details: map[string]string{"host": "example.org"},
+	}
+	networkErr := Upstream("git_clone", fmt.Errorf("clone: %w", classified), "failed to clone")
+	assert.Equal(t, TypeNetworkError, networkErr.Type)
+	assert.Equal(t, "HOST_UNREACHABLE", networkErr.Code)
+	assert.Equal(t, "failed to clone: clone: no route", networkErr.Message)
+	assert.Equal(t, map[string]string{"host": "example.org"}, networkErr.Details)
+}
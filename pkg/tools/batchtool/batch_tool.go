@@ -0,0 +1,224 @@
+package batchtool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// toolName is the name run-batch is served under.
+	toolName = "run-batch"
+	// defaultParallel is the number of steps run at once by default.
+	defaultParallel = 2
+	// maxParallel bounds the steps run at once.
+	maxParallel = 8
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// BatchTool runs a manifest of tool invocations as a dependency graph and
+// reports their outcomes together.
+type BatchTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	invoker     registry.Invoker
+}
+
+// Option defines a functional option for configuring BatchTool.
+type Option func(*BatchTool)
+
+// WithInvoker sets the catalog of tools the steps are run with.
+func WithInvoker(invoker registry.Invoker) Option {
+	return func(b *BatchTool) {
+		b.invoker = invoker
+	}
+}
+
+// BatchRequest represents the parameters of a run-batch call.
+type BatchRequest struct {
+	Manifest string `validate:"required,max=1048576"`
+	Parallel int    `validate:"min=1,max=8"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		toolName,
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewBatchTool(deps.Logger, WithInvoker(deps.Tools))
+		},
+	)
+}
+
+// NewBatchTool creates a new BatchTool instance.
+func NewBatchTool(logger *slog.Logger, opts ...Option) (*BatchTool, error) {
+	tool := mcp.NewTool(
+		toolName,
+		mcp.WithDescription(
+			"Runs a YAML or JSON manifest of tool calls as a dependency graph and returns one report. "+
+				"Each step has an id, a tool, arguments and optional depends_on; arguments may use "+
+				"${<step>.text} or ${<step>.structured.<field>} to pass the output of an earlier step. "+
+				"Steps run as soon as their dependencies succeed; steps after a failure are skipped",
+		),
+		mcp.WithTitleAnnotation("Run Batch"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"manifest",
+			mcp.Required(),
+			mcp.Description("The manifest: a steps list of {id, tool, arguments, depends_on}, as YAML or JSON"),
+		),
+		mcp.WithNumber(
+			"parallel",
+			mcp.Description(fmt.Sprintf(
+				"Number of steps run at once, at most %d (defaults to %d)",
+				maxParallel,
+				defaultParallel,
+			)),
+		),
+	)
+	batchTool := &BatchTool{
+		Name:        toolName,
+		Description: "Runs manifests of tool calls as dependency graphs",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(batchTool)
+	}
+	return batchTool, nil
+}
+
+// GetName returns the name of the tool.
+func (b *BatchTool) GetName() string {
+	return b.Name
+}
+
+// GetDescription returns the description of the tool.
+func (b *BatchTool) GetDescription() string {
+	return b.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (b *BatchTool) GetSchema() mcp.ToolInputSchema {
+	return b.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (b *BatchTool) GetTool() mcp.Tool {
+	return b.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (b *BatchTool) GetAnnotations() mcp.ToolAnnotation {
+	return b.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (b *BatchTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize a repository and turn the summary into a PDF",
+			Arguments: map[string]any{
+				"manifest": "steps:\n" +
+					"  - id: summary\n" +
+					"    tool: git-summary\n" +
+					"    arguments:\n" +
+					"      repo_url: https://github.com/dictybase/modware-stock\n" +
+					"      branch: develop\n" +
+					"      start_date: last month\n" +
+					"  - id: pdf\n" +
+					"    tool: markdown_to_pdf\n" +
+					"    arguments:\n" +
+					"      content: ${summary.text}\n" +
+					"      filename: reports/modware-stock.pdf\n",
+			},
+		},
+		{
+			Description: "Report on two repositories side by side",
+			Arguments: map[string]any{
+				"manifest": `{"steps": [` +
+					`{"id": "stock", "tool": "repo-stats", "arguments": ` +
+					`{"repo_url": "https://github.com/dictybase/modware-stock", "branch": "develop"}}, ` +
+					`{"id": "order", "tool": "repo-stats", "arguments": ` +
+					`{"repo_url": "https://github.com/dictybase/modware-order", "branch": "develop"}}]}`,
+				"parallel": 2,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (b *BatchTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := BatchRequest{
+		Manifest: request.GetString("manifest", ""),
+		Parallel: request.GetInt("parallel", defaultParallel),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	if b.invoker == nil {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeConfiguration,
+			"TOOLS_UNAVAILABLE",
+			"run-batch can only run within the server, which provides the tools it calls",
+		)), nil
+	}
+	manifest, err := b.Parse(params.Manifest)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	report := execute(ctx, b.invoker, manifest, params.Parallel)
+	logging.WithRequestID(b.Logger).Info(
+		"finished batch",
+		"steps", len(report.Steps),
+		"succeeded", report.Succeeded,
+		"failed", report.Failed,
+		"skipped", report.Skipped,
+	)
+	result := mcp.NewToolResultStructured(report, RenderMarkdown(report))
+	result.Content = append(result.Content, report.Links()...)
+	return provenance.Attach(result, provenance.New(report.Providers())), nil
+}
+
+// Parse reads a manifest and checks that every step names a tool the
+// server serves other than run-batch and run-nl, which would run tools of
+// their own.
+func (b *BatchTool) Parse(data string) (Manifest, error) {
+	manifest, err := ParseManifest([]byte(data))
+	if err != nil {
+		return Manifest{}, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_MANIFEST", err, "invalid manifest")
+	}
+	served := make([]string, 0)
+	for _, tool := range b.invoker.Tools() {
+		served = append(served, tool.Name)
+	}
+	for _, step := range manifest.Steps {
+		if registry.IsOrchestrator(step.Tool) || !slices.Contains(served, step.Tool) {
+			return Manifest{}, toolerror.New(
+				toolerror.TypeInvalidInput,
+				"UNKNOWN_TOOL",
+				fmt.Sprintf("step %q calls %q, which is not a tool this server runs in batches", step.ID, step.Tool),
+			)
+		}
+	}
+	return manifest, nil
+}
@@ -0,0 +1,183 @@
+package batchtool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInvoker serves a search tool with structured output, an echo tool,
+// a tool that always fails and the orchestration tools.
+type fakeInvoker struct{}
+
+func (fakeInvoker) Tools() []mcp.Tool {
+	return []mcp.Tool{
+		mcp.NewTool("search"), mcp.NewTool("echo"), mcp.NewTool("broken"),
+		mcp.NewTool(toolName), mcp.NewTool("run-nl"),
+	}
+}
+
+func (fakeInvoker) CallTool(_ context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	switch name {
+	case "search":
+		result := mcp.NewToolResultStructured(
+			map[string]any{"total": 2, "first": map[string]any{"pmid": "123"}},
+			"# 2 articles",
+		)
+		result.Content = append(result.Content, mcp.NewResourceLink("dcr://export/a.csv", "a.csv", "", "text/csv"))
+		return provenance.Attach(result, provenance.New([]string{metrics.ServiceEuropePMC})), nil
+	case "echo":
+		return mcp.NewToolResultText(args["text"].(string)), nil
+	case "broken":
+		return toolerror.Result(errors.New("upstream is down")), nil
+	default:
+		return nil, errors.New("unknown tool")
+	}
+}
+
+// callRequest builds a run-batch request with args.
+func callRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: toolName, Arguments: args}}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	invoker := fakeInvoker{}
+	tool, err := NewBatchTool(slog.Default(), WithInvoker(invoker))
+	require.NoError(t, err)
+
+	result, err := tool.Handler(context.Background(), callRequest(map[string]any{
+		"manifest": `
+steps:
+  - id: search
+    tool: search
+    arguments: {query: chemotaxis}
+  - id: note
+    tool: echo
+    arguments:
+      text: "Found ${search.structured.total}, first ${search.structured.first.pmid}"
+  - id: fail
+    tool: broken
+  - id: after-fail
+    tool: echo
+    arguments: {text: "${fail.text}"}
+  - id: independent
+    tool: echo
+    arguments: {text: hello}
+`,
+		"parallel": 3,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	report, ok := result.StructuredContent.(Report)
+	require.True(t, ok)
+	assert.Equal(t, 3, report.Succeeded)
+	assert.Equal(t, 1, report.Failed)
+	assert.Equal(t, 1, report.Skipped)
+
+	byID := make(map[string]StepReport)
+	for _, step := range report.Steps {
+		byID[step.ID] = step
+	}
+	assert.Equal(t, StatusSucceeded, byID["note"].Status)
+	assert.Equal(t, "Found 2, first 123", byID["note"].Text)
+	assert.Equal(t, []string{"search"}, byID["note"].DependsOn)
+	assert.Equal(t, StatusFailed, byID["fail"].Status)
+	assert.Contains(t, byID["fail"].Error, "upstream is down")
+	assert.Equal(t, StatusSkipped, byID["after-fail"].Status)
+	assert.Equal(t, `step "fail" failed`, byID["after-fail"].Error)
+	assert.Equal(t, map[string]any{"total": float64(2), "first": map[string]any{"pmid": "123"}}, byID["search"].Structured)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "5 steps: 3 succeeded, 1 failed, 1 skipped")
+	assert.Contains(t, text, "| note | `echo` | succeeded | search |")
+	require.Len(t, result.Content, 2, "the resource links of the steps are passed on")
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{metrics.ServiceEuropePMC}, record.Providers)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts []Option
+		args map[string]any
+		code string
+	}{
+		{
+			name: "missing manifest",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{},
+			code: "INVALID_INPUT",
+		},
+		{
+			name: "too parallel",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{"manifest": "steps: []", "parallel": 20},
+			code: "INVALID_INPUT",
+		},
+		{
+			name: "outside the server",
+			args: map[string]any{"manifest": "steps:\n  - id: a\n    tool: echo\n"},
+			code: "TOOLS_UNAVAILABLE",
+		},
+		{
+			name: "invalid manifest",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{"manifest": "steps: []"},
+			code: "INVALID_MANIFEST",
+		},
+		{
+			name: "unknown tool",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{"manifest": "steps:\n  - id: a\n    tool: bake\n"},
+			code: "UNKNOWN_TOOL",
+		},
+		{
+			name: "nested batch",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{"manifest": "steps:\n  - id: a\n    tool: run-batch\n"},
+			code: "UNKNOWN_TOOL",
+		},
+		{
+			name: "nested natural-language run",
+			opts: []Option{WithInvoker(fakeInvoker{})},
+			args: map[string]any{"manifest": "steps:\n  - id: a\n    tool: run-nl\n"},
+			code: "UNKNOWN_TOOL",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tool, err := NewBatchTool(slog.Default(), tc.opts...)
+			require.NoError(t, err)
+			result, err := tool.Handler(context.Background(), callRequest(tc.args))
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			toolErr, ok := result.StructuredContent.(*toolerror.Error)
+			require.True(t, ok)
+			assert.Equal(t, tc.code, toolErr.Code)
+		})
+	}
+}
+
+func TestExecute_Cancelled(t *testing.T) {
+	t.Parallel()
+	manifest, err := ParseManifest([]byte("steps:\n  - id: a\n    tool: echo\n    arguments: {text: hi}\n"))
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	report := execute(ctx, fakeInvoker{}, manifest, 1)
+	require.Len(t, report.Steps, 1)
+	assert.Equal(t, StatusSkipped, report.Steps[0].Status)
+	assert.Equal(t, 1, report.Skipped)
+}
@@ -0,0 +1,295 @@
+package batchtool
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSteps bounds the steps of a manifest.
+const maxSteps = 50
+
+// stepIDRegex matches valid step IDs.
+var stepIDRegex = regexp.MustCompile(`^[A-Za-z0-9_-]{1,64}$`)
+
+// referenceRegex matches a reference to the output of another step:
+// ${<step>.text} or ${<step>.structured[.<field or index>...]}.
+var referenceRegex = regexp.MustCompile(`\$\{([A-Za-z0-9_-]+)\.(text|structured)((?:\.[A-Za-z0-9_-]+)*)\}`)
+
+// Manifest is a batch of tool invocations.
+type Manifest struct {
+	Steps []Step `json:"steps" yaml:"steps"`
+}
+
+// Step is one tool invocation of a manifest. Its ID, Tool and Arguments
+// match the fields of a NATS job request.
+type Step struct {
+	ID        string         `json:"id"                   yaml:"id"`
+	Tool      string         `json:"tool"                 yaml:"tool"`
+	Arguments map[string]any `json:"arguments,omitempty"  yaml:"arguments"`
+	DependsOn []string       `json:"depends_on,omitempty" yaml:"depends_on"`
+}
+
+// reference is a use of the output of step in the arguments of another.
+type reference struct {
+	step string
+	// output is text or structured.
+	output string
+	// path selects a field or element of the structured output.
+	path []string
+}
+
+// ParseManifest reads a YAML or JSON manifest and checks that its steps
+// form a graph without cycles. The dependencies of a step are the steps it
+// lists in depends_on and the steps its arguments reference.
+func ParseManifest(data []byte) (Manifest, error) {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var manifest Manifest
+	if err := decoder.Decode(&manifest); err != nil {
+		if errors.Is(err, io.EOF) {
+			return Manifest{}, errors.New("the manifest is empty")
+		}
+		return Manifest{}, fmt.Errorf("error reading manifest: %w", err)
+	}
+	if len(manifest.Steps) == 0 {
+		return Manifest{}, errors.New("the manifest has no steps")
+	}
+	if len(manifest.Steps) > maxSteps {
+		return Manifest{}, fmt.Errorf("the manifest has %d steps, more than the %d allowed", len(manifest.Steps), maxSteps)
+	}
+	ids := make(map[string]bool, len(manifest.Steps))
+	for i, step := range manifest.Steps {
+		if !stepIDRegex.MatchString(step.ID) {
+			return Manifest{}, fmt.Errorf(
+				"step %d has the id %q; ids are 1 to 64 letters, digits, dashes or underscores",
+				i+1,
+				step.ID,
+			)
+		}
+		if ids[step.ID] {
+			return Manifest{}, fmt.Errorf("the step id %q is used twice", step.ID)
+		}
+		if step.Tool == "" {
+			return Manifest{}, fmt.Errorf("step %q names no tool", step.ID)
+		}
+		ids[step.ID] = true
+	}
+	for i, step := range manifest.Steps {
+		// YAML and JSON numbers decode differently; a JSON round trip
+		// gives the arguments the types of those sent by MCP clients.
+		args, err := normalize(step.Arguments)
+		if err != nil {
+			return Manifest{}, fmt.Errorf("invalid arguments of step %q: %w", step.ID, err)
+		}
+		step.Arguments = args
+		for _, ref := range references(args) {
+			if !slices.Contains(step.DependsOn, ref.step) {
+				step.DependsOn = append(step.DependsOn, ref.step)
+			}
+		}
+		for _, dependency := range step.DependsOn {
+			if !ids[dependency] {
+				return Manifest{}, fmt.Errorf("step %q depends on the unknown step %q", step.ID, dependency)
+			}
+		}
+		manifest.Steps[i] = step
+	}
+	if cycle := findCycle(manifest.Steps); len(cycle) > 0 {
+		return Manifest{}, fmt.Errorf("the steps %s depend on each other", strings.Join(cycle, ", "))
+	}
+	return manifest, nil
+}
+
+// normalize converts decoded YAML arguments to the types JSON decoding
+// gives.
+func normalize(args map[string]any) (map[string]any, error) {
+	if args == nil {
+		return map[string]any{}, nil
+	}
+	data, err := json.Marshal(args)
+	if err != nil {
+		return nil, err
+	}
+	var normalized map[string]any
+	if err := json.Unmarshal(data, &normalized); err != nil {
+		return nil, err
+	}
+	return normalized, nil
+}
+
+// findCycle returns the IDs of the steps that cannot be ordered because
+// they depend on each other, directly or through other steps.
+func findCycle(steps []Step) []string {
+	pending := make(map[string]int, len(steps))
+	dependents := make(map[string][]string)
+	for _, step := range steps {
+		pending[step.ID] = len(step.DependsOn)
+		for _, dependency := range step.DependsOn {
+			dependents[dependency] = append(dependents[dependency], step.ID)
+		}
+	}
+	var ready []string
+	for _, step := range steps {
+		if pending[step.ID] == 0 {
+			ready = append(ready, step.ID)
+		}
+	}
+	for len(ready) > 0 {
+		id := ready[0]
+		ready = ready[1:]
+		delete(pending, id)
+		for _, dependent := range dependents[id] {
+			pending[dependent]--
+			if pending[dependent] == 0 {
+				ready = append(ready, dependent)
+			}
+		}
+	}
+	var cycle []string
+	for _, step := range steps {
+		if _, ok := pending[step.ID]; ok {
+			cycle = append(cycle, step.ID)
+		}
+	}
+	return cycle
+}
+
+// references returns the references in the string values of args.
+func references(value any) []reference {
+	var refs []reference
+	switch value := value.(type) {
+	case string:
+		for _, match := range referenceRegex.FindAllStringSubmatch(value, -1) {
+			refs = append(refs, newReference(match))
+		}
+	case map[string]any:
+		// Keys are visited in order, so implied dependencies are listed
+		// the same way every time.
+		for _, key := range slices.Sorted(maps.Keys(value)) {
+			refs = append(refs, references(value[key])...)
+		}
+	case []any:
+		for _, item := range value {
+			refs = append(refs, references(item)...)
+		}
+	}
+	return refs
+}
+
+// newReference builds a reference from a match of referenceRegex.
+func newReference(match []string) reference {
+	var path []string
+	if match[3] != "" {
+		path = strings.Split(strings.TrimPrefix(match[3], "."), ".")
+	}
+	return reference{step: match[1], output: match[2], path: path}
+}
+
+// Output is what a finished step hands to the steps depending on it.
+type Output struct {
+	Text string
+	// Structured is the structured content decoded as JSON.
+	Structured any
+}
+
+// resolve replaces the references in value with the outputs of the steps
+// they name. A string that is a single reference takes the type of the
+// referenced value; references within longer strings are replaced by text.
+func resolve(value any, outputs map[string]Output) (any, error) {
+	switch value := value.(type) {
+	case string:
+		return resolveString(value, outputs)
+	case map[string]any:
+		resolved := make(map[string]any, len(value))
+		for key, item := range value {
+			item, err := resolve(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved[key] = item
+		}
+		return resolved, nil
+	case []any:
+		resolved := make([]any, 0, len(value))
+		for _, item := range value {
+			item, err := resolve(item, outputs)
+			if err != nil {
+				return nil, err
+			}
+			resolved = append(resolved, item)
+		}
+		return resolved, nil
+	default:
+		return value, nil
+	}
+}
+
+// resolveString replaces the references in value.
+func resolveString(value string, outputs map[string]Output) (any, error) {
+	matches := referenceRegex.FindAllStringSubmatchIndex(value, -1)
+	if len(matches) == 1 && matches[0][0] == 0 && matches[0][1] == len(value) {
+		return lookup(newReference(referenceRegex.FindStringSubmatch(value)), outputs)
+	}
+	var resolveErr error
+	resolved := referenceRegex.ReplaceAllStringFunc(value, func(match string) string {
+		found, err := lookup(newReference(referenceRegex.FindStringSubmatch(match)), outputs)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		if text, ok := found.(string); ok {
+			return text
+		}
+		data, err := json.Marshal(found)
+		if err != nil {
+			resolveErr = err
+			return match
+		}
+		return string(data)
+	})
+	if resolveErr != nil {
+		return nil, resolveErr
+	}
+	return resolved, nil
+}
+
+// lookup returns the value ref points to.
+func lookup(ref reference, outputs map[string]Output) (any, error) {
+	output, ok := outputs[ref.step]
+	if !ok {
+		return nil, fmt.Errorf("step %q has no output", ref.step)
+	}
+	if ref.output == "text" {
+		return output.Text, nil
+	}
+	current := output.Structured
+	for i, key := range ref.path {
+		switch node := current.(type) {
+		case map[string]any:
+			value, ok := node[key]
+			if !ok {
+				return nil, fmt.Errorf("the output of step %q has no field %s", ref.step, strings.Join(ref.path[:i+1], "."))
+			}
+			current = value
+		case []any:
+			index, err := strconv.Atoi(key)
+			if err != nil || index < 0 || index >= len(node) {
+				return nil, fmt.Errorf("the output of step %q has no element %s", ref.step, strings.Join(ref.path[:i+1], "."))
+			}
+			current = node[index]
+		default:
+			return nil, fmt.Errorf("the output of step %q has no field %s", ref.step, strings.Join(ref.path[:i+1], "."))
+		}
+	}
+	return current, nil
+}
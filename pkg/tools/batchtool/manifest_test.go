@@ -0,0 +1,100 @@
+package batchtool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseManifest(t *testing.T) {
+	t.Parallel()
+	manifest, err := ParseManifest([]byte(`
+steps:
+  - id: summary
+    tool: git-summary
+    arguments:
+      repo_url: https://github.com/a/b
+      limit: 5
+  - id: pdf
+    tool: markdown_to_pdf
+    arguments:
+      markdown: "${summary.text}"
+      title: "Report of ${stats.structured.repo.name}"
+    depends_on: [stats]
+  - id: stats
+    tool: repo-stats
+`))
+	require.NoError(t, err)
+	require.Len(t, manifest.Steps, 3)
+	assert.Equal(t, map[string]any{"repo_url": "https://github.com/a/b", "limit": float64(5)}, manifest.Steps[0].Arguments)
+	assert.Equal(t, []string{"stats", "summary"}, manifest.Steps[1].DependsOn, "references add dependencies")
+	assert.Equal(t, map[string]any{}, manifest.Steps[2].Arguments)
+
+	fromJSON, err := ParseManifest([]byte(`{"steps": [{"id": "a", "tool": "markdown", "arguments": {"text": "x"}}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, "markdown", fromJSON.Steps[0].Tool)
+}
+
+func TestParseManifest_Invalid(t *testing.T) {
+	t.Parallel()
+	tests := map[string]string{
+		"empty":          ``,
+		"no steps":       `steps: []`,
+		"unknown field":  "steps:\n  - id: a\n    tool: markdown\n    depends-on: [b]\n",
+		"bad id":         "steps:\n  - id: a b\n    tool: markdown\n",
+		"duplicate id":   "steps:\n  - id: a\n    tool: markdown\n  - id: a\n    tool: markdown\n",
+		"no tool":        "steps:\n  - id: a\n",
+		"unknown step":   "steps:\n  - id: a\n    tool: markdown\n    depends_on: [b]\n",
+		"bad reference":  "steps:\n  - id: a\n    tool: markdown\n    arguments: {text: '${b.text}'}\n",
+		"self reference": "steps:\n  - id: a\n    tool: markdown\n    arguments: {text: '${a.text}'}\n",
+		"cycle": "steps:\n  - id: a\n    tool: markdown\n    depends_on: [c]\n" +
+			"  - id: b\n    tool: markdown\n    depends_on: [a]\n  - id: c\n    tool: markdown\n    depends_on: [b]\n",
+	}
+	for name, manifest := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			_, err := ParseManifest([]byte(manifest))
+			require.Error(t, err)
+		})
+	}
+	_, err := ParseManifest([]byte("steps:\n  - id: a\n    tool: markdown\n    depends_on: [b]\n" +
+		"  - id: b\n    tool: markdown\n    depends_on: [a]\n  - id: c\n    tool: markdown\n"))
+	require.EqualError(t, err, "the steps a, b depend on each other")
+}
+
+func TestResolve(t *testing.T) {
+	t.Parallel()
+	outputs := map[string]Output{
+		"search": {
+			Text: "3 articles",
+			Structured: map[string]any{
+				"total":    float64(3),
+				"articles": []any{map[string]any{"pmid": "123"}},
+			},
+		},
+	}
+	resolved, err := resolve(map[string]any{
+		"whole":    "${search.structured.total}",
+		"embedded": "PMID ${search.structured.articles.0.pmid}: ${search.text}",
+		"list":     []any{"${search.text}", true},
+		"plain":    float64(1),
+	}, outputs)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]any{
+		"whole":    float64(3),
+		"embedded": "PMID 123: 3 articles",
+		"list":     []any{"3 articles", true},
+		"plain":    float64(1),
+	}, resolved)
+
+	for _, value := range []string{
+		"${search.structured.missing}",
+		"${search.structured.articles.5}",
+		"${search.structured.total.value}",
+		"see ${other.text}",
+	} {
+		_, err := resolve(value, outputs)
+		require.Error(t, err, value)
+	}
+}
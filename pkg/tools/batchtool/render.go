@@ -0,0 +1,65 @@
+package batchtool
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxTextLength bounds the text of a step shown in the report; the full
+// text is in the structured content.
+const maxTextLength = 2000
+
+// RenderMarkdown lists the outcome of every step of a batch, followed by
+// the errors and text outputs of the steps.
+func RenderMarkdown(report Report) string {
+	var sb strings.Builder
+	sb.WriteString("# Batch Report\n\n")
+	fmt.Fprintf(
+		&sb,
+		"%d steps: %d succeeded, %d failed, %d skipped\n\n",
+		len(report.Steps),
+		report.Succeeded,
+		report.Failed,
+		report.Skipped,
+	)
+	sb.WriteString("| Step | Tool | Status | Depends on | Duration |\n")
+	sb.WriteString("|------|------|--------|------------|----------|\n")
+	for _, step := range report.Steps {
+		fmt.Fprintf(
+			&sb,
+			"| %s | `%s` | %s | %s | %s |\n",
+			step.ID,
+			step.Tool,
+			step.Status,
+			strings.Join(step.DependsOn, ", "),
+			step.Duration,
+		)
+	}
+	for _, step := range report.Steps {
+		fmt.Fprintf(&sb, "\n## %s\n\n", step.ID)
+		switch {
+		case step.Error != "":
+			fmt.Fprintf(&sb, "**%s:** %s\n", step.Status, step.Error)
+		case step.Text != "":
+			sb.WriteString(truncate(step.Text))
+			sb.WriteString("\n")
+		default:
+			sb.WriteString("No text output.\n")
+		}
+	}
+	return sb.String()
+}
+
+// truncate shortens text to maxTextLength bytes without splitting a
+// character.
+func truncate(text string) string {
+	if len(text) <= maxTextLength {
+		return text
+	}
+	cut := maxTextLength
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut] + "\n\n… (truncated; the full text is in the structured content)"
+}
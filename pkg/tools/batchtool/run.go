@@ -0,0 +1,257 @@
+package batchtool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Status is the outcome of a step.
+type Status string
+
+const (
+	// StatusSucceeded marks a step whose tool returned a result.
+	StatusSucceeded Status = "succeeded"
+	// StatusFailed marks a step whose tool returned an error result or
+	// could not be called.
+	StatusFailed Status = "failed"
+	// StatusSkipped marks a step that did not run because a step it
+	// depends on did not succeed or the batch was cancelled.
+	StatusSkipped Status = "skipped"
+)
+
+// StepReport is the outcome of one step.
+type StepReport struct {
+	ID        string   `json:"id"`
+	Tool      string   `json:"tool"`
+	DependsOn []string `json:"depends_on,omitempty"`
+	Status    Status   `json:"status"`
+	// Arguments are the arguments the tool was called with, after the
+	// references to other steps were replaced.
+	Arguments map[string]any `json:"arguments,omitempty"`
+	Duration  string         `json:"duration,omitempty"`
+	Error     string         `json:"error,omitempty"`
+	// Text is the text content of the tool's result.
+	Text string `json:"text,omitempty"`
+	// Structured is the structured content of the tool's result.
+	Structured any `json:"structured,omitempty"`
+	// links are the resource links of the tool's result.
+	links []mcp.Content
+	// providers are the services the tool used.
+	providers []string
+}
+
+// Report is the consolidated outcome of a batch.
+type Report struct {
+	Steps     []StepReport `json:"steps"`
+	Succeeded int          `json:"succeeded"`
+	Failed    int          `json:"failed"`
+	Skipped   int          `json:"skipped"`
+}
+
+// Providers returns the services the steps used, in order of first use.
+func (r Report) Providers() []string {
+	var providers []string
+	for _, step := range r.Steps {
+		for _, provider := range step.providers {
+			if !slices.Contains(providers, provider) {
+				providers = append(providers, provider)
+			}
+		}
+	}
+	return providers
+}
+
+// Links returns the resource links of the steps' results.
+func (r Report) Links() []mcp.Content {
+	var links []mcp.Content
+	for _, step := range r.Steps {
+		links = append(links, step.links...)
+	}
+	return links
+}
+
+// executor runs the steps of a manifest as a graph: each step starts once
+// the steps it depends on have finished, and at most parallel steps run at
+// once.
+type executor struct {
+	invoker  registry.Invoker
+	steps    []Step
+	reports  []StepReport
+	outputs  map[string]Output
+	done     map[string]chan struct{}
+	status   map[string]Status
+	mu       sync.Mutex
+	slots    chan struct{}
+	reporter *progress.Reporter
+	finished int
+}
+
+// execute runs the steps of manifest with invoker.
+func execute(ctx context.Context, invoker registry.Invoker, manifest Manifest, parallel int) Report {
+	exec := &executor{
+		invoker:  invoker,
+		steps:    manifest.Steps,
+		reports:  make([]StepReport, len(manifest.Steps)),
+		outputs:  make(map[string]Output, len(manifest.Steps)),
+		done:     make(map[string]chan struct{}, len(manifest.Steps)),
+		status:   make(map[string]Status, len(manifest.Steps)),
+		slots:    make(chan struct{}, parallel),
+		reporter: progress.FromContext(ctx),
+	}
+	for _, step := range manifest.Steps {
+		exec.done[step.ID] = make(chan struct{})
+	}
+	exec.reporter.Report(0, float64(len(manifest.Steps)), "starting batch")
+	var wg sync.WaitGroup
+	for i := range manifest.Steps {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			exec.reports[i] = exec.runStep(ctx, manifest.Steps[i])
+			exec.finish(manifest.Steps[i].ID, exec.reports[i].Status)
+		}()
+	}
+	wg.Wait()
+	report := Report{Steps: exec.reports}
+	for _, step := range report.Steps {
+		switch step.Status {
+		case StatusSucceeded:
+			report.Succeeded++
+		case StatusFailed:
+			report.Failed++
+		case StatusSkipped:
+			report.Skipped++
+		}
+	}
+	return report
+}
+
+// runStep waits for the dependencies of step and runs it unless one of
+// them did not succeed.
+func (e *executor) runStep(ctx context.Context, step Step) StepReport {
+	report := StepReport{ID: step.ID, Tool: step.Tool, DependsOn: step.DependsOn}
+	for _, dependency := range step.DependsOn {
+		select {
+		case <-e.done[dependency]:
+		case <-ctx.Done():
+			return skipped(report, "the batch was cancelled: "+ctx.Err().Error())
+		}
+		if status := e.statusOf(dependency); status != StatusSucceeded {
+			return skipped(report, fmt.Sprintf("step %q %s", dependency, status))
+		}
+	}
+	if ctx.Err() != nil {
+		return skipped(report, "the batch was cancelled: "+ctx.Err().Error())
+	}
+	select {
+	case e.slots <- struct{}{}:
+		defer func() { <-e.slots }()
+	case <-ctx.Done():
+		return skipped(report, "the batch was cancelled: "+ctx.Err().Error())
+	}
+	resolved, err := resolve(step.Arguments, e.outputsOf(step.DependsOn))
+	if err != nil {
+		report.Status = StatusFailed
+		report.Error = err.Error()
+		return report
+	}
+	report.Arguments, _ = resolved.(map[string]any)
+	start := time.Now()
+	result, err := e.invoker.CallTool(ctx, step.Tool, report.Arguments)
+	report.Duration = time.Since(start).Round(time.Millisecond).String()
+	if err != nil {
+		report.Status = StatusFailed
+		report.Error = err.Error()
+		return report
+	}
+	output := newOutput(result)
+	report.Text = output.Text
+	report.Structured = output.Structured
+	for _, content := range result.Content {
+		if _, ok := content.(mcp.ResourceLink); ok {
+			report.links = append(report.links, content)
+		}
+	}
+	if record, ok := provenance.FromResult(result); ok {
+		report.providers = record.Providers
+	}
+	if result.IsError {
+		report.Status = StatusFailed
+		report.Error = output.Text
+		report.Text = ""
+		return report
+	}
+	report.Status = StatusSucceeded
+	e.mu.Lock()
+	e.outputs[step.ID] = output
+	e.mu.Unlock()
+	return report
+}
+
+// finish records the status of a step, unblocks the steps depending on it
+// and reports the progress of the batch.
+func (e *executor) finish(id string, status Status) {
+	e.mu.Lock()
+	e.status[id] = status
+	e.finished++
+	finished := e.finished
+	// Reports are sent under the lock, so progress only ever grows.
+	e.reporter.Report(float64(finished), float64(len(e.steps)), fmt.Sprintf("step %s %s", id, status))
+	e.mu.Unlock()
+	close(e.done[id])
+}
+
+// statusOf returns the status of a finished step.
+func (e *executor) statusOf(id string) Status {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	return e.status[id]
+}
+
+// outputsOf returns the outputs of the steps with ids.
+func (e *executor) outputsOf(ids []string) map[string]Output {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	outputs := make(map[string]Output, len(ids))
+	for _, id := range ids {
+		outputs[id] = e.outputs[id]
+	}
+	return outputs
+}
+
+// skipped marks report as skipped for reason.
+func skipped(report StepReport, reason string) StepReport {
+	report.Status = StatusSkipped
+	report.Error = reason
+	return report
+}
+
+// newOutput collects the text and structured content of result. The
+// structured content is decoded as JSON, so later steps can select its
+// fields whether the tool ran in process or not.
+func newOutput(result *mcp.CallToolResult) Output {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	output := Output{Text: strings.Join(texts, "\n\n")}
+	if result.StructuredContent != nil {
+		data, err := json.Marshal(result.StructuredContent)
+		if err == nil {
+			_ = json.Unmarshal(data, &output.Structured)
+		}
+	}
+	return output
+}
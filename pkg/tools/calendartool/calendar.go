@@ -0,0 +1,154 @@
+package calendartool
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// MaxIdleDays is the longest run of days without commits that still
+// belongs to one activity period.
+const MaxIdleDays = 2
+
+// Release is a tag of the repository.
+type Release struct {
+	Tag    string    `json:"tag"`
+	Commit string    `json:"commit"`
+	Date   time.Time `json:"date"`
+	// Message is the message of an annotated tag.
+	Message string `json:"message,omitempty"`
+}
+
+// Period is a run of days with commits, with at most MaxIdleDays between
+// two of them.
+type Period struct {
+	// Start and End are the first and last day with commits, as
+	// YYYY-MM-DD.
+	Start   string   `json:"start"`
+	End     string   `json:"end"`
+	Commits int      `json:"commits"`
+	Authors []string `json:"authors"`
+}
+
+// Calendar holds the events of a repository and where they were written.
+type Calendar struct {
+	RepoURL  string    `json:"repo_url"`
+	Branch   string    `json:"branch"`
+	Releases []Release `json:"releases"`
+	Periods  []Period  `json:"periods"`
+	// Path is where the artifact store wrote the .ics file.
+	Path string `json:"path"`
+	// URL is a download link, if the artifact store provides one.
+	URL string `json:"url,omitempty"`
+	// ResourceURI is the MCP resource of the file, if resources are served.
+	ResourceURI string `json:"resource_uri,omitempty"`
+}
+
+// listReleases returns the tags of repo pointing at commits, dated by the
+// tagger of annotated tags and the committer of lightweight ones, oldest
+// first. A zero start or end leaves that side of the range open.
+func listReleases(repo *git.Repository, start, end time.Time) ([]Release, error) {
+	refs, err := repo.Tags()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list tags: %w", err)
+	}
+	var releases []Release
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		release, err := newRelease(repo, ref)
+		if errors.Is(err, object.ErrUnsupportedObject) || errors.Is(err, plumbing.ErrObjectNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if (start.IsZero() || !release.Date.Before(start)) && (end.IsZero() || !release.Date.After(end)) {
+			releases = append(releases, release)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading tags: %w", err)
+	}
+	slices.SortFunc(releases, func(a, b Release) int {
+		return cmp.Or(a.Date.Compare(b.Date), cmp.Compare(a.Tag, b.Tag))
+	})
+	return releases, nil
+}
+
+// newRelease describes the tag ref.
+func newRelease(repo *git.Repository, ref *plumbing.Reference) (Release, error) {
+	release := Release{Tag: ref.Name().Short()}
+	tag, err := repo.TagObject(ref.Hash())
+	switch {
+	case err == nil:
+		commit, err := tag.Commit()
+		if err != nil {
+			return Release{}, err
+		}
+		release.Commit, release.Date = commit.Hash.String(), tag.Tagger.When
+		release.Message = strings.TrimSpace(tag.Message)
+	case errors.Is(err, plumbing.ErrObjectNotFound):
+		commit, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			return Release{}, err
+		}
+		release.Commit, release.Date = commit.Hash.String(), commit.Committer.When
+	default:
+		return Release{}, err
+	}
+	return release, nil
+}
+
+// activityPeriods groups commits into periods of days with commits no
+// more than MaxIdleDays apart and keeps those with at least minCommits
+// commits, oldest first.
+func activityPeriods(commits []worksummary.Commit, minCommits int) []Period {
+	byDay := make(map[string][]worksummary.Commit)
+	for _, commit := range commits {
+		day := commit.When.Format(time.DateOnly)
+		byDay[day] = append(byDay[day], commit)
+	}
+	days := make([]string, 0, len(byDay))
+	for day := range byDay {
+		days = append(days, day)
+	}
+	slices.Sort(days)
+	var periods []Period
+	var current *Period
+	for _, day := range days {
+		if current == nil || daysBetween(current.End, day) > MaxIdleDays+1 {
+			periods = append(periods, Period{Start: day})
+			current = &periods[len(periods)-1]
+		}
+		current.End = day
+		for _, commit := range byDay[day] {
+			current.Commits++
+			if !slices.Contains(current.Authors, commit.Author) {
+				current.Authors = append(current.Authors, commit.Author)
+			}
+		}
+	}
+	periods = slices.DeleteFunc(periods, func(period Period) bool {
+		return period.Commits < minCommits
+	})
+	for i := range periods {
+		slices.Sort(periods[i].Authors)
+	}
+	return periods
+}
+
+// daysBetween returns the number of days from one YYYY-MM-DD day to
+// another.
+func daysBetween(from, to string) int {
+	start, _ := time.Parse(time.DateOnly, from)
+	end, _ := time.Parse(time.DateOnly, to)
+	return int(end.Sub(start).Hours() / 24)
+}
@@ -0,0 +1,99 @@
+package calendartool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// day returns noon UTC of a day in 2025.
+func day(month time.Month, d int) time.Time {
+	return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC)
+}
+
+// newRepo creates a repository with one commit by Jane on each day.
+func newRepo(t *testing.T, days ...time.Time) *gittest.Repo {
+	t.Helper()
+	repo := gittest.NewRepo(t)
+	for _, when := range days {
+		repo.Commit(t, gittest.Commit{Author: object.Signature{Name: "Jane", Email: "jane@example.org", When: when}})
+	}
+	return repo
+}
+
+// tag tags the commit of repo, annotated when message is not empty.
+func tag(t *testing.T, repo *gittest.Repo, name string, commit plumbing.Hash, when time.Time, message string) {
+	t.Helper()
+	var opts *git.CreateTagOptions
+	if message != "" {
+		opts = &git.CreateTagOptions{
+			Tagger:  &object.Signature{Name: "Jane", Email: "jane@example.org", When: when},
+			Message: message,
+		}
+	}
+	_, err := repo.Repository.CreateTag(name, commit, opts)
+	require.NoError(t, err)
+}
+
+func TestListReleases(t *testing.T) {
+	t.Parallel()
+	repo := newRepo(t, day(time.March, 1), day(time.May, 1), day(time.July, 1))
+	tag(t, repo, "v1.0.0", repo.Commits[0], day(time.March, 3), "First release\n")
+	tag(t, repo, "v1.1.0", repo.Commits[1], time.Time{}, "")
+	tag(t, repo, "v2.0.0", repo.Commits[2], day(time.July, 2), "Second major")
+
+	releases, err := listReleases(repo.Repository, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, releases, 3)
+	assert.Equal(t, Release{
+		Tag:     "v1.0.0",
+		Commit:  repo.Commits[0].String(),
+		Date:    day(time.March, 3),
+		Message: "First release",
+	}, Release{
+		Tag:     releases[0].Tag,
+		Commit:  releases[0].Commit,
+		Date:    releases[0].Date.UTC(),
+		Message: releases[0].Message,
+	}, "annotated tags are dated by their tagger")
+	assert.Equal(t, "v1.1.0", releases[1].Tag)
+	assert.True(t, day(time.May, 1).Equal(releases[1].Date), "lightweight tags are dated by their commit")
+	assert.Empty(t, releases[1].Message)
+
+	releases, err = listReleases(repo.Repository, day(time.April, 1), day(time.June, 30))
+	require.NoError(t, err)
+	require.Len(t, releases, 1)
+	assert.Equal(t, "v1.1.0", releases[0].Tag)
+}
+
+func TestActivityPeriods(t *testing.T) {
+	t.Parallel()
+	commits := func(author string, when time.Time, count int) []worksummary.Commit {
+		list := make([]worksummary.Commit, count)
+		for i := range list {
+			list[i] = worksummary.Commit{Author: author, When: when.Add(time.Duration(i) * time.Minute)}
+		}
+		return list
+	}
+	var all []worksummary.Commit
+	all = append(all, commits("Joe", day(time.June, 10), 4)...)
+	all = append(all, commits("Jane", day(time.June, 2), 3)...)
+	all = append(all, commits("Joe", day(time.June, 5), 2)...)
+	all = append(all, commits("Jane", day(time.June, 1), 2)...)
+	all = append(all, commits("Ann", day(time.June, 20), 1)...)
+
+	periods := activityPeriods(all, 3)
+	assert.Equal(t, []Period{
+		{Start: "2025-06-01", End: "2025-06-05", Commits: 7, Authors: []string{"Jane", "Joe"}},
+		{Start: "2025-06-10", End: "2025-06-10", Commits: 4, Authors: []string{"Joe"}},
+	}, periods, "days at most MaxIdleDays apart join and small periods are dropped")
+	assert.Empty(t, activityPeriods(all, 100))
+	assert.Empty(t, activityPeriods(nil, 1))
+}
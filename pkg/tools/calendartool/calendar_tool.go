@@ -0,0 +1,332 @@
+package calendartool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// defaultMinCommits is the number of commits that makes a run of active
+// days an activity period by default.
+const defaultMinCommits = 10
+
+// calendarMIMEType is the MIME type of iCalendar files.
+const calendarMIMEType = "text/calendar"
+
+// Initialize validator.
+var validate = validator.New()
+
+// GitCalendarTool turns the releases and busy periods of a repository
+// into an iCalendar file that lab calendars can import.
+type GitCalendarTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+	store       artifact.Store
+	resources   *resources.Catalog
+}
+
+// ToolOption defines a functional option for configuring GitCalendarTool.
+type ToolOption func(*GitCalendarTool)
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(g *GitCalendarTool) {
+		g.analyzer = analyzer
+	}
+}
+
+// WithStore sets the artifact store calendars are written to. The default
+// store writes to the local filesystem.
+func WithStore(store artifact.Store) ToolOption {
+	return func(g *GitCalendarTool) {
+		g.store = store
+	}
+}
+
+// WithResources publishes calendars as MCP resources in the catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(g *GitCalendarTool) {
+		g.resources = catalog
+	}
+}
+
+// CalendarRequest represents the parameters for building a calendar.
+type CalendarRequest struct {
+	RepoURL string `validate:"required"`
+	Branch  string `validate:"required"`
+	// StartDate limits the calendar to a date range; without it the whole
+	// history is read.
+	StartDate  string
+	EndDate    string `validate:"excluded_without=StartDate"`
+	MinCommits int    `validate:"min=1"`
+	// Filename is the .ics file written, relative to the artifact store.
+	Filename string `validate:"required"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"git-calendar",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewGitCalendarTool(deps.Logger, WithStore(deps.Store), WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewGitCalendarTool creates a new GitCalendarTool instance.
+func NewGitCalendarTool(logger *slog.Logger, opts ...ToolOption) (*GitCalendarTool, error) {
+	tool := mcp.NewTool(
+		"git-calendar",
+		mcp.WithDescription(
+			"Writes the tagged releases and busy periods of a git repository branch to an iCalendar (.ics) "+
+				"file for import into lab calendars",
+		),
+		mcp.WithTitleAnnotation("Git Calendar"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to read"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description(
+				"Only include events from this date on, in any standard format or in words such as "+
+					"'last year' (default: the whole history)",
+			),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description(
+				"Only include events up to this date, read like the end_date of git-summary; needs start_date",
+			),
+		),
+		mcp.WithNumber(
+			"min_commits",
+			mcp.Description(fmt.Sprintf(
+				"Commits a run of active days, with at most %d idle days between them, needs to become "+
+					"an activity event (default: %d)",
+				MaxIdleDays,
+				defaultMinCommits,
+			)),
+		),
+		mcp.WithString(
+			"filename",
+			mcp.Description("Relative name of the .ics file to write (default: <repo>-<branch>.ics)"),
+		),
+	)
+	calendarTool := &GitCalendarTool{
+		Name:        "git-calendar",
+		Description: "Exports repository milestones as an iCalendar file",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(calendarTool)
+	}
+	return calendarTool, nil
+}
+
+// GetName returns the name of the tool.
+func (g *GitCalendarTool) GetName() string {
+	return g.Name
+}
+
+// GetDescription returns the description of the tool.
+func (g *GitCalendarTool) GetDescription() string {
+	return g.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (g *GitCalendarTool) GetSchema() mcp.ToolInputSchema {
+	return g.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (g *GitCalendarTool) GetTool() mcp.Tool {
+	return g.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GitCalendarTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GitCalendarTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Export every release and busy stretch of a repository",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-stock",
+				"branch":   "develop",
+			},
+		},
+		{
+			Description: "Export last year's milestones, counting smaller bursts of work",
+			Arguments: map[string]any{
+				"repo_url":    "https://github.com/dictybase/dcr-mcp",
+				"branch":      "main",
+				"start_date":  "last year",
+				"min_commits": 5,
+				"filename":    "calendars/dcr-mcp.ics",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (g *GitCalendarTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := CalendarRequest{
+		RepoURL:    request.GetString("repo_url", ""),
+		Branch:     request.GetString("branch", ""),
+		StartDate:  request.GetString("start_date", ""),
+		EndDate:    request.GetString("end_date", ""),
+		MinCommits: request.GetInt("min_commits", defaultMinCommits),
+		Filename:   strings.TrimSpace(request.GetString("filename", "")),
+	}
+	if params.Filename == "" {
+		params.Filename = fmt.Sprintf("%s-%s.ics", worksummary.RepoName(params.RepoURL), path.Base(params.Branch))
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	if err := checkFilename(params.Filename); err != nil {
+		return toolerror.Result(toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_FILENAME", err, "invalid filename")), nil
+	}
+	calendar, data, err := g.Export(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	var links []mcp.Content
+	if g.resources != nil {
+		resource, err := g.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindCalendar,
+			Name:        path.Base(params.Filename),
+			MIMEType:    calendarMIMEType,
+			Description: fmt.Sprintf("Releases and activity of %s on %s", params.RepoURL, params.Branch),
+			Data:        data,
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("failed to publish calendar %s: %w", params.Filename, err)), nil
+		}
+		calendar.ResourceURI = resource.URI
+		links = append(links, resources.Link(resource))
+	}
+	result := provenance.Attach(
+		mcp.NewToolResultStructured(calendar, RenderMarkdown(calendar)),
+		provenance.New([]string{metrics.ServiceGitClone}),
+	)
+	result.Content = append(result.Content, links...)
+	return result, nil
+}
+
+// Export clones the branch, collects its releases and activity periods,
+// within the date range when one is given, and writes them to the
+// calendar file of params. It returns the calendar with the file's
+// content.
+func (g *GitCalendarTool) Export(ctx context.Context, params CalendarRequest) (Calendar, []byte, error) {
+	calendar := Calendar{RepoURL: params.RepoURL, Branch: params.Branch}
+	activity := worksummary.ActivityParams{}
+	if params.StartDate != "" {
+		dateRange, err := g.analyzer.ResolveDateRange(params.StartDate, params.EndDate)
+		if err != nil {
+			return Calendar{}, nil, toolerror.Wrap(
+				toolerror.TypeInvalidInput,
+				"INVALID_DATE_RANGE",
+				err,
+				"failed to parse dates",
+			)
+		}
+		activity.Start, activity.End = dateRange.Start, dateRange.End
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := g.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.8)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Calendar{}, nil, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	reporter.Report(0.8, 1, "collecting releases and activity")
+	calendar.Releases, err = listReleases(repo.Repository, activity.Start, activity.End)
+	if err != nil {
+		return Calendar{}, nil, err
+	}
+	activity.Repo = repo.Repository
+	commits, err := g.analyzer.ListActivity(ctx, activity)
+	if err != nil {
+		return Calendar{}, nil, fmt.Errorf("failed to list commits: %w", err)
+	}
+	calendar.Periods = activityPeriods(commits, params.MinCommits)
+	reporter.Report(0.9, 1, "writing calendar")
+	data := EncodeICS(calendar, time.Now())
+	stored, err := g.store.Put(ctx, artifact.PutParams{
+		Name:        params.Filename,
+		ContentType: calendarMIMEType,
+		Body:        bytes.NewReader(data),
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return Calendar{}, nil, fmt.Errorf("failed to store calendar %s: %w", params.Filename, err)
+	}
+	logging.WithRequestID(g.Logger).Info(
+		"saved calendar",
+		"location", stored.Location,
+		"releases", len(calendar.Releases),
+		"periods", len(calendar.Periods),
+	)
+	calendar.Path, calendar.URL = stored.Location, stored.URL
+	reporter.Report(1, 1, "calendar saved")
+	return calendar, data, nil
+}
+
+// checkFilename requires a relative .ics name that stays inside the
+// artifact store.
+func checkFilename(name string) error {
+	if !filepath.IsLocal(name) {
+		return fmt.Errorf("filename %q must be relative and stay inside the artifact directory", name)
+	}
+	if !strings.EqualFold(filepath.Ext(name), ".ics") {
+		return errors.New("filename must end in .ics")
+	}
+	return nil
+}
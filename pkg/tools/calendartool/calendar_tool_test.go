@@ -0,0 +1,89 @@
+package calendartool
+
+import (
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, dir string, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(
+		worksummary.WithTimeZone(time.UTC),
+		worksummary.WithCurrentTime(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)),
+	)
+	tool, err := NewGitCalendarTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithAnalyzer(analyzer),
+		WithStore(artifact.NewLocalStore(dir)),
+		WithResources(resources.NewCatalog(server.NewMCPServer("test", "1.0.0"))),
+	)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "git-calendar", arguments)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	repo := newRepo(t, day(time.May, 1), day(time.June, 2), day(time.June, 3), day(time.June, 4))
+	tag(t, repo, "v1.0.0", repo.Commits[0], day(time.May, 2), "First release")
+	dir := t.TempDir()
+
+	result := callTool(t, dir, map[string]any{"repo_url": repo.Dir, "branch": "master", "min_commits": 3})
+	require.False(t, result.IsError)
+	calendar, ok := result.StructuredContent.(Calendar)
+	require.True(t, ok)
+	require.Len(t, calendar.Releases, 1)
+	assert.Equal(t, "v1.0.0", calendar.Releases[0].Tag)
+	assert.Equal(t, []Period{{Start: "2025-06-02", End: "2025-06-04", Commits: 3, Authors: []string{"Jane"}}}, calendar.Periods)
+	name := filepath.Base(repo.Dir) + "-master.ics"
+	assert.Equal(t, "dcr://calendar/"+name, calendar.ResourceURI)
+	data, err := os.ReadFile(filepath.Join(dir, name))
+	require.NoError(t, err)
+	assert.Equal(t, 2, strings.Count(string(data), "BEGIN:VEVENT"))
+
+	result = callTool(t, dir, map[string]any{
+		"repo_url":   repo.Dir,
+		"branch":     "master",
+		"start_date": "2025-06-01",
+		"filename":   "lab/dcr.ics",
+	})
+	require.False(t, result.IsError)
+	calendar, ok = result.StructuredContent.(Calendar)
+	require.True(t, ok)
+	assert.Empty(t, calendar.Releases, "releases outside the range are left out")
+	assert.Empty(t, calendar.Periods, "three commits fall short of the default min_commits")
+	assert.FileExists(t, filepath.Join(dir, "lab", "dcr.ics"))
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	repo := newRepo(t, day(time.May, 1))
+	for _, arguments := range []map[string]any{
+		{"branch": "master"},
+		{"repo_url": repo.Dir, "branch": "master", "end_date": "2025-06-01"},
+		{"repo_url": repo.Dir, "branch": "master", "min_commits": 0},
+		{"repo_url": repo.Dir, "branch": "master", "filename": "../escape.ics"},
+		{"repo_url": repo.Dir, "branch": "master", "filename": "calendar.txt"},
+		{"repo_url": repo.Dir, "branch": "master", "start_date": "not a date at all"},
+	} {
+		result := callTool(t, t.TempDir(), arguments)
+		require.True(t, result.IsError, arguments)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
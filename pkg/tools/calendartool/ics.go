@@ -0,0 +1,141 @@
+package calendartool
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+const (
+	// productID identifies the server as the producer of calendars.
+	productID = "-//dictyBase//dcr-mcp//EN"
+	// maxLineOctets is the longest content line RFC 5545 allows before
+	// folding.
+	maxLineOctets = 75
+	// icsDate is the format of DATE values.
+	icsDate = "20060102"
+	// icsStamp is the format of UTC DATE-TIME values.
+	icsStamp = "20060102T150405Z"
+)
+
+// event is an all-day calendar event.
+type event struct {
+	uid         string
+	start, end  time.Time
+	summary     string
+	description string
+	category    string
+}
+
+// EncodeICS writes the releases and activity periods of calendar as an
+// iCalendar (RFC 5545) file of all-day events. Event UIDs depend only on
+// the repository and the release or period, so importing a newer file
+// updates events instead of duplicating them.
+func EncodeICS(calendar Calendar, stamp time.Time) []byte {
+	var sb strings.Builder
+	name := fmt.Sprintf("%s (%s)", worksummary.RepoName(calendar.RepoURL), calendar.Branch)
+	writeLine(&sb, "BEGIN:VCALENDAR")
+	writeLine(&sb, "VERSION:2.0")
+	writeLine(&sb, "PRODID:"+productID)
+	writeLine(&sb, "CALSCALE:GREGORIAN")
+	writeLine(&sb, "METHOD:PUBLISH")
+	writeLine(&sb, "X-WR-CALNAME:"+escapeText(name))
+	for _, event := range events(calendar) {
+		writeLine(&sb, "BEGIN:VEVENT")
+		writeLine(&sb, "UID:"+event.uid)
+		writeLine(&sb, "DTSTAMP:"+stamp.UTC().Format(icsStamp))
+		writeLine(&sb, "DTSTART;VALUE=DATE:"+event.start.Format(icsDate))
+		writeLine(&sb, "DTEND;VALUE=DATE:"+event.end.Format(icsDate))
+		writeLine(&sb, "SUMMARY:"+escapeText(event.summary))
+		if event.description != "" {
+			writeLine(&sb, "DESCRIPTION:"+escapeText(event.description))
+		}
+		writeLine(&sb, "CATEGORIES:"+escapeText(event.category))
+		writeLine(&sb, "TRANSP:TRANSPARENT")
+		writeLine(&sb, "END:VEVENT")
+	}
+	writeLine(&sb, "END:VCALENDAR")
+	return []byte(sb.String())
+}
+
+// events lists the releases, then the activity periods, of calendar as
+// all-day events. The end of an all-day event is the day after it.
+func events(calendar Calendar) []event {
+	repo := worksummary.RepoName(calendar.RepoURL)
+	list := make([]event, 0, len(calendar.Releases)+len(calendar.Periods))
+	for _, release := range calendar.Releases {
+		day := release.Date.Format(time.DateOnly)
+		start, _ := time.Parse(time.DateOnly, day)
+		description := "Commit " + release.Commit
+		if release.Message != "" {
+			description = release.Message + "\n\n" + description
+		}
+		list = append(list, event{
+			uid:         eventUID(calendar.RepoURL, "release", release.Tag),
+			start:       start,
+			end:         start.AddDate(0, 0, 1),
+			summary:     fmt.Sprintf("%s %s released", repo, release.Tag),
+			description: description,
+			category:    "Release",
+		})
+	}
+	for _, period := range calendar.Periods {
+		start, _ := time.Parse(time.DateOnly, period.Start)
+		end, _ := time.Parse(time.DateOnly, period.End)
+		list = append(list, event{
+			uid:     eventUID(calendar.RepoURL, "activity", calendar.Branch, period.Start),
+			start:   start,
+			end:     end.AddDate(0, 0, 1),
+			summary: fmt.Sprintf("%s: %d commits on %s", repo, period.Commits, calendar.Branch),
+			description: fmt.Sprintf(
+				"%d commits from %s to %s by %s",
+				period.Commits,
+				period.Start,
+				period.End,
+				strings.Join(period.Authors, ", "),
+			),
+			category: "Activity",
+		})
+	}
+	return list
+}
+
+// eventUID returns a globally unique, stable UID for the event named by
+// parts within a repository.
+func eventUID(repoURL string, parts ...string) string {
+	sum := sha256.Sum256([]byte(strings.Join(append([]string{repoURL}, parts...), "\x00")))
+	return hex.EncodeToString(sum[:16]) + "@dcr-mcp"
+}
+
+// escapeText escapes a TEXT value.
+func escapeText(text string) string {
+	return strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	).Replace(text)
+}
+
+// writeLine writes a content line ending in CRLF, folded so no line is
+// longer than maxLineOctets without splitting a UTF-8 sequence.
+func writeLine(sb *strings.Builder, line string) {
+	limit := maxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		sb.WriteString(line[:cut] + "\r\n ")
+		line = line[cut:]
+		// Continuation lines start with a space, which counts.
+		limit = maxLineOctets - 1
+	}
+	sb.WriteString(line + "\r\n")
+}
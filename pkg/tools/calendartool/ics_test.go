@@ -0,0 +1,76 @@
+package calendartool
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEscapeText(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, `a\, b\; c\\d\ne`, escapeText("a, b; c\\d\r\ne"))
+}
+
+func TestWriteLine(t *testing.T) {
+	t.Parallel()
+	var sb strings.Builder
+	writeLine(&sb, "SUMMARY:"+strings.Repeat("é", 100))
+	lines := strings.Split(strings.TrimSuffix(sb.String(), "\r\n"), "\r\n")
+	require.Greater(t, len(lines), 1)
+	var unfolded strings.Builder
+	for i, line := range lines {
+		assert.LessOrEqual(t, len(line), maxLineOctets, "line %d", i)
+		if i > 0 {
+			require.True(t, strings.HasPrefix(line, " "))
+			line = line[1:]
+		}
+		unfolded.WriteString(line)
+	}
+	assert.Equal(t, "SUMMARY:"+strings.Repeat("é", 100), unfolded.String(), "folding keeps UTF-8 sequences whole")
+}
+
+func TestEncodeICS(t *testing.T) {
+	t.Parallel()
+	calendar := Calendar{
+		RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+		Branch:  "main",
+		Releases: []Release{{
+			Tag:     "v1.0.0",
+			Commit:  "abc123",
+			Date:    time.Date(2025, time.March, 3, 23, 30, 0, 0, time.FixedZone("PDT", -7*3600)),
+			Message: "First release, finally",
+		}},
+		Periods: []Period{{Start: "2025-06-01", End: "2025-06-05", Commits: 7, Authors: []string{"Jane", "Joe"}}},
+	}
+	stamp := time.Date(2025, time.July, 1, 8, 0, 0, 0, time.UTC)
+	data := string(EncodeICS(calendar, stamp))
+	assert.True(t, strings.HasPrefix(data, "BEGIN:VCALENDAR\r\nVERSION:2.0\r\n"))
+	assert.True(t, strings.HasSuffix(data, "END:VCALENDAR\r\n"))
+	assert.NotContains(t, strings.ReplaceAll(data, "\r\n", ""), "\n", "every line ends in CRLF")
+	assert.Equal(t, 2, strings.Count(data, "BEGIN:VEVENT"))
+	for _, line := range []string{
+		"X-WR-CALNAME:dcr-mcp (main)",
+		"DTSTAMP:20250701T080000Z",
+		"DTSTART;VALUE=DATE:20250303",
+		"DTEND;VALUE=DATE:20250304",
+		"SUMMARY:dcr-mcp v1.0.0 released",
+		`DESCRIPTION:First release\, finally\n\nCommit abc123`,
+		"CATEGORIES:Release",
+		"DTSTART;VALUE=DATE:20250601",
+		"DTEND;VALUE=DATE:20250606",
+		"SUMMARY:dcr-mcp: 7 commits on main",
+		"CATEGORIES:Activity",
+	} {
+		assert.Contains(t, data, line+"\r\n")
+	}
+	assert.Equal(
+		t,
+		eventUID(calendar.RepoURL, "release", "v1.0.0"),
+		eventUID(calendar.RepoURL, "release", "v1.0.0"),
+		"UIDs are stable so reimports update events",
+	)
+	assert.NotEqual(t, eventUID(calendar.RepoURL, "release", "v1.0.0"), eventUID("other", "release", "v1.0.0"))
+}
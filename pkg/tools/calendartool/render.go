@@ -0,0 +1,45 @@
+package calendartool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// RenderMarkdown lists the events of a calendar and where it was written.
+func RenderMarkdown(calendar Calendar) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Calendar of %s (%s)\n\n", worksummary.RepoName(calendar.RepoURL), calendar.Branch)
+	fmt.Fprintf(
+		&sb,
+		"Wrote %d releases and %d activity periods to %s\n",
+		len(calendar.Releases),
+		len(calendar.Periods),
+		calendar.Path,
+	)
+	if calendar.URL != "" {
+		fmt.Fprintf(&sb, "Download URL: %s\n", calendar.URL)
+	}
+	if len(calendar.Releases) > 0 {
+		sb.WriteString("\n## Releases\n\n")
+		for _, release := range calendar.Releases {
+			fmt.Fprintf(&sb, "- %s: %s\n", release.Date.Format(time.DateOnly), release.Tag)
+		}
+	}
+	if len(calendar.Periods) > 0 {
+		sb.WriteString("\n## Activity\n\n")
+		for _, period := range calendar.Periods {
+			fmt.Fprintf(
+				&sb,
+				"- %s to %s: %d commits by %s\n",
+				period.Start,
+				period.End,
+				period.Commits,
+				strings.Join(period.Authors, ", "),
+			)
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,253 @@
+package citationtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Defaults of the optional parameters.
+const (
+	defaultDepth = 1
+	defaultLimit = 25
+)
+
+// doiRegex matches a DOI with an optional doi: or doi.org prefix and
+// captures the bare DOI.
+var doiRegex = regexp.MustCompile(`(?i)^(?:(?:https?://)?(?:dx\.)?doi\.org/|doi:)?\s*(10\.\S+/\S+)$`)
+
+// pmidRegex matches a PubMed ID.
+var pmidRegex = regexp.MustCompile(`^\d+$`)
+
+// CitationTool lists the references of an article and the papers citing
+// it.
+type CitationTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []literaturetool.Option
+}
+
+// ToolOption defines a functional option for configuring CitationTool.
+type ToolOption func(*CitationTool)
+
+// WithClientOptions sets options for the literature clients the tool
+// reaches EuropePMC with.
+func WithClientOptions(opts ...literaturetool.Option) ToolOption {
+	return func(c *CitationTool) {
+		c.clientOptions = append(c.clientOptions, opts...)
+	}
+}
+
+// CitationRequest represents the parameters for the citation lookup.
+type CitationRequest struct {
+	ID     string `validate:"required"`
+	IDType string `validate:"required,oneof=pmid doi"`
+	// Depth is the number of levels fetched; at depth 2 the references of
+	// the references and the citing papers of the citing papers are added.
+	Depth int `validate:"min=1,max=2"`
+	Limit int `validate:"min=1,max=100"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"literature-citations",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			clientOpts, err := literaturetool.CassetteOptions(deps.Literature)
+			if err != nil {
+				return nil, err
+			}
+			citationTool, err := NewCitationTool(deps.Logger, WithClientOptions(clientOpts...))
+			if err != nil {
+				return nil, err
+			}
+			return citationTool, nil
+		},
+	)
+}
+
+// NewCitationTool creates a new CitationTool instance.
+func NewCitationTool(logger *slog.Logger, opts ...ToolOption) (*CitationTool, error) {
+	tool := mcp.NewTool(
+		"literature-citations",
+		mcp.WithDescription(
+			"Lists the references of an article and the papers citing it, by PubMed ID or DOI, from EuropePMC",
+		),
+		mcp.WithTitleAnnotation("Literature Citations"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"id",
+			mcp.Description("The PubMed ID (PMID) or DOI identifier"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"id_type",
+			mcp.Description("Type of identifier: 'pmid' for PubMed IDs or 'doi' for DOI"),
+			mcp.Required(),
+			mcp.Enum(IDTypePMID, IDTypeDOI),
+		),
+		mcp.WithNumber(
+			"depth",
+			mcp.Description(
+				"Levels to follow, 1 (default) or 2; at 2 the references of each reference and the "+
+					"citing papers of each citing paper are listed too",
+			),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Most works listed per list, from 1 to 100 (default 25)"),
+		),
+	)
+	citationTool := &CitationTool{
+		Name:        "literature-citations",
+		Description: "Lists the references of an article and the papers citing it, from EuropePMC",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(citationTool)
+	}
+	return citationTool, nil
+}
+
+// GetName returns the name of the tool.
+func (c *CitationTool) GetName() string {
+	return c.Name
+}
+
+// GetDescription returns the description of the tool.
+func (c *CitationTool) GetDescription() string {
+	return c.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (c *CitationTool) GetSchema() mcp.ToolInputSchema {
+	return c.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (c *CitationTool) GetTool() mcp.Tool {
+	return c.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (c *CitationTool) GetAnnotations() mcp.ToolAnnotation {
+	return c.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (c *CitationTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the references and citing papers of an article",
+			Arguments: map[string]any{
+				"id":      "23172289",
+				"id_type": "pmid",
+			},
+		},
+		{
+			Description: "Follow citations two levels deep, ten works per list",
+			Arguments: map[string]any{
+				"id":      "10.1093/nar/gks1064",
+				"id_type": "doi",
+				"depth":   2,
+				"limit":   10,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (c *CitationTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := CitationRequest{
+		ID:     request.GetString("id", ""),
+		IDType: request.GetString("id_type", ""),
+		Depth:  request.GetInt("depth", defaultDepth),
+		Limit:  request.GetInt("limit", defaultLimit),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	id, err := normalizeID(params.ID, params.IDType)
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_ID",
+			err,
+			fmt.Sprintf("invalid %s format", params.IDType),
+		)), nil
+	}
+
+	logger := logging.WithRequestID(c.Logger)
+	client, err := NewEuropePMCClient(logger, c.clientOptions...)
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to create EuropePMC client: %w", err)), nil
+	}
+	article, err := client.Resolve(ctx, params.IDType, id)
+	if errors.Is(err, ErrArticleNotFound) {
+		return toolerror.Result(toolerror.Wrap(toolerror.TypeNotFound, "ARTICLE_NOT_FOUND", err, "lookup failed")), nil
+	}
+	if err != nil {
+		return toolerror.Result(toolerror.Upstream(metrics.ServiceEuropePMC, err, "lookup failed")), nil
+	}
+	graph, err := FetchGraph(ctx, client, article, params.Depth, params.Limit)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	logger.Info(
+		"fetched citations",
+		"source", article.Source,
+		"id", article.ID,
+		"references", graph.References.Total,
+		"citations", graph.Citations.Total,
+	)
+	return provenance.Attach(
+		mcp.NewToolResultText(RenderMarkdown(graph)),
+		provenance.New([]string{metrics.ServiceEuropePMC}),
+	), nil
+}
+
+// normalizeID validates an identifier, stripping the prefixes DOIs are
+// often written with.
+func normalizeID(id, idType string) (string, error) {
+	id = strings.TrimSpace(id)
+	switch idType {
+	case IDTypePMID:
+		if !pmidRegex.MatchString(id) {
+			return "", fmt.Errorf("PMID must contain only digits, got: %s", id)
+		}
+		return id, nil
+	case IDTypeDOI:
+		matches := doiRegex.FindStringSubmatch(id)
+		if matches == nil {
+			return "", fmt.Errorf("expected '10.xxxx/yyyy', got: %s", id)
+		}
+		return matches[1], nil
+	default:
+		return "", fmt.Errorf("unsupported ID type: %s", idType)
+	}
+}
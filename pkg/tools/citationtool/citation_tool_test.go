@@ -0,0 +1,126 @@
+package citationtool
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T) *CitationTool {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewCitationTool(logger, WithClientOptions(literaturetool.WithEuropePMCURL(newTestServer(t).URL)))
+	require.NoError(t, err)
+	return tool
+}
+
+func TestNewCitationTool(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	assert.Equal(t, "literature-citations", tool.GetName())
+	assert.NotEmpty(t, tool.GetDescription())
+	assert.Equal(t, []string{"id", "id_type"}, tool.GetSchema().Required)
+	annotations := tool.GetAnnotations()
+	assert.True(t, *annotations.ReadOnlyHint)
+	assert.True(t, *annotations.OpenWorldHint)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+
+	result := tooltest.Call(t, tool.Handler, "literature-citations", map[string]any{
+		"id": "https://doi.org/10.1093/nar/gks1064", "id_type": "doi", "depth": 2, "limit": 2,
+	})
+	require.False(t, result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "# Citations: dictyBase 2013: integrating multiple Dictyostelid species\n")
+	assert.Contains(t, text.Text, "40 references, showing the first 2.\n")
+	assert.Contains(t, text.Text, "   1. Older work. [PMID 101]")
+	assert.Contains(t, text.Text, "2 citing papers.\n")
+
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"europepmc"}, record.Providers)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		wantType  toolerror.Type
+	}{
+		{
+			name:      "missing id type",
+			arguments: map[string]any{"id": "23172289"},
+			wantType:  toolerror.TypeInvalidInput,
+		},
+		{
+			name:      "depth too deep",
+			arguments: map[string]any{"id": "23172289", "id_type": "pmid", "depth": 3},
+			wantType:  toolerror.TypeInvalidInput,
+		},
+		{
+			name:      "limit too large",
+			arguments: map[string]any{"id": "23172289", "id_type": "pmid", "limit": 500},
+			wantType:  toolerror.TypeInvalidInput,
+		},
+		{
+			name:      "malformed PMID",
+			arguments: map[string]any{"id": "PMC123", "id_type": "pmid"},
+			wantType:  toolerror.TypeInvalidInput,
+		},
+		{
+			name:      "unknown article",
+			arguments: map[string]any{"id": "1", "id_type": "pmid"},
+			wantType:  toolerror.TypeNotFound,
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			result := tooltest.Call(t, tool.Handler, "literature-citations", testCase.arguments)
+			require.True(t, result.IsError)
+			toolErr, ok := result.StructuredContent.(*toolerror.Error)
+			require.True(t, ok)
+			assert.Equal(t, testCase.wantType, toolErr.Type)
+		})
+	}
+}
+
+func TestNormalizeID(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		id      string
+		idType  string
+		want    string
+		wantErr bool
+	}{
+		{id: " 23172289 ", idType: IDTypePMID, want: "23172289"},
+		{id: "23172289a", idType: IDTypePMID, wantErr: true},
+		{id: "10.1093/nar/gks1064", idType: IDTypeDOI, want: "10.1093/nar/gks1064"},
+		{id: "doi:10.1093/nar/gks1064", idType: IDTypeDOI, want: "10.1093/nar/gks1064"},
+		{id: "https://dx.doi.org/10.1093/nar/gks1064", idType: IDTypeDOI, want: "10.1093/nar/gks1064"},
+		{id: "10.1093", idType: IDTypeDOI, wantErr: true},
+	}
+	for _, testCase := range tests {
+		got, err := normalizeID(testCase.id, testCase.idType)
+		if testCase.wantErr {
+			assert.Error(t, err, testCase.id)
+			continue
+		}
+		require.NoError(t, err, testCase.id)
+		assert.Equal(t, testCase.want, got)
+	}
+}
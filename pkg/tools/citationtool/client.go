@@ -0,0 +1,130 @@
+package citationtool
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+)
+
+// minPageSize is the smallest page EuropePMC returns; shorter lists are
+// cut after the response.
+const minPageSize = 25
+
+// Identifier types of the looked up article.
+const (
+	IDTypePMID = "pmid"
+	IDTypeDOI  = "doi"
+)
+
+// ErrArticleNotFound is returned when EuropePMC has no article with the
+// identifier.
+var ErrArticleNotFound = errors.New("article not found in EuropePMC")
+
+// EuropePMCClient reads reference lists and citing papers from the
+// EuropePMC REST API through the literature client, which rate limits,
+// retries and replays its requests.
+type EuropePMCClient struct {
+	literature *literaturetool.LiteratureClient
+	logger     *slog.Logger
+}
+
+// NewEuropePMCClient creates a new EuropePMC client logging to logger,
+// with the options of the literature client.
+func NewEuropePMCClient(logger *slog.Logger, opts ...literaturetool.Option) (*EuropePMCClient, error) {
+	literature, err := literaturetool.NewLiteratureClient(
+		append([]literaturetool.Option{literaturetool.WithLogger(logger)}, opts...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &EuropePMCClient{literature: literature, logger: logger}, nil
+}
+
+// Resolve looks up the article with a PMID or DOI.
+func (c *EuropePMCClient) Resolve(ctx context.Context, idType, id string) (Work, error) {
+	query := fmt.Sprintf("EXT_ID:%s AND SRC:MED", id)
+	if idType == IDTypeDOI {
+		query = fmt.Sprintf("DOI:%q", id)
+	}
+	values := url.Values{
+		"query":      {query},
+		"format":     {"json"},
+		"resultType": {"lite"},
+		"pageSize":   {"1"},
+	}
+	var response searchResponse
+	if err := c.literature.FetchEuropePMC(ctx, "/search?"+values.Encode(), &response); err != nil {
+		return Work{}, fmt.Errorf("failed to look up %s %s: %w", idType, id, err)
+	}
+	if len(response.ResultList.Result) == 0 {
+		return Work{}, fmt.Errorf("%w: %s %s", ErrArticleNotFound, idType, id)
+	}
+	return toWork(response.ResultList.Result[0]), nil
+}
+
+// References returns the first limit works of the reference list of work.
+func (c *EuropePMCClient) References(ctx context.Context, work Work, limit int) (List, error) {
+	var response referencesResponse
+	if err := c.literature.FetchEuropePMC(ctx, listPath(work, "references", limit), &response); err != nil {
+		return List{}, fmt.Errorf("failed to fetch references of %s %s: %w", work.Source, work.ID, err)
+	}
+	c.logger.Debug("fetched references", "source", work.Source, "id", work.ID, "total", response.HitCount)
+	return toList(response.HitCount, response.ReferenceList.Reference, limit), nil
+}
+
+// Citations returns the first limit works citing work.
+func (c *EuropePMCClient) Citations(ctx context.Context, work Work, limit int) (List, error) {
+	var response citationsResponse
+	if err := c.literature.FetchEuropePMC(ctx, listPath(work, "citations", limit), &response); err != nil {
+		return List{}, fmt.Errorf("failed to fetch citations of %s %s: %w", work.Source, work.ID, err)
+	}
+	c.logger.Debug("fetched citations", "source", work.Source, "id", work.ID, "total", response.HitCount)
+	return toList(response.HitCount, response.CitationList.Citation, limit), nil
+}
+
+// listPath returns the path of the first page of the references or
+// citations of work.
+func listPath(work Work, kind string, limit int) string {
+	values := url.Values{
+		"format":   {"json"},
+		"page":     {"1"},
+		"pageSize": {strconv.Itoa(max(limit, minPageSize))},
+	}
+	return fmt.Sprintf(
+		"/%s/%s/%s?%s",
+		url.PathEscape(work.Source), url.PathEscape(work.ID), kind, values.Encode(),
+	)
+}
+
+// toList converts a page of works, keeping the first limit.
+func toList(total int, works []workResponse, limit int) List {
+	list := List{Total: total}
+	for _, work := range works[:min(limit, len(works))] {
+		list.Nodes = append(list.Nodes, Node{Work: toWork(work)})
+	}
+	return list
+}
+
+// toWork converts a work of a EuropePMC response.
+func toWork(work workResponse) Work {
+	return Work{
+		ID:           work.ID,
+		Source:       work.Source,
+		Title:        strings.TrimSpace(work.Title),
+		Authors:      strings.TrimSpace(work.AuthorString),
+		Journal:      cmp.Or(work.JournalAbbreviation, work.JournalTitle),
+		Year:         string(work.PubYear),
+		Volume:       cmp.Or(work.Volume, work.JournalVolume),
+		Issue:        work.Issue,
+		Pages:        work.PageInfo,
+		DOI:          work.DOI,
+		CitedByCount: work.CitedByCount,
+	}
+}
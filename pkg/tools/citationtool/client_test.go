@@ -0,0 +1,129 @@
+package citationtool
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const searchJSON = `{"hitCount":1,"resultList":{"result":[{"id":"23172289","source":"MED","pmid":"23172289",
+	"doi":"10.1093/nar/gks1064","title":"dictyBase 2013: integrating multiple Dictyostelid species.",
+	"authorString":"Basu S, Fey P, Pandit Y, Dodson R, Kibbe WA, Chisholm RL.","journalTitle":"Nucleic Acids Res",
+	"issue":"Database issue","journalVolume":"41","pubYear":"2013","pageInfo":"D676-83","citedByCount":120}]}}`
+
+// listJSON holds the reference and citation lists of the test server, by
+// source, ID and kind.
+var listJSON = map[string]string{
+	"MED/23172289/references": `{"hitCount":40,"referenceList":{"reference":[
+		{"id":"100","source":"MED","title":"The genome of the social amoeba.","authorString":"Eichinger L, et al.",
+			"journalAbbreviation":"Nature","pubYear":2005,"volume":"435","issue":"7038","pageInfo":"43-57"},
+		{"id":"200","source":"MED","title":"dictyBase, the model organism database.","authorString":"Fey P, et al.",
+			"journalAbbreviation":"Nucleic Acids Res","pubYear":2009,"volume":"37"},
+		{"title":"Unpublished observations"}
+	]}}`,
+	"MED/23172289/citations": `{"hitCount":2,"citationList":{"citation":[
+		{"id":"300","source":"MED","title":"dictyBase 2015","authorString":"Fey P, et al.",
+			"journalAbbreviation":"Nucleic Acids Res","pubYear":2015,"citedByCount":12},
+		{"id":"PPR1","source":"PPR","title":"A preprint","pubYear":2024}
+	]}}`,
+	"MED/100/references": `{"hitCount":1,"referenceList":{"reference":[{"id":"101","source":"MED","title":"Older work"}]}}`,
+	"MED/300/citations":  `{"hitCount":0,"citationList":{"citation":[]}}`,
+	"PPR/PPR1/citations": `{"hitCount":0}`,
+}
+
+// newTestServer serves the search and list endpoints of EuropePMC. Lists
+// missing from listJSON fail.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Query().Get("query") {
+		case "EXT_ID:23172289 AND SRC:MED", `DOI:"10.1093/nar/gks1064"`:
+			_, _ = io.WriteString(w, searchJSON)
+		default:
+			_, _ = io.WriteString(w, `{"hitCount":0,"resultList":{"result":[]}}`)
+		}
+	})
+	mux.HandleFunc("GET /{source}/{id}/{kind}", func(w http.ResponseWriter, r *http.Request) {
+		body, ok := listJSON[r.PathValue("source")+"/"+r.PathValue("id")+"/"+r.PathValue("kind")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		assert.Equal(t, "json", r.URL.Query().Get("format"))
+		_, _ = io.WriteString(w, body)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient returns a client of the test server.
+func newTestClient(t *testing.T) *EuropePMCClient {
+	t.Helper()
+	client, err := NewEuropePMCClient(slog.Default(), literaturetool.WithEuropePMCURL(newTestServer(t).URL))
+	require.NoError(t, err)
+	return client
+}
+
+func TestEuropePMCClient_Resolve(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+
+	article, err := client.Resolve(context.Background(), IDTypeDOI, "10.1093/nar/gks1064")
+	require.NoError(t, err)
+	assert.Equal(t, Work{
+		ID:           "23172289",
+		Source:       "MED",
+		Title:        "dictyBase 2013: integrating multiple Dictyostelid species.",
+		Authors:      "Basu S, Fey P, Pandit Y, Dodson R, Kibbe WA, Chisholm RL.",
+		Journal:      "Nucleic Acids Res",
+		Year:         "2013",
+		Volume:       "41",
+		Issue:        "Database issue",
+		Pages:        "D676-83",
+		DOI:          "10.1093/nar/gks1064",
+		CitedByCount: 120,
+	}, article)
+
+	_, err = client.Resolve(context.Background(), IDTypePMID, "1")
+	require.ErrorIs(t, err, ErrArticleNotFound)
+}
+
+func TestEuropePMCClient_Lists(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	article := Work{ID: "23172289", Source: "MED"}
+
+	references, err := client.References(context.Background(), article, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 40, references.Total)
+	require.Len(t, references.Nodes, 2)
+	assert.Equal(t, Work{
+		ID:      "100",
+		Source:  "MED",
+		Title:   "The genome of the social amoeba.",
+		Authors: "Eichinger L, et al.",
+		Journal: "Nature",
+		Year:    "2005",
+		Volume:  "435",
+		Issue:   "7038",
+		Pages:   "43-57",
+	}, references.Nodes[0].Work)
+
+	citations, err := client.Citations(context.Background(), article, 25)
+	require.NoError(t, err)
+	assert.Equal(t, 2, citations.Total)
+	require.Len(t, citations.Nodes, 2)
+	assert.Equal(t, 12, citations.Nodes[0].Work.CitedByCount)
+
+	_, err = client.Citations(context.Background(), Work{ID: "404", Source: "MED"}, 25)
+	require.ErrorContains(t, err, "EuropePMC returned unexpected status 404")
+}
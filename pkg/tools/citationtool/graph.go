@@ -0,0 +1,98 @@
+package citationtool
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+)
+
+// lookupConcurrency is the number of lists fetched at a time.
+const lookupConcurrency = 4
+
+// listFetcher fetches the references or the citing papers of a work.
+type listFetcher func(ctx context.Context, work Work, limit int) (List, error)
+
+// lookup is a list to fetch.
+type lookup struct {
+	work  Work
+	fetch listFetcher
+	list  *List
+}
+
+// FetchGraph fetches the references and the citing papers of article. At
+// each further level of depth, the references of every reference and the
+// citing papers of every citing paper are fetched as well. Every list
+// holds at most limit works. A list that cannot be fetched is marked
+// unavailable rather than failing the lookup.
+func FetchGraph(ctx context.Context, client *EuropePMCClient, article Work, depth, limit int) (Graph, error) {
+	graph := Graph{Article: article, Depth: depth}
+	reporter := progress.FromContext(ctx)
+	level := []lookup{
+		{work: article, fetch: client.References, list: &graph.References},
+		{work: article, fetch: client.Citations, list: &graph.Citations},
+	}
+	done, total := 0, len(level)
+	for current := 1; ; current++ {
+		client.fetchLists(ctx, level, limit, func(message string) {
+			done++
+			reporter.Report(float64(done), float64(total), message)
+		})
+		if err := ctx.Err(); err != nil {
+			return Graph{}, fmt.Errorf("citation lookup aborted: %w", err)
+		}
+		if current == depth {
+			return graph, nil
+		}
+		var next []lookup
+		for _, parent := range level {
+			for i := range parent.list.Nodes {
+				node := &parent.list.Nodes[i]
+				if node.Work.ID == "" || node.Work.Source == "" {
+					continue
+				}
+				node.Next = &List{}
+				next = append(next, lookup{work: node.Work, fetch: parent.fetch, list: node.Next})
+			}
+		}
+		level = next
+		total += len(next)
+	}
+}
+
+// fetchLists fetches the lists of lookups concurrently, calling report
+// after each one; calls to report are serialized.
+func (c *EuropePMCClient) fetchLists(
+	ctx context.Context,
+	lookups []lookup,
+	limit int,
+	report func(message string),
+) {
+	semaphore := make(chan struct{}, lookupConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	for _, item := range lookups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				*item.list = List{Err: ctx.Err()}
+				return
+			}
+			list, err := item.fetch(ctx, item.work, limit)
+			if err != nil {
+				c.logger.Warn("failed to fetch citation list", "source", item.work.Source, "id", item.work.ID, "error", err)
+				list = List{Err: err}
+			}
+			*item.list = list
+			mu.Lock()
+			report(fmt.Sprintf("fetched %s %s", item.work.Source, item.work.ID))
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+}
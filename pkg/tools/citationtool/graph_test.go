@@ -0,0 +1,47 @@
+package citationtool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFetchGraph(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	article := Work{ID: "23172289", Source: "MED"}
+
+	graph, err := FetchGraph(context.Background(), client, article, 1, 25)
+	require.NoError(t, err)
+	assert.Len(t, graph.References.Nodes, 3)
+	assert.Len(t, graph.Citations.Nodes, 2)
+	for _, node := range append(graph.References.Nodes, graph.Citations.Nodes...) {
+		assert.Nil(t, node.Next)
+	}
+
+	graph, err = FetchGraph(context.Background(), client, article, 2, 25)
+	require.NoError(t, err)
+	references := graph.References.Nodes
+	require.NotNil(t, references[0].Next)
+	assert.Equal(t, []Node{{Work: Work{ID: "101", Source: "MED", Title: "Older work"}}}, references[0].Next.Nodes)
+	require.NotNil(t, references[1].Next)
+	require.Error(t, references[1].Next.Err, "an unavailable list does not fail the lookup")
+	assert.Nil(t, references[2].Next, "unmatched references are not followed")
+	citations := graph.Citations.Nodes
+	require.NotNil(t, citations[0].Next)
+	assert.Zero(t, citations[0].Next.Total)
+	require.NotNil(t, citations[1].Next)
+	require.NoError(t, citations[1].Next.Err)
+}
+
+func TestFetchGraph_Cancelled(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := FetchGraph(ctx, client, Work{ID: "23172289", Source: "MED"}, 1, 25)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,123 @@
+package citationtool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// articleURL is the EuropePMC page of a work, followed by its source and
+// ID.
+const articleURL = "https://europepmc.org/article/"
+
+// RenderMarkdown renders the citation graph as a markdown document. Works
+// of the next level are nested under the work they belong to.
+func RenderMarkdown(graph Graph) string {
+	var builder strings.Builder
+	title := graph.Article.Title
+	if title == "" {
+		title = graph.Article.Source + " " + graph.Article.ID
+	}
+	fmt.Fprintf(&builder, "# Citations: %s\n\n", strings.TrimSuffix(title, "."))
+	fmt.Fprintf(&builder, "**Article:** %s\n", FormatWork(graph.Article))
+
+	builder.WriteString("\n## References\n\n")
+	writeList(&builder, graph.References, "references", "")
+	builder.WriteString("\n## Cited By\n\n")
+	writeList(&builder, graph.Citations, "citing papers", "")
+	return builder.String()
+}
+
+// writeList renders a list and the lists nested in it, each line prefixed
+// with indent.
+func writeList(builder *strings.Builder, list List, noun, indent string) {
+	switch {
+	case list.Err != nil:
+		fmt.Fprintf(builder, "%s_Unavailable: %v_\n", indent, list.Err)
+		return
+	case list.Total == 0 && len(list.Nodes) == 0:
+		fmt.Fprintf(builder, "%sNo %s found.\n", indent, noun)
+		return
+	case len(list.Nodes) < list.Total:
+		fmt.Fprintf(builder, "%s%d %s, showing the first %d.\n\n", indent, list.Total, noun, len(list.Nodes))
+	default:
+		fmt.Fprintf(builder, "%s%d %s.\n\n", indent, len(list.Nodes), noun)
+	}
+	for i, node := range list.Nodes {
+		marker := fmt.Sprintf("%d. ", i+1)
+		fmt.Fprintf(builder, "%s%s%s\n", indent, marker, FormatWork(node.Work))
+		if node.Next != nil {
+			builder.WriteString("\n")
+			writeList(builder, *node.Next, noun, indent+strings.Repeat(" ", len(marker)))
+			builder.WriteString("\n")
+		}
+	}
+}
+
+// FormatWork renders a work as a citation line: authors, title, journal
+// details and a link to the work.
+func FormatWork(work Work) string {
+	var parts []string
+	if work.Authors != "" {
+		parts = append(parts, sentence(work.Authors))
+	}
+	if work.Title != "" {
+		parts = append(parts, sentence(work.Title))
+	}
+	if details := journalDetails(work); details != "" {
+		parts = append(parts, details+".")
+	}
+	var links []string
+	if work.ID != "" && work.Source != "" {
+		label := work.Source + " " + work.ID
+		if work.Source == "MED" {
+			label = "PMID " + work.ID
+		}
+		links = append(links, fmt.Sprintf("[%s](%s%s/%s)", label, articleURL, work.Source, work.ID))
+	}
+	if work.DOI != "" {
+		links = append(links, fmt.Sprintf("[doi:%s](https://doi.org/%s)", work.DOI, work.DOI))
+	}
+	if len(links) > 0 {
+		parts = append(parts, strings.Join(links, " "))
+	}
+	if work.CitedByCount > 0 {
+		parts = append(parts, fmt.Sprintf("(cited by %d)", work.CitedByCount))
+	}
+	if len(parts) == 0 {
+		return "_Unidentified work_"
+	}
+	return strings.Join(parts, " ")
+}
+
+// journalDetails renders journal, year, volume, issue and pages in the
+// Vancouver style, e.g. "*Dev Biol* 2010;12(3):45-67".
+func journalDetails(work Work) string {
+	var builder strings.Builder
+	if work.Journal != "" {
+		fmt.Fprintf(&builder, "*%s*", strings.TrimSuffix(work.Journal, "."))
+	}
+	if work.Year != "" {
+		if builder.Len() > 0 {
+			builder.WriteString(" ")
+		}
+		builder.WriteString(work.Year)
+	}
+	if work.Volume != "" {
+		fmt.Fprintf(&builder, ";%s", work.Volume)
+		if work.Issue != "" {
+			fmt.Fprintf(&builder, "(%s)", work.Issue)
+		}
+	}
+	if work.Pages != "" {
+		fmt.Fprintf(&builder, ":%s", work.Pages)
+	}
+	return builder.String()
+}
+
+// sentence ends text with a period.
+func sentence(text string) string {
+	if strings.HasSuffix(text, ".") || strings.HasSuffix(text, "?") || strings.HasSuffix(text, "!") {
+		return text
+	}
+	return text + "."
+}
@@ -0,0 +1,83 @@
+package citationtool
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatWork(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		work Work
+		want string
+	}{
+		{
+			name: "journal article",
+			work: Work{
+				ID: "100", Source: "MED", Title: "The genome of the social amoeba", Authors: "Eichinger L, et al.",
+				Journal: "Nature", Year: "2005", Volume: "435", Issue: "7038", Pages: "43-57",
+			},
+			want: "Eichinger L, et al. The genome of the social amoeba. *Nature* 2005;435(7038):43-57. " +
+				"[PMID 100](https://europepmc.org/article/MED/100)",
+		},
+		{
+			name: "preprint with DOI and citations",
+			work: Work{ID: "PPR1", Source: "PPR", Title: "A preprint?", Year: "2024", DOI: "10.1101/x", CitedByCount: 3},
+			want: "A preprint? 2024. [PPR PPR1](https://europepmc.org/article/PPR/PPR1) " +
+				"[doi:10.1101/x](https://doi.org/10.1101/x) (cited by 3)",
+		},
+		{
+			name: "unmatched reference",
+			work: Work{Title: "Unpublished observations"},
+			want: "Unpublished observations.",
+		},
+		{
+			name: "empty",
+			want: "_Unidentified work_",
+		},
+	}
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, testCase.want, FormatWork(testCase.work))
+		})
+	}
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	graph := Graph{
+		Article: Work{ID: "23172289", Source: "MED", Title: "dictyBase 2013."},
+		Depth:   2,
+		References: List{Total: 40, Nodes: []Node{
+			{Work: Work{ID: "100", Source: "MED", Title: "Genome"}, Next: &List{
+				Total: 1,
+				Nodes: []Node{{Work: Work{ID: "101", Source: "MED", Title: "Older work"}}},
+			}},
+			{Work: Work{ID: "200", Source: "MED", Title: "dictyBase"}, Next: &List{Err: errors.New("status 500")}},
+			{Work: Work{Title: "Unpublished observations"}},
+		}},
+		Citations: List{Err: errors.New("EuropePMC returned status 503")},
+	}
+
+	assert.Equal(t, "# Citations: dictyBase 2013\n\n"+
+		"**Article:** dictyBase 2013. [PMID 23172289](https://europepmc.org/article/MED/23172289)\n\n"+
+		"## References\n\n"+
+		"40 references, showing the first 3.\n\n"+
+		"1. Genome. [PMID 100](https://europepmc.org/article/MED/100)\n\n"+
+		"   1 references.\n\n"+
+		"   1. Older work. [PMID 101](https://europepmc.org/article/MED/101)\n\n"+
+		"2. dictyBase. [PMID 200](https://europepmc.org/article/MED/200)\n\n"+
+		"   _Unavailable: status 500_\n\n"+
+		"3. Unpublished observations.\n\n"+
+		"## Cited By\n\n"+
+		"_Unavailable: EuropePMC returned status 503_\n", RenderMarkdown(graph))
+
+	empty := RenderMarkdown(Graph{Article: Work{ID: "1", Source: "MED"}})
+	assert.Contains(t, empty, "# Citations: MED 1\n")
+	assert.Contains(t, empty, "No references found.\n")
+	assert.Contains(t, empty, "No citing papers found.\n")
+}
@@ -0,0 +1,111 @@
+package citationtool
+
+import "encoding/json"
+
+// Work is a paper in a citation list, reduced to the fields needed to cite
+// it.
+type Work struct {
+	// ID and Source identify the work in EuropePMC, e.g. a PMID with source
+	// MED. Unmatched references have neither.
+	ID           string
+	Source       string
+	Title        string
+	Authors      string
+	Journal      string
+	Year         string
+	Volume       string
+	Issue        string
+	Pages        string
+	DOI          string
+	CitedByCount int
+}
+
+// Node is a work in a citation list, with the list of the next level when
+// the lookup goes deeper.
+type Node struct {
+	Work Work
+	// Next holds the references of a reference or the citing papers of a
+	// citing paper. It is nil at the last level and for works EuropePMC
+	// cannot look up.
+	Next *List
+}
+
+// List is a reference list or the list of citing papers of a work.
+type List struct {
+	// Total is the number of works EuropePMC knows of, which may exceed
+	// the number listed.
+	Total int
+	Nodes []Node
+	// Err is set when the list could not be fetched.
+	Err error
+}
+
+// Graph is the citation neighbourhood of an article.
+type Graph struct {
+	Article    Work
+	Depth      int
+	References List
+	Citations  List
+}
+
+// searchResponse is the subset of a /search response used to resolve an
+// article.
+type searchResponse struct {
+	HitCount   int `json:"hitCount"`
+	ResultList struct {
+		Result []workResponse `json:"result"`
+	} `json:"resultList"`
+}
+
+// referencesResponse is the response of /{source}/{id}/references.
+type referencesResponse struct {
+	HitCount      int `json:"hitCount"`
+	ReferenceList struct {
+		Reference []workResponse `json:"reference"`
+	} `json:"referenceList"`
+}
+
+// citationsResponse is the response of /{source}/{id}/citations.
+type citationsResponse struct {
+	HitCount     int `json:"hitCount"`
+	CitationList struct {
+		Citation []workResponse `json:"citation"`
+	} `json:"citationList"`
+}
+
+// workResponse is a work as returned by the search, references and
+// citations endpoints, which share most field names.
+type workResponse struct {
+	ID                  string     `json:"id"`
+	Source              string     `json:"source"`
+	Title               string     `json:"title"`
+	AuthorString        string     `json:"authorString"`
+	JournalAbbreviation string     `json:"journalAbbreviation"`
+	JournalTitle        string     `json:"journalTitle"`
+	PubYear             yearString `json:"pubYear"`
+	Volume              string     `json:"volume"`
+	JournalVolume       string     `json:"journalVolume"`
+	Issue               string     `json:"issue"`
+	PageInfo            string     `json:"pageInfo"`
+	DOI                 string     `json:"doi"`
+	CitedByCount        int        `json:"citedByCount"`
+}
+
+// yearString decodes a year given as a JSON string or number; EuropePMC
+// uses both, depending on the endpoint.
+type yearString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (y *yearString) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*y = yearString(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*y = yearString(number.String())
+	return nil
+}
@@ -0,0 +1,363 @@
+package coveragetool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// baselineIDArgument is the argument naming an uploaded baseline profile.
+const baselineIDArgument = "baseline_upload_id"
+
+// CoverageTool reports the test coverage of Go packages.
+type CoverageTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+	resources   *resources.Catalog
+	uploads     *upload.Store
+	runTests    bool
+}
+
+// Option defines a functional option for configuring CoverageTool.
+type Option func(*CoverageTool)
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(c *CoverageTool) {
+		c.resources = catalog
+	}
+}
+
+// WithUploads lets calls pass the profiles as completed uploads.
+func WithUploads(store *upload.Store) Option {
+	return func(c *CoverageTool) {
+		c.uploads = store
+	}
+}
+
+// WithAnalyzer replaces the analyzer repositories are cloned with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) Option {
+	return func(c *CoverageTool) {
+		c.analyzer = analyzer
+	}
+}
+
+// WithTestRuns enables the run source, which runs go test in a clone of
+// the requested repository.
+func WithTestRuns(enabled bool) Option {
+	return func(c *CoverageTool) {
+		c.runTests = enabled
+	}
+}
+
+// CoverageRequest represents the parameters for a coverage report.
+type CoverageRequest struct {
+	Source  string `validate:"required,oneof=profile run"`
+	RepoURL string `validate:"required_if=Source run"`
+	Branch  string `validate:"required_if=Source run"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"coverage-report",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewCoverageTool(
+				deps.Logger,
+				WithResources(deps.Resources),
+				WithUploads(deps.Uploads),
+				WithTestRuns(deps.RunTests),
+			)
+		},
+	)
+}
+
+// NewCoverageTool creates a new CoverageTool instance.
+func NewCoverageTool(logger *slog.Logger, opts ...Option) (*CoverageTool, error) {
+	tool := mcp.NewTool(
+		"coverage-report",
+		mcp.WithDescription(
+			"Reports the test coverage of each Go package from a coverage profile, or from running go test "+
+				"in a clone of a branch when the server allows it, with the change from a prior profile",
+		),
+		mcp.WithTitleAnnotation("Coverage Report"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"source",
+			mcp.Description(
+				"Where the profile comes from: 'profile' (default) reads the profile argument; "+
+					"'run' runs go test -coverprofile in a clone of the branch, if enabled on the server",
+			),
+			mcp.Enum(SourceProfile, SourceRun),
+		),
+		mcp.WithString(
+			"profile",
+			mcp.Description("A coverage profile written by go test -coverprofile, for the profile source"),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the profile, instead of profile"),
+		),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository, for the run source"),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to test, for the run source"),
+		),
+		mcp.WithString(
+			"baseline",
+			mcp.Description("A prior coverage profile to report the change from"),
+		),
+		mcp.WithString(
+			baselineIDArgument,
+			mcp.Description("Handle of a completed upload holding the prior profile, instead of baseline"),
+		),
+	)
+	coverageTool := &CoverageTool{
+		Name:        "coverage-report",
+		Description: "Reports the test coverage of Go packages",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(coverageTool)
+	}
+	return coverageTool, nil
+}
+
+// GetName returns the name of the tool.
+func (c *CoverageTool) GetName() string {
+	return c.Name
+}
+
+// GetDescription returns the description of the tool.
+func (c *CoverageTool) GetDescription() string {
+	return c.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (c *CoverageTool) GetSchema() mcp.ToolInputSchema {
+	return c.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (c *CoverageTool) GetTool() mcp.Tool {
+	return c.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (c *CoverageTool) GetAnnotations() mcp.ToolAnnotation {
+	return c.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (c *CoverageTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Run the tests of a branch and compare the coverage with an uploaded earlier profile",
+			Arguments: map[string]any{
+				"source":             "run",
+				"repo_url":           "https://github.com/dictybase/modware-stock",
+				"branch":             "develop",
+				"baseline_upload_id": "upl_2b8e13f07c5a94d6e1b3a820",
+			},
+		},
+		{
+			Description: "Report coverage from an uploaded Go coverage profile",
+			Arguments: map[string]any{
+				"source":    "profile",
+				"upload_id": "upl_7d41e0c39a2b58f61e0d4c7a",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (c *CoverageTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := CoverageRequest{
+		Source:  request.GetString("source", SourceProfile),
+		RepoURL: request.GetString("repo_url", ""),
+		Branch:  request.GetString("branch", ""),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	baseline, err := c.readBaseline(request)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	var report Report
+	if params.Source == SourceRun {
+		report, err = c.RunCoverage(ctx, params)
+	} else {
+		report, err = c.readProfile(request)
+	}
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	report.Baseline = baseline
+
+	content := RenderMarkdown(report)
+	result := mcp.NewToolResultText(content)
+	name := "coverage.md"
+	if params.Source == SourceRun {
+		result = provenance.Attach(result, provenance.New([]string{metrics.ServiceGitClone}))
+		name = fmt.Sprintf("%s-%s-coverage.md", worksummary.RepoName(params.RepoURL), params.Branch)
+	}
+	if c.resources != nil {
+		resource, err := c.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        name,
+			MIMEType:    "text/markdown",
+			Description: "Test coverage report",
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing report: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// readProfile reads the coverage of the profile passed to the call.
+func (c *CoverageTool) readProfile(request mcp.CallToolRequest) (Report, error) {
+	text, err := upload.TextArgument(c.uploads, request, "profile")
+	if err != nil {
+		return Report{}, err
+	}
+	profile, err := ParseProfile(text)
+	if err != nil {
+		return Report{}, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_PROFILE", err, "invalid profile")
+	}
+	return Report{Source: SourceProfile, Mode: profile.Mode, Packages: profile.Packages()}, nil
+}
+
+// readBaseline reads the coverage of the prior profile, if the call passes
+// one.
+func (c *CoverageTool) readBaseline(request mcp.CallToolRequest) ([]PackageCoverage, error) {
+	arguments := request.GetArguments()
+	if arguments["baseline"] == nil && arguments[baselineIDArgument] == nil {
+		return nil, nil
+	}
+	text, err := upload.TextArgumentFrom(c.uploads, request, "baseline", baselineIDArgument)
+	if err != nil {
+		return nil, err
+	}
+	profile, err := ParseProfile(text)
+	if err != nil {
+		return nil, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_BASELINE", err, "invalid baseline profile")
+	}
+	return profile.Packages(), nil
+}
+
+// RunCoverage clones the branch, exports its head to a temporary directory
+// and runs go test -coverprofile there. The tested code sees neither the
+// server's files nor its credentials, but it does run on the server, so
+// runs must be enabled explicitly.
+func (c *CoverageTool) RunCoverage(ctx context.Context, params CoverageRequest) (Report, error) {
+	if !c.runTests {
+		return Report{}, toolerror.New(
+			toolerror.TypeConfiguration,
+			"TEST_RUNS_DISABLED",
+			"running tests is not enabled on this server; start it with --coverage-run or pass a profile",
+		)
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := c.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.3)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Report{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	head, err := repo.Head()
+	if err != nil {
+		return Report{}, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Report{}, fmt.Errorf("error reading branch head: %w", err)
+	}
+	tree, err := tip.Tree()
+	if err != nil {
+		return Report{}, fmt.Errorf("error reading file tree: %w", err)
+	}
+	if _, err := tree.File("go.mod"); errors.Is(err, object.ErrFileNotFound) {
+		return Report{}, toolerror.New(
+			toolerror.TypeInvalidInput,
+			"NOT_A_GO_MODULE",
+			"the branch has no go.mod at the root of the repository",
+		)
+	}
+
+	reporter.Report(0.3, 1, "exporting files")
+	workDir, err := os.MkdirTemp("", "coverage-")
+	if err != nil {
+		return Report{}, fmt.Errorf("error creating work directory: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+	moduleDir := filepath.Join(workDir, "module")
+	if err := ExportTree(tree, moduleDir); err != nil {
+		return Report{}, err
+	}
+
+	reporter.Report(0.4, 1, "running tests")
+	run, err := RunTests(ctx, moduleDir)
+	if errors.Is(err, ErrGoNotFound) {
+		return Report{}, toolerror.Wrap(toolerror.TypeConfiguration, "GO_NOT_FOUND", err, "cannot run tests")
+	}
+	if err != nil {
+		return Report{}, err
+	}
+	profile, err := ParseProfile(run.Profile)
+	if err != nil {
+		return Report{}, fmt.Errorf("error reading the profile of the test run: %w", err)
+	}
+	reporter.Report(1, 1, "coverage collected")
+	return Report{
+		Source:      SourceRun,
+		RepoURL:     params.RepoURL,
+		Branch:      params.Branch,
+		Commit:      tip.Hash.String(),
+		When:        tip.Committer.When,
+		Mode:        profile.Mode,
+		Packages:    profile.Packages(),
+		TestsFailed: run.Failed,
+	}, nil
+}
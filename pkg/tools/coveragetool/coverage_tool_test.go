@@ -0,0 +1,125 @@
+package coveragetool
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T, opts ...Option) *CoverageTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(worksummary.WithTimeZone(time.UTC))
+	tool, err := NewCoverageTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]Option{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestHandler_Profile(t *testing.T) {
+	t.Parallel()
+	store := upload.NewStore()
+	info, err := store.Begin(upload.BeginParams{Name: "prior.out", ContentType: "text/plain"})
+	require.NoError(t, err)
+	_, err = store.Append(upload.AppendParams{ID: info.ID, Data: []byte("mode: set\nexample.org/calc/calc.go:4.2,5.1 3 0\n")})
+	require.NoError(t, err)
+	_, err = store.Complete(info.ID, "")
+	require.NoError(t, err)
+	tool := newTestTool(t, WithUploads(store))
+
+	text := tooltest.Text(t, tooltest.Call(t, tool.Handler, "coverage-report", map[string]any{
+		"profile": testProfile, "baseline_upload_id": info.ID,
+	}))
+	assert.Contains(t, text, "40.0% of 5 statements covered, up 40.0 points from 0.0% in the baseline.\n")
+	assert.Contains(t, text, "| example.org/calc | 3 | 2 | 66.7% | +66.7 |\n")
+	assert.Contains(t, text, "| example.org/calc/format | 2 | 0 | 0.0% | new |\n")
+}
+
+func TestHandler_Run(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, moduleFiles)
+	tool := newTestTool(t, WithTestRuns(true))
+
+	text := tooltest.Text(t, tooltest.Call(t, tool.Handler, "coverage-report", map[string]any{
+		"source":   "run",
+		"repo_url": dir,
+		"branch":   "master",
+		"baseline": "mode: set\nexample.org/calc/calc.go:4.2,5.1 1 0\nexample.org/calc/calc.go:8.2,9.1 1 0\n",
+	}))
+	assert.Contains(t, text, "**Repository:** "+dir+" (branch `master`")
+	assert.Contains(t, text, "| example.org/calc | 2 | 1 | 50.0% | +50.0 |\n")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	notModule := gittest.InitRepo(t, map[string]string{"README.md": "# Notes\n"})
+	tests := []struct {
+		name      string
+		tool      *CoverageTool
+		arguments map[string]any
+		errorType toolerror.Type
+		code      string
+	}{
+		{
+			name:      "missing profile",
+			tool:      newTestTool(t),
+			arguments: map[string]any{},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "MISSING_PARAMETER",
+		},
+		{
+			name:      "invalid profile",
+			tool:      newTestTool(t),
+			arguments: map[string]any{"profile": "coverage: 80%"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_PROFILE",
+		},
+		{
+			name:      "invalid baseline",
+			tool:      newTestTool(t),
+			arguments: map[string]any{"profile": testProfile, "baseline": "mode: set\nbroken\n"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_BASELINE",
+		},
+		{
+			name:      "run without branch",
+			tool:      newTestTool(t, WithTestRuns(true)),
+			arguments: map[string]any{"source": "run", "repo_url": notModule},
+			errorType: toolerror.TypeInvalidInput,
+		},
+		{
+			name:      "runs disabled",
+			tool:      newTestTool(t),
+			arguments: map[string]any{"source": "run", "repo_url": notModule, "branch": "master"},
+			errorType: toolerror.TypeConfiguration,
+			code:      "TEST_RUNS_DISABLED",
+		},
+		{
+			name:      "not a module",
+			tool:      newTestTool(t, WithTestRuns(true)),
+			arguments: map[string]any{"source": "run", "repo_url": notModule, "branch": "master"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "NOT_A_GO_MODULE",
+		},
+	}
+	for _, testCase := range tests {
+		result := tooltest.Call(t, testCase.tool.Handler, "coverage-report", testCase.arguments)
+		require.True(t, result.IsError, testCase.name)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok, testCase.name)
+		assert.Equal(t, testCase.errorType, toolErr.Type, testCase.name)
+		if testCase.code != "" {
+			assert.Equal(t, testCase.code, toolErr.Code, testCase.name)
+		}
+	}
+}
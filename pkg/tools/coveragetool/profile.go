@@ -0,0 +1,136 @@
+package coveragetool
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// ModeSet is the coverage mode recording only whether a block ran.
+const ModeSet = "set"
+
+// blockRegex matches a block line of a coverage profile:
+// file:startLine.startCol,endLine.endCol statements count.
+var blockRegex = regexp.MustCompile(`^(.+):(\d+)\.(\d+),(\d+)\.(\d+) (\d+) (\d+)$`)
+
+// Block is a basic block of a coverage profile.
+type Block struct {
+	File       string
+	Position   string
+	Statements int
+	Count      int
+}
+
+// Profile is a parsed Go coverage profile, as written by
+// go test -coverprofile.
+type Profile struct {
+	Mode   string
+	Blocks []Block
+}
+
+// PackageCoverage is the statement coverage of one package.
+type PackageCoverage struct {
+	Package    string
+	Statements int
+	Covered    int
+}
+
+// Percent returns the share of covered statements in percent; a package
+// without statements has none.
+func (p PackageCoverage) Percent() float64 {
+	if p.Statements == 0 {
+		return 0
+	}
+	return float64(p.Covered) * 100 / float64(p.Statements)
+}
+
+// ParseProfile parses a coverage profile. Profiles concatenated from several
+// runs are accepted; a block listed more than once is merged the way
+// go tool cover does, keeping whether it ran in set mode and adding the
+// counts otherwise.
+func ParseProfile(text string) (Profile, error) {
+	var profile Profile
+	index := make(map[string]int)
+	for number, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		if mode, found := strings.CutPrefix(line, "mode: "); found {
+			if profile.Mode != "" && mode != profile.Mode {
+				return Profile{}, fmt.Errorf("line %d: mode %q differs from %q", number+1, mode, profile.Mode)
+			}
+			profile.Mode = mode
+			continue
+		}
+		if profile.Mode == "" {
+			return Profile{}, fmt.Errorf("line %d: profile does not start with a mode line", number+1)
+		}
+		match := blockRegex.FindStringSubmatch(line)
+		if match == nil {
+			return Profile{}, fmt.Errorf("line %d: invalid block %q", number+1, line)
+		}
+		statements, _ := strconv.Atoi(match[6])
+		count, _ := strconv.Atoi(match[7])
+		block := Block{
+			File:       match[1],
+			Position:   fmt.Sprintf("%s.%s,%s.%s", match[2], match[3], match[4], match[5]),
+			Statements: statements,
+			Count:      count,
+		}
+		key := block.File + ":" + block.Position
+		existing, found := index[key]
+		if !found {
+			index[key] = len(profile.Blocks)
+			profile.Blocks = append(profile.Blocks, block)
+			continue
+		}
+		if profile.Mode == ModeSet {
+			profile.Blocks[existing].Count = max(profile.Blocks[existing].Count, count)
+		} else {
+			profile.Blocks[existing].Count += count
+		}
+	}
+	if profile.Mode == "" {
+		return Profile{}, fmt.Errorf("profile is empty")
+	}
+	return profile, nil
+}
+
+// Packages returns the coverage of each package of the profile, ordered by
+// import path.
+func (p Profile) Packages() []PackageCoverage {
+	var packages []PackageCoverage
+	for _, block := range p.Blocks {
+		name := path.Dir(block.File)
+		index := slices.IndexFunc(packages, func(pkg PackageCoverage) bool {
+			return pkg.Package == name
+		})
+		if index < 0 {
+			packages = append(packages, PackageCoverage{Package: name})
+			index = len(packages) - 1
+		}
+		packages[index].Statements += block.Statements
+		if block.Count > 0 {
+			packages[index].Covered += block.Statements
+		}
+	}
+	slices.SortFunc(packages, func(a, b PackageCoverage) int {
+		return cmp.Compare(a.Package, b.Package)
+	})
+	return packages
+}
+
+// Total sums the coverage of packages.
+func Total(packages []PackageCoverage) PackageCoverage {
+	total := PackageCoverage{Package: "Total"}
+	for _, pkg := range packages {
+		total.Statements += pkg.Statements
+		total.Covered += pkg.Covered
+	}
+	return total
+}
@@ -0,0 +1,64 @@
+package coveragetool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testProfile covers two of the three statements of calc and none of
+// format.
+const testProfile = `mode: set
+example.org/calc/calc.go:4.2,5.1 1 1
+example.org/calc/calc.go:8.2,9.1 1 0
+example.org/calc/calc.go:12.2,14.1 1 1
+example.org/calc/format/format.go:3.2,4.1 2 0
+`
+
+func TestParseProfile(t *testing.T) {
+	t.Parallel()
+	profile, err := ParseProfile(testProfile)
+	require.NoError(t, err)
+	assert.Equal(t, ModeSet, profile.Mode)
+	assert.Len(t, profile.Blocks, 4)
+	assert.Equal(t, Block{File: "example.org/calc/calc.go", Position: "8.2,9.1", Statements: 1}, profile.Blocks[1])
+	assert.Equal(t, []PackageCoverage{
+		{Package: "example.org/calc", Statements: 3, Covered: 2},
+		{Package: "example.org/calc/format", Statements: 2},
+	}, profile.Packages())
+}
+
+func TestParseProfile_Merge(t *testing.T) {
+	t.Parallel()
+	set, err := ParseProfile("mode: set\na/b.go:1.1,2.1 1 0\nmode: set\na/b.go:1.1,2.1 1 1\na/b.go:1.1,2.1 1 0\n")
+	require.NoError(t, err)
+	assert.Equal(t, []Block{{File: "a/b.go", Position: "1.1,2.1", Statements: 1, Count: 1}}, set.Blocks)
+
+	count, err := ParseProfile("mode: count\na/b.go:1.1,2.1 1 2\na/b.go:1.1,2.1 1 3\n")
+	require.NoError(t, err)
+	assert.Equal(t, 5, count.Blocks[0].Count)
+}
+
+func TestParseProfile_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, text := range []string{
+		"",
+		"a/b.go:1.1,2.1 1 0\n",
+		"mode: set\na/b.go 1 0\n",
+		"mode: set\nmode: count\n",
+	} {
+		_, err := ParseProfile(text)
+		assert.Error(t, err, text)
+	}
+}
+
+func TestPercent(t *testing.T) {
+	t.Parallel()
+	assert.InDelta(t, 66.67, PackageCoverage{Statements: 3, Covered: 2}.Percent(), 0.01)
+	assert.Zero(t, PackageCoverage{}.Percent())
+	assert.Equal(t, PackageCoverage{Package: "Total", Statements: 5, Covered: 2}, Total([]PackageCoverage{
+		{Package: "a", Statements: 3, Covered: 2},
+		{Package: "b", Statements: 2},
+	}))
+}
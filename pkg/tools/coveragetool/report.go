@@ -0,0 +1,127 @@
+package coveragetool
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Sources of the coverage profile.
+const (
+	// SourceProfile is a profile passed to the tool.
+	SourceProfile = "profile"
+	// SourceRun is a profile produced by running the tests of a clone.
+	SourceRun = "run"
+)
+
+// Report is the coverage of a profile, optionally compared with a baseline.
+type Report struct {
+	Source string
+	// RepoURL, Branch, Commit and When are only known for a run.
+	RepoURL  string
+	Branch   string
+	Commit   string
+	When     time.Time
+	Mode     string
+	Packages []PackageCoverage
+	// Baseline is the coverage of the prior profile; nil without one.
+	Baseline []PackageCoverage
+	// TestsFailed is set when go test failed, so the coverage of some
+	// packages is missing or partial.
+	TestsFailed bool
+}
+
+// RenderMarkdown renders the report as a markdown document.
+func RenderMarkdown(report Report) string {
+	var builder strings.Builder
+	if report.Source == SourceRun {
+		fmt.Fprintf(&builder, "# Coverage Report: %s\n\n", worksummary.RepoName(report.RepoURL))
+		fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`, commit `%s` of %s)\n",
+			report.RepoURL, report.Branch, worksummary.ShortHash(report.Commit), report.When.Format(time.DateOnly))
+	} else {
+		builder.WriteString("# Coverage Report\n\n")
+		fmt.Fprintf(&builder, "**Profile:** mode `%s`, %d packages\n", report.Mode, len(report.Packages))
+	}
+
+	total := Total(report.Packages)
+	builder.WriteString("\n## Overview\n\n")
+	if total.Statements == 0 {
+		builder.WriteString("The profile has no statements.")
+	} else {
+		fmt.Fprintf(&builder, "%.1f%% of %d statements covered", total.Percent(), total.Statements)
+		if report.Baseline != nil {
+			baseline := Total(report.Baseline)
+			fmt.Fprintf(&builder, ", %s from %.1f%% in the baseline", trend(total, baseline), baseline.Percent())
+		}
+		builder.WriteString(".")
+	}
+	if report.TestsFailed {
+		builder.WriteString(" Tests failed in some packages, so their coverage is missing or partial.")
+	}
+	builder.WriteString("\n")
+	if len(report.Packages) == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString("\n## Packages\n\n")
+	if report.Baseline == nil {
+		builder.WriteString("| Package | Statements | Covered | Coverage |\n|---|---|---|---|\n")
+	} else {
+		builder.WriteString("| Package | Statements | Covered | Coverage | Change |\n|---|---|---|---|---|\n")
+	}
+	for _, pkg := range append(report.Packages, total) {
+		fmt.Fprintf(&builder, "| %s | %d | %d | %.1f%% |", pkg.Package, pkg.Statements, pkg.Covered, pkg.Percent())
+		if report.Baseline != nil {
+			fmt.Fprintf(&builder, " %s |", change(pkg, report.Baseline))
+		}
+		builder.WriteString("\n")
+	}
+	if removed := removedPackages(report.Packages, report.Baseline); len(removed) > 0 {
+		fmt.Fprintf(&builder, "\nOnly in the baseline: %s.\n", strings.Join(removed, ", "))
+	}
+	return builder.String()
+}
+
+// trend describes the change of coverage from baseline to current.
+func trend(current, baseline PackageCoverage) string {
+	points := current.Percent() - baseline.Percent()
+	switch {
+	case points >= 0.05:
+		return fmt.Sprintf("up %.1f points", points)
+	case points <= -0.05:
+		return fmt.Sprintf("down %.1f points", -points)
+	default:
+		return "unchanged"
+	}
+}
+
+// change renders the change in coverage points of pkg from the baseline;
+// the total row is compared with the baseline total.
+func change(pkg PackageCoverage, baseline []PackageCoverage) string {
+	previous := Total(baseline)
+	if pkg.Package != previous.Package {
+		index := slices.IndexFunc(baseline, func(prior PackageCoverage) bool {
+			return prior.Package == pkg.Package
+		})
+		if index < 0 {
+			return "new"
+		}
+		previous = baseline[index]
+	}
+	return fmt.Sprintf("%+.1f", pkg.Percent()-previous.Percent())
+}
+
+// removedPackages returns the packages of the baseline missing from
+// packages.
+func removedPackages(packages, baseline []PackageCoverage) []string {
+	var removed []string
+	for _, prior := range baseline {
+		if !slices.ContainsFunc(packages, func(pkg PackageCoverage) bool { return pkg.Package == prior.Package }) {
+			removed = append(removed, prior.Package)
+		}
+	}
+	return removed
+}
@@ -0,0 +1,75 @@
+package coveragetool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	report := Report{
+		Source:  SourceRun,
+		RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+		Branch:  "develop",
+		Commit:  "9e8d7c6aaaa",
+		When:    time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+		Mode:    ModeSet,
+		Packages: []PackageCoverage{
+			{Package: "example.org/calc", Statements: 40, Covered: 30},
+			{Package: "example.org/calc/format", Statements: 10, Covered: 5},
+			{Package: "example.org/calc/parse", Statements: 50, Covered: 45},
+		},
+		Baseline: []PackageCoverage{
+			{Package: "example.org/calc", Statements: 40, Covered: 32},
+			{Package: "example.org/calc/format", Statements: 10, Covered: 5},
+			{Package: "example.org/calc/legacy", Statements: 50, Covered: 10},
+		},
+		TestsFailed: true,
+	}
+	assert.Equal(t, "# Coverage Report: dcr-mcp\n\n"+
+		"**Repository:** https://github.com/dictybase/dcr-mcp.git (branch `develop`, commit `9e8d7c6` of 2025-06-28)\n\n"+
+		"## Overview\n\n"+
+		"80.0% of 100 statements covered, up 33.0 points from 47.0% in the baseline. "+
+		"Tests failed in some packages, so their coverage is missing or partial.\n\n"+
+		"## Packages\n\n"+
+		"| Package | Statements | Covered | Coverage | Change |\n|---|---|---|---|---|\n"+
+		"| example.org/calc | 40 | 30 | 75.0% | -5.0 |\n"+
+		"| example.org/calc/format | 10 | 5 | 50.0% | +0.0 |\n"+
+		"| example.org/calc/parse | 50 | 45 | 90.0% | new |\n"+
+		"| Total | 100 | 80 | 80.0% | +33.0 |\n\n"+
+		"Only in the baseline: example.org/calc/legacy.\n", RenderMarkdown(report))
+}
+
+func TestRenderMarkdown_Profile(t *testing.T) {
+	t.Parallel()
+	report := Report{
+		Source:   SourceProfile,
+		Mode:     "atomic",
+		Packages: []PackageCoverage{{Package: "example.org/calc", Statements: 4, Covered: 1}},
+	}
+	assert.Equal(t, "# Coverage Report\n\n"+
+		"**Profile:** mode `atomic`, 1 packages\n\n"+
+		"## Overview\n\n25.0% of 4 statements covered.\n\n"+
+		"## Packages\n\n"+
+		"| Package | Statements | Covered | Coverage |\n|---|---|---|---|\n"+
+		"| example.org/calc | 4 | 1 | 25.0% |\n"+
+		"| Total | 4 | 1 | 25.0% |\n", RenderMarkdown(report))
+
+	empty := RenderMarkdown(Report{Source: SourceProfile, Mode: ModeSet})
+	assert.Contains(t, empty, "## Overview\n\nThe profile has no statements.\n")
+	assert.NotContains(t, empty, "## Packages")
+}
+
+func TestTrend(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "down 10.0 points", trend(
+		PackageCoverage{Statements: 10, Covered: 5},
+		PackageCoverage{Statements: 10, Covered: 6},
+	))
+	assert.Equal(t, "unchanged", trend(
+		PackageCoverage{Statements: 10, Covered: 5},
+		PackageCoverage{Statements: 20, Covered: 10},
+	))
+}
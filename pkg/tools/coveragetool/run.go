@@ -0,0 +1,134 @@
+package coveragetool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// passedEnv are the environment variables go test sees. Everything else,
+// notably the API tokens of the server, is withheld from the tested code.
+var passedEnv = []string{
+	"PATH", "HOME", "TMPDIR", "GOPATH", "GOCACHE", "GOMODCACHE",
+	"GOPROXY", "GONOPROXY", "GOPRIVATE", "GONOSUMDB", "GOSUMDB",
+}
+
+// maxOutput is the size of go test output kept for error messages.
+const maxOutput = 4 << 10
+
+// profileName is the file go test writes the profile to, outside the
+// exported tree so tests cannot overwrite it by accident.
+const profileName = "coverage.out"
+
+// ErrGoNotFound is returned when the go command is not installed.
+var ErrGoNotFound = errors.New("the go command is not installed on the server")
+
+// RunResult is the outcome of a test run.
+type RunResult struct {
+	Profile string
+	// Failed is set when go test exited with an error but still wrote
+	// coverage for some packages.
+	Failed bool
+}
+
+// ExportTree writes the regular files of tree below dir. Symbolic links and
+// submodules are skipped, so nothing outside dir is written or read.
+func ExportTree(tree *object.Tree, dir string) error {
+	return tree.Files().ForEach(func(file *object.File) error {
+		if file.Mode != filemode.Regular && file.Mode != filemode.Executable {
+			return nil
+		}
+		target := filepath.Join(dir, filepath.FromSlash(file.Name))
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(filepath.Separator)) {
+			return fmt.Errorf("file %s is outside the tree", file.Name)
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0o750); err != nil {
+			return fmt.Errorf("error creating directory for %s: %w", file.Name, err)
+		}
+		reader, err := file.Reader()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		defer reader.Close()
+		perm := os.FileMode(0o640)
+		if file.Mode == filemode.Executable {
+			perm = 0o750
+		}
+		out, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, perm)
+		if err != nil {
+			return fmt.Errorf("error writing %s: %w", file.Name, err)
+		}
+		if _, err := io.Copy(out, reader); err != nil {
+			out.Close()
+			return fmt.Errorf("error writing %s: %w", file.Name, err)
+		}
+		return out.Close()
+	})
+}
+
+// RunTests runs go test -coverprofile on all packages of the module in
+// dir, writing the profile to a file next to it. The command is killed
+// when ctx is done.
+func RunTests(ctx context.Context, dir string) (RunResult, error) {
+	goBin, err := exec.LookPath("go")
+	if err != nil {
+		return RunResult{}, ErrGoNotFound
+	}
+	profilePath := filepath.Join(filepath.Dir(dir), profileName)
+	if err := os.Remove(profilePath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return RunResult{}, fmt.Errorf("error removing the previous profile: %w", err)
+	}
+	cmd := exec.CommandContext(ctx, goBin, "test", "-coverprofile="+profilePath, "./...")
+	cmd.Dir = dir
+	cmd.Env = testEnv()
+	cmd.WaitDelay = 5 * time.Second
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	runErr := cmd.Run()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return RunResult{}, fmt.Errorf("test run aborted: %w", ctxErr)
+	}
+	// A failed build still writes the mode line, but no blocks.
+	profile, err := os.ReadFile(profilePath)
+	hasBlocks := err == nil && bytes.Contains(bytes.TrimSpace(profile), []byte("\n"))
+	if runErr != nil && !hasBlocks {
+		return RunResult{}, fmt.Errorf("go test failed: %w\n%s", runErr, tail(output.String()))
+	}
+	if err != nil {
+		return RunResult{}, fmt.Errorf("error reading the coverage profile: %w", err)
+	}
+	return RunResult{Profile: string(profile), Failed: runErr != nil}, nil
+}
+
+// testEnv returns the environment of the test run: the passed variables
+// of the server, and GOTOOLCHAIN=local so go does not download another
+// toolchain the module asks for.
+func testEnv() []string {
+	env := []string{"GOTOOLCHAIN=local"}
+	for _, name := range passedEnv {
+		if value, ok := os.LookupEnv(name); ok {
+			env = append(env, name+"="+value)
+		}
+	}
+	return env
+}
+
+// tail returns the end of the go test output.
+func tail(output string) string {
+	output = strings.TrimSpace(output)
+	if len(output) > maxOutput {
+		return "..." + output[len(output)-maxOutput:]
+	}
+	return output
+}
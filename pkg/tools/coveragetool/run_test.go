@@ -0,0 +1,92 @@
+package coveragetool
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// moduleFiles is a Go module whose test covers Add but not Sub.
+var moduleFiles = map[string]string{
+	"go.mod":  "module example.org/calc\n\ngo 1.21\n",
+	"calc.go": "package calc\n\nfunc Add(a, b int) int {\n\treturn a + b\n}\n\nfunc Sub(a, b int) int {\n\treturn a - b\n}\n",
+	"calc_test.go": "package calc\n\nimport \"testing\"\n\n" +
+		"func TestAdd(t *testing.T) {\n\tif Add(1, 2) != 3 {\n\t\tt.Fatal(\"wrong sum\")\n\t}\n}\n",
+	"scripts/check.sh": "#!/bin/sh\nexit 0\n",
+}
+
+// headTree returns the tree of the head commit of the repository at dir.
+func headTree(t *testing.T, dir string) *object.Tree {
+	t.Helper()
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+	return tree
+}
+
+func TestExportTree(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, ExportTree(headTree(t, gittest.InitRepo(t, moduleFiles)), dir))
+
+	content, err := os.ReadFile(filepath.Join(dir, "scripts", "check.sh"))
+	require.NoError(t, err)
+	assert.Equal(t, moduleFiles["scripts/check.sh"], string(content))
+	assert.FileExists(t, filepath.Join(dir, "go.mod"))
+	assert.NoDirExists(t, filepath.Join(dir, ".git"))
+}
+
+func TestRunTests(t *testing.T) {
+	t.Parallel()
+	dir := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, ExportTree(headTree(t, gittest.InitRepo(t, moduleFiles)), dir))
+
+	run, err := RunTests(context.Background(), dir)
+	require.NoError(t, err)
+	assert.False(t, run.Failed)
+	profile, err := ParseProfile(run.Profile)
+	require.NoError(t, err)
+	assert.Equal(t, []PackageCoverage{{Package: "example.org/calc", Statements: 2, Covered: 1}}, profile.Packages())
+}
+
+func TestRunTests_Failing(t *testing.T) {
+	t.Parallel()
+	files := map[string]string{
+		"go.mod":  moduleFiles["go.mod"],
+		"calc.go": moduleFiles["calc.go"],
+		"calc_test.go": "package calc\n\nimport \"testing\"\n\n" +
+			"func TestSub(t *testing.T) {\n\tif Sub(1, 2) != 3 {\n\t\tt.Fatal(\"wrong difference\")\n\t}\n}\n",
+	}
+	dir := filepath.Join(t.TempDir(), "module")
+	require.NoError(t, ExportTree(headTree(t, gittest.InitRepo(t, files)), dir))
+
+	run, err := RunTests(context.Background(), dir)
+	require.NoError(t, err)
+	assert.True(t, run.Failed)
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "calc.go"), []byte("package calc\n\nfunc ("), 0o600))
+	_, err = RunTests(context.Background(), dir)
+	require.Error(t, err, "a build failure writes no profile")
+	assert.Contains(t, err.Error(), "go test failed")
+}
+
+func TestTestEnv(t *testing.T) {
+	t.Setenv("GITHUB_TOKEN", "secret")
+	t.Setenv("GOPROXY", "off")
+	env := testEnv()
+	assert.Contains(t, env, "GOTOOLCHAIN=local")
+	assert.Contains(t, env, "GOPROXY=off")
+	assert.NotContains(t, env, "GITHUB_TOKEN=secret")
+}
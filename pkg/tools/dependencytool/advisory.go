@@ -0,0 +1,226 @@
+package dependencytool
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Severity is the severity of a vulnerability as rated by the GitHub
+// Advisory Database.
+type Severity string
+
+// Severities, in the order they are reported.
+const (
+	SeverityCritical Severity = "critical"
+	SeverityHigh     Severity = "high"
+	SeverityModerate Severity = "moderate"
+	SeverityLow      Severity = "low"
+	// SeverityUnknown is used for advisories without a rating, such as
+	// those of the Go vulnerability database.
+	SeverityUnknown Severity = "unknown"
+)
+
+// severityOrder is the order of the severities in reports.
+var severityOrder = []Severity{
+	SeverityCritical, SeverityHigh, SeverityModerate, SeverityLow, SeverityUnknown,
+}
+
+// severityTitles names the sections of each severity.
+var severityTitles = map[Severity]string{
+	SeverityCritical: "Critical",
+	SeverityHigh:     "High",
+	SeverityModerate: "Moderate",
+	SeverityLow:      "Low",
+	SeverityUnknown:  "Unrated",
+}
+
+// osvVulnerabilityURL is the page of a vulnerability, followed by its ID.
+const osvVulnerabilityURL = "https://osv.dev/vulnerability/"
+
+// parseSeverity reads a severity rating; medium is another name for
+// moderate.
+func parseSeverity(rating string) Severity {
+	switch strings.ToLower(rating) {
+	case "critical":
+		return SeverityCritical
+	case "high":
+		return SeverityHigh
+	case "moderate", "medium":
+		return SeverityModerate
+	case "low":
+		return SeverityLow
+	default:
+		return SeverityUnknown
+	}
+}
+
+// severityRank returns the position of severity in reports.
+func severityRank(severity Severity) int {
+	return slices.Index(severityOrder, severity)
+}
+
+// Finding is a vulnerability affecting a dependency of a manifest.
+type Finding struct {
+	Manifest      string
+	Dependency    Dependency
+	Vulnerability Vulnerability
+}
+
+// AdvisoryReport holds the known vulnerabilities of the dependencies at
+// the end of the compared span.
+type AdvisoryReport struct {
+	// Checked is the number of dependencies looked up.
+	Checked int
+	// Skipped is the number of dependencies whose version is not a
+	// semantic version, such as git URLs or dist tags.
+	Skipped  int
+	Findings []Finding
+	// Err is set when the advisory database could not be queried.
+	Err error
+}
+
+// Counts returns the number of findings of each severity.
+func (a AdvisoryReport) Counts() map[Severity]int {
+	counts := make(map[Severity]int)
+	for _, finding := range a.Findings {
+		counts[finding.Vulnerability.Severity]++
+	}
+	return counts
+}
+
+// checked is a dependency looked up in the advisory database.
+type checked struct {
+	manifest   string
+	dependency Dependency
+	query      Query
+}
+
+// checkAdvisories looks up the dependencies of every manifest at commit.
+// Manifests that cannot be parsed are left out, as the digest already
+// reports them.
+func checkAdvisories(ctx context.Context, client *OSVClient, commit *object.Commit) (*AdvisoryReport, error) {
+	files, err := ReadManifests(commit)
+	if err != nil {
+		return nil, err
+	}
+	report := &AdvisoryReport{}
+	var lookups []checked
+	for file, content := range files {
+		deps, err := ParseManifest(file, content)
+		if err != nil {
+			continue
+		}
+		for _, dep := range deps {
+			query, ok := advisoryQuery(file, dep)
+			if !ok {
+				report.Skipped++
+				continue
+			}
+			lookups = append(lookups, checked{manifest: file, dependency: dep, query: query})
+		}
+	}
+	report.Checked = len(lookups)
+	if len(lookups) == 0 {
+		return report, nil
+	}
+
+	// Look up each package version once, however many manifests use it.
+	var queries []Query
+	for _, lookup := range lookups {
+		if !slices.Contains(queries, lookup.query) {
+			queries = append(queries, lookup.query)
+		}
+	}
+	vulns, err := client.Vulnerabilities(ctx, queries)
+	if err != nil {
+		report.Err = err
+		return report, nil
+	}
+	for _, lookup := range lookups {
+		for _, vuln := range vulns[slices.Index(queries, lookup.query)] {
+			report.Findings = append(report.Findings, Finding{
+				Manifest:      lookup.manifest,
+				Dependency:    lookup.dependency,
+				Vulnerability: vuln,
+			})
+		}
+	}
+	slices.SortFunc(report.Findings, func(a, b Finding) int {
+		return cmp.Or(
+			severityRank(a.Vulnerability.Severity)-severityRank(b.Vulnerability.Severity),
+			cmp.Compare(a.Dependency.Name, b.Dependency.Name),
+			cmp.Compare(a.Manifest, b.Manifest),
+			cmp.Compare(a.Vulnerability.ID, b.Vulnerability.ID),
+		)
+	})
+	return report, nil
+}
+
+// advisoryQuery builds the lookup of a dependency. npm ranges are looked
+// up at their lower bound, the oldest version they allow. It reports false
+// for versions that are not semantic versions.
+func advisoryQuery(file string, dep Dependency) (Query, bool) {
+	version := NormalizeVersion(dep.Version)
+	if version == "" {
+		return Query{}, false
+	}
+	ecosystem := EcosystemNPM
+	if path.Base(file) == ManifestGoMod {
+		ecosystem = EcosystemGo
+	}
+	return Query{Ecosystem: ecosystem, Name: dep.Name, Version: strings.TrimPrefix(version, "v")}, true
+}
+
+// writeAdvisories renders the findings, grouped by severity.
+func writeAdvisories(builder *strings.Builder, report *AdvisoryReport) {
+	builder.WriteString("\n## Security Advisories\n\n")
+	if report.Err != nil {
+		fmt.Fprintf(builder, "_Unavailable: %v_\n", report.Err)
+		return
+	}
+	counts := report.Counts()
+	if len(report.Findings) == 0 {
+		fmt.Fprintf(builder, "No known vulnerabilities in %d checked dependencies.", report.Checked)
+	} else {
+		parts := make([]string, 0, len(severityOrder))
+		for _, severity := range severityOrder {
+			if counts[severity] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[severity], severity))
+			}
+		}
+		fmt.Fprintf(builder, "%d known vulnerabilities in %d checked dependencies: %s.",
+			len(report.Findings), report.Checked, strings.Join(parts, ", "))
+	}
+	if report.Skipped > 0 {
+		fmt.Fprintf(builder, " %d dependencies without a semantic version were not checked.", report.Skipped)
+	}
+	builder.WriteString("\n")
+	for _, severity := range severityOrder {
+		if counts[severity] == 0 {
+			continue
+		}
+		fmt.Fprintf(builder, "\n### %s\n\n", severityTitles[severity])
+		builder.WriteString("| Advisory | Dependency | Version | Manifest | Fixed in | Summary |\n|---|---|---|---|---|---|\n")
+		for _, finding := range report.Findings {
+			if finding.Vulnerability.Severity != severity {
+				continue
+			}
+			vuln := finding.Vulnerability
+			fmt.Fprintf(builder, "| [%s](%s%s) | `%s` | %s | %s | %s | %s |\n",
+				vuln.ID, osvVulnerabilityURL, vuln.ID,
+				finding.Dependency.Name, code(finding.Dependency.Version), finding.Manifest,
+				strings.Join(vuln.Fixed, ", "), tableCell(vuln.Summary))
+		}
+	}
+}
+
+// tableCell keeps text from breaking a markdown table row.
+func tableCell(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", `\|`)
+}
@@ -0,0 +1,447 @@
+package dependencytool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// DependencyTool digests the dependency updates of a repository, including
+// those made by the bots the work summaries leave out.
+type DependencyTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	analyzer      *worksummary.GitAnalyzer
+	resources     *resources.Catalog
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring DependencyTool.
+type ToolOption func(*DependencyTool)
+
+// WithResources publishes generated digests as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(d *DependencyTool) {
+		d.resources = catalog
+	}
+}
+
+// WithClientOptions sets options for the OSV clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(d *DependencyTool) {
+		d.clientOptions = append(d.clientOptions, opts...)
+	}
+}
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(d *DependencyTool) {
+		d.analyzer = analyzer
+	}
+}
+
+// DependencyRequest represents the parameters for a dependency digest. The
+// span is given either by dates or by refs.
+type DependencyRequest struct {
+	RepoURL   string `validate:"required"`
+	Branch    string `validate:"required"`
+	StartDate string `validate:"required_without=FromRef,excluded_with=FromRef"`
+	EndDate   string `validate:"excluded_with=FromRef"`
+	FromRef   string
+	ToRef     string `validate:"excluded_with=StartDate"`
+	// CheckAdvisories looks up the dependencies at the end of the span in
+	// the OSV database.
+	CheckAdvisories bool
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"dependency-digest",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewDependencyTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewDependencyTool creates a new DependencyTool instance.
+func NewDependencyTool(logger *slog.Logger, opts ...ToolOption) (*DependencyTool, error) {
+	tool := mcp.NewTool(
+		"dependency-digest",
+		mcp.WithDescription(
+			"Summarizes the dependencies added, removed and updated in the go.mod and package.json "+
+				"files of a git repository between two dates or refs, with major, minor and patch updates apart, "+
+				"and flags known vulnerabilities of the dependencies",
+		),
+		mcp.WithTitleAnnotation("Dependency Digest"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to analyze"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description("Compare from the last commit before this date; required unless from_ref is given"),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description("Compare to the last commit up to this date (optional, defaults to today)"),
+		),
+		mcp.WithString(
+			"from_ref",
+			mcp.Description("Compare from this tag, branch or commit instead of a date"),
+		),
+		mcp.WithString(
+			"to_ref",
+			mcp.Description("Compare to this tag, branch or commit (optional with from_ref, defaults to the branch head)"),
+		),
+		mcp.WithBoolean(
+			"check_advisories",
+			mcp.Description(
+				"Check the dependencies at the end of the span for known vulnerabilities in OSV, "+
+					"which includes the GitHub Advisory Database (optional, defaults to true)",
+			),
+		),
+	)
+	dependencyTool := &DependencyTool{
+		Name:        "dependency-digest",
+		Description: "Summarizes the dependency changes of a repository",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(dependencyTool)
+	}
+	return dependencyTool, nil
+}
+
+// GetName returns the name of the tool.
+func (d *DependencyTool) GetName() string {
+	return d.Name
+}
+
+// GetDescription returns the description of the tool.
+func (d *DependencyTool) GetDescription() string {
+	return d.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (d *DependencyTool) GetSchema() mcp.ToolInputSchema {
+	return d.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (d *DependencyTool) GetTool() mcp.Tool {
+	return d.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (d *DependencyTool) GetAnnotations() mcp.ToolAnnotation {
+	return d.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (d *DependencyTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Digest the dependency changes of a month and check them for advisories",
+			Arguments: map[string]any{
+				"repo_url":         "https://github.com/dictybase/dcr-mcp",
+				"branch":           "main",
+				"start_date":       "2024-05-01",
+				"end_date":         "2024-05-31",
+				"check_advisories": true,
+			},
+		},
+		{
+			Description: "Digest the dependency changes between two tags",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-stock",
+				"branch":   "develop",
+				"from_ref": "v2.3.0",
+				"to_ref":   "v2.4.0",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (d *DependencyTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := DependencyRequest{
+		RepoURL:         request.GetString("repo_url", ""),
+		Branch:          request.GetString("branch", ""),
+		StartDate:       request.GetString("start_date", ""),
+		EndDate:         request.GetString("end_date", ""),
+		FromRef:         request.GetString("from_ref", ""),
+		ToRef:           request.GetString("to_ref", ""),
+		CheckAdvisories: request.GetBool("check_advisories", true),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	digest, err := d.GenerateDigest(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(digest)
+	providers := []string{metrics.ServiceGitClone}
+	if params.CheckAdvisories {
+		providers = append(providers, metrics.ServiceOSV)
+	}
+	result := provenance.Attach(
+		mcp.NewToolResultText(content),
+		provenance.New(providers),
+	)
+	if d.resources != nil {
+		resource, err := d.resources.Publish(resources.PublishParams{
+			Kind: resources.KindGitSummary,
+			Name: fmt.Sprintf(
+				"%s-%s-dependencies-%s.md",
+				worksummary.RepoName(params.RepoURL), params.Branch, worksummary.ShortHash(digest.To.Hash),
+			),
+			MIMEType:    "text/markdown",
+			Description: "Dependency changes of " + params.RepoURL,
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing digest: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// GenerateDigest clones the repository, picks the revisions to compare and
+// diffs the dependencies of every manifest between them.
+func (d *DependencyTool) GenerateDigest(ctx context.Context, params DependencyRequest) (Digest, error) {
+	digest := Digest{RepoURL: params.RepoURL, Branch: params.Branch}
+	if params.StartDate != "" {
+		dateRange, err := d.analyzer.ResolveDateRange(params.StartDate, params.EndDate)
+		if err != nil {
+			return Digest{}, toolerror.Wrap(
+				toolerror.TypeInvalidInput,
+				"INVALID_DATE_RANGE",
+				err,
+				"failed to parse dates",
+			)
+		}
+		digest.Range = &dateRange
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := d.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.8)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Digest{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+
+	reporter.Report(0.8, 1, "comparing manifests")
+	var fromCommit, toCommit *object.Commit
+	if digest.Range != nil {
+		fromCommit, toCommit, err = commitsInRange(repo.Repository, *digest.Range)
+	} else {
+		fromCommit, toCommit, err = commitsAtRefs(repo.Repository, params.FromRef, params.ToRef)
+	}
+	if err != nil {
+		return Digest{}, err
+	}
+	digest.From = revision(fromCommit, fromSource(params))
+	digest.To = revision(toCommit, toSource(params))
+	digest.Manifests, err = diffManifests(fromCommit, toCommit)
+	if err != nil {
+		return Digest{}, err
+	}
+	if params.CheckAdvisories {
+		reporter.Report(0.9, 1, "checking security advisories")
+		client := NewOSVClient(append([]Option{WithLogger(d.Logger)}, d.clientOptions...)...)
+		digest.Advisories, err = checkAdvisories(ctx, client, toCommit)
+		if err != nil {
+			return Digest{}, err
+		}
+	}
+	reporter.Report(1, 1, "digest generated")
+	return digest, nil
+}
+
+// commitsInRange returns the last commit before the start of the range and
+// the last commit up to its end. The first is nil when the branch has no
+// commit before the range.
+func commitsInRange(repo *git.Repository, dateRange worksummary.DateRange) (*object.Commit, *object.Commit, error) {
+	head, err := repo.Head()
+	if err != nil {
+		return nil, nil, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	from, err := lastCommit(repo, head.Hash(), dateRange.Start.Add(-time.Nanosecond))
+	if err != nil {
+		return nil, nil, err
+	}
+	to, err := lastCommit(repo, head.Hash(), dateRange.End)
+	if err != nil {
+		return nil, nil, err
+	}
+	if to == nil {
+		return nil, nil, toolerror.New(
+			toolerror.TypeNotFound,
+			"NO_COMMITS",
+			"the branch has no commits up to the end date",
+		)
+	}
+	return from, to, nil
+}
+
+// lastCommit returns the newest commit reachable from head and committed
+// up to until, or nil when there is none.
+func lastCommit(repo *git.Repository, head plumbing.Hash, until time.Time) (*object.Commit, error) {
+	iter, err := repo.Log(&git.LogOptions{From: head, Until: &until, Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit history: %w", err)
+	}
+	defer iter.Close()
+	commit, err := iter.Next()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading commit history: %w", err)
+	}
+	return commit, nil
+}
+
+// commitsAtRefs resolves the refs to compare; an empty toRef is the branch
+// head.
+func commitsAtRefs(repo *git.Repository, fromRef, toRef string) (*object.Commit, *object.Commit, error) {
+	from, err := worksummary.ResolveRef(repo, fromRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	if toRef == "" {
+		toRef = "HEAD"
+	}
+	to, err := worksummary.ResolveRef(repo, toRef)
+	if err != nil {
+		return nil, nil, err
+	}
+	return from, to, nil
+}
+
+// fromSource tells how the start revision was chosen.
+func fromSource(params DependencyRequest) string {
+	if params.FromRef != "" {
+		return "from_ref " + params.FromRef
+	}
+	return "last commit before the start date"
+}
+
+// toSource tells how the end revision was chosen.
+func toSource(params DependencyRequest) string {
+	switch {
+	case params.ToRef != "":
+		return "to_ref " + params.ToRef
+	case params.FromRef != "":
+		return "head of " + params.Branch
+	default:
+		return "last commit up to the end date"
+	}
+}
+
+// revision describes commit, which may be nil.
+func revision(commit *object.Commit, source string) Revision {
+	if commit == nil {
+		return Revision{Source: source}
+	}
+	return Revision{Hash: commit.Hash.String(), When: commit.Committer.When, Source: source}
+}
+
+// diffManifests compares the manifests found at either commit. A nil
+// commit has no manifests.
+func diffManifests(from, to *object.Commit) ([]ManifestDiff, error) {
+	fromFiles, err := ReadManifests(from)
+	if err != nil {
+		return nil, err
+	}
+	toFiles, err := ReadManifests(to)
+	if err != nil {
+		return nil, err
+	}
+	paths := make([]string, 0, len(toFiles))
+	for file := range toFiles {
+		paths = append(paths, file)
+	}
+	for file := range fromFiles {
+		if _, ok := toFiles[file]; !ok {
+			paths = append(paths, file)
+		}
+	}
+	slices.Sort(paths)
+
+	diffs := make([]ManifestDiff, 0, len(paths))
+	for _, file := range paths {
+		diff := ManifestDiff{Path: file}
+		oldDeps, err := parseOptional(file, fromFiles)
+		if err != nil {
+			diff.Err = err
+			diffs = append(diffs, diff)
+			continue
+		}
+		newDeps, err := parseOptional(file, toFiles)
+		if err != nil {
+			diff.Err = err
+			diffs = append(diffs, diff)
+			continue
+		}
+		diff.Changes = diffDependencies(oldDeps, newDeps)
+		diffs = append(diffs, diff)
+	}
+	return diffs, nil
+}
+
+// parseOptional parses the manifest at file, which has no dependencies
+// when it does not exist.
+func parseOptional(file string, contents map[string]string) (map[string]Dependency, error) {
+	content, ok := contents[file]
+	if !ok {
+		return nil, nil
+	}
+	return ParseManifest(file, content)
+}
@@ -0,0 +1,150 @@
+package dependencytool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// dependabotCommit is a dependency bump by dependabot, days after the
+// first commit of the test repositories.
+func dependabotCommit(days int, tag string, files map[string]string) gittest.Commit {
+	return gittest.Commit{
+		Files:   files,
+		Message: "chore(deps): bump",
+		Author:  object.Signature{Name: "dependabot[bot]", Email: "bot@example.org", When: gittest.Author.When.AddDate(0, 0, days)},
+		Tag:     tag,
+	}
+}
+
+func newTestTool(t *testing.T, opts ...ToolOption) *DependencyTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(
+		worksummary.WithCurrentTime(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)),
+		worksummary.WithTimeZone(time.UTC),
+	)
+	tool, err := NewDependencyTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]ToolOption{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestGenerateDigest(t *testing.T) {
+	t.Parallel()
+	dir := gittest.NewRepo(t,
+		dependabotCommit(0, "v1.0.0", map[string]string{
+			"go.mod":           "module example.org/app\n\nrequire example.org/lib v1.2.0\n",
+			"web/package.json": `{"dependencies": {"react": "^18.2.0"}}`,
+		}),
+		dependabotCommit(1, "", map[string]string{
+			"go.mod": "module example.org/app\n\nrequire example.org/lib v1.3.0\n",
+		}),
+		dependabotCommit(2, "", map[string]string{
+			"web/package.json": `{"dependencies": {"react": "^19.0.0"}, "devDependencies": {"vitest": "^1.6.0"}}`,
+		}),
+	).Dir
+	tool := newTestTool(t)
+
+	byRef, err := tool.GenerateDigest(context.Background(), DependencyRequest{
+		RepoURL: dir,
+		Branch:  "master",
+		FromRef: "v1.0.0",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "from_ref v1.0.0", byRef.From.Source)
+	assert.Equal(t, "head of master", byRef.To.Source)
+	assert.Equal(t, []ManifestDiff{
+		{Path: "go.mod", Changes: []Change{{Name: "example.org/lib", Kind: KindMinor, From: "v1.2.0", To: "v1.3.0"}}},
+		{Path: "web/package.json", Changes: []Change{
+			{Name: "react", Kind: KindMajor, From: "^18.2.0", To: "^19.0.0"},
+			{Name: "vitest", Kind: KindAdded, To: "^1.6.0", Scope: "dev"},
+		}},
+	}, byRef.Manifests)
+
+	byDate, err := tool.GenerateDigest(context.Background(), DependencyRequest{
+		RepoURL:   dir,
+		Branch:    "master",
+		StartDate: "2025-06-03",
+		EndDate:   "2025-06-03",
+	})
+	require.NoError(t, err)
+	require.NotNil(t, byDate.Range)
+	assert.Equal(t, byRef.From.Hash, byDate.From.Hash)
+	assert.Equal(t, []ManifestDiff{
+		{Path: "go.mod", Changes: []Change{{Name: "example.org/lib", Kind: KindMinor, From: "v1.2.0", To: "v1.3.0"}}},
+		{Path: "web/package.json"},
+	}, byDate.Manifests)
+
+	_, err = tool.GenerateDigest(context.Background(), DependencyRequest{
+		RepoURL: dir,
+		Branch:  "master",
+		FromRef: "v9.9.9",
+	})
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, toolerror.TypeNotFound, toolErr.Type)
+}
+
+func TestGenerateDigest_Advisories(t *testing.T) {
+	t.Parallel()
+	dir := gittest.NewRepo(t,
+		dependabotCommit(0, "v1.0.0", map[string]string{
+			"go.mod":           "module example.org/app\n\nrequire example.org/lib v1.2.0\n",
+			"web/package.json": `{"dependencies": {"react": "^19.0.0", "local": "file:../local"}}`,
+		}),
+		dependabotCommit(1, "", map[string]string{
+			"go.mod": "module example.org/app\n\nrequire (\n\texample.org/lib v1.3.0\n\texample.org/safe v1.0.0\n)\n",
+		}),
+	).Dir
+	server, _ := newOSVServer(t)
+	tool := newTestTool(t, WithClientOptions(WithBaseURL(server.URL)))
+
+	digest, err := tool.GenerateDigest(context.Background(), DependencyRequest{
+		RepoURL:         dir,
+		Branch:          "master",
+		FromRef:         "v1.0.0",
+		CheckAdvisories: true,
+	})
+	require.NoError(t, err)
+	require.NotNil(t, digest.Advisories)
+	assert.Equal(t, 3, digest.Advisories.Checked)
+	assert.Equal(t, 1, digest.Advisories.Skipped)
+	require.NoError(t, digest.Advisories.Err)
+	require.Len(t, digest.Advisories.Findings, 2)
+	assert.Equal(t, "GHSA-aaaa-bbbb-cccc", digest.Advisories.Findings[0].Vulnerability.ID)
+	assert.Equal(t, "go.mod", digest.Advisories.Findings[0].Manifest)
+	assert.Equal(t, "GHSA-dddd-eeee-ffff", digest.Advisories.Findings[1].Vulnerability.ID)
+	assert.Equal(t, "web/package.json", digest.Advisories.Findings[1].Manifest)
+
+	unchecked, err := tool.GenerateDigest(context.Background(), DependencyRequest{
+		RepoURL: dir,
+		Branch:  "master",
+		FromRef: "v1.0.0",
+	})
+	require.NoError(t, err)
+	assert.Nil(t, unchecked.Advisories)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	for _, arguments := range []map[string]any{
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "develop"},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "develop", "start_date": "last month", "from_ref": "v1.0.0"},
+	} {
+		result := tooltest.Call(t, tool.Handler, "dependency-digest", arguments)
+		assert.True(t, result.IsError)
+	}
+}
@@ -0,0 +1,144 @@
+package dependencytool
+
+import (
+	"cmp"
+	"regexp"
+	"slices"
+	"strings"
+
+	"golang.org/x/mod/module"
+	"golang.org/x/mod/semver"
+)
+
+// ChangeKind classifies the change of a dependency.
+type ChangeKind string
+
+// Kinds of dependency changes, in the order they are reported.
+const (
+	KindMajor     ChangeKind = "major"
+	KindMinor     ChangeKind = "minor"
+	KindPatch     ChangeKind = "patch"
+	KindDowngrade ChangeKind = "downgrade"
+	// KindOther is a change between versions that are not semantic
+	// versions, such as git URLs or dist tags.
+	KindOther   ChangeKind = "other"
+	KindAdded   ChangeKind = "added"
+	KindRemoved ChangeKind = "removed"
+)
+
+// kindOrder is the order of the kinds in reports.
+var kindOrder = []ChangeKind{
+	KindMajor, KindMinor, KindPatch, KindDowngrade, KindOther, KindAdded, KindRemoved,
+}
+
+// Change is a difference in one dependency between two revisions.
+type Change struct {
+	Name string
+	Kind ChangeKind
+	// From is empty for added dependencies.
+	From string
+	// To is empty for removed dependencies.
+	To    string
+	Scope string
+}
+
+// diffDependencies compares the dependencies of a manifest at two
+// revisions. A Go module replaced by its next major version, such as
+// example.org/lib by example.org/lib/v2, is reported as a major update.
+func diffDependencies(from, to map[string]Dependency) []Change {
+	var changes []Change
+	for name, dep := range to {
+		old, ok := from[name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Name: name, Kind: KindAdded, To: dep.Version, Scope: dep.Scope})
+		case old.Version != dep.Version:
+			changes = append(changes, Change{
+				Name:  name,
+				Kind:  compareVersions(old.Version, dep.Version),
+				From:  old.Version,
+				To:    dep.Version,
+				Scope: dep.Scope,
+			})
+		}
+	}
+	for name, dep := range from {
+		if _, ok := to[name]; !ok {
+			changes = append(changes, Change{Name: name, Kind: KindRemoved, From: dep.Version, Scope: dep.Scope})
+		}
+	}
+	changes = pairMajorPaths(changes)
+	slices.SortFunc(changes, func(a, b Change) int {
+		if c := cmp.Compare(slices.Index(kindOrder, a.Kind), slices.Index(kindOrder, b.Kind)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return changes
+}
+
+// pairMajorPaths merges the removal of a Go module and the addition of a
+// later major version of it into one major update.
+func pairMajorPaths(changes []Change) []Change {
+	removed := make(map[string]int)
+	for i, change := range changes {
+		if change.Kind == KindRemoved {
+			prefix, _, _ := module.SplitPathVersion(change.Name)
+			removed[prefix] = i
+		}
+	}
+	for i, change := range changes {
+		if change.Kind != KindAdded {
+			continue
+		}
+		prefix, pathMajor, ok := module.SplitPathVersion(change.Name)
+		if !ok || pathMajor == "" {
+			continue
+		}
+		j, found := removed[prefix]
+		if !found {
+			continue
+		}
+		changes[i].Kind = KindMajor
+		changes[i].From = changes[j].From
+		// The removal is dropped below.
+		changes[j].Kind = ""
+		delete(removed, prefix)
+	}
+	return slices.DeleteFunc(changes, func(change Change) bool {
+		return change.Kind == ""
+	})
+}
+
+// versionPrefix matches the npm range operators in front of a version.
+var versionPrefix = regexp.MustCompile(`^[\^~=<>v\s]+`)
+
+// NormalizeVersion turns a Go or npm version, or the lower bound of a
+// simple npm range, into a canonical semantic version. It returns "" for
+// anything else.
+func NormalizeVersion(version string) string {
+	version = versionPrefix.ReplaceAllString(strings.TrimSpace(version), "")
+	version, _, _ = strings.Cut(version, " ")
+	return semver.Canonical("v" + version)
+}
+
+// compareVersions classifies the change of a dependency from one version to
+// another.
+func compareVersions(from, to string) ChangeKind {
+	oldVersion, newVersion := NormalizeVersion(from), NormalizeVersion(to)
+	switch {
+	case oldVersion == "" || newVersion == "":
+		return KindOther
+	case semver.Compare(newVersion, oldVersion) < 0:
+		return KindDowngrade
+	case semver.Major(oldVersion) != semver.Major(newVersion):
+		return KindMajor
+	case semver.MajorMinor(oldVersion) != semver.MajorMinor(newVersion):
+		return KindMinor
+	case semver.Compare(newVersion, oldVersion) > 0:
+		return KindPatch
+	default:
+		// Same version written differently, e.g. ^1.2.0 and ~1.2.0.
+		return KindOther
+	}
+}
@@ -0,0 +1,53 @@
+package dependencytool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompareVersions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		from string
+		to   string
+		want ChangeKind
+	}{
+		{from: "v1.2.3", to: "v2.0.0", want: KindMajor},
+		{from: "v1.2.3", to: "v1.3.0", want: KindMinor},
+		{from: "v1.2.3", to: "v1.2.4", want: KindPatch},
+		{from: "v1.2.3", to: "v1.2.2", want: KindDowngrade},
+		{from: "^18.2.0", to: "^19.0.0", want: KindMajor},
+		{from: "~1.6.0", to: ">=1.6.1", want: KindPatch},
+		{from: "^1.2.0", to: "~1.2.0", want: KindOther},
+		{from: "latest", to: "^2.0.0", want: KindOther},
+		{from: "v0.0.0-20240101000000-abcdefabcdef", to: "v0.1.0", want: KindMinor},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, compareVersions(tt.from, tt.to), "%s -> %s", tt.from, tt.to)
+	}
+}
+
+func TestDiffDependencies(t *testing.T) {
+	t.Parallel()
+	from := map[string]Dependency{
+		"example.org/lib":      {Name: "example.org/lib", Version: "v1.4.0"},
+		"example.org/old":      {Name: "example.org/old", Version: "v0.1.0"},
+		"example.org/same":     {Name: "example.org/same", Version: "v1.0.0"},
+		"example.org/minor":    {Name: "example.org/minor", Version: "v1.0.0", Scope: "indirect"},
+		"example.org/other/v3": {Name: "example.org/other/v3", Version: "v3.0.0"},
+	}
+	to := map[string]Dependency{
+		"example.org/lib/v2":   {Name: "example.org/lib/v2", Version: "v2.1.0"},
+		"example.org/new":      {Name: "example.org/new", Version: "v1.0.0"},
+		"example.org/same":     {Name: "example.org/same", Version: "v1.0.0"},
+		"example.org/minor":    {Name: "example.org/minor", Version: "v1.1.0", Scope: "indirect"},
+		"example.org/other/v3": {Name: "example.org/other/v3", Version: "v3.0.0"},
+	}
+	assert.Equal(t, []Change{
+		{Name: "example.org/lib/v2", Kind: KindMajor, From: "v1.4.0", To: "v2.1.0"},
+		{Name: "example.org/minor", Kind: KindMinor, From: "v1.0.0", To: "v1.1.0", Scope: "indirect"},
+		{Name: "example.org/new", Kind: KindAdded, To: "v1.0.0"},
+		{Name: "example.org/old", Kind: KindRemoved, From: "v0.1.0"},
+	}, diffDependencies(from, to))
+}
@@ -0,0 +1,135 @@
+package dependencytool
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/mod/modfile"
+)
+
+// Manifest types the digest reads.
+const (
+	ManifestGoMod       = "go.mod"
+	ManifestPackageJSON = "package.json"
+)
+
+// skippedDirs hold vendored or test copies of other projects, whose
+// manifests are not the repository's own.
+var skippedDirs = []string{"vendor", "node_modules", "testdata"}
+
+// Dependency is a requirement declared in a manifest.
+type Dependency struct {
+	Name    string
+	Version string
+	// Scope tells how the dependency is used, e.g. indirect for Go or dev
+	// for npm. It is empty for direct runtime dependencies.
+	Scope string
+}
+
+// isManifest reports whether file is a manifest the digest reads.
+func isManifest(file string) bool {
+	base := path.Base(file)
+	if base != ManifestGoMod && base != ManifestPackageJSON {
+		return false
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if slices.Contains(skippedDirs, dir) {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseManifest reads the dependencies of a manifest, keyed by name.
+func ParseManifest(file, content string) (map[string]Dependency, error) {
+	if path.Base(file) == ManifestGoMod {
+		return parseGoMod(file, content)
+	}
+	return parsePackageJSON(file, content)
+}
+
+// parseGoMod reads the requirements of a go.mod file.
+func parseGoMod(file, content string) (map[string]Dependency, error) {
+	parsed, err := modfile.ParseLax(file, []byte(content), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", file, err)
+	}
+	deps := make(map[string]Dependency, len(parsed.Require))
+	for _, require := range parsed.Require {
+		dep := Dependency{Name: require.Mod.Path, Version: require.Mod.Version}
+		if require.Indirect {
+			dep.Scope = "indirect"
+		}
+		deps[dep.Name] = dep
+	}
+	return deps, nil
+}
+
+// packageScopes maps the dependency sections of package.json to scopes.
+var packageScopes = []struct {
+	section string
+	scope   string
+}{
+	{section: "dependencies"},
+	{section: "devDependencies", scope: "dev"},
+	{section: "peerDependencies", scope: "peer"},
+	{section: "optionalDependencies", scope: "optional"},
+}
+
+// parsePackageJSON reads the dependencies of a package.json file. A
+// package listed in several sections keeps the first.
+func parsePackageJSON(file, content string) (map[string]Dependency, error) {
+	var manifest map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil, fmt.Errorf("error parsing %s: %w", file, err)
+	}
+	deps := make(map[string]Dependency)
+	for _, scope := range packageScopes {
+		raw, ok := manifest[scope.section]
+		if !ok {
+			continue
+		}
+		var versions map[string]string
+		if err := json.Unmarshal(raw, &versions); err != nil {
+			return nil, fmt.Errorf("error parsing %s of %s: %w", scope.section, file, err)
+		}
+		for name, version := range versions {
+			if _, seen := deps[name]; !seen {
+				deps[name] = Dependency{Name: name, Version: version, Scope: scope.scope}
+			}
+		}
+	}
+	return deps, nil
+}
+
+// ReadManifests reads the manifests in the tree of commit, keyed by path.
+// A nil commit has no manifests.
+func ReadManifests(commit *object.Commit) (map[string]string, error) {
+	found := make(map[string]string)
+	if commit == nil {
+		return found, nil
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("error reading file tree of %s: %w", commit.Hash, err)
+	}
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if !isManifest(file.Name) {
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		found[file.Name] = content
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return found, nil
+}
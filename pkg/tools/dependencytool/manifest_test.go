@@ -0,0 +1,55 @@
+package dependencytool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestIsManifest(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isManifest("go.mod"))
+	assert.True(t, isManifest("frontend/package.json"))
+	assert.False(t, isManifest("frontend/node_modules/react/package.json"))
+	assert.False(t, isManifest("vendor/example.org/lib/go.mod"))
+	assert.False(t, isManifest("go.sum"))
+}
+
+func TestParseGoMod(t *testing.T) {
+	t.Parallel()
+	deps, err := parseGoMod("go.mod", `module example.org/app
+
+go 1.23
+
+require (
+	github.com/go-git/go-git/v5 v5.14.0
+	golang.org/x/sys v0.32.0 // indirect
+)
+`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Dependency{
+		"github.com/go-git/go-git/v5": {Name: "github.com/go-git/go-git/v5", Version: "v5.14.0"},
+		"golang.org/x/sys":            {Name: "golang.org/x/sys", Version: "v0.32.0", Scope: "indirect"},
+	}, deps)
+
+	_, err = parseGoMod("go.mod", "require (")
+	require.Error(t, err)
+}
+
+func TestParsePackageJSON(t *testing.T) {
+	t.Parallel()
+	deps, err := parsePackageJSON("package.json", `{
+		"name": "web",
+		"dependencies": {"react": "^18.2.0"},
+		"devDependencies": {"vitest": "~1.6.0", "react": "18.3.1"}
+	}`)
+	require.NoError(t, err)
+	assert.Equal(t, map[string]Dependency{
+		"react":  {Name: "react", Version: "^18.2.0"},
+		"vitest": {Name: "vitest", Version: "~1.6.0", Scope: "dev"},
+	}, deps)
+
+	_, err = parsePackageJSON("package.json", `{"dependencies": ["react"]}`)
+	require.Error(t, err)
+}
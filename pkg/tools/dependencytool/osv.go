@@ -0,0 +1,319 @@
+package dependencytool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+// defaultOSVURL is the OSV API, which aggregates the GitHub Advisory
+// Database, the Go vulnerability database and others.
+const defaultOSVURL = "https://api.osv.dev"
+
+// batchSize is the most queries OSV accepts in one batch.
+const batchSize = 1000
+
+// Ecosystems of the manifests as named by OSV.
+const (
+	EcosystemGo  = "Go"
+	EcosystemNPM = "npm"
+)
+
+// Query asks for the vulnerabilities of one version of a package.
+type Query struct {
+	Ecosystem string
+	Name      string
+	Version   string
+}
+
+// Vulnerability is a known vulnerability affecting a queried version.
+type Vulnerability struct {
+	ID string
+	// Aliases are the IDs of the same vulnerability in other databases,
+	// such as CVE or GO IDs.
+	Aliases  []string
+	Summary  string
+	Severity Severity
+	// Fixed lists the versions that fix the vulnerability for the queried
+	// package.
+	Fixed []string
+}
+
+// OSVClient looks up vulnerabilities in the OSV database.
+type OSVClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// Option represents a configuration option for OSVClient.
+type Option func(*Config)
+
+// Config holds the configuration for the OSV client.
+type Config struct {
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithBaseURL overrides the OSV API base URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewOSVClient creates a client for the OSV API.
+func NewOSVClient(opts ...Option) *OSVClient {
+	cfg := &Config{
+		baseURL: defaultOSVURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &OSVClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:    strings.TrimSuffix(cfg.baseURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// osvQuery is a query of the batch endpoint.
+type osvQuery struct {
+	Package osvPackage `json:"package"`
+	Version string     `json:"version"`
+}
+
+// osvPackage names a package in an ecosystem.
+type osvPackage struct {
+	Name      string `json:"name"`
+	Ecosystem string `json:"ecosystem"`
+}
+
+// osvBatchResponse lists the IDs of the vulnerabilities matching each
+// query of a batch.
+type osvBatchResponse struct {
+	Results []struct {
+		Vulns []struct {
+			ID string `json:"id"`
+		} `json:"vulns"`
+	} `json:"results"`
+}
+
+// osvVulnerability is a vulnerability record of the OSV schema.
+type osvVulnerability struct {
+	ID       string   `json:"id"`
+	Summary  string   `json:"summary"`
+	Details  string   `json:"details"`
+	Aliases  []string `json:"aliases"`
+	Affected []struct {
+		Package osvPackage `json:"package"`
+		Ranges  []struct {
+			Events []map[string]string `json:"events"`
+		} `json:"ranges"`
+	} `json:"affected"`
+	DatabaseSpecific struct {
+		// Severity is set by the GitHub Advisory Database.
+		Severity string `json:"severity"`
+	} `json:"database_specific"`
+}
+
+// Vulnerabilities returns the vulnerabilities affecting each query, in the
+// order of the queries. A vulnerability listed in several databases is
+// returned once, under the ID that carries a severity.
+func (c *OSVClient) Vulnerabilities(ctx context.Context, queries []Query) ([][]Vulnerability, error) {
+	ids := make([][]string, 0, len(queries))
+	for start := 0; start < len(queries); start += batchSize {
+		batch, err := c.queryBatch(ctx, queries[start:min(start+batchSize, len(queries))])
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, batch...)
+	}
+	records := make(map[string]osvVulnerability)
+	results := make([][]Vulnerability, len(queries))
+	for i, query := range queries {
+		for _, id := range ids[i] {
+			record, ok := records[id]
+			if !ok {
+				var err error
+				if record, err = c.vulnerability(ctx, id); err != nil {
+					return nil, err
+				}
+				records[id] = record
+			}
+			results[i] = append(results[i], toVulnerability(record, query))
+		}
+		results[i] = mergeAliases(results[i])
+	}
+	c.logger.Debug("checked OSV advisories", "queries", len(queries), "vulnerabilities", len(records))
+	return results, nil
+}
+
+// queryBatch returns the IDs of the vulnerabilities matching each query.
+func (c *OSVClient) queryBatch(ctx context.Context, queries []Query) ([][]string, error) {
+	body := struct {
+		Queries []osvQuery `json:"queries"`
+	}{Queries: make([]osvQuery, 0, len(queries))}
+	for _, query := range queries {
+		body.Queries = append(body.Queries, osvQuery{
+			Package: osvPackage{Name: query.Name, Ecosystem: query.Ecosystem},
+			Version: query.Version,
+		})
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding OSV query: %w", err)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodPost, c.baseURL+"/v1/querybatch", bytes.NewReader(payload),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OSV request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	var response osvBatchResponse
+	if err := c.do(req, &response); err != nil {
+		return nil, err
+	}
+	if len(response.Results) != len(queries) {
+		return nil, fmt.Errorf(
+			"OSV answered %d queries with %d results", len(queries), len(response.Results),
+		)
+	}
+	ids := make([][]string, len(queries))
+	for i, result := range response.Results {
+		for _, vuln := range result.Vulns {
+			ids[i] = append(ids[i], vuln.ID)
+		}
+	}
+	return ids, nil
+}
+
+// vulnerability fetches the record of a vulnerability.
+func (c *OSVClient) vulnerability(ctx context.Context, id string) (osvVulnerability, error) {
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, c.baseURL+"/v1/vulns/"+url.PathEscape(id), nil,
+	)
+	if err != nil {
+		return osvVulnerability{}, fmt.Errorf("error creating OSV request: %w", err)
+	}
+	var record osvVulnerability
+	if err := c.do(req, &record); err != nil {
+		return osvVulnerability{}, err
+	}
+	return record, nil
+}
+
+// do performs req and decodes the response into target.
+func (c *OSVClient) do(req *http.Request, target any) error {
+	start := time.Now()
+	err := c.send(req, target)
+	metrics.ObserveOutbound(metrics.ServiceOSV, start, err)
+	return err
+}
+
+// send performs the HTTP round trip for do.
+func (c *OSVClient) send(req *http.Request, target any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling OSV: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"OSV returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error reading OSV response: %w", err)
+	}
+	return nil
+}
+
+// toVulnerability extracts what the report shows about record for the
+// queried package.
+func toVulnerability(record osvVulnerability, query Query) Vulnerability {
+	vuln := Vulnerability{
+		ID:       record.ID,
+		Aliases:  record.Aliases,
+		Summary:  record.Summary,
+		Severity: parseSeverity(record.DatabaseSpecific.Severity),
+	}
+	if vuln.Summary == "" {
+		vuln.Summary, _, _ = strings.Cut(strings.TrimSpace(record.Details), "\n")
+	}
+	for _, affected := range record.Affected {
+		if affected.Package.Name != query.Name ||
+			!strings.EqualFold(affected.Package.Ecosystem, query.Ecosystem) {
+			continue
+		}
+		for _, affectedRange := range affected.Ranges {
+			for _, event := range affectedRange.Events {
+				if fixed, ok := event["fixed"]; ok && !slices.Contains(vuln.Fixed, fixed) {
+					vuln.Fixed = append(vuln.Fixed, fixed)
+				}
+			}
+		}
+	}
+	return vuln
+}
+
+// mergeAliases folds vulnerabilities that are aliases of each other, such
+// as a GO and a GHSA entry, into the one with a known severity.
+func mergeAliases(vulns []Vulnerability) []Vulnerability {
+	if len(vulns) < 2 {
+		return vulns
+	}
+	slices.SortStableFunc(vulns, func(a, b Vulnerability) int {
+		return severityRank(a.Severity) - severityRank(b.Severity)
+	})
+	merged := make([]Vulnerability, 0, len(vulns))
+	for _, vuln := range vulns {
+		index := slices.IndexFunc(merged, func(kept Vulnerability) bool {
+			return slices.Contains(kept.Aliases, vuln.ID) || slices.Contains(vuln.Aliases, kept.ID)
+		})
+		if index < 0 {
+			merged = append(merged, vuln)
+			continue
+		}
+		kept := &merged[index]
+		kept.Aliases = append(slices.Clone(kept.Aliases), vuln.ID)
+		for _, fixed := range vuln.Fixed {
+			if !slices.Contains(kept.Fixed, fixed) {
+				kept.Fixed = append(kept.Fixed, fixed)
+			}
+		}
+	}
+	return merged
+}
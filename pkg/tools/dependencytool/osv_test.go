@@ -0,0 +1,128 @@
+package dependencytool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// osvRecords are the vulnerabilities of the fake OSV server.
+var osvRecords = map[string]string{
+	"GO-2024-0001": `{"id": "GO-2024-0001", "summary": "Path traversal in lib", "aliases": ["GHSA-aaaa-bbbb-cccc"],
+		"affected": [{"package": {"ecosystem": "Go", "name": "example.org/lib"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.3.1"}]}]}]}`,
+	"GHSA-aaaa-bbbb-cccc": `{"id": "GHSA-aaaa-bbbb-cccc", "summary": "Path traversal in example.org/lib",
+		"aliases": ["CVE-2024-0001"], "database_specific": {"severity": "HIGH"},
+		"affected": [{"package": {"ecosystem": "Go", "name": "example.org/lib"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "1.3.1"}]}]}]}`,
+	"GHSA-dddd-eeee-ffff": `{"id": "GHSA-dddd-eeee-ffff", "details": "Prototype pollution\nin react",
+		"database_specific": {"severity": "MODERATE"},
+		"affected": [{"package": {"ecosystem": "npm", "name": "react"},
+			"ranges": [{"type": "SEMVER", "events": [{"introduced": "0"}, {"fixed": "19.0.1"}]}]}]}`,
+}
+
+// osvAffected maps package versions to the IDs the fake server matches.
+var osvAffected = map[string][]string{
+	"Go example.org/lib 1.3.0": {"GO-2024-0001", "GHSA-aaaa-bbbb-cccc"},
+	"npm react 19.0.0":         {"GHSA-dddd-eeee-ffff"},
+}
+
+// newOSVServer starts a fake OSV API and counts the requests it serves.
+func newOSVServer(t *testing.T) (*httptest.Server, *atomic.Int32) {
+	t.Helper()
+	requests := &atomic.Int32{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if r.URL.Path == "/v1/querybatch" {
+			var body struct {
+				Queries []osvQuery `json:"queries"`
+			}
+			if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			type vuln struct {
+				ID string `json:"id"`
+			}
+			results := make([]map[string][]vuln, 0, len(body.Queries))
+			for _, query := range body.Queries {
+				result := map[string][]vuln{}
+				key := query.Package.Ecosystem + " " + query.Package.Name + " " + query.Version
+				for _, id := range osvAffected[key] {
+					result["vulns"] = append(result["vulns"], vuln{ID: id})
+				}
+				results = append(results, result)
+			}
+			_ = json.NewEncoder(w).Encode(map[string]any{"results": results})
+			return
+		}
+		record, ok := osvRecords[strings.TrimPrefix(r.URL.Path, "/v1/vulns/")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(record))
+	}))
+	t.Cleanup(server.Close)
+	return server, requests
+}
+
+func TestOSVClient_Vulnerabilities(t *testing.T) {
+	t.Parallel()
+	server, requests := newOSVServer(t)
+	client := NewOSVClient(WithBaseURL(server.URL))
+
+	vulns, err := client.Vulnerabilities(context.Background(), []Query{
+		{Ecosystem: EcosystemGo, Name: "example.org/lib", Version: "1.3.0"},
+		{Ecosystem: EcosystemGo, Name: "example.org/safe", Version: "1.0.0"},
+		{Ecosystem: EcosystemNPM, Name: "react", Version: "19.0.0"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]Vulnerability{
+		{{
+			ID:       "GHSA-aaaa-bbbb-cccc",
+			Aliases:  []string{"CVE-2024-0001", "GO-2024-0001"},
+			Summary:  "Path traversal in example.org/lib",
+			Severity: SeverityHigh,
+			Fixed:    []string{"1.3.1"},
+		}},
+		nil,
+		{{
+			ID:       "GHSA-dddd-eeee-ffff",
+			Summary:  "Prototype pollution",
+			Severity: SeverityModerate,
+			Fixed:    []string{"19.0.1"},
+		}},
+	}, vulns)
+	// One batch query and one lookup per vulnerability.
+	assert.Equal(t, int32(4), requests.Load())
+}
+
+func TestOSVClient_Error(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	client := NewOSVClient(WithBaseURL(server.URL))
+
+	_, err := client.Vulnerabilities(context.Background(), []Query{
+		{Ecosystem: EcosystemGo, Name: "example.org/lib", Version: "1.3.0"},
+	})
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "OSV returned status 503")
+}
+
+func TestParseSeverity(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, SeverityCritical, parseSeverity("CRITICAL"))
+	assert.Equal(t, SeverityModerate, parseSeverity("MEDIUM"))
+	assert.Equal(t, SeverityUnknown, parseSeverity(""))
+}
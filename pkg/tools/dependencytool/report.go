@@ -0,0 +1,146 @@
+package dependencytool
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Revision is one end of the compared span.
+type Revision struct {
+	// Hash is empty when the repository had no commit yet.
+	Hash string
+	When time.Time
+	// Source tells how the revision was chosen, e.g. the ref it was
+	// resolved from.
+	Source string
+}
+
+// ManifestDiff holds the dependency changes of one manifest.
+type ManifestDiff struct {
+	Path    string
+	Changes []Change
+	// Err is set when the manifest could not be parsed at one of the
+	// revisions.
+	Err error
+}
+
+// Digest is the content of one dependency digest.
+type Digest struct {
+	RepoURL string
+	Branch  string
+	From    Revision
+	To      Revision
+	// Range is set when the revisions were chosen by date.
+	Range     *worksummary.DateRange
+	Manifests []ManifestDiff
+	// Advisories is set when the dependencies were checked for known
+	// vulnerabilities.
+	Advisories *AdvisoryReport
+}
+
+// kindTitles names the sections of each kind of change.
+var kindTitles = map[ChangeKind]string{
+	KindMajor:     "Major updates",
+	KindMinor:     "Minor updates",
+	KindPatch:     "Patch updates",
+	KindDowngrade: "Downgrades",
+	KindOther:     "Other version changes",
+	KindAdded:     "Added",
+	KindRemoved:   "Removed",
+}
+
+// Counts returns the number of changes of each kind across all manifests.
+func (d Digest) Counts() map[ChangeKind]int {
+	counts := make(map[ChangeKind]int)
+	for _, manifest := range d.Manifests {
+		for _, change := range manifest.Changes {
+			counts[change.Kind]++
+		}
+	}
+	return counts
+}
+
+// RenderMarkdown renders the digest as a markdown document.
+func RenderMarkdown(digest Digest) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Dependency Digest: %s\n\n", worksummary.RepoName(digest.RepoURL))
+	fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`)\n\n", digest.RepoURL, digest.Branch)
+	if digest.Range != nil {
+		builder.WriteString(digest.Range.Header())
+		builder.WriteString("\n")
+	}
+	fmt.Fprintf(&builder, "- From: %s\n- To: %s\n", describeRevision(digest.From), describeRevision(digest.To))
+
+	builder.WriteString("\n## Overview\n\n")
+	counts := digest.Counts()
+	total := 0
+	parts := make([]string, 0, len(kindOrder))
+	for _, kind := range kindOrder {
+		total += counts[kind]
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	if total == 0 {
+		fmt.Fprintf(&builder, "No dependency changes in %d manifests.\n", len(digest.Manifests))
+	} else {
+		fmt.Fprintf(&builder, "%d dependency changes in %d manifests: %s.\n",
+			total, len(digest.Manifests), strings.Join(parts, ", "))
+	}
+	if digest.Advisories != nil {
+		writeAdvisories(&builder, digest.Advisories)
+	}
+	for _, manifest := range digest.Manifests {
+		writeManifest(&builder, manifest)
+	}
+	return builder.String()
+}
+
+// writeManifest renders the changes of one manifest, grouped by kind.
+func writeManifest(builder *strings.Builder, manifest ManifestDiff) {
+	if manifest.Err == nil && len(manifest.Changes) == 0 {
+		return
+	}
+	fmt.Fprintf(builder, "\n## %s\n", manifest.Path)
+	if manifest.Err != nil {
+		fmt.Fprintf(builder, "\n_Unavailable: %v_\n", manifest.Err)
+		return
+	}
+	for _, kind := range kindOrder {
+		var rows []Change
+		for _, change := range manifest.Changes {
+			if change.Kind == kind {
+				rows = append(rows, change)
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(builder, "\n### %s\n\n", kindTitles[kind])
+		builder.WriteString("| Dependency | From | To | Scope |\n|---|---|---|---|\n")
+		for _, change := range rows {
+			fmt.Fprintf(builder, "| `%s` | %s | %s | %s |\n",
+				change.Name, code(change.From), code(change.To), change.Scope)
+		}
+	}
+}
+
+// describeRevision renders a revision for the report header.
+func describeRevision(revision Revision) string {
+	if revision.Hash == "" {
+		return "the empty repository (" + revision.Source + ")"
+	}
+	return fmt.Sprintf("`%s` of %s (%s)",
+		worksummary.ShortHash(revision.Hash), revision.When.Format(time.DateOnly), revision.Source)
+}
+
+// code formats a version as inline code, leaving empty versions blank.
+func code(version string) string {
+	if version == "" {
+		return ""
+	}
+	return "`" + version + "`"
+}
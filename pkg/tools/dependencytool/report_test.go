@@ -0,0 +1,90 @@
+package dependencytool
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	digest := Digest{
+		RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+		Branch:  "develop",
+		From:    Revision{Hash: "1111111aaaa", When: time.Date(2025, 5, 30, 0, 0, 0, 0, time.UTC), Source: "from_ref v1.0.0"},
+		To:      Revision{Hash: "2222222bbbb", When: time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), Source: "head of develop"},
+		Manifests: []ManifestDiff{
+			{
+				Path: "go.mod",
+				Changes: []Change{
+					{Name: "github.com/mark3labs/mcp-go", Kind: KindMinor, From: "v0.37.0", To: "v0.38.0"},
+					{Name: "golang.org/x/sys", Kind: KindPatch, From: "v0.32.0", To: "v0.32.1", Scope: "indirect"},
+					{Name: "golang.org/x/mod", Kind: KindAdded, To: "v0.25.0"},
+				},
+			},
+			{Path: "docs/package.json"},
+			{Path: "web/package.json", Err: errors.New("error parsing web/package.json")},
+		},
+	}
+
+	rendered := RenderMarkdown(digest)
+	assert.True(t, strings.HasPrefix(rendered, "# Dependency Digest: dcr-mcp\n\n"))
+	assert.Contains(t, rendered, "- From: `1111111` of 2025-05-30 (from_ref v1.0.0)\n"+
+		"- To: `2222222` of 2025-06-28 (head of develop)\n")
+	assert.Contains(t, rendered, "3 dependency changes in 3 manifests: 1 minor, 1 patch, 1 added.\n")
+	assert.Contains(t, rendered, "## go.mod\n\n### Minor updates\n\n| Dependency | From | To | Scope |\n|---|---|---|---|\n"+
+		"| `github.com/mark3labs/mcp-go` | `v0.37.0` | `v0.38.0` |  |\n")
+	assert.Contains(t, rendered, "| `golang.org/x/sys` | `v0.32.0` | `v0.32.1` | indirect |\n")
+	assert.Contains(t, rendered, "### Added\n\n| Dependency | From | To | Scope |\n|---|---|---|---|\n"+
+		"| `golang.org/x/mod` |  | `v0.25.0` |  |\n")
+	assert.NotContains(t, rendered, "docs/package.json")
+	assert.Contains(t, rendered, "## web/package.json\n\n_Unavailable: error parsing web/package.json_\n")
+
+	assert.Contains(t, RenderMarkdown(Digest{From: Revision{Source: "last commit before the start date"}}),
+		"- From: the empty repository (last commit before the start date)\n")
+}
+
+func TestRenderMarkdown_Advisories(t *testing.T) {
+	t.Parallel()
+	digest := Digest{
+		RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+		Advisories: &AdvisoryReport{
+			Checked: 12,
+			Skipped: 1,
+			Findings: []Finding{
+				{
+					Manifest:   "go.mod",
+					Dependency: Dependency{Name: "example.org/lib", Version: "v1.3.0"},
+					Vulnerability: Vulnerability{
+						ID: "GHSA-aaaa-bbbb-cccc", Summary: "Path traversal | symlinks", Severity: SeverityHigh,
+						Fixed: []string{"1.3.1"},
+					},
+				},
+				{
+					Manifest:      "go.mod",
+					Dependency:    Dependency{Name: "example.org/other", Version: "v0.2.0"},
+					Vulnerability: Vulnerability{ID: "GO-2024-0002", Summary: "Panic on input", Severity: SeverityUnknown},
+				},
+			},
+		},
+	}
+
+	rendered := RenderMarkdown(digest)
+	assert.Contains(t, rendered, "## Security Advisories\n\n2 known vulnerabilities in 12 checked dependencies: "+
+		"1 high, 1 unknown. 1 dependencies without a semantic version were not checked.\n")
+	assert.Contains(t, rendered, "### High\n\n| Advisory | Dependency | Version | Manifest | Fixed in | Summary |\n"+
+		"|---|---|---|---|---|---|\n"+
+		"| [GHSA-aaaa-bbbb-cccc](https://osv.dev/vulnerability/GHSA-aaaa-bbbb-cccc) | `example.org/lib` | `v1.3.0` | go.mod | "+
+		"1.3.1 | Path traversal \\| symlinks |\n")
+	assert.Contains(t, rendered, "### Unrated\n\n")
+	assert.Less(t, strings.Index(rendered, "### High"), strings.Index(rendered, "### Unrated"))
+
+	assert.Contains(t, RenderMarkdown(Digest{Advisories: &AdvisoryReport{Checked: 3}}),
+		"No known vulnerabilities in 3 checked dependencies.\n")
+	assert.Contains(t, RenderMarkdown(Digest{Advisories: &AdvisoryReport{Err: errors.New("OSV returned status 503")}}),
+		"## Security Advisories\n\n_Unavailable: OSV returned status 503_\n")
+	assert.NotContains(t, RenderMarkdown(Digest{}), "Security Advisories")
+}
@@ -0,0 +1,359 @@
+package digesttool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Output formats of the digest.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+const (
+	defaultQuery       = "dictyostelium"
+	defaultBranch      = "develop"
+	defaultMaxArticles = 50
+)
+
+// DigestTool generates the periodic dictyBase community digest from new
+// literature, new GO annotations and repository activity.
+type DigestTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	analyzer      *worksummary.GitAnalyzer
+	resources     *resources.Catalog
+	sourceOptions []Option
+}
+
+// ToolOption defines a functional option for configuring DigestTool.
+type ToolOption func(*DigestTool)
+
+// WithResources publishes generated digests as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(d *DigestTool) {
+		d.resources = catalog
+	}
+}
+
+// WithSourceOptions sets options for the data sources the tool creates.
+func WithSourceOptions(opts ...Option) ToolOption {
+	return func(d *DigestTool) {
+		d.sourceOptions = append(d.sourceOptions, opts...)
+	}
+}
+
+// DigestRequest represents the parameters for the digest.
+type DigestRequest struct {
+	StartDate   string   `validate:"required,datetime=2006-01-02"`
+	EndDate     string   `validate:"required,datetime=2006-01-02"`
+	Queries     []string `validate:"required,min=1,dive,required"`
+	Repos       []string `validate:"dive,required"`
+	Branch      string   `validate:"required"`
+	MaxArticles int      `validate:"gte=1,lte=1000"`
+	Format      string   `validate:"required,oneof=markdown html"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"dictybase-digest",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			digestTool, err := NewDigestTool(deps.Logger, WithResources(deps.Resources))
+			if err != nil {
+				return nil, err
+			}
+			return digestTool, nil
+		},
+	)
+}
+
+// NewDigestTool creates a new DigestTool instance.
+func NewDigestTool(logger *slog.Logger, opts ...ToolOption) (*DigestTool, error) {
+	tool := mcp.NewTool(
+		"dictybase-digest",
+		mcp.WithDescription(
+			"Generates the dictyBase community digest of new Dictyostelium literature, new GO annotations "+
+				"and repository activity for a date window",
+		),
+		mcp.WithTitleAnnotation("dictyBase Digest"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"start_date",
+			mcp.Description("First day of the window, as YYYY-MM-DD"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description("Last day of the window, as YYYY-MM-DD (defaults to today)"),
+		),
+		mcp.WithString(
+			"queries",
+			mcp.Description(
+				"Semicolon-separated Europe PMC queries for new literature (defaults to 'dictyostelium')",
+			),
+		),
+		mcp.WithString(
+			"repos",
+			mcp.Description("Comma-separated git repository URLs to report activity for"),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("Branch to report repository activity on (defaults to 'develop')"),
+		),
+		mcp.WithNumber(
+			"max_articles",
+			mcp.Description("Maximum number of articles to list (defaults to 50)"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format, defaults to 'markdown'"),
+			mcp.Enum(FormatMarkdown, FormatHTML),
+		),
+	)
+	digestTool := &DigestTool{
+		Name:        "dictybase-digest",
+		Description: "Generates the dictyBase community digest for a date window",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(digestTool)
+	}
+	return digestTool, nil
+}
+
+// GetName returns the name of the tool.
+func (d *DigestTool) GetName() string {
+	return d.Name
+}
+
+// GetDescription returns the description of the tool.
+func (d *DigestTool) GetDescription() string {
+	return d.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (d *DigestTool) GetSchema() mcp.ToolInputSchema {
+	return d.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (d *DigestTool) GetTool() mcp.Tool {
+	return d.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (d *DigestTool) GetAnnotations() mcp.ToolAnnotation {
+	return d.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (d *DigestTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Build the weekly digest of commits and new Dictyostelium articles",
+			Arguments: map[string]any{
+				"start_date": "2024-06-03",
+				"end_date":   "2024-06-09",
+			},
+		},
+		{
+			Description: "Build an HTML digest of chosen repositories and literature queries",
+			Arguments: map[string]any{
+				"start_date":   "2024-06-01",
+				"repos":        "https://github.com/dictybase/stock-center,https://github.com/dictybase/modware-stock",
+				"queries":      "dictyostelium;social amoeba",
+				"max_articles": 10,
+				"format":       "html",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (d *DigestTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := DigestRequest{
+		StartDate:   request.GetString("start_date", ""),
+		EndDate:     request.GetString("end_date", time.Now().Format(time.DateOnly)),
+		Queries:     splitList(request.GetString("queries", defaultQuery), ";"),
+		Repos:       splitList(request.GetString("repos", ""), ","),
+		Branch:      request.GetString("branch", defaultBranch),
+		MaxArticles: request.GetInt("max_articles", defaultMaxArticles),
+		Format:      request.GetString("format", FormatMarkdown),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	// The validator has checked the layout of both dates.
+	start, _ := time.Parse(time.DateOnly, params.StartDate)
+	end, _ := time.Parse(time.DateOnly, params.EndDate)
+	if end.Before(start) {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_DATE_RANGE",
+			"end_date must not be before start_date",
+		)), nil
+	}
+
+	digest := d.gather(ctx, logging.WithRequestID(d.Logger), params, start, end)
+	if err := ctx.Err(); err != nil {
+		return toolerror.Result(fmt.Errorf("digest generation aborted: %w", err)), nil
+	}
+	content := RenderMarkdown(digest)
+	mimeType, ext := "text/markdown", ".md"
+	if params.Format == FormatHTML {
+		html, err := markdown.NewParser().ParseString(content)
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("failed to render HTML: %w", err)), nil
+		}
+		content, mimeType, ext = html, "text/html", ".html"
+	}
+
+	result := provenance.Attach(
+		mcp.NewToolResultText(content),
+		provenance.New(digestProviders(digest)),
+	)
+	if d.resources != nil {
+		resource, err := d.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindDigest,
+			Name:        fmt.Sprintf("dictybase-digest-%s-%s%s", params.StartDate, params.EndDate, ext),
+			MIMEType:    mimeType,
+			Description: fmt.Sprintf("dictyBase digest for %s to %s", params.StartDate, params.EndDate),
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("failed to publish digest: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// gather fetches the digest sections concurrently. A failing source is
+// recorded in the digest instead of failing the whole digest.
+func (d *DigestTool) gather(
+	ctx context.Context,
+	logger *slog.Logger,
+	params DigestRequest,
+	start, end time.Time,
+) Digest {
+	sources := NewSources(append([]Option{WithLogger(logger)}, d.sourceOptions...)...)
+	digest := Digest{Start: start, End: end}
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() {
+		defer wg.Done()
+		digest.Articles, digest.LiteratureErr = sources.NewLiterature(ctx, LiteratureParams{
+			Queries: params.Queries,
+			Start:   start,
+			End:     end,
+			Limit:   params.MaxArticles,
+		})
+	}()
+	go func() {
+		defer wg.Done()
+		digest.Annotations, digest.AnnotationsErr = sources.NewAnnotations(ctx, start, end)
+	}()
+	go func() {
+		defer wg.Done()
+		digest.Repos = d.repoActivity(ctx, params.Repos, params.Branch, start, end)
+	}()
+	wg.Wait()
+	for _, err := range []error{digest.LiteratureErr, digest.AnnotationsErr} {
+		if err != nil {
+			logger.Warn("digest source failed", "error", err)
+		}
+	}
+	return digest
+}
+
+// digestProviders lists the sources the digest was built from; failed
+// sources are left out.
+func digestProviders(digest Digest) []string {
+	var providers []string
+	if digest.LiteratureErr == nil {
+		providers = append(providers, metrics.ServiceEuropePMC)
+	}
+	if digest.AnnotationsErr == nil {
+		providers = append(providers, metrics.ServiceGeneOntology)
+	}
+	if slices.ContainsFunc(digest.Repos, func(activity RepoActivity) bool {
+		return activity.Err == nil
+	}) {
+		providers = append(providers, metrics.ServiceGitClone)
+	}
+	return providers
+}
+
+// repoActivity lists the commits on the branch of each repository within
+// the window; end is inclusive.
+func (d *DigestTool) repoActivity(
+	ctx context.Context,
+	repoURLs []string,
+	branch string,
+	start, end time.Time,
+) []RepoActivity {
+	activities := make([]RepoActivity, 0, len(repoURLs))
+	for _, repoURL := range repoURLs {
+		activity := RepoActivity{URL: repoURL, Branch: branch}
+		repo, err := d.analyzer.CloneAndCheckout(ctx, repoURL, branch)
+		if err == nil {
+			activity.Commits, err = d.analyzer.ListActivity(ctx, worksummary.ActivityParams{
+				Repo:  repo.Repository,
+				Start: start,
+				End:   end.AddDate(0, 0, 1),
+			})
+			// Release each clone before the next one is made.
+			_ = repo.Close()
+		}
+		if err != nil {
+			d.Logger.Warn("failed to read repository activity", "repo_url", repoURL, "error", err)
+			activity.Err = err
+		}
+		activities = append(activities, activity)
+	}
+	return activities
+}
+
+// splitList splits a separated list, dropping blank entries.
+func splitList(list, separator string) []string {
+	var values []string
+	for _, value := range strings.Split(list, separator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,67 @@
+package digesttool
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, searchJSON)
+	})
+	mux.HandleFunc("GET /dictybase.gaf", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, testGAF)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewDigestTool(logger, WithSourceOptions(
+		WithEuropePMCURL(server.URL),
+		WithGAFURL(server.URL+"/dictybase.gaf"),
+	))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"start_date": "2024-01-01",
+		"end_date":   "2024-01-31",
+		"format":     "html",
+	}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "<h1")
+	assert.Contains(t, text.Text, "Chemotaxis in Dictyostelium")
+	assert.Contains(t, text.Text, "acaA")
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"europepmc", "geneontology"}, record.Providers)
+	assert.Equal(t, provenance.CacheMiss, record.Cache)
+
+	request.Params.Arguments = map[string]any{
+		"start_date": "2024-02-01",
+		"end_date":   "2024-01-01",
+	}
+	result, err = tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, "INVALID_DATE_RANGE", toolErr.Code)
+}
@@ -0,0 +1,70 @@
+package digesttool
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// GAF 2.x columns read by ParseGAF, zero-based.
+const (
+	gafObjectID   = 1
+	gafSymbol     = 2
+	gafQualifier  = 3
+	gafGOID       = 4
+	gafReference  = 5
+	gafEvidence   = 6
+	gafAspect     = 8
+	gafDate       = 13
+	gafAssignedBy = 14
+	gafColumns    = 15
+)
+
+// gafDateLayout is the YYYYMMDD date format of GAF files.
+const gafDateLayout = "20060102"
+
+// ParseGAF reads a GO annotation file and returns the annotations dated
+// between start and end, inclusive. Comment and malformed lines are skipped.
+func ParseGAF(reader io.Reader, start, end time.Time) ([]Annotation, error) {
+	from := start.Format(gafDateLayout)
+	to := end.Format(gafDateLayout)
+	var annotations []Annotation
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line == "" || strings.HasPrefix(line, "!") {
+			continue
+		}
+		fields := strings.Split(line, "\t")
+		if len(fields) < gafColumns {
+			continue
+		}
+		// YYYYMMDD dates compare correctly as strings.
+		date := fields[gafDate]
+		if len(date) != len(gafDateLayout) || date < from || date > to {
+			continue
+		}
+		annotated, err := time.Parse(gafDateLayout, date)
+		if err != nil {
+			continue
+		}
+		annotations = append(annotations, Annotation{
+			GeneID:     fields[gafObjectID],
+			Symbol:     fields[gafSymbol],
+			Qualifier:  fields[gafQualifier],
+			GOID:       fields[gafGOID],
+			Aspect:     fields[gafAspect],
+			Evidence:   fields[gafEvidence],
+			Reference:  fields[gafReference],
+			AssignedBy: fields[gafAssignedBy],
+			Date:       annotated,
+		})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error reading annotation file: %w", err)
+	}
+	return annotations, nil
+}
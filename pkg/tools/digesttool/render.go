@@ -0,0 +1,184 @@
+package digesttool
+
+import (
+	"cmp"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// maxGenes is the number of genes listed in the annotation section.
+	maxGenes = 50
+	// maxCommitsPerRepo is the number of commits listed per repository.
+	maxCommitsPerRepo = 20
+)
+
+// aspectNames spells out the GAF aspect codes.
+var aspectNames = map[string]string{
+	"P": "biological process",
+	"F": "molecular function",
+	"C": "cellular component",
+}
+
+// RenderMarkdown renders the digest as a markdown document.
+func RenderMarkdown(digest Digest) string {
+	var builder strings.Builder
+	fmt.Fprintf(
+		&builder,
+		"# dictyBase Community Digest\n\n_%s to %s_\n",
+		digest.Start.Format(time.DateOnly),
+		digest.End.Format(time.DateOnly),
+	)
+	writeLiterature(&builder, digest)
+	writeAnnotations(&builder, digest)
+	if len(digest.Repos) > 0 {
+		writeRepos(&builder, digest.Repos)
+	}
+	return builder.String()
+}
+
+// writeLiterature renders the new literature section.
+func writeLiterature(builder *strings.Builder, digest Digest) {
+	builder.WriteString("\n## New Literature\n\n")
+	if digest.LiteratureErr != nil {
+		fmt.Fprintf(builder, "_Unavailable: %v_\n", digest.LiteratureErr)
+		return
+	}
+	if len(digest.Articles) == 0 {
+		builder.WriteString("No new articles.\n")
+		return
+	}
+	for _, article := range digest.Articles {
+		parts := []string{fmt.Sprintf("**%s**", strings.TrimSuffix(article.Title, "."))}
+		if article.Authors != "" {
+			parts = append(parts, article.Authors)
+		}
+		if article.Journal != "" {
+			parts = append(parts, fmt.Sprintf("*%s*", article.Journal))
+		}
+		if !article.Published.IsZero() {
+			parts = append(parts, fmt.Sprintf("(%s)", article.Published.Format(time.DateOnly)))
+		}
+		var links []string
+		if article.PMID != "" {
+			links = append(links, fmt.Sprintf(
+				"[PMID:%s](https://pubmed.ncbi.nlm.nih.gov/%s/)",
+				article.PMID,
+				article.PMID,
+			))
+		}
+		if article.DOI != "" {
+			links = append(links, fmt.Sprintf("[doi:%s](https://doi.org/%s)", article.DOI, article.DOI))
+		}
+		if len(links) > 0 {
+			parts = append(parts, strings.Join(links, " · "))
+		}
+		fmt.Fprintf(builder, "- %s\n", strings.Join(parts, " "))
+	}
+}
+
+// writeAnnotations renders the new gene annotation section, one bullet per
+// gene.
+func writeAnnotations(builder *strings.Builder, digest Digest) {
+	builder.WriteString("\n## New Gene Annotations\n\n")
+	if digest.AnnotationsErr != nil {
+		fmt.Fprintf(builder, "_Unavailable: %v_\n", digest.AnnotationsErr)
+		return
+	}
+	if len(digest.Annotations) == 0 {
+		builder.WriteString("No new GO annotations.\n")
+		return
+	}
+	byGene := make(map[string][]Annotation)
+	for _, annotation := range digest.Annotations {
+		byGene[annotation.GeneID] = append(byGene[annotation.GeneID], annotation)
+	}
+	genes := make([]string, 0, len(byGene))
+	for gene := range byGene {
+		genes = append(genes, gene)
+	}
+	// Most annotated genes first.
+	slices.SortFunc(genes, func(first, second string) int {
+		if byCount := cmp.Compare(len(byGene[second]), len(byGene[first])); byCount != 0 {
+			return byCount
+		}
+		return cmp.Compare(first, second)
+	})
+	fmt.Fprintf(
+		builder,
+		"%d new GO annotations on %d genes.\n\n",
+		len(digest.Annotations),
+		len(genes),
+	)
+	for _, gene := range genes[:min(len(genes), maxGenes)] {
+		annotations := byGene[gene]
+		terms := make([]string, 0, len(annotations))
+		for _, annotation := range annotations {
+			detail := []string{annotation.Evidence}
+			if name, ok := aspectNames[annotation.Aspect]; ok {
+				detail = append([]string{name}, detail...)
+			}
+			if annotation.Reference != "" {
+				detail = append(detail, annotation.Reference)
+			}
+			term := annotation.GOID
+			if annotation.Qualifier != "" {
+				term = annotation.Qualifier + " " + term
+			}
+			terms = append(terms, fmt.Sprintf("%s (%s)", term, strings.Join(detail, ", ")))
+		}
+		fmt.Fprintf(
+			builder,
+			"- **%s** (%s): %s\n",
+			annotations[0].Symbol,
+			gene,
+			strings.Join(terms, "; "),
+		)
+	}
+	if len(genes) > maxGenes {
+		fmt.Fprintf(builder, "- …and %d more genes\n", len(genes)-maxGenes)
+	}
+}
+
+// writeRepos renders the repository activity section.
+func writeRepos(builder *strings.Builder, repos []RepoActivity) {
+	builder.WriteString("\n## Repository Activity\n")
+	for _, repo := range repos {
+		name := strings.TrimSuffix(path.Base(repo.URL), ".git")
+		if repo.Err != nil {
+			fmt.Fprintf(builder, "\n### %s (%s)\n\n_Unavailable: %v_\n", name, repo.Branch, repo.Err)
+			continue
+		}
+		contributors := make(map[string]bool)
+		for _, commit := range repo.Commits {
+			contributors[commit.Author] = true
+		}
+		fmt.Fprintf(
+			builder,
+			"\n### %s (%s)\n\n%d commits by %d contributors.\n",
+			name,
+			repo.Branch,
+			len(repo.Commits),
+			len(contributors),
+		)
+		if len(repo.Commits) > 0 {
+			builder.WriteString("\n")
+		}
+		for _, commit := range repo.Commits[:min(len(repo.Commits), maxCommitsPerRepo)] {
+			fmt.Fprintf(
+				builder,
+				"- %s (%s, %s, `%s`)\n",
+				commit.Subject,
+				commit.Author,
+				commit.When.Format(time.DateOnly),
+				commit.Hash[:min(len(commit.Hash), 7)],
+			)
+		}
+		if len(repo.Commits) > maxCommitsPerRepo {
+			fmt.Fprintf(builder, "- …and %d more commits\n", len(repo.Commits)-maxCommitsPerRepo)
+		}
+	}
+}
@@ -0,0 +1,83 @@
+package digesttool
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	digest := Digest{
+		Start: windowStart,
+		End:   windowEnd,
+		Articles: []Article{{
+			PMID:      "38000001",
+			DOI:       "10.1000/dicty",
+			Title:     "Chemotaxis in Dictyostelium.",
+			Authors:   "Doe J, Roe R.",
+			Journal:   "Dev Biol",
+			Published: time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC),
+		}},
+		Annotations: []Annotation{
+			{GeneID: "DDB_G0267378", Symbol: "acaA", GOID: "GO:0004016", Aspect: "F", Evidence: "IDA"},
+			{GeneID: "DDB_G0267378", Symbol: "acaA", GOID: "GO:0019933", Aspect: "P", Evidence: "IMP", Reference: "PMID:1"},
+			{GeneID: "DDB_G0272112", Symbol: "carA", GOID: "GO:0005886", Aspect: "C", Evidence: "IDA"},
+		},
+		Repos: []RepoActivity{
+			{
+				URL:    "https://github.com/dictyBase/dictybase-frontpage.git",
+				Branch: "develop",
+				Commits: []worksummary.Commit{{
+					Hash:    "0123456789abcdef",
+					Author:  "Jane Doe",
+					When:    time.Date(2024, 1, 5, 0, 0, 0, 0, time.UTC),
+					Subject: "feat: add news feed",
+				}},
+			},
+			{URL: "https://github.com/dictyBase/gone", Branch: "develop", Err: errors.New("not found")},
+		},
+	}
+
+	rendered := RenderMarkdown(digest)
+	assert.Contains(t, rendered, "_2024-01-01 to 2024-01-31_")
+	assert.Contains(
+		t,
+		rendered,
+		"- **Chemotaxis in Dictyostelium** Doe J, Roe R. *Dev Biol* (2024-01-20) "+
+			"[PMID:38000001](https://pubmed.ncbi.nlm.nih.gov/38000001/) · [doi:10.1000/dicty](https://doi.org/10.1000/dicty)",
+	)
+	assert.Contains(t, rendered, "3 new GO annotations on 2 genes.")
+	assert.Contains(
+		t,
+		rendered,
+		"- **acaA** (DDB_G0267378): GO:0004016 (molecular function, IDA); "+
+			"GO:0019933 (biological process, IMP, PMID:1)",
+	)
+	assert.Less(
+		t,
+		strings.Index(rendered, "acaA"),
+		strings.Index(rendered, "carA"),
+		"most annotated gene first",
+	)
+	assert.Contains(t, rendered, "### dictybase-frontpage (develop)\n\n1 commits by 1 contributors.")
+	assert.Contains(t, rendered, "- feat: add news feed (Jane Doe, 2024-01-05, `0123456`)")
+	assert.Contains(t, rendered, "### gone (develop)\n\n_Unavailable: not found_")
+}
+
+func TestRenderMarkdown_FailedSources(t *testing.T) {
+	t.Parallel()
+	rendered := RenderMarkdown(Digest{
+		Start:          windowStart,
+		End:            windowEnd,
+		LiteratureErr:  errors.New("status 503"),
+		AnnotationsErr: errors.New("timeout"),
+	})
+	assert.Contains(t, rendered, "## New Literature\n\n_Unavailable: status 503_")
+	assert.Contains(t, rendered, "## New Gene Annotations\n\n_Unavailable: timeout_")
+	assert.NotContains(t, rendered, "Repository Activity")
+}
@@ -0,0 +1,234 @@
+package digesttool
+
+import (
+	"bufio"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const (
+	defaultEuropePMCURL = "https://www.ebi.ac.uk/europepmc/webservices/rest"
+	// defaultGAFURL is dictyBase's GO annotation file as published by the
+	// Gene Ontology Consortium.
+	defaultGAFURL = "https://current.geneontology.org/annotations/dictybase.gaf.gz"
+)
+
+// Sources fetches the data that goes into a digest.
+type Sources struct {
+	httpClient   *http.Client
+	europePMCURL string
+	gafURL       string
+	logger       *slog.Logger
+}
+
+// Option represents a configuration option for Sources.
+type Option func(*Config)
+
+// Config holds the configuration for Sources.
+type Config struct {
+	europePMCURL string
+	gafURL       string
+	timeout      time.Duration
+	logger       *slog.Logger
+}
+
+// WithEuropePMCURL overrides the Europe PMC REST API base URL.
+func WithEuropePMCURL(baseURL string) Option {
+	return func(c *Config) {
+		c.europePMCURL = baseURL
+	}
+}
+
+// WithGAFURL overrides the location of the GO annotation file. Gzipped and
+// plain files are both accepted.
+func WithGAFURL(gafURL string) Option {
+	return func(c *Config) {
+		c.gafURL = gafURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the sources.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewSources creates the digest data sources.
+func NewSources(opts ...Option) *Sources {
+	cfg := &Config{
+		europePMCURL: defaultEuropePMCURL,
+		gafURL:       defaultGAFURL,
+		timeout:      2 * time.Minute,
+		logger:       slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Sources{
+		httpClient:   ratelimit.NewHTTPClient(cfg.timeout),
+		europePMCURL: strings.TrimSuffix(cfg.europePMCURL, "/"),
+		gafURL:       cfg.gafURL,
+		logger:       cfg.logger,
+	}
+}
+
+// LiteratureParams holds the parameters of a literature search.
+type LiteratureParams struct {
+	Queries []string  `validate:"required,min=1,dive,required"`
+	Start   time.Time `validate:"required"`
+	End     time.Time `validate:"required"`
+	Limit   int       `validate:"gte=1,lte=1000"`
+}
+
+// searchResponse is the part of a Europe PMC search response we read.
+type searchResponse struct {
+	ResultList struct {
+		Result []struct {
+			PMID                 string `json:"pmid"`
+			DOI                  string `json:"doi"`
+			Title                string `json:"title"`
+			AuthorString         string `json:"authorString"`
+			JournalTitle         string `json:"journalTitle"`
+			FirstPublicationDate string `json:"firstPublicationDate"`
+		} `json:"result"`
+	} `json:"resultList"`
+}
+
+// NewLiterature returns articles first published within the window that
+// match any of the queries, newest first.
+func (s *Sources) NewLiterature(ctx context.Context, params LiteratureParams) ([]Article, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, fmt.Errorf("invalid literature parameters: %w", err)
+	}
+	clauses := make([]string, 0, len(params.Queries))
+	for _, query := range params.Queries {
+		clauses = append(clauses, "("+query+")")
+	}
+	query := fmt.Sprintf(
+		"(%s) AND FIRST_PDATE:[%s TO %s]",
+		strings.Join(clauses, " OR "),
+		params.Start.Format(time.DateOnly),
+		params.End.Format(time.DateOnly),
+	)
+	values := url.Values{
+		"query":      {query},
+		"format":     {"json"},
+		"resultType": {"lite"},
+		"pageSize":   {fmt.Sprintf("%d", params.Limit)},
+		"sort":       {"FIRST_PDATE_D desc"},
+	}
+
+	start := time.Now()
+	var response searchResponse
+	err := s.getJSON(ctx, s.europePMCURL+"/search?"+values.Encode(), &response)
+	metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search Europe PMC: %w", err)
+	}
+	articles := make([]Article, 0, len(response.ResultList.Result))
+	for _, result := range response.ResultList.Result {
+		published, _ := time.Parse(time.DateOnly, result.FirstPublicationDate)
+		articles = append(articles, Article{
+			PMID:      result.PMID,
+			DOI:       result.DOI,
+			Title:     strings.TrimSpace(result.Title),
+			Authors:   strings.TrimSpace(result.AuthorString),
+			Journal:   result.JournalTitle,
+			Published: published,
+		})
+	}
+	s.logger.Info("fetched new literature", "query", query, "count", len(articles))
+	return articles, nil
+}
+
+// NewAnnotations returns the GO annotations dated within the window, read
+// from the annotation file.
+func (s *Sources) NewAnnotations(ctx context.Context, start, end time.Time) ([]Annotation, error) {
+	fetchStart := time.Now()
+	annotations, err := s.readAnnotations(ctx, start, end)
+	metrics.ObserveOutbound(metrics.ServiceGeneOntology, fetchStart, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read GO annotations: %w", err)
+	}
+	s.logger.Info("read new GO annotations", "count", len(annotations))
+	return annotations, nil
+}
+
+// readAnnotations downloads and filters the annotation file.
+func (s *Sources) readAnnotations(ctx context.Context, start, end time.Time) ([]Annotation, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.gafURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating request: %w", err)
+	}
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error downloading %s: %w", s.gafURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("%s returned status %d", s.gafURL, resp.StatusCode)
+	}
+	reader, err := decompress(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	return ParseGAF(reader, start, end)
+}
+
+// getJSON fetches a URL and decodes the JSON response into out.
+func (s *Sources) getJSON(ctx context.Context, target string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Europe PMC: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("unexpected status %d from Europe PMC", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding Europe PMC response: %w", err)
+	}
+	return nil
+}
+
+// decompress transparently gunzips the body when it starts with the gzip
+// magic number.
+func decompress(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	magic, err := buffered.Peek(2)
+	if err != nil && err != io.EOF {
+		return nil, fmt.Errorf("error reading annotation file: %w", err)
+	}
+	if len(magic) == 2 && magic[0] == 0x1f && magic[1] == 0x8b {
+		gzipReader, err := gzip.NewReader(buffered)
+		if err != nil {
+			return nil, fmt.Errorf("error decompressing annotation file: %w", err)
+		}
+		return gzipReader, nil
+	}
+	return buffered, nil
+}
@@ -0,0 +1,110 @@
+package digesttool
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testGAF = `!gaf-version: 2.2
+!generated-by: dictyBase
+dictyBase	DDB_G0267378	acaA	enables	GO:0004016	PMID:8394351	IDA		F	adenylate cyclase A	acaA	protein	taxon:44689	20240115	dictyBase
+dictyBase	DDB_G0267378	acaA		GO:0019933	PMID:8394351	IMP		P	adenylate cyclase A	acaA	protein	taxon:44689	20240131	dictyBase
+dictyBase	DDB_G0272112	carA		GO:0005886	PMID:1234	IDA		C	cAMP receptor 1	carA	protein	taxon:44689	20231231	dictyBase
+too	short
+`
+
+const searchJSON = `{"resultList":{"result":[
+	{"pmid":"38000001","doi":"10.1000/dicty","title":"Chemotaxis in Dictyostelium.",
+	 "authorString":"Doe J, Roe R.","journalTitle":"Dev Biol","firstPublicationDate":"2024-01-20"}
+]}}`
+
+var (
+	windowStart = time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	windowEnd   = time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+)
+
+func TestParseGAF(t *testing.T) {
+	t.Parallel()
+	annotations, err := ParseGAF(strings.NewReader(testGAF), windowStart, windowEnd)
+	require.NoError(t, err)
+	require.Len(t, annotations, 2)
+	assert.Equal(t, "DDB_G0267378", annotations[0].GeneID)
+	assert.Equal(t, "acaA", annotations[0].Symbol)
+	assert.Equal(t, "enables", annotations[0].Qualifier)
+	assert.Equal(t, "GO:0004016", annotations[0].GOID)
+	assert.Equal(t, "F", annotations[0].Aspect)
+	assert.Equal(t, "IDA", annotations[0].Evidence)
+	assert.Equal(t, windowEnd, annotations[1].Date)
+}
+
+func TestSources(t *testing.T) {
+	t.Parallel()
+	var compressed bytes.Buffer
+	gzipWriter := gzip.NewWriter(&compressed)
+	_, err := io.WriteString(gzipWriter, testGAF)
+	require.NoError(t, err)
+	require.NoError(t, gzipWriter.Close())
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(
+			t,
+			"((dictyostelium) OR (polysphondylium)) AND FIRST_PDATE:[2024-01-01 TO 2024-01-31]",
+			r.URL.Query().Get("query"),
+		)
+		assert.Equal(t, "25", r.URL.Query().Get("pageSize"))
+		_, _ = io.WriteString(w, searchJSON)
+	})
+	mux.HandleFunc("GET /dictybase.gaf.gz", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = w.Write(compressed.Bytes())
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	sources := NewSources(
+		WithEuropePMCURL(server.URL),
+		WithGAFURL(server.URL+"/dictybase.gaf.gz"),
+	)
+
+	articles, err := sources.NewLiterature(context.Background(), LiteratureParams{
+		Queries: []string{"dictyostelium", "polysphondylium"},
+		Start:   windowStart,
+		End:     windowEnd,
+		Limit:   25,
+	})
+	require.NoError(t, err)
+	require.Len(t, articles, 1)
+	assert.Equal(t, "38000001", articles[0].PMID)
+	assert.Equal(t, "Dev Biol", articles[0].Journal)
+	assert.Equal(t, time.Date(2024, 1, 20, 0, 0, 0, 0, time.UTC), articles[0].Published)
+
+	annotations, err := sources.NewAnnotations(context.Background(), windowStart, windowEnd)
+	require.NoError(t, err)
+	assert.Len(t, annotations, 2)
+}
+
+func TestSources_Errors(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	sources := NewSources(WithEuropePMCURL(server.URL), WithGAFURL(server.URL+"/missing.gaf"))
+
+	_, err := sources.NewLiterature(context.Background(), LiteratureParams{
+		Queries: []string{"dictyostelium"},
+		Start:   windowStart,
+		End:     windowEnd,
+		Limit:   10,
+	})
+	require.ErrorContains(t, err, "status 404")
+	_, err = sources.NewAnnotations(context.Background(), windowStart, windowEnd)
+	require.ErrorContains(t, err, "status 404")
+}
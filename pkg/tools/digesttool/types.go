@@ -0,0 +1,54 @@
+package digesttool
+
+import (
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Article is a newly published article matching the digest queries.
+type Article struct {
+	PMID      string
+	DOI       string
+	Title     string
+	Authors   string
+	Journal   string
+	Published time.Time
+}
+
+// Annotation is a GO annotation from the dictyBase annotation file.
+type Annotation struct {
+	GeneID    string
+	Symbol    string
+	Qualifier string
+	GOID      string
+	// Aspect is P (biological process), F (molecular function) or C
+	// (cellular component).
+	Aspect     string
+	Evidence   string
+	Reference  string
+	AssignedBy string
+	Date       time.Time
+}
+
+// RepoActivity holds the commits made to a repository branch.
+type RepoActivity struct {
+	URL     string
+	Branch  string
+	Commits []worksummary.Commit
+	// Err is set when the repository could not be read.
+	Err error
+}
+
+// Digest is the content of one community digest.
+type Digest struct {
+	Start       time.Time
+	End         time.Time
+	Articles    []Article
+	Annotations []Annotation
+	Repos       []RepoActivity
+	// LiteratureErr and AnnotationsErr are set when a source failed; the
+	// digest is still rendered with the other sections.
+	LiteratureErr  error
+	AnnotationsErr error
+}
@@ -0,0 +1,617 @@
+package documenttool
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/document"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Tool actions.
+const (
+	ActionCreate  = "create"
+	ActionRevise  = "revise"
+	ActionHistory = "history"
+	ActionDiff    = "diff"
+	ActionExport  = "export"
+	ActionList    = "list"
+	// ActionComment attaches a comment to a revision.
+	ActionComment = "comment"
+	// ActionResolve marks a comment as resolved.
+	ActionResolve = "resolve"
+	// ActionComments lists the comments of a document.
+	ActionComments = "comments"
+)
+
+// Export formats.
+const (
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// DocumentTool lets clients draft a named document over several calls:
+// create it, append revisions, compare any two of them, comment on them
+// and export the final version, or an annotated review copy, to the
+// artifact store.
+type DocumentTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	documents   *document.Store
+	store       artifact.Store
+	uploads     *upload.Store
+}
+
+// Option defines a functional option for configuring DocumentTool.
+type Option func(*DocumentTool)
+
+// WithStore sets the artifact store final versions are exported to. The
+// default store writes to the local filesystem.
+func WithStore(store artifact.Store) Option {
+	return func(d *DocumentTool) {
+		d.store = store
+	}
+}
+
+// WithUploads lets calls pass revisions as completed uploads.
+func WithUploads(store *upload.Store) Option {
+	return func(d *DocumentTool) {
+		d.uploads = store
+	}
+}
+
+// ExportRequest represents the parameters of the export action.
+type ExportRequest struct {
+	Name string `validate:"required"`
+	// Revision is the revision exported, the latest when 0.
+	Revision int    `validate:"gte=0"`
+	Format   string `validate:"required,oneof=markdown html"`
+	// Annotated exports a review copy with the open comments as footnotes,
+	// leaving the final version unchanged.
+	Annotated bool
+}
+
+// Export describes the version written to the artifact store.
+type Export struct {
+	Document  document.Info     `json:"document"`
+	Revision  int               `json:"revision"`
+	Format    string            `json:"format"`
+	Annotated bool              `json:"annotated,omitempty"`
+	Comments  int               `json:"comments,omitempty"`
+	Artifact  artifact.Artifact `json:"artifact"`
+}
+
+// CommentListing is the result of the comments action.
+type CommentListing struct {
+	Name     string             `json:"name"`
+	Comments []document.Comment `json:"comments"`
+}
+
+// Listing is the result of the list action.
+type Listing struct {
+	Documents []document.Info `json:"documents"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"document",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewDocumentTool(deps.Logger, deps.Documents, WithStore(deps.Store), WithUploads(deps.Uploads))
+		},
+	)
+}
+
+// NewDocumentTool creates a new DocumentTool instance keeping documents in
+// documents.
+func NewDocumentTool(logger *slog.Logger, documents *document.Store, opts ...Option) (*DocumentTool, error) {
+	if documents == nil {
+		return nil, errors.New("document tool requires a document store")
+	}
+	tool := mcp.NewTool(
+		"document",
+		mcp.WithDescription(
+			"Drafts a named markdown document over several calls: create it, revise it with new versions, "+
+				"list its history, diff any two revisions, comment on passages or lines for review, and export "+
+				"the final version, or an annotated copy with the open comments as footnotes, as markdown or HTML",
+		),
+		mcp.WithTitleAnnotation("Document Revisions"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString(
+			"action",
+			mcp.Description("create, revise, history, diff, export, list, comment, resolve or comments"),
+			mcp.Required(),
+			mcp.Enum(
+				ActionCreate, ActionRevise, ActionHistory, ActionDiff, ActionExport, ActionList,
+				ActionComment, ActionResolve, ActionComments,
+			),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Description("Name of the document, lowercase letters, digits and hyphens (all actions but list)"),
+		),
+		mcp.WithString("title", mcp.Description("Title of the document (create)")),
+		mcp.WithString(
+			"content",
+			mcp.Description("Markdown of the new revision (revise, and optionally create for the first revision)"),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the revision, instead of content"),
+		),
+		mcp.WithString("note", mcp.Description("What changed in the revision (create, revise)")),
+		mcp.WithNumber(
+			"from",
+			mcp.Description("Older revision to compare (diff; defaults to the one before to)"),
+		),
+		mcp.WithNumber("to", mcp.Description("Newer revision to compare (diff; defaults to the latest)")),
+		mcp.WithNumber(
+			"revision",
+			mcp.Description("Revision to export or comment on (export, comment; defaults to the latest)"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("markdown (default) or html (export)"),
+			mcp.Enum(FormatMarkdown, FormatHTML),
+		),
+		mcp.WithBoolean(
+			"annotated",
+			mcp.Description("Export a review copy with the open comments as footnotes instead of the final version (export)"),
+		),
+		mcp.WithString("author", mcp.Description("Who wrote the comment (comment)")),
+		mcp.WithString("text", mcp.Description("Text of the comment (comment)")),
+		mcp.WithString(
+			"quote",
+			mcp.Description("Exact passage of the revision the comment is about (comment; optional)"),
+		),
+		mcp.WithNumber(
+			"line",
+			mcp.Description("Line of the revision the comment is about, from 1, instead of a quote (comment; optional)"),
+		),
+		mcp.WithNumber("comment_id", mcp.Description("Comment to mark as resolved (resolve)")),
+	)
+	documentTool := &DocumentTool{
+		Name:        "document",
+		Description: "Drafts, revises, compares, comments on and exports named documents",
+		Tool:        tool,
+		Logger:      logger,
+		documents:   documents,
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(documentTool)
+	}
+	return documentTool, nil
+}
+
+// GetName returns the name of the tool.
+func (d *DocumentTool) GetName() string {
+	return d.Name
+}
+
+// GetDescription returns the description of the tool.
+func (d *DocumentTool) GetDescription() string {
+	return d.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (d *DocumentTool) GetSchema() mcp.ToolInputSchema {
+	return d.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (d *DocumentTool) GetTool() mcp.Tool {
+	return d.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (d *DocumentTool) GetAnnotations() mcp.ToolAnnotation {
+	return d.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (d *DocumentTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Start a grant progress report from a first draft",
+			Arguments: map[string]any{
+				"action":  ActionCreate,
+				"name":    "r01-progress-2025",
+				"title":   "R01 Progress Report 2025",
+				"content": "# Progress Report\n\nStrain collection grew by 120 strains.\n",
+				"note":    "First draft",
+			},
+		},
+		{
+			Description: "Compare the latest revision with the first draft",
+			Arguments: map[string]any{
+				"action": ActionDiff,
+				"name":   "r01-progress-2025",
+				"from":   1,
+			},
+		},
+		{
+			Description: "Comment on a passage of the latest revision",
+			Arguments: map[string]any{
+				"action": ActionComment,
+				"name":   "r01-progress-2025",
+				"author": "PI",
+				"text":   "Give the number of strains distributed too.",
+				"quote":  "grew by 120 strains",
+			},
+		},
+		{
+			Description: "Export a review copy with the open comments as footnotes",
+			Arguments: map[string]any{
+				"action":    ActionExport,
+				"name":      "r01-progress-2025",
+				"annotated": true,
+			},
+		},
+		{
+			Description: "Export the latest revision as the final version in HTML",
+			Arguments: map[string]any{
+				"action": ActionExport,
+				"name":   "r01-progress-2025",
+				"format": FormatHTML,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (d *DocumentTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	name := request.GetString("name", "")
+	var (
+		result *mcp.CallToolResult
+		err    error
+	)
+	switch action := request.GetString("action", ""); action {
+	case ActionCreate:
+		result, err = d.create(request, name)
+	case ActionRevise:
+		result, err = d.revise(request, name)
+	case ActionHistory:
+		result, err = d.history(name)
+	case ActionDiff:
+		result, err = d.diff(request, name)
+	case ActionExport:
+		result, err = d.export(ctx, ExportRequest{
+			Name:      name,
+			Revision:  request.GetInt("revision", 0),
+			Format:    request.GetString("format", FormatMarkdown),
+			Annotated: request.GetBool("annotated", false),
+		})
+	case ActionList:
+		result, err = d.list()
+	case ActionComment:
+		result, err = d.comment(request, name)
+	case ActionResolve:
+		result, err = d.resolve(request, name)
+	case ActionComments:
+		result, err = d.comments(name)
+	default:
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_ACTION",
+			"action must be one of create, revise, history, diff, export, list, comment, resolve or comments",
+		)), nil
+	}
+	if err != nil {
+		return toolerror.Result(classify(err)), nil
+	}
+	return result, nil
+}
+
+// create adds a document, with the content of the request as its first
+// revision when given.
+func (d *DocumentTool) create(request mcp.CallToolRequest, name string) (*mcp.CallToolResult, error) {
+	var content string
+	if _, ok := request.GetArguments()["content"]; ok || request.GetString(upload.IDArgument, "") != "" {
+		var err error
+		if content, err = upload.TextArgument(d.uploads, request, "content"); err != nil {
+			return nil, err
+		}
+	}
+	created, err := d.documents.Create(document.CreateParams{
+		Name:    name,
+		Title:   request.GetString("title", ""),
+		Content: content,
+		Note:    request.GetString("note", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	info := created.Info()
+	text := fmt.Sprintf("Created document %q", info.Name)
+	if info.Revisions > 0 {
+		text += " with its first revision"
+	}
+	return mcp.NewToolResultStructured(info, text), nil
+}
+
+// revise appends the content of the request to a document.
+func (d *DocumentTool) revise(request mcp.CallToolRequest, name string) (*mcp.CallToolResult, error) {
+	content, err := upload.TextArgument(d.uploads, request, "content")
+	if err != nil {
+		return nil, err
+	}
+	revision, err := d.documents.Revise(document.ReviseParams{
+		Name:    name,
+		Content: content,
+		Note:    request.GetString("note", ""),
+	})
+	if err != nil {
+		return nil, err
+	}
+	revision.Content = ""
+	return mcp.NewToolResultStructured(
+		revision,
+		fmt.Sprintf("Saved revision %d of %q (%d bytes)", revision.Number, name, revision.Size),
+	), nil
+}
+
+// history lists the revisions of a document.
+func (d *DocumentTool) history(name string) (*mcp.CallToolResult, error) {
+	found, err := d.documents.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	found.Revisions = found.History()
+	var text strings.Builder
+	fmt.Fprintf(&text, "# %s\n\n", cmp.Or(found.Title, found.Name))
+	if len(found.Revisions) == 0 {
+		text.WriteString("No revisions yet.\n")
+		return mcp.NewToolResultStructured(found, text.String()), nil
+	}
+	text.WriteString("| Revision | Saved | Size | Note |\n|----------|-------|------|------|\n")
+	for _, revision := range found.Revisions {
+		number := fmt.Sprint(revision.Number)
+		if revision.Number == found.Final {
+			number += " (final)"
+		}
+		fmt.Fprintf(&text, "| %s | %s | %d bytes | %s |\n",
+			number, revision.CreatedAt.UTC().Format(time.DateTime), revision.Size, revision.Note)
+	}
+	return mcp.NewToolResultStructured(found, text.String()), nil
+}
+
+// diff compares two revisions of a document.
+func (d *DocumentTool) diff(request mcp.CallToolRequest, name string) (*mcp.CallToolResult, error) {
+	found, err := d.documents.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	diff, err := document.Compare(found, request.GetInt("from", 0), request.GetInt("to", 0))
+	if err != nil {
+		return nil, err
+	}
+	if diff.Unified == "" {
+		return mcp.NewToolResultStructured(
+			diff,
+			fmt.Sprintf("Revisions %d and %d of %q are the same.", diff.From, diff.To, name),
+		), nil
+	}
+	return mcp.NewToolResultStructured(diff, fmt.Sprintf(
+		"Revision %d to %d of %q (+%d -%d lines):\n\n```diff\n%s```\n",
+		diff.From, diff.To, name, diff.Added, diff.Removed, diff.Unified,
+	)), nil
+}
+
+// export writes a revision of a document to the artifact store and records
+// it as the final version; an annotated export is a review copy with the
+// open comments as footnotes, stored under its own name.
+func (d *DocumentTool) export(ctx context.Context, params ExportRequest) (*mcp.CallToolResult, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, toolerror.InvalidInput(err)
+	}
+	found, err := d.documents.Get(params.Name)
+	if err != nil {
+		return nil, err
+	}
+	revision, err := found.Revision(params.Revision)
+	if err != nil {
+		return nil, err
+	}
+	content, base, comments := revision.Content, params.Name, 0
+	if params.Annotated {
+		open := found.OpenComments()
+		content, base, comments = document.Annotate(revision, open), params.Name+"-annotated", len(open)
+	}
+	data, contentType, extension := []byte(content), "text/markdown", ".md"
+	if params.Format == FormatHTML {
+		if data, err = markdown.NewParser().Parse(data); err != nil {
+			return nil, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_MARKDOWN", err, "failed to render document")
+		}
+		contentType, extension = "text/html", ".html"
+	}
+	stored, err := d.store.Put(ctx, artifact.PutParams{
+		Name:        base + extension,
+		ContentType: contentType,
+		Body:        bytes.NewReader(data),
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", base+extension, err)
+	}
+	text := fmt.Sprintf(
+		"Exported revision %d of %q as the final version to %s", revision.Number, params.Name, stored.Location,
+	)
+	if params.Annotated {
+		text = fmt.Sprintf(
+			"Exported an annotated copy of revision %d of %q to %s (open comments: %d)",
+			revision.Number, params.Name, stored.Location, comments,
+		)
+	} else {
+		if _, err := d.documents.MarkFinal(params.Name, revision.Number); err != nil {
+			return nil, err
+		}
+		found.Final = revision.Number
+	}
+	logging.WithRequestID(d.Logger).Info(
+		"exported document", "document", params.Name, "revision", revision.Number, "format", params.Format,
+		"annotated", params.Annotated,
+	)
+
+	if stored.URL != "" {
+		text += "\nDownload URL: " + stored.URL
+	}
+	return mcp.NewToolResultStructured(Export{
+		Document:  found.Info(),
+		Revision:  revision.Number,
+		Format:    params.Format,
+		Annotated: params.Annotated,
+		Comments:  comments,
+		Artifact:  *stored,
+	}, text), nil
+}
+
+// list summarizes the documents.
+func (d *DocumentTool) list() (*mcp.CallToolResult, error) {
+	listing := Listing{Documents: d.documents.List()}
+	if len(listing.Documents) == 0 {
+		return mcp.NewToolResultStructured(listing, "No documents yet."), nil
+	}
+	var text strings.Builder
+	text.WriteString("| Document | Title | Revisions | Final | Updated |\n")
+	text.WriteString("|----------|-------|-----------|-------|---------|\n")
+	for _, info := range listing.Documents {
+		final := "-"
+		if info.Final > 0 {
+			final = fmt.Sprint(info.Final)
+		}
+		fmt.Fprintf(&text, "| %s | %s | %d | %s | %s |\n",
+			info.Name, info.Title, info.Revisions, final, info.UpdatedAt.UTC().Format(time.DateTime))
+	}
+	return mcp.NewToolResultStructured(listing, text.String()), nil
+}
+
+// comment attaches the comment of the request to a revision of a document.
+func (d *DocumentTool) comment(request mcp.CallToolRequest, name string) (*mcp.CallToolResult, error) {
+	comment, err := d.documents.AddComment(document.CommentParams{
+		Name:     name,
+		Revision: request.GetInt("revision", 0),
+		Author:   request.GetString("author", ""),
+		Text:     request.GetString("text", ""),
+		Quote:    request.GetString("quote", ""),
+		Line:     request.GetInt("line", 0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(comment, fmt.Sprintf(
+		"Added comment %d on revision %d of %q (%s)", comment.ID, comment.Revision, name, anchor(comment),
+	)), nil
+}
+
+// resolve marks a comment of a document as resolved.
+func (d *DocumentTool) resolve(request mcp.CallToolRequest, name string) (*mcp.CallToolResult, error) {
+	comment, err := d.documents.ResolveComment(name, request.GetInt("comment_id", 0))
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultStructured(
+		comment,
+		fmt.Sprintf("Resolved comment %d on %q", comment.ID, name),
+	), nil
+}
+
+// comments lists the comments of a document, open and resolved.
+func (d *DocumentTool) comments(name string) (*mcp.CallToolResult, error) {
+	found, err := d.documents.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	listing := CommentListing{Name: found.Name, Comments: found.Comments}
+	if len(listing.Comments) == 0 {
+		return mcp.NewToolResultStructured(listing, fmt.Sprintf("No comments on %q yet.", name)), nil
+	}
+	var text strings.Builder
+	fmt.Fprintf(&text, "# Comments on %s\n\n", cmp.Or(found.Title, found.Name))
+	text.WriteString("| Comment | Revision | Author | On | Text | Status |\n")
+	text.WriteString("|---------|----------|--------|----|------|--------|\n")
+	for _, comment := range listing.Comments {
+		status := "open"
+		if comment.Resolved {
+			status = "resolved"
+		}
+		fmt.Fprintf(&text, "| %d | %d | %s | %s | %s | %s |\n",
+			comment.ID, comment.Revision, comment.Author, anchor(comment), cell(comment.Text), status)
+	}
+	return mcp.NewToolResultStructured(listing, text.String()), nil
+}
+
+// anchor describes what a comment is about.
+func anchor(comment document.Comment) string {
+	switch {
+	case comment.Quote != "":
+		return fmt.Sprintf("%q", strings.Join(strings.Fields(comment.Quote), " "))
+	case comment.Line > 0:
+		return fmt.Sprintf("line %d", comment.Line)
+	default:
+		return "whole revision"
+	}
+}
+
+// cell flattens text into a markdown table cell.
+func cell(text string) string {
+	return strings.ReplaceAll(strings.Join(strings.Fields(text), " "), "|", "\\|")
+}
+
+// classify maps document store errors onto tool error types.
+func classify(err error) error {
+	var (
+		toolErr          *toolerror.Error
+		validationErrors validator.ValidationErrors
+	)
+	switch {
+	case errors.As(err, &toolErr):
+		return err
+	case errors.As(err, &validationErrors):
+		return toolerror.InvalidInput(err)
+	case errors.Is(err, document.ErrNotFound):
+		return toolerror.Wrap(toolerror.TypeNotFound, "DOCUMENT_NOT_FOUND", err, "document failed")
+	case errors.Is(err, document.ErrNoRevision):
+		return toolerror.Wrap(toolerror.TypeNotFound, "REVISION_NOT_FOUND", err, "document failed")
+	case errors.Is(err, document.ErrCommentNotFound):
+		return toolerror.Wrap(toolerror.TypeNotFound, "COMMENT_NOT_FOUND", err, "document failed")
+	case errors.Is(err, document.ErrAnchorNotFound),
+		errors.Is(err, document.ErrConflictingQuote),
+		errors.Is(err, document.ErrTooManyComments):
+		return toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_COMMENT", err, "document failed")
+	case errors.Is(err, document.ErrExists):
+		return toolerror.Wrap(toolerror.TypeInvalidInput, "DOCUMENT_EXISTS", err, "document failed")
+	case errors.Is(err, document.ErrInvalidName),
+		errors.Is(err, document.ErrUnchanged),
+		errors.Is(err, document.ErrTooLarge),
+		errors.Is(err, document.ErrTooManyDrafts):
+		return toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_DOCUMENT", err, "document failed")
+	default:
+		return toolerror.Wrap(toolerror.TypeInternal, "DOCUMENT_FAILED", err, "document failed")
+	}
+}
@@ -0,0 +1,180 @@
+package documenttool
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/document"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDocumentTool(t *testing.T) {
+	t.Parallel()
+	_, err := NewDocumentTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), nil)
+	require.Error(t, err)
+
+	tool, err := NewDocumentTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), document.NewStore())
+	require.NoError(t, err)
+	assert.Equal(t, "document", tool.GetName())
+	assert.Equal(t, []string{"action"}, tool.GetSchema().Required)
+	assert.Contains(t, tool.GetSchema().Properties, "upload_id")
+}
+
+func TestHandler_Workflow(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	uploads := upload.NewStore()
+	tool, err := NewDocumentTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		document.NewStore(),
+		WithStore(artifact.NewLocalStore(dir)),
+		WithUploads(uploads),
+	)
+	require.NoError(t, err)
+
+	result := tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionCreate, "name": "progress", "title": "Progress Report", "content": "# Progress\n\nDraft.\n",
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Equal(t, `Created document "progress" with its first revision`, tooltest.Text(t, result))
+
+	info, err := uploads.Begin(upload.BeginParams{ContentType: "text/markdown"})
+	require.NoError(t, err)
+	_, err = uploads.Append(upload.AppendParams{ID: info.ID, Data: []byte("# Progress\n\nFinal **text**.\n")})
+	require.NoError(t, err)
+	_, err = uploads.Complete(info.ID, "")
+	require.NoError(t, err)
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionRevise, "name": "progress", "upload_id": info.ID, "note": "Reviewed",
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	revision, ok := result.StructuredContent.(document.Revision)
+	require.True(t, ok)
+	assert.Equal(t, 2, revision.Number)
+	assert.Empty(t, revision.Content)
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionDiff, "name": "progress"})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Equal(t,
+		"Revision 1 to 2 of \"progress\" (+1 -1 lines):\n\n```diff\n--- progress@1\n+++ progress@2\n"+
+			"@@ -1,3 +1,3 @@\n # Progress\n \n-Draft.\n+Final **text**.\n```\n",
+		tooltest.Text(t, result),
+	)
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionExport, "name": "progress", "format": FormatHTML,
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	exported, ok := result.StructuredContent.(Export)
+	require.True(t, ok)
+	assert.Equal(t, 2, exported.Revision)
+	assert.Equal(t, 2, exported.Document.Final)
+	data, err := os.ReadFile(filepath.Join(dir, "progress.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "<strong>text</strong>")
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionHistory, "name": "progress"})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Contains(t, tooltest.Text(t, result), "# Progress Report\n")
+	assert.Contains(t, tooltest.Text(t, result), "| 2 (final) |")
+	assert.Contains(t, tooltest.Text(t, result), "| Reviewed |")
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionList})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	listing, ok := result.StructuredContent.(Listing)
+	require.True(t, ok)
+	require.Len(t, listing.Documents, 1)
+	assert.Equal(t, 2, listing.Documents[0].Revisions)
+}
+
+func TestHandler_Comments(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tool, err := NewDocumentTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		document.NewStore(),
+		WithStore(artifact.NewLocalStore(dir)),
+	)
+	require.NoError(t, err)
+	result := tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionCreate, "name": "notes", "content": "# Notes\n\nStrains: 10\n",
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionComment, "name": "notes", "author": "Ada", "text": "Recount.", "quote": "10",
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Equal(t, `Added comment 1 on revision 1 of "notes" ("10")`, tooltest.Text(t, result))
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{
+		"action": ActionComment, "name": "notes", "author": "Grace", "text": "Add a summary.", "line": 1,
+	})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionResolve, "name": "notes", "comment_id": 2})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Equal(t, `Resolved comment 2 on "notes"`, tooltest.Text(t, result))
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionComments, "name": "notes"})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	assert.Contains(t, tooltest.Text(t, result), "| 1 | 1 | Ada | \"10\" | Recount. | open |")
+	assert.Contains(t, tooltest.Text(t, result), "| 2 | 1 | Grace | line 1 | Add a summary. | resolved |")
+
+	result = tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionExport, "name": "notes", "annotated": true})
+	require.False(t, result.IsError, tooltest.Text(t, result))
+	exported, ok := result.StructuredContent.(Export)
+	require.True(t, ok)
+	assert.True(t, exported.Annotated)
+	assert.Equal(t, 1, exported.Comments)
+	assert.Zero(t, exported.Document.Final, "annotated exports are not final versions")
+	data, err := os.ReadFile(filepath.Join(dir, "notes-annotated.md"))
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "Strains: 10[^comment-1]\n")
+	assert.NotContains(t, string(data), "[^comment-2]", "resolved comments are left out")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	tool, err := NewDocumentTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), document.NewStore())
+	require.NoError(t, err)
+	result := tooltest.Call(t, tool.Handler, "document", map[string]any{"action": ActionCreate, "name": "notes"})
+	require.False(t, result.IsError, "a document may be created without a revision")
+
+	for _, tc := range []struct {
+		args map[string]any
+		want toolerror.Type
+	}{
+		{args: map[string]any{"action": "rename"}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionCreate}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionCreate, "name": "Notes"}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionCreate, "name": "notes"}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionRevise, "name": "notes"}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionRevise, "name": "other", "content": "x"}, want: toolerror.TypeNotFound},
+		{args: map[string]any{"action": ActionDiff, "name": "notes"}, want: toolerror.TypeNotFound},
+		{args: map[string]any{"action": ActionExport, "name": "notes"}, want: toolerror.TypeNotFound},
+		{args: map[string]any{"action": ActionExport, "name": "notes", "format": "pdf"}, want: toolerror.TypeInvalidInput},
+		{args: map[string]any{"action": ActionComment, "name": "notes", "author": "Ada"}, want: toolerror.TypeInvalidInput},
+		{
+			args: map[string]any{"action": ActionComment, "name": "notes", "author": "Ada", "text": "x"},
+			want: toolerror.TypeNotFound,
+		},
+		{args: map[string]any{"action": ActionResolve, "name": "notes", "comment_id": 1}, want: toolerror.TypeNotFound},
+		{args: map[string]any{"action": ActionComments, "name": "other"}, want: toolerror.TypeNotFound},
+		{
+			args: map[string]any{"action": ActionRevise, "name": "notes", "upload_id": "upl_missing"},
+			want: toolerror.TypeConfiguration,
+		},
+	} {
+		result := tooltest.Call(t, tool.Handler, "document", tc.args)
+		require.True(t, result.IsError, tc.args)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok, tc.args)
+		assert.Equal(t, tc.want, toolErr.Type, tc.args)
+	}
+}
@@ -0,0 +1,204 @@
+package genelittool
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const defaultBaseURL = "https://graphql.dictybase.org/graphql"
+
+// ErrGeneNotFound is returned when dictyBase has no gene with the ID or
+// name.
+var ErrGeneNotFound = errors.New("gene not found in dictyBase")
+
+// ErrPublicationNotFound is returned when dictyBase has no publication with
+// the PMID.
+var ErrPublicationNotFound = errors.New("publication not found in dictyBase")
+
+// publicationFields are the fields read of every publication.
+const publicationFields = `id doi title journal pub_date authors { last_name initials }`
+
+const (
+	geneQuery = `query Gene($gene: String!) {
+  gene(gene: $gene) { id name }
+}`
+	publicationsQuery = `query ListPublicationsWithGene($gene: String!) {
+  listPublicationsWithGene(gene: $gene) { ` + publicationFields + ` related_genes { id name } }
+}`
+	publicationQuery = `query Publication($id: ID!) {
+  publication(id: $id) { ` + publicationFields + ` related_genes { id name } }
+}`
+)
+
+// DictyBaseClient reads genes and publications from the dictyBase GraphQL
+// API.
+type DictyBaseClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// Option represents a configuration option for DictyBaseClient.
+type Option func(*Config)
+
+// Config holds the configuration for the dictyBase client.
+type Config struct {
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithBaseURL overrides the dictyBase GraphQL endpoint.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewDictyBaseClient creates a new dictyBase client.
+func NewDictyBaseClient(opts ...Option) *DictyBaseClient {
+	cfg := &Config{
+		baseURL: defaultBaseURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &DictyBaseClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:    strings.TrimSuffix(cfg.baseURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// Gene looks up a gene by its dictyBase ID or name.
+func (c *DictyBaseClient) Gene(ctx context.Context, gene string) (Gene, error) {
+	var data struct {
+		Gene *Gene `json:"gene"`
+	}
+	if err := c.query(ctx, geneQuery, map[string]any{"gene": gene}, &data); err != nil {
+		return Gene{}, fmt.Errorf("failed to look up gene %s: %w", gene, err)
+	}
+	if data.Gene == nil || data.Gene.ID == "" {
+		return Gene{}, fmt.Errorf("%w: %s", ErrGeneNotFound, gene)
+	}
+	return *data.Gene, nil
+}
+
+// Publications returns the publications dictyBase links to a gene, newest
+// first.
+func (c *DictyBaseClient) Publications(ctx context.Context, gene Gene) ([]Publication, error) {
+	var data struct {
+		Publications []publicationResponse `json:"listPublicationsWithGene"`
+	}
+	if err := c.query(ctx, publicationsQuery, map[string]any{"gene": gene.ID}, &data); err != nil {
+		return nil, fmt.Errorf("failed to list publications of %s: %w", gene.ID, err)
+	}
+	publications := make([]Publication, 0, len(data.Publications))
+	for _, response := range data.Publications {
+		publications = append(publications, toPublication(response))
+	}
+	slices.SortStableFunc(publications, func(a, b Publication) int {
+		return cmp.Compare(b.Year, a.Year)
+	})
+	c.logger.Debug("listed gene publications", "gene", gene.ID, "count", len(publications))
+	return publications, nil
+}
+
+// Publication looks up a publication by PMID with the genes dictyBase
+// links to it.
+func (c *DictyBaseClient) Publication(ctx context.Context, pmid string) (Publication, error) {
+	var data struct {
+		Publication *publicationResponse `json:"publication"`
+	}
+	if err := c.query(ctx, publicationQuery, map[string]any{"id": pmid}, &data); err != nil {
+		return Publication{}, fmt.Errorf("failed to look up publication %s: %w", pmid, err)
+	}
+	if data.Publication == nil || data.Publication.ID == "" {
+		return Publication{}, fmt.Errorf("%w: PMID %s", ErrPublicationNotFound, pmid)
+	}
+	return toPublication(*data.Publication), nil
+}
+
+// query runs a GraphQL query and decodes its data into out.
+func (c *DictyBaseClient) query(ctx context.Context, query string, variables map[string]any, out any) error {
+	start := time.Now()
+	err := c.post(ctx, query, variables, out)
+	metrics.ObserveOutbound(metrics.ServiceDictyBase, start, err)
+	return err
+}
+
+// graphQLResponse is the envelope of a GraphQL response.
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// post performs the HTTP round trip for query. Errors saying that a record
+// was not found leave out empty, as a missing record is not a failure of
+// the API.
+func (c *DictyBaseClient) post(ctx context.Context, query string, variables map[string]any, out any) error {
+	body, err := json.Marshal(map[string]any{"query": query, "variables": variables})
+	if err != nil {
+		return fmt.Errorf("error encoding dictyBase query: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.baseURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error creating dictyBase request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling dictyBase: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("dictyBase returned status %d", resp.StatusCode)
+	}
+	var response graphQLResponse
+	if err := json.NewDecoder(resp.Body).Decode(&response); err != nil {
+		return fmt.Errorf("error decoding dictyBase response: %w", err)
+	}
+	for _, graphQLErr := range response.Errors {
+		if !strings.Contains(strings.ToLower(graphQLErr.Message), "not found") {
+			return fmt.Errorf("dictyBase returned an error: %s", graphQLErr.Message)
+		}
+	}
+	if len(response.Data) == 0 || string(response.Data) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(response.Data, out); err != nil {
+		return fmt.Errorf("error decoding dictyBase data: %w", err)
+	}
+	return nil
+}
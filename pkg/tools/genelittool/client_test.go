@@ -0,0 +1,95 @@
+package genelittool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubDictyBase serves the gene gpaB with two publications, the second
+// also linked to carA-1.
+func stubDictyBase(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request struct {
+			Query     string         `json:"query"`
+			Variables map[string]any `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.Contains(request.Query, "listPublicationsWithGene"):
+			_, _ = w.Write([]byte(`{"data": {"listPublicationsWithGene": [
+				{"id": "11111111", "title": "Old paper", "journal": "Dev Biol", "pub_date": "2001-05-01T00:00:00Z",
+				 "authors": [{"last_name": "Doe", "initials": "J"}], "related_genes": [{"id": "DDB_G0283275", "name": "gpaB"}]},
+				{"id": "23172289", "doi": "10.1093/nar/gks1064", "title": "New paper", "journal": "Nucleic Acids Res",
+				 "pub_date": "2013-01-01T00:00:00Z",
+				 "authors": [{"last_name": "Basu", "initials": "S"}, {"last_name": "Fey", "initials": "P"}],
+				 "related_genes": [{"id": "DDB_G0283275", "name": "gpaB"}, {"id": "DDB_G0273397", "name": "carA-1"}]}
+			]}}`))
+		case strings.Contains(request.Query, "gene(") && request.Variables["gene"] == "gpaB":
+			_, _ = w.Write([]byte(`{"data": {"gene": {"id": "DDB_G0283275", "name": "gpaB"}}}`))
+		case strings.Contains(request.Query, "gene("):
+			_, _ = w.Write([]byte(`{"data": {"gene": null}, "errors": [{"message": "gene not found"}]}`))
+		case strings.Contains(request.Query, "publication(") && request.Variables["id"] == "23172289":
+			_, _ = w.Write([]byte(`{"data": {"publication": {"id": "23172289", "title": "New paper",
+				"pub_date": "2013-01-01T00:00:00Z", "related_genes": [{"id": "DDB_G0283275", "name": "gpaB"}]}}}`))
+		case strings.Contains(request.Query, "publication("):
+			_, _ = w.Write([]byte(`{"data": null, "errors": [{"message": "publication with id 1 not found"}]}`))
+		default:
+			_, _ = w.Write([]byte(`{"errors": [{"message": "unknown query"}]}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDictyBaseClient(t *testing.T) {
+	t.Parallel()
+	client := NewDictyBaseClient(WithBaseURL(stubDictyBase(t).URL))
+	ctx := context.Background()
+
+	gene, err := client.Gene(ctx, "gpaB")
+	require.NoError(t, err)
+	assert.Equal(t, Gene{ID: "DDB_G0283275", Name: "gpaB"}, gene)
+
+	publications, err := client.Publications(ctx, gene)
+	require.NoError(t, err)
+	require.Len(t, publications, 2)
+	assert.Equal(t, "23172289", publications[0].PMID, "newest first")
+	assert.Equal(t, "2013", publications[0].Year)
+	assert.Equal(t, []string{"Basu S", "Fey P"}, publications[0].Authors)
+	assert.Len(t, publications[0].Genes, 2)
+
+	publication, err := client.Publication(ctx, "23172289")
+	require.NoError(t, err)
+	assert.Equal(t, []Gene{{ID: "DDB_G0283275", Name: "gpaB"}}, publication.Genes)
+
+	_, err = client.Gene(ctx, "noSuchGene")
+	require.ErrorIs(t, err, ErrGeneNotFound)
+	_, err = client.Publication(ctx, "1")
+	require.ErrorIs(t, err, ErrPublicationNotFound)
+}
+
+func TestDictyBaseClient_Errors(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/down" {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		_, _ = w.Write([]byte(`{"errors": [{"message": "Cannot query field \"gene\""}]}`))
+	}))
+	t.Cleanup(server.Close)
+
+	_, err := NewDictyBaseClient(WithBaseURL(server.URL)).Gene(context.Background(), "gpaB")
+	require.ErrorContains(t, err, `dictyBase returned an error: Cannot query field "gene"`)
+	_, err = NewDictyBaseClient(WithBaseURL(server.URL+"/down")).Gene(context.Background(), "gpaB")
+	require.ErrorContains(t, err, "status 502")
+}
@@ -0,0 +1,226 @@
+package genelittool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultLimit is the number of publications of a gene listed by
+	// default.
+	defaultLimit = 20
+	// maxLimit bounds the publications of a gene listed.
+	maxLimit = 200
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// GeneLiteratureTool links dictyBase genes and publications in both
+// directions, so curators can move between genes and the literature tool.
+type GeneLiteratureTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring GeneLiteratureTool.
+type ToolOption func(*GeneLiteratureTool)
+
+// WithClientOptions sets options for the dictyBase clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(g *GeneLiteratureTool) {
+		g.clientOptions = append(g.clientOptions, opts...)
+	}
+}
+
+// LiteratureRequest represents the parameters of a lookup. Exactly one of
+// Gene and PMID is set.
+type LiteratureRequest struct {
+	// Gene is a dictyBase gene ID or gene name.
+	Gene  string `validate:"required_without=PMID,excluded_with=PMID"`
+	PMID  string `validate:"required_without=Gene,excluded_with=Gene,omitempty,numeric"`
+	Limit int    `validate:"min=1,max=200"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"gene-literature",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewGeneLiteratureTool(deps.Logger)
+		},
+	)
+}
+
+// NewGeneLiteratureTool creates a new GeneLiteratureTool instance.
+func NewGeneLiteratureTool(logger *slog.Logger, opts ...ToolOption) (*GeneLiteratureTool, error) {
+	tool := mcp.NewTool(
+		"gene-literature",
+		mcp.WithDescription(
+			"Lists the publications dictyBase links to a gene, given its gene ID or name, or the genes "+
+				"dictyBase links to a publication, given its PMID. Use literature-fetch for the full records",
+		),
+		mcp.WithTitleAnnotation("Gene Literature"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"gene",
+			mcp.Description("A dictyBase gene ID such as DDB_G0283275 or a gene name such as gpaB; "+
+				"give either gene or pmid"),
+		),
+		mcp.WithString(
+			"pmid",
+			mcp.Description("A PubMed ID to list the genes of; give either gene or pmid"),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description(fmt.Sprintf(
+				"Maximum number of publications of a gene to list, newest first, at most %d (defaults to %d)",
+				maxLimit,
+				defaultLimit,
+			)),
+		),
+	)
+	geneTool := &GeneLiteratureTool{
+		Name:        "gene-literature",
+		Description: "Links dictyBase genes and publications",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(geneTool)
+	}
+	return geneTool, nil
+}
+
+// GetName returns the name of the tool.
+func (g *GeneLiteratureTool) GetName() string {
+	return g.Name
+}
+
+// GetDescription returns the description of the tool.
+func (g *GeneLiteratureTool) GetDescription() string {
+	return g.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (g *GeneLiteratureTool) GetSchema() mcp.ToolInputSchema {
+	return g.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (g *GeneLiteratureTool) GetTool() mcp.Tool {
+	return g.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GeneLiteratureTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GeneLiteratureTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the ten newest publications of a gene by name",
+			Arguments: map[string]any{
+				"gene":  "gpaB",
+				"limit": 10,
+			},
+		},
+		{
+			Description: "List the genes curated from a paper",
+			Arguments: map[string]any{
+				"pmid": "23172289",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (g *GeneLiteratureTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := LiteratureRequest{
+		Gene:  strings.TrimSpace(request.GetString("gene", "")),
+		PMID:  normalizePMID(request.GetString("pmid", "")),
+		Limit: request.GetInt("limit", defaultLimit),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	result, err := g.Lookup(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	return provenance.Attach(
+		mcp.NewToolResultStructured(result, RenderMarkdown(result)),
+		provenance.New([]string{metrics.ServiceDictyBase}),
+	), nil
+}
+
+// Lookup lists the publications of the gene, or the genes of the
+// publication, of params.
+func (g *GeneLiteratureTool) Lookup(ctx context.Context, params LiteratureRequest) (GeneLiterature, error) {
+	client := NewDictyBaseClient(append([]Option{WithLogger(logging.WithRequestID(g.Logger))}, g.clientOptions...)...)
+	if params.PMID != "" {
+		publication, err := client.Publication(ctx, params.PMID)
+		if err != nil {
+			return GeneLiterature{}, classify(err, "failed to look up publication")
+		}
+		return GeneLiterature{Publication: &publication}, nil
+	}
+	gene, err := client.Gene(ctx, params.Gene)
+	if err != nil {
+		return GeneLiterature{}, classify(err, "failed to look up gene")
+	}
+	publications, err := client.Publications(ctx, gene)
+	if err != nil {
+		return GeneLiterature{}, classify(err, "failed to list publications")
+	}
+	return GeneLiterature{
+		Gene:         &gene,
+		Publications: publications[:min(len(publications), params.Limit)],
+		Total:        len(publications),
+	}, nil
+}
+
+// classify reports records dictyBase does not have as not found and any
+// other failure as an upstream error.
+func classify(err error, prefix string) *toolerror.Error {
+	switch {
+	case errors.Is(err, ErrGeneNotFound):
+		return toolerror.Wrap(toolerror.TypeNotFound, "GENE_NOT_FOUND", err, prefix)
+	case errors.Is(err, ErrPublicationNotFound):
+		return toolerror.Wrap(toolerror.TypeNotFound, "PUBLICATION_NOT_FOUND", err, prefix)
+	default:
+		return toolerror.Upstream(metrics.ServiceDictyBase, err, prefix)
+	}
+}
+
+// normalizePMID strips a PMID: prefix and surrounding space.
+func normalizePMID(pmid string) string {
+	pmid = strings.TrimSpace(pmid)
+	for _, prefix := range []string{"PMID:", "pmid:", "PMID"} {
+		pmid = strings.TrimPrefix(pmid, prefix)
+	}
+	return strings.TrimSpace(pmid)
+}
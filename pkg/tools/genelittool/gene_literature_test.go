@@ -0,0 +1,71 @@
+package genelittool
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, baseURL string, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool, err := NewGeneLiteratureTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithClientOptions(WithBaseURL(baseURL)),
+	)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "gene-literature", arguments)
+}
+
+func TestHandler_Gene(t *testing.T) {
+	t.Parallel()
+	result := callTool(t, stubDictyBase(t).URL, map[string]any{"gene": "gpaB", "limit": 1})
+	require.False(t, result.IsError)
+	literature, ok := result.StructuredContent.(GeneLiterature)
+	require.True(t, ok)
+	assert.Equal(t, "DDB_G0283275", literature.Gene.ID)
+	assert.Equal(t, 2, literature.Total)
+	require.Len(t, literature.Publications, 1)
+	assert.Equal(t, "23172289", literature.Publications[0].PMID)
+}
+
+func TestHandler_PMID(t *testing.T) {
+	t.Parallel()
+	result := callTool(t, stubDictyBase(t).URL, map[string]any{"pmid": "PMID:23172289"})
+	require.False(t, result.IsError)
+	literature, ok := result.StructuredContent.(GeneLiterature)
+	require.True(t, ok)
+	require.NotNil(t, literature.Publication)
+	assert.Equal(t, "gpaB", literature.Publication.Genes[0].Name)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	baseURL := stubDictyBase(t).URL
+	for _, arguments := range []map[string]any{
+		{},
+		{"gene": "gpaB", "pmid": "23172289"},
+		{"pmid": "not-a-pmid"},
+		{"gene": "gpaB", "limit": 0},
+		{"gene": "gpaB", "limit": 500},
+	} {
+		result := callTool(t, baseURL, arguments)
+		require.True(t, result.IsError, arguments)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+	for arguments, code := range map[string]string{"gene": "GENE_NOT_FOUND", "pmid": "PUBLICATION_NOT_FOUND"} {
+		result := callTool(t, baseURL, map[string]any{arguments: "1"})
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeNotFound, toolErr.Type)
+		assert.Equal(t, code, toolErr.Code)
+	}
+}
@@ -0,0 +1,96 @@
+package genelittool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxListedAuthors is the number of authors named per publication.
+const maxListedAuthors = 3
+
+// RenderMarkdown renders the publications of a gene, or the genes of a
+// publication, as markdown.
+func RenderMarkdown(result GeneLiterature) string {
+	var sb strings.Builder
+	if result.Publication != nil {
+		renderGenes(&sb, *result.Publication)
+		return sb.String()
+	}
+	gene := result.Gene
+	fmt.Fprintf(&sb, "# Publications of %s (%s)\n\n", gene.Name, gene.ID)
+	if len(result.Publications) == 0 {
+		sb.WriteString("dictyBase links no publications to the gene.\n")
+		return sb.String()
+	}
+	if result.Total > len(result.Publications) {
+		fmt.Fprintf(
+			&sb,
+			"The %d newest of %d publications linked in dictyBase.\n\n",
+			len(result.Publications), result.Total,
+		)
+	} else {
+		fmt.Fprintf(&sb, "%d publications linked in dictyBase, newest first.\n\n", result.Total)
+	}
+	for i, publication := range result.Publications {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, citation(publication))
+		if others := otherGenes(publication.Genes, gene.ID); others != "" {
+			fmt.Fprintf(&sb, "   Also linked to: %s\n", others)
+		}
+	}
+	sb.WriteString("\nCall `literature-fetch` with a PMID for the abstract and full record.\n")
+	return sb.String()
+}
+
+// renderGenes writes the genes linked to a publication.
+func renderGenes(sb *strings.Builder, publication Publication) {
+	fmt.Fprintf(sb, "# Genes of PMID %s\n\n%s\n\n", publication.PMID, citation(publication))
+	if len(publication.Genes) == 0 {
+		sb.WriteString("dictyBase links no genes to the publication.\n")
+		return
+	}
+	sb.WriteString("| Gene | ID |\n|------|----|\n")
+	for _, gene := range publication.Genes {
+		fmt.Fprintf(sb, "| %s | [%s](%s) |\n", gene.Name, gene.ID, GeneURL(gene.ID))
+	}
+}
+
+// citation formats a publication on one line: title, first authors,
+// journal, year and identifiers.
+func citation(publication Publication) string {
+	parts := []string{"**" + publication.Title + "**"}
+	if len(publication.Authors) > 0 {
+		authors := strings.Join(publication.Authors[:min(len(publication.Authors), maxListedAuthors)], ", ")
+		if len(publication.Authors) > maxListedAuthors {
+			authors += " et al."
+		}
+		parts = append(parts, authors)
+	}
+	if publication.Journal != "" {
+		parts = append(parts, "*"+publication.Journal+"*")
+	}
+	if publication.Year != "" {
+		parts = append(parts, "("+publication.Year+")")
+	}
+	ids := fmt.Sprintf("PMID [%s](https://pubmed.ncbi.nlm.nih.gov/%s/)", publication.PMID, publication.PMID)
+	if publication.DOI != "" {
+		ids += fmt.Sprintf(", [doi:%s](https://doi.org/%s)", publication.DOI, publication.DOI)
+	}
+	return strings.Join(parts, " ") + ". " + ids
+}
+
+// otherGenes names the genes of a publication other than the looked up
+// one.
+func otherGenes(genes []Gene, geneID string) string {
+	names := make([]string, 0, len(genes))
+	for _, gene := range genes {
+		if gene.ID != geneID {
+			names = append(names, gene.Name)
+		}
+	}
+	return strings.Join(names, ", ")
+}
+
+// GeneURL returns the dictyBase page of a gene.
+func GeneURL(geneID string) string {
+	return "https://dictybase.org/gene/" + geneID
+}
@@ -0,0 +1,40 @@
+package genelittool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	gene := &Gene{ID: "DDB_G0283275", Name: "gpaB"}
+	publication := Publication{
+		PMID:    "23172289",
+		DOI:     "10.1093/nar/gks1064",
+		Title:   "New paper",
+		Journal: "Nucleic Acids Res",
+		Year:    "2013",
+		Authors: []string{"Basu S", "Fey P", "Pilcher KE", "Chisholm RL"},
+		Genes:   []Gene{*gene, {ID: "DDB_G0273397", Name: "carA-1"}},
+	}
+	rendered := RenderMarkdown(GeneLiterature{Gene: gene, Publications: []Publication{publication}, Total: 3})
+	assert.Contains(t, rendered, "# Publications of gpaB (DDB_G0283275)")
+	assert.Contains(t, rendered, "The 1 newest of 3 publications linked in dictyBase.")
+	assert.Contains(
+		t,
+		rendered,
+		"1. **New paper** Basu S, Fey P, Pilcher KE et al. *Nucleic Acids Res* (2013). "+
+			"PMID [23172289](https://pubmed.ncbi.nlm.nih.gov/23172289/), "+
+			"[doi:10.1093/nar/gks1064](https://doi.org/10.1093/nar/gks1064)\n",
+	)
+	assert.Contains(t, rendered, "   Also linked to: carA-1\n")
+	assert.Contains(t, rendered, "`literature-fetch`")
+
+	empty := RenderMarkdown(GeneLiterature{Gene: gene})
+	assert.Contains(t, empty, "dictyBase links no publications to the gene.")
+
+	genes := RenderMarkdown(GeneLiterature{Publication: &publication})
+	assert.Contains(t, genes, "# Genes of PMID 23172289")
+	assert.Contains(t, genes, "| carA-1 | [DDB_G0273397](https://dictybase.org/gene/DDB_G0273397) |\n")
+}
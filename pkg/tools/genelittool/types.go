@@ -0,0 +1,75 @@
+package genelittool
+
+import "strings"
+
+// Gene is a dictyBase gene.
+type Gene struct {
+	// ID is the dictyBase gene identifier, e.g. DDB_G0283275.
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+// Publication is a paper curated in dictyBase.
+type Publication struct {
+	// PMID is the PubMed ID, which dictyBase uses as publication ID.
+	PMID    string   `json:"pmid"`
+	DOI     string   `json:"doi,omitempty"`
+	Title   string   `json:"title"`
+	Journal string   `json:"journal,omitempty"`
+	Year    string   `json:"year,omitempty"`
+	Authors []string `json:"authors,omitempty"`
+	// Genes are the genes dictyBase links to the publication.
+	Genes []Gene `json:"genes,omitempty"`
+}
+
+// GeneLiterature is the result of a lookup: the publications of a gene,
+// or the genes of a publication.
+type GeneLiterature struct {
+	// Gene is the looked up gene, nil for a publication lookup.
+	Gene *Gene `json:"gene,omitempty"`
+	// Publications are the publications of the gene, newest first.
+	Publications []Publication `json:"publications,omitempty"`
+	// Total is the number of publications of the gene, which may be more
+	// than listed.
+	Total int `json:"total,omitempty"`
+	// Publication is the looked up publication, nil for a gene lookup.
+	Publication *Publication `json:"publication,omitempty"`
+}
+
+// authorName is the name of a publication author in the dictyBase API.
+type authorName struct {
+	LastName string `json:"last_name"`
+	Initials string `json:"initials"`
+}
+
+// publicationResponse is a publication as returned by the dictyBase API.
+type publicationResponse struct {
+	ID           string       `json:"id"`
+	DOI          string       `json:"doi"`
+	Title        string       `json:"title"`
+	Journal      string       `json:"journal"`
+	PubDate      string       `json:"pub_date"`
+	Authors      []authorName `json:"authors"`
+	RelatedGenes []Gene       `json:"related_genes"`
+}
+
+// toPublication converts a publication of the dictyBase API.
+func toPublication(response publicationResponse) Publication {
+	publication := Publication{
+		PMID:    response.ID,
+		DOI:     response.DOI,
+		Title:   strings.TrimSpace(response.Title),
+		Journal: response.Journal,
+		Genes:   response.RelatedGenes,
+	}
+	if len(response.PubDate) >= 4 {
+		publication.Year = response.PubDate[:4]
+	}
+	for _, author := range response.Authors {
+		name := strings.TrimSpace(author.LastName + " " + author.Initials)
+		if name != "" {
+			publication.Authors = append(publication.Authors, name)
+		}
+	}
+	return publication
+}
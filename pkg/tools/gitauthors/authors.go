@@ -0,0 +1,111 @@
+package gitauthors
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Author is a distinct commit author. Commits are grouped by author name,
+// the value git-summary filters on, so one person committing from several
+// addresses is listed once.
+type Author struct {
+	Name        string    `json:"name"`
+	Emails      []string  `json:"emails"`
+	Commits     int       `json:"commits"`
+	FirstCommit time.Time `json:"first_commit"`
+	LastCommit  time.Time `json:"last_commit"`
+}
+
+// Authors lists the authors of a branch.
+type Authors struct {
+	RepoURL string `json:"repo_url"`
+	Branch  string `json:"branch"`
+	// Range is set when the commits were limited to a date range.
+	Range   *worksummary.DateRange `json:"-"`
+	Start   *time.Time             `json:"start,omitempty"`
+	End     *time.Time             `json:"end,omitempty"`
+	Commits int                    `json:"commits"`
+	Authors []Author               `json:"authors"`
+}
+
+// groupAuthors groups commits by author name, ignoring case and keeping
+// the spelling of the latest commit. Authors with the most commits come
+// first, then the most recently active.
+func groupAuthors(commits []worksummary.Commit) []Author {
+	byName := make(map[string]*Author)
+	var order []string
+	for _, commit := range commits {
+		key := strings.ToLower(strings.TrimSpace(commit.Author))
+		author, found := byName[key]
+		if !found {
+			author = &Author{Name: commit.Author, FirstCommit: commit.When, LastCommit: commit.When}
+			byName[key] = author
+			order = append(order, key)
+		}
+		author.Commits++
+		if commit.Email != "" && !slices.Contains(author.Emails, commit.Email) {
+			author.Emails = append(author.Emails, commit.Email)
+		}
+		if commit.When.Before(author.FirstCommit) {
+			author.FirstCommit = commit.When
+		}
+		if commit.When.After(author.LastCommit) {
+			author.Name = commit.Author
+			author.LastCommit = commit.When
+		}
+	}
+	authors := make([]Author, 0, len(order))
+	for _, key := range order {
+		author := *byName[key]
+		slices.Sort(author.Emails)
+		authors = append(authors, author)
+	}
+	slices.SortStableFunc(authors, func(a, b Author) int {
+		return cmp.Or(
+			cmp.Compare(b.Commits, a.Commits),
+			b.LastCommit.Compare(a.LastCommit),
+			cmp.Compare(a.Name, b.Name),
+		)
+	})
+	return authors
+}
+
+// RenderMarkdown renders the authors as a markdown table.
+func RenderMarkdown(authors Authors) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Authors of %s (%s)\n\n", authors.RepoURL, authors.Branch)
+	if authors.Range != nil {
+		builder.WriteString(authors.Range.Header())
+		builder.WriteString("\n")
+	}
+	if len(authors.Authors) == 0 {
+		builder.WriteString("No commits found.\n")
+		return builder.String()
+	}
+	fmt.Fprintf(&builder, "%d commits by %d authors. Pass names or emails as the `authors` of git-summary.\n\n",
+		authors.Commits, len(authors.Authors))
+	builder.WriteString("| Author | Emails | Commits | First commit | Last commit |\n")
+	builder.WriteString("|--------|--------|---------|--------------|-------------|\n")
+	for _, author := range authors.Authors {
+		fmt.Fprintf(
+			&builder,
+			"| %s | %s | %d | %s | %s |\n",
+			escapeCell(author.Name),
+			escapeCell(strings.Join(author.Emails, ", ")),
+			author.Commits,
+			author.FirstCommit.Format(time.DateOnly),
+			author.LastCommit.Format(time.DateOnly),
+		)
+	}
+	return builder.String()
+}
+
+// escapeCell keeps text from breaking a markdown table.
+func escapeCell(text string) string {
+	return strings.ReplaceAll(text, "|", `\|`)
+}
@@ -0,0 +1,54 @@
+package gitauthors
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupAuthors(t *testing.T) {
+	t.Parallel()
+	june := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	authors := groupAuthors([]worksummary.Commit{
+		{Author: "Ann", Email: "ann@example.org", When: june.AddDate(0, 0, 9)},
+		{Author: "Bob", Email: "bob@example.org", When: june.AddDate(0, 0, 8)},
+		{Author: "bob", Email: "bob@example.org", When: june.AddDate(0, 0, 1)},
+		{Author: "Cy", When: june.AddDate(0, 0, 5)},
+	})
+	assert.Equal(t, []Author{
+		{
+			Name:        "Bob",
+			Emails:      []string{"bob@example.org"},
+			Commits:     2,
+			FirstCommit: june.AddDate(0, 0, 1),
+			LastCommit:  june.AddDate(0, 0, 8),
+		},
+		{
+			Name:        "Ann",
+			Emails:      []string{"ann@example.org"},
+			Commits:     1,
+			FirstCommit: june.AddDate(0, 0, 9),
+			LastCommit:  june.AddDate(0, 0, 9),
+		},
+		{Name: "Cy", Commits: 1, FirstCommit: june.AddDate(0, 0, 5), LastCommit: june.AddDate(0, 0, 5)},
+	}, authors, "ties are broken by the latest activity")
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	empty := RenderMarkdown(Authors{RepoURL: "https://github.com/dictybase/dcr-mcp", Branch: "main"})
+	assert.Contains(t, empty, "# Authors of https://github.com/dictybase/dcr-mcp (main)")
+	assert.Contains(t, empty, "No commits found.")
+
+	when := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	rendered := RenderMarkdown(Authors{
+		RepoURL: "https://github.com/dictybase/dcr-mcp",
+		Branch:  "main",
+		Commits: 1,
+		Authors: []Author{{Name: "A|B", Commits: 1, FirstCommit: when, LastCommit: when}},
+	})
+	assert.Contains(t, rendered, "1 commits by 1 authors.")
+	assert.Contains(t, rendered, `| A\|B |  | 1 | 2025-06-02 | 2025-06-02 |`)
+}
@@ -0,0 +1,221 @@
+package gitauthors
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// GitAuthorsTool lists the authors of a repository, so clients can offer
+// valid author values before calling git-summary.
+type GitAuthorsTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+}
+
+// ToolOption defines a functional option for configuring GitAuthorsTool.
+type ToolOption func(*GitAuthorsTool)
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(g *GitAuthorsTool) {
+		g.analyzer = analyzer
+	}
+}
+
+// AuthorsRequest represents the parameters for listing authors.
+type AuthorsRequest struct {
+	RepoURL string `validate:"required"`
+	Branch  string `validate:"required"`
+	// StartDate limits the commits to a date range; without it the whole
+	// history is read.
+	StartDate string
+	EndDate   string `validate:"excluded_without=StartDate"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"git-authors",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewGitAuthorsTool(deps.Logger)
+		},
+	)
+}
+
+// NewGitAuthorsTool creates a new GitAuthorsTool instance.
+func NewGitAuthorsTool(logger *slog.Logger, opts ...ToolOption) (*GitAuthorsTool, error) {
+	tool := mcp.NewTool(
+		"git-authors",
+		mcp.WithDescription(
+			"Lists the distinct commit authors of a git repository branch with their emails, commit counts "+
+				"and first and last activity, to find valid author values for git-summary",
+		),
+		mcp.WithTitleAnnotation("Git Authors"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to read"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description(
+				"Only count commits from this date on, in any standard format or in words such as 'last month' "+
+					"(default: the whole history)",
+			),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description(
+				"Only count commits up to this date, read like the end_date of git-summary; needs start_date",
+			),
+		),
+	)
+	authorsTool := &GitAuthorsTool{
+		Name:        "git-authors",
+		Description: "Lists the commit authors of a repository",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(authorsTool)
+	}
+	return authorsTool, nil
+}
+
+// GetName returns the name of the tool.
+func (g *GitAuthorsTool) GetName() string {
+	return g.Name
+}
+
+// GetDescription returns the description of the tool.
+func (g *GitAuthorsTool) GetDescription() string {
+	return g.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (g *GitAuthorsTool) GetSchema() mcp.ToolInputSchema {
+	return g.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (g *GitAuthorsTool) GetTool() mcp.Tool {
+	return g.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GitAuthorsTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GitAuthorsTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List everyone who committed to a branch",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-stock",
+				"branch":   "develop",
+			},
+		},
+		{
+			Description: "List the authors active last month, before summarizing their work",
+			Arguments: map[string]any{
+				"repo_url":   "https://github.com/dictybase/dcr-mcp",
+				"branch":     "main",
+				"start_date": "last month",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (g *GitAuthorsTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := AuthorsRequest{
+		RepoURL:   request.GetString("repo_url", ""),
+		Branch:    request.GetString("branch", ""),
+		StartDate: request.GetString("start_date", ""),
+		EndDate:   request.GetString("end_date", ""),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	authors, err := g.ListAuthors(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	result := mcp.NewToolResultStructured(authors, RenderMarkdown(authors))
+	return provenance.Attach(result, provenance.New([]string{metrics.ServiceGitClone})), nil
+}
+
+// ListAuthors clones the branch and groups its commits, within the date
+// range when one is given, by author. Dependency bots are left out, as in
+// git-summary.
+func (g *GitAuthorsTool) ListAuthors(ctx context.Context, params AuthorsRequest) (Authors, error) {
+	authors := Authors{RepoURL: params.RepoURL, Branch: params.Branch}
+	activity := worksummary.ActivityParams{}
+	if params.StartDate != "" {
+		dateRange, err := g.analyzer.ResolveDateRange(params.StartDate, params.EndDate)
+		if err != nil {
+			return Authors{}, toolerror.Wrap(
+				toolerror.TypeInvalidInput,
+				"INVALID_DATE_RANGE",
+				err,
+				"failed to parse dates",
+			)
+		}
+		authors.Range = &dateRange
+		authors.Start, authors.End = &dateRange.Start, &dateRange.End
+		activity.Start, activity.End = dateRange.Start, dateRange.End
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := g.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.9)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Authors{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	reporter.Report(0.9, 1, "listing authors")
+	activity.Repo = repo.Repository
+	commits, err := g.analyzer.ListActivity(ctx, activity)
+	if err != nil {
+		return Authors{}, fmt.Errorf("failed to list commits: %w", err)
+	}
+	authors.Commits = len(commits)
+	authors.Authors = groupAuthors(commits)
+	reporter.Report(1, 1, "authors listed")
+	return authors, nil
+}
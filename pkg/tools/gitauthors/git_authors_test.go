@@ -0,0 +1,83 @@
+package gitauthors
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(
+		worksummary.WithTimeZone(time.UTC),
+		worksummary.WithCurrentTime(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)),
+	)
+	tool, err := NewGitAuthorsTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), WithAnalyzer(analyzer))
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "git-authors", arguments)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC) }
+	commit := func(name, email string, when time.Time) gittest.Commit {
+		return gittest.Commit{Author: object.Signature{Name: name, Email: email, When: when}}
+	}
+	dir := gittest.NewRepo(t,
+		commit("Jane Doe", "jane@example.org", day(time.April, 2)),
+		commit("Joe", "joe@example.org", day(time.June, 3)),
+		commit("jane doe", "jdoe@users.noreply.github.com", day(time.June, 4)),
+		commit("dependabot[bot]", "bot@github.com", day(time.June, 5)),
+		commit("Jane Doe", "jane@example.org", day(time.June, 6)),
+	).Dir
+
+	result := callTool(t, map[string]any{"repo_url": dir, "branch": "master"})
+	require.False(t, result.IsError)
+	authors, ok := result.StructuredContent.(Authors)
+	require.True(t, ok)
+	assert.Equal(t, 4, authors.Commits, "bot commits are left out")
+	require.Len(t, authors.Authors, 2)
+	jane := authors.Authors[0]
+	assert.Equal(t, "Jane Doe", jane.Name)
+	assert.Equal(t, 3, jane.Commits)
+	assert.Equal(t, []string{"jane@example.org", "jdoe@users.noreply.github.com"}, jane.Emails)
+	assert.Equal(t, day(time.April, 2), jane.FirstCommit.UTC())
+	assert.Equal(t, day(time.June, 6), jane.LastCommit.UTC())
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "| Joe | joe@example.org | 1 | 2025-06-03 | 2025-06-03 |\n")
+
+	result = callTool(t, map[string]any{"repo_url": dir, "branch": "master", "start_date": "2025-06-04"})
+	require.False(t, result.IsError)
+	authors, ok = result.StructuredContent.(Authors)
+	require.True(t, ok)
+	require.Len(t, authors.Authors, 1)
+	assert.Equal(t, 2, authors.Authors[0].Commits, "commits before the range are left out")
+	require.NotNil(t, authors.Start)
+	assert.Equal(t, day(time.June, 4).Truncate(24*time.Hour), authors.Start.UTC())
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	for _, arguments := range []map[string]any{
+		{"repo_url": "https://github.com/dictybase/dcr-mcp"},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "main", "end_date": "yesterday"},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "main", "start_date": "not a date at all"},
+	} {
+		result := callTool(t, arguments)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
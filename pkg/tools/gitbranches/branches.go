@@ -0,0 +1,69 @@
+package gitbranches
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Branches lists the branches of a repository.
+type Branches struct {
+	RepoURL string `json:"repo_url"`
+	worksummary.RemoteBranches
+}
+
+// RenderMarkdown renders the branches as a markdown table.
+func RenderMarkdown(branches Branches) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Branches of %s\n\n", branches.RepoURL)
+	if len(branches.Branches) == 0 {
+		builder.WriteString("The repository has no branches.\n")
+		return builder.String()
+	}
+	if branches.DefaultBranch != "" {
+		fmt.Fprintf(&builder, "%d branches, the default is `%s`. Pass a name as the `branch` of git-summary.\n\n",
+			len(branches.Branches), branches.DefaultBranch)
+	} else {
+		fmt.Fprintf(&builder, "%d branches. Pass a name as the `branch` of git-summary.\n\n", len(branches.Branches))
+	}
+	builder.WriteString("| Branch | Last commit | Ahead | Behind |\n")
+	builder.WriteString("|--------|-------------|-------|--------|\n")
+	unknown := false
+	for _, branch := range branches.Branches {
+		name := "`" + branch.Name + "`"
+		ahead, behind := count(branch.Ahead), count(branch.Behind)
+		if branch.Default {
+			name += " (default)"
+			ahead, behind = "-", "-"
+		} else if branch.Ahead == nil || branch.Behind == nil {
+			unknown = true
+		}
+		fmt.Fprintf(
+			&builder,
+			"| %s | %s | %s | %s |\n",
+			name,
+			branch.LastCommit.Format(time.DateOnly),
+			ahead,
+			behind,
+		)
+	}
+	if unknown && branches.DefaultBranch != "" {
+		fmt.Fprintf(
+			&builder,
+			"\n? marks branches that do not meet `%s` within their latest %d commits; raise `depth` to compare them.\n",
+			branches.DefaultBranch, branches.Depth,
+		)
+	}
+	return builder.String()
+}
+
+// count renders a commit count, or ? when it is not known.
+func count(commits *int) string {
+	if commits == nil {
+		return "?"
+	}
+	return strconv.Itoa(*commits)
+}
@@ -0,0 +1,36 @@
+package gitbranches
+
+import (
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	empty := RenderMarkdown(Branches{RepoURL: "https://github.com/dictybase/dcr-mcp"})
+	assert.Contains(t, empty, "# Branches of https://github.com/dictybase/dcr-mcp")
+	assert.Contains(t, empty, "The repository has no branches.")
+
+	when := time.Date(2025, 6, 2, 0, 0, 0, 0, time.UTC)
+	ahead, behind := 3, 0
+	rendered := RenderMarkdown(Branches{
+		RepoURL: "https://github.com/dictybase/dcr-mcp",
+		RemoteBranches: worksummary.RemoteBranches{
+			DefaultBranch: "main",
+			Depth:         200,
+			Branches: []worksummary.RemoteBranch{
+				{Name: "main", Default: true, LastCommit: when},
+				{Name: "feature/stock", LastCommit: when, Ahead: &ahead, Behind: &behind},
+				{Name: "legacy", LastCommit: when.AddDate(-3, 0, 0)},
+			},
+		},
+	})
+	assert.Contains(t, rendered, "3 branches, the default is `main`.")
+	assert.Contains(t, rendered, "| `main` (default) | 2025-06-02 | - | - |\n")
+	assert.Contains(t, rendered, "| `feature/stock` | 2025-06-02 | 3 | 0 |\n")
+	assert.Contains(t, rendered, "| `legacy` | 2022-06-02 | ? | ? |\n")
+	assert.Contains(t, rendered, "within their latest 200 commits")
+}
@@ -0,0 +1,183 @@
+package gitbranches
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxDepth bounds the commits fetched per branch.
+const maxDepth = 5000
+
+// Initialize validator.
+var validate = validator.New()
+
+// GitBranchesTool lists the branches of a repository, so clients can pick
+// a valid branch before calling git-summary instead of guessing.
+type GitBranchesTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+}
+
+// ToolOption defines a functional option for configuring GitBranchesTool.
+type ToolOption func(*GitBranchesTool)
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(g *GitBranchesTool) {
+		g.analyzer = analyzer
+	}
+}
+
+// BranchesRequest represents the parameters for listing branches.
+type BranchesRequest struct {
+	RepoURL string `validate:"required"`
+	// Depth is the number of commits of every branch fetched to compare it
+	// with the default branch.
+	Depth int `validate:"min=1,max=5000"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"git-branches",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewGitBranchesTool(deps.Logger)
+		},
+	)
+}
+
+// NewGitBranchesTool creates a new GitBranchesTool instance.
+func NewGitBranchesTool(logger *slog.Logger, opts ...ToolOption) (*GitBranchesTool, error) {
+	tool := mcp.NewTool(
+		"git-branches",
+		mcp.WithDescription(
+			"Lists the branches of a git repository with their last commit date and the commits they are "+
+				"ahead of and behind the default branch, without cloning it, to find a valid branch for git-summary",
+		),
+		mcp.WithTitleAnnotation("Git Branches"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithNumber(
+			"depth",
+			mcp.Description(fmt.Sprintf(
+				"Number of recent commits of every branch fetched to compare it with the default branch, "+
+					"at most %d (defaults to %d); branches forked earlier show unknown ahead and behind counts",
+				maxDepth,
+				worksummary.DefaultBranchDepth,
+			)),
+		),
+	)
+	branchesTool := &GitBranchesTool{
+		Name:        "git-branches",
+		Description: "Lists the branches of a repository",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(branchesTool)
+	}
+	return branchesTool, nil
+}
+
+// GetName returns the name of the tool.
+func (g *GitBranchesTool) GetName() string {
+	return g.Name
+}
+
+// GetDescription returns the description of the tool.
+func (g *GitBranchesTool) GetDescription() string {
+	return g.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (g *GitBranchesTool) GetSchema() mcp.ToolInputSchema {
+	return g.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (g *GitBranchesTool) GetTool() mcp.Tool {
+	return g.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GitBranchesTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GitBranchesTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the branches of a repository before summarizing one",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-stock",
+			},
+		},
+		{
+			Description: "Compare long-lived branches over a deeper history",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/dcr-mcp",
+				"depth":    1000,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (g *GitBranchesTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := BranchesRequest{
+		RepoURL: request.GetString("repo_url", ""),
+		Depth:   request.GetInt("depth", worksummary.DefaultBranchDepth),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	branches, err := g.ListBranches(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	result := mcp.NewToolResultStructured(branches, RenderMarkdown(branches))
+	return provenance.Attach(result, provenance.New([]string{metrics.ServiceGitClone})), nil
+}
+
+// ListBranches lists the branches of the remote repository, fetching only
+// the latest commits of each.
+func (g *GitBranchesTool) ListBranches(ctx context.Context, params BranchesRequest) (Branches, error) {
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "fetching branches")
+	remote, err := g.analyzer.ListRemoteBranches(
+		progress.NewContext(ctx, reporter.Sub(0, 0.9)),
+		params.RepoURL,
+		params.Depth,
+	)
+	if err != nil {
+		return Branches{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to list branches")
+	}
+	reporter.Report(1, 1, "branches listed")
+	return Branches{RepoURL: params.RepoURL, RemoteBranches: remote}, nil
+}
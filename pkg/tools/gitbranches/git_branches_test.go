@@ -0,0 +1,72 @@
+package gitbranches
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool, err := NewGitBranchesTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "git-branches", arguments)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	june := func(day int) object.Signature {
+		return object.Signature{When: time.Date(2025, time.June, day, 12, 0, 0, 0, time.UTC)}
+	}
+	repo := gittest.NewRepo(t,
+		gittest.Commit{Author: june(1)},
+		gittest.Commit{Author: june(2)},
+		gittest.Commit{Author: june(3), Branch: "develop"},
+	)
+	repo.Checkout(t, "master")
+	result := callTool(t, map[string]any{"repo_url": repo.Dir})
+	require.False(t, result.IsError)
+	branches, ok := result.StructuredContent.(Branches)
+	require.True(t, ok)
+	require.Len(t, branches.Branches, 2)
+	develop := branches.Branches[1]
+	assert.Equal(t, "develop", develop.Name)
+	require.NotNil(t, develop.Ahead)
+	require.NotNil(t, develop.Behind)
+	assert.Equal(t, 1, *develop.Ahead)
+	assert.Equal(t, 0, *develop.Behind)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "| `develop` | 2025-06-03 | 1 | 0 |\n")
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	for _, arguments := range []map[string]any{
+		{},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "depth": 0},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "depth": 10000},
+	} {
+		result := callTool(t, arguments)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
+
+func TestHandler_UnreachableRepository(t *testing.T) {
+	t.Parallel()
+	result := callTool(t, map[string]any{"repo_url": filepath.Join(t.TempDir(), "missing")})
+	require.True(t, result.IsError)
+}
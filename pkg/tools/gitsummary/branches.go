@@ -0,0 +1,39 @@
+package gitsummary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// branchesText renders how the commits of a summary of several branches
+// are spread over them as a markdown section, counting for each branch its
+// commits and those on none of the other branches.
+func branchesText(branches []string, commits []worksummary.Commit) string {
+	var builder strings.Builder
+	builder.WriteString("## Branches\n\n")
+	for _, branch := range branches {
+		total, only := 0, 0
+		for _, commit := range commits {
+			if !slices.Contains(commit.Branches, branch) {
+				continue
+			}
+			total++
+			if len(commit.Branches) == 1 {
+				only++
+			}
+		}
+		noun := "commits"
+		if total == 1 {
+			noun = "commit"
+		}
+		fmt.Fprintf(&builder, "- `%s`: %d %s", branch, total, noun)
+		if only > 0 && only < total {
+			fmt.Fprintf(&builder, ", %d only on this branch", only)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
@@ -1,16 +1,47 @@
 package gitsummary
 
 import (
+	"cmp"
 	"context"
+	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
+	"strings"
 
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/toolversion"
 	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
 	"github.com/go-playground/validator/v10"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
+// Output formats of a summary.
+const (
+	// FormatMarkdown is a generated summary of the work.
+	FormatMarkdown = "markdown"
+	// FormatTimesheet is a CSV of the effort per author and day, which
+	// needs no model.
+	FormatTimesheet = "timesheet"
+	// FormatChangelog lists the commits by conventional-commit type, which
+	// needs no model.
+	FormatChangelog = "changelog"
+)
+
+// Bounds of the estimated tokens the patches of diff_paths may take.
+const (
+	defaultDiffTokenBudget = 4000
+	minDiffTokenBudget     = 500
+	maxDiffTokenBudget     = 32000
+)
+
 // Initialize validator.
 var validate = validator.New()
 
@@ -20,41 +51,148 @@ type GitSummaryTool struct {
 	Description string
 	Tool        mcp.Tool
 	analyzer    *worksummary.GitAnalyzer
-	Logger      *log.Logger
+	Logger      *slog.Logger
+	resources   *resources.Catalog
+	signingKeys *worksummary.TrustedKeys
+}
+
+// Option defines a functional option for configuring GitSummaryTool.
+type Option func(*GitSummaryTool)
+
+// WithResources publishes generated summaries as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(g *GitSummaryTool) {
+		g.resources = catalog
+	}
+}
+
+// WithSigningKeys verifies commit signatures against keys when a summary
+// reports them.
+func WithSigningKeys(keys *worksummary.TrustedKeys) Option {
+	return func(g *GitSummaryTool) {
+		g.signingKeys = keys
+	}
 }
 
 // GitSummaryRequest represents the parameters for the git summary request.
+// The commits are selected either by dates or by refs.
 type GitSummaryRequest struct {
-	RepoURL   string `validate:"required"`
+	// RepoURL is a repository, or a comma-separated list of repositories
+	// summarized together.
+	RepoURL string `validate:"required"`
+	// Branch is a branch, a comma-separated list of branches, or
+	// worksummary.BranchAuto for the default branch.
 	Branch    string `validate:"required"`
-	StartDate string `validate:"required"`
-	EndDate   string
-	Author    string `validate:"required"`
-	APIKey    string `validate:"required"`
+	StartDate string `validate:"required_without=FromRef,excluded_with=FromRef"`
+	EndDate   string `validate:"excluded_with=FromRef"`
+	// FromRef and ToRef select the commits reachable from ToRef, or the
+	// branch head, but not from FromRef, such as those of a release.
+	FromRef string
+	ToRef   string `validate:"excluded_with=StartDate"`
+	// Authors selects the authors whose commits are summarized.
+	Authors worksummary.AuthorFilter
+	APIKey  string `validate:"required_if=OutputFormat markdown"`
+	// Reproducible generates with temperature 0 and a fixed seed.
+	Reproducible bool
+	// CommitLinks cites the commits behind each bullet as footnotes when
+	// the repository is on a known host.
+	CommitLinks bool
+	// Storage keeps the clone in memory or in a temporary directory.
+	Storage string `validate:"required,oneof=auto memory temp-dir"`
+	// Signatures appends a report of the signed commits and their signers.
+	Signatures bool
+	// DiffStats sends the diff stats of each commit to the model.
+	DiffStats bool
+	// DiffPaths selects the files whose patches are sent to the model,
+	// within DiffTokenBudget estimated tokens; none are sent when empty.
+	DiffPaths       []string `validate:"dive,required"`
+	DiffTokenBudget int      `validate:"min=500,max=32000"`
+	// ConventionalCommits groups the messages sent to the model by their
+	// conventional-commit type.
+	ConventionalCommits bool
+	// OutputFormat selects a generated summary, a timesheet or a changelog.
+	OutputFormat string `validate:"required,oneof=markdown timesheet changelog"`
+	// GroupBy groups the bullets of a summary of several repositories by
+	// repository or by theme.
+	GroupBy string `validate:"required,oneof=repo theme"`
+	// Concurrency is the number of repositories cloned at once.
+	Concurrency int `validate:"min=1,max=8"`
+}
+
+// Summary is a generated work summary.
+type Summary struct {
+	Text string
+	// Generation holds the model settings, or nil when there were no
+	// commits to summarize.
+	Generation *worksummary.GenerationParams
+	// Nearby describes the activity around an empty commit range, or is
+	// nil when there were commits.
+	Nearby *worksummary.NearbyActivity
+	// Signatures reports the signed commits when requested, or is nil.
+	Signatures *worksummary.SignatureReport
+	// Timesheet is the effort behind the commits of a timesheet, whose
+	// Text is CSV; it is nil for a generated summary.
+	Timesheet *worksummary.Timesheet
+	// Changelog is the commits of a changelog by category, or nil for
+	// other formats.
+	Changelog *worksummary.Categorized
+	// Repos is what was found in each repository of a summary of several,
+	// or is nil for a single repository.
+	Repos []RepoSummary
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"git-summary",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			gitSummaryTool, err := NewGitSummaryTool(
+				deps.Logger,
+				WithResources(deps.Resources),
+				WithSigningKeys(deps.SigningKeys),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return gitSummaryTool, nil
+		},
+	)
 }
 
 // NewGitSummaryTool creates a new GitSummaryTool instance.
-func NewGitSummaryTool(logger *log.Logger) (*GitSummaryTool, error) {
+func NewGitSummaryTool(logger *slog.Logger, opts ...Option) (*GitSummaryTool, error) {
 	// Create the tool with proper schema
 	tool := mcp.NewTool(
 		"git-summary",
 		mcp.WithDescription(
-			"Summarizes git commit messages within a date range using OpenAI",
+			"Summarizes git commit messages within a date range, or between two tags or commits, using OpenAI",
 		),
+		mcp.WithTitleAnnotation("Git Summary"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString(
 			"repo_url",
-			mcp.Description("The URL of the git repository"),
+			mcp.Description(
+				"The URL of the git repository, or the path or file:// URL of a repository already cloned "+
+					"on the server's filesystem, which is read in place instead of cloned. A comma-separated "+
+					"list of repositories gives a single summary across all of them",
+			),
 			mcp.Required(),
 		),
 		mcp.WithString(
 			"branch",
-			mcp.Description("The branch to analyze"),
+			mcp.Description(
+				"The branch to analyze, a comma-separated list of branches whose commits are summarized "+
+					"together with a breakdown per branch, or auto for the default branch of the repository",
+			),
 			mcp.Required(),
 		),
 		mcp.WithString(
 			"start_date",
-			mcp.Description("The start date for commit analysis"),
-			mcp.Required(),
+			mcp.Description("The start date for commit analysis; required unless from_ref is given"),
 		),
 		mcp.WithString(
 			"end_date",
@@ -63,9 +201,118 @@ func NewGitSummaryTool(logger *log.Logger) (*GitSummaryTool, error) {
 			),
 		),
 		mcp.WithString(
-			"author",
-			mcp.Description("Filter commits by author name"),
-			mcp.Required(),
+			"from_ref",
+			mcp.Description(
+				"Summarize the commits after this tag, branch or commit instead of a date range, "+
+					"such as v1.2.0 for the work since that release",
+			),
+		),
+		mcp.WithString(
+			"to_ref",
+			mcp.Description(
+				"Summarize the commits up to this tag, branch or commit (optional with from_ref, "+
+					"defaults to the branch head); needs a single branch",
+			),
+		),
+		mcp.WithString(
+			"authors",
+			mcp.Description(
+				"Comma-separated authors whose commits are summarized together: part of a name, an email "+
+					"address, or an email domain such as @dictybase.org. Required unless exclude_authors is set",
+			),
+		),
+		mcp.WithString(
+			"exclude_authors",
+			mcp.Description(
+				"Comma-separated authors whose commits are left out, matched like authors; without authors, "+
+					"the commits of everyone else are summarized",
+			),
+		),
+		mcp.WithBoolean(
+			"reproducible",
+			mcp.Description(
+				"Generate with temperature 0 and a fixed seed, and record the generation parameters next to the summary",
+			),
+		),
+		mcp.WithBoolean(
+			"commit_links",
+			mcp.Description(
+				"Cite representative commits under each bullet as footnote links to the commit pages "+
+					"on GitHub, GitLab, Bitbucket or Codeberg, defaults to true",
+			),
+		),
+		mcp.WithString(
+			"storage",
+			mcp.Description(
+				"Where to keep the clone: memory, a temporary directory removed afterwards, or auto, "+
+					"which uses a temporary directory for repositories larger than 100 MB. Defaults to auto",
+			),
+			mcp.Enum(worksummary.CloneStorages...),
+		),
+		mcp.WithBoolean(
+			"signatures",
+			mcp.Description(
+				"Append a report of how many of the commits were GPG or SSH signed and by which keys, "+
+					"verified against the keys configured on the server, if any. Defaults to false",
+			),
+		),
+		mcp.WithBoolean(
+			"diff_stats",
+			mcp.Description(
+				"Send the files changed, lines inserted and deleted, and directories touched by each commit "+
+					"to the model, so the summary can size the work and name the most changed areas. "+
+					"Slower on long ranges; defaults to false",
+			),
+		),
+		mcp.WithString(
+			"diff_paths",
+			mcp.Description(
+				"Comma-separated files, directories or glob patterns, such as pkg/api or *.proto, whose patches "+
+					"are sent to the model with the commit messages, so the summary can describe the actual code "+
+					"changes. Newest commits first, until diff_token_budget is spent",
+			),
+		),
+		mcp.WithNumber(
+			"diff_token_budget",
+			mcp.Description(fmt.Sprintf(
+				"Estimated tokens the patches of diff_paths may take together, from %d to %d (defaults to %d); "+
+					"no single commit takes more than a quarter of it",
+				minDiffTokenBudget,
+				maxDiffTokenBudget,
+				defaultDiffTokenBudget,
+			)),
+		),
+		mcp.WithBoolean(
+			"conventional_commits",
+			mcp.Description(
+				"Group the commit messages sent to the model by their conventional-commit type, such as feat, "+
+					"fix, refactor or chore, with the breaking changes listed first. Defaults to false",
+			),
+		),
+		mcp.WithString(
+			"output_format",
+			mcp.Description(
+				"markdown for a generated summary (default), timesheet for a CSV of commits and inferred "+
+					"hours per author and day, or changelog for the commits listed by conventional-commit type; "+
+					"timesheet and changelog need no OpenAI key",
+			),
+			mcp.Enum(FormatMarkdown, FormatTimesheet, FormatChangelog),
+		),
+		mcp.WithString(
+			"group_by",
+			mcp.Description(
+				"How a summary of several repositories is organized: repo for a section per repository "+
+					"(default), or theme for bullets by theme across all of them",
+			),
+			mcp.Enum(GroupByRepo, GroupByTheme),
+		),
+		mcp.WithNumber(
+			"concurrency",
+			mcp.Description(fmt.Sprintf(
+				"Number of repositories cloned at once, at most %d (defaults to %d)",
+				maxConcurrency,
+				defaultConcurrency,
+			)),
 		),
 		mcp.WithString(
 			"api_key",
@@ -73,19 +320,25 @@ func NewGitSummaryTool(logger *log.Logger) (*GitSummaryTool, error) {
 				"OpenAI API key (optional, defaults to OPENAI_API_KEY environment variable)",
 			),
 		),
+		idempotency.WithKey(),
+		toolversion.WithVersion(2, toolversion.Rename{Old: "author", New: "authors", Removal: "v2.0.0"}),
 	)
 
 	analyzer := worksummary.NewGitAnalyzer(
 		worksummary.WithLogger(logger),
 	)
 
-	return &GitSummaryTool{
+	gitSummaryTool := &GitSummaryTool{
 		Name:        "git-summary",
-		Description: "Summarizes git commit messages within a date range using OpenAI",
+		Description: "Summarizes git commit messages within a date range or between refs using OpenAI",
 		Tool:        tool,
 		analyzer:    analyzer,
 		Logger:      logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(gitSummaryTool)
+	}
+	return gitSummaryTool, nil
 }
 
 // GetName returns the name of the tool.
@@ -108,87 +361,518 @@ func (g *GitSummaryTool) GetTool() mcp.Tool {
 	return g.Tool
 }
 
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GitSummaryTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GitSummaryTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize one developer's commits on the develop branch in June 2024",
+			Arguments: map[string]any{
+				"repo_url":   "https://github.com/dictybase/modware-annotation",
+				"branch":     "develop",
+				"start_date": "2024-06-01",
+				"end_date":   "2024-06-30",
+				"authors":    "Jane Doe",
+			},
+		},
+		{
+			Description: "Summarize the commits of an author with links to each commit and the model settings recorded",
+			Arguments: map[string]any{
+				"repo_url":     "https://github.com/dictybase/dcr-mcp",
+				"branch":       "main",
+				"start_date":   "2024-01-01",
+				"authors":      "jane@example.org",
+				"commit_links": true,
+				"reproducible": true,
+			},
+		},
+		{
+			Description: "Summarize an author's work on the default branch and a release branch together",
+			Arguments: map[string]any{
+				"repo_url":   "https://github.com/dictybase/dcr-mcp",
+				"branch":     "auto,release/2.0",
+				"start_date": "2024-04-01",
+				"end_date":   "2024-04-30",
+				"authors":    "Jane Doe",
+			},
+		},
+		{
+			Description: "Summarize an author's work across the frontend and backend repositories by theme",
+			Arguments: map[string]any{
+				"repo_url":   "https://github.com/dictybase/dicty-stock-center,https://github.com/dictybase/modware-stock",
+				"branch":     "auto",
+				"start_date": "2024-05-01",
+				"end_date":   "2024-05-31",
+				"authors":    "Jane Doe",
+				"group_by":   GroupByTheme,
+			},
+		},
+		{
+			Description: "Summarize a maintainer's commits for a compliance review, reporting who signed them",
+			Arguments: map[string]any{
+				"repo_url":   "https://github.com/dictybase/dcr-mcp",
+				"branch":     "main",
+				"start_date": "2024-01-01",
+				"end_date":   "2024-03-31",
+				"authors":    "Jane Doe",
+				"signatures": true,
+			},
+		},
+		{
+			Description: "Summarize the team's work that went into a release, between its tag and the previous one",
+			Arguments: map[string]any{
+				"repo_url":        "https://github.com/dictybase/dcr-mcp",
+				"branch":          "main",
+				"from_ref":        "v1.2.0",
+				"to_ref":          "v1.3.0",
+				"exclude_authors": "release-bot",
+			},
+		},
+		{
+			Description: "List the features, fixes and breaking changes of a release without a model",
+			Arguments: map[string]any{
+				"repo_url":        "https://github.com/dictybase/dcr-mcp",
+				"branch":          "main",
+				"from_ref":        "v1.2.0",
+				"exclude_authors": "release-bot",
+				"output_format":   FormatChangelog,
+			},
+		},
+		{
+			Description: "Summarize the team's work in a month, leaving out the commits of the release bot",
+			Arguments: map[string]any{
+				"repo_url":        "https://github.com/dictybase/dcr-mcp",
+				"branch":          "main",
+				"start_date":      "2024-07-01",
+				"end_date":        "2024-07-31",
+				"authors":         "@dictybase.org",
+				"exclude_authors": "release-bot",
+			},
+		},
+	}
+}
+
 // Handler returns a function that handles tool execution requests.
 func (g *GitSummaryTool) Handler(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	args := request.GetArguments()
-
 	// Create request with required parameters
 	params := GitSummaryRequest{
-		RepoURL:   args["repo_url"].(string),
-		Branch:    args["branch"].(string),
-		StartDate: args["start_date"].(string),
-		Author:    args["author"].(string),
-		APIKey:    os.Getenv("OPENAI_API_KEY"),
+		RepoURL:   request.GetString("repo_url", ""),
+		Branch:    request.GetString("branch", ""),
+		StartDate: request.GetString("start_date", ""),
+		EndDate:   request.GetString("end_date", ""),
+		FromRef:   request.GetString("from_ref", ""),
+		ToRef:     request.GetString("to_ref", ""),
+		Authors: worksummary.AuthorFilter{
+			Include: splitList(request.GetString("authors", "")),
+			Exclude: splitList(request.GetString("exclude_authors", "")),
+		},
+		APIKey:              os.Getenv("OPENAI_API_KEY"),
+		Reproducible:        request.GetBool("reproducible", false),
+		CommitLinks:         request.GetBool("commit_links", true),
+		Storage:             request.GetString("storage", string(worksummary.StorageAuto)),
+		Signatures:          request.GetBool("signatures", false),
+		DiffStats:           request.GetBool("diff_stats", false),
+		DiffPaths:           splitList(request.GetString("diff_paths", "")),
+		DiffTokenBudget:     request.GetInt("diff_token_budget", defaultDiffTokenBudget),
+		ConventionalCommits: request.GetBool("conventional_commits", false),
+		OutputFormat:        request.GetString("output_format", FormatMarkdown),
+		GroupBy:             request.GetString("group_by", GroupByRepo),
+		Concurrency:         request.GetInt("concurrency", defaultConcurrency),
 	}
-
-	// Only add end_date if it was provided in the arguments
-	if endDate, ok := args["end_date"].(string); ok && endDate != "" {
-		params.EndDate = endDate
+	if params.APIKey == "" && params.OutputFormat == FormatMarkdown {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeConfiguration,
+			"MISSING_OPENAI_API_KEY",
+			"OPENAI_API_KEY is not set on the server",
+		)), nil
 	}
 	if err := validate.Struct(params); err != nil {
-		return nil, fmt.Errorf("validation error: %v", err)
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
 	}
 
-	client, err := worksummary.NewOpenAIClient(params.APIKey)
-	if err != nil {
-		return nil, fmt.Errorf("error initializing OpenAI client: %v", err)
+	var client *worksummary.OpenAIClient
+	if params.OutputFormat == FormatMarkdown {
+		var err error
+		client, err = worksummary.NewOpenAIClient(
+			params.APIKey,
+			worksummary.WithReproducible(params.Reproducible),
+		)
+		if err != nil {
+			return toolerror.Result(toolerror.Wrap(
+				toolerror.TypeConfiguration,
+				"OPENAI_CLIENT",
+				err,
+				"error initializing OpenAI client",
+			)), nil
+		}
 	}
 	summary, err := g.GenerateSummary(ctx, client, params)
 	if err != nil {
-		return nil, fmt.Errorf("error generating summary: %v", err)
+		return toolerror.Result(fmt.Errorf("error generating summary: %w", err)), nil
+	}
+
+	record := provenance.New([]string{metrics.ServiceGitClone})
+	if summary.Generation != nil {
+		record = provenance.New(
+			[]string{metrics.ServiceGitClone, metrics.ServiceOpenAI},
+			provenance.WithModel(summary.Generation.Model),
+		)
 	}
+	result := mcp.NewToolResultText(summary.Text)
+	switch {
+	case summary.Nearby != nil:
+		result.StructuredContent = summary.Nearby
+	case summary.Signatures != nil:
+		result.StructuredContent = summary.Signatures
+	case summary.Timesheet != nil:
+		result.StructuredContent = summary.Timesheet
+	case summary.Changelog != nil:
+		result.StructuredContent = summary.Changelog
+	case summary.Repos != nil:
+		result.StructuredContent = summary.Repos
+	}
+	result = provenance.Attach(result, record)
+	if g.resources != nil {
+		links, err := g.publish(params, summary)
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing summary: %w", err)), nil
+		}
+		result.Content = append(result.Content, links...)
+	}
+	return result, nil
+}
 
-	return mcp.NewToolResultText(summary), nil
+// publish adds the summary, and the parameters it was generated with, to
+// the resource catalog.
+func (g *GitSummaryTool) publish(params GitSummaryRequest, summary Summary) ([]mcp.Content, error) {
+	name, mimeType, description := summaryName(params), "text/markdown", "Work summary"
+	switch {
+	case summary.Timesheet != nil:
+		name, mimeType, description = strings.TrimSuffix(name, ".md")+".timesheet.csv", "text/csv", "Timesheet"
+	case summary.Changelog != nil:
+		name, description = strings.TrimSuffix(name, ".md")+".changelog.md", "Changelog"
+	}
+	resource, err := g.resources.Publish(resources.PublishParams{
+		Kind:        resources.KindGitSummary,
+		Name:        name,
+		MIMEType:    mimeType,
+		Description: fmt.Sprintf("%s of %s on %s", description, params.Authors, params.RepoURL),
+		Data:        []byte(summary.Text),
+	})
+	if err != nil {
+		return nil, err
+	}
+	links := []mcp.Content{resources.Link(resource)}
+	if summary.Generation == nil {
+		return links, nil
+	}
+	data, err := json.MarshalIndent(summary.Generation, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("error encoding generation parameters: %w", err)
+	}
+	resource, err = g.resources.Publish(resources.PublishParams{
+		Kind:        resources.KindGitSummary,
+		Name:        strings.TrimSuffix(name, ".md") + ".generation.json",
+		MIMEType:    "application/json",
+		Description: "Generation parameters of " + name,
+		Data:        data,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(links, resources.Link(resource)), nil
 }
 
-// GenerateSummary generates a summary of git commit messages.
+// summaryName names the summary resource after the request, so rerunning
+// the same request replaces the earlier summary.
+func summaryName(req GitSummaryRequest) string {
+	branch := strings.ReplaceAll(strings.ReplaceAll(req.Branch, " ", ""), ",", "+")
+	urls, _ := repoURLs(req.RepoURL)
+	names := make([]string, 0, len(urls))
+	for _, repoURL := range urls {
+		names = append(names, worksummary.RepoName(repoURL))
+	}
+	authors := strings.Join(req.Authors.Include, "+")
+	if authors == "" {
+		authors = "all"
+	}
+	span := req.StartDate
+	if req.FromRef != "" {
+		span = strings.ReplaceAll(req.FromRef+".."+cmp.Or(req.ToRef, "HEAD"), "/", "-")
+	}
+	return fmt.Sprintf("%s-%s-%s-%s.md", strings.Join(names, "+"), branch, authors, span)
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// summaryStages is the progress total of a summary: cloning takes the
+// first two steps, listing commits the next and generation the rest.
+const summaryStages = 5
+
+// GenerateSummary generates a summary of git commit messages, or a
+// timesheet or changelog of the commits, for which client may be nil, reporting each
+// stage to the progress reporter of ctx. The commits of several branches
+// are summarized together, each commit once, followed by how they are
+// spread over the branches; nearby activity is looked for on the first
+// branch only. Several repositories are read concurrently and summarized
+// as one, grouped by req.GroupBy.
 func (g *GitSummaryTool) GenerateSummary(
 	ctx context.Context,
 	client *worksummary.OpenAIClient,
 	req GitSummaryRequest,
-) (string, error) {
+) (Summary, error) {
+	urls, err := repoURLs(req.RepoURL)
+	if err != nil {
+		return Summary{}, err
+	}
+	if len(urls) > 1 {
+		return g.generateReposSummary(ctx, client, req, urls)
+	}
+	req.RepoURL = urls[0]
+	reporter := progress.FromContext(ctx)
+	branches, err := g.analyzer.ResolveBranches(ctx, req.RepoURL, req.Branch)
+	if err != nil {
+		return Summary{}, fmt.Errorf("failed to resolve branches: %w", err)
+	}
 	// Clone the repository
-	repo, err := g.analyzer.CloneAndCheckout(ctx, req.RepoURL, req.Branch)
+	reporter.Report(1, summaryStages, "cloning repository")
+	repo, err := g.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(1, 3)),
+		req.RepoURL,
+		branches[0],
+		worksummary.WithStorage(worksummary.CloneStorage(req.Storage)),
+		worksummary.WithBranches(branches[1:]...),
+	)
 	if err != nil {
-		return "", fmt.Errorf("failed to clone repository: %w", err)
+		return Summary{}, fmt.Errorf("failed to clone repository: %w", err)
 	}
+	defer repo.Close()
 
-	// Parse dates
-	startDate, endDate, err := g.analyzer.ParseAnalysisDates(
-		req.StartDate,
-		req.EndDate,
-	)
+	dateRange, err := g.resolveDates(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to parse dates: %w", err)
+		return Summary{}, err
 	}
 
 	// Create commit range parameters
-	params := worksummary.CommitRangeParams{
-		Repo:   repo,
-		Start:  startDate.Time,
-		End:    endDate.Time,
-		Author: req.Author,
+	params, header, err := commitRange(repo.Repository, req, dateRange, branches)
+	if err != nil {
+		return Summary{}, err
 	}
 
 	// Get commit messages
-	commitMsgs, err := g.analyzer.ListCommitsInRange(ctx, params)
+	reporter.Report(3, summaryStages, "listing commits")
+	commits, err := g.analyzer.ListAuthorCommits(ctx, params)
+	if len(branches) > 1 {
+		commits, err = g.analyzer.ListBranchCommits(ctx, repo, branches, params)
+	}
 	if err != nil {
-		return "", fmt.Errorf("failed to list commits: %w", err)
+		return Summary{}, fmt.Errorf("failed to list commits: %w", err)
 	}
+	if req.OutputFormat == FormatTimesheet {
+		timesheet := worksummary.NewTimesheet(worksummary.RepoName(req.RepoURL), commits)
+		text, err := timesheet.CSV()
+		if err != nil {
+			return Summary{}, err
+		}
+		reporter.Report(summaryStages, summaryStages, "timesheet generated")
+		return Summary{Text: text, Timesheet: &timesheet}, nil
+	}
+	commitMsgs := commitMessages(req, commits)
 
-	// No commits found
+	// No commits found; nearby activity is looked for around dates only
+	if commitMsgs == "" && req.FromRef != "" {
+		return Summary{Text: noCommitsText(req.Authors.String(), header, nil)}, nil
+	}
 	if commitMsgs == "" {
-		return "No commits found in the specified date range.", nil
+		nearby, err := g.analyzer.NearbyActivity(ctx, params)
+		if err != nil {
+			if ctx.Err() != nil {
+				return Summary{}, fmt.Errorf("failed to look for nearby activity: %w", err)
+			}
+			g.Logger.Warn("failed to look for nearby activity", "error", err)
+			return Summary{Text: noCommitsText(req.Authors.String(), header, nil)}, nil
+		}
+		return Summary{
+			Text:   noCommitsText(req.Authors.String(), header, &nearby),
+			Nearby: &nearby,
+		}, nil
 	}
 
-	// Generate summary using OpenAI
+	// A local repository links to the commit pages of its origin.
+	origin := cmp.Or(repo.Origin, req.RepoURL)
+	var result Summary
+	if req.OutputFormat == FormatChangelog {
+		changelog := worksummary.Categorize(worksummary.CommitSource{RepoURL: linkedURL(req, origin), Commits: commits})
+		result = Summary{Text: withHeader(changelog.Markdown(), header), Changelog: &changelog}
+	} else {
+		// Generate summary using OpenAI
+		reporter.Report(3.5, summaryStages, "generating summary")
+		generateCtx := progress.NewContext(ctx, reporter.Sub(3.5, summaryStages))
+		generation := client.GenerationParams(commitMsgs)
+		summary, revised, err := g.summarize(generateCtx, client, commitMsgs)
+		if err != nil {
+			return Summary{}, err
+		}
+		generation.Revised = revised
+		if req.CommitLinks {
+			summary = worksummary.LinkCommits(summary, origin, commits)
+		}
+		result = Summary{Text: withHeader(summary, header), Generation: &generation}
+	}
+	if len(branches) > 1 {
+		result.Text = strings.TrimRight(result.Text, "\n") + "\n\n" + branchesText(branches, commits)
+	}
+	if req.Signatures {
+		report := worksummary.ReportSignatures(repo.Repository, commits, g.signingKeys)
+		result.Text = strings.TrimRight(result.Text, "\n") + "\n\n" + signaturesText(report)
+		result.Signatures = &report
+	}
+	reporter.Report(summaryStages, summaryStages, "summary generated")
+	return result, nil
+}
+
+// commitMessages concatenates the messages of commits as sent to the
+// model, grouped by conventional-commit type when req asks for it and any
+// of them follows the convention.
+func commitMessages(req GitSummaryRequest, commits []worksummary.Commit) string {
+	if !req.ConventionalCommits {
+		return worksummary.Messages(commits)
+	}
+	categorized := worksummary.Categorize(worksummary.CommitSource{Commits: commits})
+	if categorized.Conventional == 0 {
+		return worksummary.Messages(commits)
+	}
+	return categorized.Messages()
+}
+
+// linkedURL returns the repository commit links of req point to, or
+// nothing when req asks for none.
+func linkedURL(req GitSummaryRequest, repoURL string) string {
+	if !req.CommitLinks {
+		return ""
+	}
+	return repoURL
+}
+
+// summarize generates a summary of commitMsgs in the format the prompt asks
+// for, telling whether the model had to revise it.
+func (g *GitSummaryTool) summarize(
+	ctx context.Context,
+	client *worksummary.OpenAIClient,
+	commitMsgs string,
+) (string, bool, error) {
 	summary, err := client.SummarizeCommitMessages(ctx, commitMsgs)
 	if err != nil {
-		return "", fmt.Errorf("failed to summarize commit messages: %w", err)
+		return "", false, fmt.Errorf("failed to summarize commit messages: %w", err)
+	}
+	return g.enforceFormat(ctx, client, commitMsgs, summary)
+}
+
+// enforceFormat re-prompts the model once when summary breaks the format
+// the prompt asks for, and returns the revised summary unless it came out
+// worse. A failed revision keeps the first summary, since it is still
+// usable; only a cancelled request is an error.
+func (g *GitSummaryTool) enforceFormat(
+	ctx context.Context,
+	client *worksummary.OpenAIClient,
+	commitMsgs, summary string,
+) (string, bool, error) {
+	problems := worksummary.CheckFormat(summary)
+	if len(problems) == 0 {
+		return summary, false, nil
+	}
+	g.Logger.Warn("summary breaks the required format, asking for a revision", "problems", problems)
+	revised, err := client.ReviseSummary(ctx, commitMsgs, summary, problems)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", false, fmt.Errorf("failed to revise summary: %w", err)
+		}
+		g.Logger.Warn("failed to revise summary, keeping the first one", "error", err)
+		return summary, false, nil
 	}
+	remaining := worksummary.CheckFormat(revised)
+	if len(remaining) > len(problems) {
+		g.Logger.Warn("revised summary is worse, keeping the first one", "problems", remaining)
+		return summary, false, nil
+	}
+	if len(remaining) > 0 {
+		g.Logger.Warn("revised summary still breaks the required format", "problems", remaining)
+	}
+	return revised, true, nil
+}
+
+// resolveDates resolves the date range of req, which is zero when req
+// selects its commits by refs.
+func (g *GitSummaryTool) resolveDates(req GitSummaryRequest) (worksummary.DateRange, error) {
+	if req.StartDate == "" {
+		return worksummary.DateRange{}, nil
+	}
+	dateRange, err := g.analyzer.ResolveDateRange(req.StartDate, req.EndDate)
+	if err != nil {
+		return worksummary.DateRange{}, fmt.Errorf("failed to parse dates: %w", err)
+	}
+	return dateRange, nil
+}
 
-	return summary, nil
+// commitRange returns the parameters listing the commits req selects in
+// repo, and the header describing them: the date range, or the commits
+// between req.FromRef and req.ToRef. A to_ref ends the history of one
+// branch, so it cannot be combined with several.
+func commitRange(
+	repo *git.Repository,
+	req GitSummaryRequest,
+	dateRange worksummary.DateRange,
+	branches []string,
+) (worksummary.CommitRangeParams, string, error) {
+	params := worksummary.CommitRangeParams{
+		Repo:    repo,
+		Start:   dateRange.Start,
+		End:     dateRange.End,
+		Authors: req.Authors,
+		Stats:   req.DiffStats && req.OutputFormat == FormatMarkdown,
+	}
+	if len(req.DiffPaths) > 0 && req.OutputFormat == FormatMarkdown {
+		params.Patches = &worksummary.PatchOptions{Paths: req.DiffPaths, TokenBudget: req.DiffTokenBudget}
+	}
+	if req.FromRef == "" {
+		return params, dateRange.Header(), nil
+	}
+	if req.ToRef != "" && len(branches) > 1 {
+		return worksummary.CommitRangeParams{}, "", toolerror.New(
+			toolerror.TypeInvalidInput,
+			"TO_REF_WITH_BRANCHES",
+			"to_ref ends the range on a single branch; leave it out to summarize several branches since from_ref",
+		)
+	}
+	refRange, err := worksummary.ResolveRefRange(repo, req.FromRef, req.ToRef)
+	if err != nil {
+		return worksummary.CommitRangeParams{}, "", err
+	}
+	return refRange.Params(params), refRange.Header(), nil
+}
+
+// withHeader inserts header below the summary's title, or above the
+// summary when it has none.
+func withHeader(summary, header string) string {
+	if title, body, found := strings.Cut(summary, "\n"); found && strings.HasPrefix(title, "# ") {
+		return title + "\n\n" + header + "\n" + strings.TrimLeft(body, "\n")
+	}
+	return header + "\n" + summary
 }
@@ -2,15 +2,23 @@ package gitsummary
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mark3labs/mcp-go/mcp"
 )
 
 // TestNewGitSummaryTool tests the creation of a new GitSummaryTool.
 func TestNewGitSummaryTool(t *testing.T) {
 	t.Parallel()
-	logger := log.New(os.Stderr, "", 0)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewGitSummaryTool(logger)
 	if err != nil {
 		t.Fatalf("failed to create GitSummaryTool: %v", err)
@@ -34,6 +42,11 @@ func TestNewGitSummaryTool(t *testing.T) {
 			tool.GetTool().Name,
 		)
 	}
+
+	annotations := tool.GetAnnotations()
+	if !*annotations.ReadOnlyHint || !*annotations.OpenWorldHint {
+		t.Fatal("git-summary should be annotated as read-only and network-accessing")
+	}
 }
 
 // MockOpenAIClient is a mock implementation of the worksummary.SummaryClient interface.
@@ -63,3 +76,499 @@ func TestGenerateSummary(t *testing.T) {
 	// 4. Call GenerateSummary with test parameters
 	// 5. Verify the returned summary matches expected output
 }
+
+// TestWithHeader tests placing the date range header in a summary.
+func TestWithHeader(t *testing.T) {
+	t.Parallel()
+	header := "**Date range:** 2025-06-01 00:00 to 2025-06-30 23:59 (UTC, UTC+00:00)\n"
+	got := withHeader("# Work Summary\n\n**Features**\n- Added", header)
+	want := "# Work Summary\n\n" + header + "\n**Features**\n- Added"
+	if got != want {
+		t.Fatalf("expected header below the title, got %q", got)
+	}
+	got = withHeader("**Features**\n- Added", header)
+	if !strings.HasPrefix(got, header+"\n**Features**") {
+		t.Fatalf("expected header above an untitled summary, got %q", got)
+	}
+}
+
+// TestNoCommitsText tests the report of an empty commit range.
+func TestNoCommitsText(t *testing.T) {
+	t.Parallel()
+	before := time.Date(2025, 5, 20, 12, 0, 0, 0, time.UTC)
+	text := noCommitsText("jane", "**Date range:** June\n", &worksummary.NearbyActivity{
+		LatestBefore:    &before,
+		RangeCommits:    3,
+		TopAuthors:      []worksummary.AuthorActivity{{Name: "Joe", Commits: 2}, {Name: "Ann", Commits: 1}},
+		TopAuthorsScope: worksummary.ScopeRange,
+	})
+	for _, want := range []string{
+		"No commits found in the specified range.",
+		"## Nearby Activity",
+		`- Latest commit by "jane" before the range: 2025-05-20`,
+		"- Commits by all authors in the range: 3",
+		"- Most active authors in the range: Joe (2), Ann (1)",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "after the range") {
+		t.Errorf("expected no commit after the range in:\n%s", text)
+	}
+
+	plain := noCommitsText("jane", "**Date range:** June\n", nil)
+	if strings.Contains(plain, "Nearby Activity") {
+		t.Errorf("expected no nearby activity without a lookup:\n%s", plain)
+	}
+}
+
+// TestSignaturesText tests the report of signed commits.
+func TestSignaturesText(t *testing.T) {
+	t.Parallel()
+	text := signaturesText(worksummary.SignatureReport{
+		Commits:  4,
+		Signed:   3,
+		ByType:   map[string]int{worksummary.SignatureGPG: 2, worksummary.SignatureSSH: 1},
+		Verified: true,
+		Signers: []worksummary.Signer{
+			{
+				Type:         worksummary.SignatureGPG,
+				KeyID:        "0123456789ABCDEF",
+				Identity:     "Jane Doe <jane@example.org>",
+				Authors:      []string{"Jane Doe"},
+				Commits:      2,
+				Verification: worksummary.VerificationGood,
+			},
+			{
+				Type:         worksummary.SignatureSSH,
+				KeyID:        "SHA256:abc",
+				Authors:      []string{"Joe"},
+				Commits:      1,
+				Verification: worksummary.VerificationUnknownKey,
+			},
+		},
+	})
+	for _, want := range []string{
+		"## Commit Signatures",
+		"3 of 4 commits are signed (2 GPG, 1 SSH).",
+		"| `0123456789ABCDEF` | GPG | Jane Doe <jane@example.org> | Jane Doe | 2 | verified |",
+		"| `SHA256:abc` | SSH | - | Joe | 1 | unknown key |",
+	} {
+		if !strings.Contains(text, want) {
+			t.Errorf("expected %q in:\n%s", want, text)
+		}
+	}
+	if strings.Contains(text, "not verified") {
+		t.Errorf("expected verified signatures in:\n%s", text)
+	}
+
+	unsigned := signaturesText(worksummary.SignatureReport{Commits: 2, ByType: map[string]int{}})
+	for _, want := range []string{"0 of 2 commits are signed.", "so signatures were not verified"} {
+		if !strings.Contains(unsigned, want) {
+			t.Errorf("expected %q in:\n%s", want, unsigned)
+		}
+	}
+}
+
+// TestHandler_Timesheet tests that a timesheet is generated without a
+// model.
+func TestHandler_Timesheet(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	branch := commitRepo(t, dir, "Jane Doe",
+		time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 2, 10, 0, 0, 0, time.UTC),
+	)
+
+	tool, err := NewGitSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create GitSummaryTool: %v", err)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"repo_url":      dir,
+		"branch":        branch,
+		"start_date":    "2025-06-01",
+		"end_date":      "2025-06-30",
+		"authors":       "jane",
+		"output_format": FormatTimesheet,
+	}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("failed to generate timesheet: %v %+v", err, result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	want := "date,author,repo,commits,hours\n2025-06-02,Jane Doe," + filepath.Base(dir) + ",2,1.50\n"
+	if text.Text != want {
+		t.Errorf("expected timesheet %q, got %q", want, text.Text)
+	}
+	if _, ok := result.StructuredContent.(*worksummary.Timesheet); !ok {
+		t.Errorf("expected the timesheet as structured content, got %T", result.StructuredContent)
+	}
+}
+
+// TestHandler_Changelog tests that a changelog lists the commits by
+// conventional-commit type without a model.
+func TestHandler_Changelog(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	branch := commitRepoMessages(t, dir, time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC),
+		"fix(order): keep the cart on reload",
+		"feat(api)!: page the strain list",
+		"Update README",
+	)
+
+	tool, err := NewGitSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create GitSummaryTool: %v", err)
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"repo_url":      dir,
+		"branch":        branch,
+		"start_date":    "2025-06-01",
+		"end_date":      "2025-06-30",
+		"authors":       "jane",
+		"output_format": FormatChangelog,
+	}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("failed to generate changelog: %v %+v", err, result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	for _, want := range []string{
+		"**Breaking Changes**\n- **api:** page the strain list (`",
+		"**Features**\n- **api:** page the strain list (`",
+		"**Fixes**\n- **order:** keep the cart on reload (`",
+		"**Other**\n- Update README (`",
+		"2 of 3 commits follow the Conventional Commits format.",
+	} {
+		if !strings.Contains(text.Text, want) {
+			t.Errorf("expected changelog to contain %q, got %q", want, text.Text)
+		}
+	}
+	changelog, ok := result.StructuredContent.(*worksummary.Categorized)
+	if !ok {
+		t.Fatalf("expected the changelog as structured content, got %T", result.StructuredContent)
+	}
+	if changelog.Total != 3 || changelog.Conventional != 2 || len(changelog.Breaking) != 1 {
+		t.Errorf("expected 3 commits, 2 conventional and 1 breaking, got %+v", changelog)
+	}
+}
+
+// TestCommitMessages tests that messages are grouped by category only when
+// asked to and some commit follows the convention.
+func TestCommitMessages(t *testing.T) {
+	t.Parallel()
+	feature := worksummary.Commit{Subject: "feat: add search", Message: "feat: add search\n"}
+	parsed, _ := worksummary.ParseConventional(feature.Message)
+	feature.Conventional = &parsed
+	plain := worksummary.Commit{Subject: "Update README", Message: "Update README\n"}
+
+	req := GitSummaryRequest{}
+	if got := commitMessages(req, []worksummary.Commit{feature, plain}); got != "feat: add search\nUpdate README\n" {
+		t.Errorf("expected the messages as they are, got %q", got)
+	}
+	req.ConventionalCommits = true
+	want := "Category: Features (1 commit)\n\nfeat: add search\n\nCategory: Other (1 commit)\n\nUpdate README\n"
+	if got := commitMessages(req, []worksummary.Commit{feature, plain}); got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	if got := commitMessages(req, []worksummary.Commit{plain}); got != "Update README\n" {
+		t.Errorf("expected messages without categories when none is conventional, got %q", got)
+	}
+}
+
+// TestBranchesText tests the breakdown of commits per branch.
+func TestBranchesText(t *testing.T) {
+	t.Parallel()
+	text := branchesText([]string{"main", "develop", "release"}, []worksummary.Commit{
+		{Hash: "a", Branches: []string{"main", "develop"}},
+		{Hash: "b", Branches: []string{"develop"}},
+		{Hash: "c", Branches: []string{"develop"}},
+	})
+	want := "## Branches\n\n" +
+		"- `main`: 1 commit\n" +
+		"- `develop`: 3 commits, 2 only on this branch\n" +
+		"- `release`: 0 commits\n"
+	if text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+}
+
+// commitRepo creates a repository in dir with a commit by author at each
+// of times, and returns its branch.
+func commitRepo(t *testing.T, dir, author string, times ...time.Time) string {
+	t.Helper()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to open worktree: %v", err)
+	}
+	for _, when := range times {
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(when.String()), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("file.txt"); err != nil {
+			t.Fatalf("failed to stage file: %v", err)
+		}
+		signature := &object.Signature{Name: author, Email: "jane@example.org", When: when}
+		if _, err := worktree.Commit("change", &git.CommitOptions{Author: signature, Committer: signature}); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	return head.Name().Short()
+}
+
+// commitRepoMessages creates a repository in dir with a commit by Jane Doe
+// for each of messages, a minute apart from when, and returns its branch.
+func commitRepoMessages(t *testing.T, dir string, when time.Time, messages ...string) string {
+	t.Helper()
+	repo, err := git.PlainInit(dir, false)
+	if err != nil {
+		t.Fatalf("failed to create repository: %v", err)
+	}
+	worktree, err := repo.Worktree()
+	if err != nil {
+		t.Fatalf("failed to open worktree: %v", err)
+	}
+	for i, message := range messages {
+		if err := os.WriteFile(filepath.Join(dir, "file.txt"), []byte(message), 0o600); err != nil {
+			t.Fatalf("failed to write file: %v", err)
+		}
+		if _, err := worktree.Add("file.txt"); err != nil {
+			t.Fatalf("failed to stage file: %v", err)
+		}
+		signature := &object.Signature{Name: "Jane Doe", Email: "jane@example.org", When: when.Add(time.Duration(i) * time.Minute)}
+		if _, err := worktree.Commit(message, &git.CommitOptions{Author: signature, Committer: signature}); err != nil {
+			t.Fatalf("failed to commit: %v", err)
+		}
+	}
+	head, err := repo.Head()
+	if err != nil {
+		t.Fatalf("failed to read HEAD: %v", err)
+	}
+	return head.Name().Short()
+}
+
+// TestHandler_TimesheetRepos tests a timesheet across repositories, one of
+// which cannot be read.
+func TestHandler_TimesheetRepos(t *testing.T) {
+	t.Parallel()
+	first, second := filepath.Join(t.TempDir(), "stock"), filepath.Join(t.TempDir(), "annotations")
+	branch := commitRepo(t, first, "Jane Doe", time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC))
+	commitRepo(t, second, "Jane Doe", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC))
+	missing := filepath.Join(t.TempDir(), "missing")
+
+	tool, err := NewGitSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create GitSummaryTool: %v", err)
+	}
+	args := map[string]any{
+		"repo_url":      first + ", " + second + "," + missing + "," + first,
+		"branch":        branch,
+		"start_date":    "2025-06-01",
+		"end_date":      "2025-06-30",
+		"authors":       "jane",
+		"output_format": FormatTimesheet,
+		"concurrency":   2,
+	}
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = args
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil || result.IsError {
+		t.Fatalf("failed to generate timesheet: %v %+v", err, result)
+	}
+	text, ok := result.Content[0].(mcp.TextContent)
+	if !ok {
+		t.Fatalf("expected text content, got %T", result.Content[0])
+	}
+	want := "date,author,repo,commits,hours\n" +
+		"2025-06-02,Jane Doe,annotations,1,0.50\n" +
+		"2025-06-03,Jane Doe,stock,1,0.50\n"
+	if text.Text != want {
+		t.Errorf("expected timesheet %q, got %q", want, text.Text)
+	}
+
+	args["repo_url"] = missing + "," + filepath.Join(t.TempDir(), "gone")
+	result, err = tool.Handler(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Fatalf("expected an error when no repository can be read, got %v %+v", err, result)
+	}
+}
+
+// TestHandler_Authors tests selecting the commits of several authors, and
+// of everyone but the excluded ones.
+func TestHandler_Authors(t *testing.T) {
+	t.Parallel()
+	first, second := filepath.Join(t.TempDir(), "stock"), filepath.Join(t.TempDir(), "annotations")
+	branch := commitRepo(t, first, "Jane Doe", time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC))
+	commitRepo(t, second, "Joe Bloggs", time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC))
+
+	tool, err := NewGitSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create GitSummaryTool: %v", err)
+	}
+	for _, tc := range []struct {
+		authors, exclude, want string
+	}{
+		{
+			authors: "jane, joe",
+			want:    "2025-06-02,Joe Bloggs,annotations,1,0.50\n2025-06-03,Jane Doe,stock,1,0.50\n",
+		},
+		{exclude: "Joe", want: "2025-06-03,Jane Doe,stock,1,0.50\n"},
+		{authors: "@example.org", exclude: "jane", want: "2025-06-02,Joe Bloggs,annotations,1,0.50\n"},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{
+			"repo_url":        first + "," + second,
+			"branch":          branch,
+			"start_date":      "2025-06-01",
+			"end_date":        "2025-06-30",
+			"authors":         tc.authors,
+			"exclude_authors": tc.exclude,
+			"output_format":   FormatTimesheet,
+		}
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("failed to generate timesheet: %v %+v", err, result)
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("expected text content, got %T", result.Content[0])
+		}
+		if want := "date,author,repo,commits,hours\n" + tc.want; text.Text != want {
+			t.Errorf("authors %q except %q: expected timesheet %q, got %q", tc.authors, tc.exclude, want, text.Text)
+		}
+	}
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"repo_url":      first,
+		"branch":        branch,
+		"start_date":    "2025-06-01",
+		"output_format": FormatTimesheet,
+	}
+	result, err := tool.Handler(context.Background(), request)
+	if err != nil || !result.IsError {
+		t.Errorf("expected an error without authors, got %v %+v", err, result)
+	}
+}
+
+// TestHandler_Refs tests selecting the commits between refs instead of
+// dates.
+func TestHandler_Refs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	branch := commitRepo(t, dir, "Jane Doe",
+		time.Date(2025, 6, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 3, 9, 0, 0, 0, time.UTC),
+		time.Date(2025, 6, 4, 9, 0, 0, 0, time.UTC),
+	)
+	repo, err := git.PlainOpen(dir)
+	if err != nil {
+		t.Fatalf("failed to open repository: %v", err)
+	}
+	first, err := repo.ResolveRevision("HEAD~2")
+	if err != nil {
+		t.Fatalf("failed to resolve the first commit: %v", err)
+	}
+	if _, err := repo.CreateTag("v1.0.0", *first, nil); err != nil {
+		t.Fatalf("failed to tag: %v", err)
+	}
+
+	tool, err := NewGitSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	if err != nil {
+		t.Fatalf("failed to create GitSummaryTool: %v", err)
+	}
+	for _, tc := range []struct {
+		toRef, want string
+	}{
+		{want: "2025-06-03,Jane Doe," + filepath.Base(dir) + ",1,0.50\n2025-06-04,Jane Doe," + filepath.Base(dir) + ",1,0.50\n"},
+		{toRef: "HEAD~1", want: "2025-06-03,Jane Doe," + filepath.Base(dir) + ",1,0.50\n"},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{
+			"repo_url":      dir,
+			"branch":        branch,
+			"from_ref":      "v1.0.0",
+			"to_ref":        tc.toRef,
+			"authors":       "jane",
+			"output_format": FormatTimesheet,
+		}
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil || result.IsError {
+			t.Fatalf("failed to generate timesheet: %v %+v", err, result)
+		}
+		text, ok := result.Content[0].(mcp.TextContent)
+		if !ok {
+			t.Fatalf("expected text content, got %T", result.Content[0])
+		}
+		if want := "date,author,repo,commits,hours\n" + tc.want; text.Text != want {
+			t.Errorf("to_ref %q: expected timesheet %q, got %q", tc.toRef, want, text.Text)
+		}
+	}
+
+	for _, arguments := range []map[string]any{
+		{"from_ref": "v1.0.0", "start_date": "2025-06-01"},
+		{"to_ref": "v1.0.0"},
+		{"from_ref": "v9.9.9"},
+	} {
+		request := mcp.CallToolRequest{}
+		request.Params.Arguments = map[string]any{
+			"repo_url":      dir,
+			"branch":        branch,
+			"authors":       "jane",
+			"output_format": FormatTimesheet,
+		}
+		for name, value := range arguments {
+			request.Params.Arguments.(map[string]any)[name] = value
+		}
+		result, err := tool.Handler(context.Background(), request)
+		if err != nil || !result.IsError {
+			t.Errorf("expected an error for %v, got %v %+v", arguments, err, result)
+		}
+	}
+}
+
+// TestReposText tests the overview of the repositories of a summary.
+func TestReposText(t *testing.T) {
+	t.Parallel()
+	text := reposText([]RepoSummary{
+		{Name: "stock", Branches: []string{"main", "develop"}, Commits: 3},
+		{Name: "annotations", Branches: []string{"main"}, Commits: 1},
+		{Name: "search", Error: "failed to clone repository: not found"},
+	})
+	want := "## Repositories\n\n" +
+		"- `stock` on `main`, `develop`: 3 commits\n" +
+		"- `annotations` on `main`: 1 commit\n" +
+		"- `search`: not read, failed to clone repository: not found\n"
+	if text != want {
+		t.Errorf("expected %q, got %q", want, text)
+	}
+}
+
+// TestRepoSection tests turning the summary of a repository into a
+// section.
+func TestRepoSection(t *testing.T) {
+	t.Parallel()
+	got := repoSection("# Work Summary\n\n## Features\n- **Search** Added search.[^1]\n\n[^1]: [`abc`](url) search\n", 2)
+	want := "### Features\n- **Search** Added search.[^2-1]\n\n[^2-1]: [`abc`](url) search"
+	if got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
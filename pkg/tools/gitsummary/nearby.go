@@ -0,0 +1,46 @@
+package gitsummary
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// noCommitsText reports an empty commit range. With nearby activity it
+// lists the author's closest commits and the most active authors, so the
+// date or author filter can be adjusted.
+func noCommitsText(author, header string, nearby *worksummary.NearbyActivity) string {
+	var builder strings.Builder
+	builder.WriteString("No commits found in the specified range.\n\n")
+	builder.WriteString(header)
+	if nearby == nil {
+		return builder.String()
+	}
+	builder.WriteString("\n## Nearby Activity\n\n")
+	if nearby.LatestBefore != nil {
+		fmt.Fprintf(&builder, "- Latest commit by %q before the range: %s\n",
+			author, nearby.LatestBefore.Format("2006-01-02"))
+	}
+	if nearby.EarliestAfter != nil {
+		fmt.Fprintf(&builder, "- First commit by %q after the range: %s\n",
+			author, nearby.EarliestAfter.Format("2006-01-02"))
+	}
+	if nearby.LatestBefore == nil && nearby.EarliestAfter == nil {
+		fmt.Fprintf(&builder, "- No commits by an author matching %q on this branch\n", author)
+	}
+	fmt.Fprintf(&builder, "- Commits by all authors in the range: %d\n", nearby.RangeCommits)
+	if len(nearby.TopAuthors) > 0 {
+		names := make([]string, 0, len(nearby.TopAuthors))
+		for _, activity := range nearby.TopAuthors {
+			names = append(names, fmt.Sprintf("%s (%d)", activity.Name, activity.Commits))
+		}
+		scope := "in the range"
+		if nearby.TopAuthorsScope == worksummary.ScopeHistory {
+			scope = "on the branch"
+		}
+		fmt.Fprintf(&builder, "- Most active authors %s: %s\n", scope, strings.Join(names, ", "))
+	}
+	builder.WriteString("\nTry moving `start_date` or `end_date` towards these dates, or use one of the author names above.\n")
+	return builder.String()
+}
@@ -0,0 +1,418 @@
+package gitsummary
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Groupings of the bullets of a summary of several repositories.
+const (
+	// GroupByRepo summarizes each repository in a section of its own.
+	GroupByRepo = "repo"
+	// GroupByTheme summarizes the commits of all repositories together,
+	// in bullets by theme.
+	GroupByTheme = "theme"
+)
+
+const (
+	defaultConcurrency = 4
+	maxConcurrency     = 8
+)
+
+// footnotePattern matches the footnote references and definitions of
+// LinkCommits.
+var footnotePattern = regexp.MustCompile(`\[\^(\d+)\]`)
+
+// RepoSummary is what a summary of several repositories found in one of
+// them.
+type RepoSummary struct {
+	RepoURL  string   `json:"repo_url"`
+	Name     string   `json:"name"`
+	Branches []string `json:"branches,omitempty"`
+	Commits  int      `json:"commits"`
+	// Signatures reports the signed commits when requested.
+	Signatures *worksummary.SignatureReport `json:"signatures,omitempty"`
+	// Error tells why the repository could not be read.
+	Error string `json:"error,omitempty"`
+	err   error
+	// origin is the URL commit links point to.
+	origin  string
+	commits []worksummary.Commit
+}
+
+// repoURLs splits a comma-separated list of repositories, dropping blanks
+// and duplicates.
+func repoURLs(list string) ([]string, error) {
+	var urls []string
+	for _, repoURL := range strings.Split(list, ",") {
+		repoURL = strings.TrimSpace(repoURL)
+		if repoURL != "" && !slices.Contains(urls, repoURL) {
+			urls = append(urls, repoURL)
+		}
+	}
+	if len(urls) == 0 {
+		return nil, toolerror.New(toolerror.TypeInvalidInput, "MISSING_REPO_URL", "no repository given")
+	}
+	return urls, nil
+}
+
+// generateReposSummary generates a single summary of the commits of
+// several repositories, or a timesheet or changelog of them. Grouped by repository,
+// each repository with commits is summarized in a section of its own;
+// grouped by theme, the commits of all of them are summarized together.
+// A repository that cannot be read is reported in the overview, unless
+// none can be read.
+func (g *GitSummaryTool) generateReposSummary(
+	ctx context.Context,
+	client *worksummary.OpenAIClient,
+	req GitSummaryRequest,
+	urls []string,
+) (Summary, error) {
+	dateRange, err := g.resolveDates(req)
+	if err != nil {
+		return Summary{}, err
+	}
+	// Each repository resolves the refs on its own, so the header names
+	// them only.
+	header := dateRange.Header()
+	if req.FromRef != "" {
+		header = worksummary.RefRange{FromRef: req.FromRef, ToRef: req.ToRef}.Header()
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, summaryStages, fmt.Sprintf("reading %d repositories", len(urls)))
+	repos := g.readRepos(progress.NewContext(ctx, reporter.Sub(0, 3)), urls, req, dateRange)
+	if err := ctx.Err(); err != nil {
+		return Summary{}, fmt.Errorf("summary aborted: %w", err)
+	}
+	var (
+		errs    []error
+		commits int
+	)
+	for _, repo := range repos {
+		if repo.err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", repo.RepoURL, repo.err))
+		}
+		commits += repo.Commits
+	}
+	if len(errs) == len(repos) {
+		return Summary{}, fmt.Errorf("failed to read repositories: %w", errors.Join(errs...))
+	}
+
+	if req.OutputFormat == FormatTimesheet {
+		sheets := make([]worksummary.Timesheet, 0, len(repos))
+		for _, repo := range repos {
+			sheets = append(sheets, worksummary.NewTimesheet(repo.Name, repo.commits))
+		}
+		timesheet := worksummary.MergeTimesheets(sheets...)
+		text, err := timesheet.CSV()
+		if err != nil {
+			return Summary{}, err
+		}
+		reporter.Report(summaryStages, summaryStages, "timesheet generated")
+		return Summary{Text: text, Timesheet: &timesheet, Repos: repos}, nil
+	}
+	if commits == 0 {
+		text := noCommitsText(req.Authors.String(), header, nil) + "\n" + reposText(repos)
+		return Summary{Text: text, Repos: repos}, nil
+	}
+
+	if req.OutputFormat == FormatChangelog {
+		text, changelog := reposChangelog(req, repos)
+		result := Summary{Text: withHeader(text, header), Changelog: &changelog, Repos: repos}
+		result.Text = strings.TrimRight(result.Text, "\n") + "\n\n" + reposText(repos)
+		if req.Signatures {
+			result.Text += "\n" + reposSignaturesText(repos)
+		}
+		reporter.Report(summaryStages, summaryStages, "changelog generated")
+		return result, nil
+	}
+
+	reporter.Report(3.5, summaryStages, "generating summary")
+	generateCtx := progress.NewContext(ctx, reporter.Sub(3.5, summaryStages))
+	var (
+		text       string
+		generation worksummary.GenerationParams
+	)
+	if req.GroupBy == GroupByTheme {
+		text, generation, err = g.summarizeThemes(generateCtx, client, req, repos)
+	} else {
+		text, generation, err = g.summarizeRepos(generateCtx, client, req, repos)
+	}
+	if err != nil {
+		return Summary{}, err
+	}
+
+	result := Summary{
+		Text:       withHeader(text, header),
+		Generation: &generation,
+		Repos:      repos,
+	}
+	result.Text = strings.TrimRight(result.Text, "\n") + "\n\n" + reposText(repos)
+	if req.Signatures {
+		result.Text += "\n" + reposSignaturesText(repos)
+	}
+	reporter.Report(summaryStages, summaryStages, "summary generated")
+	return result, nil
+}
+
+// summarizeThemes summarizes the commits of all repositories together,
+// each repository's messages under its name.
+func (g *GitSummaryTool) summarizeThemes(
+	ctx context.Context,
+	client *worksummary.OpenAIClient,
+	req GitSummaryRequest,
+	repos []RepoSummary,
+) (string, worksummary.GenerationParams, error) {
+	var input strings.Builder
+	sources := make([]worksummary.CommitSource, 0, len(repos))
+	for _, repo := range repos {
+		if len(repo.commits) == 0 {
+			continue
+		}
+		fmt.Fprintf(&input, "Repository %s:\n%s\n", repo.Name, commitMessages(req, repo.commits))
+		sources = append(sources, worksummary.CommitSource{RepoURL: repo.origin, Commits: repo.commits})
+	}
+	summary, revised, err := g.summarize(ctx, client, input.String())
+	if err != nil {
+		return "", worksummary.GenerationParams{}, err
+	}
+	if req.CommitLinks {
+		summary = worksummary.LinkSourceCommits(summary, sources)
+	}
+	generation := client.GenerationParams(input.String())
+	generation.Revised = revised
+	return summary, generation, nil
+}
+
+// summarizeRepos summarizes the commits of each repository on their own,
+// as sections of one summary. The generation parameters hash the inputs
+// of all sections in order, and are revised when any section was.
+func (g *GitSummaryTool) summarizeRepos(
+	ctx context.Context,
+	client *worksummary.OpenAIClient,
+	req GitSummaryRequest,
+	repos []RepoSummary,
+) (string, worksummary.GenerationParams, error) {
+	reporter := progress.FromContext(ctx)
+	var (
+		text    strings.Builder
+		inputs  []string
+		revised bool
+	)
+	text.WriteString("# Work Summary\n")
+	for i, repo := range repos {
+		if len(repo.commits) == 0 {
+			continue
+		}
+		input := commitMessages(req, repo.commits)
+		share := 1 / float64(len(repos))
+		sectionCtx := progress.NewContext(ctx, reporter.Sub(float64(i)*share, float64(i+1)*share))
+		summary, sectionRevised, err := g.summarize(sectionCtx, client, input)
+		if err != nil {
+			return "", worksummary.GenerationParams{}, fmt.Errorf("%s: %w", repo.Name, err)
+		}
+		if req.CommitLinks {
+			summary = worksummary.LinkCommits(summary, repo.origin, repo.commits)
+		}
+		fmt.Fprintf(&text, "\n## %s\n\n%s\n", repo.Name, repoSection(summary, i+1))
+		inputs = append(inputs, input)
+		revised = revised || sectionRevised
+	}
+	generation := client.GenerationParams(strings.Join(inputs, "\n"))
+	generation.Revised = revised
+	return text.String(), generation, nil
+}
+
+// reposChangelog lists the commits of the repositories by category, in a
+// section per repository or, grouped by theme, across all of them, and
+// returns the categories of all their commits.
+func reposChangelog(req GitSummaryRequest, repos []RepoSummary) (string, worksummary.Categorized) {
+	sources := make([]worksummary.CommitSource, 0, len(repos))
+	var text strings.Builder
+	text.WriteString("# Work Summary\n")
+	for i, repo := range repos {
+		if len(repo.commits) == 0 {
+			continue
+		}
+		source := worksummary.CommitSource{RepoURL: linkedURL(req, repo.origin), Commits: repo.commits}
+		sources = append(sources, source)
+		if req.GroupBy == GroupByRepo {
+			fmt.Fprintf(&text, "\n## %s\n\n%s\n", repo.Name, repoSection(worksummary.Categorize(source).Markdown(), i+1))
+		}
+	}
+	changelog := worksummary.Categorize(sources...)
+	if req.GroupBy == GroupByTheme {
+		return changelog.Markdown(), changelog
+	}
+	return text.String(), changelog
+}
+
+// repoSection turns the summary of one repository into the section it is
+// numbered as: the title is dropped, other headings move below the
+// section heading, and footnotes are prefixed with the number so they do
+// not clash with those of other sections.
+func repoSection(summary string, number int) string {
+	lines := strings.Split(strings.TrimSpace(summary), "\n")
+	if strings.HasPrefix(lines[0], "# ") {
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	section := strings.TrimSpace(strings.Join(lines, "\n"))
+	return footnotePattern.ReplaceAllString(section, fmt.Sprintf("[^%d-${1}]", number))
+}
+
+// readRepos reads the repositories, at most req.Concurrency at a time,
+// keeping their order.
+func (g *GitSummaryTool) readRepos(
+	ctx context.Context,
+	urls []string,
+	req GitSummaryRequest,
+	dateRange worksummary.DateRange,
+) []RepoSummary {
+	logger := logging.WithRequestID(g.Logger)
+	reporter := progress.FromContext(ctx)
+	repos := make([]RepoSummary, len(urls))
+	semaphore := make(chan struct{}, req.Concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for i, repoURL := range urls {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				repos[i] = RepoSummary{RepoURL: repoURL, Name: worksummary.RepoName(repoURL), Error: ctx.Err().Error(), err: ctx.Err()}
+				return
+			}
+			repos[i] = g.readRepo(ctx, repoURL, req, dateRange)
+			if repos[i].err != nil {
+				logger.Warn("failed to read repository", "repo", repoURL, "error", repos[i].err)
+			}
+			mu.Lock()
+			done++
+			reporter.Report(float64(done), float64(len(urls)), "read "+repos[i].Name)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return repos
+}
+
+// readRepo clones a repository and lists the author's commits on its
+// branches within the date range or between the refs.
+func (g *GitSummaryTool) readRepo(
+	ctx context.Context,
+	repoURL string,
+	req GitSummaryRequest,
+	dateRange worksummary.DateRange,
+) RepoSummary {
+	summary := RepoSummary{RepoURL: repoURL, Name: worksummary.RepoName(repoURL)}
+	if err := g.listRepoCommits(ctx, &summary, req, dateRange); err != nil {
+		summary.Error, summary.err = err.Error(), err
+	}
+	return summary
+}
+
+// listRepoCommits fills summary with the branches, commits and signatures
+// of its repository.
+func (g *GitSummaryTool) listRepoCommits(
+	ctx context.Context,
+	summary *RepoSummary,
+	req GitSummaryRequest,
+	dateRange worksummary.DateRange,
+) error {
+	branches, err := g.analyzer.ResolveBranches(ctx, summary.RepoURL, req.Branch)
+	if err != nil {
+		return fmt.Errorf("failed to resolve branches: %w", err)
+	}
+	summary.Branches = branches
+	// Repositories are cloned side by side, so only finished ones are
+	// reported.
+	repo, err := g.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, nil),
+		summary.RepoURL,
+		branches[0],
+		worksummary.WithStorage(worksummary.CloneStorage(req.Storage)),
+		worksummary.WithBranches(branches[1:]...),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to clone repository: %w", err)
+	}
+	defer repo.Close()
+	summary.origin = cmp.Or(repo.Origin, summary.RepoURL)
+
+	params, _, err := commitRange(repo.Repository, req, dateRange, branches)
+	if err != nil {
+		return err
+	}
+	commits, err := g.analyzer.ListAuthorCommits(ctx, params)
+	if len(branches) > 1 {
+		commits, err = g.analyzer.ListBranchCommits(ctx, repo, branches, params)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to list commits: %w", err)
+	}
+	summary.commits, summary.Commits = commits, len(commits)
+	if req.Signatures {
+		report := worksummary.ReportSignatures(repo.Repository, commits, g.signingKeys)
+		summary.Signatures = &report
+	}
+	return nil
+}
+
+// reposText renders the repositories of a summary as a markdown section,
+// with the branches read and the commits found in each, or why it could
+// not be read.
+func reposText(repos []RepoSummary) string {
+	var builder strings.Builder
+	builder.WriteString("## Repositories\n\n")
+	for _, repo := range repos {
+		fmt.Fprintf(&builder, "- `%s`", repo.Name)
+		if len(repo.Branches) > 0 {
+			fmt.Fprintf(&builder, " on `%s`", strings.Join(repo.Branches, "`, `"))
+		}
+		switch {
+		case repo.Error != "":
+			fmt.Fprintf(&builder, ": not read, %s", repo.Error)
+		case repo.Commits == 1:
+			builder.WriteString(": 1 commit")
+		default:
+			fmt.Fprintf(&builder, ": %d commits", repo.Commits)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// reposSignaturesText renders the signature reports of the repositories
+// as a markdown section with a subsection per repository.
+func reposSignaturesText(repos []RepoSummary) string {
+	var builder strings.Builder
+	builder.WriteString("## Commit Signatures\n")
+	for _, repo := range repos {
+		if repo.Signatures == nil {
+			continue
+		}
+		report := strings.TrimPrefix(signaturesText(*repo.Signatures), "## Commit Signatures\n\n")
+		fmt.Fprintf(&builder, "\n### %s\n\n%s", repo.Name, report)
+	}
+	return builder.String()
+}
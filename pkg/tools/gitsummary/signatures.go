@@ -0,0 +1,66 @@
+package gitsummary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// verificationLabels describe the verification results of a signer.
+var verificationLabels = map[string]string{
+	worksummary.VerificationGood:       "verified",
+	worksummary.VerificationBad:        "bad signature",
+	worksummary.VerificationUnknownKey: "unknown key",
+	worksummary.VerificationUnchecked:  "not checked",
+}
+
+// signaturesText renders a signature report as a markdown section.
+func signaturesText(report worksummary.SignatureReport) string {
+	var builder strings.Builder
+	builder.WriteString("## Commit Signatures\n\n")
+	fmt.Fprintf(&builder, "%d of %d commits are signed", report.Signed, report.Commits)
+	if report.Signed > 0 {
+		types := make([]string, 0, len(report.ByType))
+		for kind := range report.ByType {
+			types = append(types, kind)
+		}
+		slices.Sort(types)
+		counts := make([]string, 0, len(types))
+		for _, kind := range types {
+			counts = append(counts, fmt.Sprintf("%d %s", report.ByType[kind], strings.ToUpper(kind)))
+		}
+		fmt.Fprintf(&builder, " (%s)", strings.Join(counts, ", "))
+	}
+	builder.WriteString(".\n")
+	if !report.Verified {
+		builder.WriteString("\nNo signing keys are configured on the server, so signatures were not verified.\n")
+	}
+	if len(report.Signers) == 0 {
+		return builder.String()
+	}
+	builder.WriteString("\n| Key | Type | Signer | Authors | Commits | Status |\n")
+	builder.WriteString("|-----|------|--------|---------|---------|--------|\n")
+	for _, signer := range report.Signers {
+		key := "unknown"
+		if signer.KeyID != "" {
+			key = "`" + signer.KeyID + "`"
+		}
+		identity := signer.Identity
+		if identity == "" {
+			identity = "-"
+		}
+		fmt.Fprintf(
+			&builder,
+			"| %s | %s | %s | %s | %d | %s |\n",
+			key,
+			strings.ToUpper(signer.Type),
+			identity,
+			strings.Join(signer.Authors, ", "),
+			signer.Commits,
+			verificationLabels[signer.Verification],
+		)
+	}
+	return builder.String()
+}
@@ -0,0 +1,63 @@
+package granttool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+)
+
+// batchSize is the number of PMIDs looked up per search.
+const batchSize = 100
+
+// EuropePMCClient reads the grants of publications from the EuropePMC
+// REST API through the literature client, which rate limits, retries and
+// replays its requests.
+type EuropePMCClient struct {
+	literature *literaturetool.LiteratureClient
+	logger     *slog.Logger
+}
+
+// NewEuropePMCClient creates a new EuropePMC client logging to logger,
+// with the options of the literature client.
+func NewEuropePMCClient(logger *slog.Logger, opts ...literaturetool.Option) (*EuropePMCClient, error) {
+	literature, err := literaturetool.NewLiteratureClient(
+		append([]literaturetool.Option{literaturetool.WithLogger(logger)}, opts...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &EuropePMCClient{literature: literature, logger: logger}, nil
+}
+
+// Publications looks up the publications with the PMIDs and their grants,
+// batchSize PMIDs per request. PMIDs EuropePMC does not know are left out.
+func (c *EuropePMCClient) Publications(ctx context.Context, pmids []string) ([]Publication, error) {
+	publications := make([]Publication, 0, len(pmids))
+	for start := 0; start < len(pmids); start += batchSize {
+		batch := pmids[start:min(start+batchSize, len(pmids))]
+		clauses := make([]string, 0, len(batch))
+		for _, pmid := range batch {
+			clauses = append(clauses, "EXT_ID:"+pmid)
+		}
+		values := url.Values{
+			"query":      {"SRC:MED AND (" + strings.Join(clauses, " OR ") + ")"},
+			"format":     {"json"},
+			"resultType": {"core"},
+			"pageSize":   {strconv.Itoa(len(batch))},
+		}
+		var response searchResponse
+		if err := c.literature.FetchEuropePMC(ctx, "/search?"+values.Encode(), &response); err != nil {
+			return nil, fmt.Errorf("failed to look up %d publications: %w", len(batch), err)
+		}
+		for _, publication := range response.ResultList.Result {
+			publications = append(publications, toPublication(publication))
+		}
+	}
+	c.logger.Debug("looked up publications", "requested", len(pmids), "found", len(publications))
+	return publications, nil
+}
@@ -0,0 +1,106 @@
+package granttool
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubGrants are the grants of the publications the stub knows.
+var stubGrants = map[string][][2]string{
+	"23172289": {{"R01 GM064426", "NIGMS NIH HHS"}, {"P41 HG002273", "NHGRI NIH HHS"}},
+	"24185693": {{"R01 GM064426", "NIGMS NIH HHS"}},
+	"31875873": {},
+}
+
+// stubEuropePMC serves the EuropePMC search endpoint with the publications
+// of stubGrants, counting the requests it receives.
+func stubEuropePMC(t *testing.T, requests *atomic.Int32) *httptest.Server {
+	t.Helper()
+	extID := regexp.MustCompile(`EXT_ID:(\d+)`)
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		values := r.URL.Query()
+		assert.Equal(t, "core", values.Get("resultType"))
+		matches := extID.FindAllStringSubmatch(values.Get("query"), -1)
+		assert.Equal(t, strconv.Itoa(len(matches)), values.Get("pageSize"))
+		results := []map[string]any{}
+		for _, match := range matches {
+			grants, known := stubGrants[match[1]]
+			if !known {
+				continue
+			}
+			list := []map[string]any{}
+			for _, grant := range grants {
+				list = append(list, map[string]any{"grantId": grant[0], "agency": grant[1], "orderIn": 0})
+			}
+			results = append(results, map[string]any{
+				"pmid":       match[1],
+				"title":      "Article " + match[1],
+				"grantsList": map[string]any{"grant": list},
+			})
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"hitCount":   len(results),
+			"resultList": map[string]any{"result": results},
+		}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient returns a client of the EuropePMC server at baseURL.
+func newTestClient(t *testing.T, baseURL string) *EuropePMCClient {
+	t.Helper()
+	client, err := NewEuropePMCClient(slog.Default(), literaturetool.WithEuropePMCURL(baseURL))
+	require.NoError(t, err)
+	return client
+}
+
+func TestPublications_Batches(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	client := newTestClient(t, stubEuropePMC(t, &requests).URL)
+	pmids := []string{"23172289"}
+	for i := range batchSize + 10 {
+		pmids = append(pmids, strconv.Itoa(1000+i))
+	}
+	pmids = append(pmids, "24185693")
+	publications, err := client.Publications(context.Background(), pmids)
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), requests.Load())
+	require.Len(t, publications, 2)
+	assert.Equal(t, "23172289", publications[0].PMID)
+	assert.Equal(t, []Grant{
+		{GrantID: "R01 GM064426", Agency: "NIGMS NIH HHS"},
+		{GrantID: "P41 HG002273", Agency: "NHGRI NIH HHS"},
+	}, publications[0].Grants)
+	assert.Equal(t, "24185693", publications[1].PMID)
+}
+
+func TestPublications_Error(t *testing.T) {
+	t.Parallel()
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		requests.Add(1)
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	_, err := newTestClient(t, server.URL).Publications(context.Background(), []string{"1"})
+	require.ErrorContains(t, err, "status 503")
+	assert.Equal(t, int32(retry.Current().MaxAttempts), requests.Load(), "unavailable responses are retried")
+}
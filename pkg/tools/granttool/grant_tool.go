@@ -0,0 +1,216 @@
+package granttool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"unicode"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Output formats of a report.
+const (
+	FormatMarkdown = "markdown"
+	FormatCSV      = "csv"
+)
+
+// maxPMIDs bounds the publications of a report.
+const maxPMIDs = 500
+
+// Initialize validator.
+var validate = validator.New()
+
+// GrantTool aggregates the grants acknowledged by a set of publications
+// by funding agency, for grant progress reports.
+type GrantTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []literaturetool.Option
+}
+
+// ToolOption defines a functional option for configuring GrantTool.
+type ToolOption func(*GrantTool)
+
+// WithClientOptions sets options for the literature clients the tool
+// reaches EuropePMC with.
+func WithClientOptions(opts ...literaturetool.Option) ToolOption {
+	return func(g *GrantTool) {
+		g.clientOptions = append(g.clientOptions, opts...)
+	}
+}
+
+// GrantRequest represents the parameters of a report.
+type GrantRequest struct {
+	PMIDs        []string `validate:"required,min=1,max=500,dive,numeric"`
+	OutputFormat string   `validate:"required,oneof=markdown csv"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"grant-report",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			clientOpts, err := literaturetool.CassetteOptions(deps.Literature)
+			if err != nil {
+				return nil, err
+			}
+			return NewGrantTool(deps.Logger, WithClientOptions(clientOpts...))
+		},
+	)
+}
+
+// NewGrantTool creates a new GrantTool instance.
+func NewGrantTool(logger *slog.Logger, opts ...ToolOption) (*GrantTool, error) {
+	tool := mcp.NewTool(
+		"grant-report",
+		mcp.WithDescription(
+			"Aggregates the grants acknowledged by a set of publications, given their PMIDs, into a table "+
+				"of funding agencies with their grants and publications, as markdown or CSV",
+		),
+		mcp.WithTitleAnnotation("Grant Report"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"pmids",
+			mcp.Description(fmt.Sprintf(
+				"PubMed IDs of the publications, separated by commas or spaces, at most %d",
+				maxPMIDs,
+			)),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"output_format",
+			mcp.Description("markdown for a table (default) or csv for a spreadsheet"),
+			mcp.Enum(FormatMarkdown, FormatCSV),
+		),
+	)
+	grantTool := &GrantTool{
+		Name:        "grant-report",
+		Description: "Aggregates the grants of publications by funding agency",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(grantTool)
+	}
+	return grantTool, nil
+}
+
+// GetName returns the name of the tool.
+func (g *GrantTool) GetName() string {
+	return g.Name
+}
+
+// GetDescription returns the description of the tool.
+func (g *GrantTool) GetDescription() string {
+	return g.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (g *GrantTool) GetSchema() mcp.ToolInputSchema {
+	return g.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (g *GrantTool) GetTool() mcp.Tool {
+	return g.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (g *GrantTool) GetAnnotations() mcp.ToolAnnotation {
+	return g.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (g *GrantTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize the funding of dictyBase papers by agency",
+			Arguments: map[string]any{
+				"pmids": "23172289, 24185693, 31875873",
+			},
+		},
+		{
+			Description: "Export the agencies of a grant progress report to a spreadsheet",
+			Arguments: map[string]any{
+				"pmids":         "23172289 24185693",
+				"output_format": "csv",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (g *GrantTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := GrantRequest{
+		PMIDs:        ParsePMIDs(request.GetString("pmids", "")),
+		OutputFormat: request.GetString("output_format", FormatMarkdown),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	logger := logging.WithRequestID(g.Logger)
+	client, err := NewEuropePMCClient(logger, g.clientOptions...)
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to create EuropePMC client: %w", err)), nil
+	}
+	publications, err := client.Publications(ctx, params.PMIDs)
+	if err != nil {
+		return toolerror.Result(toolerror.Upstream(metrics.ServiceEuropePMC, err, "lookup failed")), nil
+	}
+	report := Aggregate(params.PMIDs, publications)
+	logger.Info(
+		"aggregated grants",
+		"publications", report.Publications,
+		"agencies", len(report.Agencies),
+		"not_found", len(report.NotFound),
+	)
+	text := RenderMarkdown(report)
+	if params.OutputFormat == FormatCSV {
+		text, err = RenderCSV(report)
+		if err != nil {
+			return toolerror.Result(err), nil
+		}
+	}
+	return provenance.Attach(
+		mcp.NewToolResultStructured(report, text),
+		provenance.New([]string{metrics.ServiceEuropePMC}),
+	), nil
+}
+
+// ParsePMIDs splits a list of PMIDs separated by commas or spaces,
+// stripping PMID: prefixes and dropping repeats.
+func ParsePMIDs(list string) []string {
+	fields := strings.FieldsFunc(list, func(r rune) bool {
+		return r == ',' || r == ';' || unicode.IsSpace(r)
+	})
+	pmids := make([]string, 0, len(fields))
+	for _, field := range fields {
+		pmid := field
+		for _, prefix := range []string{"PMID:", "pmid:"} {
+			pmid = strings.TrimPrefix(pmid, prefix)
+		}
+		if pmid != "" && !slices.Contains(pmids, pmid) {
+			pmids = append(pmids, pmid)
+		}
+	}
+	return pmids
+}
@@ -0,0 +1,83 @@
+package granttool
+
+import (
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	var requests atomic.Int32
+	tool, err := NewGrantTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithClientOptions(literaturetool.WithEuropePMCURL(stubEuropePMC(t, &requests).URL)),
+	)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "grant-report", arguments)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	result := callTool(t, map[string]any{"pmids": "PMID:23172289, 24185693 31875873"})
+	require.False(t, result.IsError)
+	report, ok := result.StructuredContent.(Report)
+	require.True(t, ok)
+	assert.Equal(t, 3, report.Publications)
+	assert.Equal(t, "NIGMS NIH HHS", report.Agencies[0].Agency)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(text.Text, "# Grant Report"))
+
+	result = callTool(t, map[string]any{"pmids": "23172289", "output_format": "csv"})
+	require.False(t, result.IsError)
+	text, ok = result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.True(t, strings.HasPrefix(text.Text, "agency,grants,publications,grant_ids,pmids\n"))
+}
+
+// manyPMIDs returns a list of count distinct PMIDs.
+func manyPMIDs(count int) string {
+	pmids := make([]string, 0, count)
+	for i := range count {
+		pmids = append(pmids, strconv.Itoa(1000+i))
+	}
+	return strings.Join(pmids, ",")
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	for _, arguments := range []map[string]any{
+		{},
+		{"pmids": " , "},
+		{"pmids": "23172289, doi:10.1093/nar/gks1064"},
+		{"pmids": "23172289", "output_format": "xlsx"},
+		{"pmids": manyPMIDs(maxPMIDs + 1)},
+	} {
+		result := callTool(t, arguments)
+		require.True(t, result.IsError, arguments)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
+
+func TestParsePMIDs(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		[]string{"23172289", "24185693", "1"},
+		ParsePMIDs("PMID:23172289,24185693;\n PMID: 1 23172289"),
+	)
+	assert.Empty(t, ParsePMIDs(""))
+}
@@ -0,0 +1,117 @@
+package granttool
+
+import (
+	"cmp"
+	"encoding/csv"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// unknownAgency names the agency of grants listed without one.
+const unknownAgency = "Unknown agency"
+
+// Aggregate groups the grants of the publications by agency. PMIDs with no
+// publication are reported as not found.
+func Aggregate(pmids []string, publications []Publication) Report {
+	report := Report{Publications: len(publications)}
+	found := make(map[string]bool, len(publications))
+	byAgency := make(map[string]*AgencySummary)
+	for _, publication := range publications {
+		found[publication.PMID] = true
+		if len(publication.Grants) == 0 {
+			report.WithoutGrants = append(report.WithoutGrants, publication.PMID)
+			continue
+		}
+		for _, grant := range publication.Grants {
+			agency := cmp.Or(grant.Agency, unknownAgency)
+			summary, seen := byAgency[agency]
+			if !seen {
+				summary = &AgencySummary{Agency: agency}
+				byAgency[agency] = summary
+			}
+			if grant.GrantID != "" && !slices.Contains(summary.GrantIDs, grant.GrantID) {
+				summary.GrantIDs = append(summary.GrantIDs, grant.GrantID)
+			}
+			if !slices.Contains(summary.PMIDs, publication.PMID) {
+				summary.PMIDs = append(summary.PMIDs, publication.PMID)
+			}
+		}
+	}
+	for _, pmid := range pmids {
+		if !found[pmid] {
+			report.NotFound = append(report.NotFound, pmid)
+		}
+	}
+	for _, summary := range byAgency {
+		slices.Sort(summary.GrantIDs)
+		slices.Sort(summary.PMIDs)
+		report.Agencies = append(report.Agencies, *summary)
+	}
+	slices.SortFunc(report.Agencies, func(a, b AgencySummary) int {
+		return cmp.Or(
+			cmp.Compare(len(b.PMIDs), len(a.PMIDs)),
+			cmp.Compare(len(b.GrantIDs), len(a.GrantIDs)),
+			strings.Compare(a.Agency, b.Agency),
+		)
+	})
+	slices.Sort(report.WithoutGrants)
+	return report
+}
+
+// RenderMarkdown renders a report as a markdown table of agencies.
+func RenderMarkdown(report Report) string {
+	var sb strings.Builder
+	sb.WriteString("# Grant Report\n\n")
+	fmt.Fprintf(
+		&sb,
+		"%d publications, %d acknowledging grants of %d agencies.\n\n",
+		report.Publications,
+		report.Publications-len(report.WithoutGrants),
+		len(report.Agencies),
+	)
+	if len(report.Agencies) > 0 {
+		sb.WriteString("| Agency | Grants | Publications | Grant IDs |\n")
+		sb.WriteString("|--------|--------|--------------|-----------|\n")
+		for _, agency := range report.Agencies {
+			fmt.Fprintf(
+				&sb,
+				"| %s | %d | %d | %s |\n",
+				agency.Agency,
+				len(agency.GrantIDs),
+				len(agency.PMIDs),
+				strings.Join(agency.GrantIDs, ", "),
+			)
+		}
+		sb.WriteString("\n")
+	}
+	if len(report.WithoutGrants) > 0 {
+		fmt.Fprintf(&sb, "**No grants listed:** %s\n\n", strings.Join(report.WithoutGrants, ", "))
+	}
+	if len(report.NotFound) > 0 {
+		fmt.Fprintf(&sb, "**Not found in EuropePMC:** %s\n\n", strings.Join(report.NotFound, ", "))
+	}
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// RenderCSV renders the agencies of a report as CSV, one row per agency,
+// with grant IDs and PMIDs separated by semicolons.
+func RenderCSV(report Report) (string, error) {
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	rows := [][]string{{"agency", "grants", "publications", "grant_ids", "pmids"}}
+	for _, agency := range report.Agencies {
+		rows = append(rows, []string{
+			agency.Agency,
+			strconv.Itoa(len(agency.GrantIDs)),
+			strconv.Itoa(len(agency.PMIDs)),
+			strings.Join(agency.GrantIDs, ";"),
+			strings.Join(agency.PMIDs, ";"),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("error writing CSV: %w", err)
+	}
+	return sb.String(), nil
+}
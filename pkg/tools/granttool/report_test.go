@@ -0,0 +1,62 @@
+package granttool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testReport() Report {
+	return Aggregate(
+		[]string{"1", "2", "3", "4"},
+		[]Publication{
+			{PMID: "2", Grants: []Grant{{GrantID: "R01 GM064426", Agency: "NIGMS NIH HHS"}}},
+			{PMID: "1", Grants: []Grant{
+				{GrantID: "R01 GM064426", Agency: "NIGMS NIH HHS"},
+				{GrantID: "P41 HG002273", Agency: "NHGRI NIH HHS"},
+				{GrantID: "X1"},
+			}},
+			{PMID: "3"},
+		},
+	)
+}
+
+func TestAggregate(t *testing.T) {
+	t.Parallel()
+	report := testReport()
+	assert.Equal(t, 3, report.Publications)
+	assert.Equal(t, []string{"3"}, report.WithoutGrants)
+	assert.Equal(t, []string{"4"}, report.NotFound)
+	require.Len(t, report.Agencies, 3)
+	assert.Equal(t, AgencySummary{
+		Agency:   "NIGMS NIH HHS",
+		GrantIDs: []string{"R01 GM064426"},
+		PMIDs:    []string{"1", "2"},
+	}, report.Agencies[0])
+	assert.Equal(t, "NHGRI NIH HHS", report.Agencies[1].Agency)
+	assert.Equal(t, unknownAgency, report.Agencies[2].Agency)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	text := RenderMarkdown(testReport())
+	assert.Contains(t, text, "3 publications, 2 acknowledging grants of 3 agencies.")
+	assert.Contains(t, text, "| NIGMS NIH HHS | 1 | 2 | R01 GM064426 |")
+	assert.Contains(t, text, "**No grants listed:** 3")
+	assert.Contains(t, text, "**Not found in EuropePMC:** 4")
+}
+
+func TestRenderCSV(t *testing.T) {
+	t.Parallel()
+	text, err := RenderCSV(testReport())
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"agency,grants,publications,grant_ids,pmids\n"+
+			"NIGMS NIH HHS,1,2,R01 GM064426,1;2\n"+
+			"NHGRI NIH HHS,1,1,P41 HG002273,1\n"+
+			"Unknown agency,1,1,X1,1\n",
+		text,
+	)
+}
@@ -0,0 +1,70 @@
+package granttool
+
+import "strings"
+
+// Publication is an article with the grants that funded it.
+type Publication struct {
+	PMID   string  `json:"pmid"`
+	Title  string  `json:"title"`
+	Grants []Grant `json:"grants,omitempty"`
+}
+
+// Grant is a grant acknowledged by a publication.
+type Grant struct {
+	GrantID string `json:"grant_id"`
+	Agency  string `json:"agency"`
+}
+
+// AgencySummary counts the grants of a funding agency and the publications
+// acknowledging them.
+type AgencySummary struct {
+	Agency string `json:"agency"`
+	// GrantIDs are the distinct grants of the agency, sorted.
+	GrantIDs []string `json:"grant_ids"`
+	// PMIDs are the publications acknowledging a grant of the agency,
+	// sorted.
+	PMIDs []string `json:"pmids"`
+}
+
+// Report aggregates the grants of a set of publications by agency.
+type Report struct {
+	// Agencies are sorted by the number of publications, most first.
+	Agencies []AgencySummary `json:"agencies"`
+	// Publications counts the publications found.
+	Publications int `json:"publications"`
+	// WithoutGrants are the PMIDs of publications listing no grants.
+	WithoutGrants []string `json:"without_grants,omitempty"`
+	// NotFound are the PMIDs EuropePMC has no record of.
+	NotFound []string `json:"not_found,omitempty"`
+}
+
+// searchResponse is the subset of a core /search response used.
+type searchResponse struct {
+	ResultList struct {
+		Result []publicationResponse `json:"result"`
+	} `json:"resultList"`
+}
+
+// publicationResponse is an article of a core /search response.
+type publicationResponse struct {
+	PMID       string `json:"pmid"`
+	Title      string `json:"title"`
+	GrantsList struct {
+		Grant []struct {
+			GrantID string `json:"grantId"`
+			Agency  string `json:"agency"`
+		} `json:"grant"`
+	} `json:"grantsList"`
+}
+
+// toPublication converts an article of a search response.
+func toPublication(response publicationResponse) Publication {
+	publication := Publication{PMID: response.PMID, Title: strings.TrimSpace(response.Title)}
+	for _, grant := range response.GrantsList.Grant {
+		publication.Grants = append(publication.Grants, Grant{
+			GrantID: strings.TrimSpace(grant.GrantID),
+			Agency:  strings.TrimSpace(grant.Agency),
+		})
+	}
+	return publication
+}
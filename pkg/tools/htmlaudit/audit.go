@@ -0,0 +1,466 @@
+package htmlaudit
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// Rules of the findings, named after the matching axe-core rules.
+const (
+	RuleImageAlt     = "image-alt"
+	RuleHeadingOrder = "heading-order"
+	RuleEmptyHeading = "empty-heading"
+	RuleContrast     = "color-contrast"
+)
+
+// Severities of the findings. Errors fail the guidelines; warnings hurt
+// navigation with assistive technology.
+const (
+	SeverityError   = "error"
+	SeverityWarning = "warning"
+)
+
+const (
+	// maxSnippet is the number of characters of an element or of its text
+	// quoted in a finding.
+	maxSnippet = 100
+	// baseFontSize is the font size, in pixels, of text without one.
+	baseFontSize = 16
+)
+
+// headingFontSizes are the default font sizes of the headings large enough
+// to count as large text, in pixels.
+var headingFontSizes = map[atom.Atom]float64{atom.H1: 32, atom.H2: 24, atom.H3: 18.72}
+
+// Finding is an accessibility problem of an element.
+type Finding struct {
+	Rule     string `json:"rule"`
+	Severity string `json:"severity"`
+	// Element is the start tag of the element, shortened.
+	Element string `json:"element"`
+	// Text is the start of the text of a heading or of low contrast text.
+	Text    string `json:"text,omitempty"`
+	Message string `json:"message"`
+	// Contrast is the contrast ratio of low contrast text, Required the
+	// ratio the level asks for, and Foreground and Background its colors.
+	Contrast   float64 `json:"contrast,omitempty"`
+	Required   float64 `json:"required,omitempty"`
+	Foreground string  `json:"foreground,omitempty"`
+	Background string  `json:"background,omitempty"`
+}
+
+// Report is the outcome of an audit.
+type Report struct {
+	// Level is the contrast level text was checked against.
+	Level    string `json:"level"`
+	Images   int    `json:"images"`
+	Headings int    `json:"headings"`
+	// StyledText is the number of elements with text colored by inline
+	// styles, whose contrast was checked.
+	StyledText int       `json:"styled_text"`
+	Errors     int       `json:"errors"`
+	Warnings   int       `json:"warnings"`
+	Findings   []Finding `json:"findings"`
+}
+
+// add records a finding.
+func (r *Report) add(finding Finding) {
+	if finding.Severity == SeverityError {
+		r.Errors++
+	} else {
+		r.Warnings++
+	}
+	r.Findings = append(r.Findings, finding)
+}
+
+// Audit checks an HTML document or fragment for images without alt text,
+// empty headings, headings that skip a level, and text whose inline styles
+// give it too little contrast for the level, LevelAA or LevelAAA. Colors
+// come from style attributes, and the legacy color and bgcolor attributes,
+// on a white page with black text; text colored by stylesheets is not
+// checked.
+func Audit(source, level string) (Report, error) {
+	document, err := html.Parse(strings.NewReader(source))
+	if err != nil {
+		return Report{}, fmt.Errorf("error parsing HTML: %w", err)
+	}
+	auditor := &auditor{level: level, styles: make(map[*html.Node]style)}
+	report := Report{Level: level, Findings: []Finding{}}
+	previous := 0
+	for node := range document.Descendants() {
+		if node.Type != html.ElementNode || skipped(node) {
+			continue
+		}
+		switch node.DataAtom {
+		case atom.Img, atom.Area, atom.Input:
+			if node.DataAtom == atom.Input && !strings.EqualFold(attr(node, "type"), "image") {
+				break
+			}
+			report.Images++
+			if !hasTextAlternative(node) {
+				report.add(Finding{
+					Rule:     RuleImageAlt,
+					Severity: SeverityError,
+					Element:  startTag(node),
+					Message:  "Image has no alt attribute; use alt=\"\" for decorative images",
+				})
+			}
+		case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+			report.Headings++
+			headingLevel := int(node.Data[1] - '0')
+			text := snippet(textContent(node))
+			if text == "" {
+				report.add(Finding{
+					Rule:     RuleEmptyHeading,
+					Severity: SeverityWarning,
+					Element:  startTag(node),
+					Message:  "Heading has no text",
+				})
+			}
+			if previous > 0 && headingLevel > previous+1 {
+				report.add(Finding{
+					Rule:     RuleHeadingOrder,
+					Severity: SeverityWarning,
+					Element:  startTag(node),
+					Text:     text,
+					Message:  fmt.Sprintf("h%d follows h%d, skipping h%d", headingLevel, previous, previous+1),
+				})
+			}
+			previous = headingLevel
+		}
+		if finding, styled := auditor.contrast(node); styled {
+			report.StyledText++
+			if finding != nil {
+				report.add(*finding)
+			}
+		}
+	}
+	return report, nil
+}
+
+// auditor checks the contrast of text, caching the inline styles of the
+// elements.
+type auditor struct {
+	level  string
+	styles map[*html.Node]style
+}
+
+// contrast checks the text directly inside an element, reporting whether
+// inline styles set its colors and, if its contrast is too low, the
+// finding. Text whose colors cannot be told is not checked.
+func (a *auditor) contrast(node *html.Node) (*Finding, bool) {
+	text := directText(node)
+	if text == "" {
+		return nil, false
+	}
+	var (
+		foreground *rgba
+		layers     []rgba
+		opaque     bool
+	)
+	for element := node; element != nil && (foreground == nil || !opaque); element = element.Parent {
+		if element.Type != html.ElementNode {
+			continue
+		}
+		st := a.style(element)
+		if foreground == nil {
+			if st.colorUnknown {
+				return nil, false
+			}
+			foreground = st.color
+		}
+		if !opaque {
+			if st.backgroundUnknown {
+				return nil, false
+			}
+			if st.background != nil {
+				layers = append(layers, *st.background)
+				opaque = st.background.a == 1
+			}
+		}
+	}
+	if foreground == nil && len(layers) == 0 {
+		return nil, false
+	}
+	background := white
+	for i := len(layers) - 1; i >= 0; i-- {
+		background = layers[i].over(background)
+	}
+	fg := black
+	if foreground != nil {
+		fg = foreground.over(background)
+	}
+	ratio := contrastRatio(fg, background)
+	thresholds := minContrast[a.level]
+	required, size := thresholds[0], "normal"
+	if a.largeText(node) {
+		required, size = thresholds[1], "large"
+	}
+	if ratio >= required {
+		return nil, true
+	}
+	return &Finding{
+		Rule:     RuleContrast,
+		Severity: SeverityError,
+		Element:  startTag(node),
+		Text:     snippet(text),
+		Message: fmt.Sprintf(
+			"Contrast of %.2f:1 is below the %.1f:1 level %s asks of %s text",
+			ratio, required, a.level, size,
+		),
+		Contrast:   ratio,
+		Required:   required,
+		Foreground: fg.hex(),
+		Background: background.hex(),
+	}, true
+}
+
+// largeText reports whether the text of an element is large, at least
+// 24px or 18.66px and bold, from its inline styles or those of its
+// ancestors, or its heading level.
+func (a *auditor) largeText(node *html.Node) bool {
+	var (
+		size            float64
+		bold, boldKnown bool
+	)
+	for element := node; element != nil && (size == 0 || !boldKnown); element = element.Parent {
+		if element.Type != html.ElementNode {
+			continue
+		}
+		st := a.style(element)
+		if size == 0 {
+			size = cmp.Or(st.fontSize, headingFontSizes[element.DataAtom])
+		}
+		switch {
+		case boldKnown:
+		case st.bold != nil:
+			bold, boldKnown = *st.bold, true
+		case isBoldElement(element.DataAtom):
+			bold, boldKnown = true, true
+		}
+	}
+	size = cmp.Or(size, baseFontSize)
+	return size >= 24 || (bold && size >= 18.66)
+}
+
+// isBoldElement reports whether browsers set the text of an element in bold.
+func isBoldElement(element atom.Atom) bool {
+	switch element {
+	case atom.B, atom.Strong, atom.Th, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6:
+		return true
+	}
+	return false
+}
+
+// style returns the inline style of an element.
+func (a *auditor) style(node *html.Node) style {
+	st, ok := a.styles[node]
+	if !ok {
+		st = parseStyle(attr(node, "style"))
+		if st.background == nil && !st.backgroundUnknown && hasAttr(node, "bgcolor") {
+			st.background, st.backgroundUnknown = colorValue(attr(node, "bgcolor"))
+		}
+		if node.DataAtom == atom.Font && st.color == nil && !st.colorUnknown && hasAttr(node, "color") {
+			st.color, st.colorUnknown = colorValue(attr(node, "color"))
+		}
+		a.styles[node] = st
+	}
+	return st
+}
+
+// style holds the properties of a style attribute that bear on contrast.
+type style struct {
+	color      *rgba
+	background *rgba
+	// colorUnknown and backgroundUnknown are set when a color cannot be
+	// read, such as var(--text).
+	colorUnknown      bool
+	backgroundUnknown bool
+	// fontSize is in pixels, zero when not set.
+	fontSize float64
+	bold     *bool
+}
+
+// parseStyle reads the declarations of a style attribute.
+func parseStyle(declarations string) style {
+	var st style
+	for _, declaration := range strings.Split(declarations, ";") {
+		property, value, found := strings.Cut(declaration, ":")
+		if !found {
+			continue
+		}
+		property = strings.ToLower(strings.TrimSpace(property))
+		value = strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(value), "!important"))
+		switch property {
+		case "color":
+			st.color, st.colorUnknown = colorValue(value)
+		case "background-color":
+			st.background, st.backgroundUnknown = colorValue(value)
+		case "background":
+			st.background, st.backgroundUnknown = backgroundColor(value)
+		case "font-size":
+			st.fontSize = fontSize(value)
+		case "font-weight":
+			value = strings.ToLower(value)
+			bold := value == "bold" || value == "bolder"
+			if weight, err := strconv.Atoi(value); err == nil {
+				bold = weight >= 700
+			}
+			st.bold = &bold
+		}
+	}
+	return st
+}
+
+// colorValue reads a color, reporting whether it could not be read.
+func colorValue(value string) (*rgba, bool) {
+	color, ok := parseColor(value)
+	if !ok {
+		return nil, true
+	}
+	return &color, false
+}
+
+// backgroundColor reads the color of a background shorthand, such as
+// "#fff url(bg.png) no-repeat". A background image hides the color, so
+// its contrast cannot be told.
+func backgroundColor(value string) (*rgba, bool) {
+	lower := strings.ToLower(value)
+	if strings.Contains(lower, "url(") || strings.Contains(lower, "gradient(") {
+		return nil, true
+	}
+	var tokens []string
+	depth, start := 0, 0
+	for i, r := range value {
+		switch {
+		case r == '(':
+			depth++
+		case r == ')':
+			depth--
+		case r == ' ' && depth == 0:
+			tokens = append(tokens, value[start:i])
+			start = i + 1
+		}
+	}
+	for _, token := range append(tokens, value[start:]) {
+		if color, ok := parseColor(token); ok {
+			return &color, false
+		}
+	}
+	return nil, false
+}
+
+// fontSize reads a font size in px, pt, em, rem or percent as pixels, or
+// returns zero.
+func fontSize(value string) float64 {
+	units := []struct {
+		suffix string
+		pixels float64
+	}{{"rem", baseFontSize}, {"px", 1}, {"pt", 4.0 / 3}, {"em", baseFontSize}, {"%", baseFontSize / 100.0}}
+	for _, unit := range units {
+		if number, ok := strings.CutSuffix(value, unit.suffix); ok {
+			size, err := strconv.ParseFloat(strings.TrimSpace(number), 64)
+			if err != nil {
+				return 0
+			}
+			return size * unit.pixels
+		}
+	}
+	return 0
+}
+
+// skipped reports whether an element is not rendered as content.
+func skipped(node *html.Node) bool {
+	for element := node; element != nil; element = element.Parent {
+		switch element.DataAtom {
+		case atom.Head, atom.Script, atom.Style, atom.Template, atom.Noscript:
+			return true
+		}
+	}
+	return false
+}
+
+// hasTextAlternative reports whether an image has an alt attribute, or
+// another label or role that lets screen readers handle it.
+func hasTextAlternative(node *html.Node) bool {
+	if hasAttr(node, "alt") ||
+		strings.TrimSpace(attr(node, "aria-label")) != "" ||
+		strings.TrimSpace(attr(node, "aria-labelledby")) != "" ||
+		attr(node, "aria-hidden") == "true" {
+		return true
+	}
+	role := attr(node, "role")
+	return role == "presentation" || role == "none"
+}
+
+// textContent returns the text of an element and the alt text of its
+// images, with whitespace collapsed.
+func textContent(node *html.Node) string {
+	var builder strings.Builder
+	for descendant := range node.Descendants() {
+		switch {
+		case descendant.Type == html.TextNode:
+			builder.WriteString(descendant.Data)
+		case descendant.DataAtom == atom.Img:
+			builder.WriteString(" " + attr(descendant, "alt") + " ")
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// directText returns the text directly inside an element, with whitespace
+// collapsed.
+func directText(node *html.Node) string {
+	var builder strings.Builder
+	for child := range node.ChildNodes() {
+		if child.Type == html.TextNode {
+			builder.WriteString(child.Data + " ")
+		}
+	}
+	return strings.Join(strings.Fields(builder.String()), " ")
+}
+
+// startTag renders the start tag of an element, shortened.
+func startTag(node *html.Node) string {
+	var builder strings.Builder
+	builder.WriteString("<" + node.Data)
+	for _, attribute := range node.Attr {
+		fmt.Fprintf(&builder, " %s=%q", attribute.Key, attribute.Val)
+	}
+	builder.WriteString(">")
+	return snippet(builder.String())
+}
+
+// snippet shortens text to maxSnippet characters.
+func snippet(text string) string {
+	if utf8.RuneCountInString(text) <= maxSnippet {
+		return text
+	}
+	return string([]rune(text)[:maxSnippet-1]) + "…"
+}
+
+// attr returns the value of an attribute of an element.
+func attr(node *html.Node, key string) string {
+	for _, attribute := range node.Attr {
+		if attribute.Key == key {
+			return attribute.Val
+		}
+	}
+	return ""
+}
+
+// hasAttr reports whether an element has an attribute.
+func hasAttr(node *html.Node, key string) bool {
+	for _, attribute := range node.Attr {
+		if attribute.Key == key {
+			return true
+		}
+	}
+	return false
+}
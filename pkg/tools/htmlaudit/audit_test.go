@@ -0,0 +1,80 @@
+package htmlaudit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const page = `<!DOCTYPE html>
+<html><head><title>Digest</title><style>p { color: #eee }</style></head>
+<body>
+<h1>dictyBase Community Digest</h1>
+<img src="logo.png">
+<img src="divider.png" alt="">
+<h3>New Literature</h3>
+<h2><img src="icon.png" alt=""></h2>
+<p style="color: #999999">2 new GO annotations</p>
+<p style="color:#767676">Dark enough</p>
+<div style="background: #003366 url(bg.png)"><p style="color: white">Over an image</p></div>
+<div style="background-color: navy"><span style="color: rgba(255, 255, 255, 0.4)">Faint</span></div>
+<p style="color: #949494; font-size: 24px">Large gray</p>
+<p style="color: var(--muted)">Themed</p>
+<p>Unstyled</p>
+<input type="image" src="go.png" aria-label="Search">
+<script>document.write("<img src=x>")</script>
+</body></html>`
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+	report, err := Audit(page, LevelAA)
+	require.NoError(t, err)
+	assert.Equal(t, 4, report.Images)
+	assert.Equal(t, 3, report.Headings)
+	assert.Equal(t, 4, report.StyledText, "text over images, themed and unstyled text are not checked")
+	assert.Equal(t, 3, report.Errors)
+	assert.Equal(t, 2, report.Warnings)
+
+	rules := make([]string, 0, len(report.Findings))
+	for _, finding := range report.Findings {
+		rules = append(rules, finding.Rule)
+	}
+	assert.Equal(t, []string{RuleImageAlt, RuleHeadingOrder, RuleEmptyHeading, RuleContrast, RuleContrast}, rules)
+	assert.Equal(t, `<img src="logo.png">`, report.Findings[0].Element)
+	assert.Equal(t, "h3 follows h1, skipping h2", report.Findings[1].Message)
+	assert.Equal(t, "New Literature", report.Findings[1].Text)
+
+	gray := report.Findings[3]
+	assert.Equal(t, "2 new GO annotations", gray.Text)
+	assert.InDelta(t, 2.85, gray.Contrast, 0.001)
+	assert.InDelta(t, 4.5, gray.Required, 0.001)
+	assert.Equal(t, "#999999", gray.Foreground)
+	assert.Equal(t, "#ffffff", gray.Background)
+
+	faint := report.Findings[4]
+	assert.Equal(t, "Faint", faint.Text)
+	assert.Equal(t, "#6666b3", faint.Foreground, "translucent text is blended with its background")
+	assert.Equal(t, "#000080", faint.Background)
+
+	strict, err := Audit(page, LevelAAA)
+	require.NoError(t, err)
+	assert.Equal(t, 5, strict.Errors, "AAA asks more of the darker gray and of large text")
+}
+
+func TestParseStyle(t *testing.T) {
+	t.Parallel()
+	st := parseStyle("COLOR: Red !important; font-size: 14pt; font-weight: 700; background: none")
+	require.NotNil(t, st.color)
+	assert.Equal(t, "#ff0000", st.color.hex())
+	assert.Nil(t, st.background)
+	assert.False(t, st.backgroundUnknown)
+	assert.InDelta(t, 18.67, st.fontSize, 0.01)
+	require.NotNil(t, st.bold)
+	assert.True(t, *st.bold)
+
+	st = parseStyle("background: rgb(0, 51, 102) no-repeat; font-size: 1.5em")
+	require.NotNil(t, st.background)
+	assert.Equal(t, "#003366", st.background.hex())
+	assert.InDelta(t, 24.0, st.fontSize, 0.01)
+}
@@ -0,0 +1,183 @@
+package htmlaudit
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Contrast levels of the Web Content Accessibility Guidelines.
+const (
+	LevelAA  = "AA"
+	LevelAAA = "AAA"
+)
+
+// minContrast is the lowest contrast ratio of text at each level, for
+// normal and for large text.
+var minContrast = map[string][2]float64{
+	LevelAA:  {4.5, 3},
+	LevelAAA: {7, 4.5},
+}
+
+// rgba is a color with channels from 0 to 255 and an alpha from 0 to 1.
+type rgba struct {
+	r, g, b, a float64
+}
+
+// white and black are the default background and text colors of browsers.
+var (
+	white = rgba{255, 255, 255, 1}
+	black = rgba{0, 0, 0, 1}
+)
+
+// namedColors are the CSS color keywords inline styles commonly use.
+var namedColors = map[string]rgba{
+	"black":       black,
+	"white":       white,
+	"silver":      {192, 192, 192, 1},
+	"gray":        {128, 128, 128, 1},
+	"grey":        {128, 128, 128, 1},
+	"darkgray":    {169, 169, 169, 1},
+	"darkgrey":    {169, 169, 169, 1},
+	"lightgray":   {211, 211, 211, 1},
+	"lightgrey":   {211, 211, 211, 1},
+	"gainsboro":   {220, 220, 220, 1},
+	"whitesmoke":  {245, 245, 245, 1},
+	"maroon":      {128, 0, 0, 1},
+	"red":         {255, 0, 0, 1},
+	"darkred":     {139, 0, 0, 1},
+	"purple":      {128, 0, 128, 1},
+	"fuchsia":     {255, 0, 255, 1},
+	"magenta":     {255, 0, 255, 1},
+	"pink":        {255, 192, 203, 1},
+	"green":       {0, 128, 0, 1},
+	"darkgreen":   {0, 100, 0, 1},
+	"lime":        {0, 255, 0, 1},
+	"lightgreen":  {144, 238, 144, 1},
+	"olive":       {128, 128, 0, 1},
+	"yellow":      {255, 255, 0, 1},
+	"lightyellow": {255, 255, 224, 1},
+	"gold":        {255, 215, 0, 1},
+	"orange":      {255, 165, 0, 1},
+	"brown":       {165, 42, 42, 1},
+	"navy":        {0, 0, 128, 1},
+	"blue":        {0, 0, 255, 1},
+	"darkblue":    {0, 0, 139, 1},
+	"lightblue":   {173, 216, 230, 1},
+	"teal":        {0, 128, 128, 1},
+	"aqua":        {0, 255, 255, 1},
+	"cyan":        {0, 255, 255, 1},
+	"transparent": {0, 0, 0, 0},
+}
+
+// parseColor reads a CSS color: a keyword, #rgb, #rrggbb, #rgba, #rrggbbaa,
+// rgb() or rgba(). It reports false for anything else, such as var().
+func parseColor(value string) (rgba, bool) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if color, ok := namedColors[value]; ok {
+		return color, true
+	}
+	if hex, ok := strings.CutPrefix(value, "#"); ok {
+		return parseHex(hex)
+	}
+	for _, prefix := range []string{"rgba(", "rgb("} {
+		if args, ok := strings.CutPrefix(value, prefix); ok && strings.HasSuffix(args, ")") {
+			return parseRGB(strings.TrimSuffix(args, ")"))
+		}
+	}
+	return rgba{}, false
+}
+
+// parseHex reads the digits of a hexadecimal color.
+func parseHex(hex string) (rgba, bool) {
+	if len(hex) == 3 || len(hex) == 4 {
+		var long strings.Builder
+		for _, digit := range hex {
+			long.WriteRune(digit)
+			long.WriteRune(digit)
+		}
+		hex = long.String()
+	}
+	if len(hex) != 6 && len(hex) != 8 {
+		return rgba{}, false
+	}
+	value, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return rgba{}, false
+	}
+	alpha := 1.0
+	if len(hex) == 8 {
+		alpha = float64(value&0xff) / 255
+		value >>= 8
+	}
+	return rgba{float64(value >> 16 & 0xff), float64(value >> 8 & 0xff), float64(value & 0xff), alpha}, true
+}
+
+// parseRGB reads the arguments of rgb() or rgba(), separated by commas or,
+// in the modern syntax, by spaces and a slash before the alpha.
+func parseRGB(args string) (rgba, bool) {
+	fields := strings.FieldsFunc(args, func(r rune) bool {
+		return r == ',' || r == '/' || r == ' '
+	})
+	if len(fields) != 3 && len(fields) != 4 {
+		return rgba{}, false
+	}
+	var channels [4]float64
+	channels[3] = 1
+	for i, field := range fields {
+		percent := strings.HasSuffix(field, "%")
+		number, err := strconv.ParseFloat(strings.TrimSuffix(field, "%"), 64)
+		if err != nil {
+			return rgba{}, false
+		}
+		switch {
+		case percent && i < 3:
+			number *= 2.55
+		case percent:
+			number /= 100
+		}
+		channels[i] = number
+	}
+	clamp := func(value, limit float64) float64 {
+		return math.Max(0, math.Min(limit, value))
+	}
+	return rgba{
+		clamp(channels[0], 255), clamp(channels[1], 255), clamp(channels[2], 255), clamp(channels[3], 1),
+	}, true
+}
+
+// over returns the color seen when c is drawn on an opaque background.
+func (c rgba) over(background rgba) rgba {
+	blend := func(top, bottom float64) float64 {
+		return c.a*top + (1-c.a)*bottom
+	}
+	return rgba{blend(c.r, background.r), blend(c.g, background.g), blend(c.b, background.b), 1}
+}
+
+// hex returns the color as #rrggbb, ignoring its alpha.
+func (c rgba) hex() string {
+	return fmt.Sprintf("#%02x%02x%02x", int(math.Round(c.r)), int(math.Round(c.g)), int(math.Round(c.b)))
+}
+
+// luminance returns the relative luminance of an opaque color.
+func (c rgba) luminance() float64 {
+	linear := func(channel float64) float64 {
+		channel /= 255
+		if channel <= 0.03928 {
+			return channel / 12.92
+		}
+		return math.Pow((channel+0.055)/1.055, 2.4)
+	}
+	return 0.2126*linear(c.r) + 0.7152*linear(c.g) + 0.0722*linear(c.b)
+}
+
+// contrastRatio returns the contrast ratio of two opaque colors, from 1 to
+// 21, rounded to two decimals.
+func contrastRatio(a, b rgba) float64 {
+	lighter, darker := a.luminance(), b.luminance()
+	if darker > lighter {
+		lighter, darker = darker, lighter
+	}
+	return math.Round((lighter+0.05)/(darker+0.05)*100) / 100
+}
@@ -0,0 +1,43 @@
+package htmlaudit
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseColor(t *testing.T) {
+	t.Parallel()
+	for value, want := range map[string]rgba{
+		"#777":               {119, 119, 119, 1},
+		"#FFCC00":            {255, 204, 0, 1},
+		"#00000080":          {0, 0, 0, 128.0 / 255},
+		"rgb(255, 0, 0)":     {255, 0, 0, 1},
+		"rgba(0,0,0,0.5)":    {0, 0, 0, 0.5},
+		"rgb(0 0 255 / 50%)": {0, 0, 255, 0.5},
+		"rgb(100%, 50%, 0%)": {255, 127.5, 0, 1},
+		" Navy ":             {0, 0, 128, 1},
+		"transparent":        {0, 0, 0, 0},
+	} {
+		color, ok := parseColor(value)
+		assert.True(t, ok, value)
+		assert.InDelta(t, want.r, color.r, 0.01, value)
+		assert.InDelta(t, want.g, color.g, 0.01, value)
+		assert.InDelta(t, want.b, color.b, 0.01, value)
+		assert.InDelta(t, want.a, color.a, 0.01, value)
+	}
+	for _, value := range []string{"var(--text)", "#12345", "rgb(1, 2)", "inherit", "#ggg"} {
+		_, ok := parseColor(value)
+		assert.False(t, ok, value)
+	}
+}
+
+func TestContrastRatio(t *testing.T) {
+	t.Parallel()
+	assert.InDelta(t, 21.0, contrastRatio(black, white), 0.001)
+	assert.InDelta(t, 1.0, contrastRatio(white, white), 0.001)
+	gray, _ := parseColor("#777")
+	assert.InDelta(t, 4.48, contrastRatio(white, gray), 0.001)
+	half := rgba{0, 0, 0, 0.5}.over(white)
+	assert.Equal(t, "#808080", half.hex())
+}
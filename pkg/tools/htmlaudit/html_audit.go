@@ -0,0 +1,156 @@
+package htmlaudit
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// HTMLAuditTool checks rendered HTML for accessibility problems.
+type HTMLAuditTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	uploads     *upload.Store
+}
+
+// Option defines a functional option for configuring HTMLAuditTool.
+type Option func(*HTMLAuditTool)
+
+// WithUploads lets calls pass the HTML as a completed upload.
+func WithUploads(store *upload.Store) Option {
+	return func(h *HTMLAuditTool) {
+		h.uploads = store
+	}
+}
+
+// AuditRequest represents the parameters for an audit.
+type AuditRequest struct {
+	HTML  string `validate:"required"`
+	Level string `validate:"required,oneof=AA AAA"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"html-audit",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewHTMLAuditTool(deps.Logger, WithUploads(deps.Uploads))
+		},
+	)
+}
+
+// NewHTMLAuditTool creates a new HTMLAuditTool instance.
+func NewHTMLAuditTool(logger *slog.Logger, opts ...Option) (*HTMLAuditTool, error) {
+	tool := mcp.NewTool(
+		"html-audit",
+		mcp.WithDescription(
+			"Checks rendered HTML, such as a digest or a converted markdown document, for images without alt "+
+				"text, empty headings, headings that skip a level, and inline styles giving text too little "+
+				"contrast, returning the findings before the page is published",
+		),
+		mcp.WithTitleAnnotation("HTML Accessibility Audit"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString(
+			"html",
+			mcp.Description("The HTML document or fragment to check"),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the HTML, instead of html"),
+		),
+		mcp.WithString(
+			"level",
+			mcp.Description(
+				"WCAG level text contrast is checked against: AA (default) asks 4.5:1 of normal text and 3:1 "+
+					"of large text, AAA 7:1 and 4.5:1",
+			),
+			mcp.Enum(LevelAA, LevelAAA),
+		),
+	)
+	auditTool := &HTMLAuditTool{
+		Name:        "html-audit",
+		Description: "Checks rendered HTML for accessibility problems",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(auditTool)
+	}
+	return auditTool, nil
+}
+
+// GetName returns the name of the tool.
+func (h *HTMLAuditTool) GetName() string {
+	return h.Name
+}
+
+// GetDescription returns the description of the tool.
+func (h *HTMLAuditTool) GetDescription() string {
+	return h.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (h *HTMLAuditTool) GetSchema() mcp.ToolInputSchema {
+	return h.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (h *HTMLAuditTool) GetTool() mcp.Tool {
+	return h.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (h *HTMLAuditTool) GetAnnotations() mcp.ToolAnnotation {
+	return h.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (h *HTMLAuditTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Check a digest page before publishing it to the dictyBase site",
+			Arguments: map[string]any{
+				"html": "<h1>dictyBase Community Digest</h1><h3>New Literature</h3>" +
+					"<img src=\"chemotaxis.png\"><p style=\"color:#999\">2 new GO annotations</p>",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (h *HTMLAuditTool) Handler(
+	_ context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	source, err := upload.TextArgument(h.uploads, request, "html")
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	params := AuditRequest{
+		HTML:  source,
+		Level: request.GetString("level", LevelAA),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	report, err := Audit(params.HTML, params.Level)
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_HTML", err, "invalid HTML")), nil
+	}
+	h.Logger.Debug("audited HTML", "errors", report.Errors, "warnings", report.Warnings)
+	return mcp.NewToolResultStructured(report, RenderMarkdown(report)), nil
+}
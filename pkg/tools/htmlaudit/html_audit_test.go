@@ -0,0 +1,64 @@
+package htmlaudit
+
+import (
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool, err := NewHTMLAuditTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "html-audit", arguments)
+}
+
+func TestNewHTMLAuditTool(t *testing.T) {
+	t.Parallel()
+	tool, err := NewHTMLAuditTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "html-audit", tool.GetName())
+	annotations := tool.GetAnnotations()
+	assert.True(t, *annotations.ReadOnlyHint)
+	assert.False(t, *annotations.OpenWorldHint)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	result := callTool(t, map[string]any{
+		"html": "<h1>Digest</h1><h3>Literature</h3><img src=\"a|b.png\"><p style=\"color:#999\">Faded</p>",
+	})
+	require.False(t, result.IsError)
+	report, ok := result.StructuredContent.(Report)
+	require.True(t, ok, "the report is the structured content")
+	assert.Equal(t, 2, report.Errors)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	for _, want := range []string{
+		"Checked 1 image, 2 headings and 1 styled text element against WCAG AA contrast.",
+		"3 findings: 2 errors, 1 warning.",
+		"| error | image-alt | `<img src=\"a\\|b.png\">` | Image has no alt attribute",
+		"| warning | heading-order | `<h3>` | h3 follows h1, skipping h2: \"Literature\" |",
+		"| error | color-contrast | `<p style=\"color:#999\">` | Contrast of 2.85:1 is below the 4.5:1 level AA " +
+			"asks of normal text: \"Faded\" (#999999 on #ffffff) |",
+	} {
+		assert.Contains(t, text.Text, want)
+	}
+
+	result = callTool(t, map[string]any{"html": "<h1>Digest</h1><img src=\"logo.png\" alt=\"dictyBase\">"})
+	text, ok = mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "No accessibility problems found.")
+
+	result = callTool(t, map[string]any{"html": "<p>text</p>", "level": "A"})
+	require.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+}
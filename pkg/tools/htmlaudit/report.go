@@ -0,0 +1,64 @@
+package htmlaudit
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderMarkdown renders the report as a markdown table of findings.
+func RenderMarkdown(report Report) string {
+	var builder strings.Builder
+	builder.WriteString("# HTML Audit\n\n")
+	fmt.Fprintf(
+		&builder,
+		"Checked %s, %s and %s against WCAG %s contrast.\n\n",
+		count(report.Images, "image"),
+		count(report.Headings, "heading"),
+		count(report.StyledText, "styled text element"),
+		report.Level,
+	)
+	if len(report.Findings) == 0 {
+		builder.WriteString("No accessibility problems found.\n")
+		return builder.String()
+	}
+	fmt.Fprintf(
+		&builder,
+		"%s: %s, %s.\n\n",
+		count(len(report.Findings), "finding"),
+		count(report.Errors, "error"),
+		count(report.Warnings, "warning"),
+	)
+	builder.WriteString("| Severity | Rule | Element | Finding |\n")
+	builder.WriteString("|----------|------|---------|---------|\n")
+	for _, finding := range report.Findings {
+		message := finding.Message
+		if finding.Text != "" {
+			message += fmt.Sprintf(": %q", finding.Text)
+		}
+		if finding.Foreground != "" {
+			message += fmt.Sprintf(" (%s on %s)", finding.Foreground, finding.Background)
+		}
+		fmt.Fprintf(
+			&builder,
+			"| %s | %s | `%s` | %s |\n",
+			finding.Severity,
+			finding.Rule,
+			escapeCell(strings.ReplaceAll(finding.Element, "`", "'")),
+			escapeCell(message),
+		)
+	}
+	return builder.String()
+}
+
+// count renders a number of things, such as "1 image" or "2 images".
+func count(number int, noun string) string {
+	if number == 1 {
+		return fmt.Sprintf("%d %s", number, noun)
+	}
+	return fmt.Sprintf("%d %ss", number, noun)
+}
+
+// escapeCell keeps text from breaking a markdown table.
+func escapeCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "|", `\|`), "\n", " ")
+}
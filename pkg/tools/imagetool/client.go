@@ -0,0 +1,320 @@
+package imagetool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+// maxTagPages caps the pages of tags read from a registry.
+const maxTagPages = 10
+
+// tagPageSize is the number of tags asked for per page.
+const tagPageSize = 1000
+
+var (
+	// ErrNotFound is returned when the registry does not know the
+	// repository, tag or digest.
+	ErrNotFound = errors.New("not found in the registry")
+	// ErrUnauthorized is returned when the registry refuses anonymous
+	// access, as it does for private images.
+	ErrUnauthorized = errors.New("the registry requires credentials")
+)
+
+// challengeParamRegex matches the key="value" pairs of a
+// WWW-Authenticate challenge.
+var challengeParamRegex = regexp.MustCompile(`(\w+)="([^"]*)"`)
+
+// manifestAccept lists the manifest media types the client understands.
+var manifestAccept = strings.Join([]string{
+	MediaTypeOCIIndex,
+	MediaTypeDockerManifestList,
+	MediaTypeOCIManifest,
+	MediaTypeDockerManifest,
+}, ", ")
+
+// RegistryClient reads images anonymously through the OCI distribution API
+// spoken by Docker Hub, GHCR and other registries.
+type RegistryClient struct {
+	httpClient *http.Client
+	scheme     string
+	logger     *slog.Logger
+}
+
+// Option represents a configuration option for RegistryClient.
+type Option func(*Config)
+
+// Config holds the configuration for the registry client.
+type Config struct {
+	timeout   time.Duration
+	plainHTTP bool
+	logger    *slog.Logger
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithPlainHTTP talks to registries over HTTP instead of HTTPS.
+func WithPlainHTTP() Option {
+	return func(c *Config) {
+		c.plainHTTP = true
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewRegistryClient creates a client for container registries.
+func NewRegistryClient(opts ...Option) *RegistryClient {
+	cfg := &Config{
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	scheme := "https"
+	if cfg.plainHTTP {
+		scheme = "http"
+	}
+	return &RegistryClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		scheme:     scheme,
+		logger:     cfg.logger,
+	}
+}
+
+// Session reads one repository, reusing the token of its first
+// authentication for later requests.
+type Session struct {
+	client *RegistryClient
+	host   string
+	repo   string
+	token  string
+}
+
+// Session starts reading the repository of ref.
+func (c *RegistryClient) Session(ref Reference) *Session {
+	return &Session{client: c, host: ref.Host(), repo: ref.Repository}
+}
+
+// Manifest returns the manifest or index stored under reference, a tag or
+// digest, along with its digest.
+func (s *Session) Manifest(ctx context.Context, reference string) (Manifest, string, error) {
+	var manifest Manifest
+	header, err := s.get(ctx, "/manifests/"+reference, manifestAccept, &manifest)
+	if err != nil {
+		return Manifest{}, "", err
+	}
+	if manifest.MediaType == "" {
+		manifest.MediaType = header.Get("Content-Type")
+	}
+	digest := header.Get("Docker-Content-Digest")
+	if digest == "" && strings.Contains(reference, ":") {
+		digest = reference
+	}
+	return manifest, digest, nil
+}
+
+// Config returns the image configuration blob with the given digest.
+func (s *Session) Config(ctx context.Context, digest string) (ImageConfig, error) {
+	var config ImageConfig
+	if _, err := s.get(ctx, "/blobs/"+digest, "", &config); err != nil {
+		return ImageConfig{}, err
+	}
+	return config, nil
+}
+
+// tagList is a page of the tags of a repository.
+type tagList struct {
+	Tags []string `json:"tags"`
+}
+
+// Tags returns the tags of the repository in the order of the registry,
+// and whether there were more than the pages read.
+func (s *Session) Tags(ctx context.Context) ([]string, bool, error) {
+	var tags []string
+	path := fmt.Sprintf("/tags/list?n=%d", tagPageSize)
+	for range maxTagPages {
+		var page tagList
+		header, err := s.get(ctx, path, "", &page)
+		if err != nil {
+			return nil, false, err
+		}
+		tags = append(tags, page.Tags...)
+		next := nextPage(header.Get("Link"))
+		if next == "" {
+			return tags, false, nil
+		}
+		path = next
+	}
+	return tags, true, nil
+}
+
+// nextPage returns the path below the repository of the next page in a
+// Link header, or an empty string on the last page.
+func nextPage(link string) string {
+	target, params, found := strings.Cut(link, ";")
+	if !found || !strings.Contains(params, `rel="next"`) {
+		return ""
+	}
+	next, err := url.Parse(strings.Trim(strings.TrimSpace(target), "<>"))
+	if err != nil {
+		return ""
+	}
+	index := strings.LastIndex(next.Path, "/tags/list")
+	if index < 0 {
+		return ""
+	}
+	return next.Path[index:] + "?" + next.RawQuery
+}
+
+// get reads the JSON document at path below the repository into target and
+// returns the response headers.
+func (s *Session) get(ctx context.Context, path, accept string, target any) (http.Header, error) {
+	start := time.Now()
+	header, err := s.fetch(ctx, path, accept, target)
+	if !errors.Is(err, ErrNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceRegistry, start, err)
+	}
+	return header, err
+}
+
+// fetch performs the round trip for get, authenticating on the first
+// challenge of the registry.
+func (s *Session) fetch(ctx context.Context, path, accept string, target any) (http.Header, error) {
+	endpoint := fmt.Sprintf("%s://%s/v2/%s%s", s.client.scheme, s.host, s.repo, path)
+	resp, err := s.send(ctx, endpoint, accept)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode == http.StatusUnauthorized && s.token == "" {
+		challenge := resp.Header.Get("WWW-Authenticate")
+		resp.Body.Close()
+		if err := s.authenticate(ctx, challenge); err != nil {
+			return nil, err
+		}
+		if resp, err = s.send(ctx, endpoint, accept); err != nil {
+			return nil, err
+		}
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return nil, ErrNotFound
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		return nil, ErrUnauthorized
+	case resp.StatusCode < 200 || resp.StatusCode >= 300:
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf(
+			"registry %s returned status %d: %s",
+			s.host,
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return nil, fmt.Errorf("error reading response of registry %s: %w", s.host, err)
+	}
+	return resp.Header, nil
+}
+
+// send issues a GET request with the session token, if any.
+func (s *Session) send(ctx context.Context, endpoint, accept string) (*http.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating registry request: %w", err)
+	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
+	if s.token != "" {
+		req.Header.Set("Authorization", "Bearer "+s.token)
+	}
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling registry %s: %w", s.host, err)
+	}
+	return resp, nil
+}
+
+// tokenResponse is the answer of a registry token service.
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// authenticate fetches an anonymous pull token from the token service named
+// in a Bearer challenge.
+func (s *Session) authenticate(ctx context.Context, challenge string) error {
+	scheme, params, _ := strings.Cut(challenge, " ")
+	if !strings.EqualFold(scheme, "Bearer") {
+		return ErrUnauthorized
+	}
+	values := make(map[string]string)
+	for _, match := range challengeParamRegex.FindAllStringSubmatch(params, -1) {
+		values[strings.ToLower(match[1])] = match[2]
+	}
+	realm, err := url.Parse(values["realm"])
+	if err != nil || realm.Host == "" {
+		return fmt.Errorf("registry %s sent an invalid token realm %q", s.host, values["realm"])
+	}
+	query := realm.Query()
+	if service := values["service"]; service != "" {
+		query.Set("service", service)
+	}
+	scope := values["scope"]
+	if scope == "" {
+		scope = fmt.Sprintf("repository:%s:pull", s.repo)
+	}
+	query.Set("scope", scope)
+	realm.RawQuery = query.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, realm.String(), nil)
+	if err != nil {
+		return fmt.Errorf("error creating token request: %w", err)
+	}
+	resp, err := s.client.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error requesting a token for registry %s: %w", s.host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+		return ErrUnauthorized
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("token service of registry %s returned status %d", s.host, resp.StatusCode)
+	}
+	var token tokenResponse
+	if err := json.NewDecoder(resp.Body).Decode(&token); err != nil {
+		return fmt.Errorf("error reading token of registry %s: %w", s.host, err)
+	}
+	s.token = token.Token
+	if s.token == "" {
+		s.token = token.AccessToken
+	}
+	if s.token == "" {
+		return ErrUnauthorized
+	}
+	s.client.logger.Debug("authenticated with registry", "host", s.host, "scope", scope)
+	return nil
+}
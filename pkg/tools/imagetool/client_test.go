@@ -0,0 +1,189 @@
+package imagetool
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testToken = "pull-token"
+
+var (
+	indexDigest    = "sha256:" + strings.Repeat("1", 64)
+	amd64Digest    = "sha256:" + strings.Repeat("2", 64)
+	arm64Digest    = "sha256:" + strings.Repeat("3", 64)
+	configDigest   = "sha256:" + strings.Repeat("4", 64)
+	baseLayer      = "sha256:" + strings.Repeat("5", 64)
+	appLayer       = "sha256:" + strings.Repeat("6", 64)
+	attestDigest   = "sha256:" + strings.Repeat("7", 64)
+	privateMessage = `{"errors":[{"code":"UNAUTHORIZED"}]}`
+)
+
+// repositoryPathRegex extracts the repository from a registry API path.
+var repositoryPathRegex = regexp.MustCompile(`^/v2/(.+?)/(?:manifests|blobs|tags)/`)
+
+// registryDocuments maps the paths of the fake registry to the media type
+// and body it serves.
+func registryDocuments() map[string][2]string {
+	index := Manifest{
+		MediaType: MediaTypeOCIIndex,
+		Manifests: []Descriptor{
+			{MediaType: MediaTypeOCIManifest, Digest: amd64Digest, Platform: &Platform{OS: "linux", Architecture: "amd64"}},
+			{
+				MediaType: MediaTypeOCIManifest,
+				Digest:    arm64Digest,
+				Platform:  &Platform{OS: "linux", Architecture: "arm64", Variant: "v8"},
+			},
+			{MediaType: MediaTypeOCIManifest, Digest: attestDigest, Platform: &Platform{OS: "unknown", Architecture: "unknown"}},
+		},
+	}
+	manifest := Manifest{
+		MediaType: MediaTypeOCIManifest,
+		Config:    Descriptor{Digest: configDigest, Size: 1024},
+		Layers: []Descriptor{
+			{Digest: baseLayer, Size: 3 << 20},
+			{Digest: appLayer, Size: 1 << 20},
+		},
+	}
+	config := `{
+		"architecture": "amd64",
+		"os": "linux",
+		"created": "2026-09-30T12:00:00Z",
+		"config": {"Labels": {"org.opencontainers.image.version": "1.0.0", "org.opencontainers.image.revision": "abc123"}},
+		"history": [
+			{"created_by": "/bin/sh -c #(nop) ADD file:base in / "},
+			{"created_by": "/bin/sh -c #(nop)  CMD [\"sh\"]", "empty_layer": true},
+			{"created_by": "COPY app /usr/local/bin/app # buildkit"}
+		]
+	}`
+	encode := func(value any) string {
+		data, _ := json.Marshal(value)
+		return string(data)
+	}
+	return map[string][2]string{
+		"/v2/dictybase/app/manifests/1.0.0":             {MediaTypeOCIIndex, encode(index)},
+		"/v2/dictybase/app/manifests/" + amd64Digest:    {MediaTypeOCIManifest, encode(manifest)},
+		"/v2/dictybase/app/manifests/single":            {MediaTypeDockerManifest, encode(manifest)},
+		"/v2/dictybase/app/blobs/" + configDigest:       {"application/octet-stream", config},
+		"/v2/dictybase/app/tags/list?n=1000":            {"application/json", `{"tags": ["0.9.0", "1.0.0"]}`},
+		"/v2/dictybase/app/tags/list?last=1.0.0&n=1000": {"application/json", `{"tags": ["latest", "single"]}`},
+	}
+}
+
+// newRegistryServer starts a fake registry that hands out anonymous pull
+// tokens for the dictybase/app repository only.
+func newRegistryServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	documents := registryDocuments()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			if r.URL.Query().Get("scope") != "repository:dictybase/app:pull" {
+				http.Error(w, privateMessage, http.StatusUnauthorized)
+				return
+			}
+			_, _ = w.Write([]byte(`{"token": "` + testToken + `"}`))
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			repository := repositoryPathRegex.FindStringSubmatch(r.URL.Path)[1]
+			w.Header().Set(
+				"WWW-Authenticate",
+				`Bearer realm="`+server.URL+`/token",service="test",scope="repository:`+repository+`:pull"`,
+			)
+			http.Error(w, privateMessage, http.StatusUnauthorized)
+			return
+		}
+		document, ok := documents[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		if strings.Contains(r.URL.Path, "/manifests/") {
+			digest := amd64Digest
+			if document[0] == MediaTypeOCIIndex {
+				digest = indexDigest
+			}
+			w.Header().Set("Docker-Content-Digest", digest)
+		}
+		if r.URL.RawQuery == "n=1000" {
+			w.Header().Set("Link", `</v2/dictybase/app/tags/list?last=1.0.0&n=1000>; rel="next"`)
+		}
+		w.Header().Set("Content-Type", document[0])
+		_, _ = w.Write([]byte(document[1]))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// testReference parses a reference to repository on the fake registry.
+func testReference(t *testing.T, server *httptest.Server, reference string) Reference {
+	t.Helper()
+	ref, err := ParseReference(strings.TrimPrefix(server.URL, "http://") + "/" + reference)
+	require.NoError(t, err)
+	return ref
+}
+
+func TestSession_Manifest(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	session := NewRegistryClient(WithPlainHTTP()).Session(testReference(t, server, "dictybase/app:1.0.0"))
+
+	index, digest, err := session.Manifest(context.Background(), "1.0.0")
+	require.NoError(t, err)
+	assert.True(t, index.IsIndex())
+	assert.Equal(t, indexDigest, digest)
+	assert.Len(t, index.Manifests, 3)
+
+	manifest, digest, err := session.Manifest(context.Background(), amd64Digest)
+	require.NoError(t, err)
+	assert.False(t, manifest.IsIndex())
+	assert.Equal(t, amd64Digest, digest)
+	assert.Equal(t, configDigest, manifest.Config.Digest)
+
+	config, err := session.Config(context.Background(), configDigest)
+	require.NoError(t, err)
+	assert.Equal(t, "1.0.0", config.Config.Labels["org.opencontainers.image.version"])
+	assert.Len(t, config.History, 3)
+
+	_, _, err = session.Manifest(context.Background(), "missing")
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestSession_Tags(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	session := NewRegistryClient(WithPlainHTTP()).Session(testReference(t, server, "dictybase/app"))
+
+	tags, truncated, err := session.Tags(context.Background())
+	require.NoError(t, err)
+	assert.False(t, truncated)
+	assert.Equal(t, []string{"0.9.0", "1.0.0", "latest", "single"}, tags)
+}
+
+func TestSession_Unauthorized(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	session := NewRegistryClient(WithPlainHTTP()).Session(testReference(t, server, "dictybase/private"))
+
+	_, _, err := session.Manifest(context.Background(), "latest")
+	require.ErrorIs(t, err, ErrUnauthorized)
+}
+
+func TestNextPage(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		"/tags/list?last=b&n=100",
+		nextPage(`</v2/dictybase/app/tags/list?last=b&n=100>; rel="next"`),
+	)
+	assert.Empty(t, nextPage(""))
+	assert.Empty(t, nextPage(`</v2/dictybase/app/tags/list?last=b>; rel="prev"`))
+}
@@ -0,0 +1,308 @@
+package imagetool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+const (
+	defaultPlatform = "linux/amd64"
+	defaultTagLimit = 50
+)
+
+// ImageTool inspects container images in a registry.
+type ImageTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	resources     *resources.Catalog
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring ImageTool.
+type ToolOption func(*ImageTool)
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(i *ImageTool) {
+		i.resources = catalog
+	}
+}
+
+// WithClientOptions sets options for the registry clients the tool
+// creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(i *ImageTool) {
+		i.clientOptions = append(i.clientOptions, opts...)
+	}
+}
+
+// ImageRequest represents the parameters for an image inspection.
+type ImageRequest struct {
+	Image    string `validate:"required"`
+	Platform string `validate:"required"`
+	TagLimit int    `validate:"gte=0,lte=1000"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"image-inspect",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewImageTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewImageTool creates a new ImageTool instance.
+func NewImageTool(logger *slog.Logger, opts ...ToolOption) (*ImageTool, error) {
+	tool := mcp.NewTool(
+		"image-inspect",
+		mcp.WithDescription(
+			"Inspects a public container image through the registry API and reports its digest, size, "+
+				"layers, build labels and the tags of its repository, for writing release notes",
+		),
+		mcp.WithTitleAnnotation("Image Inspect"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"image",
+			mcp.Required(),
+			mcp.Description(
+				"The image reference, e.g. 'dictybase/modware-annotation:1.2.0' or "+
+					"'ghcr.io/dictybase/graphql-server@sha256:...'; the tag defaults to latest",
+			),
+		),
+		mcp.WithString(
+			"platform",
+			mcp.Description("Platform inspected in a multi-platform image, defaults to 'linux/amd64'"),
+		),
+		mcp.WithNumber(
+			"tag_limit",
+			mcp.Description("Maximum number of repository tags listed, defaults to 50; 0 skips the tags"),
+		),
+	)
+	imageTool := &ImageTool{
+		Name:        "image-inspect",
+		Description: "Inspects container images in a registry",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(imageTool)
+	}
+	return imageTool, nil
+}
+
+// GetName returns the name of the tool.
+func (i *ImageTool) GetName() string {
+	return i.Name
+}
+
+// GetDescription returns the description of the tool.
+func (i *ImageTool) GetDescription() string {
+	return i.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (i *ImageTool) GetSchema() mcp.ToolInputSchema {
+	return i.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (i *ImageTool) GetTool() mcp.Tool {
+	return i.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (i *ImageTool) GetAnnotations() mcp.ToolAnnotation {
+	return i.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (i *ImageTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Inspect an image on Docker Hub for linux/amd64",
+			Arguments: map[string]any{
+				"image":    "dictybase/modware-annotation:latest",
+				"platform": "linux/amd64",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (i *ImageTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := ImageRequest{
+		Image:    request.GetString("image", ""),
+		Platform: request.GetString("platform", defaultPlatform),
+		TagLimit: request.GetInt("tag_limit", defaultTagLimit),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	inspection, err := i.Inspect(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+
+	content := RenderMarkdown(inspection)
+	result := provenance.Attach(
+		mcp.NewToolResultText(content),
+		provenance.New([]string{metrics.ServiceRegistry}),
+	)
+	if i.resources != nil {
+		name := strings.ReplaceAll(inspection.Reference.Repository, "/", "-") + "-" + inspection.Reference.Ref()
+		resource, err := i.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        strings.ReplaceAll(name, ":", "-") + "-image.md",
+			MIMEType:    "text/markdown",
+			Description: "Container image inspection for " + inspection.Reference.String(),
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing report: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// Inspect reads the manifest, configuration and tags of the image. For a
+// multi-platform image the manifest of the requested platform is read.
+func (i *ImageTool) Inspect(ctx context.Context, params ImageRequest) (Inspection, error) {
+	ref, err := ParseReference(params.Image)
+	if err != nil {
+		return Inspection{}, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_REFERENCE", err, "invalid image")
+	}
+	wanted, err := parsePlatform(params.Platform)
+	if err != nil {
+		return Inspection{}, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_PLATFORM", err, "invalid platform")
+	}
+	client := NewRegistryClient(append([]Option{WithLogger(i.Logger)}, i.clientOptions...)...)
+	session := client.Session(ref)
+
+	manifest, digest, err := session.Manifest(ctx, ref.Ref())
+	if err != nil {
+		return Inspection{}, registryError(ref, err)
+	}
+	inspection := Inspection{Reference: ref, Digest: digest}
+	if manifest.IsIndex() {
+		descriptor, platforms, found := selectPlatform(manifest.Manifests, wanted)
+		inspection.Platforms = platforms
+		if !found {
+			return Inspection{}, toolerror.New(
+				toolerror.TypeNotFound,
+				"PLATFORM_NOT_FOUND",
+				fmt.Sprintf("%s has no %s image; it has %s", ref, wanted, joinPlatforms(platforms)),
+			)
+		}
+		if manifest, inspection.ManifestDigest, err = session.Manifest(ctx, descriptor.Digest); err != nil {
+			return Inspection{}, registryError(ref, err)
+		}
+		inspection.Platform = *descriptor.Platform
+	}
+	config, err := session.Config(ctx, manifest.Config.Digest)
+	if err != nil {
+		return Inspection{}, registryError(ref, err)
+	}
+	if inspection.Platform.OS == "" {
+		inspection.Platform = Platform{OS: config.OS, Architecture: config.Architecture}
+	}
+	inspection.Created = config.Created
+	inspection.Labels = config.Config.Labels
+	inspection.Layers = ImageLayers(manifest, config)
+	inspection.Size = manifest.Config.Size
+	for _, layer := range manifest.Layers {
+		inspection.Size += layer.Size
+	}
+
+	if params.TagLimit > 0 {
+		tags, truncated, err := session.Tags(ctx)
+		if err != nil {
+			return Inspection{}, registryError(ref, err)
+		}
+		inspection.TagsListed = true
+		inspection.TagCount = len(tags)
+		inspection.TagsTruncated = truncated
+		inspection.Tags = tags[:min(len(tags), params.TagLimit)]
+	}
+	i.Logger.Debug("inspected image", "reference", ref.String(), "digest", digest)
+	return inspection, nil
+}
+
+// registryError classifies a failed registry call.
+func registryError(ref Reference, err error) error {
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return toolerror.New(toolerror.TypeNotFound, "IMAGE_NOT_FOUND", fmt.Sprintf("%s was not found", ref))
+	case errors.Is(err, ErrUnauthorized):
+		return toolerror.New(
+			toolerror.TypeNotFound,
+			"IMAGE_NOT_ACCESSIBLE",
+			fmt.Sprintf("%s does not exist or is private; only public images can be inspected", ref),
+		)
+	default:
+		return toolerror.Upstream(metrics.ServiceRegistry, err, "failed to read image")
+	}
+}
+
+// parsePlatform parses an os/architecture[/variant] platform.
+func parsePlatform(platform string) (Platform, error) {
+	parts := strings.Split(platform, "/")
+	if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+		return Platform{}, fmt.Errorf("expected os/architecture[/variant], got %q", platform)
+	}
+	parsed := Platform{OS: parts[0], Architecture: parts[1]}
+	if len(parts) == 3 {
+		parsed.Variant = parts[2]
+	}
+	return parsed, nil
+}
+
+// selectPlatform returns the manifest of the wanted platform in an index
+// along with all image platforms of the index. Attestation manifests,
+// listed with an unknown platform, are skipped. A wanted platform without
+// a variant matches any variant.
+func selectPlatform(manifests []Descriptor, wanted Platform) (Descriptor, []Platform, bool) {
+	var (
+		platforms []Platform
+		selected  Descriptor
+		found     bool
+	)
+	for _, descriptor := range manifests {
+		if descriptor.Platform == nil || descriptor.Platform.OS == "unknown" {
+			continue
+		}
+		platform := *descriptor.Platform
+		platforms = append(platforms, platform)
+		if !found && platform.OS == wanted.OS && platform.Architecture == wanted.Architecture &&
+			(wanted.Variant == "" || platform.Variant == wanted.Variant) {
+			selected, found = descriptor, true
+		}
+	}
+	return selected, platforms, found
+}
@@ -0,0 +1,83 @@
+package imagetool
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T) *ImageTool {
+	t.Helper()
+	tool, err := NewImageTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		WithClientOptions(WithPlainHTTP()),
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestHandler_Index(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+	result := tooltest.Call(t, newTestTool(t).Handler, "image-inspect", map[string]any{
+		"image": host + "/dictybase/app:1.0.0", "tag_limit": 3,
+	})
+	require.False(t, result.IsError)
+	report := tooltest.Text(t, result)
+	assert.Contains(t, report, "**Digest:** `"+indexDigest+"`")
+	assert.Contains(t, report, "**Platform manifest:** `"+amd64Digest+"`")
+	assert.Contains(t, report, "**Platform:** linux/amd64 (of linux/amd64, linux/arm64/v8)")
+	assert.Contains(t, report, "**Size:** 4.0 MiB compressed in 2 layers")
+	assert.Contains(t, report, "| `org.opencontainers.image.revision` | abc123 |")
+	assert.Contains(t, report, "| 2 | 1.0 MiB | `sha256:666666666666` | COPY app /usr/local/bin/app # buildkit |")
+	assert.Contains(t, report, "3 of 4 tags: `0.9.0`, `1.0.0`, `latest`")
+}
+
+func TestHandler_SingleManifest(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+	result := tooltest.Call(t, newTestTool(t).Handler, "image-inspect", map[string]any{
+		"image": host + "/dictybase/app:single", "tag_limit": 0,
+	})
+	require.False(t, result.IsError)
+	report := tooltest.Text(t, result)
+	assert.Contains(t, report, "**Platform:** linux/amd64\n")
+	assert.NotContains(t, report, "Platform manifest")
+	assert.NotContains(t, report, "## Tags")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	server := newRegistryServer(t)
+	host := strings.TrimPrefix(server.URL, "http://")
+	tool := newTestTool(t)
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		code      string
+	}{
+		{"missing image", map[string]any{}, "INVALID_INPUT"},
+		{"invalid reference", map[string]any{"image": "Dictybase/App"}, "INVALID_REFERENCE"},
+		{"invalid platform", map[string]any{"image": "dictybase/app", "platform": "linux"}, "INVALID_PLATFORM"},
+		{"unknown tag", map[string]any{"image": host + "/dictybase/app:2.0.0"}, "IMAGE_NOT_FOUND"},
+		{"private image", map[string]any{"image": host + "/dictybase/private"}, "IMAGE_NOT_ACCESSIBLE"},
+		{
+			"missing platform",
+			map[string]any{"image": host + "/dictybase/app:1.0.0", "platform": "windows/amd64"},
+			"PLATFORM_NOT_FOUND",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.code, tooltest.ErrorCode(t, tooltest.Call(t, tool.Handler, "image-inspect", tt.arguments)))
+		})
+	}
+}
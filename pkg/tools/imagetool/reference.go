@@ -0,0 +1,100 @@
+package imagetool
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// dockerHub is the registry of references without a registry host.
+	dockerHub = "docker.io"
+	// dockerHubAPI is the host serving the Docker Hub registry API.
+	dockerHubAPI = "registry-1.docker.io"
+	defaultTag   = "latest"
+)
+
+var (
+	// repositoryRegex matches a repository path: lowercase components
+	// separated by slashes.
+	repositoryRegex = regexp.MustCompile(`^[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*(?:/[a-z0-9]+(?:(?:[._]|__|-+)[a-z0-9]+)*)*$`)
+	tagRegex        = regexp.MustCompile(`^\w[\w.-]{0,127}$`)
+	digestRegex     = regexp.MustCompile(`^[a-z0-9]+(?:[.+_-][a-z0-9]+)*:[a-f0-9]{32,}$`)
+)
+
+// Reference is a parsed image reference such as
+// ghcr.io/dictybase/modware-annotation:1.2.0.
+type Reference struct {
+	// Registry is the registry host as written, docker.io by default.
+	Registry   string
+	Repository string
+	// Tag is empty when the reference has a digest only.
+	Tag    string
+	Digest string
+}
+
+// ParseReference parses an image reference the way docker pull does:
+// references without a registry host are on Docker Hub, where single name
+// repositories are official images under library/, and the tag defaults to
+// latest.
+func ParseReference(reference string) (Reference, error) {
+	remainder := strings.TrimSpace(reference)
+	var ref Reference
+	if name, digest, found := strings.Cut(remainder, "@"); found {
+		if !digestRegex.MatchString(digest) {
+			return Reference{}, fmt.Errorf("invalid digest %q in %q", digest, reference)
+		}
+		remainder, ref.Digest = name, digest
+	}
+	if index := strings.LastIndex(remainder, ":"); index > strings.LastIndex(remainder, "/") {
+		ref.Tag = remainder[index+1:]
+		remainder = remainder[:index]
+		if !tagRegex.MatchString(ref.Tag) {
+			return Reference{}, fmt.Errorf("invalid tag %q in %q", ref.Tag, reference)
+		}
+	}
+	ref.Registry = dockerHub
+	if host, repository, found := strings.Cut(remainder, "/"); found &&
+		(strings.ContainsAny(host, ".:") || host == "localhost") {
+		ref.Registry, remainder = host, repository
+	}
+	if ref.Registry == dockerHub && !strings.Contains(remainder, "/") {
+		remainder = "library/" + remainder
+	}
+	if !repositoryRegex.MatchString(remainder) {
+		return Reference{}, fmt.Errorf("invalid repository %q in %q", remainder, reference)
+	}
+	ref.Repository = remainder
+	if ref.Tag == "" && ref.Digest == "" {
+		ref.Tag = defaultTag
+	}
+	return ref, nil
+}
+
+// Host returns the host serving the registry API.
+func (r Reference) Host() string {
+	if r.Registry == dockerHub || r.Registry == "index.docker.io" {
+		return dockerHubAPI
+	}
+	return r.Registry
+}
+
+// Ref returns the digest, or the tag when there is no digest.
+func (r Reference) Ref() string {
+	if r.Digest != "" {
+		return r.Digest
+	}
+	return r.Tag
+}
+
+// String returns the fully qualified reference.
+func (r Reference) String() string {
+	reference := r.Registry + "/" + r.Repository
+	if r.Tag != "" {
+		reference += ":" + r.Tag
+	}
+	if r.Digest != "" {
+		reference += "@" + r.Digest
+	}
+	return reference
+}
@@ -0,0 +1,65 @@
+package imagetool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseReference(t *testing.T) {
+	t.Parallel()
+	digest := "sha256:" + strings.Repeat("ab", 32)
+	tests := []struct {
+		reference string
+		want      Reference
+		host      string
+	}{
+		{
+			reference: "postgres",
+			want:      Reference{Registry: "docker.io", Repository: "library/postgres", Tag: "latest"},
+			host:      "registry-1.docker.io",
+		},
+		{
+			reference: "dictybase/modware-annotation:1.2.0",
+			want:      Reference{Registry: "docker.io", Repository: "dictybase/modware-annotation", Tag: "1.2.0"},
+			host:      "registry-1.docker.io",
+		},
+		{
+			reference: "ghcr.io/dictybase/graphql-server@" + digest,
+			want:      Reference{Registry: "ghcr.io", Repository: "dictybase/graphql-server", Digest: digest},
+			host:      "ghcr.io",
+		},
+		{
+			reference: "localhost:5000/app:dev@" + digest,
+			want:      Reference{Registry: "localhost:5000", Repository: "app", Tag: "dev", Digest: digest},
+			host:      "localhost:5000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.reference, func(t *testing.T) {
+			t.Parallel()
+			ref, err := ParseReference(tt.reference)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, ref)
+			assert.Equal(t, tt.host, ref.Host())
+		})
+	}
+}
+
+func TestParseReference_Invalid(t *testing.T) {
+	t.Parallel()
+	for _, reference := range []string{"", "Dictybase/App", "app:", "app:-dev", "app@sha256:xyz", "ghcr.io/"} {
+		_, err := ParseReference(reference)
+		assert.Error(t, err, reference)
+	}
+}
+
+func TestReference_String(t *testing.T) {
+	t.Parallel()
+	ref, err := ParseReference("dictybase/app")
+	require.NoError(t, err)
+	assert.Equal(t, "docker.io/dictybase/app:latest", ref.String())
+	assert.Equal(t, "latest", ref.Ref())
+}
@@ -0,0 +1,185 @@
+package imagetool
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+)
+
+// maxCreatedBy is the length of a build step kept in the layer table.
+const maxCreatedBy = 100
+
+// Layer is a filesystem layer of an image with the build step that created
+// it, when the image history records one.
+type Layer struct {
+	Digest    string
+	Size      int64
+	CreatedBy string
+}
+
+// Inspection describes one platform image of a reference.
+type Inspection struct {
+	Reference Reference
+	// Digest is the digest of the reference, which for a multi-platform
+	// image is the digest of its index.
+	Digest string
+	// ManifestDigest is the digest of the platform manifest, set only for
+	// multi-platform images.
+	ManifestDigest string
+	Platform       Platform
+	// Platforms lists the platforms of a multi-platform image.
+	Platforms []Platform
+	Created   *time.Time
+	// Size is the compressed size of the config and layers, as pulled.
+	Size   int64
+	Labels map[string]string
+	Layers []Layer
+	// TagsListed is set when the tags of the repository were read. Tags
+	// are the tags kept; TagCount counts them all.
+	TagsListed    bool
+	Tags          []string
+	TagCount      int
+	TagsTruncated bool
+}
+
+// ImageLayers pairs the layers of a manifest with the history entries of
+// the config that produced a layer.
+func ImageLayers(manifest Manifest, config ImageConfig) []Layer {
+	var steps []string
+	for _, history := range config.History {
+		if !history.EmptyLayer {
+			steps = append(steps, history.CreatedBy)
+		}
+	}
+	layers := make([]Layer, 0, len(manifest.Layers))
+	for index, descriptor := range manifest.Layers {
+		layer := Layer{Digest: descriptor.Digest, Size: descriptor.Size}
+		if len(steps) == len(manifest.Layers) {
+			layer.CreatedBy = steps[index]
+		}
+		layers = append(layers, layer)
+	}
+	return layers
+}
+
+// RenderMarkdown renders the inspection as a markdown document.
+func RenderMarkdown(inspection Inspection) string {
+	var builder strings.Builder
+	ref := inspection.Reference
+	fmt.Fprintf(&builder, "# Image: %s\n\n", ref.Repository)
+	fmt.Fprintf(&builder, "**Reference:** `%s`\n", ref)
+	fmt.Fprintf(&builder, "**Digest:** `%s`\n", inspection.Digest)
+	if inspection.ManifestDigest != "" {
+		fmt.Fprintf(&builder, "**Platform manifest:** `%s`\n", inspection.ManifestDigest)
+	}
+	fmt.Fprintf(&builder, "**Platform:** %s", inspection.Platform)
+	if len(inspection.Platforms) > 1 {
+		fmt.Fprintf(&builder, " (of %s)", joinPlatforms(inspection.Platforms))
+	}
+	builder.WriteString("\n")
+	if inspection.Created != nil {
+		fmt.Fprintf(&builder, "**Created:** %s\n", inspection.Created.UTC().Format(time.RFC3339))
+	}
+	fmt.Fprintf(&builder, "**Size:** %s compressed in %d layers\n", formatSize(inspection.Size), len(inspection.Layers))
+
+	builder.WriteString("\n## Labels\n\n")
+	if len(inspection.Labels) == 0 {
+		builder.WriteString("The image has no labels.\n")
+	} else {
+		builder.WriteString("| Label | Value |\n|---|---|\n")
+		for _, key := range slices.Sorted(maps.Keys(inspection.Labels)) {
+			fmt.Fprintf(&builder, "| `%s` | %s |\n", key, escapeCell(inspection.Labels[key]))
+		}
+	}
+
+	if len(inspection.Layers) > 0 {
+		builder.WriteString("\n## Layers\n\n| # | Size | Digest | Created by |\n|---|---|---|---|\n")
+		for index, layer := range inspection.Layers {
+			fmt.Fprintf(&builder, "| %d | %s | `%s` | %s |\n",
+				index+1, formatSize(layer.Size), shortDigest(layer.Digest), escapeCell(truncate(layer.CreatedBy)))
+		}
+	}
+
+	if !inspection.TagsListed {
+		return builder.String()
+	}
+	builder.WriteString("\n## Tags\n\n")
+	switch {
+	case inspection.TagCount == 0:
+		builder.WriteString("The repository lists no tags.\n")
+	case len(inspection.Tags) < inspection.TagCount || inspection.TagsTruncated:
+		count := fmt.Sprint(inspection.TagCount)
+		if inspection.TagsTruncated {
+			count = "more than " + count
+		}
+		fmt.Fprintf(&builder, "%d of %s tags: %s\n", len(inspection.Tags), count, formatTags(inspection.Tags))
+	default:
+		fmt.Fprintf(&builder, "%d tags: %s\n", inspection.TagCount, formatTags(inspection.Tags))
+	}
+	return builder.String()
+}
+
+// joinPlatforms renders platforms as a comma separated list.
+func joinPlatforms(platforms []Platform) string {
+	if len(platforms) == 0 {
+		return "no image platforms"
+	}
+	names := make([]string, 0, len(platforms))
+	for _, platform := range platforms {
+		names = append(names, platform.String())
+	}
+	return strings.Join(names, ", ")
+}
+
+// formatTags renders tags as a comma separated list of code spans.
+func formatTags(tags []string) string {
+	quoted := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		quoted = append(quoted, "`"+tag+"`")
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// formatSize renders a byte count with a binary unit.
+func formatSize(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	value, exponent := float64(size)/unit, 0
+	for value >= unit && exponent < 3 {
+		value /= unit
+		exponent++
+	}
+	return fmt.Sprintf("%.1f %ciB", value, "KMGT"[exponent])
+}
+
+// shortDigest abbreviates a digest to its algorithm and first 12 hex
+// characters.
+func shortDigest(digest string) string {
+	const length = 12
+	algorithm, hex, found := strings.Cut(digest, ":")
+	if !found || len(hex) <= length {
+		return digest
+	}
+	return algorithm + ":" + hex[:length]
+}
+
+// truncate shortens a build step for the layer table, dropping the shell
+// prefix docker build records for RUN steps.
+func truncate(step string) string {
+	step = strings.Join(strings.Fields(step), " ")
+	step = strings.TrimPrefix(step, "/bin/sh -c ")
+	step = strings.TrimPrefix(step, "#(nop) ")
+	if runes := []rune(step); len(runes) > maxCreatedBy {
+		return string(runes[:maxCreatedBy]) + "…"
+	}
+	return step
+}
+
+// escapeCell keeps text from breaking a markdown table row.
+func escapeCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", `\|`)
+}
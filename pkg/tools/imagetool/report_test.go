@@ -0,0 +1,68 @@
+package imagetool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestImageLayers(t *testing.T) {
+	t.Parallel()
+	manifest := Manifest{Layers: []Descriptor{{Digest: baseLayer, Size: 10}, {Digest: appLayer, Size: 20}}}
+	config := ImageConfig{History: []History{
+		{CreatedBy: "ADD base"},
+		{CreatedBy: "ENV A=1", EmptyLayer: true},
+		{CreatedBy: "COPY app"},
+	}}
+	assert.Equal(t, []Layer{
+		{Digest: baseLayer, Size: 10, CreatedBy: "ADD base"},
+		{Digest: appLayer, Size: 20, CreatedBy: "COPY app"},
+	}, ImageLayers(manifest, config))
+
+	// History that does not line up with the layers is not attributed.
+	config.History = config.History[:1]
+	assert.Empty(t, ImageLayers(manifest, config)[0].CreatedBy)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	created := time.Date(2026, 9, 30, 12, 0, 0, 0, time.UTC)
+	inspection := Inspection{
+		Reference:      Reference{Registry: "ghcr.io", Repository: "dictybase/app", Tag: "1.0.0"},
+		Digest:         indexDigest,
+		ManifestDigest: amd64Digest,
+		Platform:       Platform{OS: "linux", Architecture: "amd64"},
+		Platforms: []Platform{
+			{OS: "linux", Architecture: "amd64"},
+			{OS: "linux", Architecture: "arm64", Variant: "v8"},
+		},
+		Created: &created,
+		Size:    4<<20 + 1024,
+		Labels:  map[string]string{"org.opencontainers.image.version": "1.0.0", "description": "a | b"},
+		Layers: []Layer{
+			{Digest: baseLayer, Size: 3 << 20, CreatedBy: "/bin/sh -c #(nop) ADD file:base in /"},
+			{Digest: appLayer, Size: 512, CreatedBy: "/bin/sh -c apk add --no-cache ca-certificates"},
+		},
+		TagsListed: true,
+		Tags:       []string{"0.9.0", "1.0.0"},
+		TagCount:   4,
+	}
+	report := RenderMarkdown(inspection)
+	assert.Contains(t, report, "# Image: dictybase/app")
+	assert.Contains(t, report, "**Reference:** `ghcr.io/dictybase/app:1.0.0`")
+	assert.Contains(t, report, "**Digest:** `"+indexDigest+"`")
+	assert.Contains(t, report, "**Platform:** linux/amd64 (of linux/amd64, linux/arm64/v8)")
+	assert.Contains(t, report, "**Created:** 2026-09-30T12:00:00Z")
+	assert.Contains(t, report, "**Size:** 4.0 MiB compressed in 2 layers")
+	assert.Contains(t, report, "| `description` | a \\| b |\n| `org.opencontainers.image.version` | 1.0.0 |")
+	assert.Contains(t, report, "| 1 | 3.0 MiB | `sha256:555555555555` | ADD file:base in / |")
+	assert.Contains(t, report, "| 2 | 512 B | `sha256:666666666666` | apk add --no-cache ca-certificates |")
+	assert.Contains(t, report, "2 of 4 tags: `0.9.0`, `1.0.0`")
+
+	inspection.Labels = nil
+	inspection.TagsListed = false
+	report = RenderMarkdown(inspection)
+	assert.Contains(t, report, "The image has no labels.")
+	assert.NotContains(t, report, "## Tags")
+}
@@ -0,0 +1,110 @@
+package infotool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/buildinfo"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// ServerName identifies the server in server-info responses.
+const ServerName = "dcr-mcp"
+
+// ServerInfo is the response of the server-info tool.
+type ServerInfo struct {
+	Name string `json:"name"`
+	buildinfo.Info
+}
+
+// InfoTool reports which build of the server a client is talking to.
+type InfoTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"server-info",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewInfoTool(deps.Logger)
+		},
+	)
+}
+
+// NewInfoTool creates a new InfoTool instance.
+func NewInfoTool(logger *slog.Logger) (*InfoTool, error) {
+	tool := mcp.NewTool(
+		"server-info",
+		mcp.WithDescription(
+			"Reports the version, commit and build date of the running dcr-mcp server",
+		),
+		mcp.WithTitleAnnotation("Server Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+	)
+	return &InfoTool{
+		Name:        "server-info",
+		Description: "Reports the build of the running server",
+		Tool:        tool,
+		Logger:      logger,
+	}, nil
+}
+
+// GetName returns the name of the tool.
+func (i *InfoTool) GetName() string {
+	return i.Name
+}
+
+// GetDescription returns the description of the tool.
+func (i *InfoTool) GetDescription() string {
+	return i.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (i *InfoTool) GetSchema() mcp.ToolInputSchema {
+	return i.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (i *InfoTool) GetTool() mcp.Tool {
+	return i.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (i *InfoTool) GetAnnotations() mcp.ToolAnnotation {
+	return i.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (i *InfoTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Report the version, commit and build date of the server",
+			Arguments:   map[string]any{},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (i *InfoTool) Handler(
+	_ context.Context,
+	_ mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	info := ServerInfo{Name: ServerName, Info: buildinfo.Get()}
+	encoded, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to encode server info: %w", err)), nil
+	}
+	return mcp.NewToolResultStructured(info, string(encoded)), nil
+}
@@ -0,0 +1,34 @@
+package infotool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/buildinfo"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	tool, err := NewInfoTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "server-info"
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	info, ok := result.StructuredContent.(ServerInfo)
+	require.True(t, ok)
+	assert.Equal(t, ServerName, info.Name)
+	assert.Equal(t, buildinfo.Get().Version, info.Version)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, `"name": "dcr-mcp"`)
+	assert.Contains(t, text.Text, `"go_version"`)
+}
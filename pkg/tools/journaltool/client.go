@@ -0,0 +1,249 @@
+package journaltool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const (
+	defaultEUtilsURL   = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+	defaultCrossrefURL = "https://api.crossref.org"
+)
+
+// maxCatalogRecords is the number of NLM Catalog records summarized per
+// lookup. An ISSN can match a journal and the titles it continues.
+const maxCatalogRecords = 5
+
+// errJournalNotFound is returned when a source has no record of a journal.
+var errJournalNotFound = errors.New("journal not found")
+
+// JournalClient looks up journals in the NLM Catalog and Crossref.
+type JournalClient struct {
+	httpClient  *http.Client
+	eutilsURL   string
+	crossrefURL string
+	logger      *slog.Logger
+}
+
+// Option represents a configuration option for JournalClient.
+type Option func(*Config)
+
+// Config holds the configuration for the journal client.
+type Config struct {
+	eutilsURL   string
+	crossrefURL string
+	timeout     time.Duration
+	logger      *slog.Logger
+}
+
+// WithEUtilsURL overrides the NCBI E-utilities base URL the NLM Catalog is
+// searched through.
+func WithEUtilsURL(eutilsURL string) Option {
+	return func(c *Config) {
+		c.eutilsURL = eutilsURL
+	}
+}
+
+// WithCrossrefURL overrides the Crossref REST API base URL.
+func WithCrossrefURL(crossrefURL string) Option {
+	return func(c *Config) {
+		c.crossrefURL = crossrefURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewJournalClient creates a new journal client.
+func NewJournalClient(opts ...Option) *JournalClient {
+	cfg := &Config{
+		eutilsURL:   defaultEUtilsURL,
+		crossrefURL: defaultCrossrefURL,
+		timeout:     30 * time.Second,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &JournalClient{
+		httpClient:  ratelimit.NewHTTPClient(cfg.timeout),
+		eutilsURL:   strings.TrimSuffix(cfg.eutilsURL, "/"),
+		crossrefURL: strings.TrimSuffix(cfg.crossrefURL, "/"),
+		logger:      cfg.logger,
+	}
+}
+
+// CatalogJournal returns the NLM Catalog record of the journal with the
+// given NLM ID, or with the given ISSN when nlmID is empty.
+func (c *JournalClient) CatalogJournal(ctx context.Context, issn, nlmID string) (*Journal, error) {
+	term := issn + "[issn]"
+	if nlmID != "" {
+		term = nlmID + "[nlmid]"
+	}
+	var search esearchResponse
+	params := url.Values{
+		"db":      {"nlmcatalog"},
+		"term":    {term},
+		"retmax":  {strconv.Itoa(maxCatalogRecords)},
+		"retmode": {"json"},
+	}
+	if err := c.getEUtils(ctx, "/esearch.fcgi", params, &search); err != nil {
+		return nil, fmt.Errorf("failed to search the NLM Catalog for %s: %w", term, err)
+	}
+	if len(search.Result.IDList) == 0 {
+		return nil, fmt.Errorf("no NLM Catalog record for %s: %w", term, errJournalNotFound)
+	}
+
+	var summary esummaryResponse
+	params = url.Values{
+		"db":      {"nlmcatalog"},
+		"id":      {strings.Join(search.Result.IDList, ",")},
+		"retmode": {"json"},
+	}
+	if err := c.getEUtils(ctx, "/esummary.fcgi", params, &summary); err != nil {
+		return nil, fmt.Errorf("failed to read NLM Catalog records for %s: %w", term, err)
+	}
+	records := make([]catalogRecord, 0, len(search.Result.IDList))
+	for _, uid := range search.Result.IDList {
+		var record catalogRecord
+		if err := json.Unmarshal(summary.Result[uid], &record); err != nil {
+			return nil, fmt.Errorf("error decoding NLM Catalog record %s: %w", uid, err)
+		}
+		records = append(records, record)
+	}
+	journal := toJournal(bestRecord(records, issn, nlmID))
+	c.logger.Info("found journal in the NLM Catalog", "term", term, "nlm_id", journal.NLMID)
+	return journal, nil
+}
+
+// CrossrefJournal returns the Crossref record of the journal with the
+// given ISSN.
+func (c *JournalClient) CrossrefJournal(ctx context.Context, issn string) (*Journal, error) {
+	start := time.Now()
+	var response crossrefJournalResponse
+	err := c.fetch(ctx, c.crossrefURL+"/journals/"+url.PathEscape(issn), "Crossref", &response)
+	metrics.ObserveOutbound(metrics.ServiceCrossref, start, err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up ISSN %s in Crossref: %w", issn, err)
+	}
+	journal := &Journal{
+		Title:     strings.TrimSpace(response.Message.Title),
+		Publisher: strings.TrimSpace(response.Message.Publisher),
+		Sources:   []string{SourceCrossref},
+	}
+	for _, issnType := range response.Message.ISSNType {
+		switch issnType.Type {
+		case "print":
+			journal.ISSNPrint = issnType.Value
+		case "electronic":
+			journal.ISSNElectronic = issnType.Value
+		}
+	}
+	return journal, nil
+}
+
+// getEUtils calls an E-utilities endpoint and decodes the JSON response
+// into out. The NLM Catalog is served by the same E-utilities as PubMed,
+// so its calls are counted and rate limited as PubMed calls.
+func (c *JournalClient) getEUtils(ctx context.Context, path string, params url.Values, out any) error {
+	start := time.Now()
+	err := c.fetch(ctx, c.eutilsURL+path+"?"+params.Encode(), "NLM Catalog", out)
+	metrics.ObserveOutbound(metrics.ServicePubMed, start, err)
+	return err
+}
+
+// fetch performs a GET request and decodes the JSON response into out. A
+// 404 is reported as errJournalNotFound.
+func (c *JournalClient) fetch(ctx context.Context, endpoint, service string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating %s request: %w", service, err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", service, err)
+	}
+	defer resp.Body.Close()
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusNotFound:
+		return errJournalNotFound
+	default:
+		return fmt.Errorf("%s returned status %d", service, resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding %s response: %w", service, err)
+	}
+	return nil
+}
+
+// bestRecord picks the record matching the NLM ID or ISSN searched for
+// from the records the search returned, falling back to the first.
+func bestRecord(records []catalogRecord, issn, nlmID string) catalogRecord {
+	index := slices.IndexFunc(records, func(record catalogRecord) bool {
+		if nlmID != "" {
+			return record.NLMUniqueID == nlmID
+		}
+		return slices.ContainsFunc(record.ISSNList, func(listed catalogISSN) bool {
+			return strings.EqualFold(listed.ISSN, issn)
+		})
+	})
+	return records[max(index, 0)]
+}
+
+// toJournal converts an NLM Catalog record into a Journal.
+func toJournal(record catalogRecord) *Journal {
+	journal := &Journal{
+		MedlineAbbreviation: record.MedlineTA,
+		ISOAbbreviation:     record.ISOAbbreviation,
+		NLMID:               record.NLMUniqueID,
+		Sources:             []string{SourceNLMCatalog},
+	}
+	if len(record.TitleMainList) > 0 {
+		// Catalog titles end with the period of the cataloging record.
+		journal.Title = strings.TrimSuffix(strings.TrimSpace(record.TitleMainList[0].Title), ".")
+	}
+	for _, listed := range record.ISSNList {
+		switch listed.Type {
+		case "Print":
+			journal.ISSNPrint = listed.ISSN
+		case "Electronic":
+			journal.ISSNElectronic = listed.ISSN
+		}
+	}
+	for _, info := range record.PublicationInfoList {
+		if info.Publisher != "" {
+			journal.Publisher = strings.TrimSpace(info.Publisher)
+			break
+		}
+	}
+	if status := record.CurrentIndexingStatus; status == "Y" || status == "N" {
+		indexed := status == "Y"
+		journal.IndexedInMEDLINE = &indexed
+	}
+	return journal
+}
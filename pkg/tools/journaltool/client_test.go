@@ -0,0 +1,112 @@
+package journaltool
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const summaryJSON = `{"result":{"uids":["0411011","7705988"],
+	"0411011":{"uid":"0411011",
+		"titlemainlist":[{"title":"Nucleic acids research."}],
+		"medlineta":"Nucleic Acids Res","isoabbreviation":"Nucleic Acids Res",
+		"nlmuniqueid":"0411011",
+		"issnlist":[{"issn":"0305-1048","issntype":"Print"},{"issn":"1362-4962","issntype":"Electronic"}],
+		"publicationinfolist":[{"imprint":"London, Information Retrieval Ltd.","publisher":"Information Retrieval Ltd."}],
+		"currentindexingstatus":"Y"},
+	"7705988":{"uid":"7705988",
+		"titlemainlist":[{"title":"Nucleic acids research. Supplement."}],
+		"nlmuniqueid":"7705988",
+		"issnlist":[{"issn":"0261-3166","issntype":"Print"}],
+		"currentindexingstatus":"N"}
+}}`
+
+const crossrefJSON = `{"status":"ok","message":{"title":"Nucleic Acids Research",
+	"publisher":"Oxford University Press (OUP)",
+	"issn-type":[{"value":"0305-1048","type":"print"},{"value":"1362-4962","type":"electronic"}]}}`
+
+// newTestServer serves the E-utilities and Crossref calls of a lookup of
+// Nucleic Acids Research. Other ISSNs are unknown to both.
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /esearch.fcgi", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "nlmcatalog", r.URL.Query().Get("db"))
+		switch r.URL.Query().Get("term") {
+		case "0305-1048[issn]", "0411011[nlmid]":
+			_, _ = io.WriteString(w, `{"esearchresult":{"count":"2","idlist":["7705988","0411011"]}}`)
+		default:
+			_, _ = io.WriteString(w, `{"esearchresult":{"count":"0","idlist":[]}}`)
+		}
+	})
+	mux.HandleFunc("GET /esummary.fcgi", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "7705988,0411011", r.URL.Query().Get("id"))
+		_, _ = io.WriteString(w, summaryJSON)
+	})
+	mux.HandleFunc("GET /journals/{issn}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("issn") != "0305-1048" {
+			http.Error(w, "Resource not found.", http.StatusNotFound)
+			return
+		}
+		_, _ = io.WriteString(w, crossrefJSON)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient creates a client that sends both sources' calls to server.
+func newTestClient(server *httptest.Server) *JournalClient {
+	return NewJournalClient(WithEUtilsURL(server.URL), WithCrossrefURL(server.URL))
+}
+
+func TestJournalClient_CatalogJournal(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(newTestServer(t))
+
+	for name, lookup := range map[string][2]string{
+		"by ISSN":   {"0305-1048", ""},
+		"by NLM ID": {"", "0411011"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			journal, err := client.CatalogJournal(context.Background(), lookup[0], lookup[1])
+			require.NoError(t, err)
+			assert.Equal(t, "Nucleic acids research", journal.Title)
+			assert.Equal(t, "Nucleic Acids Res", journal.MedlineAbbreviation)
+			assert.Equal(t, "0411011", journal.NLMID, "the record searched for, not the first")
+			assert.Equal(t, "0305-1048", journal.ISSNPrint)
+			assert.Equal(t, "1362-4962", journal.ISSNElectronic)
+			assert.Equal(t, "Information Retrieval Ltd.", journal.Publisher)
+			require.NotNil(t, journal.IndexedInMEDLINE)
+			assert.True(t, *journal.IndexedInMEDLINE)
+			assert.Equal(t, []string{SourceNLMCatalog}, journal.Sources)
+		})
+	}
+
+	_, err := client.CatalogJournal(context.Background(), "2049-3630", "")
+	require.ErrorIs(t, err, errJournalNotFound)
+}
+
+func TestJournalClient_CrossrefJournal(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(newTestServer(t))
+
+	journal, err := client.CrossrefJournal(context.Background(), "0305-1048")
+	require.NoError(t, err)
+	assert.Equal(t, &Journal{
+		Title:          "Nucleic Acids Research",
+		ISSNPrint:      "0305-1048",
+		ISSNElectronic: "1362-4962",
+		Publisher:      "Oxford University Press (OUP)",
+		Sources:        []string{SourceCrossref},
+	}, journal)
+
+	_, err = client.CrossrefJournal(context.Background(), "2049-3630")
+	require.ErrorIs(t, err, errJournalNotFound)
+}
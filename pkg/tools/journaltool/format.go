@@ -0,0 +1,60 @@
+package journaltool
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// sourceNames are the display names of the sources of a journal record.
+var sourceNames = map[string]string{
+	SourceNLMCatalog: "NLM Catalog",
+	SourceCrossref:   "Crossref",
+}
+
+// FormatJournal renders a journal record as a markdown table, leaving out
+// the fields it lacks.
+func FormatJournal(journal Journal) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# %s\n\n| Field | Value |\n|-------|-------|\n", journal.Title)
+	row := func(field, value string) {
+		if value != "" {
+			fmt.Fprintf(&builder, "| %s | %s |\n", field, value)
+		}
+	}
+	row("MEDLINE abbreviation", journal.MedlineAbbreviation)
+	row("ISO abbreviation", journal.ISOAbbreviation)
+	row("NLM ID", journal.NLMID)
+	row("ISSN (print)", journal.ISSNPrint)
+	row("ISSN (electronic)", journal.ISSNElectronic)
+	row("Publisher", journal.Publisher)
+	if journal.IndexedInMEDLINE != nil {
+		indexed := "no"
+		if *journal.IndexedInMEDLINE {
+			indexed = "yes"
+		}
+		row("Indexed in MEDLINE", indexed)
+	}
+	if metrics := journal.Metrics; metrics != nil {
+		if metrics.SJR > 0 {
+			row("SJR", strconv.FormatFloat(metrics.SJR, 'f', -1, 64))
+		}
+		row("SJR best quartile", metrics.Quartile)
+		if metrics.HIndex > 0 {
+			row("H index", strconv.Itoa(metrics.HIndex))
+		}
+		if metrics.ImpactFactor > 0 {
+			row("Impact factor", strconv.FormatFloat(metrics.ImpactFactor, 'f', -1, 64))
+		}
+	}
+
+	names := make([]string, 0, len(journal.Sources)+1)
+	for _, source := range journal.Sources {
+		names = append(names, sourceNames[source])
+	}
+	if journal.Metrics != nil {
+		names = append(names, journal.Metrics.Source)
+	}
+	fmt.Fprintf(&builder, "\nSources: %s\n", strings.Join(names, ", "))
+	return builder.String()
+}
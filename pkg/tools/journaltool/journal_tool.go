@@ -0,0 +1,278 @@
+package journaltool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// nlmIDRegex matches NLM unique IDs such as 0411011 or 101170960.
+var nlmIDRegex = regexp.MustCompile(`^\d{7,9}[A-Z]?$`)
+
+// JournalTool resolves a journal by ISSN or NLM ID to its canonical names,
+// abbreviations and publisher, with its citation metrics when a metrics
+// file is configured.
+type JournalTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []Option
+	metricsFile   string
+	metricsOnce   sync.Once
+	metricsTable  *MetricsTable
+	metricsErr    error
+}
+
+// ToolOption defines a functional option for configuring JournalTool.
+type ToolOption func(*JournalTool)
+
+// WithClientOptions sets options for the journal clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(j *JournalTool) {
+		j.clientOptions = append(j.clientOptions, opts...)
+	}
+}
+
+// WithMetricsFile sets the CSV file journal metrics are read from, see
+// LoadMetrics. It is read on the first lookup.
+func WithMetricsFile(path string) ToolOption {
+	return func(j *JournalTool) {
+		j.metricsFile = path
+	}
+}
+
+// JournalRequest represents the parameters for a journal lookup. Exactly
+// one of ISSN and NLMID is given.
+type JournalRequest struct {
+	ISSN  string `validate:"required_without=NLMID,excluded_with=NLMID,omitempty,issn"`
+	NLMID string `validate:"required_without=ISSN,omitempty,nlmid"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	if err := validate.RegisterValidation("nlmid", validateNLMID); err != nil {
+		panic(err)
+	}
+	registry.Register(
+		"journal-info",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			journalTool, err := NewJournalTool(
+				deps.Logger,
+				WithMetricsFile(os.Getenv("JOURNAL_METRICS_FILE")),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return journalTool, nil
+		},
+	)
+}
+
+// NewJournalTool creates a new JournalTool instance.
+func NewJournalTool(logger *slog.Logger, opts ...ToolOption) (*JournalTool, error) {
+	tool := mcp.NewTool(
+		"journal-info",
+		mcp.WithDescription(
+			"Resolves a journal by ISSN or NLM ID to its title, abbreviations, publisher and citation metrics",
+		),
+		mcp.WithTitleAnnotation("Journal Info"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"issn",
+			mcp.Description("Print or electronic ISSN, e.g. 0305-1048; required unless nlm_id is given"),
+		),
+		mcp.WithString(
+			"nlm_id",
+			mcp.Description("NLM Catalog ID, e.g. 0411011; required unless issn is given"),
+		),
+	)
+	journalTool := &JournalTool{
+		Name:        "journal-info",
+		Description: "Resolves a journal by ISSN or NLM ID to its title, abbreviations, publisher and citation metrics",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(journalTool)
+	}
+	return journalTool, nil
+}
+
+// GetName returns the name of the tool.
+func (j *JournalTool) GetName() string {
+	return j.Name
+}
+
+// GetDescription returns the description of the tool.
+func (j *JournalTool) GetDescription() string {
+	return j.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (j *JournalTool) GetSchema() mcp.ToolInputSchema {
+	return j.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (j *JournalTool) GetTool() mcp.Tool {
+	return j.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (j *JournalTool) GetAnnotations() mcp.ToolAnnotation {
+	return j.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (j *JournalTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Look up a journal by its print ISSN",
+			Arguments:   map[string]any{"issn": "0305-1048"},
+		},
+		{
+			Description: "Look up a journal by its NLM Catalog ID",
+			Arguments:   map[string]any{"nlm_id": "0411011"},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (j *JournalTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := JournalRequest{
+		ISSN:  normalizeISSN(request.GetString("issn", "")),
+		NLMID: strings.ToUpper(strings.TrimSpace(request.GetString("nlm_id", ""))),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	table, err := j.loadMetrics()
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeConfiguration, "INVALID_METRICS_FILE", err, "cannot use JOURNAL_METRICS_FILE",
+		)), nil
+	}
+
+	logger := logging.WithRequestID(j.Logger)
+	client := NewJournalClient(append([]Option{WithLogger(logger)}, j.clientOptions...)...)
+	services := []string{metrics.ServicePubMed}
+	journal, err := client.CatalogJournal(ctx, params.ISSN, params.NLMID)
+	cataloged := err == nil
+	switch {
+	case cataloged:
+	case !errors.Is(err, errJournalNotFound):
+		return toolerror.Result(
+			toolerror.Upstream(metrics.ServicePubMed, err, "failed to search the NLM Catalog"),
+		), nil
+	case params.ISSN == "":
+		return notFound(err), nil
+	default:
+		journal = &Journal{}
+	}
+
+	// Crossref knows the current publisher of journals that changed hands,
+	// and journals outside the biomedical literature the NLM Catalog lacks.
+	issn := params.ISSN
+	if issns := journal.ISSNs(); issn == "" && len(issns) > 0 {
+		issn = issns[0]
+	}
+	if issn != "" {
+		services = append(services, metrics.ServiceCrossref)
+		crossref, crossrefErr := client.CrossrefJournal(ctx, issn)
+		switch {
+		case crossrefErr == nil:
+			journal = merge(journal, crossref, cataloged)
+		case !errors.Is(crossrefErr, errJournalNotFound) && !cataloged:
+			return toolerror.Result(
+				toolerror.Upstream(metrics.ServiceCrossref, crossrefErr, "failed to look up the journal in Crossref"),
+			), nil
+		case !errors.Is(crossrefErr, errJournalNotFound):
+			logger.Warn("failed to look up journal in Crossref", "issn", issn, "error", crossrefErr)
+		}
+	}
+	if len(journal.Sources) == 0 {
+		return notFound(err), nil
+	}
+	if table != nil {
+		journal.Metrics = table.Lookup(append(journal.ISSNs(), params.ISSN)...)
+	}
+
+	result := mcp.NewToolResultText(FormatJournal(*journal))
+	result.StructuredContent = journal
+	return provenance.Attach(result, provenance.New(services)), nil
+}
+
+// loadMetrics returns the table of the metrics file, loading it on first use,
+// or nil when no metrics file is configured.
+func (j *JournalTool) loadMetrics() (*MetricsTable, error) {
+	if j.metricsFile == "" {
+		return nil, nil
+	}
+	j.metricsOnce.Do(func() {
+		j.metricsTable, j.metricsErr = LoadMetrics(j.metricsFile)
+		if j.metricsErr == nil {
+			j.Logger.Info("loaded journal metrics", "file", j.metricsFile, "issns", j.metricsTable.Len())
+		}
+	})
+	return j.metricsTable, j.metricsErr
+}
+
+// merge adds the Crossref record of a journal to the record found so far.
+// The Crossref publisher is the current one, so it replaces the one
+// cataloged; the NLM Catalog's title and ISSNs are kept.
+func merge(journal, crossref *Journal, cataloged bool) *Journal {
+	merged := *journal
+	if !cataloged {
+		merged.Title = crossref.Title
+		merged.ISSNPrint = crossref.ISSNPrint
+		merged.ISSNElectronic = crossref.ISSNElectronic
+	}
+	if crossref.Publisher != "" {
+		merged.Publisher = crossref.Publisher
+	}
+	merged.Sources = append(merged.Sources, crossref.Sources...)
+	return &merged
+}
+
+// notFound reports a journal neither source has a record of.
+func notFound(err error) *mcp.CallToolResult {
+	return toolerror.Result(toolerror.Wrap(toolerror.TypeNotFound, "JOURNAL_NOT_FOUND", err, "unknown journal"))
+}
+
+// normalizeISSN strips surrounding space and upper-cases the check digit,
+// adding the hyphen to ISSNs written without one.
+func normalizeISSN(issn string) string {
+	issn = strings.ToUpper(strings.TrimSpace(issn))
+	if len(issn) == 8 && !strings.Contains(issn, "-") {
+		return issn[:4] + "-" + issn[4:]
+	}
+	return issn
+}
+
+// validateNLMID checks the format of an NLM unique ID.
+func validateNLMID(fl validator.FieldLevel) bool {
+	return nlmIDRegex.MatchString(fl.Field().String())
+}
@@ -0,0 +1,85 @@
+package journaltool
+
+import (
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// callTool runs the tool against server with the given arguments.
+func callTool(t *testing.T, server string, arguments map[string]any, opts ...ToolOption) *mcp.CallToolResult {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	opts = append(opts, WithClientOptions(WithEUtilsURL(server), WithCrossrefURL(server)))
+	tool, err := NewJournalTool(logger, opts...)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "journal-info", arguments)
+}
+
+func TestHandler_MergesSources(t *testing.T) {
+	t.Parallel()
+	server := newTestServer(t)
+	path := filepath.Join(t.TempDir(), "scimagojr.csv")
+	require.NoError(t, os.WriteFile(path, []byte(scimagoCSV), 0o600))
+
+	result := callTool(t, server.URL, map[string]any{"issn": " 03051048 "}, WithMetricsFile(path))
+	require.False(t, result.IsError)
+	journal, ok := result.StructuredContent.(*Journal)
+	require.True(t, ok)
+	assert.Equal(t, "Nucleic acids research", journal.Title)
+	assert.Equal(t, "Oxford University Press (OUP)", journal.Publisher, "Crossref names the current publisher")
+	assert.Equal(t, []string{SourceNLMCatalog, SourceCrossref}, journal.Sources)
+	require.NotNil(t, journal.Metrics)
+	assert.Equal(t, "Q1", journal.Metrics.Quartile)
+
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "| MEDLINE abbreviation | Nucleic Acids Res |")
+	assert.Contains(t, text.Text, "| SJR | 7.048 |")
+	assert.Contains(t, text.Text, "Sources: NLM Catalog, Crossref, scimagojr.csv")
+
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"pubmed", "crossref"}, record.Providers)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	server := newTestServer(t)
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		opts      []ToolOption
+		code      string
+	}{
+		{name: "no identifier", arguments: map[string]any{}, code: "INVALID_INPUT"},
+		{name: "bad check digit", arguments: map[string]any{"issn": "0305-1049"}, code: "INVALID_INPUT"},
+		{
+			name:      "both identifiers",
+			arguments: map[string]any{"issn": "0305-1048", "nlm_id": "0411011"},
+			code:      "INVALID_INPUT",
+		},
+		{name: "unknown ISSN", arguments: map[string]any{"issn": "2049-3630"}, code: "JOURNAL_NOT_FOUND"},
+		{name: "unknown NLM ID", arguments: map[string]any{"nlm_id": "9999999"}, code: "JOURNAL_NOT_FOUND"},
+		{
+			name:      "missing metrics file",
+			arguments: map[string]any{"issn": "0305-1048"},
+			opts:      []ToolOption{WithMetricsFile(filepath.Join(t.TempDir(), "missing.csv"))},
+			code:      "INVALID_METRICS_FILE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := callTool(t, server.URL, tt.arguments, tt.opts...)
+			assert.Equal(t, tt.code, tooltest.ErrorCode(t, result))
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package journaltool
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Columns of a metrics file, matched case-insensitively. The names are
+// those of the SCImago Journal Rank export; the impact factor column lets
+// labs with a Journal Citation Reports licence add their own figures.
+const (
+	columnISSN         = "issn"
+	columnSJR          = "sjr"
+	columnQuartile     = "sjr best quartile"
+	columnHIndex       = "h index"
+	columnImpactFactor = "impact factor"
+)
+
+// errNoISSNColumn is returned for metrics files without an ISSN column.
+var errNoISSNColumn = errors.New("metrics file has no Issn column")
+
+// MetricsTable holds the journal metrics of a metrics file by ISSN.
+type MetricsTable struct {
+	byISSN map[string]Metrics
+}
+
+// LoadMetrics reads the metrics file at path, a CSV file such as the
+// journal rankings exported from scimagojr.com.
+func LoadMetrics(path string) (*MetricsTable, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics file: %w", err)
+	}
+	table, err := ParseMetrics(bytes.NewReader(data), filepath.Base(path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics file %s: %w", path, err)
+	}
+	return table, nil
+}
+
+// ParseMetrics reads metrics from CSV data, naming source as where they
+// came from. The data is separated by semicolons, as in the SCImago
+// export, when its header has more semicolons than commas, and by commas
+// otherwise. Decimal commas are read as points.
+func ParseMetrics(reader io.Reader, source string) (*MetricsTable, error) {
+	data, err := io.ReadAll(reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read metrics: %w", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\ufeff"))
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	csvReader := csv.NewReader(bytes.NewReader(data))
+	if bytes.Count(header, []byte(";")) > bytes.Count(header, []byte(",")) {
+		csvReader.Comma = ';'
+	}
+	csvReader.FieldsPerRecord = -1
+	csvReader.LazyQuotes = true
+	records, err := csvReader.ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse metrics: %w", err)
+	}
+	if len(records) == 0 {
+		return nil, errNoISSNColumn
+	}
+	columns := make(map[string]int, len(records[0]))
+	for index, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = index
+	}
+	if _, ok := columns[columnISSN]; !ok {
+		return nil, errNoISSNColumn
+	}
+
+	table := &MetricsTable{byISSN: make(map[string]Metrics)}
+	for _, record := range records[1:] {
+		field := func(column string) string {
+			index, ok := columns[column]
+			if !ok || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+		row := Metrics{
+			SJR:          parseDecimal(field(columnSJR)),
+			Quartile:     field(columnQuartile),
+			ImpactFactor: parseDecimal(field(columnImpactFactor)),
+			Source:       source,
+		}
+		// The export marks missing quartiles with a dash.
+		if row.Quartile == "-" {
+			row.Quartile = ""
+		}
+		row.HIndex, _ = strconv.Atoi(field(columnHIndex))
+		issns := strings.FieldsFunc(field(columnISSN), func(r rune) bool {
+			return r == ',' || r == ';' || r == ' '
+		})
+		for _, issn := range issns {
+			if key := issnKey(issn); len(key) == 8 {
+				table.byISSN[key] = row
+			}
+		}
+	}
+	return table, nil
+}
+
+// Lookup returns the metrics listed for the first of issns the table
+// holds, or nil when it holds none of them.
+func (t *MetricsTable) Lookup(issns ...string) *Metrics {
+	for _, issn := range issns {
+		if row, ok := t.byISSN[issnKey(issn)]; ok {
+			return &row
+		}
+	}
+	return nil
+}
+
+// Len returns the number of ISSNs the table holds metrics for.
+func (t *MetricsTable) Len() int {
+	return len(t.byISSN)
+}
+
+// issnKey returns issn without its hyphen, in upper case, which is how the
+// SCImago export writes ISSNs.
+func issnKey(issn string) string {
+	return strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(issn), "-", ""))
+}
+
+// parseDecimal parses a number written with a decimal point or comma,
+// returning 0 for empty or malformed values.
+func parseDecimal(value string) float64 {
+	number, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	return number
+}
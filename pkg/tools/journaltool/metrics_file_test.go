@@ -0,0 +1,48 @@
+package journaltool
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// scimagoCSV is an excerpt of the SCImago Journal Rank export.
+const scimagoCSV = "\ufeffRank;Sourceid;Title;Type;Issn;SJR;SJR Best Quartile;H index\n" +
+	`1;13683;"Nucleic Acids Research";journal;"13624962, 03051048";"7,048";Q1;624` + "\n" +
+	`2;99999;"Unranked Journal";journal;"20493630";;-;` + "\n"
+
+func TestParseMetrics_SCImago(t *testing.T) {
+	t.Parallel()
+	table, err := ParseMetrics(strings.NewReader(scimagoCSV), "scimagojr.csv")
+	require.NoError(t, err)
+	assert.Equal(t, 3, table.Len())
+
+	assert.Equal(t, &Metrics{SJR: 7.048, Quartile: "Q1", HIndex: 624, Source: "scimagojr.csv"},
+		table.Lookup("0305-1048"))
+	assert.Equal(t, table.Lookup("0305-1048"), table.Lookup("9999-9999", "1362-4962"))
+	assert.Equal(t, &Metrics{Source: "scimagojr.csv"}, table.Lookup("2049-3630"), "dashes are missing values")
+	assert.Nil(t, table.Lookup("9999-9999"))
+}
+
+func TestParseMetrics_CommaSeparated(t *testing.T) {
+	t.Parallel()
+	data := "ISSN,Title,Impact Factor\n0305-1048,Nucleic Acids Research,16.6\n"
+	table, err := ParseMetrics(strings.NewReader(data), "jcr.csv")
+	require.NoError(t, err)
+	assert.Equal(t, &Metrics{ImpactFactor: 16.6, Source: "jcr.csv"}, table.Lookup("0305-1048"))
+}
+
+func TestLoadMetrics_Errors(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "journals.csv")
+	require.NoError(t, os.WriteFile(path, []byte("Title;SJR\nNucleic Acids Research;7,048\n"), 0o600))
+
+	_, err := LoadMetrics(path)
+	require.ErrorIs(t, err, errNoISSNColumn)
+	_, err = LoadMetrics(filepath.Join(t.TempDir(), "missing.csv"))
+	require.Error(t, err)
+}
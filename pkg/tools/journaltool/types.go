@@ -0,0 +1,103 @@
+package journaltool
+
+import "encoding/json"
+
+// Sources a journal record can be assembled from.
+const (
+	SourceNLMCatalog = "nlm_catalog"
+	SourceCrossref   = "crossref"
+)
+
+// Journal is the canonical record of a journal, merged from the NLM Catalog
+// and Crossref.
+type Journal struct {
+	Title string `json:"title"`
+	// MedlineAbbreviation is the NLM title abbreviation PubMed cites the
+	// journal by.
+	MedlineAbbreviation string `json:"medline_abbreviation,omitempty"`
+	ISOAbbreviation     string `json:"iso_abbreviation,omitempty"`
+	NLMID               string `json:"nlm_id,omitempty"`
+	ISSNPrint           string `json:"issn_print,omitempty"`
+	ISSNElectronic      string `json:"issn_electronic,omitempty"`
+	// Publisher is the current publisher from Crossref, or else the first
+	// publisher the NLM Catalog lists.
+	Publisher string `json:"publisher,omitempty"`
+	// IndexedInMEDLINE reports whether MEDLINE currently indexes the
+	// journal. It is left out for journals missing from the NLM Catalog.
+	IndexedInMEDLINE *bool `json:"indexed_in_medline,omitempty"`
+	// Sources lists where the record was read from.
+	Sources []string `json:"sources"`
+	// Metrics are the journal's citation metrics, if a metrics file is
+	// configured and lists the journal.
+	Metrics *Metrics `json:"metrics,omitempty"`
+}
+
+// ISSNs returns the print and electronic ISSNs the journal has.
+func (j Journal) ISSNs() []string {
+	issns := make([]string, 0, 2)
+	for _, issn := range []string{j.ISSNPrint, j.ISSNElectronic} {
+		if issn != "" {
+			issns = append(issns, issn)
+		}
+	}
+	return issns
+}
+
+// Metrics are the citation metrics of a journal, as listed in a metrics
+// file.
+type Metrics struct {
+	SJR          float64 `json:"sjr,omitempty"`
+	Quartile     string  `json:"quartile,omitempty"`
+	HIndex       int     `json:"h_index,omitempty"`
+	ImpactFactor float64 `json:"impact_factor,omitempty"`
+	// Source is the name of the file the metrics were read from.
+	Source string `json:"source"`
+}
+
+// esearchResponse is the part of an E-utilities esearch response the client
+// reads.
+type esearchResponse struct {
+	Result struct {
+		IDList []string `json:"idlist"`
+	} `json:"esearchresult"`
+}
+
+// esummaryResponse is an E-utilities esummary response of the nlmcatalog
+// database, keyed by catalog UID next to the "uids" list.
+type esummaryResponse struct {
+	Result map[string]json.RawMessage `json:"result"`
+}
+
+// catalogRecord is the part of an NLM Catalog summary the client reads.
+type catalogRecord struct {
+	TitleMainList []struct {
+		Title string `json:"title"`
+	} `json:"titlemainlist"`
+	MedlineTA           string        `json:"medlineta"`
+	ISOAbbreviation     string        `json:"isoabbreviation"`
+	NLMUniqueID         string        `json:"nlmuniqueid"`
+	ISSNList            []catalogISSN `json:"issnlist"`
+	PublicationInfoList []struct {
+		Publisher string `json:"publisher"`
+	} `json:"publicationinfolist"`
+	CurrentIndexingStatus string `json:"currentindexingstatus"`
+}
+
+// catalogISSN is an ISSN listed in an NLM Catalog record, typed "Print"
+// or "Electronic".
+type catalogISSN struct {
+	ISSN string `json:"issn"`
+	Type string `json:"issntype"`
+}
+
+// crossrefJournalResponse is the envelope of a Crossref journal lookup.
+type crossrefJournalResponse struct {
+	Message struct {
+		Title     string `json:"title"`
+		Publisher string `json:"publisher"`
+		ISSNType  []struct {
+			Value string `json:"value"`
+			Type  string `json:"type"`
+		} `json:"issn-type"`
+	} `json:"message"`
+}
@@ -0,0 +1,235 @@
+package k8stool
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// maxChangedFields is the number of other changed fields listed for a
+// resource or container.
+const maxChangedFields = 8
+
+// Change statuses of a resource.
+const (
+	StatusAdded   = "added"
+	StatusRemoved = "removed"
+	StatusChanged = "changed"
+)
+
+// Change is a resource that differs between two versions of the manifests.
+type Change struct {
+	Status string
+	// Resource is the current resource, or the previous one when it was
+	// removed.
+	Resource Resource
+	// Details lists what changed in a changed resource.
+	Details []string
+}
+
+// Comparison is the difference between two versions of the manifests.
+type Comparison struct {
+	Changes   []Change
+	Unchanged int
+}
+
+// Count returns the number of changes with status.
+func (c Comparison) Count(status string) int {
+	count := 0
+	for _, change := range c.Changes {
+		if change.Status == status {
+			count++
+		}
+	}
+	return count
+}
+
+// Compare diffs the previous resources with the current ones, matching
+// them by kind, namespace and name. Changes are sorted by status, then by
+// kind and name.
+func Compare(previous, current []Resource) Comparison {
+	before := make(map[string]Resource, len(previous))
+	for _, resource := range previous {
+		before[resource.ID()] = resource
+	}
+	after := make(map[string]Resource, len(current))
+	for _, resource := range current {
+		after[resource.ID()] = resource
+	}
+	var comparison Comparison
+	for _, id := range slices.Sorted(maps.Keys(after)) {
+		resource := after[id]
+		old, found := before[id]
+		if !found {
+			comparison.Changes = append(comparison.Changes, Change{Status: StatusAdded, Resource: resource})
+			continue
+		}
+		details := describeChanges(old, resource)
+		if len(details) == 0 {
+			comparison.Unchanged++
+			continue
+		}
+		comparison.Changes = append(comparison.Changes, Change{
+			Status:   StatusChanged,
+			Resource: resource,
+			Details:  details,
+		})
+	}
+	for _, id := range slices.Sorted(maps.Keys(before)) {
+		if _, found := after[id]; !found {
+			comparison.Changes = append(comparison.Changes, Change{Status: StatusRemoved, Resource: before[id]})
+		}
+	}
+	order := map[string]int{StatusAdded: 0, StatusChanged: 1, StatusRemoved: 2}
+	slices.SortStableFunc(comparison.Changes, func(a, b Change) int {
+		return order[a.Status] - order[b.Status]
+	})
+	return comparison
+}
+
+// describeChanges lists the differences of a resource between versions:
+// replicas, containers, images and environment variables in detail, any
+// other field by its path.
+func describeChanges(old, current Resource) []string {
+	var details []string
+	if replicas, oldReplicas := formatReplicas(current.Replicas), formatReplicas(old.Replicas); replicas != oldReplicas {
+		details = append(details, fmt.Sprintf("replicas %s → %s", oldReplicas, replicas))
+	}
+	details = append(details, describeContainerChanges(old.Containers, current.Containers)...)
+
+	skipped := []string{"spec.replicas", "status"}
+	if podSpec, ok := podSpecPaths[current.Kind]; ok {
+		prefix := strings.Join(podSpec, ".")
+		skipped = append(skipped, prefix+".containers", prefix+".initContainers")
+	}
+	fields := changedFields(old.Object, current.Object, "", skipped)
+	if len(fields) > 0 {
+		details = append(details, "other changes: "+formatFields(fields))
+	}
+	return details
+}
+
+// describeContainerChanges lists the containers added and removed and the
+// changes of the containers kept, matched by name.
+func describeContainerChanges(old, current []Container) []string {
+	before := make(map[string]Container, len(old))
+	for _, container := range old {
+		before[container.Name] = container
+	}
+	var details []string
+	kept := make(map[string]bool, len(current))
+	for _, container := range current {
+		kept[container.Name] = true
+		previous, found := before[container.Name]
+		if !found {
+			details = append(details, fmt.Sprintf("%s added with image `%s`", containerLabel(container), container.Image))
+			continue
+		}
+		label := containerLabel(container)
+		if previous.Image != container.Image {
+			details = append(details, fmt.Sprintf("%s image `%s` → `%s`", label, previous.Image, container.Image))
+		}
+		for _, change := range describeEnvChanges(previous.Env, container.Env) {
+			details = append(details, label+" "+change)
+		}
+		fields := changedFields(previous.Object, container.Object, "", []string{"image", "env", "envFrom"})
+		if len(fields) > 0 {
+			details = append(details, fmt.Sprintf("%s changed: %s", label, formatFields(fields)))
+		}
+	}
+	for _, container := range old {
+		if !kept[container.Name] {
+			details = append(details, containerLabel(container)+" removed")
+		}
+	}
+	return details
+}
+
+// describeEnvChanges lists the environment variables added, removed and
+// changed, in the order of the current container.
+func describeEnvChanges(old, current []EnvVar) []string {
+	before := make(map[string]EnvVar, len(old))
+	for _, variable := range old {
+		before[variable.Name] = variable
+	}
+	var details []string
+	kept := make(map[string]bool, len(current))
+	for _, variable := range current {
+		kept[variable.Name] = true
+		previous, found := before[variable.Name]
+		switch {
+		case !found:
+			details = append(details, fmt.Sprintf("env `%s` added: %s", variable.Name, variable.Describe()))
+		case previous != variable:
+			details = append(details, fmt.Sprintf(
+				"env `%s` changed: %s → %s", variable.Name, previous.Describe(), variable.Describe(),
+			))
+		}
+	}
+	for _, variable := range old {
+		if !kept[variable.Name] {
+			details = append(details, fmt.Sprintf("env `%s` removed", variable.Name))
+		}
+	}
+	return details
+}
+
+// changedFields returns the paths of the fields that differ between two
+// mappings, descending into nested mappings but not into sequences. Paths
+// under skipped are left out.
+func changedFields(old, current map[string]any, prefix string, skipped []string) []string {
+	keys := make(map[string]bool, len(old)+len(current))
+	for key := range old {
+		keys[key] = true
+	}
+	for key := range current {
+		keys[key] = true
+	}
+	var fields []string
+	for _, key := range slices.Sorted(maps.Keys(keys)) {
+		field := prefix + key
+		if slices.Contains(skipped, field) || reflect.DeepEqual(old[key], current[key]) {
+			continue
+		}
+		oldMap, oldIsMap := old[key].(map[string]any)
+		currentMap, currentIsMap := current[key].(map[string]any)
+		if oldIsMap && currentIsMap {
+			fields = append(fields, changedFields(oldMap, currentMap, field+".", skipped)...)
+			continue
+		}
+		fields = append(fields, field)
+	}
+	return fields
+}
+
+// formatFields renders field paths as code spans, keeping the first few.
+func formatFields(fields []string) string {
+	quoted := make([]string, 0, min(len(fields), maxChangedFields))
+	for _, field := range fields[:min(len(fields), maxChangedFields)] {
+		quoted = append(quoted, "`"+field+"`")
+	}
+	rendered := strings.Join(quoted, ", ")
+	if len(fields) > maxChangedFields {
+		rendered += fmt.Sprintf(" and %d more", len(fields)-maxChangedFields)
+	}
+	return rendered
+}
+
+// formatReplicas renders a replica count, which may be left to the
+// default.
+func formatReplicas(replicas *int) string {
+	if replicas == nil {
+		return "default"
+	}
+	return fmt.Sprint(*replicas)
+}
+
+// containerLabel names a container in a change.
+func containerLabel(container Container) string {
+	if container.Init {
+		return fmt.Sprintf("init container `%s`", container.Name)
+	}
+	return fmt.Sprintf("container `%s`", container.Name)
+}
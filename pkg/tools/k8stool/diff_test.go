@@ -0,0 +1,82 @@
+package k8stool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompare(t *testing.T) {
+	t.Parallel()
+	previous, err := ParseManifests("previous", deploymentManifest+serviceManifest)
+	require.NoError(t, err)
+	updated := strings.NewReplacer(
+		"replicas: 2", "replicas: 3",
+		"graphql-server:2.1.0", "graphql-server:2.2.0",
+		"value: info", "value: debug",
+		"value: hunter2", "value: correct-horse",
+		"            - name: REDIS_PORT\n              value: 6379\n", "",
+		"containerPort: 8080", "containerPort: 9090",
+		"targetPort: 8080", "targetPort: 9090",
+	).Replace(deploymentManifest + serviceManifest)
+	updated = strings.Replace(updated, "---\napiVersion: batch/v1", `---
+apiVersion: networking.k8s.io/v1
+kind: Ingress
+metadata:
+  name: graphql
+spec:
+  rules:
+    - host: graphql.dictybase.org
+---
+apiVersion: batch/v1`, 1)
+	updated = updated[:strings.Index(updated, "---\napiVersion: v1\nkind: Secret")]
+	current, err := ParseManifests("current", updated)
+	require.NoError(t, err)
+
+	comparison := Compare(previous, current)
+	assert.Equal(t, 1, comparison.Count(StatusAdded))
+	assert.Equal(t, 2, comparison.Count(StatusChanged))
+	assert.Equal(t, 1, comparison.Count(StatusRemoved))
+	assert.Equal(t, 1, comparison.Unchanged)
+	require.Len(t, comparison.Changes, 4)
+
+	assert.Equal(t, "Ingress", comparison.Changes[0].Resource.Kind)
+	deployment := comparison.Changes[1]
+	assert.Equal(t, "Deployment", deployment.Resource.Kind)
+	assert.Equal(t, []string{
+		"replicas 2 → 3",
+		"container `server` image `dictybase/graphql-server:2.1.0` → `dictybase/graphql-server:2.2.0`",
+		"container `server` env `LOG_LEVEL` changed: `info` → `debug`",
+		"container `server` env `DB_PASSWORD` changed: `(redacted)` → `(redacted)`",
+		"container `server` env `REDIS_PORT` removed",
+		"container `server` changed: `ports`",
+	}, deployment.Details)
+	service := comparison.Changes[2]
+	assert.Equal(t, []string{"other changes: `spec.ports`"}, service.Details)
+	assert.Equal(t, StatusRemoved, comparison.Changes[3].Status)
+	assert.Equal(t, "Secret", comparison.Changes[3].Resource.Kind)
+}
+
+func TestChangedFields(t *testing.T) {
+	t.Parallel()
+	old := map[string]any{
+		"metadata": map[string]any{"labels": map[string]any{"version": "1"}},
+		"spec":     map[string]any{"replicas": 1, "strategy": "Recreate"},
+	}
+	current := map[string]any{
+		"metadata": map[string]any{"labels": map[string]any{"version": "2"}, "annotations": map[string]any{}},
+		"spec":     map[string]any{"replicas": 2, "strategy": "Recreate"},
+	}
+	assert.Equal(t,
+		[]string{"metadata.annotations", "metadata.labels.version"},
+		changedFields(old, current, "", []string{"spec.replicas"}),
+	)
+}
+
+func TestFormatFields(t *testing.T) {
+	t.Parallel()
+	fields := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	assert.Equal(t, "`a`, `b`, `c`, `d`, `e`, `f`, `g`, `h` and 2 more", formatFields(fields))
+}
@@ -0,0 +1,407 @@
+package k8stool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+const (
+	// previousIDArgument is the argument naming uploaded previous
+	// manifests.
+	previousIDArgument = "previous_upload_id"
+	// maxManifestSize is the size of the largest file read from a
+	// repository.
+	maxManifestSize = 1 << 20
+)
+
+// K8sTool summarizes Kubernetes manifests.
+type K8sTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+	resources   *resources.Catalog
+	uploads     *upload.Store
+}
+
+// Option defines a functional option for configuring K8sTool.
+type Option func(*K8sTool)
+
+// WithResources publishes generated summaries as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(k *K8sTool) {
+		k.resources = catalog
+	}
+}
+
+// WithUploads lets calls pass the manifests as completed uploads.
+func WithUploads(store *upload.Store) Option {
+	return func(k *K8sTool) {
+		k.uploads = store
+	}
+}
+
+// WithAnalyzer replaces the analyzer repositories are cloned with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) Option {
+	return func(k *K8sTool) {
+		k.analyzer = analyzer
+	}
+}
+
+// SummaryRequest represents the parameters for a manifest summary.
+type SummaryRequest struct {
+	Source  string `validate:"required,oneof=manifests repo"`
+	RepoURL string `validate:"required_if=Source repo"`
+	Branch  string `validate:"required_if=Source repo"`
+	// Path is a manifest file or a directory of manifests in the
+	// repository; empty reads the whole repository.
+	Path string
+	// FromRef is the ref of the previous version in the repository.
+	FromRef string `validate:"excluded_unless=Source repo"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"k8s-manifest-summary",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewK8sTool(deps.Logger, WithResources(deps.Resources), WithUploads(deps.Uploads))
+		},
+	)
+}
+
+// NewK8sTool creates a new K8sTool instance.
+func NewK8sTool(logger *slog.Logger, opts ...Option) (*K8sTool, error) {
+	tool := mcp.NewTool(
+		"k8s-manifest-summary",
+		mcp.WithDescription(
+			"Summarizes Kubernetes YAML manifests, passed inline or read from a path of a git repository, "+
+				"listing their resources, images and environment variables, and the changes since a previous "+
+				"version of the manifests",
+		),
+		mcp.WithTitleAnnotation("Kubernetes Manifest Summary"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"source",
+			mcp.Description(
+				"Where the manifests come from: 'manifests' (default) reads the manifests argument; "+
+					"'repo' reads the YAML files under path in a clone of the branch",
+			),
+			mcp.Enum(SourceManifests, SourceRepo),
+		),
+		mcp.WithString(
+			"manifests",
+			mcp.Description("YAML manifests, several documents separated by '---', for the manifests source"),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the manifests, instead of manifests"),
+		),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository, for the repo source"),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to read, for the repo source"),
+		),
+		mcp.WithString(
+			"path",
+			mcp.Description("A manifest file or directory in the repository (optional, defaults to the whole repository)"),
+		),
+		mcp.WithString(
+			"from_ref",
+			mcp.Description("Compare with the manifests at this tag, branch or commit, for the repo source"),
+		),
+		mcp.WithString(
+			"previous",
+			mcp.Description("A previous version of the manifests to compare with"),
+		),
+		mcp.WithString(
+			previousIDArgument,
+			mcp.Description("Handle of a completed upload holding the previous manifests, instead of previous"),
+		),
+	)
+	k8sTool := &K8sTool{
+		Name:        "k8s-manifest-summary",
+		Description: "Summarizes Kubernetes manifests",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(k8sTool)
+	}
+	return k8sTool, nil
+}
+
+// GetName returns the name of the tool.
+func (k *K8sTool) GetName() string {
+	return k.Name
+}
+
+// GetDescription returns the description of the tool.
+func (k *K8sTool) GetDescription() string {
+	return k.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (k *K8sTool) GetSchema() mcp.ToolInputSchema {
+	return k.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (k *K8sTool) GetTool() mcp.Tool {
+	return k.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (k *K8sTool) GetAnnotations() mcp.ToolAnnotation {
+	return k.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (k *K8sTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize the manifests under a directory of a repository and the changes since a tag",
+			Arguments: map[string]any{
+				"source":   "repo",
+				"repo_url": "https://github.com/dictybase-docker/cluster-manifests",
+				"branch":   "main",
+				"path":     "apps/stock-center",
+				"from_ref": "v1.4.0",
+			},
+		},
+		{
+			Description: "Summarize manifests passed inline",
+			Arguments: map[string]any{
+				"source":    "manifests",
+				"manifests": "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: stock-center\ndata:\n  LOG_LEVEL: info\n",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (k *K8sTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := SummaryRequest{
+		Source:  request.GetString("source", SourceManifests),
+		RepoURL: request.GetString("repo_url", ""),
+		Branch:  request.GetString("branch", ""),
+		Path:    strings.Trim(request.GetString("path", ""), "/"),
+		FromRef: request.GetString("from_ref", ""),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	previous, err := k.readPrevious(request)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	if previous != nil && params.FromRef != "" {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_INPUT",
+			"pass either from_ref or previous manifests to compare with, not both",
+		)), nil
+	}
+	var summary Summary
+	if params.Source == SourceRepo {
+		summary, err = k.SummarizeRepo(ctx, params)
+	} else {
+		summary, err = k.readManifests(request)
+	}
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	if previous != nil {
+		comparison := Compare(previous, summary.Resources)
+		summary.Comparison = &comparison
+	}
+
+	content := RenderMarkdown(summary)
+	result := mcp.NewToolResultText(content)
+	name := "k8s-manifests.md"
+	if params.Source == SourceRepo {
+		result = provenance.Attach(result, provenance.New([]string{metrics.ServiceGitClone}))
+		name = fmt.Sprintf(
+			"%s-%s-k8s-manifests-%s.md",
+			worksummary.RepoName(params.RepoURL), params.Branch, worksummary.ShortHash(summary.Revision.Hash),
+		)
+	}
+	if k.resources != nil {
+		resource, err := k.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        name,
+			MIMEType:    "text/markdown",
+			Description: "Kubernetes manifest summary",
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing summary: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// readManifests reads the manifests passed to the call.
+func (k *K8sTool) readManifests(request mcp.CallToolRequest) (Summary, error) {
+	text, err := upload.TextArgument(k.uploads, request, "manifests")
+	if err != nil {
+		return Summary{}, err
+	}
+	found, err := ParseManifests("manifests", text)
+	if err != nil {
+		return Summary{}, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_MANIFESTS", err, "invalid manifests")
+	}
+	return Summary{Source: SourceManifests, Resources: found}, nil
+}
+
+// readPrevious reads the previous manifests, if the call passes them.
+func (k *K8sTool) readPrevious(request mcp.CallToolRequest) ([]Resource, error) {
+	arguments := request.GetArguments()
+	if arguments["previous"] == nil && arguments[previousIDArgument] == nil {
+		return nil, nil
+	}
+	text, err := upload.TextArgumentFrom(k.uploads, request, "previous", previousIDArgument)
+	if err != nil {
+		return nil, err
+	}
+	found, err := ParseManifests("previous", text)
+	if err != nil {
+		return nil, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_PREVIOUS", err, "invalid previous manifests")
+	}
+	if found == nil {
+		found = []Resource{}
+	}
+	return found, nil
+}
+
+// SummarizeRepo clones the branch and reads the manifests under the path
+// at its head and, when a from_ref is given, at that ref.
+func (k *K8sTool) SummarizeRepo(ctx context.Context, params SummaryRequest) (Summary, error) {
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := k.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.8)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Summary{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+
+	reporter.Report(0.8, 1, "reading manifests")
+	head, err := worksummary.ResolveRef(repo.Repository, "HEAD")
+	if err != nil {
+		return Summary{}, err
+	}
+	summary := Summary{
+		Source:   SourceRepo,
+		RepoURL:  params.RepoURL,
+		Branch:   params.Branch,
+		Path:     params.Path,
+		Revision: Revision{Hash: head.Hash.String(), When: head.Committer.When, Ref: params.Branch},
+	}
+	var files int
+	summary.Resources, summary.Skipped, files, err = ReadCommit(head, params.Path)
+	if err != nil {
+		return Summary{}, err
+	}
+	if files == 0 && params.Path != "" {
+		return Summary{}, toolerror.New(
+			toolerror.TypeNotFound,
+			"PATH_NOT_FOUND",
+			fmt.Sprintf("%s has no YAML file at %q", params.Branch, params.Path),
+		)
+	}
+	if params.FromRef != "" {
+		from, err := worksummary.ResolveRef(repo.Repository, params.FromRef)
+		if err != nil {
+			return Summary{}, err
+		}
+		previous, _, _, err := ReadCommit(from, params.Path)
+		if err != nil {
+			return Summary{}, err
+		}
+		comparison := Compare(previous, summary.Resources)
+		summary.Comparison = &comparison
+		summary.Previous = &Revision{Hash: from.Hash.String(), When: from.Committer.When, Ref: params.FromRef}
+	}
+	reporter.Report(1, 1, "summary generated")
+	k.Logger.Debug("read manifests", "repo", params.RepoURL, "path", params.Path, "resources", len(summary.Resources))
+	return summary, nil
+}
+
+// ReadCommit reads the manifests of the YAML files under dir in the tree
+// of commit, returning the files that could not be parsed apart along with
+// the number of YAML files found. A dir naming a file reads that file;
+// an empty dir reads the whole tree.
+func ReadCommit(commit *object.Commit, dir string) ([]Resource, []SkippedFile, int, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, nil, 0, fmt.Errorf("error reading file tree of %s: %w", commit.Hash, err)
+	}
+	var (
+		found   []Resource
+		skipped []SkippedFile
+		files   int
+	)
+	err = tree.Files().ForEach(func(file *object.File) error {
+		if dir != "" && file.Name != dir && !strings.HasPrefix(file.Name, dir+"/") {
+			return nil
+		}
+		if !IsManifest(file.Name) {
+			return nil
+		}
+		files++
+		if file.Size > maxManifestSize {
+			skipped = append(skipped, SkippedFile{Path: file.Name, Reason: "larger than 1 MiB"})
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		parsed, err := ParseManifests(file.Name, content)
+		if err != nil {
+			skipped = append(skipped, SkippedFile{Path: file.Name, Reason: err.Error()})
+			return nil
+		}
+		found = append(found, parsed...)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, 0, err
+	}
+	return found, skipped, files, nil
+}
@@ -0,0 +1,156 @@
+package k8stool
+
+import (
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T, opts ...Option) *K8sTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(worksummary.WithTimeZone(time.UTC))
+	tool, err := NewK8sTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]Option{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestHandler_Manifests(t *testing.T) {
+	t.Parallel()
+	store := upload.NewStore()
+	info, err := store.Begin(upload.BeginParams{Name: "deploy.yaml", ContentType: "text/plain"})
+	require.NoError(t, err)
+	_, err = store.Append(upload.AppendParams{ID: info.ID, Data: []byte(deploymentManifest)})
+	require.NoError(t, err)
+	_, err = store.Complete(info.ID, "")
+	require.NoError(t, err)
+	tool := newTestTool(t, WithUploads(store))
+
+	text := tooltest.Text(t, tooltest.Call(t, tool.Handler, "k8s-manifest-summary", map[string]any{
+		"upload_id": info.ID,
+		"previous":  strings.Replace(deploymentManifest, "replicas: 2", "replicas: 1", 1),
+	}))
+	assert.Contains(t, text, "1 resources: 1 Deployment.\n")
+	assert.Contains(t, text, "| server | `DB_PASSWORD` | `(redacted)` |\n")
+	assert.NotContains(t, text, "hunter2")
+	assert.Contains(t, text, "### Changed\n\n- Deployment `dictybase/graphql-server`\n  - replicas 1 → 2\n")
+}
+
+func TestHandler_Repo(t *testing.T) {
+	t.Parallel()
+	dir := gittest.NewRepo(t,
+		gittest.Commit{Tag: "v1.0.0", Files: map[string]string{
+			"deploy/app.yaml":           deploymentManifest,
+			"deploy/chart/deploy.yaml":  "metadata:\n  name: {{ .Values.name }\n",
+			"docs/mkdocs.yml":           "site_name: docs\n",
+			"deploy/kustomization.yaml": "resources:\n  - app.yaml\n",
+		}},
+		gittest.Commit{
+			Files: map[string]string{
+				"deploy/app.yaml": strings.Replace(deploymentManifest, "graphql-server:2.1.0", "graphql-server:2.2.0", 1),
+			},
+			Author: object.Signature{When: gittest.Author.When.AddDate(0, 0, 1)},
+		},
+	).Dir
+	tool := newTestTool(t)
+
+	text := tooltest.Text(t, tooltest.Call(t, tool.Handler, "k8s-manifest-summary", map[string]any{
+		"source":   "repo",
+		"repo_url": dir,
+		"branch":   "master",
+		"path":     "/deploy/",
+		"from_ref": "v1.0.0",
+	}))
+	assert.Contains(t, text, "**Repository:** "+dir+" (branch `master`")
+	assert.Contains(t, text, "**Path:** `deploy`\n**Compared with:** `v1.0.0` (commit ")
+	assert.Contains(t, text, "1 resources in 1 files: 1 Deployment.\n")
+	assert.Contains(t, text, "Since the previous version: 0 added, 1 changed, 0 removed, 0 unchanged.\n")
+	assert.Contains(t, text,
+		"  - container `server` image `dictybase/graphql-server:2.1.0` → `dictybase/graphql-server:2.2.0`\n")
+	assert.Contains(t, text, "## Skipped Files\n\n- `deploy/chart/deploy.yaml`: ")
+	assert.NotContains(t, text, "mkdocs")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	dir := gittest.NewRepo(t, gittest.Commit{Tag: "v1.0.0", Files: map[string]string{"deploy/app.yaml": deploymentManifest}}).Dir
+	tests := []struct {
+		name      string
+		arguments map[string]any
+		errorType toolerror.Type
+		code      string
+	}{
+		{
+			name:      "missing manifests",
+			arguments: map[string]any{},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "MISSING_PARAMETER",
+		},
+		{
+			name:      "invalid manifests",
+			arguments: map[string]any{"manifests": "kind: [Pod"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_MANIFESTS",
+		},
+		{
+			name:      "invalid previous",
+			arguments: map[string]any{"manifests": deploymentManifest, "previous": "kind: [Pod"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_PREVIOUS",
+		},
+		{
+			name:      "from_ref without repo",
+			arguments: map[string]any{"manifests": deploymentManifest, "from_ref": "v1.0.0"},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_INPUT",
+		},
+		{
+			name: "from_ref and previous",
+			arguments: map[string]any{
+				"source": "repo", "repo_url": dir, "branch": "master", "from_ref": "v1.0.0", "previous": deploymentManifest,
+			},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_INPUT",
+		},
+		{
+			name:      "repo without branch",
+			arguments: map[string]any{"source": "repo", "repo_url": dir},
+			errorType: toolerror.TypeInvalidInput,
+			code:      "INVALID_INPUT",
+		},
+		{
+			name:      "missing path",
+			arguments: map[string]any{"source": "repo", "repo_url": dir, "branch": "master", "path": "helm"},
+			errorType: toolerror.TypeNotFound,
+			code:      "PATH_NOT_FOUND",
+		},
+		{
+			name:      "unknown ref",
+			arguments: map[string]any{"source": "repo", "repo_url": dir, "branch": "master", "from_ref": "v9.9.9"},
+			errorType: toolerror.TypeNotFound,
+			code:      "REF_NOT_FOUND",
+		},
+	}
+	tool := newTestTool(t)
+	for _, testCase := range tests {
+		result := tooltest.Call(t, tool.Handler, "k8s-manifest-summary", testCase.arguments)
+		require.True(t, result.IsError, testCase.name)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok, testCase.name)
+		assert.Equal(t, testCase.errorType, toolErr.Type, testCase.name)
+		assert.Equal(t, testCase.code, toolErr.Code, testCase.name)
+	}
+}
@@ -0,0 +1,335 @@
+package k8stool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// redacted replaces the values of environment variables that look secret.
+const redacted = "(redacted)"
+
+// sensitiveNameRegex matches the names of environment variables whose
+// literal values are kept out of reports.
+var sensitiveNameRegex = regexp.MustCompile(`(?i)passw(or)?d|secret|token|api_?key|private_?key|credential`)
+
+// podSpecPaths are the paths of the pod spec within each workload kind.
+var podSpecPaths = map[string][]string{
+	"Pod":                   {"spec"},
+	"Deployment":            {"spec", "template", "spec"},
+	"StatefulSet":           {"spec", "template", "spec"},
+	"DaemonSet":             {"spec", "template", "spec"},
+	"ReplicaSet":            {"spec", "template", "spec"},
+	"ReplicationController": {"spec", "template", "spec"},
+	"Job":                   {"spec", "template", "spec"},
+	"CronJob":               {"spec", "jobTemplate", "spec", "template", "spec"},
+}
+
+// Resource is a Kubernetes object of a manifest with the fields the
+// summary reports.
+type Resource struct {
+	APIVersion string
+	Kind       string
+	Name       string
+	Namespace  string
+	// File is the manifest file the object was read from.
+	File string
+	// Replicas is nil for kinds without replicas or when it is left to
+	// the default.
+	Replicas   *int
+	Containers []Container
+	// ServiceType and Ports describe services.
+	ServiceType string
+	Ports       []string
+	// Hosts are the hosts an ingress routes.
+	Hosts []string
+	// Schedule is the schedule of a cron job.
+	Schedule string
+	// DataKeys are the keys of a config map or secret; secret values are
+	// never read.
+	DataKeys []string
+	// Object is the whole object, for finding the fields that changed.
+	Object map[string]any
+}
+
+// ID identifies the resource within a set of manifests.
+func (r Resource) ID() string {
+	return r.Kind + "/" + r.Namespace + "/" + r.Name
+}
+
+// DisplayName names the resource, qualified by its namespace when it has
+// one.
+func (r Resource) DisplayName() string {
+	if r.Namespace == "" {
+		return r.Name
+	}
+	return r.Namespace + "/" + r.Name
+}
+
+// Container is a container or init container of a workload.
+type Container struct {
+	Name  string
+	Image string
+	Init  bool
+	Env   []EnvVar
+	Ports []string
+	// Object is the whole container, for finding the fields that changed.
+	Object map[string]any
+}
+
+// EnvVar is an environment variable of a container. Variables imported
+// wholesale through envFrom are named by their prefix followed by "*".
+type EnvVar struct {
+	Name string
+	// Value is the literal value, redacted when the name looks secret.
+	Value string
+	// Source tells where a variable not given literally comes from, such
+	// as "secret `db/password`".
+	Source string
+	// fingerprint tells redacted values apart without revealing them.
+	fingerprint [sha256.Size]byte
+}
+
+// Describe renders the value of the variable for a report.
+func (e EnvVar) Describe() string {
+	if e.Source != "" {
+		return e.Source
+	}
+	return "`" + e.Value + "`"
+}
+
+// IsManifest reports whether name is a YAML file.
+func IsManifest(name string) bool {
+	extension := strings.ToLower(path.Ext(name))
+	return extension == ".yaml" || extension == ".yml"
+}
+
+// ParseManifests reads the Kubernetes objects of the YAML documents in
+// content. Documents that are not Kubernetes objects, such as
+// kustomization files, are skipped; lists are expanded into their items.
+func ParseManifests(file, content string) ([]Resource, error) {
+	decoder := yaml.NewDecoder(bytes.NewBufferString(content))
+	var found []Resource
+	for index := 1; ; index++ {
+		var document any
+		err := decoder.Decode(&document)
+		if errors.Is(err, io.EOF) {
+			return found, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error parsing document %d of %s: %w", index, file, err)
+		}
+		object, ok := document.(map[string]any)
+		if !ok {
+			continue
+		}
+		if kind := stringAt(object, "kind"); strings.HasSuffix(kind, "List") && object["items"] != nil {
+			for _, item := range listAt(object, "items") {
+				if itemObject, ok := item.(map[string]any); ok && isObject(itemObject) {
+					found = append(found, NewResource(file, itemObject))
+				}
+			}
+			continue
+		}
+		if isObject(object) {
+			found = append(found, NewResource(file, object))
+		}
+	}
+}
+
+// isObject reports whether a YAML document is a Kubernetes object.
+func isObject(object map[string]any) bool {
+	return stringAt(object, "apiVersion") != "" && stringAt(object, "kind") != ""
+}
+
+// NewResource reads the reported fields of a Kubernetes object.
+func NewResource(file string, object map[string]any) Resource {
+	resource := Resource{
+		APIVersion: stringAt(object, "apiVersion"),
+		Kind:       stringAt(object, "kind"),
+		Name:       stringAt(object, "metadata", "name"),
+		Namespace:  stringAt(object, "metadata", "namespace"),
+		File:       file,
+		Object:     object,
+	}
+	if resource.Name == "" {
+		resource.Name = stringAt(object, "metadata", "generateName") + "*"
+	}
+	if replicas, ok := valueAt(object, "spec", "replicas").(int); ok {
+		resource.Replicas = &replicas
+	}
+	if podSpec, ok := podSpecPaths[resource.Kind]; ok {
+		spec := mapAt(object, podSpec...)
+		resource.Containers = append(readContainers(listAt(spec, "initContainers"), true),
+			readContainers(listAt(spec, "containers"), false)...)
+	}
+	switch resource.Kind {
+	case "Service":
+		resource.ServiceType = stringAt(object, "spec", "type")
+		if resource.ServiceType == "" {
+			resource.ServiceType = "ClusterIP"
+		}
+		for _, port := range listAt(object, "spec", "ports") {
+			resource.Ports = append(resource.Ports, servicePort(asMap(port)))
+		}
+	case "Ingress":
+		for _, rule := range listAt(object, "spec", "rules") {
+			if host := stringAt(asMap(rule), "host"); host != "" {
+				resource.Hosts = append(resource.Hosts, host)
+			}
+		}
+	case "CronJob":
+		resource.Schedule = stringAt(object, "spec", "schedule")
+	case "ConfigMap", "Secret":
+		keys := make(map[string]bool)
+		for _, field := range []string{"data", "stringData", "binaryData"} {
+			for key := range mapAt(object, field) {
+				keys[key] = true
+			}
+		}
+		resource.DataKeys = slices.Sorted(maps.Keys(keys))
+	}
+	return resource
+}
+
+// readContainers reads the containers of a pod spec.
+func readContainers(items []any, init bool) []Container {
+	containers := make([]Container, 0, len(items))
+	for _, item := range items {
+		object := asMap(item)
+		container := Container{
+			Name:   stringAt(object, "name"),
+			Image:  stringAt(object, "image"),
+			Init:   init,
+			Object: object,
+		}
+		for _, env := range listAt(object, "env") {
+			container.Env = append(container.Env, readEnvVar(asMap(env)))
+		}
+		for _, envFrom := range listAt(object, "envFrom") {
+			container.Env = append(container.Env, readEnvFrom(asMap(envFrom)))
+		}
+		for _, port := range listAt(object, "ports") {
+			container.Ports = append(container.Ports, containerPort(asMap(port)))
+		}
+		containers = append(containers, container)
+	}
+	return containers
+}
+
+// readEnvVar reads an env entry of a container.
+func readEnvVar(env map[string]any) EnvVar {
+	variable := EnvVar{Name: stringAt(env, "name")}
+	switch {
+	case mapAt(env, "valueFrom", "secretKeyRef") != nil:
+		ref := mapAt(env, "valueFrom", "secretKeyRef")
+		variable.Source = fmt.Sprintf("secret `%s/%s`", stringAt(ref, "name"), stringAt(ref, "key"))
+	case mapAt(env, "valueFrom", "configMapKeyRef") != nil:
+		ref := mapAt(env, "valueFrom", "configMapKeyRef")
+		variable.Source = fmt.Sprintf("config map `%s/%s`", stringAt(ref, "name"), stringAt(ref, "key"))
+	case mapAt(env, "valueFrom", "fieldRef") != nil:
+		variable.Source = fmt.Sprintf("field `%s`", stringAt(env, "valueFrom", "fieldRef", "fieldPath"))
+	case mapAt(env, "valueFrom", "resourceFieldRef") != nil:
+		variable.Source = fmt.Sprintf("resource `%s`", stringAt(env, "valueFrom", "resourceFieldRef", "resource"))
+	case sensitiveNameRegex.MatchString(variable.Name) && stringAt(env, "value") != "":
+		variable.Value = redacted
+		variable.fingerprint = sha256.Sum256([]byte(stringAt(env, "value")))
+	default:
+		variable.Value = stringAt(env, "value")
+	}
+	return variable
+}
+
+// readEnvFrom reads an envFrom entry of a container, which imports every
+// key of a config map or secret.
+func readEnvFrom(envFrom map[string]any) EnvVar {
+	variable := EnvVar{Name: stringAt(envFrom, "prefix") + "*"}
+	if name := stringAt(envFrom, "secretRef", "name"); name != "" {
+		variable.Source = fmt.Sprintf("all keys of secret `%s`", name)
+	} else {
+		variable.Source = fmt.Sprintf("all keys of config map `%s`", stringAt(envFrom, "configMapRef", "name"))
+	}
+	return variable
+}
+
+// servicePort renders a service port as "name 80→8080/TCP".
+func servicePort(port map[string]any) string {
+	rendered := stringAt(port, "port")
+	if target := stringAt(port, "targetPort"); target != "" && target != rendered {
+		rendered += "→" + target
+	}
+	if nodePort := stringAt(port, "nodePort"); nodePort != "" {
+		rendered += " (node " + nodePort + ")"
+	}
+	return withProtocol(port, rendered)
+}
+
+// containerPort renders a container port as "name 8080/TCP".
+func containerPort(port map[string]any) string {
+	return withProtocol(port, stringAt(port, "containerPort"))
+}
+
+// withProtocol adds the protocol and name of a port to its number.
+func withProtocol(port map[string]any, number string) string {
+	protocol := stringAt(port, "protocol")
+	if protocol == "" {
+		protocol = "TCP"
+	}
+	rendered := number + "/" + protocol
+	if name := stringAt(port, "name"); name != "" {
+		rendered = name + " " + rendered
+	}
+	return rendered
+}
+
+// valueAt returns the value at a path of mapping keys, or nil.
+func valueAt(object map[string]any, keys ...string) any {
+	var value any = object
+	for _, key := range keys {
+		mapping, ok := value.(map[string]any)
+		if !ok {
+			return nil
+		}
+		value = mapping[key]
+	}
+	return value
+}
+
+// mapAt returns the mapping at a path, or nil.
+func mapAt(object map[string]any, keys ...string) map[string]any {
+	return asMap(valueAt(object, keys...))
+}
+
+// listAt returns the sequence at a path, or nil.
+func listAt(object map[string]any, keys ...string) []any {
+	list, _ := valueAt(object, keys...).([]any)
+	return list
+}
+
+// stringAt returns the scalar at a path as a string, or "". Numbers and
+// booleans are formatted, since manifests often leave them unquoted.
+func stringAt(object map[string]any, keys ...string) string {
+	switch value := valueAt(object, keys...).(type) {
+	case nil, map[string]any, []any:
+		return ""
+	case string:
+		return value
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// asMap returns value as a mapping, or nil.
+func asMap(value any) map[string]any {
+	mapping, _ := value.(map[string]any)
+	return mapping
+}
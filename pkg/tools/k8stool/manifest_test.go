@@ -0,0 +1,163 @@
+package k8stool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const deploymentManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: graphql-server
+  namespace: dictybase
+spec:
+  replicas: 2
+  template:
+    spec:
+      initContainers:
+        - name: migrate
+          image: dictybase/migrate:1.0.0
+      containers:
+        - name: server
+          image: dictybase/graphql-server:2.1.0
+          ports:
+            - name: http
+              containerPort: 8080
+          env:
+            - name: LOG_LEVEL
+              value: info
+            - name: DB_PASSWORD
+              value: hunter2
+            - name: REDIS_PORT
+              value: 6379
+            - name: ARANGO_PASS
+              valueFrom:
+                secretKeyRef:
+                  name: arango
+                  key: password
+            - name: POD_NAME
+              valueFrom:
+                fieldRef:
+                  fieldPath: metadata.name
+          envFrom:
+            - configMapRef:
+                name: graphql-config
+              prefix: GQL_
+`
+
+const serviceManifest = `---
+apiVersion: v1
+kind: Service
+metadata:
+  name: graphql-server
+  namespace: dictybase
+spec:
+  ports:
+    - name: http
+      port: 80
+      targetPort: 8080
+---
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: backup
+spec:
+  schedule: "0 2 * * *"
+  jobTemplate:
+    spec:
+      template:
+        spec:
+          containers:
+            - name: backup
+              image: dictybase/backup:0.3.0
+---
+apiVersion: v1
+kind: Secret
+metadata:
+  name: arango
+stringData:
+  password: secret
+  user: root
+`
+
+func TestParseManifests(t *testing.T) {
+	t.Parallel()
+	found, err := ParseManifests("deploy.yaml", deploymentManifest+serviceManifest)
+	require.NoError(t, err)
+	require.Len(t, found, 4)
+
+	deployment := found[0]
+	assert.Equal(t, "Deployment/dictybase/graphql-server", deployment.ID())
+	assert.Equal(t, "dictybase/graphql-server", deployment.DisplayName())
+	require.NotNil(t, deployment.Replicas)
+	assert.Equal(t, 2, *deployment.Replicas)
+	require.Len(t, deployment.Containers, 2)
+	assert.Equal(t, "migrate", deployment.Containers[0].Name)
+	assert.True(t, deployment.Containers[0].Init)
+	server := deployment.Containers[1]
+	assert.Equal(t, "dictybase/graphql-server:2.1.0", server.Image)
+	assert.Equal(t, []string{"http 8080/TCP"}, server.Ports)
+	assert.Equal(t, []string{
+		"`info`",
+		"`(redacted)`",
+		"`6379`",
+		"secret `arango/password`",
+		"field `metadata.name`",
+		"all keys of config map `graphql-config`",
+	}, describeAll(server.Env))
+	assert.Equal(t, "GQL_*", server.Env[5].Name)
+
+	service := found[1]
+	assert.Equal(t, "ClusterIP", service.ServiceType)
+	assert.Equal(t, []string{"http 80→8080/TCP"}, service.Ports)
+	assert.Equal(t, "0 2 * * *", found[2].Schedule)
+	assert.Equal(t, "dictybase/backup:0.3.0", found[2].Containers[0].Image)
+	assert.Equal(t, []string{"password", "user"}, found[3].DataKeys)
+	assert.Equal(t, "deploy.yaml", found[3].File)
+}
+
+func TestParseManifestsSkipsOtherDocuments(t *testing.T) {
+	t.Parallel()
+	found, err := ParseManifests("kustomization.yaml", `resources:
+  - deploy.yaml
+---
+---
+apiVersion: v1
+kind: List
+items:
+  - apiVersion: v1
+    kind: ConfigMap
+    metadata:
+      name: settings
+    data:
+      LOG_LEVEL: debug
+`)
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, "ConfigMap", found[0].Kind)
+	assert.Equal(t, []string{"LOG_LEVEL"}, found[0].DataKeys)
+}
+
+func TestParseManifestsInvalid(t *testing.T) {
+	t.Parallel()
+	_, err := ParseManifests("chart/templates/deploy.yaml", "apiVersion: v1\nkind: Pod\nmetadata:\n  name: {{ .Values.name }\n")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "document 1 of chart/templates/deploy.yaml")
+}
+
+func TestIsManifest(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsManifest("deploy/app.yaml"))
+	assert.True(t, IsManifest("deploy/app.YML"))
+	assert.False(t, IsManifest("deploy/README.md"))
+}
+
+func describeAll(variables []EnvVar) []string {
+	described := make([]string, 0, len(variables))
+	for _, variable := range variables {
+		described = append(described, variable.Describe())
+	}
+	return described
+}
@@ -0,0 +1,252 @@
+package k8stool
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Sources of the manifests.
+const (
+	// SourceManifests are manifests passed to the tool.
+	SourceManifests = "manifests"
+	// SourceRepo are the manifests of a path in a git repository.
+	SourceRepo = "repo"
+)
+
+// Revision is a commit the manifests were read at.
+type Revision struct {
+	Hash string
+	When time.Time
+	// Ref is the ref the commit was resolved from.
+	Ref string
+}
+
+// SkippedFile is a YAML file of a repository that could not be parsed,
+// such as a Helm template.
+type SkippedFile struct {
+	Path   string
+	Reason string
+}
+
+// Summary describes a set of manifests, optionally compared with a
+// previous version.
+type Summary struct {
+	Source string
+	// RepoURL, Branch, Path and Revision are only known for the repo
+	// source.
+	RepoURL   string
+	Branch    string
+	Path      string
+	Revision  Revision
+	Resources []Resource
+	Skipped   []SkippedFile
+	// Previous is the revision compared with, set when the previous
+	// version was read from the repository.
+	Previous *Revision
+	// Comparison is nil without a previous version.
+	Comparison *Comparison
+}
+
+// RenderMarkdown renders the summary as a markdown document.
+func RenderMarkdown(summary Summary) string {
+	var builder strings.Builder
+	if summary.Source == SourceRepo {
+		fmt.Fprintf(&builder, "# Kubernetes Manifests: %s\n\n", worksummary.RepoName(summary.RepoURL))
+		fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`, commit `%s` of %s)\n",
+			summary.RepoURL, summary.Branch, worksummary.ShortHash(summary.Revision.Hash),
+			summary.Revision.When.Format(time.DateOnly))
+		if summary.Path != "" {
+			fmt.Fprintf(&builder, "**Path:** `%s`\n", summary.Path)
+		}
+	} else {
+		builder.WriteString("# Kubernetes Manifests\n\n")
+	}
+	if summary.Previous != nil {
+		fmt.Fprintf(&builder, "**Compared with:** `%s` (commit `%s` of %s)\n",
+			summary.Previous.Ref, worksummary.ShortHash(summary.Previous.Hash), summary.Previous.When.Format(time.DateOnly))
+	} else if summary.Comparison != nil {
+		builder.WriteString("**Compared with:** the previous manifests\n")
+	}
+
+	builder.WriteString("\n## Overview\n\n")
+	if len(summary.Resources) == 0 {
+		builder.WriteString("The manifests define no Kubernetes resources.\n")
+	} else {
+		fmt.Fprintf(&builder, "%d resources", len(summary.Resources))
+		if summary.Source == SourceRepo {
+			fmt.Fprintf(&builder, " in %d files", countFiles(summary.Resources))
+		}
+		fmt.Fprintf(&builder, ": %s.\n", countKinds(summary.Resources))
+	}
+	if summary.Comparison != nil {
+		comparison := summary.Comparison
+		fmt.Fprintf(&builder, "Since the previous version: %d added, %d changed, %d removed, %d unchanged.\n",
+			comparison.Count(StatusAdded), comparison.Count(StatusChanged), comparison.Count(StatusRemoved),
+			comparison.Unchanged)
+	}
+
+	if len(summary.Resources) > 0 {
+		writeResources(&builder, summary)
+		writeImages(&builder, summary.Resources)
+		writeEnvironment(&builder, summary.Resources)
+	}
+	if summary.Comparison != nil && len(summary.Comparison.Changes) > 0 {
+		writeChanges(&builder, summary)
+	}
+	if len(summary.Skipped) > 0 {
+		builder.WriteString("\n## Skipped Files\n\n")
+		for _, skipped := range summary.Skipped {
+			fmt.Fprintf(&builder, "- `%s`: %s\n", skipped.Path, escapeCell(skipped.Reason))
+		}
+	}
+	return builder.String()
+}
+
+// writeResources writes the table of resources. Files are listed for
+// manifests read from a repository.
+func writeResources(builder *strings.Builder, summary Summary) {
+	if summary.Source == SourceRepo {
+		builder.WriteString("\n## Resources\n\n| Kind | Name | File | Details |\n|---|---|---|---|\n")
+	} else {
+		builder.WriteString("\n## Resources\n\n| Kind | Name | Details |\n|---|---|---|\n")
+	}
+	for _, resource := range summary.Resources {
+		fmt.Fprintf(builder, "| %s | `%s` |", resource.Kind, resource.DisplayName())
+		if summary.Source == SourceRepo {
+			fmt.Fprintf(builder, " `%s` |", resource.File)
+		}
+		fmt.Fprintf(builder, " %s |\n", escapeCell(resourceDetails(resource)))
+	}
+}
+
+// resourceDetails summarizes the kind specific fields of a resource.
+func resourceDetails(resource Resource) string {
+	var details []string
+	if resource.Replicas != nil {
+		details = append(details, fmt.Sprintf("%d replicas", *resource.Replicas))
+	}
+	if resource.Schedule != "" {
+		details = append(details, "schedule `"+resource.Schedule+"`")
+	}
+	if len(resource.Containers) > 0 {
+		details = append(details, fmt.Sprintf("%d containers", len(resource.Containers)))
+	}
+	if resource.ServiceType != "" {
+		details = append(details, resource.ServiceType)
+	}
+	if len(resource.Ports) > 0 {
+		details = append(details, "ports "+strings.Join(resource.Ports, ", "))
+	}
+	if len(resource.Hosts) > 0 {
+		details = append(details, "hosts "+strings.Join(resource.Hosts, ", "))
+	}
+	if len(resource.DataKeys) > 0 {
+		details = append(details, "keys "+strings.Join(resource.DataKeys, ", "))
+	}
+	return strings.Join(details, "; ")
+}
+
+// writeImages writes the images of the workloads with the containers
+// running them.
+func writeImages(builder *strings.Builder, resources []Resource) {
+	users := make(map[string][]string)
+	for _, resource := range resources {
+		for _, container := range resource.Containers {
+			users[container.Image] = append(users[container.Image],
+				fmt.Sprintf("%s `%s` (%s)", resource.Kind, resource.DisplayName(), container.Name))
+		}
+	}
+	if len(users) == 0 {
+		return
+	}
+	builder.WriteString("\n## Images\n\n| Image | Used by |\n|---|---|\n")
+	for _, image := range slices.Sorted(maps.Keys(users)) {
+		fmt.Fprintf(builder, "| `%s` | %s |\n", image, strings.Join(users[image], ", "))
+	}
+}
+
+// writeEnvironment writes the environment variables of each workload.
+// Literal values of variables that look secret are redacted.
+func writeEnvironment(builder *strings.Builder, resources []Resource) {
+	written := false
+	for _, resource := range resources {
+		var rows []string
+		for _, container := range resource.Containers {
+			for _, variable := range container.Env {
+				rows = append(rows, fmt.Sprintf("| %s | `%s` | %s |",
+					container.Name, variable.Name, escapeCell(variable.Describe())))
+			}
+		}
+		if len(rows) == 0 {
+			continue
+		}
+		if !written {
+			builder.WriteString("\n## Environment\n")
+			written = true
+		}
+		fmt.Fprintf(builder, "\n### %s `%s`\n\n| Container | Variable | Value |\n|---|---|---|\n",
+			resource.Kind, resource.DisplayName())
+		builder.WriteString(strings.Join(rows, "\n") + "\n")
+	}
+}
+
+// writeChanges writes the resources added, changed and removed.
+func writeChanges(builder *strings.Builder, summary Summary) {
+	builder.WriteString("\n## Changes\n")
+	for _, status := range []string{StatusAdded, StatusChanged, StatusRemoved} {
+		var lines []string
+		for _, change := range summary.Comparison.Changes {
+			if change.Status != status {
+				continue
+			}
+			resource := change.Resource
+			line := fmt.Sprintf("- %s `%s`", resource.Kind, resource.DisplayName())
+			if summary.Source == SourceRepo {
+				line += fmt.Sprintf(" (`%s`)", resource.File)
+			}
+			for _, detail := range change.Details {
+				line += "\n  - " + detail
+			}
+			lines = append(lines, line)
+		}
+		if len(lines) == 0 {
+			continue
+		}
+		fmt.Fprintf(builder, "\n### %s%s\n\n%s\n", strings.ToUpper(status[:1]), status[1:], strings.Join(lines, "\n"))
+	}
+}
+
+// countFiles returns the number of files the resources were read from.
+func countFiles(resources []Resource) int {
+	files := make(map[string]bool)
+	for _, resource := range resources {
+		files[resource.File] = true
+	}
+	return len(files)
+}
+
+// countKinds renders the number of resources of each kind, most common
+// first.
+func countKinds(resources []Resource) string {
+	counts := make(map[string]int)
+	for _, resource := range resources {
+		counts[resource.Kind]++
+	}
+	kinds := slices.Sorted(maps.Keys(counts))
+	slices.SortStableFunc(kinds, func(a, b string) int { return counts[b] - counts[a] })
+	rendered := make([]string, 0, len(kinds))
+	for _, kind := range kinds {
+		rendered = append(rendered, fmt.Sprintf("%d %s", counts[kind], kind))
+	}
+	return strings.Join(rendered, ", ")
+}
+
+// escapeCell keeps text from breaking a markdown table row.
+func escapeCell(text string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(text, "\n", " "), "|", `\|`)
+}
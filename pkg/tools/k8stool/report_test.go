@@ -0,0 +1,83 @@
+package k8stool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	found, err := ParseManifests("deploy/app.yaml", deploymentManifest+serviceManifest)
+	require.NoError(t, err)
+	summary := Summary{
+		Source:    SourceRepo,
+		RepoURL:   "https://github.com/dictybase-docker/cluster-manifests.git",
+		Branch:    "main",
+		Path:      "deploy",
+		Revision:  Revision{Hash: "9e8d7c6aaaa", When: time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC), Ref: "main"},
+		Resources: found,
+		Skipped:   []SkippedFile{{Path: "deploy/chart/values.yaml", Reason: "larger than 1 MiB"}},
+		Previous:  &Revision{Hash: "1a2b3c4dddd", When: time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC), Ref: "v1.0.0"},
+		Comparison: &Comparison{
+			Changes: []Change{
+				{Status: StatusRemoved, Resource: Resource{Kind: "ConfigMap", Name: "legacy", File: "deploy/legacy.yaml"}},
+				{Status: StatusChanged, Resource: found[0], Details: []string{"replicas 1 → 2"}},
+			},
+			Unchanged: 3,
+		},
+	}
+	assert.Equal(t, "# Kubernetes Manifests: cluster-manifests\n\n"+
+		"**Repository:** https://github.com/dictybase-docker/cluster-manifests.git (branch `main`, commit `9e8d7c6` of 2025-06-28)\n"+
+		"**Path:** `deploy`\n"+
+		"**Compared with:** `v1.0.0` (commit `1a2b3c4` of 2025-06-01)\n\n"+
+		"## Overview\n\n"+
+		"4 resources in 1 files: 1 CronJob, 1 Deployment, 1 Secret, 1 Service.\n"+
+		"Since the previous version: 0 added, 1 changed, 1 removed, 3 unchanged.\n\n"+
+		"## Resources\n\n| Kind | Name | File | Details |\n|---|---|---|---|\n"+
+		"| Deployment | `dictybase/graphql-server` | `deploy/app.yaml` | 2 replicas; 2 containers |\n"+
+		"| Service | `dictybase/graphql-server` | `deploy/app.yaml` | ClusterIP; ports http 80→8080/TCP |\n"+
+		"| CronJob | `backup` | `deploy/app.yaml` | schedule `0 2 * * *`; 1 containers |\n"+
+		"| Secret | `arango` | `deploy/app.yaml` | keys password, user |\n\n"+
+		"## Images\n\n| Image | Used by |\n|---|---|\n"+
+		"| `dictybase/backup:0.3.0` | CronJob `backup` (backup) |\n"+
+		"| `dictybase/graphql-server:2.1.0` | Deployment `dictybase/graphql-server` (server) |\n"+
+		"| `dictybase/migrate:1.0.0` | Deployment `dictybase/graphql-server` (migrate) |\n\n"+
+		"## Environment\n\n"+
+		"### Deployment `dictybase/graphql-server`\n\n| Container | Variable | Value |\n|---|---|---|\n"+
+		"| server | `LOG_LEVEL` | `info` |\n"+
+		"| server | `DB_PASSWORD` | `(redacted)` |\n"+
+		"| server | `REDIS_PORT` | `6379` |\n"+
+		"| server | `ARANGO_PASS` | secret `arango/password` |\n"+
+		"| server | `POD_NAME` | field `metadata.name` |\n"+
+		"| server | `GQL_*` | all keys of config map `graphql-config` |\n\n"+
+		"## Changes\n\n"+
+		"### Changed\n\n- Deployment `dictybase/graphql-server` (`deploy/app.yaml`)\n  - replicas 1 → 2\n\n"+
+		"### Removed\n\n- ConfigMap `legacy` (`deploy/legacy.yaml`)\n\n"+
+		"## Skipped Files\n\n- `deploy/chart/values.yaml`: larger than 1 MiB\n",
+		RenderMarkdown(summary))
+}
+
+func TestRenderMarkdownInline(t *testing.T) {
+	t.Parallel()
+	found, err := ParseManifests("manifests", serviceManifest)
+	require.NoError(t, err)
+	text := RenderMarkdown(Summary{Source: SourceManifests, Resources: found, Comparison: &Comparison{Unchanged: 3}})
+	assert.Contains(t, text, "# Kubernetes Manifests\n\n**Compared with:** the previous manifests\n")
+	assert.Contains(t, text, "3 resources: 1 CronJob, 1 Secret, 1 Service.\n")
+	assert.Contains(t, text, "| Kind | Name | Details |\n")
+	assert.Contains(t, text, "| Secret | `arango` | keys password, user |\n")
+	assert.NotContains(t, text, "## Changes")
+	assert.NotContains(t, text, "## Environment")
+
+	empty := RenderMarkdown(Summary{Source: SourceManifests})
+	assert.Contains(t, empty, "The manifests define no Kubernetes resources.\n")
+}
+
+func TestCountKinds(t *testing.T) {
+	t.Parallel()
+	resources := []Resource{{Kind: "Service"}, {Kind: "Deployment"}, {Kind: "Service"}, {Kind: "ConfigMap"}}
+	assert.Equal(t, "2 Service, 1 ConfigMap, 1 Deployment", countKinds(resources))
+}
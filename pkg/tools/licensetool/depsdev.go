@@ -0,0 +1,139 @@
+package licensetool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const defaultDepsDevURL = "https://api.deps.dev"
+
+// Package systems of the manifests as named by deps.dev.
+const (
+	SystemGo  = "go"
+	SystemNPM = "npm"
+)
+
+// ErrVersionNotFound is returned when deps.dev does not know a package
+// version.
+var ErrVersionNotFound = errors.New("version not found on deps.dev")
+
+// DepsDevClient looks up package metadata on deps.dev, the Open Source
+// Insights service.
+type DepsDevClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// Option represents a configuration option for DepsDevClient.
+type Option func(*Config)
+
+// Config holds the configuration for the deps.dev client.
+type Config struct {
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithBaseURL overrides the deps.dev API base URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewDepsDevClient creates a client for the deps.dev API.
+func NewDepsDevClient(opts ...Option) *DepsDevClient {
+	cfg := &Config{
+		baseURL: defaultDepsDevURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &DepsDevClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:    strings.TrimSuffix(cfg.baseURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// versionResponse is the part of a deps.dev version the client reads.
+type versionResponse struct {
+	// Licenses are SPDX expressions; "non-standard" marks a license
+	// deps.dev could not identify.
+	Licenses []string `json:"licenses"`
+}
+
+// Licenses returns the SPDX license expressions of a package version.
+func (c *DepsDevClient) Licenses(ctx context.Context, system, name, version string) ([]string, error) {
+	endpoint := fmt.Sprintf(
+		"%s/v3/systems/%s/packages/%s/versions/%s",
+		c.baseURL, system, url.PathEscape(name), url.PathEscape(version),
+	)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating deps.dev request: %w", err)
+	}
+	start := time.Now()
+	var response versionResponse
+	err = c.send(req, &response)
+	if !errors.Is(err, ErrVersionNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceDepsDev, start, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("looked up licenses", "system", system, "name", name, "version", version)
+	return response.Licenses, nil
+}
+
+// send performs the HTTP round trip for Licenses.
+func (c *DepsDevClient) send(req *http.Request, target any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling deps.dev: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return ErrVersionNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"deps.dev returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error reading deps.dev response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,65 @@
+package licensetool
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// depsDevLicenses maps the version paths of the fake deps.dev server to
+// the licenses it returns.
+var depsDevLicenses = map[string]string{
+	"/v3/systems/go/packages/example.org%2Flib/versions/v1.2.0": `{"licenses": ["MIT"]}`,
+	"/v3/systems/go/packages/example.org%2Fgpl/versions/v0.3.0": `{"licenses": ["GPL-3.0-only"]}`,
+	"/v3/systems/npm/packages/react/versions/19.0.0":            `{"licenses": ["MIT"]}`,
+	"/v3/systems/npm/packages/@scope%2Fui/versions/2.1.0":       `{"licenses": ["MIT OR Apache-2.0", "BSD-3-Clause"]}`,
+	"/v3/systems/npm/packages/vitest/versions/1.6.0":            `{"licenses": ["MIT"]}`,
+}
+
+// newDepsDevServer starts a fake deps.dev API.
+func newDepsDevServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, ok := depsDevLicenses[r.URL.EscapedPath()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestDepsDevClient_Licenses(t *testing.T) {
+	t.Parallel()
+	client := NewDepsDevClient(WithBaseURL(newDepsDevServer(t).URL))
+
+	licenses, err := client.Licenses(context.Background(), SystemGo, "example.org/lib", "v1.2.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MIT"}, licenses)
+
+	licenses, err = client.Licenses(context.Background(), SystemNPM, "@scope/ui", "2.1.0")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"MIT OR Apache-2.0", "BSD-3-Clause"}, licenses)
+
+	_, err = client.Licenses(context.Background(), SystemGo, "example.org/missing", "v1.0.0")
+	require.ErrorIs(t, err, ErrVersionNotFound)
+}
+
+func TestDepsDevClient_Error(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	client := NewDepsDevClient(WithBaseURL(server.URL))
+
+	_, err := client.Licenses(context.Background(), SystemGo, "example.org/lib", "v1.2.0")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "deps.dev returned status 503")
+}
@@ -0,0 +1,192 @@
+package licensetool
+
+import (
+	"slices"
+	"strings"
+)
+
+// Category classifies a license by the obligations it brings, following
+// the license types of go-licenses.
+type Category string
+
+// License categories, from the most to the least restrictive.
+const (
+	// CategoryForbidden licenses may not be used, e.g. AGPL or
+	// non-commercial licenses.
+	CategoryForbidden Category = "forbidden"
+	// CategoryUnknown is used when no license was found or it is not
+	// recognized; it needs a manual review.
+	CategoryUnknown Category = "unknown"
+	// CategoryRestricted licenses require releasing derived work under the
+	// same license, e.g. GPL.
+	CategoryRestricted Category = "restricted"
+	// CategoryReciprocal licenses require releasing changes to the licensed
+	// files, e.g. MPL.
+	CategoryReciprocal Category = "reciprocal"
+	// CategoryNotice licenses require keeping the copyright notice, e.g.
+	// MIT or Apache-2.0.
+	CategoryNotice Category = "notice"
+	// CategoryUnencumbered licenses come without obligations, e.g. CC0.
+	CategoryUnencumbered Category = "unencumbered"
+)
+
+// categoryOrder is the order of the categories in reports; it also ranks
+// them from the most to the least restrictive.
+var categoryOrder = []Category{
+	CategoryForbidden,
+	CategoryUnknown,
+	CategoryRestricted,
+	CategoryReciprocal,
+	CategoryNotice,
+	CategoryUnencumbered,
+}
+
+// licensePrefixes maps the prefixes of SPDX license IDs to their category.
+// The longest matching prefix wins.
+var licensePrefixes = map[string]Category{
+	"AGPL-":          CategoryForbidden,
+	"CC-BY-NC":       CategoryForbidden,
+	"Commons-Clause": CategoryForbidden,
+	"SSPL-":          CategoryForbidden,
+	"BUSL-":          CategoryForbidden,
+	"WTFPL":          CategoryForbidden,
+	"GPL-":           CategoryRestricted,
+	"LGPL-":          CategoryRestricted,
+	"CC-BY-ND-":      CategoryRestricted,
+	"CC-BY-SA-":      CategoryRestricted,
+	"OSL-":           CategoryRestricted,
+	"NPL-":           CategoryRestricted,
+	"QPL-":           CategoryRestricted,
+	"Sleepycat":      CategoryRestricted,
+	"MPL-":           CategoryReciprocal,
+	"EPL-":           CategoryReciprocal,
+	"CDDL-":          CategoryReciprocal,
+	"CPL-":           CategoryReciprocal,
+	"APSL-":          CategoryReciprocal,
+	"IPL-":           CategoryReciprocal,
+	"Ruby":           CategoryReciprocal,
+	"MIT":            CategoryNotice,
+	"BSD-":           CategoryNotice,
+	"Apache-":        CategoryNotice,
+	"ISC":            CategoryNotice,
+	"Zlib":           CategoryNotice,
+	"BSL-1.0":        CategoryNotice,
+	"Artistic-":      CategoryNotice,
+	"AFL-":           CategoryNotice,
+	"CC-BY-":         CategoryNotice,
+	"Python-":        CategoryNotice,
+	"PostgreSQL":     CategoryNotice,
+	"BlueOak-":       CategoryNotice,
+	"Unicode-":       CategoryNotice,
+	"X11":            CategoryNotice,
+	"W3C":            CategoryNotice,
+	"NCSA":           CategoryNotice,
+	"OpenSSL":        CategoryNotice,
+	"MS-PL":          CategoryNotice,
+	"CC0-":           CategoryUnencumbered,
+	"Unlicense":      CategoryUnencumbered,
+	"0BSD":           CategoryUnencumbered,
+}
+
+// categoryRank returns how restrictive category is; lower is more
+// restrictive.
+func categoryRank(category Category) int {
+	return slices.Index(categoryOrder, category)
+}
+
+// Classify returns the category of an SPDX license expression. Of
+// alternatives joined by OR the least restrictive applies, of licenses
+// joined by AND the most restrictive.
+func Classify(expression string) Category {
+	parser := &expressionParser{tokens: tokenize(expression)}
+	category, ok := parser.or()
+	if !ok || parser.pos != len(parser.tokens) {
+		return CategoryUnknown
+	}
+	return category
+}
+
+// classifyID returns the category of a single SPDX license ID.
+func classifyID(id string) Category {
+	id = strings.TrimSuffix(strings.TrimSuffix(strings.TrimSuffix(id, "+"), "-only"), "-or-later")
+	best, category := "", CategoryUnknown
+	for prefix, prefixCategory := range licensePrefixes {
+		if strings.HasPrefix(strings.ToLower(id), strings.ToLower(prefix)) && len(prefix) > len(best) {
+			best, category = prefix, prefixCategory
+		}
+	}
+	return category
+}
+
+// tokenize splits an SPDX expression into parentheses, operators and
+// license IDs.
+func tokenize(expression string) []string {
+	expression = strings.NewReplacer("(", " ( ", ")", " ) ").Replace(expression)
+	return strings.Fields(expression)
+}
+
+// operators are the tokens that cannot stand for a license ID.
+var operators = []string{"(", ")", "AND", "OR", "WITH"}
+
+// expressionParser evaluates an SPDX expression to a category.
+type expressionParser struct {
+	tokens []string
+	pos    int
+}
+
+// accept consumes the next token if it is the operator op.
+func (p *expressionParser) accept(op string) bool {
+	if p.pos < len(p.tokens) && strings.EqualFold(p.tokens[p.pos], op) {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// or evaluates alternatives, keeping the least restrictive.
+func (p *expressionParser) or() (Category, bool) {
+	category, ok := p.and()
+	for ok && p.accept("OR") {
+		var next Category
+		if next, ok = p.and(); ok && categoryRank(next) > categoryRank(category) {
+			category = next
+		}
+	}
+	return category, ok
+}
+
+// and evaluates conjunctions, keeping the most restrictive.
+func (p *expressionParser) and() (Category, bool) {
+	category, ok := p.license()
+	for ok && p.accept("AND") {
+		var next Category
+		if next, ok = p.license(); ok && categoryRank(next) < categoryRank(category) {
+			category = next
+		}
+	}
+	return category, ok
+}
+
+// license evaluates a parenthesized expression or a license ID with an
+// optional exception, which does not change the category.
+func (p *expressionParser) license() (Category, bool) {
+	if p.accept("(") {
+		category, ok := p.or()
+		if !ok || !p.accept(")") {
+			return CategoryUnknown, false
+		}
+		return category, true
+	}
+	if p.pos >= len(p.tokens) || slices.Contains(operators, strings.ToUpper(p.tokens[p.pos])) {
+		return CategoryUnknown, false
+	}
+	category := classifyID(p.tokens[p.pos])
+	p.pos++
+	if p.accept("WITH") {
+		if p.pos >= len(p.tokens) {
+			return CategoryUnknown, false
+		}
+		p.pos++
+	}
+	return category, true
+}
@@ -0,0 +1,39 @@
+package licensetool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		expression string
+		want       Category
+	}{
+		{expression: "MIT", want: CategoryNotice},
+		{expression: "Apache-2.0", want: CategoryNotice},
+		{expression: "BSD-3-Clause", want: CategoryNotice},
+		{expression: "MPL-2.0", want: CategoryReciprocal},
+		{expression: "GPL-3.0-or-later", want: CategoryRestricted},
+		{expression: "LGPL-2.1+", want: CategoryRestricted},
+		{expression: "AGPL-3.0-only", want: CategoryForbidden},
+		{expression: "CC-BY-NC-SA-4.0", want: CategoryForbidden},
+		{expression: "CC-BY-4.0", want: CategoryNotice},
+		{expression: "CC0-1.0", want: CategoryUnencumbered},
+		{expression: "0BSD", want: CategoryUnencumbered},
+		{expression: "non-standard", want: CategoryUnknown},
+		{expression: "", want: CategoryUnknown},
+		{expression: "MIT OR GPL-2.0", want: CategoryNotice},
+		{expression: "MIT AND GPL-2.0", want: CategoryRestricted},
+		{expression: "(MIT OR Apache-2.0) AND MPL-2.0", want: CategoryReciprocal},
+		{expression: "GPL-2.0 WITH Classpath-exception-2.0", want: CategoryRestricted},
+		{expression: "MIT or non-standard", want: CategoryNotice},
+		{expression: "(MIT", want: CategoryUnknown},
+		{expression: "MIT AND", want: CategoryUnknown},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Classify(tt.expression), tt.expression)
+	}
+}
@@ -0,0 +1,448 @@
+package licensetool
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/dependencytool"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Output formats of the scan.
+const (
+	FormatMarkdown = "markdown"
+	FormatPDF      = "pdf"
+)
+
+// lookupConcurrency is the number of licenses looked up at a time.
+const lookupConcurrency = 8
+
+// LicenseTool inventories the licenses of the dependencies of a
+// repository.
+type LicenseTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	analyzer      *worksummary.GitAnalyzer
+	store         artifact.Store
+	resources     *resources.Catalog
+	clientOptions []Option
+	pdf           pdfRenderer
+}
+
+// ToolOption defines a functional option for configuring LicenseTool.
+type ToolOption func(*LicenseTool)
+
+// WithStore sets the store PDF reports are saved to.
+func WithStore(store artifact.Store) ToolOption {
+	return func(l *LicenseTool) {
+		l.store = store
+	}
+}
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(l *LicenseTool) {
+		l.resources = catalog
+	}
+}
+
+// WithClientOptions sets options for the deps.dev clients the tool
+// creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(l *LicenseTool) {
+		l.clientOptions = append(l.clientOptions, opts...)
+	}
+}
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(l *LicenseTool) {
+		l.analyzer = analyzer
+	}
+}
+
+// LicenseRequest represents the parameters for a license scan.
+type LicenseRequest struct {
+	RepoURL string `validate:"required"`
+	Branch  string `validate:"required"`
+	// IncludeDev adds the development dependencies of npm packages, which
+	// are not distributed with the software.
+	IncludeDev bool
+	Format     string `validate:"required,oneof=markdown pdf"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"license-scan",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewLicenseTool(deps.Logger, WithStore(deps.Store), WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewLicenseTool creates a new LicenseTool instance.
+func NewLicenseTool(logger *slog.Logger, opts ...ToolOption) (*LicenseTool, error) {
+	tool := mcp.NewTool(
+		"license-scan",
+		mcp.WithDescription(
+			"Inventories the licenses of the dependencies in the go.mod and package.json files of a git "+
+				"repository and renders a compliance table, as markdown or PDF, for grant and institutional reporting",
+		),
+		mcp.WithTitleAnnotation("License Scan"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to scan"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean(
+			"include_dev",
+			mcp.Description("Include npm development dependencies (optional, defaults to false)"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Output format, defaults to 'markdown'; 'pdf' also saves the report as a PDF"),
+			mcp.Enum(FormatMarkdown, FormatPDF),
+		),
+		idempotency.WithKey(),
+	)
+	licenseTool := &LicenseTool{
+		Name:        "license-scan",
+		Description: "Inventories the licenses of a repository's dependencies",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(licenseTool)
+	}
+	return licenseTool, nil
+}
+
+// GetName returns the name of the tool.
+func (l *LicenseTool) GetName() string {
+	return l.Name
+}
+
+// GetDescription returns the description of the tool.
+func (l *LicenseTool) GetDescription() string {
+	return l.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (l *LicenseTool) GetSchema() mcp.ToolInputSchema {
+	return l.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (l *LicenseTool) GetTool() mcp.Tool {
+	return l.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (l *LicenseTool) GetAnnotations() mcp.ToolAnnotation {
+	return l.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (l *LicenseTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the licenses of a repository's runtime dependencies",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/dcr-mcp",
+				"branch":   "main",
+			},
+		},
+		{
+			Description: "Render a PDF license report including development dependencies",
+			Arguments: map[string]any{
+				"repo_url":    "https://github.com/dictybase/stock-center",
+				"branch":      "develop",
+				"include_dev": true,
+				"format":      FormatPDF,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (l *LicenseTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := LicenseRequest{
+		RepoURL:    request.GetString("repo_url", ""),
+		Branch:     request.GetString("branch", ""),
+		IncludeDev: request.GetBool("include_dev", false),
+		Format:     request.GetString("format", FormatMarkdown),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	scan, err := l.ScanLicenses(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(scan)
+	name := fmt.Sprintf("%s-%s-licenses", worksummary.RepoName(params.RepoURL), params.Branch)
+	publish := resources.PublishParams{
+		Kind:        resources.KindGitSummary,
+		Name:        name + ".md",
+		MIMEType:    "text/markdown",
+		Description: "Dependency licenses of " + params.RepoURL,
+		Data:        []byte(content),
+	}
+	if params.Format == FormatPDF {
+		pdfData, err := l.pdf.Render(content)
+		if err != nil {
+			return toolerror.Result(err), nil
+		}
+		stored, err := l.store.Put(ctx, artifact.PutParams{
+			Name:        name + ".pdf",
+			ContentType: "application/pdf",
+			Body:        bytes.NewReader(pdfData),
+			Size:        int64(len(pdfData)),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("failed to store PDF %s.pdf: %w", name, err)), nil
+		}
+		content += fmt.Sprintf("\nPDF saved to %s\n", stored.Location)
+		if stored.URL != "" {
+			content += fmt.Sprintf("Download URL: %s\n", stored.URL)
+		}
+		publish.Kind = resources.KindPDF
+		publish.Name = name + ".pdf"
+		publish.MIMEType = "application/pdf"
+		publish.Data = pdfData
+	}
+	result := provenance.Attach(
+		mcp.NewToolResultText(content),
+		provenance.New([]string{metrics.ServiceGitClone, metrics.ServiceDepsDev}),
+	)
+	if l.resources != nil {
+		resource, err := l.resources.Publish(publish)
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing license scan: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// lookup is a package version whose licenses are looked up once, however
+// many manifests use it.
+type lookup struct {
+	system  string
+	name    string
+	version string
+}
+
+// lookupResult holds the licenses found for a lookup.
+type lookupResult struct {
+	licenses []string
+	err      error
+}
+
+// ScanLicenses clones the repository and looks up the licenses of the
+// dependencies of every manifest at the head of the branch.
+func (l *LicenseTool) ScanLicenses(ctx context.Context, params LicenseRequest) (Scan, error) {
+	scan := Scan{RepoURL: params.RepoURL, Branch: params.Branch}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := l.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.4)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Scan{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	head, err := repo.Head()
+	if err != nil {
+		return Scan{}, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	commit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Scan{}, fmt.Errorf("error reading commit %s: %w", head.Hash(), err)
+	}
+	scan.Commit, scan.When = commit.Hash.String(), commit.Committer.When
+
+	reporter.Report(0.4, 1, "reading manifests")
+	files, err := dependencytool.ReadManifests(commit)
+	if err != nil {
+		return Scan{}, err
+	}
+	paths := make([]string, 0, len(files))
+	for file := range files {
+		paths = append(paths, file)
+	}
+	slices.Sort(paths)
+	deps := make([][]dependencytool.Dependency, len(paths))
+	scan.Manifests = make([]Manifest, len(paths))
+	var lookups []lookup
+	for i, file := range paths {
+		scan.Manifests[i].Path = file
+		parsed, err := dependencytool.ParseManifest(file, files[file])
+		if err != nil {
+			scan.Manifests[i].Err = err
+			continue
+		}
+		for _, dep := range parsed {
+			if dep.Scope == "dev" && !params.IncludeDev {
+				scan.DevSkipped++
+				continue
+			}
+			deps[i] = append(deps[i], dep)
+			if key, ok := lookupKey(file, dep); ok && !slices.Contains(lookups, key) {
+				lookups = append(lookups, key)
+			}
+		}
+		slices.SortFunc(deps[i], func(a, b dependencytool.Dependency) int {
+			return cmp.Compare(a.Name, b.Name)
+		})
+	}
+
+	results, err := l.lookupLicenses(progress.NewContext(ctx, reporter.Sub(0.4, 1)), lookups)
+	if err != nil {
+		return Scan{}, err
+	}
+	for i, file := range paths {
+		for _, dep := range deps[i] {
+			scan.Manifests[i].Entries = append(scan.Manifests[i].Entries, entry(file, dep, lookups, results))
+		}
+	}
+	reporter.Report(1, 1, "license scan generated")
+	return scan, nil
+}
+
+// lookupLicenses looks up the licenses of every package version, at most
+// lookupConcurrency at a time. Failed lookups are recorded in the results;
+// only a cancelled context fails the scan.
+func (l *LicenseTool) lookupLicenses(ctx context.Context, lookups []lookup) ([]lookupResult, error) {
+	logger := logging.WithRequestID(l.Logger)
+	client := NewDepsDevClient(append([]Option{WithLogger(logger)}, l.clientOptions...)...)
+	reporter := progress.FromContext(ctx)
+	results := make([]lookupResult, len(lookups))
+	semaphore := make(chan struct{}, lookupConcurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for i, key := range lookups {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				results[i] = lookupResult{err: ctx.Err()}
+				return
+			}
+			licenses, err := client.Licenses(ctx, key.system, key.name, key.version)
+			if err != nil && !errors.Is(err, ErrVersionNotFound) {
+				logger.Warn("failed to look up licenses", "package", key.name, "version", key.version, "error", err)
+			}
+			results[i] = lookupResult{licenses: licenses, err: err}
+			mu.Lock()
+			done++
+			reporter.Report(float64(done), float64(len(lookups)), "looked up "+key.name)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	if err := ctx.Err(); err != nil {
+		return nil, fmt.Errorf("license scan aborted: %w", err)
+	}
+	return results, nil
+}
+
+// lookupKey builds the lookup of a dependency. npm ranges are looked up at
+// their lower bound. It reports false for versions that are not semantic
+// versions, such as git URLs or dist tags.
+func lookupKey(file string, dep dependencytool.Dependency) (lookup, bool) {
+	if path.Base(file) == dependencytool.ManifestGoMod {
+		return lookup{system: SystemGo, name: dep.Name, version: dep.Version}, true
+	}
+	version := dependencytool.NormalizeVersion(dep.Version)
+	if version == "" {
+		return lookup{}, false
+	}
+	return lookup{system: SystemNPM, name: dep.Name, version: strings.TrimPrefix(version, "v")}, true
+}
+
+// entry classifies the license found for a dependency.
+func entry(file string, dep dependencytool.Dependency, lookups []lookup, results []lookupResult) Entry {
+	found := Entry{Dependency: dep, Category: CategoryUnknown}
+	key, ok := lookupKey(file, dep)
+	if !ok {
+		found.Note = "no exact version to look up"
+		return found
+	}
+	result := results[slices.Index(lookups, key)]
+	switch {
+	case errors.Is(result.err, ErrVersionNotFound):
+		found.Note = "version not found on deps.dev"
+	case result.err != nil:
+		found.Note = "lookup failed"
+	case len(result.licenses) == 0:
+		found.Note = "no license declared"
+	default:
+		found.License = joinLicenses(result.licenses)
+		found.Category = Classify(found.License)
+	}
+	return found
+}
+
+// joinLicenses combines the license expressions of a package, all of which
+// apply, into one expression.
+func joinLicenses(licenses []string) string {
+	if len(licenses) == 1 {
+		return licenses[0]
+	}
+	parts := make([]string, 0, len(licenses))
+	for _, license := range licenses {
+		if strings.Contains(license, " ") {
+			license = "(" + license + ")"
+		}
+		parts = append(parts, license)
+	}
+	return strings.Join(parts, " AND ")
+}
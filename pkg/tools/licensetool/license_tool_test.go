@@ -0,0 +1,98 @@
+package licensetool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/tools/dependencytool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T, opts ...ToolOption) *LicenseTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(worksummary.WithTimeZone(time.UTC))
+	tool, err := NewLicenseTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]ToolOption{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestScanLicenses(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, map[string]string{
+		"go.mod": "module example.org/app\n\nrequire (\n\texample.org/lib v1.2.0\n" +
+			"\texample.org/gpl v0.3.0 // indirect\n\texample.org/missing v1.0.0\n)\n",
+		"web/package.json": `{"dependencies": {"react": "^19.0.0", "@scope/ui": "~2.1.0", "local": "file:../local"},
+			"devDependencies": {"vitest": "^1.6.0"}}`,
+		"broken/package.json": `{`,
+	})
+	tool := newTestTool(t, WithClientOptions(WithBaseURL(newDepsDevServer(t).URL)))
+
+	scan, err := tool.ScanLicenses(context.Background(), LicenseRequest{RepoURL: dir, Branch: "master"})
+	require.NoError(t, err)
+	assert.Len(t, scan.Commit, 40)
+	assert.Equal(t, 1, scan.DevSkipped)
+	require.Len(t, scan.Manifests, 3)
+	assert.Equal(t, "broken/package.json", scan.Manifests[0].Path)
+	require.Error(t, scan.Manifests[0].Err)
+	assert.Equal(t, Manifest{Path: "go.mod", Entries: []Entry{
+		{
+			Dependency: dependencytool.Dependency{Name: "example.org/gpl", Version: "v0.3.0", Scope: "indirect"},
+			License:    "GPL-3.0-only",
+			Category:   CategoryRestricted,
+		},
+		{
+			Dependency: dependencytool.Dependency{Name: "example.org/lib", Version: "v1.2.0"},
+			License:    "MIT",
+			Category:   CategoryNotice,
+		},
+		{
+			Dependency: dependencytool.Dependency{Name: "example.org/missing", Version: "v1.0.0"},
+			Category:   CategoryUnknown,
+			Note:       "version not found on deps.dev",
+		},
+	}}, scan.Manifests[1])
+	assert.Equal(t, Manifest{Path: "web/package.json", Entries: []Entry{
+		{
+			Dependency: dependencytool.Dependency{Name: "@scope/ui", Version: "~2.1.0"},
+			License:    "(MIT OR Apache-2.0) AND BSD-3-Clause",
+			Category:   CategoryNotice,
+		},
+		{
+			Dependency: dependencytool.Dependency{Name: "local", Version: "file:../local"},
+			Category:   CategoryUnknown,
+			Note:       "no exact version to look up",
+		},
+		{
+			Dependency: dependencytool.Dependency{Name: "react", Version: "^19.0.0"},
+			License:    "MIT",
+			Category:   CategoryNotice,
+		},
+	}}, scan.Manifests[2])
+
+	withDev, err := tool.ScanLicenses(context.Background(), LicenseRequest{RepoURL: dir, Branch: "master", IncludeDev: true})
+	require.NoError(t, err)
+	assert.Zero(t, withDev.DevSkipped)
+	assert.Len(t, withDev.Manifests[2].Entries, 4)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	for _, arguments := range []map[string]any{
+		{"repo_url": "https://github.com/dictybase/dcr-mcp"},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "develop", "format": "docx"},
+	} {
+		result := tooltest.Call(t, tool.Handler, "license-scan", arguments)
+		assert.True(t, result.IsError)
+	}
+}
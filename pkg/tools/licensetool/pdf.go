@@ -0,0 +1,29 @@
+package licensetool
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/pdftool"
+	"github.com/yuin/goldmark"
+)
+
+// pdfRenderer renders reports with the markdown_to_pdf converter, built on
+// first use.
+type pdfRenderer struct {
+	once      sync.Once
+	converter goldmark.Markdown
+}
+
+// Render converts a markdown report to PDF.
+func (p *pdfRenderer) Render(markdown string) ([]byte, error) {
+	p.once.Do(func() {
+		p.converter = pdftool.NewConverter()
+	})
+	var buf bytes.Buffer
+	if err := p.converter.Convert([]byte(markdown), &buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
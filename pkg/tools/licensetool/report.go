@@ -0,0 +1,190 @@
+package licensetool
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/dependencytool"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Entry is the license of one dependency.
+type Entry struct {
+	Dependency dependencytool.Dependency
+	// License holds the SPDX expressions found for the dependency, joined
+	// by AND. It is empty when none was found.
+	License  string
+	Category Category
+	// Note explains a missing license, e.g. a version deps.dev does not
+	// know.
+	Note string
+}
+
+// Manifest holds the licenses of the dependencies of one manifest.
+type Manifest struct {
+	Path    string
+	Entries []Entry
+	// Err is set when the manifest could not be parsed.
+	Err error
+}
+
+// Scan is the content of one license scan.
+type Scan struct {
+	RepoURL string
+	Branch  string
+	Commit  string
+	When    time.Time
+	// DevSkipped is the number of development dependencies left out.
+	DevSkipped int
+	Manifests  []Manifest
+}
+
+// LicenseCount is the number of dependencies under one license.
+type LicenseCount struct {
+	License  string
+	Category Category
+	Count    int
+}
+
+// reviewCategories are the categories whose dependencies need a review
+// before the software is distributed.
+var reviewCategories = []Category{CategoryForbidden, CategoryUnknown, CategoryRestricted}
+
+// Counts returns the number of dependencies of each category.
+func (s Scan) Counts() map[Category]int {
+	counts := make(map[Category]int)
+	for _, manifest := range s.Manifests {
+		for _, entry := range manifest.Entries {
+			counts[entry.Category]++
+		}
+	}
+	return counts
+}
+
+// Licenses returns the number of dependencies under each license, the
+// most restrictive first.
+func (s Scan) Licenses() []LicenseCount {
+	var counts []LicenseCount
+	for _, manifest := range s.Manifests {
+		for _, entry := range manifest.Entries {
+			index := slices.IndexFunc(counts, func(count LicenseCount) bool {
+				return count.License == entry.License
+			})
+			if index < 0 {
+				counts = append(counts, LicenseCount{License: entry.License, Category: entry.Category})
+				index = len(counts) - 1
+			}
+			counts[index].Count++
+		}
+	}
+	slices.SortFunc(counts, func(a, b LicenseCount) int {
+		return cmp.Or(
+			categoryRank(a.Category)-categoryRank(b.Category),
+			b.Count-a.Count,
+			cmp.Compare(a.License, b.License),
+		)
+	})
+	return counts
+}
+
+// RenderMarkdown renders the scan as a markdown document.
+func RenderMarkdown(scan Scan) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# License Scan: %s\n\n", worksummary.RepoName(scan.RepoURL))
+	fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`, commit `%s` of %s)\n",
+		scan.RepoURL, scan.Branch, worksummary.ShortHash(scan.Commit), scan.When.Format(time.DateOnly))
+
+	builder.WriteString("\n## Overview\n\n")
+	counts := scan.Counts()
+	total := 0
+	parts := make([]string, 0, len(categoryOrder))
+	for _, category := range categoryOrder {
+		total += counts[category]
+		if counts[category] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[category], category))
+		}
+	}
+	if total == 0 {
+		fmt.Fprintf(&builder, "No dependencies in %d manifests.", len(scan.Manifests))
+	} else {
+		fmt.Fprintf(&builder, "%d dependencies in %d manifests: %s.",
+			total, len(scan.Manifests), strings.Join(parts, ", "))
+	}
+	if scan.DevSkipped > 0 {
+		fmt.Fprintf(&builder, " %d development dependencies were left out.", scan.DevSkipped)
+	}
+	builder.WriteString("\n")
+	review := reviewEntries(scan)
+	if len(review) > 0 {
+		fmt.Fprintf(&builder, "\n**Needs review:** %d dependencies have forbidden, restricted or unknown licenses.\n",
+			len(review))
+	}
+
+	if total > 0 {
+		builder.WriteString("\n## Licenses\n\n| License | Category | Dependencies |\n|---|---|---|\n")
+		for _, count := range scan.Licenses() {
+			fmt.Fprintf(&builder, "| %s | %s | %d |\n", licenseCell(count.License), count.Category, count.Count)
+		}
+	}
+	if len(review) > 0 {
+		builder.WriteString("\n## Needs Review\n\n| Dependency | Version | License | Category | Manifest | Note |\n")
+		builder.WriteString("|---|---|---|---|---|---|\n")
+		for _, item := range review {
+			fmt.Fprintf(&builder, "| `%s` | `%s` | %s | %s | %s | %s |\n",
+				item.entry.Dependency.Name, item.entry.Dependency.Version, licenseCell(item.entry.License),
+				item.entry.Category, item.manifest, item.entry.Note)
+		}
+	}
+	for _, manifest := range scan.Manifests {
+		fmt.Fprintf(&builder, "\n## %s\n\n", manifest.Path)
+		if manifest.Err != nil {
+			fmt.Fprintf(&builder, "_Unavailable: %v_\n", manifest.Err)
+			continue
+		}
+		if len(manifest.Entries) == 0 {
+			builder.WriteString("_No dependencies._\n")
+			continue
+		}
+		builder.WriteString("| Dependency | Version | License | Category | Scope |\n|---|---|---|---|---|\n")
+		for _, entry := range manifest.Entries {
+			fmt.Fprintf(&builder, "| `%s` | `%s` | %s | %s | %s |\n",
+				entry.Dependency.Name, entry.Dependency.Version, licenseCell(entry.License),
+				entry.Category, entry.Dependency.Scope)
+		}
+	}
+	return builder.String()
+}
+
+// reviewItem is a dependency that needs a review, with its manifest.
+type reviewItem struct {
+	manifest string
+	entry    Entry
+}
+
+// reviewEntries returns the dependencies whose license needs a review,
+// the most restrictive first.
+func reviewEntries(scan Scan) []reviewItem {
+	var items []reviewItem
+	for _, manifest := range scan.Manifests {
+		for _, entry := range manifest.Entries {
+			if slices.Contains(reviewCategories, entry.Category) {
+				items = append(items, reviewItem{manifest: manifest.Path, entry: entry})
+			}
+		}
+	}
+	slices.SortStableFunc(items, func(a, b reviewItem) int {
+		return categoryRank(a.entry.Category) - categoryRank(b.entry.Category)
+	})
+	return items
+}
+
+// licenseCell renders a license, marking dependencies without one.
+func licenseCell(license string) string {
+	if license == "" {
+		return "_none found_"
+	}
+	return license
+}
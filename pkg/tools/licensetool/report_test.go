@@ -0,0 +1,58 @@
+package licensetool
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/dependencytool"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	scan := Scan{
+		RepoURL:    "https://github.com/dictybase/dcr-mcp.git",
+		Branch:     "develop",
+		Commit:     "9e8d7c6aaaa",
+		When:       time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+		DevSkipped: 2,
+		Manifests: []Manifest{
+			{Path: "docs/package.json", Err: errors.New("error parsing docs/package.json")},
+			{Path: "go.mod", Entries: []Entry{
+				{Dependency: dependencytool.Dependency{Name: "example.org/a", Version: "v1.0.0"}, License: "MIT", Category: CategoryNotice},
+				{Dependency: dependencytool.Dependency{Name: "example.org/b", Version: "v1.1.0"}, License: "MIT", Category: CategoryNotice},
+				{
+					Dependency: dependencytool.Dependency{Name: "example.org/gpl", Version: "v0.3.0", Scope: "indirect"},
+					License:    "GPL-3.0-only", Category: CategoryRestricted,
+				},
+				{
+					Dependency: dependencytool.Dependency{Name: "example.org/missing", Version: "v1.0.0"},
+					Category:   CategoryUnknown, Note: "version not found on deps.dev",
+				},
+			}},
+			{Path: "web/package.json"},
+		},
+	}
+
+	rendered := RenderMarkdown(scan)
+	assert.True(t, strings.HasPrefix(rendered, "# License Scan: dcr-mcp\n\n"+
+		"**Repository:** https://github.com/dictybase/dcr-mcp.git (branch `develop`, commit `9e8d7c6` of 2025-06-28)\n"))
+	assert.Contains(t, rendered, "4 dependencies in 3 manifests: 1 unknown, 1 restricted, 2 notice. "+
+		"2 development dependencies were left out.\n\n"+
+		"**Needs review:** 2 dependencies have forbidden, restricted or unknown licenses.\n")
+	assert.Contains(t, rendered, "| License | Category | Dependencies |\n|---|---|---|\n"+
+		"| _none found_ | unknown | 1 |\n| GPL-3.0-only | restricted | 1 |\n| MIT | notice | 2 |\n")
+	assert.Contains(t, rendered, "## Needs Review\n\n| Dependency | Version | License | Category | Manifest | Note |\n"+
+		"|---|---|---|---|---|---|\n"+
+		"| `example.org/missing` | `v1.0.0` | _none found_ | unknown | go.mod | version not found on deps.dev |\n"+
+		"| `example.org/gpl` | `v0.3.0` | GPL-3.0-only | restricted | go.mod |  |\n")
+	assert.Contains(t, rendered, "## docs/package.json\n\n_Unavailable: error parsing docs/package.json_\n")
+	assert.Contains(t, rendered, "| `example.org/gpl` | `v0.3.0` | GPL-3.0-only | restricted | indirect |\n")
+	assert.Contains(t, rendered, "## web/package.json\n\n_No dependencies._\n")
+
+	clean := RenderMarkdown(Scan{Manifests: []Manifest{{Path: "go.mod"}}})
+	assert.Contains(t, clean, "No dependencies in 1 manifests.\n")
+	assert.NotContains(t, clean, "Needs review")
+}
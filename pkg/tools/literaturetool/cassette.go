@@ -0,0 +1,145 @@
+package literaturetool
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// BundledFixtures selects the recorded responses shipped with the server
+// in place of a cassette directory. They cover the article with PMID
+// 40602797, looked up by PMID, DOI 10.1111/gtc.70037, PMCID PMC12221695
+// or Europe PMC ID MED:40602797.
+const BundledFixtures = "bundled"
+
+// ErrNotRecorded is returned in replay mode for requests without a
+// recorded response.
+var ErrNotRecorded = errors.New("no recorded response")
+
+//go:embed fixtures
+var fixtures embed.FS
+
+// interaction is a recorded request and its response, stored as one JSON
+// file of a cassette directory.
+type interaction struct {
+	Method string      `json:"method"`
+	URL    string      `json:"url"`
+	Status int         `json:"status"`
+	Header http.Header `json:"header,omitempty"`
+	Body   string      `json:"body"`
+}
+
+// CassetteFS opens a cassette directory, or the bundled fixtures when dir
+// is BundledFixtures.
+func CassetteFS(dir string) (fs.FS, error) {
+	if dir == BundledFixtures {
+		return fs.Sub(fixtures, "fixtures")
+	}
+	info, err := os.Stat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("error opening cassette directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("cassette %s is not a directory", dir)
+	}
+	return os.DirFS(dir), nil
+}
+
+// cassetteName returns the file a request is recorded in: the host of the
+// request and a hash of its method and URL, whose query parameters are
+// sorted so their order does not matter.
+func cassetteName(req *http.Request) string {
+	canonical := *req.URL
+	canonical.RawQuery = canonical.Query().Encode()
+	canonical.Fragment = ""
+	sum := sha256.Sum256([]byte(req.Method + " " + canonical.String()))
+	return path.Join(req.URL.Hostname(), hex.EncodeToString(sum[:8])+".json")
+}
+
+// replayTransport answers requests with the responses recorded in a
+// cassette and never reaches the network.
+type replayTransport struct {
+	cassette fs.FS
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t replayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	data, err := fs.ReadFile(t.cassette, cassetteName(req))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("%w for %s %s", ErrNotRecorded, req.Method, req.URL.Redacted())
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error reading recorded response: %w", err)
+	}
+	var recorded interaction
+	if err := json.Unmarshal(data, &recorded); err != nil {
+		return nil, fmt.Errorf("invalid recorded response for %s: %w", req.URL.Redacted(), err)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        recorded.Header,
+		Body:          io.NopCloser(strings.NewReader(recorded.Body)),
+		ContentLength: int64(len(recorded.Body)),
+		Request:       req,
+	}, nil
+}
+
+// recordTransport sends requests with next and writes every response to a
+// cassette directory, so it can be replayed later.
+type recordTransport struct {
+	dir  string
+	next http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("error reading response to record: %w", err)
+	}
+	resp.Body = io.NopCloser(bytes.NewReader(body))
+	header := resp.Header.Clone()
+	// Cookies and rate limit counters are no use in a replay.
+	header.Del("Set-Cookie")
+	var data bytes.Buffer
+	encoder := json.NewEncoder(&data)
+	encoder.SetEscapeHTML(false)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(interaction{
+		Method: req.Method,
+		URL:    req.URL.Redacted(),
+		Status: resp.StatusCode,
+		Header: header,
+		Body:   string(body),
+	}); err != nil {
+		return nil, fmt.Errorf("error encoding recorded response: %w", err)
+	}
+	name := filepath.Join(t.dir, filepath.FromSlash(cassetteName(req)))
+	if err := os.MkdirAll(filepath.Dir(name), 0o750); err != nil {
+		return nil, fmt.Errorf("error creating cassette directory: %w", err)
+	}
+	if err := os.WriteFile(name, data.Bytes(), 0o600); err != nil {
+		return nil, fmt.Errorf("error recording response: %w", err)
+	}
+	return resp, nil
+}
@@ -0,0 +1,108 @@
+package literaturetool
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithReplay_Bundled(t *testing.T) {
+	t.Parallel()
+	cassette, err := CassetteFS(BundledFixtures)
+	require.NoError(t, err)
+	client, err := NewLiteratureClient(WithReplay(cassette))
+	require.NoError(t, err)
+
+	for idType, identifier := range map[string]string{
+		IDTypePMID:        "40602797",
+		IDTypeDOI:         "10.1111/gtc.70037",
+		IDTypePMCID:       "PMC12221695",
+		IDTypeEuropePMCID: "MED:40602797",
+	} {
+		article, err := client.GetArticleWithFallback(context.Background(), identifier, idType)
+		require.NoError(t, err, idType)
+		assert.Equal(t, "europepmc", article.Source)
+		assert.Equal(t, "40602797", article.PMID)
+		assert.Equal(t, "Effect of Retinal on Dictyostelium Cells During Development.", article.Title)
+	}
+
+	_, err = client.GetArticleWithFallback(context.Background(), "10.1000/missing", IDTypeDOI)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, ErrNotRecorded.Error())
+}
+
+func TestWithRecording(t *testing.T) {
+	t.Parallel()
+	server := recordedEuropePMC(t)
+	dir := t.TempDir()
+	recorder, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithPubMedProvider(&fakeProvider{}),
+		WithRecording(dir),
+	)
+	require.NoError(t, err)
+	recorded, err := recorder.GetArticleFromEuropePMC(context.Background(), "10.1111/gtc.70037", IDTypeDOI)
+	require.NoError(t, err)
+
+	cassette, err := CassetteFS(dir)
+	require.NoError(t, err)
+	replayer, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithPubMedProvider(&fakeProvider{}),
+		WithReplay(cassette),
+	)
+	require.NoError(t, err)
+	server.Close()
+	replayed, err := replayer.GetArticleFromEuropePMC(context.Background(), "10.1111/gtc.70037", IDTypeDOI)
+	require.NoError(t, err, "the replay does not reach the closed server")
+	assert.Equal(t, recorded, replayed)
+
+	_, err = CassetteFS(t.TempDir() + "/missing")
+	require.Error(t, err)
+}
+
+func TestCassetteName(t *testing.T) {
+	t.Parallel()
+	request := func(rawURL string) *http.Request {
+		parsed, err := url.Parse(rawURL)
+		require.NoError(t, err)
+		return &http.Request{Method: http.MethodGet, URL: parsed}
+	}
+	name := cassetteName(request("https://www.ebi.ac.uk/search?query=a&format=json"))
+	assert.Regexp(t, `^www\.ebi\.ac\.uk/[0-9a-f]{16}\.json$`, name)
+	assert.Equal(t, name, cassetteName(request("https://www.ebi.ac.uk/search?format=json&query=a")),
+		"the order of query parameters does not matter")
+	assert.NotEqual(t, name, cassetteName(request("https://www.ebi.ac.uk/search?query=b&format=json")))
+}
+
+func TestFactory_Replay(t *testing.T) {
+	t.Parallel()
+	tool, err := registry.New("literature-fetch", registry.Dependencies{
+		Logger:     slog.Default(),
+		Literature: registry.LiteratureCassette{Replay: BundledFixtures},
+	})
+	require.NoError(t, err)
+	result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+		Params: mcp.CallToolParams{
+			Name:      "literature-fetch",
+			Arguments: map[string]any{"id": "PMC12221695", "id_type": IDTypePMCID},
+		},
+	})
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Effect of Retinal on Dictyostelium Cells")
+
+	_, err = registry.New("literature-fetch", registry.Dependencies{
+		Logger:     slog.Default(),
+		Literature: registry.LiteratureCassette{Replay: "/nonexistent/cassette"},
+	})
+	require.Error(t, err)
+}
@@ -0,0 +1,356 @@
+package literaturetool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Output formats of the literature tool. Apart from markdown, json and
+// summary they are citation formats reference managers import.
+const (
+	FormatMarkdown = "markdown"
+	FormatJSON     = "json"
+	FormatSummary  = "summary"
+	FormatBibTeX   = "bibtex"
+	FormatRIS      = "ris"
+	FormatEndNote  = "endnote"
+	FormatCSLJSON  = "csljson"
+)
+
+// pubmedURL is the address of a PubMed record, followed by its PMID.
+const pubmedURL = "https://pubmed.ncbi.nlm.nih.gov/"
+
+// RenderCitation renders the article in one of the citation formats.
+func RenderCitation(article *Article, format string) (string, error) {
+	switch format {
+	case FormatBibTeX:
+		return renderBibTeX(article), nil
+	case FormatRIS:
+		return renderRIS(article), nil
+	case FormatEndNote:
+		return renderEndNote(article), nil
+	case FormatCSLJSON:
+		return renderCSLJSON(article)
+	default:
+		return "", fmt.Errorf("unsupported citation format: %s", format)
+	}
+}
+
+// citationKey builds a key such as "fey2013dictybase" from the first
+// author's family name, the year and the first long word of the title.
+func citationKey(article *Article) string {
+	var key strings.Builder
+	if len(article.Authors) > 0 {
+		family, _ := authorName(article.Authors[0])
+		key.WriteString(keyWord(family))
+	}
+	if year, _, _ := issued(article); year > 0 {
+		fmt.Fprintf(&key, "%d", year)
+	}
+	for _, word := range strings.Fields(article.Title) {
+		if word = keyWord(word); len(word) > 3 {
+			key.WriteString(word)
+			break
+		}
+	}
+	if key.Len() == 0 {
+		return "pmid" + article.PMID
+	}
+	return key.String()
+}
+
+// keyWord lowercases word and drops everything but ASCII letters and
+// digits, which are safe in citation keys.
+func keyWord(word string) string {
+	return strings.Map(func(r rune) rune {
+		r = unicode.ToLower(r)
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			return r
+		}
+		return -1
+	}, word)
+}
+
+// authorName returns the family and given names of an author. Given names
+// fall back to the initials, and an author known only by a full name is
+// returned as the family name.
+func authorName(author Author) (string, string) {
+	if author.LastName == "" {
+		return strings.TrimSpace(author.FullName), ""
+	}
+	given := author.FirstName
+	if given == "" {
+		given = author.Initials
+	}
+	return author.LastName, given
+}
+
+// invertedName renders an author as "Family, Given".
+func invertedName(author Author) string {
+	family, given := authorName(author)
+	if given == "" {
+		return family
+	}
+	return family + ", " + given
+}
+
+// issued returns the publication date, as precise as it is known; unknown
+// parts are zero.
+func issued(article *Article) (int, int, int) {
+	if article.PublishDate != nil && !article.PublishDate.IsZero() {
+		date := article.PublishDate
+		return date.Year(), int(date.Month()), date.Day()
+	}
+	if article.Journal.YearOfPublication > 0 {
+		return article.Journal.YearOfPublication, article.Journal.MonthOfPublication, 0
+	}
+	var year int
+	if _, err := fmt.Sscanf(article.PubYear, "%d", &year); err != nil {
+		return 0, 0, 0
+	}
+	return year, 0, 0
+}
+
+// pageRange splits page info such as "123-30" into its first and last
+// page.
+func pageRange(pages string) (string, string) {
+	first, last, _ := strings.Cut(pages, "-")
+	return strings.TrimSpace(first), strings.TrimSpace(last)
+}
+
+// bibtexEscaper escapes the characters BibTeX and LaTeX treat specially.
+var bibtexEscaper = strings.NewReplacer(
+	`\`, `\textbackslash{}`,
+	"{", `\{`,
+	"}", `\}`,
+	"&", `\&`,
+	"%", `\%`,
+	"$", `\$`,
+	"#", `\#`,
+	"_", `\_`,
+	"~", `\textasciitilde{}`,
+	"^", `\textasciicircum{}`,
+)
+
+// renderBibTeX renders the article as a BibTeX @article entry.
+func renderBibTeX(article *Article) string {
+	var fields [][2]string
+	add := func(name, value string) {
+		if value = strings.TrimSpace(value); value != "" {
+			fields = append(fields, [2]string{name, value})
+		}
+	}
+	authors := make([]string, 0, len(article.Authors))
+	for _, author := range article.Authors {
+		family, given := authorName(author)
+		if given == "" {
+			// Braces keep a name without parts from being split.
+			authors = append(authors, "{"+bibtexEscaper.Replace(family)+"}")
+			continue
+		}
+		authors = append(authors, bibtexEscaper.Replace(family+", "+given))
+	}
+	add("author", strings.Join(authors, " and "))
+	if article.Title != "" {
+		// Double braces keep the capitalization of the title.
+		add("title", "{"+bibtexEscaper.Replace(article.Title)+"}")
+	}
+	add("journal", bibtexEscaper.Replace(article.Journal.Title))
+	year, month, _ := issued(article)
+	if year > 0 {
+		add("year", fmt.Sprint(year))
+	}
+	if month > 0 {
+		add("month", fmt.Sprint(month))
+	}
+	add("volume", article.Journal.Volume)
+	add("number", article.Journal.Issue)
+	add("pages", strings.Replace(article.PageInfo, "-", "--", 1))
+	add("issn", article.Journal.ISSN)
+	add("doi", article.DOI)
+	add("pmid", article.PMID)
+	add("pmcid", article.PMCID)
+	add("keywords", bibtexEscaper.Replace(strings.Join(article.Keywords, ", ")))
+	add("abstract", bibtexEscaper.Replace(article.Abstract))
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "@article{%s,\n", citationKey(article))
+	for i, field := range fields {
+		fmt.Fprintf(&builder, "  %s = {%s}", field[0], field[1])
+		if i < len(fields)-1 {
+			builder.WriteString(",")
+		}
+		builder.WriteString("\n")
+	}
+	builder.WriteString("}\n")
+	return builder.String()
+}
+
+// taggedWriter writes the tag and value lines shared by RIS and EndNote,
+// skipping empty values.
+type taggedWriter struct {
+	builder strings.Builder
+	format  string
+}
+
+// write adds a line for value under tag.
+func (w *taggedWriter) write(tag, value string) {
+	if value = strings.TrimSpace(value); value != "" {
+		fmt.Fprintf(&w.builder, w.format, tag, value)
+	}
+}
+
+// renderRIS renders the article as an RIS record.
+func renderRIS(article *Article) string {
+	writer := &taggedWriter{format: "%s  - %s\n"}
+	writer.write("TY", "JOUR")
+	for _, author := range article.Authors {
+		writer.write("AU", invertedName(author))
+	}
+	writer.write("TI", article.Title)
+	writer.write("T2", article.Journal.Title)
+	writer.write("J2", article.Journal.ISOAbbreviation)
+	year, month, day := issued(article)
+	if year > 0 {
+		writer.write("PY", fmt.Sprint(year))
+	}
+	if month > 0 {
+		date := fmt.Sprintf("%04d/%02d/", year, month)
+		if day > 0 {
+			date += fmt.Sprintf("%02d", day)
+		}
+		writer.write("DA", date)
+	}
+	writer.write("VL", article.Journal.Volume)
+	writer.write("IS", article.Journal.Issue)
+	first, last := pageRange(article.PageInfo)
+	writer.write("SP", first)
+	writer.write("EP", last)
+	writer.write("SN", article.Journal.ISSN)
+	writer.write("DO", article.DOI)
+	writer.write("AN", article.PMID)
+	writer.write("C2", article.PMCID)
+	if article.PMID != "" {
+		writer.write("UR", pubmedURL+article.PMID+"/")
+	}
+	writer.write("LA", article.Language)
+	for _, keyword := range article.Keywords {
+		writer.write("KW", keyword)
+	}
+	writer.write("AB", article.Abstract)
+	writer.builder.WriteString("ER  - \n")
+	return writer.builder.String()
+}
+
+// renderEndNote renders the article in the EndNote tagged (.enw) format.
+func renderEndNote(article *Article) string {
+	writer := &taggedWriter{format: "%s %s\n"}
+	writer.write("%0", "Journal Article")
+	for _, author := range article.Authors {
+		writer.write("%A", invertedName(author))
+	}
+	writer.write("%T", article.Title)
+	writer.write("%J", article.Journal.Title)
+	if year, _, _ := issued(article); year > 0 {
+		writer.write("%D", fmt.Sprint(year))
+	}
+	writer.write("%V", article.Journal.Volume)
+	writer.write("%N", article.Journal.Issue)
+	writer.write("%P", article.PageInfo)
+	writer.write("%@", article.Journal.ISSN)
+	writer.write("%R", article.DOI)
+	writer.write("%M", article.PMID)
+	if article.PMID != "" {
+		writer.write("%U", pubmedURL+article.PMID+"/")
+	}
+	writer.write("%G", article.Language)
+	for _, keyword := range article.Keywords {
+		writer.write("%K", keyword)
+	}
+	writer.write("%X", article.Abstract)
+	return writer.builder.String()
+}
+
+// cslItem is an article in CSL-JSON, the input format of citeproc
+// processors.
+type cslItem struct {
+	ID                  string    `json:"id"`
+	Type                string    `json:"type"`
+	Title               string    `json:"title,omitempty"`
+	ContainerTitle      string    `json:"container-title,omitempty"`
+	ContainerTitleShort string    `json:"container-title-short,omitempty"`
+	Author              []cslName `json:"author,omitempty"`
+	Issued              *cslDate  `json:"issued,omitempty"`
+	Volume              string    `json:"volume,omitempty"`
+	Issue               string    `json:"issue,omitempty"`
+	Page                string    `json:"page,omitempty"`
+	ISSN                string    `json:"ISSN,omitempty"`
+	DOI                 string    `json:"DOI,omitempty"`
+	PMID                string    `json:"PMID,omitempty"`
+	PMCID               string    `json:"PMCID,omitempty"`
+	URL                 string    `json:"URL,omitempty"`
+	Language            string    `json:"language,omitempty"`
+	Keyword             string    `json:"keyword,omitempty"`
+	Abstract            string    `json:"abstract,omitempty"`
+}
+
+// cslName is a CSL-JSON name; literal holds names without parts.
+type cslName struct {
+	Family  string `json:"family,omitempty"`
+	Given   string `json:"given,omitempty"`
+	Literal string `json:"literal,omitempty"`
+}
+
+// cslDate is a CSL-JSON date.
+type cslDate struct {
+	DateParts [][]int `json:"date-parts"`
+}
+
+// renderCSLJSON renders the article as a CSL-JSON array of one item.
+func renderCSLJSON(article *Article) (string, error) {
+	item := cslItem{
+		ID:                  citationKey(article),
+		Type:                "article-journal",
+		Title:               article.Title,
+		ContainerTitle:      article.Journal.Title,
+		ContainerTitleShort: article.Journal.ISOAbbreviation,
+		Volume:              article.Journal.Volume,
+		Issue:               article.Journal.Issue,
+		Page:                article.PageInfo,
+		ISSN:                article.Journal.ISSN,
+		DOI:                 article.DOI,
+		PMID:                article.PMID,
+		PMCID:               article.PMCID,
+		Language:            article.Language,
+		Keyword:             strings.Join(article.Keywords, ", "),
+		Abstract:            article.Abstract,
+	}
+	if article.PMID != "" {
+		item.URL = pubmedURL + article.PMID + "/"
+	}
+	for _, author := range article.Authors {
+		family, given := authorName(author)
+		if given == "" {
+			item.Author = append(item.Author, cslName{Literal: family})
+			continue
+		}
+		item.Author = append(item.Author, cslName{Family: family, Given: given})
+	}
+	if year, month, day := issued(article); year > 0 {
+		parts := []int{year}
+		if month > 0 {
+			parts = append(parts, month)
+			if day > 0 {
+				parts = append(parts, day)
+			}
+		}
+		item.Issued = &cslDate{DateParts: [][]int{parts}}
+	}
+	data, err := json.MarshalIndent([]cslItem{item}, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to encode CSL-JSON: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,154 @@
+package literaturetool
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func citationArticle() *Article {
+	published := time.Date(2013, 1, 15, 0, 0, 0, 0, time.UTC)
+	return &Article{
+		PMID:  "23172289",
+		PMCID: "PMC3531190",
+		DOI:   "10.1093/nar/gks1064",
+		Title: "dictyBase 2013: integrating multiple Dictyostelid species",
+		Authors: []Author{
+			{FullName: "Basu S", FirstName: "Siddhartha", LastName: "Basu", Initials: "S"},
+			{FullName: "Fey P", LastName: "Fey", Initials: "P"},
+			{FullName: "dictyBase Consortium"},
+		},
+		Abstract: "Resources for R&D at 100% coverage.",
+		Journal: Journal{
+			Title:           "Nucleic Acids Research",
+			ISOAbbreviation: "Nucleic Acids Res",
+			ISSN:            "0305-1048",
+			Volume:          "41",
+			Issue:           "D1",
+		},
+		PubYear:     "2013",
+		PageInfo:    "D676-83",
+		Keywords:    []string{"Dictyostelium", "databases"},
+		Language:    "eng",
+		PublishDate: &published,
+	}
+}
+
+func TestCitationKey(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "basu2013dictybase", citationKey(citationArticle()))
+	assert.Equal(t, "pmid42", citationKey(&Article{PMID: "42"}))
+}
+
+func TestRenderCitation_BibTeX(t *testing.T) {
+	t.Parallel()
+	rendered, err := RenderCitation(citationArticle(), FormatBibTeX)
+	require.NoError(t, err)
+	assert.Equal(t, `@article{basu2013dictybase,
+  author = {Basu, Siddhartha and Fey, P and {dictyBase Consortium}},
+  title = {{dictyBase 2013: integrating multiple Dictyostelid species}},
+  journal = {Nucleic Acids Research},
+  year = {2013},
+  month = {1},
+  volume = {41},
+  number = {D1},
+  pages = {D676--83},
+  issn = {0305-1048},
+  doi = {10.1093/nar/gks1064},
+  pmid = {23172289},
+  pmcid = {PMC3531190},
+  keywords = {Dictyostelium, databases},
+  abstract = {Resources for R\&D at 100\% coverage.}
+}
+`, rendered)
+}
+
+func TestRenderCitation_RIS(t *testing.T) {
+	t.Parallel()
+	rendered, err := RenderCitation(citationArticle(), FormatRIS)
+	require.NoError(t, err)
+	assert.Equal(t, `TY  - JOUR
+AU  - Basu, Siddhartha
+AU  - Fey, P
+AU  - dictyBase Consortium
+TI  - dictyBase 2013: integrating multiple Dictyostelid species
+T2  - Nucleic Acids Research
+J2  - Nucleic Acids Res
+PY  - 2013
+DA  - 2013/01/15
+VL  - 41
+IS  - D1
+SP  - D676
+EP  - 83
+SN  - 0305-1048
+DO  - 10.1093/nar/gks1064
+AN  - 23172289
+C2  - PMC3531190
+UR  - https://pubmed.ncbi.nlm.nih.gov/23172289/
+LA  - eng
+KW  - Dictyostelium
+KW  - databases
+AB  - Resources for R&D at 100% coverage.
+`+"ER  - \n", rendered)
+}
+
+func TestRenderCitation_EndNote(t *testing.T) {
+	t.Parallel()
+	rendered, err := RenderCitation(citationArticle(), FormatEndNote)
+	require.NoError(t, err)
+	assert.Equal(t, `%0 Journal Article
+%A Basu, Siddhartha
+%A Fey, P
+%A dictyBase Consortium
+%T dictyBase 2013: integrating multiple Dictyostelid species
+%J Nucleic Acids Research
+%D 2013
+%V 41
+%N D1
+%P D676-83
+%@ 0305-1048
+%R 10.1093/nar/gks1064
+%M 23172289
+%U https://pubmed.ncbi.nlm.nih.gov/23172289/
+%G eng
+%K Dictyostelium
+%K databases
+%X Resources for R&D at 100% coverage.
+`, rendered)
+}
+
+func TestRenderCitation_CSLJSON(t *testing.T) {
+	t.Parallel()
+	rendered, err := RenderCitation(citationArticle(), FormatCSLJSON)
+	require.NoError(t, err)
+	var items []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(rendered), &items))
+	require.Len(t, items, 1)
+	item := items[0]
+	assert.Equal(t, "basu2013dictybase", item["id"])
+	assert.Equal(t, "article-journal", item["type"])
+	assert.Equal(t, "Nucleic Acids Research", item["container-title"])
+	assert.Equal(t, "10.1093/nar/gks1064", item["DOI"])
+	assert.Equal(t, []any{
+		map[string]any{"family": "Basu", "given": "Siddhartha"},
+		map[string]any{"family": "Fey", "given": "P"},
+		map[string]any{"literal": "dictyBase Consortium"},
+	}, item["author"])
+	assert.Equal(t, map[string]any{"date-parts": []any{[]any{2013.0, 1.0, 15.0}}}, item["issued"])
+
+	minimal, err := RenderCitation(&Article{Title: "Untitled", PubYear: "2020"}, FormatCSLJSON)
+	require.NoError(t, err)
+	var minimalItems []map[string]any
+	require.NoError(t, json.Unmarshal([]byte(minimal), &minimalItems))
+	assert.Equal(t, map[string]any{"date-parts": []any{[]any{2020.0}}}, minimalItems[0]["issued"])
+	assert.NotContains(t, minimalItems[0], "author")
+}
+
+func TestRenderCitation_Unsupported(t *testing.T) {
+	t.Parallel()
+	_, err := RenderCitation(citationArticle(), "markdown")
+	assert.Error(t, err)
+}
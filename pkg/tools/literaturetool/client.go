@@ -2,24 +2,68 @@ package literaturetool
 
 import (
 	"context"
+	"errors"
 	"fmt"
-	"log"
+	"io/fs"
+	"log/slog"
+	"net/http"
+	"strconv"
 	"strings"
 	"time"
 
-	"github.com/dictybase/literature"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
 )
 
+// Identifier types accepted by the client.
 const (
 	IDTypePMID = "pmid"
 	IDTypeDOI  = "doi"
+	// IDTypePMCID is a PubMed Central ID such as PMC3531190.
+	IDTypePMCID = "pmcid"
+	// IDTypeEuropePMCID is a Europe PMC record written SOURCE:ID, such as
+	// MED:23172289 or PPR:PPR123456 for a preprint.
+	IDTypeEuropePMCID = "europepmc_id"
 )
 
-// LiteratureClient wraps the dictyBase literature clients.
+// idTypeLabels are the names of the identifier types used in messages.
+var idTypeLabels = map[string]string{
+	IDTypePMID:        "PMID",
+	IDTypeDOI:         "DOI",
+	IDTypePMCID:       "PMCID",
+	IDTypeEuropePMCID: "Europe PMC ID",
+}
+
+// Provider looks up articles in PubMed or EuropePMC. The literature client
+// reaches both through providers, so tests can replace them with fakes.
+// Calls must return once ctx is done; the client adds its rate limits,
+// retries and a deadline for every attempt.
+type Provider interface {
+	// GetArticle fetches the article with a PMID.
+	GetArticle(ctx context.Context, pmid string) (*Article, error)
+	// Search returns at most limit articles matching a query written in
+	// the provider's search syntax.
+	Search(ctx context.Context, query string, limit int) ([]*Article, error)
+}
+
+// LiteratureClient wraps the PubMed and EuropePMC providers, a Crossref
+// client for DOIs neither of them knows, a client for preprint servers, an
+// OpenAlex client for concept tags and citation metrics and a Semantic
+// Scholar client for influential citations and TLDR summaries.
 type LiteratureClient struct {
-	pubmedClient    *literature.Client
-	europePMCClient *literature.EuropePMCClient
-	logger          *log.Logger
+	pubmed                Provider
+	europePMC             Provider
+	europePMCREST         *europePMCProvider
+	crossrefClient        *CrossrefClient
+	preprintClient        *PreprintClient
+	openAlexClient        *OpenAlexClient
+	semanticScholarClient *SemanticScholarClient
+	dataLinksClient       *DataLinksClient
+	// requestTimeout bounds every attempt of a PubMed or EuropePMC call.
+	requestTimeout time.Duration
+	logger         *slog.Logger
 }
 
 // Option represents a configuration option for LiteratureClient.
@@ -27,11 +71,26 @@ type Option func(*Config)
 
 // Config holds the configuration for the literature client.
 type Config struct {
-	timeout time.Duration
-	logger  *log.Logger
+	timeout               time.Duration
+	logger                *slog.Logger
+	httpClient            *http.Client
+	pubmed                Provider
+	europePMC             Provider
+	pubMedURL             string
+	europePMCURL          string
+	annotationsURL        string
+	cassette              fs.FS
+	recordDir             string
+	crossrefURL           string
+	bioRxivURL            string
+	arXivURL              string
+	openAlexURL           string
+	semanticScholarURL    string
+	semanticScholarAPIKey string
 }
 
-// WithTimeout sets the HTTP timeout for requests.
+// WithTimeout sets the HTTP timeout for requests, which is also the
+// deadline of every attempt of a PubMed or EuropePMC call.
 func WithTimeout(timeout time.Duration) Option {
 	return func(c *Config) {
 		c.timeout = timeout
@@ -39,61 +98,215 @@ func WithTimeout(timeout time.Duration) Option {
 }
 
 // WithLogger sets the logger for the client.
-func WithLogger(logger *log.Logger) Option {
+func WithLogger(logger *slog.Logger) Option {
 	return func(c *Config) {
 		c.logger = logger
 	}
 }
 
-// NewLiteratureClient creates a new literature client with both PubMed and EuropePMC support.
+// WithHTTPClient sets the HTTP client of every provider, replacing the
+// rate-limited clients with the timeout of WithTimeout. Tests pass the
+// client of a server replaying recorded responses.
+func WithHTTPClient(client *http.Client) Option {
+	return func(c *Config) {
+		c.httpClient = client
+	}
+}
+
+// WithPubMedProvider replaces the PubMed provider.
+func WithPubMedProvider(provider Provider) Option {
+	return func(c *Config) {
+		c.pubmed = provider
+	}
+}
+
+// WithEuropePMCProvider replaces the EuropePMC provider.
+func WithEuropePMCProvider(provider Provider) Option {
+	return func(c *Config) {
+		c.europePMC = provider
+	}
+}
+
+// WithReplay answers every request of the client with the responses
+// recorded in cassette instead of reaching the network, so demos and CI
+// run offline; requests without a recorded response fail with
+// ErrNotRecorded. See CassetteFS.
+func WithReplay(cassette fs.FS) Option {
+	return func(c *Config) {
+		c.cassette = cassette
+	}
+}
+
+// WithRecording writes the response to every request of the client to the
+// cassette directory dir, for WithReplay to serve later.
+func WithRecording(dir string) Option {
+	return func(c *Config) {
+		c.recordDir = dir
+	}
+}
+
+// WithPubMedURL overrides the NCBI E-utilities base URL PubMed records are
+// fetched from by PMID. The literature library always searches NCBI.
+func WithPubMedURL(pubMedURL string) Option {
+	return func(c *Config) {
+		c.pubMedURL = pubMedURL
+	}
+}
+
+// WithEuropePMCURL overrides the EuropePMC REST API base URL.
+func WithEuropePMCURL(europePMCURL string) Option {
+	return func(c *Config) {
+		c.europePMCURL = europePMCURL
+	}
+}
+
+// WithAnnotationsURL overrides the Europe PMC annotations API base URL,
+// which serves the accession numbers cited by articles.
+func WithAnnotationsURL(annotationsURL string) Option {
+	return func(c *Config) {
+		c.annotationsURL = annotationsURL
+	}
+}
+
+// WithCrossrefURL overrides the Crossref API base URL.
+func WithCrossrefURL(crossrefURL string) Option {
+	return func(c *Config) {
+		c.crossrefURL = crossrefURL
+	}
+}
+
+// WithBioRxivURL overrides the bioRxiv API base URL, which also serves
+// medRxiv.
+func WithBioRxivURL(bioRxivURL string) Option {
+	return func(c *Config) {
+		c.bioRxivURL = bioRxivURL
+	}
+}
+
+// WithArXivURL overrides the arXiv API base URL.
+func WithArXivURL(arXivURL string) Option {
+	return func(c *Config) {
+		c.arXivURL = arXivURL
+	}
+}
+
+// WithOpenAlexURL overrides the OpenAlex API base URL.
+func WithOpenAlexURL(openAlexURL string) Option {
+	return func(c *Config) {
+		c.openAlexURL = openAlexURL
+	}
+}
+
+// WithSemanticScholarURL overrides the Semantic Scholar Graph API base URL.
+func WithSemanticScholarURL(semanticScholarURL string) Option {
+	return func(c *Config) {
+		c.semanticScholarURL = semanticScholarURL
+	}
+}
+
+// WithSemanticScholarAPIKey sets the Semantic Scholar API key, which gives
+// the client a rate limit of its own instead of the shared public pool.
+func WithSemanticScholarAPIKey(apiKey string) Option {
+	return func(c *Config) {
+		c.semanticScholarAPIKey = apiKey
+	}
+}
+
+// NewLiteratureClient creates a new literature client with PubMed, EuropePMC, Crossref, preprint
+// server, OpenAlex and Semantic Scholar support.
 func NewLiteratureClient(opts ...Option) (*LiteratureClient, error) {
 	cfg := &Config{
-		timeout: 30 * time.Second,
-		logger:  log.Default(),
+		timeout:            30 * time.Second,
+		logger:             slog.Default(),
+		pubMedURL:          defaultPubMedURL,
+		crossrefURL:        defaultCrossrefURL,
+		bioRxivURL:         defaultBioRxivURL,
+		arXivURL:           defaultArXivURL,
+		openAlexURL:        defaultOpenAlexURL,
+		semanticScholarURL: defaultSemanticScholarURL,
+		annotationsURL:     defaultAnnotationsURL,
 	}
 
 	for _, opt := range opts {
 		opt(cfg)
 	}
-
-	// Create PubMed client
-	pubmedClient, err := literature.New(
-		literature.WithTimeout(cfg.timeout),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create PubMed client: %w", err)
+	switch {
+	case cfg.cassette != nil:
+		cfg.httpClient = &http.Client{Timeout: cfg.timeout, Transport: replayTransport{cassette: cfg.cassette}}
+	case cfg.recordDir != "":
+		next := cfg.client().Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		cfg.httpClient = &http.Client{Timeout: cfg.timeout, Transport: recordTransport{dir: cfg.recordDir, next: next}}
 	}
 
-	// Create EuropePMC client
-	europePMCClient, err := literature.NewEuropePMCClient(
-		literature.WithEuropePMCTimeout(cfg.timeout),
-	)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create EuropePMC client: %w", err)
+	pubmed := cfg.pubmed
+	if pubmed == nil {
+		pubmed = newPubMedProvider(cfg)
+	}
+	europePMC := cfg.europePMC
+	if europePMC == nil {
+		europePMC = newEuropePMCProvider(cfg)
 	}
 
 	return &LiteratureClient{
-		pubmedClient:    pubmedClient,
-		europePMCClient: europePMCClient,
-		logger:          cfg.logger,
+		pubmed:                pubmed,
+		europePMC:             europePMC,
+		europePMCREST:         newEuropePMCProvider(cfg),
+		crossrefClient:        newCrossrefClient(cfg),
+		preprintClient:        newPreprintClient(cfg),
+		openAlexClient:        newOpenAlexClient(cfg),
+		semanticScholarClient: newSemanticScholarClient(cfg),
+		dataLinksClient:       newDataLinksClient(cfg),
+		requestTimeout:        cfg.timeout,
+		logger:                cfg.logger,
 	}, nil
 }
 
+// client returns the HTTP client set with WithHTTPClient, or a new
+// rate-limited client.
+func (c *Config) client() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return ratelimit.NewHTTPClient(c.timeout)
+}
+
+// providerClient returns the HTTP client set with WithHTTPClient, or a new
+// client with the timeout of WithTimeout. PubMed and EuropePMC calls wait
+// for their rate limit before each attempt, so their client does not.
+func (c *Config) providerClient() *http.Client {
+	if c.httpClient != nil {
+		return c.httpClient
+	}
+	return &http.Client{Timeout: c.timeout}
+}
+
 // GetArticleFromPubMed fetches article information from PubMed.
 func (c *LiteratureClient) GetArticleFromPubMed(ctx context.Context, identifier, idType string) (*Article, error) {
-	var article interface{}
+	var article *Article
 	var err error
 
 	switch idType {
 	case IDTypePMID:
-		article, err = c.pubmedClient.GetArticle(identifier)
-	case IDTypeDOI:
-		// PubMed doesn't directly support DOI lookup, so we'll use EuropePMC as fallback
+		start := time.Now()
+		err = c.runWithRetry(ctx, ratelimit.ProviderPubMed, func(ctx context.Context) error {
+			var callErr error
+			article, callErr = c.pubmed.GetArticle(ctx, identifier)
+			return callErr
+		})
+		metrics.ObserveOutbound(metrics.ServicePubMed, start, err)
+	case IDTypeDOI, IDTypePMCID, IDTypeEuropePMCID:
+		// PubMed only looks up PMIDs, so we'll use EuropePMC as fallback
 		return c.GetArticleFromEuropePMC(ctx, identifier, idType)
 	default:
 		return nil, fmt.Errorf("unsupported ID type for PubMed: %s", idType)
 	}
 
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("PubMed request aborted: %w", ctxErr)
+	}
 	if err != nil {
 		// Convert to our standard error format
 		if isNotFoundError(err) {
@@ -103,6 +316,9 @@ func (c *LiteratureClient) GetArticleFromPubMed(ctx context.Context, identifier,
 				Code:    "PUBMED_NOT_FOUND",
 			}
 		}
+		if unavailable := unavailableError("PubMed", err); unavailable != nil {
+			return nil, unavailable
+		}
 		return nil, &LiteratureError{
 			Type:    ErrorTypeAPIError,
 			Message: fmt.Sprintf("PubMed API error: %v", err),
@@ -110,40 +326,64 @@ func (c *LiteratureClient) GetArticleFromPubMed(ctx context.Context, identifier,
 		}
 	}
 
-	return c.convertToStandardArticle(article, "pubmed")
+	return article, nil
 }
 
 // GetArticleFromEuropePMC fetches article information from EuropePMC.
 func (c *LiteratureClient) GetArticleFromEuropePMC(ctx context.Context, identifier, idType string) (*Article, error) {
-	var article interface{}
+	var article *Article
 	var err error
 
+	start := time.Now()
 	switch idType {
 	case IDTypePMID:
-		article, err = c.europePMCClient.GetArticle(identifier)
-	case IDTypeDOI:
-		// For DOI, we need to search first to get the article
-		searchResult, searchErr := c.europePMCClient.Search(
-			fmt.Sprintf("DOI:%s", identifier),
-			literature.WithEuropePMCLimit(1),
-		)
+		err = c.runWithRetry(ctx, ratelimit.ProviderEuropePMC, func(ctx context.Context) error {
+			var callErr error
+			article, callErr = c.europePMC.GetArticle(ctx, identifier)
+			return callErr
+		})
+		metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, err)
+	case IDTypeDOI, IDTypePMCID, IDTypeEuropePMCID:
+		// Other identifiers than PMIDs need a search to get the article
+		found := false
+		searchErr := c.runWithRetry(ctx, ratelimit.ProviderEuropePMC, func(ctx context.Context) error {
+			articles, callErr := c.europePMC.Search(ctx, europePMCQuery(identifier, idType), 1)
+			if callErr == nil && len(articles) > 0 {
+				article = articles[0]
+				found = true
+			}
+			return callErr
+		})
+		metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, searchErr)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, fmt.Errorf("EuropePMC request aborted: %w", ctxErr)
+		}
+		if unavailable := unavailableError("EuropePMC", searchErr); unavailable != nil {
+			return nil, unavailable
+		}
 		if searchErr != nil {
-			return nil, fmt.Errorf("EuropePMC search error: %w", searchErr)
+			return nil, toolerror.Wrap(
+				ErrorTypeAPIError,
+				"EUROPEPMC_SEARCH_ERROR",
+				searchErr,
+				"EuropePMC search error",
+			)
 		}
 
-		if len(searchResult.Articles) == 0 {
+		if !found {
 			return nil, &LiteratureError{
 				Type:    ErrorTypeArticleNotFound,
-				Message: fmt.Sprintf("no article found for DOI: %s", identifier),
-				Code:    "DOI_NOT_FOUND",
+				Message: fmt.Sprintf("no article found for %s: %s", idTypeLabels[idType], identifier),
+				Code:    strings.ToUpper(idType) + "_NOT_FOUND",
 			}
 		}
-
-		article = searchResult.Articles[0]
 	default:
 		return nil, fmt.Errorf("unsupported ID type for EuropePMC: %s", idType)
 	}
 
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("EuropePMC request aborted: %w", ctxErr)
+	}
 	if err != nil {
 		// Convert to our standard error format
 		if isNotFoundError(err) {
@@ -153,6 +393,9 @@ func (c *LiteratureClient) GetArticleFromEuropePMC(ctx context.Context, identifi
 				Code:    "EUROPEPMC_NOT_FOUND",
 			}
 		}
+		if unavailable := unavailableError("EuropePMC", err); unavailable != nil {
+			return nil, unavailable
+		}
 		return nil, &LiteratureError{
 			Type:    ErrorTypeAPIError,
 			Message: fmt.Sprintf("EuropePMC API error: %v", err),
@@ -160,241 +403,369 @@ func (c *LiteratureClient) GetArticleFromEuropePMC(ctx context.Context, identifi
 		}
 	}
 
-	return c.convertToStandardArticle(article, "europepmc")
+	return article, nil
 }
 
-// isNotFoundError checks if an error indicates that an article was not found.
-func isNotFoundError(err error) bool {
-	if err == nil {
-		return false
-	}
+// FetchEuropePMC gets a path of the EuropePMC REST API, such as
+// "/search?query=...", and decodes the JSON response into out, for the
+// tools reading endpoints the Provider interface does not cover. Like the
+// article lookups it waits for the EuropePMC rate limit and retries
+// transient failures, and it replays or records responses as the client
+// is configured. Responses other than 200 OK fail with a
+// *retry.StatusError.
+func (c *LiteratureClient) FetchEuropePMC(ctx context.Context, path string, out any) error {
+	start := time.Now()
+	err := c.runWithRetry(ctx, ratelimit.ProviderEuropePMC, func(ctx context.Context) error {
+		return c.europePMCREST.fetch(ctx, path, out)
+	})
+	metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, err)
+	return err
+}
 
-	errMsg := strings.ToLower(err.Error())
-	notFoundIndicators := []string{
-		"not found",
-		"404",
-		"no results",
-		"no articles found",
-		"article not found",
+// AddDataLinks adds the supplementary files and the dataset accessions of
+// an article found by Europe PMC, which are looked up by PMCID and by
+// PMID or PMCID. Data links already set are kept. Both are tried; the
+// errors of the lookups that failed are returned together.
+func (c *LiteratureClient) AddDataLinks(ctx context.Context, article *Article) error {
+	var errs []error
+	if article.PMCID != "" && len(article.SupplementaryFiles) == 0 {
+		files, err := c.dataLinksClient.SupplementaryFiles(ctx, article.PMCID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing supplementary files: %w", err))
+		}
+		article.SupplementaryFiles = files
 	}
-
-	for _, indicator := range notFoundIndicators {
-		if strings.Contains(errMsg, indicator) {
-			return true
+	articleID := "MED:" + article.PMID
+	if article.PMID == "" {
+		articleID = "PMC:" + article.PMCID
+	}
+	if (article.PMID != "" || article.PMCID != "") && len(article.DataCitations) == 0 {
+		citations, err := c.dataLinksClient.DataCitations(ctx, articleID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("error listing data citations: %w", err))
 		}
+		article.DataCitations = citations
 	}
-
-	return false
+	if len(article.FieldSources) > 0 {
+		if len(article.SupplementaryFiles) > 0 {
+			article.FieldSources["supplementary_files"] = metrics.ServiceEuropePMC
+		}
+		if len(article.DataCitations) > 0 {
+			article.FieldSources["data_citations"] = metrics.ServiceEuropePMC
+		}
+	}
+	return errors.Join(errs...)
 }
 
-// GetArticleWithFallback implements the recommended logic: EuropePMC first, then PubMed fallback.
-func (c *LiteratureClient) GetArticleWithFallback(ctx context.Context, identifier, idType string) (*Article, error) {
-	// Try EuropePMC first
-	article, err := c.GetArticleFromEuropePMC(ctx, identifier, idType)
-	if err == nil {
-		return article, nil
+// europePMCQuery returns the Europe PMC search query matching the article
+// with the given DOI, PMCID or Europe PMC ID.
+func europePMCQuery(identifier, idType string) string {
+	switch idType {
+	case IDTypePMCID:
+		return "PMCID:" + identifier
+	case IDTypeEuropePMCID:
+		source, externalID, _ := strings.Cut(identifier, ":")
+		return fmt.Sprintf("EXT_ID:%s AND SRC:%s", externalID, source)
+	default:
+		return "DOI:" + identifier
 	}
+}
 
-	c.logger.Printf("EuropePMC failed for %s %s: %v, trying PubMed fallback", idType, identifier, err)
-
-	// Only try PubMed fallback for PMIDs (since PubMed doesn't handle DOIs directly)
-	if idType == IDTypePMID {
-		fallbackArticle, fallbackErr := c.GetArticleFromPubMed(ctx, identifier, idType)
-		if fallbackErr == nil {
-			return fallbackArticle, nil
+// GetArticleFromCrossref fetches article information from Crossref, which
+// only resolves DOIs.
+func (c *LiteratureClient) GetArticleFromCrossref(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType != IDTypeDOI {
+		return nil, fmt.Errorf("unsupported ID type for Crossref: %s", idType)
+	}
+	article, err := c.crossrefClient.Work(ctx, identifier)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("Crossref request aborted: %w", ctxErr)
+	}
+	if errors.Is(err, errCrossrefNotFound) {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeArticleNotFound,
+			Message: fmt.Sprintf("article not found in Crossref for %s: %s", idType, identifier),
+			Code:    "CROSSREF_NOT_FOUND",
 		}
-		c.logger.Printf("PubMed fallback also failed for PMID %s: %v", identifier, fallbackErr)
 	}
-
-	// Return the original EuropePMC error
-	return nil, err
+	if err != nil {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeAPIError,
+			Message: fmt.Sprintf("Crossref API error: %v", err),
+			Code:    "CROSSREF_API_ERROR",
+		}
+	}
+	return article, nil
 }
 
-// convertToStandardArticle converts provider-specific article structs to our standard Article struct.
-func (c *LiteratureClient) convertToStandardArticle(article interface{}, provider string) (*Article, error) {
-	switch provider {
-	case "pubmed":
-		return c.convertPubMedArticle(article)
-	case "europepmc":
-		return c.convertEuropePMCArticle(article)
-	default:
-		return nil, fmt.Errorf("unsupported provider: %s", provider)
+// GetArticleFromPreprintServer fetches a preprint from bioRxiv, medRxiv or
+// arXiv, the servers that know whether a journal has published it.
+func (c *LiteratureClient) GetArticleFromPreprintServer(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType != IDTypeDOI || !IsPreprintDOI(identifier) {
+		return nil, fmt.Errorf("not a preprint DOI: %s", identifier)
+	}
+	article, err := c.preprintClient.Preprint(ctx, identifier)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("preprint server request aborted: %w", ctxErr)
+	}
+	if errors.Is(err, errPreprintNotFound) {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeArticleNotFound,
+			Message: fmt.Sprintf("preprint not found for DOI: %s", identifier),
+			Code:    "PREPRINT_NOT_FOUND",
+		}
 	}
+	if err != nil {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeAPIError,
+			Message: fmt.Sprintf("preprint server API error: %v", err),
+			Code:    "PREPRINT_API_ERROR",
+		}
+	}
+	return article, nil
 }
 
-// convertPubMedArticle converts a PubMed article to our standard format.
-func (c *LiteratureClient) convertPubMedArticle(article interface{}) (*Article, error) {
-	// Type assertion for PubMed article
-	pubmedArticle, ok := article.(*literature.Article)
-	if !ok {
-		return nil, fmt.Errorf("invalid PubMed article type")
+// GetArticleFromOpenAlex fetches article information from OpenAlex, the
+// only provider with concept tags, institution-resolved affiliations and
+// citation percentiles.
+func (c *LiteratureClient) GetArticleFromOpenAlex(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType != IDTypePMID && idType != IDTypeDOI && idType != IDTypePMCID {
+		return nil, fmt.Errorf("unsupported ID type for OpenAlex: %s", idType)
+	}
+	article, err := c.openAlexClient.Work(ctx, identifier, idType)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("OpenAlex request aborted: %w", ctxErr)
 	}
+	if errors.Is(err, errOpenAlexNotFound) {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeArticleNotFound,
+			Message: fmt.Sprintf("article not found in OpenAlex for %s: %s", idType, identifier),
+			Code:    "OPENALEX_NOT_FOUND",
+		}
+	}
+	if err != nil {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeAPIError,
+			Message: fmt.Sprintf("OpenAlex API error: %v", err),
+			Code:    "OPENALEX_API_ERROR",
+		}
+	}
+	return article, nil
+}
 
-	// Convert authors
-	authors := make([]Author, len(pubmedArticle.Authors))
-	for i, author := range pubmedArticle.Authors {
-		authors[i] = Author{
-			FullName:  author.FullName,
-			FirstName: author.FirstName,
-			LastName:  author.LastName,
+// GetArticleFromSemanticScholar fetches article information from Semantic
+// Scholar, the only provider with influential citation counts, fields of
+// study and TLDR summaries.
+func (c *LiteratureClient) GetArticleFromSemanticScholar(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType != IDTypePMID && idType != IDTypeDOI && idType != IDTypePMCID {
+		return nil, fmt.Errorf("unsupported ID type for Semantic Scholar: %s", idType)
+	}
+	article, err := c.semanticScholarClient.Paper(ctx, identifier, idType)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("Semantic Scholar request aborted: %w", ctxErr)
+	}
+	if errors.Is(err, errSemanticScholarNotFound) {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeArticleNotFound,
+			Message: fmt.Sprintf("article not found in Semantic Scholar for %s: %s", idType, identifier),
+			Code:    "SEMANTICSCHOLAR_NOT_FOUND",
 		}
 	}
+	if err != nil {
+		return nil, &LiteratureError{
+			Type:    ErrorTypeAPIError,
+			Message: fmt.Sprintf("Semantic Scholar API error: %v", err),
+			Code:    "SEMANTICSCHOLAR_API_ERROR",
+		}
+	}
+	return article, nil
+}
 
-	// Extract year from publish date
-	pubYear := ""
-	if !pubmedArticle.PublishDate.IsZero() {
-		pubYear = fmt.Sprintf("%d", pubmedArticle.PublishDate.Year())
-	}
-
-	return &Article{
-		ID:           pubmedArticle.PMID,
-		Source:       "pubmed",
-		PMID:         pubmedArticle.PMID,
-		DOI:          pubmedArticle.DOI,
-		Title:        pubmedArticle.Title,
-		AuthorString: "", // Will be constructed from authors
-		Authors:      authors,
-		Abstract:     pubmedArticle.Abstract,
-		Journal: Journal{
-			Title:  pubmedArticle.Journal,
-			Volume: pubmedArticle.Volume,
-			Issue:  pubmedArticle.Issue,
-		},
-		PubYear:      pubYear,
-		PageInfo:     pubmedArticle.Pages,
-		Keywords:     pubmedArticle.Keywords,
-		IsOpenAccess: false,
-		HasPDF:       false,
-		CitedByCount: 0,
-		PublishDate:  &pubmedArticle.PublishDate,
-	}, nil
+// runWithContext waits for the provider's rate limit, then runs a
+// literature call with a context that is done when ctx is, or when timeout
+// passes first. An attempt that runs out of its own time fails with a
+// requestTimeoutError, while ctx's errors are returned as they are.
+func runWithContext(ctx context.Context, provider string, timeout time.Duration, call func(context.Context) error) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if err := ratelimit.Wait(ctx, provider); err != nil {
+		return err
+	}
+	attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+	if timeout > 0 {
+		attemptCtx, cancel = context.WithTimeout(ctx, timeout)
+	}
+	defer cancel()
+	err := call(attemptCtx)
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+	if err != nil && errors.Is(attemptCtx.Err(), context.DeadlineExceeded) {
+		return &requestTimeoutError{provider: provider, timeout: timeout}
+	}
+	return err
 }
 
-// convertEuropePMCArticle converts a EuropePMC article to our standard format.
-func (c *LiteratureClient) convertEuropePMCArticle(article interface{}) (*Article, error) {
-	europePMCArticle, ok := article.(*literature.EuropePMCArticle)
-	if !ok {
-		return nil, fmt.Errorf("invalid EuropePMC article type")
-	}
-
-	authors := c.convertAuthors(europePMCArticle.Authors)
-	meshHeadings := c.convertMeshHeadings(europePMCArticle.MeshHeadings)
-	chemicals := c.convertChemicals(europePMCArticle.Chemicals)
-	grants := c.convertGrants(europePMCArticle.Grants)
-	journal := c.convertJournal(europePMCArticle.Journal)
-
-	return &Article{
-		ID:           europePMCArticle.ID,
-		Source:       "europepmc",
-		PMID:         europePMCArticle.PMID,
-		PMCID:        europePMCArticle.PMCID,
-		DOI:          europePMCArticle.DOI,
-		Title:        europePMCArticle.Title,
-		AuthorString: europePMCArticle.AuthorString,
-		Authors:      authors,
-		Abstract:     europePMCArticle.Abstract,
-		Journal:      journal,
-		PubYear:      europePMCArticle.PubYear,
-		PageInfo:     europePMCArticle.PageInfo,
-		Keywords:     europePMCArticle.Keywords,
-		IsOpenAccess: europePMCArticle.IsOpenAccess,
-		HasPDF:       europePMCArticle.HasPDF,
-		License:      europePMCArticle.License,
-		CitedByCount: europePMCArticle.CitedByCount,
-		Language:     europePMCArticle.Language,
-		PubTypes:     europePMCArticle.PubTypes,
-		MeshHeadings: meshHeadings,
-		Chemicals:    chemicals,
-		Grants:       grants,
-		PublishDate:  europePMCArticle.PublishDate,
-		CreationDate: europePMCArticle.CreationDate,
-		RevisionDate: europePMCArticle.RevisionDate,
-	}, nil
+// runWithRetry runs call like runWithContext, retrying transient failures
+// such as rate limiting, server errors and timed out attempts under the
+// shared retry policy. Every attempt waits for the provider's rate limit.
+func (c *LiteratureClient) runWithRetry(ctx context.Context, provider string, call func(context.Context) error) error {
+	return retry.Do(ctx, c.logger.With("provider", provider), func() error {
+		return runWithContext(ctx, provider, c.requestTimeout, call)
+	})
 }
 
-// convertAuthors converts EuropePMC authors to standard format.
-func (c *LiteratureClient) convertAuthors(europePMCAuthors []literature.EuropePMCAuthor) []Author {
-	authors := make([]Author, len(europePMCAuthors))
-	for authorIndex, author := range europePMCAuthors {
-		affiliations := make([]Affiliation, len(author.Affiliations))
-		for affiliationIndex, affil := range author.Affiliations {
-			affiliations[affiliationIndex] = Affiliation{
-				Affiliation: affil.Affiliation,
-			}
-		}
+// requestTimeoutError reports an attempt of a provider call that did not
+// finish within the client's request timeout while the caller was still
+// waiting. It is a net.Error timeout, so the attempt is retried.
+type requestTimeoutError struct {
+	provider string
+	timeout  time.Duration
+}
 
-		authors[authorIndex] = Author{
-			FullName:     author.FullName,
-			FirstName:    author.FirstName,
-			LastName:     author.LastName,
-			Initials:     author.Initials,
-			ORCID:        author.ORCID,
-			Affiliations: affiliations,
-		}
+// Error implements the error interface.
+func (e *requestTimeoutError) Error() string {
+	return fmt.Sprintf("%s request timed out after %s", e.provider, e.timeout)
+}
+
+// Timeout implements net.Error.
+func (e *requestTimeoutError) Timeout() bool {
+	return true
+}
+
+// Temporary implements net.Error.
+func (e *requestTimeoutError) Temporary() bool {
+	return true
+}
+
+// boundClient returns a copy of client that sends every request with ctx,
+// for the literature library, which builds its requests without one.
+func boundClient(ctx context.Context, client *http.Client) *http.Client {
+	next := client.Transport
+	if next == nil {
+		next = http.DefaultTransport
 	}
-	return authors
+	bound := *client
+	bound.Transport = contextTransport{ctx: ctx, next: next}
+	return &bound
 }
 
-// convertMeshHeadings converts EuropePMC MeSH headings to standard format.
-func (c *LiteratureClient) convertMeshHeadings(europePMCMeshHeadings []literature.EuropePMCMeshHeading) []MeshHeading {
-	meshHeadings := make([]MeshHeading, len(europePMCMeshHeadings))
-	for meshIndex, mesh := range europePMCMeshHeadings {
-		qualifiers := make([]MeshQualifier, len(mesh.MeshQualifiers))
-		for qualifierIndex, qual := range mesh.MeshQualifiers {
-			qualifiers[qualifierIndex] = MeshQualifier{
-				QualifierName: qual.QualifierName,
-				MajorTopic:    qual.MajorTopic,
-			}
-		}
+// contextTransport replaces the context of every request with ctx.
+type contextTransport struct {
+	ctx  context.Context
+	next http.RoundTripper
+}
 
-		meshHeadings[meshIndex] = MeshHeading{
-			MajorTopic:     mesh.MajorTopic,
-			DescriptorName: mesh.DescriptorName,
-			MeshQualifiers: qualifiers,
-		}
+// RoundTrip implements http.RoundTripper.
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.next.RoundTrip(req.WithContext(t.ctx))
+}
+
+// unavailableError reports a provider that still failed with a transient
+// error after the last retry as a network error, or returns nil for any
+// other error.
+func unavailableError(service string, err error) *LiteratureError {
+	var retryErr *retry.Error
+	if !errors.As(err, &retryErr) {
+		return nil
+	}
+	details := map[string]string{"attempts": strconv.Itoa(retryErr.Attempts)}
+	if retryErr.RetryAfter > 0 {
+		details["retry_after"] = retryErr.RetryAfter.String()
+	}
+	return &LiteratureError{
+		Type:    ErrorTypeNetworkError,
+		Message: fmt.Sprintf("%s unavailable: %v", service, retryErr),
+		Code:    strings.ToUpper(service) + "_UNAVAILABLE",
+		Details: details,
+		Err:     retryErr,
 	}
-	return meshHeadings
 }
 
-// convertChemicals converts EuropePMC chemicals to standard format.
-func (c *LiteratureClient) convertChemicals(europePMCChemicals []literature.EuropePMCChemical) []Chemical {
-	chemicals := make([]Chemical, len(europePMCChemicals))
-	for chemicalIndex, chem := range europePMCChemicals {
-		chemicals[chemicalIndex] = Chemical{
-			Name:        chem.Name,
-			RegistryNum: chem.RegistryNumber,
+// isNotFoundError checks if an error indicates that an article was not found.
+func isNotFoundError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	errMsg := strings.ToLower(err.Error())
+	notFoundIndicators := []string{
+		"not found",
+		"404",
+		"no results",
+		"no articles found",
+		"article not found",
+	}
+
+	for _, indicator := range notFoundIndicators {
+		if strings.Contains(errMsg, indicator) {
+			return true
 		}
 	}
-	return chemicals
+
+	return false
 }
 
-// convertGrants converts EuropePMC grants to standard format.
-func (c *LiteratureClient) convertGrants(europePMCGrants []literature.EuropePMCGrant) []Grant {
-	grants := make([]Grant, len(europePMCGrants))
-	for grantIndex, grant := range europePMCGrants {
-		grants[grantIndex] = Grant{
-			GrantID: grant.GrantID,
-			Agency:  grant.Agency,
-			OrderIn: grant.OrderIn,
+// GetArticleWithFallback implements the recommended logic: EuropePMC first, then PubMed fallback
+// for PMIDs and PubMed records, Crossref fallback for DOIs and OpenAlex fallback for PMCIDs.
+// Preprint DOIs are looked up on their preprint server before all others.
+func (c *LiteratureClient) GetArticleWithFallback(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType == IDTypeDOI && IsPreprintDOI(identifier) {
+		article, err := c.GetArticleFromPreprintServer(ctx, identifier, idType)
+		if err == nil || ctx.Err() != nil {
+			return article, err
 		}
+		c.logger.Warn(
+			"preprint server lookup failed, trying EuropePMC",
+			"id", identifier,
+			"error", err,
+		)
 	}
-	return grants
-}
-
-// convertJournal converts EuropePMC journal to standard format.
-func (c *LiteratureClient) convertJournal(europePMCJournal literature.EuropePMCJournal) Journal {
-	return Journal{
-		Title:               europePMCJournal.Title,
-		MedlineAbbreviation: europePMCJournal.MedlineAbbreviation,
-		ISOAbbreviation:     europePMCJournal.ISOAbbreviation,
-		ISSN:                europePMCJournal.ISSN,
-		ESSN:                europePMCJournal.ESSN,
-		Volume:              europePMCJournal.Volume,
-		Issue:               europePMCJournal.Issue,
-		IssueID:             europePMCJournal.IssueID,
-		DateOfPublication:   europePMCJournal.DateOfPublication,
-		MonthOfPublication:  europePMCJournal.MonthOfPublication,
-		YearOfPublication:   europePMCJournal.YearOfPublication,
-		NLMID:               europePMCJournal.NLMID,
+
+	// Try EuropePMC first
+	article, err := c.GetArticleFromEuropePMC(ctx, identifier, idType)
+	if err == nil {
+		return article, nil
+	}
+	// A cancelled or timed out call leaves no time for a fallback.
+	if ctx.Err() != nil {
+		return nil, err
+	}
+
+	// PubMed doesn't handle DOIs directly; Crossref resolves the books,
+	// chapters and conference papers EuropePMC does not index, and OpenAlex
+	// the PMCIDs. Only Europe PMC IDs of PubMed records have a fallback.
+	fallbackName, fallback := "PubMed", c.GetArticleFromPubMed
+	fallbackID, fallbackIDType := identifier, idType
+	switch idType {
+	case IDTypeDOI:
+		fallbackName, fallback = "Crossref", c.GetArticleFromCrossref
+	case IDTypePMCID:
+		fallbackName, fallback = "OpenAlex", c.GetArticleFromOpenAlex
+	case IDTypeEuropePMCID:
+		source, externalID, _ := strings.Cut(identifier, ":")
+		if source != "MED" {
+			return nil, err
+		}
+		fallbackID, fallbackIDType = externalID, IDTypePMID
 	}
+	c.logger.Warn(
+		"EuropePMC lookup failed, trying "+fallbackName+" fallback",
+		"id_type", idType,
+		"id", identifier,
+		"error", err,
+	)
+	fallbackArticle, fallbackErr := fallback(ctx, fallbackID, fallbackIDType)
+	if fallbackErr == nil {
+		return fallbackArticle, nil
+	}
+	c.logger.Warn(
+		fallbackName+" fallback also failed",
+		"id", identifier,
+		"error", fallbackErr,
+	)
+
+	// Return the original EuropePMC error
+	return nil, err
 }
@@ -0,0 +1,275 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+const defaultCrossrefURL = "https://api.crossref.org"
+
+// errCrossrefNotFound is returned when Crossref has no record of a DOI.
+var errCrossrefNotFound = errors.New("DOI not found in Crossref")
+
+// jatsTagRegex matches the JATS markup Crossref abstracts are written in.
+var jatsTagRegex = regexp.MustCompile(`<[^>]+>`)
+
+// CrossrefClient looks up DOIs in the Crossref REST API. Crossref registers
+// the books, chapters and conference papers EuropePMC does not index.
+type CrossrefClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// newCrossrefClient creates a Crossref client from the literature client
+// configuration.
+func newCrossrefClient(cfg *Config) *CrossrefClient {
+	return &CrossrefClient{
+		httpClient: cfg.client(),
+		baseURL:    strings.TrimSuffix(cfg.crossrefURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// crossrefResponse is the envelope of a Crossref works lookup.
+type crossrefResponse struct {
+	Message crossrefWork `json:"message"`
+}
+
+// crossrefWork is the part of a Crossref work the client reads.
+type crossrefWork struct {
+	DOI                 string           `json:"DOI"`
+	Type                string           `json:"type"`
+	Title               []string         `json:"title"`
+	ContainerTitle      []string         `json:"container-title"`
+	ShortContainerTitle []string         `json:"short-container-title"`
+	Authors             []crossrefAuthor `json:"author"`
+	Abstract            string           `json:"abstract"`
+	Volume              string           `json:"volume"`
+	Issue               string           `json:"issue"`
+	Page                string           `json:"page"`
+	ISSN                []string         `json:"ISSN"`
+	Subjects            []string         `json:"subject"`
+	Language            string           `json:"language"`
+	Licenses            []struct {
+		URL string `json:"URL"`
+	} `json:"license"`
+	ReferencedByCount int `json:"is-referenced-by-count"`
+	Relation          struct {
+		// IsPreprintOf links a posted preprint to its journal article.
+		IsPreprintOf []crossrefRelation `json:"is-preprint-of"`
+	} `json:"relation"`
+	Issued struct {
+		// DateParts holds one [year, month, day] array; month and day
+		// may be missing.
+		DateParts [][]int `json:"date-parts"`
+	} `json:"issued"`
+}
+
+// crossrefAuthor is a contributor of a Crossref work. Organizations have a
+// name instead of given and family names.
+type crossrefAuthor struct {
+	Given       string                `json:"given"`
+	Family      string                `json:"family"`
+	Name        string                `json:"name"`
+	ORCID       string                `json:"ORCID"`
+	Affiliation []crossrefAffiliation `json:"affiliation"`
+}
+
+// crossrefAffiliation is an institution of a Crossref contributor.
+type crossrefAffiliation struct {
+	Name string `json:"name"`
+}
+
+// crossrefRelation is a work related to a Crossref work.
+type crossrefRelation struct {
+	IDType string `json:"id-type"`
+	ID     string `json:"id"`
+}
+
+// Work returns the Crossref record of a DOI.
+func (c *CrossrefClient) Work(ctx context.Context, doi string) (*Article, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+"/works/"+url.PathEscape(doi), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Crossref request: %w", err)
+	}
+	start := time.Now()
+	var response crossrefResponse
+	err = c.send(req, &response)
+	if !errors.Is(err, errCrossrefNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceCrossref, start, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("looked up DOI in Crossref", "doi", doi, "type", response.Message.Type)
+	return convertCrossrefWork(response.Message), nil
+}
+
+// send performs the HTTP round trip for Work.
+func (c *CrossrefClient) send(req *http.Request, target any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Crossref: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errCrossrefNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"Crossref returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error reading Crossref response: %w", err)
+	}
+	return nil
+}
+
+// convertCrossrefWork converts a Crossref work to our standard format. The
+// container title is the journal, book or proceedings the work is part of.
+func convertCrossrefWork(work crossrefWork) *Article {
+	authors := make([]Author, len(work.Authors))
+	for index, author := range work.Authors {
+		authors[index] = convertCrossrefAuthor(author)
+	}
+
+	journal := Journal{
+		Title:           firstOf(work.ContainerTitle),
+		ISOAbbreviation: firstOf(work.ShortContainerTitle),
+		ISSN:            firstOf(work.ISSN),
+		Volume:          work.Volume,
+		Issue:           work.Issue,
+	}
+	var pubYear string
+	var publishDate *time.Time
+	if len(work.Issued.DateParts) > 0 {
+		parts := work.Issued.DateParts[0]
+		if len(parts) > 0 && parts[0] > 0 {
+			journal.YearOfPublication = parts[0]
+			pubYear = fmt.Sprintf("%d", parts[0])
+		}
+		if len(parts) > 1 {
+			journal.MonthOfPublication = parts[1]
+		}
+		if len(parts) > 2 {
+			date := time.Date(parts[0], time.Month(parts[1]), parts[2], 0, 0, 0, 0, time.UTC)
+			publishDate = &date
+		}
+	}
+	var license string
+	if len(work.Licenses) > 0 {
+		license = work.Licenses[0].URL
+	}
+	var pubTypes []string
+	if work.Type != "" {
+		pubTypes = []string{work.Type}
+	}
+	// Preprints are registered as posted content.
+	status := PublicationStatusPublished
+	var publishedVersion *PublishedVersion
+	if work.Type == "posted-content" {
+		status = PublicationStatusPreprint
+		for _, relation := range work.Relation.IsPreprintOf {
+			if relation.IDType == "doi" {
+				publishedVersion = &PublishedVersion{DOI: relation.ID}
+				break
+			}
+		}
+	}
+
+	return &Article{
+		ID:                work.DOI,
+		Source:            "crossref",
+		DOI:               work.DOI,
+		Title:             firstOf(work.Title),
+		AuthorString:      authorString(authors),
+		Authors:           authors,
+		Abstract:          strings.Join(strings.Fields(jatsTagRegex.ReplaceAllString(work.Abstract, " ")), " "),
+		Journal:           journal,
+		PubYear:           pubYear,
+		PageInfo:          work.Page,
+		Keywords:          work.Subjects,
+		License:           license,
+		CitedByCount:      work.ReferencedByCount,
+		Language:          work.Language,
+		PubTypes:          pubTypes,
+		PublishDate:       publishDate,
+		PublicationStatus: status,
+		PublishedVersion:  publishedVersion,
+	}
+}
+
+// convertCrossrefAuthor converts a Crossref contributor to standard format.
+func convertCrossrefAuthor(author crossrefAuthor) Author {
+	affiliations := make([]Affiliation, len(author.Affiliation))
+	for index, affil := range author.Affiliation {
+		affiliations[index] = Affiliation{Affiliation: affil.Name}
+	}
+	var initials strings.Builder
+	for _, name := range strings.FieldsFunc(author.Given, func(r rune) bool { return r == ' ' || r == '-' || r == '.' }) {
+		initials.WriteString(strings.ToUpper(string([]rune(name)[:1])))
+	}
+	fullName := strings.TrimSpace(author.Given + " " + author.Family)
+	if fullName == "" {
+		fullName = author.Name
+	}
+	return Author{
+		FullName:     fullName,
+		FirstName:    author.Given,
+		LastName:     author.Family,
+		Initials:     initials.String(),
+		ORCID:        strings.TrimPrefix(strings.TrimPrefix(author.ORCID, "https://orcid.org/"), "http://orcid.org/"),
+		Affiliations: affiliations,
+	}
+}
+
+// convertDisplayName converts an author known only by a display name,
+// taking the last word as the family name.
+func convertDisplayName(displayName, orcid string) Author {
+	name := strings.Join(strings.Fields(displayName), " ")
+	given, family := "", name
+	if index := strings.LastIndex(name, " "); index > 0 {
+		given, family = name[:index], name[index+1:]
+	}
+	return convertCrossrefAuthor(crossrefAuthor{Given: given, Family: family, ORCID: orcid})
+}
+
+// authorString lists authors as "Doe JQ, Roe JL, dictyBase Consortium.",
+// the way EuropePMC does.
+func authorString(authors []Author) string {
+	if len(authors) == 0 {
+		return ""
+	}
+	shortNames := make([]string, len(authors))
+	for index, author := range authors {
+		shortNames[index] = strings.TrimSpace(author.LastName + " " + author.Initials)
+		if author.LastName == "" {
+			shortNames[index] = author.FullName
+		}
+	}
+	return strings.Join(shortNames, ", ") + "."
+}
+
+// firstOf returns the first of values, or "" when there is none.
+func firstOf(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
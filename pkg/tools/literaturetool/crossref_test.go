@@ -0,0 +1,148 @@
+package literaturetool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chapterJSON is a Crossref record of a book chapter.
+const chapterJSON = `{
+  "status": "ok",
+  "message": {
+    "DOI": "10.1007/978-1-62703-302-2_1",
+    "type": "book-chapter",
+    "title": ["Dictyostelium discoideum: A Model System for Cell Biology"],
+    "container-title": ["Methods in Molecular Biology", "Dictyostelium discoideum Protocols"],
+    "author": [
+      {"given": "Jane Q.", "family": "Doe", "ORCID": "http://orcid.org/0000-0002-1825-0097",
+       "affiliation": [{"name": "Northwestern University"}]},
+      {"given": "Jean-Luc", "family": "Roe"},
+      {"name": "dictyBase Consortium"}
+    ],
+    "abstract": "<jats:p>The social amoeba  <jats:italic>D. discoideum</jats:italic> is a model.</jats:p>",
+    "volume": "983",
+    "page": "1-20",
+    "ISSN": ["1064-3745", "1940-6029"],
+    "license": [{"URL": "http://www.springer.com/tdm"}],
+    "is-referenced-by-count": 12,
+    "language": "en",
+    "issued": {"date-parts": [[2013, 3, 14]]}
+  }
+}`
+
+func newCrossrefServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/works/10.1007/978-1-62703-302-2_1":
+			_, _ = w.Write([]byte(chapterJSON))
+		case "/works/10.1000/broken":
+			http.Error(w, "upstream down", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCrossrefClient_Work(t *testing.T) {
+	t.Parallel()
+	server := newCrossrefServer(t)
+	client, err := NewLiteratureClient(WithCrossrefURL(server.URL))
+	require.NoError(t, err)
+
+	article, err := client.GetArticleFromCrossref(context.Background(), "10.1007/978-1-62703-302-2_1", IDTypeDOI)
+	require.NoError(t, err)
+	publishDate := time.Date(2013, 3, 14, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &Article{
+		ID:           "10.1007/978-1-62703-302-2_1",
+		Source:       "crossref",
+		DOI:          "10.1007/978-1-62703-302-2_1",
+		Title:        "Dictyostelium discoideum: A Model System for Cell Biology",
+		AuthorString: "Doe JQ, Roe JL, dictyBase Consortium.",
+		Authors: []Author{
+			{
+				FullName:     "Jane Q. Doe",
+				FirstName:    "Jane Q.",
+				LastName:     "Doe",
+				Initials:     "JQ",
+				ORCID:        "0000-0002-1825-0097",
+				Affiliations: []Affiliation{{Affiliation: "Northwestern University"}},
+			},
+			{FullName: "Jean-Luc Roe", FirstName: "Jean-Luc", LastName: "Roe", Initials: "JL", Affiliations: []Affiliation{}},
+			{FullName: "dictyBase Consortium", Affiliations: []Affiliation{}},
+		},
+		Abstract: "The social amoeba D. discoideum is a model.",
+		Journal: Journal{
+			Title:              "Methods in Molecular Biology",
+			ISSN:               "1064-3745",
+			Volume:             "983",
+			MonthOfPublication: 3,
+			YearOfPublication:  2013,
+		},
+		PubYear:           "2013",
+		PageInfo:          "1-20",
+		License:           "http://www.springer.com/tdm",
+		CitedByCount:      12,
+		Language:          "en",
+		PubTypes:          []string{"book-chapter"},
+		PublishDate:       &publishDate,
+		PublicationStatus: PublicationStatusPublished,
+	}, article)
+}
+
+func TestCrossrefClient_Errors(t *testing.T) {
+	t.Parallel()
+	server := newCrossrefServer(t)
+	client, err := NewLiteratureClient(WithCrossrefURL(server.URL))
+	require.NoError(t, err)
+
+	tests := []struct {
+		doi       string
+		errorType ErrorType
+		code      string
+	}{
+		{doi: "10.1000/missing", errorType: ErrorTypeArticleNotFound, code: "CROSSREF_NOT_FOUND"},
+		{doi: "10.1000/broken", errorType: ErrorTypeAPIError, code: "CROSSREF_API_ERROR"},
+	}
+	for _, testCase := range tests {
+		_, err := client.GetArticleFromCrossref(context.Background(), testCase.doi, IDTypeDOI)
+		var litErr *LiteratureError
+		require.True(t, errors.As(err, &litErr), testCase.doi)
+		assert.Equal(t, testCase.errorType, litErr.Type, testCase.doi)
+		assert.Equal(t, testCase.code, litErr.Code, testCase.doi)
+	}
+
+	_, err = client.GetArticleFromCrossref(context.Background(), "12345678", IDTypePMID)
+	require.Error(t, err)
+}
+
+func TestConvertCrossrefWork_PartialDate(t *testing.T) {
+	t.Parallel()
+	work := crossrefWork{DOI: "10.1145/3292500.3330701", Type: "proceedings-article"}
+	work.Issued.DateParts = [][]int{{2019}}
+
+	article := convertCrossrefWork(work)
+	assert.Equal(t, "2019", article.PubYear)
+	assert.Equal(t, 2019, article.Journal.YearOfPublication)
+	assert.Nil(t, article.PublishDate, "a year alone is not a publish date")
+	assert.Empty(t, article.AuthorString)
+}
+
+func TestConvertCrossrefWork_PostedContent(t *testing.T) {
+	t.Parallel()
+	work := crossrefWork{DOI: "10.1101/2023.05.01.538912", Type: "posted-content"}
+	work.Relation.IsPreprintOf = []crossrefRelation{{IDType: "doi", ID: "10.1016/j.cell.2024.01.001"}}
+
+	article := convertCrossrefWork(work)
+	assert.Equal(t, PublicationStatusPreprint, article.PublicationStatus)
+	assert.Equal(t, &PublishedVersion{DOI: "10.1016/j.cell.2024.01.001"}, article.PublishedVersion)
+}
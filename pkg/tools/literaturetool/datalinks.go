@@ -0,0 +1,312 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+const (
+	defaultEuropePMCRESTURL = "https://www.ebi.ac.uk/europepmc/webservices/rest"
+	defaultAnnotationsURL   = "https://www.ebi.ac.uk/europepmc/annotations_api"
+	// europePMCArticlesURL is where Europe PMC serves the files of its
+	// open access articles.
+	europePMCArticlesURL = "https://europepmc.org/articles"
+	// maxDataCitations bounds the accessions read for one article.
+	maxDataCitations = 200
+	// maxCaption is the length in characters of a supplementary file
+	// caption.
+	maxCaption = 200
+)
+
+// accessionDatabases names the archives of the identifiers.org prefixes
+// Europe PMC tags accession numbers with. Other prefixes are upper-cased.
+var accessionDatabases = map[string]string{
+	"arrayexpress":   "ArrayExpress",
+	"bioproject":     "BioProject",
+	"biosample":      "BioSample",
+	"dbsnp":          "dbSNP",
+	"emdb":           "EMDB",
+	"ena.embl":       "ENA",
+	"ensembl":        "Ensembl",
+	"geo":            "GEO",
+	"insdc.sra":      "SRA",
+	"interpro":       "InterPro",
+	"pfam":           "Pfam",
+	"refseq":         "RefSeq",
+	"refsnp":         "dbSNP",
+	"sra":            "SRA",
+	"uniprot":        "UniProt",
+	"clinicaltrials": "ClinicalTrials.gov",
+}
+
+// DataLinksClient finds the supplementary files and dataset accessions of
+// articles in Europe PMC: the files are listed in the full text of open
+// access articles, and the accessions are text-mined annotations.
+type DataLinksClient struct {
+	httpClient     *http.Client
+	restURL        string
+	annotationsURL string
+	logger         *slog.Logger
+}
+
+// newDataLinksClient creates a data links client from the literature
+// client configuration.
+func newDataLinksClient(cfg *Config) *DataLinksClient {
+	restURL := cfg.europePMCURL
+	if restURL == "" {
+		restURL = defaultEuropePMCRESTURL
+	}
+	return &DataLinksClient{
+		httpClient:     cfg.client(),
+		restURL:        strings.TrimSuffix(restURL, "/"),
+		annotationsURL: strings.TrimSuffix(cfg.annotationsURL, "/"),
+		logger:         cfg.logger,
+	}
+}
+
+// jatsSupplement is a supplementary-material element of a JATS article.
+type jatsSupplement struct {
+	Href    string   `xml:"http://www.w3.org/1999/xlink href,attr"`
+	Label   jatsText `xml:"label"`
+	Title   jatsText `xml:"caption>title"`
+	Caption jatsText `xml:"caption>p"`
+	Media   []struct {
+		Href        string `xml:"http://www.w3.org/1999/xlink href,attr"`
+		MimeType    string `xml:"mimetype,attr"`
+		MimeSubtype string `xml:"mime-subtype,attr"`
+	} `xml:"media"`
+}
+
+// jatsText is the text of a JATS element without its inline markup, such
+// as italics, and with its white space collapsed.
+type jatsText string
+
+// UnmarshalXML implements xml.Unmarshaler.
+func (t *jatsText) UnmarshalXML(decoder *xml.Decoder, start xml.StartElement) error {
+	var text strings.Builder
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return err
+		}
+		switch token := token.(type) {
+		case xml.CharData:
+			text.Write(token)
+		case xml.EndElement:
+			if token.Name == start.Name {
+				*t = jatsText(strings.Join(strings.Fields(text.String()), " "))
+				return nil
+			}
+		}
+	}
+}
+
+// SupplementaryFiles returns the supplementary files of the open access
+// article with pmcid, followed by a zip archive of all of them. Articles
+// Europe PMC has no full text of have none.
+func (c *DataLinksClient) SupplementaryFiles(ctx context.Context, pmcid string) ([]SupplementaryFile, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.restURL+"/"+url.PathEscape(pmcid)+"/fullTextXML", nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Europe PMC full text request: %w", err)
+	}
+	start := time.Now()
+	var files []SupplementaryFile
+	err = c.send(req, func(body io.Reader) error {
+		parsed, parseErr := parseSupplements(body, pmcid)
+		files = parsed
+		return parseErr
+	})
+	metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, err)
+	if err != nil {
+		return nil, err
+	}
+	if len(files) > 0 {
+		files = append(files, SupplementaryFile{
+			Label:    "All supplementary files",
+			URL:      c.restURL + "/" + url.PathEscape(pmcid) + "/supplementaryFiles",
+			MimeType: "application/zip",
+		})
+	}
+	c.logger.Debug("listed supplementary files", "pmcid", pmcid, "files", len(files))
+	return files, nil
+}
+
+// parseSupplements reads the supplementary-material elements of a JATS
+// article. Files are linked on the element or on its media elements.
+func parseSupplements(body io.Reader, pmcid string) ([]SupplementaryFile, error) {
+	decoder := xml.NewDecoder(body)
+	// Europe PMC declares the JATS DTD entities, which the decoder does
+	// not read; they only occur in text.
+	decoder.Strict = false
+	decoder.Entity = xml.HTMLEntity
+	var files []SupplementaryFile
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("error reading Europe PMC full text: %w", err)
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok || start.Name.Local != "supplementary-material" {
+			continue
+		}
+		var supplement jatsSupplement
+		if err := decoder.DecodeElement(&supplement, &start); err != nil {
+			return nil, fmt.Errorf("error reading Europe PMC supplementary material: %w", err)
+		}
+		caption := string(supplement.Title)
+		if caption == "" {
+			caption = string(supplement.Caption)
+		}
+		file := SupplementaryFile{Label: string(supplement.Label), Caption: excerpt(caption, maxCaption)}
+		if supplement.Href != "" {
+			file.URL = supplementURL(pmcid, supplement.Href)
+			files = append(files, file)
+		}
+		for _, media := range supplement.Media {
+			if media.Href == "" {
+				continue
+			}
+			file.URL = supplementURL(pmcid, media.Href)
+			file.MimeType = ""
+			if media.MimeType != "" && media.MimeSubtype != "" {
+				file.MimeType = media.MimeType + "/" + media.MimeSubtype
+			}
+			files = append(files, file)
+		}
+	}
+}
+
+// supplementURL returns the Europe PMC address of a file of an article,
+// unless href is an address already.
+func supplementURL(pmcid, href string) string {
+	if strings.HasPrefix(href, "http://") || strings.HasPrefix(href, "https://") {
+		return href
+	}
+	return europePMCArticlesURL + "/" + url.PathEscape(pmcid) + "/bin/" + url.PathEscape(href)
+}
+
+// annotatedArticle is an article of the Europe PMC annotations API with
+// its annotations.
+type annotatedArticle struct {
+	Annotations []struct {
+		Exact string `json:"exact"`
+		Tags  []struct {
+			Name string `json:"name"`
+			URI  string `json:"uri"`
+		} `json:"tags"`
+	} `json:"annotations"`
+}
+
+// DataCitations returns the accession numbers Europe PMC text-mined from
+// an article, given as SOURCE:ID such as MED:23172289 or PMC:PMC3531190,
+// sorted by database and accession.
+func (c *DataLinksClient) DataCitations(ctx context.Context, articleID string) ([]DataCitation, error) {
+	query := url.Values{
+		"articleIds": {articleID},
+		"type":       {"Accession Numbers"},
+		"format":     {"JSON"},
+		"pageSize":   {fmt.Sprint(maxDataCitations)},
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		c.annotationsURL+"/annotationsByArticleIds?"+query.Encode(),
+		nil,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Europe PMC annotations request: %w", err)
+	}
+	start := time.Now()
+	var articles []annotatedArticle
+	err = c.send(req, func(body io.Reader) error {
+		return json.NewDecoder(body).Decode(&articles)
+	})
+	metrics.ObserveOutbound(metrics.ServiceEuropePMC, start, err)
+	if err != nil {
+		return nil, err
+	}
+	var citations []DataCitation
+	for _, article := range articles {
+		for _, annotation := range article.Annotations {
+			for _, tag := range annotation.Tags {
+				citation := newDataCitation(annotation.Exact, tag.Name, tag.URI)
+				if citation.Accession != "" && !slices.Contains(citations, citation) {
+					citations = append(citations, citation)
+				}
+			}
+		}
+	}
+	slices.SortFunc(citations, func(a, b DataCitation) int {
+		if byDatabase := strings.Compare(a.Database, b.Database); byDatabase != 0 {
+			return byDatabase
+		}
+		return strings.Compare(a.Accession, b.Accession)
+	})
+	c.logger.Debug("listed data citations", "article", articleID, "citations", len(citations))
+	return citations, nil
+}
+
+// newDataCitation reads the database of an accession from its
+// identifiers.org URI, written https://identifiers.org/geo/GSE1 or
+// https://identifiers.org/pdb:1ABC.
+func newDataCitation(exact, name, uri string) DataCitation {
+	accession := strings.TrimSpace(name)
+	if accession == "" {
+		accession = strings.TrimSpace(exact)
+	}
+	path := uri
+	for _, prefix := range []string{"http://identifiers.org/", "https://identifiers.org/"} {
+		path = strings.TrimPrefix(path, prefix)
+	}
+	prefix, _, found := strings.Cut(path, "/")
+	if !found {
+		prefix, _, _ = strings.Cut(path, ":")
+	}
+	prefix = strings.ToLower(prefix)
+	database, ok := accessionDatabases[prefix]
+	if !ok {
+		database = strings.ToUpper(prefix)
+	}
+	return DataCitation{
+		Database:  database,
+		Accession: accession,
+		URL:       strings.Replace(uri, "http://", "https://", 1),
+	}
+}
+
+// send performs the HTTP round trip of a data links request and passes the
+// body to read. A response with status 404 is read as nothing found.
+func (c *DataLinksClient) send(req *http.Request, read func(io.Reader) error) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Europe PMC: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return nil
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"Europe PMC returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	return read(resp.Body)
+}
@@ -0,0 +1,213 @@
+package literaturetool
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fullTextXML is a JATS article with a supplementary file linked on its
+// media element and one linked on the element itself.
+const fullTextXML = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE article PUBLIC "-//NLM//DTD JATS (Z39.96) Journal Archiving and Interchange DTD v1.2 20190208//EN" "JATS.dtd">
+<article xmlns:xlink="http://www.w3.org/1999/xlink">
+  <body><p>Cells move towards cAMP&nbsp;pulses.</p></body>
+  <back>
+    <sec sec-type="supplementary-material">
+      <supplementary-material id="sup1" content-type="local-data">
+        <label>Table S1</label>
+        <caption><title>Genes induced by <italic>cAMP</italic> pulses</title></caption>
+        <media xlink:href="gks1064_Table_S1.xlsx" mimetype="application" mime-subtype="vnd.ms-excel"/>
+      </supplementary-material>
+      <supplementary-material id="sup2" xlink:href="gks1064_Movie.mp4">
+        <caption><p>Aggregation   of
+          cells.</p></caption>
+      </supplementary-material>
+    </sec>
+  </back>
+</article>`
+
+// annotationsJSON is the Europe PMC annotations API response listing the
+// accession numbers of an article, one of them twice.
+const annotationsJSON = `[{
+  "source": "MED",
+  "extId": "23172289",
+  "annotations": [
+    {"exact": "GSE12345", "type": "Accession Numbers",
+     "tags": [{"name": "GSE12345", "uri": "http://identifiers.org/geo/GSE12345"}]},
+    {"exact": "1abc", "type": "Accession Numbers",
+     "tags": [{"name": "1ABC", "uri": "http://identifiers.org/pdb/1ABC"}]},
+    {"exact": "SRR000001", "type": "Accession Numbers",
+     "tags": [{"name": "SRR000001", "uri": "http://identifiers.org/insdc.sra/SRR000001"}]},
+    {"exact": "GSE12345", "type": "Accession Numbers",
+     "tags": [{"name": "GSE12345", "uri": "http://identifiers.org/geo/GSE12345"}]}
+  ]
+}]`
+
+// dataLinksServer serves the full text and the annotations of PMC3531190,
+// or PMID 23172289; other articles are not found.
+func dataLinksServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/PMC3531190/fullTextXML":
+			w.Header().Set("Content-Type", "application/xml")
+			_, _ = w.Write([]byte(fullTextXML))
+		case r.URL.Path == "/annotationsByArticleIds" && r.URL.Query().Get("articleIds") == "MED:23172289":
+			assert.Equal(t, "Accession Numbers", r.URL.Query().Get("type"))
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(annotationsJSON))
+		case r.URL.Path == "/annotationsByArticleIds":
+			_, _ = w.Write([]byte(`[]`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestAddDataLinks(t *testing.T) {
+	t.Parallel()
+	server := dataLinksServer(t)
+	client, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithAnnotationsURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+	require.NoError(t, err)
+
+	article := &Article{PMID: "23172289", PMCID: "PMC3531190"}
+	require.NoError(t, client.AddDataLinks(context.Background(), article))
+	assert.Equal(t, []SupplementaryFile{
+		{
+			Label:    "Table S1",
+			Caption:  "Genes induced by cAMP pulses",
+			URL:      "https://europepmc.org/articles/PMC3531190/bin/gks1064_Table_S1.xlsx",
+			MimeType: "application/vnd.ms-excel",
+		},
+		{
+			Caption: "Aggregation of cells.",
+			URL:     "https://europepmc.org/articles/PMC3531190/bin/gks1064_Movie.mp4",
+		},
+		{
+			Label:    "All supplementary files",
+			URL:      server.URL + "/PMC3531190/supplementaryFiles",
+			MimeType: "application/zip",
+		},
+	}, article.SupplementaryFiles)
+	assert.Equal(t, []DataCitation{
+		{Database: "GEO", Accession: "GSE12345", URL: "https://identifiers.org/geo/GSE12345"},
+		{Database: "PDB", Accession: "1ABC", URL: "https://identifiers.org/pdb/1ABC"},
+		{Database: "SRA", Accession: "SRR000001", URL: "https://identifiers.org/insdc.sra/SRR000001"},
+	}, article.DataCitations)
+
+	closed := &Article{PMID: "1", PMCID: "PMC1"}
+	require.NoError(t, client.AddDataLinks(context.Background(), closed),
+		"articles without full text have no supplementary files")
+	assert.Empty(t, closed.SupplementaryFiles)
+	assert.Empty(t, closed.DataCitations)
+
+	merged := &Article{PMID: "23172289", FieldSources: map[string]string{"title": "pubmed"}}
+	require.NoError(t, client.AddDataLinks(context.Background(), merged))
+	assert.Equal(t, "europepmc", merged.FieldSources["data_citations"])
+	assert.Equal(t, []string{"europepmc", "pubmed"}, merged.Providers())
+}
+
+func TestAddDataLinks_Failure(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithAnnotationsURL(server.URL),
+		WithHTTPClient(server.Client()),
+	)
+	require.NoError(t, err)
+
+	article := &Article{PMID: "23172289", PMCID: "PMC3531190"}
+	err = client.AddDataLinks(context.Background(), article)
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "error listing supplementary files")
+	assert.ErrorContains(t, err, "error listing data citations")
+	assert.ErrorContains(t, err, "status 503")
+}
+
+func TestConvertEuropePMCArticle_FullTextURLs(t *testing.T) {
+	t.Parallel()
+	server := recordedEuropePMC(t)
+	client, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithPubMedProvider(&fakeProvider{}),
+	)
+	require.NoError(t, err)
+
+	article, err := client.GetArticleFromEuropePMC(context.Background(), "40602797", IDTypePMID)
+	require.NoError(t, err)
+	assert.Equal(t, []FullTextURL{
+		{URL: "https://doi.org/10.1111/gtc.70037", Site: "DOI", Style: "doi", Availability: "Subscription required"},
+		{URL: "https://europepmc.org/articles/PMC12221695", Site: "Europe PMC", Style: "html", Availability: "Open access"},
+		{
+			URL:          "https://europepmc.org/articles/PMC12221695?pdf=render",
+			Site:         "Europe PMC",
+			Style:        "pdf",
+			Availability: "Open access",
+		},
+	}, article.FullTextURLs)
+}
+
+func TestHandler_DataLinks(t *testing.T) {
+	t.Parallel()
+	server := dataLinksServer(t)
+	europePMC := &fakeProvider{articles: map[string]*Article{"23172289": {
+		Source:       "europepmc",
+		PMID:         "23172289",
+		PMCID:        "PMC3531190",
+		Title:        "dictyBase 2013",
+		FullTextURLs: []FullTextURL{{URL: "https://europepmc.org/articles/PMC3531190", Site: "Europe PMC", Style: "html"}},
+	}}}
+	tool, err := NewLiteratureTool(slog.New(slog.NewTextHandler(io.Discard, nil)), WithClientOptions(
+		WithEuropePMCProvider(europePMC),
+		WithPubMedProvider(&fakeProvider{}),
+		WithEuropePMCURL(server.URL),
+		WithAnnotationsURL(server.URL),
+		WithHTTPClient(server.Client()),
+	))
+	require.NoError(t, err)
+
+	call := func(arguments map[string]any) string {
+		result, err := tool.Handler(context.Background(), mcp.CallToolRequest{
+			Params: mcp.CallToolParams{Name: "literature-fetch", Arguments: arguments},
+		})
+		require.NoError(t, err)
+		require.False(t, result.IsError)
+		return result.Content[0].(mcp.TextContent).Text
+	}
+	text := call(map[string]any{"id": "23172289", "id_type": IDTypePMID})
+	assert.NotContains(t, text, "Data Citations", "data links are fetched on request")
+
+	text = call(map[string]any{"id": "23172289", "id_type": IDTypePMID, "data_links": true})
+	assert.Contains(t, text, "**Full Text:** [Europe PMC (html)](https://europepmc.org/articles/PMC3531190)")
+	assert.Contains(t, text,
+		"- [Table S1](https://europepmc.org/articles/PMC3531190/bin/gks1064_Table_S1.xlsx): Genes induced by cAMP pulses")
+	assert.Contains(t, text, "- GEO [GSE12345](https://identifiers.org/geo/GSE12345)")
+
+	summary := call(map[string]any{
+		"id":            "23172289",
+		"id_type":       IDTypePMID,
+		"data_links":    true,
+		"output_format": FormatSummary,
+	})
+	assert.Contains(t, summary, "Datasets: GEO GSE12345, PDB 1ABC, SRA SRR000001.")
+
+}
@@ -0,0 +1,250 @@
+package literaturetool
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/literature"
+)
+
+// europePMCProvider looks up EuropePMC records with the dictyBase
+// literature library.
+type europePMCProvider struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// newEuropePMCProvider creates a EuropePMC provider from the literature
+// client configuration.
+func newEuropePMCProvider(cfg *Config) *europePMCProvider {
+	return &europePMCProvider{httpClient: cfg.providerClient(), baseURL: cfg.europePMCURL}
+}
+
+// client returns a library client whose requests are bound to ctx, as the
+// library builds them without a context.
+func (p *europePMCProvider) client(ctx context.Context) (*literature.EuropePMCClient, error) {
+	return literature.NewEuropePMCClient(
+		literature.WithEuropePMCBaseURL(p.baseURL),
+		literature.WithEuropePMCHTTPClient(boundClient(ctx, p.httpClient)),
+	)
+}
+
+// GetArticle implements Provider.
+func (p *europePMCProvider) GetArticle(ctx context.Context, pmid string) (*Article, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	article, err := client.GetArticle(pmid)
+	if err != nil {
+		return nil, err
+	}
+	return convertEuropePMCArticle(article), nil
+}
+
+// Search implements Provider.
+func (p *europePMCProvider) Search(ctx context.Context, query string, limit int) ([]*Article, error) {
+	client, err := p.client(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Search(query, literature.WithEuropePMCLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]*Article, len(result.Articles))
+	for index, article := range result.Articles {
+		articles[index] = convertEuropePMCArticle(article)
+	}
+	return articles, nil
+}
+
+// fetch performs the HTTP round trip of FetchEuropePMC. The library has
+// no generic request, so the path is fetched from the REST API directly.
+func (p *europePMCProvider) fetch(ctx context.Context, path string, out any) error {
+	baseURL := strings.TrimSuffix(cmp.Or(p.baseURL, defaultEuropePMCRESTURL), "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating EuropePMC request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling EuropePMC: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		statusErr := &retry.StatusError{
+			StatusCode: resp.StatusCode,
+			RetryAfter: retry.ParseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		return fmt.Errorf("EuropePMC returned %w: %s", statusErr, strings.TrimSpace(string(detail)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding EuropePMC response: %w", err)
+	}
+	return nil
+}
+
+// convertEuropePMCArticle converts a EuropePMC article to our standard format.
+func convertEuropePMCArticle(europePMCArticle *literature.EuropePMCArticle) *Article {
+	authors := convertAuthors(europePMCArticle.Authors)
+	meshHeadings := convertMeshHeadings(europePMCArticle.MeshHeadings)
+	chemicals := convertChemicals(europePMCArticle.Chemicals)
+	grants := convertGrants(europePMCArticle.Grants)
+	journal := convertJournal(europePMCArticle.Journal)
+
+	return &Article{
+		ID:                europePMCArticle.ID,
+		Source:            "europepmc",
+		PMID:              europePMCArticle.PMID,
+		PMCID:             europePMCArticle.PMCID,
+		DOI:               europePMCArticle.DOI,
+		Title:             europePMCArticle.Title,
+		AuthorString:      europePMCArticle.AuthorString,
+		Authors:           authors,
+		Abstract:          europePMCArticle.Abstract,
+		Journal:           journal,
+		PubYear:           europePMCArticle.PubYear,
+		PageInfo:          europePMCArticle.PageInfo,
+		Keywords:          europePMCArticle.Keywords,
+		IsOpenAccess:      europePMCArticle.IsOpenAccess,
+		HasPDF:            europePMCArticle.HasPDF,
+		License:           europePMCArticle.License,
+		CitedByCount:      europePMCArticle.CitedByCount,
+		Language:          europePMCArticle.Language,
+		PubTypes:          europePMCArticle.PubTypes,
+		MeshHeadings:      meshHeadings,
+		Chemicals:         chemicals,
+		Grants:            grants,
+		PublishDate:       europePMCArticle.PublishDate,
+		CreationDate:      europePMCArticle.CreationDate,
+		RevisionDate:      europePMCArticle.RevisionDate,
+		PublicationStatus: europePMCStatus(europePMCArticle.PubTypes),
+		FullTextURLs:      convertFullTextURLs(europePMCArticle.FullTextURLs),
+	}
+}
+
+// convertFullTextURLs converts the EuropePMC full text links, whose site
+// names use underscores for spaces.
+func convertFullTextURLs(europePMCURLs []literature.EuropePMCFullTextURL) []FullTextURL {
+	var urls []FullTextURL
+	for _, europePMCURL := range europePMCURLs {
+		if europePMCURL.URL == "" {
+			continue
+		}
+		urls = append(urls, FullTextURL{
+			URL:          europePMCURL.URL,
+			Site:         strings.ReplaceAll(europePMCURL.Site, "_", " "),
+			Style:        europePMCURL.DocumentStyle,
+			Availability: europePMCURL.Availability,
+		})
+	}
+	return urls
+}
+
+// europePMCStatus returns the publication status of a EuropePMC article,
+// which indexes preprints with the "preprint" publication type.
+func europePMCStatus(pubTypes []string) string {
+	for _, pubType := range pubTypes {
+		if strings.EqualFold(pubType, PublicationStatusPreprint) {
+			return PublicationStatusPreprint
+		}
+	}
+	return PublicationStatusPublished
+}
+
+// convertAuthors converts EuropePMC authors to standard format.
+func convertAuthors(europePMCAuthors []literature.EuropePMCAuthor) []Author {
+	authors := make([]Author, len(europePMCAuthors))
+	for authorIndex, author := range europePMCAuthors {
+		affiliations := make([]Affiliation, len(author.Affiliations))
+		for affiliationIndex, affil := range author.Affiliations {
+			affiliations[affiliationIndex] = Affiliation{
+				Affiliation: affil.Affiliation,
+			}
+		}
+
+		authors[authorIndex] = Author{
+			FullName:     author.FullName,
+			FirstName:    author.FirstName,
+			LastName:     author.LastName,
+			Initials:     author.Initials,
+			ORCID:        author.ORCID,
+			Affiliations: affiliations,
+		}
+	}
+	return authors
+}
+
+// convertMeshHeadings converts EuropePMC MeSH headings to standard format.
+func convertMeshHeadings(europePMCMeshHeadings []literature.EuropePMCMeshHeading) []MeshHeading {
+	meshHeadings := make([]MeshHeading, len(europePMCMeshHeadings))
+	for meshIndex, mesh := range europePMCMeshHeadings {
+		qualifiers := make([]MeshQualifier, len(mesh.MeshQualifiers))
+		for qualifierIndex, qual := range mesh.MeshQualifiers {
+			qualifiers[qualifierIndex] = MeshQualifier{
+				QualifierName: qual.QualifierName,
+				MajorTopic:    qual.MajorTopic,
+			}
+		}
+
+		meshHeadings[meshIndex] = MeshHeading{
+			MajorTopic:     mesh.MajorTopic,
+			DescriptorName: mesh.DescriptorName,
+			MeshQualifiers: qualifiers,
+		}
+	}
+	return meshHeadings
+}
+
+// convertChemicals converts EuropePMC chemicals to standard format.
+func convertChemicals(europePMCChemicals []literature.EuropePMCChemical) []Chemical {
+	chemicals := make([]Chemical, len(europePMCChemicals))
+	for chemicalIndex, chem := range europePMCChemicals {
+		chemicals[chemicalIndex] = Chemical{
+			Name:        chem.Name,
+			RegistryNum: chem.RegistryNumber,
+		}
+	}
+	return chemicals
+}
+
+// convertGrants converts EuropePMC grants to standard format.
+func convertGrants(europePMCGrants []literature.EuropePMCGrant) []Grant {
+	grants := make([]Grant, len(europePMCGrants))
+	for grantIndex, grant := range europePMCGrants {
+		grants[grantIndex] = Grant{
+			GrantID: grant.GrantID,
+			Agency:  grant.Agency,
+			OrderIn: grant.OrderIn,
+		}
+	}
+	return grants
+}
+
+// convertJournal converts EuropePMC journal to standard format.
+func convertJournal(europePMCJournal literature.EuropePMCJournal) Journal {
+	return Journal{
+		Title:               europePMCJournal.Title,
+		MedlineAbbreviation: europePMCJournal.MedlineAbbreviation,
+		ISOAbbreviation:     europePMCJournal.ISOAbbreviation,
+		ISSN:                europePMCJournal.ISSN,
+		ESSN:                europePMCJournal.ESSN,
+		Volume:              europePMCJournal.Volume,
+		Issue:               europePMCJournal.Issue,
+		IssueID:             europePMCJournal.IssueID,
+		DateOfPublication:   europePMCJournal.DateOfPublication,
+		MonthOfPublication:  europePMCJournal.MonthOfPublication,
+		YearOfPublication:   europePMCJournal.YearOfPublication,
+		NLMID:               europePMCJournal.NLMID,
+	}
+}
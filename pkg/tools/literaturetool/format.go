@@ -0,0 +1,153 @@
+package literaturetool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// maxMarkdownAuthors is the number of authors the markdown output names.
+	maxMarkdownAuthors = 10
+	// maxSummaryAuthors is the number of authors the summary names.
+	maxSummaryAuthors = 3
+	// maxSummaryText is the length in characters of the abstract excerpt of
+	// the summary.
+	maxSummaryText = 300
+	// maxSummaryDatasets is the number of dataset accessions the summary
+	// names.
+	maxSummaryDatasets = 5
+)
+
+// Formatter renders a fetched article as the text of the tool result.
+type Formatter func(article *Article) (string, error)
+
+// formatter returns the formatter of an output format.
+func (l *LiteratureTool) formatter(format string) (Formatter, error) {
+	switch format {
+	case FormatMarkdown:
+		return l.formatArticleResult, nil
+	case FormatJSON:
+		return formatJSON, nil
+	case FormatSummary:
+		return formatSummary, nil
+	case FormatBibTeX, FormatRIS, FormatEndNote, FormatCSLJSON:
+		return func(article *Article) (string, error) {
+			return RenderCitation(article, format)
+		}, nil
+	default:
+		return nil, fmt.Errorf("unsupported output format: %s", format)
+	}
+}
+
+// formatJSON renders every field of the article as indented JSON.
+func formatJSON(article *Article) (string, error) {
+	data, err := json.MarshalIndent(article, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal article data: %w", err)
+	}
+	return string(data), nil
+}
+
+// formatSummary renders a few plain lines for a model to read: the title,
+// the first authors, the journal and year, the identifiers, the status of
+// a preprint, the citation count, the first datasets cited and the TLDR or
+// the start of the abstract.
+func formatSummary(article *Article) (string, error) {
+	var lines []string
+	if article.Title != "" {
+		lines = append(lines, article.Title)
+	}
+	var source []string
+	if authors := summaryAuthors(article.Authors); authors != "" {
+		source = append(source, authors)
+	}
+	if venue := strings.TrimSpace(strings.Join([]string{article.Journal.Title, article.PubYear}, " ")); venue != "" {
+		source = append(source, venue)
+	}
+	if len(source) > 0 {
+		for index, part := range source {
+			if !strings.HasSuffix(part, ".") {
+				source[index] = part + "."
+			}
+		}
+		lines = append(lines, strings.Join(source, " "))
+	}
+	var ids []string
+	for _, id := range [][2]string{{"PMID", article.PMID}, {"PMCID", article.PMCID}, {"DOI", article.DOI}} {
+		if id[1] != "" {
+			ids = append(ids, id[0]+" "+id[1])
+		}
+	}
+	if len(ids) > 0 {
+		lines = append(lines, strings.Join(ids, " | "))
+	}
+	if article.PublicationStatus == PublicationStatusPreprint {
+		status := "Preprint"
+		if published := article.PublishedVersion; published != nil && published.DOI != "" {
+			status += ", published as DOI " + published.DOI
+		}
+		lines = append(lines, status+".")
+	}
+	if article.CitedByCount > 0 {
+		lines = append(lines, fmt.Sprintf("Cited %d times.", article.CitedByCount))
+	}
+	if datasets := summaryDatasets(article.DataCitations); datasets != "" {
+		lines = append(lines, "Datasets: "+datasets+".")
+	}
+	switch {
+	case article.TLDR != "":
+		lines = append(lines, "Summary: "+article.TLDR)
+	case article.Abstract != "":
+		lines = append(lines, "Abstract: "+excerpt(article.Abstract, maxSummaryText))
+	}
+	if len(lines) == 0 {
+		return "No article found", nil
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// summaryAuthors names the first authors, adding "et al." when there are
+// more.
+func summaryAuthors(authors []Author) string {
+	names := make([]string, 0, min(len(authors), maxSummaryAuthors))
+	for _, author := range authors[:cap(names)] {
+		names = append(names, author.FullName)
+	}
+	if len(authors) > maxSummaryAuthors {
+		names = append(names, "et al.")
+	}
+	return strings.Join(names, ", ")
+}
+
+// summaryDatasets names the first dataset accessions with their database,
+// adding how many more there are.
+func summaryDatasets(citations []DataCitation) string {
+	names := make([]string, 0, min(len(citations), maxSummaryDatasets))
+	for _, citation := range citations[:cap(names)] {
+		names = append(names, citation.Database+" "+citation.Accession)
+	}
+	if more := len(citations) - maxSummaryDatasets; more > 0 {
+		names = append(names, fmt.Sprintf("and %d more", more))
+	}
+	return strings.Join(names, ", ")
+}
+
+// excerpt shortens text to at most limit characters, ending after the last
+// whole sentence that fits, or else after the last whole word followed by
+// an ellipsis.
+func excerpt(text string, limit int) string {
+	text = strings.Join(strings.Fields(text), " ")
+	if utf8.RuneCountInString(text) <= limit {
+		return text
+	}
+	cut := string([]rune(text)[:limit])
+	if end := strings.LastIndex(cut, ". "); end > 0 {
+		return cut[:end+1]
+	}
+	if space := strings.LastIndex(cut, " "); space > 0 {
+		cut = cut[:space]
+	}
+	return cut + "…"
+}
@@ -0,0 +1,108 @@
+package literaturetool
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// formatArticle is an article with more authors than any output names.
+var formatArticle = func() *Article {
+	article := &Article{
+		Source:            "europepmc",
+		PMID:              "23172289",
+		PMCID:             "PMC3531190",
+		DOI:               "10.1093/nar/gks1064",
+		Title:             "DictyBase 2013: integrating multiple Dictyostelid species",
+		Journal:           Journal{Title: "Nucleic Acids Res"},
+		PubYear:           "2013",
+		CitedByCount:      120,
+		PublicationStatus: PublicationStatusPublished,
+		Abstract: "dictyBase is the model organism database for Dictyostelium discoideum. " +
+			strings.Repeat("It integrates the genomes of several Dictyostelid species. ", 10),
+	}
+	for i := 1; i <= 12; i++ {
+		article.Authors = append(article.Authors, Author{FullName: fmt.Sprintf("Author %d", i)})
+	}
+	return article
+}()
+
+func TestFormatter(t *testing.T) {
+	t.Parallel()
+	tool, err := NewLiteratureTool(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	for _, format := range []string{
+		FormatMarkdown, FormatJSON, FormatSummary, FormatBibTeX, FormatRIS, FormatEndNote, FormatCSLJSON,
+	} {
+		formatter, err := tool.formatter(format)
+		require.NoError(t, err, format)
+		text, err := formatter(formatArticle)
+		require.NoError(t, err, format)
+		assert.NotEmpty(t, text, format)
+	}
+	_, err = tool.formatter("docx")
+	require.Error(t, err)
+}
+
+func TestFormatMarkdown_Compact(t *testing.T) {
+	t.Parallel()
+	tool, err := NewLiteratureTool(slog.New(slog.NewTextHandler(io.Discard, nil)))
+	require.NoError(t, err)
+	text, err := tool.formatArticleResult(formatArticle)
+	require.NoError(t, err)
+	assert.Contains(t, text, "Author 10 and 2 more\n")
+	assert.NotContains(t, text, "Author 11")
+	assert.NotContains(t, text, "```json")
+}
+
+func TestFormatJSON(t *testing.T) {
+	t.Parallel()
+	text, err := formatJSON(formatArticle)
+	require.NoError(t, err)
+	var decoded Article
+	require.NoError(t, json.Unmarshal([]byte(text), &decoded))
+	assert.Len(t, decoded.Authors, 12, "every field is kept")
+	assert.Equal(t, formatArticle.Abstract, decoded.Abstract)
+}
+
+func TestFormatSummary(t *testing.T) {
+	t.Parallel()
+	text, err := formatSummary(formatArticle)
+	require.NoError(t, err)
+	lines := strings.Split(text, "\n")
+	require.Len(t, lines, 5)
+	assert.Equal(t, formatArticle.Title, lines[0])
+	assert.Equal(t, "Author 1, Author 2, Author 3, et al. Nucleic Acids Res 2013.", lines[1])
+	assert.Equal(t, "PMID 23172289 | PMCID PMC3531190 | DOI 10.1093/nar/gks1064", lines[2])
+	assert.Equal(t, "Cited 120 times.", lines[3])
+	assert.True(t, strings.HasPrefix(lines[4], "Abstract: dictyBase is the model organism database"))
+	assert.True(t, strings.HasSuffix(lines[4], "species."), "the abstract ends after a whole sentence")
+	assert.LessOrEqual(t, len([]rune(lines[4])), len("Abstract: ")+maxSummaryText)
+
+	preprint := &Article{
+		Title:             "Chemotaxis in Dictyostelium",
+		PublicationStatus: PublicationStatusPreprint,
+		PublishedVersion:  &PublishedVersion{DOI: "10.1016/j.cell.2024.01.001"},
+		TLDR:              "Cells follow cAMP waves.",
+	}
+	text, err = formatSummary(preprint)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"Chemotaxis in Dictyostelium\nPreprint, published as DOI 10.1016/j.cell.2024.01.001.\nSummary: Cells follow cAMP waves.",
+		text,
+	)
+}
+
+func TestExcerpt(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "Short text.", excerpt("Short   text.", 20))
+	assert.Equal(t, "One sentence.", excerpt("One sentence. Another sentence here.", 20))
+	assert.Equal(t, "A long run of…", excerpt("A long run of words without end", 16))
+}
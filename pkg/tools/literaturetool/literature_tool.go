@@ -2,12 +2,20 @@ package literaturetool
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"log"
+	"log/slog"
+	"os"
+	"path"
 	"regexp"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
 	"github.com/go-playground/validator/v10"
 	"github.com/mark3labs/mcp-go/mcp"
 )
@@ -25,88 +33,216 @@ var doiRegex = regexp.MustCompile(
 // PMID regex pattern to validate and extract PMID (positive integers only).
 var pmidRegex = regexp.MustCompile(`^\d+$`)
 
-// LiteratureTool is a tool that fetches literature information using PubMed or DOI IDs.
+// PMCID regex pattern to match and extract the digits of a PubMed Central ID.
+// Handles optional prefixes: PMC, pmcid: and PMC article URLs.
+var pmcidRegex = regexp.MustCompile(
+	`(?i)^\s*(?:https?://(?:www\.ncbi\.nlm\.nih\.gov/pmc|pmc\.ncbi\.nlm\.nih\.gov)/articles/|pmcid:\s*)?` +
+		`(?:pmc)?(\d+)/?\s*$`,
+)
+
+// Europe PMC ID regex pattern to match a record written SOURCE:ID, SOURCE/ID
+// or as a europepmc.org article URL. Captures the optional source and the ID.
+var europePMCIDRegex = regexp.MustCompile(
+	`(?i)^\s*(?:https?://(?:www\.)?europepmc\.org/(?:article|abstract)/)?(?:([a-z]{3})[:/])?\s*([a-z]*\d+)/?\s*$`,
+)
+
+// europePMCSources are the record sources of Europe PMC, such as MED for
+// PubMed, PMC for PubMed Central and PPR for preprints.
+var europePMCSources = []string{"AGR", "CBA", "CIT", "CTX", "ETH", "HIR", "MED", "NBK", "PAT", "PMC", "PPR"}
+
+// LiteratureTool is a tool that fetches literature information using PubMed, PMC, Europe PMC or DOI IDs.
+// The underlying HTTP clients are created lazily on the first fetch.
 type LiteratureTool struct {
 	Name        string
 	Description string
 	Tool        mcp.Tool
+	Logger      *slog.Logger
+	clientOnce  sync.Once
 	client      *LiteratureClient
-	Logger      *log.Logger
+	clientErr   error
+	// clientOptions configure the literature client.
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring LiteratureTool.
+type ToolOption func(*LiteratureTool)
+
+// WithClientOptions sets options for the literature client, such as its
+// providers or HTTP client.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(l *LiteratureTool) {
+		l.clientOptions = append(l.clientOptions, opts...)
+	}
 }
 
 // LiteratureRequest represents the parameters for the literature fetch request.
 type LiteratureRequest struct {
-	ID       string `validate:"required"                         json:"id"`
-	IDType   string `validate:"required,oneof=pmid doi"          json:"id_type"`
-	Provider string `validate:"omitempty,oneof=pubmed europepmc" json:"provider"`
+	ID           string `validate:"required"                                                  json:"id"`
+	IDType       string `validate:"required,oneof=pmid doi pmcid europepmc_id"                json:"id_type"`
+	Provider     string `validate:"omitempty,oneof=pubmed europepmc openalex semanticscholar merged" json:"provider"`
+	OutputFormat string `validate:"omitempty,oneof=markdown json summary bibtex ris endnote csljson" json:"output_format"`
+	DataLinks    bool   `json:"data_links"`
 }
 
 // fetchArticle retrieves article information using the recommended strategy:
-// - For DOI: Try EuropePMC
-// - For PMID: Try EuropePMC first, fallback to NCBI/PubMed.
+// - For preprint DOI: Try bioRxiv, medRxiv or arXiv first, then as any DOI
+// - For DOI: Try EuropePMC first, fallback to Crossref
+// - For PMID: Try EuropePMC first, fallback to NCBI/PubMed
+// - For PMCID: Try EuropePMC first, fallback to OpenAlex
+// - For Europe PMC ID: EuropePMC only, with PubMed fallback for MED records
+// - With the openalex or semanticscholar provider: that provider only, as no
+// other provider has its citation metrics. Neither knows Europe PMC IDs.
+// - With the merged provider: EuropePMC and PubMed, merged field by field.
 func (l *LiteratureTool) fetchArticle(
 	ctx context.Context,
+	logger *slog.Logger,
 	params LiteratureRequest,
 ) (*Article, error) {
-	if params.IDType == IDTypeDOI {
-		// For DOI, only use EuropePMC as it has better DOI support
-		l.Logger.Printf(
-			"Fetching article for DOI %s using EuropePMC",
-			params.ID,
-		)
-		return l.client.GetArticleFromEuropePMC(ctx, params.ID, params.IDType)
+	client, err := l.literatureClient()
+	if err != nil {
+		return nil, err
+	}
+	switch params.Provider {
+	case ProviderOpenAlex:
+		logger.Info("fetching article using OpenAlex", "id_type", params.IDType, "id", params.ID)
+		return client.GetArticleFromOpenAlex(ctx, params.ID, params.IDType)
+	case ProviderSemanticScholar:
+		logger.Info("fetching article using Semantic Scholar", "id_type", params.IDType, "id", params.ID)
+		return client.GetArticleFromSemanticScholar(ctx, params.ID, params.IDType)
+	case ProviderMerged:
+		logger.Info("fetching article from EuropePMC and PubMed to merge", "id_type", params.IDType, "id", params.ID)
+		return client.GetMergedArticle(ctx, params.ID, params.IDType)
+	}
+	fallback := " with PubMed fallback"
+	switch {
+	case params.IDType == IDTypeDOI:
+		// Crossref also registers books, chapters and conference papers
+		fallback = " with Crossref fallback"
+	case params.IDType == IDTypePMCID:
+		fallback = " with OpenAlex fallback"
+	case params.IDType == IDTypeEuropePMCID && !strings.HasPrefix(params.ID, "MED:"):
+		fallback = ""
+	}
+	first := "EuropePMC"
+	if params.IDType == IDTypeDOI && IsPreprintDOI(params.ID) {
+		first = "the preprint server, then EuropePMC,"
 	}
+	logger.Info(
+		"fetching article using "+first+fallback,
+		"id_type", params.IDType,
+		"id", params.ID,
+	)
+	return client.GetArticleWithFallback(ctx, params.ID, params.IDType)
+}
 
-	// For PMID, use EuropePMC first with PubMed fallback
-	l.Logger.Printf(
-		"Fetching article for PMID %s using EuropePMC with PubMed fallback",
-		params.ID,
+// literatureClient returns the literature client, creating it on first use.
+func (l *LiteratureTool) literatureClient() (*LiteratureClient, error) {
+	l.clientOnce.Do(func() {
+		l.client, l.clientErr = NewLiteratureClient(append([]Option{
+			WithLogger(l.Logger),
+			WithSemanticScholarAPIKey(os.Getenv("SEMANTIC_SCHOLAR_API_KEY")),
+		}, l.clientOptions...)...)
+		if l.clientErr != nil {
+			l.clientErr = fmt.Errorf(
+				"failed to create literature client: %w",
+				l.clientErr,
+			)
+		}
+	})
+	return l.client, l.clientErr
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"literature-fetch",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			clientOpts, err := CassetteOptions(deps.Literature)
+			if err != nil {
+				return nil, err
+			}
+			literatureTool, err := NewLiteratureTool(deps.Logger, WithClientOptions(clientOpts...))
+			if err != nil {
+				return nil, err
+			}
+			return literatureTool, nil
+		},
 	)
-	return l.client.GetArticleWithFallback(ctx, params.ID, params.IDType)
 }
 
 // NewLiteratureTool creates a new LiteratureTool instance.
-func NewLiteratureTool(logger *log.Logger) (*LiteratureTool, error) {
+func NewLiteratureTool(logger *slog.Logger, opts ...ToolOption) (*LiteratureTool, error) {
 	// Create the tool with proper schema
 	tool := mcp.NewTool(
 		"literature-fetch",
 		mcp.WithDescription(
-			"Fetches scientific literature information using PubMed or DOI IDs via the dictyBase literature API",
+			"Fetches scientific literature information using PubMed, PubMed Central, Europe PMC or DOI IDs "+
+				"via the dictyBase literature API",
 		),
+		mcp.WithTitleAnnotation("Literature Fetch"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
 		mcp.WithString(
 			"id",
-			mcp.Description("The PubMed ID (PMID) or DOI identifier"),
+			mcp.Description(
+				"The PubMed ID (PMID), DOI, PubMed Central ID (PMCID) or Europe PMC ID; DOIs include bioRxiv, "+
+					"medRxiv and arXiv preprint DOIs",
+			),
 			mcp.Required(),
 		),
 		mcp.WithString(
 			"id_type",
 			mcp.Description(
-				"Type of identifier: 'pmid' for PubMed IDs or 'doi' for DOI",
+				"Type of identifier: 'pmid' for PubMed IDs, 'doi' for DOI, 'pmcid' for PubMed Central IDs "+
+					"such as PMC3531190, or 'europepmc_id' for Europe PMC records as SOURCE:ID, such as "+
+					"MED:23172289 or PPR:PPR123456",
 			),
 			mcp.Required(),
-			mcp.Enum("pmid", "doi"),
+			mcp.Enum(IDTypePMID, IDTypeDOI, IDTypePMCID, IDTypeEuropePMCID),
 		),
 		mcp.WithString(
 			"provider",
 			mcp.Description(
-				"Literature provider: 'pubmed' (default) or 'europepmc' for enhanced metadata",
+				"Literature provider: 'pubmed' (default), 'europepmc' for enhanced metadata, 'openalex' "+
+					"for concept tags, institution-resolved affiliations and citation percentiles, or "+
+					"'semanticscholar' for influential citation counts, fields of study and a TLDR summary, or "+
+					"'merged' to combine the EuropePMC and PubMed records, taking the richer value of each field",
+			),
+			mcp.Enum("pubmed", "europepmc", ProviderOpenAlex, ProviderSemanticScholar, ProviderMerged),
+		),
+		mcp.WithString(
+			"output_format",
+			mcp.Description(
+				"Output format: 'markdown' (default) for a compact readable record, 'json' for every field "+
+					"as JSON, 'summary' for a few plain lines, or a citation format for reference managers: "+
+					"'bibtex', 'ris', 'endnote' or 'csljson'",
+			),
+			mcp.Enum(
+				FormatMarkdown, FormatJSON, FormatSummary, FormatBibTeX, FormatRIS, FormatEndNote, FormatCSLJSON,
+			),
+		),
+		mcp.WithBoolean(
+			"data_links",
+			mcp.Description(
+				"Also list the supplementary files of open access articles and the dataset accessions, "+
+					"such as GEO, SRA or PDB, cited by the article, from Europe PMC. Takes two more "+
+					"requests; defaults to false",
 			),
-			mcp.Enum("pubmed", "europepmc"),
 		),
 	)
 
-	client, err := NewLiteratureClient(WithLogger(logger))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create literature client: %w", err)
-	}
-
-	return &LiteratureTool{
+	literatureTool := &LiteratureTool{
 		Name:        "literature-fetch",
-		Description: "Fetches scientific literature information using PubMed or DOI IDs via the dictyBase literature API",
+		Description: "Fetches scientific literature information using PubMed, PMC, Europe PMC or DOI IDs",
 		Tool:        tool,
-		client:      client,
 		Logger:      logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(literatureTool)
+	}
+	return literatureTool, nil
 }
 
 // GetName returns the name of the tool.
@@ -129,6 +265,72 @@ func (l *LiteratureTool) GetTool() mcp.Tool {
 	return l.Tool
 }
 
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (l *LiteratureTool) GetAnnotations() mcp.ToolAnnotation {
+	return l.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (l *LiteratureTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Fetch an article by PubMed ID",
+			Arguments: map[string]any{
+				"id":      "23172289",
+				"id_type": "pmid",
+			},
+		},
+		{
+			Description: "Fetch an article by PubMed Central ID",
+			Arguments: map[string]any{
+				"id":      "PMC3531190",
+				"id_type": IDTypePMCID,
+			},
+		},
+		{
+			Description: "Fetch an article by DOI from Semantic Scholar",
+			Arguments: map[string]any{
+				"id":       "10.1093/nar/gks1064",
+				"id_type":  "doi",
+				"provider": ProviderSemanticScholar,
+			},
+		},
+		{
+			Description: "Merge the EuropePMC and PubMed records of an article",
+			Arguments: map[string]any{
+				"id":       "23172289",
+				"id_type":  "pmid",
+				"provider": ProviderMerged,
+			},
+		},
+		{
+			Description: "Get a short plain summary of an article to reason about",
+			Arguments: map[string]any{
+				"id":            "PMC3531190",
+				"id_type":       IDTypePMCID,
+				"output_format": FormatSummary,
+			},
+		},
+		{
+			Description: "List the supplementary files and datasets of an article",
+			Arguments: map[string]any{
+				"id":         "PMC3531190",
+				"id_type":    IDTypePMCID,
+				"data_links": true,
+			},
+		},
+		{
+			Description: "Export the citation of an article as BibTeX",
+			Arguments: map[string]any{
+				"id":            "10.1093/nar/gks1064",
+				"id_type":       "doi",
+				"output_format": FormatBibTeX,
+			},
+		},
+	}
+}
+
 // Handler returns a function that handles tool execution requests.
 func (l *LiteratureTool) Handler(
 	ctx context.Context,
@@ -141,7 +343,11 @@ func (l *LiteratureTool) Handler(
 	idType, idTypeOk := args["id_type"].(string)
 
 	if !idOk || !idTypeOk {
-		return nil, fmt.Errorf("missing required parameters: id and id_type")
+		return toolerror.Result(&LiteratureError{
+			Type:    ErrorTypeInvalidInput,
+			Message: "missing required parameters: id and id_type",
+			Code:    "MISSING_PARAMETER",
+		}), nil
 	}
 
 	params := LiteratureRequest{
@@ -155,32 +361,81 @@ func (l *LiteratureTool) Handler(
 	} else {
 		params.Provider = "pubmed" // Default to PubMed
 	}
+	params.OutputFormat = FormatMarkdown
+	if format, ok := args["output_format"].(string); ok && format != "" {
+		params.OutputFormat = format
+	}
+	params.DataLinks = request.GetBool("data_links", false)
 
 	// Validate parameters
 	if err := validate.Struct(params); err != nil {
-		return nil, fmt.Errorf("validation error: %w", err)
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
 	}
 
 	// Normalize ID based on type
 	normalizedID, err := l.normalizeID(params.ID, params.IDType)
 	if err != nil {
-		return nil, fmt.Errorf("invalid %s format: %w", params.IDType, err)
+		return toolerror.Result(toolerror.Wrap(
+			ErrorTypeInvalidInput,
+			"INVALID_ID",
+			err,
+			fmt.Sprintf("invalid %s format", params.IDType),
+		)), nil
 	}
 	params.ID = normalizedID
 
 	// Fetch literature information
-	article, err := l.fetchArticle(ctx, params)
+	logger := logging.WithRequestID(l.Logger)
+	article, err := l.fetchArticle(ctx, logger, params)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch literature: %w", err)
+		return toolerror.Result(fmt.Errorf("failed to fetch literature: %w", err)), nil
+	}
+	providers := article.Providers()
+	if params.DataLinks {
+		providers = l.addDataLinks(ctx, logger, article, providers)
 	}
 
 	// Format and return the result
-	result, err := l.formatArticleResult(article)
+	format, err := l.formatter(params.OutputFormat)
 	if err != nil {
-		return nil, fmt.Errorf("failed to format result: %w", err)
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
 	}
+	result, err := format(article)
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to format result: %w", err)), nil
+	}
+
+	return provenance.Attach(
+		mcp.NewToolResultText(result),
+		provenance.New(providers),
+	), nil
+}
 
-	return mcp.NewToolResultText(result), nil
+// addDataLinks adds the supplementary files and data citations to an
+// article and returns its providers, with Europe PMC if it found any. The
+// article is returned without them when the lookups fail.
+func (l *LiteratureTool) addDataLinks(
+	ctx context.Context,
+	logger *slog.Logger,
+	article *Article,
+	providers []string,
+) []string {
+	client, err := l.literatureClient()
+	if err != nil {
+		logger.Warn("skipping data links", "error", err)
+		return providers
+	}
+	if err := client.AddDataLinks(ctx, article); err != nil {
+		logger.Warn("failed to fetch data links", "pmid", article.PMID, "pmcid", article.PMCID, "error", err)
+	}
+	if len(article.SupplementaryFiles) == 0 && len(article.DataCitations) == 0 {
+		return providers
+	}
+	if !slices.Contains(providers, metrics.ServiceEuropePMC) {
+		providers = append(providers, metrics.ServiceEuropePMC)
+		slices.Sort(providers)
+	}
+	return providers
 }
 
 // normalizeID validates and normalizes the identifier based on its type.
@@ -190,6 +445,10 @@ func (l *LiteratureTool) normalizeID(id, idType string) (string, error) {
 		return l.normalizePMID(id)
 	case IDTypeDOI:
 		return l.normalizeDOI(id)
+	case IDTypePMCID:
+		return l.normalizePMCID(id)
+	case IDTypeEuropePMCID:
+		return l.normalizeEuropePMCID(id)
 	default:
 		return "", fmt.Errorf("unsupported ID type: %s", idType)
 	}
@@ -237,23 +496,66 @@ func (l *LiteratureTool) normalizeDOI(doi string) (string, error) {
 	return normalizedDOI, nil
 }
 
-// formatArticleResult formats the article information for display.
+// normalizePMCID validates a PubMed Central ID and normalizes it to its
+// PMC-prefixed form.
+func (l *LiteratureTool) normalizePMCID(pmcid string) (string, error) {
+	matches := pmcidRegex.FindStringSubmatch(pmcid)
+	if len(matches) < 2 {
+		return "", fmt.Errorf(
+			"invalid PMCID format, expected 'PMC' followed by digits, got: %s",
+			pmcid,
+		)
+	}
+	return "PMC" + matches[1], nil
+}
+
+// normalizeEuropePMCID validates a Europe PMC ID and normalizes it to
+// SOURCE:ID. The source may be left out of PMC and PPR IDs, whose prefix
+// names it, and of PubMed IDs.
+func (l *LiteratureTool) normalizeEuropePMCID(id string) (string, error) {
+	matches := europePMCIDRegex.FindStringSubmatch(id)
+	if len(matches) < 3 {
+		return "", fmt.Errorf(
+			"invalid Europe PMC ID format, expected SOURCE:ID such as 'MED:23172289', got: %s",
+			id,
+		)
+	}
+	source, externalID := strings.ToUpper(matches[1]), strings.ToUpper(matches[2])
+	if source == "" {
+		switch {
+		case strings.HasPrefix(externalID, "PMC"):
+			source = "PMC"
+		case strings.HasPrefix(externalID, "PPR"):
+			source = "PPR"
+		case pmidRegex.MatchString(externalID):
+			source = "MED"
+		default:
+			return "", fmt.Errorf("missing source in Europe PMC ID, expected SOURCE:ID, got: %s", id)
+		}
+	}
+	if !slices.Contains(europePMCSources, source) {
+		return "", fmt.Errorf(
+			"unknown Europe PMC source %s, expected one of %s",
+			source,
+			strings.Join(europePMCSources, ", "),
+		)
+	}
+	return source + ":" + externalID, nil
+}
+
+// formatArticleResult formats the article information for display as
+// compact markdown; the json output format has every field.
 func (l *LiteratureTool) formatArticleResult(article *Article) (string, error) {
 	if article == nil {
 		return "No article found", nil
 	}
 
-	jsonData, err := json.MarshalIndent(article, "", "  ")
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal article data: %w", err)
-	}
-
 	var result strings.Builder
 	result.WriteString("## Literature Information\n\n")
 
 	l.formatBasicInfo(&result, article)
 	l.formatMetadata(&result, article)
-	l.formatJSONData(&result, jsonData)
+	l.formatDataLinks(&result, article)
 
 	return result.String(), nil
 }
@@ -266,12 +568,15 @@ func (l *LiteratureTool) formatBasicInfo(result *strings.Builder, article *Artic
 
 	if len(article.Authors) > 0 {
 		result.WriteString("**Authors:** ")
-		for index, author := range article.Authors {
+		for index, author := range article.Authors[:min(len(article.Authors), maxMarkdownAuthors)] {
 			if index > 0 {
 				result.WriteString(", ")
 			}
 			result.WriteString(author.FullName)
 		}
+		if more := len(article.Authors) - maxMarkdownAuthors; more > 0 {
+			fmt.Fprintf(result, " and %d more", more)
+		}
 		result.WriteString("\n\n")
 	}
 
@@ -283,30 +588,123 @@ func (l *LiteratureTool) formatBasicInfo(result *strings.Builder, article *Artic
 		result.WriteString("\n\n")
 	}
 
+	if article.TLDR != "" {
+		fmt.Fprintf(result, "**TLDR:** %s\n\n", article.TLDR)
+	}
+
 	if article.Abstract != "" {
 		fmt.Fprintf(result, "**Abstract:** %s\n\n", article.Abstract)
 	}
 }
 
-// formatMetadata formats PMID, DOI, and citation information.
+// formatMetadata formats PMID, PMCID, DOI, preprint status, concept, field of
+// study, merged provider and citation information.
 func (l *LiteratureTool) formatMetadata(result *strings.Builder, article *Article) {
 	if article.PMID != "" {
 		fmt.Fprintf(result, "**PMID:** %s\n", article.PMID)
 	}
 
+	if article.PMCID != "" {
+		fmt.Fprintf(result, "**PMCID:** %s\n", article.PMCID)
+	}
+
 	if article.DOI != "" {
 		fmt.Fprintf(result, "**DOI:** %s\n", article.DOI)
 	}
 
+	if article.PublicationStatus == PublicationStatusPreprint {
+		result.WriteString("**Status:** preprint")
+		if published := article.PublishedVersion; published != nil {
+			result.WriteString(", published")
+			if published.Journal != "" {
+				fmt.Fprintf(result, " in %s", published.Journal)
+			}
+			if published.DOI != "" {
+				fmt.Fprintf(result, " as https://doi.org/%s", published.DOI)
+			}
+		}
+		result.WriteString("\n")
+	}
+
+	if len(article.Concepts) > 0 {
+		names := make([]string, 0, min(len(article.Concepts), maxConcepts))
+		for _, concept := range article.Concepts[:cap(names)] {
+			names = append(names, concept.Name)
+		}
+		fmt.Fprintf(result, "**Concepts:** %s\n", strings.Join(names, ", "))
+	}
+
+	if len(article.FieldsOfStudy) > 0 {
+		fmt.Fprintf(result, "**Fields of Study:** %s\n", strings.Join(article.FieldsOfStudy, ", "))
+	}
+
+	if len(article.FieldSources) > 0 {
+		fmt.Fprintf(result, "**Merged From:** %s\n", strings.Join(article.Providers(), ", "))
+	}
+
 	if article.CitedByCount > 0 {
-		fmt.Fprintf(result, "**Citations:** %d\n", article.CitedByCount)
+		fmt.Fprintf(result, "**Citations:** %d", article.CitedByCount)
+		if article.InfluentialCitationCount > 0 {
+			fmt.Fprintf(result, ", %d influential", article.InfluentialCitationCount)
+		}
+		if citationMetrics := article.CitationMetrics; citationMetrics != nil {
+			fmt.Fprintf(result, " (percentile %.1f", citationMetrics.Percentile)
+			switch {
+			case citationMetrics.TopOnePercent:
+				result.WriteString(", top 1%")
+			case citationMetrics.TopTenPercent:
+				result.WriteString(", top 10%")
+			}
+			if citationMetrics.FWCI > 0 {
+				fmt.Fprintf(result, ", FWCI %.2f", citationMetrics.FWCI)
+			}
+			result.WriteString(")")
+		}
+		result.WriteString("\n")
 	}
 }
 
-// formatJSONData appends the raw JSON data section.
-func (l *LiteratureTool) formatJSONData(result *strings.Builder, jsonData []byte) {
-	result.WriteString("\n---\n\n")
-	result.WriteString("**Raw JSON Data:**\n```json\n")
-	result.WriteString(string(jsonData))
-	result.WriteString("\n```")
+// formatDataLinks formats the full text links, the supplementary files and
+// the dataset accessions, linking each to where it can be downloaded.
+func (l *LiteratureTool) formatDataLinks(result *strings.Builder, article *Article) {
+	if len(article.FullTextURLs) > 0 {
+		links := make([]string, 0, len(article.FullTextURLs))
+		for _, fullText := range article.FullTextURLs {
+			name := fullText.Site
+			if name == "" {
+				name = "Full text"
+			}
+			if fullText.Style != "" {
+				name += " (" + fullText.Style + ")"
+			}
+			links = append(links, fmt.Sprintf("[%s](%s)", name, fullText.URL))
+		}
+		fmt.Fprintf(result, "**Full Text:** %s\n", strings.Join(links, ", "))
+	}
+
+	if len(article.SupplementaryFiles) > 0 {
+		result.WriteString("\n### Supplementary Files\n\n")
+		for _, file := range article.SupplementaryFiles {
+			label := file.Label
+			if label == "" {
+				label = path.Base(file.URL)
+			}
+			fmt.Fprintf(result, "- [%s](%s)", label, file.URL)
+			if file.Caption != "" {
+				fmt.Fprintf(result, ": %s", file.Caption)
+			}
+			result.WriteString("\n")
+		}
+	}
+
+	if len(article.DataCitations) > 0 {
+		result.WriteString("\n### Data Citations\n\n")
+		for _, citation := range article.DataCitations {
+			if citation.URL == "" {
+				fmt.Fprintf(result, "- %s %s\n", citation.Database, citation.Accession)
+				continue
+			}
+			fmt.Fprintf(result, "- %s [%s](%s)\n", citation.Database, citation.Accession, citation.URL)
+		}
+	}
 }
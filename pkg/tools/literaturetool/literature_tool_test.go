@@ -2,7 +2,7 @@ package literaturetool
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"testing"
 
@@ -14,7 +14,7 @@ import (
 func TestNewLiteratureTool(t *testing.T) {
 	t.Parallel()
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 
 	require.NoError(t, err)
@@ -28,21 +28,40 @@ func TestNewLiteratureTool(t *testing.T) {
 func TestLiteratureTool_GetMethods(t *testing.T) {
 	t.Parallel()
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
 	assert.Equal(t, "literature-fetch", tool.GetName())
-	expectedDescription := "Fetches scientific literature information using PubMed or DOI IDs via the dictyBase literature API"
+	expectedDescription := "Fetches scientific literature information using PubMed, PMC, Europe PMC or DOI IDs"
 	assert.Equal(t, expectedDescription, tool.GetDescription())
 
 	schema := tool.GetSchema()
 	assert.NotNil(t, schema)
 
 	mcpTool := tool.GetTool()
+	assert.True(t, *mcpTool.Annotations.ReadOnlyHint)
+	assert.True(t, *mcpTool.Annotations.OpenWorldHint)
 	assert.Equal(t, "literature-fetch", mcpTool.Name)
 }
 
+func TestLiteratureTool_LazyClient(t *testing.T) {
+	t.Parallel()
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewLiteratureTool(logger)
+	require.NoError(t, err)
+	assert.Nil(t, tool.client, "client should not be created at construction")
+
+	first, err := tool.literatureClient()
+	require.NoError(t, err)
+	require.NotNil(t, first)
+
+	second, err := tool.literatureClient()
+	require.NoError(t, err)
+	assert.Same(t, first, second, "client should be created only once")
+}
+
 func TestNormalizePMID(t *testing.T) {
 	t.Parallel()
 
@@ -84,7 +103,7 @@ func TestNormalizePMID(t *testing.T) {
 		},
 	}
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
@@ -174,7 +193,7 @@ func TestNormalizeDOI(t *testing.T) {
 		},
 	}
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
@@ -194,10 +213,97 @@ func TestNormalizeDOI(t *testing.T) {
 	}
 }
 
+func TestNormalizePMCID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "PMCID", input: "PMC3531190", want: "PMC3531190"},
+		{name: "digits only", input: " 3531190 ", want: "PMC3531190"},
+		{name: "pmcid prefix", input: "PMCID: PMC3531190", want: "PMC3531190"},
+		{name: "NCBI URL", input: "https://www.ncbi.nlm.nih.gov/pmc/articles/PMC3531190/", want: "PMC3531190"},
+		{name: "PMC URL", input: "https://pmc.ncbi.nlm.nih.gov/articles/PMC3531190/", want: "PMC3531190"},
+		{name: "empty", input: "", wantErr: true},
+		{name: "letters", input: "PMC35311a0", wantErr: true},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewLiteratureTool(logger)
+	require.NoError(t, err)
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tool.normalizePMCID(testCase.input)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestNormalizeEuropePMCID(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "PubMed record", input: "MED:23172289", want: "MED:23172289"},
+		{name: "lowercase preprint", input: "ppr:ppr123456", want: "PPR:PPR123456"},
+		{name: "slash separator", input: "PMC/PMC3531190", want: "PMC:PMC3531190"},
+		{name: "article URL", input: "https://europepmc.org/article/MED/23172289", want: "MED:23172289"},
+		{name: "abstract URL", input: "https://europepmc.org/abstract/AGR/IND43672213", want: "AGR:IND43672213"},
+		{name: "bare PMID", input: "23172289", want: "MED:23172289"},
+		{name: "bare preprint", input: "PPR123456", want: "PPR:PPR123456"},
+		{name: "bare PMCID", input: "PMC3531190", want: "PMC:PMC3531190"},
+		{name: "unknown source", input: "XYZ:123", wantErr: true},
+		{name: "bare ID without known prefix", input: "IND43672213", wantErr: true},
+		{name: "empty", input: "", wantErr: true},
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewLiteratureTool(logger)
+	require.NoError(t, err)
+
+	for _, testCase := range tests {
+		t.Run(testCase.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tool.normalizeEuropePMCID(testCase.input)
+
+			if testCase.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, testCase.want, got)
+		})
+	}
+}
+
+func TestEuropePMCQuery(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "DOI:10.1093/nar/gks1064", europePMCQuery("10.1093/nar/gks1064", IDTypeDOI))
+	assert.Equal(t, "PMCID:PMC3531190", europePMCQuery("PMC3531190", IDTypePMCID))
+	assert.Equal(t, "EXT_ID:PPR123456 AND SRC:PPR", europePMCQuery("PPR:PPR123456", IDTypeEuropePMCID))
+}
+
 func TestNormalizeID(t *testing.T) {
 	t.Parallel()
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
@@ -222,6 +328,20 @@ func TestNormalizeID(t *testing.T) {
 			want:    "10.1038/nature12373",
 			wantErr: false,
 		},
+		{
+			name:    "normalize PMCID",
+			id:      "pmc3531190",
+			idType:  "pmcid",
+			want:    "PMC3531190",
+			wantErr: false,
+		},
+		{
+			name:    "normalize Europe PMC ID",
+			id:      "ppr:ppr123456",
+			idType:  "europepmc_id",
+			want:    "PPR:PPR123456",
+			wantErr: false,
+		},
 		{
 			name:    "unsupported ID type",
 			id:      "12345",
@@ -249,7 +369,7 @@ func TestNormalizeID(t *testing.T) {
 
 func TestHandler_ValidationErrors(t *testing.T) {
 	t.Parallel()
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
@@ -289,6 +409,15 @@ func TestHandler_ValidationErrors(t *testing.T) {
 			},
 			wantErrContains: "validation error",
 		},
+		{
+			name: "invalid output format",
+			args: map[string]any{
+				"id":            "12345678",
+				"id_type":       "pmid",
+				"output_format": "docx",
+			},
+			wantErrContains: "validation error",
+		},
 		{
 			name: "invalid PMID format",
 			args: map[string]any{
@@ -319,9 +448,14 @@ func TestHandler_ValidationErrors(t *testing.T) {
 
 			result, err := tool.Handler(context.Background(), request)
 
-			require.Error(t, err)
-			assert.Nil(t, result)
-			assert.Contains(t, err.Error(), testCase.wantErrContains)
+			require.NoError(t, err)
+			assert.True(t, result.IsError)
+			text, ok := mcp.AsTextContent(result.Content[0])
+			require.True(t, ok)
+			assert.Contains(t, text.Text, testCase.wantErrContains)
+			toolErr, ok := result.StructuredContent.(*LiteratureError)
+			require.True(t, ok)
+			assert.Equal(t, ErrorTypeInvalidInput, toolErr.Type)
 		})
 	}
 }
@@ -329,7 +463,7 @@ func TestHandler_ValidationErrors(t *testing.T) {
 func TestFormatArticleResult(t *testing.T) {
 	t.Parallel()
 
-	logger := log.New(os.Stderr, "[test] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 	tool, err := NewLiteratureTool(logger)
 	require.NoError(t, err)
 
@@ -379,6 +513,71 @@ func TestFormatArticleResult(t *testing.T) {
 		assert.Contains(t, result, "10.1038/nature12373")
 		assert.Contains(t, result, "42")
 		assert.Contains(t, result, "This is a test abstract")
-		assert.Contains(t, result, "Raw JSON Data")
+		assert.NotContains(t, result, "Raw JSON Data", "markdown is compact; the json format has every field")
+		assert.NotContains(t, result, "**Status:**")
+	})
+
+	t.Run("merged article", func(t *testing.T) {
+		t.Parallel()
+		article := MergeArticles(
+			&Article{Source: "europepmc", PMID: "23172289", Title: "DictyBase 2013"},
+			&Article{Source: "pubmed", DOI: "10.1093/nar/gks1064"},
+		)
+		result, err := tool.formatArticleResult(article)
+		require.NoError(t, err)
+		assert.Contains(t, result, "**Merged From:** europepmc, pubmed")
+	})
+
+	t.Run("published preprint", func(t *testing.T) {
+		t.Parallel()
+		article := &Article{
+			DOI:               "10.1101/2023.05.01.538912",
+			Title:             "Chemotaxis in Dictyostelium",
+			Journal:           Journal{Title: "bioRxiv"},
+			PublicationStatus: PublicationStatusPreprint,
+			PublishedVersion:  &PublishedVersion{DOI: "10.1016/j.cell.2024.01.001"},
+		}
+
+		result, err := tool.formatArticleResult(article)
+		require.NoError(t, err)
+		assert.Contains(t, result, "**Status:** preprint, published as https://doi.org/10.1016/j.cell.2024.01.001\n")
+	})
+	t.Run("openalex metrics", func(t *testing.T) {
+		t.Parallel()
+		article := &Article{
+			Title:        "cAMP relay in Dictyostelium",
+			CitedByCount: 120,
+			Concepts: []Concept{
+				{Name: "Biology", Level: 0, Score: 0.9},
+				{Name: "Chemotaxis", Level: 2, Score: 0.8},
+				{Name: "Dictyostelium", Level: 3, Score: 0.7},
+				{Name: "Cell biology", Level: 1, Score: 0.6},
+				{Name: "Cyclic AMP", Level: 2, Score: 0.5},
+				{Name: "Gene", Level: 1, Score: 0.4},
+			},
+			CitationMetrics: &CitationMetrics{Percentile: 99.2, TopOnePercent: true, TopTenPercent: true, FWCI: 4.5},
+		}
+
+		result, err := tool.formatArticleResult(article)
+		require.NoError(t, err)
+		assert.Contains(t, result, "**Concepts:** Biology, Chemotaxis, Dictyostelium, Cell biology, Cyclic AMP\n")
+		assert.Contains(t, result, "**Citations:** 120 (percentile 99.2, top 1%, FWCI 4.50)\n")
+	})
+	t.Run("semantic scholar metrics", func(t *testing.T) {
+		t.Parallel()
+		article := &Article{
+			Title:                    "cAMP relay in Dictyostelium",
+			Abstract:                 "Cells move towards cAMP.",
+			CitedByCount:             120,
+			InfluentialCitationCount: 15,
+			FieldsOfStudy:            []string{"Biology", "Medicine"},
+			TLDR:                     "Cells follow cAMP waves.",
+		}
+
+		result, err := tool.formatArticleResult(article)
+		require.NoError(t, err)
+		assert.Contains(t, result, "**TLDR:** Cells follow cAMP waves.\n\n**Abstract:** Cells move towards cAMP.\n")
+		assert.Contains(t, result, "**Fields of Study:** Biology, Medicine\n")
+		assert.Contains(t, result, "**Citations:** 120, 15 influential\n")
 	})
 }
@@ -0,0 +1,250 @@
+package literaturetool
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// ProviderMerged selects the literature-fetch strategy that combines the
+// EuropePMC and PubMed records of an article instead of falling back from
+// one to the other.
+const ProviderMerged = "merged"
+
+// GetMergedArticle fetches an article from EuropePMC and PubMed and merges
+// the two records with MergeArticles, EuropePMC being the primary one. PubMed
+// only looks up PMIDs, so for other identifiers it is asked for the PMID
+// EuropePMC returned; the lookups run at once when the PMID is known up
+// front. A provider that fails leaves the record of the other one, and the
+// EuropePMC error is returned when both fail.
+func (c *LiteratureClient) GetMergedArticle(ctx context.Context, identifier, idType string) (*Article, error) {
+	var pubmed *Article
+	var pubmedErr error
+	var wg sync.WaitGroup
+	pmid := knownPMID(identifier, idType)
+	if pmid != "" {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			pubmed, pubmedErr = c.GetArticleFromPubMed(ctx, pmid, IDTypePMID)
+		}()
+	}
+	europePMC, europePMCErr := c.GetArticleFromEuropePMC(ctx, identifier, idType)
+	wg.Wait()
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return nil, fmt.Errorf("merged lookup aborted: %w", ctxErr)
+	}
+	if pmid == "" && europePMCErr == nil && europePMC.PMID != "" {
+		pubmed, pubmedErr = c.GetArticleFromPubMed(ctx, europePMC.PMID, IDTypePMID)
+	}
+
+	switch {
+	case europePMCErr != nil && pubmedErr == nil && pubmed != nil:
+		c.logger.Warn(
+			"EuropePMC lookup failed, using the PubMed record only",
+			"id_type", idType,
+			"id", identifier,
+			"error", europePMCErr,
+		)
+		return MergeArticles(pubmed, nil), nil
+	case europePMCErr != nil:
+		return nil, europePMCErr
+	case pubmedErr != nil:
+		c.logger.Warn(
+			"PubMed lookup failed, using the EuropePMC record only",
+			"id_type", idType,
+			"id", identifier,
+			"error", pubmedErr,
+		)
+	}
+	return MergeArticles(europePMC, pubmed), nil
+}
+
+// knownPMID returns the PMID an identifier names without a lookup: a PMID
+// itself or the ID of a MED record of Europe PMC.
+func knownPMID(identifier, idType string) string {
+	switch idType {
+	case IDTypePMID:
+		return identifier
+	case IDTypeEuropePMCID:
+		if source, externalID, _ := strings.Cut(identifier, ":"); source == "MED" {
+			return externalID
+		}
+	}
+	return ""
+}
+
+// MergeArticles combines two records of the same article field by field.
+// Text, lists, counts and flags take the richer value: the longer text, the
+// longer list, the higher count and a set flag. Identifiers, dates and
+// statuses take the first value set, so two spellings of one fact are never
+// mixed. Ties go to primary. FieldSources of the result names the provider
+// of every field set, keyed by its JSON name. secondary may be nil, and the
+// ID and source of the result are those of primary.
+func MergeArticles(primary, secondary *Article) *Article {
+	if secondary == nil {
+		secondary = &Article{}
+	}
+	m := &fieldMerger{
+		primary:   primary.Source,
+		secondary: secondary.Source,
+		sources:   make(map[string]string),
+	}
+	return &Article{
+		ID:                primary.ID,
+		Source:            primary.Source,
+		PMID:              pick(m, "pmid", primary.PMID, secondary.PMID, stringSet),
+		PMCID:             pick(m, "pmcid", primary.PMCID, secondary.PMCID, stringSet),
+		DOI:               pick(m, "doi", primary.DOI, secondary.DOI, stringSet),
+		Title:             pick(m, "title", primary.Title, secondary.Title, textLength),
+		AuthorString:      pick(m, "author_string", primary.AuthorString, secondary.AuthorString, textLength),
+		Authors:           pick(m, "authors", primary.Authors, secondary.Authors, count[Author]),
+		Abstract:          pick(m, "abstract", primary.Abstract, secondary.Abstract, textLength),
+		Journal:           mergeJournal(m, primary.Journal, secondary.Journal),
+		PubYear:           pick(m, "pub_year", primary.PubYear, secondary.PubYear, stringSet),
+		PageInfo:          pick(m, "page_info", primary.PageInfo, secondary.PageInfo, stringSet),
+		Keywords:          pick(m, "keywords", primary.Keywords, secondary.Keywords, count[string]),
+		IsOpenAccess:      pick(m, "is_open_access", primary.IsOpenAccess, secondary.IsOpenAccess, flagSet),
+		HasPDF:            pick(m, "has_pdf", primary.HasPDF, secondary.HasPDF, flagSet),
+		License:           pick(m, "license", primary.License, secondary.License, stringSet),
+		CitedByCount:      pick(m, "cited_by_count", primary.CitedByCount, secondary.CitedByCount, amount),
+		Language:          pick(m, "language", primary.Language, secondary.Language, stringSet),
+		PubTypes:          pick(m, "pub_types", primary.PubTypes, secondary.PubTypes, count[string]),
+		MeshHeadings:      pick(m, "mesh_headings", primary.MeshHeadings, secondary.MeshHeadings, count[MeshHeading]),
+		Chemicals:         pick(m, "chemicals", primary.Chemicals, secondary.Chemicals, count[Chemical]),
+		Grants:            pick(m, "grants", primary.Grants, secondary.Grants, count[Grant]),
+		PublishDate:       pick(m, "publish_date", primary.PublishDate, secondary.PublishDate, dateSet),
+		CreationDate:      pick(m, "creation_date", primary.CreationDate, secondary.CreationDate, dateSet),
+		RevisionDate:      pick(m, "revision_date", primary.RevisionDate, secondary.RevisionDate, dateSet),
+		PublicationStatus: pick(m, "publication_status", primary.PublicationStatus, secondary.PublicationStatus, stringSet),
+		PublishedVersion:  pick(m, "published_version", primary.PublishedVersion, secondary.PublishedVersion, present),
+		Concepts:          pick(m, "concepts", primary.Concepts, secondary.Concepts, count[Concept]),
+		CitationMetrics:   pick(m, "citation_metrics", primary.CitationMetrics, secondary.CitationMetrics, present),
+		InfluentialCitationCount: pick(
+			m, "influential_citation_count", primary.InfluentialCitationCount, secondary.InfluentialCitationCount, amount,
+		),
+		FieldsOfStudy: pick(m, "fields_of_study", primary.FieldsOfStudy, secondary.FieldsOfStudy, count[string]),
+		TLDR:          pick(m, "tldr", primary.TLDR, secondary.TLDR, textLength),
+		FullTextURLs:  pick(m, "full_text_urls", primary.FullTextURLs, secondary.FullTextURLs, count[FullTextURL]),
+		SupplementaryFiles: pick(
+			m, "supplementary_files", primary.SupplementaryFiles, secondary.SupplementaryFiles, count[SupplementaryFile],
+		),
+		DataCitations: pick(m, "data_citations", primary.DataCitations, secondary.DataCitations, count[DataCitation]),
+		FieldSources:  m.sources,
+	}
+}
+
+// mergeJournal combines the journal fields of two records, recording them
+// under "journal." and their JSON name.
+func mergeJournal(m *fieldMerger, primary, secondary Journal) Journal {
+	return Journal{
+		Title: pick(m, "journal.title", primary.Title, secondary.Title, textLength),
+		MedlineAbbreviation: pick(
+			m, "journal.medline_abbreviation", primary.MedlineAbbreviation, secondary.MedlineAbbreviation, stringSet,
+		),
+		ISOAbbreviation: pick(m, "journal.iso_abbreviation", primary.ISOAbbreviation, secondary.ISOAbbreviation, stringSet),
+		ISSN:            pick(m, "journal.issn", primary.ISSN, secondary.ISSN, stringSet),
+		ESSN:            pick(m, "journal.essn", primary.ESSN, secondary.ESSN, stringSet),
+		Volume:          pick(m, "journal.volume", primary.Volume, secondary.Volume, stringSet),
+		Issue:           pick(m, "journal.issue", primary.Issue, secondary.Issue, stringSet),
+		IssueID:         pick(m, "journal.issue_id", primary.IssueID, secondary.IssueID, numberSet),
+		DateOfPublication: pick(
+			m, "journal.date_of_publication", primary.DateOfPublication, secondary.DateOfPublication, stringSet,
+		),
+		MonthOfPublication: pick(
+			m, "journal.month_of_publication", primary.MonthOfPublication, secondary.MonthOfPublication, numberSet,
+		),
+		YearOfPublication: pick(m, "journal.year_of_publication", primary.YearOfPublication, secondary.YearOfPublication, numberSet),
+		NLMID:             pick(m, "journal.nlm_id", primary.NLMID, secondary.NLMID, stringSet),
+	}
+}
+
+// fieldMerger records the provider of every field MergeArticles sets.
+type fieldMerger struct {
+	primary   string
+	secondary string
+	sources   map[string]string
+}
+
+// pick returns the value of field with the larger size, the primary value
+// on a tie, and records its provider unless both values are empty.
+func pick[T any](m *fieldMerger, field string, primary, secondary T, size func(T) int) T {
+	primarySize, secondarySize := size(primary), size(secondary)
+	switch {
+	case secondarySize > primarySize:
+		m.sources[field] = m.secondary
+		return secondary
+	case primarySize > 0:
+		m.sources[field] = m.primary
+	}
+	return primary
+}
+
+// textLength sizes a text by its characters, so the longer one is richer.
+func textLength(value string) int {
+	return utf8.RuneCountInString(strings.TrimSpace(value))
+}
+
+// stringSet sizes an identifier or status, which is either set or not.
+func stringSet(value string) int {
+	if strings.TrimSpace(value) == "" {
+		return 0
+	}
+	return 1
+}
+
+// numberSet sizes a number that is either set or not.
+func numberSet(value int) int {
+	if value == 0 {
+		return 0
+	}
+	return 1
+}
+
+// amount sizes a count by its value, so the higher one is richer.
+func amount(value int) int {
+	return value
+}
+
+// flagSet sizes a flag, so a set one is richer.
+func flagSet(value bool) int {
+	if value {
+		return 1
+	}
+	return 0
+}
+
+// dateSet sizes a date that is either set or not.
+func dateSet(value *time.Time) int {
+	if value == nil || value.IsZero() {
+		return 0
+	}
+	return 1
+}
+
+// present sizes an optional value that is either set or not.
+func present[T any](value *T) int {
+	if value == nil {
+		return 0
+	}
+	return 1
+}
+
+// count sizes a list by its length.
+func count[T any](values []T) int {
+	return len(values)
+}
+
+// Providers returns the providers the article's fields came from, or only
+// its source when it was not merged.
+func (a *Article) Providers() []string {
+	if len(a.FieldSources) == 0 {
+		return []string{a.Source}
+	}
+	return slices.Compact(slices.Sorted(maps.Values(a.FieldSources)))
+}
@@ -0,0 +1,100 @@
+package literaturetool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMergeArticles(t *testing.T) {
+	t.Parallel()
+	published := time.Date(2013, 1, 5, 0, 0, 0, 0, time.UTC)
+	europePMC := &Article{
+		ID:                "23172289",
+		Source:            "europepmc",
+		PMID:              "23172289",
+		PMCID:             "PMC3531190",
+		Title:             "DictyBase 2013",
+		Authors:           []Author{{FullName: "Basu S"}, {FullName: "Fey P"}, {FullName: "Chisholm RL"}},
+		Abstract:          "dictyBase is the model organism database.",
+		Journal:           Journal{Title: "Nucleic Acids Res", ISSN: "0305-1048"},
+		PubYear:           "2013",
+		IsOpenAccess:      true,
+		CitedByCount:      120,
+		PublicationStatus: PublicationStatusPreprint,
+		PublishDate:       &published,
+	}
+	pubmed := &Article{
+		ID:                "23172289",
+		Source:            "pubmed",
+		PMID:              "23172289",
+		DOI:               "10.1093/nar/gks1064",
+		Title:             "DictyBase 2013: integrating multiple Dictyostelid species",
+		Authors:           []Author{{FullName: "Siddhartha Basu"}},
+		Abstract:          "dictyBase is the model organism database for Dictyostelium discoideum.",
+		Journal:           Journal{Title: "Nucleic acids research", Volume: "41"},
+		PubYear:           "2012",
+		Keywords:          []string{"Dictyostelium"},
+		PublicationStatus: PublicationStatusPublished,
+		PublishDate:       &time.Time{},
+	}
+
+	merged := MergeArticles(europePMC, pubmed)
+	assert.Equal(t, "europepmc", merged.Source)
+	assert.Equal(t, "PMC3531190", merged.PMCID)
+	assert.Equal(t, "10.1093/nar/gks1064", merged.DOI, "a field only one provider set is taken")
+	assert.Equal(t, pubmed.Title, merged.Title, "the longer title is richer")
+	assert.Equal(t, pubmed.Abstract, merged.Abstract)
+	assert.Len(t, merged.Authors, 3, "the longer author list is richer")
+	assert.Equal(t, "Nucleic acids research", merged.Journal.Title)
+	assert.Equal(t, "0305-1048", merged.Journal.ISSN)
+	assert.Equal(t, "41", merged.Journal.Volume)
+	assert.Equal(t, "2013", merged.PubYear, "identifiers and years keep the primary value")
+	assert.Equal(t, PublicationStatusPreprint, merged.PublicationStatus, "statuses are not compared by length")
+	assert.Equal(t, &published, merged.PublishDate, "an unset date loses")
+	assert.True(t, merged.IsOpenAccess)
+	assert.Equal(t, 120, merged.CitedByCount)
+
+	assert.Equal(t, map[string]string{
+		"pmid":               "europepmc",
+		"pmcid":              "europepmc",
+		"doi":                "pubmed",
+		"title":              "pubmed",
+		"authors":            "europepmc",
+		"abstract":           "pubmed",
+		"journal.title":      "pubmed",
+		"journal.issn":       "europepmc",
+		"journal.volume":     "pubmed",
+		"pub_year":           "europepmc",
+		"keywords":           "pubmed",
+		"is_open_access":     "europepmc",
+		"cited_by_count":     "europepmc",
+		"publication_status": "europepmc",
+		"publish_date":       "europepmc",
+	}, merged.FieldSources)
+	assert.Equal(t, []string{"europepmc", "pubmed"}, merged.Providers())
+}
+
+func TestMergeArticles_SingleRecord(t *testing.T) {
+	t.Parallel()
+	merged := MergeArticles(&Article{ID: "1", Source: "pubmed", PMID: "1", Title: "Only PubMed"}, nil)
+	require.NotNil(t, merged)
+	assert.Equal(t, "Only PubMed", merged.Title)
+	assert.Equal(t, map[string]string{"pmid": "pubmed", "title": "pubmed"}, merged.FieldSources)
+	assert.Equal(t, []string{"pubmed"}, merged.Providers())
+}
+
+func TestArticle_Providers(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []string{"crossref"}, (&Article{Source: "crossref"}).Providers(), "unmerged articles name their source")
+}
+
+func TestKnownPMID(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "23172289", knownPMID("23172289", IDTypePMID))
+	assert.Equal(t, "23172289", knownPMID("MED:23172289", IDTypeEuropePMCID))
+	assert.Empty(t, knownPMID("PMC:PMC3531190", IDTypeEuropePMCID))
+	assert.Empty(t, knownPMID("10.1093/nar/gks1064", IDTypeDOI))
+}
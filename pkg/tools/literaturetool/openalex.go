@@ -0,0 +1,347 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+const (
+	defaultOpenAlexURL = "https://api.openalex.org"
+	// ProviderOpenAlex selects OpenAlex as the literature-fetch provider.
+	ProviderOpenAlex = "openalex"
+	// maxConcepts is the number of concepts shown in the markdown summary.
+	maxConcepts = 5
+)
+
+// errOpenAlexNotFound is returned when OpenAlex has no record of a work.
+var errOpenAlexNotFound = errors.New("work not found in OpenAlex")
+
+// OpenAlexClient looks up works in the OpenAlex API, which tags them with
+// concepts, resolves author affiliations to institutions and ranks their
+// citation counts against works of the same field and year.
+type OpenAlexClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// newOpenAlexClient creates an OpenAlex client from the literature client
+// configuration.
+func newOpenAlexClient(cfg *Config) *OpenAlexClient {
+	return &OpenAlexClient{
+		httpClient: cfg.client(),
+		baseURL:    strings.TrimSuffix(cfg.openAlexURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// openAlexWork is the part of an OpenAlex work the client reads.
+type openAlexWork struct {
+	ID              string `json:"id"`
+	DOI             string `json:"doi"`
+	Title           string `json:"title"`
+	PublicationYear int    `json:"publication_year"`
+	PublicationDate string `json:"publication_date"`
+	Language        string `json:"language"`
+	Type            string `json:"type"`
+	IDs             struct {
+		PMID  string `json:"pmid"`
+		PMCID string `json:"pmcid"`
+	} `json:"ids"`
+	PrimaryLocation struct {
+		License string `json:"license"`
+		PDFURL  string `json:"pdf_url"`
+		Source  *struct {
+			DisplayName string `json:"display_name"`
+			ISSNL       string `json:"issn_l"`
+		} `json:"source"`
+	} `json:"primary_location"`
+	OpenAccess struct {
+		IsOA bool `json:"is_oa"`
+	} `json:"open_access"`
+	Authorships []openAlexAuthorship `json:"authorships"`
+	Biblio      struct {
+		Volume    string `json:"volume"`
+		Issue     string `json:"issue"`
+		FirstPage string `json:"first_page"`
+		LastPage  string `json:"last_page"`
+	} `json:"biblio"`
+	CitedByCount int      `json:"cited_by_count"`
+	FWCI         *float64 `json:"fwci"`
+	// CitationNormalizedPercentile ranks the citation count among works
+	// of the same field and year; value is between 0 and 1.
+	CitationNormalizedPercentile *struct {
+		Value            float64 `json:"value"`
+		IsInTop1Percent  bool    `json:"is_in_top_1_percent"`
+		IsInTop10Percent bool    `json:"is_in_top_10_percent"`
+	} `json:"citation_normalized_percentile"`
+	Concepts []struct {
+		DisplayName string  `json:"display_name"`
+		Level       int     `json:"level"`
+		Score       float64 `json:"score"`
+	} `json:"concepts"`
+	Keywords []struct {
+		DisplayName string `json:"display_name"`
+	} `json:"keywords"`
+	Mesh []struct {
+		DescriptorName string `json:"descriptor_name"`
+		QualifierName  string `json:"qualifier_name"`
+		IsMajorTopic   bool   `json:"is_major_topic"`
+	} `json:"mesh"`
+	Grants []struct {
+		FunderDisplayName string `json:"funder_display_name"`
+		AwardID           string `json:"award_id"`
+	} `json:"grants"`
+	// AbstractInvertedIndex maps each word of the abstract to its
+	// positions; OpenAlex may not redistribute abstracts as text.
+	AbstractInvertedIndex map[string][]int `json:"abstract_inverted_index"`
+}
+
+// openAlexAuthorship is an author of an OpenAlex work with the
+// institutions the affiliations were resolved to.
+type openAlexAuthorship struct {
+	Author struct {
+		DisplayName string `json:"display_name"`
+		ORCID       string `json:"orcid"`
+	} `json:"author"`
+	Institutions []openAlexInstitution `json:"institutions"`
+	Affiliations []struct {
+		RawAffiliationString string   `json:"raw_affiliation_string"`
+		InstitutionIDs       []string `json:"institution_ids"`
+	} `json:"affiliations"`
+}
+
+// openAlexInstitution is an institution in the OpenAlex registry.
+type openAlexInstitution struct {
+	ID          string `json:"id"`
+	DisplayName string `json:"display_name"`
+	ROR         string `json:"ror"`
+	CountryCode string `json:"country_code"`
+}
+
+// Work returns the OpenAlex record of a DOI, PMID or PMCID.
+func (c *OpenAlexClient) Work(ctx context.Context, identifier, idType string) (*Article, error) {
+	workPath := (&url.URL{Path: "/works/" + idType + ":" + identifier}).EscapedPath()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+workPath, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating OpenAlex request: %w", err)
+	}
+	start := time.Now()
+	var work openAlexWork
+	err = c.send(req, &work)
+	if !errors.Is(err, errOpenAlexNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceOpenAlex, start, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("looked up work in OpenAlex", idType, identifier, "work", work.ID)
+	return convertOpenAlexWork(work), nil
+}
+
+// send performs the HTTP round trip for Work.
+func (c *OpenAlexClient) send(req *http.Request, target any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling OpenAlex: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errOpenAlexNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"OpenAlex returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error reading OpenAlex response: %w", err)
+	}
+	return nil
+}
+
+// convertOpenAlexWork converts an OpenAlex work to our standard format.
+// OpenAlex identifiers are URLs, which are reduced to the bare IDs.
+func convertOpenAlexWork(work openAlexWork) *Article {
+	authors := make([]Author, 0, len(work.Authorships))
+	for _, authorship := range work.Authorships {
+		authors = append(authors, convertOpenAlexAuthorship(authorship))
+	}
+	journal := Journal{
+		Volume:            work.Biblio.Volume,
+		Issue:             work.Biblio.Issue,
+		YearOfPublication: work.PublicationYear,
+	}
+	if source := work.PrimaryLocation.Source; source != nil {
+		journal.Title = source.DisplayName
+		journal.ISSN = source.ISSNL
+	}
+	pageInfo := work.Biblio.FirstPage
+	if work.Biblio.LastPage != "" && work.Biblio.LastPage != work.Biblio.FirstPage {
+		pageInfo += "-" + work.Biblio.LastPage
+	}
+	article := &Article{
+		ID:                strings.TrimPrefix(work.ID, "https://openalex.org/"),
+		Source:            ProviderOpenAlex,
+		PMID:              strings.TrimPrefix(work.IDs.PMID, "https://pubmed.ncbi.nlm.nih.gov/"),
+		PMCID:             openAlexPMCID(work.IDs.PMCID),
+		DOI:               strings.TrimPrefix(work.DOI, "https://doi.org/"),
+		Title:             work.Title,
+		AuthorString:      authorString(authors),
+		Authors:           authors,
+		Abstract:          openAlexAbstract(work.AbstractInvertedIndex),
+		Journal:           journal,
+		PageInfo:          pageInfo,
+		IsOpenAccess:      work.OpenAccess.IsOA,
+		HasPDF:            work.PrimaryLocation.PDFURL != "",
+		License:           work.PrimaryLocation.License,
+		CitedByCount:      work.CitedByCount,
+		Language:          work.Language,
+		MeshHeadings:      convertOpenAlexMesh(work),
+		PublicationStatus: PublicationStatusPublished,
+	}
+	if work.PublicationYear > 0 {
+		article.PubYear = fmt.Sprint(work.PublicationYear)
+	}
+	if date, err := time.Parse(time.DateOnly, work.PublicationDate); err == nil {
+		article.PublishDate = &date
+	}
+	if work.Type != "" {
+		article.PubTypes = []string{work.Type}
+	}
+	if work.Type == PublicationStatusPreprint {
+		article.PublicationStatus = PublicationStatusPreprint
+	}
+	for _, keyword := range work.Keywords {
+		article.Keywords = append(article.Keywords, keyword.DisplayName)
+	}
+	for _, concept := range work.Concepts {
+		article.Concepts = append(article.Concepts, Concept{
+			Name:  concept.DisplayName,
+			Level: concept.Level,
+			Score: concept.Score,
+		})
+	}
+	for index, grant := range work.Grants {
+		article.Grants = append(article.Grants, Grant{
+			GrantID: grant.AwardID,
+			Agency:  grant.FunderDisplayName,
+			OrderIn: index + 1,
+		})
+	}
+	if percentile := work.CitationNormalizedPercentile; percentile != nil {
+		article.CitationMetrics = &CitationMetrics{
+			Percentile:    math.Round(percentile.Value*1000) / 10,
+			TopOnePercent: percentile.IsInTop1Percent,
+			TopTenPercent: percentile.IsInTop10Percent,
+		}
+		if work.FWCI != nil {
+			article.CitationMetrics.FWCI = *work.FWCI
+		}
+	}
+	return article
+}
+
+// convertOpenAlexAuthorship converts an OpenAlex author to standard
+// format. Each affiliation carries the institution it was resolved to;
+// institutions without a raw affiliation string are listed by name.
+func convertOpenAlexAuthorship(authorship openAlexAuthorship) Author {
+	author := convertDisplayName(authorship.Author.DisplayName, authorship.Author.ORCID)
+	institutions := make(map[string]openAlexInstitution, len(authorship.Institutions))
+	for _, institution := range authorship.Institutions {
+		institutions[institution.ID] = institution
+	}
+	resolved := make(map[string]bool)
+	for _, affiliation := range authorship.Affiliations {
+		converted := Affiliation{Affiliation: affiliation.RawAffiliationString}
+		for _, id := range affiliation.InstitutionIDs {
+			if institution, found := institutions[id]; found {
+				withInstitution(&converted, institution)
+				resolved[id] = true
+				break
+			}
+		}
+		author.Affiliations = append(author.Affiliations, converted)
+	}
+	for _, institution := range authorship.Institutions {
+		if !resolved[institution.ID] {
+			converted := Affiliation{Affiliation: institution.DisplayName}
+			withInstitution(&converted, institution)
+			author.Affiliations = append(author.Affiliations, converted)
+		}
+	}
+	return author
+}
+
+// withInstitution sets the institution an affiliation was resolved to.
+func withInstitution(affiliation *Affiliation, institution openAlexInstitution) {
+	affiliation.Institution = institution.DisplayName
+	affiliation.ROR = strings.TrimPrefix(institution.ROR, "https://ror.org/")
+	affiliation.CountryCode = institution.CountryCode
+}
+
+// convertOpenAlexMesh groups the MeSH terms of a work, which OpenAlex
+// lists once per qualifier, by descriptor.
+func convertOpenAlexMesh(work openAlexWork) []MeshHeading {
+	var headings []MeshHeading
+	index := make(map[string]int)
+	for _, term := range work.Mesh {
+		position, found := index[term.DescriptorName]
+		if !found {
+			position = len(headings)
+			index[term.DescriptorName] = position
+			headings = append(headings, MeshHeading{DescriptorName: term.DescriptorName})
+		}
+		if term.QualifierName == "" {
+			headings[position].MajorTopic = headings[position].MajorTopic || term.IsMajorTopic
+			continue
+		}
+		headings[position].MeshQualifiers = append(headings[position].MeshQualifiers, MeshQualifier{
+			QualifierName: term.QualifierName,
+			MajorTopic:    term.IsMajorTopic,
+		})
+	}
+	return headings
+}
+
+// openAlexAbstract rebuilds an abstract from its inverted index.
+func openAlexAbstract(index map[string][]int) string {
+	var words []string
+	for word, positions := range index {
+		for _, position := range positions {
+			if position >= len(words) {
+				words = slices.Grow(words, position+1-len(words))[:position+1]
+			}
+			words[position] = word
+		}
+	}
+	return strings.Join(strings.Fields(strings.Join(words, " ")), " ")
+}
+
+// openAlexPMCID reduces an OpenAlex PMC link to a PMCID.
+func openAlexPMCID(link string) string {
+	if link == "" {
+		return ""
+	}
+	id := link[strings.LastIndex(strings.TrimSuffix(link, "/"), "/")+1:]
+	id = strings.TrimSuffix(id, "/")
+	if !strings.HasPrefix(id, "PMC") {
+		id = "PMC" + id
+	}
+	return id
+}
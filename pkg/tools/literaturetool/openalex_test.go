@@ -0,0 +1,181 @@
+package literaturetool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// openAlexJSON is an OpenAlex work with resolved institutions, concepts
+// and a citation percentile.
+const openAlexJSON = `{
+  "id": "https://openalex.org/W2100837269",
+  "doi": "https://doi.org/10.1038/nature12373",
+  "title": "Chemotaxis in Dictyostelium",
+  "publication_year": 2013,
+  "publication_date": "2013-08-01",
+  "language": "en",
+  "type": "article",
+  "ids": {"pmid": "https://pubmed.ncbi.nlm.nih.gov/23903748", "pmcid": "https://www.ncbi.nlm.nih.gov/pmc/articles/3828573"},
+  "primary_location": {"license": "cc-by", "pdf_url": null, "source": {"display_name": "Nature", "issn_l": "0028-0836"}},
+  "open_access": {"is_oa": true},
+  "authorships": [
+    {
+      "author": {"display_name": "Jane Q. Doe", "orcid": "https://orcid.org/0000-0002-1825-0097"},
+      "institutions": [
+        {"id": "https://openalex.org/I111979921", "display_name": "Northwestern University",
+         "ror": "https://ror.org/000e0be47", "country_code": "US"},
+        {"id": "https://openalex.org/I4210", "display_name": "dictyBase", "ror": "", "country_code": "US"}
+      ],
+      "affiliations": [
+        {"raw_affiliation_string": "Feinberg School of Medicine, Northwestern University, Chicago, IL",
+         "institution_ids": ["https://openalex.org/I111979921"]}
+      ]
+    },
+    {"author": {"display_name": "Roe", "orcid": null}, "institutions": [], "affiliations": []}
+  ],
+  "biblio": {"volume": "500", "issue": "7463", "first_page": "415", "last_page": "421"},
+  "cited_by_count": 120,
+  "fwci": 4.5,
+  "citation_normalized_percentile": {"value": 0.99213, "is_in_top_1_percent": true, "is_in_top_10_percent": true},
+  "concepts": [
+    {"display_name": "Chemotaxis", "level": 2, "score": 0.82},
+    {"display_name": "Biology", "level": 0, "score": 0.41}
+  ],
+  "keywords": [{"display_name": "cAMP relay"}],
+  "mesh": [
+    {"descriptor_name": "Chemotaxis", "qualifier_name": null, "is_major_topic": true},
+    {"descriptor_name": "Chemotaxis", "qualifier_name": "physiology", "is_major_topic": false},
+    {"descriptor_name": "Dictyostelium", "qualifier_name": "metabolism", "is_major_topic": false}
+  ],
+  "grants": [{"funder_display_name": "National Institutes of Health", "award_id": "GM64426"}],
+  "abstract_inverted_index": {"Cells": [0], "move": [1], "towards": [2], "cAMP.": [3]}
+}`
+
+func newOpenAlexTestClient(t *testing.T) *LiteratureClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/works/doi:10.1038/nature12373", "/works/pmid:23903748", "/works/pmcid:PMC3828573":
+			_, _ = w.Write([]byte(openAlexJSON))
+		case "/works/pmid:1":
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewLiteratureClient(WithOpenAlexURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
+func TestOpenAlexClient_Work(t *testing.T) {
+	t.Parallel()
+	client := newOpenAlexTestClient(t)
+
+	article, err := client.GetArticleFromOpenAlex(context.Background(), "10.1038/nature12373", IDTypeDOI)
+	require.NoError(t, err)
+	publishDate := time.Date(2013, 8, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &Article{
+		ID:           "W2100837269",
+		Source:       "openalex",
+		PMID:         "23903748",
+		PMCID:        "PMC3828573",
+		DOI:          "10.1038/nature12373",
+		Title:        "Chemotaxis in Dictyostelium",
+		AuthorString: "Doe JQ, Roe.",
+		Authors: []Author{
+			{
+				FullName:  "Jane Q. Doe",
+				FirstName: "Jane Q.",
+				LastName:  "Doe",
+				Initials:  "JQ",
+				ORCID:     "0000-0002-1825-0097",
+				Affiliations: []Affiliation{
+					{
+						Affiliation: "Feinberg School of Medicine, Northwestern University, Chicago, IL",
+						Institution: "Northwestern University",
+						ROR:         "000e0be47",
+						CountryCode: "US",
+					},
+					{Affiliation: "dictyBase", Institution: "dictyBase", CountryCode: "US"},
+				},
+			},
+			{FullName: "Roe", LastName: "Roe", Affiliations: []Affiliation{}},
+		},
+		Abstract: "Cells move towards cAMP.",
+		Journal: Journal{
+			Title:             "Nature",
+			ISSN:              "0028-0836",
+			Volume:            "500",
+			Issue:             "7463",
+			YearOfPublication: 2013,
+		},
+		PubYear:      "2013",
+		PageInfo:     "415-421",
+		Keywords:     []string{"cAMP relay"},
+		IsOpenAccess: true,
+		License:      "cc-by",
+		CitedByCount: 120,
+		Language:     "en",
+		PubTypes:     []string{"article"},
+		MeshHeadings: []MeshHeading{
+			{
+				MajorTopic:     true,
+				DescriptorName: "Chemotaxis",
+				MeshQualifiers: []MeshQualifier{{QualifierName: "physiology"}},
+			},
+			{DescriptorName: "Dictyostelium", MeshQualifiers: []MeshQualifier{{QualifierName: "metabolism"}}},
+		},
+		Grants:            []Grant{{GrantID: "GM64426", Agency: "National Institutes of Health", OrderIn: 1}},
+		PublishDate:       &publishDate,
+		PublicationStatus: PublicationStatusPublished,
+		Concepts: []Concept{
+			{Name: "Chemotaxis", Level: 2, Score: 0.82},
+			{Name: "Biology", Level: 0, Score: 0.41},
+		},
+		CitationMetrics: &CitationMetrics{Percentile: 99.2, TopOnePercent: true, TopTenPercent: true, FWCI: 4.5},
+	}, article)
+
+	byPMID, err := client.GetArticleFromOpenAlex(context.Background(), "23903748", IDTypePMID)
+	require.NoError(t, err)
+	assert.Equal(t, article, byPMID)
+
+	byPMCID, err := client.GetArticleFromOpenAlex(context.Background(), "PMC3828573", IDTypePMCID)
+	require.NoError(t, err)
+	assert.Equal(t, article, byPMCID)
+}
+
+func TestOpenAlexClient_Errors(t *testing.T) {
+	t.Parallel()
+	client := newOpenAlexTestClient(t)
+
+	tests := []struct {
+		id        string
+		errorType ErrorType
+		code      string
+	}{
+		{id: "99999999", errorType: ErrorTypeArticleNotFound, code: "OPENALEX_NOT_FOUND"},
+		{id: "1", errorType: ErrorTypeAPIError, code: "OPENALEX_API_ERROR"},
+	}
+	for _, testCase := range tests {
+		_, err := client.GetArticleFromOpenAlex(context.Background(), testCase.id, IDTypePMID)
+		var litErr *LiteratureError
+		require.True(t, errors.As(err, &litErr), testCase.id)
+		assert.Equal(t, testCase.errorType, litErr.Type, testCase.id)
+		assert.Equal(t, testCase.code, litErr.Code, testCase.id)
+	}
+}
+
+func TestOpenAlexAbstract(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "a b a c", openAlexAbstract(map[string][]int{"a": {0, 2}, "b": {1}, "c": {3}}))
+	assert.Empty(t, openAlexAbstract(nil))
+}
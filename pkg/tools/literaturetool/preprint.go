@@ -0,0 +1,296 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+const (
+	defaultBioRxivURL = "https://api.biorxiv.org"
+	defaultArXivURL   = "https://export.arxiv.org"
+)
+
+// Publication statuses of an article.
+const (
+	// PublicationStatusPublished marks an article of a journal, book or
+	// proceedings.
+	PublicationStatusPublished = "published"
+	// PublicationStatusPreprint marks a preprint; its published version,
+	// if known, is linked in PublishedVersion.
+	PublicationStatusPreprint = "preprint"
+)
+
+var (
+	// bioRxivDOIRegex matches the DOIs of bioRxiv and medRxiv preprints.
+	// Cold Spring Harbor journals share the 10.1101 prefix, but their
+	// suffixes start with a journal code instead of digits.
+	bioRxivDOIRegex = regexp.MustCompile(`^10\.1101/(?:\d{6}|\d{4}\.\d{2}\.\d{2}\.\d{6,})(?:v\d+)?$`)
+	// arXivDOIRegex matches the DOIs arXiv assigns, capturing the arXiv ID.
+	arXivDOIRegex = regexp.MustCompile(`(?i)^10\.48550/arxiv\.(.+)$`)
+)
+
+// bioRxivServers are the preprint servers of the bioRxiv API, in the order
+// they are tried, with their display names.
+var bioRxivServers = [][2]string{{"biorxiv", "bioRxiv"}, {"medrxiv", "medRxiv"}}
+
+// errPreprintNotFound is returned when a preprint server has no record of
+// a DOI.
+var errPreprintNotFound = errors.New("DOI not found on the preprint server")
+
+// IsPreprintDOI reports whether doi was assigned by bioRxiv, medRxiv or
+// arXiv.
+func IsPreprintDOI(doi string) bool {
+	return bioRxivDOIRegex.MatchString(doi) || arXivDOIRegex.MatchString(doi)
+}
+
+// PreprintClient looks up preprints in the bioRxiv and arXiv APIs, which
+// know their latest version and whether a journal has published them.
+type PreprintClient struct {
+	httpClient *http.Client
+	bioRxivURL string
+	arXivURL   string
+	logger     *slog.Logger
+}
+
+// newPreprintClient creates a preprint client from the literature client
+// configuration.
+func newPreprintClient(cfg *Config) *PreprintClient {
+	return &PreprintClient{
+		httpClient: cfg.client(),
+		bioRxivURL: strings.TrimSuffix(cfg.bioRxivURL, "/"),
+		arXivURL:   strings.TrimSuffix(cfg.arXivURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// Preprint returns the record of a preprint DOI from its preprint server.
+func (c *PreprintClient) Preprint(ctx context.Context, doi string) (*Article, error) {
+	if matches := arXivDOIRegex.FindStringSubmatch(doi); matches != nil {
+		return c.arXiv(ctx, doi, matches[1])
+	}
+	if bioRxivDOIRegex.MatchString(doi) {
+		return c.bioRxiv(ctx, doi)
+	}
+	return nil, fmt.Errorf("%s is not a bioRxiv, medRxiv or arXiv DOI", doi)
+}
+
+// bioRxivResponse is the answer of the bioRxiv details endpoint.
+type bioRxivResponse struct {
+	// Collection holds one entry per version of the preprint, oldest
+	// first; it is empty when the server does not know the DOI.
+	Collection []bioRxivPreprint `json:"collection"`
+}
+
+// bioRxivPreprint is a version of a bioRxiv or medRxiv preprint.
+type bioRxivPreprint struct {
+	DOI      string `json:"doi"`
+	Title    string `json:"title"`
+	Authors  string `json:"authors"`
+	Date     string `json:"date"`
+	Version  string `json:"version"`
+	License  string `json:"license"`
+	Category string `json:"category"`
+	Abstract string `json:"abstract"`
+	// Published is the DOI of the journal article, or "NA".
+	Published string `json:"published"`
+}
+
+// bioRxiv looks up a DOI on bioRxiv, then on medRxiv.
+func (c *PreprintClient) bioRxiv(ctx context.Context, doi string) (*Article, error) {
+	for _, server := range bioRxivServers {
+		endpoint := fmt.Sprintf("%s/details/%s/%s/na/json", c.bioRxivURL, server[0], doi)
+		start := time.Now()
+		var response bioRxivResponse
+		err := c.send(ctx, endpoint, func(body io.Reader) error {
+			return json.NewDecoder(body).Decode(&response)
+		})
+		metrics.ObserveOutbound(metrics.ServiceBioRxiv, start, err)
+		if err != nil {
+			return nil, err
+		}
+		if len(response.Collection) == 0 {
+			continue
+		}
+		latest := response.Collection[len(response.Collection)-1]
+		c.logger.Debug("looked up preprint", "server", server[0], "doi", doi, "version", latest.Version)
+		return convertBioRxivPreprint(latest, server[1]), nil
+	}
+	return nil, errPreprintNotFound
+}
+
+// arXivFeed is the Atom feed of the arXiv query API.
+type arXivFeed struct {
+	Entries []arXivEntry `xml:"entry"`
+}
+
+// arXivEntry is an arXiv article in the query feed.
+type arXivEntry struct {
+	ID        string `xml:"id"`
+	Title     string `xml:"title"`
+	Summary   string `xml:"summary"`
+	Published string `xml:"published"`
+	Authors   []struct {
+		Name         string   `xml:"name"`
+		Affiliations []string `xml:"http://arxiv.org/schemas/atom affiliation"`
+	} `xml:"author"`
+	// DOI and JournalRef describe the published version, when the authors
+	// have added them.
+	DOI        string `xml:"http://arxiv.org/schemas/atom doi"`
+	JournalRef string `xml:"http://arxiv.org/schemas/atom journal_ref"`
+	Categories []struct {
+		Term string `xml:"term,attr"`
+	} `xml:"category"`
+}
+
+// arXiv looks up an arXiv ID in the arXiv query API.
+func (c *PreprintClient) arXiv(ctx context.Context, doi, arXivID string) (*Article, error) {
+	query := url.Values{"id_list": {arXivID}, "max_results": {"1"}}
+	start := time.Now()
+	var feed arXivFeed
+	err := c.send(ctx, c.arXivURL+"/api/query?"+query.Encode(), func(body io.Reader) error {
+		return xml.NewDecoder(body).Decode(&feed)
+	})
+	metrics.ObserveOutbound(metrics.ServiceArXiv, start, err)
+	if err != nil {
+		return nil, err
+	}
+	// Unknown IDs yield no entry, or an entry describing the error.
+	if len(feed.Entries) == 0 || feed.Entries[0].Title == "" || strings.Contains(feed.Entries[0].ID, "/api/errors") {
+		return nil, errPreprintNotFound
+	}
+	c.logger.Debug("looked up preprint", "server", "arxiv", "doi", doi)
+	return convertArXivEntry(feed.Entries[0], doi), nil
+}
+
+// send performs a GET request and decodes the response body with decode.
+func (c *PreprintClient) send(ctx context.Context, endpoint string, decode func(io.Reader) error) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating preprint server request: %w", err)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling %s: %w", req.URL.Host, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"%s returned status %d: %s",
+			req.URL.Host,
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("error reading %s response: %w", req.URL.Host, err)
+	}
+	return nil
+}
+
+// convertBioRxivPreprint converts a bioRxiv or medRxiv preprint to our
+// standard format. Authors are listed as "Doe, J. Q.; Roe, J.".
+func convertBioRxivPreprint(preprint bioRxivPreprint, server string) *Article {
+	var authors []Author
+	for _, name := range strings.Split(preprint.Authors, ";") {
+		family, given, found := strings.Cut(strings.TrimSpace(name), ",")
+		switch {
+		case family == "":
+			continue
+		case !found:
+			// Consortia are listed without initials.
+			authors = append(authors, convertCrossrefAuthor(crossrefAuthor{Name: family}))
+		default:
+			authors = append(authors, convertCrossrefAuthor(crossrefAuthor{
+				Given:  strings.TrimSpace(given),
+				Family: strings.TrimSpace(family),
+			}))
+		}
+	}
+	article := &Article{
+		ID:                preprint.DOI,
+		Source:            metrics.ServiceBioRxiv,
+		DOI:               preprint.DOI,
+		Title:             preprint.Title,
+		AuthorString:      authorString(authors),
+		Authors:           authors,
+		Abstract:          strings.Join(strings.Fields(preprint.Abstract), " "),
+		Journal:           Journal{Title: server},
+		License:           preprint.License,
+		IsOpenAccess:      true,
+		PubTypes:          []string{PublicationStatusPreprint},
+		PublicationStatus: PublicationStatusPreprint,
+	}
+	if preprint.Category != "" {
+		article.Keywords = []string{preprint.Category}
+	}
+	if date, err := time.Parse(time.DateOnly, preprint.Date); err == nil {
+		article.PublishDate = &date
+		article.PubYear = fmt.Sprint(date.Year())
+	}
+	if preprint.Published != "" && preprint.Published != "NA" {
+		article.PublishedVersion = &PublishedVersion{DOI: preprint.Published}
+	}
+	return article
+}
+
+// convertArXivEntry converts an arXiv entry to our standard format. arXiv
+// gives full names, which are split at the last space.
+func convertArXivEntry(entry arXivEntry, doi string) *Article {
+	authors := make([]Author, 0, len(entry.Authors))
+	for _, author := range entry.Authors {
+		name := strings.Join(strings.Fields(author.Name), " ")
+		given, family := "", name
+		if index := strings.LastIndex(name, " "); index > 0 {
+			given, family = name[:index], name[index+1:]
+		}
+		converted := crossrefAuthor{Given: given, Family: family}
+		for _, affiliation := range author.Affiliations {
+			converted.Affiliation = append(converted.Affiliation, crossrefAffiliation{Name: affiliation})
+		}
+		authors = append(authors, convertCrossrefAuthor(converted))
+	}
+	keywords := make([]string, 0, len(entry.Categories))
+	for _, category := range entry.Categories {
+		keywords = append(keywords, category.Term)
+	}
+	article := &Article{
+		ID:                doi,
+		Source:            metrics.ServiceArXiv,
+		DOI:               doi,
+		Title:             strings.Join(strings.Fields(entry.Title), " "),
+		AuthorString:      authorString(authors),
+		Authors:           authors,
+		Abstract:          strings.Join(strings.Fields(entry.Summary), " "),
+		Journal:           Journal{Title: "arXiv"},
+		Keywords:          keywords,
+		IsOpenAccess:      true,
+		HasPDF:            true,
+		PubTypes:          []string{PublicationStatusPreprint},
+		PublicationStatus: PublicationStatusPreprint,
+	}
+	if published, err := time.Parse(time.RFC3339, entry.Published); err == nil {
+		date := published.UTC()
+		article.PublishDate = &date
+		article.PubYear = fmt.Sprint(date.Year())
+	}
+	if entry.DOI != "" || entry.JournalRef != "" {
+		article.PublishedVersion = &PublishedVersion{
+			DOI:     strings.TrimSpace(entry.DOI),
+			Journal: strings.Join(strings.Fields(entry.JournalRef), " "),
+		}
+	}
+	return article
+}
@@ -0,0 +1,194 @@
+package literaturetool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bioRxivJSON holds two versions of a bioRxiv preprint, the second one
+// published in a journal.
+const bioRxivJSON = `{
+  "messages": [{"status": "ok"}],
+  "collection": [
+    {"doi": "10.1101/2023.05.01.538912", "title": "Old title", "authors": "Doe, J.", "date": "2023-05-01",
+     "version": "1", "published": "NA", "server": "biorxiv"},
+    {"doi": "10.1101/2023.05.01.538912", "title": "Chemotaxis in Dictyostelium",
+     "authors": "Doe, J. Q.; Roe, J.-L.; dictyBase Consortium", "date": "2023-08-15", "version": "2",
+     "license": "cc_by", "category": "cell biology", "abstract": "Cells  move\n towards cAMP.",
+     "published": "10.1016/j.cell.2024.01.001", "server": "biorxiv"}
+  ]
+}`
+
+// emptyBioRxivJSON is the bioRxiv answer for an unknown DOI.
+const emptyBioRxivJSON = `{"messages": [{"status": "no posts found"}], "collection": []}`
+
+// arXivXML is an arXiv query feed with an article that has a journal
+// reference.
+const arXivXML = `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:arxiv="http://arxiv.org/schemas/atom">
+  <entry>
+    <id>http://arxiv.org/abs/2401.01234v2</id>
+    <published>2024-01-03T18:00:00Z</published>
+    <title>Modeling cAMP waves
+      in social amoebae</title>
+    <summary>  We model cAMP waves. </summary>
+    <author><name>Jane Q. Doe</name><arxiv:affiliation>Northwestern University</arxiv:affiliation></author>
+    <author><name>Roe</name></author>
+    <arxiv:doi>10.1103/PhysRevE.109.014401</arxiv:doi>
+    <arxiv:journal_ref>Phys. Rev. E 109, 014401 (2024)</arxiv:journal_ref>
+    <category term="q-bio.CB"/>
+    <category term="physics.bio-ph"/>
+  </entry>
+</feed>`
+
+// errorArXivXML is the arXiv answer for a malformed ID.
+const errorArXivXML = `<feed xmlns="http://www.w3.org/2005/Atom">
+  <entry><id>http://arxiv.org/api/errors#incorrect_id_format_for_9999</id><title>Error</title></entry>
+</feed>`
+
+func newPreprintServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/details/biorxiv/10.1101/2023.05.01.538912/na/json":
+			_, _ = w.Write([]byte(bioRxivJSON))
+		case "/details/biorxiv/10.1101/2020.03.12.20034660/na/json",
+			"/details/biorxiv/10.1101/123456/na/json",
+			"/details/medrxiv/10.1101/123456/na/json":
+			_, _ = w.Write([]byte(emptyBioRxivJSON))
+		case "/details/medrxiv/10.1101/2020.03.12.20034660/na/json":
+			_, _ = w.Write([]byte(`{"collection": [{"doi": "10.1101/2020.03.12.20034660", "title": "A trial",
+				"authors": "Roe, J.", "date": "2020-03-14", "version": "1", "published": "NA", "server": "medrxiv"}]}`))
+		case "/api/query":
+			switch r.URL.Query().Get("id_list") {
+			case "2401.01234":
+				_, _ = w.Write([]byte(arXivXML))
+			case "9999":
+				_, _ = w.Write([]byte(errorArXivXML))
+			default:
+				http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			}
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func newPreprintTestClient(t *testing.T) *LiteratureClient {
+	t.Helper()
+	server := newPreprintServer(t)
+	client, err := NewLiteratureClient(WithBioRxivURL(server.URL), WithArXivURL(server.URL))
+	require.NoError(t, err)
+	return client
+}
+
+func TestIsPreprintDOI(t *testing.T) {
+	t.Parallel()
+	for doi, want := range map[string]bool{
+		"10.1101/2023.05.01.538912":   true,
+		"10.1101/2023.05.01.538912v2": true,
+		"10.1101/339747":              true,
+		"10.48550/arXiv.2401.01234":   true,
+		"10.48550/ARXIV.2401.01234":   true,
+		"10.1101/gad.1234567":         false,
+		"10.1101/gr.123456.111":       false,
+		"10.1038/nature12373":         false,
+	} {
+		assert.Equal(t, want, IsPreprintDOI(doi), doi)
+	}
+}
+
+func TestPreprintClient_BioRxiv(t *testing.T) {
+	t.Parallel()
+	client := newPreprintTestClient(t)
+
+	article, err := client.GetArticleFromPreprintServer(context.Background(), "10.1101/2023.05.01.538912", IDTypeDOI)
+	require.NoError(t, err)
+	publishDate := time.Date(2023, 8, 15, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &Article{
+		ID:           "10.1101/2023.05.01.538912",
+		Source:       "biorxiv",
+		DOI:          "10.1101/2023.05.01.538912",
+		Title:        "Chemotaxis in Dictyostelium",
+		AuthorString: "Doe JQ, Roe JL, dictyBase Consortium.",
+		Authors: []Author{
+			{FullName: "J. Q. Doe", FirstName: "J. Q.", LastName: "Doe", Initials: "JQ", Affiliations: []Affiliation{}},
+			{FullName: "J.-L. Roe", FirstName: "J.-L.", LastName: "Roe", Initials: "JL", Affiliations: []Affiliation{}},
+			{FullName: "dictyBase Consortium", Affiliations: []Affiliation{}},
+		},
+		Abstract:          "Cells move towards cAMP.",
+		Journal:           Journal{Title: "bioRxiv"},
+		PubYear:           "2023",
+		Keywords:          []string{"cell biology"},
+		IsOpenAccess:      true,
+		License:           "cc_by",
+		PubTypes:          []string{"preprint"},
+		PublishDate:       &publishDate,
+		PublicationStatus: PublicationStatusPreprint,
+		PublishedVersion:  &PublishedVersion{DOI: "10.1016/j.cell.2024.01.001"},
+	}, article)
+}
+
+func TestPreprintClient_MedRxiv(t *testing.T) {
+	t.Parallel()
+	client := newPreprintTestClient(t)
+
+	article, err := client.GetArticleFromPreprintServer(context.Background(), "10.1101/2020.03.12.20034660", IDTypeDOI)
+	require.NoError(t, err)
+	assert.Equal(t, "medRxiv", article.Journal.Title)
+	assert.Equal(t, PublicationStatusPreprint, article.PublicationStatus)
+	assert.Nil(t, article.PublishedVersion)
+}
+
+func TestPreprintClient_ArXiv(t *testing.T) {
+	t.Parallel()
+	client := newPreprintTestClient(t)
+
+	article, err := client.GetArticleFromPreprintServer(context.Background(), "10.48550/arXiv.2401.01234", IDTypeDOI)
+	require.NoError(t, err)
+	assert.Equal(t, "arxiv", article.Source)
+	assert.Equal(t, "Modeling cAMP waves in social amoebae", article.Title)
+	assert.Equal(t, "We model cAMP waves.", article.Abstract)
+	assert.Equal(t, "Doe JQ, Roe.", article.AuthorString)
+	assert.Equal(t, []Affiliation{{Affiliation: "Northwestern University"}}, article.Authors[0].Affiliations)
+	assert.Equal(t, []string{"q-bio.CB", "physics.bio-ph"}, article.Keywords)
+	assert.Equal(t, "2024", article.PubYear)
+	assert.Equal(t, &PublishedVersion{
+		DOI:     "10.1103/PhysRevE.109.014401",
+		Journal: "Phys. Rev. E 109, 014401 (2024)",
+	}, article.PublishedVersion)
+}
+
+func TestPreprintClient_Errors(t *testing.T) {
+	t.Parallel()
+	client := newPreprintTestClient(t)
+
+	tests := []struct {
+		doi       string
+		errorType ErrorType
+		code      string
+	}{
+		{doi: "10.1101/123456", errorType: ErrorTypeArticleNotFound, code: "PREPRINT_NOT_FOUND"},
+		{doi: "10.48550/arXiv.9999", errorType: ErrorTypeArticleNotFound, code: "PREPRINT_NOT_FOUND"},
+		{doi: "10.48550/arXiv.2402.00001", errorType: ErrorTypeAPIError, code: "PREPRINT_API_ERROR"},
+	}
+	for _, testCase := range tests {
+		_, err := client.GetArticleFromPreprintServer(context.Background(), testCase.doi, IDTypeDOI)
+		var litErr *LiteratureError
+		require.True(t, errors.As(err, &litErr), testCase.doi)
+		assert.Equal(t, testCase.errorType, litErr.Type, testCase.doi)
+		assert.Equal(t, testCase.code, litErr.Code, testCase.doi)
+	}
+
+	_, err := client.GetArticleFromPreprintServer(context.Background(), "10.1038/nature12373", IDTypeDOI)
+	require.Error(t, err)
+}
@@ -0,0 +1,344 @@
+package literaturetool
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeProvider serves articles from memory and records its calls.
+type fakeProvider struct {
+	articles map[string]*Article
+	// err fails every call when set.
+	err      error
+	lookups  []string
+	searches []string
+}
+
+// GetArticle implements Provider.
+func (f *fakeProvider) GetArticle(_ context.Context, pmid string) (*Article, error) {
+	f.lookups = append(f.lookups, pmid)
+	if f.err != nil {
+		return nil, f.err
+	}
+	article, ok := f.articles[pmid]
+	if !ok {
+		return nil, errors.New("article not found")
+	}
+	return article, nil
+}
+
+// Search implements Provider, matching queries exactly.
+func (f *fakeProvider) Search(_ context.Context, query string, limit int) ([]*Article, error) {
+	f.searches = append(f.searches, query)
+	if f.err != nil {
+		return nil, f.err
+	}
+	if article, ok := f.articles[query]; ok && limit > 0 {
+		return []*Article{article}, nil
+	}
+	return nil, nil
+}
+
+// recordedEuropePMC replays the recorded EuropePMC search responses in
+// testdata, keyed by query; other queries match nothing.
+func recordedEuropePMC(t *testing.T) *httptest.Server {
+	t.Helper()
+	fixtures := map[string]string{
+		"ext_id:40602797":             "europepmc_40602797.json",
+		"DOI:10.1111/gtc.70037":       "europepmc_40602797.json",
+		"PMCID:PMC12221695":           "europepmc_40602797.json",
+		"EXT_ID:40602797 AND SRC:MED": "europepmc_40602797.json",
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/search", r.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		name, ok := fixtures[r.URL.Query().Get("query")]
+		if !ok {
+			_, _ = w.Write([]byte(`{"version":"6.9","hitCount":0,"resultList":{"result":[]}}`))
+			return
+		}
+		data, err := os.ReadFile(filepath.Join("testdata", name))
+		assert.NoError(t, err)
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestEuropePMCProvider_Recorded(t *testing.T) {
+	t.Parallel()
+	server := recordedEuropePMC(t)
+	pubmed := &fakeProvider{}
+	client, err := NewLiteratureClient(
+		WithEuropePMCURL(server.URL),
+		WithHTTPClient(server.Client()),
+		WithPubMedProvider(pubmed),
+	)
+	require.NoError(t, err)
+
+	for idType, identifier := range map[string]string{
+		IDTypePMID:        "40602797",
+		IDTypeDOI:         "10.1111/gtc.70037",
+		IDTypePMCID:       "PMC12221695",
+		IDTypeEuropePMCID: "MED:40602797",
+	} {
+		article, err := client.GetArticleFromEuropePMC(context.Background(), identifier, idType)
+		require.NoError(t, err, idType)
+		assert.Equal(t, "europepmc", article.Source)
+		assert.Equal(t, "40602797", article.PMID)
+		assert.Equal(t, "PMC12221695", article.PMCID)
+		assert.Equal(t, "10.1111/gtc.70037", article.DOI)
+		assert.Equal(t, "Effect of Retinal on Dictyostelium Cells During Development.", article.Title)
+		assert.Equal(t, "Genes Cells", article.Journal.ISOAbbreviation)
+		require.Len(t, article.Authors, 3)
+		assert.Equal(t, "0000-0003-1573-8967", article.Authors[2].ORCID)
+		assert.True(t, article.IsOpenAccess)
+		assert.Equal(t, PublicationStatusPublished, article.PublicationStatus)
+	}
+
+	_, err = client.GetArticleFromEuropePMC(context.Background(), "10.1000/missing", IDTypeDOI)
+	var litErr *LiteratureError
+	require.ErrorAs(t, err, &litErr)
+	assert.Equal(t, "DOI_NOT_FOUND", litErr.Code)
+	assert.Empty(t, pubmed.lookups, "EuropePMC lookups leave PubMed alone")
+}
+
+func TestGetArticleWithFallback_Providers(t *testing.T) {
+	t.Parallel()
+	pubmedArticle := &Article{ID: "23172289", Source: "pubmed", PMID: "23172289", Title: "dictyBase 2013"}
+	europePMC := &fakeProvider{}
+	pubmed := &fakeProvider{articles: map[string]*Article{"23172289": pubmedArticle}}
+	client, err := NewLiteratureClient(WithEuropePMCProvider(europePMC), WithPubMedProvider(pubmed))
+	require.NoError(t, err)
+
+	article, err := client.GetArticleWithFallback(context.Background(), "23172289", IDTypePMID)
+	require.NoError(t, err)
+	assert.Same(t, pubmedArticle, article, "PubMed answers when EuropePMC does not know the PMID")
+	assert.Equal(t, []string{"23172289"}, europePMC.lookups)
+
+	article, err = client.GetArticleWithFallback(context.Background(), "MED:23172289", IDTypeEuropePMCID)
+	require.NoError(t, err)
+	assert.Same(t, pubmedArticle, article, "MED records fall back to PubMed by PMID")
+	assert.Equal(t, []string{"EXT_ID:23172289 AND SRC:MED"}, europePMC.searches)
+
+	_, err = client.GetArticleWithFallback(context.Background(), "1", IDTypePMID)
+	var litErr *LiteratureError
+	require.ErrorAs(t, err, &litErr)
+	assert.Equal(t, "EUROPEPMC_NOT_FOUND", litErr.Code, "the EuropePMC error is reported when both fail")
+
+	europePMC.err = errors.New("API request failed with status 400")
+	_, err = client.GetArticleFromEuropePMC(context.Background(), "23172289", IDTypePMID)
+	require.ErrorAs(t, err, &litErr)
+	assert.Equal(t, "EUROPEPMC_API_ERROR", litErr.Code)
+}
+
+func TestHandler_Providers(t *testing.T) {
+	t.Parallel()
+	europePMC := &fakeProvider{articles: map[string]*Article{
+		"DOI:10.1093/nar/gks1064": {
+			ID:      "23172289",
+			Source:  "europepmc",
+			PMID:    "23172289",
+			DOI:     "10.1093/nar/gks1064",
+			Title:   "DictyBase 2013: integrating multiple Dictyostelid species.",
+			PubYear: "2013",
+		},
+	}}
+	tool, err := NewLiteratureTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithClientOptions(WithEuropePMCProvider(europePMC), WithPubMedProvider(&fakeProvider{})),
+	)
+	require.NoError(t, err)
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"id":            "https://doi.org/10.1093/nar/gks1064",
+		"id_type":       IDTypeDOI,
+		"output_format": FormatJSON,
+	}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text, ok := result.Content[0].(mcp.TextContent)
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "DictyBase 2013")
+	assert.Equal(t, []string{"DOI:10.1093/nar/gks1064"}, europePMC.searches)
+
+	europePMC.err = errors.New("API request failed with status 400")
+	request.Params.Arguments = map[string]any{"id": "23172289", "id_type": IDTypePMID, "provider": "europepmc"}
+	result, err = tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.True(t, result.IsError)
+	_, ok = result.StructuredContent.(*toolerror.Error)
+	assert.True(t, ok)
+}
+
+// hangingServer answers no request until the client abandons it, and
+// reports each abandoned request path on aborted.
+func hangingServer(t *testing.T, started chan<- struct{}, aborted chan<- string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-r.Context().Done()
+		aborted <- r.URL.Path
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestLiteratureClient_CancelAbortsInFlightRequests(t *testing.T) {
+	t.Parallel()
+	for name, lookup := range map[string]struct {
+		id, idType, path string
+		get              func(*LiteratureClient, context.Context, string, string) (*Article, error)
+	}{
+		"pubmed pmid":    {"23172289", IDTypePMID, "/efetch.fcgi", (*LiteratureClient).GetArticleFromPubMed},
+		"europepmc pmid": {"23172289", IDTypePMID, "/search", (*LiteratureClient).GetArticleFromEuropePMC},
+		"europepmc doi":  {"10.1093/nar/gks1064", IDTypeDOI, "/search", (*LiteratureClient).GetArticleFromEuropePMC},
+	} {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			started, aborted := make(chan struct{}), make(chan string, 1)
+			server := hangingServer(t, started, aborted)
+			client, err := NewLiteratureClient(
+				WithPubMedURL(server.URL),
+				WithEuropePMCURL(server.URL),
+				WithHTTPClient(server.Client()),
+			)
+			require.NoError(t, err)
+
+			ctx, cancel := context.WithCancel(context.Background())
+			go func() {
+				<-started
+				cancel()
+			}()
+			_, err = lookup.get(client, ctx, lookup.id, lookup.idType)
+			require.ErrorIs(t, err, context.Canceled)
+			assert.Equal(t, lookup.path, <-aborted, "the provider request is abandoned, not left running")
+		})
+	}
+}
+
+// hangingProvider answers no call until its context is done.
+type hangingProvider struct {
+	calls int
+}
+
+// GetArticle implements Provider.
+func (h *hangingProvider) GetArticle(ctx context.Context, _ string) (*Article, error) {
+	h.calls++
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// Search implements Provider.
+func (h *hangingProvider) Search(ctx context.Context, _ string, _ int) ([]*Article, error) {
+	h.calls++
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func TestLiteratureClient_RequestTimeout(t *testing.T) {
+	t.Parallel()
+	pubmed := &hangingProvider{}
+	client, err := NewLiteratureClient(
+		WithTimeout(20*time.Millisecond),
+		WithPubMedProvider(pubmed),
+		WithEuropePMCProvider(&fakeProvider{}),
+	)
+	require.NoError(t, err)
+
+	_, err = client.GetArticleFromPubMed(context.Background(), "23172289", IDTypePMID)
+	var litErr *LiteratureError
+	require.ErrorAs(t, err, &litErr)
+	assert.Equal(t, "PUBMED_UNAVAILABLE", litErr.Code, "timed out attempts are retried, then reported as unavailable")
+	assert.Equal(t, ErrorTypeNetworkError, litErr.Type)
+	assert.Contains(t, litErr.Message, "pubmed request timed out after 20ms")
+	assert.Equal(t, retry.Current().MaxAttempts, pubmed.calls)
+}
+
+func TestPubMedProvider_GetArticle(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/efetch.fcgi", r.URL.Path)
+		if r.URL.Query().Get("id") != "23172289" {
+			_, _ = w.Write([]byte(`<PubmedArticleSet></PubmedArticleSet>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<PubmedArticleSet><PubmedArticle>
+<MedlineCitation><PMID>23172289</PMID><Article>
+<Journal><Title>Nucleic acids research</Title></Journal>
+<ArticleTitle>DictyBase 2013: integrating multiple Dictyostelid species.</ArticleTitle>
+<Abstract><AbstractText>dictyBase is the model organism database.</AbstractText></Abstract>
+<AuthorList><Author><LastName>Basu</LastName><ForeName>Siddhartha</ForeName></Author></AuthorList>
+</Article></MedlineCitation>
+<PubmedData><ArticleIdList>
+<ArticleId IdType="pubmed">23172289</ArticleId><ArticleId IdType="doi">10.1093/nar/gks1064</ArticleId>
+</ArticleIdList></PubmedData>
+</PubmedArticle></PubmedArticleSet>`))
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewLiteratureClient(WithPubMedURL(server.URL), WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	article, err := client.GetArticleFromPubMed(context.Background(), "23172289", IDTypePMID)
+	require.NoError(t, err)
+	assert.Equal(t, "pubmed", article.Source)
+	assert.Equal(t, "DictyBase 2013: integrating multiple Dictyostelid species.", article.Title)
+	assert.Equal(t, "10.1093/nar/gks1064", article.DOI)
+	assert.Equal(t, "Nucleic acids research", article.Journal.Title)
+	require.Len(t, article.Authors, 1)
+	assert.Equal(t, "Siddhartha Basu", article.Authors[0].FullName)
+
+	_, err = client.GetArticleFromPubMed(context.Background(), "1", IDTypePMID)
+	var litErr *LiteratureError
+	require.ErrorAs(t, err, &litErr)
+	assert.Equal(t, "PUBMED_NOT_FOUND", litErr.Code)
+}
+
+func TestLiteratureClient_FetchEuropePMC(t *testing.T) {
+	t.Parallel()
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case r.URL.Query().Get("query") == "(":
+			http.Error(w, "syntax error", http.StatusBadRequest)
+		case calls == 1:
+			http.Error(w, "busy", http.StatusServiceUnavailable)
+		default:
+			assert.Equal(t, "/search", r.URL.Path)
+			_, _ = w.Write([]byte(`{"hitCount":7}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewLiteratureClient(WithEuropePMCURL(server.URL), WithHTTPClient(server.Client()))
+	require.NoError(t, err)
+
+	var response struct {
+		HitCount int `json:"hitCount"`
+	}
+	require.NoError(t, client.FetchEuropePMC(context.Background(), "/search?query=dicty", &response))
+	assert.Equal(t, 7, response.HitCount)
+	assert.Equal(t, 2, calls, "the unavailable response is retried")
+
+	err = client.FetchEuropePMC(context.Background(), "/search?query=(", &response)
+	var statusErr *retry.StatusError
+	require.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusBadRequest, statusErr.StatusCode)
+	assert.ErrorContains(t, err, "syntax error")
+	assert.Equal(t, 3, calls, "a rejected request is not retried")
+}
@@ -0,0 +1,169 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/dictybase/literature"
+)
+
+const defaultPubMedURL = "https://eutils.ncbi.nlm.nih.gov/entrez/eutils"
+
+// errPubMedNotFound is returned when PubMed has no record of a PMID.
+var errPubMedNotFound = errors.New("article not found in PubMed")
+
+// pubMedProvider looks up PubMed records with the E-utilities. Records are
+// fetched by PMID directly, since the dictyBase literature library fetches
+// them with a client of its own that cannot be cancelled; searches go
+// through the library with the configured client.
+type pubMedProvider struct {
+	httpClient *http.Client
+	baseURL    string
+}
+
+// newPubMedProvider creates a PubMed provider from the literature client
+// configuration.
+func newPubMedProvider(cfg *Config) *pubMedProvider {
+	return &pubMedProvider{
+		httpClient: cfg.providerClient(),
+		baseURL:    strings.TrimSuffix(cfg.pubMedURL, "/"),
+	}
+}
+
+// pubMedArticleSet is the efetch response for a PMID, holding the fields
+// the literature library reads.
+type pubMedArticleSet struct {
+	Articles []struct {
+		PMID    string `xml:"MedlineCitation>PMID"`
+		Article struct {
+			Journal  string `xml:"Journal>Title"`
+			Title    string `xml:"ArticleTitle"`
+			Abstract string `xml:"Abstract>AbstractText"`
+			Authors  []struct {
+				LastName string `xml:"LastName"`
+				ForeName string `xml:"ForeName"`
+			} `xml:"AuthorList>Author"`
+		} `xml:"MedlineCitation>Article"`
+		ArticleIDs []struct {
+			IDType string `xml:"IdType,attr"`
+			Value  string `xml:",chardata"`
+		} `xml:"PubmedData>ArticleIdList>ArticleId"`
+	} `xml:"PubmedArticle"`
+}
+
+// GetArticle implements Provider.
+func (p *pubMedProvider) GetArticle(ctx context.Context, pmid string) (*Article, error) {
+	query := url.Values{"db": {"pubmed"}, "retmode": {"xml"}, "id": {pmid}}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, p.baseURL+"/efetch.fcgi?"+query.Encode(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating PubMed request: %w", err)
+	}
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error calling PubMed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, fmt.Errorf(
+			"PubMed returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	var set pubMedArticleSet
+	if err := xml.NewDecoder(resp.Body).Decode(&set); err != nil {
+		return nil, fmt.Errorf("error reading PubMed response: %w", err)
+	}
+	if len(set.Articles) == 0 {
+		return nil, fmt.Errorf("%w: %s", errPubMedNotFound, pmid)
+	}
+	record := set.Articles[0]
+	article := &literature.Article{
+		PMID:     record.PMID,
+		Title:    record.Article.Title,
+		Abstract: record.Article.Abstract,
+		Journal:  record.Article.Journal,
+	}
+	for _, id := range record.ArticleIDs {
+		if id.IDType == "doi" {
+			article.DOI = id.Value
+			break
+		}
+	}
+	for _, author := range record.Article.Authors {
+		article.Authors = append(article.Authors, literature.Author{
+			FirstName: author.ForeName,
+			LastName:  author.LastName,
+			FullName:  strings.TrimSpace(author.ForeName + " " + author.LastName),
+		})
+	}
+	return convertPubMedArticle(article), nil
+}
+
+// Search implements Provider. The library builds its requests without a
+// context, so it is given a client bound to ctx for each search.
+func (p *pubMedProvider) Search(ctx context.Context, query string, limit int) ([]*Article, error) {
+	client, err := literature.New(literature.WithHTTPClient(boundClient(ctx, p.httpClient)))
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Search(query, literature.WithLimit(limit))
+	if err != nil {
+		return nil, err
+	}
+	articles := make([]*Article, len(result.Articles))
+	for index, article := range result.Articles {
+		articles[index] = convertPubMedArticle(article)
+	}
+	return articles, nil
+}
+
+// convertPubMedArticle converts a PubMed article to our standard format.
+func convertPubMedArticle(pubmedArticle *literature.Article) *Article {
+	// Convert authors
+	authors := make([]Author, len(pubmedArticle.Authors))
+	for i, author := range pubmedArticle.Authors {
+		authors[i] = Author{
+			FullName:  author.FullName,
+			FirstName: author.FirstName,
+			LastName:  author.LastName,
+		}
+	}
+
+	// Extract year from publish date
+	pubYear := ""
+	if !pubmedArticle.PublishDate.IsZero() {
+		pubYear = fmt.Sprintf("%d", pubmedArticle.PublishDate.Year())
+	}
+
+	return &Article{
+		ID:           pubmedArticle.PMID,
+		Source:       "pubmed",
+		PMID:         pubmedArticle.PMID,
+		DOI:          pubmedArticle.DOI,
+		Title:        pubmedArticle.Title,
+		AuthorString: "", // Will be constructed from authors
+		Authors:      authors,
+		Abstract:     pubmedArticle.Abstract,
+		Journal: Journal{
+			Title:  pubmedArticle.Journal,
+			Volume: pubmedArticle.Volume,
+			Issue:  pubmedArticle.Issue,
+		},
+		PubYear:           pubYear,
+		PageInfo:          pubmedArticle.Pages,
+		Keywords:          pubmedArticle.Keywords,
+		IsOpenAccess:      false,
+		HasPDF:            false,
+		CitedByCount:      0,
+		PublishDate:       &pubmedArticle.PublishDate,
+		PublicationStatus: PublicationStatusPublished,
+	}
+}
@@ -0,0 +1,152 @@
+package literaturetool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+)
+
+// maxReferenceAuthors is the number of authors named in a reference list
+// entry before "et al.".
+const maxReferenceAuthors = 6
+
+// markdownEscaper escapes the characters of titles and journal names that
+// markdown would read as markup.
+var markdownEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	"*", `\*`,
+	"_", `\_`,
+	"[", `\[`,
+	"]", `\]`,
+	"`", "\\`",
+	"<", `\<`,
+)
+
+// CassetteOptions returns the client options replaying or recording
+// provider responses as cassette configures.
+func CassetteOptions(cassette registry.LiteratureCassette) ([]Option, error) {
+	switch {
+	case cassette.Replay != "":
+		replay, err := CassetteFS(cassette.Replay)
+		if err != nil {
+			return nil, err
+		}
+		return []Option{WithReplay(replay)}, nil
+	case cassette.Record != "":
+		return []Option{WithRecording(cassette.Record)}, nil
+	}
+	return nil, nil
+}
+
+// NewCitationResolver returns a resolver looking up the citations of
+// markdown documents with a literature client, replaying or recording
+// provider responses as cassette configures.
+func NewCitationResolver(logger *slog.Logger, cassette registry.LiteratureCassette) (markdown.CitationResolver, error) {
+	opts, err := CassetteOptions(cassette)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewLiteratureClient(append([]Option{
+		WithLogger(logger),
+		WithSemanticScholarAPIKey(os.Getenv("SEMANTIC_SCHOLAR_API_KEY")),
+	}, opts...)...)
+	if err != nil {
+		return nil, err
+	}
+	return client.ResolveCitation, nil
+}
+
+// ResolveCitation looks up a cited publication and renders it as an entry
+// of a reference list.
+func (c *LiteratureClient) ResolveCitation(ctx context.Context, citation markdown.Citation) (string, error) {
+	idType := IDTypePMID
+	if citation.Type == markdown.CitationDOI {
+		idType = IDTypeDOI
+	}
+	article, err := c.GetArticleWithFallback(ctx, citation.ID, idType)
+	if err != nil {
+		return "", err
+	}
+	return renderReference(article), nil
+}
+
+// renderReference renders the article as a markdown reference list entry
+// in the Vancouver style: authors, title, journal, year, volume, issue and
+// pages, followed by links to its DOI and PubMed record.
+func renderReference(article *Article) string {
+	var parts []string
+	if authors := referenceAuthors(article.Authors); authors != "" {
+		parts = append(parts, authors+".")
+	}
+	if article.Title != "" {
+		parts = append(parts, sentence(markdownEscaper.Replace(article.Title)))
+	}
+	journal := article.Journal.ISOAbbreviation
+	if journal == "" {
+		journal = article.Journal.Title
+	}
+	if journal != "" {
+		parts = append(parts, "*"+sentence(markdownEscaper.Replace(journal))+"*")
+	}
+	if source := referenceSource(article); source != "" {
+		parts = append(parts, source+".")
+	}
+	if article.DOI != "" {
+		parts = append(parts, fmt.Sprintf("doi:[%s](https://doi.org/%s)", article.DOI, article.DOI))
+	}
+	if article.PMID != "" {
+		parts = append(parts, fmt.Sprintf("PMID: [%s](%s%s/)", article.PMID, pubmedURL, article.PMID))
+	}
+	return strings.Join(parts, " ")
+}
+
+// referenceAuthors names the authors as "Family Initials", the first
+// maxReferenceAuthors of them followed by "et al." when there are more.
+func referenceAuthors(authors []Author) string {
+	names := make([]string, 0, min(len(authors), maxReferenceAuthors))
+	for _, author := range authors[:cap(names)] {
+		name := author.LastName + " " + author.Initials
+		if author.LastName == "" {
+			name = author.FullName
+		}
+		names = append(names, strings.TrimSpace(name))
+	}
+	if len(authors) > maxReferenceAuthors {
+		names = append(names, "et al")
+	}
+	return strings.Join(names, ", ")
+}
+
+// referenceSource renders the year, volume, issue and pages of the article
+// as "2013;41(D1):D676-82".
+func referenceSource(article *Article) string {
+	var source string
+	if year, _, _ := issued(article); year > 0 {
+		source = fmt.Sprint(year)
+	}
+	volume := article.Journal.Volume
+	if article.Journal.Issue != "" {
+		volume += "(" + article.Journal.Issue + ")"
+	}
+	if volume != "" {
+		source += ";" + volume
+	}
+	if article.PageInfo != "" {
+		source += ":" + article.PageInfo
+	}
+	return strings.TrimPrefix(source, ";")
+}
+
+// sentence ends text with a period unless it ends with punctuation.
+func sentence(text string) string {
+	text = strings.TrimSpace(text)
+	if text == "" || strings.ContainsAny(text[len(text)-1:], ".?!") {
+		return text
+	}
+	return text + "."
+}
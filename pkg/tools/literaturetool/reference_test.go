@@ -0,0 +1,50 @@
+package literaturetool
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRenderReference(t *testing.T) {
+	t.Parallel()
+	article := citationArticle()
+	assert.Equal(t,
+		"Basu S, Fey P, dictyBase Consortium. dictyBase 2013: integrating multiple Dictyostelid species. "+
+			"*Nucleic Acids Res.* 2013;41(D1):D676-83. doi:[10.1093/nar/gks1064](https://doi.org/10.1093/nar/gks1064) "+
+			"PMID: [23172289](https://pubmed.ncbi.nlm.nih.gov/23172289/)",
+		renderReference(article))
+
+	article.Authors = append(article.Authors, article.Authors...)
+	article.Authors = append(article.Authors, article.Authors[0])
+	article.Title = "The *cAR1* gene?"
+	article.Journal = Journal{Title: "Dev Biol"}
+	article.PageInfo = ""
+	article.DOI, article.PMID = "", ""
+	assert.Equal(t,
+		"Basu S, Fey P, dictyBase Consortium, Basu S, Fey P, dictyBase Consortium, et al. "+
+			`The \*cAR1\* gene? *Dev Biol.* 2013.`,
+		renderReference(article))
+}
+
+func TestNewCitationResolver(t *testing.T) {
+	t.Parallel()
+	resolve, err := NewCitationResolver(slog.Default(), registry.LiteratureCassette{Replay: BundledFixtures})
+	require.NoError(t, err)
+
+	text, err := resolve(context.Background(), markdown.Citation{Type: markdown.CitationDOI, ID: "10.1111/gtc.70037"})
+	require.NoError(t, err)
+	assert.Contains(t, text, "Effect of Retinal on Dictyostelium Cells During Development.")
+	assert.Contains(t, text, "PMID: [40602797](https://pubmed.ncbi.nlm.nih.gov/40602797/)")
+
+	_, err = resolve(context.Background(), markdown.Citation{Type: markdown.CitationPMID, ID: "1"})
+	require.ErrorContains(t, err, ErrNotRecorded.Error())
+
+	_, err = NewCitationResolver(slog.Default(), registry.LiteratureCassette{Replay: "/nonexistent"})
+	require.Error(t, err)
+}
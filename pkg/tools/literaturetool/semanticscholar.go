@@ -0,0 +1,215 @@
+package literaturetool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+const (
+	defaultSemanticScholarURL = "https://api.semanticscholar.org/graph/v1"
+	// ProviderSemanticScholar selects Semantic Scholar as the
+	// literature-fetch provider.
+	ProviderSemanticScholar = "semanticscholar"
+	// semanticScholarFields are the paper fields requested from the Graph
+	// API, which only returns paperId and title by default.
+	semanticScholarFields = "paperId,externalIds,title,abstract,venue,year,publicationDate,journal,authors," +
+		"citationCount,influentialCitationCount,fieldsOfStudy,s2FieldsOfStudy,tldr,isOpenAccess," +
+		"openAccessPdf,publicationTypes"
+)
+
+// errSemanticScholarNotFound is returned when Semantic Scholar has no
+// record of a paper.
+var errSemanticScholarNotFound = errors.New("paper not found in Semantic Scholar")
+
+// SemanticScholarClient looks up papers in the Semantic Scholar Graph API,
+// which counts influential citations, classifies papers by field of study
+// and summarizes them in a single generated sentence.
+type SemanticScholarClient struct {
+	httpClient *http.Client
+	baseURL    string
+	apiKey     string
+	logger     *slog.Logger
+}
+
+// newSemanticScholarClient creates a Semantic Scholar client from the
+// literature client configuration.
+func newSemanticScholarClient(cfg *Config) *SemanticScholarClient {
+	return &SemanticScholarClient{
+		httpClient: cfg.client(),
+		baseURL:    strings.TrimSuffix(cfg.semanticScholarURL, "/"),
+		apiKey:     cfg.semanticScholarAPIKey,
+		logger:     cfg.logger,
+	}
+}
+
+// semanticScholarPaper is the part of a Semantic Scholar paper the client
+// reads.
+type semanticScholarPaper struct {
+	PaperID     string `json:"paperId"`
+	ExternalIDs struct {
+		DOI           string `json:"DOI"`
+		PubMed        string `json:"PubMed"`
+		PubMedCentral string `json:"PubMedCentral"`
+	} `json:"externalIds"`
+	Title           string `json:"title"`
+	Abstract        string `json:"abstract"`
+	Venue           string `json:"venue"`
+	Year            int    `json:"year"`
+	PublicationDate string `json:"publicationDate"`
+	Journal         *struct {
+		Name   string `json:"name"`
+		Volume string `json:"volume"`
+		Pages  string `json:"pages"`
+	} `json:"journal"`
+	Authors []struct {
+		Name string `json:"name"`
+	} `json:"authors"`
+	CitationCount            int      `json:"citationCount"`
+	InfluentialCitationCount int      `json:"influentialCitationCount"`
+	FieldsOfStudy            []string `json:"fieldsOfStudy"`
+	S2FieldsOfStudy          []struct {
+		Category string `json:"category"`
+	} `json:"s2FieldsOfStudy"`
+	// TLDR is a one sentence summary generated from the abstract.
+	TLDR *struct {
+		Text string `json:"text"`
+	} `json:"tldr"`
+	IsOpenAccess  bool `json:"isOpenAccess"`
+	OpenAccessPDF *struct {
+		URL     string `json:"url"`
+		License string `json:"license"`
+	} `json:"openAccessPdf"`
+	PublicationTypes []string `json:"publicationTypes"`
+}
+
+// Paper returns the Semantic Scholar record of a DOI, PMID or PMCID. The
+// Graph API takes PMCIDs without their PMC prefix.
+func (c *SemanticScholarClient) Paper(ctx context.Context, identifier, idType string) (*Article, error) {
+	if idType == IDTypePMCID {
+		identifier = strings.TrimPrefix(identifier, "PMC")
+	}
+	paperPath := (&url.URL{Path: "/paper/" + strings.ToUpper(idType) + ":" + identifier}).EscapedPath()
+	endpoint := c.baseURL + paperPath + "?" + url.Values{"fields": {semanticScholarFields}}.Encode()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Semantic Scholar request: %w", err)
+	}
+	if c.apiKey != "" {
+		req.Header.Set("x-api-key", c.apiKey)
+	}
+	start := time.Now()
+	var paper semanticScholarPaper
+	err = c.send(req, &paper)
+	if !errors.Is(err, errSemanticScholarNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceSemanticScholar, start, err)
+	}
+	if err != nil {
+		return nil, err
+	}
+	c.logger.Debug("looked up paper in Semantic Scholar", idType, identifier, "paper", paper.PaperID)
+	return convertSemanticScholarPaper(paper), nil
+}
+
+// send performs the HTTP round trip for Paper.
+func (c *SemanticScholarClient) send(req *http.Request, target any) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Semantic Scholar: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return errSemanticScholarNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"Semantic Scholar returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return fmt.Errorf("error reading Semantic Scholar response: %w", err)
+	}
+	return nil
+}
+
+// convertSemanticScholarPaper converts a Semantic Scholar paper to our
+// standard format. The journal falls back to the venue for conference
+// papers and preprints.
+func convertSemanticScholarPaper(paper semanticScholarPaper) *Article {
+	authors := make([]Author, 0, len(paper.Authors))
+	for _, author := range paper.Authors {
+		authors = append(authors, convertDisplayName(author.Name, ""))
+	}
+	journal := Journal{Title: paper.Venue, YearOfPublication: paper.Year}
+	var pageInfo string
+	if paper.Journal != nil {
+		if paper.Journal.Name != "" {
+			journal.Title = paper.Journal.Name
+		}
+		journal.Volume = strings.TrimSpace(paper.Journal.Volume)
+		pageInfo = strings.Join(strings.Fields(paper.Journal.Pages), "")
+	}
+	article := &Article{
+		ID:                       paper.PaperID,
+		Source:                   ProviderSemanticScholar,
+		PMID:                     paper.ExternalIDs.PubMed,
+		DOI:                      paper.ExternalIDs.DOI,
+		Title:                    paper.Title,
+		AuthorString:             authorString(authors),
+		Authors:                  authors,
+		Abstract:                 strings.Join(strings.Fields(paper.Abstract), " "),
+		Journal:                  journal,
+		PageInfo:                 pageInfo,
+		IsOpenAccess:             paper.IsOpenAccess,
+		CitedByCount:             paper.CitationCount,
+		InfluentialCitationCount: paper.InfluentialCitationCount,
+		PubTypes:                 paper.PublicationTypes,
+		FieldsOfStudy:            semanticScholarFieldsOfStudy(paper),
+		PublicationStatus:        PublicationStatusPublished,
+	}
+	if paper.ExternalIDs.PubMedCentral != "" {
+		article.PMCID = "PMC" + strings.TrimPrefix(paper.ExternalIDs.PubMedCentral, "PMC")
+	}
+	if paper.Year > 0 {
+		article.PubYear = fmt.Sprint(paper.Year)
+	}
+	if date, err := time.Parse(time.DateOnly, paper.PublicationDate); err == nil {
+		article.PublishDate = &date
+	}
+	if paper.OpenAccessPDF != nil && paper.OpenAccessPDF.URL != "" {
+		article.HasPDF = true
+		article.License = paper.OpenAccessPDF.License
+	}
+	if paper.TLDR != nil {
+		article.TLDR = strings.TrimSpace(paper.TLDR.Text)
+	}
+	if IsPreprintDOI(article.DOI) {
+		article.PublicationStatus = PublicationStatusPreprint
+	}
+	return article
+}
+
+// semanticScholarFieldsOfStudy merges the external fields of study with
+// the ones Semantic Scholar's own classifier assigned, without repeats.
+func semanticScholarFieldsOfStudy(paper semanticScholarPaper) []string {
+	fields := slices.Clone(paper.FieldsOfStudy)
+	for _, field := range paper.S2FieldsOfStudy {
+		if !slices.Contains(fields, field.Category) {
+			fields = append(fields, field.Category)
+		}
+	}
+	return fields
+}
@@ -0,0 +1,133 @@
+package literaturetool
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// semanticScholarJSON is a Semantic Scholar paper with influential
+// citations, fields of study and a TLDR.
+const semanticScholarJSON = `{
+  "paperId": "649def34f8be52c8b66281af98ae884c09aef38b",
+  "externalIds": {"DOI": "10.1038/nature12373", "PubMed": "23903748", "PubMedCentral": "3828573"},
+  "title": "Chemotaxis in Dictyostelium",
+  "abstract": "Cells  move\n towards cAMP.",
+  "venue": "Nature",
+  "year": 2013,
+  "publicationDate": "2013-08-01",
+  "journal": {"name": "Nature", "volume": " 500", "pages": "\n          415-21\n        "},
+  "authors": [{"authorId": "1", "name": "Jane Q. Doe"}, {"authorId": null, "name": "dictyBase"}],
+  "citationCount": 120,
+  "influentialCitationCount": 15,
+  "fieldsOfStudy": ["Biology"],
+  "s2FieldsOfStudy": [{"category": "Biology", "source": "external"}, {"category": "Medicine", "source": "s2-fos-model"}],
+  "tldr": {"model": "tldr@v2.0.0", "text": "Cells follow cAMP waves. "},
+  "isOpenAccess": true,
+  "openAccessPdf": {"url": "https://europepmc.org/articles/pmc3828573?pdf=render", "status": "GREEN", "license": "CCBY"},
+  "publicationTypes": ["JournalArticle"]
+}`
+
+func newSemanticScholarTestClient(t *testing.T, apiKey string) *LiteratureClient {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("x-api-key") != apiKey || r.URL.Query().Get("fields") != semanticScholarFields {
+			http.Error(w, "forbidden", http.StatusForbidden)
+			return
+		}
+		switch r.URL.Path {
+		case "/paper/DOI:10.1038/nature12373", "/paper/PMID:23903748", "/paper/PMCID:3828573":
+			_, _ = w.Write([]byte(semanticScholarJSON))
+		case "/paper/PMID:1":
+			http.Error(w, "too many requests", http.StatusTooManyRequests)
+		default:
+			http.Error(w, `{"error": "Paper not found"}`, http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewLiteratureClient(WithSemanticScholarURL(server.URL), WithSemanticScholarAPIKey(apiKey))
+	require.NoError(t, err)
+	return client
+}
+
+func TestSemanticScholarClient_Paper(t *testing.T) {
+	t.Parallel()
+	client := newSemanticScholarTestClient(t, "secret")
+
+	article, err := client.GetArticleFromSemanticScholar(context.Background(), "10.1038/nature12373", IDTypeDOI)
+	require.NoError(t, err)
+	publishDate := time.Date(2013, 8, 1, 0, 0, 0, 0, time.UTC)
+	assert.Equal(t, &Article{
+		ID:           "649def34f8be52c8b66281af98ae884c09aef38b",
+		Source:       "semanticscholar",
+		PMID:         "23903748",
+		PMCID:        "PMC3828573",
+		DOI:          "10.1038/nature12373",
+		Title:        "Chemotaxis in Dictyostelium",
+		AuthorString: "Doe JQ, dictyBase.",
+		Authors: []Author{
+			{FullName: "Jane Q. Doe", FirstName: "Jane Q.", LastName: "Doe", Initials: "JQ", Affiliations: []Affiliation{}},
+			{FullName: "dictyBase", LastName: "dictyBase", Affiliations: []Affiliation{}},
+		},
+		Abstract:                 "Cells move towards cAMP.",
+		Journal:                  Journal{Title: "Nature", Volume: "500", YearOfPublication: 2013},
+		PubYear:                  "2013",
+		PageInfo:                 "415-21",
+		IsOpenAccess:             true,
+		HasPDF:                   true,
+		License:                  "CCBY",
+		CitedByCount:             120,
+		PubTypes:                 []string{"JournalArticle"},
+		PublishDate:              &publishDate,
+		PublicationStatus:        PublicationStatusPublished,
+		InfluentialCitationCount: 15,
+		FieldsOfStudy:            []string{"Biology", "Medicine"},
+		TLDR:                     "Cells follow cAMP waves.",
+	}, article)
+
+	byPMID, err := client.GetArticleFromSemanticScholar(context.Background(), "23903748", IDTypePMID)
+	require.NoError(t, err)
+	assert.Equal(t, article, byPMID)
+
+	byPMCID, err := client.GetArticleFromSemanticScholar(context.Background(), "PMC3828573", IDTypePMCID)
+	require.NoError(t, err)
+	assert.Equal(t, article, byPMCID)
+}
+
+func TestSemanticScholarClient_Errors(t *testing.T) {
+	t.Parallel()
+	client := newSemanticScholarTestClient(t, "")
+
+	tests := []struct {
+		id        string
+		errorType ErrorType
+		code      string
+	}{
+		{id: "99999999", errorType: ErrorTypeArticleNotFound, code: "SEMANTICSCHOLAR_NOT_FOUND"},
+		{id: "1", errorType: ErrorTypeAPIError, code: "SEMANTICSCHOLAR_API_ERROR"},
+	}
+	for _, testCase := range tests {
+		_, err := client.GetArticleFromSemanticScholar(context.Background(), testCase.id, IDTypePMID)
+		var litErr *LiteratureError
+		require.True(t, errors.As(err, &litErr), testCase.id)
+		assert.Equal(t, testCase.errorType, litErr.Type, testCase.id)
+		assert.Equal(t, testCase.code, litErr.Code, testCase.id)
+	}
+}
+
+func TestConvertSemanticScholarPaper_Preprint(t *testing.T) {
+	t.Parallel()
+	var paper semanticScholarPaper
+	paper.ExternalIDs.DOI = "10.1101/2023.05.01.538912"
+	paper.Venue = "bioRxiv"
+	article := convertSemanticScholarPaper(paper)
+	assert.Equal(t, PublicationStatusPreprint, article.PublicationStatus)
+	assert.Equal(t, "bioRxiv", article.Journal.Title)
+	assert.Empty(t, article.PMCID)
+}
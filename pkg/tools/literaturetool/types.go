@@ -1,6 +1,10 @@
 package literaturetool
 
-import "time"
+import (
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+)
 
 // Article represents literature information from various providers.
 type Article struct {
@@ -29,6 +33,89 @@ type Article struct {
 	PublishDate  *time.Time    `json:"publish_date,omitempty"`
 	CreationDate *time.Time    `json:"creation_date,omitempty"`
 	RevisionDate *time.Time    `json:"revision_date,omitempty"`
+	// PublicationStatus tells preprints from published articles.
+	PublicationStatus string `json:"publication_status,omitempty"`
+	// PublishedVersion links a preprint to its journal article, when the
+	// preprint server knows it.
+	PublishedVersion *PublishedVersion `json:"published_version,omitempty"`
+	// Concepts are the OpenAlex topic tags of the article.
+	Concepts []Concept `json:"concepts,omitempty"`
+	// CitationMetrics ranks the citation count against articles of the
+	// same field and year, when the provider computes it.
+	CitationMetrics *CitationMetrics `json:"citation_metrics,omitempty"`
+	// InfluentialCitationCount counts the citing papers Semantic Scholar
+	// found to build on the article rather than only mention it.
+	InfluentialCitationCount int `json:"influential_citation_count,omitempty"`
+	// FieldsOfStudy are the Semantic Scholar fields of the article.
+	FieldsOfStudy []string `json:"fields_of_study,omitempty"`
+	// TLDR is a one sentence summary generated by Semantic Scholar.
+	TLDR string `json:"tldr,omitempty"`
+	// FullTextURLs are the places the full text can be read, as HTML, PDF
+	// or through the DOI.
+	FullTextURLs []FullTextURL `json:"full_text_urls,omitempty"`
+	// SupplementaryFiles are the files published with an open access
+	// article, set when data links are requested.
+	SupplementaryFiles []SupplementaryFile `json:"supplementary_files,omitempty"`
+	// DataCitations are the dataset accessions cited by the article, such
+	// as GEO series, SRA runs and PDB structures, set when data links are
+	// requested.
+	DataCitations []DataCitation `json:"data_citations,omitempty"`
+	// FieldSources names the provider of every field of a merged article,
+	// keyed by the field's JSON name; fields of the journal are prefixed
+	// with "journal.".
+	FieldSources map[string]string `json:"field_sources,omitempty"`
+}
+
+// Concept is a topic an article was tagged with. Level 0 concepts are the
+// broadest fields; higher levels are increasingly specific.
+type Concept struct {
+	Name  string  `json:"name"`
+	Level int     `json:"level"`
+	Score float64 `json:"score"`
+}
+
+// CitationMetrics describes how often an article is cited relative to
+// articles of the same field and year.
+type CitationMetrics struct {
+	// Percentile is the citation percentile, from 0 to 100.
+	Percentile    float64 `json:"percentile"`
+	TopOnePercent bool    `json:"top_one_percent"`
+	TopTenPercent bool    `json:"top_ten_percent"`
+	// FWCI is the field-weighted citation impact; 1 is the field average.
+	FWCI float64 `json:"fwci,omitempty"`
+}
+
+// FullTextURL is a place the full text of an article can be read.
+type FullTextURL struct {
+	URL  string `json:"url"`
+	Site string `json:"site,omitempty"`
+	// Style is the document style: html, pdf or doi.
+	Style string `json:"style,omitempty"`
+	// Availability tells open access, free and subscription copies apart.
+	Availability string `json:"availability,omitempty"`
+}
+
+// SupplementaryFile is a file published with an article, such as a data
+// table or a movie.
+type SupplementaryFile struct {
+	Label    string `json:"label,omitempty"`
+	Caption  string `json:"caption,omitempty"`
+	URL      string `json:"url"`
+	MimeType string `json:"mime_type,omitempty"`
+}
+
+// DataCitation is a dataset an article cites by accession.
+type DataCitation struct {
+	// Database is the archive holding the dataset, such as GEO or PDB.
+	Database  string `json:"database"`
+	Accession string `json:"accession"`
+	URL       string `json:"url,omitempty"`
+}
+
+// PublishedVersion is the journal article a preprint was published as.
+type PublishedVersion struct {
+	DOI     string `json:"doi,omitempty"`
+	Journal string `json:"journal,omitempty"`
 }
 
 // Author represents author information.
@@ -42,8 +129,13 @@ type Author struct {
 }
 
 // Affiliation represents author affiliation information.
+// Institution, ROR and CountryCode are set when the provider resolved the
+// affiliation to an institution.
 type Affiliation struct {
 	Affiliation string `json:"affiliation"`
+	Institution string `json:"institution,omitempty"`
+	ROR         string `json:"ror,omitempty"`
+	CountryCode string `json:"country_code,omitempty"`
 }
 
 // Journal represents journal information.
@@ -88,24 +180,16 @@ type Grant struct {
 	OrderIn int    `json:"order_in"`
 }
 
-// LiteratureError represents errors from literature API operations.
-type LiteratureError struct {
-	Type    ErrorType `json:"type"`
-	Message string    `json:"message"`
-	Code    string    `json:"code,omitempty"`
-}
+// LiteratureError represents errors from literature API operations. It is
+// the error type shared by all tools.
+type LiteratureError = toolerror.Error
 
 // ErrorType represents different types of literature API errors.
-type ErrorType string
+type ErrorType = toolerror.Type
 
 const (
-	ErrorTypeInvalidInput    ErrorType = "invalid_input"
-	ErrorTypeArticleNotFound ErrorType = "article_not_found"
-	ErrorTypeNetworkError    ErrorType = "network_error"
-	ErrorTypeAPIError        ErrorType = "api_error"
+	ErrorTypeInvalidInput    = toolerror.TypeInvalidInput
+	ErrorTypeArticleNotFound = toolerror.TypeNotFound
+	ErrorTypeNetworkError    = toolerror.TypeNetworkError
+	ErrorTypeAPIError        = toolerror.TypeAPIError
 )
-
-// Error implements the error interface.
-func (e *LiteratureError) Error() string {
-	return e.Message
-}
@@ -2,11 +2,14 @@ package markdowntool
 
 import (
 	"context"
-	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
 	"github.com/mark3labs/mcp-go/mcp"
 )
 
@@ -15,29 +18,120 @@ type MarkdownTool struct {
 	Name        string
 	Description string
 	Tool        mcp.Tool
-	Logger      *log.Logger
+	Logger      *slog.Logger
+	resources   *resources.Catalog
+	uploads     *upload.Store
+	citations   markdown.CitationResolver
+	glossary    markdown.Glossary
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"markdown",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			markdownTool, err := NewMarkdownTool(
+				deps.Logger,
+				WithResources(deps.Resources),
+				WithUploads(deps.Uploads),
+				WithCitations(deps.Citations),
+				WithGlossary(deps.Glossary),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return markdownTool, nil
+		},
+	)
+}
+
+// Option defines a functional option for configuring MarkdownTool.
+type Option func(*MarkdownTool)
+
+// WithResources publishes rendered HTML as MCP resources in the catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(m *MarkdownTool) {
+		m.resources = catalog
+	}
+}
+
+// WithUploads lets calls pass the markdown as a completed upload.
+func WithUploads(store *upload.Store) Option {
+	return func(m *MarkdownTool) {
+		m.uploads = store
+	}
+}
+
+// WithCitations expands the [@pmid:...] and [@doi:...] citations of the
+// markdown into numbered references, looking them up with resolve.
+func WithCitations(resolve markdown.CitationResolver) Option {
+	return func(m *MarkdownTool) {
+		m.citations = resolve
+	}
+}
+
+// WithGlossary links the terms of glossary in the markdown of calls that
+// ask for term links.
+func WithGlossary(glossary markdown.Glossary) Option {
+	return func(m *MarkdownTool) {
+		m.glossary = glossary
+	}
 }
 
 // NewMarkdownTool creates a new MarkdownTool instance.
-func NewMarkdownTool(logger *log.Logger) (*MarkdownTool, error) {
+func NewMarkdownTool(logger *slog.Logger, opts ...Option) (*MarkdownTool, error) {
 	// Create the tool with proper schema
 	tool := mcp.NewTool(
 		"markdown",
 		mcp.WithDescription(
 			"Converts markdown to HTML with support for GFM, syntax highlighting, and more",
 		),
+		mcp.WithTitleAnnotation("Markdown to HTML"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithString(
 			"content",
-			mcp.Description("The markdown content to convert to HTML"),
-			mcp.Required(),
+			mcp.Description(
+				"The markdown content to convert to HTML. Citations such as [@pmid:23172289] or "+
+					"[@doi:10.1093/nar/gks1064] become numbered references when the server resolves them",
+			),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the markdown, instead of content"),
+		),
+		mcp.WithBoolean(
+			"link_terms",
+			mcp.Description(
+				"Link dictyBase gene IDs such as DDB_G0273397 to their gene pages, and the terms of "+
+					"the server's glossary to their pages. Defaults to false",
+			),
+		),
+		mcp.WithString(
+			"glossary",
+			mcp.Description(
+				"Comma-separated term=page pairs to link, added to the server's glossary, e.g. "+
+					"carA=DDB_G0273397,cAMP=https://en.wikipedia.org/wiki/CAMP. A page may be a "+
+					"dictyBase gene ID. Implies link_terms",
+			),
 		),
 	)
-	return &MarkdownTool{
+	markdownTool := &MarkdownTool{
 		Name:        "markdown",
 		Description: "Converts markdown to HTML with support for GFM, syntax highlighting, and more",
 		Tool:        tool,
 		Logger:      logger,
-	}, nil
+	}
+	for _, opt := range opts {
+		opt(markdownTool)
+	}
+	if markdownTool.citations != nil {
+		// Citations are looked up with literature providers.
+		markdownTool.Tool.Annotations.OpenWorldHint = mcp.ToBoolPtr(true)
+	}
+	return markdownTool, nil
 }
 
 // GetName returns the name of the tool.
@@ -60,20 +154,86 @@ func (m *MarkdownTool) GetTool() mcp.Tool {
 	return m.Tool
 }
 
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (m *MarkdownTool) GetAnnotations() mcp.ToolAnnotation {
+	return m.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (m *MarkdownTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Render a short markdown document as HTML",
+			Arguments: map[string]any{
+				"content": "# Release notes\n\n- Added strain search\n- Fixed *GO* annotation export",
+			},
+		},
+	}
+}
+
 // Handler returns a function that handles tool execution requests.
 func (m *MarkdownTool) Handler(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
-	args := request.GetArguments()
-	contentVal, ok := args["content"].(string)
-	if !ok {
-		return nil, errors.New("missing required parameter: content")
+	contentVal, err := upload.TextArgument(m.uploads, request, "content")
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	contentVal, err = m.linkTerms(request, contentVal)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	if m.citations != nil {
+		var references []markdown.Reference
+		contentVal, references, err = markdown.ExpandCitations(ctx, contentVal, m.citations)
+		if err != nil {
+			return toolerror.Result(err), nil
+		}
+		for _, reference := range references {
+			if reference.Unresolved {
+				m.Logger.Warn("citation not found", "type", reference.Citation.Type, "id", reference.Citation.ID)
+			}
+		}
 	}
 	parser := markdown.NewParser()
 	html, err := parser.ParseString(contentVal)
 	if err != nil {
-		return nil, fmt.Errorf("failed to parse markdown: %w", err)
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_MARKDOWN",
+			err,
+			"failed to parse markdown",
+		)), nil
+	}
+	result := mcp.NewToolResultText(html)
+	if m.resources != nil {
+		resource, err := m.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindHTML,
+			Name:        resources.ContentName("document", ".html", []byte(html)),
+			MIMEType:    "text/html",
+			Description: "HTML rendered from markdown",
+			Data:        []byte(html),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("failed to publish HTML: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// linkTerms links the gene IDs and glossary terms of content when the
+// request asks for term links.
+func (m *MarkdownTool) linkTerms(request mcp.CallToolRequest, content string) (string, error) {
+	pairs := request.GetString("glossary", "")
+	if !request.GetBool("link_terms", false) && pairs == "" {
+		return content, nil
+	}
+	glossary, err := markdown.ParseGlossary(pairs)
+	if err != nil {
+		return "", toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_GLOSSARY", err, "invalid glossary")
 	}
-	return mcp.NewToolResultText(html), nil
+	return markdown.LinkTerms(content, m.glossary.Merge(glossary)), nil
 }
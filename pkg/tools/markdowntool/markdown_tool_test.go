@@ -2,30 +2,38 @@ package markdowntool
 
 import (
 	"context"
-	"log"
+	"log/slog"
 	"os"
 	"testing"
 
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
 	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
 	"github.com/stretchr/testify/require"
 )
 
 func TestNewMarkdownTool(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
-	logger := log.New(os.Stderr, "", 0)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewMarkdownTool(logger)
 	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
 	requireHelper.NotNil(tool, "Tool should not be nil")
 	requireHelper.Equal("markdown", tool.GetName(), "Tool name should be 'markdown'")
 	requireHelper.NotNil(tool.GetSchema(), "Tool schema should not be nil")
+	annotations := tool.GetAnnotations()
+	requireHelper.True(*annotations.ReadOnlyHint, "markdown should be read-only")
+	requireHelper.False(*annotations.OpenWorldHint, "markdown should not reach the network")
 }
 
 func TestHandler(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
-	logger := log.New(os.Stderr, "", 0)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewMarkdownTool(logger)
 	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
@@ -54,6 +62,135 @@ func TestHandler(t *testing.T) {
 	invalidRequest.Params.Name = "markdown"
 	invalidRequest.Params.Arguments = map[string]interface{}{}
 
-	_, err = tool.Handler(context.Background(), invalidRequest)
-	requireHelper.Error(err, "Handler should return an error for invalid request")
+	result, err = tool.Handler(context.Background(), invalidRequest)
+	requireHelper.NoError(err, "Handler should report failures as results")
+	requireHelper.True(result.IsError, "Result should be an error for invalid request")
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	requireHelper.True(ok, "Error result should carry a structured error")
+	requireHelper.Equal(toolerror.TypeInvalidInput, toolErr.Type)
+}
+
+func TestHandlerPublishesResource(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	mcpServer := server.NewMCPServer("test", "1.0.0")
+
+	tool, err := NewMarkdownTool(
+		logger,
+		WithResources(resources.NewCatalog(mcpServer)),
+	)
+	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "markdown"
+	request.Params.Arguments = map[string]interface{}{
+		"content": "# Published",
+	}
+	result, err := tool.Handler(context.Background(), request)
+	requireHelper.NoError(err, "Handler should not return an error")
+	requireHelper.Len(result.Content, 2, "Result should link the published resource")
+
+	link, ok := result.Content[1].(mcp.ResourceLink)
+	requireHelper.True(ok, "Second content item should be a resource link")
+	requireHelper.Equal("text/html", link.MIMEType)
+	requireHelper.Regexp(`^dcr://html/document-[0-9a-f]+\.html$`, link.URI)
+}
+
+func TestHandler_Upload(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	store := upload.NewStore()
+	info, err := store.Begin(upload.BeginParams{ContentType: "text/markdown"})
+	requireHelper.NoError(err)
+	_, err = store.Append(upload.AppendParams{ID: info.ID, Data: []byte("# Uploaded")})
+	requireHelper.NoError(err)
+	_, err = store.Complete(info.ID, "")
+	requireHelper.NoError(err)
+
+	tool, err := NewMarkdownTool(logger, WithUploads(store))
+	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "markdown"
+	request.Params.Arguments = map[string]interface{}{"upload_id": info.ID}
+	result, err := tool.Handler(context.Background(), request)
+	requireHelper.NoError(err, "Handler should not return an error")
+	requireHelper.False(result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	requireHelper.True(ok)
+	requireHelper.Contains(text.Text, "Uploaded</h1>")
+
+	request.Params.Arguments = map[string]interface{}{"upload_id": "upl_unknown"}
+	result, err = tool.Handler(context.Background(), request)
+	requireHelper.NoError(err, "Handler should not return an error")
+	requireHelper.True(result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	requireHelper.True(ok)
+	requireHelper.Equal("UPLOAD_NOT_FOUND", toolErr.Code)
+}
+
+func TestHandler_Citations(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	resolve := func(_ context.Context, citation markdown.Citation) (string, error) {
+		return "Fey P, et al. dictyBase. PMID " + citation.ID + ".", nil
+	}
+
+	tool, err := NewMarkdownTool(logger, WithCitations(resolve))
+	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
+	requireHelper.True(*tool.GetAnnotations().OpenWorldHint, "citations are looked up with literature providers")
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "markdown"
+	request.Params.Arguments = map[string]interface{}{
+		"content": "dictyBase hosts the genome [@pmid:23172289].",
+	}
+	result, err := tool.Handler(context.Background(), request)
+	requireHelper.NoError(err, "Handler should not return an error")
+	requireHelper.False(result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	requireHelper.True(ok)
+	requireHelper.Contains(text.Text, `[<a href="#references">1</a>]`)
+	requireHelper.Contains(text.Text, `<h2 id="references">References</h2>`)
+	requireHelper.Contains(text.Text, "<li>Fey P, et al. dictyBase. PMID 23172289.</li>")
+}
+
+func TestHandler_LinkTerms(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewMarkdownTool(logger, WithGlossary(markdown.Glossary{"carA": "DDB_G0273397"}))
+	requireHelper.NoError(err, "NewMarkdownTool should not return an error")
+
+	call := func(args map[string]any) *mcp.CallToolResult {
+		request := mcp.CallToolRequest{}
+		request.Params.Name = "markdown"
+		request.Params.Arguments = args
+		result, err := tool.Handler(context.Background(), request)
+		requireHelper.NoError(err, "Handler should not return an error")
+		return result
+	}
+	content := "carA (DDB_G0273397) binds cAMP."
+
+	text, ok := mcp.AsTextContent(call(map[string]any{"content": content}).Content[0])
+	requireHelper.True(ok)
+	requireHelper.NotContains(text.Text, "<a ", "terms are linked only on request")
+
+	text, ok = mcp.AsTextContent(call(map[string]any{"content": content, "link_terms": true}).Content[0])
+	requireHelper.True(ok)
+	requireHelper.Contains(text.Text, `<a href="https://dictybase.org/gene/DDB_G0273397">carA</a>`)
+	requireHelper.Contains(text.Text, `<a href="https://dictybase.org/gene/DDB_G0273397">DDB_G0273397</a>`)
+	requireHelper.NotContains(text.Text, `>cAMP</a>`)
+
+	text, ok = mcp.AsTextContent(call(map[string]any{
+		"content":  content,
+		"glossary": "cAMP=https://en.wikipedia.org/wiki/CAMP",
+	}).Content[0])
+	requireHelper.True(ok)
+	requireHelper.Contains(text.Text, `<a href="https://en.wikipedia.org/wiki/CAMP">cAMP</a>`)
+	requireHelper.Contains(text.Text, `>carA</a>`, "the call's glossary adds to the server's")
+
+	result := call(map[string]any{"content": content, "glossary": "cAMP"})
+	requireHelper.True(result.IsError)
 }
@@ -0,0 +1,315 @@
+package nltool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// toolName is the name run-nl is served under.
+const toolName = "run-nl"
+
+// Initialize validator.
+var validate = validator.New()
+
+// NLTool carries out natural-language instructions by letting the language
+// model choose one of the served tools and its arguments.
+type NLTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	invoker     registry.Invoker
+	planner     Planner
+}
+
+// Option defines a functional option for configuring NLTool.
+type Option func(*NLTool)
+
+// WithInvoker sets the catalog of tools instructions are carried out with.
+func WithInvoker(invoker registry.Invoker) Option {
+	return func(n *NLTool) {
+		n.invoker = invoker
+	}
+}
+
+// WithPlanner replaces the language model client that chooses tool calls,
+// which is otherwise created from OPENAI_API_KEY on every call.
+func WithPlanner(planner Planner) Option {
+	return func(n *NLTool) {
+		n.planner = planner
+	}
+}
+
+// NLRequest represents the parameters of a run-nl call.
+type NLRequest struct {
+	Instruction string `validate:"required,max=4000"`
+	// DryRun returns the plan without running the chosen tool.
+	DryRun bool
+}
+
+// Run is the outcome of an instruction: the planned call and, unless the
+// call was a dry run, the result of the tool.
+type Run struct {
+	Instruction string   `json:"instruction"`
+	Plan        Plan     `json:"plan"`
+	Executed    bool     `json:"executed"`
+	Result      *Outcome `json:"result,omitempty"`
+}
+
+// Outcome is the result of the tool a plan called.
+type Outcome struct {
+	IsError bool `json:"is_error"`
+	// Structured is the structured content of the result, if any.
+	Structured any `json:"structured,omitempty"`
+	// Text is the text content of results without structured content.
+	Text string `json:"text,omitempty"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		toolName,
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewNLTool(deps.Logger, WithInvoker(deps.Tools))
+		},
+	)
+}
+
+// NewNLTool creates a new NLTool instance.
+func NewNLTool(logger *slog.Logger, opts ...Option) (*NLTool, error) {
+	tool := mcp.NewTool(
+		toolName,
+		mcp.WithDescription(
+			"Carries out a plain-language instruction such as \"summarize last week's commits on "+
+				"dictybase/modware-stock develop\": the language model picks the matching tool and "+
+				"its arguments, which are checked against the tool's schema before it runs. "+
+				"Returns the plan together with the tool's result; requires OPENAI_API_KEY",
+		),
+		mcp.WithTitleAnnotation("Run Instruction"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"instruction",
+			mcp.Required(),
+			mcp.Description("What to do, in plain language, with the repository URLs, "+
+				"identifiers and dates the tool needs"),
+		),
+		mcp.WithBoolean(
+			"dry_run",
+			mcp.Description("Only plan the tool call without running it (defaults to false)"),
+		),
+	)
+	nlTool := &NLTool{
+		Name:        toolName,
+		Description: "Carries out natural-language instructions with the served tools",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(nlTool)
+	}
+	return nlTool, nil
+}
+
+// GetName returns the name of the tool.
+func (n *NLTool) GetName() string {
+	return n.Name
+}
+
+// GetDescription returns the description of the tool.
+func (n *NLTool) GetDescription() string {
+	return n.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (n *NLTool) GetSchema() mcp.ToolInputSchema {
+	return n.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (n *NLTool) GetTool() mcp.Tool {
+	return n.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (n *NLTool) GetAnnotations() mcp.ToolAnnotation {
+	return n.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (n *NLTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize recent work without knowing the tool's parameters",
+			Arguments: map[string]any{
+				"instruction": "Summarize what was done on the develop branch of " +
+					"https://github.com/dictybase/modware-stock last week",
+			},
+		},
+		{
+			Description: "Check which tool and arguments an instruction maps to",
+			Arguments: map[string]any{
+				"instruction": "Find recent papers on chemotaxis in Dictyostelium",
+				"dry_run":     true,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (n *NLTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := NLRequest{
+		Instruction: strings.TrimSpace(request.GetString("instruction", "")),
+		DryRun:      request.GetBool("dry_run", false),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	if n.invoker == nil {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeConfiguration,
+			"TOOLS_UNAVAILABLE",
+			"run-nl can only run within the server, which provides the tools it calls",
+		)), nil
+	}
+	planner := n.planner
+	if planner == nil {
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return toolerror.Result(toolerror.New(
+				toolerror.TypeConfiguration,
+				"MISSING_OPENAI_API_KEY",
+				"OPENAI_API_KEY is not set on the server",
+			)), nil
+		}
+		client, err := worksummary.NewOpenAIClient(apiKey)
+		if err != nil {
+			return toolerror.Result(toolerror.Wrap(
+				toolerror.TypeConfiguration,
+				"OPENAI_CLIENT",
+				err,
+				"error initializing OpenAI client",
+			)), nil
+		}
+		planner = client
+	}
+	run, result, err := n.Run(ctx, planner, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	return newResult(run, result, planner.Model()), nil
+}
+
+// Run plans the tool call of params and runs it unless params asks for a
+// dry run. The result of the tool is nil for dry runs.
+func (n *NLTool) Run(
+	ctx context.Context,
+	planner Planner,
+	params NLRequest,
+) (Run, *mcp.CallToolResult, error) {
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 2, "choosing a tool")
+	planned, err := plan(ctx, planner, n.candidates(), params.Instruction)
+	if err != nil {
+		var rejected *UnplannableError
+		if errors.As(err, &rejected) {
+			return Run{}, nil, toolerror.Wrap(
+				toolerror.TypeInvalidInput,
+				"UNPLANNABLE_INSTRUCTION",
+				err,
+				"could not turn the instruction into a valid tool call; rephrase it with the missing details",
+			)
+		}
+		return Run{}, nil, toolerror.Upstream(metrics.ServiceOpenAI, err, "failed to choose a tool")
+	}
+	logging.WithRequestID(n.Logger).Info(
+		"planned tool call",
+		"tool", planned.Tool,
+		"attempts", planned.Attempts,
+		"dry_run", params.DryRun,
+	)
+	run := Run{Instruction: params.Instruction, Plan: planned}
+	if params.DryRun {
+		reporter.Report(2, 2, "planned "+planned.Tool)
+		return run, nil, nil
+	}
+	reporter.Report(1, 2, "running "+planned.Tool)
+	result, err := n.invoker.CallTool(ctx, planned.Tool, planned.Arguments)
+	if err != nil {
+		return Run{}, nil, fmt.Errorf("error running %s: %w", planned.Tool, err)
+	}
+	reporter.Report(2, 2, "ran "+planned.Tool)
+	run.Executed = true
+	run.Result = newOutcome(result)
+	return run, result, nil
+}
+
+// candidates returns the tools instructions may be carried out with: every
+// served tool but run-nl and run-batch, which would run tools of their own.
+func (n *NLTool) candidates() []mcp.Tool {
+	return slices.DeleteFunc(n.invoker.Tools(), func(tool mcp.Tool) bool {
+		return registry.IsOrchestrator(tool.Name)
+	})
+}
+
+// newOutcome summarizes the result of a tool for the structured content
+// of run-nl.
+func newOutcome(result *mcp.CallToolResult) *Outcome {
+	outcome := &Outcome{IsError: result.IsError, Structured: result.StructuredContent}
+	if outcome.Structured == nil {
+		outcome.Text = textContent(result)
+	}
+	return outcome
+}
+
+// textContent joins the text content of result.
+func textContent(result *mcp.CallToolResult) string {
+	var texts []string
+	for _, content := range result.Content {
+		if text, ok := content.(mcp.TextContent); ok {
+			texts = append(texts, text.Text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// newResult builds the result of run-nl: the plan in front of the content
+// of the tool's result, which keeps its error flag. The provenance lists
+// the language model next to the services the tool used.
+func newResult(run Run, result *mcp.CallToolResult, model string) *mcp.CallToolResult {
+	combined := mcp.NewToolResultStructured(run, RenderMarkdown(run))
+	providers := []string{metrics.ServiceOpenAI}
+	if result != nil {
+		combined.Content = append(combined.Content, result.Content...)
+		combined.IsError = result.IsError
+		if record, ok := provenance.FromResult(result); ok {
+			for _, provider := range record.Providers {
+				if !slices.Contains(providers, provider) {
+					providers = append(providers, provider)
+				}
+			}
+		}
+	}
+	return provenance.Attach(combined, provenance.New(providers, provenance.WithModel(model)))
+}
@@ -0,0 +1,156 @@
+package nltool
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeInvoker serves git-summary, run-nl and run-batch and records the
+// calls it got.
+type fakeInvoker struct {
+	calls []map[string]any
+}
+
+func (f *fakeInvoker) Tools() []mcp.Tool {
+	tool, err := NewNLTool(slog.Default())
+	if err != nil {
+		panic(err)
+	}
+	return []mcp.Tool{summaryTool(), tool.GetTool(), mcp.NewTool("run-batch")}
+}
+
+func (f *fakeInvoker) CallTool(_ context.Context, name string, args map[string]any) (*mcp.CallToolResult, error) {
+	if name != "git-summary" {
+		return nil, errors.New("unknown tool")
+	}
+	f.calls = append(f.calls, args)
+	return provenance.Attach(
+		mcp.NewToolResultText("# Work Summary"),
+		provenance.New([]string{metrics.ServiceGitClone}),
+	), nil
+}
+
+// callRequest builds a run-nl request with args.
+func callRequest(args map[string]any) mcp.CallToolRequest {
+	return mcp.CallToolRequest{Params: mcp.CallToolParams{Name: toolName, Arguments: args}}
+}
+
+// summaryPlanner answers every instruction with a valid git-summary call.
+func summaryPlanner() *fakePlanner {
+	return &fakePlanner{calls: []worksummary.FunctionCall{{
+		Name:      "git-summary",
+		Arguments: `{"repo_url":"https://github.com/a/b","branch":"develop"}`,
+		Reasoning: "It summarizes commits.",
+	}}}
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	invoker := &fakeInvoker{}
+	planner := summaryPlanner()
+	tool, err := NewNLTool(slog.Default(), WithInvoker(invoker), WithPlanner(planner))
+	require.NoError(t, err)
+
+	result, err := tool.Handler(context.Background(), callRequest(map[string]any{
+		"instruction": "summarize last week on a/b develop",
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	require.Len(t, invoker.calls, 1)
+	assert.Equal(t, map[string]any{"repo_url": "https://github.com/a/b", "branch": "develop"}, invoker.calls[0])
+	require.Len(t, planner.functions, 1, "run-nl never plans a call of run-nl or run-batch")
+	assert.Equal(t, "git-summary", planner.functions[0].Name)
+
+	run, ok := result.StructuredContent.(Run)
+	require.True(t, ok)
+	assert.True(t, run.Executed)
+	assert.Equal(t, "git-summary", run.Plan.Tool)
+	assert.Equal(t, &Outcome{Text: "# Work Summary"}, run.Result)
+	require.Len(t, result.Content, 2)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "# Plan: git-summary")
+	assert.Equal(t, "# Work Summary", result.Content[1].(mcp.TextContent).Text)
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{metrics.ServiceOpenAI, metrics.ServiceGitClone}, record.Providers)
+	assert.Equal(t, "test-model", record.Model)
+}
+
+func TestHandler_DryRun(t *testing.T) {
+	t.Parallel()
+	invoker := &fakeInvoker{}
+	tool, err := NewNLTool(slog.Default(), WithInvoker(invoker), WithPlanner(summaryPlanner()))
+	require.NoError(t, err)
+
+	result, err := tool.Handler(context.Background(), callRequest(map[string]any{
+		"instruction": "summarize last week on a/b develop",
+		"dry_run":     true,
+	}))
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	assert.Empty(t, invoker.calls)
+	run, ok := result.StructuredContent.(Run)
+	require.True(t, ok)
+	assert.False(t, run.Executed)
+	assert.Nil(t, run.Result)
+	assert.Contains(t, result.Content[0].(mcp.TextContent).Text, "Dry run")
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		opts []Option
+		args map[string]any
+		code string
+	}{
+		{
+			name: "missing instruction",
+			opts: []Option{WithInvoker(&fakeInvoker{}), WithPlanner(summaryPlanner())},
+			args: map[string]any{},
+			code: "INVALID_INPUT",
+		},
+		{
+			name: "outside the server",
+			opts: []Option{WithPlanner(summaryPlanner())},
+			args: map[string]any{"instruction": "summarize a/b"},
+			code: "TOOLS_UNAVAILABLE",
+		},
+		{
+			name: "invalid call",
+			opts: []Option{WithInvoker(&fakeInvoker{}), WithPlanner(&fakePlanner{calls: []worksummary.FunctionCall{
+				{Name: "git-summary", Arguments: `{"branch":"develop"}`},
+			}})},
+			args: map[string]any{"instruction": "summarize develop"},
+			code: "UNPLANNABLE_INSTRUCTION",
+		},
+		{
+			name: "model failure",
+			opts: []Option{WithInvoker(&fakeInvoker{}), WithPlanner(&fakePlanner{err: errors.New("boom")})},
+			args: map[string]any{"instruction": "summarize a/b"},
+			code: "OPENAI_API_ERROR",
+		},
+	}
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+			tool, err := NewNLTool(slog.Default(), tc.opts...)
+			require.NoError(t, err)
+			result, err := tool.Handler(context.Background(), callRequest(tc.args))
+			require.NoError(t, err)
+			require.True(t, result.IsError)
+			toolErr, ok := result.StructuredContent.(*toolerror.Error)
+			require.True(t, ok)
+			assert.Equal(t, tc.code, toolErr.Code)
+		})
+	}
+}
@@ -0,0 +1,247 @@
+package nltool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// maxAttempts is how often the model may call a tool before the
+// instruction is given up on; each retry shows it what was wrong.
+const maxAttempts = 2
+
+// systemPrompt tells the model how to turn an instruction into a tool call.
+const systemPrompt = `
+You route requests from members of the dictyBase lab to the tools of the
+dcr-mcp server. Call exactly one tool: the one whose description best
+matches the request. Take argument values from the request, leave out
+optional arguments it does not mention and never invent repository URLs,
+identifiers or other values the request does not give. Dates may be
+relative, such as "last week". Before the call, explain in one sentence why
+the tool fits the request.
+`
+
+// Planner chooses a function to call for a prompt.
+type Planner interface {
+	// CallFunction asks the model to answer prompt by calling one of
+	// functions.
+	CallFunction(
+		ctx context.Context,
+		system, prompt string,
+		functions []worksummary.Function,
+	) (worksummary.FunctionCall, error)
+	// Model returns the language model the planner asks.
+	Model() string
+}
+
+// Plan is the tool call chosen for an instruction.
+type Plan struct {
+	Tool      string         `json:"tool"`
+	Arguments map[string]any `json:"arguments"`
+	// Rationale is the model's explanation of its choice.
+	Rationale string `json:"rationale,omitempty"`
+	// Attempts is the number of calls the model needed to produce
+	// arguments that match the tool's schema.
+	Attempts int `json:"attempts"`
+}
+
+// UnplannableError reports an instruction the model could not turn into a
+// valid tool call.
+type UnplannableError struct {
+	// Tool and Arguments are the model's last, rejected call.
+	Tool      string
+	Arguments string
+	Problems  []string
+}
+
+// Error implements error.
+func (e *UnplannableError) Error() string {
+	return fmt.Sprintf("the call of %q was rejected: %s", e.Tool, strings.Join(e.Problems, "; "))
+}
+
+// plan asks planner for a call of one of tools that carries out
+// instruction, retrying once with the problems found in an invalid call.
+func plan(ctx context.Context, planner Planner, tools []mcp.Tool, instruction string) (Plan, error) {
+	functions := make([]worksummary.Function, 0, len(tools))
+	schemas := make(map[string]mcp.ToolInputSchema, len(tools))
+	for _, tool := range tools {
+		schema, err := inputSchema(tool)
+		if err != nil {
+			return Plan{}, err
+		}
+		schemas[tool.Name] = schema
+		functions = append(functions, worksummary.Function{
+			Name:        tool.Name,
+			Description: tool.Description,
+			Parameters:  schema,
+		})
+	}
+	prompt := instruction
+	var rejected *UnplannableError
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		call, err := planner.CallFunction(ctx, systemPrompt, prompt, functions)
+		if err != nil {
+			return Plan{}, err
+		}
+		args, problems := checkCall(schemas, call)
+		if len(problems) == 0 {
+			return Plan{
+				Tool:      call.Name,
+				Arguments: args,
+				Rationale: strings.TrimSpace(call.Reasoning),
+				Attempts:  attempt,
+			}, nil
+		}
+		rejected = &UnplannableError{Tool: call.Name, Arguments: call.Arguments, Problems: problems}
+		prompt = revisionPrompt(instruction, rejected)
+	}
+	return Plan{}, rejected
+}
+
+// revisionPrompt repeats instruction with the problems of the rejected
+// call, so the model can correct it.
+func revisionPrompt(instruction string, rejected *UnplannableError) string {
+	var sb strings.Builder
+	sb.WriteString(instruction)
+	fmt.Fprintf(
+		&sb,
+		"\n\nYour previous call of %s with the arguments %s was rejected:\n",
+		rejected.Tool,
+		rejected.Arguments,
+	)
+	for _, problem := range rejected.Problems {
+		fmt.Fprintf(&sb, "- %s\n", problem)
+	}
+	sb.WriteString("Call a tool again, fixing these problems.")
+	return sb.String()
+}
+
+// checkCall decodes the arguments of call and returns them together with
+// the ways the call breaks the schema of the tool it names.
+func checkCall(schemas map[string]mcp.ToolInputSchema, call worksummary.FunctionCall) (map[string]any, []string) {
+	schema, ok := schemas[call.Name]
+	if !ok {
+		return nil, []string{fmt.Sprintf("there is no tool named %q", call.Name)}
+	}
+	args := make(map[string]any)
+	if strings.TrimSpace(call.Arguments) != "" {
+		if err := json.Unmarshal([]byte(call.Arguments), &args); err != nil {
+			return nil, []string{fmt.Sprintf("the arguments are not a JSON object: %s", err)}
+		}
+	}
+	// Models pass null for the optional arguments they leave out, as strict
+	// mode requires and many do anyway.
+	for name, value := range args {
+		if value == nil {
+			delete(args, name)
+		}
+	}
+	return args, checkArguments(schema, args)
+}
+
+// checkArguments returns the ways args break schema, sorted.
+func checkArguments(schema mcp.ToolInputSchema, args map[string]any) []string {
+	var problems []string
+	for _, name := range schema.Required {
+		if _, ok := args[name]; !ok {
+			problems = append(problems, fmt.Sprintf("the required argument %q is missing", name))
+		}
+	}
+	for name, value := range args {
+		property, ok := schema.Properties[name].(map[string]any)
+		if !ok {
+			problems = append(problems, fmt.Sprintf("there is no argument %q", name))
+			continue
+		}
+		if problem := checkValue(property, value); problem != "" {
+			problems = append(problems, fmt.Sprintf("the argument %q %s", name, problem))
+		}
+	}
+	slices.Sort(problems)
+	return problems
+}
+
+// checkValue returns how value breaks the type and enum of a property
+// schema, or an empty string.
+func checkValue(property map[string]any, value any) string {
+	kind, _ := property["type"].(string)
+	if !hasType(kind, value) {
+		return "must be " + article(kind) + " " + kind
+	}
+	allowed := enumValues(property["enum"])
+	if len(allowed) > 0 && !slices.Contains(allowed, fmt.Sprint(value)) {
+		return "must be one of " + strings.Join(allowed, ", ")
+	}
+	return ""
+}
+
+// hasType reports whether a decoded JSON value has the JSON schema type
+// kind. Values of properties without a type always match.
+func hasType(kind string, value any) bool {
+	switch kind {
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "number":
+		_, ok := value.(float64)
+		return ok
+	case "integer":
+		number, ok := value.(float64)
+		return ok && number == math.Trunc(number)
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "array":
+		_, ok := value.([]any)
+		return ok
+	case "object":
+		_, ok := value.(map[string]any)
+		return ok
+	default:
+		return true
+	}
+}
+
+// enumValues returns the allowed values of an enum as strings, whether the
+// schema was built in process or decoded from JSON.
+func enumValues(enum any) []string {
+	switch values := enum.(type) {
+	case []string:
+		return values
+	case []any:
+		allowed := make([]string, 0, len(values))
+		for _, value := range values {
+			allowed = append(allowed, fmt.Sprint(value))
+		}
+		return allowed
+	default:
+		return nil
+	}
+}
+
+// article returns the indefinite article for a JSON schema type.
+func article(kind string) string {
+	if kind == "array" || kind == "object" || kind == "integer" {
+		return "an"
+	}
+	return "a"
+}
+
+// inputSchema returns the input schema of tool, decoding it when the tool
+// was defined with a raw JSON schema.
+func inputSchema(tool mcp.Tool) (mcp.ToolInputSchema, error) {
+	if tool.RawInputSchema == nil {
+		return tool.InputSchema, nil
+	}
+	var schema mcp.ToolInputSchema
+	if err := json.Unmarshal(tool.RawInputSchema, &schema); err != nil {
+		return mcp.ToolInputSchema{}, fmt.Errorf("invalid input schema of %s: %w", tool.Name, err)
+	}
+	return schema, nil
+}
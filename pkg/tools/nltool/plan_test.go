@@ -0,0 +1,130 @@
+package nltool
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePlanner answers with calls in turn and records the prompts it got.
+type fakePlanner struct {
+	calls     []worksummary.FunctionCall
+	err       error
+	prompts   []string
+	functions []worksummary.Function
+}
+
+func (f *fakePlanner) CallFunction(
+	_ context.Context,
+	_, prompt string,
+	functions []worksummary.Function,
+) (worksummary.FunctionCall, error) {
+	f.prompts = append(f.prompts, prompt)
+	f.functions = functions
+	if f.err != nil {
+		return worksummary.FunctionCall{}, f.err
+	}
+	call := f.calls[0]
+	if len(f.calls) > 1 {
+		f.calls = f.calls[1:]
+	}
+	return call, nil
+}
+
+func (f *fakePlanner) Model() string { return "test-model" }
+
+// summaryTool is a tool definition like the one of git-summary.
+func summaryTool() mcp.Tool {
+	return mcp.NewTool(
+		"git-summary",
+		mcp.WithDescription("Summarizes commits"),
+		mcp.WithString("repo_url", mcp.Required()),
+		mcp.WithString("branch"),
+		mcp.WithString("output_format", mcp.Enum("markdown", "html")),
+		mcp.WithNumber("limit"),
+		mcp.WithBoolean("verbose"),
+	)
+}
+
+func TestCheckArguments(t *testing.T) {
+	t.Parallel()
+	schema := summaryTool().InputSchema
+	assert.Empty(t, checkArguments(schema, map[string]any{
+		"repo_url":      "https://github.com/a/b",
+		"output_format": "html",
+		"limit":         float64(3),
+		"verbose":       true,
+	}))
+	assert.Equal(t, []string{
+		`the argument "limit" must be a number`,
+		`the argument "output_format" must be one of markdown, html`,
+		`the required argument "repo_url" is missing`,
+		`there is no argument "color"`,
+	}, checkArguments(schema, map[string]any{
+		"output_format": "pdf",
+		"limit":         "3",
+		"color":         "red",
+	}))
+	assert.Equal(
+		t,
+		[]string{`the argument "count" must be an integer`},
+		checkArguments(mcp.ToolInputSchema{
+			Properties: map[string]any{"count": map[string]any{"type": "integer"}},
+		}, map[string]any{"count": 1.5}),
+	)
+}
+
+func TestCheckArguments_RawSchema(t *testing.T) {
+	t.Parallel()
+	data, err := json.Marshal(summaryTool().InputSchema)
+	require.NoError(t, err)
+	raw := mcp.NewToolWithRawSchema("git-summary", "Summarizes commits", data)
+	schema, err := inputSchema(raw)
+	require.NoError(t, err)
+	assert.Equal(t, []string{`the argument "output_format" must be one of markdown, html`}, checkArguments(
+		schema,
+		map[string]any{"repo_url": "https://github.com/a/b", "output_format": "pdf"},
+	))
+}
+
+func TestPlan(t *testing.T) {
+	t.Parallel()
+	planner := &fakePlanner{calls: []worksummary.FunctionCall{
+		{Name: "git-summary", Arguments: `{"branch":"develop","limit":null}`},
+		{
+			Name:      "git-summary",
+			Arguments: `{"repo_url":"https://github.com/a/b","branch":"develop"}`,
+			Reasoning: " It summarizes commits. ",
+		},
+	}}
+	planned, err := plan(context.Background(), planner, []mcp.Tool{summaryTool()}, "summarize a/b develop")
+	require.NoError(t, err)
+	assert.Equal(t, Plan{
+		Tool:      "git-summary",
+		Arguments: map[string]any{"repo_url": "https://github.com/a/b", "branch": "develop"},
+		Rationale: "It summarizes commits.",
+		Attempts:  2,
+	}, planned)
+	require.Len(t, planner.prompts, 2)
+	assert.Equal(t, "summarize a/b develop", planner.prompts[0])
+	assert.Contains(t, planner.prompts[1], `the required argument "repo_url" is missing`)
+	require.Len(t, planner.functions, 1)
+	assert.Equal(t, "git-summary", planner.functions[0].Name)
+}
+
+func TestPlan_Rejected(t *testing.T) {
+	t.Parallel()
+	planner := &fakePlanner{calls: []worksummary.FunctionCall{
+		{Name: "bake-cake", Arguments: `{}`},
+	}}
+	_, err := plan(context.Background(), planner, []mcp.Tool{summaryTool()}, "bake a cake")
+	var rejected *UnplannableError
+	require.ErrorAs(t, err, &rejected)
+	assert.Equal(t, []string{`there is no tool named "bake-cake"`}, rejected.Problems)
+	assert.Len(t, planner.prompts, maxAttempts)
+}
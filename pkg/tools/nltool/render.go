@@ -0,0 +1,29 @@
+package nltool
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// RenderMarkdown describes the tool call planned for an instruction. The
+// content of the tool's result follows it in the result of run-nl.
+func RenderMarkdown(run Run) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Plan: %s\n\n", run.Plan.Tool)
+	fmt.Fprintf(&sb, "Instruction: %s\n", run.Instruction)
+	if run.Plan.Rationale != "" {
+		fmt.Fprintf(&sb, "Why: %s\n", run.Plan.Rationale)
+	}
+	// Map keys are sorted when marshaled, so the arguments read the same
+	// every time.
+	args, err := json.MarshalIndent(run.Plan.Arguments, "", "  ")
+	if err != nil {
+		args = []byte(fmt.Sprint(run.Plan.Arguments))
+	}
+	fmt.Fprintf(&sb, "\n```json\n%s\n```\n", args)
+	if !run.Executed {
+		sb.WriteString("\nDry run: the tool was not called.\n")
+	}
+	return sb.String()
+}
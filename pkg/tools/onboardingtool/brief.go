@@ -0,0 +1,436 @@
+package onboardingtool
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// maxStatCommits caps the commits whose changed files are read to
+	// find the active areas; diffing is the slowest part of a brief.
+	maxStatCommits = 300
+	// maxAreas is the number of active areas listed.
+	maxAreas = 8
+	// maxContributors is the number of contributors listed.
+	maxContributors = 8
+	// maxCommands is the number of commands listed per entry point.
+	maxCommands = 10
+	// maxThemeInput caps the commit messages sent to the language model.
+	maxThemeInput = 64 << 10
+)
+
+// Brief is the content of one onboarding brief.
+type Brief struct {
+	RepoURL string
+	Branch  string
+	Range   worksummary.DateRange
+	// Commits is the number of commits in the range.
+	Commits      int
+	EntryPoints  []EntryPoint
+	Areas        []Area
+	Contributors []Contributor
+	// Themes is the model's summary of the recent commits, empty when not
+	// requested.
+	Themes string
+}
+
+// EntryPoint is a file a new developer builds, runs or reads the project
+// from.
+type EntryPoint struct {
+	Path string
+	Kind string
+	// Commands are the invocations the file offers, such as make targets
+	// or npm scripts.
+	Commands []string
+}
+
+// Area is a directory of the repository and how much it changed.
+type Area struct {
+	Name    string
+	Commits int
+	Lines   int
+}
+
+// Contributor is an author of commits in the range.
+type Contributor struct {
+	Name       string
+	Commits    int
+	LastCommit time.Time
+	// Area is where most of the contributor's commits went.
+	Area string
+}
+
+// commitStats counts, per area, the commits and changed lines of the
+// newest maxStatCommits commits, and the commits per author and area.
+func commitStats(
+	ctx context.Context,
+	repo *git.Repository,
+	commits []worksummary.Commit,
+) ([]Area, map[string]map[string]int, error) {
+	areas := make(map[string]*Area)
+	byAuthor := make(map[string]map[string]int)
+	for _, commit := range commits[:min(len(commits), maxStatCommits)] {
+		cmt, err := repo.CommitObject(plumbing.NewHash(commit.Hash))
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading commit %s: %w", commit.Hash, err)
+		}
+		if cmt.NumParents() > 1 {
+			continue
+		}
+		stats, err := cmt.StatsContext(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("error reading changes of commit %s: %w", commit.Hash, err)
+		}
+		touched := make(map[string]bool)
+		for _, stat := range stats {
+			name := worksummary.AreaOf(stat.Name)
+			area, ok := areas[name]
+			if !ok {
+				area = &Area{Name: name}
+				areas[name] = area
+			}
+			area.Lines += stat.Addition + stat.Deletion
+			touched[name] = true
+		}
+		if byAuthor[commit.Author] == nil {
+			byAuthor[commit.Author] = make(map[string]int)
+		}
+		for name := range touched {
+			areas[name].Commits++
+			byAuthor[commit.Author][name]++
+		}
+	}
+	ranked := make([]Area, 0, len(areas))
+	for _, area := range areas {
+		ranked = append(ranked, *area)
+	}
+	slices.SortFunc(ranked, func(a, b Area) int {
+		if c := cmp.Compare(b.Commits, a.Commits); c != 0 {
+			return c
+		}
+		if c := cmp.Compare(b.Lines, a.Lines); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return ranked[:min(len(ranked), maxAreas)], byAuthor, nil
+}
+
+// rankContributors orders the authors of commits by commit count and names
+// the area each worked on most.
+func rankContributors(commits []worksummary.Commit, byAuthor map[string]map[string]int) []Contributor {
+	authors := make(map[string]*Contributor)
+	for _, commit := range commits {
+		contributor, ok := authors[commit.Author]
+		if !ok {
+			contributor = &Contributor{Name: commit.Author}
+			authors[commit.Author] = contributor
+		}
+		contributor.Commits++
+		if commit.When.After(contributor.LastCommit) {
+			contributor.LastCommit = commit.When
+		}
+	}
+	ranked := make([]Contributor, 0, len(authors))
+	for _, contributor := range authors {
+		contributor.Area = topArea(byAuthor[contributor.Name])
+		ranked = append(ranked, *contributor)
+	}
+	slices.SortFunc(ranked, func(a, b Contributor) int {
+		if c := cmp.Compare(b.Commits, a.Commits); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Name, b.Name)
+	})
+	return ranked[:min(len(ranked), maxContributors)]
+}
+
+// topArea returns the area with the most commits, empty when there is
+// none.
+func topArea(counts map[string]int) string {
+	top := ""
+	for name, count := range counts {
+		if top == "" || count > counts[top] || (count == counts[top] && name < top) {
+			top = name
+		}
+	}
+	return top
+}
+
+// entryPointKinds describes the files found at the top of the tree.
+var entryPointKinds = map[string]string{
+	"README.md":           "Project overview",
+	"README":              "Project overview",
+	"CONTRIBUTING.md":     "Contribution guide",
+	"Makefile":            "Make targets",
+	"GNUmakefile":         "Make targets",
+	"Taskfile.yml":        "Task runner",
+	"Taskfile.yaml":       "Task runner",
+	"justfile":            "just recipes",
+	"Justfile":            "just recipes",
+	"go.mod":              "Go module",
+	"package.json":        "Node.js package",
+	"pyproject.toml":      "Python project",
+	"requirements.txt":    "Python requirements",
+	"Cargo.toml":          "Rust crate",
+	"pom.xml":             "Maven project",
+	"build.gradle":        "Gradle project",
+	"Dockerfile":          "Container image",
+	"docker-compose.yml":  "Docker Compose services",
+	"docker-compose.yaml": "Docker Compose services",
+	"compose.yml":         "Docker Compose services",
+	"compose.yaml":        "Docker Compose services",
+}
+
+// fixedCommands are the commands of entry points that do not list their
+// own.
+var fixedCommands = map[string][]string{
+	"Taskfile.yml":        {"task --list"},
+	"Taskfile.yaml":       {"task --list"},
+	"justfile":            {"just --list"},
+	"Justfile":            {"just --list"},
+	"requirements.txt":    {"pip install -r requirements.txt"},
+	"Cargo.toml":          {"cargo build", "cargo test"},
+	"pom.xml":             {"mvn package"},
+	"build.gradle":        {"gradle build"},
+	"Dockerfile":          {"docker build ."},
+	"docker-compose.yml":  {"docker compose up"},
+	"docker-compose.yaml": {"docker compose up"},
+	"compose.yml":         {"docker compose up"},
+	"compose.yaml":        {"docker compose up"},
+}
+
+// entryPoints detects the build files, manifests and CI workflows in the
+// tree at the tip of the branch.
+func entryPoints(tree *object.Tree) ([]EntryPoint, error) {
+	var files []string
+	err := tree.Files().ForEach(func(file *object.File) error {
+		files = append(files, file.Name)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error listing files: %w", err)
+	}
+	var found []EntryPoint
+	for _, file := range files {
+		kind, ok := entryPointKinds[file]
+		if !ok {
+			if isWorkflow(file) {
+				found = append(found, EntryPoint{Path: file, Kind: "CI workflow"})
+			}
+			continue
+		}
+		commands, err := entryCommands(tree, file, files)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, EntryPoint{Path: file, Kind: kind, Commands: commands})
+	}
+	slices.SortFunc(found, func(a, b EntryPoint) int {
+		return cmp.Compare(a.Path, b.Path)
+	})
+	return found, nil
+}
+
+// isWorkflow reports whether file is a GitHub Actions workflow.
+func isWorkflow(file string) bool {
+	ext := path.Ext(file)
+	return path.Dir(file) == ".github/workflows" && (ext == ".yml" || ext == ".yaml")
+}
+
+// entryCommands lists the commands an entry point offers.
+func entryCommands(tree *object.Tree, file string, files []string) ([]string, error) {
+	switch file {
+	case "Makefile", "GNUmakefile":
+		content, err := fileContents(tree, file)
+		if err != nil {
+			return nil, err
+		}
+		return makeTargets(content), nil
+	case "package.json":
+		content, err := fileContents(tree, file)
+		if err != nil {
+			return nil, err
+		}
+		return npmScripts(content), nil
+	case "go.mod":
+		return goCommands(files), nil
+	default:
+		return fixedCommands[file], nil
+	}
+}
+
+// fileContents reads a file of the tree.
+func fileContents(tree *object.Tree, name string) (string, error) {
+	file, err := tree.File(name)
+	if err != nil {
+		return "", fmt.Errorf("error opening %s: %w", name, err)
+	}
+	content, err := file.Contents()
+	if err != nil {
+		return "", fmt.Errorf("error reading %s: %w", name, err)
+	}
+	return content, nil
+}
+
+// makeTarget matches a rule at the start of a makefile line, but not a
+// variable assigned with := or ::=.
+var makeTarget = regexp.MustCompile(`^([A-Za-z0-9][A-Za-z0-9_./-]*)\s*:($|[^=:])`)
+
+// makeTargets lists the targets of a makefile in order of appearance.
+func makeTargets(content string) []string {
+	var targets []string
+	for _, line := range strings.Split(content, "\n") {
+		match := makeTarget.FindStringSubmatch(line)
+		if match == nil || slices.Contains(targets, "make "+match[1]) {
+			continue
+		}
+		targets = append(targets, "make "+match[1])
+	}
+	return targets[:min(len(targets), maxCommands)]
+}
+
+// npmScripts lists the scripts of a package.json in name order.
+func npmScripts(content string) []string {
+	var manifest struct {
+		Scripts map[string]string `json:"scripts"`
+	}
+	if err := json.Unmarshal([]byte(content), &manifest); err != nil {
+		return nil
+	}
+	scripts := make([]string, 0, len(manifest.Scripts))
+	for name := range manifest.Scripts {
+		scripts = append(scripts, "npm run "+name)
+	}
+	slices.Sort(scripts)
+	return scripts[:min(len(scripts), maxCommands)]
+}
+
+// goCommands lists how the commands of a Go module are run.
+func goCommands(files []string) []string {
+	var commands []string
+	for _, file := range files {
+		switch {
+		case file == "main.go":
+			commands = append(commands, "go run .")
+		case path.Base(file) == "main.go" && path.Dir(path.Dir(file)) == "cmd":
+			commands = append(commands, "go run ./"+path.Dir(file))
+		}
+	}
+	slices.Sort(commands)
+	commands = commands[:min(len(commands), maxCommands-1)]
+	return append(commands, "go test ./...")
+}
+
+// themeInput joins the commit messages, newest first, for the language
+// model. Messages beyond maxThemeInput are dropped.
+func themeInput(commits []worksummary.Commit) string {
+	var builder strings.Builder
+	for _, commit := range commits {
+		message := strings.TrimSpace(commit.Message) + "\n"
+		if builder.Len()+len(message) > maxThemeInput {
+			break
+		}
+		builder.WriteString(message)
+	}
+	return builder.String()
+}
+
+// RenderMarkdown renders the brief as a markdown document.
+func RenderMarkdown(brief Brief) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Onboarding Brief: %s\n\n", worksummary.RepoName(brief.RepoURL))
+	fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`)\n\n", brief.RepoURL, brief.Branch)
+	builder.WriteString(brief.Range.Header())
+	writeEntryPoints(&builder, brief.EntryPoints)
+	if brief.Commits == 0 {
+		builder.WriteString("\n## Recent Activity\n\nNo commits found in the specified date range.\n")
+		return builder.String()
+	}
+	writeAreas(&builder, brief)
+	writeContributors(&builder, brief.Contributors)
+	if brief.Themes != "" {
+		builder.WriteString("\n## Recent Themes\n\n")
+		builder.WriteString(themes(brief.Themes))
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// writeEntryPoints renders the files to start from.
+func writeEntryPoints(builder *strings.Builder, entries []EntryPoint) {
+	builder.WriteString("\n## Getting Started\n\n")
+	if len(entries) == 0 {
+		builder.WriteString("No build files or manifests were found at the top of the repository.\n")
+		return
+	}
+	builder.WriteString("| File | What it is | Try |\n|---|---|---|\n")
+	for _, entry := range entries {
+		commands := make([]string, 0, len(entry.Commands))
+		for _, command := range entry.Commands {
+			commands = append(commands, "`"+command+"`")
+		}
+		fmt.Fprintf(builder, "| `%s` | %s | %s |\n", entry.Path, entry.Kind, strings.Join(commands, ", "))
+	}
+}
+
+// writeAreas renders the directories that changed most.
+func writeAreas(builder *strings.Builder, brief Brief) {
+	builder.WriteString("\n## Active Areas\n\n")
+	if len(brief.Areas) == 0 {
+		builder.WriteString("Only merge commits were found in the specified date range.\n")
+		return
+	}
+	builder.WriteString("| Area | Commits | Lines changed |\n|---|---|---|\n")
+	for _, area := range brief.Areas {
+		fmt.Fprintf(builder, "| `%s` | %d | %d |\n", area.Name, area.Commits, area.Lines)
+	}
+	if brief.Commits > maxStatCommits {
+		fmt.Fprintf(builder, "\nAreas are counted from the latest %d of %d commits.\n", maxStatCommits, brief.Commits)
+	}
+}
+
+// writeContributors renders the people to ask.
+func writeContributors(builder *strings.Builder, contributors []Contributor) {
+	builder.WriteString("\n## Main Contributors\n\n")
+	builder.WriteString("| Contributor | Commits | Last commit | Works mostly on |\n|---|---|---|---|\n")
+	for _, contributor := range contributors {
+		area := ""
+		if contributor.Area != "" {
+			area = "`" + contributor.Area + "`"
+		}
+		fmt.Fprintf(builder, "| %s | %d | %s | %s |\n",
+			contributor.Name,
+			contributor.Commits,
+			contributor.LastCommit.Format(time.DateOnly),
+			area,
+		)
+	}
+}
+
+// themes drops the title of the model's summary and moves its other
+// headings below the brief's Recent Themes section.
+func themes(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if strings.HasPrefix(lines[0], "# ") {
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
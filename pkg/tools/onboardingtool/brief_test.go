@@ -0,0 +1,89 @@
+package onboardingtool
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMakeTargets(t *testing.T) {
+	t.Parallel()
+	content := "VERSION := 1.0\nLDFLAGS ::= -s\n.PHONY: build test\n\n" +
+		"build: deps\n\tgo build ./...\n\ntest:\n\tgo test ./...\n\nbuild:\n%.o: %.c\n"
+	assert.Equal(t, []string{"make build", "make test"}, makeTargets(content))
+}
+
+func TestNpmScripts(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		[]string{"npm run build", "npm run dev", "npm run test"},
+		npmScripts(`{"name": "web", "scripts": {"test": "vitest", "dev": "vite", "build": "vite build"}}`),
+	)
+	assert.Empty(t, npmScripts("not json"))
+}
+
+func TestGoCommands(t *testing.T) {
+	t.Parallel()
+	files := []string{"go.mod", "cmd/server/main.go", "cmd/cli/main.go", "pkg/main.go", "cmd/tools/gen/main.go"}
+	assert.Equal(t, []string{"go run ./cmd/cli", "go run ./cmd/server", "go test ./..."}, goCommands(files))
+}
+
+func TestRankContributors(t *testing.T) {
+	t.Parallel()
+	june := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	commits := []worksummary.Commit{
+		{Author: "Jane", When: june},
+		{Author: "Joe", When: june.AddDate(0, 0, 1)},
+		{Author: "Jane", When: june.AddDate(0, 0, -3)},
+	}
+	byAuthor := map[string]map[string]int{
+		"Jane": {"web": 1, "api": 1},
+		"Joe":  {"docs": 1},
+	}
+	assert.Equal(t, []Contributor{
+		{Name: "Jane", Commits: 2, LastCommit: june, Area: "api"},
+		{Name: "Joe", Commits: 1, LastCommit: june.AddDate(0, 0, 1), Area: "docs"},
+	}, rankContributors(commits, byAuthor))
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	brief := Brief{
+		RepoURL: "https://github.com/dictybase/modware-stock.git",
+		Branch:  "develop",
+		Range: worksummary.DateRange{
+			Start:      time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+			End:        time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC),
+			StartInput: "2025-01-01",
+		},
+		Commits: 12,
+		EntryPoints: []EntryPoint{
+			{Path: "Makefile", Kind: "Make targets", Commands: []string{"make build", "make test"}},
+			{Path: "README.md", Kind: "Project overview"},
+		},
+		Areas: []Area{{Name: "internal/app", Commits: 9, Lines: 420}},
+		Contributors: []Contributor{
+			{Name: "Jane", Commits: 12, LastCommit: time.Date(2025, 6, 3, 0, 0, 0, 0, time.UTC), Area: "internal/app"},
+		},
+		Themes: "# Work Summary\n\n## Stocks\n- **Orders** can be placed online.",
+	}
+
+	rendered := RenderMarkdown(brief)
+	assert.True(t, strings.HasPrefix(rendered, "# Onboarding Brief: modware-stock\n\n"+
+		"**Repository:** https://github.com/dictybase/modware-stock.git (branch `develop`)\n\n"))
+	assert.Contains(t, rendered, "| `Makefile` | Make targets | `make build`, `make test` |\n"+
+		"| `README.md` | Project overview |  |\n")
+	assert.Contains(t, rendered, "| `internal/app` | 9 | 420 |\n")
+	assert.Contains(t, rendered, "| Jane | 12 | 2025-06-03 | `internal/app` |\n")
+	assert.Contains(t, rendered, "## Recent Themes\n\n### Stocks\n- **Orders** can be placed online.\n")
+	assert.NotContains(t, rendered, "# Work Summary")
+
+	brief.Commits = 0
+	rendered = RenderMarkdown(brief)
+	assert.Contains(t, rendered, "No commits found in the specified date range.")
+	assert.NotContains(t, rendered, "## Main Contributors")
+}
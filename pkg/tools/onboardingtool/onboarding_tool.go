@@ -0,0 +1,317 @@
+package onboardingtool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// defaultSince is how far back the brief looks when no start date is given.
+const defaultSince = "180 days ago"
+
+// OnboardingTool writes a brief that helps a new developer find their way
+// around a repository.
+type OnboardingTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+	resources   *resources.Catalog
+}
+
+// Option defines a functional option for configuring OnboardingTool.
+type Option func(*OnboardingTool)
+
+// WithResources publishes generated briefs as MCP resources in the catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(o *OnboardingTool) {
+		o.resources = catalog
+	}
+}
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) Option {
+	return func(o *OnboardingTool) {
+		o.analyzer = analyzer
+	}
+}
+
+// OnboardingRequest represents the parameters for an onboarding brief.
+type OnboardingRequest struct {
+	RepoURL   string `validate:"required"`
+	Branch    string `validate:"required"`
+	StartDate string `validate:"required"`
+	EndDate   string
+	// Summarize adds the recent themes written by the language model.
+	Summarize bool
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"onboarding-brief",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewOnboardingTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewOnboardingTool creates a new OnboardingTool instance.
+func NewOnboardingTool(logger *slog.Logger, opts ...Option) (*OnboardingTool, error) {
+	tool := mcp.NewTool(
+		"onboarding-brief",
+		mcp.WithDescription(
+			"Writes an onboarding brief for a git repository: how to build and run it, "+
+				"the most active areas, the main contributors and the themes of recent work",
+		),
+		mcp.WithTitleAnnotation("Onboarding Brief"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to analyze"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description(
+				"The start of the recent activity considered (optional, defaults to "+defaultSince+")",
+			),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description("The end of the recent activity considered (optional, defaults to today)"),
+		),
+		mcp.WithBoolean(
+			"summarize",
+			mcp.Description(
+				"Add the themes of recent work written by the language model, defaults to true; "+
+					"requires OPENAI_API_KEY",
+			),
+		),
+		idempotency.WithKey(),
+	)
+	onboardingTool := &OnboardingTool{
+		Name:        "onboarding-brief",
+		Description: "Writes an onboarding brief for new developers of a repository",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(onboardingTool)
+	}
+	return onboardingTool, nil
+}
+
+// GetName returns the name of the tool.
+func (o *OnboardingTool) GetName() string {
+	return o.Name
+}
+
+// GetDescription returns the description of the tool.
+func (o *OnboardingTool) GetDescription() string {
+	return o.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (o *OnboardingTool) GetSchema() mcp.ToolInputSchema {
+	return o.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (o *OnboardingTool) GetTool() mcp.Tool {
+	return o.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (o *OnboardingTool) GetAnnotations() mcp.ToolAnnotation {
+	return o.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (o *OnboardingTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Brief a new contributor on a repository",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/stock-center",
+				"branch":   "develop",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (o *OnboardingTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := OnboardingRequest{
+		RepoURL:   request.GetString("repo_url", ""),
+		Branch:    request.GetString("branch", ""),
+		StartDate: request.GetString("start_date", defaultSince),
+		EndDate:   request.GetString("end_date", ""),
+		Summarize: request.GetBool("summarize", true),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	var summarizer worksummary.SummaryClient
+	if params.Summarize {
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return toolerror.Result(toolerror.New(
+				toolerror.TypeConfiguration,
+				"MISSING_OPENAI_API_KEY",
+				"OPENAI_API_KEY is not set on the server; set summarize to false for a brief without themes",
+			)), nil
+		}
+		client, err := worksummary.NewOpenAIClient(apiKey)
+		if err != nil {
+			return toolerror.Result(toolerror.Wrap(
+				toolerror.TypeConfiguration,
+				"OPENAI_CLIENT",
+				err,
+				"error initializing OpenAI client",
+			)), nil
+		}
+		summarizer = client
+	}
+
+	brief, err := o.GenerateBrief(ctx, summarizer, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(brief)
+	providers := []string{metrics.ServiceGitClone}
+	if brief.Themes != "" {
+		providers = append(providers, metrics.ServiceOpenAI)
+	}
+	result := provenance.Attach(mcp.NewToolResultText(content), provenance.New(providers))
+	if o.resources != nil {
+		resource, err := o.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        fmt.Sprintf("%s-%s-onboarding.md", worksummary.RepoName(params.RepoURL), params.Branch),
+			MIMEType:    "text/markdown",
+			Description: "Onboarding brief for " + params.RepoURL,
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing brief: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// briefStages is the progress total of a brief: cloning takes the first
+// two steps, reading the tree and history the next and the themes the
+// rest.
+const briefStages = 5
+
+// GenerateBrief clones the repository and analyzes its tree and the
+// commits within the range. The themes are added when summarizer is set.
+func (o *OnboardingTool) GenerateBrief(
+	ctx context.Context,
+	summarizer worksummary.SummaryClient,
+	params OnboardingRequest,
+) (Brief, error) {
+	dateRange, err := o.analyzer.ResolveDateRange(params.StartDate, params.EndDate)
+	if err != nil {
+		return Brief{}, toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_DATE_RANGE",
+			err,
+			"failed to parse dates",
+		)
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, briefStages, "cloning repository")
+	repo, err := o.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 2)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Brief{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+
+	reporter.Report(2, briefStages, "reading the file tree and history")
+	head, err := repo.Head()
+	if err != nil {
+		return Brief{}, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Brief{}, fmt.Errorf("error reading branch head: %w", err)
+	}
+	tree, err := tip.Tree()
+	if err != nil {
+		return Brief{}, fmt.Errorf("error reading file tree: %w", err)
+	}
+	entries, err := entryPoints(tree)
+	if err != nil {
+		return Brief{}, err
+	}
+	commits, err := o.analyzer.ListActivity(ctx, worksummary.ActivityParams{
+		Repo:  repo.Repository,
+		Start: dateRange.Start,
+		End:   dateRange.End,
+	})
+	if err != nil {
+		return Brief{}, fmt.Errorf("failed to list commits: %w", err)
+	}
+	areas, byAuthor, err := commitStats(ctx, repo.Repository, commits)
+	if err != nil {
+		return Brief{}, err
+	}
+	brief := Brief{
+		RepoURL:      params.RepoURL,
+		Branch:       params.Branch,
+		Range:        dateRange,
+		Commits:      len(commits),
+		EntryPoints:  entries,
+		Areas:        areas,
+		Contributors: rankContributors(commits, byAuthor),
+	}
+
+	if summarizer != nil && len(commits) > 0 {
+		reporter.Report(3, briefStages, "summarizing recent themes")
+		themes, err := summarizer.SummarizeCommitMessages(
+			progress.NewContext(ctx, reporter.Sub(3, briefStages)),
+			themeInput(commits),
+		)
+		if err != nil {
+			return Brief{}, toolerror.Upstream(metrics.ServiceOpenAI, err, "failed to summarize commits")
+		}
+		brief.Themes = themes
+	}
+	reporter.Report(briefStages, briefStages, "brief generated")
+	return brief, nil
+}
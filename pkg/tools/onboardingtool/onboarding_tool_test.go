@@ -0,0 +1,96 @@
+package onboardingtool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingSummarizer returns a fixed summary and records its input.
+type recordingSummarizer struct {
+	input string
+}
+
+// SummarizeCommitMessages implements worksummary.SummaryClient.
+func (r *recordingSummarizer) SummarizeCommitMessages(_ context.Context, commitMsgs string) (string, error) {
+	r.input = commitMsgs
+	return "# Work Summary\n\n- Stock orders were added.", nil
+}
+
+// change is a commit by author, days after June 2nd, 2025.
+func change(author string, days int, message string, files map[string]string) gittest.Commit {
+	return gittest.Commit{
+		Files:   files,
+		Message: message,
+		Author:  object.Signature{Name: author, Email: author + "@example.org", When: gittest.Author.When.AddDate(0, 0, days)},
+	}
+}
+
+func TestGenerateBrief(t *testing.T) {
+	t.Parallel()
+	dir := gittest.NewRepo(t,
+		change("Jane", 0, "chore: scaffold", map[string]string{
+			"go.mod":             "module example.org/stock\n",
+			"Makefile":           "build:\n\tgo build ./...\n",
+			"cmd/server/main.go": "package main\n",
+		}),
+		change("Jane", 1, "feat: add stock orders", map[string]string{
+			"internal/orders/orders.go": "package orders\n",
+		}),
+		change("Joe", 2, "docs: describe orders", map[string]string{
+			"docs/orders.md":            "# Orders\n",
+			"internal/orders/orders.go": "package orders\n\n// Orders.\n",
+		}),
+	).Dir
+	analyzer := worksummary.NewGitAnalyzer(
+		worksummary.WithCurrentTime(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)),
+		worksummary.WithTimeZone(time.UTC),
+	)
+	tool, err := NewOnboardingTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), WithAnalyzer(analyzer))
+	require.NoError(t, err)
+
+	summarizer := &recordingSummarizer{}
+	brief, err := tool.GenerateBrief(context.Background(), summarizer, OnboardingRequest{
+		RepoURL:   dir,
+		Branch:    "master",
+		StartDate: "2025-06-03",
+		EndDate:   "2025-06-30",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, brief.Commits)
+	assert.Equal(t, []EntryPoint{
+		{Path: "Makefile", Kind: "Make targets", Commands: []string{"make build"}},
+		{Path: "go.mod", Kind: "Go module", Commands: []string{"go run ./cmd/server", "go test ./..."}},
+	}, brief.EntryPoints)
+	assert.Equal(t, []Area{
+		{Name: "internal/orders", Commits: 2, Lines: 3},
+		{Name: "docs", Commits: 1, Lines: 1},
+	}, brief.Areas)
+	require.Len(t, brief.Contributors, 2)
+	assert.Equal(t, "Jane", brief.Contributors[0].Name)
+	assert.Equal(t, "internal/orders", brief.Contributors[0].Area)
+	assert.Equal(t, "docs: describe orders\nfeat: add stock orders\n", summarizer.input)
+	assert.Equal(t, "# Work Summary\n\n- Stock orders were added.", brief.Themes)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool, err := NewOnboardingTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "onboarding-brief"
+	request.Params.Arguments = map[string]any{"repo_url": "https://github.com/dictybase/modware-stock"}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,269 @@
+package orcidtool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const defaultBaseURL = "https://pub.orcid.org/v3.0"
+
+// defaultCrossrefURL is the Crossref API, which renders works by DOI in
+// CSL styles.
+const defaultCrossrefURL = "https://api.crossref.org"
+
+// maxCitationSize is the largest rendered citation read from Crossref.
+const maxCitationSize = 16 << 10
+
+// maxBulkWorks is the largest number of works the bulk endpoint returns
+// per request.
+const maxBulkWorks = 100
+
+// OrcidClient reads public ORCID records and renders the works on them in
+// CSL styles through Crossref.
+type OrcidClient struct {
+	httpClient  *http.Client
+	baseURL     string
+	crossrefURL string
+	accessToken string
+	logger      *slog.Logger
+}
+
+// Option represents a configuration option for OrcidClient.
+type Option func(*Config)
+
+// Config holds the configuration for the ORCID client.
+type Config struct {
+	baseURL     string
+	crossrefURL string
+	accessToken string
+	timeout     time.Duration
+	logger      *slog.Logger
+}
+
+// WithBaseURL overrides the ORCID API base URL, e.g. for the sandbox.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithCrossrefURL overrides the Crossref API base URL citations are
+// rendered with.
+func WithCrossrefURL(crossrefURL string) Option {
+	return func(c *Config) {
+		c.crossrefURL = crossrefURL
+	}
+}
+
+// WithAccessToken authenticates requests with a /read-public access token,
+// which raises the API rate limits.
+func WithAccessToken(token string) Option {
+	return func(c *Config) {
+		c.accessToken = token
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewOrcidClient creates a new ORCID client.
+func NewOrcidClient(opts ...Option) *OrcidClient {
+	cfg := &Config{
+		baseURL:     defaultBaseURL,
+		crossrefURL: defaultCrossrefURL,
+		timeout:     30 * time.Second,
+		logger:      slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &OrcidClient{
+		httpClient:  ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:     strings.TrimSuffix(cfg.baseURL, "/"),
+		crossrefURL: strings.TrimSuffix(cfg.crossrefURL, "/"),
+		accessToken: cfg.accessToken,
+		logger:      cfg.logger,
+	}
+}
+
+// GetResearcher returns the public name on an ORCID record.
+func (c *OrcidClient) GetResearcher(ctx context.Context, orcid string) (*Researcher, error) {
+	var person personResponse
+	if err := c.get(ctx, fmt.Sprintf("/%s/person", orcid), &person); err != nil {
+		return nil, fmt.Errorf("failed to fetch ORCID record %s: %w", orcid, err)
+	}
+	researcher := &Researcher{ORCID: orcid}
+	if person.Name != nil {
+		researcher.GivenNames = valueOf(person.Name.GivenNames)
+		researcher.FamilyName = valueOf(person.Name.FamilyName)
+		researcher.CreditName = valueOf(person.Name.CreditName)
+	}
+	return researcher, nil
+}
+
+// ListPublications returns the works on an ORCID record. Works listed by
+// several sources are returned once.
+func (c *OrcidClient) ListPublications(ctx context.Context, orcid string) ([]Publication, error) {
+	var works worksResponse
+	if err := c.get(ctx, fmt.Sprintf("/%s/works", orcid), &works); err != nil {
+		return nil, fmt.Errorf("failed to list works of %s: %w", orcid, err)
+	}
+	putCodes := make([]string, 0, len(works.Group))
+	for _, group := range works.Group {
+		// The first summary in a group is the preferred source.
+		if len(group.WorkSummary) > 0 {
+			putCodes = append(putCodes, strconv.FormatInt(group.WorkSummary[0].PutCode, 10))
+		}
+	}
+
+	publications := make([]Publication, 0, len(putCodes))
+	for start := 0; start < len(putCodes); start += maxBulkWorks {
+		end := min(start+maxBulkWorks, len(putCodes))
+		var bulk bulkWorksResponse
+		path := fmt.Sprintf("/%s/works/%s", orcid, strings.Join(putCodes[start:end], ","))
+		if err := c.get(ctx, path, &bulk); err != nil {
+			return nil, fmt.Errorf("failed to fetch works of %s: %w", orcid, err)
+		}
+		for _, entry := range bulk.Bulk {
+			if entry.Work != nil {
+				publications = append(publications, toPublication(entry.Work))
+			}
+		}
+	}
+	c.logger.Info("fetched ORCID works", "orcid", orcid, "count", len(publications))
+	return publications, nil
+}
+
+// FormatCitation renders the work with the DOI as a bibliography entry in
+// the CSL style, such as "nature" or "chicago-author-date".
+func (c *OrcidClient) FormatCitation(ctx context.Context, doi, style string) (citation string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOutbound(metrics.ServiceCrossref, start, err)
+	}()
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodGet,
+		fmt.Sprintf("%s/works/%s/transform", c.crossrefURL, url.PathEscape(doi)),
+		nil,
+	)
+	if err != nil {
+		return "", fmt.Errorf("error creating Crossref request: %w", err)
+	}
+	req.Header.Set("Accept", fmt.Sprintf("text/x-bibliography; style=%s; locale=en-US", style))
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error calling Crossref: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("Crossref returned status %d for %s", resp.StatusCode, doi)
+	}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxCitationSize))
+	if err != nil {
+		return "", fmt.Errorf("error reading Crossref response: %w", err)
+	}
+	citation = strings.Join(strings.Fields(string(body)), " ")
+	if citation == "" {
+		return "", fmt.Errorf("Crossref returned no citation for %s", doi)
+	}
+	return citation, nil
+}
+
+// get fetches an ORCID API path and decodes the JSON response into out.
+func (c *OrcidClient) get(ctx context.Context, path string, out any) error {
+	start := time.Now()
+	err := c.fetch(ctx, path, out)
+	metrics.ObserveOutbound(metrics.ServiceORCID, start, err)
+	return err
+}
+
+// fetch performs the HTTP round trip for get.
+func (c *OrcidClient) fetch(ctx context.Context, path string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.baseURL+path, nil)
+	if err != nil {
+		return fmt.Errorf("error creating ORCID request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.accessToken != "" {
+		req.Header.Set("Authorization", "Bearer "+c.accessToken)
+	}
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling ORCID: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("ORCID returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding ORCID response: %w", err)
+	}
+	return nil
+}
+
+// toPublication converts a full work record into a Publication.
+func toPublication(record *work) Publication {
+	publication := Publication{
+		PutCode: record.PutCode,
+		Journal: valueOf(record.JournalTitle),
+		Type:    record.Type,
+	}
+	if record.Title != nil {
+		publication.Title = valueOf(record.Title.Title)
+	}
+	if record.PublicationDate != nil {
+		publication.Year, _ = strconv.Atoi(valueOf(record.PublicationDate.Year))
+	}
+	if record.ExternalIDs != nil {
+		for _, externalID := range record.ExternalIDs.ExternalID {
+			if externalID.Relationship != "" && externalID.Relationship != "self" {
+				continue
+			}
+			switch strings.ToLower(externalID.Type) {
+			case "doi":
+				publication.DOI = externalID.Value
+			case "pmid":
+				publication.PMID = externalID.Value
+			}
+		}
+	}
+	if record.Contributors != nil {
+		for _, contributor := range record.Contributors.Contributor {
+			if name := valueOf(contributor.CreditName); name != "" {
+				publication.Authors = append(publication.Authors, name)
+			}
+		}
+	}
+	return publication
+}
+
+// valueOf unwraps an optional ORCID value.
+func valueOf(wrapped *value) string {
+	if wrapped == nil {
+		return ""
+	}
+	return strings.TrimSpace(wrapped.Value)
+}
@@ -0,0 +1,109 @@
+package orcidtool
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testORCID = "0000-0002-1825-0097"
+
+const personJSON = `{"name":{"given-names":{"value":"Josiah"},"family-name":{"value":"Carberry"}}}`
+
+const worksJSON = `{"group":[
+	{"work-summary":[{"put-code":11},{"put-code":12}]},
+	{"work-summary":[{"put-code":21}]}
+]}`
+
+const bulkJSON = `{"bulk":[
+	{"work":{"put-code":11,"type":"journal-article",
+		"title":{"title":{"value":"Chemotaxis in Dictyostelium"}},
+		"journal-title":{"value":"Dev Biol"},
+		"publication-date":{"year":{"value":"2021"}},
+		"external-ids":{"external-id":[
+			{"external-id-type":"doi","external-id-value":"10.1000/xyz","external-id-relationship":"self"},
+			{"external-id-type":"pmid","external-id-value":"123","external-id-relationship":"self"},
+			{"external-id-type":"doi","external-id-value":"10.1000/parent","external-id-relationship":"part-of"}
+		]},
+		"contributors":{"contributor":[{"credit-name":{"value":"Josiah S. Carberry"}},{"credit-name":{"value":"Jane Roe"}}]}}},
+	{"work":{"put-code":21,"type":"journal-article","title":{"title":{"value":"Cell sorting"}}}}
+]}`
+
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{orcid}/person", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, personJSON)
+	})
+	mux.HandleFunc("GET /{orcid}/works", func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, worksJSON)
+	})
+	mux.HandleFunc("GET /{orcid}/works/{codes}", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "11,21", r.PathValue("codes"))
+		_, _ = io.WriteString(w, bulkJSON)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOrcidClient_ListPublications(t *testing.T) {
+	t.Parallel()
+	server := newTestServer(t)
+	client := NewOrcidClient(WithBaseURL(server.URL))
+
+	publications, err := client.ListPublications(context.Background(), testORCID)
+	require.NoError(t, err)
+	require.Len(t, publications, 2)
+	assert.Equal(t, Publication{
+		PutCode: 11,
+		Title:   "Chemotaxis in Dictyostelium",
+		Journal: "Dev Biol",
+		Year:    2021,
+		Type:    "journal-article",
+		DOI:     "10.1000/xyz",
+		PMID:    "123",
+		Authors: []string{"Josiah S. Carberry", "Jane Roe"},
+	}, publications[0])
+	assert.Zero(t, publications[1].Year)
+}
+
+func TestOrcidClient_GetResearcher(t *testing.T) {
+	t.Parallel()
+	server := newTestServer(t)
+	client := NewOrcidClient(WithBaseURL(server.URL))
+
+	researcher, err := client.GetResearcher(context.Background(), testORCID)
+	require.NoError(t, err)
+	assert.Equal(t, "Josiah Carberry", researcher.DisplayName())
+}
+
+func TestOrcidClient_NotFound(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+	client := NewOrcidClient(WithBaseURL(server.URL))
+
+	_, err := client.GetResearcher(context.Background(), testORCID)
+	require.ErrorContains(t, err, "status 404")
+}
+
+func TestOrcidClient_FormatCitation(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/works/10.1000%2Fxyz/transform", r.URL.EscapedPath())
+		assert.Equal(t, "text/x-bibliography; style=nature; locale=en-US", r.Header.Get("Accept"))
+		_, _ = io.WriteString(w, "1.\tCarberry, J. S. & Roe, J. Chemotaxis in Dictyostelium.\n")
+	}))
+	t.Cleanup(server.Close)
+	client := NewOrcidClient(WithCrossrefURL(server.URL))
+
+	citation, err := client.FormatCitation(context.Background(), "10.1000/xyz", "nature")
+	require.NoError(t, err)
+	assert.Equal(t, "1. Carberry, J. S. & Roe, J. Chemotaxis in Dictyostelium.", citation)
+}
@@ -0,0 +1,251 @@
+package orcidtool
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Citation styles FormatPublicationList renders itself, named after their
+// CSL style IDs. Other CSL styles are rendered by Crossref.
+const (
+	StyleAPA       = "apa"
+	StyleVancouver = "vancouver"
+)
+
+// cslStyleID matches the ID of a CSL style in the CSL style repository,
+// such as "chicago-author-date".
+var cslStyleID = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// validateCSLStyle checks that a field holds the ID of a CSL style.
+func validateCSLStyle(fl validator.FieldLevel) bool {
+	return cslStyleID.MatchString(fl.Field().String())
+}
+
+// isBuiltinStyle reports whether FormatPublicationList renders style
+// itself rather than relying on citations rendered by Crossref.
+func isBuiltinStyle(style string) bool {
+	return style == StyleAPA || style == StyleVancouver
+}
+
+// maxVancouverAuthors is the number of authors listed before "et al.".
+const maxVancouverAuthors = 6
+
+// ListParams holds the parameters for formatting a publication list.
+type ListParams struct {
+	Researcher   Researcher
+	Publications []Publication
+	// Style is the ID of a CSL style. Publications without a citation in
+	// Citations are rendered in APA unless Style is built in.
+	Style string `validate:"required,cslstyle"`
+	// SinceYear drops publications from before this year when positive.
+	SinceYear int
+	// Citations holds the citations already rendered in Style, keyed by the
+	// put codes of their publications.
+	Citations map[int64]string
+	// Summary is a summary of the research, listed before the publications
+	// when set.
+	Summary string
+}
+
+// FormatPublicationList renders publications as a markdown list grouped by
+// year, newest first, with the researcher's name in bold.
+func FormatPublicationList(params ListParams) (string, error) {
+	if err := validate.Struct(params); err != nil {
+		return "", fmt.Errorf("invalid publication list parameters: %w", err)
+	}
+	publications := selectPublications(params.Publications, params.SinceYear)
+
+	var builder strings.Builder
+	fmt.Fprintf(
+		&builder,
+		"# Publications of %s\n\nORCID: https://orcid.org/%s\n",
+		params.Researcher.DisplayName(),
+		params.Researcher.ORCID,
+	)
+	if params.Summary != "" {
+		fmt.Fprintf(&builder, "\n## Summary\n\n%s\n", params.Summary)
+	}
+	if len(publications) == 0 {
+		builder.WriteString("\nNo publications found.\n")
+		return builder.String(), nil
+	}
+	currentYear := -1
+	for _, publication := range publications {
+		if publication.Year != currentYear {
+			currentYear = publication.Year
+			heading := "Undated"
+			if currentYear > 0 {
+				heading = fmt.Sprintf("%d", currentYear)
+			}
+			fmt.Fprintf(&builder, "\n## %s\n\n", heading)
+		}
+		entry, ok := params.Citations[publication.PutCode]
+		if !ok {
+			entry = citation(params.Style, publication, params.Researcher)
+		}
+		fmt.Fprintf(&builder, "- %s\n", entry)
+	}
+	return builder.String(), nil
+}
+
+// selectPublications returns the titled publications from sinceYear on,
+// or all of them when sinceYear is not positive, newest first with undated
+// works last.
+func selectPublications(publications []Publication, sinceYear int) []Publication {
+	selected := slices.DeleteFunc(
+		slices.Clone(publications),
+		func(publication Publication) bool {
+			return publication.Title == "" || (sinceYear > 0 && publication.Year < sinceYear)
+		},
+	)
+	slices.SortStableFunc(selected, func(first, second Publication) int {
+		if byYear := cmp.Compare(second.Year, first.Year); byYear != 0 {
+			return byYear
+		}
+		return cmp.Compare(strings.ToLower(first.Title), strings.ToLower(second.Title))
+	})
+	return selected
+}
+
+// citation renders a single publication in the given style, or in APA when
+// the style is not built in.
+func citation(style string, publication Publication, researcher Researcher) string {
+	if style == StyleVancouver {
+		return vancouverCitation(publication, researcher)
+	}
+	return apaCitation(publication, researcher)
+}
+
+// apaCitation renders a publication in APA style.
+func apaCitation(publication Publication, researcher Researcher) string {
+	authors := make([]string, 0, len(publication.Authors))
+	for _, author := range publication.Authors {
+		family, initials := splitName(author)
+		name := family
+		if initials != "" {
+			name += ", " + dottedInitials(initials)
+		}
+		authors = append(authors, highlight(name, family, researcher))
+	}
+	var parts []string
+	if len(authors) > 0 {
+		parts = append(parts, joinAPAAuthors(authors))
+	}
+	parts = append(parts, fmt.Sprintf("(%s).", yearOrND(publication.Year)))
+	parts = append(parts, ensurePeriod(publication.Title))
+	if publication.Journal != "" {
+		parts = append(parts, fmt.Sprintf("*%s*.", publication.Journal))
+	}
+	if publication.DOI != "" {
+		parts = append(parts, "https://doi.org/"+publication.DOI)
+	}
+	return strings.Join(parts, " ")
+}
+
+// vancouverCitation renders a publication in Vancouver style.
+func vancouverCitation(publication Publication, researcher Researcher) string {
+	authors := make([]string, 0, len(publication.Authors))
+	for i, author := range publication.Authors {
+		if i == maxVancouverAuthors {
+			authors = append(authors, "et al")
+			break
+		}
+		family, initials := splitName(author)
+		name := strings.TrimSpace(family + " " + initials)
+		authors = append(authors, highlight(name, family, researcher))
+	}
+	var parts []string
+	if len(authors) > 0 {
+		parts = append(parts, strings.Join(authors, ", ")+".")
+	}
+	parts = append(parts, ensurePeriod(publication.Title))
+	if publication.Journal != "" {
+		parts = append(parts, publication.Journal+".")
+	}
+	if publication.Year > 0 {
+		parts = append(parts, fmt.Sprintf("%d.", publication.Year))
+	}
+	if publication.DOI != "" {
+		parts = append(parts, fmt.Sprintf("doi:%s.", publication.DOI))
+	}
+	if publication.PMID != "" {
+		parts = append(parts, fmt.Sprintf("PMID: %s.", publication.PMID))
+	}
+	return strings.Join(parts, " ")
+}
+
+// splitName splits a display name such as "Jane A. Doe" or "Doe, Jane A."
+// into the family name and undotted initials ("Doe", "JA").
+func splitName(name string) (string, string) {
+	var family string
+	var given []string
+	if last, first, found := strings.Cut(name, ","); found {
+		family = strings.TrimSpace(last)
+		given = strings.Fields(first)
+	} else {
+		fields := strings.Fields(name)
+		if len(fields) == 0 {
+			return "", ""
+		}
+		family = fields[len(fields)-1]
+		given = fields[:len(fields)-1]
+	}
+	var initials strings.Builder
+	for _, part := range given {
+		for _, hyphenated := range strings.Split(strings.Trim(part, "."), "-") {
+			if runes := []rune(hyphenated); len(runes) > 0 {
+				initials.WriteRune(runes[0])
+			}
+		}
+	}
+	return family, strings.ToUpper(initials.String())
+}
+
+// dottedInitials turns "JA" into "J. A.".
+func dottedInitials(initials string) string {
+	dotted := make([]string, 0, len(initials))
+	for _, initial := range initials {
+		dotted = append(dotted, string(initial)+".")
+	}
+	return strings.Join(dotted, " ")
+}
+
+// joinAPAAuthors joins author names with commas and an ampersand.
+func joinAPAAuthors(authors []string) string {
+	if len(authors) == 1 {
+		return authors[0]
+	}
+	return strings.Join(authors[:len(authors)-1], ", ") + ", & " + authors[len(authors)-1]
+}
+
+// highlight bolds the name when the family name matches the researcher.
+func highlight(name, family string, researcher Researcher) string {
+	if researcher.FamilyName != "" && strings.EqualFold(family, researcher.FamilyName) {
+		return "**" + name + "**"
+	}
+	return name
+}
+
+// ensurePeriod terminates a title with a period unless it already ends in
+// punctuation.
+func ensurePeriod(title string) string {
+	title = strings.TrimSpace(title)
+	if strings.HasSuffix(title, ".") || strings.HasSuffix(title, "?") ||
+		strings.HasSuffix(title, "!") {
+		return title
+	}
+	return title + "."
+}
+
+// yearOrND returns the year, or "n.d." when it is unknown.
+func yearOrND(year int) string {
+	if year <= 0 {
+		return "n.d."
+	}
+	return fmt.Sprintf("%d", year)
+}
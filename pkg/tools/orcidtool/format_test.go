@@ -0,0 +1,111 @@
+package orcidtool
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testPublications = []Publication{
+	{Title: "Old work", Journal: "J Old", Year: 2015, Authors: []string{"Josiah Carberry"}},
+	{
+		Title:   "Chemotaxis in Dictyostelium",
+		Journal: "Dev Biol",
+		Year:    2021,
+		DOI:     "10.1000/xyz",
+		PMID:    "123",
+		Authors: []string{"Josiah S. Carberry", "Jane Roe"},
+	},
+	{Title: "Undated preprint"},
+}
+
+func TestFormatPublicationList_APA(t *testing.T) {
+	t.Parallel()
+	list, err := FormatPublicationList(ListParams{
+		Researcher:   Researcher{ORCID: testORCID, GivenNames: "Josiah", FamilyName: "Carberry"},
+		Publications: testPublications,
+		Style:        StyleAPA,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, `# Publications of Josiah Carberry
+
+ORCID: https://orcid.org/0000-0002-1825-0097
+
+## 2021
+
+- **Carberry, J. S.**, & Roe, J. (2021). Chemotaxis in Dictyostelium. *Dev Biol*. https://doi.org/10.1000/xyz
+
+## 2015
+
+- **Carberry, J.** (2015). Old work. *J Old*.
+
+## Undated
+
+- (n.d.). Undated preprint.
+`, list)
+}
+
+func TestFormatPublicationList_VancouverSinceYear(t *testing.T) {
+	t.Parallel()
+	list, err := FormatPublicationList(ListParams{
+		Researcher:   Researcher{ORCID: testORCID, FamilyName: "Carberry"},
+		Publications: testPublications,
+		Style:        StyleVancouver,
+		SinceYear:    2020,
+	})
+	require.NoError(t, err)
+	assert.Contains(
+		t,
+		list,
+		"- **Carberry JS**, Roe J. Chemotaxis in Dictyostelium. Dev Biol. 2021. doi:10.1000/xyz. PMID: 123.",
+	)
+	assert.NotContains(t, list, "Old work")
+	assert.NotContains(t, list, "Undated")
+}
+
+func TestFormatPublicationList_InvalidStyle(t *testing.T) {
+	t.Parallel()
+	_, err := FormatPublicationList(ListParams{Style: "APA 7th"})
+	require.Error(t, err, "CSL style IDs are lowercase words joined by hyphens")
+}
+
+func TestFormatPublicationList_CitationsAndSummary(t *testing.T) {
+	t.Parallel()
+	publications := slices.Clone(testPublications)
+	publications[1].PutCode = 11
+	list, err := FormatPublicationList(ListParams{
+		Researcher:   Researcher{ORCID: testORCID, FamilyName: "Carberry"},
+		Publications: publications,
+		Style:        "nature",
+		Citations:    map[int64]string{11: "Carberry, J. S. & Roe, J. Chemotaxis in Dictyostelium. Dev Biol (2021)."},
+		Summary:      "Carberry studies chemotaxis.",
+	})
+	require.NoError(t, err)
+	assert.Contains(t, list, "\n## Summary\n\nCarberry studies chemotaxis.\n\n## 2021\n")
+	assert.Contains(t, list, "- Carberry, J. S. & Roe, J. Chemotaxis in Dictyostelium. Dev Biol (2021).\n")
+	assert.Contains(t, list, "- **Carberry, J.** (2015). Old work. *J Old*.", "works without a citation are listed in APA")
+}
+
+func TestSplitName(t *testing.T) {
+	t.Parallel()
+	for name, want := range map[string][2]string{
+		"Jane A. Doe":      {"Doe", "JA"},
+		"Doe, Jane A.":     {"Doe", "JA"},
+		"Jean-Paul Sartre": {"Sartre", "JP"},
+		"Consortium":       {"Consortium", ""},
+	} {
+		family, initials := splitName(name)
+		assert.Equal(t, want, [2]string{family, initials}, name)
+	}
+}
+
+func TestIsValidORCID(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isValidORCID("0000-0002-1825-0097"))
+	assert.True(t, isValidORCID("0000-0002-1694-233X"))
+	assert.False(t, isValidORCID("0000-0002-1825-0098"))
+	assert.False(t, isValidORCID("0000000218250097"))
+	assert.Equal(t, "0000-0002-1694-233X", normalizeORCID(" https://orcid.org/0000-0002-1694-233x "))
+}
@@ -0,0 +1,384 @@
+package orcidtool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// OrcidTool builds a researcher's publication list from their ORCID record,
+// in a CSL style and optionally opened by a summary of their research, ready
+// to paste into biosketches and lab webpages.
+type OrcidTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []Option
+	summarizer    Summarizer
+}
+
+// Summarizer writes a summary of the research shown by a publication list.
+type Summarizer interface {
+	SummarizePublications(ctx context.Context, publications string) (string, error)
+}
+
+// ToolOption defines a functional option for configuring OrcidTool.
+type ToolOption func(*OrcidTool)
+
+// WithClientOptions sets options for the ORCID clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(o *OrcidTool) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// WithSummarizer sets the language model client publication lists are
+// summarized with, which is otherwise created from OPENAI_API_KEY on every
+// call that summarizes.
+func WithSummarizer(summarizer Summarizer) ToolOption {
+	return func(o *OrcidTool) {
+		o.summarizer = summarizer
+	}
+}
+
+// PublicationsRequest represents the parameters for the publication list.
+type PublicationsRequest struct {
+	ORCID     string `validate:"required,orcid"`
+	Style     string `validate:"required,cslstyle"`
+	SinceYear int    `validate:"gte=0"`
+	// Summarize adds a summary of the research written by the language
+	// model.
+	Summarize bool
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	if err := validate.RegisterValidation("orcid", validateORCID); err != nil {
+		panic(err)
+	}
+	if err := validate.RegisterValidation("cslstyle", validateCSLStyle); err != nil {
+		panic(err)
+	}
+	registry.Register(
+		"orcid-publications",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			orcidTool, err := NewOrcidTool(deps.Logger)
+			if err != nil {
+				return nil, err
+			}
+			return orcidTool, nil
+		},
+	)
+}
+
+// NewOrcidTool creates a new OrcidTool instance.
+func NewOrcidTool(logger *slog.Logger, opts ...ToolOption) (*OrcidTool, error) {
+	tool := mcp.NewTool(
+		"orcid-publications",
+		mcp.WithDescription(
+			"Builds a year-grouped publication list for a researcher from their ORCID record",
+		),
+		mcp.WithTitleAnnotation("ORCID Publications"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"orcid",
+			mcp.Description("The researcher's ORCID iD, e.g. 0000-0002-1825-0097"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"style",
+			mcp.Description(
+				"ID of the CSL style to cite in, such as 'apa', 'vancouver', 'nature' or "+
+					"'chicago-author-date', defaults to 'apa'. Styles other than apa and vancouver are "+
+					"rendered by Crossref for works with a DOI; the other works are listed in APA",
+			),
+		),
+		mcp.WithNumber(
+			"since_year",
+			mcp.Description("Only list publications from this year onwards"),
+		),
+		mcp.WithBoolean(
+			"summarize",
+			mcp.Description(
+				"Open the list with a summary of the research written by the language model, "+
+					"defaults to false; requires OPENAI_API_KEY",
+			),
+		),
+	)
+	orcidTool := &OrcidTool{
+		Name:        "orcid-publications",
+		Description: "Builds a year-grouped publication list for a researcher from their ORCID record",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(orcidTool)
+	}
+	return orcidTool, nil
+}
+
+// GetName returns the name of the tool.
+func (o *OrcidTool) GetName() string {
+	return o.Name
+}
+
+// GetDescription returns the description of the tool.
+func (o *OrcidTool) GetDescription() string {
+	return o.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (o *OrcidTool) GetSchema() mcp.ToolInputSchema {
+	return o.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (o *OrcidTool) GetTool() mcp.Tool {
+	return o.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (o *OrcidTool) GetAnnotations() mcp.ToolAnnotation {
+	return o.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (o *OrcidTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List a researcher's publications since 2020 in Vancouver style",
+			Arguments: map[string]any{
+				"orcid":      "0000-0002-1825-0097",
+				"since_year": 2020,
+				"style":      "vancouver",
+			},
+		},
+		{
+			Description: "Summarize a researcher's work above a publication list in Nature style",
+			Arguments: map[string]any{
+				"orcid":     "0000-0002-1825-0097",
+				"style":     "nature",
+				"summarize": true,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (o *OrcidTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := PublicationsRequest{
+		ORCID:     normalizeORCID(request.GetString("orcid", "")),
+		Style:     request.GetString("style", StyleAPA),
+		SinceYear: request.GetInt("since_year", 0),
+		Summarize: request.GetBool("summarize", false),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	var summarizer Summarizer
+	if params.Summarize {
+		var err error
+		if summarizer, err = o.summarizerClient(); err != nil {
+			return toolerror.Result(err), nil
+		}
+	}
+
+	logger := logging.WithRequestID(o.Logger)
+	client := o.orcidClient(logger)
+	researcher, err := client.GetResearcher(ctx, params.ORCID)
+	if err != nil {
+		return toolerror.Result(
+			toolerror.Upstream(metrics.ServiceORCID, err, "failed to read ORCID record"),
+		), nil
+	}
+	publications, err := client.ListPublications(ctx, params.ORCID)
+	if err != nil {
+		return toolerror.Result(
+			toolerror.Upstream(metrics.ServiceORCID, err, "failed to list publications"),
+		), nil
+	}
+	publications = selectPublications(publications, params.SinceYear)
+	providers := []string{metrics.ServiceORCID}
+	var citations map[int64]string
+	if !isBuiltinStyle(params.Style) {
+		if citations, err = renderCitations(ctx, client, publications, params.Style, logger); err != nil {
+			return toolerror.Result(fmt.Errorf("citations stopped: %w", err)), nil
+		}
+		if len(citations) > 0 {
+			providers = append(providers, metrics.ServiceCrossref)
+		}
+	}
+	var summary string
+	if summarizer != nil && len(publications) > 0 {
+		summary, err = summarizer.SummarizePublications(ctx, summaryInput(publications))
+		if err != nil {
+			return toolerror.Result(
+				toolerror.Upstream(metrics.ServiceOpenAI, err, "failed to summarize publications"),
+			), nil
+		}
+		providers = append(providers, metrics.ServiceOpenAI)
+	}
+	list, err := FormatPublicationList(ListParams{
+		Researcher:   *researcher,
+		Publications: publications,
+		Style:        params.Style,
+		SinceYear:    params.SinceYear,
+		Citations:    citations,
+		Summary:      summary,
+	})
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to format publication list: %w", err)), nil
+	}
+	return provenance.Attach(
+		mcp.NewToolResultText(list),
+		provenance.New(providers),
+	), nil
+}
+
+// renderCitations renders the publications with a DOI in the CSL style
+// through Crossref. A publication Crossref fails to render is left out and
+// listed in APA instead, unless ctx is done, which stops the rendering.
+func renderCitations(
+	ctx context.Context,
+	client *OrcidClient,
+	publications []Publication,
+	style string,
+	logger *slog.Logger,
+) (map[int64]string, error) {
+	citations := make(map[int64]string)
+	failed := 0
+	for _, publication := range publications {
+		if publication.DOI == "" {
+			continue
+		}
+		citation, err := client.FormatCitation(ctx, publication.DOI, style)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if err != nil {
+			logger.Warn("failed to render citation", "doi", publication.DOI, "style", style, "error", err)
+			failed++
+			continue
+		}
+		citations[publication.PutCode] = citation
+	}
+	logger.Info("rendered citations", "style", style, "rendered", len(citations), "failed", failed)
+	return citations, nil
+}
+
+// summaryInput lists the publications for the summarizer, one per line
+// with their year, title and journal.
+func summaryInput(publications []Publication) string {
+	var builder strings.Builder
+	for _, publication := range publications {
+		year := "Undated"
+		if publication.Year > 0 {
+			year = strconv.Itoa(publication.Year)
+		}
+		fmt.Fprintf(&builder, "- %s. %s", year, ensurePeriod(publication.Title))
+		if publication.Journal != "" {
+			fmt.Fprintf(&builder, " %s.", publication.Journal)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// summarizerClient returns the summarizer of the tool, or creates one from
+// OPENAI_API_KEY.
+func (o *OrcidTool) summarizerClient() (Summarizer, error) {
+	if o.summarizer != nil {
+		return o.summarizer, nil
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, toolerror.New(
+			toolerror.TypeConfiguration,
+			"MISSING_OPENAI_API_KEY",
+			"OPENAI_API_KEY is not set on the server; set summarize to false for a list without a summary",
+		)
+	}
+	client, err := worksummary.NewOpenAIClient(apiKey)
+	if err != nil {
+		return nil, toolerror.Wrap(
+			toolerror.TypeConfiguration,
+			"OPENAI_CLIENT",
+			err,
+			"error initializing OpenAI client",
+		)
+	}
+	return client, nil
+}
+
+// orcidClient creates an ORCID client, authenticated with ORCID_ACCESS_TOKEN
+// when it is set.
+func (o *OrcidTool) orcidClient(logger *slog.Logger) *OrcidClient {
+	opts := []Option{WithLogger(logger)}
+	if token := os.Getenv("ORCID_ACCESS_TOKEN"); token != "" {
+		opts = append(opts, WithAccessToken(token))
+	}
+	return NewOrcidClient(append(opts, o.clientOptions...)...)
+}
+
+// normalizeORCID strips an https://orcid.org/ prefix and surrounding space.
+func normalizeORCID(orcid string) string {
+	orcid = strings.TrimSpace(orcid)
+	for _, prefix := range []string{"https://orcid.org/", "http://orcid.org/", "orcid.org/"} {
+		orcid = strings.TrimPrefix(orcid, prefix)
+	}
+	return strings.ToUpper(orcid)
+}
+
+// validateORCID checks the format and ISO 7064 11,2 check digit of an
+// ORCID iD.
+func validateORCID(fl validator.FieldLevel) bool {
+	return isValidORCID(fl.Field().String())
+}
+
+// isValidORCID reports whether orcid is a well-formed ORCID iD such as
+// 0000-0002-1825-0097.
+func isValidORCID(orcid string) bool {
+	if len(orcid) != 19 || orcid[4] != '-' || orcid[9] != '-' || orcid[14] != '-' {
+		return false
+	}
+	digits := strings.ReplaceAll(orcid, "-", "")
+	if len(digits) != 16 {
+		return false
+	}
+	total := 0
+	for _, digit := range digits[:15] {
+		if digit < '0' || digit > '9' {
+			return false
+		}
+		total = (total + int(digit-'0')) * 2
+	}
+	check := (12 - total%11) % 11
+	expected := byte('0' + check)
+	if check == 10 {
+		expected = 'X'
+	}
+	return digits[15] == expected
+}
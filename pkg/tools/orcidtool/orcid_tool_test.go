@@ -0,0 +1,87 @@
+package orcidtool
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_Provenance(t *testing.T) {
+	t.Parallel()
+	server := newTestServer(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewOrcidTool(logger, WithClientOptions(WithBaseURL(server.URL)))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"orcid": "https://orcid.org/" + testORCID}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "Chemotaxis in Dictyostelium")
+
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"orcid"}, record.Providers)
+	assert.Equal(t, provenance.CacheMiss, record.Cache)
+	assert.Empty(t, record.Model)
+}
+
+// fakeSummarizer summarizes every publication list the same way.
+type fakeSummarizer struct {
+	input string
+}
+
+func (f *fakeSummarizer) SummarizePublications(_ context.Context, publications string) (string, error) {
+	f.input = publications
+	return "Carberry studies chemotaxis.", nil
+}
+
+func TestHandler_CSLStyleAndSummary(t *testing.T) {
+	t.Parallel()
+	crossref := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "text/x-bibliography; style=nature; locale=en-US", r.Header.Get("Accept"))
+		_, _ = io.WriteString(w, "Carberry, J. S. & Roe, J. Chemotaxis in Dictyostelium. Dev Biol (2021).\n")
+	}))
+	t.Cleanup(crossref.Close)
+	summarizer := &fakeSummarizer{}
+	tool, err := NewOrcidTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		WithClientOptions(WithBaseURL(newTestServer(t).URL), WithCrossrefURL(crossref.URL)),
+		WithSummarizer(summarizer),
+	)
+	require.NoError(t, err)
+
+	result := tooltest.Call(t, tool.Handler, "orcid-publications", map[string]any{
+		"orcid": testORCID, "style": "nature", "summarize": true,
+	})
+	text := tooltest.Text(t, result)
+	assert.Contains(t, text, "## Summary\n\nCarberry studies chemotaxis.\n")
+	assert.Contains(t, text, "- Carberry, J. S. & Roe, J. Chemotaxis in Dictyostelium. Dev Biol (2021).\n")
+	assert.Contains(t, text, "- (n.d.). Cell sorting.", "works without a DOI are listed in APA")
+	assert.Equal(t, "- 2021. Chemotaxis in Dictyostelium. Dev Biol.\n- Undated. Cell sorting.\n", summarizer.input)
+
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"orcid", "crossref", "openai"}, record.Providers)
+}
+
+func TestHandler_InvalidStyle(t *testing.T) {
+	t.Parallel()
+	tool, err := NewOrcidTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+	result := tooltest.Call(t, tool.Handler, "orcid-publications", map[string]any{"orcid": testORCID, "style": "APA 7th"})
+	assert.Equal(t, "INVALID_INPUT", tooltest.ErrorCode(t, result))
+}
@@ -0,0 +1,89 @@
+package orcidtool
+
+// Publication is a work from an ORCID record reduced to the fields needed
+// for a publication list.
+type Publication struct {
+	PutCode int64
+	Title   string
+	Journal string
+	Year    int
+	Type    string
+	DOI     string
+	PMID    string
+	Authors []string
+}
+
+// Researcher is the public name on an ORCID record.
+type Researcher struct {
+	ORCID      string
+	GivenNames string
+	FamilyName string
+	CreditName string
+}
+
+// DisplayName returns the name the researcher publishes under.
+func (r Researcher) DisplayName() string {
+	if r.CreditName != "" {
+		return r.CreditName
+	}
+	if r.GivenNames == "" {
+		return r.FamilyName
+	}
+	return r.GivenNames + " " + r.FamilyName
+}
+
+// value is the {"value": ...} wrapper used throughout the ORCID schema.
+type value struct {
+	Value string `json:"value"`
+}
+
+// personResponse is the subset of /person used to name the researcher.
+type personResponse struct {
+	Name *struct {
+		GivenNames *value `json:"given-names"`
+		FamilyName *value `json:"family-name"`
+		CreditName *value `json:"credit-name"`
+	} `json:"name"`
+}
+
+// worksResponse is the response of /works listing work summaries grouped
+// by duplicates from different sources.
+type worksResponse struct {
+	Group []struct {
+		WorkSummary []struct {
+			PutCode int64 `json:"put-code"`
+		} `json:"work-summary"`
+	} `json:"group"`
+}
+
+// bulkWorksResponse is the response of /works/{put-codes}.
+type bulkWorksResponse struct {
+	Bulk []struct {
+		Work *work `json:"work"`
+	} `json:"bulk"`
+}
+
+// work is a full ORCID work record.
+type work struct {
+	PutCode int64 `json:"put-code"`
+	Title   *struct {
+		Title *value `json:"title"`
+	} `json:"title"`
+	JournalTitle    *value `json:"journal-title"`
+	Type            string `json:"type"`
+	PublicationDate *struct {
+		Year *value `json:"year"`
+	} `json:"publication-date"`
+	ExternalIDs *struct {
+		ExternalID []struct {
+			Type         string `json:"external-id-type"`
+			Value        string `json:"external-id-value"`
+			Relationship string `json:"external-id-relationship"`
+		} `json:"external-id"`
+	} `json:"external-ids"`
+	Contributors *struct {
+		Contributor []struct {
+			CreditName *value `json:"credit-name"`
+		} `json:"contributor"`
+	} `json:"contributors"`
+}
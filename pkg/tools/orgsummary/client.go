@@ -0,0 +1,211 @@
+package orgsummary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const defaultBaseURL = "https://api.github.com"
+
+// pageSize is the largest page the GitHub API returns.
+const pageSize = 100
+
+// errNotFound is returned for a 404 response.
+var errNotFound = errors.New("not found")
+
+// ErrOwnerNotFound is returned when no organization or user has the name.
+var ErrOwnerNotFound = errors.New("no GitHub organization or user named")
+
+// ErrRepositoryNotFound is returned when a repository does not exist or is
+// not visible with the token.
+var ErrRepositoryNotFound = errors.New("no GitHub repository named")
+
+// Repository is a GitHub repository as listed by the API.
+type Repository struct {
+	Name          string    `json:"name"`
+	FullName      string    `json:"full_name"`
+	HTMLURL       string    `json:"html_url"`
+	CloneURL      string    `json:"clone_url"`
+	DefaultBranch string    `json:"default_branch"`
+	PushedAt      time.Time `json:"pushed_at"`
+	// Size is the size of the repository in kilobytes.
+	Size     int  `json:"size"`
+	Private  bool `json:"private"`
+	Fork     bool `json:"fork"`
+	Archived bool `json:"archived"`
+}
+
+// GitHubClient reads the repositories of GitHub organizations and users.
+type GitHubClient struct {
+	httpClient *http.Client
+	baseURL    string
+	token      string
+	logger     *slog.Logger
+}
+
+// Option represents a configuration option for GitHubClient.
+type Option func(*Config)
+
+// Config holds the configuration for the GitHub client.
+type Config struct {
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithBaseURL overrides the GitHub API base URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewGitHubClient creates a client authenticating with token, which may be
+// empty for public data at a lower rate limit.
+func NewGitHubClient(token string, opts ...Option) *GitHubClient {
+	cfg := &Config{
+		baseURL: defaultBaseURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &GitHubClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:    strings.TrimSuffix(cfg.baseURL, "/"),
+		token:      token,
+		logger:     cfg.logger,
+	}
+}
+
+// ListRepositories returns the repositories of an organization, or of a
+// user when no organization has that name.
+func (c *GitHubClient) ListRepositories(ctx context.Context, owner string) ([]Repository, error) {
+	owner = url.PathEscape(owner)
+	repos, err := c.listPages(ctx, "/orgs/"+owner+"/repos", url.Values{"type": {"all"}})
+	if errors.Is(err, errNotFound) {
+		repos, err = c.listPages(ctx, "/users/"+owner+"/repos", url.Values{"type": {"owner"}})
+	}
+	if errors.Is(err, errNotFound) {
+		return nil, fmt.Errorf("%w %q", ErrOwnerNotFound, owner)
+	}
+	return repos, err
+}
+
+// Languages returns the bytes of code in each language of a repository,
+// as detected by GitHub on its default branch.
+func (c *GitHubClient) Languages(ctx context.Context, owner, repo string) (map[string]int64, error) {
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/languages", c.baseURL, url.PathEscape(owner), url.PathEscape(repo))
+	languages := make(map[string]int64)
+	if _, err := c.get(ctx, endpoint, &languages); err != nil {
+		if errors.Is(err, errNotFound) {
+			return nil, fmt.Errorf("%w %s/%s", ErrRepositoryNotFound, owner, repo)
+		}
+		return nil, err
+	}
+	c.logger.Debug("read GitHub languages", "owner", owner, "repo", repo, "count", len(languages))
+	return languages, nil
+}
+
+// listPages fetches every page of a repository listing.
+func (c *GitHubClient) listPages(
+	ctx context.Context,
+	path string,
+	query url.Values,
+) ([]Repository, error) {
+	query.Set("per_page", fmt.Sprint(pageSize))
+	endpoint := c.baseURL + path + "?" + query.Encode()
+	var repos []Repository
+	for endpoint != "" {
+		var page []Repository
+		next, err := c.get(ctx, endpoint, &page)
+		if err != nil {
+			return nil, err
+		}
+		repos = append(repos, page...)
+		endpoint = next
+	}
+	c.logger.Debug("listed GitHub repositories", "path", path, "count", len(repos))
+	return repos, nil
+}
+
+// get fetches endpoint into target and returns the URL of the next page,
+// if any.
+func (c *GitHubClient) get(ctx context.Context, endpoint string, target any) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", fmt.Errorf("error creating GitHub request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.token)
+	}
+	start := time.Now()
+	next, err := c.send(req, target)
+	if !errors.Is(err, errNotFound) {
+		metrics.ObserveOutbound(metrics.ServiceGitHub, start, err)
+	}
+	return next, err
+}
+
+// send performs the HTTP round trip for get.
+func (c *GitHubClient) send(req *http.Request, target any) (string, error) {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("error calling GitHub: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode == http.StatusNotFound {
+		return "", errNotFound
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return "", fmt.Errorf(
+			"github returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(target); err != nil {
+		return "", fmt.Errorf("error reading GitHub response: %w", err)
+	}
+	return nextPage(resp.Header.Get("Link")), nil
+}
+
+// nextPage extracts the rel="next" URL from a Link header.
+func nextPage(link string) string {
+	for _, part := range strings.Split(link, ",") {
+		target, params, found := strings.Cut(strings.TrimSpace(part), ";")
+		if found && strings.Contains(params, `rel="next"`) {
+			return strings.Trim(strings.TrimSpace(target), "<>")
+		}
+	}
+	return ""
+}
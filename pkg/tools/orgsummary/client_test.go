@@ -0,0 +1,84 @@
+package orgsummary
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListRepositories_FallsBackToUser(t *testing.T) {
+	t.Parallel()
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "Bearer secret", r.Header.Get("Authorization"))
+		switch {
+		case r.URL.Path == "/orgs/jdoe/repos":
+			http.NotFound(w, r)
+		case r.URL.Path == "/users/jdoe/repos" && r.URL.Query().Get("page") == "":
+			assert.Equal(t, "100", r.URL.Query().Get("per_page"))
+			w.Header().Set("Link", `<`+server.URL+`/users/jdoe/repos?page=2>; rel="next", <`+
+				server.URL+`/users/jdoe/repos?page=2>; rel="last"`)
+			_, _ = w.Write([]byte(`[{"name": "first", "default_branch": "main", "size": 12}]`))
+		case r.URL.Path == "/users/jdoe/repos":
+			_, _ = w.Write([]byte(`[{"name": "second", "fork": true}]`))
+		default:
+			t.Errorf("unexpected request %s", r.URL)
+		}
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewGitHubClient("secret", WithBaseURL(server.URL))
+	repos, err := client.ListRepositories(context.Background(), "jdoe")
+	require.NoError(t, err)
+	require.Len(t, repos, 2)
+	assert.Equal(t, "first", repos[0].Name)
+	assert.Equal(t, "main", repos[0].DefaultBranch)
+	assert.Equal(t, 12, repos[0].Size)
+	assert.True(t, repos[1].Fork)
+}
+
+func TestListRepositories_UnknownOwner(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(server.Close)
+
+	client := NewGitHubClient("", WithBaseURL(server.URL))
+	_, err := client.ListRepositories(context.Background(), "nobody")
+	assert.ErrorContains(t, err, `no GitHub organization or user named "nobody"`)
+}
+
+func TestLanguages(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/dictybase/dcr-mcp/languages" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"Go":52340,"Shell":1200}`))
+	}))
+	t.Cleanup(server.Close)
+
+	client := NewGitHubClient("", WithBaseURL(server.URL))
+	languages, err := client.Languages(context.Background(), "dictybase", "dcr-mcp")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int64{"Go": 52340, "Shell": 1200}, languages)
+
+	_, err = client.Languages(context.Background(), "dictybase", "missing")
+	require.ErrorIs(t, err, ErrRepositoryNotFound)
+}
+
+func TestNextPage(t *testing.T) {
+	t.Parallel()
+	assert.Equal(
+		t,
+		"https://api.github.com/orgs/a/repos?page=3",
+		nextPage(`<https://api.github.com/orgs/a/repos?page=1>; rel="prev", `+
+			`<https://api.github.com/orgs/a/repos?page=3>; rel="next"`),
+	)
+	assert.Empty(t, nextPage(`<https://api.github.com/orgs/a/repos?page=1>; rel="first"`))
+	assert.Empty(t, nextPage(""))
+}
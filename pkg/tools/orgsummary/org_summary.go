@@ -0,0 +1,486 @@
+package orgsummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+const (
+	defaultMaxRepos      = 20
+	defaultMaxRepoSizeMB = 500
+	defaultConcurrency   = 4
+	maxConcurrency       = 8
+)
+
+// OrgSummaryTool summarizes the work done across all repositories of a
+// GitHub organization or user. The GitHub API is called with the token in
+// GITHUB_TOKEN, when set.
+type OrgSummaryTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	analyzer      *worksummary.GitAnalyzer
+	resources     *resources.Catalog
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring OrgSummaryTool.
+type ToolOption func(*OrgSummaryTool)
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(o *OrgSummaryTool) {
+		o.resources = catalog
+	}
+}
+
+// WithClientOptions sets options for the GitHub clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(o *OrgSummaryTool) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// WithAnalyzer replaces the analyzer repositories are read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(o *OrgSummaryTool) {
+		o.analyzer = analyzer
+	}
+}
+
+// OrgSummaryRequest represents the parameters for the organization summary.
+type OrgSummaryRequest struct {
+	Owner     string `validate:"required"`
+	StartDate string `validate:"required"`
+	EndDate   string
+	// Author limits the report to commits whose author name contains it.
+	Author       string
+	IncludeForks bool
+	// MaxRepos caps the number of repositories cloned; the most recently
+	// pushed ones are kept.
+	MaxRepos int `validate:"gte=1,lte=100"`
+	// MaxRepoSizeMB skips repositories larger than this.
+	MaxRepoSizeMB int `validate:"gte=1"`
+	// Concurrency is the number of repositories cloned at once.
+	Concurrency int  `validate:"gte=1,lte=8"`
+	Summarize   bool `validate:"-"`
+}
+
+// repoLister lists the repositories of an owner.
+type repoLister interface {
+	ListRepositories(ctx context.Context, owner string) ([]Repository, error)
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"org-summary",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewOrgSummaryTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewOrgSummaryTool creates a new OrgSummaryTool instance.
+func NewOrgSummaryTool(logger *slog.Logger, opts ...ToolOption) (*OrgSummaryTool, error) {
+	tool := mcp.NewTool(
+		"org-summary",
+		mcp.WithDescription(
+			"Summarizes the work done within a date range across all repositories of a GitHub organization or user",
+		),
+		mcp.WithTitleAnnotation("Organization Summary"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"owner",
+			mcp.Description("The GitHub organization or user"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"start_date",
+			mcp.Description("The start date for commit analysis"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"end_date",
+			mcp.Description("The end date for commit analysis (optional, defaults to today)"),
+		),
+		mcp.WithString(
+			"author",
+			mcp.Description("Only include commits by this author name (optional)"),
+		),
+		mcp.WithBoolean(
+			"include_forks",
+			mcp.Description("Include forked repositories, defaults to false"),
+		),
+		mcp.WithNumber(
+			"max_repos",
+			mcp.Description(fmt.Sprintf(
+				"Maximum number of active repositories to clone, the most recently pushed first (defaults to %d)",
+				defaultMaxRepos,
+			)),
+		),
+		mcp.WithNumber(
+			"max_repo_size_mb",
+			mcp.Description(fmt.Sprintf(
+				"Skip repositories larger than this many megabytes (defaults to %d)",
+				defaultMaxRepoSizeMB,
+			)),
+		),
+		mcp.WithNumber(
+			"concurrency",
+			mcp.Description(fmt.Sprintf(
+				"Number of repositories cloned at once, at most %d (defaults to %d)",
+				maxConcurrency,
+				defaultConcurrency,
+			)),
+		),
+		mcp.WithBoolean(
+			"summarize",
+			mcp.Description(
+				"Add highlights written by the language model, defaults to true; requires OPENAI_API_KEY",
+			),
+		),
+		idempotency.WithKey(),
+	)
+	orgSummaryTool := &OrgSummaryTool{
+		Name:        "org-summary",
+		Description: "Summarizes the work done across the repositories of a GitHub organization",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(orgSummaryTool)
+	}
+	return orgSummaryTool, nil
+}
+
+// GetName returns the name of the tool.
+func (o *OrgSummaryTool) GetName() string {
+	return o.Name
+}
+
+// GetDescription returns the description of the tool.
+func (o *OrgSummaryTool) GetDescription() string {
+	return o.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (o *OrgSummaryTool) GetSchema() mcp.ToolInputSchema {
+	return o.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (o *OrgSummaryTool) GetTool() mcp.Tool {
+	return o.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (o *OrgSummaryTool) GetAnnotations() mcp.ToolAnnotation {
+	return o.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (o *OrgSummaryTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Summarize the work across an organization's repositories in the first quarter of 2024",
+			Arguments: map[string]any{
+				"owner":      "dictybase",
+				"start_date": "2024-01-01",
+				"end_date":   "2024-03-31",
+				"max_repos":  20,
+			},
+		},
+		{
+			Description: "List one developer's activity across an organization without an AI summary",
+			Arguments: map[string]any{
+				"owner":      "dictybase-docker",
+				"start_date": "2024-06-01",
+				"author":     "Jane Doe",
+				"summarize":  false,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (o *OrgSummaryTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := OrgSummaryRequest{
+		Owner:         request.GetString("owner", ""),
+		StartDate:     request.GetString("start_date", ""),
+		EndDate:       request.GetString("end_date", ""),
+		Author:        request.GetString("author", ""),
+		IncludeForks:  request.GetBool("include_forks", false),
+		MaxRepos:      request.GetInt("max_repos", defaultMaxRepos),
+		MaxRepoSizeMB: request.GetInt("max_repo_size_mb", defaultMaxRepoSizeMB),
+		Concurrency:   request.GetInt("concurrency", defaultConcurrency),
+		Summarize:     request.GetBool("summarize", true),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	var summarizer worksummary.SummaryClient
+	if params.Summarize {
+		apiKey := os.Getenv("OPENAI_API_KEY")
+		if apiKey == "" {
+			return toolerror.Result(toolerror.New(
+				toolerror.TypeConfiguration,
+				"MISSING_OPENAI_API_KEY",
+				"OPENAI_API_KEY is not set on the server; set summarize to false for a report without highlights",
+			)), nil
+		}
+		client, err := worksummary.NewOpenAIClient(apiKey)
+		if err != nil {
+			return toolerror.Result(toolerror.Wrap(
+				toolerror.TypeConfiguration,
+				"OPENAI_CLIENT",
+				err,
+				"error initializing OpenAI client",
+			)), nil
+		}
+		summarizer = client
+	}
+	github := NewGitHubClient(
+		os.Getenv("GITHUB_TOKEN"),
+		append([]Option{WithLogger(o.Logger)}, o.clientOptions...)...,
+	)
+
+	report, err := o.GenerateReport(ctx, github, summarizer, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(report)
+	providers := []string{metrics.ServiceGitHub, metrics.ServiceGitClone}
+	if report.Highlights != "" {
+		providers = append(providers, metrics.ServiceOpenAI)
+	}
+	result := provenance.Attach(mcp.NewToolResultText(content), provenance.New(providers))
+	if o.resources != nil {
+		resource, err := o.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        fmt.Sprintf("%s-org-summary-%s.md", params.Owner, params.StartDate),
+			MIMEType:    "text/markdown",
+			Description: "Work summary of the repositories of " + params.Owner,
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing summary: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// GenerateReport lists the owner's repositories, reads the commits of the
+// active ones and, when summarizer is set, adds highlights of all of them.
+func (o *OrgSummaryTool) GenerateReport(
+	ctx context.Context,
+	lister repoLister,
+	summarizer worksummary.SummaryClient,
+	params OrgSummaryRequest,
+) (Report, error) {
+	dateRange, err := o.analyzer.ResolveDateRange(params.StartDate, params.EndDate)
+	if err != nil {
+		return Report{}, toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_DATE_RANGE",
+			err,
+			"failed to parse dates",
+		)
+	}
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "listing repositories of "+params.Owner)
+	repos, err := lister.ListRepositories(ctx, params.Owner)
+	if errors.Is(err, ErrOwnerNotFound) {
+		return Report{}, toolerror.Wrap(toolerror.TypeNotFound, "GITHUB_OWNER_NOT_FOUND", err, "failed to list repositories")
+	}
+	if err != nil {
+		return Report{}, toolerror.Upstream(metrics.ServiceGitHub, err, "failed to list repositories")
+	}
+	selected, skipped := selectRepos(repos, params, dateRange)
+	report := Report{Owner: params.Owner, Range: dateRange, Skipped: skipped}
+
+	const readShare = 0.8
+	activities := o.readRepos(
+		progress.NewContext(ctx, reporter.Sub(0, readShare)),
+		selected,
+		params,
+		dateRange,
+	)
+	if err := ctx.Err(); err != nil {
+		return Report{}, fmt.Errorf("organization summary aborted: %w", err)
+	}
+	for _, activity := range activities {
+		if activity.Err == nil && len(activity.Commits) == 0 {
+			report.Quiet = append(report.Quiet, activity.Repository.Name)
+			continue
+		}
+		report.Repos = append(report.Repos, activity)
+	}
+
+	input := summaryInput(report.Repos)
+	if summarizer != nil && input != "" {
+		reporter.Report(readShare, 1, "generating highlights")
+		highlights, err := summarizer.SummarizeCommitMessages(
+			progress.NewContext(ctx, reporter.Sub(readShare, 1)),
+			input,
+		)
+		if err != nil {
+			return Report{}, toolerror.Upstream(metrics.ServiceOpenAI, err, "failed to summarize commits")
+		}
+		report.Highlights = highlights
+	}
+	reporter.Report(1, 1, "report generated")
+	return report, nil
+}
+
+// selectRepos picks the repositories pushed to since the start of the
+// range and lists those left out by the guards. Private repositories are
+// skipped because they are cloned without credentials.
+func selectRepos(
+	repos []Repository,
+	params OrgSummaryRequest,
+	dateRange worksummary.DateRange,
+) ([]Repository, []SkippedRepo) {
+	var active []Repository
+	var skipped []SkippedRepo
+	for _, repo := range repos {
+		switch {
+		case repo.PushedAt.Before(dateRange.Start):
+			continue
+		case repo.Fork && !params.IncludeForks:
+			continue
+		case repo.Private:
+			skipped = append(skipped, SkippedRepo{Name: repo.Name, Reason: "private"})
+		case repo.Size > params.MaxRepoSizeMB*1024:
+			skipped = append(skipped, SkippedRepo{
+				Name:   repo.Name,
+				Reason: fmt.Sprintf("larger than %d MB", params.MaxRepoSizeMB),
+			})
+		default:
+			active = append(active, repo)
+		}
+	}
+	slices.SortStableFunc(active, func(a, b Repository) int {
+		return b.PushedAt.Compare(a.PushedAt)
+	})
+	if len(active) > params.MaxRepos {
+		for _, repo := range active[params.MaxRepos:] {
+			skipped = append(skipped, SkippedRepo{
+				Name:   repo.Name,
+				Reason: fmt.Sprintf("beyond max_repos (%d)", params.MaxRepos),
+			})
+		}
+		active = active[:params.MaxRepos]
+	}
+	return active, skipped
+}
+
+// readRepos clones the repositories, at most params.Concurrency at a time,
+// and lists their commits within the range. A repository that cannot be
+// read is reported with its error.
+func (o *OrgSummaryTool) readRepos(
+	ctx context.Context,
+	repos []Repository,
+	params OrgSummaryRequest,
+	dateRange worksummary.DateRange,
+) []RepoActivity {
+	logger := logging.WithRequestID(o.Logger)
+	reporter := progress.FromContext(ctx)
+	activities := make([]RepoActivity, len(repos))
+	semaphore := make(chan struct{}, params.Concurrency)
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	done := 0
+	for i, repo := range repos {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case semaphore <- struct{}{}:
+				defer func() { <-semaphore }()
+			case <-ctx.Done():
+				activities[i] = RepoActivity{Repository: repo, Err: ctx.Err()}
+				return
+			}
+			activity := o.readRepo(ctx, repo, params.Author, dateRange)
+			if activity.Err != nil {
+				logger.Warn("failed to read repository", "repo", repo.FullName, "error", activity.Err)
+			}
+			activities[i] = activity
+			mu.Lock()
+			done++
+			reporter.Report(float64(done), float64(len(repos)), "read "+repo.Name)
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return activities
+}
+
+// readRepo lists the commits on the default branch of repo within the
+// range, optionally limited to an author.
+func (o *OrgSummaryTool) readRepo(
+	ctx context.Context,
+	repo Repository,
+	author string,
+	dateRange worksummary.DateRange,
+) RepoActivity {
+	activity := RepoActivity{Repository: repo}
+	cloned, err := o.analyzer.CloneAndCheckout(
+		ctx,
+		repo.CloneURL,
+		repo.DefaultBranch,
+		worksummary.WithExpectedSize(int64(repo.Size)<<10),
+	)
+	if err != nil {
+		activity.Err = err
+		return activity
+	}
+	defer cloned.Close()
+	if author != "" {
+		activity.Commits, activity.Err = o.analyzer.ListAuthorCommits(ctx, worksummary.CommitRangeParams{
+			Repo:    cloned.Repository,
+			Start:   dateRange.Start,
+			End:     dateRange.End,
+			Authors: worksummary.AuthorFilter{Include: []string{author}},
+		})
+		return activity
+	}
+	activity.Commits, activity.Err = o.analyzer.ListActivity(ctx, worksummary.ActivityParams{
+		Repo:  cloned.Repository,
+		Start: dateRange.Start,
+		End:   dateRange.End,
+	})
+	return activity
+}
@@ -0,0 +1,141 @@
+package orgsummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// staticLister returns fixed repositories.
+type staticLister struct {
+	repos []Repository
+	err   error
+}
+
+// ListRepositories implements repoLister.
+func (s staticLister) ListRepositories(context.Context, string) ([]Repository, error) {
+	return s.repos, s.err
+}
+
+// dailyCommits are a commit by author per message, one day apart from
+// when on.
+func dailyCommits(author string, when time.Time, messages ...string) []gittest.Commit {
+	commits := make([]gittest.Commit, 0, len(messages))
+	for i, message := range messages {
+		commits = append(commits, gittest.Commit{
+			Message: message,
+			Author:  object.Signature{Name: author, Email: author + "@example.org", When: when.AddDate(0, 0, i)},
+		})
+	}
+	return commits
+}
+
+// recordingSummarizer returns a fixed summary and records its input.
+type recordingSummarizer struct {
+	input string
+}
+
+// SummarizeCommitMessages implements worksummary.SummaryClient.
+func (r *recordingSummarizer) SummarizeCommitMessages(_ context.Context, commitMsgs string) (string, error) {
+	r.input = commitMsgs
+	return "# Work Summary\n\n- Stock orders were added.", nil
+}
+
+func TestGenerateReport(t *testing.T) {
+	t.Parallel()
+	june := time.Date(2025, 6, 2, 12, 0, 0, 0, time.UTC)
+	repos := []Repository{
+		{
+			Name:          "api",
+			CloneURL:      gittest.NewRepo(t, dailyCommits("Jane", june, "feat: add stock orders", "fix: paging")...).Dir,
+			DefaultBranch: "master",
+			PushedAt:      june,
+		},
+		{
+			Name:          "quiet",
+			CloneURL:      gittest.NewRepo(t, dailyCommits("Joe", june.AddDate(0, -2, 0), "chore: old work")...).Dir,
+			DefaultBranch: "master",
+			PushedAt:      june,
+		},
+		{Name: "stale", PushedAt: june.AddDate(-1, 0, 0)},
+	}
+	analyzer := worksummary.NewGitAnalyzer(
+		worksummary.WithCurrentTime(time.Date(2025, 7, 15, 0, 0, 0, 0, time.UTC)),
+		worksummary.WithTimeZone(time.UTC),
+	)
+	tool, err := NewOrgSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), WithAnalyzer(analyzer))
+	require.NoError(t, err)
+
+	summarizer := &recordingSummarizer{}
+	report, err := tool.GenerateReport(
+		context.Background(),
+		staticLister{repos: repos},
+		summarizer,
+		OrgSummaryRequest{
+			Owner:         "dictybase",
+			StartDate:     "2025-06-01",
+			EndDate:       "2025-06-30",
+			MaxRepos:      10,
+			MaxRepoSizeMB: 100,
+			Concurrency:   2,
+		},
+	)
+	require.NoError(t, err)
+	require.Len(t, report.Repos, 1)
+	assert.Equal(t, "api", report.Repos[0].Repository.Name)
+	require.Len(t, report.Repos[0].Commits, 2)
+	assert.Equal(t, "fix: paging", report.Repos[0].Commits[0].Subject)
+	assert.Equal(t, []string{"quiet"}, report.Quiet)
+	assert.Equal(t, "Repository api:\nfix: paging\nfeat: add stock orders\n\n", summarizer.input)
+	assert.Equal(t, "# Work Summary\n\n- Stock orders were added.", report.Highlights)
+}
+
+func TestGenerateReport_ListFailure(t *testing.T) {
+	t.Parallel()
+	tool, err := NewOrgSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	_, err = tool.GenerateReport(
+		context.Background(),
+		staticLister{err: errors.New("github returned status 403")},
+		nil,
+		OrgSummaryRequest{Owner: "dictybase", StartDate: "2025-06-01", MaxRepos: 1, MaxRepoSizeMB: 1, Concurrency: 1},
+	)
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, toolerror.TypeAPIError, toolErr.Type)
+
+	_, err = tool.GenerateReport(
+		context.Background(),
+		staticLister{err: fmt.Errorf("%w %q", ErrOwnerNotFound, "nobody")},
+		nil,
+		OrgSummaryRequest{Owner: "nobody", StartDate: "2025-06-01", MaxRepos: 1, MaxRepoSizeMB: 1, Concurrency: 1},
+	)
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, toolerror.TypeNotFound, toolErr.Type)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool, err := NewOrgSummaryTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "org-summary"
+	request.Params.Arguments = map[string]any{"owner": "dictybase", "start_date": "2025-06-01", "concurrency": 20}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+}
@@ -0,0 +1,205 @@
+package orgsummary
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+const (
+	// maxCommitsPerRepo is the number of commits listed per repository.
+	maxCommitsPerRepo = 10
+	// maxContributors is the number of contributors named per repository.
+	maxContributors = 5
+	// maxSummaryInput caps the commit messages sent to the language model.
+	maxSummaryInput = 64 << 10
+)
+
+// Report is the content of one organization summary.
+type Report struct {
+	Owner string
+	Range worksummary.DateRange
+	// Repos are the repositories with commits in the range, or that could
+	// not be read.
+	Repos []RepoActivity
+	// Quiet names the repositories pushed to without commits in the range
+	// on their default branch.
+	Quiet   []string
+	Skipped []SkippedRepo
+	// Highlights is the model's summary across all repositories, empty
+	// when not requested.
+	Highlights string
+}
+
+// RepoActivity holds the commits made to a repository within the range.
+type RepoActivity struct {
+	Repository Repository
+	Commits    []worksummary.Commit
+	// Err is set when the repository could not be read.
+	Err error
+}
+
+// SkippedRepo is an active repository left out by a guard.
+type SkippedRepo struct {
+	Name   string
+	Reason string
+}
+
+// RenderMarkdown renders the report as a markdown document.
+func RenderMarkdown(report Report) string {
+	repos := slices.Clone(report.Repos)
+	slices.SortStableFunc(repos, func(a, b RepoActivity) int {
+		if c := cmp.Compare(len(b.Commits), len(a.Commits)); c != 0 {
+			return c
+		}
+		return cmp.Compare(a.Repository.Name, b.Repository.Name)
+	})
+
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Organization Summary: %s\n\n", report.Owner)
+	builder.WriteString(report.Range.Header())
+	if report.Highlights != "" {
+		builder.WriteString("\n## Highlights\n\n")
+		builder.WriteString(highlights(report.Highlights))
+		builder.WriteString("\n")
+	}
+	writeOverview(&builder, repos)
+	for _, activity := range repos {
+		writeRepo(&builder, activity)
+	}
+	writeLeftOut(&builder, report)
+	return builder.String()
+}
+
+// writeOverview renders the table of repositories and their commit counts.
+func writeOverview(builder *strings.Builder, repos []RepoActivity) {
+	builder.WriteString("\n## Repositories\n\n")
+	if len(repos) == 0 {
+		builder.WriteString("No commits found in the specified date range.\n")
+		return
+	}
+	total := 0
+	builder.WriteString("| Repository | Commits | Contributors |\n|---|---|---|\n")
+	for _, activity := range repos {
+		if activity.Err != nil {
+			fmt.Fprintf(builder, "| [%s](%s) | _unavailable_ | |\n",
+				activity.Repository.Name, activity.Repository.HTMLURL)
+			continue
+		}
+		total += len(activity.Commits)
+		fmt.Fprintf(builder, "| [%s](%s) | %d | %s |\n",
+			activity.Repository.Name,
+			activity.Repository.HTMLURL,
+			len(activity.Commits),
+			strings.Join(contributors(activity.Commits), ", "),
+		)
+	}
+	fmt.Fprintf(builder, "\n%d commits across %d repositories.\n", total, len(repos))
+}
+
+// writeRepo renders the latest commits of a repository.
+func writeRepo(builder *strings.Builder, activity RepoActivity) {
+	repo := activity.Repository
+	fmt.Fprintf(builder, "\n### %s\n\n", repo.Name)
+	if activity.Err != nil {
+		fmt.Fprintf(builder, "_Unavailable: %v_\n", activity.Err)
+		return
+	}
+	base, linked := worksummary.CommitURLBase(repo.HTMLURL)
+	for _, commit := range activity.Commits[:min(len(activity.Commits), maxCommitsPerRepo)] {
+		hash := "`" + worksummary.ShortHash(commit.Hash) + "`"
+		if linked {
+			hash = fmt.Sprintf("[%s](%s%s)", hash, base, commit.Hash)
+		}
+		fmt.Fprintf(builder, "- %s (%s, %s, %s)\n",
+			commit.Subject, commit.Author, commit.When.Format(time.DateOnly), hash)
+	}
+	if more := len(activity.Commits) - maxCommitsPerRepo; more > 0 {
+		fmt.Fprintf(builder, "- … and %d more\n", more)
+	}
+}
+
+// writeLeftOut lists the repositories without commits and those skipped by
+// the guards.
+func writeLeftOut(builder *strings.Builder, report Report) {
+	if len(report.Quiet) == 0 && len(report.Skipped) == 0 {
+		return
+	}
+	builder.WriteString("\n## Not Included\n\n")
+	if len(report.Quiet) > 0 {
+		quiet := slices.Sorted(slices.Values(report.Quiet))
+		fmt.Fprintf(builder, "- No commits in range: %s\n", strings.Join(quiet, ", "))
+	}
+	for _, skipped := range report.Skipped {
+		fmt.Fprintf(builder, "- %s: skipped, %s\n", skipped.Name, skipped.Reason)
+	}
+}
+
+// contributors names the most frequent authors of commits, most commits
+// first.
+func contributors(commits []worksummary.Commit) []string {
+	counts := make(map[string]int)
+	for _, commit := range commits {
+		counts[commit.Author]++
+	}
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	slices.SortFunc(names, func(a, b string) int {
+		if c := cmp.Compare(counts[b], counts[a]); c != 0 {
+			return c
+		}
+		return cmp.Compare(a, b)
+	})
+	if len(names) > maxContributors {
+		names = append(names[:maxContributors], fmt.Sprintf("+%d", len(names)-maxContributors))
+	}
+	return names
+}
+
+// summaryInput joins the commit messages of all repositories, each under
+// its repository name, for the language model. Messages beyond
+// maxSummaryInput are dropped, so the most recently pushed repositories and
+// their newest commits are kept.
+func summaryInput(repos []RepoActivity) string {
+	var builder strings.Builder
+	for _, activity := range repos {
+		if activity.Err != nil || len(activity.Commits) == 0 {
+			continue
+		}
+		section := fmt.Sprintf("Repository %s:\n", activity.Repository.Name)
+		if builder.Len()+len(section) > maxSummaryInput {
+			break
+		}
+		builder.WriteString(section)
+		for _, commit := range activity.Commits {
+			message := strings.TrimSpace(commit.Message) + "\n"
+			if builder.Len()+len(message) > maxSummaryInput {
+				break
+			}
+			builder.WriteString(message)
+		}
+		builder.WriteString("\n")
+	}
+	return builder.String()
+}
+
+// highlights drops the title of the model's summary and moves its other
+// headings below the report's Highlights section.
+func highlights(text string) string {
+	lines := strings.Split(strings.TrimSpace(text), "\n")
+	if strings.HasPrefix(lines[0], "# ") {
+		lines = lines[1:]
+	}
+	for i, line := range lines {
+		if strings.HasPrefix(line, "#") {
+			lines[i] = "#" + line
+		}
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n"))
+}
@@ -0,0 +1,112 @@
+package orgsummary
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSelectRepos(t *testing.T) {
+	t.Parallel()
+	start := time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC)
+	dateRange := worksummary.DateRange{Start: start, End: start.AddDate(0, 1, 0)}
+	repos := []Repository{
+		{Name: "stale", PushedAt: start.AddDate(0, -1, 0)},
+		{Name: "fork", Fork: true, PushedAt: start.AddDate(0, 0, 1)},
+		{Name: "private", Private: true, PushedAt: start.AddDate(0, 0, 1)},
+		{Name: "huge", Size: 3 * 1024, PushedAt: start.AddDate(0, 0, 1)},
+		{Name: "older", PushedAt: start.AddDate(0, 0, 2)},
+		{Name: "newest", PushedAt: start.AddDate(0, 0, 5)},
+		{Name: "newer", PushedAt: start.AddDate(0, 0, 3)},
+	}
+	params := OrgSummaryRequest{MaxRepos: 2, MaxRepoSizeMB: 2}
+
+	selected, skipped := selectRepos(repos, params, dateRange)
+	names := make([]string, 0, len(selected))
+	for _, repo := range selected {
+		names = append(names, repo.Name)
+	}
+	assert.Equal(t, []string{"newest", "newer"}, names)
+	assert.Equal(t, []SkippedRepo{
+		{Name: "private", Reason: "private"},
+		{Name: "huge", Reason: "larger than 2 MB"},
+		{Name: "older", Reason: "beyond max_repos (2)"},
+	}, skipped)
+
+	params.IncludeForks = true
+	params.MaxRepos = 10
+	selected, _ = selectRepos(repos, params, dateRange)
+	assert.Len(t, selected, 4)
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	when := time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC)
+	report := Report{
+		Owner: "dictybase",
+		Range: worksummary.DateRange{
+			Start:      time.Date(2025, 6, 1, 0, 0, 0, 0, time.UTC),
+			End:        time.Date(2025, 6, 30, 23, 59, 0, 0, time.UTC),
+			StartInput: "2025-06-01",
+		},
+		Repos: []RepoActivity{
+			{
+				Repository: Repository{Name: "broken", HTMLURL: "https://github.com/dictybase/broken"},
+				Err:        errors.New("clone failed"),
+			},
+			{
+				Repository: Repository{Name: "api", HTMLURL: "https://github.com/dictybase/api"},
+				Commits: []worksummary.Commit{
+					{Hash: "1111111aaaa", Author: "Jane", When: when, Subject: "feat: add stock endpoint"},
+					{Hash: "2222222bbbb", Author: "Joe", When: when, Subject: "fix: paging"},
+					{Hash: "3333333cccc", Author: "Jane", When: when, Subject: "docs: readme"},
+				},
+			},
+		},
+		Quiet:      []string{"web", "cli"},
+		Skipped:    []SkippedRepo{{Name: "huge", Reason: "larger than 500 MB"}},
+		Highlights: "# Work Summary\n\n## Stocks\n- Stock orders can be placed online.",
+	}
+
+	rendered := RenderMarkdown(report)
+	assert.True(t, strings.HasPrefix(rendered, "# Organization Summary: dictybase\n\n**Date range:**"))
+	assert.Contains(t, rendered, "## Highlights\n\n### Stocks\n- Stock orders can be placed online.\n")
+	assert.NotContains(t, rendered, "# Work Summary")
+	assert.Contains(t, rendered, "| [api](https://github.com/dictybase/api) | 3 | Jane, Joe |\n"+
+		"| [broken](https://github.com/dictybase/broken) | _unavailable_ | |\n")
+	assert.Contains(t, rendered, "3 commits across 2 repositories.")
+	assert.Contains(t, rendered, "- feat: add stock endpoint (Jane, 2025-06-10, "+
+		"[`1111111`](https://github.com/dictybase/api/commit/1111111aaaa))\n")
+	assert.Contains(t, rendered, "### broken\n\n_Unavailable: clone failed_\n")
+	assert.Contains(t, rendered, "## Not Included\n\n- No commits in range: cli, web\n"+
+		"- huge: skipped, larger than 500 MB\n")
+	assert.Less(t, strings.Index(rendered, "### api"), strings.Index(rendered, "### broken"))
+}
+
+func TestContributors(t *testing.T) {
+	t.Parallel()
+	var commits []worksummary.Commit
+	for _, author := range []string{"a", "b", "b", "c", "d", "e", "f", "g"} {
+		commits = append(commits, worksummary.Commit{Author: author})
+	}
+	assert.Equal(t, []string{"b", "a", "c", "d", "e", "+2"}, contributors(commits))
+}
+
+func TestSummaryInput(t *testing.T) {
+	t.Parallel()
+	repos := []RepoActivity{
+		{Repository: Repository{Name: "broken"}, Err: errors.New("clone failed")},
+		{
+			Repository: Repository{Name: "api"},
+			Commits: []worksummary.Commit{
+				{Message: "feat: add stock endpoint\n\nDetails."},
+				{Message: strings.Repeat("x", maxSummaryInput)},
+			},
+		},
+	}
+	assert.Equal(t, "Repository api:\nfeat: add stock endpoint\n\nDetails.\n\n", summaryInput(repos))
+}
@@ -0,0 +1,73 @@
+package pdftool
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+)
+
+// Chapter modes of the chapters argument.
+const (
+	// ChaptersAuto splits documents longer than chapterThreshold.
+	ChaptersAuto = "auto"
+	// ChaptersSplit splits every document with more than one chapter.
+	ChaptersSplit = "split"
+	// ChaptersNone renders every document as a single PDF.
+	ChaptersNone = "none"
+)
+
+// chapterModes lists the accepted chapter modes, for the tool schema.
+var chapterModes = []string{ChaptersAuto, ChaptersSplit, ChaptersNone}
+
+// chapterThreshold is the length in bytes of markdown above which
+// ChaptersAuto splits a document, roughly fifty pages of report.
+const chapterThreshold = 100_000
+
+// part is one PDF rendered from a document.
+type part struct {
+	// title is the chapter heading, empty for a document that is not split.
+	title    string
+	filename string
+	content  string
+}
+
+// splitDocument returns the PDFs to render content as, named after
+// filename. With mode and level allowing it, each chapter at level, or at
+// the highest heading level occurring twice when level is 0, becomes its
+// own PDF named filename-01-slug.pdf.
+func splitDocument(content, filename, mode string, level int) ([]part, error) {
+	if !slices.Contains(chapterModes, mode) {
+		return nil, fmt.Errorf("chapters must be one of %s, got %q", strings.Join(chapterModes, ", "), mode)
+	}
+	if level < 0 || level > 6 {
+		return nil, errors.New("chapter_level must be between 1 and 6")
+	}
+	whole := []part{{filename: filename, content: content}}
+	if mode == ChaptersNone || (mode == ChaptersAuto && len(content) <= chapterThreshold) {
+		return whole, nil
+	}
+	if level == 0 {
+		level = markdown.ChapterLevel([]byte(content))
+	}
+	if level == 0 {
+		return whole, nil
+	}
+	chapters := markdown.SplitChapters([]byte(content), level)
+	if len(chapters) < 2 {
+		return whole, nil
+	}
+	base := strings.TrimSuffix(filename, path.Ext(filename))
+	parts := make([]part, 0, len(chapters))
+	for i, chapter := range chapters {
+		parts = append(parts, part{
+			title:    chapter.Title,
+			filename: fmt.Sprintf("%s-%02d-%s.pdf", base, i+1, chapter.Slug()),
+			content:  chapter.Content,
+		})
+	}
+	return parts, nil
+}
@@ -0,0 +1,41 @@
+package pdftool
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDocument(t *testing.T) {
+	t.Parallel()
+	report := "# Org Summary\n\n## Stock Center\n\nOrders.\n\n## Annotations\n\nGO terms.\n"
+
+	parts, err := splitDocument(report, "reports/org.pdf", ChaptersSplit, 0)
+	require.NoError(t, err)
+	require.Len(t, parts, 2)
+	assert.Equal(t, "Stock Center", parts[0].title)
+	assert.Equal(t, "reports/org-01-stock-center.pdf", parts[0].filename)
+	assert.True(t, strings.HasPrefix(parts[0].content, "# Org Summary\n"), "the title opens the first chapter")
+	assert.Equal(t, "reports/org-02-annotations.pdf", parts[1].filename)
+
+	parts, err = splitDocument(report, "org.pdf", ChaptersAuto, 0)
+	require.NoError(t, err)
+	assert.Equal(t, []part{{filename: "org.pdf", content: report}}, parts, "short reports stay whole")
+	long := report + strings.Repeat("More text.\n", chapterThreshold/10)
+	parts, err = splitDocument(long, "org.pdf", ChaptersAuto, 0)
+	require.NoError(t, err)
+	assert.Len(t, parts, 2)
+	parts, err = splitDocument(long, "org.pdf", ChaptersNone, 0)
+	require.NoError(t, err)
+	assert.Len(t, parts, 1)
+	parts, err = splitDocument(report, "org.pdf", ChaptersSplit, 1)
+	require.NoError(t, err)
+	assert.Len(t, parts, 1, "a single chapter is not split")
+
+	_, err = splitDocument(report, "org.pdf", "pages", 0)
+	require.ErrorContains(t, err, "chapters must be one of auto, split, none")
+	_, err = splitDocument(report, "org.pdf", ChaptersSplit, 7)
+	require.Error(t, err)
+}
@@ -0,0 +1,105 @@
+package pdftool
+
+import (
+	"strings"
+
+	pdf "github.com/stephenafamo/goldmark-pdf"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// outlineDepth is the deepest heading level, 3 for ###, that gets a
+// bookmark in the PDF outline.
+const outlineDepth = 3
+
+// kindBookmark is the node kind of bookmark.
+var kindBookmark = ast.NewNodeKind("PDFBookmark")
+
+// bookmark marks the position of a heading in the PDF outline. It is the
+// first child of the heading, so it renders after the spacing above the
+// heading and before its text.
+type bookmark struct {
+	ast.BaseInline
+	title string
+	// level is the depth in the outline, 0 for the top.
+	level int
+}
+
+// Kind returns kindBookmark.
+func (b *bookmark) Kind() ast.NodeKind {
+	return kindBookmark
+}
+
+// Dump dumps the bookmark for debugging.
+func (b *bookmark) Dump(source []byte, level int) {
+	ast.DumpHelper(b, source, level, map[string]string{"Title": b.title}, nil)
+}
+
+// outlineTransformer adds a bookmark to each heading up to outlineDepth,
+// leaving out headings within block quotes and lists.
+// Outline levels count from the highest heading level used, so a report
+// made of ## sections has them at the top of the outline, and a skipped
+// level, such as ### right below #, nests one level deeper only.
+type outlineTransformer struct{}
+
+// Transform adds the bookmarks to the headings of document.
+func (outlineTransformer) Transform(document *ast.Document, reader text.Reader, _ parser.Context) {
+	var headings []*ast.Heading
+	top := outlineDepth + 1
+	for child := document.FirstChild(); child != nil; child = child.NextSibling() {
+		if heading, ok := child.(*ast.Heading); ok && heading.Level <= outlineDepth {
+			headings = append(headings, heading)
+			top = min(top, heading.Level)
+		}
+	}
+	previous := -1
+	for _, heading := range headings {
+		title := headingText(heading, reader.Source())
+		if title == "" {
+			continue
+		}
+		level := min(heading.Level-top, previous+1)
+		previous = level
+		heading.InsertBefore(heading, heading.FirstChild(), &bookmark{title: title, level: level})
+	}
+}
+
+// headingText returns the text of heading without its markup.
+func headingText(heading ast.Node, source []byte) string {
+	var title strings.Builder
+	_ = ast.Walk(heading, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if !entering {
+			return ast.WalkContinue, nil
+		}
+		switch node := n.(type) {
+		case *ast.Text:
+			title.Write(node.Segment.Value(source))
+			if node.SoftLineBreak() || node.HardLineBreak() {
+				title.WriteByte(' ')
+			}
+		case *ast.String:
+			title.Write(node.Value)
+		}
+		return ast.WalkContinue, nil
+	})
+	return strings.TrimSpace(title.String())
+}
+
+// outlineRenderer renders bookmarks as entries of the PDF outline.
+type outlineRenderer struct{}
+
+// RegisterFuncs registers the rendering of bookmarks.
+func (outlineRenderer) RegisterFuncs(reg pdf.NodeRendererFuncRegisterer) {
+	reg.Register(kindBookmark, renderBookmark)
+}
+
+// renderBookmark adds the bookmark to the outline at the current position.
+// PDF backends other than the default one have no outline and skip it.
+func renderBookmark(w *pdf.Writer, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	mark, isBookmark := n.(*bookmark)
+	if fpdf, ok := w.Pdf.(*pdf.Fpdf); ok && isBookmark && entering {
+		fpdf.Fpdf.Bookmark(mark.title, mark.level, -1)
+	}
+	return ast.WalkContinue, nil
+}
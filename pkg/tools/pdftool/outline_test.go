@@ -0,0 +1,63 @@
+package pdftool
+
+import (
+	"bytes"
+	"testing"
+
+	pdf "github.com/stephenafamo/goldmark-pdf"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+	"github.com/yuin/goldmark/util"
+)
+
+const outlined = "## Stock *Center*\n\nOrders.\n\n#### Too deep\n\n## Annotations\n\n" +
+	"> # Quoted\n\n" +
+	"### GO `terms`\n\nText.\n"
+
+func TestOutlineTransformer(t *testing.T) {
+	t.Parallel()
+	source := []byte(outlined)
+	document := goldmark.New(goldmark.WithParserOptions(
+		parser.WithASTTransformers(util.Prioritized(outlineTransformer{}, 100)),
+	)).Parser().Parse(text.NewReader(source))
+
+	var marks []bookmark
+	require.NoError(t, ast.Walk(document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if mark, ok := n.(*bookmark); ok && entering {
+			assert.IsType(t, &ast.Heading{}, mark.Parent())
+			marks = append(marks, bookmark{title: mark.title, level: mark.level})
+		}
+		return ast.WalkContinue, nil
+	}))
+	assert.Equal(t, []bookmark{
+		{title: "Stock Center", level: 0},
+		{title: "Annotations", level: 0},
+		{title: "GO terms", level: 1},
+	}, marks)
+}
+
+func TestOutlineRenderer(t *testing.T) {
+	t.Parallel()
+	converter := newConverter(testFonts()...)
+	var out bytes.Buffer
+	require.NoError(t, converter.Convert([]byte(outlined), &out))
+	assert.Contains(t, out.String(), "/Outlines")
+	assert.Contains(t, out.String(), "/Title (Stock Center)")
+	assert.Contains(t, out.String(), "/Title (GO terms)")
+	assert.NotContains(t, out.String(), "/Title (Too deep)")
+	assert.NotContains(t, out.String(), "/Title (Quoted)")
+}
+
+// testFonts returns the inbuilt PDF fonts, which render without
+// downloading fonts.
+func testFonts() []pdf.Option {
+	return []pdf.Option{
+		pdf.WithHeadingFont(pdf.FontHelvetica),
+		pdf.WithBodyFont(pdf.FontHelvetica),
+		pdf.WithCodeFont(pdf.FontCourier),
+	}
+}
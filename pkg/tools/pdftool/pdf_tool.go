@@ -1,41 +1,151 @@
 package pdftool
 
 import (
+	"bytes"
 	"context"
-	"errors"
 	"fmt"
 	"image/color"
-	"log"
+	"log/slog"
 	"net/http"
 	"os"
+	"path"
+	"strings"
+	"sync"
 
-	// Add this line.
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
 	"github.com/mark3labs/mcp-go/mcp"
 	pdf "github.com/stephenafamo/goldmark-pdf" // pdf renderer
 	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/util"
 )
 
 // PdfTool is a tool that converts markdown to PDF.
+// The PDF converter is built on first use, and the font files it downloads
+// are kept in fontFiles for the life of the process.
 type PdfTool struct {
-	Name        string
-	Description string
-	Tool        mcp.Tool
-	Logger      *log.Logger
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	store         artifact.Store
+	resources     *resources.Catalog
+	uploads       *upload.Store
+	citations     markdown.CitationResolver
+	glossary      markdown.Glossary
+	converterOnce sync.Once
+	converter     goldmark.Markdown
+}
+
+// Option defines a functional option for configuring PdfTool.
+type Option func(*PdfTool)
+
+// WithStore sets the artifact store generated PDFs are written to. The
+// default store writes to the local filesystem.
+func WithStore(store artifact.Store) Option {
+	return func(pt *PdfTool) {
+		pt.store = store
+	}
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"markdown_to_pdf",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			pdfTool, err := NewPdfTool(
+				deps.Logger,
+				WithStore(deps.Store),
+				WithResources(deps.Resources),
+				WithUploads(deps.Uploads),
+				WithCitations(deps.Citations),
+				WithGlossary(deps.Glossary),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return pdfTool, nil
+		},
+	)
+}
+
+// WithResources publishes generated PDFs as MCP resources in the catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(pt *PdfTool) {
+		pt.resources = catalog
+	}
+}
+
+// WithUploads lets calls pass the markdown as a completed upload.
+func WithUploads(store *upload.Store) Option {
+	return func(pt *PdfTool) {
+		pt.uploads = store
+	}
+}
+
+// WithCitations expands the [@pmid:...] and [@doi:...] citations of the
+// markdown into numbered references, looking them up with resolve.
+func WithCitations(resolve markdown.CitationResolver) Option {
+	return func(pt *PdfTool) {
+		pt.citations = resolve
+	}
+}
+
+// WithGlossary links the terms of glossary in the markdown of calls that
+// ask for term links.
+func WithGlossary(glossary markdown.Glossary) Option {
+	return func(pt *PdfTool) {
+		pt.glossary = glossary
+	}
 }
 
 // NewPdfTool creates a new PdfTool instance.
-func NewPdfTool(logger *log.Logger) (*PdfTool, error) {
-	// Create the tool with proper schema
+func NewPdfTool(logger *slog.Logger, opts ...Option) (*PdfTool, error) {
 	// Create the tool with proper schema
 	tool := mcp.NewTool(
 		"markdown_to_pdf",
 		mcp.WithDescription(
 			"Converts markdown content to a PDF document and saves it to a file.", // Updated description
 		),
+		mcp.WithTitleAnnotation("Markdown to PDF"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
 		mcp.WithString(
 			"content",
-			mcp.Description("The markdown content to convert to PDF"),
-			mcp.Required(),
+			mcp.Description(
+				"The markdown content to convert to PDF. Citations such as [@pmid:23172289] or "+
+					"[@doi:10.1093/nar/gks1064] become numbered references when the server resolves them",
+			),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the markdown, instead of content"),
+		),
+		mcp.WithBoolean(
+			"link_terms",
+			mcp.Description(
+				"Link dictyBase gene IDs such as DDB_G0273397 to their gene pages, and the terms of "+
+					"the server's glossary to their pages. Defaults to false",
+			),
+		),
+		mcp.WithString(
+			"glossary",
+			mcp.Description(
+				"Comma-separated term=page pairs to link, added to the server's glossary, e.g. "+
+					"carA=DDB_G0273397,cAMP=https://en.wikipedia.org/wiki/CAMP. A page may be a "+
+					"dictyBase gene ID. Implies link_terms",
+			),
 		),
 		// Add optional filename parameter
 		mcp.WithString( // Add this block
@@ -45,13 +155,40 @@ func NewPdfTool(logger *log.Logger) (*PdfTool, error) {
 			),
 			// Not required
 		),
+		mcp.WithString(
+			"chapters",
+			mcp.Description(
+				"Split long reports into one PDF per chapter, named filename-01-title.pdf and so on: "+
+					"auto splits documents over about fifty pages, split always splits, none never does. "+
+					"Defaults to auto",
+			),
+			mcp.Enum(chapterModes...),
+		),
+		mcp.WithNumber(
+			"chapter_level",
+			mcp.Description(
+				"Heading level chapters start at, 1 for #; defaults to the highest level used by more than one heading",
+			),
+			mcp.Min(1),
+			mcp.Max(6),
+		),
+		idempotency.WithKey(),
 	)
-	return &PdfTool{
+	pdfTool := &PdfTool{
 		Name:        "markdown_to_pdf",
 		Description: "Converts markdown content to a PDF document and saves it to a file.", // Updated description
 		Tool:        tool,
 		Logger:      logger,
-	}, nil
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(pdfTool)
+	}
+	if pdfTool.citations != nil {
+		// Citations are looked up with literature providers.
+		pdfTool.Tool.Annotations.OpenWorldHint = mcp.ToBoolPtr(true)
+	}
+	return pdfTool, nil
 }
 
 // GetName returns the name of the tool.
@@ -74,15 +211,34 @@ func (pt *PdfTool) GetTool() mcp.Tool {
 	return pt.Tool
 }
 
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (pt *PdfTool) GetAnnotations() mcp.ToolAnnotation {
+	return pt.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (pt *PdfTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Convert a markdown document to a named PDF",
+			Arguments: map[string]any{
+				"content":  "# Meeting notes\n\n- Review the stock center backlog",
+				"filename": "meeting-notes.pdf",
+			},
+		},
+	}
+}
+
 // Handler returns a function that handles tool execution requests.
 func (pt *PdfTool) Handler(
 	ctx context.Context,
 	request mcp.CallToolRequest,
 ) (*mcp.CallToolResult, error) {
 	args := request.GetArguments()
-	contentVal, ok := args["content"].(string)
-	if !ok {
-		return nil, errors.New("missing required parameter: content")
+	contentVal, err := upload.TextArgument(pt.uploads, request, "content")
+	if err != nil {
+		return toolerror.Result(err), nil
 	}
 	// --- Determine output filename ---
 	outputFilename := "output.pdf" // Default filename
@@ -90,47 +246,212 @@ func (pt *PdfTool) Handler(
 		fname != "" {
 		outputFilename = fname
 	}
-	pdfFile, err := os.Create(outputFilename)
+	logger := logging.WithRequestID(pt.Logger)
+	contentVal, err = pt.linkTerms(request, contentVal)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	// Citations are expanded before splitting, so references are numbered
+	// across chapters.
+	if pt.citations != nil {
+		var references []markdown.Reference
+		contentVal, references, err = markdown.ExpandCitations(ctx, contentVal, pt.citations)
+		if err != nil {
+			return toolerror.Result(err), nil
+		}
+		for _, reference := range references {
+			if reference.Unresolved {
+				logger.Warn("citation not found", "type", reference.Citation.Type, "id", reference.Citation.ID)
+			}
+		}
+	}
+	parts, err := splitDocument(
+		contentVal,
+		outputFilename,
+		request.GetString("chapters", ChaptersAuto),
+		request.GetInt("chapter_level", 0),
+	)
 	if err != nil {
-		return nil, fmt.Errorf(
-			"error creating file %s %w", outputFilename, err,
-		)
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	reporter := progress.FromContext(ctx)
+	// Each part is rendered and stored, and the last step reports the
+	// PDFs saved.
+	stages := float64(2*len(parts) + 1)
+	var (
+		lines []string
+		links []mcp.Content
+	)
+	for i, part := range parts {
+		reporter.Report(float64(2*i+1), stages, "rendering "+part.filename)
+		stored, resource, err := pt.save(ctx, part)
+		if err != nil {
+			logger.Error("error saving PDF", "filename", part.filename, "error", err)
+			return toolerror.Result(err), nil
+		}
+		reporter.Report(float64(2*i+2), stages, "stored "+part.filename)
+		logger.Info("saved PDF", "location", stored.Location)
+		line := stored.Location
+		if part.title != "" {
+			line = fmt.Sprintf("- %s: %s", part.title, stored.Location)
+		}
+		if stored.URL != "" {
+			line += fmt.Sprintf("\nDownload URL: %s", stored.URL)
+		}
+		lines = append(lines, line)
+		if resource != nil {
+			links = append(links, resources.Link(*resource))
+		}
 	}
-	defer pdfFile.Close()
 
-	markdown := goldmark.New(
-		goldmark.WithRenderer(pdf.New(
-			pdf.WithContext(
-				context.Background(),
-			),
-			pdf.WithLinkColor(
-				color.RGBA{R: 204, G: 69, B: 120, A: 255},
-			),
-			pdf.WithImageFS(
-				http.FS(os.DirFS(".")),
-			), // Consider security implications of reading local files
-			pdf.WithHeadingFont(
-				pdf.GetTextFont(
-					"IBM Plex Serif", pdf.FontLora,
-				),
+	message := "PDF successfully saved to " + lines[0]
+	if len(parts) > 1 {
+		message = fmt.Sprintf("PDF split into %d chapters:\n%s", len(parts), strings.Join(lines, "\n"))
+	}
+	result := mcp.NewToolResultText(message)
+	result.Content = append(result.Content, links...)
+	reporter.Report(stages, stages, "PDF saved")
+	return result, nil
+}
+
+// save renders a part as PDF, stores it and publishes it as a resource when
+// the tool has a catalog.
+func (pt *PdfTool) save(ctx context.Context, part part) (*artifact.Artifact, *mcp.Resource, error) {
+	var pdfData bytes.Buffer
+	if err := pt.markdownConverter().Convert([]byte(part.content), &pdfData); err != nil {
+		return nil, nil, fmt.Errorf("failed to convert markdown to PDF: %w", err)
+	}
+	pdfBytes := pdfData.Bytes()
+	stored, err := pt.store.Put(ctx, artifact.PutParams{
+		Name:        part.filename,
+		ContentType: "application/pdf",
+		Body:        bytes.NewReader(pdfBytes),
+		Size:        int64(len(pdfBytes)),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to store PDF %s: %w", part.filename, err)
+	}
+	if pt.resources == nil {
+		return stored, nil, nil
+	}
+	description := "PDF generated from markdown"
+	if part.title != "" {
+		description = "Chapter " + part.title + " of a PDF generated from markdown"
+	}
+	resource, err := pt.resources.Publish(resources.PublishParams{
+		Kind:        resources.KindPDF,
+		Name:        path.Base(part.filename),
+		MIMEType:    "application/pdf",
+		Description: description,
+		Data:        pdfBytes,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to publish PDF %s: %w", part.filename, err)
+	}
+	return stored, &resource, nil
+}
+
+// markdownConverter returns the goldmark PDF converter, creating it on first use.
+func (pt *PdfTool) markdownConverter() goldmark.Markdown {
+	pt.converterOnce.Do(func() {
+		pt.converter = NewConverter()
+	})
+	return pt.converter
+}
+
+// fontCache keeps downloaded font files by their key. Unlike the cache of
+// goldmark-pdf, whose entries expire after a minute, it keeps them for the
+// life of the process, so fonts are downloaded once rather than for every
+// render.
+type fontCache struct {
+	mu    sync.RWMutex
+	files map[string][]byte
+}
+
+// fontFiles is the font cache shared by the converters of NewConverter.
+var fontFiles = &fontCache{files: make(map[string][]byte)}
+
+// Get returns the font file stored under key.
+func (c *fontCache) Get(key string) ([]byte, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	data, ok := c.files[key]
+	return data, ok
+}
+
+// Set stores a font file under key.
+func (c *fontCache) Set(key string, data []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.files[key] = data
+}
+
+// NewConverter returns a goldmark converter that renders markdown as PDF
+// with the fonts and link color used by markdown_to_pdf. It renders
+// footnotes, definition lists and links to headings within the document,
+// and an outline of bookmarks for the headings up to ###. The fonts are
+// downloaded on the first render and reused by every converter.
+func NewConverter() goldmark.Markdown {
+	return newConverter(
+		pdf.WithFontsCache(fontFiles),
+		pdf.WithHeadingFont(
+			pdf.GetTextFont(
+				"IBM Plex Serif", pdf.FontLora,
 			),
-			pdf.WithBodyFont(
-				pdf.GetTextFont("Open Sans", pdf.FontRoboto)),
-			pdf.WithCodeFont(
-				pdf.GetCodeFont("Inconsolata", pdf.FontRobotoMono),
+		),
+		pdf.WithBodyFont(
+			pdf.GetTextFont("Open Sans", pdf.FontRoboto)),
+		pdf.WithCodeFont(
+			pdf.GetCodeFont("Inconsolata", pdf.FontRobotoMono),
+		),
+	)
+}
+
+// newConverter returns the converter of NewConverter with fonts.
+func newConverter(fonts ...pdf.Option) goldmark.Markdown {
+	options := []pdf.Option{
+		pdf.WithContext(
+			context.Background(),
+		),
+		pdf.WithLinkColor(
+			color.RGBA{R: 204, G: 69, B: 120, A: 255},
+		),
+		pdf.WithImageFS(
+			http.FS(os.DirFS(".")),
+		), // Consider security implications of reading local files
+		pdf.WithNodeRenderers(
+			util.Prioritized(outlineRenderer{}, 100),
+			util.Prioritized(referenceRenderer{}, 100),
+		),
+	}
+	return goldmark.New(
+		goldmark.WithExtensions(
+			extension.Footnote,
+			extension.DefinitionList,
+		),
+		goldmark.WithParserOptions(
+			parser.WithAutoHeadingID(),
+			parser.WithHeadingAttribute(),
+			parser.WithASTTransformers(
+				util.Prioritized(outlineTransformer{}, 100),
+				// after the footnote transformer at 999
+				util.Prioritized(referenceTransformer{}, 1000),
 			),
-		)),
+		),
+		goldmark.WithRenderer(pdf.New(append(options, fonts...)...)),
 	)
-	err = markdown.Convert([]byte(contentVal), pdfFile)
+}
+
+// linkTerms links the gene IDs and glossary terms of content when the
+// request asks for term links.
+func (pt *PdfTool) linkTerms(request mcp.CallToolRequest, content string) (string, error) {
+	pairs := request.GetString("glossary", "")
+	if !request.GetBool("link_terms", false) && pairs == "" {
+		return content, nil
+	}
+	glossary, err := markdown.ParseGlossary(pairs)
 	if err != nil {
-		pt.Logger.Printf("Error converting markdown to PDF: %v", err)
-		return nil, fmt.Errorf("failed to convert markdown to PDF: %w", err)
+		return "", toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_GLOSSARY", err, "invalid glossary")
 	}
-	pt.Logger.Println(
-		"Successfully converted markdown to PDF",
-	)
-	pt.Logger.Printf("Saved PDF to file: %s", outputFilename)
-	return mcp.NewToolResultText(
-		fmt.Sprintf("PDF successfully saved to %s", outputFilename),
-	), nil
+	return markdown.LinkTerms(content, pt.glossary.Merge(glossary)), nil
 }
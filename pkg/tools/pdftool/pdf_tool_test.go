@@ -3,11 +3,14 @@ package pdftool
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
 
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
 	"github.com/mark3labs/mcp-go/mcp"
 	"github.com/stretchr/testify/require"
 )
@@ -15,7 +18,7 @@ import (
 func TestNewPdfTool(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
-	logger := log.New(os.Stderr, "[pdf-test] ", 0)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewPdfTool(logger)
 	requireHelper.NoError(err, "NewPdfTool should not return an error")
@@ -32,6 +35,8 @@ func TestNewPdfTool(t *testing.T) {
 	)
 	requireHelper.NotNil(tool.GetSchema(), "Tool schema should not be nil")
 	requireHelper.Equal("markdown_to_pdf", tool.GetTool().Name, "MCP Tool name mismatch")
+	requireHelper.False(*tool.GetAnnotations().ReadOnlyHint, "markdown_to_pdf writes files")
+	requireHelper.True(*tool.GetAnnotations().DestructiveHint, "markdown_to_pdf may overwrite files")
 
 	// Check schema details
 	schema := tool.GetSchema()
@@ -61,7 +66,10 @@ func TestNewPdfTool(t *testing.T) {
 		filenamePropMap["description"],
 	)
 
-	requireHelper.Contains(schema.Required, "content")
+	// content may be given as a completed upload instead
+	requireHelper.Contains(schema.Properties, upload.IDArgument)
+	requireHelper.NotContains(schema.Required, "content")
+	requireHelper.NotContains(schema.Required, upload.IDArgument)
 	requireHelper.NotContains(
 		schema.Required,
 		"filename",
@@ -72,7 +80,7 @@ func TestHandlerDefaultFilename(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
 	// Use a logger that writes to stderr for visibility during tests
-	logger := log.New(os.Stderr, "[pdf-test-handler-default] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewPdfTool(logger)
 	requireHelper.NoError(err, "NewPdfTool should not return an error")
@@ -140,7 +148,7 @@ func TestHandlerCustomFilename(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
 	// Use a logger that writes to stderr for visibility during tests
-	logger := log.New(os.Stderr, "[pdf-test-handler-custom] ", log.LstdFlags)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewPdfTool(logger)
 	requireHelper.NoError(err, "NewPdfTool should not return an error")
@@ -208,7 +216,7 @@ func TestHandlerCustomFilename(t *testing.T) {
 func TestHandlerMissingContent(t *testing.T) {
 	t.Parallel()
 	requireHelper := require.New(t)
-	logger := log.New(os.Stderr, "[pdf-test] ", 0)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
 
 	tool, err := NewPdfTool(logger)
 	requireHelper.NoError(err, "NewPdfTool should not return an error")
@@ -222,7 +230,65 @@ func TestHandlerMissingContent(t *testing.T) {
 	result, err := tool.Handler(context.Background(), request)
 
 	// Assertions
-	requireHelper.Error(err, "Handler should return an error for missing content")
-	requireHelper.Nil(result, "Result should be nil on error")
-	requireHelper.Contains(err.Error(), "missing required parameter: content")
+	requireHelper.NoError(err, "Handler should report missing content as an error result")
+	requireHelper.NotNil(result, "Result should not be nil")
+	requireHelper.True(result.IsError, "Result should be an error")
+	text, ok := result.Content[0].(mcp.TextContent)
+	requireHelper.True(ok, "Result should have text content")
+	requireHelper.Contains(text.Text, "missing required parameter: content")
+}
+
+func TestFontCache(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	cache := &fontCache{files: make(map[string][]byte)}
+	_, ok := cache.Get("Open Sans-regular")
+	requireHelper.False(ok, "an empty cache has no fonts")
+	cache.Set("Open Sans-regular", []byte("font"))
+	data, ok := cache.Get("Open Sans-regular")
+	requireHelper.True(ok, "a stored font is kept")
+	requireHelper.Equal([]byte("font"), data)
+}
+
+func TestHandlerUploads(t *testing.T) {
+	t.Parallel()
+	requireHelper := require.New(t)
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	dir := t.TempDir()
+	uploads := upload.NewStore()
+	tool, err := NewPdfTool(logger, WithStore(artifact.NewLocalStore(dir)), WithUploads(uploads))
+	requireHelper.NoError(err, "NewPdfTool should not return an error")
+	// Render with built-in fonts, so the test needs no font downloads.
+	tool.converterOnce.Do(func() {
+		tool.converter = newConverter(testFonts()...)
+	})
+
+	info, err := uploads.Begin(upload.BeginParams{ContentType: "text/markdown"})
+	requireHelper.NoError(err)
+	_, err = uploads.Append(upload.AppendParams{ID: info.ID, Data: []byte("# Uploaded\n\nFrom an upload.\n")})
+	requireHelper.NoError(err)
+	_, err = uploads.Complete(info.ID, "")
+	requireHelper.NoError(err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "markdown_to_pdf"
+	request.Params.Arguments = map[string]any{upload.IDArgument: info.ID, "filename": "uploaded.pdf"}
+	result, err := tool.Handler(context.Background(), request)
+	requireHelper.NoError(err)
+	requireHelper.False(result.IsError, "Handler should convert the uploaded markdown")
+	pdfBytes, err := os.ReadFile(filepath.Join(dir, "uploaded.pdf"))
+	requireHelper.NoError(err, "The PDF should be stored")
+	requireHelper.Equal([]byte("%PDF-"), pdfBytes[:5])
+
+	request.Params.Arguments = map[string]any{"filename": "neither.pdf"}
+	result, err = tool.Handler(context.Background(), request)
+	requireHelper.NoError(err)
+	requireHelper.True(result.IsError, "Handler should reject a call without content or upload_id")
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	requireHelper.True(ok, "Result should carry a tool error")
+	requireHelper.Equal(toolerror.TypeInvalidInput, toolErr.Type)
+	text, ok := result.Content[0].(mcp.TextContent)
+	requireHelper.True(ok, "Result should have text content")
+	requireHelper.Contains(text.Text, "missing required parameter: content or upload_id")
+	requireHelper.NoFileExists(filepath.Join(dir, "neither.pdf"))
 }
@@ -0,0 +1,174 @@
+package pdftool
+
+import (
+	"fmt"
+
+	pdf "github.com/stephenafamo/goldmark-pdf"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/parser"
+	"github.com/yuin/goldmark/text"
+)
+
+// Node kinds of the references within a PDF.
+var (
+	kindAnchor = ast.NewNodeKind("PDFAnchor")
+	kindLabel  = ast.NewNodeKind("PDFLabel")
+)
+
+// anchor is a target of internal links, such as #fn:1 for the first
+// footnote.
+type anchor struct {
+	ast.BaseInline
+	name string
+}
+
+// Kind returns kindAnchor.
+func (a *anchor) Kind() ast.NodeKind {
+	return kindAnchor
+}
+
+// Dump dumps the anchor for debugging.
+func (a *anchor) Dump(source []byte, level int) {
+	ast.DumpHelper(a, source, level, map[string]string{"Name": a.name}, nil)
+}
+
+// label is text that is not part of the source, such as [1] for a
+// footnote reference.
+type label struct {
+	ast.BaseInline
+	text string
+}
+
+// Kind returns kindLabel.
+func (l *label) Kind() ast.NodeKind {
+	return kindLabel
+}
+
+// Dump dumps the label for debugging.
+func (l *label) Dump(source []byte, level int) {
+	ast.DumpHelper(l, source, level, map[string]string{"Text": l.text}, nil)
+}
+
+// footnoteAnchor returns the anchor of the footnote with index, the same
+// as the id of the footnote in HTML.
+func footnoteAnchor(index int) string {
+	return fmt.Sprintf("fn:%d", index)
+}
+
+// referenceTransformer rewrites footnotes and definition lists, which the
+// PDF renderer has no rendering of, into nodes it renders. A footnote
+// reference becomes a [1] link to its footnote, the footnotes a numbered
+// list below a rule at the end of the document, a definition term a bold
+// paragraph and its description an indented block. It runs after the
+// footnote transformer that gathers the footnotes.
+type referenceTransformer struct{}
+
+// Transform rewrites the footnotes and definition lists of document.
+func (referenceTransformer) Transform(document *ast.Document, _ text.Reader, _ parser.Context) {
+	var found []ast.Node
+	_ = ast.Walk(document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		switch n.Kind() {
+		case east.KindFootnoteLink, east.KindFootnoteBacklink, east.KindFootnoteList, east.KindDefinitionList:
+			if entering {
+				found = append(found, n)
+			}
+		}
+		return ast.WalkContinue, nil
+	})
+	for _, n := range found {
+		parent := n.Parent()
+		switch node := n.(type) {
+		case *east.FootnoteLink:
+			link := ast.NewLink()
+			link.Destination = []byte("#" + footnoteAnchor(node.Index))
+			link.AppendChild(link, &label{text: fmt.Sprintf("[%d]", node.Index)})
+			parent.ReplaceChild(parent, node, link)
+		case *east.FootnoteBacklink:
+			parent.RemoveChild(parent, node)
+		case *east.FootnoteList:
+			parent.InsertBefore(parent, node, ast.NewThematicBreak())
+			parent.ReplaceChild(parent, node, footnoteList(node))
+		case *east.DefinitionList:
+			for _, block := range definitions(node) {
+				parent.InsertBefore(parent, node, block)
+			}
+			parent.RemoveChild(parent, node)
+		}
+	}
+}
+
+// footnoteList returns the footnotes of footnotes as a numbered list,
+// each item starting with the anchor of its footnote.
+func footnoteList(footnotes *east.FootnoteList) *ast.List {
+	list := ast.NewList('.')
+	list.Start = 1
+	for footnotes.HasChildren() {
+		footnote := footnotes.FirstChild()
+		footnotes.RemoveChild(footnotes, footnote)
+		item := ast.NewListItem(0)
+		if note, ok := footnote.(*east.Footnote); ok {
+			item.AppendChild(item, &anchor{name: footnoteAnchor(note.Index)})
+		}
+		moveChildren(item, footnote)
+		list.AppendChild(list, item)
+	}
+	return list
+}
+
+// definitions returns the terms and descriptions of list as bold
+// paragraphs and indented blocks.
+func definitions(list *east.DefinitionList) []ast.Node {
+	var blocks []ast.Node
+	for child := list.FirstChild(); child != nil; child = child.NextSibling() {
+		switch child.(type) {
+		case *east.DefinitionTerm:
+			strong := ast.NewEmphasis(2)
+			moveChildren(strong, child)
+			term := ast.NewParagraph()
+			term.AppendChild(term, strong)
+			blocks = append(blocks, term)
+		case *east.DefinitionDescription:
+			description := ast.NewBlockquote()
+			moveChildren(description, child)
+			blocks = append(blocks, description)
+		}
+	}
+	return blocks
+}
+
+// moveChildren moves the children of from to the end of to.
+func moveChildren(to, from ast.Node) {
+	for from.HasChildren() {
+		child := from.FirstChild()
+		from.RemoveChild(from, child)
+		to.AppendChild(to, child)
+	}
+}
+
+// referenceRenderer renders the anchors and labels of references.
+type referenceRenderer struct{}
+
+// RegisterFuncs registers the rendering of anchors and labels.
+func (referenceRenderer) RegisterFuncs(reg pdf.NodeRendererFuncRegisterer) {
+	reg.Register(kindAnchor, renderAnchor)
+	reg.Register(kindLabel, renderLabel)
+}
+
+// renderAnchor makes the current position the target of links to the
+// anchor.
+func renderAnchor(w *pdf.Writer, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if target, ok := n.(*anchor); ok && entering {
+		w.Pdf.AddInternalLink(target.name)
+	}
+	return ast.WalkContinue, nil
+}
+
+// renderLabel writes the text of the label in the current style, as a link
+// within a link.
+func renderLabel(w *pdf.Writer, _ []byte, n ast.Node, entering bool) (ast.WalkStatus, error) {
+	if text, ok := n.(*label); ok && entering {
+		w.WriteText(text.text)
+	}
+	return ast.WalkContinue, nil
+}
@@ -0,0 +1,56 @@
+package pdftool
+
+import (
+	"bytes"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/yuin/goldmark/ast"
+	east "github.com/yuin/goldmark/extension/ast"
+	"github.com/yuin/goldmark/text"
+)
+
+const scholarly = "## Methods {#methods}\n\nStrains were grown axenically.[^growth]\n\n" +
+	"## Results\n\nSee [Methods](#methods) and [the appendix](#appendix-a).\n\n" +
+	"Axenic\n: Grown without other organisms.\n\n" +
+	"## Appendix A\n\nMedia.\n\n" +
+	"[^growth]: In HL5 medium at 22 °C.\n"
+
+func TestReferenceTransformer(t *testing.T) {
+	t.Parallel()
+	source := []byte(scholarly)
+	document := newConverter().Parser().Parse(text.NewReader(source))
+
+	kinds := make(map[ast.NodeKind]int)
+	var destinations []string
+	require.NoError(t, ast.Walk(document, func(n ast.Node, entering bool) (ast.WalkStatus, error) {
+		if entering {
+			kinds[n.Kind()]++
+		}
+		if link, ok := n.(*ast.Link); ok && entering {
+			destinations = append(destinations, string(link.Destination))
+		}
+		return ast.WalkContinue, nil
+	}))
+	for _, kind := range []ast.NodeKind{
+		east.KindFootnoteLink, east.KindFootnoteBacklink, east.KindFootnoteList, east.KindFootnote,
+		east.KindDefinitionList, east.KindDefinitionTerm, east.KindDefinitionDescription,
+	} {
+		assert.Zero(t, kinds[kind], kind.String())
+	}
+	assert.Equal(t, []string{"#fn:1", "#methods", "#appendix-a"}, destinations)
+	assert.Equal(t, 1, kinds[kindAnchor], "the footnote is a link target")
+	assert.Equal(t, 1, kinds[kindLabel])
+	assert.Equal(t, 1, kinds[ast.KindThematicBreak])
+	assert.Equal(t, 1, kinds[ast.KindBlockquote], "the definition is indented")
+}
+
+func TestReferenceRenderer(t *testing.T) {
+	t.Parallel()
+	var out bytes.Buffer
+	require.NoError(t, newConverter(testFonts()...).Convert([]byte(scholarly), &out))
+	assert.Len(t, regexp.MustCompile(`/Subtype /Link [^>]*/Dest \[`).FindAll(out.Bytes(), -1), 3,
+		"the footnote reference and both heading links point within the document")
+}
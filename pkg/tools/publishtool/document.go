@@ -0,0 +1,155 @@
+package publishtool
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+)
+
+// Document is a markdown document split into its front matter metadata and
+// body.
+type Document struct {
+	Metadata Metadata
+	// Body is the markdown without front matter.
+	Body []byte
+}
+
+// Metadata is the front matter shared by every published format.
+type Metadata struct {
+	Title       string   `json:"title,omitempty"`
+	Authors     []string `json:"authors,omitempty"`
+	Date        string   `json:"date,omitempty"`
+	Description string   `json:"description,omitempty"`
+	Keywords    []string `json:"keywords,omitempty"`
+	// Formats lists the formats requested by the document.
+	Formats []string `json:"-"`
+	// Filename is the base name of the published files.
+	Filename string `json:"-"`
+}
+
+// nonSlugChars matches runs of characters that are not allowed in
+// generated file names.
+var nonSlugChars = regexp.MustCompile(`[^a-z0-9]+`)
+
+// ParseDocument reads the YAML front matter of a markdown document.
+func ParseDocument(content []byte) (Document, error) {
+	parser := markdown.NewParser()
+	if _, err := parser.Parse(content); err != nil {
+		return Document{}, fmt.Errorf("failed to parse markdown: %w", err)
+	}
+	values := parser.GetMetadata()
+	metadata := Metadata{
+		Title:       stringValue(values["title"]),
+		Authors:     listValue(firstOf(values, "authors", "author")),
+		Date:        stringValue(values["date"]),
+		Description: stringValue(firstOf(values, "description", "abstract")),
+		Keywords:    listValue(firstOf(values, "keywords", "tags")),
+		Formats:     listValue(values["formats"]),
+		Filename:    stringValue(values["filename"]),
+	}
+	return Document{Metadata: metadata, Body: stripFrontMatter(content)}, nil
+}
+
+// BaseName returns the file name without extension for the published
+// files: the filename from the front matter, else a slug of the title.
+func (m Metadata) BaseName() string {
+	name := m.Filename
+	if name == "" {
+		name = m.Title
+	}
+	slug := strings.Trim(nonSlugChars.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if slug == "" {
+		return "document"
+	}
+	return slug
+}
+
+// Markdown returns the body preceded by a title block built from the
+// metadata, so every format shows the same title, authors and date. The
+// block is left out when the body already starts with a top-level heading.
+func (d Document) Markdown() []byte {
+	body := bytes.TrimLeft(d.Body, "\r\n")
+	if d.Metadata.Title == "" || bytes.HasPrefix(body, []byte("# ")) {
+		return body
+	}
+	var builder bytes.Buffer
+	fmt.Fprintf(&builder, "# %s\n\n", d.Metadata.Title)
+	byline := d.Metadata.Authors
+	if d.Metadata.Date != "" {
+		byline = append(append([]string{}, byline...), d.Metadata.Date)
+	}
+	if len(byline) > 0 {
+		fmt.Fprintf(&builder, "*%s*\n\n", strings.Join(byline, " · "))
+	}
+	builder.Write(body)
+	return builder.Bytes()
+}
+
+// stripFrontMatter removes a leading block delimited by --- lines.
+func stripFrontMatter(content []byte) []byte {
+	normalized := bytes.ReplaceAll(content, []byte("\r\n"), []byte("\n"))
+	if !bytes.HasPrefix(normalized, []byte("---\n")) {
+		return normalized
+	}
+	rest := normalized[len("---\n"):]
+	if end := bytes.Index(rest, []byte("\n---\n")); end >= 0 {
+		return rest[end+len("\n---\n"):]
+	}
+	if bytes.HasSuffix(rest, []byte("\n---")) {
+		return nil
+	}
+	return normalized
+}
+
+// firstOf returns the value of the first key present in values.
+func firstOf(values map[string]interface{}, keys ...string) interface{} {
+	for _, key := range keys {
+		if value, ok := values[key]; ok {
+			return value
+		}
+	}
+	return nil
+}
+
+// stringValue converts a scalar front matter value to a string.
+func stringValue(value interface{}) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case time.Time:
+		return value.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// listValue converts a front matter list, or a comma-separated string, to
+// a list of strings.
+func listValue(value interface{}) []string {
+	var items []string
+	switch value := value.(type) {
+	case []interface{}:
+		for _, item := range value {
+			items = append(items, stringValue(item))
+		}
+	case string:
+		items = strings.Split(value, ",")
+	case nil:
+		return nil
+	default:
+		items = []string{stringValue(value)}
+	}
+	values := make([]string, 0, len(items))
+	for _, item := range items {
+		if item = strings.TrimSpace(item); item != "" {
+			values = append(values, item)
+		}
+	}
+	return values
+}
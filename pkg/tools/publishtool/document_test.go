@@ -0,0 +1,57 @@
+package publishtool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sampleDocument = `---
+title: Chemotaxis in Dictyostelium
+authors:
+  - Jane Doe
+  - Richard Roe
+date: 2024-01-15
+description: Annual progress report
+tags: chemotaxis, cAMP
+formats: [html, docx]
+---
+Cells move toward **cAMP**.
+`
+
+func TestParseDocument(t *testing.T) {
+	t.Parallel()
+	doc, err := ParseDocument([]byte(sampleDocument))
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{
+		Title:       "Chemotaxis in Dictyostelium",
+		Authors:     []string{"Jane Doe", "Richard Roe"},
+		Date:        "2024-01-15",
+		Description: "Annual progress report",
+		Keywords:    []string{"chemotaxis", "cAMP"},
+		Formats:     []string{"html", "docx"},
+	}, doc.Metadata)
+	assert.Equal(t, "Cells move toward **cAMP**.\n", string(doc.Body))
+	assert.Equal(t, "chemotaxis-in-dictyostelium", doc.Metadata.BaseName())
+	assert.Equal(
+		t,
+		"# Chemotaxis in Dictyostelium\n\n*Jane Doe · Richard Roe · 2024-01-15*\n\nCells move toward **cAMP**.\n",
+		string(doc.Markdown()),
+	)
+}
+
+func TestParseDocument_WithoutFrontMatter(t *testing.T) {
+	t.Parallel()
+	doc, err := ParseDocument([]byte("# Notes\n\nPlain text.\n"))
+	require.NoError(t, err)
+	assert.Equal(t, Metadata{}, doc.Metadata)
+	assert.Equal(t, "document", doc.Metadata.BaseName())
+	assert.Equal(t, "# Notes\n\nPlain text.\n", string(doc.Markdown()))
+}
+
+func TestBaseName(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "report-2024", Metadata{Title: "Ignored", Filename: "Report 2024!"}.BaseName())
+	assert.Equal(t, "a-b-c", Metadata{Title: "  A / B / C  "}.BaseName())
+}
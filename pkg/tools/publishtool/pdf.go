@@ -0,0 +1,39 @@
+package publishtool
+
+import (
+	"bytes"
+	"fmt"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/pdftool"
+	"github.com/yuin/goldmark"
+)
+
+// pdfRenderer renders PDFs with the markdown_to_pdf converter, built on
+// first use.
+type pdfRenderer struct {
+	once      sync.Once
+	converter goldmark.Markdown
+}
+
+// Extension implements Renderer.
+func (*pdfRenderer) Extension() string {
+	return ".pdf"
+}
+
+// ContentType implements Renderer.
+func (*pdfRenderer) ContentType() string {
+	return "application/pdf"
+}
+
+// Render implements Renderer.
+func (p *pdfRenderer) Render(doc Document) ([]byte, error) {
+	p.once.Do(func() {
+		p.converter = pdftool.NewConverter()
+	})
+	var buf bytes.Buffer
+	if err := p.converter.Convert(doc.Markdown(), &buf); err != nil {
+		return nil, fmt.Errorf("failed to render PDF: %w", err)
+	}
+	return buf.Bytes(), nil
+}
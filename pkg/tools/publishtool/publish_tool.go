@@ -0,0 +1,341 @@
+package publishtool
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// defaultFormats are published when neither the request nor the front
+// matter lists formats.
+var defaultFormats = []string{FormatHTML, FormatPDF, FormatDOCX}
+
+// PublishTool publishes one markdown document with front matter in several
+// formats plus a manifest describing them.
+type PublishTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	store       artifact.Store
+	renderers   map[string]Renderer
+	uploads     *upload.Store
+	now         func() time.Time
+}
+
+// Option defines a functional option for configuring PublishTool.
+type Option func(*PublishTool)
+
+// WithStore sets the artifact store published files are written to. The
+// default store writes to the local filesystem.
+func WithStore(store artifact.Store) Option {
+	return func(p *PublishTool) {
+		p.store = store
+	}
+}
+
+// WithRenderer replaces the renderer of a format.
+func WithRenderer(format string, renderer Renderer) Option {
+	return func(p *PublishTool) {
+		p.renderers[format] = renderer
+	}
+}
+
+// WithUploads lets calls pass the document as a completed upload.
+func WithUploads(store *upload.Store) Option {
+	return func(p *PublishTool) {
+		p.uploads = store
+	}
+}
+
+// PublishRequest represents the parameters for publishing a document.
+type PublishRequest struct {
+	Content []byte   `validate:"required"`
+	Formats []string `validate:"required,min=1,dive,oneof=html pdf docx"`
+}
+
+// Manifest describes the files produced by one publish run.
+type Manifest struct {
+	Metadata
+	SourceSHA256 string     `json:"source_sha256"`
+	GeneratedAt  time.Time  `json:"generated_at"`
+	Artifacts    []Artifact `json:"artifacts"`
+}
+
+// Artifact is one published file.
+type Artifact struct {
+	Format      string `json:"format"`
+	Name        string `json:"name"`
+	Location    string `json:"location"`
+	URL         string `json:"url,omitempty"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+	SHA256      string `json:"sha256"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"publish",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			publishTool, err := NewPublishTool(
+				deps.Logger,
+				WithStore(deps.Store),
+				WithUploads(deps.Uploads),
+			)
+			if err != nil {
+				return nil, err
+			}
+			return publishTool, nil
+		},
+	)
+}
+
+// NewPublishTool creates a new PublishTool instance.
+func NewPublishTool(logger *slog.Logger, opts ...Option) (*PublishTool, error) {
+	tool := mcp.NewTool(
+		"publish",
+		mcp.WithDescription(
+			"Publishes a markdown document with YAML front matter as HTML, PDF and DOCX with consistent metadata, plus a JSON manifest",
+		),
+		mcp.WithTitleAnnotation("Publish Document"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString(
+			"content",
+			mcp.Description(
+				"Markdown document; front matter may set title, authors, date, description, keywords, formats and filename",
+			),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle of a completed upload holding the document, instead of content"),
+		),
+		mcp.WithString(
+			"formats",
+			mcp.Description(
+				"Comma-separated formats to emit: html, pdf, docx (overrides the front matter; defaults to all)",
+			),
+		),
+		idempotency.WithKey(),
+	)
+	publishTool := &PublishTool{
+		Name:        "publish",
+		Description: "Publishes a markdown document as HTML, PDF and DOCX plus a manifest",
+		Tool:        tool,
+		Logger:      logger,
+		store:       artifact.NewLocalStore(""),
+		renderers: map[string]Renderer{
+			FormatHTML: htmlRenderer{},
+			FormatPDF:  &pdfRenderer{},
+			FormatDOCX: docxRenderer{},
+		},
+		now: time.Now,
+	}
+	for _, opt := range opts {
+		opt(publishTool)
+	}
+	return publishTool, nil
+}
+
+// GetName returns the name of the tool.
+func (p *PublishTool) GetName() string {
+	return p.Name
+}
+
+// GetDescription returns the description of the tool.
+func (p *PublishTool) GetDescription() string {
+	return p.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (p *PublishTool) GetSchema() mcp.ToolInputSchema {
+	return p.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (p *PublishTool) GetTool() mcp.Tool {
+	return p.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (p *PublishTool) GetAnnotations() mcp.ToolAnnotation {
+	return p.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (p *PublishTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Publish a document as HTML and PDF",
+			Arguments: map[string]any{
+				"content": "---\ntitle: Quarterly report\nauthors: [Jane Doe]\n---\n\n# Summary\n\nStrain orders grew by 12%.",
+				"formats": "html,pdf",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (p *PublishTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	text, err := upload.TextArgument(p.uploads, request, "content")
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := []byte(text)
+	doc, err := ParseDocument(content)
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_MARKDOWN",
+			err,
+			"failed to read document",
+		)), nil
+	}
+	formats := splitList(request.GetString("formats", ""))
+	if len(formats) == 0 {
+		formats = doc.Metadata.Formats
+	}
+	if len(formats) == 0 {
+		formats = defaultFormats
+	}
+	params := PublishRequest{Content: content, Formats: dedupe(formats)}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+
+	manifest, err := p.Publish(ctx, doc, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to encode manifest: %w", err)), nil
+	}
+	var message strings.Builder
+	fmt.Fprintf(&message, "Published %q in %d formats:\n", doc.Metadata.BaseName(), len(params.Formats))
+	for _, published := range manifest.Artifacts {
+		fmt.Fprintf(&message, "- %s: %s\n", published.Format, published.Location)
+		if published.URL != "" {
+			fmt.Fprintf(&message, "  Download URL: %s\n", published.URL)
+		}
+	}
+	fmt.Fprintf(&message, "\nManifest:\n%s", encoded)
+	return mcp.NewToolResultStructured(manifest, message.String()), nil
+}
+
+// Publish renders the document in each requested format, stores the files
+// and finally stores the manifest next to them.
+func (p *PublishTool) Publish(
+	ctx context.Context,
+	doc Document,
+	params PublishRequest,
+) (*Manifest, error) {
+	logger := logging.WithRequestID(p.Logger)
+	baseName := doc.Metadata.BaseName()
+	sourceSum := sha256.Sum256(params.Content)
+	manifest := &Manifest{
+		Metadata:     doc.Metadata,
+		SourceSHA256: hex.EncodeToString(sourceSum[:]),
+		GeneratedAt:  p.now().UTC(),
+		Artifacts:    make([]Artifact, 0, len(params.Formats)+1),
+	}
+	for _, format := range params.Formats {
+		renderer := p.renderers[format]
+		data, err := renderer.Render(doc)
+		if err != nil {
+			return nil, err
+		}
+		published, err := p.put(ctx, baseName+renderer.Extension(), renderer.ContentType(), data)
+		if err != nil {
+			return nil, err
+		}
+		published.Format = format
+		logger.Info("published document", "format", format, "location", published.Location)
+		manifest.Artifacts = append(manifest.Artifacts, *published)
+	}
+
+	encoded, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	if _, err := p.put(ctx, baseName+".manifest.json", "application/json", encoded); err != nil {
+		return nil, err
+	}
+	return manifest, nil
+}
+
+// put writes a file to the artifact store.
+func (p *PublishTool) put(
+	ctx context.Context,
+	name, contentType string,
+	data []byte,
+) (*Artifact, error) {
+	stored, err := p.store.Put(ctx, artifact.PutParams{
+		Name:        name,
+		ContentType: contentType,
+		Body:        bytes.NewReader(data),
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to store %s: %w", name, err)
+	}
+	sum := sha256.Sum256(data)
+	return &Artifact{
+		Name:        name,
+		Location:    stored.Location,
+		URL:         stored.URL,
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		SHA256:      hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// splitList splits a comma-separated list, dropping blank entries.
+func splitList(list string) []string {
+	var values []string
+	for _, value := range strings.Split(list, ",") {
+		if value = strings.ToLower(strings.TrimSpace(value)); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
+
+// dedupe drops repeated formats, keeping the first occurrence.
+func dedupe(formats []string) []string {
+	seen := make(map[string]bool, len(formats))
+	unique := make([]string, 0, len(formats))
+	for _, format := range formats {
+		format = strings.ToLower(format)
+		if !seen[format] {
+			seen[format] = true
+			unique = append(unique, format)
+		}
+	}
+	return unique
+}
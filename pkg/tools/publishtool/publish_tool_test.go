@@ -0,0 +1,113 @@
+package publishtool
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakePDF stands in for the PDF renderer, which downloads fonts on first
+// use.
+type fakePDF struct{}
+
+func (fakePDF) Extension() string   { return ".pdf" }
+func (fakePDF) ContentType() string { return "application/pdf" }
+func (fakePDF) Render(doc Document) ([]byte, error) {
+	return append([]byte("%PDF-"), doc.Markdown()...), nil
+}
+
+func newTestTool(t *testing.T, dir string) *PublishTool {
+	t.Helper()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewPublishTool(
+		logger,
+		WithStore(artifact.NewLocalStore(dir)),
+		WithRenderer(FormatPDF, fakePDF{}),
+	)
+	require.NoError(t, err)
+	tool.now = func() time.Time { return time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC) }
+	return tool
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	tool := newTestTool(t, dir)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"content": sampleDocument,
+		"formats": "html, pdf, docx, html",
+	}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+	manifest, ok := result.StructuredContent.(*Manifest)
+	require.True(t, ok)
+	assert.Equal(t, "Chemotaxis in Dictyostelium", manifest.Title)
+	assert.Equal(t, time.Date(2024, 2, 1, 12, 0, 0, 0, time.UTC), manifest.GeneratedAt)
+	require.Len(t, manifest.Artifacts, 3)
+	for i, format := range []string{FormatHTML, FormatPDF, FormatDOCX} {
+		assert.Equal(t, format, manifest.Artifacts[i].Format)
+		assert.FileExists(t, filepath.Join(dir, manifest.Artifacts[i].Name))
+	}
+
+	page, err := os.ReadFile(filepath.Join(dir, "chemotaxis-in-dictyostelium.html"))
+	require.NoError(t, err)
+	assert.Contains(t, string(page), "<title>Chemotaxis in Dictyostelium</title>")
+	assert.Contains(t, string(page), `<meta name="author" content="Jane Doe, Richard Roe">`)
+	assert.Contains(t, string(page), "Jane Doe · Richard Roe · 2024-01-15")
+
+	document, err := os.ReadFile(filepath.Join(dir, "chemotaxis-in-dictyostelium.docx"))
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(document), int64(len(document)))
+	require.NoError(t, err)
+	assert.NotEmpty(t, archive.File)
+
+	stored, err := os.ReadFile(filepath.Join(dir, "chemotaxis-in-dictyostelium.manifest.json"))
+	require.NoError(t, err)
+	var decoded Manifest
+	require.NoError(t, json.Unmarshal(stored, &decoded))
+	assert.Equal(t, manifest.SourceSHA256, decoded.SourceSHA256)
+	assert.Equal(t, []string{"Jane Doe", "Richard Roe"}, decoded.Authors)
+	assert.Len(t, decoded.Artifacts, 3)
+}
+
+func TestHandler_FrontMatterFormats(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t, t.TempDir())
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"content": sampleDocument}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	manifest, ok := result.StructuredContent.(*Manifest)
+	require.True(t, ok)
+	require.Len(t, manifest.Artifacts, 2)
+	assert.Equal(t, FormatHTML, manifest.Artifacts[0].Format)
+	assert.Equal(t, FormatDOCX, manifest.Artifacts[1].Format)
+}
+
+func TestHandler_InvalidFormat(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t, t.TempDir())
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"content": sampleDocument, "formats": "epub"}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+}
@@ -0,0 +1,103 @@
+package publishtool
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/docx"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+)
+
+// Output formats of the publish tool.
+const (
+	FormatHTML = "html"
+	FormatPDF  = "pdf"
+	FormatDOCX = "docx"
+)
+
+// Renderer converts a document into one output format.
+type Renderer interface {
+	// Extension returns the file extension, including the dot.
+	Extension() string
+	// ContentType returns the MIME type of the output.
+	ContentType() string
+	Render(doc Document) ([]byte, error)
+}
+
+// htmlRenderer renders a standalone HTML page with the metadata in its head.
+type htmlRenderer struct{}
+
+// Extension implements Renderer.
+func (htmlRenderer) Extension() string {
+	return ".html"
+}
+
+// ContentType implements Renderer.
+func (htmlRenderer) ContentType() string {
+	return "text/html"
+}
+
+// Render implements Renderer.
+func (htmlRenderer) Render(doc Document) ([]byte, error) {
+	body, err := markdown.NewParser().Parse(doc.Markdown())
+	if err != nil {
+		return nil, fmt.Errorf("failed to render HTML: %w", err)
+	}
+	metadata := doc.Metadata
+	var page bytes.Buffer
+	page.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	fmt.Fprintf(&page, "<title>%s</title>\n", html.EscapeString(metadata.Title))
+	writeMeta(&page, "author", strings.Join(metadata.Authors, ", "))
+	writeMeta(&page, "date", metadata.Date)
+	writeMeta(&page, "description", metadata.Description)
+	writeMeta(&page, "keywords", strings.Join(metadata.Keywords, ", "))
+	page.WriteString("</head>\n<body>\n")
+	page.Write(body)
+	page.WriteString("</body>\n</html>\n")
+	return page.Bytes(), nil
+}
+
+// writeMeta writes a meta tag unless content is empty.
+func writeMeta(page *bytes.Buffer, name, content string) {
+	if content != "" {
+		fmt.Fprintf(page, "<meta name=%q content=\"%s\">\n", name, html.EscapeString(content))
+	}
+}
+
+// docxRenderer renders a Word document with the metadata as document
+// properties.
+type docxRenderer struct{}
+
+// Extension implements Renderer.
+func (docxRenderer) Extension() string {
+	return ".docx"
+}
+
+// ContentType implements Renderer.
+func (docxRenderer) ContentType() string {
+	return docx.ContentType
+}
+
+// Render implements Renderer.
+func (docxRenderer) Render(doc Document) ([]byte, error) {
+	metadata := doc.Metadata
+	created, _ := time.Parse(time.DateOnly, metadata.Date)
+	var buf bytes.Buffer
+	err := docx.Convert(&buf, docx.ConvertParams{
+		Source: doc.Markdown(),
+		Metadata: docx.Metadata{
+			Title:       metadata.Title,
+			Authors:     metadata.Authors,
+			Description: metadata.Description,
+			Keywords:    metadata.Keywords,
+			Created:     created,
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to render DOCX: %w", err)
+	}
+	return buf.Bytes(), nil
+}
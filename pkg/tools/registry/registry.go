@@ -0,0 +1,170 @@
+package registry
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/document"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/status"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Tool is the interface implemented by every tool served by dcr-mcp.
+type Tool interface {
+	// GetName returns the unique name of the tool.
+	GetName() string
+	// GetTool returns the MCP tool definition including its input schema.
+	GetTool() mcp.Tool
+	// GetAnnotations returns the MCP hints telling clients whether the tool
+	// only reads, may destroy data or reaches outside the server. Tools that
+	// set no hints get the pessimistic defaults of mcp.NewTool.
+	GetAnnotations() mcp.ToolAnnotation
+	// Handler executes the tool.
+	Handler(
+		ctx context.Context,
+		request mcp.CallToolRequest,
+	) (*mcp.CallToolResult, error)
+}
+
+// Example is a sample call of a tool, listed in the tool schema document
+// so clients can prefill forms and documentation.
+type Example struct {
+	// Description says what the call does.
+	Description string `json:"description"`
+	// Arguments are the tool arguments of the call.
+	Arguments map[string]any `json:"arguments"`
+}
+
+// ExampleProvider is implemented by tools that document sample calls.
+type ExampleProvider interface {
+	// GetExamples returns the sample calls of the tool.
+	GetExamples() []Example
+}
+
+// Invoker lists and calls the tools the server serves, through the same
+// middlewares as calls from clients.
+type Invoker interface {
+	// Tools returns the served tools sorted by name.
+	Tools() []mcp.Tool
+	// CallTool invokes the named tool with the given arguments.
+	CallTool(ctx context.Context, name string, args map[string]any) (*mcp.CallToolResult, error)
+}
+
+// Dependencies holds the shared services handed to tool factories.
+type Dependencies struct {
+	Logger *slog.Logger
+	Store  artifact.Store
+	// Resources publishes generated artifacts as MCP resources. It is nil
+	// when resources are not served.
+	Resources *resources.Catalog
+	// Uploads holds inputs sent in chunks, which tools accept by handle.
+	Uploads *upload.Store
+	// Documents keeps the named documents revised over several calls.
+	Documents *document.Store
+	// Status reports the server's health to the server-status tool.
+	Status *status.Monitor
+	// RunTests lets tools run the test suites of cloned repositories,
+	// which executes their code on the server.
+	RunTests bool
+	// SigningKeys are the keys commit signatures are verified against. It
+	// is nil when no keys are configured.
+	SigningKeys *worksummary.TrustedKeys
+	// Tools calls the other served tools. It is nil when tools are
+	// created outside the server.
+	Tools Invoker
+	// Literature replays or records the responses of literature providers.
+	// Its zero value reaches the network.
+	Literature LiteratureCassette
+	// Citations looks up the publications cited in markdown documents. It
+	// is nil when citations are left as written.
+	Citations markdown.CitationResolver
+	// Glossary maps the terms markdown documents link, when asked to, to
+	// their pages.
+	Glossary markdown.Glossary
+}
+
+// LiteratureCassette makes literature-fetch serve recorded provider
+// responses instead of reaching the network, or record them for later.
+type LiteratureCassette struct {
+	// Replay is the cassette directory responses are served from, or
+	// "bundled" for the fixtures shipped with the server.
+	Replay string
+	// Record is the cassette directory responses are written to.
+	Record string
+}
+
+// Factory creates a tool from the shared dependencies.
+type Factory func(deps Dependencies) (Tool, error)
+
+// orchestrators are the tools that call other served tools.
+var orchestrators = []string{"run-batch", "run-nl"}
+
+var (
+	mu        sync.RWMutex
+	factories = make(map[string]Factory)
+)
+
+// Register makes a tool factory available under the given name. Tool
+// packages call it from an init function, so importing a package is enough
+// to make its tool available. Register panics if the name is registered
+// twice or the factory is nil.
+func Register(name string, factory Factory) {
+	mu.Lock()
+	defer mu.Unlock()
+	if factory == nil {
+		panic("registry: Register factory is nil for tool " + name)
+	}
+	if _, dup := factories[name]; dup {
+		panic("registry: Register called twice for tool " + name)
+	}
+	factories[name] = factory
+}
+
+// Names returns the names of all registered tools in sorted order.
+func Names() []string {
+	mu.RLock()
+	defer mu.RUnlock()
+	names := make([]string, 0, len(factories))
+	for name := range factories {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// IsRegistered reports whether a tool with the given name is registered.
+func IsRegistered(name string) bool {
+	mu.RLock()
+	defer mu.RUnlock()
+	_, ok := factories[name]
+	return ok
+}
+
+// IsOrchestrator reports whether the named tool calls other served tools.
+// Such tools never call one another, so their calls cannot recurse.
+func IsOrchestrator(name string) bool {
+	return slices.Contains(orchestrators, name)
+}
+
+// New creates the named tool using its registered factory.
+func New(name string, deps Dependencies) (Tool, error) {
+	mu.RLock()
+	factory, ok := factories[name]
+	mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("tool %s is not registered", name)
+	}
+	tool, err := factory(deps)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create %s tool: %w", name, err)
+	}
+	return tool, nil
+}
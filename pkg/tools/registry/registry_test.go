@@ -0,0 +1,66 @@
+package registry
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeTool struct{ name string }
+
+func (f fakeTool) GetName() string { return f.name }
+
+func (f fakeTool) GetTool() mcp.Tool { return mcp.NewTool(f.name) }
+
+func (f fakeTool) GetAnnotations() mcp.ToolAnnotation { return f.GetTool().Annotations }
+
+func (f fakeTool) Handler(
+	_ context.Context,
+	_ mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(f.name), nil
+}
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+
+	Register("registry-test-b", func(Dependencies) (Tool, error) {
+		return fakeTool{name: "registry-test-b"}, nil
+	})
+	Register("registry-test-a", func(Dependencies) (Tool, error) {
+		return nil, errors.New("boom")
+	})
+
+	assert.True(t, IsRegistered("registry-test-a"))
+	assert.False(t, IsRegistered("registry-test-missing"))
+	names := Names()
+	assert.Less(t, slices.Index(names, "registry-test-a"), slices.Index(names, "registry-test-b"))
+
+	tool, err := New("registry-test-b", Dependencies{})
+	require.NoError(t, err)
+	assert.Equal(t, "registry-test-b", tool.GetName())
+
+	_, err = New("registry-test-a", Dependencies{})
+	require.ErrorContains(t, err, "boom")
+
+	_, err = New("registry-test-missing", Dependencies{})
+	require.Error(t, err)
+
+	assert.Panics(t, func() {
+		Register("registry-test-b", func(Dependencies) (Tool, error) {
+			return fakeTool{}, nil
+		})
+	})
+}
+
+func TestIsOrchestrator(t *testing.T) {
+	t.Parallel()
+	assert.True(t, IsOrchestrator("run-batch"))
+	assert.True(t, IsOrchestrator("run-nl"))
+	assert.False(t, IsOrchestrator("git-summary"))
+}
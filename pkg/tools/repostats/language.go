@@ -0,0 +1,122 @@
+package repostats
+
+import (
+	"path"
+	"slices"
+	"strings"
+)
+
+// Language is a programming or markup language recognized by its file
+// names.
+type Language struct {
+	Name string
+	// Comments are the prefixes of line comments. Block comments are
+	// counted as code.
+	Comments []string
+}
+
+// Comment prefixes shared by several languages.
+var (
+	slashComments = []string{"//"}
+	hashComments  = []string{"#"}
+	dashComments  = []string{"--"}
+)
+
+// languagesByExtension maps lowercase file extensions to their language.
+var languagesByExtension = map[string]Language{
+	".go":      {Name: "Go", Comments: slashComments},
+	".py":      {Name: "Python", Comments: hashComments},
+	".js":      {Name: "JavaScript", Comments: slashComments},
+	".mjs":     {Name: "JavaScript", Comments: slashComments},
+	".cjs":     {Name: "JavaScript", Comments: slashComments},
+	".jsx":     {Name: "JavaScript", Comments: slashComments},
+	".ts":      {Name: "TypeScript", Comments: slashComments},
+	".tsx":     {Name: "TypeScript", Comments: slashComments},
+	".vue":     {Name: "Vue"},
+	".svelte":  {Name: "Svelte"},
+	".java":    {Name: "Java", Comments: slashComments},
+	".kt":      {Name: "Kotlin", Comments: slashComments},
+	".scala":   {Name: "Scala", Comments: slashComments},
+	".c":       {Name: "C", Comments: slashComments},
+	".h":       {Name: "C", Comments: slashComments},
+	".cc":      {Name: "C++", Comments: slashComments},
+	".cpp":     {Name: "C++", Comments: slashComments},
+	".cxx":     {Name: "C++", Comments: slashComments},
+	".hpp":     {Name: "C++", Comments: slashComments},
+	".rs":      {Name: "Rust", Comments: slashComments},
+	".swift":   {Name: "Swift", Comments: slashComments},
+	".php":     {Name: "PHP", Comments: []string{"//", "#"}},
+	".rb":      {Name: "Ruby", Comments: hashComments},
+	".pl":      {Name: "Perl", Comments: hashComments},
+	".pm":      {Name: "Perl", Comments: hashComments},
+	".r":       {Name: "R", Comments: hashComments},
+	".jl":      {Name: "Julia", Comments: hashComments},
+	".ex":      {Name: "Elixir", Comments: hashComments},
+	".exs":     {Name: "Elixir", Comments: hashComments},
+	".hs":      {Name: "Haskell", Comments: dashComments},
+	".lua":     {Name: "Lua", Comments: dashComments},
+	".sql":     {Name: "SQL", Comments: dashComments},
+	".sh":      {Name: "Shell", Comments: hashComments},
+	".bash":    {Name: "Shell", Comments: hashComments},
+	".zsh":     {Name: "Shell", Comments: hashComments},
+	".nix":     {Name: "Nix", Comments: hashComments},
+	".nf":      {Name: "Nextflow", Comments: slashComments},
+	".proto":   {Name: "Protocol Buffers", Comments: slashComments},
+	".graphql": {Name: "GraphQL", Comments: hashComments},
+	".gql":     {Name: "GraphQL", Comments: hashComments},
+	".html":    {Name: "HTML"},
+	".htm":     {Name: "HTML"},
+	".css":     {Name: "CSS"},
+	".scss":    {Name: "SCSS", Comments: slashComments},
+	".xml":     {Name: "XML"},
+	".json":    {Name: "JSON"},
+	".yaml":    {Name: "YAML", Comments: hashComments},
+	".yml":     {Name: "YAML", Comments: hashComments},
+	".toml":    {Name: "TOML", Comments: hashComments},
+	".md":      {Name: "Markdown"},
+	".mk":      {Name: "Makefile", Comments: hashComments},
+}
+
+// languagesByName maps file names without a telling extension to their
+// language.
+var languagesByName = map[string]Language{
+	"Dockerfile":  {Name: "Dockerfile", Comments: hashComments},
+	"Makefile":    {Name: "Makefile", Comments: hashComments},
+	"GNUmakefile": {Name: "Makefile", Comments: hashComments},
+	"Justfile":    {Name: "Just", Comments: hashComments},
+	"Taskfile":    {Name: "Task", Comments: hashComments},
+}
+
+// skippedDirs are directories of vendored code, which is not the
+// repository's own.
+var skippedDirs = []string{"vendor", "node_modules"}
+
+// generatedFiles are lock files written by package managers.
+var generatedFiles = []string{"go.sum", "package-lock.json", "yarn.lock", "pnpm-lock.yaml", "Cargo.lock"}
+
+// Detect returns the language of a file, or false when it is in none of
+// the known languages.
+func Detect(file string) (Language, bool) {
+	base := path.Base(file)
+	if language, ok := languagesByName[base]; ok {
+		return language, true
+	}
+	if strings.HasPrefix(base, "Dockerfile.") {
+		return languagesByName["Dockerfile"], true
+	}
+	language, ok := languagesByExtension[strings.ToLower(path.Ext(base))]
+	return language, ok
+}
+
+// isSkipped reports whether file is vendored or generated.
+func isSkipped(file string) bool {
+	if slices.Contains(generatedFiles, path.Base(file)) {
+		return true
+	}
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if slices.Contains(skippedDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,39 @@
+package repostats
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetect(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		file string
+		want string
+	}{
+		{file: "cmd/server/main.go", want: "Go"},
+		{file: "web/src/App.TSX", want: "TypeScript"},
+		{file: "build/Dockerfile", want: "Dockerfile"},
+		{file: "Dockerfile.dev", want: "Dockerfile"},
+		{file: "Makefile", want: "Makefile"},
+		{file: "scripts/load.R", want: "R"},
+		{file: "LICENSE", want: ""},
+		{file: "data/strains.tsv", want: ""},
+	}
+	for _, testCase := range tests {
+		language, ok := Detect(testCase.file)
+		assert.Equal(t, testCase.want != "", ok, testCase.file)
+		assert.Equal(t, testCase.want, language.Name, testCase.file)
+	}
+}
+
+func TestIsSkipped(t *testing.T) {
+	t.Parallel()
+	assert.True(t, isSkipped("vendor/github.com/pkg/errors/errors.go"))
+	assert.True(t, isSkipped("web/node_modules/react/index.js"))
+	assert.True(t, isSkipped("go.sum"))
+	assert.True(t, isSkipped("web/package-lock.json"))
+	assert.False(t, isSkipped("pkg/vendors/vendor.go"))
+	assert.False(t, isSkipped("main.go"))
+}
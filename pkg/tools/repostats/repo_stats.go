@@ -0,0 +1,297 @@
+package repostats
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/orgsummary"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// RepoStatsTool counts the code of a repository by language.
+type RepoStatsTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	analyzer      *worksummary.GitAnalyzer
+	resources     *resources.Catalog
+	clientOptions []orgsummary.Option
+}
+
+// ToolOption defines a functional option for configuring RepoStatsTool.
+type ToolOption func(*RepoStatsTool)
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(r *RepoStatsTool) {
+		r.resources = catalog
+	}
+}
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) ToolOption {
+	return func(r *RepoStatsTool) {
+		r.analyzer = analyzer
+	}
+}
+
+// WithClientOptions sets options for the GitHub clients the tool creates.
+func WithClientOptions(opts ...orgsummary.Option) ToolOption {
+	return func(r *RepoStatsTool) {
+		r.clientOptions = append(r.clientOptions, opts...)
+	}
+}
+
+// StatsRequest represents the parameters for the repository statistics.
+type StatsRequest struct {
+	RepoURL string `validate:"required"`
+	// Branch is only used for clones; GitHub reports the default branch.
+	Branch string `validate:"required_if=Source clone"`
+	Source string `validate:"required,oneof=clone github"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"repo-stats",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewRepoStatsTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewRepoStatsTool creates a new RepoStatsTool instance.
+func NewRepoStatsTool(logger *slog.Logger, opts ...ToolOption) (*RepoStatsTool, error) {
+	tool := mcp.NewTool(
+		"repo-stats",
+		mcp.WithDescription(
+			"Counts the files and lines of code of a git repository by language, from a clone of a branch "+
+				"or from the languages GitHub detected",
+		),
+		mcp.WithTitleAnnotation("Repository Statistics"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to count, required for the clone source"),
+		),
+		mcp.WithString(
+			"source",
+			mcp.Description(
+				"Where the statistics come from: 'clone' (default) counts files and lines of the branch; "+
+					"'github' reads the bytes per language of the default branch of a GitHub repository without cloning",
+			),
+			mcp.Enum(SourceClone, SourceGitHub),
+		),
+	)
+	statsTool := &RepoStatsTool{
+		Name:        "repo-stats",
+		Description: "Counts the code of a repository by language",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(statsTool)
+	}
+	return statsTool, nil
+}
+
+// GetName returns the name of the tool.
+func (r *RepoStatsTool) GetName() string {
+	return r.Name
+}
+
+// GetDescription returns the description of the tool.
+func (r *RepoStatsTool) GetDescription() string {
+	return r.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (r *RepoStatsTool) GetSchema() mcp.ToolInputSchema {
+	return r.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (r *RepoStatsTool) GetTool() mcp.Tool {
+	return r.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (r *RepoStatsTool) GetAnnotations() mcp.ToolAnnotation {
+	return r.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (r *RepoStatsTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Count languages and lines of code of a cloned repository",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-stock",
+				"branch":   "develop",
+			},
+		},
+		{
+			Description: "Read repository statistics from the GitHub API without cloning",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/dcr-mcp",
+				"source":   "github",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (r *RepoStatsTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := StatsRequest{
+		RepoURL: request.GetString("repo_url", ""),
+		Branch:  request.GetString("branch", ""),
+		Source:  request.GetString("source", SourceClone),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	var stats Stats
+	var err error
+	name := fmt.Sprintf("%s-%s-stats.md", worksummary.RepoName(params.RepoURL), params.Branch)
+	provider := metrics.ServiceGitClone
+	if params.Source == SourceGitHub {
+		stats, err = r.readGitHub(ctx, params.RepoURL)
+		name = worksummary.RepoName(params.RepoURL) + "-stats.md"
+		provider = metrics.ServiceGitHub
+	} else {
+		stats, err = r.CollectStats(ctx, params)
+	}
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(stats)
+	result := provenance.Attach(mcp.NewToolResultText(content), provenance.New([]string{provider}))
+	if r.resources != nil {
+		resource, err := r.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        name,
+			MIMEType:    "text/markdown",
+			Description: "Code statistics of " + params.RepoURL,
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing statistics: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// CollectStats clones the branch and counts the code at its head.
+func (r *RepoStatsTool) CollectStats(ctx context.Context, params StatsRequest) (Stats, error) {
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := r.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.8)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Stats{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	reporter.Report(0.8, 1, "counting code")
+	head, err := repo.Head()
+	if err != nil {
+		return Stats{}, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Stats{}, fmt.Errorf("error reading branch head: %w", err)
+	}
+	tree, err := tip.Tree()
+	if err != nil {
+		return Stats{}, fmt.Errorf("error reading file tree: %w", err)
+	}
+	stats, err := Count(tree)
+	if err != nil {
+		return Stats{}, err
+	}
+	stats.RepoURL = params.RepoURL
+	stats.Branch = params.Branch
+	stats.Commit = tip.Hash.String()
+	stats.When = tip.Committer.When
+	reporter.Report(1, 1, "statistics collected")
+	return stats, nil
+}
+
+// readGitHub reads the languages GitHub detected in a repository,
+// authenticating with GITHUB_TOKEN when it is set.
+func (r *RepoStatsTool) readGitHub(ctx context.Context, repoURL string) (Stats, error) {
+	owner, name, ok := gitHubRepository(repoURL)
+	if !ok {
+		return Stats{}, toolerror.New(
+			toolerror.TypeInvalidInput,
+			"NOT_A_GITHUB_REPOSITORY",
+			"the github source needs a github.com repository URL, got: "+repoURL,
+		)
+	}
+	client := orgsummary.NewGitHubClient(
+		os.Getenv("GITHUB_TOKEN"),
+		append([]orgsummary.Option{orgsummary.WithLogger(logging.WithRequestID(r.Logger))}, r.clientOptions...)...,
+	)
+	languages, err := client.Languages(ctx, owner, name)
+	if errors.Is(err, orgsummary.ErrRepositoryNotFound) {
+		return Stats{}, toolerror.Wrap(toolerror.TypeNotFound, "REPOSITORY_NOT_FOUND", err, "failed to read languages")
+	}
+	if err != nil {
+		return Stats{}, toolerror.Upstream(metrics.ServiceGitHub, err, "failed to read languages")
+	}
+	return Stats{RepoURL: repoURL, Source: SourceGitHub, Languages: FromGitHub(languages)}, nil
+}
+
+// gitHubRepository returns the owner and name of a github.com repository
+// given by its HTTPS or SSH URL.
+func gitHubRepository(repoURL string) (owner, name string, ok bool) {
+	repoPath, found := strings.CutPrefix(repoURL, "git@github.com:")
+	if !found {
+		parsed, err := url.Parse(repoURL)
+		if err != nil || !strings.EqualFold(parsed.Host, "github.com") {
+			return "", "", false
+		}
+		repoPath = parsed.Path
+	}
+	owner, name, found = strings.Cut(strings.Trim(repoPath, "/"), "/")
+	name = strings.TrimSuffix(name, ".git")
+	if !found || owner == "" || name == "" || strings.Contains(name, "/") {
+		return "", "", false
+	}
+	return owner, name, true
+}
@@ -0,0 +1,117 @@
+package repostats
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/orgsummary"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFiles is the tree of the test repository.
+var testFiles = map[string]string{
+	"main.go":                 "package main\n\n// main starts the server.\nfunc main() {\n\tserve()\n}\n",
+	"serve.go":                "package main\n\n// serve is a stub.\nfunc serve() {}\n",
+	"scripts/setup.sh":        "#!/bin/sh\necho 'set up'\n",
+	"README.md":               "# Tools\n",
+	"LICENSE":                 "BSD 2-Clause License\n",
+	"go.sum":                  "example.org/lib v1.0.0 h1:abc=\n",
+	"vendor/example.org/l.go": "package l\n",
+	"docs/logo.png":           "\x89PNG\r\n\x1a\n\x00\x00",
+}
+
+func newTestTool(t *testing.T, opts ...ToolOption) *RepoStatsTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(worksummary.WithTimeZone(time.UTC))
+	tool, err := NewRepoStatsTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]ToolOption{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestCollectStats(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, testFiles)
+	tool := newTestTool(t)
+
+	stats, err := tool.CollectStats(context.Background(), StatsRequest{RepoURL: dir, Branch: "master", Source: SourceClone})
+	require.NoError(t, err)
+	assert.Equal(t, dir, stats.RepoURL)
+	assert.Len(t, stats.Commit, 40)
+	assert.Equal(t, gittest.Author.When, stats.When.UTC())
+	assert.Equal(t, 8, stats.Totals().Code)
+}
+
+func TestHandler_GitHub(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/repos/dictybase/dcr-mcp/languages", r.URL.Path)
+		_, _ = w.Write([]byte(`{"Go":900,"Shell":100}`))
+	}))
+	t.Cleanup(server.Close)
+	tool := newTestTool(t, WithClientOptions(orgsummary.WithBaseURL(server.URL)))
+
+	result := tooltest.Call(t, tool.Handler, "repo-stats", map[string]any{
+		"repo_url": "git@github.com:dictybase/dcr-mcp.git", "source": "github",
+	})
+	require.False(t, result.IsError)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "| Go | 900 | 90.0% |\n")
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"github"}, record.Providers)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	for _, arguments := range []map[string]any{
+		{"repo_url": "https://github.com/dictybase/dcr-mcp"},
+		{"repo_url": "https://github.com/dictybase/dcr-mcp", "branch": "develop", "source": "sloc"},
+		{"repo_url": "https://gitlab.com/dictybase/dcr-mcp", "source": "github"},
+	} {
+		result := tooltest.Call(t, tool.Handler, "repo-stats", arguments)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+	}
+}
+
+func TestGitHubRepository(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		repoURL string
+		owner   string
+		name    string
+		ok      bool
+	}{
+		{repoURL: "https://github.com/dictybase/dcr-mcp", owner: "dictybase", name: "dcr-mcp", ok: true},
+		{repoURL: "https://github.com/dictybase/dcr-mcp.git/", owner: "dictybase", name: "dcr-mcp", ok: true},
+		{repoURL: "git@github.com:dictybase/dcr-mcp.git", owner: "dictybase", name: "dcr-mcp", ok: true},
+		{repoURL: "https://github.com/dictybase/dcr-mcp/tree/develop"},
+		{repoURL: "https://github.com/dictybase"},
+		{repoURL: "https://gitlab.com/dictybase/dcr-mcp"},
+	}
+	for _, testCase := range tests {
+		owner, name, ok := gitHubRepository(testCase.repoURL)
+		assert.Equal(t, testCase.ok, ok, testCase.repoURL)
+		assert.Equal(t, testCase.owner, owner, testCase.repoURL)
+		assert.Equal(t, testCase.name, name, testCase.repoURL)
+	}
+}
@@ -0,0 +1,106 @@
+package repostats
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Sources of the statistics.
+const (
+	// SourceClone counts the files of a clone of the branch.
+	SourceClone = "clone"
+	// SourceGitHub reads the languages GitHub detected on the default
+	// branch; only their size is known.
+	SourceGitHub = "github"
+)
+
+// Stats is the code statistics of a repository.
+type Stats struct {
+	RepoURL string
+	// Branch, Commit and When are only known for a clone.
+	Branch    string
+	Commit    string
+	When      time.Time
+	Source    string
+	Languages []LanguageStats
+	// Other is the number of text files in no known language.
+	Other int
+	// Skipped is the number of vendored, generated and binary files left
+	// out.
+	Skipped int
+}
+
+// Totals sums the statistics of all languages.
+func (s Stats) Totals() LanguageStats {
+	totals := LanguageStats{Language: "Total"}
+	for _, language := range s.Languages {
+		totals.Files += language.Files
+		totals.Code += language.Code
+		totals.Comments += language.Comments
+		totals.Blank += language.Blank
+		totals.Bytes += language.Bytes
+	}
+	return totals
+}
+
+// RenderMarkdown renders the statistics as a markdown document.
+func RenderMarkdown(stats Stats) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# Repository Statistics: %s\n\n", worksummary.RepoName(stats.RepoURL))
+	if stats.Source == SourceGitHub {
+		fmt.Fprintf(&builder, "**Repository:** %s (default branch, languages detected by GitHub)\n", stats.RepoURL)
+	} else {
+		fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`, commit `%s` of %s)\n",
+			stats.RepoURL, stats.Branch, worksummary.ShortHash(stats.Commit), stats.When.Format(time.DateOnly))
+	}
+
+	totals := stats.Totals()
+	builder.WriteString("\n## Overview\n\n")
+	switch {
+	case len(stats.Languages) == 0:
+		builder.WriteString("No code in a known language.")
+	case stats.Source == SourceGitHub:
+		fmt.Fprintf(&builder, "%d bytes of code in %d languages.", totals.Bytes, len(stats.Languages))
+	default:
+		fmt.Fprintf(&builder, "%d lines of code in %d files and %d languages.",
+			totals.Code, totals.Files, len(stats.Languages))
+	}
+	if stats.Other > 0 {
+		fmt.Fprintf(&builder, " %d other files are in no known language.", stats.Other)
+	}
+	if stats.Skipped > 0 {
+		fmt.Fprintf(&builder, " %d vendored, generated or binary files were left out.", stats.Skipped)
+	}
+	builder.WriteString("\n")
+	if len(stats.Languages) == 0 {
+		return builder.String()
+	}
+
+	builder.WriteString("\n## Languages\n\n")
+	if stats.Source == SourceGitHub {
+		builder.WriteString("| Language | Bytes | Share |\n|---|---|---|\n")
+		for _, language := range stats.Languages {
+			fmt.Fprintf(&builder, "| %s | %d | %s |\n",
+				language.Language, language.Bytes, share(language.Bytes, totals.Bytes))
+		}
+		return builder.String()
+	}
+	builder.WriteString("| Language | Files | Code | Comments | Blank | Share |\n|---|---|---|---|---|---|\n")
+	for _, language := range append(stats.Languages, totals) {
+		fmt.Fprintf(&builder, "| %s | %d | %d | %d | %d | %s |\n",
+			language.Language, language.Files, language.Code, language.Comments, language.Blank,
+			share(int64(language.Code), int64(totals.Code)))
+	}
+	return builder.String()
+}
+
+// share renders part as a percentage of total.
+func share(part, total int64) string {
+	if total == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.1f%%", float64(part)*100/float64(total))
+}
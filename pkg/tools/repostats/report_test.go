@@ -0,0 +1,53 @@
+package repostats
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	stats := Stats{
+		RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+		Branch:  "develop",
+		Commit:  "9e8d7c6aaaa",
+		When:    time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+		Source:  SourceClone,
+		Languages: []LanguageStats{
+			{Language: "Go", Files: 3, Code: 300, Comments: 40, Blank: 50},
+			{Language: "Shell", Files: 1, Code: 100, Comments: 5, Blank: 2},
+		},
+		Other:   2,
+		Skipped: 4,
+	}
+	assert.Equal(t, "# Repository Statistics: dcr-mcp\n\n"+
+		"**Repository:** https://github.com/dictybase/dcr-mcp.git (branch `develop`, commit `9e8d7c6` of 2025-06-28)\n\n"+
+		"## Overview\n\n"+
+		"400 lines of code in 4 files and 2 languages. 2 other files are in no known language. "+
+		"4 vendored, generated or binary files were left out.\n\n"+
+		"## Languages\n\n"+
+		"| Language | Files | Code | Comments | Blank | Share |\n|---|---|---|---|---|---|\n"+
+		"| Go | 3 | 300 | 40 | 50 | 75.0% |\n"+
+		"| Shell | 1 | 100 | 5 | 2 | 25.0% |\n"+
+		"| Total | 4 | 400 | 45 | 52 | 100.0% |\n", RenderMarkdown(stats))
+}
+
+func TestRenderMarkdown_GitHub(t *testing.T) {
+	t.Parallel()
+	stats := Stats{
+		RepoURL:   "https://github.com/dictybase/dcr-mcp",
+		Source:    SourceGitHub,
+		Languages: []LanguageStats{{Language: "Go", Bytes: 900}, {Language: "Shell", Bytes: 100}},
+	}
+	assert.Equal(t, "# Repository Statistics: dcr-mcp\n\n"+
+		"**Repository:** https://github.com/dictybase/dcr-mcp (default branch, languages detected by GitHub)\n\n"+
+		"## Overview\n\n1000 bytes of code in 2 languages.\n\n"+
+		"## Languages\n\n| Language | Bytes | Share |\n|---|---|---|\n"+
+		"| Go | 900 | 90.0% |\n| Shell | 100 | 10.0% |\n", RenderMarkdown(stats))
+
+	empty := RenderMarkdown(Stats{RepoURL: "https://github.com/dictybase/empty", Source: SourceGitHub})
+	assert.Contains(t, empty, "No code in a known language.\n")
+	assert.NotContains(t, empty, "## Languages")
+}
@@ -0,0 +1,111 @@
+package repostats
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// LanguageStats counts the files and lines of one language.
+type LanguageStats struct {
+	Language string
+	Files    int
+	Code     int
+	Comments int
+	Blank    int
+	// Bytes is the size of the files, the only measure GitHub reports.
+	Bytes int64
+}
+
+// Count walks tree and counts the files and lines of each language. It
+// fills in the counts of the returned Stats; the caller adds the
+// repository.
+func Count(tree *object.Tree) (Stats, error) {
+	stats := Stats{Source: SourceClone}
+	byName := make(map[string]*LanguageStats)
+	err := tree.Files().ForEach(func(file *object.File) error {
+		if isSkipped(file.Name) {
+			stats.Skipped++
+			return nil
+		}
+		binary, err := file.IsBinary()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		if binary {
+			stats.Skipped++
+			return nil
+		}
+		language, ok := Detect(file.Name)
+		if !ok {
+			stats.Other++
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		counted, ok := byName[language.Name]
+		if !ok {
+			counted = &LanguageStats{Language: language.Name}
+			byName[language.Name] = counted
+		}
+		counted.Files++
+		counted.Bytes += file.Size
+		countLines(counted, language, content)
+		return nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+	for _, counted := range byName {
+		stats.Languages = append(stats.Languages, *counted)
+	}
+	sortLanguages(stats.Languages)
+	return stats, nil
+}
+
+// countLines adds the code, comment and blank lines of content to stats.
+func countLines(stats *LanguageStats, language Language, content string) {
+	if content == "" {
+		return
+	}
+	for _, line := range strings.Split(strings.TrimSuffix(content, "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "":
+			stats.Blank++
+		case slices.ContainsFunc(language.Comments, func(prefix string) bool {
+			return strings.HasPrefix(line, prefix)
+		}):
+			stats.Comments++
+		default:
+			stats.Code++
+		}
+	}
+}
+
+// FromGitHub converts the bytes per language reported by GitHub.
+func FromGitHub(bytes map[string]int64) []LanguageStats {
+	languages := make([]LanguageStats, 0, len(bytes))
+	for name, size := range bytes {
+		languages = append(languages, LanguageStats{Language: name, Bytes: size})
+	}
+	sortLanguages(languages)
+	return languages
+}
+
+// sortLanguages orders languages by lines of code, then size, largest
+// first.
+func sortLanguages(languages []LanguageStats) {
+	slices.SortFunc(languages, func(a, b LanguageStats) int {
+		return cmp.Or(
+			cmp.Compare(b.Code, a.Code),
+			cmp.Compare(b.Bytes, a.Bytes),
+			cmp.Compare(a.Language, b.Language),
+		)
+	})
+}
@@ -0,0 +1,54 @@
+package repostats
+
+import (
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	git "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCount(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, testFiles)
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+
+	stats, err := Count(tree)
+	require.NoError(t, err)
+	assert.Equal(t, SourceClone, stats.Source)
+	assert.Equal(t, 1, stats.Other)
+	assert.Equal(t, 3, stats.Skipped)
+	assert.Equal(t, []LanguageStats{
+		{Language: "Go", Files: 2, Code: 6, Comments: 2, Blank: 2, Bytes: 116},
+		{Language: "Shell", Files: 1, Code: 1, Comments: 1, Bytes: 24},
+		{Language: "Markdown", Files: 1, Code: 1, Bytes: 8},
+	}, stats.Languages)
+}
+
+func TestCountLines(t *testing.T) {
+	t.Parallel()
+	stats := &LanguageStats{}
+	countLines(stats, Language{Name: "SQL", Comments: dashComments}, "-- schema\n\nselect 1;\n  -- done")
+	assert.Equal(t, LanguageStats{Code: 1, Comments: 2, Blank: 1}, *stats)
+
+	empty := &LanguageStats{}
+	countLines(empty, Language{Name: "Go"}, "")
+	assert.Equal(t, LanguageStats{}, *empty)
+}
+
+func TestFromGitHub(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []LanguageStats{
+		{Language: "Go", Bytes: 5000},
+		{Language: "Makefile", Bytes: 120},
+		{Language: "Shell", Bytes: 120},
+	}, FromGitHub(map[string]int64{"Shell": 120, "Go": 5000, "Makefile": 120}))
+}
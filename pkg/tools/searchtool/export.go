@@ -0,0 +1,124 @@
+package searchtool
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+)
+
+const (
+	// maxExportHits bounds the articles written to an export.
+	maxExportHits = 10000
+	// exportPageSize is the page size used to walk the results of an
+	// export, the largest EuropePMC serves.
+	exportPageSize = 1000
+)
+
+// Export formats, chosen by the extension of the export path.
+const (
+	ExportCSV = "csv"
+	ExportTSV = "tsv"
+)
+
+// exportColumns are the header of an export.
+var exportColumns = []string{
+	"source", "id", "pmid", "pmcid", "doi", "title", "authors", "journal", "year", "open_access", "cited_by_count",
+}
+
+// Export describes search results written to a file.
+type Export struct {
+	Query  string `json:"query"`
+	Format string `json:"format"`
+	// Path is where the artifact store wrote the file.
+	Path string `json:"path"`
+	// URL is a download link, if the artifact store provides one.
+	URL string `json:"url,omitempty"`
+	// ResourceURI is the MCP resource of the file, if resources are served.
+	ResourceURI string `json:"resource_uri,omitempty"`
+	Exported    int    `json:"exported"`
+	HitCount    int    `json:"hit_count"`
+	// NextCursor continues the export when it stopped at maxExportHits.
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// ExportFormat returns the format of an export path, which must name a
+// .csv or .tsv file inside the export directory.
+func ExportFormat(path string) (string, error) {
+	if !filepath.IsLocal(path) {
+		return "", fmt.Errorf("export path %q must be relative and stay inside the export directory", path)
+	}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return ExportCSV, nil
+	case ".tsv":
+		return ExportTSV, nil
+	default:
+		return "", errors.New("export path must end in .csv or .tsv")
+	}
+}
+
+// exportHits walks the results of query from cursor, a page at a time,
+// until they end or maxExportHits are collected. It returns the hits, the
+// number of matches and the cursor the export stopped at, if any.
+func exportHits(ctx context.Context, client *EuropePMCClient, query, cursor string) ([]Hit, int, string, error) {
+	reporter := progress.FromContext(ctx)
+	var hits []Hit
+	for {
+		page, err := client.Search(ctx, query, cursor, min(exportPageSize, maxExportHits-len(hits)))
+		if err != nil {
+			return nil, 0, "", err
+		}
+		hits = append(hits, page.Hits...)
+		total := min(page.HitCount, maxExportHits)
+		reporter.Report(float64(len(hits)), float64(total), fmt.Sprintf("exported %d of %d articles", len(hits), total))
+		if page.NextCursor == "" || len(hits) >= maxExportHits {
+			return hits, page.HitCount, page.NextCursor, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// encodeExport writes hits as CSV or TSV with a header row.
+func encodeExport(hits []Hit, format string) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if format == ExportTSV {
+		writer.Comma = '\t'
+	}
+	rows := make([][]string, 0, len(hits)+1)
+	rows = append(rows, exportColumns)
+	for _, hit := range hits {
+		rows = append(rows, []string{
+			hit.Source,
+			hit.ID,
+			hit.PMID,
+			hit.PMCID,
+			hit.DOI,
+			hit.Title,
+			hit.Authors,
+			hit.Journal,
+			hit.Year,
+			strconv.FormatBool(hit.OpenAccess),
+			strconv.Itoa(hit.CitedByCount),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return nil, fmt.Errorf("error writing export: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// exportMIMEType returns the MIME type of an export format.
+func exportMIMEType(format string) string {
+	if format == ExportTSV {
+		return "text/tab-separated-values"
+	}
+	return "text/csv"
+}
@@ -0,0 +1,97 @@
+package searchtool
+
+import (
+	"encoding/csv"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExportFormat(t *testing.T) {
+	t.Parallel()
+	for path, want := range map[string]string{
+		"hits.csv":         ExportCSV,
+		"2024/chemo.TSV":   ExportTSV,
+		"results.tsv":      ExportTSV,
+		"nested/a/b/x.csv": ExportCSV,
+	} {
+		format, err := ExportFormat(path)
+		require.NoError(t, err, path)
+		assert.Equal(t, want, format, path)
+	}
+	for _, path := range []string{"/tmp/hits.csv", "../hits.csv", "a/../../hits.csv", "hits.xlsx", "hits"} {
+		_, err := ExportFormat(path)
+		assert.Error(t, err, path)
+	}
+}
+
+func TestEncodeExport(t *testing.T) {
+	t.Parallel()
+	data, err := encodeExport([]Hit{{
+		Source:       "MED",
+		ID:           "38000000",
+		PMID:         "38000000",
+		Title:        "Cells\tmove.",
+		Authors:      "Doe J, Roe R.",
+		Year:         "2024",
+		OpenAccess:   true,
+		CitedByCount: 3,
+	}}, ExportTSV)
+	require.NoError(t, err)
+	reader := csv.NewReader(strings.NewReader(string(data)))
+	reader.Comma = '\t'
+	rows, err := reader.ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, 2)
+	assert.Equal(t, exportColumns, rows[0])
+	assert.Equal(t, "Cells\tmove.", rows[1][5], "fields containing the separator are quoted")
+	assert.Equal(t, "true", rows[1][9])
+	assert.Equal(t, "3", rows[1][10])
+}
+
+func TestHandler_Export(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	catalog := resources.NewCatalog(server.NewMCPServer("test", "1.0.0"))
+	tool, err := NewSearchTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithClientOptions(literaturetool.WithEuropePMCURL(stubEuropePMC(t).URL)),
+		WithStore(artifact.NewLocalStore(dir)),
+		WithResources(catalog),
+	)
+	require.NoError(t, err)
+	result := tooltest.Call(t, tool.Handler, "literature-search", map[string]any{
+		"query": "dictyostelium", "cursor": "c1", "export_path": "out/hits.csv",
+	})
+	require.False(t, result.IsError)
+	export, ok := result.StructuredContent.(Export)
+	require.True(t, ok)
+	assert.Equal(t, ExportCSV, export.Format)
+	assert.Equal(t, stubHits-1, export.Exported, "the export starts at the cursor")
+	assert.Equal(t, stubHits, export.HitCount)
+	assert.Empty(t, export.NextCursor)
+	assert.Equal(t, "dcr://export/hits.csv", export.ResourceURI)
+
+	data, err := os.ReadFile(filepath.Join(dir, "out", "hits.csv"))
+	require.NoError(t, err)
+	rows, err := csv.NewReader(strings.NewReader(string(data))).ReadAll()
+	require.NoError(t, err)
+	require.Len(t, rows, stubHits)
+	assert.Equal(t, "38000001", rows[1][2])
+
+	result = tooltest.Call(t, tool.Handler, "literature-search", map[string]any{
+		"query": "dictyostelium", "export_path": "../hits.csv",
+	})
+	assert.Equal(t, "INVALID_EXPORT_PATH", tooltest.ErrorCode(t, result))
+}
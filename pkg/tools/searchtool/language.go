@@ -0,0 +1,81 @@
+package searchtool
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// english is the MEDLINE code of English, the language abstracts are
+// translated into.
+const english = "eng"
+
+// language is a language EuropePMC records articles in, by its MEDLINE
+// code, with its English name and ISO 639 codes.
+type language struct {
+	code    string
+	name    string
+	aliases []string
+}
+
+// languages are the languages of most articles in EuropePMC.
+var languages = []language{
+	{code: "eng", name: "English", aliases: []string{"en"}},
+	{code: "ger", name: "German", aliases: []string{"de", "deu"}},
+	{code: "fre", name: "French", aliases: []string{"fr", "fra"}},
+	{code: "spa", name: "Spanish", aliases: []string{"es"}},
+	{code: "ita", name: "Italian", aliases: []string{"it"}},
+	{code: "por", name: "Portuguese", aliases: []string{"pt"}},
+	{code: "dut", name: "Dutch", aliases: []string{"nl", "nld"}},
+	{code: "rus", name: "Russian", aliases: []string{"ru"}},
+	{code: "chi", name: "Chinese", aliases: []string{"zh", "zho"}},
+	{code: "jpn", name: "Japanese", aliases: []string{"ja"}},
+	{code: "kor", name: "Korean", aliases: []string{"ko"}},
+	{code: "pol", name: "Polish", aliases: []string{"pl"}},
+	{code: "cze", name: "Czech", aliases: []string{"cs", "ces"}},
+	{code: "hun", name: "Hungarian", aliases: []string{"hu"}},
+	{code: "swe", name: "Swedish", aliases: []string{"sv"}},
+	{code: "dan", name: "Danish", aliases: []string{"da"}},
+	{code: "nor", name: "Norwegian", aliases: []string{"no", "nb", "nn"}},
+	{code: "fin", name: "Finnish", aliases: []string{"fi"}},
+	{code: "tur", name: "Turkish", aliases: []string{"tr"}},
+	{code: "gre", name: "Greek", aliases: []string{"el", "ell"}},
+	{code: "heb", name: "Hebrew", aliases: []string{"he"}},
+	{code: "ara", name: "Arabic", aliases: []string{"ar"}},
+	{code: "per", name: "Persian", aliases: []string{"fa", "fas"}},
+	{code: "ukr", name: "Ukrainian", aliases: []string{"uk"}},
+	{code: "rum", name: "Romanian", aliases: []string{"ro", "ron"}},
+	{code: "bul", name: "Bulgarian", aliases: []string{"bg"}},
+	{code: "hrv", name: "Croatian", aliases: []string{"hr"}},
+	{code: "slv", name: "Slovenian", aliases: []string{"sl"}},
+	{code: "srp", name: "Serbian", aliases: []string{"sr"}},
+}
+
+// languageCode returns the MEDLINE code of a language given by its
+// MEDLINE code, its ISO 639 code or its English name, in any case.
+func languageCode(name string) (string, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, lang := range languages {
+		if name == lang.code || name == strings.ToLower(lang.name) || slices.Contains(lang.aliases, name) {
+			return lang.code, nil
+		}
+	}
+	return "", fmt.Errorf("unknown language %q, use a code such as ger or de, or a name such as German", name)
+}
+
+// languageName returns the English name of the language with a MEDLINE
+// code, or the code of a language not listed.
+func languageName(code string) string {
+	for _, lang := range languages {
+		if lang.code == code {
+			return lang.name
+		}
+	}
+	return code
+}
+
+// languageQuery restricts query to articles in the language with a
+// MEDLINE code.
+func languageQuery(query, code string) string {
+	return fmt.Sprintf("(%s) AND LANG:%q", query, code)
+}
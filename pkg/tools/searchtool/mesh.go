@@ -0,0 +1,272 @@
+package searchtool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+)
+
+const defaultMeSHURL = "https://id.nlm.nih.gov/mesh"
+
+const (
+	// maxSearchedTerms bounds the terms a MeSH search looks for in titles
+	// and abstracts, which keeps the query within EuropePMC's limits for
+	// descriptors with dozens of entry terms.
+	maxSearchedTerms = 20
+	// maxSuggestions is the number of descriptors suggested for a name
+	// that is not a descriptor.
+	maxSuggestions = 5
+)
+
+// descriptorUIPattern matches MeSH descriptor unique IDs such as D002633.
+var descriptorUIPattern = regexp.MustCompile(`^D\d{6,9}$`)
+
+// ErrUnknownDescriptor is returned when MeSH has no descriptor with the
+// name or ID searched for.
+var ErrUnknownDescriptor = errors.New("unknown MeSH descriptor")
+
+// Descriptor is the MeSH descriptor a search was expanded with.
+type Descriptor struct {
+	UI   string `json:"ui"`
+	Name string `json:"name"`
+	// EntryTerms are the synonyms and variants MeSH lists for the
+	// descriptor, such as Chemotaxes for Chemotaxis.
+	EntryTerms []string `json:"entry_terms,omitempty"`
+	// Qualifiers are the subheadings MeSH allows with the descriptor, such
+	// as physiology or genetics.
+	Qualifiers []string `json:"qualifiers,omitempty"`
+	// SearchedTerms are the name and entry terms looked for in titles and
+	// abstracts, which find articles not yet indexed with MeSH. It is empty
+	// when the search was not expanded.
+	SearchedTerms []string `json:"searched_terms,omitempty"`
+}
+
+// Query returns the EuropePMC query for articles indexed with the
+// descriptor or mentioning one of its searched terms.
+func (d Descriptor) Query() string {
+	clauses := []string{fmt.Sprintf("MESH:%q", d.Name)}
+	for _, term := range d.SearchedTerms {
+		clauses = append(clauses, fmt.Sprintf("TITLE_ABS:%q", term))
+	}
+	return "(" + strings.Join(clauses, " OR ") + ")"
+}
+
+// textTerms returns the name and entry terms worth looking for in text.
+// Inverted entry terms such as "Chemotaxis, Leukocyte" are left out, as
+// they do not occur in prose.
+func (d Descriptor) textTerms() []string {
+	terms := []string{d.Name}
+	for _, term := range d.EntryTerms {
+		if len(terms) == maxSearchedTerms {
+			break
+		}
+		if !strings.Contains(term, ", ") {
+			terms = append(terms, term)
+		}
+	}
+	return terms
+}
+
+// match returns how an article with the given MeSH headings, title and
+// abstract matched the descriptor, or nil when it did not.
+func (d Descriptor) match(headings []literaturetool.MeshHeading, text string) *MeSHMatch {
+	var found MeSHMatch
+	for _, heading := range headings {
+		if strings.EqualFold(heading.DescriptorName, d.Name) {
+			found.Headings = append(found.Headings, heading)
+		}
+	}
+	text = strings.ToLower(text)
+	for _, term := range d.SearchedTerms {
+		if strings.Contains(text, strings.ToLower(term)) {
+			found.Terms = append(found.Terms, term)
+		}
+	}
+	if len(found.Headings) == 0 && len(found.Terms) == 0 {
+		return nil
+	}
+	return &found
+}
+
+// MeSHMatch is how an article matched the descriptor of a MeSH search.
+type MeSHMatch struct {
+	// Headings are the article's MeSH headings for the descriptor, with
+	// their qualifiers.
+	Headings []literaturetool.MeshHeading `json:"headings,omitempty"`
+	// Terms are the searched terms found in the title or abstract.
+	Terms []string `json:"terms,omitempty"`
+}
+
+// Option represents a configuration option for MeSHClient.
+type Option func(*Config)
+
+// Config holds the configuration for the MeSH client.
+type Config struct {
+	meshURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithMeSHURL overrides the base URL of the MeSH lookup API descriptors are
+// resolved with.
+func WithMeSHURL(meshURL string) Option {
+	return func(c *Config) {
+		c.meshURL = meshURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// MeSHClient looks up descriptors in the MeSH RDF lookup API of the NLM.
+type MeSHClient struct {
+	httpClient *http.Client
+	baseURL    string
+	logger     *slog.Logger
+}
+
+// NewMeSHClient creates a new MeSH client.
+func NewMeSHClient(opts ...Option) *MeSHClient {
+	cfg := &Config{
+		meshURL: defaultMeSHURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &MeSHClient{
+		httpClient: ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:    strings.TrimSuffix(cfg.meshURL, "/"),
+		logger:     cfg.logger,
+	}
+}
+
+// lookupResult is a descriptor or term of a lookup response.
+type lookupResult struct {
+	Resource string `json:"resource"`
+	Label    string `json:"label"`
+}
+
+// detailsResponse is the subset of a /lookup/details response used.
+type detailsResponse struct {
+	Qualifiers []lookupResult `json:"qualifiers"`
+	Terms      []struct {
+		Label     string `json:"label"`
+		Preferred bool   `json:"preferred"`
+	} `json:"terms"`
+}
+
+// Descriptor returns the descriptor with a unique ID such as D002633, or
+// with a name such as Chemotaxis in any case, with its entry terms and
+// allowed qualifiers.
+func (c *MeSHClient) Descriptor(ctx context.Context, name string) (Descriptor, error) {
+	name = strings.TrimSpace(name)
+	ui := strings.ToUpper(name)
+	if !descriptorUIPattern.MatchString(ui) {
+		var err error
+		if ui, err = c.descriptorUI(ctx, name); err != nil {
+			return Descriptor{}, err
+		}
+	}
+	var details detailsResponse
+	if err := c.get(ctx, "/lookup/details", url.Values{"descriptor": {ui}}, &details); err != nil {
+		return Descriptor{}, fmt.Errorf("failed to read MeSH descriptor %s: %w", ui, err)
+	}
+	descriptor := Descriptor{UI: ui}
+	for _, term := range details.Terms {
+		if term.Preferred {
+			descriptor.Name = term.Label
+		} else {
+			descriptor.EntryTerms = append(descriptor.EntryTerms, term.Label)
+		}
+	}
+	if descriptor.Name == "" {
+		return Descriptor{}, fmt.Errorf("%w %s", ErrUnknownDescriptor, ui)
+	}
+	for _, qualifier := range details.Qualifiers {
+		descriptor.Qualifiers = append(descriptor.Qualifiers, qualifier.Label)
+	}
+	slices.Sort(descriptor.EntryTerms)
+	slices.Sort(descriptor.Qualifiers)
+	c.logger.Debug("resolved MeSH descriptor", "ui", ui, "name", descriptor.Name, "entry_terms", len(descriptor.EntryTerms))
+	return descriptor, nil
+}
+
+// descriptorUI returns the unique ID of the descriptor named name. A name
+// that is no descriptor's fails with ErrUnknownDescriptor, suggesting the
+// descriptors whose names contain it.
+func (c *MeSHClient) descriptorUI(ctx context.Context, name string) (string, error) {
+	var exact []lookupResult
+	params := url.Values{"label": {name}, "match": {"exact"}, "limit": {"1"}}
+	if err := c.get(ctx, "/lookup/descriptor", params, &exact); err != nil {
+		return "", fmt.Errorf("failed to look up MeSH descriptor %q: %w", name, err)
+	}
+	if len(exact) > 0 {
+		return exact[0].Resource[strings.LastIndex(exact[0].Resource, "/")+1:], nil
+	}
+	var similar []lookupResult
+	params = url.Values{"label": {name}, "match": {"contains"}, "limit": {strconv.Itoa(maxSuggestions)}}
+	if err := c.get(ctx, "/lookup/descriptor", params, &similar); err != nil || len(similar) == 0 {
+		return "", fmt.Errorf("%w %q", ErrUnknownDescriptor, name)
+	}
+	labels := make([]string, 0, len(similar))
+	for _, result := range similar {
+		labels = append(labels, result.Label)
+	}
+	return "", fmt.Errorf("%w %q, did you mean %s?", ErrUnknownDescriptor, name, strings.Join(labels, ", "))
+}
+
+// get fetches a MeSH API path with the query params and decodes the JSON
+// response into out.
+func (c *MeSHClient) get(ctx context.Context, path string, params url.Values, out any) error {
+	start := time.Now()
+	err := c.fetch(ctx, c.baseURL+path+"?"+params.Encode(), out)
+	metrics.ObserveOutbound(metrics.ServiceMeSH, start, err)
+	return err
+}
+
+// fetch performs the HTTP round trip for get.
+func (c *MeSHClient) fetch(ctx context.Context, endpoint string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("error creating MeSH request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling MeSH: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("MeSH returned status %d", resp.StatusCode)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("error decoding MeSH response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package searchtool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const detailsJSON = `{"descriptor":"http://id.nlm.nih.gov/mesh/D002633",
+	"qualifiers":[{"resource":"http://id.nlm.nih.gov/mesh/Q000502","label":"physiology"},
+		{"resource":"http://id.nlm.nih.gov/mesh/Q000235","label":"genetics"}],
+	"terms":[{"label":"Chemotaxis","preferred":true},{"label":"Chemotaxes","preferred":false},
+		{"label":"Chemotaxis, Cellular","preferred":false}]}`
+
+const meshHitsJSON = `{"hitCount":2,"nextCursorMark":"*","resultList":{"result":[
+	{"id":"38000001","source":"MED","title":"Signal relay during chemotaxis.",
+		"abstractText":"Cells move.",
+		"meshHeadingList":{"meshHeading":[
+			{"majorTopic_YN":"N","descriptorName":"Dictyostelium"},
+			{"majorTopic_YN":"N","descriptorName":"Chemotaxis","meshQualifierList":{"meshQualifier":[
+				{"abbreviation":"PH","qualifierName":"physiology","majorTopic_YN":"Y"}]}}]}},
+	{"id":"PPR1","source":"PPR","title":"A preprint","abstractText":"Directed chemotaxes of amoebae."}
+]}}`
+
+// stubMeSH serves the MeSH lookup API, which knows the descriptor
+// Chemotaxis, and the EuropePMC search endpoint, which answers every core
+// search with meshHitsJSON and records the query.
+func stubMeSH(t *testing.T, queries chan<- string) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /lookup/descriptor", func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		switch {
+		case values.Get("label") == "chemotaxis" && values.Get("match") == "exact":
+			_, _ = io.WriteString(w, `[{"resource":"http://id.nlm.nih.gov/mesh/D002633","label":"Chemotaxis"}]`)
+		case values.Get("label") == "chemo" && values.Get("match") == "contains":
+			_, _ = io.WriteString(w, `[{"label":"Chemotaxis"},{"label":"Chemotaxis, Leukocyte"}]`)
+		default:
+			_, _ = io.WriteString(w, `[]`)
+		}
+	})
+	mux.HandleFunc("GET /lookup/details", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("descriptor") != "D002633" {
+			_, _ = io.WriteString(w, `{"qualifiers":[],"terms":[]}`)
+			return
+		}
+		_, _ = io.WriteString(w, detailsJSON)
+	})
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "core", r.URL.Query().Get("resultType"))
+		queries <- r.URL.Query().Get("query")
+		_, _ = io.WriteString(w, meshHitsJSON)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestMeSHClient_Descriptor(t *testing.T) {
+	t.Parallel()
+	client := NewMeSHClient(WithMeSHURL(stubMeSH(t, nil).URL))
+
+	for _, name := range []string{"chemotaxis", " d002633 "} {
+		descriptor, err := client.Descriptor(context.Background(), name)
+		require.NoError(t, err, name)
+		assert.Equal(t, Descriptor{
+			UI:         "D002633",
+			Name:       "Chemotaxis",
+			EntryTerms: []string{"Chemotaxes", "Chemotaxis, Cellular"},
+			Qualifiers: []string{"genetics", "physiology"},
+		}, descriptor, name)
+		assert.Equal(t, []string{"Chemotaxis", "Chemotaxes"}, descriptor.textTerms(), "inverted terms are left out")
+	}
+
+	_, err := client.Descriptor(context.Background(), "chemo")
+	require.ErrorIs(t, err, ErrUnknownDescriptor)
+	assert.ErrorContains(t, err, "did you mean Chemotaxis, Chemotaxis, Leukocyte?")
+	_, err = client.Descriptor(context.Background(), "D000000")
+	require.ErrorIs(t, err, ErrUnknownDescriptor)
+}
+
+func TestHandler_MeSHSearch(t *testing.T) {
+	t.Parallel()
+	queries := make(chan string, 1)
+	server := stubMeSH(t, queries)
+	result := callTool(t, server.URL, map[string]any{"query": "dictyostelium", "mesh": "chemotaxis"},
+		WithMeSHOptions(WithMeSHURL(server.URL)))
+	require.False(t, result.IsError)
+	assert.Equal(t,
+		`(dictyostelium) AND (MESH:"Chemotaxis" OR TITLE_ABS:"Chemotaxis" OR TITLE_ABS:"Chemotaxes")`,
+		<-queries)
+
+	page, ok := result.StructuredContent.(Page)
+	require.True(t, ok)
+	require.NotNil(t, page.MeSH)
+	assert.Equal(t, "D002633", page.MeSH.UI)
+	require.Len(t, page.Hits, 2)
+	assert.Equal(t, &MeSHMatch{
+		Headings: []literaturetool.MeshHeading{{
+			DescriptorName: "Chemotaxis",
+			MeshQualifiers: []literaturetool.MeshQualifier{{QualifierName: "physiology", MajorTopic: true}},
+		}},
+		Terms: []string{"Chemotaxis"},
+	}, page.Hits[0].MeSH)
+	assert.Equal(t, &MeSHMatch{Terms: []string{"Chemotaxes"}}, page.Hits[1].MeSH, "matched in the abstract only")
+
+	text := RenderMarkdown(page)
+	assert.Contains(t, text, "Searched by MeSH descriptor **Chemotaxis** (D002633), expanded with 2 terms")
+	assert.Contains(t, text, "   *MeSH:* Chemotaxis/physiology*\n")
+	assert.Contains(t, text, "   *Mentions:* Chemotaxes\n")
+
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"europepmc", "mesh"}, record.Providers)
+}
+
+func TestHandler_MeSHWithoutExpansion(t *testing.T) {
+	t.Parallel()
+	queries := make(chan string, 1)
+	server := stubMeSH(t, queries)
+	result := callTool(t, server.URL, map[string]any{"mesh": "D002633", "expand_mesh": false},
+		WithMeSHOptions(WithMeSHURL(server.URL)))
+	require.False(t, result.IsError)
+	assert.Equal(t, `(MESH:"Chemotaxis")`, <-queries)
+
+	page, ok := result.StructuredContent.(Page)
+	require.True(t, ok)
+	data, err := json.Marshal(page)
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "searched_terms")
+	assert.Nil(t, page.Hits[1].MeSH, "unindexed articles do not match")
+
+	result = callTool(t, server.URL, map[string]any{"mesh": "chemo"}, WithMeSHOptions(WithMeSHURL(server.URL)))
+	require.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, "UNKNOWN_MESH_DESCRIPTOR", toolErr.Code)
+}
@@ -0,0 +1,128 @@
+package searchtool
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RenderMarkdown renders a page of search results as markdown, with the
+// MeSH matches and translated abstracts under their articles, ending with
+// the cursor of the next page.
+func RenderMarkdown(page Page) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Search results for `%s`\n\n", page.Query)
+	if page.MeSH != nil {
+		fmt.Fprintf(&sb, "Searched by MeSH descriptor **%s** (%s)", page.MeSH.Name, page.MeSH.UI)
+		if terms := len(page.MeSH.SearchedTerms); terms > 0 {
+			fmt.Fprintf(&sb, ", expanded with %d terms searched in titles and abstracts", terms)
+		}
+		sb.WriteString(".\n\n")
+	}
+	if len(page.Hits) == 0 {
+		if page.Cursor == InitialCursor {
+			sb.WriteString("No articles in EuropePMC match the query.\n")
+		} else {
+			fmt.Fprintf(&sb, "No more articles; all %d matches have been listed.\n", page.HitCount)
+		}
+		return sb.String()
+	}
+	fmt.Fprintf(&sb, "%d articles match; this page lists %d.\n\n", page.HitCount, len(page.Hits))
+	for i, hit := range page.Hits {
+		fmt.Fprintf(&sb, "%d. %s\n", i+1, formatHit(hit))
+		if hit.MeSH != nil {
+			writeMeSHMatch(&sb, *hit.MeSH)
+		}
+		if hit.TranslatedAbstract != "" {
+			fmt.Fprintf(&sb, "   *Abstract, translated from %s:* %s\n", languageName(hit.Language), hit.TranslatedAbstract)
+		}
+	}
+	if page.TranslationErrors > 0 {
+		fmt.Fprintf(&sb, "\n%d abstracts could not be translated; their originals are in the structured result.\n",
+			page.TranslationErrors)
+	}
+	if page.NextCursor == "" {
+		sb.WriteString("\nThis is the last page.\n")
+	} else {
+		fmt.Fprintf(&sb, "\nCall again with `cursor` set to `%s` for the next page.\n", page.NextCursor)
+	}
+	return sb.String()
+}
+
+// formatHit formats an article on one line: title, authors, journal, year
+// and identifiers.
+func formatHit(hit Hit) string {
+	parts := []string{"**" + strings.TrimSuffix(hit.Title, ".") + ".**"}
+	if hit.Authors != "" {
+		parts = append(parts, hit.Authors)
+	}
+	if hit.Journal != "" {
+		parts = append(parts, "*"+hit.Journal+"*")
+	}
+	if hit.Year != "" {
+		parts = append(parts, "("+hit.Year+")")
+	}
+	ids := []string{fmt.Sprintf("[%s:%s](https://europepmc.org/article/%s/%s)", hit.Source, hit.ID, hit.Source, hit.ID)}
+	if hit.DOI != "" {
+		ids = append(ids, fmt.Sprintf("[doi:%s](https://doi.org/%s)", hit.DOI, hit.DOI))
+	}
+	if hit.OpenAccess {
+		ids = append(ids, "open access")
+	}
+	if hit.CitedByCount > 0 {
+		ids = append(ids, fmt.Sprintf("cited by %d", hit.CitedByCount))
+	}
+	return strings.Join(parts, " ") + " " + strings.Join(ids, ", ")
+}
+
+// writeMeSHMatch writes how an article matched a MeSH descriptor under it,
+// marking major topics with an asterisk as PubMed does.
+func writeMeSHMatch(sb *strings.Builder, match MeSHMatch) {
+	if len(match.Headings) > 0 {
+		headings := make([]string, 0, len(match.Headings))
+		for _, heading := range match.Headings {
+			formatted := heading.DescriptorName + majorMark(heading.MajorTopic)
+			for _, qualifier := range heading.MeshQualifiers {
+				formatted += "/" + qualifier.QualifierName + majorMark(qualifier.MajorTopic)
+			}
+			headings = append(headings, formatted)
+		}
+		fmt.Fprintf(sb, "   *MeSH:* %s\n", strings.Join(headings, "; "))
+	}
+	if len(match.Terms) > 0 {
+		fmt.Fprintf(sb, "   *Mentions:* %s\n", strings.Join(match.Terms, ", "))
+	}
+}
+
+// majorMark returns the asterisk marking a major topic.
+func majorMark(major bool) string {
+	if major {
+		return "*"
+	}
+	return ""
+}
+
+// RenderExport reports where an export was written.
+func RenderExport(export Export) string {
+	var sb strings.Builder
+	fmt.Fprintf(
+		&sb,
+		"Exported %d of %d articles matching `%s` as %s to %s\n",
+		export.Exported,
+		export.HitCount,
+		export.Query,
+		strings.ToUpper(export.Format),
+		export.Path,
+	)
+	if export.URL != "" {
+		fmt.Fprintf(&sb, "Download URL: %s\n", export.URL)
+	}
+	if export.NextCursor != "" {
+		fmt.Fprintf(
+			&sb,
+			"\nThe export stopped at %d articles; call again with `cursor` set to `%s` to export the rest.\n",
+			maxExportHits,
+			export.NextCursor,
+		)
+	}
+	return sb.String()
+}
@@ -0,0 +1,45 @@
+package searchtool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	page := Page{
+		Query:    "chemotaxis",
+		Cursor:   InitialCursor,
+		PageSize: 1,
+		HitCount: 12,
+		Hits: []Hit{{
+			ID:           "23172289",
+			Source:       "MED",
+			DOI:          "10.1093/nar/gks1064",
+			Title:        "dictyBase 2013.",
+			Authors:      "Basu S, Fey P.",
+			Journal:      "Nucleic Acids Res",
+			Year:         "2013",
+			OpenAccess:   true,
+			CitedByCount: 85,
+		}},
+		NextCursor: "AoE=",
+	}
+	text := RenderMarkdown(page)
+	assert.Contains(t, text, "# Search results for `chemotaxis`")
+	assert.Contains(t, text, "12 articles match; this page lists 1.")
+	assert.Contains(
+		t,
+		text,
+		"1. **dictyBase 2013.** Basu S, Fey P. *Nucleic Acids Res* (2013) "+
+			"[MED:23172289](https://europepmc.org/article/MED/23172289), "+
+			"[doi:10.1093/nar/gks1064](https://doi.org/10.1093/nar/gks1064), open access, cited by 85",
+	)
+	assert.Contains(t, text, "Call again with `cursor` set to `AoE=` for the next page.")
+
+	page.NextCursor = ""
+	assert.Contains(t, RenderMarkdown(page), "This is the last page.")
+	assert.Contains(t, RenderMarkdown(Page{Query: "x", Cursor: InitialCursor}), "No articles in EuropePMC match")
+	assert.Contains(t, RenderMarkdown(Page{Query: "x", Cursor: "AoE=", HitCount: 3}), "all 3 matches have been listed")
+}
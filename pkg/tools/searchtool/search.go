@@ -0,0 +1,112 @@
+package searchtool
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/dictybase/dcr-mcp/pkg/retry"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+)
+
+// InitialCursor requests the first page of a search.
+const InitialCursor = "*"
+
+// ErrInvalidQuery is returned when EuropePMC rejects the query or cursor
+// of a search.
+var ErrInvalidQuery = errors.New("search rejected by EuropePMC")
+
+// EuropePMCClient searches the EuropePMC REST API through the literature
+// client, which rate limits, retries and replays its requests.
+type EuropePMCClient struct {
+	literature *literaturetool.LiteratureClient
+	logger     *slog.Logger
+}
+
+// NewEuropePMCClient creates a new EuropePMC client logging to logger,
+// with the options of the literature client.
+func NewEuropePMCClient(logger *slog.Logger, opts ...literaturetool.Option) (*EuropePMCClient, error) {
+	literature, err := literaturetool.NewLiteratureClient(
+		append([]literaturetool.Option{literaturetool.WithLogger(logger)}, opts...)...,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &EuropePMCClient{literature: literature, logger: logger}, nil
+}
+
+// Search returns the page of the results of query that starts at cursor.
+// EuropePMC hands out the cursor of the next page with each page, so a
+// result set is walked by passing NextCursor back until it is empty.
+func (c *EuropePMCClient) Search(ctx context.Context, query, cursor string, pageSize int) (Page, error) {
+	return c.search(ctx, query, cursor, pageSize, "lite", toHit)
+}
+
+// SearchAbstracts is Search with the language and the abstract of every
+// article, which make the response several times larger.
+func (c *EuropePMCClient) SearchAbstracts(ctx context.Context, query, cursor string, pageSize int) (Page, error) {
+	return c.search(ctx, query, cursor, pageSize, "core", toHit)
+}
+
+// SearchMeSH is SearchAbstracts with how each article matched descriptor,
+// for queries built with its Query.
+func (c *EuropePMCClient) SearchMeSH(
+	ctx context.Context,
+	descriptor Descriptor,
+	query, cursor string,
+	pageSize int,
+) (Page, error) {
+	return c.search(ctx, query, cursor, pageSize, "core", func(response hitResponse) Hit {
+		hit := toHit(response)
+		hit.MeSH = descriptor.match(toMeshHeadings(response.MeshHeadingList.MeshHeading), hit.Title+" "+hit.Abstract)
+		return hit
+	})
+}
+
+// search fetches a page of results of the result type, lite for the
+// listing fields or core for the whole records, converting each article
+// with convert.
+func (c *EuropePMCClient) search(
+	ctx context.Context,
+	query, cursor string,
+	pageSize int,
+	resultType string,
+	convert func(hitResponse) Hit,
+) (Page, error) {
+	values := url.Values{
+		"query":      {query},
+		"format":     {"json"},
+		"resultType": {resultType},
+		"cursorMark": {cursor},
+		"pageSize":   {strconv.Itoa(pageSize)},
+	}
+	var response searchResponse
+	err := c.literature.FetchEuropePMC(ctx, "/search?"+values.Encode(), &response)
+	var statusErr *retry.StatusError
+	if errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusBadRequest {
+		return Page{}, fmt.Errorf("%w: %w", ErrInvalidQuery, err)
+	}
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to search for %q: %w", query, err)
+	}
+	page := Page{
+		Query:    query,
+		Cursor:   cursor,
+		PageSize: pageSize,
+		HitCount: response.HitCount,
+		Hits:     make([]Hit, 0, len(response.ResultList.Result)),
+	}
+	for _, hit := range response.ResultList.Result {
+		page.Hits = append(page.Hits, convert(hit))
+	}
+	// The last page repeats its own cursor as the next one, or is short.
+	if response.NextCursorMark != "" && response.NextCursorMark != cursor && len(page.Hits) == pageSize {
+		page.NextCursor = response.NextCursorMark
+	}
+	c.logger.Debug("searched EuropePMC", "query", query, "hits", response.HitCount, "page", len(page.Hits))
+	return page, nil
+}
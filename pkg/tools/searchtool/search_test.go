@@ -0,0 +1,131 @@
+package searchtool
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubHits is the number of articles matching every query of the stub.
+const stubHits = 5
+
+// stubEuropePMC serves the EuropePMC search endpoint with stubHits
+// articles, paged with cursors of the form c<offset>. The query "bad" is
+// rejected like a malformed query.
+func stubEuropePMC(t *testing.T) *httptest.Server {
+	t.Helper()
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /search", func(w http.ResponseWriter, r *http.Request) {
+		values := r.URL.Query()
+		if values.Get("query") == "bad" {
+			http.Error(w, `{"errMsg":"invalid query"}`, http.StatusBadRequest)
+			return
+		}
+		assert.Equal(t, "json", values.Get("format"))
+		pageSize, err := strconv.Atoi(values.Get("pageSize"))
+		require.NoError(t, err)
+		cursor := values.Get("cursorMark")
+		offset := 0
+		if cursor != InitialCursor {
+			offset, err = strconv.Atoi(strings.TrimPrefix(cursor, "c"))
+			require.NoError(t, err)
+		}
+		end := min(offset+pageSize, stubHits)
+		results := make([]map[string]any, 0, pageSize)
+		for i := offset; i < end; i++ {
+			results = append(results, map[string]any{
+				"id":           strconv.Itoa(38000000 + i),
+				"source":       "MED",
+				"pmid":         strconv.Itoa(38000000 + i),
+				"title":        fmt.Sprintf("Article %d.", i),
+				"authorString": "Doe J, Roe R.",
+				"journalTitle": "Dev Biol",
+				"pubYear":      "2024",
+				"isOpenAccess": "Y",
+				"citedByCount": i,
+			})
+		}
+		next := fmt.Sprintf("c%d", end)
+		if offset >= stubHits {
+			next = cursor
+		}
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"hitCount":       stubHits,
+			"nextCursorMark": next,
+			"resultList":     map[string]any{"result": results},
+		}))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestClient returns a client of the EuropePMC server at baseURL.
+func newTestClient(t *testing.T, baseURL string) *EuropePMCClient {
+	t.Helper()
+	client, err := NewEuropePMCClient(slog.Default(), literaturetool.WithEuropePMCURL(baseURL))
+	require.NoError(t, err)
+	return client
+}
+
+func TestSearch_WalksPages(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, stubEuropePMC(t).URL)
+	var pmids []string
+	cursor := InitialCursor
+	for pages := 0; cursor != ""; pages++ {
+		require.Less(t, pages, stubHits, "pagination does not end")
+		page, err := client.Search(context.Background(), "dictyostelium", cursor, 2)
+		require.NoError(t, err)
+		assert.Equal(t, stubHits, page.HitCount)
+		for _, hit := range page.Hits {
+			pmids = append(pmids, hit.PMID)
+		}
+		cursor = page.NextCursor
+	}
+	assert.Equal(t, []string{"38000000", "38000001", "38000002", "38000003", "38000004"}, pmids)
+}
+
+func TestSearch_FullLastPage(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, stubEuropePMC(t).URL)
+	page, err := client.Search(context.Background(), "dictyostelium", InitialCursor, stubHits)
+	require.NoError(t, err)
+	require.Len(t, page.Hits, stubHits)
+	assert.Equal(t, "c5", page.NextCursor, "a full page cannot tell that it is the last")
+	hit := page.Hits[1]
+	assert.Equal(t, Hit{
+		ID:           "38000001",
+		Source:       "MED",
+		PMID:         "38000001",
+		Title:        "Article 1.",
+		Authors:      "Doe J, Roe R.",
+		Journal:      "Dev Biol",
+		Year:         "2024",
+		OpenAccess:   true,
+		CitedByCount: 1,
+	}, hit)
+
+	page, err = client.Search(context.Background(), "dictyostelium", page.NextCursor, stubHits)
+	require.NoError(t, err)
+	assert.Empty(t, page.Hits)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestSearch_InvalidQuery(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, stubEuropePMC(t).URL)
+	_, err := client.Search(context.Background(), "bad", InitialCursor, 10)
+	require.ErrorIs(t, err, ErrInvalidQuery)
+}
@@ -0,0 +1,528 @@
+package searchtool
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// defaultPageSize is the number of articles listed per page by
+	// default.
+	defaultPageSize = 25
+	// maxPageSize bounds a page, which keeps a response within the size
+	// clients accept.
+	maxPageSize = 100
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// SearchTool searches EuropePMC, a page of results per call.
+type SearchTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []literaturetool.Option
+	meshOptions   []Option
+	store         artifact.Store
+	resources     *resources.Catalog
+	translator    Translator
+}
+
+// ToolOption defines a functional option for configuring SearchTool.
+type ToolOption func(*SearchTool)
+
+// WithClientOptions sets options for the literature clients the tool
+// reaches EuropePMC with.
+func WithClientOptions(opts ...literaturetool.Option) ToolOption {
+	return func(s *SearchTool) {
+		s.clientOptions = append(s.clientOptions, opts...)
+	}
+}
+
+// WithMeSHOptions sets options for the MeSH clients the tool creates.
+func WithMeSHOptions(opts ...Option) ToolOption {
+	return func(s *SearchTool) {
+		s.meshOptions = append(s.meshOptions, opts...)
+	}
+}
+
+// WithStore sets the store exports are written to.
+func WithStore(store artifact.Store) ToolOption {
+	return func(s *SearchTool) {
+		s.store = store
+	}
+}
+
+// WithResources publishes exports as MCP resources in the catalog.
+func WithResources(catalog *resources.Catalog) ToolOption {
+	return func(s *SearchTool) {
+		s.resources = catalog
+	}
+}
+
+// WithTranslator sets the language model client abstracts are translated
+// with, which is otherwise created from OPENAI_API_KEY on every call that
+// translates.
+func WithTranslator(translator Translator) ToolOption {
+	return func(s *SearchTool) {
+		s.translator = translator
+	}
+}
+
+// SearchRequest represents the parameters of a search.
+type SearchRequest struct {
+	Query string `validate:"required_without=MeSH"`
+	// MeSH is the name or unique ID of a MeSH descriptor to search by, if
+	// any, and ExpandMeSH also looks for its entry terms in titles and
+	// abstracts.
+	MeSH       string
+	ExpandMeSH bool
+	// Cursor is * for the first page, or the next cursor of a page.
+	Cursor   string `validate:"required,printascii,excludes= "`
+	PageSize int    `validate:"min=1,max=100"`
+	// ExportPath writes all results from Cursor on to a CSV or TSV file
+	// instead of returning a page.
+	ExportPath string
+	// Language is the MEDLINE code of the language articles are limited
+	// to, if any.
+	Language string
+	// Translate translates the abstracts of articles not in English; it
+	// applies to pages, not exports.
+	Translate bool `validate:"excluded_with=ExportPath"`
+	// descriptor is the resolved MeSH descriptor.
+	descriptor *Descriptor
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"literature-search",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			clientOpts, err := literaturetool.CassetteOptions(deps.Literature)
+			if err != nil {
+				return nil, err
+			}
+			return NewSearchTool(
+				deps.Logger,
+				WithClientOptions(clientOpts...),
+				WithStore(deps.Store),
+				WithResources(deps.Resources),
+			)
+		},
+	)
+}
+
+// NewSearchTool creates a new SearchTool instance.
+func NewSearchTool(logger *slog.Logger, opts ...ToolOption) (*SearchTool, error) {
+	tool := mcp.NewTool(
+		"literature-search",
+		mcp.WithDescription(
+			"Searches EuropePMC for articles matching a query, one page per call. Each page ends with "+
+				"the cursor of the next one; use literature-fetch for the full record of an article",
+		),
+		mcp.WithTitleAnnotation("EuropePMC Search"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"query",
+			mcp.Description(
+				"A EuropePMC query, e.g. dictyostelium AND chemotaxis or AUTH:\"Fey P\"; required unless mesh is given",
+			),
+		),
+		mcp.WithString(
+			"mesh",
+			mcp.Description(
+				"A MeSH descriptor to search by, given by name or ID, e.g. Chemotaxis or D002633. Combined with "+
+					"query when both are given; each hit reports the MeSH headings and terms it matched",
+			),
+		),
+		mcp.WithBoolean(
+			"expand_mesh",
+			mcp.Description(
+				"Also find articles mentioning the descriptor or its entry terms in their title or abstract, "+
+					"which catches articles not yet indexed with MeSH. Defaults to true",
+			),
+		),
+		mcp.WithString(
+			"cursor",
+			mcp.Description(fmt.Sprintf(
+				"The next_cursor of the previous page, or %s for the first page (defaults to %s)",
+				InitialCursor,
+				InitialCursor,
+			)),
+		),
+		mcp.WithNumber(
+			"page_size",
+			mcp.Description(fmt.Sprintf(
+				"Number of articles per page, at most %d (defaults to %d)",
+				maxPageSize,
+				defaultPageSize,
+			)),
+		),
+		mcp.WithString(
+			"language",
+			mcp.Description(
+				"Only list articles written in this language, given by name or code, e.g. German, de or ger",
+			),
+		),
+		mcp.WithBoolean(
+			"translate",
+			mcp.Description(
+				"Add the abstract of every article and translate those not in English into English with "+
+					"the language model, keeping the original. Not available with export_path; requires "+
+					"OPENAI_API_KEY. Defaults to false",
+			),
+		),
+		mcp.WithString(
+			"export_path",
+			mcp.Description(fmt.Sprintf(
+				"Write the results from cursor on, up to %d articles, to this .csv or .tsv file in the "+
+					"server's export directory and return its location instead of a page",
+				maxExportHits,
+			)),
+		),
+	)
+	searchTool := &SearchTool{
+		Name:        "literature-search",
+		Description: "Searches EuropePMC with cursor pagination",
+		Tool:        tool,
+		Logger:      logger,
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(searchTool)
+	}
+	return searchTool, nil
+}
+
+// GetName returns the name of the tool.
+func (s *SearchTool) GetName() string {
+	return s.Name
+}
+
+// GetDescription returns the description of the tool.
+func (s *SearchTool) GetDescription() string {
+	return s.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (s *SearchTool) GetSchema() mcp.ToolInputSchema {
+	return s.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (s *SearchTool) GetTool() mcp.Tool {
+	return s.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (s *SearchTool) GetAnnotations() mcp.ToolAnnotation {
+	return s.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (s *SearchTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the first 50 articles about chemotaxis in Dictyostelium",
+			Arguments: map[string]any{
+				"query":     "dictyostelium AND chemotaxis",
+				"page_size": 50,
+			},
+		},
+		{
+			Description: "Continue a search with the next cursor of the previous page",
+			Arguments: map[string]any{
+				"query":  "dictyostelium AND chemotaxis",
+				"cursor": "AoIIP4AAACgzODAwMDAwMQ==",
+			},
+		},
+		{
+			Description: "List articles on Dictyostelium indexed with the MeSH descriptor Chemotaxis",
+			Arguments: map[string]any{
+				"query":       "dictyostelium",
+				"mesh":        "Chemotaxis",
+				"expand_mesh": false,
+			},
+		},
+		{
+			Description: "List German articles about Dictyostelium with their abstracts in English",
+			Arguments: map[string]any{
+				"query":     "dictyostelium",
+				"language":  "German",
+				"translate": true,
+			},
+		},
+		{
+			Description: "Export every article of a search to a spreadsheet",
+			Arguments: map[string]any{
+				"query":       "dictyostelium AND chemotaxis AND PUB_YEAR:2024",
+				"export_path": "chemotaxis-2024.tsv",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (s *SearchTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := SearchRequest{
+		Query:      strings.TrimSpace(request.GetString("query", "")),
+		MeSH:       strings.TrimSpace(request.GetString("mesh", "")),
+		ExpandMeSH: request.GetBool("expand_mesh", true),
+		Cursor:     strings.TrimSpace(request.GetString("cursor", InitialCursor)),
+		PageSize:   request.GetInt("page_size", defaultPageSize),
+		ExportPath: strings.TrimSpace(request.GetString("export_path", "")),
+		Translate:  request.GetBool("translate", false),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	if name := request.GetString("language", ""); strings.TrimSpace(name) != "" {
+		code, err := languageCode(name)
+		if err != nil {
+			return toolerror.Result(toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_LANGUAGE", err, "invalid language")), nil
+		}
+		params.Language = code
+	}
+	sources := []string{metrics.ServiceEuropePMC}
+	if params.MeSH != "" {
+		descriptor, err := s.resolveDescriptor(ctx, params)
+		if err != nil {
+			return toolerror.Result(err), nil
+		}
+		params.descriptor = &descriptor
+		sources = append(sources, metrics.ServiceMeSH)
+	}
+	if params.ExportPath != "" {
+		return s.handleExport(ctx, params, sources)
+	}
+	page, err := s.Search(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	if page.Translated > 0 {
+		sources = append(sources, metrics.ServiceOpenAI)
+	}
+	return provenance.Attach(
+		mcp.NewToolResultStructured(page, RenderMarkdown(page)),
+		provenance.New(sources),
+	), nil
+}
+
+// resolveDescriptor looks up the MeSH descriptor of params, with the terms
+// to search for when params expands it.
+func (s *SearchTool) resolveDescriptor(ctx context.Context, params SearchRequest) (Descriptor, error) {
+	logger := logging.WithRequestID(s.Logger)
+	client := NewMeSHClient(append([]Option{WithLogger(logger)}, s.meshOptions...)...)
+	descriptor, err := client.Descriptor(ctx, params.MeSH)
+	if errors.Is(err, ErrUnknownDescriptor) {
+		return Descriptor{}, toolerror.Wrap(toolerror.TypeInvalidInput, "UNKNOWN_MESH_DESCRIPTOR", err, "invalid mesh")
+	}
+	if err != nil {
+		return Descriptor{}, toolerror.Upstream(metrics.ServiceMeSH, err, "MeSH lookup failed")
+	}
+	if params.ExpandMeSH {
+		descriptor.SearchedTerms = descriptor.textTerms()
+	}
+	return descriptor, nil
+}
+
+// query returns the EuropePMC query of params, combined with its MeSH
+// descriptor and limited to its language.
+func (params SearchRequest) query() string {
+	query := params.Query
+	switch {
+	case params.descriptor == nil:
+	case query == "":
+		query = params.descriptor.Query()
+	default:
+		query = fmt.Sprintf("(%s) AND %s", query, params.descriptor.Query())
+	}
+	if params.Language == "" {
+		return query
+	}
+	return languageQuery(query, params.Language)
+}
+
+// Search fetches the page of params, translating its abstracts when params
+// asks for it.
+func (s *SearchTool) Search(ctx context.Context, params SearchRequest) (Page, error) {
+	logger := logging.WithRequestID(s.Logger)
+	var translator Translator
+	if params.Translate {
+		var err error
+		if translator, err = s.translatorClient(); err != nil {
+			return Page{}, err
+		}
+	}
+	client, err := NewEuropePMCClient(logger, s.clientOptions...)
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to create EuropePMC client: %w", err)
+	}
+	search := client.Search
+	switch {
+	case params.descriptor != nil:
+		search = func(ctx context.Context, query, cursor string, pageSize int) (Page, error) {
+			return client.SearchMeSH(ctx, *params.descriptor, query, cursor, pageSize)
+		}
+	case params.Translate:
+		search = client.SearchAbstracts
+	}
+	page, err := search(ctx, params.query(), params.Cursor, params.PageSize)
+	if errors.Is(err, ErrInvalidQuery) {
+		return Page{}, toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_QUERY",
+			err,
+			"invalid query or cursor",
+		)
+	}
+	if err != nil {
+		return Page{}, toolerror.Upstream(metrics.ServiceEuropePMC, err, "search failed")
+	}
+	page.MeSH = params.descriptor
+	logger.Info("searched EuropePMC", "hits", page.HitCount, "listed", len(page.Hits))
+	if translator != nil {
+		if err := translateAbstracts(ctx, translator, &page, logger); err != nil {
+			return Page{}, fmt.Errorf("translation stopped: %w", err)
+		}
+		logger.Info("translated abstracts", "translated", page.Translated, "failed", page.TranslationErrors)
+	}
+	return page, nil
+}
+
+// translatorClient returns the translator of the tool, or creates one from
+// OPENAI_API_KEY.
+func (s *SearchTool) translatorClient() (Translator, error) {
+	if s.translator != nil {
+		return s.translator, nil
+	}
+	apiKey := os.Getenv("OPENAI_API_KEY")
+	if apiKey == "" {
+		return nil, toolerror.New(
+			toolerror.TypeConfiguration,
+			"MISSING_OPENAI_API_KEY",
+			"OPENAI_API_KEY is not set on the server; translate needs it",
+		)
+	}
+	client, err := worksummary.NewOpenAIClient(apiKey)
+	if err != nil {
+		return nil, toolerror.Wrap(
+			toolerror.TypeConfiguration,
+			"OPENAI_CLIENT",
+			err,
+			"error initializing OpenAI client",
+		)
+	}
+	return client, nil
+}
+
+// handleExport writes the results of params to its export path, fetched
+// from the services in sources.
+func (s *SearchTool) handleExport(
+	ctx context.Context,
+	params SearchRequest,
+	sources []string,
+) (*mcp.CallToolResult, error) {
+	format, err := ExportFormat(params.ExportPath)
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_EXPORT_PATH",
+			err,
+			"invalid export_path",
+		)), nil
+	}
+	export, data, err := s.Export(ctx, params, format)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	var links []mcp.Content
+	if s.resources != nil {
+		resource, err := s.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindExport,
+			Name:        filepath.Base(params.ExportPath),
+			MIMEType:    exportMIMEType(format),
+			Description: fmt.Sprintf("EuropePMC search results for %s", export.Query),
+			Data:        data,
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing export: %w", err)), nil
+		}
+		export.ResourceURI = resource.URI
+		links = append(links, resources.Link(resource))
+	}
+	result := provenance.Attach(
+		mcp.NewToolResultStructured(export, RenderExport(export)),
+		provenance.New(sources),
+	)
+	result.Content = append(result.Content, links...)
+	return result, nil
+}
+
+// Export writes the results of params, from its cursor on, to its export
+// path in the artifact store and returns the export with the file's
+// content.
+func (s *SearchTool) Export(ctx context.Context, params SearchRequest, format string) (Export, []byte, error) {
+	logger := logging.WithRequestID(s.Logger)
+	client, err := NewEuropePMCClient(logger, s.clientOptions...)
+	if err != nil {
+		return Export{}, nil, fmt.Errorf("failed to create EuropePMC client: %w", err)
+	}
+	hits, hitCount, next, err := exportHits(ctx, client, params.query(), params.Cursor)
+	if errors.Is(err, ErrInvalidQuery) {
+		return Export{}, nil, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_QUERY", err, "invalid query or cursor")
+	}
+	if err != nil {
+		return Export{}, nil, toolerror.Upstream(metrics.ServiceEuropePMC, err, "export failed")
+	}
+	data, err := encodeExport(hits, format)
+	if err != nil {
+		return Export{}, nil, err
+	}
+	stored, err := s.store.Put(ctx, artifact.PutParams{
+		Name:        params.ExportPath,
+		ContentType: exportMIMEType(format),
+		Body:        bytes.NewReader(data),
+		Size:        int64(len(data)),
+	})
+	if err != nil {
+		return Export{}, nil, fmt.Errorf("failed to store export %s: %w", params.ExportPath, err)
+	}
+	logger.Info("exported EuropePMC search", "hits", hitCount, "exported", len(hits), "location", stored.Location)
+	return Export{
+		Query:      params.query(),
+		Format:     format,
+		Path:       stored.Location,
+		URL:        stored.URL,
+		Exported:   len(hits),
+		HitCount:   hitCount,
+		NextCursor: next,
+	}, data, nil
+}
@@ -0,0 +1,62 @@
+package searchtool
+
+import (
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, baseURL string, arguments map[string]any, opts ...ToolOption) *mcp.CallToolResult {
+	t.Helper()
+	tool, err := NewSearchTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		append([]ToolOption{WithClientOptions(literaturetool.WithEuropePMCURL(baseURL))}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "literature-search", arguments)
+}
+
+func TestHandler_Pages(t *testing.T) {
+	t.Parallel()
+	baseURL := stubEuropePMC(t).URL
+	result := callTool(t, baseURL, map[string]any{"query": "dictyostelium", "page_size": 3})
+	require.False(t, result.IsError)
+	page, ok := result.StructuredContent.(Page)
+	require.True(t, ok)
+	assert.Equal(t, InitialCursor, page.Cursor)
+	assert.Len(t, page.Hits, 3)
+	assert.Equal(t, "c3", page.NextCursor)
+
+	result = callTool(t, baseURL, map[string]any{"query": "dictyostelium", "page_size": 3, "cursor": page.NextCursor})
+	require.False(t, result.IsError)
+	page, ok = result.StructuredContent.(Page)
+	require.True(t, ok)
+	assert.Len(t, page.Hits, 2)
+	assert.Empty(t, page.NextCursor)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	baseURL := stubEuropePMC(t).URL
+	for _, arguments := range []map[string]any{
+		{},
+		{"query": "  "},
+		{"query": "dictyostelium", "page_size": 0},
+		{"query": "dictyostelium", "page_size": 1000},
+		{"query": "dictyostelium", "cursor": "two words"},
+		{"query": "bad"},
+	} {
+		result := callTool(t, baseURL, arguments)
+		require.True(t, result.IsError, arguments)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
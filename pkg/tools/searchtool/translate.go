@@ -0,0 +1,36 @@
+package searchtool
+
+import (
+	"context"
+	"log/slog"
+)
+
+// Translator translates text into a language, given by its English name.
+type Translator interface {
+	Translate(ctx context.Context, text, language string) (string, error)
+}
+
+// translateAbstracts translates the abstracts of the articles of page that
+// are not in English, one at a time. An abstract the translation fails for
+// is left untranslated and counted, unless ctx is done, which stops the
+// translations.
+func translateAbstracts(ctx context.Context, translator Translator, page *Page, logger *slog.Logger) error {
+	for index := range page.Hits {
+		hit := &page.Hits[index]
+		if hit.Abstract == "" || hit.Language == "" || hit.Language == english {
+			continue
+		}
+		translation, err := translator.Translate(ctx, hit.Abstract, languageName(english))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			logger.Warn("failed to translate abstract", "id", hit.ID, "language", hit.Language, "error", err)
+			page.TranslationErrors++
+			continue
+		}
+		hit.TranslatedAbstract = translation
+		page.Translated++
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package searchtool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTranslator translates by prefixing the language, and fails for
+// texts in failing.
+type fakeTranslator struct {
+	failing string
+	texts   []string
+}
+
+// Translate implements Translator.
+func (f *fakeTranslator) Translate(_ context.Context, text, language string) (string, error) {
+	f.texts = append(f.texts, text)
+	if text == f.failing {
+		return "", errors.New("model unavailable")
+	}
+	return language + ": " + text, nil
+}
+
+// coreEuropePMC serves a core search response with a German, a French
+// and an English article, and records the queries and result types.
+func coreEuropePMC(t *testing.T, requests *[]map[string]string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*requests = append(*requests, map[string]string{
+			"query":      r.URL.Query().Get("query"),
+			"resultType": r.URL.Query().Get("resultType"),
+		})
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"hitCount": 3,
+			"resultList": map[string]any{"result": []map[string]any{
+				{
+					"id": "1", "source": "MED", "title": "Chemotaxis bei Dictyostelium.", "language": "ger",
+					"abstractText": "<h4>Hintergrund</h4>Zellen bewegen sich zu <i>cAMP</i>.",
+				},
+				{"id": "2", "source": "MED", "title": "Chimiotaxie.", "language": "fre", "abstractText": "Les cellules."},
+				{"id": "3", "source": "MED", "title": "Chemotaxis.", "language": "eng", "abstractText": "Cells move."},
+			}},
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandler_Translate(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]string
+	server := coreEuropePMC(t, &requests)
+	translator := &fakeTranslator{failing: "Les cellules."}
+	tool, err := NewSearchTool(
+		slog.New(slog.NewTextHandler(io.Discard, nil)),
+		WithClientOptions(literaturetool.WithEuropePMCURL(server.URL)),
+		WithTranslator(translator),
+	)
+	require.NoError(t, err)
+	result := tooltest.Call(t, tool.Handler, "literature-search", map[string]any{
+		"query": "dictyostelium", "language": "de", "translate": true,
+	})
+	require.False(t, result.IsError)
+
+	assert.Equal(t, []map[string]string{{"query": `(dictyostelium) AND LANG:"ger"`, "resultType": "core"}}, requests)
+	page, ok := result.StructuredContent.(Page)
+	require.True(t, ok)
+	require.Len(t, page.Hits, 3)
+	assert.Equal(t, "Hintergrund Zellen bewegen sich zu cAMP.", page.Hits[0].Abstract)
+	assert.Equal(t, "English: Hintergrund Zellen bewegen sich zu cAMP.", page.Hits[0].TranslatedAbstract)
+	assert.Empty(t, page.Hits[1].TranslatedAbstract, "a failed translation keeps the original alone")
+	assert.Equal(t, "Les cellules.", page.Hits[1].Abstract)
+	assert.Empty(t, page.Hits[2].TranslatedAbstract, "English abstracts are not translated")
+	assert.Len(t, translator.texts, 2)
+	assert.Equal(t, 1, page.Translated)
+	assert.Equal(t, 1, page.TranslationErrors)
+
+	text := result.Content[0].(mcp.TextContent).Text
+	assert.Contains(t, text, "   *Abstract, translated from German:* English: Hintergrund")
+	assert.Contains(t, text, "1 abstracts could not be translated")
+}
+
+func TestHandler_Language(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]string
+	server := coreEuropePMC(t, &requests)
+	result := callTool(t, server.URL, map[string]any{"query": "dictyostelium", "language": "French"})
+	require.False(t, result.IsError)
+	assert.Equal(t, []map[string]string{{"query": `(dictyostelium) AND LANG:"fre"`, "resultType": "lite"}}, requests)
+
+	for _, arguments := range []map[string]any{
+		{"query": "dictyostelium", "language": "Klingon"},
+		{"query": "dictyostelium", "translate": true, "export_path": "out.csv"},
+	} {
+		result := callTool(t, server.URL, arguments)
+		require.True(t, result.IsError, arguments)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, arguments)
+	}
+}
+
+func TestLanguageCode(t *testing.T) {
+	t.Parallel()
+	for name, code := range map[string]string{"ger": "ger", "de": "ger", "DEU": "ger", " German ": "ger", "zh": "chi"} {
+		got, err := languageCode(name)
+		require.NoError(t, err, name)
+		assert.Equal(t, code, got, name)
+	}
+	_, err := languageCode("Klingon")
+	require.Error(t, err)
+	assert.Equal(t, "German", languageName("ger"))
+	assert.Equal(t, "xxx", languageName("xxx"))
+}
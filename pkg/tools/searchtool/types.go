@@ -0,0 +1,171 @@
+package searchtool
+
+import (
+	"encoding/json"
+	"regexp"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/literaturetool"
+)
+
+// Hit is an article matching a search.
+type Hit struct {
+	// ID and Source identify the article in EuropePMC, e.g. a PMID with
+	// source MED or a preprint ID with source PPR.
+	ID           string `json:"id"`
+	Source       string `json:"source"`
+	PMID         string `json:"pmid,omitempty"`
+	PMCID        string `json:"pmcid,omitempty"`
+	DOI          string `json:"doi,omitempty"`
+	Title        string `json:"title"`
+	Authors      string `json:"authors,omitempty"`
+	Journal      string `json:"journal,omitempty"`
+	Year         string `json:"year,omitempty"`
+	OpenAccess   bool   `json:"open_access"`
+	CitedByCount int    `json:"cited_by_count"`
+	// Language is the MEDLINE code of the language of the article, such
+	// as eng or ger. It and the abstracts are set when translating or
+	// searching by MeSH.
+	Language string `json:"language,omitempty"`
+	// Abstract is the abstract in the language of the article.
+	Abstract string `json:"abstract,omitempty"`
+	// TranslatedAbstract is the abstract translated into English, set for
+	// articles in other languages.
+	TranslatedAbstract string `json:"translated_abstract,omitempty"`
+	// MeSH is how the article matched the descriptor of a MeSH search.
+	MeSH *MeSHMatch `json:"mesh,omitempty"`
+}
+
+// Page is one page of the results of a search.
+type Page struct {
+	Query string `json:"query"`
+	// MeSH is the descriptor the query was expanded with, if any.
+	MeSH *Descriptor `json:"mesh,omitempty"`
+	// Cursor is the cursor the page was requested with.
+	Cursor   string `json:"cursor"`
+	PageSize int    `json:"page_size"`
+	// HitCount is the number of articles matching the query.
+	HitCount int   `json:"hit_count"`
+	Hits     []Hit `json:"hits"`
+	// NextCursor requests the next page; it is empty on the last page.
+	NextCursor string `json:"next_cursor,omitempty"`
+	// Translated and TranslationErrors count the abstracts translated into
+	// English and those the translation failed for.
+	Translated        int `json:"translated,omitempty"`
+	TranslationErrors int `json:"translation_errors,omitempty"`
+}
+
+// searchResponse is the subset of a /search response used.
+type searchResponse struct {
+	HitCount       int    `json:"hitCount"`
+	NextCursorMark string `json:"nextCursorMark"`
+	ResultList     struct {
+		Result []hitResponse `json:"result"`
+	} `json:"resultList"`
+}
+
+// hitResponse is an article of a /search response; the language and the
+// abstract are only in core responses.
+type hitResponse struct {
+	ID           string     `json:"id"`
+	Source       string     `json:"source"`
+	PMID         string     `json:"pmid"`
+	PMCID        string     `json:"pmcid"`
+	DOI          string     `json:"doi"`
+	Title        string     `json:"title"`
+	AuthorString string     `json:"authorString"`
+	JournalTitle string     `json:"journalTitle"`
+	PubYear      yearString `json:"pubYear"`
+	IsOpenAccess string     `json:"isOpenAccess"`
+	CitedByCount int        `json:"citedByCount"`
+	Language     string     `json:"language"`
+	AbstractText string     `json:"abstractText"`
+	// MeshHeadingList is only in core responses.
+	MeshHeadingList struct {
+		MeshHeading []meshHeadingResponse `json:"meshHeading"`
+	} `json:"meshHeadingList"`
+}
+
+// meshHeadingResponse is a MeSH heading of an article in a core response.
+type meshHeadingResponse struct {
+	MajorTopic        string `json:"majorTopic_YN"`
+	DescriptorName    string `json:"descriptorName"`
+	MeshQualifierList struct {
+		MeshQualifier []struct {
+			QualifierName string `json:"qualifierName"`
+			MajorTopic    string `json:"majorTopic_YN"`
+		} `json:"meshQualifier"`
+	} `json:"meshQualifierList"`
+}
+
+// toMeshHeadings converts the MeSH headings of a core response.
+func toMeshHeadings(responses []meshHeadingResponse) []literaturetool.MeshHeading {
+	headings := make([]literaturetool.MeshHeading, 0, len(responses))
+	for _, response := range responses {
+		heading := literaturetool.MeshHeading{
+			MajorTopic:     response.MajorTopic == "Y",
+			DescriptorName: response.DescriptorName,
+		}
+		for _, qualifier := range response.MeshQualifierList.MeshQualifier {
+			heading.MeshQualifiers = append(heading.MeshQualifiers, literaturetool.MeshQualifier{
+				QualifierName: qualifier.QualifierName,
+				MajorTopic:    qualifier.MajorTopic == "Y",
+			})
+		}
+		headings = append(headings, heading)
+	}
+	return headings
+}
+
+// toHit converts an article of a search response.
+func toHit(response hitResponse) Hit {
+	return Hit{
+		ID:           response.ID,
+		Source:       response.Source,
+		PMID:         response.PMID,
+		PMCID:        response.PMCID,
+		DOI:          response.DOI,
+		Title:        strings.TrimSpace(response.Title),
+		Authors:      strings.TrimSpace(response.AuthorString),
+		Journal:      response.JournalTitle,
+		Year:         string(response.PubYear),
+		OpenAccess:   response.IsOpenAccess == "Y",
+		CitedByCount: response.CitedByCount,
+		Language:     response.Language,
+		Abstract:     plainText(response.AbstractText),
+	}
+}
+
+var (
+	// blockPattern matches the tags of EuropePMC abstracts that separate
+	// text, such as section headings.
+	blockPattern = regexp.MustCompile(`</?(?:h[1-6]|p|br|div)\b[^>]*>`)
+	// markupPattern matches the other tags, such as italics.
+	markupPattern = regexp.MustCompile(`<[^>]+>`)
+)
+
+// plainText removes the markup of an abstract and collapses its white
+// space.
+func plainText(text string) string {
+	text = markupPattern.ReplaceAllString(blockPattern.ReplaceAllString(text, " "), "")
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// yearString decodes a year given as a JSON string or number; EuropePMC
+// uses both, depending on the endpoint.
+type yearString string
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (y *yearString) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		*y = yearString(text)
+		return nil
+	}
+	var number json.Number
+	if err := json.Unmarshal(data, &number); err != nil {
+		return err
+	}
+	*y = yearString(number.String())
+	return nil
+}
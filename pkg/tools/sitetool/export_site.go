@@ -0,0 +1,335 @@
+package sitetool
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"path"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/idempotency"
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Outputs of a site bundle.
+const (
+	// OutputFiles writes each file of the site to the artifact store.
+	OutputFiles = "files"
+	// OutputZip writes the site as a single zip archive.
+	OutputZip = "zip"
+)
+
+const (
+	// defaultTitle is the title of a site when the call gives none.
+	defaultTitle = "Lab Reports"
+	// uploadIDsArgument names the uploads holding the documents.
+	uploadIDsArgument = "upload_ids"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// ExportSiteTool renders a collection of markdown documents as a static
+// site.
+type ExportSiteTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	store       artifact.Store
+	uploads     *upload.Store
+}
+
+// Option defines a functional option for configuring ExportSiteTool.
+type Option func(*ExportSiteTool)
+
+// WithStore sets the artifact store the site is written to. The default
+// store writes to the local filesystem.
+func WithStore(store artifact.Store) Option {
+	return func(e *ExportSiteTool) {
+		e.store = store
+	}
+}
+
+// WithUploads lets calls pass the documents as completed uploads.
+func WithUploads(store *upload.Store) Option {
+	return func(e *ExportSiteTool) {
+		e.uploads = store
+	}
+}
+
+// ExportRequest represents the parameters for exporting a site.
+type ExportRequest struct {
+	Documents []string `validate:"required,min=1,max=200,dive,required"`
+	Title     string   `validate:"required"`
+	Theme     string   `validate:"required,oneof=light dark dictybase"`
+	Output    string   `validate:"required,oneof=files zip"`
+	// Name is the directory of the files, or the base name of the zip.
+	Name string `validate:"required"`
+}
+
+// Export describes a written site bundle.
+type Export struct {
+	Title string `json:"title"`
+	Theme string `json:"theme"`
+	Pages []Page `json:"pages"`
+	// Artifacts are the files written, or the zip holding them.
+	Artifacts []artifact.Artifact `json:"artifacts"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"export-site",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewExportSiteTool(deps.Logger, WithStore(deps.Store), WithUploads(deps.Uploads))
+		},
+	)
+}
+
+// NewExportSiteTool creates a new ExportSiteTool instance.
+func NewExportSiteTool(logger *slog.Logger, opts ...Option) (*ExportSiteTool, error) {
+	tool := mcp.NewTool(
+		"export-site",
+		mcp.WithDescription(
+			"Renders a collection of markdown documents, such as lab reports, as a small static site: an index, "+
+				"navigation between the pages, a themed stylesheet and one HTML page per document, written as "+
+				"files or as a zip archive ready to publish",
+		),
+		mcp.WithTitleAnnotation("Export Static Site"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(true),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString(
+			"documents",
+			mcp.Description(
+				"Markdown documents, at most 200, separated by lines holding only "+PageSeparator+"; front "+
+					"matter may set each page's title, date and description",
+			),
+		),
+		mcp.WithString(
+			uploadIDsArgument,
+			mcp.Description("Comma-separated handles of completed uploads holding one document each, after documents"),
+		),
+		mcp.WithString(
+			"title",
+			mcp.Description("Title of the site, shown on the index and in the navigation (defaults to "+defaultTitle+")"),
+		),
+		mcp.WithString(
+			"theme",
+			mcp.Description("Color theme of the stylesheet (defaults to light)"),
+			mcp.Enum(themes...),
+		),
+		mcp.WithString(
+			"output",
+			mcp.Description(
+				"files writes index.html, the pages and style.css to a directory named after the site (default); "+
+					"zip writes them as one archive",
+			),
+			mcp.Enum(OutputFiles, OutputZip),
+		),
+		mcp.WithString(
+			"name",
+			mcp.Description("Name of the directory or zip archive (defaults to a slug of the title)"),
+		),
+		idempotency.WithKey(),
+	)
+	exportTool := &ExportSiteTool{
+		Name:        "export-site",
+		Description: "Renders markdown documents as a static site bundle",
+		Tool:        tool,
+		Logger:      logger,
+		store:       artifact.NewLocalStore(""),
+	}
+	for _, opt := range opts {
+		opt(exportTool)
+	}
+	return exportTool, nil
+}
+
+// GetName returns the name of the tool.
+func (e *ExportSiteTool) GetName() string {
+	return e.Name
+}
+
+// GetDescription returns the description of the tool.
+func (e *ExportSiteTool) GetDescription() string {
+	return e.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (e *ExportSiteTool) GetSchema() mcp.ToolInputSchema {
+	return e.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (e *ExportSiteTool) GetTool() mcp.Tool {
+	return e.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (e *ExportSiteTool) GetAnnotations() mcp.ToolAnnotation {
+	return e.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (e *ExportSiteTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Publish two lab reports as a zipped site in the dictyBase theme",
+			Arguments: map[string]any{
+				"documents": "---\ntitle: Chemotaxis Assays\ndate: 2025-06-02\n---\n\nCells aggregated after 6 hours.\n" +
+					PageSeparator + "\n" +
+					"---\ntitle: Strain Validation\ndate: 2025-06-09\n---\n\nAll 12 strains were confirmed by PCR.\n",
+				"title":  "Summer 2025 Lab Reports",
+				"theme":  ThemeDictybase,
+				"output": OutputZip,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (e *ExportSiteTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	documents, err := e.documents(request)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	params := ExportRequest{
+		Documents: documents,
+		Title:     request.GetString("title", defaultTitle),
+		Theme:     request.GetString("theme", ThemeLight),
+		Output:    request.GetString("output", OutputFiles),
+	}
+	params.Name = markdown.Chapter{Title: request.GetString("name", params.Title)}.Slug()
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	site, err := BuildSite(params.Title, params.Theme, params.Documents)
+	if err != nil {
+		return toolerror.Result(toolerror.Wrap(
+			toolerror.TypeInvalidInput,
+			"INVALID_MARKDOWN",
+			err,
+			"failed to render site",
+		)), nil
+	}
+	export, err := e.Export(ctx, site, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+
+	var message strings.Builder
+	fmt.Fprintf(&message, "Exported %q with %d pages in the %s theme:\n", site.Title, len(site.Pages), site.Theme)
+	for _, written := range export.Artifacts {
+		fmt.Fprintf(&message, "- %s: %s\n", written.Name, written.Location)
+		if written.URL != "" {
+			fmt.Fprintf(&message, "  Download URL: %s\n", written.URL)
+		}
+	}
+	return mcp.NewToolResultStructured(export, message.String()), nil
+}
+
+// Export writes the files of site to the artifact store, under the
+// directory params.Name or as the archive params.Name.zip.
+func (e *ExportSiteTool) Export(ctx context.Context, site Site, params ExportRequest) (*Export, error) {
+	logger := logging.WithRequestID(e.Logger)
+	export := &Export{Title: site.Title, Theme: site.Theme, Pages: site.Pages}
+	var files []File
+	if params.Output == OutputZip {
+		archive, err := zipFiles(params.Name, site.Files)
+		if err != nil {
+			return nil, err
+		}
+		files = []File{{Name: params.Name + ".zip", ContentType: "application/zip", Data: archive}}
+	} else {
+		files = make([]File, 0, len(site.Files))
+		for _, file := range site.Files {
+			file.Name = path.Join(params.Name, file.Name)
+			files = append(files, file)
+		}
+	}
+	for _, file := range files {
+		stored, err := e.store.Put(ctx, artifact.PutParams{
+			Name:        file.Name,
+			ContentType: file.ContentType,
+			Body:        bytes.NewReader(file.Data),
+			Size:        int64(len(file.Data)),
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to store %s: %w", file.Name, err)
+		}
+		export.Artifacts = append(export.Artifacts, *stored)
+	}
+	logger.Info("exported site", "name", params.Name, "pages", len(site.Pages), "output", params.Output)
+	return export, nil
+}
+
+// documents returns the documents passed inline, followed by those of the
+// uploads.
+func (e *ExportSiteTool) documents(request mcp.CallToolRequest) ([]string, error) {
+	documents := SplitDocuments(request.GetString("documents", ""))
+	ids := strings.Split(request.GetString(uploadIDsArgument, ""), ",")
+	for _, id := range ids {
+		if id = strings.TrimSpace(id); id == "" {
+			continue
+		}
+		if e.uploads == nil {
+			return nil, toolerror.New(
+				toolerror.TypeConfiguration,
+				"UPLOADS_UNAVAILABLE",
+				"uploads are not enabled on this server",
+			)
+		}
+		text, err := e.uploads.Text(id)
+		switch {
+		case errors.Is(err, upload.ErrNotFound):
+			return nil, toolerror.Wrap(toolerror.TypeNotFound, "UPLOAD_NOT_FOUND", err, "invalid "+uploadIDsArgument)
+		case err != nil:
+			return nil, toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_UPLOAD", err, "invalid "+uploadIDsArgument)
+		}
+		documents = append(documents, text)
+	}
+	if len(documents) == 0 {
+		return nil, toolerror.New(
+			toolerror.TypeInvalidInput,
+			"MISSING_PARAMETER",
+			"missing required parameter: documents or "+uploadIDsArgument,
+		)
+	}
+	return documents, nil
+}
+
+// zipFiles archives files in the directory dir of a zip.
+func zipFiles(dir string, files []File) ([]byte, error) {
+	var buf bytes.Buffer
+	archive := zip.NewWriter(&buf)
+	for _, file := range files {
+		writer, err := archive.Create(path.Join(dir, file.Name))
+		if err != nil {
+			return nil, fmt.Errorf("error adding %s to the archive: %w", file.Name, err)
+		}
+		if _, err := writer.Write(file.Data); err != nil {
+			return nil, fmt.Errorf("error writing %s to the archive: %w", file.Name, err)
+		}
+	}
+	if err := archive.Close(); err != nil {
+		return nil, fmt.Errorf("error closing the archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
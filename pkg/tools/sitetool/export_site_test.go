@@ -0,0 +1,120 @@
+package sitetool
+
+import (
+	"archive/zip"
+	"bytes"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/artifact"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func callTool(t *testing.T, dir string, uploads *upload.Store, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	tool, err := NewExportSiteTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		WithStore(artifact.NewLocalStore(dir)),
+		WithUploads(uploads),
+	)
+	require.NoError(t, err)
+	return tooltest.Call(t, tool.Handler, "export-site", arguments)
+}
+
+func TestNewExportSiteTool(t *testing.T) {
+	t.Parallel()
+	tool, err := NewExportSiteTool(slog.New(slog.NewTextHandler(os.Stderr, nil)))
+	require.NoError(t, err)
+	assert.Equal(t, "export-site", tool.GetName())
+	annotations := tool.GetAnnotations()
+	assert.False(t, *annotations.ReadOnlyHint)
+	assert.True(t, *annotations.IdempotentHint)
+}
+
+func TestHandler_Files(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	uploads := upload.NewStore()
+	info, err := uploads.Begin(upload.BeginParams{ContentType: "text/markdown"})
+	require.NoError(t, err)
+	_, err = uploads.Append(upload.AppendParams{ID: info.ID, Data: []byte("# Strain Validation\n")})
+	require.NoError(t, err)
+	_, err = uploads.Complete(info.ID, "")
+	require.NoError(t, err)
+
+	result := callTool(t, dir, uploads, map[string]any{
+		"documents":  "# Chemotaxis Assays\n\nCells aggregated.\n",
+		"upload_ids": info.ID,
+		"title":      "Summer Reports",
+	})
+	require.False(t, result.IsError, "%+v", result)
+	export, ok := result.StructuredContent.(*Export)
+	require.True(t, ok)
+	assert.Equal(t, ThemeLight, export.Theme)
+	require.Len(t, export.Pages, 2)
+	assert.Equal(t, "strain-validation.html", export.Pages[1].File)
+	require.Len(t, export.Artifacts, 4)
+	for _, name := range []string{"index.html", "chemotaxis-assays.html", "strain-validation.html", "style.css"} {
+		assert.FileExists(t, filepath.Join(dir, "summer-reports", name))
+	}
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, `Exported "Summer Reports" with 2 pages in the light theme:`)
+	assert.Contains(t, text.Text, "- summer-reports/index.html: "+filepath.Join(dir, "summer-reports", "index.html"))
+
+	result = callTool(t, dir, uploads, map[string]any{"upload_ids": "upl_unknown"})
+	assert.Equal(t, "UPLOAD_NOT_FOUND", tooltest.ErrorCode(t, result))
+}
+
+func TestHandler_Zip(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	result := callTool(t, dir, nil, map[string]any{
+		"documents": "# One\n" + PageSeparator + "\n# Two\n",
+		"output":    OutputZip,
+		"name":      "Reports 2025",
+		"theme":     ThemeDictybase,
+	})
+	require.False(t, result.IsError, "%+v", result)
+	export, ok := result.StructuredContent.(*Export)
+	require.True(t, ok)
+	require.Len(t, export.Artifacts, 1)
+	assert.Equal(t, "reports-2025.zip", export.Artifacts[0].Name)
+
+	data, err := os.ReadFile(filepath.Join(dir, "reports-2025.zip"))
+	require.NoError(t, err)
+	archive, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	names := make([]string, 0, len(archive.File))
+	for _, file := range archive.File {
+		names = append(names, file.Name)
+	}
+	assert.Equal(t, []string{
+		"reports-2025/index.html", "reports-2025/one.html", "reports-2025/two.html", "reports-2025/style.css",
+	}, names)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	for _, tc := range []struct {
+		arguments map[string]any
+		code      string
+	}{
+		{arguments: map[string]any{}, code: "MISSING_PARAMETER"},
+		{arguments: map[string]any{"upload_ids": "upl_1"}, code: "UPLOADS_UNAVAILABLE"},
+		{arguments: map[string]any{"documents": "# One\n", "theme": "neon"}, code: "INVALID_INPUT"},
+	} {
+		result := callTool(t, t.TempDir(), nil, tc.arguments)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, tc.code, toolErr.Code, tc.arguments)
+	}
+}
@@ -0,0 +1,166 @@
+package sitetool
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/markdown"
+)
+
+// PageSeparator is the line separating documents passed inline in one
+// string.
+const PageSeparator = "<!-- page -->"
+
+// Content types of the files of a site.
+const (
+	contentTypeHTML = "text/html"
+	contentTypeCSS  = "text/css"
+)
+
+// Page is one rendered document of a site.
+type Page struct {
+	Title string `json:"title"`
+	// File is the name of the page within the bundle.
+	File        string `json:"file"`
+	Date        string `json:"date,omitempty"`
+	Description string `json:"description,omitempty"`
+	// Body is the HTML of the document.
+	Body template.HTML `json:"-"`
+}
+
+// File is one file of a site bundle.
+type File struct {
+	Name        string
+	ContentType string
+	Data        []byte
+}
+
+// Site is a rendered site bundle.
+type Site struct {
+	Title string
+	Theme string
+	Pages []Page
+	// Files are the index, the pages and the stylesheet, in that order.
+	Files []File
+}
+
+// SplitDocuments splits documents passed inline at PageSeparator lines,
+// dropping blank ones.
+func SplitDocuments(text string) []string {
+	var (
+		documents []string
+		current   strings.Builder
+	)
+	flush := func() {
+		if document := strings.TrimSpace(current.String()); document != "" {
+			documents = append(documents, document+"\n")
+		}
+		current.Reset()
+	}
+	for _, line := range strings.SplitAfter(strings.ReplaceAll(text, "\r\n", "\n"), "\n") {
+		if strings.TrimSpace(line) == PageSeparator {
+			flush()
+			continue
+		}
+		current.WriteString(line)
+	}
+	flush()
+	return documents
+}
+
+// BuildSite renders documents as the pages of a site titled title, in the
+// given order, with an index, navigation between the pages and the
+// stylesheet of theme. Page file names are slugs of the page titles.
+func BuildSite(title, theme string, documents []string) (Site, error) {
+	css, ok := stylesheet(theme)
+	if !ok {
+		return Site{}, fmt.Errorf("unknown theme %q", theme)
+	}
+	site := Site{Title: title, Theme: theme, Pages: make([]Page, 0, len(documents))}
+	used := map[string]bool{"index": true, "style": true}
+	for i, document := range documents {
+		page, err := renderPage([]byte(document), i+1)
+		if err != nil {
+			return Site{}, fmt.Errorf("document %d: %w", i+1, err)
+		}
+		page.File = uniqueName(markdown.Chapter{Title: page.Title}.Slug(), used) + ".html"
+		site.Pages = append(site.Pages, page)
+	}
+
+	var index bytes.Buffer
+	err := siteTemplates.ExecuteTemplate(&index, "index", map[string]any{"Site": site, "Current": "index.html"})
+	if err != nil {
+		return Site{}, fmt.Errorf("failed to render index: %w", err)
+	}
+	site.Files = append(site.Files, File{Name: "index.html", ContentType: contentTypeHTML, Data: index.Bytes()})
+	for i, page := range site.Pages {
+		data := map[string]any{"Site": site, "Page": page, "Current": page.File}
+		if i > 0 {
+			data["Previous"] = site.Pages[i-1]
+		}
+		if i+1 < len(site.Pages) {
+			data["Next"] = site.Pages[i+1]
+		}
+		var rendered bytes.Buffer
+		if err := siteTemplates.ExecuteTemplate(&rendered, "page", data); err != nil {
+			return Site{}, fmt.Errorf("failed to render %s: %w", page.File, err)
+		}
+		site.Files = append(site.Files, File{Name: page.File, ContentType: contentTypeHTML, Data: rendered.Bytes()})
+	}
+	site.Files = append(site.Files, File{Name: "style.css", ContentType: contentTypeCSS, Data: []byte(css)})
+	return site, nil
+}
+
+// renderPage renders the number-th document. Its title is the title of
+// its front matter, else its first top-level heading, else "Page" and
+// the number.
+func renderPage(source []byte, number int) (Page, error) {
+	parser := markdown.NewParser()
+	body, err := parser.Parse(source)
+	if err != nil {
+		return Page{}, fmt.Errorf("failed to render markdown: %w", err)
+	}
+	metadata := parser.GetMetadata()
+	page := Page{
+		Title:       frontMatterString(metadata["title"]),
+		Date:        frontMatterString(metadata["date"]),
+		Description: frontMatterString(metadata["description"]),
+		//nolint:gosec // the HTML is rendered by goldmark, which escapes raw HTML
+		Body: template.HTML(body),
+	}
+	if page.Title == "" {
+		page.Title = markdown.SplitChapters(source, 1)[0].Title
+	}
+	if page.Title == "" {
+		page.Title = fmt.Sprintf("Page %d", number)
+	}
+	return page, nil
+}
+
+// frontMatterString converts a scalar front matter value to a string.
+func frontMatterString(value any) string {
+	switch value := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return strings.TrimSpace(value)
+	case time.Time:
+		return value.Format(time.DateOnly)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+// uniqueName returns name, or name with the first free number appended
+// when it is taken, and marks the result as taken.
+func uniqueName(name string, used map[string]bool) string {
+	unique := name
+	for n := 2; used[unique]; n++ {
+		unique = fmt.Sprintf("%s-%d", name, n)
+	}
+	used[unique] = true
+	return unique
+}
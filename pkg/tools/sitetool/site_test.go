@@ -0,0 +1,56 @@
+package sitetool
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitDocuments(t *testing.T) {
+	t.Parallel()
+	documents := SplitDocuments("# One\r\n\r\ntext\r\n" + PageSeparator + "\n\n  " + PageSeparator + "  \n# Two\n")
+	assert.Equal(t, []string{"# One\n\ntext\n", "# Two\n"}, documents)
+	assert.Empty(t, SplitDocuments("\n"+PageSeparator+"\n"))
+}
+
+func TestBuildSite(t *testing.T) {
+	t.Parallel()
+	site, err := BuildSite("Lab <Reports>", ThemeDark, []string{
+		"---\ntitle: Chemotaxis Assays\ndate: 2025-06-02\ndescription: Aggregation timing\n---\n\nCells aggregated.\n",
+		"Intro text\n\n# Strain Validation\n\nAll strains confirmed.\n",
+		"No heading at all.\n",
+		"# Index\n",
+		"# Chemotaxis Assays\n\nA second run.\n",
+	})
+	require.NoError(t, err)
+	files := make([]string, 0, len(site.Files))
+	for _, file := range site.Files {
+		files = append(files, file.Name)
+	}
+	assert.Equal(t, []string{
+		"index.html", "chemotaxis-assays.html", "strain-validation.html", "page-3.html", "index-2.html",
+		"chemotaxis-assays-2.html", "style.css",
+	}, files)
+	assert.Equal(t, "2025-06-02", site.Pages[0].Date)
+	assert.Equal(t, "Page 3", site.Pages[2].Title)
+
+	index := string(site.Files[0].Data)
+	assert.Contains(t, index, "<h1>Lab &lt;Reports&gt;</h1>")
+	assert.Contains(t, index, `<li><a href="chemotaxis-assays.html">Chemotaxis Assays</a> <span class="meta">2025-06-02</span>`+
+		`<br><span class="meta">Aggregation timing</span></li>`)
+
+	page := string(site.Files[2].Data)
+	assert.Contains(t, page, "<title>Strain Validation · Lab &lt;Reports&gt;</title>")
+	assert.Contains(t, page, `<li><a href="strain-validation.html" aria-current="page">Strain Validation</a></li>`)
+	assert.Contains(t, page, "<p>All strains confirmed.</p>")
+	assert.Contains(t, page, `<a rel="prev" href="chemotaxis-assays.html">← Chemotaxis Assays</a>`)
+	assert.Contains(t, page, `<a rel="next" href="page-3.html">Page 3 →</a>`)
+	assert.NotContains(t, string(site.Files[1].Data), `rel="prev"`, "the first page has no previous page")
+
+	assert.Contains(t, string(site.Files[6].Data), "--background: #0d1117;")
+	assert.Equal(t, "text/css", site.Files[6].ContentType)
+
+	_, err = BuildSite("Lab Reports", "neon", []string{"# One\n"})
+	assert.ErrorContains(t, err, `unknown theme "neon"`)
+}
@@ -0,0 +1,189 @@
+package sitetool
+
+import (
+	"fmt"
+	"html/template"
+	"strings"
+)
+
+// Themes of a site.
+const (
+	ThemeLight     = "light"
+	ThemeDark      = "dark"
+	ThemeDictybase = "dictybase"
+)
+
+// themes lists the theme names in the order they are offered.
+var themes = []string{ThemeLight, ThemeDark, ThemeDictybase}
+
+// palette holds the colors a theme gives the stylesheet.
+type palette struct {
+	Background string
+	Text       string
+	Muted      string
+	Accent     string
+	Sidebar    string
+	Border     string
+	Code       string
+}
+
+// palettes are the colors of each theme. Text and links keep a contrast
+// of at least 4.5:1 against their backgrounds.
+var palettes = map[string]palette{
+	ThemeLight: {
+		Background: "#ffffff",
+		Text:       "#1f2328",
+		Muted:      "#59636e",
+		Accent:     "#0969da",
+		Sidebar:    "#f6f8fa",
+		Border:     "#d1d9e0",
+		Code:       "#f6f8fa",
+	},
+	ThemeDark: {
+		Background: "#0d1117",
+		Text:       "#e6edf3",
+		Muted:      "#9198a1",
+		Accent:     "#4493f8",
+		Sidebar:    "#151b23",
+		Border:     "#3d444d",
+		Code:       "#151b23",
+	},
+	ThemeDictybase: {
+		Background: "#fffdf7",
+		Text:       "#2b2118",
+		Muted:      "#6b5a48",
+		Accent:     "#155a8a",
+		Sidebar:    "#f3ecdc",
+		Border:     "#d8c9a8",
+		Code:       "#f3ecdc",
+	},
+}
+
+// baseStylesheet lays out the site with the colors of the theme, which
+// precede it as custom properties.
+const baseStylesheet = `
+* { box-sizing: border-box; }
+body {
+  margin: 0;
+  display: flex;
+  min-height: 100vh;
+  background: var(--background);
+  color: var(--text);
+  font: 16px/1.6 -apple-system, "Segoe UI", Helvetica, Arial, sans-serif;
+}
+a { color: var(--accent); }
+.site-nav {
+  flex: 0 0 16rem;
+  padding: 1.5rem 1rem;
+  background: var(--sidebar);
+  border-right: 1px solid var(--border);
+}
+.site-nav .site-title { display: block; margin-bottom: 1rem; font-weight: 600; font-size: 1.1rem; }
+.site-nav ul { list-style: none; margin: 0; padding: 0; }
+.site-nav li { margin: 0.25rem 0; }
+.site-nav a { text-decoration: none; }
+.site-nav a[aria-current="page"] { color: var(--text); font-weight: 600; }
+main { flex: 1; max-width: 50rem; padding: 1.5rem 2rem; }
+.page-list { list-style: none; padding: 0; }
+.page-list li { margin: 0 0 1rem; }
+.page-list .meta, .meta { color: var(--muted); font-size: 0.9rem; }
+pre, code { background: var(--code); border-radius: 4px; }
+pre { padding: 0.75rem; overflow-x: auto; }
+code { padding: 0.1rem 0.3rem; }
+pre code { padding: 0; }
+table { border-collapse: collapse; }
+th, td { border: 1px solid var(--border); padding: 0.3rem 0.6rem; }
+img { max-width: 100%; }
+.pager {
+  display: flex;
+  justify-content: space-between;
+  margin-top: 2rem;
+  padding-top: 1rem;
+  border-top: 1px solid var(--border);
+}
+@media (max-width: 48rem) {
+  body { display: block; }
+  .site-nav { border-right: none; border-bottom: 1px solid var(--border); }
+}
+`
+
+// stylesheet returns the CSS of theme, and false for an unknown theme.
+func stylesheet(theme string) (string, bool) {
+	colors, ok := palettes[theme]
+	if !ok {
+		return "", false
+	}
+	var builder strings.Builder
+	builder.WriteString(":root {\n")
+	fmt.Fprintf(&builder, "  --background: %s;\n", colors.Background)
+	fmt.Fprintf(&builder, "  --text: %s;\n", colors.Text)
+	fmt.Fprintf(&builder, "  --muted: %s;\n", colors.Muted)
+	fmt.Fprintf(&builder, "  --accent: %s;\n", colors.Accent)
+	fmt.Fprintf(&builder, "  --sidebar: %s;\n", colors.Sidebar)
+	fmt.Fprintf(&builder, "  --border: %s;\n", colors.Border)
+	fmt.Fprintf(&builder, "  --code: %s;\n", colors.Code)
+	builder.WriteString("}\n")
+	builder.WriteString(baseStylesheet)
+	return builder.String(), true
+}
+
+// siteTemplates renders the index and the pages of a site, which share
+// the navigation.
+var siteTemplates = template.Must(template.New("site").Parse(`
+{{- define "head" -}}
+<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.}}</title>
+<link rel="stylesheet" href="style.css">
+</head>
+{{- end}}
+
+{{- define "nav"}}
+<nav class="site-nav">
+<a class="site-title" href="index.html">{{.Site.Title}}</a>
+<ul>
+{{- range .Site.Pages}}
+<li><a href="{{.File}}"{{if eq .File $.Current}} aria-current="page"{{end}}>{{.Title}}</a></li>
+{{- end}}
+</ul>
+</nav>
+{{- end}}
+
+{{- define "index" -}}
+{{template "head" .Site.Title}}
+<body>
+{{- template "nav" .}}
+<main>
+<h1>{{.Site.Title}}</h1>
+<ul class="page-list">
+{{- range .Site.Pages}}
+<li><a href="{{.File}}">{{.Title}}</a>
+{{- if .Date}} <span class="meta">{{.Date}}</span>{{end}}
+{{- if .Description}}<br><span class="meta">{{.Description}}</span>{{end}}</li>
+{{- end}}
+</ul>
+</main>
+</body>
+</html>
+{{end}}
+
+{{- define "page" -}}
+{{template "head" (printf "%s · %s" .Page.Title .Site.Title)}}
+<body>
+{{- template "nav" .}}
+<main>
+<article>
+{{.Page.Body}}
+</article>
+<nav class="pager">
+{{- with .Previous}}<a rel="prev" href="{{.File}}">← {{.Title}}</a>{{else}}<span></span>{{end}}
+{{- with .Next}}<a rel="next" href="{{.File}}">{{.Title}} →</a>{{end -}}
+</nav>
+</main>
+</body>
+</html>
+{{end}}
+`))
@@ -0,0 +1,137 @@
+package statustool
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/status"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// StatusTool reports the server's uptime, registered tools, configured
+// limits and provider reachability, so operators and clients can verify
+// the server is healthy, and on request the requests sent to each
+// provider, so deployments can show they keep to the providers' usage
+// policies.
+type StatusTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	monitor     *status.Monitor
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"server-status",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewStatusTool(deps.Logger, deps.Status)
+		},
+	)
+}
+
+// NewStatusTool creates a new StatusTool instance reporting from monitor.
+func NewStatusTool(logger *slog.Logger, monitor *status.Monitor) (*StatusTool, error) {
+	if monitor == nil {
+		return nil, errors.New("server-status tool requires a status monitor")
+	}
+	tool := mcp.NewTool(
+		"server-status",
+		mcp.WithDescription(
+			"Reports server uptime, registered tools, configured limits and whether "+
+				"EuropePMC, PubMed and OpenRouter can be reached",
+		),
+		mcp.WithTitleAnnotation("Server Status"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithBoolean(
+			"check_providers",
+			mcp.Description("Probe the upstream providers, defaults to true; false answers immediately"),
+		),
+		mcp.WithBoolean(
+			"compliance",
+			mcp.Description(
+				"Add the compliance report: the requests sent to each provider since the server started, "+
+					"their peak and recent rates and daily volumes, checked against the provider's published "+
+					"or configured cap. Defaults to false",
+			),
+		),
+	)
+	return &StatusTool{
+		Name:        "server-status",
+		Description: "Reports the health of the server and its upstream providers",
+		Tool:        tool,
+		Logger:      logger,
+		monitor:     monitor,
+	}, nil
+}
+
+// GetName returns the name of the tool.
+func (s *StatusTool) GetName() string {
+	return s.Name
+}
+
+// GetDescription returns the description of the tool.
+func (s *StatusTool) GetDescription() string {
+	return s.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (s *StatusTool) GetSchema() mcp.ToolInputSchema {
+	return s.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (s *StatusTool) GetTool() mcp.Tool {
+	return s.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (s *StatusTool) GetAnnotations() mcp.ToolAnnotation {
+	return s.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (s *StatusTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Report the server's health and check that the outbound providers answer",
+			Arguments: map[string]any{
+				"check_providers": true,
+			},
+		},
+		{
+			Description: "Show how many requests went to PubMed and Europe PMC and whether they kept to NCBI's cap",
+			Arguments: map[string]any{
+				"check_providers": false,
+				"compliance":      true,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests. An
+// unreachable provider is part of the report rather than an error result.
+func (s *StatusTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	report := s.monitor.Report(ctx, request.GetBool("check_providers", true))
+	if request.GetBool("compliance", false) {
+		report.Compliance = s.monitor.Compliance()
+	}
+	encoded, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to encode status report: %w", err)), nil
+	}
+	return mcp.NewToolResultStructured(report, string(encoded)), nil
+}
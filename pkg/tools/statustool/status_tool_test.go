@@ -0,0 +1,78 @@
+package statustool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+	"github.com/dictybase/dcr-mcp/pkg/status"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewStatusTool_RequiresMonitor(t *testing.T) {
+	t.Parallel()
+	_, err := NewStatusTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), nil)
+	assert.Error(t, err)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	monitor := status.NewMonitor(
+		status.WithVersion("1.0.0"),
+		status.WithLimits(status.Limits{MaxHeavyTools: 2}),
+	)
+	monitor.SetTools([]string{"server-status", "markdown"})
+	tool, err := NewStatusTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), monitor)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "server-status"
+	request.Params.Arguments = map[string]any{"check_providers": false}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	require.False(t, result.IsError)
+
+	report, ok := result.StructuredContent.(status.Report)
+	require.True(t, ok)
+	assert.Equal(t, status.StatusOK, report.Status)
+	assert.Equal(t, []string{"markdown", "server-status"}, report.Tools)
+	assert.Empty(t, report.Providers)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, `"max_heavy_tools": 2`)
+}
+
+func TestHandler_Compliance(t *testing.T) {
+	t.Parallel()
+	compliant := true
+	monitor := status.NewMonitor(status.WithUsage(func() []ratelimit.ProviderUsage {
+		return []ratelimit.ProviderUsage{{Provider: "pubmed", Requests: 12, PeakPerSecond: 3, Compliant: &compliant}}
+	}))
+	tool, err := NewStatusTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), monitor)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Name = "server-status"
+	request.Params.Arguments = map[string]any{"check_providers": false}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	report, ok := result.StructuredContent.(status.Report)
+	require.True(t, ok)
+	assert.Empty(t, report.Compliance, "the compliance report is added on request")
+
+	request.Params.Arguments = map[string]any{"check_providers": false, "compliance": true}
+	result, err = tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	report, ok = result.StructuredContent.(status.Report)
+	require.True(t, ok)
+	require.Len(t, report.Compliance, 1)
+	assert.Equal(t, "pubmed", report.Compliance[0].Provider)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, `"peak_per_second": 3`)
+	assert.Contains(t, text.Text, `"compliant": true`)
+}
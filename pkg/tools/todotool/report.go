@@ -0,0 +1,171 @@
+package todotool
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+)
+
+// Scan is the content of one marker scan.
+type Scan struct {
+	RepoURL string
+	Branch  string
+	Commit  string
+	When    time.Time
+	Markers []Marker
+	// Blamed is whether authors were looked up; BlamedFiles is the number
+	// of files they were looked up in, which may be capped.
+	Blamed      bool
+	BlamedFiles int
+}
+
+// AreaMarkers are the markers of one area.
+type AreaMarkers struct {
+	Area    string
+	Markers []Marker
+}
+
+// AuthorCount is the number of markers of each kind last touched by one
+// author.
+type AuthorCount struct {
+	Author string
+	Counts map[string]int
+	Total  int
+}
+
+// Counts returns the number of markers of each kind.
+func (s Scan) Counts() map[string]int {
+	counts := make(map[string]int)
+	for _, marker := range s.Markers {
+		counts[marker.Kind]++
+	}
+	return counts
+}
+
+// Files returns the number of files with markers.
+func (s Scan) Files() int {
+	var files []string
+	for _, marker := range s.Markers {
+		if !slices.Contains(files, marker.File) {
+			files = append(files, marker.File)
+		}
+	}
+	return len(files)
+}
+
+// Areas groups the markers by area, the area with the most markers first.
+// Within an area, FIXMEs come first, then HACKs and TODOs.
+func (s Scan) Areas() []AreaMarkers {
+	var areas []AreaMarkers
+	for _, marker := range s.Markers {
+		index := slices.IndexFunc(areas, func(area AreaMarkers) bool {
+			return area.Area == marker.Area()
+		})
+		if index < 0 {
+			areas = append(areas, AreaMarkers{Area: marker.Area()})
+			index = len(areas) - 1
+		}
+		areas[index].Markers = append(areas[index].Markers, marker)
+	}
+	for _, area := range areas {
+		slices.SortStableFunc(area.Markers, func(a, b Marker) int {
+			return slices.Index(kindOrder, a.Kind) - slices.Index(kindOrder, b.Kind)
+		})
+	}
+	slices.SortFunc(areas, func(a, b AreaMarkers) int {
+		return cmp.Or(len(b.Markers)-len(a.Markers), cmp.Compare(a.Area, b.Area))
+	})
+	return areas
+}
+
+// Authors counts the markers by the author who last touched them, the
+// author with the most first. Markers not blamed are left out.
+func (s Scan) Authors() []AuthorCount {
+	var authors []AuthorCount
+	for _, marker := range s.Markers {
+		if marker.Author == "" {
+			continue
+		}
+		index := slices.IndexFunc(authors, func(author AuthorCount) bool {
+			return author.Author == marker.Author
+		})
+		if index < 0 {
+			authors = append(authors, AuthorCount{Author: marker.Author, Counts: make(map[string]int)})
+			index = len(authors) - 1
+		}
+		authors[index].Counts[marker.Kind]++
+		authors[index].Total++
+	}
+	slices.SortFunc(authors, func(a, b AuthorCount) int {
+		return cmp.Or(b.Total-a.Total, cmp.Compare(a.Author, b.Author))
+	})
+	return authors
+}
+
+// RenderMarkdown renders the scan as a markdown document.
+func RenderMarkdown(scan Scan) string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "# TODO Scan: %s\n\n", worksummary.RepoName(scan.RepoURL))
+	fmt.Fprintf(&builder, "**Repository:** %s (branch `%s`, commit `%s` of %s)\n",
+		scan.RepoURL, scan.Branch, worksummary.ShortHash(scan.Commit), scan.When.Format(time.DateOnly))
+
+	builder.WriteString("\n## Overview\n\n")
+	if len(scan.Markers) == 0 {
+		builder.WriteString("No TODO, FIXME or HACK comments found.\n")
+		return builder.String()
+	}
+	counts := scan.Counts()
+	parts := make([]string, 0, len(kindOrder))
+	for _, kind := range kindOrder {
+		if counts[kind] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[kind], kind))
+		}
+	}
+	fmt.Fprintf(&builder, "%d markers in %d files: %s.", len(scan.Markers), scan.Files(), strings.Join(parts, ", "))
+	if scan.Blamed && scan.BlamedFiles < scan.Files() {
+		fmt.Fprintf(&builder, " Authors were looked up in the first %d files only.", scan.BlamedFiles)
+	}
+	builder.WriteString("\n")
+
+	if authors := scan.Authors(); len(authors) > 0 {
+		builder.WriteString("\n## By Author\n\n| Author | FIXME | HACK | TODO | Total |\n|---|---|---|---|---|\n")
+		for _, author := range authors {
+			fmt.Fprintf(&builder, "| %s | %d | %d | %d | %d |\n", author.Author,
+				author.Counts[KindFIXME], author.Counts[KindHACK], author.Counts[KindTODO], author.Total)
+		}
+	}
+	for _, area := range scan.Areas() {
+		fmt.Fprintf(&builder, "\n## %s\n\n", area.Area)
+		if scan.Blamed {
+			builder.WriteString("| Marker | Location | Text | Author | Date |\n|---|---|---|---|---|\n")
+		} else {
+			builder.WriteString("| Marker | Location | Text |\n|---|---|---|\n")
+		}
+		for _, marker := range area.Markers {
+			fmt.Fprintf(&builder, "| %s | `%s:%d` | %s |", marker.Kind, marker.File, marker.Line, markerText(marker))
+			if scan.Blamed {
+				date := ""
+				if !marker.Date.IsZero() {
+					date = marker.Date.Format(time.DateOnly)
+				}
+				fmt.Fprintf(&builder, " %s | %s |", marker.Author, date)
+			}
+			builder.WriteString("\n")
+		}
+	}
+	return builder.String()
+}
+
+// markerText renders the text of a marker with its owner, keeping it from
+// breaking the table row.
+func markerText(marker Marker) string {
+	text := strings.ReplaceAll(marker.Text, "|", `\|`)
+	if marker.Owner != "" {
+		text = strings.TrimSpace(fmt.Sprintf("%s (%s)", text, marker.Owner))
+	}
+	return text
+}
@@ -0,0 +1,71 @@
+package todotool
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var testScan = Scan{
+	RepoURL: "https://github.com/dictybase/dcr-mcp.git",
+	Branch:  "develop",
+	Commit:  "9e8d7c6aaaa",
+	When:    time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+	Markers: []Marker{
+		{Kind: KindTODO, File: "cmd/server/main.go", Line: 10, Text: "read | parse flags", Author: "Jane Doe", Date: testTime},
+		{Kind: KindFIXME, File: "cmd/server/main.go", Line: 20, Text: "leak", Owner: "bob", Author: "John Roe", Date: testTime},
+		{Kind: KindHACK, File: "pkg/cache.go", Line: 3, Text: "retry twice", Author: "Jane Doe", Date: testTime},
+		{Kind: KindTODO, File: "Makefile", Line: 1, Text: "lint"},
+	},
+	Blamed:      true,
+	BlamedFiles: 2,
+}
+
+func TestAreas(t *testing.T) {
+	t.Parallel()
+	areas := testScan.Areas()
+	assert.Len(t, areas, 3)
+	assert.Equal(t, "cmd/server", areas[0].Area)
+	assert.Equal(t, []string{KindFIXME, KindTODO}, []string{areas[0].Markers[0].Kind, areas[0].Markers[1].Kind})
+	assert.Equal(t, "(root)", areas[1].Area)
+	assert.Equal(t, "pkg", areas[2].Area)
+}
+
+func TestAuthors(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, []AuthorCount{
+		{Author: "Jane Doe", Counts: map[string]int{KindTODO: 1, KindHACK: 1}, Total: 2},
+		{Author: "John Roe", Counts: map[string]int{KindFIXME: 1}, Total: 1},
+	}, testScan.Authors())
+}
+
+func TestRenderMarkdown(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "# TODO Scan: dcr-mcp\n\n"+
+		"**Repository:** https://github.com/dictybase/dcr-mcp.git (branch `develop`, commit `9e8d7c6` of 2025-06-28)\n\n"+
+		"## Overview\n\n"+
+		"4 markers in 3 files: 1 FIXME, 1 HACK, 2 TODO. Authors were looked up in the first 2 files only.\n\n"+
+		"## By Author\n\n| Author | FIXME | HACK | TODO | Total |\n|---|---|---|---|---|\n"+
+		"| Jane Doe | 0 | 1 | 1 | 2 |\n"+
+		"| John Roe | 1 | 0 | 0 | 1 |\n\n"+
+		"## cmd/server\n\n| Marker | Location | Text | Author | Date |\n|---|---|---|---|---|\n"+
+		"| FIXME | `cmd/server/main.go:20` | leak (bob) | John Roe | 2025-06-02 |\n"+
+		"| TODO | `cmd/server/main.go:10` | read \\| parse flags | Jane Doe | 2025-06-02 |\n\n"+
+		"## (root)\n\n| Marker | Location | Text | Author | Date |\n|---|---|---|---|---|\n"+
+		"| TODO | `Makefile:1` | lint |  |  |\n\n"+
+		"## pkg\n\n| Marker | Location | Text | Author | Date |\n|---|---|---|---|---|\n"+
+		"| HACK | `pkg/cache.go:3` | retry twice | Jane Doe | 2025-06-02 |\n", RenderMarkdown(testScan))
+}
+
+func TestRenderMarkdown_Empty(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "# TODO Scan: empty\n\n"+
+		"**Repository:** https://github.com/dictybase/empty (branch `main`, commit `abc` of 2025-06-28)\n\n"+
+		"## Overview\n\nNo TODO, FIXME or HACK comments found.\n", RenderMarkdown(Scan{
+		RepoURL: "https://github.com/dictybase/empty",
+		Branch:  "main",
+		Commit:  "abc",
+		When:    time.Date(2025, 6, 28, 0, 0, 0, 0, time.UTC),
+	}))
+}
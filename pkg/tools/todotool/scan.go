@@ -0,0 +1,161 @@
+package todotool
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Kinds of markers, in the order they are reported.
+const (
+	KindFIXME = "FIXME"
+	KindHACK  = "HACK"
+	KindTODO  = "TODO"
+)
+
+// kindOrder is the order of the kinds in reports: what is broken first.
+var kindOrder = []string{KindFIXME, KindHACK, KindTODO}
+
+// markerRegex matches a marker after a line comment or block comment
+// leader, with an optional owner in parentheses, e.g. "// TODO(jane): x".
+// Markers in prose or identifiers are not matched.
+var markerRegex = regexp.MustCompile(
+	`(?://|#|--|/\*|^\s*\*|<!--|;|%)\s*(TODO|FIXME|HACK)\b(?:\(([^)]*)\))?[:\s-]*(.*)$`,
+)
+
+// skippedDirs are directories of vendored code, whose markers are not the
+// repository's own.
+var skippedDirs = []string{"vendor", "node_modules"}
+
+// maxFileSize is the size above which files are not scanned; they are
+// usually generated or minified.
+const maxFileSize = 1 << 20
+
+// Marker is a TODO, FIXME or HACK comment.
+type Marker struct {
+	Kind string
+	File string
+	Line int
+	Text string
+	// Owner is the name in parentheses after the marker, if any.
+	Owner string
+	// Author and Date are the author and date of the commit that last
+	// changed the line, when blame was run.
+	Author string
+	Date   time.Time
+}
+
+// Area is the area of a marker, as reported by the onboarding brief.
+func (m Marker) Area() string {
+	return worksummary.AreaOf(m.File)
+}
+
+// FindMarkers returns the markers in the files of tree, ordered by file
+// and line.
+func FindMarkers(tree *object.Tree) ([]Marker, error) {
+	var markers []Marker
+	err := tree.Files().ForEach(func(file *object.File) error {
+		if isSkipped(file.Name) || file.Size > maxFileSize {
+			return nil
+		}
+		binary, err := file.IsBinary()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		if binary {
+			return nil
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return fmt.Errorf("error reading %s: %w", file.Name, err)
+		}
+		markers = append(markers, markersIn(file.Name, content)...)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	slices.SortFunc(markers, func(a, b Marker) int {
+		if a.File != b.File {
+			return strings.Compare(a.File, b.File)
+		}
+		return a.Line - b.Line
+	})
+	return markers, nil
+}
+
+// markersIn returns the markers in the content of file.
+func markersIn(file, content string) []Marker {
+	var markers []Marker
+	for index, line := range strings.Split(content, "\n") {
+		match := markerRegex.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text := strings.TrimSpace(match[3])
+		for _, closer := range []string{"*/", "-->"} {
+			text = strings.TrimSpace(strings.TrimSuffix(text, closer))
+		}
+		markers = append(markers, Marker{
+			Kind:  match[1],
+			File:  file,
+			Line:  index + 1,
+			Text:  text,
+			Owner: strings.TrimSpace(match[2]),
+		})
+	}
+	return markers
+}
+
+// isSkipped reports whether file is vendored.
+func isSkipped(file string) bool {
+	for _, dir := range strings.Split(path.Dir(file), "/") {
+		if slices.Contains(skippedDirs, dir) {
+			return true
+		}
+	}
+	return false
+}
+
+// blameMarkers sets the author and date of the markers in the first limit
+// files that have any, from the blame of commit. It returns the number of
+// files blamed.
+func blameMarkers(ctx context.Context, commit *object.Commit, markers []Marker, limit int) (int, error) {
+	reporter := progress.FromContext(ctx)
+	var files []string
+	for _, marker := range markers {
+		if !slices.Contains(files, marker.File) {
+			files = append(files, marker.File)
+		}
+	}
+	files = files[:min(limit, len(files))]
+	for index, file := range files {
+		if err := ctx.Err(); err != nil {
+			return index, fmt.Errorf("blame aborted: %w", err)
+		}
+		blame, err := git.Blame(commit, file)
+		if err != nil {
+			return index, fmt.Errorf("error blaming %s: %w", file, err)
+		}
+		for i := range markers {
+			marker := &markers[i]
+			if marker.File != file || marker.Line > len(blame.Lines) {
+				continue
+			}
+			line := blame.Lines[marker.Line-1]
+			marker.Author = line.AuthorName
+			marker.Date = line.Date
+		}
+		reporter.Report(float64(index+1), float64(len(files)), "blamed "+file)
+	}
+	return len(files), nil
+}
@@ -0,0 +1,129 @@
+package todotool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testFiles is the tree of the test repository.
+var testFiles = map[string]string{
+	"main.go": "package main\n\n// TODO(jane): read the port from the environment\nfunc main() {}\n",
+	"cmd/server/serve.go": "package main\n\n// FIXME: handle shutdown\nfunc serve() {\n" +
+		"\t// HACK work around the proxy timeout\n}\n",
+	"scripts/setup.sh":        "#!/bin/sh\n# TODO install the linter\necho 'TODO list'\n",
+	"vendor/example.org/l.go": "package l\n\n// TODO: not ours\n",
+	"docs/logo.png":           "\x89PNG\r\n\x1a\n\x00\x00// TODO binary\n",
+}
+
+// testTime is when the test repository is committed.
+var testTime = gittest.Author.When
+
+// headCommit returns the head commit of the repository at dir.
+func headCommit(t *testing.T, dir string) *object.Commit {
+	t.Helper()
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	head, err := repo.Head()
+	require.NoError(t, err)
+	commit, err := repo.CommitObject(head.Hash())
+	require.NoError(t, err)
+	return commit
+}
+
+func TestMarkersIn(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		line   string
+		marker *Marker
+	}{
+		{line: "// TODO: add caching", marker: &Marker{Kind: KindTODO, Text: "add caching"}},
+		{line: "\tx := 1 // FIXME(bob) off by one", marker: &Marker{Kind: KindFIXME, Text: "off by one", Owner: "bob"}},
+		{line: "# HACK - pin the version", marker: &Marker{Kind: KindHACK, Text: "pin the version"}},
+		{line: "/* TODO remove after release */", marker: &Marker{Kind: KindTODO, Text: "remove after release"}},
+		{line: " * TODO document the options", marker: &Marker{Kind: KindTODO, Text: "document the options"}},
+		{line: "<!-- FIXME: broken link -->", marker: &Marker{Kind: KindFIXME, Text: "broken link"}},
+		{line: "-- TODO index this column", marker: &Marker{Kind: KindTODO, Text: "index this column"}},
+		{line: "// TODO", marker: &Marker{Kind: KindTODO}},
+		{line: `fmt.Println("TODO list")`},
+		{line: "// TODOS are tracked elsewhere"},
+		{line: "todoList := nil // todo"},
+	}
+	for _, testCase := range tests {
+		markers := markersIn("a.go", testCase.line)
+		if testCase.marker == nil {
+			assert.Empty(t, markers, testCase.line)
+			continue
+		}
+		expected := *testCase.marker
+		expected.File = "a.go"
+		expected.Line = 1
+		assert.Equal(t, []Marker{expected}, markers, testCase.line)
+	}
+}
+
+func TestFindMarkers(t *testing.T) {
+	t.Parallel()
+	tree, err := headCommit(t, gittest.InitRepo(t, testFiles)).Tree()
+	require.NoError(t, err)
+
+	markers, err := FindMarkers(tree)
+	require.NoError(t, err)
+	assert.Equal(t, []Marker{
+		{Kind: KindFIXME, File: "cmd/server/serve.go", Line: 3, Text: "handle shutdown"},
+		{Kind: KindHACK, File: "cmd/server/serve.go", Line: 5, Text: "work around the proxy timeout"},
+		{Kind: KindTODO, File: "main.go", Line: 3, Text: "read the port from the environment", Owner: "jane"},
+		{Kind: KindTODO, File: "scripts/setup.sh", Line: 2, Text: "install the linter"},
+	}, markers)
+	assert.Equal(t, "cmd/server", markers[0].Area())
+	assert.Equal(t, "(root)", markers[2].Area())
+}
+
+func TestBlameMarkers(t *testing.T) {
+	t.Parallel()
+	later := testTime.AddDate(0, 1, 0)
+	repo := gittest.NewRepo(t,
+		gittest.Commit{Files: testFiles},
+		gittest.Commit{
+			Files: map[string]string{
+				"main.go": "package main\n\n// TODO(jane): read the port from the environment\nfunc main() {}\n\n// TODO: add tests\n",
+			},
+			Author: object.Signature{Name: "John Roe", Email: "dev@example.org", When: later},
+		},
+	)
+	commit := headCommit(t, repo.Dir)
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+	markers, err := FindMarkers(tree)
+	require.NoError(t, err)
+
+	blamed, err := blameMarkers(context.Background(), commit, markers, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 2, blamed)
+	assert.Equal(t, "Jane Doe", markers[0].Author)
+	assert.Equal(t, "Jane Doe", markers[1].Author)
+	assert.Equal(t, "Jane Doe", markers[2].Author)
+	assert.Equal(t, testTime, markers[2].Date.UTC())
+	assert.Equal(t, "John Roe", markers[3].Author)
+	assert.Equal(t, later, markers[3].Date.UTC())
+	assert.Empty(t, markers[4].Author, "files beyond the limit are not blamed")
+}
+
+func TestBlameMarkers_Cancelled(t *testing.T) {
+	t.Parallel()
+	commit := headCommit(t, gittest.InitRepo(t, testFiles))
+	tree, err := commit.Tree()
+	require.NoError(t, err)
+	markers, err := FindMarkers(tree)
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err = blameMarkers(ctx, commit, markers, maxBlamedFiles)
+	require.ErrorIs(t, err, context.Canceled)
+}
@@ -0,0 +1,243 @@
+package todotool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// maxBlamedFiles is the number of files authors are looked up in; blame
+// reads the history of each file, which is slow in large repositories.
+const maxBlamedFiles = 100
+
+// TodoTool lists the TODO, FIXME and HACK comments of a repository.
+type TodoTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	analyzer    *worksummary.GitAnalyzer
+	resources   *resources.Catalog
+}
+
+// Option defines a functional option for configuring TodoTool.
+type Option func(*TodoTool)
+
+// WithResources publishes generated reports as MCP resources in the
+// catalog.
+func WithResources(catalog *resources.Catalog) Option {
+	return func(t *TodoTool) {
+		t.resources = catalog
+	}
+}
+
+// WithAnalyzer replaces the analyzer the repository is read with.
+func WithAnalyzer(analyzer *worksummary.GitAnalyzer) Option {
+	return func(t *TodoTool) {
+		t.analyzer = analyzer
+	}
+}
+
+// TodoRequest represents the parameters for a marker scan.
+type TodoRequest struct {
+	RepoURL string `validate:"required"`
+	Branch  string `validate:"required"`
+	// Blame looks up who last changed each marker.
+	Blame bool
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"todo-scan",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewTodoTool(deps.Logger, WithResources(deps.Resources))
+		},
+	)
+}
+
+// NewTodoTool creates a new TodoTool instance.
+func NewTodoTool(logger *slog.Logger, opts ...Option) (*TodoTool, error) {
+	tool := mcp.NewTool(
+		"todo-scan",
+		mcp.WithDescription(
+			"Lists the TODO, FIXME and HACK comments of a git repository with file, line and the author "+
+				"who last changed them, grouped by area, to seed sprint planning",
+		),
+		mcp.WithTitleAnnotation("TODO Scan"),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(true),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"repo_url",
+			mcp.Description("The URL of the git repository"),
+			mcp.Required(),
+		),
+		mcp.WithString(
+			"branch",
+			mcp.Description("The branch to scan"),
+			mcp.Required(),
+		),
+		mcp.WithBoolean(
+			"blame",
+			mcp.Description("Look up the author who last changed each comment, defaults to true"),
+		),
+	)
+	todoTool := &TodoTool{
+		Name:        "todo-scan",
+		Description: "Lists the TODO, FIXME and HACK comments of a repository",
+		Tool:        tool,
+		Logger:      logger,
+		analyzer:    worksummary.NewGitAnalyzer(worksummary.WithLogger(logger)),
+	}
+	for _, opt := range opts {
+		opt(todoTool)
+	}
+	return todoTool, nil
+}
+
+// GetName returns the name of the tool.
+func (t *TodoTool) GetName() string {
+	return t.Name
+}
+
+// GetDescription returns the description of the tool.
+func (t *TodoTool) GetDescription() string {
+	return t.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (t *TodoTool) GetSchema() mcp.ToolInputSchema {
+	return t.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (t *TodoTool) GetTool() mcp.Tool {
+	return t.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (t *TodoTool) GetAnnotations() mcp.ToolAnnotation {
+	return t.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (t *TodoTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "List the TODO and FIXME comments of a repository with their authors",
+			Arguments: map[string]any{
+				"repo_url": "https://github.com/dictybase/modware-annotation",
+				"branch":   "develop",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (t *TodoTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := TodoRequest{
+		RepoURL: request.GetString("repo_url", ""),
+		Branch:  request.GetString("branch", ""),
+		Blame:   request.GetBool("blame", true),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	scan, err := t.ScanMarkers(ctx, params)
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	content := RenderMarkdown(scan)
+	result := provenance.Attach(
+		mcp.NewToolResultText(content),
+		provenance.New([]string{metrics.ServiceGitClone}),
+	)
+	if t.resources != nil {
+		resource, err := t.resources.Publish(resources.PublishParams{
+			Kind:        resources.KindGitSummary,
+			Name:        fmt.Sprintf("%s-%s-todos.md", worksummary.RepoName(params.RepoURL), params.Branch),
+			MIMEType:    "text/markdown",
+			Description: "TODO scan of " + params.RepoURL,
+			Data:        []byte(content),
+		})
+		if err != nil {
+			return toolerror.Result(fmt.Errorf("error publishing scan: %w", err)), nil
+		}
+		result.Content = append(result.Content, resources.Link(resource))
+	}
+	return result, nil
+}
+
+// ScanMarkers clones the branch and finds the markers at its head,
+// blaming them when asked.
+func (t *TodoTool) ScanMarkers(ctx context.Context, params TodoRequest) (Scan, error) {
+	reporter := progress.FromContext(ctx)
+	reporter.Report(0, 1, "cloning repository")
+	repo, err := t.analyzer.CloneAndCheckout(
+		progress.NewContext(ctx, reporter.Sub(0, 0.5)),
+		params.RepoURL,
+		params.Branch,
+	)
+	if err != nil {
+		return Scan{}, toolerror.Upstream(metrics.ServiceGitClone, err, "failed to clone repository")
+	}
+	defer repo.Close()
+	reporter.Report(0.5, 1, "scanning for markers")
+	head, err := repo.Head()
+	if err != nil {
+		return Scan{}, fmt.Errorf("error resolving branch head: %w", err)
+	}
+	tip, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return Scan{}, fmt.Errorf("error reading branch head: %w", err)
+	}
+	tree, err := tip.Tree()
+	if err != nil {
+		return Scan{}, fmt.Errorf("error reading file tree: %w", err)
+	}
+	markers, err := FindMarkers(tree)
+	if err != nil {
+		return Scan{}, err
+	}
+	scan := Scan{
+		RepoURL: params.RepoURL,
+		Branch:  params.Branch,
+		Commit:  tip.Hash.String(),
+		When:    tip.Committer.When,
+		Markers: markers,
+		Blamed:  params.Blame,
+	}
+	if params.Blame && len(markers) > 0 {
+		reporter.Report(0.6, 1, "looking up authors")
+		scan.BlamedFiles, err = blameMarkers(
+			progress.NewContext(ctx, reporter.Sub(0.6, 1)),
+			tip,
+			scan.Markers,
+			maxBlamedFiles,
+		)
+		if err != nil {
+			return Scan{}, err
+		}
+	}
+	reporter.Report(1, 1, "scan complete")
+	return scan, nil
+}
@@ -0,0 +1,79 @@
+package todotool
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/gittest"
+	"github.com/dictybase/dcr-mcp/pkg/provenance"
+	"github.com/dictybase/dcr-mcp/pkg/resources"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/worksummary"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestTool(t *testing.T, opts ...Option) *TodoTool {
+	t.Helper()
+	analyzer := worksummary.NewGitAnalyzer(worksummary.WithTimeZone(time.UTC))
+	tool, err := NewTodoTool(
+		slog.New(slog.NewTextHandler(os.Stderr, nil)),
+		append([]Option{WithAnalyzer(analyzer)}, opts...)...,
+	)
+	require.NoError(t, err)
+	return tool
+}
+
+func TestScanMarkers(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, testFiles)
+	tool := newTestTool(t)
+
+	scan, err := tool.ScanMarkers(context.Background(), TodoRequest{RepoURL: dir, Branch: "master", Blame: true})
+	require.NoError(t, err)
+	assert.Len(t, scan.Commit, 40)
+	assert.Equal(t, testTime, scan.When.UTC())
+	assert.Len(t, scan.Markers, 4)
+	assert.Equal(t, 3, scan.BlamedFiles)
+	assert.Equal(t, "Jane Doe", scan.Markers[0].Author)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	dir := gittest.InitRepo(t, testFiles)
+	catalog := resources.NewCatalog(server.NewMCPServer("test", "1.0.0"))
+	tool := newTestTool(t, WithResources(catalog))
+
+	result := tooltest.Call(t, tool.Handler, "todo-scan", map[string]any{"repo_url": dir, "branch": "master", "blame": false})
+	require.False(t, result.IsError)
+	require.Len(t, result.Content, 2)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "4 markers in 3 files: 1 FIXME, 1 HACK, 2 TODO.\n")
+	assert.Contains(t, text.Text, "| FIXME | `cmd/server/serve.go:3` | handle shutdown |\n")
+	assert.NotContains(t, text.Text, "## By Author")
+	record, ok := provenance.FromResult(result)
+	require.True(t, ok)
+	assert.Equal(t, []string{"git_clone"}, record.Providers)
+}
+
+func TestHandler_InvalidInput(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool(t)
+	for _, arguments := range []map[string]any{
+		{"repo_url": "https://github.com/dictybase/dcr-mcp"},
+		{"branch": "develop"},
+	} {
+		result := tooltest.Call(t, tool.Handler, "todo-scan", arguments)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+	}
+}
@@ -0,0 +1,260 @@
+package uploadtool
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Tool actions.
+const (
+	ActionBegin    = "begin"
+	ActionAppend   = "append"
+	ActionComplete = "complete"
+	ActionStatus   = "status"
+	ActionDiscard  = "discard"
+)
+
+// Chunk encodings.
+const (
+	EncodingText   = "text"
+	EncodingBase64 = "base64"
+)
+
+// UploadTool lets clients send inputs too large for a single tool call in
+// chunks. The handle of a completed upload is passed as upload_id to the
+// conversion tools.
+type UploadTool struct {
+	Name        string
+	Description string
+	Tool        mcp.Tool
+	Logger      *slog.Logger
+	store       *upload.Store
+}
+
+// ChunkRequest represents the parameters of the append action.
+type ChunkRequest struct {
+	ID       string `validate:"required"`
+	Index    int    `validate:"gte=0"`
+	Data     string
+	Encoding string `validate:"required,oneof=text base64"`
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"upload",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			return NewUploadTool(deps.Logger, deps.Uploads)
+		},
+	)
+}
+
+// NewUploadTool creates a new UploadTool instance storing uploads in store.
+func NewUploadTool(logger *slog.Logger, store *upload.Store) (*UploadTool, error) {
+	if store == nil {
+		return nil, errors.New("upload tool requires an upload store")
+	}
+	tool := mcp.NewTool(
+		"upload",
+		mcp.WithDescription(
+			"Uploads a large markdown, PDF or CSV input in chunks: begin returns an upload_id, "+
+				"append sends chunks in order, complete finishes it; then pass upload_id to "+
+				"markdown, markdown_to_pdf or publish instead of content",
+		),
+		mcp.WithTitleAnnotation("Chunked Upload"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(false),
+		mcp.WithString(
+			"action",
+			mcp.Description("begin, append, complete, status or discard"),
+			mcp.Required(),
+			mcp.Enum(ActionBegin, ActionAppend, ActionComplete, ActionStatus, ActionDiscard),
+		),
+		mcp.WithString(
+			upload.IDArgument,
+			mcp.Description("Handle returned by begin (all actions but begin)"),
+		),
+		mcp.WithString("name", mcp.Description("File name of the input (begin)")),
+		mcp.WithString(
+			"content_type",
+			mcp.Description(
+				"MIME type of the input, defaults to text/markdown (begin); markdown, plain text, "+
+					"PDF, PNG, JPEG, GIF, WebP, CSV, TSV, JSON, XLSX and gzip are accepted and "+
+					"the content is checked against it on complete",
+			),
+		),
+		mcp.WithNumber("index", mcp.Description("0-based chunk number (append)")),
+		mcp.WithString("data", mcp.Description("Chunk content (append)")),
+		mcp.WithString(
+			"encoding",
+			mcp.Description("Encoding of data: text or base64 for binary input, defaults to text (append)"),
+			mcp.Enum(EncodingText, EncodingBase64),
+		),
+		mcp.WithString(
+			"sha256",
+			mcp.Description("Optional hex SHA-256 of the whole input, verified on complete"),
+		),
+	)
+	return &UploadTool{
+		Name:        "upload",
+		Description: "Uploads large inputs in chunks for use by the conversion tools",
+		Tool:        tool,
+		Logger:      logger,
+		store:       store,
+	}, nil
+}
+
+// GetName returns the name of the tool.
+func (u *UploadTool) GetName() string {
+	return u.Name
+}
+
+// GetDescription returns the description of the tool.
+func (u *UploadTool) GetDescription() string {
+	return u.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (u *UploadTool) GetSchema() mcp.ToolInputSchema {
+	return u.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (u *UploadTool) GetTool() mcp.Tool {
+	return u.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (u *UploadTool) GetAnnotations() mcp.ToolAnnotation {
+	return u.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (u *UploadTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Begin an upload of a large markdown document",
+			Arguments: map[string]any{
+				"action":       "begin",
+				"name":         "report.md",
+				"content_type": "text/markdown",
+			},
+		},
+		{
+			Description: "Append the first chunk of an upload",
+			Arguments: map[string]any{
+				"action":    "append",
+				"upload_id": "upl_5f2c9a0e81d4b73c2a6e19f0",
+				"index":     0,
+				"data":      "# Annual report\n\n",
+				"encoding":  "text",
+			},
+		},
+		{
+			Description: "Complete an upload",
+			Arguments: map[string]any{
+				"action":    "complete",
+				"upload_id": "upl_5f2c9a0e81d4b73c2a6e19f0",
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (u *UploadTool) Handler(
+	_ context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	id := request.GetString(upload.IDArgument, "")
+	var info upload.Info
+	var err error
+	switch action := request.GetString("action", ""); action {
+	case ActionBegin:
+		info, err = u.store.Begin(upload.BeginParams{
+			Name:        request.GetString("name", ""),
+			ContentType: request.GetString("content_type", "text/markdown"),
+		})
+	case ActionAppend:
+		info, err = u.appendChunk(request, id)
+	case ActionComplete:
+		info, err = u.store.Complete(id, request.GetString("sha256", ""))
+	case ActionStatus:
+		info, err = u.store.Status(id)
+	case ActionDiscard:
+		if err = u.store.Discard(id); err == nil {
+			return mcp.NewToolResultText(fmt.Sprintf("Discarded upload %s", id)), nil
+		}
+	default:
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_ACTION",
+			"action must be one of begin, append, complete, status or discard",
+		)), nil
+	}
+	if err != nil {
+		return toolerror.Result(classify(err)), nil
+	}
+	encoded, err := json.MarshalIndent(info, "", "  ")
+	if err != nil {
+		return toolerror.Result(fmt.Errorf("failed to encode upload status: %w", err)), nil
+	}
+	return mcp.NewToolResultStructured(info, string(encoded)), nil
+}
+
+// appendChunk decodes the chunk in the request and adds it to the upload.
+func (u *UploadTool) appendChunk(request mcp.CallToolRequest, id string) (upload.Info, error) {
+	params := ChunkRequest{
+		ID:       id,
+		Index:    request.GetInt("index", -1),
+		Data:     request.GetString("data", ""),
+		Encoding: request.GetString("encoding", EncodingText),
+	}
+	if err := validate.Struct(params); err != nil {
+		return upload.Info{}, toolerror.InvalidInput(err)
+	}
+	data := []byte(params.Data)
+	if params.Encoding == EncodingBase64 {
+		decoded, err := base64.StdEncoding.DecodeString(params.Data)
+		if err != nil {
+			return upload.Info{}, toolerror.Wrap(
+				toolerror.TypeInvalidInput,
+				"INVALID_ENCODING",
+				err,
+				"chunk is not valid base64",
+			)
+		}
+		data = decoded
+	}
+	return u.store.Append(upload.AppendParams{ID: params.ID, Index: params.Index, Data: data})
+}
+
+// classify maps upload store errors onto tool error types.
+func classify(err error) error {
+	var toolErr *toolerror.Error
+	switch {
+	case errors.As(err, &toolErr):
+		return err
+	case errors.Is(err, upload.ErrNotFound):
+		return toolerror.Wrap(toolerror.TypeNotFound, "UPLOAD_NOT_FOUND", err, "upload failed")
+	case errors.Is(err, upload.ErrTooManyOpen):
+		return toolerror.Wrap(toolerror.TypeInternal, "TOO_MANY_UPLOADS", err, "upload failed")
+	default:
+		return toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_UPLOAD", err, "upload failed")
+	}
+}
@@ -0,0 +1,73 @@
+package uploadtool
+
+import (
+	"encoding/base64"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tooltest"
+	"github.com/dictybase/dcr-mcp/pkg/upload"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHandler_ChunkedUpload(t *testing.T) {
+	t.Parallel()
+	store := upload.NewStore()
+	tool, err := NewUploadTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), store)
+	require.NoError(t, err)
+
+	result := tooltest.Call(t, tool.Handler, "upload", map[string]any{"action": "begin", "name": "notes.md"})
+	require.False(t, result.IsError)
+	info, ok := result.StructuredContent.(upload.Info)
+	require.True(t, ok)
+
+	result = tooltest.Call(t, tool.Handler, "upload", map[string]any{
+		"action": "append", "upload_id": info.ID, "index": 0, "data": "# Notes\n",
+	})
+	require.False(t, result.IsError)
+	result = tooltest.Call(t, tool.Handler, "upload", map[string]any{
+		"action":    "append",
+		"upload_id": info.ID,
+		"index":     1,
+		"data":      base64.StdEncoding.EncodeToString([]byte("Chunked.\n")),
+		"encoding":  "base64",
+	})
+	require.False(t, result.IsError)
+	result = tooltest.Call(t, tool.Handler, "upload", map[string]any{"action": "complete", "upload_id": info.ID})
+	require.False(t, result.IsError)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"upload_id": info.ID}
+	text, err := upload.TextArgument(store, request, "content")
+	require.NoError(t, err)
+	assert.Equal(t, "# Notes\nChunked.\n", text)
+}
+
+func TestHandler_Errors(t *testing.T) {
+	t.Parallel()
+	tool, err := NewUploadTool(slog.New(slog.NewTextHandler(os.Stderr, nil)), upload.NewStore())
+	require.NoError(t, err)
+
+	for _, test := range []struct {
+		args map[string]any
+		code string
+	}{
+		{map[string]any{"action": "status", "upload_id": "upl_missing"}, "UPLOAD_NOT_FOUND"},
+		{map[string]any{"action": "append", "upload_id": "upl_missing"}, "INVALID_INPUT"},
+		{map[string]any{"action": "append", "upload_id": "x", "index": 0, "data": "%", "encoding": "base64"}, "INVALID_ENCODING"},
+		{map[string]any{"action": "rename"}, "INVALID_ACTION"},
+	} {
+		result := tooltest.Call(t, tool.Handler, "upload", test.args)
+		require.True(t, result.IsError)
+		toolErr, ok := result.StructuredContent.(*toolerror.Error)
+		require.True(t, ok)
+		assert.Equal(t, test.code, toolErr.Code, test.args)
+	}
+
+	_, err = NewUploadTool(nil, nil)
+	require.Error(t, err)
+}
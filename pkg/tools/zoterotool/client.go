@@ -0,0 +1,241 @@
+package zoterotool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
+)
+
+const defaultBaseURL = "https://api.zotero.org"
+
+// maxWriteItems is the largest number of items the API accepts per write.
+const maxWriteItems = 50
+
+// ZoteroClient talks to the Zotero web API for a single user or group
+// library.
+type ZoteroClient struct {
+	httpClient  *http.Client
+	baseURL     string
+	libraryPath string
+	apiKey      string
+	logger      *slog.Logger
+}
+
+// LibraryConfig identifies a Zotero library and the key used to access it.
+type LibraryConfig struct {
+	LibraryType string `validate:"required,oneof=user group"`
+	LibraryID   string `validate:"required,numeric"`
+	APIKey      string `validate:"required"`
+}
+
+// Option represents a configuration option for ZoteroClient.
+type Option func(*Config)
+
+// Config holds the configuration for the Zotero client.
+type Config struct {
+	baseURL string
+	timeout time.Duration
+	logger  *slog.Logger
+}
+
+// WithBaseURL overrides the Zotero API base URL.
+func WithBaseURL(baseURL string) Option {
+	return func(c *Config) {
+		c.baseURL = baseURL
+	}
+}
+
+// WithTimeout sets the HTTP timeout for requests.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Config) {
+		c.timeout = timeout
+	}
+}
+
+// WithLogger sets the logger for the client.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// ExportParams holds the parameters for exporting library items.
+type ExportParams struct {
+	Format string `validate:"required,oneof=bibtex ris csljson"`
+	// Collection limits the export to a collection key.
+	Collection string
+	// Tag limits the export to items with the tag.
+	Tag   string
+	Limit int `validate:"gte=0,lte=100"`
+}
+
+// NewZoteroClient creates a client for the configured library.
+func NewZoteroClient(library LibraryConfig, opts ...Option) (*ZoteroClient, error) {
+	if err := validate.Struct(library); err != nil {
+		return nil, fmt.Errorf("invalid Zotero library configuration: %w", err)
+	}
+	cfg := &Config{
+		baseURL: defaultBaseURL,
+		timeout: 30 * time.Second,
+		logger:  slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &ZoteroClient{
+		httpClient:  ratelimit.NewHTTPClient(cfg.timeout),
+		baseURL:     strings.TrimSuffix(cfg.baseURL, "/"),
+		libraryPath: fmt.Sprintf("/%ss/%s", library.LibraryType, library.LibraryID),
+		apiKey:      library.APIKey,
+		logger:      cfg.logger,
+	}, nil
+}
+
+// AddItems creates items in the library and returns their keys in the
+// order the items were given.
+func (c *ZoteroClient) AddItems(ctx context.Context, items []Item) ([]string, error) {
+	if len(items) == 0 {
+		return nil, fmt.Errorf("no items to add")
+	}
+	if len(items) > maxWriteItems {
+		return nil, fmt.Errorf("cannot add more than %d items at once", maxWriteItems)
+	}
+	body, err := json.Marshal(items)
+	if err != nil {
+		return nil, fmt.Errorf("error encoding items: %w", err)
+	}
+	req, err := c.newRequest(ctx, http.MethodPost, "/items", nil, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	var written writeResponse
+	if err := c.do(req, func(resp io.Reader) error {
+		return json.NewDecoder(resp).Decode(&written)
+	}); err != nil {
+		return nil, err
+	}
+	if len(written.Failed) > 0 {
+		return nil, failedWriteError(written)
+	}
+	keys := make([]string, len(items))
+	for index, success := range written.Successful {
+		position, err := strconv.Atoi(index)
+		if err != nil || position < 0 || position >= len(keys) {
+			return nil, fmt.Errorf("unexpected item index %q in Zotero response", index)
+		}
+		keys[position] = success.Key
+	}
+	c.logger.Info("added items to Zotero", "count", len(keys))
+	return keys, nil
+}
+
+// Export returns library items rendered in the requested format.
+func (c *ZoteroClient) Export(ctx context.Context, params ExportParams) (string, error) {
+	if err := validate.Struct(params); err != nil {
+		return "", fmt.Errorf("invalid export parameters: %w", err)
+	}
+	path := "/items/top"
+	if params.Collection != "" {
+		path = fmt.Sprintf("/collections/%s/items/top", url.PathEscape(params.Collection))
+	}
+	query := url.Values{"format": {params.Format}}
+	if params.Tag != "" {
+		query.Set("tag", params.Tag)
+	}
+	if params.Limit > 0 {
+		query.Set("limit", strconv.Itoa(params.Limit))
+	}
+	req, err := c.newRequest(ctx, http.MethodGet, path, query, nil)
+	if err != nil {
+		return "", err
+	}
+
+	var exported string
+	if err := c.do(req, func(resp io.Reader) error {
+		data, err := io.ReadAll(resp)
+		exported = string(data)
+		return err
+	}); err != nil {
+		return "", err
+	}
+	return exported, nil
+}
+
+// newRequest builds an authenticated request against the library.
+func (c *ZoteroClient) newRequest(
+	ctx context.Context,
+	method, path string,
+	query url.Values,
+	body io.Reader,
+) (*http.Request, error) {
+	endpoint := c.baseURL + c.libraryPath + path
+	if len(query) > 0 {
+		endpoint += "?" + query.Encode()
+	}
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, body)
+	if err != nil {
+		return nil, fmt.Errorf("error creating Zotero request: %w", err)
+	}
+	req.Header.Set("Zotero-API-Key", c.apiKey)
+	req.Header.Set("Zotero-API-Version", "3")
+	return req, nil
+}
+
+// do sends the request, records its latency and hands successful response
+// bodies to decode.
+func (c *ZoteroClient) do(req *http.Request, decode func(io.Reader) error) error {
+	start := time.Now()
+	err := c.send(req, decode)
+	metrics.ObserveOutbound(metrics.ServiceZotero, start, err)
+	return err
+}
+
+// send performs the HTTP round trip for do.
+func (c *ZoteroClient) send(req *http.Request, decode func(io.Reader) error) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("error calling Zotero: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		detail, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf(
+			"zotero returned status %d: %s",
+			resp.StatusCode,
+			strings.TrimSpace(string(detail)),
+		)
+	}
+	if err := decode(resp.Body); err != nil {
+		return fmt.Errorf("error reading Zotero response: %w", err)
+	}
+	return nil
+}
+
+// failedWriteError summarizes the items Zotero rejected.
+func failedWriteError(written writeResponse) error {
+	indexes := make([]string, 0, len(written.Failed))
+	for index := range written.Failed {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	messages := make([]string, 0, len(indexes))
+	for _, index := range indexes {
+		failure := written.Failed[index]
+		messages = append(messages, fmt.Sprintf("item %s: %s (%d)", index, failure.Message, failure.Code))
+	}
+	return fmt.Errorf("zotero rejected items: %s", strings.Join(messages, "; "))
+}
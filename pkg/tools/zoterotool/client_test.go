@@ -0,0 +1,94 @@
+package zoterotool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestClient(t *testing.T, handler http.Handler) *ZoteroClient {
+	t.Helper()
+	server := httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+	client, err := NewZoteroClient(
+		LibraryConfig{LibraryType: LibraryTypeGroup, LibraryID: "42", APIKey: "secret"},
+		WithBaseURL(server.URL),
+	)
+	require.NoError(t, err)
+	return client
+}
+
+func TestZoteroClient_AddItems(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/groups/42/items", r.URL.Path)
+		assert.Equal(t, "secret", r.Header.Get("Zotero-API-Key"))
+		var items []Item
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&items))
+		assert.Len(t, items, 2)
+		_, _ = io.WriteString(w, `{"successful":{"1":{"key":"BBBB"},"0":{"key":"AAAA"}},"failed":{}}`)
+	}))
+
+	keys, err := client.AddItems(context.Background(), []Item{
+		{ItemType: "journalArticle", Title: "First"},
+		{ItemType: "journalArticle", Title: "Second"},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"AAAA", "BBBB"}, keys)
+}
+
+func TestZoteroClient_AddItemsFailed(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		_, _ = io.WriteString(w, `{"successful":{},"failed":{"0":{"code":400,"message":"Invalid itemType"}}}`)
+	}))
+
+	_, err := client.AddItems(context.Background(), []Item{{ItemType: "bogus", Title: "x"}})
+	require.ErrorContains(t, err, "Invalid itemType")
+}
+
+func TestZoteroClient_Export(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/groups/42/collections/COLL/items/top", r.URL.Path)
+		assert.Equal(t, "bibtex", r.URL.Query().Get("format"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+		_, _ = io.WriteString(w, "@article{doe2024}")
+	}))
+
+	exported, err := client.Export(context.Background(), ExportParams{
+		Format:     FormatBibTeX,
+		Collection: "COLL",
+		Limit:      10,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, "@article{doe2024}", exported)
+
+	_, err = client.Export(context.Background(), ExportParams{Format: "docx"})
+	require.Error(t, err)
+}
+
+func TestZoteroClient_ErrorStatus(t *testing.T) {
+	t.Parallel()
+	client := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		http.Error(w, "Forbidden", http.StatusForbidden)
+	}))
+
+	_, err := client.Export(context.Background(), ExportParams{Format: FormatRIS})
+	require.ErrorContains(t, err, "status 403")
+}
+
+func TestNewZoteroClient_Validation(t *testing.T) {
+	t.Parallel()
+	_, err := NewZoteroClient(LibraryConfig{LibraryType: "team", LibraryID: "1", APIKey: "k"})
+	require.Error(t, err)
+	_, err = NewZoteroClient(LibraryConfig{LibraryType: LibraryTypeUser, LibraryID: "1"})
+	require.Error(t, err)
+}
@@ -0,0 +1,57 @@
+package zoterotool
+
+// Library types supported by the Zotero API.
+const (
+	LibraryTypeUser  = "user"
+	LibraryTypeGroup = "group"
+)
+
+// Export formats supported by the export action.
+const (
+	FormatBibTeX  = "bibtex"
+	FormatRIS     = "ris"
+	FormatCSLJSON = "csljson"
+)
+
+// Item is a Zotero library item in the API's JSON representation.
+type Item struct {
+	ItemType         string    `json:"itemType"`
+	Title            string    `json:"title"`
+	Creators         []Creator `json:"creators"`
+	PublicationTitle string    `json:"publicationTitle,omitempty"`
+	Date             string    `json:"date,omitempty"`
+	Volume           string    `json:"volume,omitempty"`
+	Issue            string    `json:"issue,omitempty"`
+	Pages            string    `json:"pages,omitempty"`
+	DOI              string    `json:"DOI,omitempty"`
+	URL              string    `json:"url,omitempty"`
+	AbstractNote     string    `json:"abstractNote,omitempty"`
+	Extra            string    `json:"extra,omitempty"`
+	Collections      []string  `json:"collections,omitempty"`
+	Tags             []Tag     `json:"tags,omitempty"`
+}
+
+// Creator is an author or other contributor of an item.
+type Creator struct {
+	CreatorType string `json:"creatorType"`
+	FirstName   string `json:"firstName,omitempty"`
+	LastName    string `json:"lastName,omitempty"`
+	Name        string `json:"name,omitempty"`
+}
+
+// Tag is a free-form item tag.
+type Tag struct {
+	Tag string `json:"tag"`
+}
+
+// writeResponse is the response to a multi-object write request. Objects
+// are keyed by their index in the request.
+type writeResponse struct {
+	Successful map[string]struct {
+		Key string `json:"key"`
+	} `json:"successful"`
+	Failed map[string]struct {
+		Code    int    `json:"code"`
+		Message string `json:"message"`
+	} `json:"failed"`
+}
@@ -0,0 +1,329 @@
+package zoterotool
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/logging"
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/go-playground/validator/v10"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Tool actions.
+const (
+	ActionAdd    = "add"
+	ActionExport = "export"
+)
+
+// ZoteroTool adds references to and exports bibliographies from the lab's
+// Zotero library. The library is configured with the ZOTERO_API_KEY,
+// ZOTERO_LIBRARY_ID and ZOTERO_LIBRARY_TYPE environment variables.
+type ZoteroTool struct {
+	Name          string
+	Description   string
+	Tool          mcp.Tool
+	Logger        *slog.Logger
+	clientOptions []Option
+}
+
+// ToolOption defines a functional option for configuring ZoteroTool.
+type ToolOption func(*ZoteroTool)
+
+// WithClientOptions sets options for the Zotero clients the tool creates.
+func WithClientOptions(opts ...Option) ToolOption {
+	return func(z *ZoteroTool) {
+		z.clientOptions = append(z.clientOptions, opts...)
+	}
+}
+
+// AddRequest represents the parameters for adding a journal article.
+type AddRequest struct {
+	Title      string `validate:"required"`
+	Authors    []string
+	Journal    string
+	Year       string `validate:"omitempty,numeric,len=4"`
+	DOI        string
+	PMID       string `validate:"omitempty,numeric"`
+	Collection string
+	Tags       []string
+}
+
+//nolint:gochecknoinits // tools self-register so the server can discover them
+func init() {
+	registry.Register(
+		"zotero",
+		func(deps registry.Dependencies) (registry.Tool, error) {
+			zoteroTool, err := NewZoteroTool(deps.Logger)
+			if err != nil {
+				return nil, err
+			}
+			return zoteroTool, nil
+		},
+	)
+}
+
+// NewZoteroTool creates a new ZoteroTool instance.
+func NewZoteroTool(logger *slog.Logger, opts ...ToolOption) (*ZoteroTool, error) {
+	tool := mcp.NewTool(
+		"zotero",
+		mcp.WithDescription(
+			"Adds journal articles to the lab's Zotero library or exports its references as BibTeX, RIS or CSL-JSON",
+		),
+		mcp.WithTitleAnnotation("Zotero Library"),
+		mcp.WithReadOnlyHintAnnotation(false),
+		mcp.WithDestructiveHintAnnotation(false),
+		mcp.WithIdempotentHintAnnotation(false),
+		mcp.WithOpenWorldHintAnnotation(true),
+		mcp.WithString(
+			"action",
+			mcp.Description("'add' to create a reference, 'export' to export references"),
+			mcp.Required(),
+			mcp.Enum(ActionAdd, ActionExport),
+		),
+		mcp.WithString("title", mcp.Description("Article title (add)")),
+		mcp.WithString(
+			"authors",
+			mcp.Description("Semicolon-separated authors as 'Last, First' (add)"),
+		),
+		mcp.WithString("journal", mcp.Description("Journal name (add)")),
+		mcp.WithString("year", mcp.Description("Publication year (add)")),
+		mcp.WithString("doi", mcp.Description("DOI of the article (add)")),
+		mcp.WithString("pmid", mcp.Description("PubMed ID of the article (add)")),
+		mcp.WithString(
+			"tags",
+			mcp.Description("Comma-separated tags (add) or a single tag to filter by (export)"),
+		),
+		mcp.WithString(
+			"collection",
+			mcp.Description("Zotero collection key to add to or export from"),
+		),
+		mcp.WithString(
+			"format",
+			mcp.Description("Export format, defaults to 'bibtex' (export)"),
+			mcp.Enum(FormatBibTeX, FormatRIS, FormatCSLJSON),
+		),
+		mcp.WithNumber(
+			"limit",
+			mcp.Description("Maximum number of references to export, at most 100 (export)"),
+		),
+	)
+	zoteroTool := &ZoteroTool{
+		Name:        "zotero",
+		Description: "Adds journal articles to the lab's Zotero library or exports its references",
+		Tool:        tool,
+		Logger:      logger,
+	}
+	for _, opt := range opts {
+		opt(zoteroTool)
+	}
+	return zoteroTool, nil
+}
+
+// GetName returns the name of the tool.
+func (z *ZoteroTool) GetName() string {
+	return z.Name
+}
+
+// GetDescription returns the description of the tool.
+func (z *ZoteroTool) GetDescription() string {
+	return z.Description
+}
+
+// GetSchema returns the JSON schema for the tool's parameters.
+func (z *ZoteroTool) GetSchema() mcp.ToolInputSchema {
+	return z.Tool.InputSchema
+}
+
+// GetTool returns the MCP Tool.
+func (z *ZoteroTool) GetTool() mcp.Tool {
+	return z.Tool
+}
+
+// GetAnnotations returns the hints telling clients how the tool affects its
+// environment.
+func (z *ZoteroTool) GetAnnotations() mcp.ToolAnnotation {
+	return z.Tool.Annotations
+}
+
+// GetExamples returns sample calls of the tool.
+func (z *ZoteroTool) GetExamples() []registry.Example {
+	return []registry.Example{
+		{
+			Description: "Add a journal article to a collection of the lab's library",
+			Arguments: map[string]any{
+				"action":     "add",
+				"title":      "DictyBase 2013: integrating multiple Dictyostelid species",
+				"authors":    "Basu, Siddhartha; Fey, Petra",
+				"journal":    "Nucleic Acids Research",
+				"year":       "2013",
+				"doi":        "10.1093/nar/gks1064",
+				"pmid":       "23172289",
+				"collection": "9KH9TNSJ",
+				"tags":       "dictybase,database",
+			},
+		},
+		{
+			Description: "Export the 50 most recent references as BibTeX",
+			Arguments: map[string]any{
+				"action": "export",
+				"format": "bibtex",
+				"limit":  50,
+			},
+		},
+	}
+}
+
+// Handler returns a function that handles tool execution requests.
+func (z *ZoteroTool) Handler(
+	ctx context.Context,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	action := request.GetString("action", "")
+	if action != ActionAdd && action != ActionExport {
+		return toolerror.Result(toolerror.New(
+			toolerror.TypeInvalidInput,
+			"INVALID_ACTION",
+			"action must be 'add' or 'export'",
+		)), nil
+	}
+	client, err := z.zoteroClient()
+	if err != nil {
+		return toolerror.Result(err), nil
+	}
+	if action == ActionAdd {
+		return z.add(ctx, client, request)
+	}
+	return z.export(ctx, client, request)
+}
+
+// zoteroClient creates a client for the library configured in the
+// environment.
+func (z *ZoteroTool) zoteroClient() (*ZoteroClient, error) {
+	libraryType := os.Getenv("ZOTERO_LIBRARY_TYPE")
+	if libraryType == "" {
+		libraryType = LibraryTypeUser
+	}
+	opts := append([]Option{WithLogger(z.Logger)}, z.clientOptions...)
+	client, err := NewZoteroClient(LibraryConfig{
+		LibraryType: libraryType,
+		LibraryID:   os.Getenv("ZOTERO_LIBRARY_ID"),
+		APIKey:      os.Getenv("ZOTERO_API_KEY"),
+	}, opts...)
+	if err != nil {
+		return nil, toolerror.Wrap(
+			toolerror.TypeConfiguration,
+			"ZOTERO_CONFIGURATION",
+			err,
+			"error initializing Zotero client",
+		)
+	}
+	return client, nil
+}
+
+// add creates a journal article from the request arguments.
+func (z *ZoteroTool) add(
+	ctx context.Context,
+	client *ZoteroClient,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	params := AddRequest{
+		Title:      strings.TrimSpace(request.GetString("title", "")),
+		Authors:    splitList(request.GetString("authors", ""), ";"),
+		Journal:    request.GetString("journal", ""),
+		Year:       request.GetString("year", ""),
+		DOI:        request.GetString("doi", ""),
+		PMID:       request.GetString("pmid", ""),
+		Collection: request.GetString("collection", ""),
+		Tags:       splitList(request.GetString("tags", ""), ","),
+	}
+	if err := validate.Struct(params); err != nil {
+		return toolerror.Result(toolerror.InvalidInput(err)), nil
+	}
+	keys, err := client.AddItems(ctx, []Item{articleItem(params)})
+	if err != nil {
+		return toolerror.Result(
+			toolerror.Upstream(metrics.ServiceZotero, err, "failed to add reference"),
+		), nil
+	}
+	logging.WithRequestID(z.Logger).Info("added Zotero item", "key", keys[0])
+	return mcp.NewToolResultText(
+		fmt.Sprintf("Added %q to Zotero with item key %s", params.Title, keys[0]),
+	), nil
+}
+
+// export renders library items in the requested format.
+func (z *ZoteroTool) export(
+	ctx context.Context,
+	client *ZoteroClient,
+	request mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	exported, err := client.Export(ctx, ExportParams{
+		Format:     request.GetString("format", FormatBibTeX),
+		Collection: request.GetString("collection", ""),
+		Tag:        strings.TrimSpace(request.GetString("tags", "")),
+		Limit:      request.GetInt("limit", 0),
+	})
+	if err != nil {
+		return toolerror.Result(
+			toolerror.Upstream(metrics.ServiceZotero, err, "failed to export references"),
+		), nil
+	}
+	if strings.TrimSpace(exported) == "" {
+		return mcp.NewToolResultText("No references found."), nil
+	}
+	return mcp.NewToolResultText(exported), nil
+}
+
+// articleItem converts the add request into a Zotero journal article.
+func articleItem(params AddRequest) Item {
+	item := Item{
+		ItemType:         "journalArticle",
+		Title:            params.Title,
+		Creators:         make([]Creator, 0, len(params.Authors)),
+		PublicationTitle: params.Journal,
+		Date:             params.Year,
+		DOI:              params.DOI,
+	}
+	for _, author := range params.Authors {
+		lastName, firstName, found := strings.Cut(author, ",")
+		if !found {
+			item.Creators = append(item.Creators, Creator{CreatorType: "author", Name: author})
+			continue
+		}
+		item.Creators = append(item.Creators, Creator{
+			CreatorType: "author",
+			LastName:    strings.TrimSpace(lastName),
+			FirstName:   strings.TrimSpace(firstName),
+		})
+	}
+	if params.PMID != "" {
+		item.Extra = "PMID: " + params.PMID
+	}
+	if params.Collection != "" {
+		item.Collections = []string{params.Collection}
+	}
+	for _, tag := range params.Tags {
+		item.Tags = append(item.Tags, Tag{Tag: tag})
+	}
+	return item
+}
+
+// splitList splits a separated list, dropping blank entries.
+func splitList(list, separator string) []string {
+	var values []string
+	for _, value := range strings.Split(list, separator) {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+package zoterotool
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewZoteroTool(t *testing.T) {
+	t.Parallel()
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewZoteroTool(logger)
+	require.NoError(t, err)
+	assert.Equal(t, "zotero", tool.GetName())
+	assert.Contains(t, tool.GetSchema().Properties, "action")
+}
+
+func TestArticleItem(t *testing.T) {
+	t.Parallel()
+	item := articleItem(AddRequest{
+		Title:   "Dictyostelium chemotaxis",
+		Authors: []string{"Doe, Jane", "dictyBase Consortium"},
+		Year:    "2024",
+		PMID:    "12345",
+		Tags:    []string{"dicty"},
+	})
+	assert.Equal(t, "journalArticle", item.ItemType)
+	assert.Equal(t, []Creator{
+		{CreatorType: "author", LastName: "Doe", FirstName: "Jane"},
+		{CreatorType: "author", Name: "dictyBase Consortium"},
+	}, item.Creators)
+	assert.Equal(t, "PMID: 12345", item.Extra)
+	assert.Equal(t, []Tag{{Tag: "dicty"}}, item.Tags)
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestHandler_Add(t *testing.T) {
+	t.Setenv("ZOTERO_API_KEY", "secret")
+	t.Setenv("ZOTERO_LIBRARY_ID", "7")
+	t.Setenv("ZOTERO_LIBRARY_TYPE", "")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/users/7/items", r.URL.Path)
+		var items []Item
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&items))
+		assert.Equal(t, "A title", items[0].Title)
+		_, _ = io.WriteString(w, `{"successful":{"0":{"key":"ABCD1234"}}}`)
+	}))
+	t.Cleanup(server.Close)
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewZoteroTool(logger, WithClientOptions(WithBaseURL(server.URL)))
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{
+		"action":  "add",
+		"title":   "A title",
+		"authors": "Doe, Jane; Roe, Richard",
+	}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	assert.Contains(t, text.Text, "ABCD1234")
+
+	request.Params.Arguments = map[string]any{"action": "add"}
+	result, err = tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+	assert.Contains(t, toolErr.Message, "validation error")
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestHandler_MissingConfiguration(t *testing.T) {
+	t.Setenv("ZOTERO_API_KEY", "")
+	t.Setenv("ZOTERO_LIBRARY_ID", "")
+	logger := slog.New(slog.NewTextHandler(os.Stderr, nil))
+	tool, err := NewZoteroTool(logger)
+	require.NoError(t, err)
+
+	request := mcp.CallToolRequest{}
+	request.Params.Arguments = map[string]any{"action": "export"}
+	result, err := tool.Handler(context.Background(), request)
+	require.NoError(t, err)
+	assert.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	assert.Equal(t, toolerror.TypeConfiguration, toolErr.Type)
+	assert.Contains(t, toolErr.Message, "Zotero client")
+}
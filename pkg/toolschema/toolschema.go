@@ -0,0 +1,170 @@
+// Package toolschema describes the registered tools in a single JSON
+// document: their input schemas, MCP annotations and sample calls.
+// dictyBase frontends read it to generate forms for the HTTP gateway.
+package toolschema
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"maps"
+	"net/http"
+	"slices"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/toolversion"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// Document is the schema export of all tools.
+type Document struct {
+	Title   string `json:"title"`
+	Version string `json:"version"`
+	Tools   []Tool `json:"tools"`
+}
+
+// Tool describes a single tool.
+type Tool struct {
+	Name string `json:"name"`
+	// Version is the API version of the tool, see toolversion.
+	Version     int    `json:"version"`
+	Description string `json:"description"`
+	// Endpoint is the HTTP gateway route that runs the tool.
+	Endpoint    string             `json:"endpoint"`
+	InputSchema any                `json:"inputSchema"`
+	Annotations mcp.ToolAnnotation `json:"annotations"`
+	Examples    []registry.Example `json:"examples"`
+	// Deprecated lists the parameter names still accepted under their old
+	// name until the given release.
+	Deprecated []toolversion.Rename `json:"deprecated"`
+}
+
+// Params holds the inputs for building a Document.
+type Params struct {
+	Title   string
+	Version string
+	Tools   []registry.Tool
+}
+
+// Build describes the given tools, sorted by name.
+func Build(params Params) Document {
+	tools := make([]Tool, 0, len(params.Tools))
+	for _, tool := range params.Tools {
+		tools = append(tools, Describe(tool))
+	}
+	slices.SortFunc(tools, func(a, b Tool) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return Document{Title: params.Title, Version: params.Version, Tools: tools}
+}
+
+// Describe returns the schema of a tool. Tools that do not implement
+// registry.ExampleProvider get an empty list of examples.
+func Describe(tool registry.Tool) Tool {
+	definition := tool.GetTool()
+	var inputSchema any = definition.InputSchema
+	if definition.RawInputSchema != nil {
+		inputSchema = definition.RawInputSchema
+	}
+	examples := []registry.Example{}
+	if provider, ok := tool.(registry.ExampleProvider); ok {
+		examples = append(examples, provider.GetExamples()...)
+	}
+	api := toolversion.FromTool(definition)
+	deprecated := []toolversion.Rename{}
+	deprecated = append(deprecated, api.Renamed...)
+	return Tool{
+		Name:        definition.Name,
+		Version:     api.Version,
+		Description: definition.Description,
+		Endpoint:    "/tools/" + definition.Name,
+		InputSchema: inputSchema,
+		Annotations: tool.GetAnnotations(),
+		Examples:    examples,
+		Deprecated:  deprecated,
+	}
+}
+
+// inputSchema is the part of a tool's JSON schema CheckExamples reads.
+type inputSchema struct {
+	Properties map[string]struct {
+		Type string `json:"type"`
+		Enum []any  `json:"enum"`
+	} `json:"properties"`
+	Required []string `json:"required"`
+}
+
+// CheckExamples reports the examples of a tool whose arguments do not match
+// its input schema: missing required arguments, unknown arguments, values
+// of the wrong JSON type and values outside an enum.
+func CheckExamples(tool Tool) error {
+	data, err := json.Marshal(tool.InputSchema)
+	if err != nil {
+		return fmt.Errorf("%s: error encoding input schema: %w", tool.Name, err)
+	}
+	var schema inputSchema
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return fmt.Errorf("%s: error reading input schema: %w", tool.Name, err)
+	}
+	var problems []error
+	for index, example := range tool.Examples {
+		prefix := fmt.Sprintf("%s example %d", tool.Name, index+1)
+		for _, name := range schema.Required {
+			if _, ok := example.Arguments[name]; !ok {
+				problems = append(problems, fmt.Errorf("%s: missing required argument %q", prefix, name))
+			}
+		}
+		for _, name := range slices.Sorted(maps.Keys(example.Arguments)) {
+			property, ok := schema.Properties[name]
+			if !ok {
+				problems = append(problems, fmt.Errorf("%s: unknown argument %q", prefix, name))
+				continue
+			}
+			value := example.Arguments[name]
+			if property.Type != "" && jsonType(value) != property.Type &&
+				(property.Type != "number" || jsonType(value) != "integer") {
+				problems = append(problems, fmt.Errorf(
+					"%s: argument %q is %s, expected %s", prefix, name, jsonType(value), property.Type,
+				))
+			}
+			if len(property.Enum) > 0 && !slices.Contains(property.Enum, value) {
+				problems = append(problems, fmt.Errorf("%s: argument %q is not one of %v", prefix, name, property.Enum))
+			}
+		}
+	}
+	return errors.Join(problems...)
+}
+
+// jsonType returns the JSON schema type of a Go value.
+func jsonType(value any) string {
+	switch value.(type) {
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	case int, int64:
+		return "integer"
+	case float64:
+		return "number"
+	case []any, []string:
+		return "array"
+	case map[string]any:
+		return "object"
+	case nil:
+		return "null"
+	default:
+		return fmt.Sprintf("%T", value)
+	}
+}
+
+// Handler serves the document as JSON.
+func Handler(doc Document, logger *slog.Logger) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(doc); err != nil {
+			logger.Error("error writing tool schema", "error", err)
+		}
+	})
+}
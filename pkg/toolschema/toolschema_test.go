@@ -0,0 +1,111 @@
+package toolschema
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/tools/registry"
+	"github.com/dictybase/dcr-mcp/pkg/toolversion"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeTool is a tool with an id and a format argument.
+type fakeTool struct {
+	name     string
+	examples []registry.Example
+}
+
+func (f fakeTool) GetName() string { return f.name }
+
+func (f fakeTool) GetTool() mcp.Tool {
+	return mcp.NewTool(
+		f.name,
+		mcp.WithDescription("fake tool "+f.name),
+		mcp.WithReadOnlyHintAnnotation(true),
+		mcp.WithString("id", mcp.Required()),
+		mcp.WithString("format", mcp.Enum("markdown", "json")),
+		mcp.WithNumber("limit"),
+	)
+}
+
+func (f fakeTool) GetAnnotations() mcp.ToolAnnotation { return f.GetTool().Annotations }
+
+func (f fakeTool) Handler(
+	_ context.Context,
+	_ mcp.CallToolRequest,
+) (*mcp.CallToolResult, error) {
+	return mcp.NewToolResultText(f.name), nil
+}
+
+// exampleTool is a fakeTool that documents sample calls.
+type exampleTool struct{ fakeTool }
+
+func (e exampleTool) GetExamples() []registry.Example { return e.examples }
+
+func TestBuild(t *testing.T) {
+	t.Parallel()
+	doc := Build(Params{
+		Title:   "test",
+		Version: "v1.0.0",
+		Tools: []registry.Tool{
+			fakeTool{name: "zeta"},
+			exampleTool{fakeTool{name: "alpha", examples: []registry.Example{
+				{Description: "fetch one", Arguments: map[string]any{"id": "42"}},
+			}}},
+		},
+	})
+	require.Len(t, doc.Tools, 2)
+	alpha, zeta := doc.Tools[0], doc.Tools[1]
+	assert.Equal(t, "alpha", alpha.Name)
+	assert.Equal(t, toolversion.DefaultVersion, alpha.Version)
+	assert.Equal(t, "/tools/alpha", alpha.Endpoint)
+	assert.Len(t, alpha.Examples, 1)
+	assert.True(t, *alpha.Annotations.ReadOnlyHint)
+	assert.Equal(t, "zeta", zeta.Name)
+	assert.NotNil(t, zeta.Examples, "tools without examples list none rather than null")
+
+	data, err := json.Marshal(doc)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"examples":[]`)
+	assert.Contains(t, string(data), `"deprecated":[]`)
+	assert.Contains(t, string(data), `"required":["id"]`)
+}
+
+func TestCheckExamples(t *testing.T) {
+	t.Parallel()
+	valid := Describe(exampleTool{fakeTool{name: "valid", examples: []registry.Example{
+		{Arguments: map[string]any{"id": "42", "format": "json", "limit": 10}},
+	}}})
+	require.NoError(t, CheckExamples(valid))
+
+	invalid := Describe(exampleTool{fakeTool{name: "invalid", examples: []registry.Example{
+		{Arguments: map[string]any{"format": "xml", "limit": "ten", "page": 2}},
+	}}})
+	err := CheckExamples(invalid)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), `invalid example 1: missing required argument "id"`)
+	assert.Contains(t, err.Error(), `argument "format" is not one of [markdown json]`)
+	assert.Contains(t, err.Error(), `argument "limit" is string, expected number`)
+	assert.Contains(t, err.Error(), `unknown argument "page"`)
+}
+
+func TestHandler(t *testing.T) {
+	t.Parallel()
+	doc := Build(Params{Title: "test", Tools: []registry.Tool{fakeTool{name: "alpha"}}})
+	recorder := httptest.NewRecorder()
+	Handler(doc, slog.New(slog.NewTextHandler(io.Discard, nil))).
+		ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/schema.json", nil))
+
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Equal(t, "application/json", recorder.Header().Get("Content-Type"))
+	var got Document
+	require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &got))
+	assert.Equal(t, "alpha", got.Tools[0].Name)
+}
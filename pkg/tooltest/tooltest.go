@@ -0,0 +1,46 @@
+// Package tooltest calls tool handlers and reads their results for the
+// tests of the tools.
+package tooltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+	"github.com/stretchr/testify/require"
+)
+
+// Call invokes handler as a call of the named tool with args. It fails the
+// test when the handler returns an error rather than an error result.
+func Call(t testing.TB, handler server.ToolHandlerFunc, name string, args map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = args
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	return result
+}
+
+// Text returns the text of the first content of result, which must not be
+// an error result.
+func Text(t testing.TB, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.False(t, result.IsError, "unexpected error result")
+	require.NotEmpty(t, result.Content)
+	text, ok := mcp.AsTextContent(result.Content[0])
+	require.True(t, ok)
+	return text.Text
+}
+
+// ErrorCode returns the code of the tool error result carries, which must
+// be an error result.
+func ErrorCode(t testing.TB, result *mcp.CallToolResult) string {
+	t.Helper()
+	require.True(t, result.IsError)
+	toolErr, ok := result.StructuredContent.(*toolerror.Error)
+	require.True(t, ok)
+	return toolErr.Code
+}
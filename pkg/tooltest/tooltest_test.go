@@ -0,0 +1,26 @@
+package tooltest
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCall(t *testing.T) {
+	t.Parallel()
+	handler := func(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+		if request.GetString("fail", "") != "" {
+			return toolerror.Result(toolerror.New(toolerror.TypeInvalidInput, "BAD_INPUT", "bad input")), nil
+		}
+		return mcp.NewToolResultText(request.Params.Name + ": " + request.GetString("greeting", "")), nil
+	}
+
+	result := Call(t, handler, "echo", map[string]any{"greeting": "hello"})
+	assert.Equal(t, "echo: hello", Text(t, result))
+
+	result = Call(t, handler, "echo", map[string]any{"fail": "yes"})
+	assert.Equal(t, "BAD_INPUT", ErrorCode(t, result))
+}
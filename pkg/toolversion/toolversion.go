@@ -0,0 +1,194 @@
+// Package toolversion versions the tool APIs. A tool declares its API
+// version, and the parameters renamed in it, with the WithVersion option;
+// Middleware keeps the old parameter names working until their removal
+// release and warns the caller in the result, so schema changes do not
+// break existing clients.
+package toolversion
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/mark3labs/mcp-go/server"
+)
+
+// MetaKey is the _meta field of a tool holding its API.
+const MetaKey = "dictybase.org/api"
+
+// DeprecationsKey is the _meta field of a result holding the deprecation
+// warnings of the call.
+const DeprecationsKey = "dictybase.org/deprecations"
+
+// DefaultVersion is the API version of tools that declare none.
+const DefaultVersion = 1
+
+// Rename records a parameter renamed in a new API version.
+type Rename struct {
+	// Old is the parameter name clients may still send.
+	Old string `json:"old"`
+	// New is the parameter that replaced it.
+	New string `json:"new"`
+	// Removal is the server release that stops accepting Old.
+	Removal string `json:"removal"`
+}
+
+// API is the version of a tool's API and its deprecated parameters.
+type API struct {
+	Version int      `json:"version"`
+	Renamed []Rename `json:"renamed,omitempty"`
+}
+
+var (
+	mu   sync.RWMutex
+	apis = make(map[string]API)
+)
+
+// WithVersion declares the API version of a tool and the parameters it
+// renamed. It must follow the parameter options, because every old name is
+// added to the input schema as a deprecated copy of its new parameter, so
+// clients validating their arguments keep working.
+func WithVersion(version int, renamed ...Rename) mcp.ToolOption {
+	return func(tool *mcp.Tool) {
+		api := API{Version: version, Renamed: renamed}
+		if tool.Meta == nil {
+			tool.Meta = &mcp.Meta{}
+		}
+		if tool.Meta.AdditionalFields == nil {
+			tool.Meta.AdditionalFields = make(map[string]any)
+		}
+		tool.Meta.AdditionalFields[MetaKey] = api
+		if tool.InputSchema.Properties == nil {
+			tool.InputSchema.Properties = make(map[string]any)
+		}
+		for _, rename := range renamed {
+			tool.InputSchema.Properties[rename.Old] = deprecatedProperty(tool.InputSchema.Properties[rename.New], rename)
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		apis[tool.Name] = api
+	}
+}
+
+// deprecatedProperty returns the schema of an old parameter name: the
+// schema of its new parameter marked deprecated.
+func deprecatedProperty(property any, rename Rename) map[string]any {
+	deprecated := make(map[string]any)
+	if schema, ok := property.(map[string]any); ok {
+		maps.Copy(deprecated, schema)
+	}
+	description := fmt.Sprintf("Deprecated, use %s; removed in %s", rename.New, rename.Removal)
+	if original, ok := deprecated["description"].(string); ok && original != "" {
+		description += ". " + original
+	}
+	deprecated["description"] = description
+	deprecated["deprecated"] = true
+	return deprecated
+}
+
+// FromTool returns the API a tool declared, or DefaultVersion without
+// renamed parameters.
+func FromTool(tool mcp.Tool) API {
+	if tool.Meta == nil {
+		return API{Version: DefaultVersion}
+	}
+	switch value := tool.Meta.AdditionalFields[MetaKey].(type) {
+	case API:
+		return value
+	case nil:
+		return API{Version: DefaultVersion}
+	default:
+		// The tool definition was decoded from JSON.
+		data, err := json.Marshal(value)
+		if err != nil {
+			return API{Version: DefaultVersion}
+		}
+		var api API
+		if err := json.Unmarshal(data, &api); err != nil || api.Version == 0 {
+			return API{Version: DefaultVersion}
+		}
+		return api
+	}
+}
+
+// lookup returns the API declared by the named tool.
+func lookup(name string) (API, bool) {
+	mu.RLock()
+	defer mu.RUnlock()
+	api, ok := apis[name]
+	return api, ok
+}
+
+// Middleware returns a middleware that moves arguments sent under renamed
+// parameter names to their new names and appends a deprecation warning to
+// the result, both as text the model reads and in the result's _meta.
+func Middleware(logger *slog.Logger) server.ToolHandlerMiddleware {
+	return func(next server.ToolHandlerFunc) server.ToolHandlerFunc {
+		return func(ctx context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+			api, ok := lookup(request.Params.Name)
+			if !ok || len(api.Renamed) == 0 {
+				return next(ctx, request)
+			}
+			arguments, warnings := upgrade(request.Params.Name, request.GetArguments(), api.Renamed)
+			if len(warnings) == 0 {
+				return next(ctx, request)
+			}
+			logger.Warn("deprecated parameters used", "tool", request.Params.Name, "warnings", warnings)
+			request.Params.Arguments = arguments
+			result, err := next(ctx, request)
+			if err != nil || result == nil {
+				return result, err
+			}
+			return attach(result, warnings), nil
+		}
+	}
+}
+
+// upgrade returns a copy of arguments with the renamed parameters under
+// their new names, and a warning for each old name used. A value sent under
+// both names keeps the one of the new name.
+func upgrade(tool string, arguments map[string]any, renamed []Rename) (map[string]any, []string) {
+	upgraded := maps.Clone(arguments)
+	var warnings []string
+	for _, rename := range renamed {
+		value, ok := upgraded[rename.Old]
+		if !ok {
+			continue
+		}
+		delete(upgraded, rename.Old)
+		if _, set := upgraded[rename.New]; set {
+			warnings = append(warnings, fmt.Sprintf(
+				"%s: parameter %q is deprecated and was ignored because %q is also set; it is removed in %s",
+				tool, rename.Old, rename.New, rename.Removal,
+			))
+			continue
+		}
+		upgraded[rename.New] = value
+		warnings = append(warnings, fmt.Sprintf(
+			"%s: parameter %q is deprecated, use %q; it is removed in %s",
+			tool, rename.Old, rename.New, rename.Removal,
+		))
+	}
+	return upgraded, warnings
+}
+
+// attach adds the warnings to the result's content and _meta field.
+func attach(result *mcp.CallToolResult, warnings []string) *mcp.CallToolResult {
+	result.Content = append(
+		result.Content,
+		mcp.NewTextContent("Deprecation warning: "+strings.Join(warnings, "\n")),
+	)
+	if result.Meta == nil {
+		result.Meta = &mcp.Meta{}
+	}
+	if result.Meta.AdditionalFields == nil {
+		result.Meta.AdditionalFields = make(map[string]any)
+	}
+	result.Meta.AdditionalFields[DeprecationsKey] = warnings
+	return result
+}
@@ -0,0 +1,97 @@
+package toolversion
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTool declares a tool that renamed provider to source.
+func newTestTool(name string) mcp.Tool {
+	return mcp.NewTool(
+		name,
+		mcp.WithString("id", mcp.Required()),
+		mcp.WithString("source", mcp.Description("Where to look"), mcp.Enum("pubmed", "europepmc")),
+		WithVersion(2, Rename{Old: "provider", New: "source", Removal: "v2.0.0"}),
+	)
+}
+
+// echoHandler returns the arguments it was called with.
+func echoHandler(_ context.Context, request mcp.CallToolRequest) (*mcp.CallToolResult, error) {
+	data, err := json.Marshal(request.GetArguments())
+	if err != nil {
+		return nil, err
+	}
+	return mcp.NewToolResultText(string(data)), nil
+}
+
+// call runs the middleware around echoHandler.
+func call(t *testing.T, name string, arguments map[string]any) *mcp.CallToolResult {
+	t.Helper()
+	handler := Middleware(slog.New(slog.NewTextHandler(io.Discard, nil)))(echoHandler)
+	request := mcp.CallToolRequest{}
+	request.Params.Name = name
+	request.Params.Arguments = arguments
+	result, err := handler(context.Background(), request)
+	require.NoError(t, err)
+	return result
+}
+
+func TestWithVersion(t *testing.T) {
+	t.Parallel()
+	tool := newTestTool("toolversion-schema")
+
+	api := FromTool(tool)
+	assert.Equal(t, 2, api.Version)
+	assert.Equal(t, []Rename{{Old: "provider", New: "source", Removal: "v2.0.0"}}, api.Renamed)
+	provider, ok := tool.InputSchema.Properties["provider"].(map[string]any)
+	require.True(t, ok)
+	assert.Equal(t, true, provider["deprecated"])
+	assert.Equal(t, "Deprecated, use source; removed in v2.0.0. Where to look", provider["description"])
+	assert.Equal(t, []string{"pubmed", "europepmc"}, provider["enum"])
+	source := tool.InputSchema.Properties["source"].(map[string]any)
+	assert.NotContains(t, source, "deprecated", "the new parameter is left alone")
+
+	decoded := mcp.Tool{Meta: mcp.NewMetaFromMap(map[string]any{
+		MetaKey: map[string]any{"version": 3.0, "renamed": []any{map[string]any{"old": "a", "new": "b"}}},
+	})}
+	assert.Equal(t, API{Version: 3, Renamed: []Rename{{Old: "a", New: "b"}}}, FromTool(decoded))
+	assert.Equal(t, API{Version: DefaultVersion}, FromTool(mcp.NewTool("unversioned")))
+}
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	newTestTool("toolversion-middleware")
+
+	t.Run("renamed parameter", func(t *testing.T) {
+		t.Parallel()
+		result := call(t, "toolversion-middleware", map[string]any{"id": "1", "provider": "pubmed"})
+		require.Len(t, result.Content, 2)
+		assert.JSONEq(t, `{"id": "1", "source": "pubmed"}`, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[1].(mcp.TextContent).Text, `parameter "provider" is deprecated, use "source"`)
+		assert.Len(t, result.Meta.AdditionalFields[DeprecationsKey], 1)
+	})
+	t.Run("both names", func(t *testing.T) {
+		t.Parallel()
+		result := call(t, "toolversion-middleware", map[string]any{"id": "1", "provider": "pubmed", "source": "europepmc"})
+		assert.JSONEq(t, `{"id": "1", "source": "europepmc"}`, result.Content[0].(mcp.TextContent).Text)
+		assert.Contains(t, result.Content[1].(mcp.TextContent).Text, "was ignored")
+	})
+	t.Run("current parameters", func(t *testing.T) {
+		t.Parallel()
+		result := call(t, "toolversion-middleware", map[string]any{"id": "1", "source": "pubmed"})
+		assert.Len(t, result.Content, 1)
+		assert.Nil(t, result.Meta)
+	})
+	t.Run("unversioned tool", func(t *testing.T) {
+		t.Parallel()
+		result := call(t, "toolversion-unknown", map[string]any{"provider": "pubmed"})
+		assert.JSONEq(t, `{"provider": "pubmed"}`, result.Content[0].(mcp.TextContent).Text)
+	})
+}
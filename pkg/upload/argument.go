@@ -0,0 +1,51 @@
+package upload
+
+import (
+	"errors"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+// IDArgument is the tool argument naming a completed upload.
+const IDArgument = "upload_id"
+
+// TextArgument returns the text a tool call passes either inline in the
+// named argument or as a completed upload referenced by upload_id. Failures
+// are classified tool errors.
+func TextArgument(store *Store, request mcp.CallToolRequest, argument string) (string, error) {
+	return TextArgumentFrom(store, request, argument, IDArgument)
+}
+
+// TextArgumentFrom is TextArgument with the upload handle read from
+// idArgument, for tools taking more than one text.
+func TextArgumentFrom(store *Store, request mcp.CallToolRequest, argument, idArgument string) (string, error) {
+	id := request.GetString(idArgument, "")
+	if id == "" {
+		text, ok := request.GetArguments()[argument].(string)
+		if !ok {
+			return "", toolerror.New(
+				toolerror.TypeInvalidInput,
+				"MISSING_PARAMETER",
+				"missing required parameter: "+argument+" or "+idArgument,
+			)
+		}
+		return text, nil
+	}
+	if store == nil {
+		return "", toolerror.New(
+			toolerror.TypeConfiguration,
+			"UPLOADS_UNAVAILABLE",
+			"uploads are not enabled on this server",
+		)
+	}
+	text, err := store.Text(id)
+	switch {
+	case err == nil:
+		return text, nil
+	case errors.Is(err, ErrNotFound):
+		return "", toolerror.Wrap(toolerror.TypeNotFound, "UPLOAD_NOT_FOUND", err, "invalid "+idArgument)
+	default:
+		return "", toolerror.Wrap(toolerror.TypeInvalidInput, "INVALID_UPLOAD", err, "invalid "+idArgument)
+	}
+}
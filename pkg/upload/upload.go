@@ -0,0 +1,380 @@
+// Package upload assembles large tool inputs, such as long markdown
+// documents, PDFs or CSV files, from chunks sent over several tool calls.
+// A completed upload is referenced by its handle in place of an inline
+// argument, which keeps each call under the client's argument size limit.
+package upload
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/dictybase/dcr-mcp/pkg/filecheck"
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+const (
+	defaultMaxSize    = 50 << 20
+	defaultMaxUploads = 20
+	defaultTTL        = time.Hour
+)
+
+// DefaultPolicy accepts the documents, images and data files the tools
+// work with.
+var DefaultPolicy = filecheck.Combine(
+	0,
+	filecheck.Text,
+	filecheck.PDF,
+	filecheck.Images,
+	filecheck.Data,
+)
+
+// Errors returned by the store; wrapped errors carry the upload ID.
+var (
+	ErrNotFound    = errors.New("upload not found")
+	ErrTooLarge    = errors.New("upload exceeds the size limit")
+	ErrOutOfOrder  = errors.New("chunk out of order")
+	ErrIncomplete  = errors.New("upload is not complete")
+	ErrCompleted   = errors.New("upload is already complete")
+	ErrChecksum    = errors.New("checksum mismatch")
+	ErrTooManyOpen = errors.New("too many uploads in progress")
+	ErrNotText     = errors.New("upload is not UTF-8 text")
+)
+
+// BeginParams holds the parameters for starting an upload.
+type BeginParams struct {
+	// Name is an optional file name, e.g. report.md.
+	Name        string
+	ContentType string `validate:"required"`
+}
+
+// AppendParams holds one chunk of an upload.
+type AppendParams struct {
+	ID string `validate:"required"`
+	// Index is the 0-based position of the chunk. Chunks must arrive in
+	// order; resending an already received chunk is a no-op, so a client
+	// can retry after a lost response.
+	Index int `validate:"gte=0"`
+	Data  []byte
+}
+
+// Info describes an upload.
+type Info struct {
+	ID          string    `json:"id"`
+	Name        string    `json:"name,omitempty"`
+	ContentType string    `json:"content_type"`
+	Size        int       `json:"size"`
+	Chunks      int       `json:"chunks"`
+	Complete    bool      `json:"complete"`
+	SHA256      string    `json:"sha256,omitempty"`
+	ExpiresAt   time.Time `json:"expires_at"`
+}
+
+// Store keeps uploads in memory until they expire.
+type Store struct {
+	mu      sync.Mutex
+	uploads map[string]*entry
+	config  *Config
+}
+
+// entry is an upload and its data.
+type entry struct {
+	info Info
+	data []byte
+}
+
+// Option represents a configuration option for Store.
+type Option func(*Config)
+
+// Config holds the configuration for the store.
+type Config struct {
+	maxSize    int
+	maxUploads int
+	ttl        time.Duration
+	policy     filecheck.Policy
+	now        func() time.Time
+	logger     *slog.Logger
+}
+
+// WithMaxSize sets the largest upload in bytes.
+func WithMaxSize(size int) Option {
+	return func(c *Config) {
+		c.maxSize = size
+	}
+}
+
+// WithMaxUploads sets how many uploads may be kept at once.
+func WithMaxUploads(count int) Option {
+	return func(c *Config) {
+		c.maxUploads = count
+	}
+}
+
+// WithTTL sets how long an upload is kept after its last chunk.
+func WithTTL(ttl time.Duration) Option {
+	return func(c *Config) {
+		c.ttl = ttl
+	}
+}
+
+// WithPolicy sets the content types accepted by the store. Declared types
+// are checked when an upload begins and the content when it completes.
+func WithPolicy(policy filecheck.Policy) Option {
+	return func(c *Config) {
+		c.policy = policy
+	}
+}
+
+// WithLogger sets the logger for the store.
+func WithLogger(logger *slog.Logger) Option {
+	return func(c *Config) {
+		c.logger = logger
+	}
+}
+
+// NewStore creates an empty upload store.
+func NewStore(opts ...Option) *Store {
+	cfg := &Config{
+		maxSize:    defaultMaxSize,
+		maxUploads: defaultMaxUploads,
+		ttl:        defaultTTL,
+		policy:     DefaultPolicy,
+		now:        time.Now,
+		logger:     slog.Default(),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Store{uploads: make(map[string]*entry), config: cfg}
+}
+
+// Begin starts an upload and returns its handle.
+func (s *Store) Begin(params BeginParams) (Info, error) {
+	if err := validate.Struct(params); err != nil {
+		return Info{}, fmt.Errorf("invalid upload parameters: %w", err)
+	}
+	if err := s.config.policy.Allows(params.ContentType); err != nil {
+		return Info{}, err
+	}
+	id, err := newID()
+	if err != nil {
+		return Info{}, err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	if len(s.uploads) >= s.config.maxUploads {
+		return Info{}, ErrTooManyOpen
+	}
+	upload := &entry{info: Info{
+		ID:          id,
+		Name:        params.Name,
+		ContentType: params.ContentType,
+		ExpiresAt:   s.config.now().Add(s.config.ttl),
+	}}
+	s.uploads[id] = upload
+	s.config.logger.Debug("upload started", "upload_id", id, "content_type", params.ContentType)
+	return upload.info, nil
+}
+
+// Append adds a chunk to an upload.
+func (s *Store) Append(params AppendParams) (Info, error) {
+	if err := validate.Struct(params); err != nil {
+		return Info{}, fmt.Errorf("invalid chunk parameters: %w", err)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, err := s.lookup(params.ID)
+	if err != nil {
+		return Info{}, err
+	}
+	switch {
+	case upload.info.Complete:
+		return Info{}, fmt.Errorf("upload %s: %w", params.ID, ErrCompleted)
+	case params.Index < upload.info.Chunks:
+		return upload.info, nil
+	case params.Index > upload.info.Chunks:
+		return Info{}, fmt.Errorf(
+			"upload %s: %w, expected chunk %d but got %d",
+			params.ID, ErrOutOfOrder, upload.info.Chunks, params.Index,
+		)
+	}
+	if len(upload.data)+len(params.Data) > s.config.maxSize {
+		delete(s.uploads, params.ID)
+		return Info{}, fmt.Errorf(
+			"upload %s: %w of %d bytes and was discarded",
+			params.ID, ErrTooLarge, s.config.maxSize,
+		)
+	}
+	upload.data = append(upload.data, params.Data...)
+	upload.info.Chunks++
+	upload.info.Size = len(upload.data)
+	upload.info.ExpiresAt = s.config.now().Add(s.config.ttl)
+	return upload.info, nil
+}
+
+// Complete marks an upload as finished. When checksum is not empty it must
+// match the hex-encoded SHA-256 hash of the assembled data. Content that
+// does not match its declared type is rejected with a filecheck error and
+// the upload is discarded.
+func (s *Store) Complete(id, checksum string) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, err := s.lookup(id)
+	if err != nil {
+		return Info{}, err
+	}
+	sum := sha256.Sum256(upload.data)
+	digest := hex.EncodeToString(sum[:])
+	if checksum != "" && checksum != digest {
+		return Info{}, fmt.Errorf("upload %s: %w, assembled data hashes to %s", id, ErrChecksum, digest)
+	}
+	if _, err := s.config.policy.Check(upload.data, upload.info.ContentType); err != nil {
+		delete(s.uploads, id)
+		s.config.logger.Warn("upload rejected", "upload_id", id, "error", err)
+		return Info{}, err
+	}
+	upload.info.Complete = true
+	upload.info.SHA256 = digest
+	upload.info.ExpiresAt = s.config.now().Add(s.config.ttl)
+	s.config.logger.Debug("upload completed", "upload_id", id, "size", upload.info.Size)
+	return upload.info, nil
+}
+
+// Status returns the state of an upload.
+func (s *Store) Status(id string) (Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, err := s.lookup(id)
+	if err != nil {
+		return Info{}, err
+	}
+	return upload.info, nil
+}
+
+// Get returns the data of a completed upload.
+func (s *Store) Get(id string) ([]byte, Info, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	upload, err := s.lookup(id)
+	if err != nil {
+		return nil, Info{}, err
+	}
+	if !upload.info.Complete {
+		return nil, Info{}, fmt.Errorf("upload %s: %w", id, ErrIncomplete)
+	}
+	return upload.data, upload.info, nil
+}
+
+// Discard removes an upload.
+func (s *Store) Discard(id string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := s.lookup(id); err != nil {
+		return err
+	}
+	delete(s.uploads, id)
+	return nil
+}
+
+// lookup returns a live upload. The caller must hold s.mu.
+func (s *Store) lookup(id string) (*entry, error) {
+	s.expire()
+	upload, ok := s.uploads[id]
+	if !ok {
+		return nil, fmt.Errorf("upload %s: %w", id, ErrNotFound)
+	}
+	return upload, nil
+}
+
+// expire drops uploads past their expiry. The caller must hold s.mu.
+func (s *Store) expire() {
+	now := s.config.now()
+	for id, upload := range s.uploads {
+		if now.After(upload.info.ExpiresAt) {
+			delete(s.uploads, id)
+			s.config.logger.Debug("upload expired", "upload_id", id)
+		}
+	}
+}
+
+// newID returns a random upload handle.
+func newID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("error generating upload ID: %w", err)
+	}
+	return "upl_" + hex.EncodeToString(buf), nil
+}
+
+// Text returns the data of a completed upload of a textual content type
+// as text.
+func (s *Store) Text(id string) (string, error) {
+	data, info, err := s.Get(id)
+	if err != nil {
+		return "", err
+	}
+	if !filecheck.IsTextual(info.ContentType) || !utf8.Valid(data) {
+		return "", fmt.Errorf("upload %s: %w", id, ErrNotText)
+	}
+	return string(data), nil
+}
+
+// Record is an upload together with its data, as kept in a session
+// snapshot.
+type Record struct {
+	Info
+	Data []byte `json:"data"`
+}
+
+// Export returns the live uploads, complete or not, ordered by ID.
+func (s *Store) Export() []Record {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	records := make([]Record, 0, len(s.uploads))
+	for _, upload := range s.uploads {
+		records = append(records, Record{Info: upload.info, Data: slices.Clone(upload.data)})
+	}
+	slices.SortFunc(records, func(a, b Record) int {
+		return strings.Compare(a.ID, b.ID)
+	})
+	return records
+}
+
+// Import adds uploads exported from another store under their original
+// handles, replacing uploads with the same handle. Their expiry restarts,
+// so handles from an old snapshot stay usable for the store's TTL. Uploads
+// larger than the store accepts, or beyond how many it keeps, are skipped.
+func (s *Store) Import(records []Record) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.expire()
+	expiresAt := s.config.now().Add(s.config.ttl)
+	skipped := 0
+	for _, record := range records {
+		_, replaces := s.uploads[record.ID]
+		if len(record.Data) > s.config.maxSize || (!replaces && len(s.uploads) >= s.config.maxUploads) {
+			skipped++
+			continue
+		}
+		info := record.Info
+		info.ExpiresAt = expiresAt
+		s.uploads[info.ID] = &entry{info: info, data: slices.Clone(record.Data)}
+	}
+	if skipped > 0 {
+		s.config.logger.Warn("uploads of snapshot skipped over the store's limits", "skipped", skipped)
+	}
+	s.config.logger.Debug("uploads imported", "uploads", len(records)-skipped)
+}
@@ -0,0 +1,144 @@
+package upload
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/filecheck"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStore_RoundTrip(t *testing.T) {
+	t.Parallel()
+	store := NewStore()
+	info, err := store.Begin(BeginParams{Name: "report.md", ContentType: "text/markdown"})
+	require.NoError(t, err)
+	assert.Regexp(t, `^upl_[0-9a-f]{24}$`, info.ID)
+
+	for index, chunk := range []string{"# Report\n", "Body text.\n"} {
+		_, err := store.Append(AppendParams{ID: info.ID, Index: index, Data: []byte(chunk)})
+		require.NoError(t, err)
+	}
+	// A retried chunk is ignored.
+	info, err = store.Append(AppendParams{ID: info.ID, Index: 1, Data: []byte("Body text.\n")})
+	require.NoError(t, err)
+	assert.Equal(t, 2, info.Chunks)
+
+	_, err = store.Text(info.ID)
+	require.ErrorIs(t, err, ErrIncomplete)
+
+	sum := sha256.Sum256([]byte("# Report\nBody text.\n"))
+	info, err = store.Complete(info.ID, hex.EncodeToString(sum[:]))
+	require.NoError(t, err)
+	assert.True(t, info.Complete)
+	assert.Equal(t, 20, info.Size)
+
+	text, err := store.Text(info.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "# Report\nBody text.\n", text)
+
+	_, err = store.Append(AppendParams{ID: info.ID, Index: 2, Data: []byte("more")})
+	require.ErrorIs(t, err, ErrCompleted)
+	require.NoError(t, store.Discard(info.ID))
+	_, err = store.Status(info.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Errors(t *testing.T) {
+	t.Parallel()
+	store := NewStore(WithMaxSize(8), WithMaxUploads(2))
+	first, err := store.Begin(BeginParams{ContentType: "text/csv"})
+	require.NoError(t, err)
+
+	_, err = store.Append(AppendParams{ID: first.ID, Index: 1, Data: []byte("a")})
+	require.ErrorIs(t, err, ErrOutOfOrder)
+	_, err = store.Complete(first.ID, "0000")
+	require.ErrorIs(t, err, ErrChecksum)
+	_, err = store.Append(AppendParams{ID: first.ID, Index: 0, Data: []byte("123456789")})
+	require.ErrorIs(t, err, ErrTooLarge)
+	_, err = store.Status(first.ID)
+	require.ErrorIs(t, err, ErrNotFound, "oversized uploads are discarded")
+
+	binary, err := store.Begin(BeginParams{ContentType: "application/pdf"})
+	require.NoError(t, err)
+	_, err = store.Append(AppendParams{ID: binary.ID, Index: 0, Data: []byte("%PDF-\xff\xfe")})
+	require.NoError(t, err)
+	_, err = store.Complete(binary.ID, "")
+	require.NoError(t, err)
+	_, err = store.Text(binary.ID)
+	require.ErrorIs(t, err, ErrNotText)
+
+	_, err = store.Begin(BeginParams{ContentType: "text/plain"})
+	require.NoError(t, err)
+	_, err = store.Begin(BeginParams{ContentType: "text/plain"})
+	require.ErrorIs(t, err, ErrTooManyOpen)
+}
+
+func TestStore_Expiry(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := NewStore(WithTTL(time.Minute))
+	store.config.now = func() time.Time { return now }
+	info, err := store.Begin(BeginParams{ContentType: "text/markdown"})
+	require.NoError(t, err)
+
+	now = now.Add(30 * time.Second)
+	_, err = store.Append(AppendParams{ID: info.ID, Index: 0, Data: []byte("x")})
+	require.NoError(t, err, "appending extends the expiry")
+	now = now.Add(45 * time.Second)
+	_, err = store.Status(info.ID)
+	require.NoError(t, err)
+
+	now = now.Add(time.Minute)
+	_, err = store.Status(info.ID)
+	require.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStore_Policy(t *testing.T) {
+	t.Parallel()
+	store := NewStore()
+	_, err := store.Begin(BeginParams{ContentType: "application/x-msdownload"})
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, filecheck.CodeUnsupported, toolErr.Code)
+
+	info, err := store.Begin(BeginParams{Name: "figure.pdf", ContentType: "application/pdf"})
+	require.NoError(t, err)
+	_, err = store.Append(AppendParams{ID: info.ID, Index: 0, Data: []byte("# Not a PDF")})
+	require.NoError(t, err)
+	_, err = store.Complete(info.ID, "")
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, filecheck.CodeMismatch, toolErr.Code)
+	assert.Equal(t, "text/plain", toolErr.Details["detected"])
+	_, err = store.Status(info.ID)
+	require.ErrorIs(t, err, ErrNotFound, "rejected uploads are discarded")
+
+	custom := NewStore(WithPolicy(filecheck.Data))
+	_, err = custom.Begin(BeginParams{ContentType: "text/markdown"})
+	require.ErrorAs(t, err, &toolErr)
+	_, err = custom.Begin(BeginParams{ContentType: "text/csv; charset=utf-8"})
+	require.NoError(t, err)
+}
+
+func TestStore_ImportLimits(t *testing.T) {
+	t.Parallel()
+	store := NewStore(WithMaxSize(8), WithMaxUploads(2))
+	first, err := store.Begin(BeginParams{ContentType: "text/csv"})
+	require.NoError(t, err)
+
+	store.Import([]Record{
+		{Info: Info{ID: first.ID, ContentType: "text/csv"}, Data: []byte("a,b")},
+		{Info: Info{ID: "upl_large", ContentType: "text/csv"}, Data: []byte("too large")},
+		{Info: Info{ID: "upl_second", ContentType: "text/csv"}, Data: []byte("c,d")},
+		{Info: Info{ID: "upl_third", ContentType: "text/csv"}, Data: []byte("e,f")},
+	})
+	ids := make([]string, 0, 2)
+	for _, record := range store.Export() {
+		ids = append(ids, record.ID)
+	}
+	assert.ElementsMatch(t, []string{first.ID, "upl_second"}, ids, "imports replace uploads but stay within the limits")
+}
@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Initialize validator.
+var validate = validator.New()
+
+// Config describes how inbound webhook events map to tool runs.
+type Config struct {
+	// Secret is the shared secret used to verify X-Hub-Signature-256
+	// headers. When empty, signatures are not checked.
+	Secret string `json:"secret"`
+	// Rules lists the tool runs triggered by each event.
+	Rules []Rule `json:"rules" validate:"required,min=1,dive"`
+}
+
+// Rule maps a single event type to a tool invocation.
+type Rule struct {
+	// Event is the GitHub event name, e.g. "push" or "release".
+	Event string `json:"event"      validate:"required"`
+	// Action optionally restricts the rule to a payload action such as
+	// "published" for release events.
+	Action string `json:"action"`
+	// Tool is the name of the registered tool to run.
+	Tool string `json:"tool"       validate:"required"`
+	// Arguments are passed to the tool. String values may contain
+	// placeholders such as {{repo_url}} that are filled from the event.
+	Arguments map[string]any `json:"arguments"`
+	// DeliverTo is an optional URL that receives the tool result as a
+	// JSON POST request.
+	DeliverTo string `json:"deliver_to" validate:"omitempty,url"`
+}
+
+// LoadConfig reads and validates a webhook configuration file. The secret
+// may be supplied through the DCR_WEBHOOK_SECRET environment variable
+// instead of the file.
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read webhook config %s: %w", path, err)
+	}
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse webhook config %s: %w", path, err)
+	}
+	if secret := os.Getenv("DCR_WEBHOOK_SECRET"); secret != "" {
+		cfg.Secret = secret
+	}
+	if err := validate.Struct(cfg); err != nil {
+		return nil, fmt.Errorf("invalid webhook config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// matchingRules returns the rules that apply to the given event and action.
+func (c *Config) matchingRules(event, action string) []Rule {
+	var rules []Rule
+	for _, rule := range c.Rules {
+		if rule.Event != event {
+			continue
+		}
+		if rule.Action != "" && rule.Action != action {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
@@ -0,0 +1,84 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+)
+
+// Delivery is the outcome of a webhook-triggered tool run.
+type Delivery struct {
+	Event  string `json:"event"`
+	Action string `json:"action,omitempty"`
+	Tool   string `json:"tool"`
+	Result string `json:"result,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Deliverer sends the outcome of a tool run to its destination.
+type Deliverer interface {
+	Deliver(ctx context.Context, target string, delivery Delivery) error
+}
+
+// HTTPDeliverer posts deliveries as JSON to the rule's target URL and logs
+// deliveries that have no target.
+type HTTPDeliverer struct {
+	client *http.Client
+	logger *slog.Logger
+}
+
+// NewHTTPDeliverer creates a new HTTPDeliverer.
+func NewHTTPDeliverer(logger *slog.Logger) *HTTPDeliverer {
+	return &HTTPDeliverer{
+		client: &http.Client{Timeout: 30 * time.Second},
+		logger: logger,
+	}
+}
+
+// Deliver implements Deliverer.
+func (d *HTTPDeliverer) Deliver(
+	ctx context.Context,
+	target string,
+	delivery Delivery,
+) error {
+	if target == "" {
+		d.logger.Info(
+			"webhook tool run finished",
+			"event", delivery.Event,
+			"tool", delivery.Tool,
+			"error", delivery.Error,
+		)
+		return nil
+	}
+	body, err := json.Marshal(delivery)
+	if err != nil {
+		return fmt.Errorf("failed to encode delivery: %w", err)
+	}
+	req, err := http.NewRequestWithContext(
+		ctx,
+		http.MethodPost,
+		target,
+		bytes.NewReader(body),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create delivery request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to deliver result to %s: %w", target, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= http.StatusBadRequest {
+		return fmt.Errorf(
+			"delivery to %s failed with status %d",
+			target,
+			resp.StatusCode,
+		)
+	}
+	return nil
+}
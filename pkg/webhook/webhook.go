@@ -0,0 +1,260 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+)
+
+const (
+	// maxPayloadSize limits the size of an inbound webhook payload.
+	maxPayloadSize = 5 << 20
+	// deliveryTimeout limits the delivery of a run's outcome, which starts
+	// after the run so that runs that time out are still delivered.
+	deliveryTimeout = 30 * time.Second
+)
+
+// ToolInvoker runs a registered tool by name.
+type ToolInvoker interface {
+	CallTool(
+		ctx context.Context,
+		name string,
+		args map[string]any,
+	) (*mcp.CallToolResult, error)
+}
+
+// Handler accepts GitHub webhook events and triggers the configured tool
+// runs in the background. Results are handed to a Deliverer.
+type Handler struct {
+	config    *Config
+	invoker   ToolInvoker
+	deliverer Deliverer
+	logger    *slog.Logger
+	timeout   time.Duration
+	wg        sync.WaitGroup
+}
+
+// HandlerOption defines a functional option for configuring Handler.
+type HandlerOption func(*Handler)
+
+// WithLogger sets the logger for the handler.
+func WithLogger(logger *slog.Logger) HandlerOption {
+	return func(h *Handler) {
+		h.logger = logger
+	}
+}
+
+// WithDeliverer sets the deliverer that receives tool results.
+func WithDeliverer(deliverer Deliverer) HandlerOption {
+	return func(h *Handler) {
+		h.deliverer = deliverer
+	}
+}
+
+// WithRunTimeout limits how long a single triggered tool run may take.
+func WithRunTimeout(timeout time.Duration) HandlerOption {
+	return func(h *Handler) {
+		h.timeout = timeout
+	}
+}
+
+// NewHandler creates a webhook handler for the given configuration.
+func NewHandler(
+	config *Config,
+	invoker ToolInvoker,
+	opts ...HandlerOption,
+) *Handler {
+	handler := &Handler{
+		config:  config,
+		invoker: invoker,
+		logger:  slog.Default(),
+		timeout: 10 * time.Minute,
+	}
+	for _, opt := range opts {
+		opt(handler)
+	}
+	if handler.deliverer == nil {
+		handler.deliverer = NewHTTPDeliverer(handler.logger)
+	}
+	return handler
+}
+
+// githubEvent holds the payload fields used to fill argument placeholders.
+type githubEvent struct {
+	Action     string `json:"action"`
+	Ref        string `json:"ref"`
+	Repository struct {
+		FullName      string `json:"full_name"`
+		CloneURL      string `json:"clone_url"`
+		DefaultBranch string `json:"default_branch"`
+	} `json:"repository"`
+	Release struct {
+		TagName string `json:"tag_name"`
+	} `json:"release"`
+	Sender struct {
+		Login string `json:"login"`
+	} `json:"sender"`
+}
+
+// placeholders returns the values available to rule arguments.
+func (e githubEvent) placeholders() map[string]string {
+	branch := strings.TrimPrefix(e.Ref, "refs/heads/")
+	if branch == e.Ref {
+		branch = e.Repository.DefaultBranch
+	}
+	return map[string]string{
+		"action":         e.Action,
+		"ref":            e.Ref,
+		"branch":         branch,
+		"repo_url":       e.Repository.CloneURL,
+		"repo_name":      e.Repository.FullName,
+		"default_branch": e.Repository.DefaultBranch,
+		"tag":            e.Release.TagName,
+		"sender":         e.Sender.Login,
+	}
+}
+
+// ServeHTTP implements http.Handler.
+func (h *Handler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		http.Error(w, "failed to read payload", http.StatusBadRequest)
+		return
+	}
+	if err := h.verifySignature(r.Header.Get("X-Hub-Signature-256"), body); err != nil {
+		h.logger.Warn("rejected webhook", "error", err)
+		http.Error(w, err.Error(), http.StatusUnauthorized)
+		return
+	}
+
+	eventName := r.Header.Get("X-GitHub-Event")
+	if eventName == "" {
+		http.Error(w, "missing X-GitHub-Event header", http.StatusBadRequest)
+		return
+	}
+	var event githubEvent
+	if err := json.Unmarshal(body, &event); err != nil {
+		http.Error(w, "invalid JSON payload", http.StatusBadRequest)
+		return
+	}
+
+	rules := h.config.matchingRules(eventName, event.Action)
+	for _, rule := range rules {
+		h.wg.Add(1)
+		go h.run(rule, eventName, event)
+	}
+	h.logger.Info(
+		"accepted webhook",
+		"event", eventName,
+		"action", event.Action,
+		"triggered", len(rules),
+	)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(map[string]int{"triggered": len(rules)})
+}
+
+// Wait blocks until all in-flight tool runs have finished.
+func (h *Handler) Wait() {
+	h.wg.Wait()
+}
+
+// verifySignature checks the X-Hub-Signature-256 header against the payload.
+func (h *Handler) verifySignature(signature string, body []byte) error {
+	if h.config.Secret == "" {
+		return nil
+	}
+	hexDigest, found := strings.CutPrefix(signature, "sha256=")
+	if !found {
+		return errors.New("missing or malformed signature")
+	}
+	got, err := hex.DecodeString(hexDigest)
+	if err != nil {
+		return errors.New("malformed signature")
+	}
+	mac := hmac.New(sha256.New, []byte(h.config.Secret))
+	mac.Write(body)
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return errors.New("signature mismatch")
+	}
+	return nil
+}
+
+// run executes a single rule and delivers its outcome.
+func (h *Handler) run(rule Rule, eventName string, event githubEvent) {
+	defer h.wg.Done()
+	ctx, cancel := context.WithTimeout(context.Background(), h.timeout)
+	defer cancel()
+
+	delivery := Delivery{
+		Event:  eventName,
+		Action: event.Action,
+		Tool:   rule.Tool,
+	}
+	args := expandArguments(rule.Arguments, event.placeholders())
+	result, err := h.invoker.CallTool(ctx, rule.Tool, args)
+	switch {
+	case err != nil:
+		delivery.Error = err.Error()
+	case result.IsError:
+		delivery.Error = resultText(result)
+	default:
+		delivery.Result = resultText(result)
+	}
+
+	deliverCtx, cancelDelivery := context.WithTimeout(context.Background(), deliveryTimeout)
+	defer cancelDelivery()
+	if err := h.deliverer.Deliver(deliverCtx, rule.DeliverTo, delivery); err != nil {
+		h.logger.Error(
+			"failed to deliver webhook result",
+			"tool", rule.Tool,
+			"error", err,
+		)
+	}
+}
+
+// expandArguments replaces {{name}} placeholders in string arguments.
+func expandArguments(
+	arguments map[string]any,
+	values map[string]string,
+) map[string]any {
+	replacements := make([]string, 0, len(values)*2)
+	for name, value := range values {
+		replacements = append(replacements, fmt.Sprintf("{{%s}}", name), value)
+	}
+	replacer := strings.NewReplacer(replacements...)
+
+	expanded := make(map[string]any, len(arguments))
+	for key, value := range arguments {
+		if text, ok := value.(string); ok {
+			expanded[key] = replacer.Replace(text)
+			continue
+		}
+		expanded[key] = value
+	}
+	return expanded
+}
+
+// resultText concatenates the text content of a tool result.
+func resultText(result *mcp.CallToolResult) string {
+	var builder strings.Builder
+	for _, content := range result.Content {
+		if text, ok := mcp.AsTextContent(content); ok {
+			builder.WriteString(text.Text)
+		}
+	}
+	return builder.String()
+}
@@ -0,0 +1,169 @@
+package webhook
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/mark3labs/mcp-go/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const pushPayload = `{
+	"ref": "refs/heads/develop",
+	"repository": {
+		"full_name": "dictybase/dcr-mcp",
+		"clone_url": "https://github.com/dictybase/dcr-mcp.git",
+		"default_branch": "main"
+	},
+	"sender": {"login": "octocat"}
+}`
+
+type recordingInvoker struct {
+	mu    sync.Mutex
+	calls []map[string]any
+	// block makes calls wait for their context to end.
+	block bool
+}
+
+func (ri *recordingInvoker) CallTool(
+	ctx context.Context,
+	name string,
+	args map[string]any,
+) (*mcp.CallToolResult, error) {
+	ri.mu.Lock()
+	ri.calls = append(ri.calls, args)
+	ri.mu.Unlock()
+	if ri.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	return mcp.NewToolResultText("ran " + name), nil
+}
+
+type recordingDeliverer struct {
+	mu         sync.Mutex
+	deliveries []Delivery
+}
+
+func (rd *recordingDeliverer) Deliver(ctx context.Context, _ string, delivery Delivery) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	rd.mu.Lock()
+	defer rd.mu.Unlock()
+	rd.deliveries = append(rd.deliveries, delivery)
+	return nil
+}
+
+func newTestHandler(secret string, opts ...HandlerOption) (*Handler, *recordingInvoker, *recordingDeliverer) {
+	invoker := &recordingInvoker{}
+	deliverer := &recordingDeliverer{}
+	cfg := &Config{
+		Secret: secret,
+		Rules: []Rule{
+			{
+				Event: "push",
+				Tool:  "git-summary",
+				Arguments: map[string]any{
+					"repo_url":   "{{repo_url}}",
+					"branch":     "{{branch}}",
+					"start_date": "7 days ago",
+				},
+			},
+			{Event: "release", Action: "published", Tool: "changelog"},
+		},
+	}
+	handler := NewHandler(
+		cfg,
+		invoker,
+		append([]HandlerOption{
+			WithDeliverer(deliverer),
+			WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		}, opts...)...,
+	)
+	return handler, invoker, deliverer
+}
+
+func sign(secret, body string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(body))
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestHandler_PushTriggersTool(t *testing.T) {
+	t.Parallel()
+	handler, invoker, deliverer := newTestHandler("s3cret")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(pushPayload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", sign("s3cret", pushPayload))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	handler.Wait()
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Len(t, invoker.calls, 1)
+	assert.Equal(t, "https://github.com/dictybase/dcr-mcp.git", invoker.calls[0]["repo_url"])
+	assert.Equal(t, "develop", invoker.calls[0]["branch"])
+	assert.Equal(t, "7 days ago", invoker.calls[0]["start_date"])
+	require.Len(t, deliverer.deliveries, 1)
+	assert.Equal(t, "ran git-summary", deliverer.deliveries[0].Result)
+}
+
+func TestHandler_DeliversTimedOutRun(t *testing.T) {
+	t.Parallel()
+	handler, invoker, deliverer := newTestHandler("", WithRunTimeout(10*time.Millisecond))
+	invoker.block = true
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(pushPayload))
+	req.Header.Set("X-GitHub-Event", "push")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	handler.Wait()
+
+	require.Equal(t, http.StatusAccepted, rec.Code)
+	require.Len(t, deliverer.deliveries, 1, "the outcome of a run that timed out is delivered")
+	assert.Equal(t, context.DeadlineExceeded.Error(), deliverer.deliveries[0].Error)
+}
+
+func TestHandler_RejectsBadSignature(t *testing.T) {
+	t.Parallel()
+	handler, invoker, _ := newTestHandler("s3cret")
+
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(pushPayload))
+	req.Header.Set("X-GitHub-Event", "push")
+	req.Header.Set("X-Hub-Signature-256", sign("wrong", pushPayload))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	handler.Wait()
+
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Empty(t, invoker.calls)
+}
+
+func TestHandler_ActionFilter(t *testing.T) {
+	t.Parallel()
+	handler, invoker, _ := newTestHandler("")
+
+	payload := `{"action": "created", "release": {"tag_name": "v1.0.0"}}`
+	req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(payload))
+	req.Header.Set("X-GitHub-Event", "release")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	handler.Wait()
+
+	assert.Equal(t, http.StatusAccepted, rec.Code)
+	assert.JSONEq(t, `{"triggered": 0}`, rec.Body.String())
+	assert.Empty(t, invoker.calls)
+}
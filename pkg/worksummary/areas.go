@@ -0,0 +1,32 @@
+package worksummary
+
+import "strings"
+
+// containerDirs hold one module, command or app per subdirectory, so their
+// subdirectories are reported as the areas.
+var containerDirs = map[string]bool{
+	"apps":     true,
+	"cmd":      true,
+	"internal": true,
+	"lib":      true,
+	"packages": true,
+	"pkg":      true,
+	"services": true,
+	"src":      true,
+}
+
+// RootArea is the area of the files at the top of the repository.
+const RootArea = "(root)"
+
+// AreaOf returns the area of the repository a file belongs to.
+func AreaOf(file string) string {
+	parts := strings.Split(file, "/")
+	switch {
+	case len(parts) == 1:
+		return RootArea
+	case len(parts) > 2 && containerDirs[parts[0]]:
+		return parts[0] + "/" + parts[1]
+	default:
+		return parts[0]
+	}
+}
@@ -0,0 +1,15 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAreaOf(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, RootArea, AreaOf("README.md"))
+	assert.Equal(t, "docs", AreaOf("docs/guide/intro.md"))
+	assert.Equal(t, "pkg/tools", AreaOf("pkg/tools/registry/registry.go"))
+	assert.Equal(t, "pkg", AreaOf("pkg/doc.go"))
+}
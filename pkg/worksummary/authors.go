@@ -0,0 +1,66 @@
+package worksummary
+
+import (
+	"slices"
+	"strings"
+)
+
+// AuthorFilter selects commits by their author. A pattern with an @ is
+// matched against the author's email: in full, or as its domain when the
+// pattern starts with @, such as @dictybase.org. Any other pattern is
+// matched against part of the author's name. Case is ignored.
+type AuthorFilter struct {
+	// Include lists the authors whose commits are kept, every author when
+	// it is empty.
+	Include []string `validate:"required_without=Exclude"`
+	// Exclude lists the authors whose commits are left out, even when
+	// they are included.
+	Exclude []string
+}
+
+// Matches reports whether the filter keeps the commits of the author with
+// the name and email.
+func (f AuthorFilter) Matches(name, email string) bool {
+	if f.excludes(name, email) {
+		return false
+	}
+	return len(f.Include) == 0 || slices.ContainsFunc(f.Include, func(pattern string) bool {
+		return matchesAuthor(name, email, pattern)
+	})
+}
+
+// excludes reports whether the author with the name and email is excluded.
+func (f AuthorFilter) excludes(name, email string) bool {
+	return slices.ContainsFunc(f.Exclude, func(pattern string) bool {
+		return matchesAuthor(name, email, pattern)
+	})
+}
+
+// String describes the authors the filter keeps, such as "Jane Doe, Joe"
+// or "everyone except renovate".
+func (f AuthorFilter) String() string {
+	included := strings.Join(f.Include, ", ")
+	if included == "" {
+		included = "everyone"
+	}
+	if len(f.Exclude) == 0 {
+		return included
+	}
+	return included + " except " + strings.Join(f.Exclude, ", ")
+}
+
+// matchesAuthor reports whether the author with the name and email matches
+// a pattern of an AuthorFilter.
+func matchesAuthor(name, email, pattern string) bool {
+	pattern, email = strings.ToLower(strings.TrimSpace(pattern)), strings.ToLower(email)
+	switch {
+	case pattern == "":
+		return false
+	case strings.HasPrefix(pattern, "@"):
+		return strings.HasSuffix(email, pattern)
+	case strings.Contains(pattern, "@"):
+		return email == pattern
+	default:
+		return strings.Contains(strings.ToLower(name), pattern)
+	}
+}
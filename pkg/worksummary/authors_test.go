@@ -0,0 +1,33 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthorFilter(t *testing.T) {
+	t.Parallel()
+	team := AuthorFilter{Include: []string{"jane", "JOE@example.org", "@dictybase.org"}, Exclude: []string{"Jane Bot"}}
+	for _, tc := range []struct {
+		name, email string
+		want        bool
+	}{
+		{"Jane Doe", "jane@example.org", true},
+		{"Joe", "joe@example.org", true},
+		{"Joseph", "joseph@example.org", false},
+		{"Ann", "ann@dictybase.org", true},
+		{"Ann", "ann@notdictybase.org", false},
+		{"Jane Bot", "bot@dictybase.org", false},
+	} {
+		assert.Equal(t, tc.want, team.Matches(tc.name, tc.email), "%s <%s>", tc.name, tc.email)
+	}
+	assert.Equal(t, "jane, JOE@example.org, @dictybase.org except Jane Bot", team.String())
+
+	everyone := AuthorFilter{Exclude: []string{"renovate"}}
+	assert.True(t, everyone.Matches("Jane Doe", "jane@example.org"))
+	assert.False(t, everyone.Matches("renovate[bot]", "bot@renovateapp.com"))
+	assert.Equal(t, "everyone except renovate", everyone.String())
+
+	assert.Error(t, validate.Struct(AuthorFilter{}), "a filter selects some authors")
+}
@@ -0,0 +1,180 @@
+package worksummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// BranchAuto stands for the default branch of a repository in a list of
+// branches.
+const BranchAuto = "auto"
+
+// ResolveBranches splits a comma-separated list of branches, such as
+// "main,develop", replacing BranchAuto with the default branch of the
+// repository at repoURL. Repeated branches are dropped.
+func (ga *GitAnalyzer) ResolveBranches(ctx context.Context, repoURL, list string) ([]string, error) {
+	var branches []string
+	for _, branch := range strings.Split(list, ",") {
+		branch = strings.TrimSpace(branch)
+		if branch == BranchAuto {
+			var err error
+			if branch, err = ga.DefaultBranch(ctx, repoURL); err != nil {
+				return nil, err
+			}
+		}
+		if branch != "" && !slices.Contains(branches, branch) {
+			branches = append(branches, branch)
+		}
+	}
+	if len(branches) == 0 {
+		return nil, toolerror.New(toolerror.TypeInvalidInput, "MISSING_BRANCH", "no branch given")
+	}
+	return branches, nil
+}
+
+// DefaultBranch returns the branch the HEAD of a repository points to,
+// asking the remote without cloning it. For a local repository that is the
+// branch checked out. The URL is normalized and its host checked as for
+// CloneAndCheckout.
+func (ga *GitAnalyzer) DefaultBranch(ctx context.Context, repoURL string) (string, error) {
+	repoURL, err := ga.prepareRepoURL(ctx, repoURL)
+	if err != nil {
+		return "", err
+	}
+	remote := git.NewRemote(memory.NewStorage(), &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{repoURL}})
+	start := time.Now()
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	metrics.ObserveOutbound(metrics.ServiceGitClone, start, err)
+	if err != nil {
+		return "", fmt.Errorf("error listing remote references: %w", err)
+	}
+	branch := defaultBranch(refs)
+	if branch == "" {
+		return "", toolerror.New(
+			toolerror.TypeInvalidInput,
+			"DEFAULT_BRANCH_UNKNOWN",
+			fmt.Sprintf("%s does not name a default branch, give the branch instead of %s", repoURL, BranchAuto),
+		)
+	}
+	ga.logger.Info("detected default branch", "repo_url", repoURL, "branch", branch)
+	return branch, nil
+}
+
+// branchNotFound is the error of a repository, described by where, without
+// branch.
+func branchNotFound(where, branch string, err error) *toolerror.Error {
+	return &toolerror.Error{
+		Type:    toolerror.TypeInvalidInput,
+		Code:    "BRANCH_NOT_FOUND",
+		Message: fmt.Sprintf("%s has no branch %s", where, branch),
+		Err:     err,
+	}
+}
+
+// Branch returns the commit a branch of the clone points to, looking at
+// local branches first and then at the branches of the origin remote.
+func (c *Clone) Branch(name string) (plumbing.Hash, error) {
+	for _, ref := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(name),
+		plumbing.NewRemoteReferenceName(git.DefaultRemoteName, name),
+	} {
+		if resolved, err := c.Reference(ref, true); err == nil {
+			return resolved.Hash(), nil
+		}
+	}
+	return plumbing.ZeroHash, branchNotFound("the repository", name, nil)
+}
+
+// checkBranches returns an error for the first of branches missing from
+// the clone.
+func (c *Clone) checkBranches(branches []string) error {
+	for _, branch := range branches {
+		if _, err := c.Branch(branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// fetchBranches fetches branches into a fresh clone, which only has the
+// branch checked out.
+func (ga *GitAnalyzer) fetchBranches(ctx context.Context, clone *Clone, branches []string) error {
+	if len(branches) == 0 {
+		return nil
+	}
+	specs := make([]config.RefSpec, 0, len(branches))
+	for _, branch := range branches {
+		specs = append(specs, config.RefSpec(fmt.Sprintf(
+			"+%s:%s",
+			plumbing.NewBranchReferenceName(branch),
+			plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branch),
+		)))
+	}
+	ga.logger.Info("fetching branches", "branches", branches)
+	start := time.Now()
+	err := clone.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   specs,
+		Tags:       git.NoTags,
+		Progress:   cloneProgress{reporter: progress.FromContext(ctx)},
+	})
+	metrics.ObserveOutbound(metrics.ServiceGitClone, start, err)
+	var missing git.NoMatchingRefSpecError
+	switch {
+	case errors.As(err, &missing):
+		return branchNotFound("the repository", strings.Join(branches, ", "), err)
+	case err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate):
+		return fmt.Errorf("error fetching branches: %w", err)
+	}
+	return clone.checkBranches(branches)
+}
+
+// ListBranchCommits returns the commits of the author within the date
+// range on any of branches of clone, newest first. A commit on several of
+// the branches is listed once, with Branches naming all of them in the
+// order of branches. The patches of all branches share one token budget.
+func (ga *GitAnalyzer) ListBranchCommits(
+	ctx context.Context, clone *Clone, branches []string, params CommitRangeParams,
+) ([]Commit, error) {
+	var commits []Commit
+	seen := make(map[string]int)
+	for _, branch := range branches {
+		tip, err := clone.Branch(branch)
+		if err != nil {
+			return nil, err
+		}
+		params.From = tip
+		listed, err := ga.ListAuthorCommits(ctx, params)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list commits of branch %s: %w", branch, err)
+		}
+		for _, commit := range listed {
+			if i, ok := seen[commit.Hash]; ok {
+				commits[i].Branches = append(commits[i].Branches, branch)
+				continue
+			}
+			seen[commit.Hash] = len(commits)
+			commit.Branches = []string{branch}
+			commits = append(commits, commit)
+		}
+	}
+	slices.SortStableFunc(commits, func(a, b Commit) int {
+		return b.When.Compare(a.When)
+	})
+	if params.Patches != nil {
+		fitPatches(commits, params.Patches.TokenBudget)
+	}
+	return commits, nil
+}
@@ -0,0 +1,72 @@
+package worksummary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveBranches(t *testing.T) {
+	t.Parallel()
+	dir := branchedRepo(t)
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	branch, err := analyzer.DefaultBranch(context.Background(), dir)
+	require.NoError(t, err)
+	assert.Equal(t, "master", branch)
+
+	branches, err := analyzer.ResolveBranches(context.Background(), dir, "auto, feature/stock,master,")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"master", "feature/stock"}, branches)
+
+	_, err = analyzer.ResolveBranches(context.Background(), dir, " , ")
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, "MISSING_BRANCH", toolErr.Code)
+}
+
+func TestListBranchCommits(t *testing.T) {
+	t.Parallel()
+	dir := branchedRepo(t)
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	branches := []string{"master", "feature/stock"}
+
+	for _, storage := range []CloneStorage{StorageAuto, StorageMemory} {
+		clone, err := analyzer.CloneAndCheckout(
+			context.Background(), dir, branches[0], WithStorage(storage), WithBranches(branches[1:]...),
+		)
+		require.NoError(t, err, storage)
+		commits, err := analyzer.ListBranchCommits(context.Background(), clone, branches, CommitRangeParams{
+			Repo:    clone.Repository,
+			Start:   time.Date(2025, time.June, 1, 0, 0, 0, 0, time.UTC),
+			End:     time.Date(2025, time.June, 30, 0, 0, 0, 0, time.UTC),
+			Authors: AuthorFilter{Include: []string{"Jane Doe"}},
+		})
+		require.NoError(t, err, storage)
+		require.Len(t, commits, 5, "the shared commits are listed once")
+		assert.Equal(t, 6, commits[0].When.Day(), "newest first")
+		assert.Equal(t, []string{"master"}, commits[0].Branches)
+		assert.Equal(t, []string{"master", "feature/stock"}, commits[4].Branches)
+		require.NoError(t, clone.Close())
+	}
+}
+
+func TestCloneAndCheckout_MissingBranch(t *testing.T) {
+	t.Parallel()
+	dir := branchedRepo(t)
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	for _, storage := range []CloneStorage{StorageAuto, StorageMemory} {
+		_, err := analyzer.CloneAndCheckout(
+			context.Background(), dir, "master", WithStorage(storage), WithBranches("develop"),
+		)
+		var toolErr *toolerror.Error
+		require.ErrorAs(t, err, &toolErr, storage)
+		assert.Equal(t, "BRANCH_NOT_FOUND", toolErr.Code, storage)
+	}
+}
@@ -0,0 +1,262 @@
+package worksummary
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// DefaultBranchDepth is the number of commits of every branch fetched to
+// compare it with the default branch.
+const DefaultBranchDepth = 200
+
+// remoteBranchPrefix is where the fetched branches are stored.
+const remoteBranchPrefix = "refs/remotes/origin/"
+
+// RemoteBranch is a branch of a remote repository.
+type RemoteBranch struct {
+	Name       string    `json:"name"`
+	Commit     string    `json:"commit"`
+	Default    bool      `json:"default,omitempty"`
+	LastCommit time.Time `json:"last_commit"`
+	// Ahead counts the commits of the branch missing from the default
+	// branch, Behind the reverse. Both are nil for the default branch and
+	// for branches that do not meet it within the fetched commits.
+	Ahead  *int `json:"ahead,omitempty"`
+	Behind *int `json:"behind,omitempty"`
+}
+
+// RemoteBranches lists the branches of a remote repository.
+type RemoteBranches struct {
+	// DefaultBranch is the branch HEAD of the remote points to, empty when
+	// the remote does not say.
+	DefaultBranch string `json:"default_branch,omitempty"`
+	// Depth is the number of commits of every branch the comparison saw.
+	Depth    int            `json:"depth"`
+	Branches []RemoteBranch `json:"branches"`
+}
+
+// ListRemoteBranches lists the branches of a remote repository without
+// cloning it. It lists the remote's references, then fetches only the
+// latest depth commits of every branch, without a worktree, to date each
+// branch and count the commits it is ahead of and behind the default
+// branch. The URL is normalized and its host checked as for
+// CloneAndCheckout. The default branch comes first, then the most recently committed
+// to. Fetch progress goes to the progress reporter of ctx.
+func (ga *GitAnalyzer) ListRemoteBranches(ctx context.Context, repoURL string, depth int) (RemoteBranches, error) {
+	if err := validate.Var(repoURL, "required"); err != nil {
+		return RemoteBranches{}, fmt.Errorf("repository URL cannot be empty: %w", err)
+	}
+	if err := validate.Var(depth, "min=1"); err != nil {
+		return RemoteBranches{}, fmt.Errorf("depth must be positive: %w", err)
+	}
+	repoURL, err := ga.prepareRepoURL(ctx, repoURL)
+	if err != nil {
+		return RemoteBranches{}, err
+	}
+	ga.logger.Info("listing remote branches", "repo_url", repoURL, "depth", depth)
+
+	storage := memory.NewStorage()
+	remote := git.NewRemote(storage, &config.RemoteConfig{Name: git.DefaultRemoteName, URLs: []string{repoURL}})
+	start := time.Now()
+	refs, err := remote.ListContext(ctx, &git.ListOptions{})
+	metrics.ObserveOutbound(metrics.ServiceGitClone, start, err)
+	if errors.Is(err, transport.ErrEmptyRemoteRepository) {
+		return RemoteBranches{Depth: depth, Branches: []RemoteBranch{}}, nil
+	}
+	if err != nil {
+		return RemoteBranches{}, fmt.Errorf("error listing remote references: %w", err)
+	}
+	branches := RemoteBranches{DefaultBranch: defaultBranch(refs), Depth: depth}
+
+	start = time.Now()
+	err = remote.FetchContext(ctx, &git.FetchOptions{
+		RemoteName: git.DefaultRemoteName,
+		RefSpecs:   []config.RefSpec{config.RefSpec("+refs/heads/*:" + remoteBranchPrefix + "*")},
+		Depth:      depth,
+		Tags:       git.NoTags,
+		Progress:   cloneProgress{reporter: progress.FromContext(ctx)},
+	})
+	metrics.ObserveOutbound(metrics.ServiceGitClone, start, err)
+	if err != nil && !errors.Is(err, git.NoErrAlreadyUpToDate) {
+		return RemoteBranches{}, fmt.Errorf("error fetching branches: %w", err)
+	}
+
+	tips, err := branchTips(storage)
+	if err != nil {
+		return RemoteBranches{}, err
+	}
+	shallow, err := shallowCommits(storage)
+	if err != nil {
+		return RemoteBranches{}, err
+	}
+	branches.Branches, err = compareBranches(storage, tips, branches.DefaultBranch, shallow)
+	if err != nil {
+		return RemoteBranches{}, err
+	}
+	return branches, nil
+}
+
+// defaultBranch returns the branch the HEAD of a remote points to.
+func defaultBranch(refs []*plumbing.Reference) string {
+	for _, ref := range refs {
+		if ref.Name() == plumbing.HEAD && ref.Type() == plumbing.SymbolicReference && ref.Target().IsBranch() {
+			return ref.Target().Short()
+		}
+	}
+	return ""
+}
+
+// branchTips returns the commit of every fetched branch by branch name.
+func branchTips(storage *memory.Storage) (map[string]plumbing.Hash, error) {
+	refs, err := storage.IterReferences()
+	if err != nil {
+		return nil, fmt.Errorf("error reading fetched branches: %w", err)
+	}
+	tips := make(map[string]plumbing.Hash)
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if name, ok := strings.CutPrefix(ref.Name().String(), remoteBranchPrefix); ok && ref.Type() == plumbing.HashReference {
+			tips[name] = ref.Hash()
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error reading fetched branches: %w", err)
+	}
+	return tips, nil
+}
+
+// shallowCommits returns the commits whose parents were not fetched.
+func shallowCommits(storage *memory.Storage) (map[plumbing.Hash]bool, error) {
+	hashes, err := storage.Shallow()
+	if err != nil {
+		return nil, fmt.Errorf("error reading shallow commits: %w", err)
+	}
+	shallow := make(map[plumbing.Hash]bool, len(hashes))
+	for _, hash := range hashes {
+		shallow[hash] = true
+	}
+	return shallow, nil
+}
+
+// compareBranches dates every branch and compares it with the default
+// branch.
+func compareBranches(
+	objects storer.EncodedObjectStorer,
+	tips map[string]plumbing.Hash,
+	defaultName string,
+	shallow map[plumbing.Hash]bool,
+) ([]RemoteBranch, error) {
+	defaultTip, hasDefault := tips[defaultName]
+	var defaultHistory history
+	if hasDefault {
+		defaultHistory = walkHistory(objects, defaultTip, shallow)
+	}
+	branches := make([]RemoteBranch, 0, len(tips))
+	for name, tip := range tips {
+		commit, err := object.GetCommit(objects, tip)
+		if err != nil {
+			return nil, fmt.Errorf("error reading the last commit of branch %s: %w", name, err)
+		}
+		branch := RemoteBranch{
+			Name:       name,
+			Commit:     tip.String(),
+			Default:    hasDefault && name == defaultName,
+			LastCommit: commit.Committer.When,
+		}
+		if hasDefault && !branch.Default {
+			branchHistory := walkHistory(objects, tip, shallow)
+			if ahead, ok := exclusiveCommits(objects, tip, defaultHistory, shallow); ok {
+				branch.Ahead = &ahead
+			}
+			if behind, ok := exclusiveCommits(objects, defaultTip, branchHistory, shallow); ok {
+				branch.Behind = &behind
+			}
+		}
+		branches = append(branches, branch)
+	}
+	slices.SortFunc(branches, func(a, b RemoteBranch) int {
+		if a.Default != b.Default {
+			if a.Default {
+				return -1
+			}
+			return 1
+		}
+		return cmp.Or(b.LastCommit.Compare(a.LastCommit), cmp.Compare(a.Name, b.Name))
+	})
+	return branches, nil
+}
+
+// history is the set of fetched commits reachable from a branch.
+type history struct {
+	commits map[plumbing.Hash]bool
+	// truncated tells the walk reached commits whose parents were not
+	// fetched, so older commits of the branch are missing from the set.
+	truncated bool
+}
+
+// walkHistory returns the fetched commits reachable from tip.
+func walkHistory(objects storer.EncodedObjectStorer, tip plumbing.Hash, shallow map[plumbing.Hash]bool) history {
+	walked := history{commits: make(map[plumbing.Hash]bool)}
+	queue := []plumbing.Hash{tip}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if walked.commits[hash] {
+			continue
+		}
+		walked.commits[hash] = true
+		commit, err := object.GetCommit(objects, hash)
+		if shallow[hash] || err != nil {
+			walked.truncated = true
+			continue
+		}
+		queue = append(queue, commit.ParentHashes...)
+	}
+	return walked
+}
+
+// exclusiveCommits counts the commits reachable from tip that are not in
+// other. The count is only reported when it is exact: when the walk stops
+// at commits of other before running out of fetched commits, and does not
+// end at a root commit other may hold beyond its own fetched commits.
+func exclusiveCommits(
+	objects storer.EncodedObjectStorer,
+	tip plumbing.Hash,
+	other history,
+	shallow map[plumbing.Hash]bool,
+) (int, bool) {
+	seen := make(map[plumbing.Hash]bool)
+	queue := []plumbing.Hash{tip}
+	for len(queue) > 0 {
+		hash := queue[0]
+		queue = queue[1:]
+		if seen[hash] || other.commits[hash] {
+			continue
+		}
+		seen[hash] = true
+		if shallow[hash] {
+			return 0, false
+		}
+		commit, err := object.GetCommit(objects, hash)
+		if err != nil || (len(commit.ParentHashes) == 0 && other.truncated) {
+			return 0, false
+		}
+		queue = append(queue, commit.ParentHashes...)
+	}
+	return len(seen), true
+}
@@ -0,0 +1,95 @@
+package worksummary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// checkout switches the worktree of repo to branch, creating it if asked.
+func checkout(t *testing.T, repo *git.Repository, branch string, create bool) {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, worktree.Checkout(&git.CheckoutOptions{
+		Branch: plumbing.NewBranchReferenceName(branch),
+		Create: create,
+	}))
+}
+
+// branchedRepo creates a repository whose master has five commits and
+// whose feature branch forked after the third and has two commits of its
+// own.
+func branchedRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC) }
+	for d := 1; d <= 3; d++ {
+		commitAt(t, repo, dir, "Jane Doe", day(d))
+	}
+	checkout(t, repo, "feature/stock", true)
+	commitAt(t, repo, dir, "Joe", day(4))
+	commitAt(t, repo, dir, "Joe", day(10))
+	checkout(t, repo, "master", false)
+	commitAt(t, repo, dir, "Jane Doe", day(5))
+	commitAt(t, repo, dir, "Jane Doe", day(6))
+	return dir
+}
+
+func TestListRemoteBranches(t *testing.T) {
+	t.Parallel()
+	branches, err := NewGitAnalyzer().ListRemoteBranches(context.Background(), branchedRepo(t), DefaultBranchDepth)
+	require.NoError(t, err)
+	assert.Equal(t, "master", branches.DefaultBranch)
+	require.Len(t, branches.Branches, 2)
+
+	master, feature := branches.Branches[0], branches.Branches[1]
+	assert.Equal(t, "master", master.Name)
+	assert.True(t, master.Default, "the default branch comes first")
+	assert.Nil(t, master.Ahead)
+	assert.Nil(t, master.Behind)
+
+	assert.Equal(t, "feature/stock", feature.Name)
+	assert.Equal(t, time.Date(2025, time.June, 10, 12, 0, 0, 0, time.UTC), feature.LastCommit.UTC())
+	assert.Len(t, feature.Commit, 40)
+	require.NotNil(t, feature.Ahead)
+	require.NotNil(t, feature.Behind)
+	assert.Equal(t, 2, *feature.Ahead)
+	assert.Equal(t, 2, *feature.Behind)
+}
+
+func TestListRemoteBranches_ShallowComparison(t *testing.T) {
+	t.Parallel()
+	branches, err := NewGitAnalyzer().ListRemoteBranches(context.Background(), branchedRepo(t), 2)
+	require.NoError(t, err)
+	require.Len(t, branches.Branches, 2)
+	feature := branches.Branches[1]
+	assert.Nil(t, feature.Ahead, "the branches do not meet within two commits")
+	assert.Nil(t, feature.Behind)
+	assert.False(t, feature.LastCommit.IsZero(), "the last commit is still dated")
+}
+
+func TestListRemoteBranches_EmptyRepository(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	_, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	branches, err := NewGitAnalyzer().ListRemoteBranches(context.Background(), dir, DefaultBranchDepth)
+	require.NoError(t, err)
+	assert.Empty(t, branches.Branches)
+}
+
+func TestListRemoteBranches_InvalidInput(t *testing.T) {
+	t.Parallel()
+	_, err := NewGitAnalyzer().ListRemoteBranches(context.Background(), "", DefaultBranchDepth)
+	require.Error(t, err)
+	_, err = NewGitAnalyzer().ListRemoteBranches(context.Background(), "https://example.org/repo", 0)
+	require.Error(t, err)
+}
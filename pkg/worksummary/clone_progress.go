@@ -0,0 +1,47 @@
+package worksummary
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+)
+
+// clonePhasePattern matches a git sideband progress line such as
+// "Receiving objects:  45% (450/1000)".
+var clonePhasePattern = regexp.MustCompile(`([A-Za-z ]+):\s+(\d+)%`)
+
+// clonePhases place the slow phases of a clone within its progress range;
+// counting and compressing on the server are quick and not reported.
+var clonePhases = map[string][2]float64{
+	"receiving objects": {0, 0.8},
+	"resolving deltas":  {0.8, 1},
+}
+
+// cloneProgress turns git sideband output into progress notifications.
+type cloneProgress struct {
+	reporter *progress.Reporter
+}
+
+// Write implements io.Writer. It never fails, so a clone is not aborted
+// because its progress could not be reported.
+func (c cloneProgress) Write(data []byte) (int, error) {
+	matches := clonePhasePattern.FindAllStringSubmatch(string(data), -1)
+	if len(matches) == 0 {
+		return len(data), nil
+	}
+	last := matches[len(matches)-1]
+	phase := strings.ToLower(strings.TrimSpace(last[1]))
+	bounds, ok := clonePhases[phase]
+	if !ok {
+		return len(data), nil
+	}
+	percent, _ := strconv.Atoi(last[2])
+	c.reporter.Sub(bounds[0], bounds[1]).Throttled(
+		float64(percent),
+		100,
+		"cloning repository: "+strings.TrimSpace(last[0]),
+	)
+	return len(data), nil
+}
@@ -0,0 +1,39 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneProgress(t *testing.T) {
+	t.Parallel()
+	var sent []map[string]any
+	reporter := progress.NewFuncReporter(func(params map[string]any) {
+		sent = append(sent, params)
+	})
+	writer := cloneProgress{reporter: reporter.Sub(0, 10)}
+
+	for _, line := range []string{
+		"Counting objects: 100% (10/10), done.\r",
+		"Receiving objects:  50% (5/10)\rReceiving objects:  60% (6/10)\r",
+		"no progress here\n",
+	} {
+		written, err := writer.Write([]byte(line))
+		require.NoError(t, err)
+		assert.Equal(t, len(line), written)
+	}
+
+	require.Len(t, sent, 1, "counting is skipped and later updates are throttled")
+	assert.InDelta(t, 4.8, sent[0]["progress"], 1e-9)
+	assert.Equal(t, "cloning repository: Receiving objects:  60%", sent[0]["message"])
+}
+
+func TestStreamProgress(t *testing.T) {
+	t.Parallel()
+	assert.Zero(t, streamProgress(0))
+	assert.InDelta(t, 0.5, streamProgress(expectedSummaryLength), 1e-9)
+	assert.Less(t, streamProgress(1_000_000), 1.0)
+}
@@ -0,0 +1,269 @@
+package worksummary
+
+import (
+	"cmp"
+	"fmt"
+	"net/url"
+	"slices"
+	"strings"
+	"unicode"
+)
+
+const (
+	// maxLinksPerBullet is the number of commits cited by one bullet.
+	maxLinksPerBullet = 3
+	// shortHashLength is the length of the hashes shown in citations.
+	shortHashLength = 7
+)
+
+// commitPaths maps known hosts to the path of a commit page below the
+// repository URL.
+var commitPaths = map[string]string{
+	"github.com":    "/commit/",
+	"gitlab.com":    "/-/commit/",
+	"codeberg.org":  "/commit/",
+	"bitbucket.org": "/commits/",
+}
+
+// stopWords are too common in summaries and commit messages to tie one to
+// the other.
+var stopWords = map[string]bool{
+	"about": true, "added": true, "also": true, "been": true, "change": true,
+	"does": true, "from": true, "have": true, "into": true, "make": true,
+	"more": true, "much": true, "need": true, "only": true, "other": true,
+	"some": true, "such": true, "than": true, "that": true, "their": true,
+	"them": true, "then": true, "there": true, "these": true, "they": true,
+	"this": true, "those": true, "update": true, "user": true, "using": true,
+	"were": true, "what": true, "when": true, "which": true, "while": true,
+	"will": true, "with": true, "work": true, "your": true,
+}
+
+// CommitURLBase returns the URL commit hashes are appended to for a
+// repository on a known host, accepting HTTPS and SSH remotes and the
+// other forms NormalizeRepoURL understands. It reports false for other
+// hosts.
+func CommitURLBase(repoURL string) (string, bool) {
+	if normalized, err := NormalizeRepoURL(repoURL); err == nil {
+		repoURL = normalized
+	}
+	host, repoPath, ok := splitRemote(repoURL)
+	if !ok {
+		return "", false
+	}
+	commitPath, known := commitPaths[host]
+	repoPath = strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git")
+	if !known || strings.Count(repoPath, "/") < 1 {
+		return "", false
+	}
+	return "https://" + host + "/" + repoPath + commitPath, true
+}
+
+// splitRemote splits a git remote into its lowercased host and path.
+func splitRemote(repoURL string) (string, string, bool) {
+	repoURL = strings.TrimSpace(repoURL)
+	if !strings.Contains(repoURL, "://") {
+		// scp-like syntax, e.g. git@github.com:owner/repo.git
+		userHost, repoPath, found := strings.Cut(repoURL, ":")
+		if !found {
+			return "", "", false
+		}
+		_, host, _ := strings.Cut(userHost, "@")
+		if host == "" {
+			host = userHost
+		}
+		return strings.ToLower(host), repoPath, true
+	}
+	parsed, err := url.Parse(repoURL)
+	if err != nil || parsed.Hostname() == "" {
+		return "", "", false
+	}
+	return strings.ToLower(parsed.Hostname()), parsed.Path, true
+}
+
+// CommitSource is a repository and the commits of a summary made there.
+type CommitSource struct {
+	RepoURL string
+	Commits []Commit
+}
+
+// LinkCommits cites representative commits under each bullet of summary
+// as markdown footnotes linking to the commit pages, so every claim can be
+// checked with one click. A bullet cites the commits sharing the most
+// words with it; bullets without a matching commit are left as they are.
+// The summary is returned unchanged when the repository is not on a known
+// host.
+func LinkCommits(summary, repoURL string, commits []Commit) string {
+	return LinkSourceCommits(summary, []CommitSource{{RepoURL: repoURL, Commits: commits}})
+}
+
+// LinkSourceCommits is LinkCommits for a summary of the commits of several
+// repositories. Commits of repositories that are not on a known host are
+// not cited.
+func LinkSourceCommits(summary string, sources []CommitSource) string {
+	var (
+		commits []Commit
+		bases   []string
+	)
+	for _, source := range sources {
+		base, ok := CommitURLBase(source.RepoURL)
+		if !ok {
+			continue
+		}
+		for _, commit := range source.Commits {
+			commits = append(commits, commit)
+			bases = append(bases, base)
+		}
+	}
+	if len(commits) == 0 {
+		return summary
+	}
+	commitWords := make([]map[string]bool, len(commits))
+	for i, commit := range commits {
+		commitWords[i] = words(commit.Message)
+	}
+
+	lines := strings.Split(strings.TrimRight(summary, "\n"), "\n")
+	footnotes := make(map[string]int)
+	var cited []int
+	for _, bullet := range bullets(lines) {
+		text := strings.Join(lines[bullet.first:bullet.last+1], " ")
+		var markers strings.Builder
+		for _, index := range matchCommits(words(text), commitWords) {
+			key := bases[index] + commits[index].Hash
+			number, seen := footnotes[key]
+			if !seen {
+				cited = append(cited, index)
+				number = len(cited)
+				footnotes[key] = number
+			}
+			fmt.Fprintf(&markers, "[^%d]", number)
+		}
+		lines[bullet.last] += markers.String()
+	}
+	if len(cited) == 0 {
+		return summary
+	}
+
+	var linked strings.Builder
+	linked.WriteString(strings.Join(lines, "\n"))
+	linked.WriteString("\n\n")
+	for i, index := range cited {
+		commit := commits[index]
+		fmt.Fprintf(
+			&linked,
+			"[^%d]: [`%s`](%s%s) %s\n",
+			i+1,
+			ShortHash(commit.Hash),
+			bases[index],
+			commit.Hash,
+			commit.Subject,
+		)
+	}
+	return linked.String()
+}
+
+// lineRange is an inclusive range of line indexes.
+type lineRange struct {
+	first, last int
+}
+
+// bullets returns the line ranges of the list items in lines. An item runs
+// until the next item, a blank line, a heading or a bold paragraph title.
+func bullets(lines []string) []lineRange {
+	var ranges []lineRange
+	current := -1
+	for i, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case isListItem(trimmed):
+			ranges = append(ranges, lineRange{first: i, last: i})
+			current = len(ranges) - 1
+		case trimmed == "" || strings.HasPrefix(trimmed, "#") ||
+			strings.HasPrefix(trimmed, "**") || strings.HasPrefix(trimmed, "[^"):
+			current = -1
+		case current >= 0:
+			ranges[current].last = i
+		}
+	}
+	return ranges
+}
+
+// isListItem reports whether a trimmed line starts a list item.
+func isListItem(line string) bool {
+	if strings.HasPrefix(line, "- ") || strings.HasPrefix(line, "* ") ||
+		strings.HasPrefix(line, "+ ") {
+		return true
+	}
+	digits := strings.TrimLeftFunc(line, unicode.IsDigit)
+	return len(digits) < len(line) && strings.HasPrefix(digits, ". ")
+}
+
+// matchCommits returns the indexes of the commits best matching a bullet,
+// most shared words first and newer commits before older ones on ties. A
+// commit matches when it shares two words with the bullet, or at least
+// half of its words when its message is short.
+func matchCommits(bulletWords map[string]bool, commitWords []map[string]bool) []int {
+	type match struct {
+		index, shared int
+	}
+	var matches []match
+	for i, candidate := range commitWords {
+		shared := 0
+		for word := range candidate {
+			if bulletWords[word] {
+				shared++
+			}
+		}
+		if shared >= 2 || (shared > 0 && shared*2 >= len(candidate)) {
+			matches = append(matches, match{index: i, shared: shared})
+		}
+	}
+	slices.SortStableFunc(matches, func(a, b match) int {
+		return cmp.Compare(b.shared, a.shared)
+	})
+	indexes := make([]int, 0, maxLinksPerBullet)
+	for _, m := range matches[:min(len(matches), maxLinksPerBullet)] {
+		indexes = append(indexes, m.index)
+	}
+	return indexes
+}
+
+// words returns the stems of the significant words of text.
+func words(text string) map[string]bool {
+	stems := make(map[string]bool)
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, field := range fields {
+		if len(field) < 4 || stopWords[field] {
+			continue
+		}
+		stems[stem(field)] = true
+	}
+	return stems
+}
+
+// stem strips common English suffixes so "parse", "parsing", "parsed" and
+// "parses" compare equal.
+func stem(word string) string {
+	const minStem = 3
+	for _, suffix := range []string{"ing", "ed", "s"} {
+		if trimmed := strings.TrimSuffix(word, suffix); trimmed != word && len(trimmed) >= minStem {
+			word = trimmed
+			break
+		}
+	}
+	if trimmed := strings.TrimSuffix(word, "e"); len(trimmed) >= minStem {
+		word = trimmed
+	}
+	return word
+}
+
+// ShortHash abbreviates a commit hash to the length shown in summaries and
+// reports.
+func ShortHash(hash string) string {
+	if len(hash) > shortHashLength {
+		return hash[:shortHashLength]
+	}
+	return hash
+}
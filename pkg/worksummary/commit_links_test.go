@@ -0,0 +1,129 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCommitURLBase(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		repoURL string
+		want    string
+		ok      bool
+	}{
+		{"https://github.com/dictybase/dcr-mcp", "https://github.com/dictybase/dcr-mcp/commit/", true},
+		{"https://github.com/dictybase/dcr-mcp.git", "https://github.com/dictybase/dcr-mcp/commit/", true},
+		{"git@github.com:dictybase/dcr-mcp.git", "https://github.com/dictybase/dcr-mcp/commit/", true},
+		{"ssh://git@GitLab.com/group/sub/project.git", "https://gitlab.com/group/sub/project/-/commit/", true},
+		{"https://bitbucket.org/team/repo", "https://bitbucket.org/team/repo/commits/", true},
+		{"dictybase/dcr-mcp", "https://github.com/dictybase/dcr-mcp/commit/", true},
+		{"https://git.example.org/team/repo", "", false},
+		{"https://github.com/dictybase", "", false},
+		{"/srv/git/repo", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := CommitURLBase(tt.repoURL)
+		assert.Equal(t, tt.ok, ok, tt.repoURL)
+		assert.Equal(t, tt.want, got, tt.repoURL)
+	}
+}
+
+func TestLinkCommits(t *testing.T) {
+	t.Parallel()
+	commits := []Commit{
+		{
+			Hash:    "1111111aaaaaaa",
+			Subject: "feat: filter commits by author name",
+			Message: "feat: filter commits by author name\n",
+		},
+		{
+			Hash:    "2222222bbbbbbb",
+			Subject: "fix: parse dates in the local timezone",
+			Message: "fix: parse dates in the local timezone\n",
+		},
+		{
+			Hash:    "3333333ccccccc",
+			Subject: "docs: describe author filter",
+			Message: "docs: describe author filter\n",
+		},
+	}
+	summary := "# Work Summary\n\n" +
+		"**Feature Enhancements**\n" +
+		"- Added filtering of commits by author. Users can focus on\n" +
+		"contributions from specific team members.\n" +
+		"**Bug Fixes**\n" +
+		"- Fixed date parsing so timezones are handled correctly.\n" +
+		"**Other**\n" +
+		"- Improved overall stability.\n"
+
+	linked := LinkCommits(summary, "git@github.com:dictybase/dcr-mcp.git", commits)
+	assert.Equal(
+		t,
+		"# Work Summary\n\n"+
+			"**Feature Enhancements**\n"+
+			"- Added filtering of commits by author. Users can focus on\n"+
+			"contributions from specific team members.[^1][^2]\n"+
+			"**Bug Fixes**\n"+
+			"- Fixed date parsing so timezones are handled correctly.[^3]\n"+
+			"**Other**\n"+
+			"- Improved overall stability.\n\n"+
+			"[^1]: [`1111111`](https://github.com/dictybase/dcr-mcp/commit/1111111aaaaaaa) feat: filter commits by author name\n"+
+			"[^2]: [`3333333`](https://github.com/dictybase/dcr-mcp/commit/3333333ccccccc) docs: describe author filter\n"+
+			"[^3]: [`2222222`](https://github.com/dictybase/dcr-mcp/commit/2222222bbbbbbb) fix: parse dates in the local timezone\n",
+		linked,
+	)
+	assert.Equal(t, summary, LinkCommits(summary, "https://git.example.org/a/b", commits))
+	assert.Equal(t, summary, LinkCommits(summary, "https://github.com/a/b", nil))
+}
+
+func TestBullets(t *testing.T) {
+	t.Parallel()
+	lines := []string{
+		"# Work Summary",
+		"1. First item",
+		"   continued",
+		"",
+		"* Second item",
+		"**Title**",
+		"not a bullet",
+	}
+	assert.Equal(t, []lineRange{{first: 1, last: 2}, {first: 4, last: 4}}, bullets(lines))
+}
+
+func TestLinkSourceCommits(t *testing.T) {
+	t.Parallel()
+	summary := "# Work Summary\n\n" +
+		"- **Search** Added a strain search to the stock center.\n" +
+		"- **Annotations** Exported GO annotations as GAF files.\n"
+	linked := LinkSourceCommits(summary, []CommitSource{
+		{
+			RepoURL: "https://github.com/dictybase/stock-center",
+			Commits: []Commit{{Hash: "aaaaaaa111", Subject: "feat: strain search", Message: "feat: strain search\n"}},
+		},
+		{
+			RepoURL: "https://gitlab.com/dictybase/annotations",
+			Commits: []Commit{{Hash: "bbbbbbb222", Subject: "feat: export GAF files", Message: "feat: export GAF files\n"}},
+		},
+		{
+			RepoURL: "/srv/git/search",
+			Commits: []Commit{{Hash: "ccccccc333", Subject: "strain search", Message: "strain search\n"}},
+		},
+	})
+	assert.Equal(
+		t,
+		"# Work Summary\n\n"+
+			"- **Search** Added a strain search to the stock center.[^1]\n"+
+			"- **Annotations** Exported GO annotations as GAF files.[^2]\n\n"+
+			"[^1]: [`aaaaaaa`](https://github.com/dictybase/stock-center/commit/aaaaaaa111) feat: strain search\n"+
+			"[^2]: [`bbbbbbb`](https://gitlab.com/dictybase/annotations/-/commit/bbbbbbb222) feat: export GAF files\n",
+		linked,
+	)
+}
+
+func TestShortHash(t *testing.T) {
+	t.Parallel()
+	assert.Equal(t, "1111111", ShortHash("1111111aaaa"))
+	assert.Equal(t, "abc", ShortHash("abc"))
+}
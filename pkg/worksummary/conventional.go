@@ -0,0 +1,223 @@
+package worksummary
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// CategoryOther holds the commits that are not conventional commits or
+// whose type has no category of its own.
+const CategoryOther = "Other"
+
+// conventionalPattern matches the subject of a conventional commit: a
+// type, an optional scope in parentheses, an optional "!" marking a
+// breaking change, and the description.
+var conventionalPattern = regexp.MustCompile(`^([A-Za-z]+)(?:\(([^()]*)\))?(!)?: +(\S.*)$`)
+
+// breakingPattern matches the footer describing a breaking change.
+var breakingPattern = regexp.MustCompile(`(?m)^BREAKING[ -]CHANGE: *(\S.*)$`)
+
+// commitCategories are the categories of conventional commits in the
+// order they are presented, with the types each one holds.
+var commitCategories = []struct {
+	name  string
+	types []string
+}{
+	{name: "Features", types: []string{"feat", "feature"}},
+	{name: "Fixes", types: []string{"fix", "bugfix", "hotfix"}},
+	{name: "Performance", types: []string{"perf"}},
+	{name: "Refactoring", types: []string{"refactor"}},
+	{name: "Documentation", types: []string{"docs", "doc"}},
+	{name: "Tests", types: []string{"test", "tests"}},
+	{name: "Maintenance", types: []string{"chore", "build", "ci", "deps", "style", "revert"}},
+}
+
+// ConventionalCommit is a commit message parsed as a conventional commit,
+// such as "feat(api)!: drop the v1 endpoints".
+type ConventionalCommit struct {
+	// Type is the lowercased type, such as feat or fix.
+	Type        string `json:"type"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	// Breaking marks a breaking change, by a "!" after the type or scope
+	// or by a BREAKING CHANGE footer.
+	Breaking bool `json:"breaking,omitempty"`
+	// BreakingNote is the text of the BREAKING CHANGE footer, if any.
+	BreakingNote string `json:"breaking_note,omitempty"`
+}
+
+// ParseConventional parses a commit message as a conventional commit,
+// reporting false when its subject does not follow the convention.
+func ParseConventional(message string) (ConventionalCommit, bool) {
+	subject, body, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	match := conventionalPattern.FindStringSubmatch(strings.TrimSpace(subject))
+	if match == nil {
+		return ConventionalCommit{}, false
+	}
+	parsed := ConventionalCommit{
+		Type:        strings.ToLower(match[1]),
+		Scope:       strings.TrimSpace(match[2]),
+		Description: strings.TrimSpace(match[4]),
+		Breaking:    match[3] == "!",
+	}
+	if footer := breakingPattern.FindStringSubmatch(body); footer != nil {
+		parsed.Breaking, parsed.BreakingNote = true, strings.TrimSpace(footer[1])
+	}
+	return parsed, true
+}
+
+// CategorizedCommit is a commit as listed under its category.
+type CategorizedCommit struct {
+	Hash        string `json:"hash"`
+	Type        string `json:"type,omitempty"`
+	Scope       string `json:"scope,omitempty"`
+	Description string `json:"description"`
+	Breaking    bool   `json:"breaking,omitempty"`
+	// BreakingNote tells what breaks, from the BREAKING CHANGE footer.
+	BreakingNote string `json:"breaking_note,omitempty"`
+	// URL is the commit page when the repository is on a known host.
+	URL string `json:"url,omitempty"`
+	// message is the commit as sent to the model.
+	message Commit
+}
+
+// CommitCategory is a category of commits, newest first.
+type CommitCategory struct {
+	Name    string              `json:"name"`
+	Commits []CategorizedCommit `json:"commits"`
+}
+
+// Categorized groups commits by their conventional-commit type.
+type Categorized struct {
+	// Breaking lists the breaking changes, which are listed under their
+	// category too.
+	Breaking   []CategorizedCommit `json:"breaking,omitempty"`
+	Categories []CommitCategory    `json:"categories"`
+	// Conventional counts the commits that follow the convention.
+	Conventional int `json:"conventional"`
+	Total        int `json:"total"`
+}
+
+// Categorize groups the commits of sources by their conventional-commit
+// type, in the order of commitCategories with CategoryOther last; empty
+// categories are left out. Commits of a repository on a known host link
+// to their pages.
+func Categorize(sources ...CommitSource) Categorized {
+	byName := make(map[string][]CategorizedCommit)
+	var categorized Categorized
+	for _, source := range sources {
+		base, linked := CommitURLBase(source.RepoURL)
+		for _, commit := range source.Commits {
+			entry := CategorizedCommit{Hash: commit.Hash, Description: commit.Subject, message: commit}
+			if linked {
+				entry.URL = base + commit.Hash
+			}
+			name := CategoryOther
+			if parsed := commit.Conventional; parsed != nil {
+				categorized.Conventional++
+				entry.Type, entry.Scope, entry.Description = parsed.Type, parsed.Scope, parsed.Description
+				entry.Breaking, entry.BreakingNote = parsed.Breaking, parsed.BreakingNote
+				name = categoryName(parsed.Type)
+			}
+			if entry.Breaking {
+				categorized.Breaking = append(categorized.Breaking, entry)
+			}
+			byName[name] = append(byName[name], entry)
+			categorized.Total++
+		}
+	}
+	for _, category := range commitCategories {
+		if commits := byName[category.name]; len(commits) > 0 {
+			categorized.Categories = append(categorized.Categories, CommitCategory{Name: category.name, Commits: commits})
+		}
+	}
+	if commits := byName[CategoryOther]; len(commits) > 0 {
+		categorized.Categories = append(categorized.Categories, CommitCategory{Name: CategoryOther, Commits: commits})
+	}
+	return categorized
+}
+
+// categoryName returns the category of a conventional-commit type.
+func categoryName(commitType string) string {
+	for _, category := range commitCategories {
+		if slices.Contains(category.types, commitType) {
+			return category.name
+		}
+	}
+	return CategoryOther
+}
+
+// Markdown renders the categories as a work summary without a model: the
+// breaking changes first, then a bold category above the commits of each,
+// closed by how many of the commits follow the convention when not all
+// of them do.
+func (c Categorized) Markdown() string {
+	var builder strings.Builder
+	builder.WriteString("# Work Summary\n")
+	if len(c.Breaking) > 0 {
+		builder.WriteString("\n**Breaking Changes**\n")
+		for _, commit := range c.Breaking {
+			commit.Description = cmp.Or(commit.BreakingNote, commit.Description)
+			builder.WriteString(commit.item())
+		}
+	}
+	for _, category := range c.Categories {
+		fmt.Fprintf(&builder, "\n**%s**\n", category.Name)
+		for _, commit := range category.Commits {
+			builder.WriteString(commit.item())
+		}
+	}
+	if c.Conventional < c.Total {
+		fmt.Fprintf(&builder, "\n%d of %s follow the Conventional Commits format.\n",
+			c.Conventional, plural(c.Total, "commit"))
+	}
+	return builder.String()
+}
+
+// item renders the commit as a list item with its scope and hash.
+func (c CategorizedCommit) item() string {
+	text := c.Description
+	if c.Scope != "" {
+		text = fmt.Sprintf("**%s:** %s", c.Scope, text)
+	}
+	hash := "`" + ShortHash(c.Hash) + "`"
+	if c.URL != "" {
+		hash = fmt.Sprintf("[%s](%s)", ShortHash(c.Hash), c.URL)
+	}
+	return fmt.Sprintf("- %s (%s)\n", text, hash)
+}
+
+// Messages concatenates the commit messages as Messages does, grouped
+// under a "Category:" line per category and preceded by a list of the
+// breaking changes, so the model is given the categories rather than
+// inferring them.
+func (c Categorized) Messages() string {
+	var (
+		buf     strings.Builder
+		commits []Commit
+	)
+	if len(c.Breaking) > 0 {
+		buf.WriteString("Breaking changes:\n")
+		for _, commit := range c.Breaking {
+			fmt.Fprintf(&buf, "- %s\n", cmp.Or(commit.BreakingNote, commit.Description))
+		}
+		buf.WriteString("\n")
+	}
+	for i, category := range c.Categories {
+		if i > 0 {
+			buf.WriteString("\n")
+		}
+		fmt.Fprintf(&buf, "Category: %s (%s)\n\n", category.Name, plural(len(category.Commits), "commit"))
+		for _, commit := range category.Commits {
+			writeCommit(&buf, commit.message)
+			commits = append(commits, commit.message)
+		}
+	}
+	if totals := statsTotals(commits); totals != "" {
+		buf.WriteString("\n" + totals)
+	}
+	return buf.String()
+}
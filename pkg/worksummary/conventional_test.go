@@ -0,0 +1,87 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseConventional(t *testing.T) {
+	t.Parallel()
+	for message, want := range map[string]*ConventionalCommit{
+		"feat: add strain search\n": {Type: "feat", Description: "add strain search"},
+		"Fix(stock-center): show the order total": {
+			Type: "fix", Scope: "stock-center", Description: "show the order total",
+		},
+		"refactor(api)!: rename the order fields": {
+			Type: "refactor", Scope: "api", Description: "rename the order fields", Breaking: true,
+		},
+		"chore: drop Go 1.21\n\nBREAKING CHANGE: Go 1.22 is required\n": {
+			Type: "chore", Description: "drop Go 1.21", Breaking: true, BreakingNote: "Go 1.22 is required",
+		},
+		"Add strain search":                       nil,
+		"Merge pull request #12 from dictybase/x": nil,
+		"feat:missing space":                      nil,
+		"feat(api: unclosed scope":                nil,
+	} {
+		parsed, ok := ParseConventional(message)
+		if want == nil {
+			assert.False(t, ok, message)
+			continue
+		}
+		assert.True(t, ok, message)
+		assert.Equal(t, *want, parsed, message)
+	}
+}
+
+func TestCategorize(t *testing.T) {
+	t.Parallel()
+	commit := func(hash, message string) Commit {
+		commit := Commit{Hash: hash, Subject: message, Message: message + "\n"}
+		if parsed, ok := ParseConventional(message); ok {
+			commit.Conventional = &parsed
+		}
+		return commit
+	}
+	categorized := Categorize(CommitSource{
+		RepoURL: "https://github.com/dictybase/modware-stock",
+		Commits: []Commit{
+			commit("1111111aaaa", "fix(order): keep the cart on reload"),
+			commit("2222222bbbb", "Update README"),
+			commit("3333333cccc", "feat(api)!: page the strain list"),
+			commit("4444444dddd", "chore: bump go-git"),
+			commit("5555555eeee", "feat: add plasmid search"),
+		},
+	})
+	assert.Equal(t, 5, categorized.Total)
+	assert.Equal(t, 4, categorized.Conventional)
+	names := make([]string, 0, len(categorized.Categories))
+	for _, category := range categorized.Categories {
+		names = append(names, category.Name)
+	}
+	assert.Equal(t, []string{"Features", "Fixes", "Maintenance", CategoryOther}, names)
+
+	base := "https://github.com/dictybase/modware-stock/commit/"
+	assert.Equal(t,
+		"# Work Summary\n\n"+
+			"**Breaking Changes**\n- **api:** page the strain list ([3333333]("+base+"3333333cccc))\n\n"+
+			"**Features**\n- **api:** page the strain list ([3333333]("+base+"3333333cccc))\n"+
+			"- add plasmid search ([5555555]("+base+"5555555eeee))\n\n"+
+			"**Fixes**\n- **order:** keep the cart on reload ([1111111]("+base+"1111111aaaa))\n\n"+
+			"**Maintenance**\n- bump go-git ([4444444]("+base+"4444444dddd))\n\n"+
+			"**Other**\n- Update README ([2222222]("+base+"2222222bbbb))\n\n"+
+			"4 of 5 commits follow the Conventional Commits format.\n",
+		categorized.Markdown(),
+	)
+	assert.Equal(t,
+		"Breaking changes:\n- page the strain list\n\n"+
+			"Category: Features (2 commits)\n\nfeat(api)!: page the strain list\nfeat: add plasmid search\n\n"+
+			"Category: Fixes (1 commit)\n\nfix(order): keep the cart on reload\n\n"+
+			"Category: Maintenance (1 commit)\n\nchore: bump go-git\n\n"+
+			"Category: Other (1 commit)\n\nUpdate README\n",
+		categorized.Messages(),
+	)
+
+	unlinked := Categorize(CommitSource{Commits: []Commit{commit("6666666ffff", "docs: explain tokens")}})
+	assert.Equal(t, "# Work Summary\n\n**Documentation**\n- explain tokens (`6666666`)\n", unlinked.Markdown())
+}
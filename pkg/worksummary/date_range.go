@@ -0,0 +1,149 @@
+package worksummary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/markusmobius/go-dateparser/date"
+)
+
+// DateRange is the resolved span of an analysis together with the inputs
+// it was read from, so reports can show the range actually used.
+type DateRange struct {
+	Start      time.Time
+	End        time.Time
+	StartInput string
+	EndInput   string
+	// StartPeriod and EndPeriod are the precision the inputs were parsed
+	// with; EndPeriod is date.None when no end date was given.
+	StartPeriod date.Period
+	EndPeriod   date.Period
+}
+
+// ResolveDateRange parses the start and end dates and widens each to the
+// period it names, so "last month" starts on the first of that month and
+// an end date includes its whole day. Without an end date the range ends
+// with the start date's month or year when the start names one, and now
+// otherwise.
+func (ga *GitAnalyzer) ResolveDateRange(startInput, endInput string) (DateRange, error) {
+	start, end, err := ga.ParseAnalysisDates(startInput, endInput)
+	if err != nil {
+		return DateRange{}, err
+	}
+	location := ga.dateConfig.DefaultTimezone
+	dateRange := DateRange{
+		Start:       periodStart(start.Time.In(location), start.Period),
+		StartInput:  startInput,
+		EndInput:    endInput,
+		StartPeriod: start.Period,
+	}
+	switch {
+	case endInput != "":
+		dateRange.EndPeriod = end.Period
+		dateRange.End = periodEnd(end.Time.In(location), end.Period)
+	case start.Period == date.Month || start.Period == date.Year:
+		dateRange.End = periodEnd(start.Time.In(location), start.Period)
+	default:
+		dateRange.End = end.Time.In(location)
+	}
+	if dateRange.End.Before(dateRange.Start) {
+		return DateRange{}, fmt.Errorf(
+			"end date %s is before start date %s",
+			dateRange.End.Format(time.DateOnly),
+			dateRange.Start.Format(time.DateOnly),
+		)
+	}
+	return dateRange, nil
+}
+
+// Header renders the range as markdown for the top of a report. It spells
+// out how each input was read, e.g. "last month" as 2025-06-01..2025-06-30.
+func (r DateRange) Header() string {
+	var builder strings.Builder
+	fmt.Fprintf(
+		&builder,
+		"**Date range:** %s to %s (%s)\n\n",
+		r.Start.Format("2006-01-02 15:04"),
+		r.End.Format("2006-01-02 15:04"),
+		zoneName(r.Start),
+	)
+	fmt.Fprintf(
+		&builder,
+		"- start_date %q read as %s (%s)\n",
+		r.StartInput,
+		span(r.Start, r.StartPeriod),
+		periodName(r.StartPeriod),
+	)
+	switch {
+	case r.EndInput != "":
+		fmt.Fprintf(
+			&builder,
+			"- end_date %q read as %s (%s)\n",
+			r.EndInput,
+			span(periodStart(r.End, r.EndPeriod), r.EndPeriod),
+			periodName(r.EndPeriod),
+		)
+	case r.StartPeriod == date.Month || r.StartPeriod == date.Year:
+		fmt.Fprintf(&builder, "- end_date not given, using the end of that %s\n", periodName(r.StartPeriod))
+	default:
+		builder.WriteString("- end_date not given, using the current time\n")
+	}
+	return builder.String()
+}
+
+// periodStart returns the beginning of the period containing t.
+func periodStart(t time.Time, period date.Period) time.Time {
+	switch period {
+	case date.Year:
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, t.Location())
+	case date.Month:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, t.Location())
+	case date.Day:
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	}
+	return t
+}
+
+// periodEnd returns the last instant of the period containing t.
+func periodEnd(t time.Time, period date.Period) time.Time {
+	start := periodStart(t, period)
+	switch period {
+	case date.Year:
+		return start.AddDate(1, 0, 0).Add(-time.Nanosecond)
+	case date.Month:
+		return start.AddDate(0, 1, 0).Add(-time.Nanosecond)
+	case date.Day:
+		return start.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return t
+}
+
+// span formats the period starting at start, e.g. 2025-06-01..2025-06-30.
+func span(start time.Time, period date.Period) string {
+	switch period {
+	case date.Year, date.Month:
+		return start.Format(time.DateOnly) + ".." + periodEnd(start, period).Format(time.DateOnly)
+	case date.Day:
+		return start.Format(time.DateOnly)
+	}
+	return start.Format("2006-01-02 15:04")
+}
+
+// periodName names a period in lower case, e.g. "month".
+func periodName(period date.Period) string {
+	if period == date.None {
+		return "exact time"
+	}
+	return strings.ToLower(period.String())
+}
+
+// zoneName describes the time zone of t, e.g. "Europe/Berlin, UTC+02:00".
+// The process-local zone is named by its abbreviation.
+func zoneName(t time.Time) string {
+	name := t.Location().String()
+	if name == "Local" {
+		name = t.Format("MST")
+	}
+	return name + ", UTC" + t.Format("-07:00")
+}
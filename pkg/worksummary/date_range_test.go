@@ -0,0 +1,81 @@
+package worksummary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/markusmobius/go-dateparser/date"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveDateRange(t *testing.T) {
+	t.Parallel()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	now := time.Date(2025, 7, 15, 14, 30, 0, 0, berlin)
+	analyzer := NewGitAnalyzer(WithCurrentTime(now), WithTimeZone(berlin))
+
+	tests := map[string]struct {
+		start, end string
+		expected   [2]time.Time
+	}{
+		"last month": {
+			start: "last month",
+			expected: [2]time.Time{
+				time.Date(2025, 6, 1, 0, 0, 0, 0, berlin),
+				time.Date(2025, 6, 30, 23, 59, 59, 999999999, berlin),
+			},
+		},
+		"day until now": {
+			start:    "2025-07-01",
+			expected: [2]time.Time{time.Date(2025, 7, 1, 0, 0, 0, 0, berlin), now},
+		},
+		"whole end day": {
+			start: "2025-07-01",
+			end:   "2025-07-10",
+			expected: [2]time.Time{
+				time.Date(2025, 7, 1, 0, 0, 0, 0, berlin),
+				time.Date(2025, 7, 10, 23, 59, 59, 999999999, berlin),
+			},
+		},
+	}
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			dateRange, err := analyzer.ResolveDateRange(tc.start, tc.end)
+			require.NoError(t, err)
+			assert.True(t, tc.expected[0].Equal(dateRange.Start), "start %s", dateRange.Start)
+			assert.True(t, tc.expected[1].Equal(dateRange.End), "end %s", dateRange.End)
+		})
+	}
+
+	_, err = analyzer.ResolveDateRange("2025-07-10", "2025-07-01")
+	require.Error(t, err, "the end must not precede the start")
+}
+
+func TestDateRange_Header(t *testing.T) {
+	t.Parallel()
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	require.NoError(t, err)
+	header := DateRange{
+		Start:       time.Date(2025, 6, 1, 0, 0, 0, 0, berlin),
+		End:         time.Date(2025, 6, 30, 23, 59, 59, 999999999, berlin),
+		StartInput:  "last month",
+		StartPeriod: date.Month,
+	}.Header()
+	assert.Contains(t, header, "**Date range:** 2025-06-01 00:00 to 2025-06-30 23:59 (Europe/Berlin, UTC+02:00)")
+	assert.Contains(t, header, `start_date "last month" read as 2025-06-01..2025-06-30 (month)`)
+	assert.Contains(t, header, "end_date not given, using the end of that month")
+
+	header = DateRange{
+		Start:       time.Date(2025, 7, 1, 0, 0, 0, 0, time.UTC),
+		End:         time.Date(2025, 7, 10, 23, 59, 59, 0, time.UTC),
+		StartInput:  "July 1",
+		EndInput:    "yesterday",
+		StartPeriod: date.Day,
+		EndPeriod:   date.Day,
+	}.Header()
+	assert.Contains(t, header, "(UTC, UTC+00:00)")
+	assert.Contains(t, header, `end_date "yesterday" read as 2025-07-10 (day)`)
+}
@@ -0,0 +1,126 @@
+package worksummary
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// maxStatsDirectories is the number of directories named in the stats
+	// line of a commit.
+	maxStatsDirectories = 3
+	// maxHotspots is the number of directories named in the totals of a
+	// range.
+	maxHotspots = 5
+)
+
+// DiffStats is the size of the change a commit made to its first parent.
+type DiffStats struct {
+	Files      int
+	Insertions int
+	Deletions  int
+	// Directories are the directories of the changed files, most changed
+	// lines first; "." is the root of the repository.
+	Directories []string
+}
+
+// diffStats computes the stats of cmt against its first parent, or against
+// the empty tree for a root commit.
+func diffStats(ctx context.Context, cmt *object.Commit) (*DiffStats, error) {
+	fileStats, err := cmt.StatsContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error computing stats of commit %s: %w", cmt.Hash, err)
+	}
+	stats := &DiffStats{Files: len(fileStats)}
+	lines := make(map[string]int)
+	for _, file := range fileStats {
+		stats.Insertions += file.Addition
+		stats.Deletions += file.Deletion
+		dir := path.Dir(file.Name)
+		if _, ok := lines[dir]; !ok {
+			stats.Directories = append(stats.Directories, dir)
+		}
+		lines[dir] += file.Addition + file.Deletion
+	}
+	slices.SortStableFunc(stats.Directories, func(a, b string) int {
+		return cmp.Or(cmp.Compare(lines[b], lines[a]), strings.Compare(a, b))
+	})
+	return stats, nil
+}
+
+// String renders the stats like git's shortstat, followed by the most
+// changed directories, e.g. "2 files changed, 10 insertions(+), 3
+// deletions(-) in pkg/worksummary, cmd/server".
+func (s DiffStats) String() string {
+	text := fmt.Sprintf(
+		"%s changed, %s(+), %s(-)",
+		plural(s.Files, "file"), plural(s.Insertions, "insertion"), plural(s.Deletions, "deletion"),
+	)
+	if len(s.Directories) == 0 {
+		return text
+	}
+	dirs := s.Directories[:min(len(s.Directories), maxStatsDirectories)]
+	text += " in " + strings.Join(dirs, ", ")
+	if more := len(s.Directories) - len(dirs); more > 0 {
+		text += fmt.Sprintf(" and %d more", more)
+	}
+	return text
+}
+
+// statsTotals renders the totals of the commits with stats and the
+// directories most of them touched, or nothing when none has stats.
+func statsTotals(commits []Commit) string {
+	var (
+		total   DiffStats
+		counted int
+		touched = make(map[string]int)
+		dirs    []string
+	)
+	for _, commit := range commits {
+		if commit.Stats == nil {
+			continue
+		}
+		counted++
+		total.Files += commit.Stats.Files
+		total.Insertions += commit.Stats.Insertions
+		total.Deletions += commit.Stats.Deletions
+		for _, dir := range commit.Stats.Directories {
+			if touched[dir] == 0 {
+				dirs = append(dirs, dir)
+			}
+			touched[dir]++
+		}
+	}
+	if counted == 0 {
+		return ""
+	}
+	slices.SortStableFunc(dirs, func(a, b string) int {
+		return cmp.Or(cmp.Compare(touched[b], touched[a]), strings.Compare(a, b))
+	})
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "Totals: %s, %s\n", plural(counted, "commit"), DiffStats{
+		Files: total.Files, Insertions: total.Insertions, Deletions: total.Deletions,
+	})
+	hotspots := make([]string, 0, maxHotspots)
+	for _, dir := range dirs[:min(len(dirs), maxHotspots)] {
+		hotspots = append(hotspots, fmt.Sprintf("%s (%s)", dir, plural(touched[dir], "commit")))
+	}
+	if len(hotspots) > 0 {
+		fmt.Fprintf(&builder, "Most changed directories: %s\n", strings.Join(hotspots, ", "))
+	}
+	return builder.String()
+}
+
+// plural renders a count of things, such as "1 file" or "2 files".
+func plural(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
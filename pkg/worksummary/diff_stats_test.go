@@ -0,0 +1,88 @@
+package worksummary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitFiles commits files, by path relative to dir, with the given
+// contents.
+func commitFiles(t *testing.T, repo *git.Repository, dir, message string, when time.Time, files map[string]string) {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	for name, content := range files {
+		require.NoError(t, os.MkdirAll(filepath.Join(dir, filepath.Dir(name)), 0o755))
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), []byte(content), 0o600))
+		_, err = worktree.Add(name)
+		require.NoError(t, err)
+	}
+	signature := &object.Signature{Name: "Jane Doe", Email: "jane@example.org", When: when}
+	_, err = worktree.Commit(message, &git.CommitOptions{Author: signature, Committer: signature})
+	require.NoError(t, err)
+}
+
+func TestListAuthorCommits_Stats(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC) }
+	commitFiles(t, repo, dir, "Add stock search\n", day(2), map[string]string{
+		"README.md":                "stock\n",
+		"pkg/stock/search.go":      "a\nb\nc\n",
+		"pkg/stock/search_test.go": "a\n",
+	})
+	commitFiles(t, repo, dir, "Fix stock search\n", day(3), map[string]string{
+		"pkg/stock/search.go": "a\nB\nc\nd\n",
+		"cmd/server/main.go":  "main\n",
+	})
+
+	params := CommitRangeParams{
+		Repo:    repo,
+		Start:   day(1),
+		End:     day(30),
+		Authors: AuthorFilter{Include: []string{"jane"}},
+		Stats:   true,
+	}
+	commits, err := NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, commits, 2)
+	assert.Equal(t, &DiffStats{
+		Files: 2, Insertions: 3, Deletions: 1, Directories: []string{"pkg/stock", "cmd/server"},
+	}, commits[0].Stats)
+	assert.Equal(t, &DiffStats{
+		Files: 3, Insertions: 5, Directories: []string{"pkg/stock", "."},
+	}, commits[1].Stats, "a root commit is compared to the empty tree")
+
+	assert.Equal(t,
+		"Fix stock search\n"+
+			"Stats: 2 files changed, 3 insertions(+), 1 deletion(-) in pkg/stock, cmd/server\n"+
+			"Add stock search\n"+
+			"Stats: 3 files changed, 5 insertions(+), 0 deletions(-) in pkg/stock, .\n"+
+			"\nTotals: 2 commits, 5 files changed, 8 insertions(+), 1 deletion(-)\n"+
+			"Most changed directories: pkg/stock (2 commits), . (1 commit), cmd/server (1 commit)\n",
+		Messages(commits),
+	)
+
+	params.Stats = false
+	commits, err = NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	assert.Nil(t, commits[0].Stats)
+	assert.Equal(t, "Fix stock search\nAdd stock search\n", Messages(commits))
+}
+
+func TestDiffStats_String(t *testing.T) {
+	t.Parallel()
+	stats := DiffStats{Files: 1, Insertions: 1, Deletions: 2, Directories: []string{"a", "b", "c", "d", "e"}}
+	assert.Equal(t, "1 file changed, 1 insertion(+), 2 deletions(-) in a, b, c and 2 more", stats.String())
+	assert.Equal(t, "0 files changed, 0 insertions(+), 0 deletions(-)", DiffStats{}.String())
+}
@@ -0,0 +1,86 @@
+package worksummary
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// maxSummaryBullets is the number of bullet points GitSummaryPrompt allows.
+const maxSummaryBullets = 4
+
+// CheckFormat returns how summary departs from the structure
+// GitSummaryPrompt asks for: an H1 heading and at most four bullet points,
+// each under a bold category. The category may start the bullet or stand
+// on its own line above a group of bullets. A well-formed summary has no
+// problems.
+func CheckFormat(summary string) []string {
+	lines := strings.Split(summary, "\n")
+	var problems []string
+	if !slices.ContainsFunc(lines, func(line string) bool {
+		return strings.HasPrefix(strings.TrimSpace(line), "# ")
+	}) {
+		problems = append(problems, `it has no H1 heading such as "# Work Summary"`)
+	}
+	var bullets, uncategorized int
+	inCategory := false
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case strings.HasPrefix(trimmed, "#"):
+			inCategory = false
+		case isListItem(trimmed):
+			// Indented items belong to the bullet above them.
+			if len(line)-len(strings.TrimLeft(line, " \t")) >= 2 {
+				continue
+			}
+			bullets++
+			if !inCategory && !startsBold(listItemText(trimmed)) {
+				uncategorized++
+			}
+		case startsBold(trimmed):
+			inCategory = true
+		}
+	}
+	switch {
+	case bullets == 0:
+		problems = append(problems, "it has no bullet points")
+	case bullets > maxSummaryBullets:
+		problems = append(problems, fmt.Sprintf(
+			"it has %d bullet points, at most %d are allowed", bullets, maxSummaryBullets,
+		))
+	}
+	if uncategorized > 0 {
+		problems = append(problems, fmt.Sprintf(
+			`%d of its bullet points do not begin with a bold category such as "**Performance**"`, uncategorized,
+		))
+	}
+	return problems
+}
+
+// listItemText returns a list item without its marker.
+func listItemText(item string) string {
+	if _, text, found := strings.Cut(item, " "); found {
+		return strings.TrimSpace(text)
+	}
+	return item
+}
+
+// startsBold tells whether text begins with a bold span.
+func startsBold(text string) bool {
+	return strings.HasPrefix(text, "**") && strings.Contains(text[2:], "**")
+}
+
+// revisionPrompt asks the model to rewrite a summary that has problems.
+func revisionPrompt(problems []string) string {
+	var prompt strings.Builder
+	prompt.WriteString("Your summary does not follow the required format:\n")
+	for _, problem := range problems {
+		prompt.WriteString("- " + problem + "\n")
+	}
+	prompt.WriteString(
+		"\nRewrite it with \"Work Summary\" as the H1 heading and not more than four bullet points, " +
+			"each beginning with a bold category. Reply with the summary only.",
+	)
+	return prompt.String()
+}
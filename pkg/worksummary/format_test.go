@@ -0,0 +1,49 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		summary  string
+		problems []string
+	}{
+		{
+			name:    "bold category per bullet",
+			summary: "# Work Summary\n\n- **Docs** Fixed typos.\n- **Performance** Faster search.\n  - nested detail",
+		},
+		{
+			name:    "bold category above bullets",
+			summary: "# Work Summary\n\n**Feature Enhancements**\n- Added new features\n- Added more features",
+		},
+		{
+			name:     "missing heading",
+			summary:  "- **Docs** Fixed typos.",
+			problems: []string{`it has no H1 heading such as "# Work Summary"`},
+		},
+		{
+			name:    "too many bullets without categories",
+			summary: "# Work Summary\n\n- one\n- two\n- three\n- four\n- **Five** five",
+			problems: []string{
+				"it has 5 bullet points, at most 4 are allowed",
+				`4 of its bullet points do not begin with a bold category such as "**Performance**"`,
+			},
+		},
+		{
+			name:     "prose only",
+			summary:  "# Work Summary\n\nLots of work was done.",
+			problems: []string{"it has no bullet points"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			assert.Equal(t, tt.problems, CheckFormat(tt.summary))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package worksummary
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/sashabaranov/go-openai"
+)
+
+// ErrNoFunctionCall is returned when the model answers without calling one
+// of the offered functions.
+var ErrNoFunctionCall = errors.New("the model did not call a function")
+
+// Function describes a function the model may call.
+type Function struct {
+	Name        string
+	Description string
+	// Parameters is the JSON schema of the function arguments.
+	Parameters any
+}
+
+// FunctionCall is the function the model chose to call.
+type FunctionCall struct {
+	Name string
+	// Arguments is the JSON object of arguments the model generated.
+	Arguments string
+	// Reasoning is any text the model wrote next to the call.
+	Reasoning string
+}
+
+// CallFunction asks the model to answer prompt by calling exactly one of
+// functions. The model must call a function, and the functions whose
+// schemas allow it are sent in strict mode, so the provider constrains the
+// arguments to the schema while they are decoded. Optional arguments then
+// come as null when the model leaves them out.
+func (c *OpenAIClient) CallFunction(
+	ctx context.Context,
+	system, prompt string,
+	functions []Function,
+) (call FunctionCall, err error) {
+	if len(functions) == 0 {
+		return FunctionCall{}, errors.New("no functions to call")
+	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOutbound(metrics.ServiceOpenAI, start, err)
+	}()
+	tools := make([]openai.Tool, 0, len(functions))
+	for _, function := range functions {
+		definition := &openai.FunctionDefinition{
+			Name:        function.Name,
+			Description: function.Description,
+			Parameters:  function.Parameters,
+		}
+		if schema, ok := strictSchema(function.Parameters); ok {
+			definition.Parameters = schema
+			definition.Strict = true
+		}
+		tools = append(tools, openai.Tool{Type: openai.ToolTypeFunction, Function: definition})
+	}
+	request := chatCompletionRequest(c.model, 0, system, prompt)
+	request.Tools = tools
+	request.ToolChoice = "required"
+	request.ParallelToolCalls = false
+	resp, err := c.client.CreateChatCompletion(ctx, request)
+	if err != nil {
+		return FunctionCall{}, fmt.Errorf("OpenAI completion error: %w", err)
+	}
+	if len(resp.Choices) == 0 || len(resp.Choices[0].Message.ToolCalls) == 0 {
+		return FunctionCall{}, ErrNoFunctionCall
+	}
+	message := resp.Choices[0].Message
+	return FunctionCall{
+		Name:      message.ToolCalls[0].Function.Name,
+		Arguments: message.ToolCalls[0].Function.Arguments,
+		Reasoning: message.Content,
+	}, nil
+}
+
+// strictSchema rewrites a JSON schema of function arguments into the form
+// strict mode accepts: every object lists all its properties as required
+// and allows no others, and the properties that were optional also accept
+// null. It reports false for schemas strict mode cannot express, such as
+// objects with free-form properties.
+func strictSchema(parameters any) (map[string]any, bool) {
+	data, err := json.Marshal(parameters)
+	if err != nil {
+		return nil, false
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(data, &schema); err != nil {
+		return nil, false
+	}
+	if !strictify(schema) {
+		return nil, false
+	}
+	return schema, true
+}
+
+// strictify rewrites schema and the schemas nested in it in place for
+// strict mode, and reports whether strict mode can express it.
+func strictify(schema map[string]any) bool {
+	_, typed := schema["type"]
+	_, anyOf := schema["anyOf"]
+	if !typed && !anyOf {
+		return false
+	}
+	// Strict mode rejects defaults; the tools apply their own.
+	delete(schema, "default")
+	if items, ok := schema["items"].(map[string]any); ok && !strictify(items) {
+		return false
+	}
+	if schema["type"] != "object" {
+		return true
+	}
+	properties, _ := schema["properties"].(map[string]any)
+	if len(properties) == 0 || schema["additionalProperties"] == true {
+		return false
+	}
+	required := make(map[string]bool)
+	if names, ok := schema["required"].([]any); ok {
+		for _, name := range names {
+			if name, ok := name.(string); ok {
+				required[name] = true
+			}
+		}
+	}
+	names := make([]string, 0, len(properties))
+	for name, property := range properties {
+		property, ok := property.(map[string]any)
+		if !ok || !strictify(property) {
+			return false
+		}
+		if !required[name] {
+			nullable(property)
+		}
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	schema["required"] = names
+	schema["additionalProperties"] = false
+	return true
+}
+
+// nullable lets a property schema accept null as well.
+func nullable(property map[string]any) {
+	switch kind := property["type"].(type) {
+	case string:
+		property["type"] = []any{kind, "null"}
+	case []any:
+		if !slices.Contains(kind, "null") {
+			property["type"] = append(kind, "null")
+		}
+	}
+	if values, ok := property["enum"].([]any); ok && !slices.Contains(values, nil) {
+		property["enum"] = append(values, nil)
+	}
+}
@@ -0,0 +1,134 @@
+package worksummary
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// completion serves a chat completion whose message is message.
+func completion(t *testing.T, message map[string]any, requests *[]map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var request map[string]any
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		*requests = append(*requests, request)
+		w.Header().Set("Content-Type", "application/json")
+		assert.NoError(t, json.NewEncoder(w).Encode(map[string]any{
+			"choices": []map[string]any{{"index": 0, "message": message}},
+		}))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCallFunction(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{
+		"role":    "assistant",
+		"content": "The user wants a summary.",
+		"tool_calls": []map[string]any{{
+			"id":       "call_1",
+			"type":     "function",
+			"function": map[string]any{"name": "git-summary", "arguments": `{"repo_url":"https://github.com/a/b"}`},
+		}},
+	}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	call, err := client.CallFunction(context.Background(), "pick a tool", "summarize a/b", []Function{{
+		Name:        "git-summary",
+		Description: "Summarizes commits",
+		Parameters:  map[string]any{"type": "object"},
+	}})
+	require.NoError(t, err)
+	assert.Equal(t, FunctionCall{
+		Name:      "git-summary",
+		Arguments: `{"repo_url":"https://github.com/a/b"}`,
+		Reasoning: "The user wants a summary.",
+	}, call)
+	require.Len(t, requests, 1)
+	assert.Equal(t, "required", requests[0]["tool_choice"])
+	tools, ok := requests[0]["tools"].([]any)
+	require.True(t, ok)
+	require.Len(t, tools, 1)
+	function := tools[0].(map[string]any)["function"].(map[string]any)
+	assert.NotContains(t, function, "strict", "objects without properties cannot be strict")
+
+	_, err = client.CallFunction(context.Background(), "pick a tool", "summarize a/b", nil)
+	require.Error(t, err, "at least one function is required")
+}
+
+func TestCallFunction_NoCall(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{"role": "assistant", "content": "I cannot help."}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.CallFunction(context.Background(), "pick a tool", "bake a cake", []Function{{
+		Name:       "git-summary",
+		Parameters: map[string]any{"type": "object"},
+	}})
+	require.ErrorIs(t, err, ErrNoFunctionCall)
+}
+
+func TestCallFunction_Strict(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{
+		"role": "assistant",
+		"tool_calls": []map[string]any{{
+			"id":       "call_1",
+			"type":     "function",
+			"function": map[string]any{"name": "git-summary", "arguments": `{"repo_url":"a/b","branch":null}`},
+		}},
+	}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.CallFunction(context.Background(), "pick a tool", "summarize a/b", []Function{{
+		Name: "git-summary",
+		Parameters: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"repo_url": map[string]any{"type": "string"},
+				"branch":   map[string]any{"type": "string", "default": "main"},
+				"format":   map[string]any{"type": "string", "enum": []string{"markdown", "html"}},
+			},
+			"required": []string{"repo_url"},
+		},
+	}})
+	require.NoError(t, err)
+	require.Len(t, requests, 1)
+	function := requests[0]["tools"].([]any)[0].(map[string]any)["function"].(map[string]any)
+	assert.Equal(t, true, function["strict"])
+	assert.Equal(t, map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"repo_url": map[string]any{"type": "string"},
+			"branch":   map[string]any{"type": []any{"string", "null"}},
+			"format":   map[string]any{"type": []any{"string", "null"}, "enum": []any{"markdown", "html", nil}},
+		},
+		"required":             []any{"branch", "format", "repo_url"},
+		"additionalProperties": false,
+	}, function["parameters"])
+}
+
+func TestStrictSchema_Unsupported(t *testing.T) {
+	t.Parallel()
+	for _, parameters := range []map[string]any{
+		{"type": "object"},
+		{"type": "object", "properties": map[string]any{"options": map[string]any{"type": "object"}}},
+		{"type": "object", "properties": map[string]any{"value": map[string]any{"description": "anything"}}},
+	} {
+		_, ok := strictSchema(parameters)
+		assert.False(t, ok, parameters)
+	}
+}
@@ -0,0 +1,93 @@
+package worksummary
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+
+	"github.com/sashabaranov/go-openai"
+)
+
+const (
+	// defaultTemperature keeps summaries focused while allowing some
+	// variation in wording.
+	defaultTemperature float32 = 0.1
+	// ReproducibleSeed is the sampling seed used in reproducible mode.
+	ReproducibleSeed = 42
+)
+
+// GenerationParams are the settings a summary was generated with. Stored
+// next to a report, they tell whether a regenerated report used the same
+// model, prompt and input as the original.
+type GenerationParams struct {
+	Model        string  `json:"model"`
+	Temperature  float32 `json:"temperature"`
+	Seed         *int    `json:"seed,omitempty"`
+	Reproducible bool    `json:"reproducible"`
+	// PromptSHA256 is the hash of the system prompt.
+	PromptSHA256 string `json:"promptSha256"`
+	// InputSHA256 is the hash of the commit messages sent to the model.
+	InputSHA256 string `json:"inputSha256"`
+	// Revised is set when the first summary broke the required format and
+	// the model rewrote it.
+	Revised bool `json:"revised,omitempty"`
+}
+
+// WithReproducible turns on reproducible mode: temperature 0 and a fixed
+// seed, so repeated runs on the same input give the same summary as far as
+// the provider supports it.
+func WithReproducible(enabled bool) OpenAIClientOption {
+	return func(c *OpenAIClient) {
+		c.reproducible = enabled
+	}
+}
+
+// GenerationParams returns the settings used to summarize commitMsgs.
+func (c *OpenAIClient) GenerationParams(commitMsgs string) GenerationParams {
+	params := GenerationParams{
+		Model:        c.model,
+		Temperature:  defaultTemperature,
+		Reproducible: c.reproducible,
+		PromptSHA256: sha256Hex(GitSummaryPrompt),
+		InputSHA256:  sha256Hex(commitMsgs),
+	}
+	if c.reproducible {
+		seed := ReproducibleSeed
+		params.Temperature = 0
+		params.Seed = &seed
+	}
+	return params
+}
+
+// chatRequest builds the streaming completion request for commitMsgs.
+func (c *OpenAIClient) chatRequest(commitMsgs string) openai.ChatCompletionRequest {
+	params := c.GenerationParams(commitMsgs)
+	request := chatCompletionRequest(params.Model, params.Temperature, GitSummaryPrompt, commitMsgs)
+	request.Stream = true
+	request.Seed = params.Seed
+	return request
+}
+
+// chatCompletionRequest builds a completion request for model with a
+// system and a user message. A zero temperature is dropped from the
+// request JSON, which leaves the provider default in place, so it is sent
+// as the smallest positive temperature instead.
+func chatCompletionRequest(model string, temperature float32, system, user string) openai.ChatCompletionRequest {
+	if temperature == 0 {
+		temperature = math.SmallestNonzeroFloat32
+	}
+	return openai.ChatCompletionRequest{
+		Model:       model,
+		Temperature: temperature,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: system},
+			{Role: openai.ChatMessageRoleUser, Content: user},
+		},
+	}
+}
+
+// sha256Hex returns the hex-encoded SHA-256 hash of text.
+func sha256Hex(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,41 @@
+package worksummary
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGenerationParams(t *testing.T) {
+	t.Parallel()
+	client, err := NewOpenAIClient("test-key", WithModel("test/model"))
+	require.NoError(t, err)
+	params := client.GenerationParams("fix: typo")
+	assert.Equal(t, "test/model", params.Model)
+	assert.InDelta(t, 0.1, params.Temperature, 1e-6)
+	assert.Nil(t, params.Seed)
+	assert.False(t, params.Reproducible)
+	assert.Len(t, params.PromptSHA256, 64)
+	assert.Equal(t, sha256Hex("fix: typo"), params.InputSHA256)
+	assert.NotEqual(t, params.InputSHA256, client.GenerationParams("feat: x").InputSHA256)
+
+	client, err = NewOpenAIClient("test-key", WithReproducible(true))
+	require.NoError(t, err)
+	params = client.GenerationParams("fix: typo")
+	assert.True(t, params.Reproducible)
+	assert.Zero(t, params.Temperature)
+	require.NotNil(t, params.Seed)
+	assert.Equal(t, ReproducibleSeed, *params.Seed)
+
+	request := client.chatRequest("fix: typo")
+	assert.Positive(t, request.Temperature, "zero must survive JSON encoding")
+	assert.Equal(t, params.Seed, request.Seed)
+	assert.True(t, request.Stream)
+	require.Len(t, request.Messages, 2)
+	assert.Equal(t, GitSummaryPrompt, request.Messages[0].Content)
+	assert.Equal(t, "fix: typo", request.Messages[1].Content)
+
+	request = chatCompletionRequest("test/model", 0.1, "system", "user")
+	assert.InDelta(t, 0.1, request.Temperature, 1e-6, "other temperatures are sent as they are")
+}
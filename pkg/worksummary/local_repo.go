@@ -0,0 +1,69 @@
+package worksummary
+
+import (
+	"fmt"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage"
+)
+
+// branchHead is the storage of a local repository with HEAD pointing at a
+// branch, so the history of the branch is read without checking it out
+// and without touching the working tree.
+type branchHead struct {
+	storage.Storer
+	branch plumbing.ReferenceName
+}
+
+// Reference returns the branch for HEAD and the stored reference for any
+// other name.
+func (b branchHead) Reference(name plumbing.ReferenceName) (*plumbing.Reference, error) {
+	if name == plumbing.HEAD {
+		return plumbing.NewSymbolicReference(plumbing.HEAD, b.branch), nil
+	}
+	return b.Storer.Reference(name)
+}
+
+// openLocal opens the repository at dir, or containing dir, in place of a
+// clone. The branch is a local branch or, failing that, a branch of the
+// origin remote. A directory that is not a repository, or a repository
+// without the branch, is an invalid input error.
+func (ga *GitAnalyzer) openLocal(dir, branchName string) (*Clone, error) {
+	repo, err := git.PlainOpenWithOptions(dir, &git.PlainOpenOptions{
+		DetectDotGit:          true,
+		EnableDotGitCommonDir: true,
+	})
+	if err != nil {
+		return nil, &toolerror.Error{
+			Type:    toolerror.TypeInvalidInput,
+			Code:    "INVALID_REPO_PATH",
+			Message: fmt.Sprintf("cannot open the local repository %s: %v", dir, err),
+			Err:     err,
+		}
+	}
+	var branch plumbing.ReferenceName
+	for _, name := range []plumbing.ReferenceName{
+		plumbing.NewBranchReferenceName(branchName),
+		plumbing.NewRemoteReferenceName(git.DefaultRemoteName, branchName),
+	} {
+		if _, err := repo.Reference(name, true); err == nil {
+			branch = name
+			break
+		}
+	}
+	if branch == "" {
+		return nil, branchNotFound("the local repository "+dir, branchName, nil)
+	}
+	view, err := git.Open(branchHead{Storer: repo.Storer, branch: branch}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error opening local repository %s: %w", dir, err)
+	}
+	clone := &Clone{Repository: view, Storage: StorageLocal}
+	if remote, err := repo.Remote(git.DefaultRemoteName); err == nil && len(remote.Config().URLs) > 0 {
+		clone.Origin = remote.Config().URLs[0]
+	}
+	ga.logger.Info("opened local repository", "dir", dir, "branch", branch.Short())
+	return clone, nil
+}
@@ -0,0 +1,66 @@
+package worksummary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCloneAndCheckout_Local(t *testing.T) {
+	t.Parallel()
+	dir := branchedRepo(t)
+	repo, err := git.PlainOpen(dir)
+	require.NoError(t, err)
+	_, err = repo.CreateRemote(&config.RemoteConfig{
+		Name: git.DefaultRemoteName,
+		URLs: []string{"git@github.com:dictybase/dcr-mcp.git"},
+	})
+	require.NoError(t, err)
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+
+	for _, repoURL := range []string{dir, "file://" + dir} {
+		clone, err := analyzer.CloneAndCheckout(context.Background(), repoURL, "feature/stock")
+		require.NoError(t, err, repoURL)
+		assert.Equal(t, StorageLocal, clone.Storage, repoURL)
+		assert.Equal(t, "git@github.com:dictybase/dcr-mcp.git", clone.Origin, repoURL)
+		head, err := clone.Head()
+		require.NoError(t, err)
+		assert.Equal(t, "refs/heads/feature/stock", head.Name().String(), repoURL)
+		commits, err := clone.Log(&git.LogOptions{})
+		require.NoError(t, err)
+		count := 0
+		require.NoError(t, commits.ForEach(func(*object.Commit) error {
+			count++
+			return nil
+		}))
+		assert.Equal(t, 5, count, "the history of the branch, not of the checkout")
+		require.NoError(t, clone.Close())
+	}
+
+	checkedOut, err := repo.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", checkedOut.Name().String(), "the working tree is left alone")
+}
+
+func TestCloneAndCheckout_LocalErrors(t *testing.T) {
+	t.Parallel()
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	for repoURL, code := range map[string]string{
+		branchedRepo(t): "BRANCH_NOT_FOUND",
+		t.TempDir():     "INVALID_REPO_PATH",
+	} {
+		_, err := analyzer.CloneAndCheckout(context.Background(), repoURL, "develop")
+		var toolErr *toolerror.Error
+		require.ErrorAs(t, err, &toolErr, repoURL)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type)
+		assert.Equal(t, code, toolErr.Code)
+	}
+}
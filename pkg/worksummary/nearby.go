@@ -0,0 +1,115 @@
+package worksummary
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// maxTopAuthors is the number of authors suggested for an empty range.
+const maxTopAuthors = 5
+
+// Scopes of the top authors of NearbyActivity.
+const (
+	ScopeRange   = "range"
+	ScopeHistory = "history"
+)
+
+// AuthorActivity counts the commits of an author.
+type AuthorActivity struct {
+	Name    string `json:"name"`
+	Commits int    `json:"commits"`
+}
+
+// NearbyActivity describes the commits around a date range in which the
+// author has none, so the date or author filter can be adjusted.
+type NearbyActivity struct {
+	// LatestBefore is the time of the author's last commit before the
+	// range, nil when there is none.
+	LatestBefore *time.Time `json:"latest_before,omitempty"`
+	// EarliestAfter is the time of the author's first commit after the
+	// range, nil when there is none.
+	EarliestAfter *time.Time `json:"earliest_after,omitempty"`
+	// RangeCommits counts the commits of all authors within the range.
+	RangeCommits int `json:"range_commits"`
+	// TopAuthors are the authors with the most commits, within the range
+	// or, when it has none, in the whole history as told by
+	// TopAuthorsScope.
+	TopAuthors      []AuthorActivity `json:"top_authors"`
+	TopAuthorsScope string           `json:"top_authors_scope"`
+}
+
+// NearbyActivity walks the whole history of the repository to find the
+// selected authors' commits closest to the range and the most active
+// authors. Bot commits and those of excluded authors are left out, as in
+// ListAuthorCommits.
+func (ga *GitAnalyzer) NearbyActivity(
+	ctx context.Context, params CommitRangeParams,
+) (NearbyActivity, error) {
+	if err := validate.Struct(params); err != nil {
+		return NearbyActivity{}, fmt.Errorf("invalid commit range parameters: %w", err)
+	}
+	commitIter, err := params.Repo.Log(&git.LogOptions{Order: git.LogOrderCommitterTime})
+	if err != nil {
+		return NearbyActivity{}, fmt.Errorf("failed to get commit history: %w", err)
+	}
+
+	var nearby NearbyActivity
+	inRange := make(map[string]int)
+	inHistory := make(map[string]int)
+	err = commitIter.ForEach(func(cmt *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if isBotAuthor(cmt.Author.Name) || params.Authors.excludes(cmt.Author.Name, cmt.Author.Email) {
+			return nil
+		}
+		// The range is matched on the committer time, like the log
+		// filter of ListAuthorCommits.
+		when := cmt.Committer.When
+		inHistory[cmt.Author.Name]++
+		switch {
+		case when.Before(params.Start):
+			if params.Authors.Matches(cmt.Author.Name, cmt.Author.Email) &&
+				(nearby.LatestBefore == nil || when.After(*nearby.LatestBefore)) {
+				nearby.LatestBefore = &when
+			}
+		case when.After(params.End):
+			if params.Authors.Matches(cmt.Author.Name, cmt.Author.Email) &&
+				(nearby.EarliestAfter == nil || when.Before(*nearby.EarliestAfter)) {
+				nearby.EarliestAfter = &when
+			}
+		default:
+			inRange[cmt.Author.Name]++
+			nearby.RangeCommits++
+		}
+		return nil
+	})
+	if err != nil {
+		return NearbyActivity{}, fmt.Errorf("error iterating commits: %w", err)
+	}
+	nearby.TopAuthors, nearby.TopAuthorsScope = topAuthors(inRange), ScopeRange
+	if nearby.RangeCommits == 0 {
+		nearby.TopAuthors, nearby.TopAuthorsScope = topAuthors(inHistory), ScopeHistory
+	}
+	return nearby, nil
+}
+
+// topAuthors returns the authors with the most commits, most active first
+// and by name on ties.
+func topAuthors(commits map[string]int) []AuthorActivity {
+	authors := make([]AuthorActivity, 0, len(commits))
+	for _, name := range slices.Sorted(maps.Keys(commits)) {
+		authors = append(authors, AuthorActivity{Name: name, Commits: commits[name]})
+	}
+	slices.SortStableFunc(authors, func(a, b AuthorActivity) int {
+		return cmp.Compare(b.Commits, a.Commits)
+	})
+	return authors[:min(len(authors), maxTopAuthors)]
+}
@@ -0,0 +1,67 @@
+package worksummary
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// commitAt adds a commit by author at when to the repository in dir.
+func commitAt(t *testing.T, repo *git.Repository, dir, author string, when time.Time) {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(when.String()), 0o600))
+	_, err = worktree.Add("file.txt")
+	require.NoError(t, err)
+	signature := &object.Signature{Name: author, Email: "dev@example.org", When: when}
+	_, err = worktree.Commit("change by "+author, &git.CommitOptions{Author: signature, Committer: signature})
+	require.NoError(t, err)
+}
+
+func TestNearbyActivity(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	day := func(month time.Month, d int) time.Time { return time.Date(2025, month, d, 12, 0, 0, 0, time.UTC) }
+	commitAt(t, repo, dir, "Jane Doe", day(time.April, 2))
+	commitAt(t, repo, dir, "Jane Doe", day(time.May, 20))
+	commitAt(t, repo, dir, "Joe", day(time.June, 3))
+	commitAt(t, repo, dir, "Ann", day(time.June, 4))
+	commitAt(t, repo, dir, "Joe", day(time.June, 5))
+	commitAt(t, repo, dir, "dependabot[bot]", day(time.June, 6))
+	commitAt(t, repo, dir, "Jane Doe", day(time.July, 8))
+
+	params := CommitRangeParams{
+		Repo:    repo,
+		Start:   day(time.June, 1),
+		End:     day(time.June, 30),
+		Authors: AuthorFilter{Include: []string{"jane"}},
+	}
+	nearby, err := NewGitAnalyzer().NearbyActivity(context.Background(), params)
+	require.NoError(t, err)
+	require.NotNil(t, nearby.LatestBefore)
+	require.NotNil(t, nearby.EarliestAfter)
+	assert.Equal(t, day(time.May, 20), nearby.LatestBefore.UTC())
+	assert.Equal(t, day(time.July, 8), nearby.EarliestAfter.UTC())
+	assert.Equal(t, 3, nearby.RangeCommits, "bot commits are left out")
+	assert.Equal(t, ScopeRange, nearby.TopAuthorsScope)
+	assert.Equal(t, []AuthorActivity{{Name: "Joe", Commits: 2}, {Name: "Ann", Commits: 1}}, nearby.TopAuthors)
+
+	params.Start, params.End = day(time.August, 1), day(time.August, 31)
+	params.Authors = AuthorFilter{Include: []string{"nobody"}}
+	nearby, err = NewGitAnalyzer().NearbyActivity(context.Background(), params)
+	require.NoError(t, err)
+	assert.Nil(t, nearby.LatestBefore)
+	assert.Nil(t, nearby.EarliestAfter)
+	assert.Equal(t, ScopeHistory, nearby.TopAuthorsScope, "an empty range suggests authors from the whole history")
+	assert.Equal(t, "Jane Doe", nearby.TopAuthors[0].Name)
+}
@@ -5,8 +5,13 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
+	"time"
 
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
 	"github.com/sashabaranov/go-openai"
 )
 
@@ -21,6 +26,22 @@ const (
     3. Avoid technical jargon when possible, or explain technical terms when they must be used
     4. Focus on the business value and user impact rather than implementation details
 
+    A commit message may be followed by a "Stats:" line with the files changed,
+	lines inserted and deleted, and directories touched, and the messages may
+	end with totals and the most changed directories. When they are given, use
+	them to convey how large the work was and which areas changed most.
+
+    A commit may also be followed by a "Patch" with the unified diff of its
+	changes to selected files, which may be truncated or omitted to save
+	space. When patches are given, use them to describe what the changes
+	actually do rather than relying on the commit messages alone, still in
+	plain language and without quoting code.
+
+    The messages may be grouped under "Category:" lines, such as Features or
+	Fixes, taken from their conventional-commit types, and preceded by a list
+	of breaking changes. When they are, use the categories to decide what
+	matters most, and always mention the breaking changes.
+
     Present the output in markdown format, with "Work Summary" as the main
 	heading (H1). The summary should be easily understood by someone without
 	technical background, focusing on what was accomplished rather than how
@@ -38,9 +59,10 @@ type SummaryClient interface {
 
 // OpenAIClient implements SummaryClient using OpenAI API.
 type OpenAIClient struct {
-	client *openai.Client
-	model  string
-	config openai.ClientConfig
+	client       *openai.Client
+	model        string
+	config       openai.ClientConfig
+	reproducible bool
 }
 
 // OpenAIClientOption defines a functional option for configuring OpenAIClient.
@@ -79,6 +101,7 @@ func NewOpenAIClient(
 		config: openai.DefaultConfig(apiKey),
 	}
 	llm.config.BaseURL = "https://openrouter.ai/api/v1"
+	llm.config.HTTPClient = &http.Client{Transport: &ratelimit.Transport{}}
 	// Apply all options
 	for _, opt := range opts {
 		opt(llm)
@@ -88,7 +111,25 @@ func NewOpenAIClient(
 	return llm, nil
 }
 
-// SummarizeCommitMessages generates a summary of commit messages using OpenAI.
+// Model returns the model the client generates summaries with.
+func (c *OpenAIClient) Model() string {
+	return c.model
+}
+
+// expectedSummaryLength is the typical length of a summary in characters,
+// used to estimate progress while it streams in.
+const expectedSummaryLength = 2000
+
+// streamProgress estimates the fraction of a summary generated after
+// length characters. The length of the summary is not known in advance, so
+// the estimate approaches but never reaches 1.
+func streamProgress(length int) float64 {
+	return float64(length) / float64(length+expectedSummaryLength)
+}
+
+// SummarizeCommitMessages generates a summary of commit messages using
+// OpenAI. The partial summary is sent to the progress reporter of ctx as it
+// streams in.
 func (c *OpenAIClient) SummarizeCommitMessages(
 	ctx context.Context,
 	commitMsgs string,
@@ -96,22 +137,44 @@ func (c *OpenAIClient) SummarizeCommitMessages(
 	if err := validate.Var(commitMsgs, "required"); err != nil {
 		return "", fmt.Errorf("commit messages cannot be empty: %w", err)
 	}
-	req := openai.ChatCompletionRequest{
-		Model:       c.model,
-		Stream:      true,
-		Temperature: 0.1, // Controls randomness in the response
-		Messages: []openai.ChatCompletionMessage{
-			{
-				Role:    openai.ChatMessageRoleSystem,
-				Content: GitSummaryPrompt,
-			},
-			{
-				Role:    openai.ChatMessageRoleUser,
-				Content: commitMsgs,
-			},
-		},
+	return c.stream(ctx, c.chatRequest(commitMsgs))
+}
+
+// ReviseSummary asks the model once more to summarize commitMsgs, showing
+// it the summary it gave and the format problems CheckFormat found in it.
+func (c *OpenAIClient) ReviseSummary(
+	ctx context.Context,
+	commitMsgs, summary string,
+	problems []string,
+) (string, error) {
+	if err := validate.Var(commitMsgs, "required"); err != nil {
+		return "", fmt.Errorf("commit messages cannot be empty: %w", err)
 	}
+	req := c.chatRequest(commitMsgs)
+	req.Messages = append(req.Messages,
+		openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleAssistant,
+			Content: summary,
+		},
+		openai.ChatCompletionMessage{
+			Role:    openai.ChatMessageRoleUser,
+			Content: revisionPrompt(problems),
+		},
+	)
+	return c.stream(ctx, req)
+}
 
+// stream sends a streaming completion request and returns the generated
+// text, sending it to the progress reporter of ctx as it grows.
+func (c *OpenAIClient) stream(
+	ctx context.Context,
+	req openai.ChatCompletionRequest,
+) (text string, err error) {
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOutbound(metrics.ServiceOpenAI, start, err)
+	}()
+	reporter := progress.FromContext(ctx)
 	var stringBuilder strings.Builder
 	stream, err := c.client.CreateChatCompletionStream(ctx, req)
 	if err != nil {
@@ -135,6 +198,13 @@ func (c *OpenAIClient) SummarizeCommitMessages(
 				)
 			}
 			stringBuilder.WriteString(resp.Choices[0].Delta.Content)
+			// The message carries the summary so far, so clients can show
+			// it growing instead of waiting for the result.
+			reporter.Throttled(
+				streamProgress(stringBuilder.Len()),
+				1,
+				stringBuilder.String(),
+			)
 		}
 	}
 }
@@ -0,0 +1,86 @@
+package worksummary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// streamChunks serves a streaming chat completion made of chunks.
+func streamChunks(t *testing.T, chunks ...string) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for _, chunk := range chunks {
+			data, err := json.Marshal(map[string]any{
+				"choices": []map[string]any{{"index": 0, "delta": map[string]any{"content": chunk}}},
+			})
+			assert.NoError(t, err)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+		}
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSummarizeCommitMessages_StreamsPartialSummary(t *testing.T) {
+	t.Parallel()
+	server := streamChunks(t, "# Work Summary\n", "- **Docs** Fixed typos.")
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	var messages []string
+	reporter := progress.NewFuncReporter(func(params map[string]any) {
+		messages = append(messages, params["message"].(string))
+	})
+	summary, err := client.SummarizeCommitMessages(
+		progress.NewContext(context.Background(), reporter),
+		"docs: fix typos",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "# Work Summary\n- **Docs** Fixed typos.", summary)
+	require.NotEmpty(t, messages)
+	assert.Equal(t, "# Work Summary\n", messages[0], "the first chunk is sent right away")
+	assert.Len(t, messages, 1, "later chunks within the interval are throttled")
+}
+
+func TestReviseSummary_SendsProblems(t *testing.T) {
+	t.Parallel()
+	var request struct {
+		Messages []struct {
+			Role    string `json:"role"`
+			Content string `json:"content"`
+		} `json:"messages"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&request))
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, `data: {"choices":[{"index":0,"delta":{"content":"# Work Summary\n- **Docs** Fixed typos."}}]}`+"\n\n")
+		fmt.Fprint(w, "data: [DONE]\n\n")
+	}))
+	t.Cleanup(server.Close)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	revised, err := client.ReviseSummary(
+		context.Background(),
+		"docs: fix typos",
+		"Fixed typos.",
+		[]string{"it has no bullet points"},
+	)
+	require.NoError(t, err)
+	assert.Equal(t, "# Work Summary\n- **Docs** Fixed typos.", revised)
+	require.Len(t, request.Messages, 4)
+	assert.Equal(t, "assistant", request.Messages[2].Role)
+	assert.Equal(t, "Fixed typos.", request.Messages[2].Content)
+	assert.Equal(t, "user", request.Messages[3].Role)
+	assert.Contains(t, request.Messages[3].Content, "- it has no bullet points")
+}
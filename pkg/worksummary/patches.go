@@ -0,0 +1,157 @@
+package worksummary
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const (
+	// charsPerToken estimates the characters of a patch per model token.
+	charsPerToken = 4
+	// patchShare is the number of commits that can split the token budget:
+	// no commit's patch takes more than this fraction of it, so one large
+	// change does not crowd out the rest.
+	patchShare = 4
+)
+
+// PatchOptions selects the changes whose patches are sent to the model
+// along with the commit messages.
+type PatchOptions struct {
+	// Paths are the files or directories, or path.Match patterns, whose
+	// changes are included.
+	Paths []string `validate:"min=1,dive,required"`
+	// TokenBudget bounds the estimated tokens of the patches of all
+	// commits together.
+	TokenBudget int `validate:"min=1"`
+}
+
+// Patch is the part of a commit's diff that touches the selected paths.
+type Patch struct {
+	// Text is the unified diff, cut at a line to fit the token budget. It
+	// is empty when the budget was spent before the commit was reached.
+	Text string
+	// Files is the number of selected files the commit changed.
+	Files int
+	// Truncated reports that Text holds less than the whole diff.
+	Truncated bool
+}
+
+// Selects reports whether the change of the file name is included: it is
+// one of the paths, lies under one of them, or matches one as a pattern.
+func (o PatchOptions) Selects(name string) bool {
+	for _, selected := range o.Paths {
+		selected = strings.Trim(path.Clean(selected), "/")
+		if selected == "." || name == selected || strings.HasPrefix(name, selected+"/") {
+			return true
+		}
+		if matched, err := path.Match(selected, name); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// commitPatch returns the diff of cmt against its first parent, or against
+// the empty tree for a root commit, limited to the selected files, or nil
+// when the commit changed none of them. The diff text is computed only
+// when withText is set.
+func commitPatch(ctx context.Context, cmt *object.Commit, options PatchOptions, withText bool) (*Patch, error) {
+	to, err := cmt.Tree()
+	if err != nil {
+		return nil, fmt.Errorf("error reading tree of commit %s: %w", cmt.Hash, err)
+	}
+	from := &object.Tree{}
+	if cmt.NumParents() > 0 {
+		parent, err := cmt.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("error reading parent of commit %s: %w", cmt.Hash, err)
+		}
+		if from, err = parent.Tree(); err != nil {
+			return nil, fmt.Errorf("error reading tree of commit %s: %w", parent.Hash, err)
+		}
+	}
+	changes, err := object.DiffTreeWithOptions(ctx, from, to, object.DefaultDiffTreeOptions)
+	if err != nil {
+		return nil, fmt.Errorf("error diffing commit %s: %w", cmt.Hash, err)
+	}
+	var selected object.Changes
+	for _, change := range changes {
+		if options.Selects(change.From.Name) || options.Selects(change.To.Name) {
+			selected = append(selected, change)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, nil
+	}
+	patch := &Patch{Files: len(selected), Truncated: !withText}
+	if !withText {
+		return patch, nil
+	}
+	diff, err := selected.PatchContext(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("error computing patch of commit %s: %w", cmt.Hash, err)
+	}
+	patch.Text = diff.String()
+	return patch, nil
+}
+
+// patchBudget tracks the tokens left for the patches of a list of commits.
+type patchBudget struct {
+	total     int
+	remaining int
+}
+
+// newPatchBudget returns a budget of tokens tokens.
+func newPatchBudget(tokens int) *patchBudget {
+	return &patchBudget{total: tokens, remaining: tokens}
+}
+
+// spent reports whether no tokens are left.
+func (b *patchBudget) spent() bool {
+	return b.remaining <= 0
+}
+
+// fit cuts the text of patch to the tokens a single commit may take and
+// spends them. The text is cut at a line, or within the first line when
+// not even that fits.
+func (b *patchBudget) fit(patch *Patch) {
+	if patch == nil {
+		return
+	}
+	limit := max(min(b.remaining, max(b.total/patchShare, 1)), 0) * charsPerToken
+	if len(patch.Text) > limit {
+		text := patch.Text[:limit]
+		if cut := strings.LastIndex(text, "\n"); cut >= 0 {
+			text = text[:cut+1]
+		} else if text != "" {
+			text += "\n"
+		}
+		patch.Text, patch.Truncated = text, true
+	}
+	b.remaining -= (len(patch.Text) + charsPerToken - 1) / charsPerToken
+}
+
+// fitPatches cuts the patches of commits, in order, to share tokens.
+func fitPatches(commits []Commit, tokens int) {
+	budget := newPatchBudget(tokens)
+	for i := range commits {
+		budget.fit(commits[i].Patch)
+	}
+}
+
+// String renders the patch for the model: a line naming the number of
+// files, followed by the diff or by why it was left out.
+func (p Patch) String() string {
+	switch {
+	case p.Text == "":
+		return fmt.Sprintf("Patch (%s): omitted, the token budget was spent\n", plural(p.Files, "file"))
+	case p.Truncated:
+		return fmt.Sprintf("Patch (%s, truncated):\n%s", plural(p.Files, "file"), p.Text)
+	default:
+		return fmt.Sprintf("Patch (%s):\n%s", plural(p.Files, "file"), p.Text)
+	}
+}
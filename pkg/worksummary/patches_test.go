@@ -0,0 +1,96 @@
+package worksummary
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchOptions_Selects(t *testing.T) {
+	t.Parallel()
+	options := PatchOptions{Paths: []string{"pkg/stock/", "cmd/server/main.go", "*.proto", "api/*.yaml"}}
+	for name, want := range map[string]bool{
+		"pkg/stock/search.go":    true,
+		"pkg/stock/sub/order.go": true,
+		"pkg/stocks/search.go":   false,
+		"cmd/server/main.go":     true,
+		"cmd/server/tools.go":    false,
+		"stock.proto":            true,
+		"proto/stock.proto":      false,
+		"api/stock.yaml":         true,
+	} {
+		assert.Equal(t, want, options.Selects(name), name)
+	}
+	assert.True(t, PatchOptions{Paths: []string{"."}}.Selects("README.md"))
+}
+
+func TestListAuthorCommits_Patches(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC) }
+	commitFiles(t, repo, dir, "Add stock search\n", day(2), map[string]string{
+		"README.md":           "stock\n",
+		"pkg/stock/search.go": "package stock\n",
+	})
+	commitFiles(t, repo, dir, "Update readme\n", day(3), map[string]string{
+		"README.md": "stock center\n",
+	})
+	commitFiles(t, repo, dir, "Search by name\n", day(4), map[string]string{
+		"pkg/stock/search.go": "package stock\n\nfunc SearchByName() {}\n",
+	})
+
+	params := CommitRangeParams{
+		Repo:    repo,
+		Start:   day(1),
+		End:     day(30),
+		Authors: AuthorFilter{Include: []string{"jane"}},
+		Patches: &PatchOptions{Paths: []string{"pkg/stock"}, TokenBudget: 1000},
+	}
+	commits, err := NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, commits, 3)
+	require.NotNil(t, commits[0].Patch)
+	assert.Equal(t, 1, commits[0].Patch.Files)
+	assert.False(t, commits[0].Patch.Truncated)
+	assert.Contains(t, commits[0].Patch.Text, "+func SearchByName() {}")
+	assert.Nil(t, commits[1].Patch, "a commit changing no selected file has no patch")
+	require.NotNil(t, commits[2].Patch, "a root commit is compared to the empty tree")
+	assert.NotContains(t, commits[2].Patch.Text, "README.md")
+
+	messages := Messages(commits)
+	assert.True(t, strings.HasPrefix(messages, "Search by name\nPatch (1 file):\ndiff --git a/pkg/stock/search.go"), messages)
+	assert.Contains(t, messages, "\nUpdate readme\nAdd stock search\nPatch (1 file):\n")
+
+	params.Patches.TokenBudget = 40
+	commits, err = NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	assert.True(t, commits[0].Patch.Truncated)
+	assert.LessOrEqual(t, len(commits[0].Patch.Text), 40/patchShare*charsPerToken+1)
+	assert.True(t, strings.HasSuffix(commits[0].Patch.Text, "\n"), "a truncated patch ends with a newline")
+	assert.Contains(t, Messages(commits), "Patch (1 file, truncated):\n")
+}
+
+func TestFitPatches(t *testing.T) {
+	t.Parallel()
+	line := "+stock\n\n"
+	commits := []Commit{{Message: "First\n", Patch: &Patch{Text: line, Files: 1}}, {Message: "Second\n"}}
+	for _, message := range []string{"Third\n", "Fourth\n", "Fifth\n", "Sixth\n"} {
+		commits = append(commits, Commit{Message: message, Patch: &Patch{Text: line + line, Files: 2}})
+	}
+	fitPatches(commits, 8)
+	assert.Equal(t, &Patch{Text: line, Files: 1}, commits[0].Patch)
+	assert.Nil(t, commits[1].Patch)
+	for _, commit := range commits[2:5] {
+		assert.Equal(t, &Patch{Text: line, Files: 2, Truncated: true}, commit.Patch,
+			"a commit takes at most a quarter of the budget")
+	}
+	assert.Equal(t, &Patch{Files: 2, Truncated: true}, commits[5].Patch)
+	assert.Equal(t, "Patch (2 files): omitted, the token budget was spent\n", commits[5].Patch.String())
+}
@@ -0,0 +1,45 @@
+package worksummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+// PublicationSummaryPrompt is the system prompt of publication summaries.
+const PublicationSummaryPrompt = `
+	You summarize the research of a scientist from their publication list,
+	given as one publication per line with its year, title and journal.
+	Write one paragraph of three to five sentences, suitable for the
+	personal statement of a biosketch or a lab webpage, naming the main
+	research themes and how they developed over time. Only use what the
+	list shows; do not invent findings, awards or affiliations. Answer with
+	the paragraph only, without a heading or preamble.
+	`
+
+// SummarizePublications writes a short summary of the research shown by a
+// publication list.
+func (c *OpenAIClient) SummarizePublications(ctx context.Context, publications string) (summary string, err error) {
+	if err := validate.Var(publications, "required"); err != nil {
+		return "", fmt.Errorf("publications cannot be empty: %w", err)
+	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOutbound(metrics.ServiceOpenAI, start, err)
+	}()
+	resp, err := c.client.CreateChatCompletion(
+		ctx,
+		chatCompletionRequest(c.model, 0, PublicationSummaryPrompt, publications),
+	)
+	if err != nil {
+		return "", fmt.Errorf("OpenAI completion error: %w", err)
+	}
+	if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
+		return "", errors.New("the model returned no summary")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,33 @@
+package worksummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSummarizePublications(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{
+		"role":    "assistant",
+		"content": "\nCarberry studies chemotaxis.\n",
+	}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	summary, err := client.SummarizePublications(context.Background(), "- 2021. Chemotaxis in Dictyostelium. Dev Biol.")
+	require.NoError(t, err)
+	assert.Equal(t, "Carberry studies chemotaxis.", summary)
+	require.Len(t, requests, 1)
+	messages, ok := requests[0]["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0].(map[string]any)["content"], "publication list")
+	assert.Equal(t, "- 2021. Chemotaxis in Dictyostelium. Dev Biol.", messages[1].(map[string]any)["content"])
+
+	_, err = client.SummarizePublications(context.Background(), "")
+	require.Error(t, err, "there is nothing to summarize")
+}
@@ -0,0 +1,116 @@
+package worksummary
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// RefRange is the span of an analysis between two refs, such as the tags
+// of two releases, together with the commits they resolved to, so reports
+// can show the range actually used.
+type RefRange struct {
+	FromRef string
+	// ToRef is the end of the range; the branch head when empty.
+	ToRef string
+	// From and To are the commits the refs resolved to, or nil for a range
+	// not yet resolved in a repository.
+	From *object.Commit
+	To   *object.Commit
+}
+
+// ResolveRefRange resolves the tags, branches or hashes bounding a range
+// in repo. An empty toRef is the head of the branch checked out.
+func ResolveRefRange(repo *git.Repository, fromRef, toRef string) (RefRange, error) {
+	refRange := RefRange{FromRef: fromRef, ToRef: toRef}
+	from, err := ResolveRef(repo, fromRef)
+	if err != nil {
+		return RefRange{}, err
+	}
+	to, err := ResolveRef(repo, orHead(toRef))
+	if err != nil {
+		return RefRange{}, err
+	}
+	refRange.From, refRange.To = from, to
+	return refRange, nil
+}
+
+// Params narrows params to the commits reachable from the end of the
+// range but not from its start, as git log from..to lists them.
+func (r RefRange) Params(params CommitRangeParams) CommitRangeParams {
+	params.From, params.Exclude = r.To.Hash, r.From.Hash
+	params.Start, params.End = time.Time{}, time.Time{}
+	return params
+}
+
+// Header renders the range as markdown for the top of a report. It names
+// the commit each ref resolved to once the range is resolved.
+func (r RefRange) Header() string {
+	var builder strings.Builder
+	fmt.Fprintf(&builder, "**Commit range:** %s..%s\n\n", r.FromRef, orHead(r.ToRef))
+	fmt.Fprintf(&builder, "- from_ref %q%s\n", r.FromRef, resolvedTo(r.From))
+	if r.ToRef == "" {
+		fmt.Fprintf(&builder, "- to_ref not given, using the branch head%s\n", resolvedTo(r.To))
+	} else {
+		fmt.Fprintf(&builder, "- to_ref %q%s\n", r.ToRef, resolvedTo(r.To))
+	}
+	return builder.String()
+}
+
+// orHead returns ref, or HEAD when it is empty.
+func orHead(ref string) string {
+	if ref == "" {
+		return "HEAD"
+	}
+	return ref
+}
+
+// resolvedTo describes the commit a ref resolved to, or nothing when the
+// range is not resolved.
+func resolvedTo(commit *object.Commit) string {
+	if commit == nil {
+		return ""
+	}
+	return fmt.Sprintf(" resolved to `%s` of %s", commit.Hash.String()[:7], commit.Committer.When.Format(time.DateOnly))
+}
+
+// ResolveRef returns the commit a tag, branch or hash names in repo. A
+// ref that names nothing fails with a not found tool error.
+func ResolveRef(repo *git.Repository, ref string) (*object.Commit, error) {
+	hash, err := repo.ResolveRevision(plumbing.Revision(ref))
+	if err != nil {
+		return nil, toolerror.Wrap(
+			toolerror.TypeNotFound,
+			"REF_NOT_FOUND",
+			err,
+			fmt.Sprintf("failed to resolve %q on the cloned branch", ref),
+		)
+	}
+	commit, err := repo.CommitObject(*hash)
+	if err != nil {
+		return nil, fmt.Errorf("error reading commit %s: %w", hash, err)
+	}
+	return commit, nil
+}
+
+// reachable returns the commits reachable from hash, including it.
+func reachable(repo *git.Repository, hash plumbing.Hash) (map[plumbing.Hash]bool, error) {
+	iter, err := repo.Log(&git.LogOptions{From: hash})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit history of %s: %w", hash, err)
+	}
+	seen := make(map[plumbing.Hash]bool)
+	err = iter.ForEach(func(cmt *object.Commit) error {
+		seen[cmt.Hash] = true
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating commits: %w", err)
+	}
+	return seen, nil
+}
@@ -0,0 +1,58 @@
+package worksummary
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	git "github.com/go-git/go-git/v5"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResolveRefRange(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	day := func(d int) time.Time { return time.Date(2025, time.June, d, 12, 0, 0, 0, time.UTC) }
+	commitAt(t, repo, dir, "Jane Doe", day(2))
+	head, err := repo.Head()
+	require.NoError(t, err)
+	_, err = repo.CreateTag("v1.0.0", head.Hash(), nil)
+	require.NoError(t, err)
+	commitAt(t, repo, dir, "Jane Doe", day(3))
+	commitAt(t, repo, dir, "Joe", day(4))
+	// A commit dated before the tag is still after it in the history.
+	commitAt(t, repo, dir, "Jane Doe", day(1))
+
+	refRange, err := ResolveRefRange(repo, "v1.0.0", "")
+	require.NoError(t, err)
+	assert.Equal(t, head.Hash(), refRange.From.Hash)
+	header := refRange.Header()
+	assert.Contains(t, header, "**Commit range:** v1.0.0..HEAD")
+	assert.Contains(t, header, `from_ref "v1.0.0" resolved to `+"`"+head.Hash().String()[:7]+"` of 2025-06-02")
+	assert.Contains(t, header, "to_ref not given, using the branch head resolved to")
+
+	params := refRange.Params(CommitRangeParams{Repo: repo, Authors: AuthorFilter{Include: []string{"jane"}}})
+	commits, err := NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, commits, 2, "commits reachable from the tag are left out")
+	assert.Equal(t, 1, commits[0].When.Day())
+	assert.Equal(t, 3, commits[1].When.Day())
+
+	refRange, err = ResolveRefRange(repo, "v1.0.0", "HEAD~2")
+	require.NoError(t, err)
+	params = refRange.Params(CommitRangeParams{Repo: repo, Authors: AuthorFilter{Exclude: []string{"joe"}}})
+	commits, err = NewGitAnalyzer().ListAuthorCommits(context.Background(), params)
+	require.NoError(t, err)
+	require.Len(t, commits, 1)
+	assert.Equal(t, 3, commits[0].When.Day())
+	assert.Contains(t, refRange.Header(), `to_ref "HEAD~2" resolved to`)
+
+	_, err = ResolveRefRange(repo, "v9.9.9", "")
+	require.ErrorContains(t, err, `failed to resolve "v9.9.9"`)
+
+	assert.Equal(t, "**Commit range:** v1.0.0..v2.0.0\n\n- from_ref \"v1.0.0\"\n- to_ref \"v2.0.0\"\n",
+		RefRange{FromRef: "v1.0.0", ToRef: "v2.0.0"}.Header())
+}
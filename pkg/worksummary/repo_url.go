@@ -0,0 +1,205 @@
+package worksummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+)
+
+// hostCheckTimeout bounds the connection made to tell whether the host of
+// a repository can be reached.
+const hostCheckTimeout = 5 * time.Second
+
+// shorthandPattern matches the GitHub shorthand owner/repo.
+var shorthandPattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*/[A-Za-z0-9._-]+$`)
+
+// defaultPorts are the ports a host check connects to, by URL scheme.
+var defaultPorts = map[string]string{
+	"https": "443",
+	"http":  "80",
+	"ssh":   "22",
+	"git":   "9418",
+}
+
+// DialFunc opens a network connection, like net.Dialer.DialContext.
+type DialFunc func(ctx context.Context, network, address string) (net.Conn, error)
+
+// WithHostDialer replaces the dialer that checks the host of a repository
+// can be reached before it is cloned; nil skips the check.
+func WithHostDialer(dial DialFunc) GitAnalyzerOption {
+	return func(ga *GitAnalyzer) {
+		ga.dial = dial
+	}
+}
+
+// NormalizeRepoURL turns the common ways of naming a repository into a URL
+// git can clone:
+//   - the GitHub shorthand owner/repo becomes https://github.com/owner/repo.git
+//   - a URL without scheme, such as github.com/owner/repo, gets https://
+//   - HTTPS URLs of GitHub, GitLab, Codeberg and Bitbucket get the .git suffix
+//   - scp-like SSH remotes such as git@github.com:owner/repo, URLs with a
+//     scheme and local paths are kept, without trailing slashes
+//
+// Anything else is rejected with an invalid input error naming the accepted
+// forms.
+func NormalizeRepoURL(repoURL string) (string, error) {
+	repoURL = strings.TrimSpace(repoURL)
+	switch {
+	case repoURL == "":
+		return "", repoURLError(repoURL, "the repository URL is empty")
+	case repoURL == ".", repoURL == "..",
+		strings.HasPrefix(repoURL, "/"), strings.HasPrefix(repoURL, "./"), strings.HasPrefix(repoURL, "../"):
+		return strings.TrimRight(repoURL, "/"), nil
+	case strings.Contains(repoURL, "://"):
+		return normalizeURL(repoURL)
+	case shorthandPattern.MatchString(repoURL):
+		return "https://github.com/" + strings.TrimSuffix(repoURL, ".git") + ".git", nil
+	}
+	if userHost, repoPath, found := strings.Cut(repoURL, ":"); found && !strings.Contains(userHost, "/") {
+		if userHost == "" || strings.Trim(repoPath, "/") == "" {
+			return "", repoURLError(repoURL, "the SSH remote needs a host and a repository path")
+		}
+		return strings.TrimRight(repoURL, "/"), nil
+	}
+	if host, _, found := strings.Cut(repoURL, "/"); found && strings.Contains(host, ".") {
+		return normalizeURL("https://" + repoURL)
+	}
+	return "", repoURLError(repoURL, "the repository URL is not recognized")
+}
+
+// RepoName returns the name of the repository at repoURL, the last
+// element of its path without the .git suffix.
+func RepoName(repoURL string) string {
+	return strings.TrimSuffix(path.Base(strings.TrimSuffix(repoURL, "/")), ".git")
+}
+
+// normalizeURL checks a URL with a scheme and adds the .git suffix to the
+// HTTPS URLs of known hosts.
+func normalizeURL(repoURL string) (string, error) {
+	parsed, err := url.Parse(repoURL)
+	if err != nil {
+		return "", repoURLError(repoURL, err.Error())
+	}
+	switch parsed.Scheme {
+	case "file":
+		return repoURL, nil
+	case "https", "http", "ssh", "git":
+	default:
+		return "", repoURLError(repoURL, fmt.Sprintf("the scheme %q is not supported", parsed.Scheme))
+	}
+	if parsed.Hostname() == "" {
+		return "", repoURLError(repoURL, "the URL has no host")
+	}
+	parsed.Path = strings.TrimRight(parsed.Path, "/")
+	if parsed.Path == "" {
+		return "", repoURLError(repoURL, "the URL has no repository path")
+	}
+	if _, known := commitPaths[strings.ToLower(parsed.Hostname())]; known && strings.HasPrefix(parsed.Scheme, "http") {
+		if strings.Count(strings.Trim(parsed.Path, "/"), "/") < 1 {
+			return "", repoURLError(repoURL, "the URL needs an owner and a repository name")
+		}
+		if path.Ext(parsed.Path) != ".git" {
+			parsed.Path += ".git"
+		}
+	}
+	return parsed.String(), nil
+}
+
+// repoURLError reports a repository URL that cannot be cloned.
+func repoURLError(repoURL, reason string) *toolerror.Error {
+	return &toolerror.Error{
+		Type: toolerror.TypeInvalidInput,
+		Code: "INVALID_REPO_URL",
+		Message: fmt.Sprintf(
+			"invalid repository URL %q: %s; use https://host/owner/repo, git@host:owner/repo or owner/repo for GitHub",
+			repoURL, reason,
+		),
+	}
+}
+
+// checkHost connects to the host of a normalized repository URL, so an
+// unknown or unreachable host fails fast with a clear error instead of
+// after a clone attempt. Local repositories, hosts reached through an HTTP
+// proxy and analyzers without a dialer are not checked.
+func (ga *GitAnalyzer) checkHost(ctx context.Context, repoURL string) error {
+	if ga.dial == nil {
+		return nil
+	}
+	scheme, host := "ssh", ""
+	if strings.Contains(repoURL, "://") {
+		parsed, err := url.Parse(repoURL)
+		if err != nil {
+			return nil
+		}
+		scheme, host = parsed.Scheme, parsed.Host
+		if strings.HasPrefix(scheme, "http") && behindProxy(parsed) {
+			return nil
+		}
+	} else if userHost, _, found := strings.Cut(repoURL, ":"); found && !strings.Contains(userHost, "/") {
+		_, host, _ = strings.Cut(userHost, "@")
+		if host == "" {
+			host = userHost
+		}
+	}
+	port, known := defaultPorts[scheme]
+	if host == "" || !known {
+		return nil
+	}
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(strings.Trim(host, "[]"), port)
+	}
+	dialCtx, cancel := context.WithTimeout(ctx, hostCheckTimeout)
+	defer cancel()
+	conn, err := ga.dial(dialCtx, "tcp", host)
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		reason := "cannot be reached"
+		var dnsErr *net.DNSError
+		if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+			reason = "does not exist"
+		}
+		return &toolerror.Error{
+			Type:    toolerror.TypeNetworkError,
+			Code:    "REPO_HOST_UNREACHABLE",
+			Message: fmt.Sprintf("repository host %s %s: %v", host, reason, err),
+			Details: map[string]string{"host": host},
+			Err:     err,
+		}
+	}
+	_ = conn.Close()
+	return nil
+}
+
+// behindProxy reports whether requests to the URL go through a proxy, which
+// may be the only way out of the network.
+func behindProxy(parsed *url.URL) bool {
+	proxy, err := http.ProxyFromEnvironment(&http.Request{URL: parsed})
+	return err != nil || proxy != nil
+}
+
+// prepareRepoURL normalizes a repository URL and checks its host can be
+// reached.
+func (ga *GitAnalyzer) prepareRepoURL(ctx context.Context, repoURL string) (string, error) {
+	normalized, err := NormalizeRepoURL(repoURL)
+	if err != nil {
+		return "", err
+	}
+	if normalized != repoURL {
+		ga.logger.Debug("normalized repository URL", "repo_url", repoURL, "normalized", normalized)
+	}
+	if err := ga.checkHost(ctx, normalized); err != nil {
+		return "", err
+	}
+	return normalized, nil
+}
@@ -0,0 +1,98 @@
+package worksummary
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"net"
+	"testing"
+
+	"github.com/dictybase/dcr-mcp/pkg/toolerror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeRepoURL(t *testing.T) {
+	t.Parallel()
+	for input, want := range map[string]string{
+		"dictybase/dcr-mcp":                        "https://github.com/dictybase/dcr-mcp.git",
+		"github.com/dictybase/dcr-mcp":             "https://github.com/dictybase/dcr-mcp.git",
+		"https://github.com/dictybase/dcr-mcp/":    "https://github.com/dictybase/dcr-mcp.git",
+		"https://github.com/dictybase/dcr-mcp.git": "https://github.com/dictybase/dcr-mcp.git",
+		"https://git.example.org/tools/dcr":        "https://git.example.org/tools/dcr",
+		"git@github.com:dictybase/dcr-mcp.git":     "git@github.com:dictybase/dcr-mcp.git",
+		"ssh://git@github.com/dictybase/dcr-mcp":   "ssh://git@github.com/dictybase/dcr-mcp",
+		"/srv/git/dcr-mcp/":                        "/srv/git/dcr-mcp",
+		"file:///srv/git/dcr-mcp":                  "file:///srv/git/dcr-mcp",
+	} {
+		got, err := NormalizeRepoURL(input)
+		require.NoError(t, err, input)
+		assert.Equal(t, want, got, input)
+	}
+	for _, input := range []string{
+		"",
+		"dcr-mcp",
+		"ftp://example.org/dcr-mcp",
+		"https://github.com/dictybase",
+		"https:///dictybase/dcr-mcp",
+		"git@github.com:",
+	} {
+		_, err := NormalizeRepoURL(input)
+		var toolErr *toolerror.Error
+		require.ErrorAs(t, err, &toolErr, input)
+		assert.Equal(t, toolerror.TypeInvalidInput, toolErr.Type, input)
+		assert.Equal(t, "INVALID_REPO_URL", toolErr.Code, input)
+	}
+}
+
+func TestRepoName(t *testing.T) {
+	t.Parallel()
+	for input, want := range map[string]string{
+		"https://github.com/dictybase/dcr-mcp.git": "dcr-mcp",
+		"https://github.com/dictybase/dcr-mcp/":    "dcr-mcp",
+		"git@github.com:dictybase/dcr-mcp.git":     "dcr-mcp",
+		"/srv/git/dcr-mcp":                         "dcr-mcp",
+	} {
+		assert.Equal(t, want, RepoName(input), input)
+	}
+}
+
+func TestCheckHost(t *testing.T) {
+	t.Parallel()
+	var dialed []string
+	analyzer := NewGitAnalyzer(
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithHostDialer(func(_ context.Context, _, address string) (net.Conn, error) {
+			dialed = append(dialed, address)
+			return nil, &net.DNSError{Err: "no such host", Name: address, IsNotFound: true}
+		}),
+	)
+
+	_, err := analyzer.CloneAndCheckout(context.Background(), "git@git.invalid:dictybase/dcr-mcp", "main")
+	var toolErr *toolerror.Error
+	require.ErrorAs(t, err, &toolErr)
+	assert.Equal(t, toolerror.TypeNetworkError, toolErr.Type)
+	assert.Equal(t, "REPO_HOST_UNREACHABLE", toolErr.Code)
+	assert.Contains(t, toolErr.Message, "does not exist")
+	assert.Equal(t, "git.invalid:22", toolErr.Details["host"])
+
+	require.NoError(t, analyzer.checkHost(context.Background(), "/srv/git/dcr-mcp"))
+	assert.Equal(t, []string{"git.invalid:22"}, dialed, "local paths are not dialed")
+}
+
+func TestCheckHost_NoDialer(t *testing.T) {
+	t.Parallel()
+	analyzer := NewGitAnalyzer(
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithHostDialer(nil),
+	)
+	require.NoError(t, analyzer.checkHost(context.Background(), "ssh://git@git.invalid/dictybase/dcr-mcp"))
+
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+	analyzer.dial = func(context.Context, string, string) (net.Conn, error) {
+		return nil, errors.New("dial failed")
+	}
+	require.ErrorIs(t, analyzer.checkHost(cancelled, "ssh://git@git.invalid/dictybase/dcr-mcp"), context.Canceled)
+}
@@ -0,0 +1,385 @@
+package worksummary
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"hash"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	pgperrors "github.com/ProtonMail/go-crypto/openpgp/errors"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"golang.org/x/crypto/ssh"
+)
+
+// Signature types of signed commits.
+const (
+	SignatureGPG  = "gpg"
+	SignatureSSH  = "ssh"
+	SignatureX509 = "x509"
+)
+
+// Verification results of a signature.
+const (
+	// VerificationGood means a trusted key made the signature.
+	VerificationGood = "good"
+	// VerificationBad means the signature was made by a trusted key but
+	// does not match the commit.
+	VerificationBad = "bad"
+	// VerificationUnknownKey means no trusted key made the signature.
+	VerificationUnknownKey = "unknown_key"
+	// VerificationUnchecked means no trusted keys were given, or the
+	// signature type cannot be verified.
+	VerificationUnchecked = "unchecked"
+)
+
+const (
+	pgpPublicKeyBegin = "-----BEGIN PGP PUBLIC KEY BLOCK-----"
+	pgpPublicKeyEnd   = "-----END PGP PUBLIC KEY BLOCK-----"
+	sshSignatureBegin = "-----BEGIN SSH SIGNATURE-----"
+	sshSignatureEnd   = "-----END SSH SIGNATURE-----"
+	// sshSigMagic starts SSH signatures and the data they sign.
+	sshSigMagic = "SSHSIG"
+	// sshSigNamespace is the namespace git signs commits in.
+	sshSigNamespace = "git"
+)
+
+// Signature describes the signature of a commit.
+type Signature struct {
+	// Type is gpg, ssh or x509.
+	Type string `json:"type"`
+	// KeyID identifies the signing key: the long key ID of a GPG key or
+	// the SHA256 fingerprint of an SSH key. It is empty when the signature
+	// cannot be read.
+	KeyID string `json:"key_id,omitempty"`
+}
+
+// TrustedKeys are the keys signatures are verified against.
+type TrustedKeys struct {
+	pgp openpgp.EntityList
+	// ssh maps SHA256 fingerprints to the principal or comment of the key.
+	ssh map[string]string
+}
+
+// SignatureReport counts the signed commits of a range by signer.
+type SignatureReport struct {
+	Commits int `json:"commits"`
+	Signed  int `json:"signed"`
+	// ByType counts the signed commits by signature type.
+	ByType map[string]int `json:"by_type"`
+	// Signers are grouped by signing key, most commits first.
+	Signers []Signer `json:"signers"`
+	// Verified tells whether signatures were checked against trusted keys.
+	Verified bool `json:"verified"`
+}
+
+// Signer is a key that signed commits of a range.
+type Signer struct {
+	Type  string `json:"type"`
+	KeyID string `json:"key_id,omitempty"`
+	// Identity is the user ID of a trusted GPG key or the principal of a
+	// trusted SSH key; it is empty for unknown keys.
+	Identity string `json:"identity,omitempty"`
+	// Authors are the commit authors who signed with the key.
+	Authors      []string `json:"authors"`
+	Commits      int      `json:"commits"`
+	Verification string   `json:"verification"`
+}
+
+// ParseTrustedKeys reads armored OpenPGP public key blocks and SSH public
+// keys, one per line in authorized_keys or allowed_signers format. Blank
+// lines and lines starting with # are skipped.
+func ParseTrustedKeys(data []byte) (*TrustedKeys, error) {
+	keys := &TrustedKeys{ssh: make(map[string]string)}
+	rest := string(data)
+	for {
+		before, block, found := strings.Cut(rest, pgpPublicKeyBegin)
+		if err := keys.parseSSH(before); err != nil {
+			return nil, err
+		}
+		if !found {
+			break
+		}
+		block, rest, found = strings.Cut(block, pgpPublicKeyEnd)
+		if !found {
+			return nil, errors.New("unterminated PGP public key block")
+		}
+		entities, err := openpgp.ReadArmoredKeyRing(strings.NewReader(pgpPublicKeyBegin + block + pgpPublicKeyEnd))
+		if err != nil {
+			return nil, fmt.Errorf("error reading PGP public key: %w", err)
+		}
+		keys.pgp = append(keys.pgp, entities...)
+	}
+	return keys, nil
+}
+
+// LoadTrustedKeys reads the trusted keys in a file, as ParseTrustedKeys.
+func LoadTrustedKeys(path string) (*TrustedKeys, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading trusted keys: %w", err)
+	}
+	keys, err := ParseTrustedKeys(data)
+	if err != nil {
+		return nil, fmt.Errorf("error parsing trusted keys in %s: %w", path, err)
+	}
+	return keys, nil
+}
+
+// Len returns the number of trusted keys.
+func (k *TrustedKeys) Len() int {
+	if k == nil {
+		return 0
+	}
+	return len(k.pgp) + len(k.ssh)
+}
+
+// parseSSH reads SSH public keys, one per line. An allowed_signers line
+// names the principal before the key, which is read like the options of an
+// authorized_keys line; an authorized_keys line without options is named
+// by its comment.
+func (k *TrustedKeys) parseSSH(text string) error {
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, comment, options, _, err := ssh.ParseAuthorizedKey([]byte(line))
+		if err != nil {
+			return fmt.Errorf("error reading SSH public key %q: %w", line, err)
+		}
+		if len(options) > 0 {
+			comment = options[0]
+		}
+		k.ssh[ssh.FingerprintSHA256(key)] = comment
+	}
+	return nil
+}
+
+// parseSignature describes the armored signature of a commit, or returns
+// nil for an unsigned commit.
+func parseSignature(armored string) *Signature {
+	switch {
+	case armored == "":
+		return nil
+	case strings.Contains(armored, sshSignatureBegin):
+		signature := &Signature{Type: SignatureSSH}
+		if sig, err := decodeSSHSig(armored); err == nil {
+			if key, err := ssh.ParsePublicKey(sig.PublicKey); err == nil {
+				signature.KeyID = ssh.FingerprintSHA256(key)
+			}
+		}
+		return signature
+	case strings.Contains(armored, "-----BEGIN SIGNED MESSAGE-----"):
+		return &Signature{Type: SignatureX509}
+	default:
+		return &Signature{Type: SignatureGPG, KeyID: pgpKeyID(armored)}
+	}
+}
+
+// pgpKeyID returns the long ID of the key that made an armored OpenPGP
+// signature.
+func pgpKeyID(armored string) string {
+	block, err := armor.Decode(strings.NewReader(armored))
+	if err != nil {
+		return ""
+	}
+	parsed, err := packet.Read(block.Body)
+	if err != nil {
+		return ""
+	}
+	sig, ok := parsed.(*packet.Signature)
+	switch {
+	case !ok:
+		return ""
+	case sig.IssuerKeyId != nil:
+		return fmt.Sprintf("%016X", *sig.IssuerKeyId)
+	case len(sig.IssuerFingerprint) >= 8:
+		return fmt.Sprintf("%X", sig.IssuerFingerprint[len(sig.IssuerFingerprint)-8:])
+	default:
+		return ""
+	}
+}
+
+// sshSig is an SSH signature as described in PROTOCOL.sshsig, after the
+// magic preamble.
+type sshSig struct {
+	Version   uint32
+	PublicKey []byte
+	Namespace string
+	Reserved  string
+	HashAlg   string
+	Signature []byte
+}
+
+// decodeSSHSig decodes an armored SSH signature.
+func decodeSSHSig(armored string) (sshSig, error) {
+	_, body, _ := strings.Cut(armored, sshSignatureBegin)
+	body, _, _ = strings.Cut(body, sshSignatureEnd)
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(body), ""))
+	if err != nil {
+		return sshSig{}, fmt.Errorf("error decoding SSH signature: %w", err)
+	}
+	data, found := bytes.CutPrefix(data, []byte(sshSigMagic))
+	if !found {
+		return sshSig{}, errors.New("not an SSH signature")
+	}
+	var sig sshSig
+	if err := ssh.Unmarshal(data, &sig); err != nil {
+		return sshSig{}, fmt.Errorf("error decoding SSH signature: %w", err)
+	}
+	return sig, nil
+}
+
+// verifySSHSig checks that an SSH signature of message was made in the git
+// namespace by the key it names, and returns that key.
+func verifySSHSig(armored string, message []byte) (ssh.PublicKey, error) {
+	sig, err := decodeSSHSig(armored)
+	if err != nil {
+		return nil, err
+	}
+	key, err := ssh.ParsePublicKey(sig.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("error reading SSH signature key: %w", err)
+	}
+	if sig.Namespace != sshSigNamespace {
+		return key, fmt.Errorf("SSH signature is for namespace %q, not %q", sig.Namespace, sshSigNamespace)
+	}
+	var digest hash.Hash
+	switch sig.HashAlg {
+	case "sha256":
+		digest = sha256.New()
+	case "sha512":
+		digest = sha512.New()
+	default:
+		return key, fmt.Errorf("unsupported SSH signature hash %q", sig.HashAlg)
+	}
+	digest.Write(message)
+	signed := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace string
+		Reserved  string
+		HashAlg   string
+		Hash      []byte
+	}{sig.Namespace, sig.Reserved, sig.HashAlg, digest.Sum(nil)})...)
+	var blob ssh.Signature
+	if err := ssh.Unmarshal(sig.Signature, &blob); err != nil {
+		return key, fmt.Errorf("error decoding SSH signature blob: %w", err)
+	}
+	return key, key.Verify(signed, &blob)
+}
+
+// verify checks the signature of a commit against the trusted keys,
+// returning the verification result and the identity of the trusted key
+// that made it.
+func (k *TrustedKeys) verify(cmt *object.Commit, signature *Signature) (string, string) {
+	encoded := &plumbing.MemoryObject{}
+	if err := cmt.EncodeWithoutSignature(encoded); err != nil {
+		return VerificationUnchecked, ""
+	}
+	reader, err := encoded.Reader()
+	if err != nil {
+		return VerificationUnchecked, ""
+	}
+	defer reader.Close()
+	var message bytes.Buffer
+	if _, err := message.ReadFrom(reader); err != nil {
+		return VerificationUnchecked, ""
+	}
+	switch signature.Type {
+	case SignatureGPG:
+		entity, err := openpgp.CheckArmoredDetachedSignature(
+			k.pgp,
+			bytes.NewReader(message.Bytes()),
+			strings.NewReader(cmt.PGPSignature),
+			nil,
+		)
+		switch {
+		case errors.Is(err, pgperrors.ErrUnknownIssuer):
+			return VerificationUnknownKey, ""
+		case err != nil:
+			return VerificationBad, ""
+		}
+		if identity := entity.PrimaryIdentity(); identity != nil {
+			return VerificationGood, identity.Name
+		}
+		return VerificationGood, ""
+	case SignatureSSH:
+		principal, trusted := k.ssh[signature.KeyID]
+		if !trusted {
+			return VerificationUnknownKey, ""
+		}
+		if _, err := verifySSHSig(cmt.PGPSignature, message.Bytes()); err != nil {
+			return VerificationBad, ""
+		}
+		return VerificationGood, principal
+	default:
+		return VerificationUnchecked, ""
+	}
+}
+
+// signerKey groups the commits of a signer.
+type signerKey struct {
+	signature    Signature
+	identity     string
+	verification string
+}
+
+// ReportSignatures counts the signed commits among commits by signing key.
+// With trusted keys, each signature is verified against them; a key whose
+// signatures verify differently is listed once per result.
+func ReportSignatures(repo *git.Repository, commits []Commit, keys *TrustedKeys) SignatureReport {
+	report := SignatureReport{
+		Commits:  len(commits),
+		ByType:   make(map[string]int),
+		Verified: keys.Len() > 0,
+	}
+	bySigner := make(map[signerKey]*Signer)
+	for _, commit := range commits {
+		if commit.Signature == nil {
+			continue
+		}
+		report.Signed++
+		report.ByType[commit.Signature.Type]++
+		key := signerKey{signature: *commit.Signature, verification: VerificationUnchecked}
+		if report.Verified {
+			if cmt, err := repo.CommitObject(plumbing.NewHash(commit.Hash)); err == nil {
+				key.verification, key.identity = keys.verify(cmt, commit.Signature)
+			}
+		}
+		signer, seen := bySigner[key]
+		if !seen {
+			signer = &Signer{
+				Type:         key.signature.Type,
+				KeyID:        key.signature.KeyID,
+				Identity:     key.identity,
+				Verification: key.verification,
+			}
+			bySigner[key] = signer
+		}
+		signer.Commits++
+		if !slices.Contains(signer.Authors, commit.Author) {
+			signer.Authors = append(signer.Authors, commit.Author)
+		}
+	}
+	for _, signer := range bySigner {
+		slices.Sort(signer.Authors)
+		report.Signers = append(report.Signers, *signer)
+	}
+	slices.SortFunc(report.Signers, func(a, b Signer) int {
+		if a.Commits != b.Commits {
+			return b.Commits - a.Commits
+		}
+		return strings.Compare(a.KeyID+a.Verification, b.KeyID+b.Verification)
+	})
+	return report
+}
@@ -0,0 +1,222 @@
+package worksummary
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha512"
+	"encoding/base64"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ProtonMail/go-crypto/openpgp"
+	"github.com/ProtonMail/go-crypto/openpgp/armor"
+	"github.com/ProtonMail/go-crypto/openpgp/packet"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/ssh"
+)
+
+// sshSigner signs commits the way git does with gpg.format=ssh.
+type sshSigner struct {
+	signer    ssh.Signer
+	namespace string
+}
+
+// newSSHSigner creates an SSH signer with a fresh ed25519 key.
+func newSSHSigner(t *testing.T, namespace string) sshSigner {
+	t.Helper()
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	require.NoError(t, err)
+	signer, err := ssh.NewSignerFromKey(key)
+	require.NoError(t, err)
+	return sshSigner{signer: signer, namespace: namespace}
+}
+
+// Sign returns an armored SSH signature of message.
+func (s sshSigner) Sign(message io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(message)
+	if err != nil {
+		return nil, err
+	}
+	digest := sha512.Sum512(data)
+	signed := append([]byte(sshSigMagic), ssh.Marshal(struct {
+		Namespace string
+		Reserved  string
+		HashAlg   string
+		Hash      []byte
+	}{s.namespace, "", "sha512", digest[:]})...)
+	sig, err := s.signer.Sign(rand.Reader, signed)
+	if err != nil {
+		return nil, err
+	}
+	blob := append([]byte(sshSigMagic), ssh.Marshal(sshSig{
+		Version:   1,
+		PublicKey: s.signer.PublicKey().Marshal(),
+		Namespace: s.namespace,
+		HashAlg:   "sha512",
+		Signature: ssh.Marshal(sig),
+	})...)
+	encoded := base64.StdEncoding.EncodeToString(blob)
+	var armored strings.Builder
+	armored.WriteString(sshSignatureBegin + "\n")
+	for len(encoded) > 70 {
+		armored.WriteString(encoded[:70] + "\n")
+		encoded = encoded[70:]
+	}
+	armored.WriteString(encoded + "\n" + sshSignatureEnd + "\n")
+	return []byte(armored.String()), nil
+}
+
+// newPGPEntity creates an OpenPGP key for name.
+func newPGPEntity(t *testing.T, name string) *openpgp.Entity {
+	t.Helper()
+	entity, err := openpgp.NewEntity(name, "", strings.ToLower(name)+"@example.org", &packet.Config{
+		Algorithm: packet.PubKeyAlgoEdDSA,
+	})
+	require.NoError(t, err)
+	return entity
+}
+
+// armoredPublicKey returns the armored public key of entity.
+func armoredPublicKey(t *testing.T, entity *openpgp.Entity) string {
+	t.Helper()
+	var buf bytes.Buffer
+	writer, err := armor.Encode(&buf, openpgp.PublicKeyType, nil)
+	require.NoError(t, err)
+	require.NoError(t, entity.Serialize(writer))
+	require.NoError(t, writer.Close())
+	return buf.String()
+}
+
+// signedCommit adds a commit by author, signed as opts says, to the
+// repository in dir.
+func signedCommit(t *testing.T, repo *git.Repository, dir, author string, opts git.CommitOptions) {
+	t.Helper()
+	worktree, err := repo.Worktree()
+	require.NoError(t, err)
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "file.txt"), []byte(time.Now().String()), 0o600))
+	_, err = worktree.Add("file.txt")
+	require.NoError(t, err)
+	opts.Author = &object.Signature{Name: author, Email: "dev@example.org", When: time.Now()}
+	_, err = worktree.Commit("change by "+author, &opts)
+	require.NoError(t, err)
+}
+
+// repoCommits lists the commits of repo.
+func repoCommits(t *testing.T, repo *git.Repository) []Commit {
+	t.Helper()
+	iter, err := repo.Log(&git.LogOptions{})
+	require.NoError(t, err)
+	var commits []Commit
+	require.NoError(t, iter.ForEach(func(cmt *object.Commit) error {
+		commits = append(commits, newCommit(cmt))
+		return nil
+	}))
+	return commits
+}
+
+func TestParseTrustedKeys(t *testing.T) {
+	t.Parallel()
+	entity := newPGPEntity(t, "Jane")
+	signer := newSSHSigner(t, "git")
+	other := newSSHSigner(t, "git")
+	authorized := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(other.signer.PublicKey())))
+	data := strings.Join([]string{
+		"# maintainers",
+		"joe@example.org " + strings.TrimSpace(string(ssh.MarshalAuthorizedKey(signer.signer.PublicKey()))),
+		"",
+		armoredPublicKey(t, entity),
+		authorized + " ann@laptop",
+	}, "\n")
+	keys, err := ParseTrustedKeys([]byte(data))
+	require.NoError(t, err)
+	assert.Equal(t, 3, keys.Len())
+	assert.Equal(t, "joe@example.org", keys.ssh[ssh.FingerprintSHA256(signer.signer.PublicKey())])
+	assert.Equal(t, "ann@laptop", keys.ssh[ssh.FingerprintSHA256(other.signer.PublicKey())])
+
+	_, err = ParseTrustedKeys([]byte("not a key"))
+	require.Error(t, err)
+	_, err = ParseTrustedKeys([]byte(pgpPublicKeyBegin + "\n"))
+	require.Error(t, err)
+	assert.Zero(t, (*TrustedKeys)(nil).Len())
+}
+
+func TestReportSignatures(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	repo, err := git.PlainInit(dir, false)
+	require.NoError(t, err)
+	jane := newPGPEntity(t, "Jane")
+	stranger := newPGPEntity(t, "Stranger")
+	joe := newSSHSigner(t, "git")
+	wrongNamespace := newSSHSigner(t, "file")
+	signedCommit(t, repo, dir, "Ann", git.CommitOptions{})
+	signedCommit(t, repo, dir, "Jane", git.CommitOptions{SignKey: jane})
+	signedCommit(t, repo, dir, "Jane", git.CommitOptions{SignKey: jane})
+	signedCommit(t, repo, dir, "Ann", git.CommitOptions{SignKey: stranger})
+	signedCommit(t, repo, dir, "Joe", git.CommitOptions{Signer: joe})
+	signedCommit(t, repo, dir, "Joe", git.CommitOptions{Signer: wrongNamespace})
+	commits := repoCommits(t, repo)
+	janeID := strings.ToUpper(jane.PrimaryKey.KeyIdString())
+	joeID := ssh.FingerprintSHA256(joe.signer.PublicKey())
+
+	report := ReportSignatures(repo, commits, nil)
+	assert.Equal(t, 6, report.Commits)
+	assert.Equal(t, 5, report.Signed)
+	assert.Equal(t, map[string]int{SignatureGPG: 3, SignatureSSH: 2}, report.ByType)
+	assert.False(t, report.Verified)
+	require.Len(t, report.Signers, 4)
+	assert.Equal(t, Signer{
+		Type:         SignatureGPG,
+		KeyID:        janeID,
+		Authors:      []string{"Jane"},
+		Commits:      2,
+		Verification: VerificationUnchecked,
+	}, report.Signers[0])
+
+	allowed := "joe@example.org " + string(ssh.MarshalAuthorizedKey(joe.signer.PublicKey())) +
+		"wrong@example.org " + string(ssh.MarshalAuthorizedKey(wrongNamespace.signer.PublicKey())) +
+		armoredPublicKey(t, jane)
+	keys, err := ParseTrustedKeys([]byte(allowed))
+	require.NoError(t, err)
+	report = ReportSignatures(repo, commits, keys)
+	assert.True(t, report.Verified)
+	byKey := make(map[string]Signer)
+	for _, signer := range report.Signers {
+		byKey[signer.KeyID] = signer
+	}
+	assert.Equal(t, VerificationGood, byKey[janeID].Verification)
+	assert.Equal(t, "Jane <jane@example.org>", byKey[janeID].Identity)
+	assert.Equal(t, VerificationGood, byKey[joeID].Verification)
+	assert.Equal(t, "joe@example.org", byKey[joeID].Identity)
+	assert.Equal(t, VerificationUnknownKey, byKey[strings.ToUpper(stranger.PrimaryKey.KeyIdString())].Verification)
+	assert.Equal(
+		t,
+		VerificationBad,
+		byKey[ssh.FingerprintSHA256(wrongNamespace.signer.PublicKey())].Verification,
+		"signatures outside the git namespace do not verify",
+	)
+}
+
+func TestParseSignature(t *testing.T) {
+	t.Parallel()
+	assert.Nil(t, parseSignature(""))
+	assert.Equal(
+		t,
+		&Signature{Type: SignatureX509},
+		parseSignature("-----BEGIN SIGNED MESSAGE-----\nMIIB\n-----END SIGNED MESSAGE-----\n"),
+	)
+	assert.Equal(
+		t,
+		&Signature{Type: SignatureGPG},
+		parseSignature("-----BEGIN PGP SIGNATURE-----\n\ngarbage\n-----END PGP SIGNATURE-----\n"),
+		"unreadable signatures keep their type",
+	)
+}
@@ -0,0 +1,268 @@
+package worksummary
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/go-git/go-billy/v5/osfs"
+	git "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// CloneStorage selects where a clone keeps the objects of a repository.
+type CloneStorage string
+
+const (
+	// StorageAuto keeps a repository in memory unless its estimated size
+	// is above the temp-dir threshold of the analyzer.
+	StorageAuto CloneStorage = "auto"
+	// StorageMemory keeps a repository in memory.
+	StorageMemory CloneStorage = "memory"
+	// StorageTempDir keeps a repository in a temporary directory that is
+	// removed when the clone is closed.
+	StorageTempDir CloneStorage = "temp-dir"
+	// StorageLocal is a repository already on disk, opened in place
+	// instead of cloned.
+	StorageLocal CloneStorage = "local"
+)
+
+const (
+	// DefaultTempDirThreshold is the estimated repository size above which
+	// StorageAuto clones into a temporary directory.
+	DefaultTempDirThreshold int64 = 100 << 20
+	// DefaultGitHubAPI is the GitHub API the default size estimator asks.
+	DefaultGitHubAPI = "https://api.github.com"
+	// sizeTimeout bounds the request for the size of a repository.
+	sizeTimeout = 5 * time.Second
+)
+
+// CloneStorages lists the accepted storage names, for tool schemas.
+var CloneStorages = []string{string(StorageAuto), string(StorageMemory), string(StorageTempDir)}
+
+// SizeFunc estimates the size in bytes of a repository before it is
+// cloned. It reports false when the size is unknown.
+type SizeFunc func(ctx context.Context, repoURL string) (int64, bool)
+
+// Clone is a cloned repository. Close releases its storage.
+type Clone struct {
+	*git.Repository
+	// Storage is where the clone keeps its objects, StorageMemory,
+	// StorageTempDir or StorageLocal.
+	Storage CloneStorage
+	// Origin is the URL of the origin remote of a local repository, if it
+	// has one.
+	Origin string
+	// dir is the temporary directory of the clone, if any.
+	dir string
+}
+
+// Close removes the temporary directory of the clone, if any. It may be
+// called more than once.
+func (c *Clone) Close() error {
+	if c == nil || c.dir == "" {
+		return nil
+	}
+	dir := c.dir
+	c.dir = ""
+	if err := os.RemoveAll(dir); err != nil {
+		return fmt.Errorf("error removing clone directory %s: %w", dir, err)
+	}
+	return nil
+}
+
+// CloneOption configures a single call of CloneAndCheckout.
+type CloneOption func(*cloneConfig)
+
+// cloneConfig holds the settings of a single clone.
+type cloneConfig struct {
+	storage CloneStorage
+	// size is the known size of the repository in bytes, or negative.
+	size int64
+	// branches are fetched along with the branch checked out.
+	branches []string
+}
+
+// WithStorage overrides the storage of the analyzer for one clone. An
+// empty storage keeps the analyzer's.
+func WithStorage(storage CloneStorage) CloneOption {
+	return func(c *cloneConfig) {
+		if storage != "" {
+			c.storage = storage
+		}
+	}
+}
+
+// WithBranches fetches branches along with the branch checked out, so the
+// history of each can be read from the one clone.
+func WithBranches(branches ...string) CloneOption {
+	return func(c *cloneConfig) {
+		c.branches = append(c.branches, branches...)
+	}
+}
+
+// WithExpectedSize gives the size in bytes of the repository when the
+// caller already knows it, so StorageAuto does not estimate it.
+func WithExpectedSize(size int64) CloneOption {
+	return func(c *cloneConfig) {
+		c.size = size
+	}
+}
+
+// WithCloneStorage sets the storage of clones, StorageAuto by default.
+func WithCloneStorage(storage CloneStorage) GitAnalyzerOption {
+	return func(ga *GitAnalyzer) {
+		ga.storage = storage
+	}
+}
+
+// WithTempDirThreshold sets the estimated size in bytes above which
+// StorageAuto clones into a temporary directory.
+func WithTempDirThreshold(size int64) GitAnalyzerOption {
+	return func(ga *GitAnalyzer) {
+		ga.tempDirThreshold = size
+	}
+}
+
+// WithSizeEstimator replaces the estimate of repository sizes StorageAuto
+// relies on; nil treats every size as unknown, keeping clones in memory.
+func WithSizeEstimator(size SizeFunc) GitAnalyzerOption {
+	return func(ga *GitAnalyzer) {
+		ga.size = size
+	}
+}
+
+// resolveStorage picks the storage of a clone, estimating the size of the
+// repository for StorageAuto. Repositories of unknown size are kept in
+// memory.
+func (ga *GitAnalyzer) resolveStorage(ctx context.Context, repoURL string, config cloneConfig) (CloneStorage, error) {
+	switch config.storage {
+	case StorageMemory, StorageTempDir:
+		return config.storage, nil
+	case StorageAuto, "":
+	default:
+		return "", fmt.Errorf(
+			"unknown clone storage %q, use one of %s",
+			config.storage, strings.Join(CloneStorages, ", "),
+		)
+	}
+	size, known := config.size, config.size >= 0
+	if !known && ga.size != nil {
+		size, known = ga.size(ctx, repoURL)
+	}
+	if known && size > ga.tempDirThreshold {
+		ga.logger.Debug(
+			"cloning into a temporary directory",
+			"repo_url", repoURL,
+			"size", size,
+			"threshold", ga.tempDirThreshold,
+		)
+		return StorageTempDir, nil
+	}
+	return StorageMemory, nil
+}
+
+// newStorer creates the storage of a clone, returning the temporary
+// directory it lives in, if any.
+func newStorer(kind CloneStorage) (storage.Storer, string, error) {
+	if kind != StorageTempDir {
+		return memory.NewStorage(), "", nil
+	}
+	dir, err := os.MkdirTemp("", "dcr-mcp-clone-*")
+	if err != nil {
+		return nil, "", fmt.Errorf("error creating clone directory: %w", err)
+	}
+	return filesystem.NewStorage(osfs.New(dir), cache.NewObjectLRUDefault()), dir, nil
+}
+
+// NewSizeEstimator returns a SizeFunc that measures local repositories on
+// disk and asks the GitHub API at apiBase for the size of github.com
+// repositories, authenticating with token when it is not empty. The sizes
+// of other repositories are unknown.
+func NewSizeEstimator(client *http.Client, apiBase, token string) SizeFunc {
+	return func(ctx context.Context, repoURL string) (int64, bool) {
+		if dir, ok := localPath(repoURL); ok {
+			return dirSize(dir)
+		}
+		host, repoPath, ok := splitRemote(repoURL)
+		if !ok || host != "github.com" {
+			return 0, false
+		}
+		return gitHubSize(ctx, client, apiBase, token, strings.TrimSuffix(strings.Trim(repoPath, "/"), ".git"))
+	}
+}
+
+// localPath returns the directory of a repository given by a local path
+// or file URL.
+func localPath(repoURL string) (string, bool) {
+	if dir, found := strings.CutPrefix(repoURL, "file://"); found {
+		return dir, true
+	}
+	if strings.HasPrefix(repoURL, "/") || strings.HasPrefix(repoURL, ".") {
+		return repoURL, true
+	}
+	return "", false
+}
+
+// dirSize adds up the sizes of the files below dir.
+func dirSize(dir string) (int64, bool) {
+	var size int64
+	err := filepath.WalkDir(dir, func(_ string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !entry.Type().IsRegular() {
+			return nil
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		size += info.Size()
+		return nil
+	})
+	return size, err == nil
+}
+
+// gitHubSize asks the GitHub API for the size of the repository
+// owner/name, which it reports in kilobytes.
+func gitHubSize(ctx context.Context, client *http.Client, apiBase, token, fullName string) (int64, bool) {
+	ctx, cancel := context.WithTimeout(ctx, sizeTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(apiBase, "/")+"/repos/"+fullName, nil)
+	if err != nil {
+		return 0, false
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	start := time.Now()
+	resp, err := client.Do(req)
+	metrics.ObserveOutbound(metrics.ServiceGitHub, start, err)
+	if err != nil {
+		return 0, false
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return 0, false
+	}
+	var repo struct {
+		Size int64 `json:"size"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&repo); err != nil {
+		return 0, false
+	}
+	return repo.Size << 10, true
+}
@@ -0,0 +1,108 @@
+package worksummary
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fixedSize estimates every repository at size bytes.
+func fixedSize(size int64, known bool) SizeFunc {
+	return func(context.Context, string) (int64, bool) {
+		return size, known
+	}
+}
+
+func TestResolveStorage(t *testing.T) {
+	t.Parallel()
+	analyzer := NewGitAnalyzer(
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithTempDirThreshold(1000),
+		WithSizeEstimator(fixedSize(2000, true)),
+	)
+	tests := []struct {
+		name   string
+		config cloneConfig
+		size   SizeFunc
+		want   CloneStorage
+	}{
+		{"auto above the threshold", cloneConfig{storage: StorageAuto, size: -1}, fixedSize(2000, true), StorageTempDir},
+		{"auto below the threshold", cloneConfig{storage: StorageAuto, size: -1}, fixedSize(500, true), StorageMemory},
+		{"auto of unknown size", cloneConfig{storage: StorageAuto, size: -1}, fixedSize(0, false), StorageMemory},
+		{"auto without an estimator", cloneConfig{storage: StorageAuto, size: -1}, nil, StorageMemory},
+		{"expected size", cloneConfig{storage: StorageAuto, size: 10}, fixedSize(2000, true), StorageMemory},
+		{"memory", cloneConfig{storage: StorageMemory, size: -1}, fixedSize(2000, true), StorageMemory},
+		{"temp-dir", cloneConfig{storage: StorageTempDir, size: 1}, fixedSize(1, true), StorageTempDir},
+	}
+	for _, tt := range tests {
+		local := *analyzer
+		local.size = tt.size
+		got, err := local.resolveStorage(context.Background(), "/srv/git/repo", tt.config)
+		require.NoError(t, err, tt.name)
+		assert.Equal(t, tt.want, got, tt.name)
+	}
+	_, err := analyzer.resolveStorage(context.Background(), "/srv/git/repo", cloneConfig{storage: "disk"})
+	require.ErrorContains(t, err, `unknown clone storage "disk"`)
+}
+
+func TestCloneAndCheckout_TempDir(t *testing.T) {
+	t.Parallel()
+	analyzer := NewGitAnalyzer(WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))))
+	clone, err := analyzer.CloneAndCheckout(
+		context.Background(),
+		branchedRepo(t),
+		"master",
+		WithStorage(StorageTempDir),
+	)
+	require.NoError(t, err)
+	assert.Equal(t, StorageTempDir, clone.Storage)
+	dir := clone.dir
+	assert.DirExists(t, dir)
+	head, err := clone.Head()
+	require.NoError(t, err)
+	assert.Equal(t, "refs/heads/master", head.Name().String())
+
+	require.NoError(t, clone.Close())
+	assert.NoDirExists(t, dir, "closing removes the clone")
+	require.NoError(t, clone.Close())
+
+	inMemory, err := analyzer.CloneAndCheckout(context.Background(), branchedRepo(t), "master", WithStorage(StorageMemory))
+	require.NoError(t, err)
+	assert.Equal(t, StorageMemory, inMemory.Storage)
+	require.NoError(t, inMemory.Close())
+}
+
+func TestNewSizeEstimator(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/repos/dictybase/dcr-mcp" || r.Header.Get("Authorization") != "Bearer secret" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{"size": 2048}`))
+	}))
+	t.Cleanup(server.Close)
+	estimate := NewSizeEstimator(server.Client(), server.URL, "secret")
+
+	size, known := estimate(context.Background(), "https://github.com/dictybase/dcr-mcp.git")
+	require.True(t, known)
+	assert.Equal(t, int64(2<<20), size)
+	_, known = estimate(context.Background(), "https://github.com/dictybase/missing.git")
+	assert.False(t, known, "repositories GitHub does not show are of unknown size")
+	_, known = estimate(context.Background(), "https://git.example.org/dictybase/dcr-mcp.git")
+	assert.False(t, known, "other hosts are of unknown size")
+
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "pack"), make([]byte, 1500), 0o600))
+	size, known = estimate(context.Background(), dir)
+	require.True(t, known)
+	assert.Equal(t, int64(1500), size)
+}
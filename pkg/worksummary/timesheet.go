@@ -0,0 +1,130 @@
+package worksummary
+
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// MaxSessionGap is the longest pause between two commits of an author
+	// that still counts as work; a longer pause starts a new session.
+	MaxSessionGap = 2 * time.Hour
+	// SessionStart is the work credited before the first commit of a
+	// session, which has no earlier commit to measure from.
+	SessionStart = 30 * time.Minute
+)
+
+// TimesheetRow is the effort of an author on a day.
+type TimesheetRow struct {
+	// Date is the day in the author's time zone, as YYYY-MM-DD.
+	Date    string `json:"date"`
+	Author  string `json:"author"`
+	Repo    string `json:"repo"`
+	Commits int    `json:"commits"`
+	// Hours is the inferred effort, rounded to the quarter hour.
+	Hours float64 `json:"hours"`
+}
+
+// Timesheet is the effort behind a range of commits, one row per author
+// and day.
+type Timesheet struct {
+	Rows       []TimesheetRow `json:"rows"`
+	TotalHours float64        `json:"total_hours"`
+}
+
+// NewTimesheet groups commits by author and day and infers the hours
+// worked: each session of commits no more than MaxSessionGap apart counts
+// from SessionStart before its first commit to its last. Rows are sorted
+// by date, then author.
+func NewTimesheet(repo string, commits []Commit) Timesheet {
+	byDay := make(map[[2]string][]time.Time)
+	for _, commit := range commits {
+		key := [2]string{commit.When.Format(time.DateOnly), commit.Author}
+		byDay[key] = append(byDay[key], commit.When)
+	}
+	sheet := Timesheet{Rows: make([]TimesheetRow, 0, len(byDay))}
+	for key, times := range byDay {
+		hours := roundQuarter(sessionHours(times))
+		sheet.Rows = append(sheet.Rows, TimesheetRow{
+			Date:    key[0],
+			Author:  key[1],
+			Repo:    repo,
+			Commits: len(times),
+			Hours:   hours,
+		})
+		sheet.TotalHours += hours
+	}
+	slices.SortFunc(sheet.Rows, func(a, b TimesheetRow) int {
+		if a.Date != b.Date {
+			return strings.Compare(a.Date, b.Date)
+		}
+		return strings.Compare(a.Author, b.Author)
+	})
+	return sheet
+}
+
+// MergeTimesheets joins the timesheets of several repositories into one,
+// its rows sorted by date, author and repository.
+func MergeTimesheets(sheets ...Timesheet) Timesheet {
+	merged := Timesheet{Rows: []TimesheetRow{}}
+	for _, sheet := range sheets {
+		merged.Rows = append(merged.Rows, sheet.Rows...)
+		merged.TotalHours += sheet.TotalHours
+	}
+	slices.SortStableFunc(merged.Rows, func(a, b TimesheetRow) int {
+		if a.Date != b.Date {
+			return strings.Compare(a.Date, b.Date)
+		}
+		if a.Author != b.Author {
+			return strings.Compare(a.Author, b.Author)
+		}
+		return strings.Compare(a.Repo, b.Repo)
+	})
+	return merged
+}
+
+// sessionHours infers the hours worked from the times of an author's
+// commits on a day.
+func sessionHours(times []time.Time) float64 {
+	slices.SortFunc(times, time.Time.Compare)
+	worked := SessionStart
+	for i := 1; i < len(times); i++ {
+		gap := times[i].Sub(times[i-1])
+		if gap > MaxSessionGap {
+			worked += SessionStart
+			continue
+		}
+		worked += gap
+	}
+	return worked.Hours()
+}
+
+// roundQuarter rounds hours to the nearest quarter hour.
+func roundQuarter(hours float64) float64 {
+	return math.Round(hours*4) / 4
+}
+
+// CSV renders the timesheet as CSV with a header row.
+func (t Timesheet) CSV() (string, error) {
+	var sb strings.Builder
+	writer := csv.NewWriter(&sb)
+	rows := [][]string{{"date", "author", "repo", "commits", "hours"}}
+	for _, row := range t.Rows {
+		rows = append(rows, []string{
+			row.Date,
+			row.Author,
+			row.Repo,
+			strconv.Itoa(row.Commits),
+			strconv.FormatFloat(row.Hours, 'f', 2, 64),
+		})
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		return "", fmt.Errorf("error writing timesheet: %w", err)
+	}
+	return sb.String(), nil
+}
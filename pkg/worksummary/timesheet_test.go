@@ -0,0 +1,78 @@
+package worksummary
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimesheet(t *testing.T) {
+	t.Parallel()
+	berlin := time.FixedZone("CEST", 2*60*60)
+	at := func(day, hour, minute int) time.Time {
+		return time.Date(2025, time.June, day, hour, minute, 0, 0, berlin)
+	}
+	commits := []Commit{
+		{Author: "Jane", When: at(2, 11, 0)},
+		{Author: "Jane", When: at(2, 9, 0)},
+		{Author: "Jane", When: at(2, 10, 10)},
+		// A pause of more than two hours starts a new session.
+		{Author: "Jane", When: at(2, 15, 0)},
+		{Author: "Joe", When: at(2, 9, 0)},
+		// Local dates count, not UTC ones.
+		{Author: "Jane", When: at(3, 1, 0)},
+	}
+	sheet := NewTimesheet("dcr-mcp", commits)
+	assert.Equal(t, []TimesheetRow{
+		{Date: "2025-06-02", Author: "Jane", Repo: "dcr-mcp", Commits: 4, Hours: 3},
+		{Date: "2025-06-02", Author: "Joe", Repo: "dcr-mcp", Commits: 1, Hours: 0.5},
+		{Date: "2025-06-03", Author: "Jane", Repo: "dcr-mcp", Commits: 1, Hours: 0.5},
+	}, sheet.Rows)
+	assert.InDelta(t, 4.0, sheet.TotalHours, 0.001)
+
+	text, err := sheet.CSV()
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		"date,author,repo,commits,hours\n"+
+			"2025-06-02,Jane,dcr-mcp,4,3.00\n"+
+			"2025-06-02,Joe,dcr-mcp,1,0.50\n"+
+			"2025-06-03,Jane,dcr-mcp,1,0.50\n",
+		text,
+	)
+}
+
+func TestNewTimesheet_Empty(t *testing.T) {
+	t.Parallel()
+	sheet := NewTimesheet("dcr-mcp", nil)
+	assert.Empty(t, sheet.Rows)
+	text, err := sheet.CSV()
+	require.NoError(t, err)
+	assert.Equal(t, "date,author,repo,commits,hours\n", text)
+}
+
+func TestMergeTimesheets(t *testing.T) {
+	t.Parallel()
+	merged := MergeTimesheets(
+		Timesheet{
+			Rows: []TimesheetRow{
+				{Date: "2025-06-02", Author: "Jane", Repo: "stock-center", Commits: 2, Hours: 1.5},
+				{Date: "2025-06-03", Author: "Jane", Repo: "stock-center", Commits: 1, Hours: 0.5},
+			},
+			TotalHours: 2,
+		},
+		Timesheet{
+			Rows:       []TimesheetRow{{Date: "2025-06-02", Author: "Jane", Repo: "annotations", Commits: 1, Hours: 0.5}},
+			TotalHours: 0.5,
+		},
+	)
+	assert.InDelta(t, 2.5, merged.TotalHours, 0.001)
+	require.Len(t, merged.Rows, 3)
+	assert.Equal(t, []string{"annotations", "stock-center", "stock-center"}, []string{
+		merged.Rows[0].Repo, merged.Rows[1].Repo, merged.Rows[2].Repo,
+	})
+	assert.Equal(t, "2025-06-03", merged.Rows[2].Date)
+	assert.Empty(t, MergeTimesheets().Rows)
+}
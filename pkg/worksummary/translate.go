@@ -0,0 +1,43 @@
+package worksummary
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+)
+
+// TranslationPrompt is the system prompt of translations; %s is the
+// language to translate to.
+const TranslationPrompt = `
+	You translate the abstracts of scientific articles into %s. Keep the
+	meaning, the technical terms, gene and protein names, numbers and units
+	exactly as they are. Answer with the translation only, without a
+	preamble, notes or quotation marks.
+	`
+
+// Translate translates text, such as the abstract of an article, into the
+// named language.
+func (c *OpenAIClient) Translate(ctx context.Context, text, language string) (translation string, err error) {
+	if err := validate.Var(text, "required"); err != nil {
+		return "", fmt.Errorf("text to translate cannot be empty: %w", err)
+	}
+	start := time.Now()
+	defer func() {
+		metrics.ObserveOutbound(metrics.ServiceOpenAI, start, err)
+	}()
+	resp, err := c.client.CreateChatCompletion(
+		ctx,
+		chatCompletionRequest(c.model, 0, fmt.Sprintf(TranslationPrompt, language), text),
+	)
+	if err != nil {
+		return "", fmt.Errorf("OpenAI completion error: %w", err)
+	}
+	if len(resp.Choices) == 0 || strings.TrimSpace(resp.Choices[0].Message.Content) == "" {
+		return "", errors.New("the model returned no translation")
+	}
+	return strings.TrimSpace(resp.Choices[0].Message.Content), nil
+}
@@ -0,0 +1,44 @@
+package worksummary
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTranslate(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{
+		"role":    "assistant",
+		"content": "\nCells move towards cAMP.\n",
+	}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	translation, err := client.Translate(context.Background(), "Zellen bewegen sich zu cAMP.", "English")
+	require.NoError(t, err)
+	assert.Equal(t, "Cells move towards cAMP.", translation)
+	require.Len(t, requests, 1)
+	messages, ok := requests[0]["messages"].([]any)
+	require.True(t, ok)
+	require.Len(t, messages, 2)
+	assert.Contains(t, messages[0].(map[string]any)["content"], "into English")
+	assert.Equal(t, "Zellen bewegen sich zu cAMP.", messages[1].(map[string]any)["content"])
+
+	_, err = client.Translate(context.Background(), "", "English")
+	require.Error(t, err, "there is nothing to translate")
+}
+
+func TestTranslate_Empty(t *testing.T) {
+	t.Parallel()
+	var requests []map[string]any
+	server := completion(t, map[string]any{"role": "assistant", "content": " "}, &requests)
+	client, err := NewOpenAIClient("test-key", WithBaseURL(server.URL))
+	require.NoError(t, err)
+
+	_, err = client.Translate(context.Background(), "Zellen bewegen sich zu cAMP.", "English")
+	require.ErrorContains(t, err, "no translation")
+}
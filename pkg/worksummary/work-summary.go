@@ -3,15 +3,18 @@ package worksummary
 import (
 	"context"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"os"
 	"strings"
 	"time"
 
+	"github.com/dictybase/dcr-mcp/pkg/metrics"
+	"github.com/dictybase/dcr-mcp/pkg/progress"
+	"github.com/dictybase/dcr-mcp/pkg/ratelimit"
 	git "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/storage/memory"
 	validator "github.com/go-playground/validator/v10"
 	dps "github.com/markusmobius/go-dateparser"
 	"github.com/markusmobius/go-dateparser/date"
@@ -24,23 +27,74 @@ var validate = validator.New()
 // repositories, parsing dates, and retrieving commit histories within specified
 // date ranges.
 type GitAnalyzer struct {
-	logger     *log.Logger
+	logger     *slog.Logger
 	dateConfig *dps.Configuration
+	// dial checks the host of a repository can be reached before cloning.
+	dial DialFunc
+	// storage, tempDirThreshold and size choose where clones are kept.
+	storage          CloneStorage
+	tempDirThreshold int64
+	size             SizeFunc
 }
 
 // CommitRangeParams holds parameters for listing commits in a date range.
 type CommitRangeParams struct {
-	Repo   *git.Repository `validate:"required"`
-	Start  time.Time       `validate:"required"`
-	End    time.Time       `validate:"required"`
-	Author string          `validate:"required"`
+	Repo *git.Repository `validate:"required"`
+	// Start and End bound the commit dates; both may be zero when Exclude
+	// bounds the history instead.
+	Start time.Time `validate:"required_without=Exclude"`
+	End   time.Time `validate:"required_without=Exclude"`
+	// Authors selects the authors whose commits are listed.
+	Authors AuthorFilter
+	// From is the commit the history is listed from, HEAD when zero.
+	From plumbing.Hash
+	// Exclude leaves out the commits reachable from it, so that From and
+	// Exclude list the commits between two refs; zero leaves out none.
+	Exclude plumbing.Hash
+	// Stats computes the diff stats of each commit listed.
+	Stats bool
+	// Patches selects the patches listed with each commit, newest first
+	// until their token budget is spent, or is nil for none.
+	Patches *PatchOptions
+}
+
+// ActivityParams holds parameters for listing all activity in a date range.
+// A zero Start or End leaves that side of the range open.
+type ActivityParams struct {
+	Repo  *git.Repository `validate:"required"`
+	Start time.Time
+	End   time.Time
+}
+
+// Commit describes a single commit.
+type Commit struct {
+	Hash    string
+	Author  string
+	Email   string
+	When    time.Time
+	Subject string
+	// Message is the full commit message.
+	Message string
+	// Signature describes the signature of a signed commit, or is nil.
+	Signature *Signature
+	// Branches names the branches the commit is on when the commits of
+	// several branches were listed together, or is nil.
+	Branches []string
+	// Stats is the size of the change when stats were requested, or nil.
+	Stats *DiffStats
+	// Patch is the change to the selected paths when patches were
+	// requested, or nil when there were none.
+	Patch *Patch
+	// Conventional is the message parsed as a conventional commit, or nil
+	// when it does not follow the convention.
+	Conventional *ConventionalCommit
 }
 
 // GitAnalyzerOption defines a functional option for configuring GitAnalyzer.
 type GitAnalyzerOption func(*GitAnalyzer)
 
 // WithLogger sets a custom logger for GitAnalyzer.
-func WithLogger(logger *log.Logger) GitAnalyzerOption {
+func WithLogger(logger *slog.Logger) GitAnalyzerOption {
 	return func(ga *GitAnalyzer) {
 		ga.logger = logger
 	}
@@ -63,15 +117,21 @@ func WithTimeZone(tz *time.Location) GitAnalyzerOption {
 // NewGitAnalyzer creates a new GitAnalyzer with the provided options.
 func NewGitAnalyzer(opts ...GitAnalyzerOption) *GitAnalyzer {
 	gitAnalyzer := &GitAnalyzer{
-		logger: log.New(
-			os.Stderr,
-			"[git-commit-summary] ",
-			log.LstdFlags|log.Lmsgprefix,
+		logger: slog.New(slog.NewTextHandler(os.Stderr, nil)).With(
+			"component", "git-commit-summary",
 		),
 		dateConfig: &dps.Configuration{
 			DefaultTimezone: time.Local,
 			CurrentTime:     time.Now(),
 		},
+		dial:             (&net.Dialer{}).DialContext,
+		storage:          StorageAuto,
+		tempDirThreshold: DefaultTempDirThreshold,
+		size: NewSizeEstimator(
+			ratelimit.NewHTTPClient(sizeTimeout),
+			DefaultGitHubAPI,
+			os.Getenv("GITHUB_TOKEN"),
+		),
 	}
 
 	// Apply all options
@@ -130,9 +190,17 @@ func (ga *GitAnalyzer) ParseAnalysisDates(
 }
 
 // CloneAndCheckout clones a repository and checks out the specified branch.
+// The URL is normalized with NormalizeRepoURL and its host checked first.
+// With StorageAuto, a local path or file URL is opened in place instead,
+// with the branch read from the repository's refs and its working tree
+// left untouched; StorageMemory and StorageTempDir still clone it.
+// The clone is kept in memory or in a temporary directory as chosen by the
+// storage of the analyzer or opts; callers Close it once done. Branches
+// added by WithBranches can be read with Clone.Branch.
+// Clone progress goes to the progress reporter of ctx.
 func (ga *GitAnalyzer) CloneAndCheckout(
-	ctx context.Context, repoURL, branchName string,
-) (*git.Repository, error) {
+	ctx context.Context, repoURL, branchName string, opts ...CloneOption,
+) (*Clone, error) {
 	// Validate inputs
 	if err := validate.Var(repoURL, "required"); err != nil {
 		return nil, fmt.Errorf("repository URL cannot be empty: %w", err)
@@ -140,54 +208,114 @@ func (ga *GitAnalyzer) CloneAndCheckout(
 	if err := validate.Var(branchName, "required"); err != nil {
 		return nil, fmt.Errorf("branch name cannot be empty: %w", err)
 	}
+	repoURL, err := ga.prepareRepoURL(ctx, repoURL)
+	if err != nil {
+		return nil, err
+	}
+	config := cloneConfig{storage: ga.storage, size: -1}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if dir, ok := localPath(repoURL); ok && (config.storage == StorageAuto || config.storage == "") {
+		clone, err := ga.openLocal(dir, branchName)
+		if err != nil {
+			return nil, err
+		}
+		return clone, clone.checkBranches(config.branches)
+	}
+	kind, err := ga.resolveStorage(ctx, repoURL, config)
+	if err != nil {
+		return nil, err
+	}
+	storer, dir, err := newStorer(kind)
+	if err != nil {
+		return nil, err
+	}
 
-	ga.logger.Printf("Analyzing repository: %s", repoURL)
-	ga.logger.Printf("Cloning branch: %s", branchName)
+	ga.logger.Info(
+		"cloning repository",
+		"repo_url", repoURL,
+		"branch", branchName,
+		"storage", kind,
+	)
 
+	start := time.Now()
 	repo, err := git.CloneContext(
 		ctx,
-		memory.NewStorage(),
+		storer,
 		nil,
 		&git.CloneOptions{
 			URL:           repoURL,
 			ReferenceName: plumbing.NewBranchReferenceName(branchName),
 			SingleBranch:  true,
-			Progress:      os.Stdout,
+			Progress:      cloneProgress{reporter: progress.FromContext(ctx)},
 		},
 	)
+	metrics.ObserveOutbound(metrics.ServiceGitClone, start, err)
+	clone := &Clone{Repository: repo, Storage: kind, dir: dir}
 	if err != nil {
+		_ = clone.Close()
 		return nil, fmt.Errorf("error cloning repository: %w", err)
 	}
-	return repo, nil
+	if err := ga.fetchBranches(ctx, clone, config.branches); err != nil {
+		_ = clone.Close()
+		return nil, err
+	}
+	return clone, nil
 }
 
-// ListCommitsInRange retrieves commit messages from the repository within the specified date range.
+// ListCommitsInRange retrieves commit messages from the repository within the specified date range,
+// with the diff stats of each commit when params.Stats is set and its patch when params.Patches is.
 func (ga *GitAnalyzer) ListCommitsInRange(
 	ctx context.Context, params CommitRangeParams,
 ) (string, error) {
+	commits, err := ga.ListAuthorCommits(ctx, params)
+	if err != nil {
+		return "", err
+	}
+	return Messages(commits), nil
+}
+
+// ListAuthorCommits returns the commits of the selected authors within
+// the date range, or between Exclude and From, newest first.
+func (ga *GitAnalyzer) ListAuthorCommits(
+	ctx context.Context, params CommitRangeParams,
+) ([]Commit, error) {
 	// Validate params using validator
 	if err := validate.Struct(params); err != nil {
-		return "", fmt.Errorf("invalid commit range parameters: %w", err)
+		return nil, fmt.Errorf("invalid commit range parameters: %w", err)
 	}
 
-	ga.logger.Printf(
-		"Date range: %s - %s",
-		params.Start.Format("2006-01-02"),
-		params.End.Format("2006-01-02"),
-	)
+	options := &git.LogOptions{From: params.From, Order: git.LogOrderCommitterTime}
+	var excluded map[plumbing.Hash]bool
+	if params.Exclude.IsZero() {
+		ga.logger.Info(
+			"listing commits",
+			"start", params.Start.Format("2006-01-02"),
+			"end", params.End.Format("2006-01-02"),
+		)
+		options.Since, options.Until = &params.Start, &params.End
+	} else {
+		ga.logger.Info("listing commits", "from", params.Exclude, "to", params.From)
+		var err error
+		excluded, err = reachable(params.Repo, params.Exclude)
+		if err != nil {
+			return nil, err
+		}
+	}
 
-	var buf strings.Builder
-	commitIter, err := params.Repo.Log(
-		&git.LogOptions{
-			Since: &params.Start,
-			Until: &params.End,
-			Order: git.LogOrderCommitterTime,
-		},
-	)
+	commitIter, err := params.Repo.Log(options)
 	if err != nil {
-		return "", fmt.Errorf("failed to get commit history: %w", err)
+		return nil, fmt.Errorf("failed to get commit history: %w", err)
 	}
 
+	var (
+		commits []Commit
+		budget  *patchBudget
+	)
+	if params.Patches != nil {
+		budget = newPatchBudget(params.Patches.TokenBudget)
+	}
 	err = commitIter.ForEach(func(cmt *object.Commit) error {
 		select {
 		case <-ctx.Done():
@@ -195,25 +323,135 @@ func (ga *GitAnalyzer) ListCommitsInRange(
 		default:
 		}
 
-		if strings.Contains(cmt.Author.Name, "dependabot[bot]") ||
-			strings.Contains(cmt.Author.Name, "kodiakhq[bot]") {
+		if isBotAuthor(cmt.Author.Name) || excluded[cmt.Hash] {
 			return nil
 		}
 
-		// Skip commits not from the specified author if author filter is provided
-		if params.Author != "" && !strings.Contains(
-			strings.ToLower(cmt.Author.Name),
-			strings.ToLower(params.Author),
-		) {
+		// Skip commits of authors the filter leaves out
+		if !params.Authors.Matches(cmt.Author.Name, cmt.Author.Email) {
 			return nil
 		}
 
-		buf.WriteString(cmt.Message)
+		commit := newCommit(cmt)
+		if params.Stats {
+			stats, err := diffStats(ctx, cmt)
+			if err != nil {
+				return err
+			}
+			commit.Stats = stats
+		}
+		if params.Patches != nil {
+			patch, err := commitPatch(ctx, cmt, *params.Patches, !budget.spent())
+			if err != nil {
+				return err
+			}
+			budget.fit(patch)
+			commit.Patch = patch
+		}
+		commits = append(commits, commit)
 		return nil
 	})
 	if err != nil {
-		return "", fmt.Errorf("error iterating commits: %w", err)
+		return nil, fmt.Errorf("error iterating commits: %w", err)
 	}
 
-	return buf.String(), nil
+	return commits, nil
+}
+
+// Messages concatenates the commit messages, as sent to the model. A
+// commit with stats is followed by a line of them, and one with a patch by
+// the patch; the totals of all commits with stats close the input.
+func Messages(commits []Commit) string {
+	var buf strings.Builder
+	for _, commit := range commits {
+		writeCommit(&buf, commit)
+	}
+	if totals := statsTotals(commits); totals != "" {
+		buf.WriteString("\n" + totals)
+	}
+	return buf.String()
+}
+
+// writeCommit writes the message of commit to buf, followed by its stats
+// and patch when it has them.
+func writeCommit(buf *strings.Builder, commit Commit) {
+	buf.WriteString(commit.Message)
+	if commit.Stats == nil && commit.Patch == nil {
+		return
+	}
+	if !strings.HasSuffix(commit.Message, "\n") {
+		buf.WriteString("\n")
+	}
+	if commit.Stats != nil {
+		fmt.Fprintf(buf, "Stats: %s\n", commit.Stats)
+	}
+	if commit.Patch != nil {
+		patch := commit.Patch.String()
+		buf.WriteString(patch)
+		if !strings.HasSuffix(patch, "\n") {
+			buf.WriteString("\n")
+		}
+	}
+}
+
+// ListActivity returns the commits of all human authors within the date
+// range, newest first.
+func (ga *GitAnalyzer) ListActivity(
+	ctx context.Context, params ActivityParams,
+) ([]Commit, error) {
+	if err := validate.Struct(params); err != nil {
+		return nil, fmt.Errorf("invalid activity parameters: %w", err)
+	}
+
+	options := &git.LogOptions{Order: git.LogOrderCommitterTime}
+	if !params.Start.IsZero() {
+		options.Since = &params.Start
+	}
+	if !params.End.IsZero() {
+		options.Until = &params.End
+	}
+	commitIter, err := params.Repo.Log(options)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get commit history: %w", err)
+	}
+
+	var commits []Commit
+	err = commitIter.ForEach(func(cmt *object.Commit) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if isBotAuthor(cmt.Author.Name) {
+			return nil
+		}
+		commits = append(commits, newCommit(cmt))
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error iterating commits: %w", err)
+	}
+	return commits, nil
+}
+
+// newCommit describes cmt.
+func newCommit(cmt *object.Commit) Commit {
+	subject, _, _ := strings.Cut(strings.TrimSpace(cmt.Message), "\n")
+	commit := Commit{
+		Hash:      cmt.Hash.String(),
+		Author:    cmt.Author.Name,
+		Email:     cmt.Author.Email,
+		When:      cmt.Author.When,
+		Subject:   strings.TrimSpace(subject),
+		Message:   cmt.Message,
+		Signature: parseSignature(cmt.PGPSignature),
+	}
+	if parsed, ok := ParseConventional(cmt.Message); ok {
+		commit.Conventional = &parsed
+	}
+	return commit
+}
+
+// isBotAuthor reports whether a commit was made by a dependency bot.
+func isBotAuthor(name string) bool {
+	return strings.Contains(name, "dependabot[bot]") ||
+		strings.Contains(name, "kodiakhq[bot]")
 }